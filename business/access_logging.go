@@ -0,0 +1,219 @@
+package business
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/prometheus/internalmetrics"
+)
+
+// The access log provider built in Istio, writing TEXT logs to the standard output of the proxy
+const builtinAccessLogProvider = "envoy"
+
+// telemetryAccessLogging is the access logging config of the most specific Telemetry setting it
+type telemetryAccessLogging struct {
+	set      bool
+	from     string
+	disabled bool
+	// The providers of the entries, the entries without provider using the default providers of the mesh
+	providers    []string
+	withDefaults bool
+}
+
+// GetWorkloadAccessLogging resolves whether the proxy of a workload writes access logs, where and in which format,
+// combining the accessLogFile and the defaultProviders of the mesh config with the Telemetry resources applied to the
+// workload. The disagreements between the mesh config and the Telemetries are reported as conflicts.
+func (in *WorkloadService) GetWorkloadAccessLogging(namespace, workload string) (*models.AccessLogging, error) {
+	var err error
+	promtimer := internalmetrics.GetGoFunctionMetric("business", "WorkloadService", "GetWorkloadAccessLogging")
+	defer promtimer.ObserveNow(&err)
+
+	// Check if user has access to the namespace (RBAC) in cache scenarios and/or
+	// if namespace is accessible from Kiali (Deployment.AccessibleNamespaces)
+	if _, err = in.businessLayer.Namespace.GetNamespace(namespace); err != nil {
+		return nil, err
+	}
+	var wkd *models.Workload
+	if wkd, err = fetchWorkload(in.businessLayer, namespace, workload, ""); err != nil {
+		return nil, err
+	}
+	var meshConfig *models.MeshConfig
+	if meshConfig, err = in.businessLayer.Mesh.GetEffectiveMeshConfig(); err != nil {
+		return nil, err
+	}
+	var telemetries []kubernetes.IstioObject
+	if telemetries, err = fetchTelemetries(in.k8s, namespace, meshConfig.RootNamespace); err != nil {
+		return nil, err
+	}
+
+	accessLogging := resolveAccessLogging(meshConfig, workloadTelemetries(namespace, meshConfig.RootNamespace, wkd.Labels, telemetries))
+	accessLogging.Namespace = namespace
+	accessLogging.Workload = workload
+	return accessLogging, nil
+}
+
+// resolveAccessLogging resolves the access logging of the telemetries applied to a workload, sorted from the least to
+// the most specific. A Telemetry overrides the mesh config, whose defaultProviders override its accessLogFile.
+func resolveAccessLogging(meshConfig *models.MeshConfig, telemetries []kubernetes.IstioObject) *models.AccessLogging {
+	accessLogging := &models.AccessLogging{Providers: []models.AccessLogProvider{}, Conflicts: []string{}}
+	defaults := meshDefaultProviders(meshConfig, "accessLogging")
+	legacy := meshConfig.AccessLogFile != ""
+	meshFrom := ""
+	switch {
+	case len(defaults) > 0:
+		meshFrom = "the defaultProviders of the mesh config"
+	case legacy:
+		meshFrom = "the accessLogFile of the mesh config"
+	}
+	if len(defaults) > 0 && legacy {
+		accessLogging.Conflicts = append(accessLogging.Conflicts,
+			"Both the accessLogFile and the defaultProviders of the mesh config set the access logging, the accessLogFile is overridden")
+	}
+
+	tal := resolveTelemetryAccessLogging(telemetries)
+	switch {
+	case tal.set:
+		accessLogging.Source = models.AccessLoggingFromTelemetry
+		accessLogging.Telemetry = tal.from
+		if tal.disabled {
+			if meshFrom != "" {
+				accessLogging.Conflicts = append(accessLogging.Conflicts,
+					fmt.Sprintf("Access logging is enabled by %s, but disabled by the Telemetry %s", meshFrom, tal.from))
+			}
+			break
+		}
+		names := tal.providers
+		if tal.withDefaults {
+			names = append(names, defaults...)
+		}
+		if len(names) == 0 {
+			accessLogging.Conflicts = append(accessLogging.Conflicts,
+				fmt.Sprintf("The Telemetry %s enables access logging without provider, and the mesh config has no defaultProviders for access logging", tal.from))
+			break
+		}
+		accessLogging.Enabled = true
+		accessLogging.Providers = accessLogProviders(meshConfig, names, "the Telemetry "+tal.from, accessLogging)
+		if meshFrom == "" {
+			accessLogging.Conflicts = append(accessLogging.Conflicts,
+				fmt.Sprintf("Access logging is disabled by the mesh config, but enabled by the Telemetry %s", tal.from))
+		}
+		if legacy {
+			for _, provider := range accessLogging.Providers {
+				if provider.Encoding != "" && !strings.EqualFold(provider.Encoding, meshConfig.AccessLogEncoding) {
+					accessLogging.Conflicts = append(accessLogging.Conflicts,
+						fmt.Sprintf("The provider [%s] of the Telemetry %s writes %s logs, but the accessLogEncoding of the mesh config is %s",
+							provider.Name, tal.from, provider.Encoding, meshConfig.AccessLogEncoding))
+				}
+			}
+		}
+	case len(defaults) > 0:
+		accessLogging.Source = models.AccessLoggingFromDefaultProviders
+		accessLogging.Enabled = true
+		accessLogging.Providers = accessLogProviders(meshConfig, defaults, meshFrom, accessLogging)
+	case legacy:
+		accessLogging.Source = models.AccessLoggingFromAccessLogFile
+		accessLogging.Enabled = true
+		encoding := strings.ToUpper(meshConfig.AccessLogEncoding)
+		if encoding == "" {
+			encoding = "TEXT"
+		}
+		accessLogging.Providers = []models.AccessLogProvider{{
+			Name:     models.AccessLoggingFromAccessLogFile,
+			Type:     "file",
+			Path:     meshConfig.AccessLogFile,
+			Encoding: encoding,
+			Format:   meshConfig.AccessLogFormat,
+		}}
+	default:
+		accessLogging.Source = models.AccessLoggingFromNone
+	}
+	return accessLogging
+}
+
+// accessLogProviders describes the named providers from the extensionProviders of the mesh config. The providers not
+// defined are reported as conflicts.
+func accessLogProviders(meshConfig *models.MeshConfig, names []string, from string, accessLogging *models.AccessLogging) []models.AccessLogProvider {
+	defined := meshExtensionProviders(meshConfig)
+	providers := make([]models.AccessLogProvider, 0, len(names))
+	for _, name := range names {
+		provider := models.AccessLogProvider{Name: name, Type: "unknown"}
+		definition, found := defined[name]
+		switch {
+		case found:
+			describeAccessLogProvider(&provider, definition)
+		case name == builtinAccessLogProvider:
+			provider.Type = "file"
+			provider.Path = "/dev/stdout"
+			provider.Encoding = "TEXT"
+		default:
+			accessLogging.Conflicts = append(accessLogging.Conflicts,
+				fmt.Sprintf("The access log provider [%s], set by %s, is not defined in the extensionProviders of the mesh config", name, from))
+		}
+		providers = append(providers, provider)
+	}
+	return providers
+}
+
+func describeAccessLogProvider(provider *models.AccessLogProvider, definition map[string]interface{}) {
+	if file, ok := definition["envoyFileAccessLog"].(map[string]interface{}); ok {
+		provider.Type = "file"
+		provider.Path, _ = file["path"].(string)
+		provider.Encoding = "TEXT"
+		if logFormat, ok := file["logFormat"].(map[string]interface{}); ok {
+			if _, found := logFormat["labels"]; found {
+				provider.Encoding = "JSON"
+			} else {
+				provider.Format, _ = logFormat["text"].(string)
+			}
+		}
+		return
+	}
+	if _, ok := definition["envoyOtelAls"]; ok {
+		provider.Type = "otel"
+		return
+	}
+	for _, grpc := range []string{"envoyHttpAls", "envoyTcpAls"} {
+		if _, ok := definition[grpc]; ok {
+			provider.Type = "grpc"
+			return
+		}
+	}
+}
+
+// resolveTelemetryAccessLogging returns the access logging of the most specific Telemetry setting it. The proxy logs
+// are disabled when all its entries are disabled.
+func resolveTelemetryAccessLogging(telemetries []kubernetes.IstioObject) telemetryAccessLogging {
+	tal := telemetryAccessLogging{}
+	for _, telemetry := range telemetries {
+		entries, ok := telemetry.GetSpec()["accessLogging"].([]interface{})
+		if !ok || len(entries) == 0 {
+			continue
+		}
+		meta := telemetry.GetObjectMeta()
+		tal = telemetryAccessLogging{set: true, from: meta.Namespace + "/" + meta.Name, disabled: true, providers: []string{}}
+		for _, e := range entries {
+			entry, ok := e.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if disabled, _ := entry["disabled"].(bool); disabled {
+				continue
+			}
+			tal.disabled = false
+			providers, _ := entry["providers"].([]interface{})
+			if len(providers) == 0 {
+				tal.withDefaults = true
+			}
+			for _, provider := range providers {
+				if p, ok := provider.(map[string]interface{}); ok {
+					if name, ok := p["name"].(string); ok && !checkType(tal.providers, name) {
+						tal.providers = append(tal.providers, name)
+					}
+				}
+			}
+		}
+	}
+	return tal
+}
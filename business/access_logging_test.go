@@ -0,0 +1,127 @@
+package business
+
+import (
+	"bytes"
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v2"
+
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/tests/data"
+)
+
+var reviewsLabels = map[string]string{"app": "reviews", "version": "v1"}
+
+func TestAccessLoggingFromAccessLogFile(t *testing.T) {
+	assert := assert.New(t)
+
+	accessLogging := accessLoggingTestPrep("mesh-file-only.yaml", reviewsLabels, t)
+
+	assert.True(accessLogging.Enabled)
+	assert.Equal(models.AccessLoggingFromAccessLogFile, accessLogging.Source)
+	assert.Equal([]models.AccessLogProvider{{Name: "accessLogFile", Type: "file", Path: "/dev/stdout", Encoding: "JSON"}}, accessLogging.Providers)
+	assert.Empty(accessLogging.Conflicts)
+}
+
+// Context: the accessLogFile of the mesh writes TEXT logs, a Telemetry of the namespace writes JSON logs
+func TestAccessLoggingTelemetryOverridesEncoding(t *testing.T) {
+	assert := assert.New(t)
+
+	accessLogging := accessLoggingTestPrep("mesh-file-telemetry-json.yaml", reviewsLabels, t)
+
+	assert.True(accessLogging.Enabled)
+	assert.Equal(models.AccessLoggingFromTelemetry, accessLogging.Source)
+	assert.Equal("bookinfo/access-logs", accessLogging.Telemetry)
+	assert.Equal([]models.AccessLogProvider{{Name: "json-logs", Type: "file", Path: "/dev/stdout", Encoding: "JSON"}}, accessLogging.Providers)
+	assert.Equal([]string{"The provider [json-logs] of the Telemetry bookinfo/access-logs writes JSON logs, but the accessLogEncoding of the mesh config is TEXT"},
+		accessLogging.Conflicts)
+}
+
+// Context: the defaultProviders of the mesh enable access logging, a Telemetry disables it for the reviews workloads
+func TestAccessLoggingDisabledByWorkloadTelemetry(t *testing.T) {
+	assert := assert.New(t)
+
+	accessLogging := accessLoggingTestPrep("mesh-default-telemetry-disabled.yaml", reviewsLabels, t)
+
+	assert.False(accessLogging.Enabled)
+	assert.Equal(models.AccessLoggingFromTelemetry, accessLogging.Source)
+	assert.Equal("bookinfo/no-reviews-logs", accessLogging.Telemetry)
+	assert.Empty(accessLogging.Providers)
+	assert.Equal([]string{"Access logging is enabled by the defaultProviders of the mesh config, but disabled by the Telemetry bookinfo/no-reviews-logs"},
+		accessLogging.Conflicts)
+
+	// The Telemetry of the root namespace applies to the other workloads
+	accessLogging = accessLoggingTestPrep("mesh-default-telemetry-disabled.yaml", map[string]string{"app": "productpage"}, t)
+
+	assert.True(accessLogging.Enabled)
+	assert.Equal("istio-system/mesh-default", accessLogging.Telemetry)
+	assert.Equal([]models.AccessLogProvider{{Name: "envoy", Type: "file", Path: "/dev/stdout", Encoding: "TEXT"}}, accessLogging.Providers)
+	assert.Empty(accessLogging.Conflicts)
+}
+
+// Context: the mesh config doesn't enable access logging, a Telemetry of the root namespace does, with an undefined provider
+func TestAccessLoggingEnabledByRootTelemetry(t *testing.T) {
+	assert := assert.New(t)
+
+	accessLogging := accessLoggingTestPrep("mesh-off-telemetry-on.yaml", reviewsLabels, t)
+
+	assert.True(accessLogging.Enabled)
+	assert.Equal("istio-system/mesh-default", accessLogging.Telemetry)
+	assert.Equal([]models.AccessLogProvider{{Name: "otel", Type: "otel"}, {Name: "als", Type: "unknown"}}, accessLogging.Providers)
+	assert.Equal([]string{
+		"The access log provider [als], set by the Telemetry istio-system/mesh-default, is not defined in the extensionProviders of the mesh config",
+		"Access logging is disabled by the mesh config, but enabled by the Telemetry istio-system/mesh-default",
+	}, accessLogging.Conflicts)
+}
+
+func TestAccessLoggingNone(t *testing.T) {
+	assert := assert.New(t)
+
+	meshConfig, err := models.ParseMeshConfig("", models.DefaultMeshConfig("istio-system"))
+	assert.NoError(err)
+	accessLogging := resolveAccessLogging(meshConfig, nil)
+
+	assert.False(accessLogging.Enabled)
+	assert.Equal(models.AccessLoggingFromNone, accessLogging.Source)
+	assert.Empty(accessLogging.Providers)
+	assert.Empty(accessLogging.Conflicts)
+}
+
+// fixtureConfigMap is the part of the ConfigMap of the mesh config read from the fixtures
+type fixtureConfigMap struct {
+	Kind string            `yaml:"kind"`
+	Data map[string]string `yaml:"data"`
+}
+
+func accessLoggingTestPrep(scenario string, workloadLabels map[string]string, t *testing.T) *models.AccessLogging {
+	path := "../tests/data/access_logging/" + scenario
+	loader := &data.YamlFixtureLoader{Filename: path}
+	if err := loader.Load(); err != nil {
+		t.Fatal("Error loading test data.")
+	}
+
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	meshConfigYaml := ""
+	dec := yaml.NewDecoder(bytes.NewReader(content))
+	for {
+		var resource fixtureConfigMap
+		if err := dec.Decode(&resource); err != nil {
+			break
+		}
+		if resource.Kind == "ConfigMap" {
+			meshConfigYaml = resource.Data["mesh"]
+		}
+	}
+	meshConfig, err := models.ParseMeshConfig(meshConfigYaml, models.DefaultMeshConfig("istio-system"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	telemetries := workloadTelemetries("bookinfo", meshConfig.RootNamespace, workloadLabels, loader.GetResources("Telemetry"))
+	return resolveAccessLogging(meshConfig, telemetries)
+}
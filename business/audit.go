@@ -0,0 +1,141 @@
+package business
+
+import (
+	"fmt"
+	"time"
+
+	core_v1 "k8s.io/api/core/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/log"
+)
+
+// The operations recorded in the audit log
+const (
+	AuditCreate = "CREATE"
+	AuditUpdate = "UPDATE"
+	AuditDelete = "DELETE"
+)
+
+// AuditEventReason is the reason of the Kubernetes Events recording a mutation performed through Kiali
+const AuditEventReason = "KialiAudit"
+
+// AuditRecord is a mutation performed through Kiali. It only identifies the mutated object: the object itself, or
+// the patch applied to it, is never recorded as it may hold secret data.
+type AuditRecord struct {
+	User      string
+	Cluster   string
+	Namespace string
+	Kind      string
+	Name      string
+	Operation string
+	Timestamp time.Time
+}
+
+// AuditLogger records the mutations performed by a user through Kiali in the sinks set in the configuration
+type AuditLogger struct {
+	businessLayer *Layer
+	user          string
+
+	// infof writes the records of the log sink, log.Infof when not set. It is set by the tests to read the records.
+	infof func(format string, args ...interface{})
+	// kialiClient returns the client of the Kiali ServiceAccount in a cluster. It is set by the tests to mock it.
+	kialiClient func(cluster string) (kubernetes.ClientInterface, error)
+}
+
+// SetUser sets the identity of the user performing the mutations
+func (in *AuditLogger) SetUser(user string) {
+	in.user = user
+}
+
+// Record writes the audit record of a mutation of the cluster to the configured sinks. An empty cluster is the home
+// cluster of Kiali. The audit Event is written in the mutated cluster, with the Kiali ServiceAccount: the users can
+// neither skip the record nor forge one. A failure to write a record is logged but doesn't fail the mutation, already
+// performed.
+func (in *AuditLogger) Record(cluster, operation, namespace, kind, name string) {
+	conf := config.Get()
+	if !conf.Server.AuditLog {
+		return
+	}
+
+	clusterName := cluster
+	if clusterName == "" {
+		clusterName = conf.KubernetesConfig.ClusterName
+	}
+	user := in.user
+	if user == "" {
+		// No user is identified with the anonymous and header strategies
+		if conf.Auth.Strategy == config.AuthStrategyHeader {
+			user = "header-auth"
+		} else {
+			user = "anonymous"
+		}
+	}
+	record := AuditRecord{
+		User:      user,
+		Cluster:   clusterName,
+		Namespace: namespace,
+		Kind:      kind,
+		Name:      name,
+		Operation: operation,
+		Timestamp: time.Now(),
+	}
+
+	sinks := conf.Server.AuditLogSinks
+	if len(sinks) == 0 {
+		sinks = []string{config.AuditLogSinkLog}
+	}
+	for _, sink := range sinks {
+		switch sink {
+		case config.AuditLogSinkLog:
+			infof := in.infof
+			if infof == nil {
+				infof = log.Infof
+			}
+			infof("AUDIT User [%s] Cluster [%s] Namespace [%s] Kind [%s] Name [%s] Operation [%s] Timestamp [%s]",
+				record.User, record.Cluster, record.Namespace, record.Kind, record.Name, record.Operation, record.Timestamp.Format(time.RFC3339))
+		case config.AuditLogSinkEvent:
+			client, err := in.clusterClient(cluster)
+			if err == nil {
+				err = client.CreateEvent(namespace, auditEvent(record))
+			}
+			if err != nil {
+				log.Warningf("Error recording the audit Event of the %s of %s [%s/%s] in cluster [%s]: %v", record.Operation, record.Kind, namespace, name, record.Cluster, err)
+			}
+		}
+	}
+}
+
+// clusterClient returns the client writing the audit Events of the cluster, with the Kiali ServiceAccount
+func (in *AuditLogger) clusterClient(cluster string) (kubernetes.ClientInterface, error) {
+	if in.kialiClient != nil {
+		return in.kialiClient(cluster)
+	}
+	return in.businessLayer.Mesh.getKialiClusterClient(cluster)
+}
+
+// auditEvent returns the Kubernetes Event recording a mutation on the mutated object
+func auditEvent(record AuditRecord) *core_v1.Event {
+	timestamp := meta_v1.NewTime(record.Timestamp)
+	return &core_v1.Event{
+		ObjectMeta: meta_v1.ObjectMeta{
+			// Same naming as the events of the Kubernetes recorder
+			Name:      fmt.Sprintf("%s.%x", record.Name, record.Timestamp.UnixNano()),
+			Namespace: record.Namespace,
+		},
+		InvolvedObject: core_v1.ObjectReference{
+			Kind:      record.Kind,
+			Namespace: record.Namespace,
+			Name:      record.Name,
+		},
+		Reason:         AuditEventReason,
+		Message:        fmt.Sprintf("%s by user [%s] through Kiali", record.Operation, record.User),
+		Source:         core_v1.EventSource{Component: "kiali"},
+		FirstTimestamp: timestamp,
+		LastTimestamp:  timestamp,
+		Count:          1,
+		Type:           core_v1.EventTypeNormal,
+	}
+}
@@ -0,0 +1,230 @@
+package business
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	osapps_v1 "github.com/openshift/api/apps/v1"
+	osproject_v1 "github.com/openshift/api/project/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	apps_v1 "k8s.io/api/apps/v1"
+	batch_v1 "k8s.io/api/batch/v1"
+	batch_v1beta1 "k8s.io/api/batch/v1beta1"
+	core_v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/kubernetes/kubetest"
+)
+
+func TestAuditIstioConfigMutations(t *testing.T) {
+	assert := assert.New(t)
+	setupAuditConfig(config.AuditLogSinkEvent)
+
+	vs := &kubernetes.GenericIstioObject{ObjectMeta: meta_v1.ObjectMeta{Name: "reviews", Namespace: "bookinfo"}}
+	k8s := new(kubetest.K8SClientMock)
+	k8s.On("IsOpenShift").Return(false)
+	k8s.On("CreateIstioObject", "networking.istio.io", "bookinfo", "virtualservices", mock.AnythingOfType("string")).Return(vs, nil)
+	k8s.On("UpdateIstioObject", "networking.istio.io", "bookinfo", "virtualservices", "reviews", mock.AnythingOfType("string")).Return(vs, nil)
+	k8s.On("DeleteIstioObject", "networking.istio.io", "bookinfo", "virtualservices", "reviews").Return(nil)
+	kiali := mockAuditKialiClient("bookinfo")
+
+	layer := NewWithBackends(k8s, nil, nil)
+	layer.Audit.SetUser("jdoe")
+	layer.Audit.kialiClient = func(string) (kubernetes.ClientInterface, error) { return kiali, nil }
+
+	_, err := layer.IstioConfig.CreateIstioConfigDetail("", "networking.istio.io", "bookinfo", "virtualservices", []byte("{}"))
+	assert.NoError(err)
+	_, err = layer.IstioConfig.UpdateIstioConfigDetail("", "networking.istio.io", "bookinfo", "virtualservices", "reviews", "{}")
+	assert.NoError(err)
+	assert.NoError(layer.IstioConfig.DeleteIstioConfigDetail("", "networking.istio.io", "bookinfo", "virtualservices", "reviews"))
+
+	// The Events are written with the Kiali ServiceAccount, not with the credentials of the user
+	k8s.AssertNotCalled(t, "CreateEvent", mock.Anything, mock.Anything)
+	events := auditEvents(kiali)
+	assert.Len(events, 3)
+	for i, operation := range []string{AuditCreate, AuditUpdate, AuditDelete} {
+		assert.Equal("bookinfo", events[i].Namespace)
+		assert.Equal(core_v1.ObjectReference{Kind: kubernetes.VirtualServiceType, Namespace: "bookinfo", Name: "reviews"}, events[i].InvolvedObject)
+		assert.Equal(AuditEventReason, events[i].Reason)
+		assert.Equal(operation+" by user [jdoe] through Kiali", events[i].Message)
+	}
+}
+
+func TestAuditIstioConfigMutationFailed(t *testing.T) {
+	assert := assert.New(t)
+	setupAuditConfig(config.AuditLogSinkEvent)
+
+	k8s := new(kubetest.K8SClientMock)
+	k8s.On("IsOpenShift").Return(false)
+	k8s.On("DeleteIstioObject", "networking.istio.io", "bookinfo", "virtualservices", "reviews").Return(errors.NewNotFound(schema.GroupResource{}, "reviews"))
+
+	layer := NewWithBackends(k8s, nil, nil)
+	assert.Error(layer.IstioConfig.DeleteIstioConfigDetail("", "networking.istio.io", "bookinfo", "virtualservices", "reviews"))
+
+	k8s.AssertNotCalled(t, "CreateEvent", mock.Anything, mock.Anything)
+}
+
+func TestAuditWorkloadMutation(t *testing.T) {
+	assert := assert.New(t)
+
+	notfound := errors.NewNotFound(schema.GroupResource{}, "not found")
+	k8s := new(kubetest.K8SClientMock)
+	k8s.On("IsOpenShift").Return(true)
+	k8s.On("GetProject", mock.AnythingOfType("string")).Return(&osproject_v1.Project{}, nil)
+	k8s.On("GetDeployment", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return(&FakeDepSyncedWithRS()[0], nil)
+	k8s.On("GetDeploymentConfig", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return(&osapps_v1.DeploymentConfig{}, notfound)
+	k8s.On("GetReplicaSets", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return([]apps_v1.ReplicaSet{}, nil)
+	k8s.On("GetReplicationControllers", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return([]core_v1.ReplicationController{}, nil)
+	k8s.On("GetStatefulSet", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return(&apps_v1.StatefulSet{}, notfound)
+	k8s.On("GetPods", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return(FakePodsSyncedWithDeployments(), nil)
+	k8s.On("GetJobs", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return([]batch_v1.Job{}, nil)
+	k8s.On("GetCronJobs", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return([]batch_v1beta1.CronJob{}, nil)
+	k8s.On("PatchWorkload", "Namespace", "details-v1", kubernetes.DeploymentType, mock.AnythingOfType("string"), types.StrategicMergePatchType).Return(nil)
+	kiali := mockAuditKialiClient("Namespace")
+
+	// The workload fixtures reset the config
+	setupAuditConfig(config.AuditLogSinkEvent)
+	svc := setupWorkloadService(k8s)
+	svc.businessLayer.Audit.SetUser("jdoe")
+	svc.businessLayer.Audit.kialiClient = func(string) (kubernetes.ClientInterface, error) { return kiali, nil }
+
+	owner := "team-a"
+	_, err := svc.PatchWorkloadMetadata("Namespace", "details-v1", kubernetes.DeploymentType, map[string]*string{"owner": &owner}, nil, false)
+	assert.NoError(err)
+
+	events := auditEvents(kiali)
+	assert.Len(events, 1)
+	assert.Equal(core_v1.ObjectReference{Kind: kubernetes.DeploymentType, Namespace: "Namespace", Name: "details-v1"}, events[0].InvolvedObject)
+	assert.Equal("UPDATE by user [jdoe] through Kiali", events[0].Message)
+}
+
+func TestAuditNamespaceMutation(t *testing.T) {
+	assert := assert.New(t)
+	setupAuditConfig(config.AuditLogSinkLog)
+
+	patch := `{"metadata":{"labels":{"istio-injection":"enabled"}}}`
+	k8s := new(kubetest.K8SClientMock)
+	k8s.On("IsOpenShift").Return(true)
+	k8s.On("GetProject", "bookinfo").Return(&osproject_v1.Project{ObjectMeta: meta_v1.ObjectMeta{Name: "bookinfo"}}, nil)
+	k8s.On("UpdateNamespace", "bookinfo", patch).Return(&core_v1.Namespace{}, nil)
+	var buf bytes.Buffer
+	layer := NewWithBackends(k8s, nil, nil)
+	layer.Audit.SetUser("jdoe")
+	layer.Audit.infof = func(format string, args ...interface{}) {
+		fmt.Fprintf(&buf, format, args...)
+	}
+
+	_, err := layer.Namespace.UpdateNamespace("bookinfo", patch)
+	assert.NoError(err)
+
+	// The record identifies the namespace, the patch is not logged
+	assert.Contains(buf.String(), "AUDIT User [jdoe] Cluster [east] Namespace [bookinfo] Kind [Namespace] Name [bookinfo] Operation [UPDATE]")
+	assert.NotContains(buf.String(), "istio-injection")
+}
+
+func TestAuditConfigBundle(t *testing.T) {
+	assert := assert.New(t)
+	setupAuditConfig(config.AuditLogSinkLog)
+
+	k8s := mockConfigBundle()
+	k8s.On("CreateIstioObject", "networking.istio.io", "bookinfo", "virtualservices", mock.AnythingOfType("string")).Return(&kubernetes.GenericIstioObject{}, nil)
+	k8s.On("UpdateIstioObject", "networking.istio.io", "bookinfo", "destinationrules", "reviews", mock.AnythingOfType("string")).Return(&kubernetes.GenericIstioObject{}, nil)
+	k8s.On("CreateIstioObject", "security.istio.io", "bookinfo", "peerauthentications", mock.AnythingOfType("string")).Return(&kubernetes.GenericIstioObject{}, errors.NewBadRequest("denied"))
+	k8s.On("DeleteIstioObject", "networking.istio.io", "bookinfo", "virtualservices", "reviews").Return(nil)
+	records := []string{}
+	configService := newConfigBundleService(k8s)
+	configService.businessLayer.Audit.infof = func(format string, args ...interface{}) {
+		// Kind, name and operation of the record
+		records = append(records, fmt.Sprintf("%s %s %s", args[3], args[4], args[5]))
+	}
+
+	_, err := configService.ApplyConfigBundle("bookinfo", []byte(configBundle))
+	assert.Error(err)
+
+	// The applied objects, then the rolled back ones
+	assert.Equal([]string{
+		"VirtualService reviews CREATE",
+		"DestinationRule reviews UPDATE",
+		"DestinationRule reviews UPDATE",
+		"VirtualService reviews DELETE",
+	}, records)
+}
+
+func TestAuditLogSink(t *testing.T) {
+	assert := assert.New(t)
+	setupAuditConfig(config.AuditLogSinkLog)
+
+	var buf bytes.Buffer
+	kiali := new(kubetest.K8SClientMock)
+	audit := AuditLogger{user: "jdoe", infof: func(format string, args ...interface{}) {
+		fmt.Fprintf(&buf, format, args...)
+	}, kialiClient: func(string) (kubernetes.ClientInterface, error) { return kiali, nil }}
+	audit.Record("", AuditDelete, "bookinfo", "Secret", "credentials")
+
+	assert.Contains(buf.String(), "AUDIT User [jdoe] Cluster [east] Namespace [bookinfo] Kind [Secret] Name [credentials] Operation [DELETE] Timestamp [")
+	kiali.AssertNotCalled(t, "CreateEvent", mock.Anything, mock.Anything)
+}
+
+func TestAuditAnonymousUser(t *testing.T) {
+	assert := assert.New(t)
+	setupAuditConfig(config.AuditLogSinkEvent)
+
+	kiali := mockAuditKialiClient("bookinfo")
+	audit := AuditLogger{kialiClient: func(string) (kubernetes.ClientInterface, error) { return kiali, nil }}
+	audit.Record("", AuditUpdate, "bookinfo", kubernetes.DeploymentType, "reviews-v1")
+
+	conf := config.Get()
+	conf.Auth.Strategy = config.AuthStrategyHeader
+	config.Set(conf)
+	audit.Record("", AuditUpdate, "bookinfo", kubernetes.DeploymentType, "reviews-v1")
+
+	events := auditEvents(kiali)
+	assert.Len(events, 2)
+	assert.Equal("UPDATE by user [anonymous] through Kiali", events[0].Message)
+	assert.Equal("UPDATE by user [header-auth] through Kiali", events[1].Message)
+}
+
+func TestAuditDisabled(t *testing.T) {
+	setupAuditConfig(config.AuditLogSinkEvent)
+	conf := config.Get()
+	conf.Server.AuditLog = false
+	config.Set(conf)
+
+	kiali := new(kubetest.K8SClientMock)
+	audit := AuditLogger{user: "jdoe", kialiClient: func(string) (kubernetes.ClientInterface, error) { return kiali, nil }}
+	audit.Record("", AuditUpdate, "bookinfo", kubernetes.DeploymentType, "reviews-v1")
+
+	kiali.AssertNotCalled(t, "CreateEvent", mock.Anything, mock.Anything)
+}
+
+func setupAuditConfig(sinks ...string) {
+	conf := config.NewConfig()
+	conf.KubernetesConfig.ClusterName = "east"
+	conf.Server.AuditLogSinks = sinks
+	config.Set(conf)
+}
+
+// mockAuditKialiClient returns the client of the Kiali ServiceAccount writing the audit Events of the namespace
+func mockAuditKialiClient(namespace string) *kubetest.K8SClientMock {
+	kiali := new(kubetest.K8SClientMock)
+	kiali.On("CreateEvent", namespace, mock.AnythingOfType("*v1.Event")).Return(nil)
+	return kiali
+}
+
+// auditEvents returns the audit Events created, in order
+func auditEvents(k8s *kubetest.K8SClientMock) []*core_v1.Event {
+	events := []*core_v1.Event{}
+	for _, call := range k8s.Calls {
+		if call.Method == "CreateEvent" {
+			events = append(events, call.Arguments.Get(1).(*core_v1.Event))
+		}
+	}
+	return events
+}
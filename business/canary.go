@@ -0,0 +1,280 @@
+package business
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	apps_v1 "k8s.io/api/apps/v1"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/log"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/prometheus/internalmetrics"
+)
+
+const (
+	flaggerPrimarySuffix     = "-primary"
+	argoRolloutKind          = "Rollout"
+	argoRevisionAnnotation   = "rollout.argoproj.io/revision"
+	argoPodTemplateHashLabel = "rollouts-pod-template-hash"
+)
+
+// GetCanaryRollouts returns the progressive delivery rollouts (Flagger and Argo Rollouts) found in the namespace
+func (in *WorkloadService) GetCanaryRollouts(namespace string) (models.CanaryRollouts, error) {
+	var err error
+	promtimer := internalmetrics.GetGoFunctionMetric("business", "WorkloadService", "GetCanaryRollouts")
+	defer promtimer.ObserveNow(&err)
+
+	// Check if user has access to the namespace (RBAC) in cache scenarios and/or
+	// if namespace is accessible from Kiali (Deployment.AccessibleNamespaces)
+	if _, err = in.businessLayer.Namespace.GetNamespace(namespace); err != nil {
+		return nil, err
+	}
+
+	var dep []apps_v1.Deployment
+	var repset []apps_v1.ReplicaSet
+
+	wg := sync.WaitGroup{}
+	wg.Add(2)
+	errChan := make(chan error, 2)
+
+	go func() {
+		defer wg.Done()
+		var err error
+		if IsNamespaceCached(namespace) {
+			dep, err = kialiCache.GetDeployments(namespace)
+		} else {
+			dep, err = in.k8s.GetDeployments(namespace)
+		}
+		if err != nil {
+			log.Errorf("Error fetching Deployments per namespace %s: %s", namespace, err)
+			errChan <- err
+		}
+	}()
+
+	go func() {
+		defer wg.Done()
+		var err error
+		if IsNamespaceCached(namespace) {
+			repset, err = kialiCache.GetReplicaSets(namespace)
+		} else {
+			repset, err = in.k8s.GetReplicaSets(namespace)
+		}
+		if err != nil {
+			log.Errorf("Error fetching ReplicaSets per namespace %s: %s", namespace, err)
+			errChan <- err
+		}
+	}()
+
+	wg.Wait()
+	if len(errChan) != 0 {
+		err = <-errChan
+		return nil, err
+	}
+
+	return detectCanaryRollouts(namespace, dep, repset), nil
+}
+
+// GetCanaryRollout returns a single rollout of the namespace, by name
+func (in *WorkloadService) GetCanaryRollout(namespace, name string) (*models.CanaryRollout, error) {
+	rollouts, err := in.GetCanaryRollouts(namespace)
+	if err != nil {
+		return nil, err
+	}
+	for i := range rollouts {
+		if rollouts[i].Name == name {
+			return &rollouts[i], nil
+		}
+	}
+	return nil, kubernetes.NewNotFound(name, "Kiali", "Rollout")
+}
+
+// GetCanaryRolloutMetrics compares the inbound success rate and latencies of the canary and the stable variants of a rollout
+func (in *WorkloadService) GetCanaryRolloutMetrics(namespace, name, rateInterval string, queryTime time.Time) (*models.CanaryMetrics, error) {
+	var err error
+	promtimer := internalmetrics.GetGoFunctionMetric("business", "WorkloadService", "GetCanaryRolloutMetrics")
+	defer promtimer.ObserveNow(&err)
+
+	rollout, err := in.GetCanaryRollout(namespace, name)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &models.CanaryMetrics{Rollout: *rollout}
+	if rollout.Canary != nil {
+		if result.Canary, err = in.getVariantMetrics(namespace, rollout.Canary.Workload, rateInterval, queryTime); err != nil {
+			return nil, err
+		}
+	}
+	if rollout.Stable != nil {
+		if result.Stable, err = in.getVariantMetrics(namespace, rollout.Stable.Workload, rateInterval, queryTime); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+func (in *WorkloadService) getVariantMetrics(namespace, workload, rateInterval string, queryTime time.Time) (*models.VariantMetrics, error) {
+	inbound, _, err := in.prom.GetWorkloadRequestRates(namespace, workload, rateInterval, queryTime)
+	if err != nil {
+		return nil, err
+	}
+	rqHealth := models.NewEmptyRequestHealth()
+	for _, sample := range inbound {
+		rqHealth.AggregateInbound(sample)
+	}
+	rqHealth.CombineReporters()
+
+	metrics := &models.VariantMetrics{Latencies: []models.Stat{}}
+	metrics.RequestRate, metrics.SuccessRate = models.RequestsSuccessRate(rqHealth.Inbound)
+
+	lb := NewMetricsLabelsBuilder("inbound")
+	lb.SelfReporter()
+	lb.Workload(workload, namespace)
+	stats, err := in.prom.FetchHistogramValues(telemetryMetric("istio_request_duration_milliseconds"), lb.Build(), "", rateInterval, true, latencyQuantiles, queryTime)
+	if err != nil {
+		return nil, err
+	}
+	for stat, vec := range stats {
+		for _, sample := range vec {
+			value := float64(sample.Value)
+			if math.IsNaN(value) {
+				continue
+			}
+			metrics.Latencies = append(metrics.Latencies, models.Stat{Name: stat, Value: value})
+		}
+	}
+	sort.Slice(metrics.Latencies, func(i, j int) bool {
+		return metrics.Latencies[i].Name < metrics.Latencies[j].Name
+	})
+	return metrics, nil
+}
+
+// detectCanaryRollouts finds the Flagger (Deployment pairs) and Argo Rollouts (ReplicaSets owned by a Rollout) rollouts
+func detectCanaryRollouts(namespace string, deployments []apps_v1.Deployment, replicaSets []apps_v1.ReplicaSet) models.CanaryRollouts {
+	appLabel := config.Get().IstioLabels.AppLabelName
+	rollouts := models.CanaryRollouts{}
+
+	depByName := make(map[string]*apps_v1.Deployment, len(deployments))
+	for i := range deployments {
+		depByName[deployments[i].Name] = &deployments[i]
+	}
+	for _, primary := range deployments {
+		if !strings.HasSuffix(primary.Name, flaggerPrimarySuffix) {
+			continue
+		}
+		// Flagger keeps the target Deployment, scaled down to zero between analyses, next to the primary one
+		target, found := depByName[strings.TrimSuffix(primary.Name, flaggerPrimarySuffix)]
+		if !found {
+			continue
+		}
+		rollouts = append(rollouts, models.CanaryRollout{
+			Name:      target.Name,
+			Namespace: namespace,
+			Tool:      models.FlaggerTool,
+			App:       target.Spec.Template.Labels[appLabel],
+			Canary:    deploymentVariant(models.CanaryRole, *target),
+			Stable:    deploymentVariant(models.StableRole, primary),
+		})
+	}
+
+	rsByRollout := map[string][]apps_v1.ReplicaSet{}
+	for _, rs := range replicaSets {
+		if _, ok := rs.Labels[argoPodTemplateHashLabel]; !ok {
+			continue
+		}
+		for _, ref := range rs.OwnerReferences {
+			if ref.Kind == argoRolloutKind && ref.Controller != nil && *ref.Controller {
+				rsByRollout[ref.Name] = append(rsByRollout[ref.Name], rs)
+			}
+		}
+	}
+	for name, rsList := range rsByRollout {
+		active := []apps_v1.ReplicaSet{}
+		for _, rs := range rsList {
+			if rs.Spec.Replicas == nil || *rs.Spec.Replicas > 0 {
+				active = append(active, rs)
+			}
+		}
+		if len(active) == 0 {
+			continue
+		}
+		// The newest revision is the canary, the previous one is the stable. A single active revision is a stable rollout.
+		sort.Slice(active, func(i, j int) bool {
+			return argoRevision(active[i]) > argoRevision(active[j])
+		})
+		rollout := models.CanaryRollout{
+			Name:      name,
+			Namespace: namespace,
+			Tool:      models.ArgoRolloutsTool,
+			App:       active[0].Spec.Template.Labels[appLabel],
+		}
+		if len(active) == 1 {
+			rollout.Stable = replicaSetVariant(models.StableRole, active[0])
+		} else {
+			rollout.Canary = replicaSetVariant(models.CanaryRole, active[0])
+			rollout.Stable = replicaSetVariant(models.StableRole, active[1])
+		}
+		rollouts = append(rollouts, rollout)
+	}
+
+	sort.Slice(rollouts, func(i, j int) bool {
+		return rollouts[i].Name < rollouts[j].Name
+	})
+	return rollouts
+}
+
+func deploymentVariant(role string, d apps_v1.Deployment) *models.CanaryVariant {
+	variant := &models.CanaryVariant{
+		Role:              role,
+		Workload:          d.Name,
+		AvailableReplicas: d.Status.AvailableReplicas,
+	}
+	if d.Spec.Replicas != nil {
+		variant.DesiredReplicas = *d.Spec.Replicas
+	}
+	return variant
+}
+
+// replicaSetVariant uses the ReplicaSet name as workload: Argo pods are labeled with rollouts-pod-template-hash
+// instead of pod-template-hash, so Istio doesn't trim the hash when it names the workload in the telemetry
+func replicaSetVariant(role string, rs apps_v1.ReplicaSet) *models.CanaryVariant {
+	variant := &models.CanaryVariant{
+		Role:              role,
+		Workload:          rs.Name,
+		Revision:          rs.Annotations[argoRevisionAnnotation],
+		AvailableReplicas: rs.Status.AvailableReplicas,
+	}
+	if rs.Spec.Replicas != nil {
+		variant.DesiredReplicas = *rs.Spec.Replicas
+	}
+	return variant
+}
+
+func argoRevision(rs apps_v1.ReplicaSet) int {
+	revision, err := strconv.Atoi(rs.Annotations[argoRevisionAnnotation])
+	if err != nil {
+		return 0
+	}
+	return revision
+}
+
+// GetAppCanaryRollouts returns the progressive delivery rollouts of the pods labeled with the given app
+func (in *AppService) GetAppCanaryRollouts(namespace, app string) (models.CanaryRollouts, error) {
+	rollouts, err := in.businessLayer.Workload.GetCanaryRollouts(namespace)
+	if err != nil {
+		return nil, err
+	}
+	appRollouts := models.CanaryRollouts{}
+	for _, r := range rollouts {
+		if r.App == app {
+			appRollouts = append(appRollouts, r)
+		}
+	}
+	return appRollouts, nil
+}
@@ -0,0 +1,109 @@
+package business
+
+import (
+	"testing"
+	"time"
+
+	osproject_v1 "github.com/openshift/api/project/v1"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	apps_v1 "k8s.io/api/apps/v1"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes/kubetest"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/prometheus/prometheustest"
+	"github.com/kiali/kiali/tests/data"
+)
+
+func TestDetectFlaggerRollouts(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	deployments := append(data.CreateFlaggerDeployments("podinfo", "ns", 0, 2), data.CreateFlaggerDeployments("unrelated", "ns", 1, 1)[0])
+	rollouts := detectCanaryRollouts("ns", deployments, []apps_v1.ReplicaSet{})
+
+	assert.Len(rollouts, 1)
+	assert.Equal("podinfo", rollouts[0].Name)
+	assert.Equal(models.FlaggerTool, rollouts[0].Tool)
+	assert.Equal("podinfo", rollouts[0].App)
+	// The target Deployment is scaled down between analyses, but it's still reported
+	assert.Equal("podinfo", rollouts[0].Canary.Workload)
+	assert.Equal(int32(0), rollouts[0].Canary.DesiredReplicas)
+	assert.Equal("podinfo-primary", rollouts[0].Stable.Workload)
+	assert.Equal(int32(2), rollouts[0].Stable.DesiredReplicas)
+}
+
+func TestDetectArgoRollouts(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	replicaSets := []apps_v1.ReplicaSet{
+		data.CreateArgoRolloutReplicaSet("reviews", "ns", "6f9d8c7b5", 1, 0),
+		data.CreateArgoRolloutReplicaSet("reviews", "ns", "7c4b9d6f8", 2, 3),
+		data.CreateArgoRolloutReplicaSet("reviews", "ns", "5d8f7b9c6", 3, 1),
+		data.CreateArgoRolloutReplicaSet("ratings", "ns", "8b7c6d5f4", 4, 2),
+	}
+	rollouts := detectCanaryRollouts("ns", []apps_v1.Deployment{}, replicaSets)
+
+	assert.Len(rollouts, 2)
+	ratings := rollouts[0]
+	assert.Equal("ratings", ratings.Name)
+	assert.Equal(models.ArgoRolloutsTool, ratings.Tool)
+	assert.Nil(ratings.Canary)
+	assert.Equal("ratings-8b7c6d5f4", ratings.Stable.Workload)
+
+	reviews := rollouts[1]
+	assert.Equal("reviews", reviews.Name)
+	assert.Equal("reviews", reviews.App)
+	// The scaled down revision 1 is ignored
+	assert.Equal("reviews-5d8f7b9c6", reviews.Canary.Workload)
+	assert.Equal("3", reviews.Canary.Revision)
+	assert.Equal("reviews-7c4b9d6f8", reviews.Stable.Workload)
+	assert.Equal("2", reviews.Stable.Revision)
+}
+
+func TestGetCanaryRolloutMetrics(t *testing.T) {
+	assert := assert.New(t)
+
+	k8s := new(kubetest.K8SClientMock)
+	prom := new(prometheustest.PromClientMock)
+	conf := config.NewConfig()
+	config.Set(conf)
+
+	k8s.On("IsOpenShift").Return(true)
+	k8s.On("GetProject", mock.AnythingOfType("string")).Return(&osproject_v1.Project{}, nil)
+	k8s.On("GetDeployments", "ns").Return(data.CreateFlaggerDeployments("podinfo", "ns", 1, 2), nil)
+	k8s.On("GetReplicaSets", "ns").Return([]apps_v1.ReplicaSet{}, nil)
+
+	queryTime := time.Date(2017, 01, 15, 0, 0, 0, 0, time.UTC)
+	prom.MockWorkloadRequestRates("ns", "podinfo", model.Vector{
+		dependencySample("podinfo", "podinfo", "200", 9),
+		dependencySample("podinfo", "podinfo", "503", 1),
+	}, model.Vector{})
+	prom.MockWorkloadRequestRates("ns", "podinfo-primary", model.Vector{
+		dependencySample("podinfo-primary", "podinfo", "200", 20),
+	}, model.Vector{})
+	prom.On("FetchHistogramValues", "istio_request_duration_milliseconds", `{reporter="destination",destination_workload_namespace="ns",destination_workload="podinfo"}`, "", "1m", true, latencyQuantiles, queryTime).
+		Return(map[string]model.Vector{"0.99": {&model.Sample{Value: 120}}, "avg": {&model.Sample{Value: 40}}}, nil)
+	prom.On("FetchHistogramValues", "istio_request_duration_milliseconds", `{reporter="destination",destination_workload_namespace="ns",destination_workload="podinfo-primary"}`, "", "1m", true, latencyQuantiles, queryTime).
+		Return(map[string]model.Vector{"0.99": {&model.Sample{Value: 80}}, "avg": {&model.Sample{Value: 30}}}, nil)
+
+	svc := WorkloadService{k8s: k8s, prom: prom, businessLayer: NewWithBackends(k8s, prom, nil)}
+
+	metrics, err := svc.GetCanaryRolloutMetrics("ns", "podinfo", "1m", queryTime)
+	assert.NoError(err)
+	assert.Equal(models.FlaggerTool, metrics.Rollout.Tool)
+
+	assert.Equal(10.0, metrics.Canary.RequestRate)
+	assert.InDelta(0.9, *metrics.Canary.SuccessRate, 0.0001)
+	assert.Equal([]models.Stat{{Name: "0.99", Value: 120}, {Name: "avg", Value: 40}}, metrics.Canary.Latencies)
+
+	assert.Equal(20.0, metrics.Stable.RequestRate)
+	assert.Equal(1.0, *metrics.Stable.SuccessRate)
+	assert.Equal([]models.Stat{{Name: "0.99", Value: 80}, {Name: "avg", Value: 30}}, metrics.Stable.Latencies)
+
+	_, err = svc.GetCanaryRolloutMetrics("ns", "unknown", "1m", queryTime)
+	assert.Error(err)
+}
@@ -0,0 +1,39 @@
+package authorization
+
+import (
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+)
+
+// ExtensionProviderChecker flags the CUSTOM policies delegating the authorization to a provider not defined in the
+// mesh config, or whose service is not found in the mesh. The proxies deny all the requests of the first ones.
+type ExtensionProviderChecker struct {
+	AuthorizationPolicy kubernetes.IstioObject
+	ExtensionProviders  models.ExtensionProviders
+}
+
+func (e ExtensionProviderChecker) Check() ([]*models.IstioCheck, bool) {
+	checks, valid := make([]*models.IstioCheck, 0), true
+
+	if action, _ := e.AuthorizationPolicy.GetSpec()["action"].(string); action != "CUSTOM" {
+		return checks, valid
+	}
+	provider, _ := e.AuthorizationPolicy.GetSpec()["provider"].(map[string]interface{})
+	name, _ := provider["name"].(string)
+	if name == "" {
+		return checks, valid
+	}
+
+	definition := e.ExtensionProviders.Get(name)
+	switch {
+	case definition == nil:
+		check := models.Build("authorizationpolicy.provider.notfound", "spec/provider/name")
+		checks = append(checks, &check)
+		valid = false
+	case !definition.Reachable:
+		check := models.Build("authorizationpolicy.provider.unreachable", "spec/provider/name")
+		checks = append(checks, &check)
+	}
+
+	return checks, valid
+}
@@ -0,0 +1,62 @@
+package authorization
+
+import (
+	"testing"
+
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/tests/data"
+	"github.com/kiali/kiali/tests/data/validations"
+)
+
+var extensionProviders = models.ExtensionProviders{
+	{Name: "ext-authz", Type: "envoyExtAuthzHttp", Reachable: true},
+	{Name: "ext-authz-grpc", Type: "envoyExtAuthzGrpc", Reachable: false},
+}
+
+func TestExtensionProviderDefined(t *testing.T) {
+	policies := extensionProviderCheckerTestPrep(t)
+
+	vals, valid := ExtensionProviderChecker{AuthorizationPolicy: policies["ext-authz"], ExtensionProviders: extensionProviders}.Check()
+	validations.IstioCheckTestAsserter{T: t, Validations: vals, Valid: valid}.AssertNoValidations()
+}
+
+func TestExtensionProviderNotFound(t *testing.T) {
+	policies := extensionProviderCheckerTestPrep(t)
+
+	vals, valid := ExtensionProviderChecker{AuthorizationPolicy: policies["dangling-authz"], ExtensionProviders: extensionProviders}.Check()
+	ta := validations.IstioCheckTestAsserter{T: t, Validations: vals, Valid: valid}
+	ta.AssertValidationsPresent(1, false)
+	ta.AssertValidationAt(0, models.ErrorSeverity, "spec/provider/name", "authorizationpolicy.provider.notfound")
+}
+
+func TestExtensionProviderUnreachable(t *testing.T) {
+	policies := extensionProviderCheckerTestPrep(t)
+
+	vals, valid := ExtensionProviderChecker{AuthorizationPolicy: policies["unreachable-authz"], ExtensionProviders: extensionProviders}.Check()
+	ta := validations.IstioCheckTestAsserter{T: t, Validations: vals, Valid: valid}
+	ta.AssertValidationsPresent(1, true)
+	ta.AssertValidationAt(0, models.WarningSeverity, "spec/provider/name", "authorizationpolicy.provider.unreachable")
+}
+
+// Context: the provider of a policy is only used by the CUSTOM action
+func TestExtensionProviderIgnoredWithoutCustomAction(t *testing.T) {
+	policies := extensionProviderCheckerTestPrep(t)
+
+	vals, valid := ExtensionProviderChecker{AuthorizationPolicy: policies["allow-details"], ExtensionProviders: extensionProviders}.Check()
+	validations.IstioCheckTestAsserter{T: t, Validations: vals, Valid: valid}.AssertNoValidations()
+}
+
+func extensionProviderCheckerTestPrep(t *testing.T) map[string]kubernetes.IstioObject {
+	loader := &data.YamlFixtureLoader{Filename: "../../../tests/data/validations/extension_providers/references.yaml"}
+	err := loader.Load()
+	if err != nil {
+		t.Error("Error loading test data.")
+	}
+
+	policies := map[string]kubernetes.IstioObject{}
+	for _, policy := range loader.GetResources("AuthorizationPolicy") {
+		policies[policy.GetObjectMeta().Name] = policy
+	}
+	return policies
+}
@@ -0,0 +1,178 @@
+package authorization
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+)
+
+// The methods which don't change the state of the destination
+var readOnlyMethods = map[string]bool{
+	"GET":     true,
+	"HEAD":    true,
+	"OPTIONS": true,
+}
+
+// The path prefixes usually exposing the administration of a service
+var sensitivePathPrefixes = []string{"/admin", "/debug", "/internal", "/actuator", "/config"}
+
+// PermissiveChecker flags the ALLOW rules opening the workloads too widely: any source allowed to sensitive
+// operations, or any principal or namespace allowed. The checks are ranked by risk, the most permissive first.
+type PermissiveChecker struct {
+	AuthorizationPolicy kubernetes.IstioObject
+}
+
+func (p PermissiveChecker) Check() ([]*models.IstioCheck, bool) {
+	checks, valid := make([]*models.IstioCheck, 0), true
+
+	// DENY, AUDIT and CUSTOM policies don't open access
+	if action, ok := p.AuthorizationPolicy.GetSpec()["action"].(string); ok && action != "" && action != "ALLOW" {
+		return checks, valid
+	}
+
+	// Getting rules array. If not present, quitting validation.
+	rulesStct, ok := p.AuthorizationPolicy.GetSpec()["rules"]
+	if !ok {
+		return checks, valid
+	}
+
+	// Getting slice of Rules. Quitting if not an slice.
+	rules := reflect.ValueOf(rulesStct)
+	if rules.Kind() != reflect.Slice {
+		return checks, valid
+	}
+
+	for ruleIdx := 0; ruleIdx < rules.Len(); ruleIdx++ {
+		rule, ok := rules.Index(ruleIdx).Interface().(map[string]interface{})
+		if !ok || rule == nil {
+			continue
+		}
+
+		// The conditions of a rule scope the sources, like the from field
+		if anySource(rule["from"]) && rule["when"] == nil {
+			checks = append(checks, sensitiveOperationChecks(ruleIdx, rule["to"])...)
+		}
+		checks = append(checks, wildcardSourceChecks(ruleIdx, rule["from"])...)
+	}
+
+	sort.SliceStable(checks, func(i, j int) bool {
+		return !checks[j].Severity.AtLeast(checks[i].Severity)
+	})
+	for _, check := range checks {
+		valid = valid && check.Severity != models.ErrorSeverity
+	}
+
+	return checks, valid
+}
+
+// anySource tells whether a rule matches the requests of any source: no from field, or a source without field
+func anySource(from interface{}) bool {
+	fromSl, ok := from.([]interface{})
+	if !ok || len(fromSl) == 0 {
+		return true
+	}
+	for _, fromStc := range fromSl {
+		fromMap, ok := fromStc.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if sourceMap, ok := fromMap["source"].(map[string]interface{}); !ok || len(sourceMap) == 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// sensitiveOperationChecks flags the operations of a rule which aren't read-only or target an administration path.
+// A rule without to field allows all the operations.
+func sensitiveOperationChecks(ruleIdx int, to interface{}) []*models.IstioCheck {
+	toSl, ok := to.([]interface{})
+	if !ok || len(toSl) == 0 {
+		validation := models.Build("authorizationpolicy.source.anysource", fmt.Sprintf("spec/rules[%d]", ruleIdx))
+		return []*models.IstioCheck{&validation}
+	}
+
+	checks := make([]*models.IstioCheck, 0, len(toSl))
+	for toIdx, toStc := range toSl {
+		toMap, ok := toStc.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		operationMap, ok := toMap["operation"].(map[string]interface{})
+		if !ok || sensitiveOperation(operationMap) {
+			validation := models.Build("authorizationpolicy.source.anysource", fmt.Sprintf("spec/rules[%d]/to[%d]/operation", ruleIdx, toIdx))
+			checks = append(checks, &validation)
+		}
+	}
+	return checks
+}
+
+func sensitiveOperation(operation map[string]interface{}) bool {
+	methods, _ := operation["methods"].([]interface{})
+	if len(methods) == 0 {
+		return true
+	}
+	for _, m := range methods {
+		if method, ok := m.(string); !ok || !readOnlyMethods[strings.TrimSpace(strings.ToUpper(method))] {
+			return true
+		}
+	}
+
+	paths, _ := operation["paths"].([]interface{})
+	for _, p := range paths {
+		path, ok := p.(string)
+		if !ok {
+			continue
+		}
+		if path == "*" {
+			return true
+		}
+		for _, prefix := range sensitivePathPrefixes {
+			if strings.HasPrefix(strings.ToLower(path), prefix) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// wildcardSourceChecks flags the sources allowing any principal or any namespace
+func wildcardSourceChecks(ruleIdx int, from interface{}) []*models.IstioCheck {
+	fromSl, ok := from.([]interface{})
+	if !ok {
+		return nil
+	}
+
+	checks := make([]*models.IstioCheck, 0)
+	for fromIdx, fromStc := range fromSl {
+		fromMap, ok := fromStc.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		sourceMap, ok := fromMap["source"].(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		for _, field := range []string{"principals", "namespaces"} {
+			values, ok := sourceMap[field].([]interface{})
+			if !ok {
+				continue
+			}
+			for i, v := range values {
+				if v == "*" {
+					path := fmt.Sprintf("spec/rules[%d]/from[%d]/source/%s[%d]", ruleIdx, fromIdx, field, i)
+					validation := models.Build("authorizationpolicy.source.any"+strings.TrimSuffix(field, "s"), path)
+					checks = append(checks, &validation)
+				}
+			}
+		}
+	}
+	return checks
+}
@@ -0,0 +1,68 @@
+package authorization
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/tests/data/validations"
+)
+
+// Context: ALLOW policies open to any source, or to any principal or namespace
+// It returns the validations ranked by risk
+func TestPermissivePolicies(t *testing.T) {
+	policies := permissiveCheckerTestPrep("permissive_checker_1.yaml", t)
+
+	vals, valid := PermissiveChecker{AuthorizationPolicy: policies["allow-all"]}.Check()
+	ta := validations.IstioCheckTestAsserter{T: t, Validations: vals, Valid: valid}
+	ta.AssertValidationsPresent(1, false)
+	ta.AssertValidationAt(0, models.ErrorSeverity, "spec/rules[0]", "authorizationpolicy.source.anysource")
+
+	// The read-only operation isn't flagged
+	vals, valid = PermissiveChecker{AuthorizationPolicy: policies["anyone-writes"]}.Check()
+	ta = validations.IstioCheckTestAsserter{T: t, Validations: vals, Valid: valid}
+	ta.AssertValidationsPresent(1, false)
+	ta.AssertValidationAt(0, models.ErrorSeverity, "spec/rules[0]/to[1]/operation", "authorizationpolicy.source.anysource")
+
+	vals, valid = PermissiveChecker{AuthorizationPolicy: policies["anyone-admin"]}.Check()
+	ta = validations.IstioCheckTestAsserter{T: t, Validations: vals, Valid: valid}
+	ta.AssertValidationsPresent(1, false)
+	ta.AssertValidationAt(0, models.ErrorSeverity, "spec/rules[0]/to[0]/operation", "authorizationpolicy.source.anysource")
+
+	// The rule open to any source comes first, whatever its position
+	vals, valid = PermissiveChecker{AuthorizationPolicy: policies["any-principal"]}.Check()
+	ta = validations.IstioCheckTestAsserter{T: t, Validations: vals, Valid: valid}
+	ta.AssertValidationsPresent(3, false)
+	ta.AssertValidationAt(0, models.ErrorSeverity, "spec/rules[1]/to[0]/operation", "authorizationpolicy.source.anysource")
+	ta.AssertValidationAt(1, models.WarningSeverity, "spec/rules[0]/from[0]/source/principals[0]", "authorizationpolicy.source.anyprincipal")
+	ta.AssertValidationAt(2, models.WarningSeverity, "spec/rules[0]/from[1]/source/namespaces[0]", "authorizationpolicy.source.anynamespace")
+}
+
+// Context: ALLOW policies scoped by source, operations or conditions, and DENY policies
+// It doesn't return any validation
+func TestScopedPolicies(t *testing.T) {
+	policies := permissiveCheckerTestPrep("permissive_checker_2.yaml", t)
+	assert.Len(t, policies, 5)
+
+	for name, policy := range policies {
+		vals, valid := PermissiveChecker{AuthorizationPolicy: policy}.Check()
+		assert.Empty(t, vals, name)
+		assert.True(t, valid, name)
+	}
+}
+
+func permissiveCheckerTestPrep(scenario string, t *testing.T) map[string]kubernetes.IstioObject {
+	loader := yamlFixtureLoaderFor(scenario)
+	err := loader.Load()
+	if err != nil {
+		t.Error("Error loading test data.")
+	}
+
+	policies := map[string]kubernetes.IstioObject{}
+	for _, policy := range loader.GetResources("AuthorizationPolicy") {
+		policies[policy.GetObjectMeta().Name] = policy
+	}
+	return policies
+}
@@ -20,6 +20,8 @@ type AuthorizationPolicyChecker struct {
 	WorkloadList          models.WorkloadList
 	MtlsDetails           kubernetes.MTLSDetails
 	VirtualServices       []kubernetes.IstioObject
+	// The extension providers of the mesh config, nil when unknown
+	ExtensionProviders models.ExtensionProviders
 }
 
 func (a AuthorizationPolicyChecker) Check() models.IstioValidations {
@@ -49,10 +51,15 @@ func (a AuthorizationPolicyChecker) runChecks(authPolicy kubernetes.IstioObject)
 	enabledCheckers := []Checker{
 		common.SelectorNoWorkloadFoundChecker(AuthorizationPolicyCheckerType, authPolicy, a.WorkloadList),
 		authorization.NamespaceMethodChecker{AuthorizationPolicy: authPolicy, Namespaces: a.Namespaces.GetNames()},
+		authorization.PermissiveChecker{AuthorizationPolicy: authPolicy},
 		authorization.NoHostChecker{AuthorizationPolicy: authPolicy, Namespace: a.Namespace, Namespaces: a.Namespaces,
 			ServiceEntries: serviceHosts, Services: a.Services, VirtualServices: a.VirtualServices},
 	}
 
+	if a.ExtensionProviders != nil {
+		enabledCheckers = append(enabledCheckers, authorization.ExtensionProviderChecker{AuthorizationPolicy: authPolicy, ExtensionProviders: a.ExtensionProviders})
+	}
+
 	for _, checker := range enabledCheckers {
 		checks, validChecker := checker.Check()
 		rrValidation.Checks = append(rrValidation.Checks, checks...)
@@ -49,13 +49,23 @@ func (wsc GenericNoWorkloadFoundChecker) Check() ([]*models.IstioCheck, bool) {
 }
 
 func (wsc GenericNoWorkloadFoundChecker) hasMatchingWorkload(labelSelector map[string]string) bool {
+	return len(wsc.matchingWorkloads(labelSelector)) > 0
+}
+
+// MatchingWorkloads returns the workloads selected by the subject, all of them when it has no selector
+func (wsc GenericNoWorkloadFoundChecker) MatchingWorkloads() []models.WorkloadListItem {
+	return wsc.matchingWorkloads(wsc.GetSelectorLabels(wsc.Subject))
+}
+
+func (wsc GenericNoWorkloadFoundChecker) matchingWorkloads(labelSelector map[string]string) []models.WorkloadListItem {
 	selector := labels.SelectorFromSet(labelSelector)
 
+	workloads := make([]models.WorkloadListItem, 0)
 	for _, wl := range wsc.WorkloadList.Workloads {
 		wlLabelSet := labels.Set(wl.Labels)
 		if selector.Matches(wlLabelSet) {
-			return true
+			workloads = append(workloads, wl)
 		}
 	}
-	return false
+	return workloads
 }
@@ -0,0 +1,41 @@
+package checkers
+
+import (
+	"github.com/kiali/kiali/business/checkers/envoyfilters"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+)
+
+const EnvoyFilterCheckerType = "envoyfilter"
+
+type EnvoyFilterChecker struct {
+	EnvoyFilters     []kubernetes.IstioObject
+	MeshEnvoyFilters []kubernetes.IstioObject
+}
+
+func (e EnvoyFilterChecker) Check() models.IstioValidations {
+	validations := models.IstioValidations{}
+
+	for _, envoyFilter := range e.EnvoyFilters {
+		validations.MergeValidations(e.runChecks(envoyFilter))
+	}
+
+	return validations
+}
+
+// runChecks runs all the individual checks for a single envoy filter and appends the result into validations.
+func (e EnvoyFilterChecker) runChecks(envoyFilter kubernetes.IstioObject) models.IstioValidations {
+	key, validation := EmptyValidValidation(envoyFilter.GetObjectMeta().Name, envoyFilter.GetObjectMeta().Namespace, EnvoyFilterCheckerType)
+
+	enabledCheckers := []Checker{
+		envoyfilters.RateLimitChecker{EnvoyFilter: envoyFilter, EnvoyFilters: e.EnvoyFilters, MeshEnvoyFilters: e.MeshEnvoyFilters},
+	}
+
+	for _, checker := range enabledCheckers {
+		checks, validChecker := checker.Check()
+		validation.Checks = append(validation.Checks, checks...)
+		validation.Valid = validation.Valid && validChecker
+	}
+
+	return models.IstioValidations{key: validation}
+}
@@ -0,0 +1,59 @@
+package envoyfilters
+
+import (
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+)
+
+// RateLimitChecker checks that the rate limits configured by the EnvoyFilter are complete: local rate limits
+// need a token bucket, and global rate limits a rate limit service (RLS) cluster that is defined. The cluster
+// is defined when an EnvoyFilter of the namespace or of the root namespace adds it, or when it is a cluster
+// that Istio generates for a service of its registry.
+type RateLimitChecker struct {
+	EnvoyFilter      kubernetes.IstioObject
+	EnvoyFilters     []kubernetes.IstioObject
+	MeshEnvoyFilters []kubernetes.IstioObject
+}
+
+func (r RateLimitChecker) Check() ([]*models.IstioCheck, bool) {
+	checks := make([]*models.IstioCheck, 0)
+
+	var clusters map[string]bool
+	for _, rateLimit := range models.ParseRateLimits(r.EnvoyFilter) {
+		path := rateLimit.Path + "/patch/value"
+		switch rateLimit.Type {
+		case models.LocalRateLimit:
+			if rateLimit.MaxTokens == nil || rateLimit.FillInterval == "" {
+				check := models.Build("envoyfilter.ratelimit.tokenbucketinvalid", path)
+				checks = append(checks, &check)
+			}
+		case models.GlobalRateLimit:
+			if rateLimit.RateLimitService == "" {
+				check := models.Build("envoyfilter.ratelimit.rlsmissing", path)
+				checks = append(checks, &check)
+				continue
+			}
+			if clusters == nil {
+				clusters = r.definedClusters()
+			}
+			if !clusters[rateLimit.RateLimitService] && !models.IsIstioCluster(rateLimit.RateLimitService) {
+				check := models.Build("envoyfilter.ratelimit.rlsnotfound", path)
+				checks = append(checks, &check)
+			}
+		}
+	}
+
+	return checks, len(checks) == 0
+}
+
+func (r RateLimitChecker) definedClusters() map[string]bool {
+	clusters := map[string]bool{}
+	for _, envoyFilters := range [][]kubernetes.IstioObject{r.EnvoyFilters, r.MeshEnvoyFilters, {r.EnvoyFilter}} {
+		for _, envoyFilter := range envoyFilters {
+			for _, cluster := range models.EnvoyFilterClusters(envoyFilter) {
+				clusters[cluster] = true
+			}
+		}
+	}
+	return clusters
+}
@@ -0,0 +1,82 @@
+package envoyfilters
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/tests/data"
+	"github.com/kiali/kiali/tests/data/validations"
+)
+
+func TestValidLocalRateLimits(t *testing.T) {
+	loader := rateLimitTestPrep("local_rate_limit.yaml", t)
+
+	for _, envoyFilter := range loader.GetResources("EnvoyFilter") {
+		vals, valid := RateLimitChecker{EnvoyFilter: envoyFilter}.Check()
+		validations.IstioCheckTestAsserter{T: t, Validations: vals, Valid: valid}.AssertNoValidations()
+	}
+}
+
+func TestInvalidLocalRateLimit(t *testing.T) {
+	loader := rateLimitTestPrep("invalid_local_rate_limit.yaml", t)
+
+	vals, valid := RateLimitChecker{EnvoyFilter: loader.GetFirstResource("EnvoyFilter")}.Check()
+	tb := validations.IstioCheckTestAsserter{T: t, Validations: vals, Valid: valid}
+	tb.AssertValidationsPresent(1, false)
+	tb.AssertValidationAt(0, models.ErrorSeverity, "spec/configPatches[0]/patch/value", "envoyfilter.ratelimit.tokenbucketinvalid")
+}
+
+func TestGlobalRateLimitClusterInMeshEnvoyFilter(t *testing.T) {
+	loader := rateLimitTestPrep("global_rate_limit.yaml", t)
+	meshEnvoyFilters := loader.GetResourcesIn("EnvoyFilter", "istio-system")
+
+	// The cluster is added by the EnvoyFilter itself
+	vals, valid := RateLimitChecker{EnvoyFilter: meshEnvoyFilters[0]}.Check()
+	validations.IstioCheckTestAsserter{T: t, Validations: vals, Valid: valid}.AssertNoValidations()
+
+	// The cluster generated by Istio for the rate limit service is defined
+	ratings := loader.GetResource("EnvoyFilter", "ratings-ratelimit", "bookinfo")
+	vals, valid = RateLimitChecker{EnvoyFilter: ratings, EnvoyFilters: []kubernetes.IstioObject{ratings}}.Check()
+	validations.IstioCheckTestAsserter{T: t, Validations: vals, Valid: valid}.AssertNoValidations()
+}
+
+func TestGlobalRateLimitUndefinedCluster(t *testing.T) {
+	loader := rateLimitTestPrep("global_rate_limit_undefined_cluster.yaml", t)
+	reviews := loader.GetFirstResource("EnvoyFilter")
+
+	vals, valid := RateLimitChecker{EnvoyFilter: reviews, EnvoyFilters: []kubernetes.IstioObject{reviews}}.Check()
+	tb := validations.IstioCheckTestAsserter{T: t, Validations: vals, Valid: valid}
+	tb.AssertValidationsPresent(2, false)
+	tb.AssertValidationAt(0, models.ErrorSeverity, "spec/configPatches[0]/patch/value", "envoyfilter.ratelimit.rlsnotfound")
+	tb.AssertValidationAt(1, models.ErrorSeverity, "spec/configPatches[1]/patch/value", "envoyfilter.ratelimit.rlsmissing")
+}
+
+func TestGlobalRateLimitClusterAddedInRootNamespace(t *testing.T) {
+	loader := rateLimitTestPrep("global_rate_limit_undefined_cluster.yaml", t)
+	reviews := loader.GetFirstResource("EnvoyFilter")
+	meshLoader := rateLimitTestPrep("global_rate_limit.yaml", t)
+
+	vals, valid := RateLimitChecker{EnvoyFilter: reviews, EnvoyFilters: []kubernetes.IstioObject{reviews},
+		MeshEnvoyFilters: meshLoader.GetResourcesIn("EnvoyFilter", "istio-system")}.Check()
+	tb := validations.IstioCheckTestAsserter{T: t, Validations: vals, Valid: valid}
+	tb.AssertValidationsPresent(1, false)
+	tb.AssertValidationAt(0, models.ErrorSeverity, "spec/configPatches[1]/patch/value", "envoyfilter.ratelimit.rlsmissing")
+}
+
+func rateLimitTestPrep(scenario string, t *testing.T) *data.YamlFixtureLoader {
+	config.Set(config.NewConfig())
+
+	loader := yamlFixtureLoaderFor(scenario)
+	if err := loader.Load(); err != nil {
+		t.Error("Error loading test data.")
+	}
+	return loader
+}
+
+func yamlFixtureLoaderFor(file string) *data.YamlFixtureLoader {
+	path := fmt.Sprintf("../../../tests/data/validations/envoyfilters/%s", file)
+	return &data.YamlFixtureLoader{Filename: path}
+}
@@ -12,6 +12,7 @@ type GatewayChecker struct {
 	GatewaysPerNamespace  [][]kubernetes.IstioObject
 	Namespace             string
 	WorkloadsPerNamespace map[string]models.WorkloadList
+	CredentialSecrets     gateways.CredentialSecrets
 }
 
 // Check runs checks for the all namespaces actions as well as for the single namespace validations
@@ -41,6 +42,11 @@ func (g GatewayChecker) runSingleChecks(gw kubernetes.IstioObject) models.IstioV
 			Gateway:               gw,
 			WorkloadsPerNamespace: g.WorkloadsPerNamespace,
 		},
+		gateways.CredentialChecker{
+			Gateway:               gw,
+			WorkloadsPerNamespace: g.WorkloadsPerNamespace,
+			CredentialSecrets:     g.CredentialSecrets,
+		},
 	}
 
 	for _, checker := range enabledCheckers {
@@ -0,0 +1,204 @@
+package gateways
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"sort"
+	"time"
+
+	core_v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/util"
+)
+
+// A certificate expiring within this period is reported before it breaks the ingress
+const credentialExpiryWarning = 30 * 24 * time.Hour
+
+// The TLS modes where the gateway doesn't terminate TLS with its own certificate
+var credentialIgnoredModes = map[string]bool{
+	"PASSTHROUGH":      true,
+	"AUTO_PASSTHROUGH": true,
+	"ISTIO_MUTUAL":     true,
+}
+
+// CredentialSecrets are the Secrets of the namespaces of the gateway workloads, where the credentials are looked up
+type CredentialSecrets struct {
+	Secrets []core_v1.Secret
+	// The namespaces whose Secrets can't be read by Kiali
+	Unreadable map[string]bool
+}
+
+// CredentialChecker checks that the certificates of the TLS servers of the Gateway exist and are not expired.
+// The Secret of a credentialName is looked up in the namespaces of the gateway workloads, as the gateways do.
+// The certificates mounted from files of the gateway workloads can't be read, they are reported as unverified.
+type CredentialChecker struct {
+	Gateway               kubernetes.IstioObject
+	WorkloadsPerNamespace map[string]models.WorkloadList
+	CredentialSecrets     CredentialSecrets
+}
+
+func (c CredentialChecker) Check() ([]*models.IstioCheck, bool) {
+	checks := make([]*models.IstioCheck, 0)
+
+	servers, _ := c.Gateway.GetSpec()["servers"].([]interface{})
+	for i, server := range servers {
+		credentialName, serverCertificate, checked := serverCredential(server)
+		if !checked {
+			continue
+		}
+		if credentialName == "" {
+			if serverCertificate != "" {
+				check := models.Build("gateways.credential.filemounted", fmt.Sprintf("spec/servers[%d]/tls/serverCertificate", i))
+				checks = append(checks, &check)
+			}
+			continue
+		}
+		path := fmt.Sprintf("spec/servers[%d]/tls/credentialName", i)
+		for _, namespace := range CredentialNamespaces(c.Gateway, c.WorkloadsPerNamespace) {
+			checks = append(checks, c.checkCredential(namespace, credentialName, path)...)
+		}
+	}
+
+	valid := true
+	for _, check := range checks {
+		if check.Severity == models.ErrorSeverity {
+			valid = false
+		}
+	}
+	return checks, valid
+}
+
+func (c CredentialChecker) checkCredential(namespace, credentialName, path string) []*models.IstioCheck {
+	secretName := fmt.Sprintf("%s/%s", namespace, credentialName)
+	if c.CredentialSecrets.Unreadable[namespace] {
+		check := models.BuildWithDetail("gateways.credential.unreadable", path, secretName)
+		return []*models.IstioCheck{&check}
+	}
+
+	var secret *core_v1.Secret
+	for i := range c.CredentialSecrets.Secrets {
+		s := &c.CredentialSecrets.Secrets[i]
+		if s.Namespace == namespace && s.Name == credentialName {
+			secret = s
+			break
+		}
+	}
+	if secret == nil {
+		check := models.BuildWithDetail("gateways.credential.notfound", path, secretName)
+		return []*models.IstioCheck{&check}
+	}
+
+	cert := secretCertificate(secret)
+	if cert == nil {
+		check := models.BuildWithDetail("gateways.credential.invalid", path, secretName)
+		return []*models.IstioCheck{&check}
+	}
+	now := util.Clock.Now()
+	detail := fmt.Sprintf("%s expires on %s", secretName, cert.NotAfter.UTC().Format(time.RFC3339))
+	if now.After(cert.NotAfter) {
+		check := models.BuildWithDetail("gateways.credential.expired", path, detail)
+		return []*models.IstioCheck{&check}
+	}
+	if now.Add(credentialExpiryWarning).After(cert.NotAfter) {
+		check := models.BuildWithDetail("gateways.credential.expiring", path, detail)
+		return []*models.IstioCheck{&check}
+	}
+	return []*models.IstioCheck{}
+}
+
+// CredentialNamespaces returns the namespaces where the Secrets of the credentialNames of the Gateway are looked up:
+// the namespaces of the workloads selected by the Gateway, or the namespace of the Gateway when none is found.
+// It returns nothing when the Gateway has no credentialName to check.
+func CredentialNamespaces(gw kubernetes.IstioObject, workloadsPerNamespace map[string]models.WorkloadList) []string {
+	hasCredential := false
+	servers, _ := gw.GetSpec()["servers"].([]interface{})
+	for _, server := range servers {
+		if credentialName, _, checked := serverCredential(server); checked && credentialName != "" {
+			hasCredential = true
+			break
+		}
+	}
+	if !hasCredential {
+		return nil
+	}
+
+	namespaces := []string{}
+	if selectors, ok := gw.GetSpec()["selector"].(map[string]interface{}); ok && len(selectors) > 0 {
+		labelSelectors := make(map[string]string, len(selectors))
+		for k, v := range selectors {
+			labelSelectors[k], _ = v.(string)
+		}
+		selector := labels.SelectorFromSet(labelSelectors)
+		for namespace, wls := range workloadsPerNamespace {
+			for _, wl := range wls.Workloads {
+				if selector.Matches(labels.Set(wl.Labels)) {
+					namespaces = append(namespaces, namespace)
+					break
+				}
+			}
+		}
+	}
+	if len(namespaces) == 0 {
+		return []string{gw.GetObjectMeta().Namespace}
+	}
+	sort.Strings(namespaces)
+	return namespaces
+}
+
+// CredentialNames returns the credentialNames of the TLS servers of the Gateway which are checked
+func CredentialNames(gw kubernetes.IstioObject) []string {
+	names := []string{}
+	servers, _ := gw.GetSpec()["servers"].([]interface{})
+	for _, server := range servers {
+		if credentialName, _, checked := serverCredential(server); checked && credentialName != "" {
+			names = append(names, credentialName)
+		}
+	}
+	return names
+}
+
+// serverCredential returns the credentialName and the serverCertificate of the TLS settings of the server,
+// and whether the gateway terminates TLS with them
+func serverCredential(server interface{}) (string, string, bool) {
+	serverDef, ok := server.(map[string]interface{})
+	if !ok {
+		return "", "", false
+	}
+	tls, ok := serverDef["tls"].(map[string]interface{})
+	if !ok {
+		return "", "", false
+	}
+	if mode, _ := tls["mode"].(string); credentialIgnoredModes[mode] {
+		return "", "", false
+	}
+	credentialName, _ := tls["credentialName"].(string)
+	serverCertificate, _ := tls["serverCertificate"].(string)
+	return credentialName, serverCertificate, true
+}
+
+// secretCertificate returns the certificate of the Secret, either a TLS Secret or a generic Secret with a cert key
+func secretCertificate(secret *core_v1.Secret) *x509.Certificate {
+	data := secret.Data[core_v1.TLSCertKey]
+	if len(data) == 0 {
+		data = secret.Data["cert"]
+	}
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			return nil
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil
+		}
+		return cert
+	}
+}
@@ -0,0 +1,184 @@
+package gateways
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v2"
+	core_v1 "k8s.io/api/core/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/tests/data"
+	"github.com/kiali/kiali/tests/data/validations"
+	"github.com/kiali/kiali/util"
+)
+
+func TestValidCredential(t *testing.T) {
+	vals, valid := credentialTestPrep("credential-valid.yaml", nil, t)
+
+	tb := validations.IstioCheckTestAsserter{T: t, Validations: vals, Valid: valid}
+	tb.AssertNoValidations()
+}
+
+// Context: the Secret of the credentialName is in the namespace of the Gateway, not in the one of the gateway workload
+func TestMissingCredential(t *testing.T) {
+	assert := assert.New(t)
+	vals, valid := credentialTestPrep("credential-missing.yaml", nil, t)
+
+	assert.False(valid)
+	assert.Len(vals, 1)
+	assertCredentialCheck(assert, vals[0], "gateways.credential.notfound", models.ErrorSeverity, "spec/servers[0]/tls/credentialName", "istio-system/bookinfo-cert")
+}
+
+func TestExpiringCredentials(t *testing.T) {
+	assert := assert.New(t)
+	vals, valid := credentialTestPrep("credential-expiring.yaml", nil, t)
+
+	assert.False(valid)
+	assert.Len(vals, 2)
+	assertCredentialCheck(assert, vals[0], "gateways.credential.expiring", models.WarningSeverity, "spec/servers[0]/tls/credentialName",
+		"istio-system/expiring-cert expires on 2022-01-15T00:00:00Z")
+	assertCredentialCheck(assert, vals[1], "gateways.credential.expired", models.ErrorSeverity, "spec/servers[1]/tls/credentialName",
+		"istio-system/expired-cert expires on 2021-06-01T00:00:00Z")
+}
+
+func TestFileMountedCredential(t *testing.T) {
+	vals, valid := credentialTestPrep("credential-file-mounted.yaml", nil, t)
+
+	tb := validations.IstioCheckTestAsserter{T: t, Validations: vals, Valid: valid}
+	tb.AssertValidationsPresent(1, true)
+	tb.AssertValidationAt(0, models.Unknown, "spec/servers[0]/tls/serverCertificate", "gateways.credential.filemounted")
+}
+
+func TestUnreadableCredential(t *testing.T) {
+	assert := assert.New(t)
+	vals, valid := credentialTestPrep("credential-missing.yaml", map[string]bool{"istio-system": true}, t)
+
+	assert.True(valid)
+	assert.Len(vals, 1)
+	assertCredentialCheck(assert, vals[0], "gateways.credential.unreadable", models.Unknown, "spec/servers[0]/tls/credentialName", "istio-system/bookinfo-cert")
+}
+
+func TestInvalidCredential(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	gw := data.AddServerToGateway(data.CreateServer([]string{"bookinfo.example.com"}, 443, "https", "https"),
+		data.CreateEmptyGateway("bookinfo-gateway", "bookinfo", map[string]string{"istio": "ingressgateway"}))
+	gw.GetSpec()["servers"].([]interface{})[0].(map[string]interface{})["tls"] = map[string]interface{}{
+		"mode":           "SIMPLE",
+		"credentialName": "bookinfo-cert",
+	}
+
+	vals, valid := CredentialChecker{
+		Gateway: gw,
+		CredentialSecrets: CredentialSecrets{Secrets: []core_v1.Secret{
+			{
+				ObjectMeta: meta_v1.ObjectMeta{Name: "bookinfo-cert", Namespace: "bookinfo"},
+				Data:       map[string][]byte{"tls.crt": []byte("not a certificate")},
+			},
+		}},
+	}.Check()
+
+	// Without gateway workload, the Secret is looked up in the namespace of the Gateway
+	assert.False(valid)
+	assert.Len(vals, 1)
+	assertCredentialCheck(assert, vals[0], "gateways.credential.invalid", models.ErrorSeverity, "spec/servers[0]/tls/credentialName", "bookinfo/bookinfo-cert")
+}
+
+func TestCredentialNamespaces(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	loader := yamlFixtureLoaderFor("credential-valid.yaml")
+	if err := loader.Load(); err != nil {
+		t.Fatal("Error loading test data.")
+	}
+	gw := loader.GetFirstResource("Gateway")
+	assert.Equal([]string{"ingress-a", "istio-system"}, CredentialNamespaces(gw, map[string]models.WorkloadList{
+		"istio-system": data.CreateWorkloadList("istio-system",
+			data.CreateWorkloadListItem("istio-ingressgateway", map[string]string{"istio": "ingressgateway"})),
+		"ingress-a": data.CreateWorkloadList("ingress-a",
+			data.CreateWorkloadListItem("ingress-a-gateway", map[string]string{"istio": "ingressgateway"})),
+		"bookinfo": data.CreateWorkloadList("bookinfo",
+			data.CreateWorkloadListItem("reviews-v1", map[string]string{"app": "reviews"})),
+	}))
+	assert.Equal([]string{"bookinfo"}, CredentialNamespaces(gw, map[string]models.WorkloadList{}))
+
+	// Without credentialName, no Secret is looked up
+	assert.Nil(CredentialNamespaces(data.CreateEmptyGateway("bookinfo-gateway", "bookinfo", map[string]string{"istio": "ingressgateway"}), nil))
+}
+
+func assertCredentialCheck(assert *assert.Assertions, check *models.IstioCheck, checkId string, severity models.SeverityLevel, path, detail string) {
+	assert.Equal(severity, check.Severity)
+	assert.Equal(path, check.Path)
+	assert.Equal(fmt.Sprintf("%s: %s", models.CheckMessage(checkId), detail), check.Message)
+}
+
+func credentialTestPrep(scenario string, unreadable map[string]bool, t *testing.T) ([]*models.IstioCheck, bool) {
+	config.Set(config.NewConfig())
+	util.Clock = util.ClockMock{Time: time.Date(2022, 01, 01, 0, 0, 0, 0, time.UTC)}
+	defer func() { util.Clock = util.RealClock{} }()
+
+	loader := yamlFixtureLoaderFor(scenario)
+	if err := loader.Load(); err != nil {
+		t.Fatal("Error loading test data.")
+	}
+
+	return CredentialChecker{
+		Gateway: loader.GetFirstResource("Gateway"),
+		WorkloadsPerNamespace: map[string]models.WorkloadList{
+			"istio-system": data.CreateWorkloadList("istio-system",
+				data.CreateWorkloadListItem("istio-ingressgateway", map[string]string{"istio": "ingressgateway"})),
+		},
+		CredentialSecrets: CredentialSecrets{Secrets: loadSecrets(scenario, t), Unreadable: unreadable},
+	}.Check()
+}
+
+// fixtureSecret is the part of a Secret read from the fixtures: the Kubernetes types can't be decoded from YAML
+type fixtureSecret struct {
+	Kind     string             `yaml:"kind"`
+	Metadata meta_v1.ObjectMeta `yaml:"metadata"`
+	Type     string             `yaml:"type"`
+	Data     map[string]string  `yaml:"data"`
+}
+
+func loadSecrets(scenario string, t *testing.T) []core_v1.Secret {
+	content, err := ioutil.ReadFile(fixturePath(scenario))
+	if err != nil {
+		t.Fatal(err)
+	}
+	secrets := []core_v1.Secret{}
+	dec := yaml.NewDecoder(bytes.NewReader(content))
+	for {
+		var resource fixtureSecret
+		if err := dec.Decode(&resource); err != nil {
+			return secrets
+		}
+		if resource.Kind != "Secret" {
+			continue
+		}
+		secret := core_v1.Secret{ObjectMeta: resource.Metadata, Type: core_v1.SecretType(resource.Type), Data: map[string][]byte{}}
+		for k, v := range resource.Data {
+			if secret.Data[k], err = base64.StdEncoding.DecodeString(v); err != nil {
+				t.Fatal(err)
+			}
+		}
+		secrets = append(secrets, secret)
+	}
+}
+
+func yamlFixtureLoaderFor(file string) *data.YamlFixtureLoader {
+	return &data.YamlFixtureLoader{Filename: fixturePath(file)}
+}
+
+func fixturePath(file string) string {
+	return fmt.Sprintf("../../../tests/data/validations/gateways/%s", file)
+}
@@ -0,0 +1,52 @@
+package checkers
+
+import (
+	core_v1 "k8s.io/api/core/v1"
+
+	"github.com/kiali/kiali/business/checkers/workloads"
+	"github.com/kiali/kiali/models"
+)
+
+const (
+	NamespaceCheckerType = "namespace"
+	PodCheckerType       = "pod"
+)
+
+// InjectionChecker flags the conflicting sidecar injection configurations of a namespace and of its pods
+type InjectionChecker struct {
+	Namespace  string
+	Namespaces models.Namespaces
+	Pods       []core_v1.Pod
+}
+
+// Check only returns the validations of the namespace and of the pods with any check,
+// like the workloads they are not Istio objects
+func (in InjectionChecker) Check() models.IstioValidations {
+	validations := models.IstioValidations{}
+
+	namespace := models.Namespace{Name: in.Namespace}
+	for _, ns := range in.Namespaces {
+		if ns.Name == in.Namespace {
+			namespace = ns
+			break
+		}
+	}
+
+	validations.MergeValidations(runInjectionChecker(workloads.NamespaceInjectionChecker{Namespace: namespace}, namespace.Name, NamespaceCheckerType, in.Namespace))
+	for _, pod := range in.Pods {
+		validations.MergeValidations(runInjectionChecker(workloads.PodInjectionChecker{Pod: pod, Namespace: namespace}, pod.Name, PodCheckerType, in.Namespace))
+	}
+
+	return validations
+}
+
+func runInjectionChecker(checker Checker, name, objectType, namespace string) models.IstioValidations {
+	checks, valid := checker.Check()
+	if len(checks) == 0 {
+		return models.IstioValidations{}
+	}
+	key, validation := EmptyValidValidation(name, namespace, objectType)
+	validation.Checks = checks
+	validation.Valid = valid
+	return models.IstioValidations{key: validation}
+}
@@ -0,0 +1,50 @@
+package checkers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	core_v1 "k8s.io/api/core/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/models"
+)
+
+func TestInjectionChecker(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	namespaces := models.Namespaces{
+		{Name: "bookinfo", Labels: map[string]string{"istio-injection": "enabled", "istio.io/rev": "canary"}},
+	}
+	pods := []core_v1.Pod{
+		{ObjectMeta: meta_v1.ObjectMeta{Name: "reviews-v1-1", Labels: map[string]string{"sidecar.istio.io/inject": "false"}}},
+		{ObjectMeta: meta_v1.ObjectMeta{Name: "reviews-v2-1"}},
+	}
+
+	validations := InjectionChecker{Namespace: "bookinfo", Namespaces: namespaces, Pods: pods}.Check()
+
+	// Only the objects with checks
+	assert.Len(validations, 2)
+	namespace := validations[models.IstioValidationKey{ObjectType: "namespace", Name: "bookinfo", Namespace: "bookinfo"}]
+	if assert.NotNil(namespace) {
+		assert.True(namespace.Valid)
+		assert.Equal(models.CheckMessage("namespace.injection.revisionignored"), namespace.Checks[0].Message)
+	}
+	pod := validations[models.IstioValidationKey{ObjectType: "pod", Name: "reviews-v1-1", Namespace: "bookinfo"}]
+	if assert.NotNil(pod) {
+		assert.True(pod.Valid)
+		assert.Equal(models.CheckMessage("pod.injection.optout"), pod.Checks[0].Message)
+	}
+}
+
+func TestInjectionCheckerWithoutConflict(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	namespaces := models.Namespaces{{Name: "bookinfo", Labels: map[string]string{"istio-injection": "enabled"}}}
+	pods := []core_v1.Pod{{ObjectMeta: meta_v1.ObjectMeta{Name: "reviews-v1-1"}}}
+
+	assert.Empty(InjectionChecker{Namespace: "bookinfo", Namespaces: namespaces, Pods: pods}.Check())
+}
@@ -11,6 +11,7 @@ import (
 const PeerAuthenticationCheckerType = "peerauthentication"
 
 type PeerAuthenticationChecker struct {
+	Namespace           string
 	PeerAuthentications []kubernetes.IstioObject
 	MTLSDetails         kubernetes.MTLSDetails
 	WorkloadList        models.WorkloadList
@@ -20,6 +21,12 @@ func (m PeerAuthenticationChecker) Check() models.IstioValidations {
 	validations := models.IstioValidations{}
 
 	validations.MergeValidations(common.SelectorMultiMatchChecker(PeerAuthenticationCheckerType, m.PeerAuthentications, m.WorkloadList).Check())
+	validations.MergeValidations(peerauthentications.ScopeConflictChecker{
+		Namespace:               m.Namespace,
+		PeerAuthentications:     m.PeerAuthentications,
+		MeshPeerAuthentications: m.MTLSDetails.MeshPeerAuthentications,
+		WorkloadList:            m.WorkloadList,
+	}.Check())
 
 	for _, peerAuthn := range m.PeerAuthentications {
 		validations.MergeValidations(m.runChecks(peerAuthn))
@@ -0,0 +1,116 @@
+package peerauthentications
+
+import (
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/kiali/kiali/business/checkers/common"
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+)
+
+const peerAuthenticationType = "peerauthentication"
+
+// ScopeConflictChecker detects PeerAuthentications setting a different mTLS mode for the same workloads at different scopes.
+// The narrowest scope takes precedence: a workload selector overrides the namespace-wide PeerAuthentication,
+// which overrides the mesh-wide one. Only the PeerAuthentications of the namespace get validations.
+type ScopeConflictChecker struct {
+	Namespace               string
+	PeerAuthentications     []kubernetes.IstioObject
+	MeshPeerAuthentications []kubernetes.IstioObject
+	WorkloadList            models.WorkloadList
+}
+
+func (s ScopeConflictChecker) Check() models.IstioValidations {
+	validations := models.IstioValidations{}
+
+	meshPeerAuthn := selectorLessPeerAuthn(s.MeshPeerAuthentications)
+	namespacePeerAuthn := meshPeerAuthn
+	if s.Namespace != config.Get().IstioNamespace {
+		if nsPeerAuthn := selectorLessPeerAuthn(s.PeerAuthentications); nsPeerAuthn != nil {
+			if meshPeerAuthn != nil && conflictingModes(nsPeerAuthn, meshPeerAuthn) {
+				validations.MergeValidations(buildOverrideValidations(nsPeerAuthn, meshPeerAuthn))
+			}
+			if explicitMode(nsPeerAuthn) != "" || meshPeerAuthn == nil {
+				namespacePeerAuthn = nsPeerAuthn
+			}
+		}
+	}
+	if namespacePeerAuthn == nil {
+		return validations
+	}
+
+	for _, peerAuthn := range s.PeerAuthentications {
+		if !common.HasSelector(peerAuthn) || !conflictingModes(peerAuthn, namespacePeerAuthn) {
+			continue
+		}
+		if s.matchesAnyWorkload(common.GetSelectorLabels(peerAuthn)) {
+			validations.MergeValidations(buildOverrideValidations(peerAuthn, namespacePeerAuthn))
+		}
+	}
+
+	return validations
+}
+
+func (s ScopeConflictChecker) matchesAnyWorkload(selectorLabels map[string]string) bool {
+	selector := labels.SelectorFromSet(selectorLabels)
+	for _, wl := range s.WorkloadList.Workloads {
+		if selector.Matches(labels.Set(wl.Labels)) {
+			return true
+		}
+	}
+	return false
+}
+
+// buildOverrideValidations reports the PeerAuthentication taking precedence and the shadowed one,
+// the shadowed PeerAuthentication only gets a validation when it belongs to the same namespace
+func buildOverrideValidations(overriding, shadowed kubernetes.IstioObject) models.IstioValidations {
+	overridingKey := models.BuildKey(peerAuthenticationType, overriding.GetObjectMeta().Name, overriding.GetObjectMeta().Namespace)
+	shadowedKey := models.BuildKey(peerAuthenticationType, shadowed.GetObjectMeta().Name, shadowed.GetObjectMeta().Namespace)
+
+	overrideCheck := models.Build("peerauthentications.mtls.overrides", "spec/mtls/mode")
+	validations := models.IstioValidations{
+		overridingKey: &models.IstioValidation{
+			Name:       overridingKey.Name,
+			ObjectType: peerAuthenticationType,
+			Valid:      true,
+			Checks:     []*models.IstioCheck{&overrideCheck},
+			References: []models.IstioValidationKey{shadowedKey},
+		},
+	}
+	if overridingKey.Namespace == shadowedKey.Namespace {
+		shadowedCheck := models.Build("peerauthentications.mtls.shadowed", "spec/mtls/mode")
+		validations[shadowedKey] = &models.IstioValidation{
+			Name:       shadowedKey.Name,
+			ObjectType: peerAuthenticationType,
+			Valid:      true,
+			Checks:     []*models.IstioCheck{&shadowedCheck},
+			References: []models.IstioValidationKey{overridingKey},
+		}
+	}
+	return validations
+}
+
+// conflictingModes returns true when both PeerAuthentications set explicitly different mTLS modes.
+// An unset mode inherits the mode of the broader scope, so it doesn't conflict.
+func conflictingModes(peerAuthn, parent kubernetes.IstioObject) bool {
+	mode, parentMode := explicitMode(peerAuthn), explicitMode(parent)
+	return mode != "" && parentMode != "" && mode != parentMode
+}
+
+func explicitMode(peerAuthn kubernetes.IstioObject) string {
+	_, mode := kubernetes.PeerAuthnMTLSMode(peerAuthn)
+	if mode == "UNSET" {
+		return ""
+	}
+	return mode
+}
+
+func selectorLessPeerAuthn(peerAuthns []kubernetes.IstioObject) kubernetes.IstioObject {
+	for _, peerAuthn := range peerAuthns {
+		if !common.HasSelector(peerAuthn) {
+			return peerAuthn
+		}
+	}
+	return nil
+}
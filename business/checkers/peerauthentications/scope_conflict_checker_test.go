@@ -0,0 +1,159 @@
+package peerauthentications
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/tests/data"
+)
+
+// Context: mesh-wide PeerAuthentication in STRICT mode
+// Context: namespace-wide PeerAuthentication in PERMISSIVE mode
+// It reports the namespace-wide PeerAuthentication overriding the mesh-wide one
+func TestNamespaceOverridesMesh(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	validations := ScopeConflictChecker{
+		Namespace: "bar",
+		MeshPeerAuthentications: []kubernetes.IstioObject{
+			data.CreateEmptyMeshPeerAuthentication("default", data.CreateMTLS("STRICT")),
+		},
+		PeerAuthentications: []kubernetes.IstioObject{
+			data.CreateEmptyPeerAuthentication("default", "bar", data.CreateMTLS("PERMISSIVE")),
+		},
+		WorkloadList: data.CreateWorkloadList("bar"),
+	}.Check()
+
+	// The mesh-wide PeerAuthentication belongs to another namespace, it doesn't get any validation
+	assert.Len(validations, 1)
+	validation := validations[models.BuildKey("peerauthentication", "default", "bar")]
+	assert.NotNil(validation)
+	assert.True(validation.Valid)
+	assertCheck(assert, validation, "peerauthentications.mtls.overrides")
+	assert.Equal([]models.IstioValidationKey{models.BuildKey("peerauthentication", "default", "istio-system")}, validation.References)
+}
+
+// Context: namespace-wide PeerAuthentication in STRICT mode
+// Context: PeerAuthentication in DISABLE mode selecting a workload of the namespace
+// It reports the selector PeerAuthentication taking precedence and the namespace-wide one shadowed
+func TestSelectorOverridesNamespace(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	validations := ScopeConflictChecker{
+		Namespace: "bar",
+		PeerAuthentications: []kubernetes.IstioObject{
+			data.CreateEmptyPeerAuthentication("default", "bar", data.CreateMTLS("STRICT")),
+			data.AddSelectorToPeerAuthn(data.CreateOneLabelSelector("reviews"),
+				data.CreateEmptyPeerAuthentication("reviews-disable", "bar", data.CreateMTLS("DISABLE"))),
+		},
+		WorkloadList: data.CreateWorkloadList("bar",
+			data.CreateWorkloadListItem("reviews-v1", map[string]string{"app": "reviews", "version": "v1"})),
+	}.Check()
+
+	assert.Len(validations, 2)
+	overriding := validations[models.BuildKey("peerauthentication", "reviews-disable", "bar")]
+	assert.NotNil(overriding)
+	assertCheck(assert, overriding, "peerauthentications.mtls.overrides")
+	assert.Equal([]models.IstioValidationKey{models.BuildKey("peerauthentication", "default", "bar")}, overriding.References)
+
+	shadowed := validations[models.BuildKey("peerauthentication", "default", "bar")]
+	assert.NotNil(shadowed)
+	assert.True(shadowed.Valid)
+	assertCheck(assert, shadowed, "peerauthentications.mtls.shadowed")
+	assert.Equal([]models.IstioValidationKey{models.BuildKey("peerauthentication", "reviews-disable", "bar")}, shadowed.References)
+}
+
+// Context: mesh-wide PeerAuthentication in STRICT mode
+// Context: namespace-wide PeerAuthentication without mode, inheriting the mesh-wide mode
+// Context: PeerAuthentication in PERMISSIVE mode selecting a workload of the namespace
+// It reports the selector PeerAuthentication overriding the mesh-wide one
+func TestSelectorOverridesMeshThroughUnsetNamespace(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	validations := ScopeConflictChecker{
+		Namespace: "bar",
+		MeshPeerAuthentications: []kubernetes.IstioObject{
+			data.CreateEmptyMeshPeerAuthentication("default", data.CreateMTLS("STRICT")),
+		},
+		PeerAuthentications: []kubernetes.IstioObject{
+			data.CreateEmptyPeerAuthentication("default", "bar", data.CreateMTLS("UNSET")),
+			data.AddSelectorToPeerAuthn(data.CreateOneLabelSelector("reviews"),
+				data.CreateEmptyPeerAuthentication("reviews-permissive", "bar", data.CreateMTLS("PERMISSIVE"))),
+		},
+		WorkloadList: data.CreateWorkloadList("bar",
+			data.CreateWorkloadListItem("reviews-v1", map[string]string{"app": "reviews", "version": "v1"})),
+	}.Check()
+
+	assert.Len(validations, 1)
+	overriding := validations[models.BuildKey("peerauthentication", "reviews-permissive", "bar")]
+	assert.NotNil(overriding)
+	assertCheck(assert, overriding, "peerauthentications.mtls.overrides")
+	assert.Equal([]models.IstioValidationKey{models.BuildKey("peerauthentication", "default", "istio-system")}, overriding.References)
+}
+
+// Context: mesh-wide PeerAuthentication in STRICT mode, validating the control plane namespace
+// Context: PeerAuthentication in PERMISSIVE mode selecting a workload of the control plane namespace
+// It reports both PeerAuthentications, as they belong to the same namespace
+func TestSelectorOverridesMeshInControlPlane(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	meshPeerAuthns := []kubernetes.IstioObject{
+		data.CreateEmptyMeshPeerAuthentication("default", data.CreateMTLS("STRICT")),
+		data.AddSelectorToPeerAuthn(data.CreateOneLabelSelector("istio-ingressgateway"),
+			data.CreateEmptyMeshPeerAuthentication("ingress-permissive", data.CreateMTLS("PERMISSIVE"))),
+	}
+	validations := ScopeConflictChecker{
+		Namespace:               "istio-system",
+		MeshPeerAuthentications: meshPeerAuthns,
+		PeerAuthentications:     meshPeerAuthns,
+		WorkloadList: data.CreateWorkloadList("istio-system",
+			data.CreateWorkloadListItem("istio-ingressgateway", map[string]string{"app": "istio-ingressgateway"})),
+	}.Check()
+
+	assert.Len(validations, 2)
+	assertCheck(assert, validations[models.BuildKey("peerauthentication", "ingress-permissive", "istio-system")], "peerauthentications.mtls.overrides")
+	assertCheck(assert, validations[models.BuildKey("peerauthentication", "default", "istio-system")], "peerauthentications.mtls.shadowed")
+}
+
+// Context: PeerAuthentications with the same mode, with unset mode, or selecting no workload
+// It doesn't return any validation
+func TestNoScopeConflicts(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	validations := ScopeConflictChecker{
+		Namespace: "bar",
+		MeshPeerAuthentications: []kubernetes.IstioObject{
+			data.CreateEmptyMeshPeerAuthentication("default", data.CreateMTLS("STRICT")),
+		},
+		PeerAuthentications: []kubernetes.IstioObject{
+			data.CreateEmptyPeerAuthentication("default", "bar", data.CreateMTLS("STRICT")),
+			data.AddSelectorToPeerAuthn(data.CreateOneLabelSelector("reviews"),
+				data.CreateEmptyPeerAuthentication("reviews-strict", "bar", data.CreateMTLS("STRICT"))),
+			data.AddSelectorToPeerAuthn(data.CreateOneLabelSelector("ratings"),
+				data.CreateEmptyPeerAuthentication("ratings-unset", "bar", data.CreateMTLS("UNSET"))),
+			data.AddSelectorToPeerAuthn(data.CreateOneLabelSelector("details"),
+				data.CreateEmptyPeerAuthentication("details-disable", "bar", data.CreateMTLS("DISABLE"))),
+		},
+		WorkloadList: data.CreateWorkloadList("bar",
+			data.CreateWorkloadListItem("reviews-v1", map[string]string{"app": "reviews", "version": "v1"}),
+			data.CreateWorkloadListItem("ratings-v1", map[string]string{"app": "ratings", "version": "v1"})),
+	}.Check()
+
+	assert.Empty(validations)
+}
+
+func assertCheck(assert *assert.Assertions, validation *models.IstioValidation, checkId string) {
+	if assert.NotNil(validation) && assert.Len(validation.Checks, 1) {
+		assert.Equal(models.CheckMessage(checkId), validation.Checks[0].Message)
+		assert.Equal("spec/mtls/mode", validation.Checks[0].Path)
+	}
+}
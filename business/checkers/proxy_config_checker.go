@@ -0,0 +1,45 @@
+package checkers
+
+import (
+	"github.com/kiali/kiali/business/checkers/common"
+	"github.com/kiali/kiali/business/checkers/proxyconfigs"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+)
+
+const ProxyConfigCheckerType = "proxyconfig"
+
+type ProxyConfigChecker struct {
+	ProxyConfigs []kubernetes.IstioObject
+	WorkloadList models.WorkloadList
+}
+
+func (p ProxyConfigChecker) Check() models.IstioValidations {
+	validations := models.IstioValidations{}
+
+	validations.MergeValidations(proxyconfigs.ConflictChecker{ProxyConfigs: p.ProxyConfigs, WorkloadList: p.WorkloadList, SubjectType: ProxyConfigCheckerType}.Check())
+
+	for _, proxyConfig := range p.ProxyConfigs {
+		validations.MergeValidations(p.runChecks(proxyConfig))
+	}
+
+	return validations
+}
+
+// runChecks runs all the individual checks for a single proxy config and appends the result into validations.
+func (p ProxyConfigChecker) runChecks(proxyConfig kubernetes.IstioObject) models.IstioValidations {
+	key, validation := EmptyValidValidation(proxyConfig.GetObjectMeta().Name, proxyConfig.GetObjectMeta().Namespace, ProxyConfigCheckerType)
+
+	enabledCheckers := []Checker{
+		common.SelectorNoWorkloadFoundChecker(ProxyConfigCheckerType, proxyConfig, p.WorkloadList),
+		proxyconfigs.SettingsChecker{ProxyConfig: proxyConfig},
+	}
+
+	for _, checker := range enabledCheckers {
+		checks, validChecker := checker.Check()
+		validation.Checks = append(validation.Checks, checks...)
+		validation.Valid = validation.Valid && validChecker
+	}
+
+	return models.IstioValidations{key: validation}
+}
@@ -0,0 +1,111 @@
+package proxyconfigs
+
+import (
+	"reflect"
+
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/kiali/kiali/business/checkers/common"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+)
+
+// ConflictChecker flags the ProxyConfigs that apply to the same workloads with different values for the same setting.
+// Istio doesn't merge ProxyConfigs of the same scope, so only one of them is applied to the proxy.
+type ConflictChecker struct {
+	ProxyConfigs []kubernetes.IstioObject
+	WorkloadList models.WorkloadList
+	SubjectType  string
+}
+
+func (c ConflictChecker) Check() models.IstioValidations {
+	references := common.ReferenceMap{}
+
+	// Namespace-wide ProxyConfigs apply to all the workloads of the namespace
+	selectorLess := make([]kubernetes.IstioObject, 0, len(c.ProxyConfigs))
+	for _, pc := range c.ProxyConfigs {
+		if !common.HasSelector(pc) {
+			selectorLess = append(selectorLess, pc)
+		}
+	}
+	c.addConflicts(references, selectorLess)
+
+	// Workload-specific ProxyConfigs overlap when they select the same workload
+	for _, w := range c.WorkloadList.Workloads {
+		matching := make([]kubernetes.IstioObject, 0, len(c.ProxyConfigs))
+		for _, pc := range c.ProxyConfigs {
+			selector := labels.SelectorFromSet(common.GetSelectorLabels(pc))
+			if !selector.Empty() && selector.Matches(labels.Set(w.Labels)) {
+				matching = append(matching, pc)
+			}
+		}
+		c.addConflicts(references, matching)
+	}
+
+	validations := models.IstioValidations{}
+	for key, refs := range references {
+		check := models.Build("proxyconfigs.multimatch.conflict", "spec")
+		validations.MergeValidations(models.IstioValidations{
+			key: &models.IstioValidation{
+				Name:       key.Name,
+				ObjectType: key.ObjectType,
+				Valid:      false,
+				References: refs,
+				Checks:     []*models.IstioCheck{&check},
+			},
+		})
+	}
+	return validations
+}
+
+func (c ConflictChecker) addConflicts(references common.ReferenceMap, proxyConfigs []kubernetes.IstioObject) {
+	for i := range proxyConfigs {
+		for j := i + 1; j < len(proxyConfigs); j++ {
+			if !conflicting(proxyConfigs[i].GetSpec(), proxyConfigs[j].GetSpec()) {
+				continue
+			}
+			iKey := models.BuildKey(c.SubjectType, proxyConfigs[i].GetObjectMeta().Name, proxyConfigs[i].GetObjectMeta().Namespace)
+			jKey := models.BuildKey(c.SubjectType, proxyConfigs[j].GetObjectMeta().Name, proxyConfigs[j].GetObjectMeta().Namespace)
+			addUnique(references, iKey, jKey)
+			addUnique(references, jKey, iKey)
+		}
+	}
+}
+
+// A workload can overlap several times for the same pair of ProxyConfigs
+func addUnique(references common.ReferenceMap, key, ref models.IstioValidationKey) {
+	for _, existing := range references.Get(key) {
+		if existing == ref {
+			return
+		}
+	}
+	references.Add(key, ref)
+}
+
+// conflicting returns true when both specs set the same setting with different values
+func conflicting(a, b map[string]interface{}) bool {
+	aConcurrency, aFound := a["concurrency"]
+	bConcurrency, bFound := b["concurrency"]
+	if aFound && bFound {
+		aValue, aOk := intValue(aConcurrency)
+		bValue, bOk := intValue(bConcurrency)
+		if aOk != bOk || aValue != bValue {
+			return true
+		}
+	}
+
+	aImage, aFound := a["image"]
+	bImage, bFound := b["image"]
+	if aFound && bFound && !reflect.DeepEqual(aImage, bImage) {
+		return true
+	}
+
+	aEnvVars, _ := a["environmentVariables"].(map[string]interface{})
+	bEnvVars, _ := b["environmentVariables"].(map[string]interface{})
+	for name, aValue := range aEnvVars {
+		if bValue, found := bEnvVars[name]; found && !reflect.DeepEqual(aValue, bValue) {
+			return true
+		}
+	}
+	return false
+}
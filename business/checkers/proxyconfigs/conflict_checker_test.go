@@ -0,0 +1,61 @@
+package proxyconfigs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/tests/data"
+	"github.com/kiali/kiali/tests/data/validations"
+)
+
+func TestOverlappingWithoutConflict(t *testing.T) {
+	vals := conflictTestPrep("overlapping_proxyconfigs.yaml", t)
+
+	tb := validations.ValidationsTestAsserter{T: t, Validations: vals}
+	tb.AssertNoValidations()
+}
+
+func TestOverlappingWithConflict(t *testing.T) {
+	assert := assert.New(t)
+	vals := conflictTestPrep("conflicting_proxyconfigs.yaml", t)
+
+	tb := validations.ValidationsTestAsserter{T: t, Validations: vals}
+	tb.AssertValidationsPresent(4)
+
+	// Workload-specific ProxyConfigs selecting reviews-v2
+	reviews := models.BuildKey("proxyconfig", "reviews-concurrency", "bookinfo")
+	reviewsV2 := models.BuildKey("proxyconfig", "reviews-v2-concurrency", "bookinfo")
+	tb.AssertValidationAt(reviews, models.WarningSeverity, "spec", "proxyconfigs.multimatch.conflict")
+	tb.AssertValidationAt(reviewsV2, models.WarningSeverity, "spec", "proxyconfigs.multimatch.conflict")
+	assert.Equal([]models.IstioValidationKey{reviewsV2}, vals[reviews].References)
+	assert.Equal([]models.IstioValidationKey{reviews}, vals[reviewsV2].References)
+
+	// Namespace-wide ProxyConfigs
+	dns := models.BuildKey("proxyconfig", "bookinfo-dns", "bookinfo")
+	noDns := models.BuildKey("proxyconfig", "bookinfo-no-dns", "bookinfo")
+	tb.AssertValidationAt(dns, models.WarningSeverity, "spec", "proxyconfigs.multimatch.conflict")
+	tb.AssertValidationAt(noDns, models.WarningSeverity, "spec", "proxyconfigs.multimatch.conflict")
+	assert.Equal([]models.IstioValidationKey{noDns}, vals[dns].References)
+}
+
+func conflictTestPrep(scenario string, t *testing.T) models.IstioValidations {
+	config.Set(config.NewConfig())
+
+	loader := yamlFixtureLoaderFor(scenario)
+	if err := loader.Load(); err != nil {
+		t.Error("Error loading test data.")
+	}
+
+	return ConflictChecker{
+		ProxyConfigs: loader.GetResources("ProxyConfig"),
+		WorkloadList: data.CreateWorkloadList("bookinfo",
+			data.CreateWorkloadListItem("reviews-v1", map[string]string{"app": "reviews", "version": "v1"}),
+			data.CreateWorkloadListItem("reviews-v2", map[string]string{"app": "reviews", "version": "v2"}),
+			data.CreateWorkloadListItem("details-v2", map[string]string{"app": "details", "version": "v2"}),
+		),
+		SubjectType: "proxyconfig",
+	}.Check()
+}
@@ -0,0 +1,98 @@
+package proxyconfigs
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+)
+
+// Above this number of worker threads the proxy likely wastes resources: nodes rarely have more cores
+const maxConcurrency = 64
+
+var imageTypes = map[string]bool{
+	"default":    true,
+	"debug":      true,
+	"distroless": true,
+}
+
+// SettingsChecker checks that the proxy settings of the ProxyConfig have sane values
+type SettingsChecker struct {
+	ProxyConfig kubernetes.IstioObject
+}
+
+func (s SettingsChecker) Check() ([]*models.IstioCheck, bool) {
+	checks := make([]*models.IstioCheck, 0)
+	spec := s.ProxyConfig.GetSpec()
+
+	if concurrency, found := spec["concurrency"]; found {
+		if value, ok := intValue(concurrency); !ok || value < 0 {
+			check := models.Build("proxyconfigs.concurrency.invalid", "spec/concurrency")
+			checks = append(checks, &check)
+		} else if value > maxConcurrency {
+			check := models.Build("proxyconfigs.concurrency.high", "spec/concurrency")
+			checks = append(checks, &check)
+		}
+	}
+
+	if image, found := spec["image"]; found {
+		imageType := ""
+		if imageMap, ok := image.(map[string]interface{}); ok {
+			imageType, _ = imageMap["imageType"].(string)
+		}
+		if !imageTypes[imageType] {
+			check := models.Build("proxyconfigs.image.invalidtype", "spec/image/imageType")
+			checks = append(checks, &check)
+		}
+	}
+
+	if envVars, ok := spec["environmentVariables"].(map[string]interface{}); ok {
+		// Sorted to report the checks in a stable order
+		names := make([]string, 0, len(envVars))
+		for name := range envVars {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			path := fmt.Sprintf("spec/environmentVariables/%s", name)
+			if len(validation.IsEnvVarName(name)) > 0 {
+				check := models.Build("proxyconfigs.env.invalidname", path)
+				checks = append(checks, &check)
+			}
+			if _, ok := envVars[name].(string); !ok {
+				check := models.Build("proxyconfigs.env.invalidvalue", path)
+				checks = append(checks, &check)
+			}
+		}
+	}
+
+	valid := true
+	for _, check := range checks {
+		if check.Severity == models.ErrorSeverity {
+			valid = false
+		}
+	}
+	return checks, valid
+}
+
+// intValue returns the value as an integer, whatever the decoder used to parse it (JSON or YAML).
+// It returns false when the value is not an integer.
+func intValue(value interface{}) (int64, bool) {
+	switch v := value.(type) {
+	case int:
+		return int64(v), true
+	case int64:
+		return v, true
+	case float64:
+		if v != math.Trunc(v) {
+			return 0, false
+		}
+		return int64(v), true
+	}
+	return 0, false
+}
@@ -0,0 +1,68 @@
+package proxyconfigs
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/tests/data"
+	"github.com/kiali/kiali/tests/data/validations"
+)
+
+func TestValidSettings(t *testing.T) {
+	vals, valid := settingsTestPrep("valid_settings.yaml", t)
+
+	tb := validations.IstioCheckTestAsserter{T: t, Validations: vals, Valid: valid}
+	tb.AssertNoValidations()
+}
+
+func TestInvalidSettings(t *testing.T) {
+	vals, valid := settingsTestPrep("invalid_settings.yaml", t)
+
+	tb := validations.IstioCheckTestAsserter{T: t, Validations: vals, Valid: valid}
+	tb.AssertValidationsPresent(4, false)
+	tb.AssertValidationAt(0, models.ErrorSeverity, "spec/concurrency", "proxyconfigs.concurrency.invalid")
+	tb.AssertValidationAt(1, models.ErrorSeverity, "spec/image/imageType", "proxyconfigs.image.invalidtype")
+	tb.AssertValidationAt(2, models.ErrorSeverity, "spec/environmentVariables/1_INVALID", "proxyconfigs.env.invalidname")
+	tb.AssertValidationAt(3, models.ErrorSeverity, "spec/environmentVariables/ISTIO_META_DNS_CAPTURE", "proxyconfigs.env.invalidvalue")
+}
+
+func TestHighConcurrency(t *testing.T) {
+	vals, valid := settingsTestPrep("high_concurrency.yaml", t)
+
+	tb := validations.IstioCheckTestAsserter{T: t, Validations: vals, Valid: valid}
+	tb.AssertValidationsPresent(1, true)
+	tb.AssertValidationAt(0, models.WarningSeverity, "spec/concurrency", "proxyconfigs.concurrency.high")
+}
+
+func TestConcurrencyFromJson(t *testing.T) {
+	config.Set(config.NewConfig())
+
+	// Resources fetched from the API are decoded as JSON, with float numbers
+	proxyConfig := data.CreateProxyConfig("bookinfo-proxy", "bookinfo", map[string]interface{}{"concurrency": float64(2)})
+	vals, valid := SettingsChecker{ProxyConfig: proxyConfig}.Check()
+	validations.IstioCheckTestAsserter{T: t, Validations: vals, Valid: valid}.AssertNoValidations()
+
+	proxyConfig = data.CreateProxyConfig("bookinfo-proxy", "bookinfo", map[string]interface{}{"concurrency": 2.5})
+	vals, valid = SettingsChecker{ProxyConfig: proxyConfig}.Check()
+	tb := validations.IstioCheckTestAsserter{T: t, Validations: vals, Valid: valid}
+	tb.AssertValidationsPresent(1, false)
+	tb.AssertValidationAt(0, models.ErrorSeverity, "spec/concurrency", "proxyconfigs.concurrency.invalid")
+}
+
+func settingsTestPrep(scenario string, t *testing.T) ([]*models.IstioCheck, bool) {
+	config.Set(config.NewConfig())
+
+	loader := yamlFixtureLoaderFor(scenario)
+	if err := loader.Load(); err != nil {
+		t.Error("Error loading test data.")
+	}
+
+	return SettingsChecker{ProxyConfig: loader.GetFirstResource("ProxyConfig")}.Check()
+}
+
+func yamlFixtureLoaderFor(file string) *data.YamlFixtureLoader {
+	path := fmt.Sprintf("../../../tests/data/validations/proxyconfigs/%s", file)
+	return &data.YamlFixtureLoader{Filename: path}
+}
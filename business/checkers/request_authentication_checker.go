@@ -2,6 +2,7 @@ package checkers
 
 import (
 	"github.com/kiali/kiali/business/checkers/common"
+	"github.com/kiali/kiali/business/checkers/requestauthentications"
 	"github.com/kiali/kiali/kubernetes"
 	"github.com/kiali/kiali/models"
 )
@@ -11,6 +12,8 @@ const RequestAuthenticationCheckerType = "requestauthentication"
 type RequestAuthenticationChecker struct {
 	RequestAuthentications []kubernetes.IstioObject
 	WorkloadList           models.WorkloadList
+	AuthorizationDetails   kubernetes.RBACDetails
+	JwksFetcher            requestauthentications.JwksFetcher
 }
 
 func (m RequestAuthenticationChecker) Check() models.IstioValidations {
@@ -30,8 +33,16 @@ func (m RequestAuthenticationChecker) runChecks(requestAuthn kubernetes.IstioObj
 	requestAuthnName := requestAuthn.GetObjectMeta().Name
 	key, rrValidation := EmptyValidValidation(requestAuthnName, requestAuthn.GetObjectMeta().Namespace, RequestAuthenticationCheckerType)
 
+	jwksFetcher := m.JwksFetcher
+	if jwksFetcher == nil {
+		jwksFetcher = requestauthentications.HttpJwksFetcher
+	}
+
 	enabledCheckers := []Checker{
 		common.SelectorNoWorkloadFoundChecker(RequestAuthenticationCheckerType, requestAuthn, m.WorkloadList),
+		requestauthentications.JwtRulesChecker{RequestAuthentication: requestAuthn, JwksFetcher: jwksFetcher},
+		requestauthentications.EnforcementChecker{RequestAuthentication: requestAuthn, AuthorizationPolicies: m.AuthorizationDetails.AuthorizationPolicies,
+			MeshAuthorizationPolicies: m.AuthorizationDetails.MeshAuthorizationPolicies, WorkloadList: m.WorkloadList},
 	}
 
 	for _, checker := range enabledCheckers {
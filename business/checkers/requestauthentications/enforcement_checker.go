@@ -0,0 +1,106 @@
+package requestauthentications
+
+import (
+	"strings"
+
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/kiali/kiali/business/checkers/common"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+)
+
+// EnforcementChecker flags RequestAuthentications with no AuthorizationPolicy requiring the JWT.
+// A RequestAuthentication only rejects requests carrying an invalid token: requests without any token
+// are still accepted unless an AuthorizationPolicy checks the request principals or the request.auth.* attributes.
+type EnforcementChecker struct {
+	RequestAuthentication     kubernetes.IstioObject
+	AuthorizationPolicies     []kubernetes.IstioObject
+	MeshAuthorizationPolicies []kubernetes.IstioObject
+	WorkloadList              models.WorkloadList
+}
+
+func (e EnforcementChecker) Check() ([]*models.IstioCheck, bool) {
+	checks := make([]*models.IstioCheck, 0)
+
+	if _, ok := e.RequestAuthentication.GetSpec()["jwtRules"].([]interface{}); !ok {
+		return checks, true
+	}
+
+	authPolicies := append(append([]kubernetes.IstioObject{}, e.AuthorizationPolicies...), e.MeshAuthorizationPolicies...)
+	for _, ap := range authPolicies {
+		if e.appliesToSameWorkloads(ap) && requiresJwt(ap) {
+			return checks, true
+		}
+	}
+
+	check := models.Build("requestauthentications.jwt.notenforced", "spec/jwtRules")
+	return append(checks, &check), true
+}
+
+// appliesToSameWorkloads returns true when the AuthorizationPolicy covers at least one of the workloads
+// targeted by the RequestAuthentication
+func (e EnforcementChecker) appliesToSameWorkloads(authPolicy kubernetes.IstioObject) bool {
+	if !common.HasSelector(authPolicy) || !common.HasSelector(e.RequestAuthentication) {
+		return true
+	}
+
+	raLabels := common.GetSelectorLabels(e.RequestAuthentication)
+	apSelector := labels.SelectorFromSet(common.GetSelectorLabels(authPolicy))
+	if apSelector.Matches(labels.Set(raLabels)) {
+		return true
+	}
+
+	raSelector := labels.SelectorFromSet(raLabels)
+	for _, wl := range e.WorkloadList.Workloads {
+		wlLabels := labels.Set(wl.Labels)
+		if raSelector.Matches(wlLabels) && apSelector.Matches(wlLabels) {
+			return true
+		}
+	}
+	return false
+}
+
+// requiresJwt returns true when any rule of the AuthorizationPolicy is based on the request principals
+// or on the request.auth.* conditions
+func requiresJwt(authPolicy kubernetes.IstioObject) bool {
+	rules, ok := authPolicy.GetSpec()["rules"].([]interface{})
+	if !ok {
+		return false
+	}
+
+	for _, r := range rules {
+		rule, ok := r.(map[string]interface{})
+		if !ok || rule == nil {
+			continue
+		}
+
+		if from, ok := rule["from"].([]interface{}); ok {
+			for _, f := range from {
+				fromMap, ok := f.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				if source, ok := fromMap["source"].(map[string]interface{}); ok {
+					if source["requestPrincipals"] != nil || source["notRequestPrincipals"] != nil {
+						return true
+					}
+				}
+			}
+		}
+
+		if when, ok := rule["when"].([]interface{}); ok {
+			for _, w := range when {
+				condition, ok := w.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				if key, ok := condition["key"].(string); ok && strings.HasPrefix(key, "request.auth.") {
+					return true
+				}
+			}
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,104 @@
+package requestauthentications
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/tests/data"
+)
+
+func jwtRequestAuthentication(selector map[string]interface{}) kubernetes.IstioObject {
+	return data.CreateRequestAuthentication("jwt", "bookinfo", selector,
+		data.CreateJwtRuleWithJwksUri("https://auth.example.com", "https://auth.example.com/jwks.json"))
+}
+
+func TestJwtNotEnforced(t *testing.T) {
+	assert := assert.New(t)
+
+	checks, valid := EnforcementChecker{
+		RequestAuthentication: jwtRequestAuthentication(data.CreateOneLabelSelector("productpage")),
+		AuthorizationPolicies: []kubernetes.IstioObject{
+			data.CreateAuthorizationPolicy([]interface{}{"bookinfo"}, []interface{}{"GET"}, []interface{}{}, map[string]interface{}{"app": "productpage"}),
+		},
+		WorkloadList: data.CreateWorkloadList("bookinfo", data.CreateWorkloadListItem("productpage-v1", map[string]string{"app": "productpage"})),
+	}.Check()
+
+	assert.True(valid)
+	assert.Len(checks, 1)
+	assert.Equal(models.CheckMessage("requestauthentications.jwt.notenforced"), checks[0].Message)
+	assert.Equal(models.WarningSeverity, checks[0].Severity)
+	assert.Equal("spec/jwtRules", checks[0].Path)
+}
+
+func TestJwtEnforcedByRequestPrincipals(t *testing.T) {
+	assert := assert.New(t)
+
+	checks, valid := EnforcementChecker{
+		RequestAuthentication: jwtRequestAuthentication(data.CreateOneLabelSelector("productpage")),
+		AuthorizationPolicies: []kubernetes.IstioObject{
+			data.CreateJwtAuthorizationPolicy("require-jwt", "bookinfo", data.CreateOneLabelSelector("productpage"), []interface{}{"*"}),
+		},
+	}.Check()
+
+	assert.True(valid)
+	assert.Empty(checks)
+}
+
+func TestJwtEnforcedByMeshPolicy(t *testing.T) {
+	assert := assert.New(t)
+
+	checks, valid := EnforcementChecker{
+		RequestAuthentication: jwtRequestAuthentication(nil),
+		MeshAuthorizationPolicies: []kubernetes.IstioObject{
+			data.CreateJwtAuthorizationPolicy("require-jwt", "istio-system", nil, []interface{}{"https://auth.example.com/*"}),
+		},
+	}.Check()
+
+	assert.True(valid)
+	assert.Empty(checks)
+}
+
+func TestJwtEnforcedOnOtherWorkloads(t *testing.T) {
+	assert := assert.New(t)
+
+	checks, valid := EnforcementChecker{
+		RequestAuthentication: jwtRequestAuthentication(data.CreateOneLabelSelector("productpage")),
+		AuthorizationPolicies: []kubernetes.IstioObject{
+			data.CreateJwtAuthorizationPolicy("require-jwt", "bookinfo", data.CreateOneLabelSelector("reviews"), []interface{}{"*"}),
+		},
+		WorkloadList: data.CreateWorkloadList("bookinfo",
+			data.CreateWorkloadListItem("productpage-v1", map[string]string{"app": "productpage"}),
+			data.CreateWorkloadListItem("reviews-v1", map[string]string{"app": "reviews"})),
+	}.Check()
+
+	assert.True(valid)
+	assert.Len(checks, 1)
+	assert.Equal(models.CheckMessage("requestauthentications.jwt.notenforced"), checks[0].Message)
+}
+
+func TestJwtEnforcedByCondition(t *testing.T) {
+	assert := assert.New(t)
+
+	authPolicy := data.CreateJwtAuthorizationPolicy("require-claims", "bookinfo", nil, nil)
+	authPolicy.GetSpec()["rules"] = []interface{}{
+		map[string]interface{}{
+			"when": []interface{}{
+				map[string]interface{}{
+					"key":    "request.auth.claims[groups]",
+					"values": []interface{}{"admins"},
+				},
+			},
+		},
+	}
+
+	checks, valid := EnforcementChecker{
+		RequestAuthentication: jwtRequestAuthentication(nil),
+		AuthorizationPolicies: []kubernetes.IstioObject{authPolicy},
+	}.Check()
+
+	assert.True(valid)
+	assert.Empty(checks)
+}
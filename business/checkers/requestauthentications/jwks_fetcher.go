@@ -0,0 +1,119 @@
+package requestauthentications
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/log"
+)
+
+// JwksFetcher returns an error when the JSON Web Key Set published at the uri can't be retrieved
+type JwksFetcher func(uri string) error
+
+const (
+	jwksFetchTimeout = 3 * time.Second
+	jwksCacheTTL     = 1 * time.Minute
+	jwksMaxSize      = 1 << 20
+)
+
+type jwksFetchResult struct {
+	err       error
+	fetchedAt time.Time
+}
+
+var (
+	jwksResults     = map[string]jwksFetchResult{}
+	jwksFetching    = map[string]bool{}
+	jwksResultsLock sync.Mutex
+	// jwksFetches tracks the fetches running in background
+	jwksFetches sync.WaitGroup
+)
+
+// HttpJwksFetcher reports the result of the last fetch of the JWKS, kept for a minute. It never blocks the
+// validations: the JWKS is fetched in background, when the result is unknown or expired, and no error is
+// reported until then. The fetches are disabled by default, and restricted to the allowed hosts of the
+// configuration. Note that Kiali may not share the network view of istiod, so a failure is only a hint.
+func HttpJwksFetcher(uri string) error {
+	conf := config.Get().Validations.JwksFetch
+	if !conf.Enabled || !allowedJwksUri(uri, conf) {
+		return nil
+	}
+
+	jwksResultsLock.Lock()
+	defer jwksResultsLock.Unlock()
+	result, found := jwksResults[uri]
+	if (!found || time.Since(result.fetchedAt) >= jwksCacheTTL) && !jwksFetching[uri] {
+		jwksFetching[uri] = true
+		jwksFetches.Add(1)
+		go func() {
+			defer jwksFetches.Done()
+			err := fetchJwks(uri, conf)
+			if err != nil {
+				log.Debugf("Unable to fetch the JWKS of [%s]: %v", uri, err)
+			}
+			jwksResultsLock.Lock()
+			jwksResults[uri] = jwksFetchResult{err: err, fetchedAt: time.Now()}
+			delete(jwksFetching, uri)
+			jwksResultsLock.Unlock()
+		}()
+	}
+	if !found {
+		return nil
+	}
+	return result.err
+}
+
+// allowedJwksUri accepts the HTTPS URIs, and the HTTP ones when allowed, whose host is one of the allowed hosts
+func allowedJwksUri(uri string, conf config.JwksFetchConfig) bool {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return false
+	}
+	if u.Scheme != "https" && !(u.Scheme == "http" && conf.AllowHTTP) {
+		return false
+	}
+	host := strings.ToLower(u.Hostname())
+	for _, allowed := range conf.AllowedHosts {
+		allowed = strings.ToLower(allowed)
+		if host == allowed || (strings.HasPrefix(allowed, "*.") && strings.HasSuffix(host, allowed[1:])) {
+			return true
+		}
+	}
+	return false
+}
+
+// fetchJwks follows the redirects to the allowed hosts only
+func fetchJwks(uri string, conf config.JwksFetchConfig) error {
+	client := http.Client{
+		Timeout: jwksFetchTimeout,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= 10 || !allowedJwksUri(req.URL.String(), conf) {
+				return fmt.Errorf("redirect to %s not allowed", req.URL)
+			}
+			return nil
+		},
+	}
+	resp, err := client.Get(uri)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status code %d fetching %s", resp.StatusCode, uri)
+	}
+	body, err := ioutil.ReadAll(io.LimitReader(resp.Body, jwksMaxSize))
+	if err != nil {
+		return err
+	}
+	if !validJwks(string(body)) {
+		return fmt.Errorf("no valid JSON Web Key Set found at %s", uri)
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+package requestauthentications
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kiali/kiali/config"
+)
+
+func TestHttpJwksFetcher(t *testing.T) {
+	assert := assert.New(t)
+
+	var requests int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		switch r.URL.Path {
+		case "/jwks.json":
+			_, _ = w.Write([]byte(validJwksSample))
+		case "/empty.json":
+			_, _ = w.Write([]byte(`{"keys":[]}`))
+		case "/redirect.json":
+			http.Redirect(w, r, "http://auth.example.com/jwks.json", http.StatusFound)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	conf := config.NewConfig()
+	conf.Validations.JwksFetch = config.JwksFetchConfig{Enabled: true, AllowHTTP: true, AllowedHosts: []string{"127.0.0.1"}}
+	config.Set(conf)
+
+	// Unknown until fetched in background
+	for _, path := range []string{"/jwks.json", "/empty.json", "/missing.json", "/redirect.json"} {
+		assert.NoError(HttpJwksFetcher(ts.URL + path))
+	}
+	jwksFetches.Wait()
+	assert.Equal(int32(4), atomic.LoadInt32(&requests))
+
+	assert.NoError(HttpJwksFetcher(ts.URL + "/jwks.json"))
+	assert.Error(HttpJwksFetcher(ts.URL + "/empty.json"))
+	assert.Error(HttpJwksFetcher(ts.URL + "/missing.json"))
+	// The redirects to a host not allowed are not followed
+	assert.Error(HttpJwksFetcher(ts.URL + "/redirect.json"))
+
+	// Results are cached
+	jwksFetches.Wait()
+	assert.Equal(int32(4), atomic.LoadInt32(&requests))
+}
+
+func TestHttpJwksFetcherRestricted(t *testing.T) {
+	assert := assert.New(t)
+
+	var requests int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer ts.Close()
+
+	// Disabled by default
+	config.Set(config.NewConfig())
+	assert.NoError(HttpJwksFetcher(ts.URL + "/restricted.json"))
+
+	// HTTP not allowed
+	conf := config.NewConfig()
+	conf.Validations.JwksFetch = config.JwksFetchConfig{Enabled: true, AllowedHosts: []string{"127.0.0.1"}}
+	config.Set(conf)
+	assert.NoError(HttpJwksFetcher(ts.URL + "/restricted.json"))
+
+	// Host not allowed
+	conf.Validations.JwksFetch = config.JwksFetchConfig{Enabled: true, AllowHTTP: true, AllowedHosts: []string{"*.example.com"}}
+	config.Set(conf)
+	assert.NoError(HttpJwksFetcher(ts.URL + "/restricted.json"))
+
+	jwksFetches.Wait()
+	assert.Equal(int32(0), atomic.LoadInt32(&requests))
+	assert.NoError(HttpJwksFetcher(ts.URL + "/restricted.json"))
+}
+
+func TestAllowedJwksUri(t *testing.T) {
+	assert := assert.New(t)
+	conf := config.JwksFetchConfig{Enabled: true, AllowedHosts: []string{"auth.example.com", "*.idp.example.org"}}
+
+	assert.True(allowedJwksUri("https://auth.example.com/jwks.json", conf))
+	assert.True(allowedJwksUri("https://AUTH.example.com:8443/jwks.json", conf))
+	assert.True(allowedJwksUri("https://eu.idp.example.org/jwks.json", conf))
+	assert.False(allowedJwksUri("https://idp.example.org/jwks.json", conf))
+	assert.False(allowedJwksUri("https://auth.example.com.evil.io/jwks.json", conf))
+	assert.False(allowedJwksUri("http://auth.example.com/jwks.json", conf))
+	assert.False(allowedJwksUri("https://169.254.169.254/latest/meta-data", conf))
+	assert.False(allowedJwksUri("file:///etc/passwd", conf))
+}
@@ -0,0 +1,114 @@
+package requestauthentications
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+)
+
+// JwtRulesChecker validates the jwtRules of a RequestAuthentication: every rule needs an issuer and
+// a source for the JSON Web Key Set, either inline (jwks) or remote (jwksUri).
+type JwtRulesChecker struct {
+	RequestAuthentication kubernetes.IstioObject
+	JwksFetcher           JwksFetcher
+}
+
+func (j JwtRulesChecker) Check() ([]*models.IstioCheck, bool) {
+	checks, valid := make([]*models.IstioCheck, 0), true
+
+	rules, ok := j.RequestAuthentication.GetSpec()["jwtRules"].([]interface{})
+	if !ok {
+		return checks, valid
+	}
+
+	for ruleIdx, r := range rules {
+		rule, ok := r.(map[string]interface{})
+		if !ok || rule == nil {
+			continue
+		}
+
+		ruleChecks, ruleValid := j.validateRule(ruleIdx, rule)
+		checks = append(checks, ruleChecks...)
+		valid = valid && ruleValid
+	}
+
+	return checks, valid
+}
+
+func (j JwtRulesChecker) validateRule(ruleIdx int, rule map[string]interface{}) ([]*models.IstioCheck, bool) {
+	checks, valid := make([]*models.IstioCheck, 0), true
+
+	issuer, _ := rule["issuer"].(string)
+	if issuer == "" {
+		check := models.Build("requestauthentications.jwt.issuermissing", fmt.Sprintf("spec/jwtRules[%d]", ruleIdx))
+		checks = append(checks, &check)
+		valid = false
+	} else if !validIssuer(issuer) {
+		check := models.Build("requestauthentications.jwt.invalidissuer", fmt.Sprintf("spec/jwtRules[%d]/issuer", ruleIdx))
+		checks = append(checks, &check)
+		valid = false
+	}
+
+	jwks, _ := rule["jwks"].(string)
+	jwksUri, _ := rule["jwksUri"].(string)
+
+	switch {
+	case jwks != "":
+		// Inline JWKS takes precedence over the jwksUri
+		if !validJwks(jwks) {
+			check := models.Build("requestauthentications.jwt.invalidjwks", fmt.Sprintf("spec/jwtRules[%d]/jwks", ruleIdx))
+			checks = append(checks, &check)
+			valid = false
+		}
+	case jwksUri != "":
+		path := fmt.Sprintf("spec/jwtRules[%d]/jwksUri", ruleIdx)
+		if !validHttpUrl(jwksUri) {
+			check := models.Build("requestauthentications.jwt.invalidjwksuri", path)
+			checks = append(checks, &check)
+			valid = false
+		} else if j.JwksFetcher != nil {
+			if err := j.JwksFetcher(jwksUri); err != nil {
+				check := models.Build("requestauthentications.jwt.unreachablejwksuri", path)
+				checks = append(checks, &check)
+			}
+		}
+	default:
+		check := models.Build("requestauthentications.jwt.jwksmissing", fmt.Sprintf("spec/jwtRules[%d]", ruleIdx))
+		checks = append(checks, &check)
+	}
+
+	return checks, valid
+}
+
+// validIssuer accepts any opaque issuer (i.e. "testing@secure.istio.io"), but when it looks like an URL it must be a valid one
+func validIssuer(issuer string) bool {
+	if strings.ContainsAny(issuer, " \t\n") {
+		return false
+	}
+	if !strings.Contains(issuer, "://") {
+		return true
+	}
+	return validHttpUrl(issuer)
+}
+
+func validHttpUrl(rawUrl string) bool {
+	u, err := url.Parse(rawUrl)
+	if err != nil {
+		return false
+	}
+	return (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+func validJwks(jwks string) bool {
+	keySet := struct {
+		Keys []map[string]interface{} `json:"keys"`
+	}{}
+	if err := json.Unmarshal([]byte(jwks), &keySet); err != nil {
+		return false
+	}
+	return len(keySet.Keys) > 0
+}
@@ -0,0 +1,93 @@
+package requestauthentications
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/tests/data"
+)
+
+const validJwksSample = `{"keys":[{"kty":"RSA","e":"AQAB","kid":"key-1","n":"abc"}]}`
+
+func reachableFetcher(uri string) error {
+	return nil
+}
+
+func unreachableFetcher(uri string) error {
+	return errors.New("dial tcp: lookup auth.unreachable.example: no such host")
+}
+
+func TestValidJwtRules(t *testing.T) {
+	assert := assert.New(t)
+
+	checks, valid := JwtRulesChecker{
+		RequestAuthentication: data.CreateRequestAuthentication("jwt", "bookinfo", nil,
+			data.CreateJwtRuleWithJwksUri("https://auth.example.com", "https://auth.example.com/jwks.json"),
+			data.CreateJwtRuleWithJwks("testing@secure.istio.io", validJwksSample)),
+		JwksFetcher: reachableFetcher,
+	}.Check()
+
+	assert.True(valid)
+	assert.Empty(checks)
+}
+
+func TestUnreachableJwksUri(t *testing.T) {
+	assert := assert.New(t)
+
+	checks, valid := JwtRulesChecker{
+		RequestAuthentication: data.CreateUnreachableJwksRequestAuthentication("jwt", "bookinfo"),
+		JwksFetcher:           unreachableFetcher,
+	}.Check()
+
+	// Kiali may not reach the identity provider while istiod can, it is only a warning
+	assert.True(valid)
+	assert.Len(checks, 1)
+	assert.Equal(models.CheckMessage("requestauthentications.jwt.unreachablejwksuri"), checks[0].Message)
+	assert.Equal(models.WarningSeverity, checks[0].Severity)
+	assert.Equal("spec/jwtRules[0]/jwksUri", checks[0].Path)
+}
+
+func TestInvalidJwtRules(t *testing.T) {
+	assert := assert.New(t)
+
+	fetched := false
+	checks, valid := JwtRulesChecker{
+		RequestAuthentication: data.CreateRequestAuthentication("jwt", "bookinfo", nil,
+			data.CreateJwtRuleWithJwksUri("", "ftp://auth.example.com/jwks.json"),
+			data.CreateJwtRuleWithJwks("https://", "not-a-key-set")),
+		JwksFetcher: func(uri string) error {
+			fetched = true
+			return nil
+		},
+	}.Check()
+
+	assert.False(valid)
+	assert.False(fetched)
+	assert.Len(checks, 4)
+	assert.Equal(models.CheckMessage("requestauthentications.jwt.issuermissing"), checks[0].Message)
+	assert.Equal("spec/jwtRules[0]", checks[0].Path)
+	assert.Equal(models.CheckMessage("requestauthentications.jwt.invalidjwksuri"), checks[1].Message)
+	assert.Equal("spec/jwtRules[0]/jwksUri", checks[1].Path)
+	assert.Equal(models.CheckMessage("requestauthentications.jwt.invalidissuer"), checks[2].Message)
+	assert.Equal("spec/jwtRules[1]/issuer", checks[2].Path)
+	assert.Equal(models.CheckMessage("requestauthentications.jwt.invalidjwks"), checks[3].Message)
+	assert.Equal("spec/jwtRules[1]/jwks", checks[3].Path)
+}
+
+func TestMissingJwks(t *testing.T) {
+	assert := assert.New(t)
+
+	checks, valid := JwtRulesChecker{
+		RequestAuthentication: data.CreateRequestAuthentication("jwt", "bookinfo", nil,
+			map[string]interface{}{"issuer": "https://auth.example.com"}),
+		JwksFetcher: reachableFetcher,
+	}.Check()
+
+	assert.True(valid)
+	assert.Len(checks, 1)
+	assert.Equal(models.CheckMessage("requestauthentications.jwt.jwksmissing"), checks[0].Message)
+	assert.Equal(models.WarningSeverity, checks[0].Severity)
+}
@@ -15,6 +15,10 @@ type ServiceChecker struct {
 	Services    []v1.Service
 	Deployments []apps_v1.Deployment
 	Pods        []core_v1.Pod
+	// Protocols of the traffic received by each Service, as reported by the metrics. Nil when unknown.
+	TrafficProtocols map[string][]string
+	// Endpoints of each Service, by Service name. Nil when unknown, the endpoints are not checked.
+	Endpoints map[string]*core_v1.Endpoints
 }
 
 func (sc ServiceChecker) Check() models.IstioValidations {
@@ -32,6 +36,11 @@ func (sc ServiceChecker) runSingleChecks(service v1.Service) models.IstioValidat
 
 	enabledCheckers := []Checker{
 		services.PortMappingChecker{Service: service, Deployments: sc.Deployments, Pods: sc.Pods},
+		services.PortProtocolChecker{Service: service, Pods: sc.Pods, TrafficProtocols: sc.TrafficProtocols[service.Name]},
+	}
+
+	if sc.Endpoints != nil {
+		enabledCheckers = append(enabledCheckers, services.EndpointsChecker{Service: service, Endpoints: sc.Endpoints[service.Name]})
 	}
 
 	for _, checker := range enabledCheckers {
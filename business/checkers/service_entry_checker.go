@@ -1,6 +1,9 @@
 package checkers
 
 import (
+	core_v1 "k8s.io/api/core/v1"
+
+	"github.com/kiali/kiali/business/checkers/serviceentries"
 	"github.com/kiali/kiali/kubernetes"
 	"github.com/kiali/kiali/models"
 )
@@ -9,11 +12,20 @@ const ServiceEntryCheckerType = "serviceentry"
 
 type ServiceEntryChecker struct {
 	ServiceEntries []kubernetes.IstioObject
+	// ServiceEntries of every namespace, which may declare the same hosts
+	MeshServiceEntries []kubernetes.IstioObject
+	// Sidecars of every namespace, the ServiceEntry is reached from the namespaces it is exported to
+	MeshSidecars []kubernetes.IstioObject
+	// Services of every namespace, the hosts of a ServiceEntry may shadow any of them
+	MeshServices []core_v1.Service
+	Namespaces   models.Namespaces
 }
 
 func (s ServiceEntryChecker) Check() models.IstioValidations {
 	validations := models.IstioValidations{}
 
+	validations.MergeValidations(serviceentries.DuplicateHostChecker{ServiceEntries: s.ServiceEntries, MeshServiceEntries: s.MeshServiceEntries, Namespaces: s.Namespaces, SubjectType: ServiceEntryCheckerType}.Check())
+
 	for _, se := range s.ServiceEntries {
 		validations.MergeValidations(s.runSingleChecks(se))
 	}
@@ -24,7 +36,10 @@ func (s ServiceEntryChecker) Check() models.IstioValidations {
 func (s ServiceEntryChecker) runSingleChecks(se kubernetes.IstioObject) models.IstioValidations {
 	key, validations := EmptyValidValidation(se.GetObjectMeta().Name, se.GetObjectMeta().Namespace, ServiceEntryCheckerType)
 
-	enabledCheckers := []Checker{}
+	enabledCheckers := []Checker{
+		serviceentries.SidecarReachabilityChecker{ServiceEntry: se, Sidecars: s.MeshSidecars, Namespaces: s.Namespaces},
+		serviceentries.ServiceShadowingChecker{ServiceEntry: se, Services: s.MeshServices, Namespaces: s.Namespaces},
+	}
 
 	for _, checker := range enabledCheckers {
 		checks, validChecker := checker.Check()
@@ -0,0 +1,190 @@
+package serviceentries
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+)
+
+// DuplicateHostChecker warns when a host of the ServiceEntry is declared by other ServiceEntries with conflicting
+// settings: resolution, ports or endpoints. Istio keeps a single declaration of the host per namespace, according
+// to their exportTo scopes, so the settings of the others are ignored there:
+// - In the namespace of one of the ServiceEntries only, it wins.
+// - In the other namespaces both are exported to, the oldest one wins.
+type DuplicateHostChecker struct {
+	// ServiceEntries validated
+	ServiceEntries []kubernetes.IstioObject
+	// ServiceEntries of every namespace, which may declare the same hosts. The validated ones when empty.
+	MeshServiceEntries []kubernetes.IstioObject
+	Namespaces         models.Namespaces
+	SubjectType        string
+}
+
+func (d DuplicateHostChecker) Check() models.IstioValidations {
+	validations := models.IstioValidations{}
+
+	meshServiceEntries := d.MeshServiceEntries
+	if len(meshServiceEntries) == 0 {
+		meshServiceEntries = d.ServiceEntries
+	}
+
+	for _, se := range d.ServiceEntries {
+		seMeta := se.GetObjectMeta()
+		checks := make([]*models.IstioCheck, 0)
+		references := make([]models.IstioValidationKey, 0)
+
+		for i, host := range getServiceEntryHosts(se) {
+			duplicated, ignored := false, false
+			for _, other := range meshServiceEntries {
+				otherMeta := other.GetObjectMeta()
+				if otherMeta.Name == seMeta.Name && otherMeta.Namespace == seMeta.Namespace {
+					continue
+				}
+				if !declaresHost(other, host) || !conflictingSettings(se, other) {
+					continue
+				}
+				wins, loses := d.resolution(se, other)
+				// No namespace sees both of them
+				if !wins && !loses {
+					continue
+				}
+				duplicated = true
+				ignored = ignored || loses
+				references = appendUniqueKey(references, models.BuildKey(d.SubjectType, otherMeta.Name, otherMeta.Namespace))
+			}
+			if !duplicated {
+				continue
+			}
+
+			checkId := "serviceentries.host.duplicate"
+			if ignored {
+				checkId = "serviceentries.host.duplicate.ignored"
+			}
+			check := models.Build(checkId, fmt.Sprintf("spec/hosts[%d]", i))
+			checks = append(checks, &check)
+		}
+
+		if len(checks) == 0 {
+			continue
+		}
+		key := models.BuildKey(d.SubjectType, seMeta.Name, seMeta.Namespace)
+		validations.MergeValidations(models.IstioValidations{
+			key: &models.IstioValidation{
+				Name:       seMeta.Name,
+				ObjectType: d.SubjectType,
+				Valid:      false,
+				Checks:     checks,
+				References: references,
+			},
+		})
+	}
+
+	return validations
+}
+
+// resolution tells whether the ServiceEntry wins the resolution of the host declared by the other one in some
+// of the namespaces both are exported to, and whether it loses it in some others
+func (d DuplicateHostChecker) resolution(se, other kubernetes.IstioObject) (wins bool, loses bool) {
+	seMeta, otherMeta := se.GetObjectMeta(), other.GetObjectMeta()
+	seExportTo, otherExportTo := getServiceEntryExportTo(se), getServiceEntryExportTo(other)
+
+	for _, ns := range d.Namespaces {
+		if !exportedTo(seExportTo, seMeta.Namespace, ns.Name) || !exportedTo(otherExportTo, otherMeta.Namespace, ns.Name) {
+			continue
+		}
+		switch {
+		case seMeta.Namespace != otherMeta.Namespace && ns.Name == seMeta.Namespace:
+			wins = true
+		case seMeta.Namespace != otherMeta.Namespace && ns.Name == otherMeta.Namespace:
+			loses = true
+		case olderServiceEntry(se, other):
+			wins = true
+		default:
+			loses = true
+		}
+	}
+	return wins, loses
+}
+
+// olderServiceEntry checks whether the ServiceEntry is taken before the other one by Istio: the oldest one, then
+// the first by namespace and name
+func olderServiceEntry(se, other kubernetes.IstioObject) bool {
+	seMeta, otherMeta := se.GetObjectMeta(), other.GetObjectMeta()
+	if !seMeta.CreationTimestamp.Equal(&otherMeta.CreationTimestamp) {
+		return seMeta.CreationTimestamp.Before(&otherMeta.CreationTimestamp)
+	}
+	if seMeta.Namespace != otherMeta.Namespace {
+		return seMeta.Namespace < otherMeta.Namespace
+	}
+	return seMeta.Name < otherMeta.Name
+}
+
+func declaresHost(se kubernetes.IstioObject, host string) bool {
+	for _, h := range getServiceEntryHosts(se) {
+		if h == host {
+			return true
+		}
+	}
+	return false
+}
+
+// conflictingSettings returns true when the ServiceEntries differ by their resolution, their ports or their endpoints
+func conflictingSettings(a, b kubernetes.IstioObject) bool {
+	aSpec, bSpec := a.GetSpec(), b.GetSpec()
+	if getResolution(aSpec) != getResolution(bSpec) {
+		return true
+	}
+	if !reflect.DeepEqual(getPortProtocols(aSpec), getPortProtocols(bSpec)) {
+		return true
+	}
+	return !reflect.DeepEqual(nonEmpty(aSpec["endpoints"]), nonEmpty(bSpec["endpoints"])) ||
+		!reflect.DeepEqual(nonEmpty(aSpec["workloadSelector"]), nonEmpty(bSpec["workloadSelector"]))
+}
+
+// getResolution returns the resolution of the ServiceEntry, NONE by default
+func getResolution(spec map[string]interface{}) string {
+	if resolution, ok := spec["resolution"].(string); ok && resolution != "" {
+		return strings.ToUpper(resolution)
+	}
+	return "NONE"
+}
+
+// getPortProtocols returns the protocol of each port number of the ServiceEntry
+func getPortProtocols(spec map[string]interface{}) map[string]string {
+	protocols := make(map[string]string)
+	ports, _ := spec["ports"].([]interface{})
+	for _, p := range ports {
+		if port, ok := p.(map[string]interface{}); ok {
+			protocol, _ := port["protocol"].(string)
+			protocols[fmt.Sprintf("%v", port["number"])] = strings.ToUpper(protocol)
+		}
+	}
+	return protocols
+}
+
+// nonEmpty returns nil for the unset and the empty values, so that they are equal
+func nonEmpty(value interface{}) interface{} {
+	switch v := value.(type) {
+	case []interface{}:
+		if len(v) == 0 {
+			return nil
+		}
+	case map[string]interface{}:
+		if len(v) == 0 {
+			return nil
+		}
+	}
+	return value
+}
+
+func appendUniqueKey(keys []models.IstioValidationKey, key models.IstioValidationKey) []models.IstioValidationKey {
+	for _, k := range keys {
+		if k == key {
+			return keys
+		}
+	}
+	return append(keys, key)
+}
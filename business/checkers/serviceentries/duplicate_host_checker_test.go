@@ -0,0 +1,107 @@
+package serviceentries
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/tests/data/validations"
+)
+
+// Context: Two ServiceEntries of the same namespace declaring the same host, with different resolutions
+// It returns a validation for each, the oldest being likely to win
+func TestDuplicateHostConflictingResolution(t *testing.T) {
+	assert := assert.New(t)
+	vals := duplicateHostTestPrep("duplicate_hosts_1.yaml", "", t)
+
+	tb := validations.ValidationsTestAsserter{T: t, Validations: vals}
+	tb.AssertValidationsPresent(2)
+
+	dns := models.BuildKey("serviceentry", "payments-dns", "external")
+	static := models.BuildKey("serviceentry", "payments-static", "external")
+	tb.AssertValidationAt(dns, models.WarningSeverity, "spec/hosts[0]", "serviceentries.host.duplicate")
+	tb.AssertValidationAt(static, models.WarningSeverity, "spec/hosts[1]", "serviceentries.host.duplicate.ignored")
+	assert.Equal([]models.IstioValidationKey{static}, vals[dns].References)
+	assert.Equal([]models.IstioValidationKey{dns}, vals[static].References)
+}
+
+// Context: Two ServiceEntries of different namespaces declaring the same host with the same settings
+// It doesn't return any validation
+func TestDuplicateHostSameSettings(t *testing.T) {
+	vals := duplicateHostTestPrep("duplicate_hosts_2.yaml", "", t)
+
+	tb := validations.ValidationsTestAsserter{T: t, Validations: vals}
+	tb.AssertNoValidations()
+}
+
+// Context: Two ServiceEntries declaring the same host with conflicting settings, each only exported to its namespace
+// It doesn't return any validation, as no namespace sees both
+func TestDuplicateHostNotSharingScope(t *testing.T) {
+	vals := duplicateHostTestPrep("duplicate_hosts_3.yaml", "", t)
+
+	tb := validations.ValidationsTestAsserter{T: t, Validations: vals}
+	tb.AssertNoValidations()
+}
+
+// Context: Two ServiceEntries of different namespaces declaring the same host with conflicting ports, exported everywhere
+// It returns a validation for each: the newest only wins in its own namespace
+func TestDuplicateHostConflictingPorts(t *testing.T) {
+	vals := duplicateHostTestPrep("duplicate_hosts_4.yaml", "", t)
+
+	tb := validations.ValidationsTestAsserter{T: t, Validations: vals}
+	tb.AssertValidationsPresent(2)
+	tb.AssertValidationAt(models.BuildKey("serviceentry", "payments", "bookinfo"), models.WarningSeverity, "spec/hosts[0]", "serviceentries.host.duplicate.ignored")
+	tb.AssertValidationAt(models.BuildKey("serviceentry", "payments", "travel"), models.WarningSeverity, "spec/hosts[0]", "serviceentries.host.duplicate.ignored")
+}
+
+// Context: Two ServiceEntries declaring the same host with conflicting settings, the newest only exported to its namespace
+// It returns a validation for each: the newest wins in the only namespace they share
+func TestDuplicateHostExportedToOwnNamespace(t *testing.T) {
+	vals := duplicateHostTestPrep("duplicate_hosts_5.yaml", "", t)
+
+	tb := validations.ValidationsTestAsserter{T: t, Validations: vals}
+	tb.AssertValidationsPresent(2)
+	tb.AssertValidationAt(models.BuildKey("serviceentry", "payments", "bookinfo"), models.WarningSeverity, "spec/hosts[0]", "serviceentries.host.duplicate.ignored")
+	tb.AssertValidationAt(models.BuildKey("serviceentry", "payments", "travel"), models.WarningSeverity, "spec/hosts[0]", "serviceentries.host.duplicate")
+}
+
+// Context: ServiceEntries of a namespace validated against the ServiceEntries of the mesh
+// It only returns the validations of the namespace, referencing the ServiceEntries of the other namespaces
+func TestDuplicateHostOtherNamespace(t *testing.T) {
+	assert := assert.New(t)
+	vals := duplicateHostTestPrep("duplicate_hosts_5.yaml", "travel", t)
+
+	tb := validations.ValidationsTestAsserter{T: t, Validations: vals}
+	tb.AssertValidationsPresent(1)
+	travel := models.BuildKey("serviceentry", "payments", "travel")
+	tb.AssertValidationAt(travel, models.WarningSeverity, "spec/hosts[0]", "serviceentries.host.duplicate")
+	assert.Equal([]models.IstioValidationKey{models.BuildKey("serviceentry", "payments", "bookinfo")}, vals[travel].References)
+}
+
+// duplicateHostTestPrep validates the ServiceEntries of the namespace, all of them when empty
+func duplicateHostTestPrep(scenario, namespace string, t *testing.T) models.IstioValidations {
+	config.Set(config.NewConfig())
+
+	loader := yamlFixtureLoaderFor(scenario)
+	if err := loader.Load(); err != nil {
+		t.Error("Error loading test data.")
+	}
+
+	meshServiceEntries := loader.GetResources("ServiceEntry")
+	serviceEntries := make([]kubernetes.IstioObject, 0, len(meshServiceEntries))
+	for _, se := range meshServiceEntries {
+		if namespace == "" || se.GetObjectMeta().Namespace == namespace {
+			serviceEntries = append(serviceEntries, se)
+		}
+	}
+
+	return DuplicateHostChecker{
+		ServiceEntries:     serviceEntries,
+		MeshServiceEntries: meshServiceEntries,
+		Namespaces:         shadowingNamespaces(),
+		SubjectType:        "serviceentry",
+	}.Check()
+}
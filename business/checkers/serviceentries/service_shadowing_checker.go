@@ -0,0 +1,122 @@
+package serviceentries
+
+import (
+	"fmt"
+	"strings"
+
+	core_v1 "k8s.io/api/core/v1"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+)
+
+// ServiceShadowingChecker warns when a host of the ServiceEntry collides with the FQDN of an in-mesh Service.
+// Istio resolves the host to a single one of them per namespace, according to their exportTo scopes:
+// - In the namespace of the Service, the Service wins.
+// - In the namespace of the ServiceEntry, the ServiceEntry wins, unless it is the namespace of the Service.
+// - In the other namespaces both are exported to, the oldest one wins, the Service when both are in the same namespace.
+type ServiceShadowingChecker struct {
+	ServiceEntry kubernetes.IstioObject
+	Services     []core_v1.Service
+	Namespaces   models.Namespaces
+}
+
+func (ssc ServiceShadowingChecker) Check() ([]*models.IstioCheck, bool) {
+	checks, valid := make([]*models.IstioCheck, 0), true
+
+	domain := config.Get().ExternalServices.Istio.IstioIdentityDomain
+	for i, host := range getServiceEntryHosts(ssc.ServiceEntry) {
+		for _, svc := range ssc.Services {
+			fqdn := fmt.Sprintf("%s.%s.%s", svc.Name, svc.Namespace, domain)
+			if host != fqdn && !kubernetes.HostWithinWildcardHost(fqdn, host) {
+				continue
+			}
+			path := fmt.Sprintf("spec/hosts[%d]", i)
+			for _, checkId := range ssc.resolutionChecks(svc) {
+				check := models.Build(checkId, path)
+				checks = append(checks, &check)
+			}
+		}
+	}
+
+	return checks, valid
+}
+
+// resolutionChecks returns the checks describing which of the ServiceEntry and the Service wins the resolution
+// of the colliding host, in the namespaces both are exported to
+func (ssc ServiceShadowingChecker) resolutionChecks(svc core_v1.Service) []string {
+	seMeta := ssc.ServiceEntry.GetObjectMeta()
+	seExportTo := getServiceEntryExportTo(ssc.ServiceEntry)
+	svcExportTo := getServiceExportTo(svc)
+
+	seWinsLocal, seWinsMesh, svcWins := false, false, false
+	for _, ns := range ssc.Namespaces {
+		if !exportedTo(seExportTo, seMeta.Namespace, ns.Name) || !exportedTo(svcExportTo, svc.Namespace, ns.Name) {
+			continue
+		}
+		switch {
+		case ns.Name == svc.Namespace:
+			svcWins = true
+		case ns.Name == seMeta.Namespace:
+			seWinsLocal = true
+		case seMeta.Namespace != svc.Namespace && seMeta.CreationTimestamp.Before(&svc.CreationTimestamp):
+			seWinsMesh = true
+		default:
+			svcWins = true
+		}
+	}
+
+	checkIds := make([]string, 0, 2)
+	if seWinsLocal {
+		checkIds = append(checkIds, "serviceentries.host.shadowing.local")
+	}
+	if seWinsMesh {
+		checkIds = append(checkIds, "serviceentries.host.shadowing.mesh")
+	}
+	if !seWinsLocal && !seWinsMesh && svcWins {
+		checkIds = append(checkIds, "serviceentries.host.shadowed")
+	}
+	return checkIds
+}
+
+// getServiceEntryExportTo returns the exportTo scope of the ServiceEntry, all the namespaces by default
+func getServiceEntryExportTo(se kubernetes.IstioObject) []string {
+	exportTo := make([]string, 0)
+	if exportSpec, found := se.GetSpec()["exportTo"]; found {
+		if exportSlice, ok := exportSpec.([]interface{}); ok {
+			for _, e := range exportSlice {
+				if export, ok := e.(string); ok {
+					exportTo = append(exportTo, export)
+				}
+			}
+		}
+	}
+	if len(exportTo) == 0 {
+		return []string{"*"}
+	}
+	return exportTo
+}
+
+// getServiceExportTo returns the exportTo scope of the Service, set in the networking.istio.io/exportTo annotation
+func getServiceExportTo(svc core_v1.Service) []string {
+	annotation, found := svc.Annotations["networking.istio.io/exportTo"]
+	if !found {
+		return []string{"*"}
+	}
+	exportTo := make([]string, 0)
+	for _, export := range strings.Split(annotation, ",") {
+		exportTo = append(exportTo, strings.TrimSpace(export))
+	}
+	return exportTo
+}
+
+// exportedTo checks whether an exportTo scope of an object of the owner namespace includes the namespace
+func exportedTo(exportTo []string, ownerNamespace, namespace string) bool {
+	for _, export := range exportTo {
+		if export == "*" || export == namespace || (export == "." && ownerNamespace == namespace) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,151 @@
+package serviceentries
+
+import (
+	"testing"
+	"time"
+
+	core_v1 "k8s.io/api/core/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/tests/data"
+	"github.com/kiali/kiali/tests/data/validations"
+)
+
+var shadowedServiceCreation = time.Date(2021, 01, 15, 0, 0, 0, 0, time.UTC)
+
+// Context: ServiceEntry in another namespace declaring the FQDN of an in-mesh Service, exported everywhere
+// Context: ServiceEntry older than the Service
+// It returns a validation for its namespace and another for the rest of the mesh
+func TestServiceEntryShadowsService(t *testing.T) {
+	vals, valid := serviceShadowingTestPrep("service_shadowing_1.yaml", nil, t)
+
+	tb := validations.IstioCheckTestAsserter{T: t, Validations: vals, Valid: valid}
+	tb.AssertValidationsPresent(2, true)
+	tb.AssertValidationAt(0, models.WarningSeverity, "spec/hosts[0]", "serviceentries.host.shadowing.local")
+	tb.AssertValidationAt(1, models.WarningSeverity, "spec/hosts[0]", "serviceentries.host.shadowing.mesh")
+}
+
+// Context: ServiceEntry in another namespace declaring the FQDN of an in-mesh Service, exported everywhere
+// Context: ServiceEntry newer than the Service
+// It returns a validation for its namespace only
+func TestNewerServiceEntryShadowsServiceLocally(t *testing.T) {
+	created := shadowedServiceCreation.Add(time.Hour)
+	vals, valid := serviceShadowingTestPrep("service_shadowing_1.yaml", &created, t)
+
+	tb := validations.IstioCheckTestAsserter{T: t, Validations: vals, Valid: valid}
+	tb.AssertValidationsPresent(1, true)
+	tb.AssertValidationAt(0, models.WarningSeverity, "spec/hosts[0]", "serviceentries.host.shadowing.local")
+}
+
+// Context: ServiceEntry in the namespace of the Service declaring its FQDN
+// It returns a validation as the ServiceEntry is ignored
+func TestServiceEntryShadowedByService(t *testing.T) {
+	vals, valid := serviceShadowingTestPrep("service_shadowing_2.yaml", nil, t)
+
+	tb := validations.IstioCheckTestAsserter{T: t, Validations: vals, Valid: valid}
+	tb.AssertValidationsPresent(1, true)
+	tb.AssertValidationAt(0, models.WarningSeverity, "spec/hosts[0]", "serviceentries.host.shadowed")
+}
+
+// Context: ServiceEntry declaring the FQDN of an in-mesh Service, only exported to its namespace
+// It returns a validation for its namespace
+func TestServiceEntryShadowsServiceInOwnNamespace(t *testing.T) {
+	vals, valid := serviceShadowingTestPrep("service_shadowing_3.yaml", nil, t)
+
+	tb := validations.IstioCheckTestAsserter{T: t, Validations: vals, Valid: valid}
+	tb.AssertValidationsPresent(1, true)
+	tb.AssertValidationAt(0, models.WarningSeverity, "spec/hosts[0]", "serviceentries.host.shadowing.local")
+}
+
+// Context: ServiceEntry with a wildcard host covering the Services of a namespace, only exported to its namespace
+// It returns a validation for each Service of the namespace
+func TestServiceEntryWildcardShadowsServices(t *testing.T) {
+	vals, valid := serviceShadowingTestPrep("service_shadowing_4.yaml", nil, t)
+
+	tb := validations.IstioCheckTestAsserter{T: t, Validations: vals, Valid: valid}
+	tb.AssertValidationsPresent(2, true)
+	tb.AssertValidationAt(0, models.WarningSeverity, "spec/hosts[0]", "serviceentries.host.shadowing.local")
+	tb.AssertValidationAt(1, models.WarningSeverity, "spec/hosts[0]", "serviceentries.host.shadowing.local")
+}
+
+// Context: ServiceEntry declaring an external host
+// It doesn't return any validation
+func TestServiceEntryExternalHost(t *testing.T) {
+	vals, valid := serviceShadowingTestPrep("service_shadowing_5.yaml", nil, t)
+
+	tb := validations.IstioCheckTestAsserter{T: t, Validations: vals, Valid: valid}
+	tb.AssertNoValidations()
+}
+
+// Context: ServiceEntry declaring the FQDN of an in-mesh Service, only exported to the namespace of the Service
+// It returns a validation as the Service wins the resolution there
+func TestServiceEntryExportedToServiceNamespace(t *testing.T) {
+	vals, valid := serviceShadowingTestPrep("service_shadowing_6.yaml", nil, t)
+
+	tb := validations.IstioCheckTestAsserter{T: t, Validations: vals, Valid: valid}
+	tb.AssertValidationsPresent(1, true)
+	tb.AssertValidationAt(0, models.WarningSeverity, "spec/hosts[0]", "serviceentries.host.shadowed")
+}
+
+// Context: ServiceEntry declaring the FQDN of an in-mesh Service not exported to the ServiceEntry scope
+// It doesn't return any validation
+func TestServiceEntryNotSharingScopeWithService(t *testing.T) {
+	conf := config.NewConfig()
+	config.Set(conf)
+
+	loader := yamlFixtureLoaderFor("service_shadowing_3.yaml")
+	if err := loader.Load(); err != nil {
+		t.Error("Error loading test data.")
+	}
+	svc := shadowedService("reviews")
+	svc.Annotations = map[string]string{"networking.istio.io/exportTo": "."}
+
+	vals, valid := ServiceShadowingChecker{
+		ServiceEntry: loader.GetFirstResource("ServiceEntry"),
+		Services:     []core_v1.Service{svc},
+		Namespaces:   shadowingNamespaces(),
+	}.Check()
+
+	tb := validations.IstioCheckTestAsserter{T: t, Validations: vals, Valid: valid}
+	tb.AssertNoValidations()
+}
+
+func serviceShadowingTestPrep(scenario string, seCreation *time.Time, t *testing.T) ([]*models.IstioCheck, bool) {
+	conf := config.NewConfig()
+	config.Set(conf)
+
+	loader := yamlFixtureLoaderFor(scenario)
+	err := loader.Load()
+	if err != nil {
+		t.Error("Error loading test data.")
+	}
+
+	se := loader.GetFirstResource("ServiceEntry")
+	if seCreation != nil {
+		meta := se.GetObjectMeta()
+		meta.CreationTimestamp = meta_v1.NewTime(*seCreation)
+		se.SetObjectMeta(meta)
+	}
+
+	return ServiceShadowingChecker{
+		ServiceEntry: se,
+		Services:     []core_v1.Service{shadowedService("reviews"), shadowedService("ratings"), otherNamespaceService("reviews")},
+		Namespaces:   shadowingNamespaces(),
+	}.Check()
+}
+
+func shadowedService(name string) core_v1.Service {
+	svc := data.CreateServiceWithPorts(name, "bookinfo", map[string]string{"app": name}, data.CreateServicePort("http", 9080, ""))
+	svc.CreationTimestamp = meta_v1.NewTime(shadowedServiceCreation)
+	return svc
+}
+
+func otherNamespaceService(name string) core_v1.Service {
+	return data.CreateServiceWithPorts(name, "travel", map[string]string{"app": name}, data.CreateServicePort("http", 9080, ""))
+}
+
+func shadowingNamespaces() models.Namespaces {
+	return models.Namespaces{{Name: "bookinfo"}, {Name: "external"}, {Name: "travel"}, {Name: "istio-system"}}
+}
@@ -0,0 +1,172 @@
+package serviceentries
+
+import (
+	"strings"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+)
+
+// SidecarReachabilityChecker warns when, in every namespace the ServiceEntry is exported to, the Sidecars restrict
+// the egress of the workloads in such a way that none of the ServiceEntry hosts can be reached.
+// The workloads of a namespace without a namespace-wide Sidecar get the default Sidecar of the root namespace.
+type SidecarReachabilityChecker struct {
+	ServiceEntry kubernetes.IstioObject
+	// Sidecars of every namespace
+	Sidecars   []kubernetes.IstioObject
+	Namespaces models.Namespaces
+}
+
+func (src SidecarReachabilityChecker) Check() ([]*models.IstioCheck, bool) {
+	checks, valid := make([]*models.IstioCheck, 0), true
+
+	hosts := getServiceEntryHosts(src.ServiceEntry)
+	if len(hosts) == 0 {
+		return checks, valid
+	}
+
+	seNamespace := src.ServiceEntry.GetObjectMeta().Namespace
+	exportTo := getServiceEntryExportTo(src.ServiceEntry)
+	for _, namespace := range src.namespaceNames() {
+		if exportedTo(exportTo, seNamespace, namespace) && src.reachableFrom(namespace, hosts) {
+			return checks, valid
+		}
+	}
+
+	check := models.Build("serviceentries.sidecar.unreachable", "spec/hosts")
+	checks = append(checks, &check)
+
+	return checks, valid
+}
+
+// namespaceNames returns the namespaces of the mesh, including the namespace of the ServiceEntry
+func (src SidecarReachabilityChecker) namespaceNames() []string {
+	seNamespace := src.ServiceEntry.GetObjectMeta().Namespace
+	names := []string{seNamespace}
+	for _, ns := range src.Namespaces {
+		if ns.Name != seNamespace {
+			names = append(names, ns.Name)
+		}
+	}
+	return names
+}
+
+// reachableFrom checks whether a workload of the namespace can reach one of the hosts, according to the Sidecars
+// applying in the namespace
+func (src SidecarReachabilityChecker) reachableFrom(namespace string, hosts []string) bool {
+	rootNamespace := config.Get().IstioNamespace
+	sidecars := make([]kubernetes.IstioObject, 0)
+	var namespaceWide, rootDefault kubernetes.IstioObject
+	for _, sc := range src.Sidecars {
+		scNamespace := sc.GetObjectMeta().Namespace
+		if scNamespace == namespace {
+			if !sc.HasWorkloadSelectorLabels() {
+				namespaceWide = sc
+			}
+			sidecars = append(sidecars, sc)
+		} else if scNamespace == rootNamespace && !sc.HasWorkloadSelectorLabels() {
+			rootDefault = sc
+		}
+	}
+
+	// Without any default Sidecar, workloads not selected by any Sidecar keep the default egress scope
+	if namespaceWide == nil {
+		if rootDefault == nil {
+			return true
+		}
+		sidecars = append(sidecars, rootDefault)
+	}
+
+	seNamespace := src.ServiceEntry.GetObjectMeta().Namespace
+	for _, sc := range sidecars {
+		egressHosts, restricted := getSidecarEgressHosts(sc)
+		if !restricted {
+			return true
+		}
+		for _, egressHost := range egressHosts {
+			for _, host := range hosts {
+				// The "." of the default Sidecar of the root namespace is the namespace of the workload
+				if egressHostMatches(egressHost, host, namespace, seNamespace) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+func getServiceEntryHosts(se kubernetes.IstioObject) []string {
+	hosts := make([]string, 0)
+	if hostsSpec, found := se.GetSpec()["hosts"]; found {
+		if hostsSlice, ok := hostsSpec.([]interface{}); ok {
+			for _, h := range hostsSlice {
+				if host, ok := h.(string); ok {
+					hosts = append(hosts, host)
+				}
+			}
+		}
+	}
+	return hosts
+}
+
+// getSidecarEgressHosts returns the egress hosts declared in the sidecar.
+// The boolean is false when the Sidecar doesn't declare any egress restriction.
+func getSidecarEgressHosts(sidecar kubernetes.IstioObject) ([]string, bool) {
+	egressSpec, found := sidecar.GetSpec()["egress"]
+	if !found {
+		return nil, false
+	}
+	egresses, ok := egressSpec.([]interface{})
+	if !ok || len(egresses) == 0 {
+		return nil, false
+	}
+
+	hosts := make([]string, 0)
+	for _, e := range egresses {
+		egress, ok := e.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if hostsSpec, found := egress["hosts"]; found {
+			if hostsSlice, ok := hostsSpec.([]interface{}); ok {
+				for _, h := range hostsSlice {
+					if host, ok := h.(string); ok {
+						hosts = append(hosts, host)
+					}
+				}
+			}
+		}
+	}
+	return hosts, true
+}
+
+// egressHostMatches checks whether a Sidecar egress host in "namespace/dnsName" form includes the ServiceEntry host.
+func egressHostMatches(egressHost, seHost, workloadNamespace, seNamespace string) bool {
+	parts := strings.Split(egressHost, "/")
+	if len(parts) != 2 {
+		return false
+	}
+	hostNs, dnsName := parts[0], parts[1]
+
+	switch hostNs {
+	case "*":
+	case ".":
+		if workloadNamespace != seNamespace {
+			return false
+		}
+	default:
+		// "~" imports nothing and any other value needs to be the ServiceEntry namespace
+		if hostNs != seNamespace {
+			return false
+		}
+	}
+
+	if dnsName == "*" || dnsName == seHost {
+		return true
+	}
+
+	// Both directions: a wildcard egress host covering the ServiceEntry host and
+	// a specific egress host contained in a wildcard ServiceEntry host
+	return kubernetes.HostWithinWildcardHost(seHost, dnsName) || kubernetes.HostWithinWildcardHost(dnsName, seHost)
+}
@@ -0,0 +1,111 @@
+package serviceentries
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/tests/data"
+	"github.com/kiali/kiali/tests/data/validations"
+)
+
+// Context: namespace-wide Sidecar importing all the services in the same namespace
+// Context: ServiceEntry in the same namespace
+// It doesn't return any validation
+func TestServiceEntryReachableFromLocalNamespace(t *testing.T) {
+	testNoSidecarReachabilityValidations("sidecar_reachability_1.yaml", t)
+}
+
+// Context: namespace-wide Sidecar only importing istio-system and a single service
+// Context: ServiceEntry only exported to its namespace, host not imported by any egress listener
+// It returns a validation
+func TestServiceEntryUnreachable(t *testing.T) {
+	testWithSidecarReachabilityValidations("sidecar_reachability_2.yaml", t)
+}
+
+// Context: namespace-wide Sidecar excluding the ServiceEntry
+// Context: workload Sidecar importing the ServiceEntry host through a wildcard
+// It doesn't return any validation
+func TestServiceEntryReachableFromWorkloadSidecar(t *testing.T) {
+	testNoSidecarReachabilityValidations("sidecar_reachability_3.yaml", t)
+}
+
+// Context: Sidecar restricted to a single workload
+// Context: Workloads without Sidecar keep the default egress
+// It doesn't return any validation
+func TestServiceEntryReachableWithoutNamespaceSidecar(t *testing.T) {
+	testNoSidecarReachabilityValidations("sidecar_reachability_4.yaml", t)
+}
+
+// Context: namespace-wide Sidecar importing nothing from any namespace
+// Context: ServiceEntry only exported to its namespace
+// It returns a validation
+func TestServiceEntryUnreachableNoImport(t *testing.T) {
+	testWithSidecarReachabilityValidations("sidecar_reachability_5.yaml", t)
+}
+
+// Context: namespace-wide Sidecar excluding the ServiceEntry in its namespace
+// Context: ServiceEntry exported to a namespace without Sidecar
+// It doesn't return any validation
+func TestServiceEntryReachableFromOtherNamespace(t *testing.T) {
+	testNoSidecarReachabilityValidations("sidecar_reachability_6.yaml", t)
+}
+
+// Context: namespace-wide Sidecar excluding the ServiceEntry in its namespace
+// Context: default Sidecar of the root namespace only importing the local namespace in the other namespaces
+// It returns a validation
+func TestServiceEntryUnreachableWithRootSidecar(t *testing.T) {
+	testWithSidecarReachabilityValidations("sidecar_reachability_7.yaml", t)
+}
+
+// Context: ServiceEntry only exported to its namespace
+// Context: default Sidecar of the root namespace importing the namespace of the workloads
+// It doesn't return any validation
+func TestServiceEntryReachableWithRootSidecar(t *testing.T) {
+	testNoSidecarReachabilityValidations("sidecar_reachability_8.yaml", t)
+}
+
+// Context: ServiceEntry only exported to another namespace
+// Context: default Sidecar of the root namespace only importing the local namespace
+// It returns a validation
+func TestServiceEntryUnreachableFromExportedNamespace(t *testing.T) {
+	testWithSidecarReachabilityValidations("sidecar_reachability_9.yaml", t)
+}
+
+func sidecarReachabilityTestPrep(scenario string, t *testing.T) ([]*models.IstioCheck, bool) {
+	conf := config.NewConfig()
+	config.Set(conf)
+
+	loader := yamlFixtureLoaderFor(scenario)
+	err := loader.Load()
+	if err != nil {
+		t.Error("Error loading test data.")
+	}
+
+	return SidecarReachabilityChecker{
+		ServiceEntry: loader.GetFirstResource("ServiceEntry"),
+		Sidecars:     loader.GetResources("Sidecar"),
+		Namespaces:   models.Namespaces{{Name: "bookinfo"}, {Name: "travel"}, {Name: "istio-system"}},
+	}.Check()
+}
+
+func testNoSidecarReachabilityValidations(scenario string, t *testing.T) {
+	vals, valid := sidecarReachabilityTestPrep(scenario, t)
+
+	tb := validations.IstioCheckTestAsserter{T: t, Validations: vals, Valid: valid}
+	tb.AssertNoValidations()
+}
+
+func testWithSidecarReachabilityValidations(scenario string, t *testing.T) {
+	vals, valid := sidecarReachabilityTestPrep(scenario, t)
+
+	tb := validations.IstioCheckTestAsserter{T: t, Validations: vals, Valid: valid}
+	tb.AssertValidationsPresent(1, true)
+	tb.AssertValidationAt(0, models.WarningSeverity, "spec/hosts", "serviceentries.sidecar.unreachable")
+}
+
+func yamlFixtureLoaderFor(file string) *data.YamlFixtureLoader {
+	path := fmt.Sprintf("../../../tests/data/validations/serviceentries/%s", file)
+	return &data.YamlFixtureLoader{Filename: path}
+}
@@ -0,0 +1,44 @@
+package services
+
+import (
+	core_v1 "k8s.io/api/core/v1"
+
+	"github.com/kiali/kiali/models"
+)
+
+// EndpointsChecker flags the Services selecting no ready pod, left behind when their workloads are removed or
+// scaled down, or whose pods all fail their readiness probe. The Services without selector, whose endpoints are
+// managed manually, and the ExternalName Services, which have no endpoints, are not checked.
+type EndpointsChecker struct {
+	Service core_v1.Service
+	// The Endpoints of the Service, nil when it has none
+	Endpoints *core_v1.Endpoints
+}
+
+func (e EndpointsChecker) Check() ([]*models.IstioCheck, bool) {
+	validations := make([]*models.IstioCheck, 0)
+
+	if e.Service.Spec.Type == core_v1.ServiceTypeExternalName || len(e.Service.Spec.Selector) == 0 {
+		return validations, true
+	}
+
+	ready, notReady := 0, 0
+	if e.Endpoints != nil {
+		for _, subset := range e.Endpoints.Subsets {
+			ready += len(subset.Addresses)
+			notReady += len(subset.NotReadyAddresses)
+		}
+	}
+
+	switch {
+	case ready > 0:
+	case notReady > 0:
+		check := models.Build("service.pods.notready", "spec/selector")
+		validations = append(validations, &check)
+	default:
+		check := models.Build("service.pods.notfound", "spec/selector")
+		validations = append(validations, &check)
+	}
+
+	return validations, true
+}
@@ -0,0 +1,89 @@
+package services
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	core_v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/yaml"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/tests/data/validations"
+)
+
+func TestServiceWithReadyPods(t *testing.T) {
+	config.Set(config.NewConfig())
+	services, endpoints := loadServiceEndpoints(t)
+
+	for _, name := range []string{"reviews", "mongodb"} {
+		vals, valid := EndpointsChecker{Service: services[name], Endpoints: endpoints[name]}.Check()
+		validations.IstioCheckTestAsserter{T: t, Validations: vals, Valid: valid}.AssertNoValidations()
+	}
+}
+
+func TestServiceWithoutReadyPods(t *testing.T) {
+	config.Set(config.NewConfig())
+	services, endpoints := loadServiceEndpoints(t)
+
+	vals, valid := EndpointsChecker{Service: services["ratings"], Endpoints: endpoints["ratings"]}.Check()
+	tb := validations.IstioCheckTestAsserter{T: t, Validations: vals, Valid: valid}
+	tb.AssertValidationsPresent(1, true)
+	tb.AssertValidationAt(0, models.WarningSeverity, "spec/selector", "service.pods.notready")
+}
+
+func TestServiceWithoutPods(t *testing.T) {
+	config.Set(config.NewConfig())
+	services, endpoints := loadServiceEndpoints(t)
+
+	// Empty Endpoints, no Endpoints at all, headless
+	for _, name := range []string{"details", "productpage", "mysqldb"} {
+		vals, valid := EndpointsChecker{Service: services[name], Endpoints: endpoints[name]}.Check()
+		tb := validations.IstioCheckTestAsserter{T: t, Validations: vals, Valid: valid}
+		tb.AssertValidationsPresent(1, true)
+		tb.AssertValidationAt(0, models.WarningSeverity, "spec/selector", "service.pods.notfound")
+	}
+}
+
+func TestServiceWithoutEndpointsSkipped(t *testing.T) {
+	config.Set(config.NewConfig())
+	services, endpoints := loadServiceEndpoints(t)
+
+	// Without selector, ExternalName
+	for _, name := range []string{"legacy-db", "external-api"} {
+		vals, valid := EndpointsChecker{Service: services[name], Endpoints: endpoints[name]}.Check()
+		validations.IstioCheckTestAsserter{T: t, Validations: vals, Valid: valid}.AssertNoValidations()
+	}
+}
+
+// loadServiceEndpoints returns the Services and the Endpoints of the endpoints fixture, by name
+func loadServiceEndpoints(t *testing.T) (map[string]core_v1.Service, map[string]*core_v1.Endpoints) {
+	content, err := ioutil.ReadFile("../../../tests/data/validations/services/endpoints.yaml")
+	if err != nil {
+		t.Fatalf("Error loading test data: %v", err)
+	}
+	services := map[string]core_v1.Service{}
+	endpoints := map[string]*core_v1.Endpoints{}
+	for _, doc := range strings.Split(string(content), "\n---\n") {
+		js, err := yaml.ToJSON([]byte(doc))
+		if err != nil {
+			t.Fatalf("Error parsing test data: %v", err)
+		}
+		if strings.Contains(string(js), `"kind":"Endpoints"`) {
+			eps := &core_v1.Endpoints{}
+			if err := json.Unmarshal(js, eps); err != nil {
+				t.Fatalf("Error parsing test data: %v", err)
+			}
+			endpoints[eps.Name] = eps
+			continue
+		}
+		svc := core_v1.Service{}
+		if err := json.Unmarshal(js, &svc); err != nil {
+			t.Fatalf("Error parsing test data: %v", err)
+		}
+		services[svc.Name] = svc
+	}
+	return services, endpoints
+}
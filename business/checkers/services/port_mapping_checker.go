@@ -28,7 +28,8 @@ func (p PortMappingChecker) Check() ([]*models.IstioCheck, bool) {
 		for portIndex, sp := range p.Service.Spec.Ports {
 			if strings.ToLower(string(sp.Protocol)) == "udp" {
 				continue
-			} else if !kubernetes.MatchPortNameWithValidProtocols(sp.Name) {
+			} else if !kubernetes.MatchPortNameWithValidProtocols(sp.Name) && !kubernetes.MatchPortAppProtocolWithValidProtocols(sp.AppProtocol) {
+				// A supported appProtocol declares the protocol as well
 				validation := models.Build("port.name.mismatch", fmt.Sprintf("spec/ports[%d]", portIndex))
 				validations = append(validations, &validation)
 			}
@@ -122,6 +122,26 @@ func TestServicePortNamingWithoutSidecar(t *testing.T) {
 	assert.Empty(validations)
 }
 
+func TestServicePortNamingWithAppProtocol(t *testing.T) {
+	conf := config.NewConfig()
+	config.Set(conf)
+
+	assert := assert.New(t)
+
+	service := getService(9080, "http2foo")
+	appProtocol := "http"
+	service.Spec.Ports[0].AppProtocol = &appProtocol
+	pmc := PortMappingChecker{
+		Service:     service,
+		Deployments: getDeployment(9080),
+		Pods:        getPods(true),
+	}
+
+	validations, valid := pmc.Check()
+	assert.True(valid)
+	assert.Empty(validations)
+}
+
 func getService(servicePort int32, portName string) v1.Service {
 	return v1.Service{
 		ObjectMeta: meta_v1.ObjectMeta{
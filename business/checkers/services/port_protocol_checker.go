@@ -0,0 +1,88 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+
+	core_v1 "k8s.io/api/core/v1"
+
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+)
+
+// PortProtocolChecker checks the appProtocol of the ports of a Service in the mesh, and compares the protocol they
+// declare, by appProtocol or by name, with the traffic the Service receives. The ports declaring no protocol are
+// reported by the PortMappingChecker.
+type PortProtocolChecker struct {
+	Service core_v1.Service
+	Pods    []core_v1.Pod
+	// Protocols of the traffic received by the Service, as reported by the metrics. Nil when unknown.
+	TrafficProtocols []string
+}
+
+func (p PortProtocolChecker) Check() ([]*models.IstioCheck, bool) {
+	validations := make([]*models.IstioCheck, 0)
+
+	if !p.hasMatchingPodsWithSidecar() {
+		return validations, true
+	}
+
+	for portIndex, sp := range p.Service.Spec.Ports {
+		if strings.ToLower(string(sp.Protocol)) == "udp" {
+			continue
+		}
+		path := fmt.Sprintf("spec/ports[%d]", portIndex)
+
+		validName := kubernetes.MatchPortNameWithValidProtocols(sp.Name)
+		validAppProtocol := kubernetes.MatchPortAppProtocolWithValidProtocols(sp.AppProtocol)
+		if sp.AppProtocol != nil && *sp.AppProtocol != "" && !validAppProtocol {
+			// Istio ignores it and falls back to the port name
+			validation := models.Build("port.appprotocol.unknown", path)
+			validations = append(validations, &validation)
+		}
+		if !validName && !validAppProtocol {
+			// Reported by the PortMappingChecker
+			continue
+		}
+
+		if p.declaresL7(sp, validAppProtocol) && p.receivesOnlyTCP() {
+			validation := models.Build("port.protocol.traffic.mismatch", path)
+			validations = append(validations, &validation)
+		}
+	}
+
+	return validations, len(validations) == 0
+}
+
+// declaresL7 returns true when the protocol declared by the port is HTTP or gRPC
+func (p PortProtocolChecker) declaresL7(sp core_v1.ServicePort, validAppProtocol bool) bool {
+	protocol := sp.Name
+	if validAppProtocol {
+		// appProtocol takes precedence over the port name
+		protocol = *sp.AppProtocol
+	}
+	switch strings.ToLower(strings.SplitN(protocol, "-", 2)[0]) {
+	case "grpc", "http", "http2":
+		return true
+	}
+	return false
+}
+
+// receivesOnlyTCP returns true when the metrics report traffic to the Service, but no HTTP nor gRPC request
+func (p PortProtocolChecker) receivesOnlyTCP() bool {
+	if len(p.TrafficProtocols) == 0 {
+		return false
+	}
+	for _, protocol := range p.TrafficProtocols {
+		if protocol != models.TCPProtocol {
+			return false
+		}
+	}
+	return true
+}
+
+func (p PortProtocolChecker) hasMatchingPodsWithSidecar() bool {
+	sPods := models.Pods{}
+	sPods.Parse(kubernetes.FilterPodsForService(&p.Service, p.Pods))
+	return sPods.HasIstioSidecar()
+}
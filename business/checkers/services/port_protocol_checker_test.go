@@ -0,0 +1,83 @@
+package services
+
+import (
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/tests/data"
+	"github.com/kiali/kiali/tests/data/validations"
+)
+
+func TestDeclaredPortProtocols(t *testing.T) {
+	vals, valid := portProtocolCheck(nil,
+		data.CreateServicePort("http", 9080, ""),
+		data.CreateServicePort("grpc-api", 9090, ""),
+		data.CreateServicePort("db", 3306, "mysql"),
+		data.CreateServicePort("", 8080, "HTTP"),
+		// UDP ports don't need to declare a protocol
+		v1.ServicePort{Name: "dns", Port: 53, Protocol: v1.ProtocolUDP},
+	)
+
+	tb := validations.IstioCheckTestAsserter{T: t, Validations: vals, Valid: valid}
+	tb.AssertNoValidations()
+}
+
+func TestUndeclaredPortProtocols(t *testing.T) {
+	// The ports declaring no protocol are reported by the PortMappingChecker
+	vals, valid := portProtocolCheck([]string{models.TCPProtocol},
+		data.CreateUnnamedServicePort(9080),
+		data.CreateMisnamedServicePort(8080),
+		data.CreateServicePort("http", 9090, ""),
+	)
+
+	tb := validations.IstioCheckTestAsserter{T: t, Validations: vals, Valid: valid}
+	tb.AssertValidationsPresent(1, false)
+	tb.AssertValidationAt(0, models.WarningSeverity, "spec/ports[2]", "port.protocol.traffic.mismatch")
+}
+
+func TestUnknownAppProtocol(t *testing.T) {
+	vals, valid := portProtocolCheck(nil,
+		// The port name is used instead
+		data.CreateServicePort("http", 9080, "kubernetes.io/h2c"),
+		data.CreateServicePort("web", 8080, "kubernetes.io/h2c"),
+	)
+
+	tb := validations.IstioCheckTestAsserter{T: t, Validations: vals, Valid: valid}
+	tb.AssertValidationsPresent(2, false)
+	tb.AssertValidationAt(0, models.WarningSeverity, "spec/ports[0]", "port.appprotocol.unknown")
+	tb.AssertValidationAt(1, models.WarningSeverity, "spec/ports[1]", "port.appprotocol.unknown")
+}
+
+func TestPortProtocolTrafficMismatch(t *testing.T) {
+	ports := []v1.ServicePort{
+		data.CreateServicePort("http", 9080, ""),
+		data.CreateServicePort("tcp", 9090, ""),
+		data.CreateServicePort("", 8080, "grpc"),
+	}
+
+	vals, valid := portProtocolCheck([]string{models.TCPProtocol}, ports...)
+	tb := validations.IstioCheckTestAsserter{T: t, Validations: vals, Valid: valid}
+	tb.AssertValidationsPresent(2, false)
+	tb.AssertValidationAt(0, models.WarningSeverity, "spec/ports[0]", "port.protocol.traffic.mismatch")
+	tb.AssertValidationAt(1, models.WarningSeverity, "spec/ports[2]", "port.protocol.traffic.mismatch")
+
+	// HTTP or gRPC requests are observed, or there's no traffic at all
+	for _, protocols := range [][]string{{models.HTTPProtocol, models.TCPProtocol}, {models.GRPCProtocol}, {}} {
+		vals, valid = portProtocolCheck(protocols, ports...)
+		tb = validations.IstioCheckTestAsserter{T: t, Validations: vals, Valid: valid}
+		tb.AssertNoValidations()
+	}
+}
+
+func portProtocolCheck(trafficProtocols []string, ports ...v1.ServicePort) ([]*models.IstioCheck, bool) {
+	config.Set(config.NewConfig())
+
+	return PortProtocolChecker{
+		Service:          data.CreateServiceWithPorts("service1", "bookinfo", map[string]string{"dep": "one"}, ports...),
+		Pods:             getPods(true),
+		TrafficProtocols: trafficProtocols,
+	}.Check()
+}
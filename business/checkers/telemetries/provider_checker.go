@@ -0,0 +1,50 @@
+package telemetries
+
+import (
+	"fmt"
+
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+)
+
+// The fields of a Telemetry referencing extension providers
+var providerFields = []string{"accessLogging", "metrics", "tracing"}
+
+// ProviderChecker flags the providers of the Telemetry not defined in the mesh config, or whose service is not
+// found in the mesh
+type ProviderChecker struct {
+	Telemetry          kubernetes.IstioObject
+	ExtensionProviders models.ExtensionProviders
+}
+
+func (p ProviderChecker) Check() ([]*models.IstioCheck, bool) {
+	checks, valid := make([]*models.IstioCheck, 0), true
+
+	for _, field := range providerFields {
+		entries, _ := p.Telemetry.GetSpec()[field].([]interface{})
+		for i, e := range entries {
+			entry, _ := e.(map[string]interface{})
+			providers, _ := entry["providers"].([]interface{})
+			for j, pr := range providers {
+				provider, _ := pr.(map[string]interface{})
+				name, _ := provider["name"].(string)
+				if name == "" {
+					continue
+				}
+				path := fmt.Sprintf("spec/%s[%d]/providers[%d]/name", field, i, j)
+				definition := p.ExtensionProviders.Get(name)
+				switch {
+				case definition == nil:
+					check := models.Build("telemetry.provider.notfound", path)
+					checks = append(checks, &check)
+					valid = false
+				case !definition.Reachable:
+					check := models.Build("telemetry.provider.unreachable", path)
+					checks = append(checks, &check)
+				}
+			}
+		}
+	}
+
+	return checks, valid
+}
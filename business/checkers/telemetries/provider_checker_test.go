@@ -0,0 +1,54 @@
+package telemetries
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/tests/data"
+	"github.com/kiali/kiali/tests/data/validations"
+)
+
+var extensionProviders = models.ExtensionProviders{
+	{Name: "envoy", Type: "envoyFileAccessLog", Builtin: true, Reachable: true},
+	{Name: "external-tracing", Type: "zipkin", Reachable: true},
+	{Name: "json-logs", Type: "envoyFileAccessLog", Reachable: true},
+	{Name: "otel", Type: "opentelemetry", Reachable: false},
+	{Name: "prometheus", Type: "prometheus", Builtin: true, Reachable: true},
+}
+
+// Context: a Telemetry referencing defined and builtin providers
+func TestProvidersDefined(t *testing.T) {
+	telemetries := providerCheckerTestPrep(t)
+
+	vals, valid := ProviderChecker{Telemetry: telemetries["mesh-default"], ExtensionProviders: extensionProviders}.Check()
+	validations.IstioCheckTestAsserter{T: t, Validations: vals, Valid: valid}.AssertNoValidations()
+}
+
+// Context: a Telemetry referencing an unreachable provider and an undefined one
+func TestProvidersDangling(t *testing.T) {
+	telemetries := providerCheckerTestPrep(t)
+
+	vals, valid := ProviderChecker{Telemetry: telemetries["dangling"], ExtensionProviders: extensionProviders}.Check()
+	ta := validations.IstioCheckTestAsserter{T: t, Validations: vals, Valid: valid}
+	ta.AssertValidationsPresent(2, false)
+	ta.AssertValidationAt(0, models.ErrorSeverity, "spec/accessLogging[0]/providers[1]/name", "telemetry.provider.notfound")
+	ta.AssertValidationAt(1, models.WarningSeverity, "spec/tracing[0]/providers[0]/name", "telemetry.provider.unreachable")
+}
+
+func providerCheckerTestPrep(t *testing.T) map[string]kubernetes.IstioObject {
+	loader := &data.YamlFixtureLoader{Filename: "../../../tests/data/validations/extension_providers/references.yaml"}
+	err := loader.Load()
+	if err != nil {
+		t.Error("Error loading test data.")
+	}
+
+	telemetries := map[string]kubernetes.IstioObject{}
+	for _, telemetry := range loader.GetResources("Telemetry") {
+		telemetries[telemetry.GetObjectMeta().Name] = telemetry
+	}
+	assert.Len(t, telemetries, 2)
+	return telemetries
+}
@@ -0,0 +1,46 @@
+package checkers
+
+import (
+	"github.com/kiali/kiali/business/checkers/telemetries"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+)
+
+const TelemetryCheckerType = "telemetry"
+
+// TelemetryChecker validates the Telemetries. Without the extension providers of the mesh config, their references
+// can't be resolved and the Telemetries are not validated.
+type TelemetryChecker struct {
+	Telemetries        []kubernetes.IstioObject
+	ExtensionProviders models.ExtensionProviders
+}
+
+func (t TelemetryChecker) Check() models.IstioValidations {
+	validations := models.IstioValidations{}
+	if t.ExtensionProviders == nil {
+		return validations
+	}
+
+	for _, telemetry := range t.Telemetries {
+		validations.MergeValidations(t.runChecks(telemetry))
+	}
+
+	return validations
+}
+
+// runChecks runs all the individual checks for a single telemetry and appends the result into validations.
+func (t TelemetryChecker) runChecks(telemetry kubernetes.IstioObject) models.IstioValidations {
+	key, validation := EmptyValidValidation(telemetry.GetObjectMeta().Name, telemetry.GetObjectMeta().Namespace, TelemetryCheckerType)
+
+	enabledCheckers := []Checker{
+		telemetries.ProviderChecker{Telemetry: telemetry, ExtensionProviders: t.ExtensionProviders},
+	}
+
+	for _, checker := range enabledCheckers {
+		checks, validChecker := checker.Check()
+		validation.Checks = append(validation.Checks, checks...)
+		validation.Valid = validation.Valid && validChecker
+	}
+
+	return models.IstioValidations{key: validation}
+}
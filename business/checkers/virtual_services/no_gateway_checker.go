@@ -41,11 +41,8 @@ func (s NoGatewayChecker) ValidateVirtualServiceGateways(spec map[string]interfa
 					// Gateways should be using <namespace>/<gateway>
 					checkNomenclature(gate, index, validations)
 
-					hostname := kubernetes.ParseGatewayAsHost(gate, namespace, clusterName).String()
-					for gw := range s.GatewayNames {
-						if found := kubernetes.FilterByHost(hostname, gw, namespace); found {
-							continue GatewaySearch
-						}
+					if _, found := MatchingGateway(gate, namespace, clusterName, s.GatewayNames); found {
+						continue GatewaySearch
 					}
 					path := fmt.Sprintf("spec/gateways[%d]", index)
 					validation := models.Build("virtualservices.nogateway", path)
@@ -59,6 +56,17 @@ func (s NoGatewayChecker) ValidateVirtualServiceGateways(spec map[string]interfa
 	return valid
 }
 
+// MatchingGateway returns the name, among the gatewayNames, of the Gateway referred by a gateway of a VirtualService
+func MatchingGateway(gateway, namespace, clusterName string, gatewayNames map[string]struct{}) (string, bool) {
+	hostname := kubernetes.ParseGatewayAsHost(gateway, namespace, clusterName).String()
+	for gw := range gatewayNames {
+		if found := kubernetes.FilterByHost(hostname, gw, namespace); found {
+			return gw, true
+		}
+	}
+	return "", false
+}
+
 func checkNomenclature(gateway string, index int, validations *[]*models.IstioCheck) {
 	if strings.Contains(gateway, ".") {
 		path := fmt.Sprintf("spec/gateways[%d]", index)
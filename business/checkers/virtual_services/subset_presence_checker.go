@@ -41,7 +41,7 @@ func (checker SubsetPresenceChecker) Check() ([]*models.IstioCheck, bool) {
 			// Getting a []DestinationWeight
 			destinationWeights := reflect.ValueOf(httpRoute["route"])
 			if destinationWeights.Kind() != reflect.Slice {
-				return validations, valid
+				continue
 			}
 
 			for destWeightIdx := 0; destWeightIdx < destinationWeights.Len(); destWeightIdx++ {
@@ -67,7 +67,8 @@ func (checker SubsetPresenceChecker) Check() ([]*models.IstioCheck, bool) {
 
 				if !checker.subsetPresent(host, subset) {
 					path := fmt.Sprintf("spec/%s[%d]/route[%d]/destination", protocol, routeIdx, destWeightIdx)
-					validation := models.Build("virtualservices.subsetpresent.subsetnotfound", path)
+					detail := fmt.Sprintf("the subset [%s] of the host [%s] is not defined by any DestinationRule", subset, host)
+					validation := models.BuildWithDetail("virtualservices.subsetpresent.subsetnotfound", path, detail)
 					validations = append(validations, &validation)
 				}
 			}
@@ -78,22 +79,33 @@ func (checker SubsetPresenceChecker) Check() ([]*models.IstioCheck, bool) {
 }
 
 func (checker SubsetPresenceChecker) subsetPresent(host string, subset string) bool {
+	return len(checker.DestinationRulesWithSubset(host, subset)) > 0
+}
+
+// DestinationRulesWithSubset returns the DestinationRules defining the subset of a host of the VirtualService
+func (checker SubsetPresenceChecker) DestinationRulesWithSubset(host string, subset string) []kubernetes.IstioObject {
+	subsetRules := make([]kubernetes.IstioObject, 0)
 	destinationRules, ok := checker.getDestinationRules(host)
-	if !ok || destinationRules == nil || len(destinationRules) == 0 {
-		return false
+	if !ok {
+		return subsetRules
 	}
 
 	for _, dr := range destinationRules {
 		if hasSubsetDefined(dr, subset) {
-			return true
+			subsetRules = append(subsetRules, dr)
 		}
 	}
 
-	return false
+	return subsetRules
 }
 
+// getDestinationRules returns the DestinationRules of the host of the VirtualService visible from the namespace of
+// the VirtualService: the ones of its namespace and the ones of the other namespaces exported to it. Their host
+// may be the host of the VirtualService in any of its forms, or a wildcard host including it.
 func (checker SubsetPresenceChecker) getDestinationRules(virtualServiceHost string) ([]kubernetes.IstioObject, bool) {
 	drs := make([]kubernetes.IstioObject, 0, len(checker.DestinationRules))
+	vsNamespace := checker.virtualServiceNamespace()
+	vsHost := kubernetes.GetHost(virtualServiceHost, vsNamespace, checker.VirtualService.GetObjectMeta().ClusterName, checker.Namespaces)
 
 	for _, destinationRule := range checker.DestinationRules {
 		host, ok := destinationRule.GetSpec()["host"]
@@ -106,11 +118,14 @@ func (checker SubsetPresenceChecker) getDestinationRules(virtualServiceHost stri
 			continue
 		}
 
-		drHost := kubernetes.GetHost(sHost, destinationRule.GetObjectMeta().Namespace, destinationRule.GetObjectMeta().ClusterName, checker.Namespaces)
-		vsHost := kubernetes.GetHost(virtualServiceHost, checker.Namespace, checker.VirtualService.GetObjectMeta().ClusterName, checker.Namespaces)
+		drNamespace := destinationRule.GetObjectMeta().Namespace
+		if !exportedTo(destinationRule, drNamespace, vsNamespace) {
+			continue
+		}
 
-		// TODO Host could be in another namespace (FQDN)
-		if kubernetes.FilterByHost(vsHost.String(), drHost.Service, drHost.Namespace) {
+		drHost := kubernetes.GetHost(sHost, drNamespace, destinationRule.GetObjectMeta().ClusterName, checker.Namespaces)
+		if kubernetes.FilterByHost(vsHost.String(), drHost.Service, drHost.Namespace) ||
+			sHost == "*" || kubernetes.HostWithinWildcardHost(vsHost.String(), sHost) {
 			drs = append(drs, destinationRule)
 		}
 	}
@@ -118,6 +133,30 @@ func (checker SubsetPresenceChecker) getDestinationRules(virtualServiceHost stri
 	return drs, len(drs) > 0
 }
 
+func (checker SubsetPresenceChecker) virtualServiceNamespace() string {
+	if namespace := checker.VirtualService.GetObjectMeta().Namespace; namespace != "" {
+		return namespace
+	}
+	return checker.Namespace
+}
+
+// exportedTo checks whether the exportTo scope of the DestinationRule, all the namespaces by default, includes
+// the namespace
+func exportedTo(destinationRule kubernetes.IstioObject, drNamespace, namespace string) bool {
+	exportTo, ok := destinationRule.GetSpec()["exportTo"].([]interface{})
+	if !ok || len(exportTo) == 0 {
+		return true
+	}
+	for _, e := range exportTo {
+		if export, ok := e.(string); ok {
+			if export == "*" || export == namespace || (export == "." && drNamespace == namespace) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 func hasSubsetDefined(destinationRule kubernetes.IstioObject, subsetTarget string) bool {
 	if subsets, ok := destinationRule.GetSpec()["subsets"]; ok {
 		if dSubsets, ok := subsets.([]interface{}); ok {
@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
+
 	"github.com/kiali/kiali/config"
 	"github.com/kiali/kiali/kubernetes"
 	"github.com/kiali/kiali/models"
@@ -20,7 +22,7 @@ func TestCheckerWithSubsetsMatchingShortHostname(t *testing.T) {
 }
 
 func TestCheckerWithSubsetsMatchingShortHostnameDiffNs(t *testing.T) {
-	testSubsetPresenceValidationsFound("subset-presence-matching-subsets-diff-ns.yaml", t)
+	testSubsetPresenceValidationsFound("subset-presence-matching-subsets-diff-ns.yaml", t, "reviews", "v1", "reviews", "v2")
 }
 
 func TestDestRuleDifferentNamespaceFQDNName(t *testing.T) {
@@ -36,15 +38,18 @@ func TestCheckerWithSubsetsMatchingSVCNSHostname(t *testing.T) {
 }
 
 func TestSubsetsNotFound(t *testing.T) {
-	testSubsetPresenceValidationsFound("subset-presence-no-matching-subsets-1.yaml", t)
+	testSubsetPresenceValidationsFound("subset-presence-no-matching-subsets-1.yaml", t,
+		"reviews.bookinfo.svc.cluster.local", "not-v1", "reviews.bookinfo.svc.cluster.local", "not-v2")
 }
 
 func TestSubsetsNotFoundSVCNS(t *testing.T) {
-	testSubsetPresenceValidationsFound("subset-presence-no-matching-subsets-2.yaml", t)
+	testSubsetPresenceValidationsFound("subset-presence-no-matching-subsets-2.yaml", t,
+		"reviews.bookinfo", "not-v1", "reviews.bookinfo.svc.cluster.local", "not-v2")
 }
 
 func TestWrongDestinationRule(t *testing.T) {
-	testSubsetPresenceValidationsFound("subset-presence-no-matching-subsets-3.yaml", t)
+	testSubsetPresenceValidationsFound("subset-presence-no-matching-subsets-3.yaml", t,
+		"reviews.bookinfo.svc.cluster.local", "v1", "reviews.bookinfo.svc.cluster.local", "v2")
 }
 
 func TestCorrectServiceEntry(t *testing.T) {
@@ -56,8 +61,25 @@ func TestInvalidServiceEntry(t *testing.T) {
 
 	tb := validations.IstioCheckTestAsserter{T: t, Validations: vals, Valid: valid}
 	tb.AssertValidationsPresent(2, true)
-	tb.AssertValidationAt(0, models.WarningSeverity, "spec/http[1]/route[0]/destination", "virtualservices.subsetpresent.subsetnotfound")
-	tb.AssertValidationAt(1, models.WarningSeverity, "spec/tls[1]/route[0]/destination", "virtualservices.subsetpresent.subsetnotfound")
+	assertSubsetNotFound(t, vals[0], "spec/http[1]/route[0]/destination", "orahub.oci.oraclecorp.com", "bogus-subset")
+	assertSubsetNotFound(t, vals[1], "spec/tls[1]/route[0]/destination", "orahub.oci.oraclecorp.com", "bogus-subset-2")
+}
+
+func TestSubsetNotDefinedByDestinationRuleOfOtherNamespace(t *testing.T) {
+	vals, valid := subsetPresenceCheckerPrep("subset-presence-missing-subset-diff-ns.yaml", t)
+
+	tb := validations.IstioCheckTestAsserter{T: t, Validations: vals, Valid: valid}
+	tb.AssertValidationsPresent(1, true)
+	assertSubsetNotFound(t, vals[0], "spec/http[0]/route[1]/destination", "reviews.reviews-ns.svc.cluster.local", "v3")
+}
+
+func TestDestinationRuleNotExported(t *testing.T) {
+	testSubsetPresenceValidationsFound("subset-presence-not-exported.yaml", t,
+		"reviews.reviews-ns.svc.cluster.local", "v1", "reviews.reviews-ns.svc.cluster.local", "v2")
+}
+
+func TestCheckerWithSubsetsMatchingWildcardHost(t *testing.T) {
+	testNoSubsetPresenceValidationsFound("subset-presence-matching-subsets-wildcard.yaml", t)
 }
 
 func subsetPresenceCheckerPrep(scenario string, t *testing.T) ([]*models.IstioCheck, bool) {
@@ -100,11 +122,21 @@ func testNoSubsetPresenceValidationsFound(scenario string, t *testing.T) {
 	tb.AssertNoValidations()
 }
 
-func testSubsetPresenceValidationsFound(scenario string, t *testing.T) {
+// testSubsetPresenceValidationsFound expects the subsets of the first two routes, given as host and subset pairs,
+// to be reported as not found
+func testSubsetPresenceValidationsFound(scenario string, t *testing.T, firstHost, firstSubset, secondHost, secondSubset string) {
 	vals, valid := subsetPresenceCheckerPrep(scenario, t)
 
 	tb := validations.IstioCheckTestAsserter{T: t, Validations: vals, Valid: valid}
 	tb.AssertValidationsPresent(2, true)
-	tb.AssertValidationAt(0, models.WarningSeverity, "spec/http[0]/route[0]/destination", "virtualservices.subsetpresent.subsetnotfound")
-	tb.AssertValidationAt(1, models.WarningSeverity, "spec/http[1]/route[0]/destination", "virtualservices.subsetpresent.subsetnotfound")
+	assertSubsetNotFound(t, vals[0], "spec/http[0]/route[0]/destination", firstHost, firstSubset)
+	assertSubsetNotFound(t, vals[1], "spec/http[1]/route[0]/destination", secondHost, secondSubset)
+}
+
+func assertSubsetNotFound(t *testing.T, check *models.IstioCheck, path, host, subset string) {
+	assert := assert.New(t)
+	assert.Equal(models.WarningSeverity, check.Severity)
+	assert.Equal(path, check.Path)
+	assert.Equal(fmt.Sprintf("%s: the subset [%s] of the host [%s] is not defined by any DestinationRule",
+		models.CheckMessage("virtualservices.subsetpresent.subsetnotfound"), subset, host), check.Message)
 }
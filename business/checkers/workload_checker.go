@@ -0,0 +1,53 @@
+package checkers
+
+import (
+	"github.com/kiali/kiali/business/checkers/workloads"
+	"github.com/kiali/kiali/models"
+)
+
+const WorkloadCheckerType = "workload"
+
+type WorkloadChecker struct {
+	Namespace    string
+	Namespaces   models.Namespaces
+	WorkloadList models.WorkloadList
+}
+
+// Check only returns the validations of the workloads with any check,
+// workloads are not Istio objects and most of them don't need to be listed
+func (w WorkloadChecker) Check() models.IstioValidations {
+	validations := models.IstioValidations{}
+
+	namespace := models.Namespace{Name: w.Namespace}
+	for _, ns := range w.Namespaces {
+		if ns.Name == w.Namespace {
+			namespace = ns
+			break
+		}
+	}
+
+	for _, wk := range w.WorkloadList.Workloads {
+		validations.MergeValidations(w.runSingleChecks(wk, namespace))
+	}
+
+	return validations
+}
+
+func (w WorkloadChecker) runSingleChecks(workload models.WorkloadListItem, namespace models.Namespace) models.IstioValidations {
+	key, validations := EmptyValidValidation(workload.Name, w.Namespace, WorkloadCheckerType)
+
+	enabledCheckers := []Checker{
+		workloads.MissingSidecarChecker{Workload: workload, Namespace: namespace},
+	}
+
+	for _, checker := range enabledCheckers {
+		checks, validChecker := checker.Check()
+		validations.Checks = append(validations.Checks, checks...)
+		validations.Valid = validations.Valid && validChecker
+	}
+
+	if len(validations.Checks) == 0 {
+		return models.IstioValidations{}
+	}
+	return models.IstioValidations{key: validations}
+}
@@ -0,0 +1,37 @@
+package checkers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/tests/data"
+)
+
+func TestWorkloadCheckerOnlyListsFlaggedWorkloads(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	injected := data.CreateWorkloadListItem("details-v1", map[string]string{"app": "details", "version": "v1"})
+	injected.PodCount = 1
+	injected.IstioSidecar = true
+	notInjected := data.CreateWorkloadListItem("reviews-v1", map[string]string{"app": "reviews", "version": "v1"})
+	notInjected.PodCount = 1
+
+	validations := WorkloadChecker{
+		Namespace: "bookinfo",
+		Namespaces: models.Namespaces{
+			{Name: "bookinfo", Labels: map[string]string{"istio-injection": "enabled"}},
+		},
+		WorkloadList: data.CreateWorkloadList("bookinfo", injected, notInjected),
+	}.Check()
+
+	assert.Len(validations, 1)
+	validation, found := validations[models.BuildKey(WorkloadCheckerType, "reviews-v1", "bookinfo")]
+	assert.True(found)
+	assert.True(validation.Valid)
+	assert.Len(validation.Checks, 1)
+	assert.Equal(models.CheckMessage("workload.sidecar.missing"), validation.Checks[0].Message)
+}
@@ -0,0 +1,88 @@
+package workloads
+
+import (
+	"strconv"
+
+	core_v1 "k8s.io/api/core/v1"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/models"
+)
+
+// NamespaceInjectionChecker flags the namespaces labeled both with the injection label and with an Istio revision,
+// the injection label takes precedence and the revision is ignored
+type NamespaceInjectionChecker struct {
+	Namespace models.Namespace
+}
+
+func (n NamespaceInjectionChecker) Check() ([]*models.IstioCheck, bool) {
+	checks := make([]*models.IstioCheck, 0)
+
+	injection, found := n.Namespace.Labels[config.Get().IstioLabels.InjectionLabelName]
+	if _, revision := n.Namespace.Labels[istioRevisionLabel]; !found || !revision {
+		return checks, true
+	}
+
+	if injection == "enabled" {
+		check := models.Build("namespace.injection.revisionignored", "metadata/labels")
+		checks = append(checks, &check)
+	} else {
+		check := models.Build("namespace.injection.revisiondisabled", "metadata/labels")
+		checks = append(checks, &check)
+	}
+
+	// The sidecars are still injected, or not, in a deterministic way
+	return checks, true
+}
+
+// PodInjectionChecker flags the pods whose injection label or annotation contradicts the injection of their namespace
+type PodInjectionChecker struct {
+	Pod       core_v1.Pod
+	Namespace models.Namespace
+}
+
+func (p PodInjectionChecker) Check() ([]*models.IstioCheck, bool) {
+	checks := make([]*models.IstioCheck, 0)
+
+	inject, path, found := podInjection(p.Pod)
+	if !found {
+		return checks, true
+	}
+
+	enabled := InjectionEnabled(p.Namespace)
+	_, labeled := p.Namespace.Labels[config.Get().IstioLabels.InjectionLabelName]
+	switch {
+	case !inject && enabled:
+		check := models.Build("pod.injection.optout", path)
+		checks = append(checks, &check)
+	case inject && labeled && !enabled:
+		check := models.Build("pod.injection.ignored", path)
+		checks = append(checks, &check)
+	case inject && !enabled && path == "metadata/annotations":
+		// Only the label is matched by the injection webhook in the namespaces without injection label
+		check := models.Build("pod.injection.annotationonly", path)
+		checks = append(checks, &check)
+	}
+
+	return checks, true
+}
+
+// podInjection returns the injection requested by the pod, the label taking precedence over the annotation as done
+// by the injection webhook
+func podInjection(pod core_v1.Pod) (inject bool, path string, found bool) {
+	name := config.Get().ExternalServices.Istio.IstioInjectionAnnotation
+	value, found := pod.Labels[name]
+	path = "metadata/labels"
+	if !found {
+		value, found = pod.Annotations[name]
+		path = "metadata/annotations"
+	}
+	if !found {
+		return false, "", false
+	}
+	inject, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, "", false
+	}
+	return inject, path, true
+}
@@ -0,0 +1,127 @@
+package workloads
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	core_v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/yaml"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/models"
+)
+
+func TestNamespaceInjectionConflicts(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	namespaces, _ := loadInjectionPermutations(t)
+	expected := map[string]string{
+		"injection-enabled":  "",
+		"injection-disabled": "",
+		"revision":           "",
+		"enabled-revision":   "namespace.injection.revisionignored",
+		"disabled-revision":  "namespace.injection.revisiondisabled",
+		"unlabeled":          "",
+	}
+	assert.Len(namespaces, len(expected))
+	for name, code := range expected {
+		checks, valid := NamespaceInjectionChecker{Namespace: namespaces[name]}.Check()
+		assert.True(valid, name)
+		if code == "" {
+			assert.Empty(checks, name)
+			continue
+		}
+		if assert.Len(checks, 1, name) {
+			assert.Equal(models.CheckMessage(code), checks[0].Message, name)
+			assert.Equal(models.WarningSeverity, checks[0].Severity, name)
+			assert.Equal("metadata/labels", checks[0].Path, name)
+		}
+	}
+}
+
+func TestPodInjectionConflicts(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	namespaces, pods := loadInjectionPermutations(t)
+
+	// The checks of the pods per injection of the namespace: the code and the path of the check
+	enabled := map[string][2]string{
+		"label-false":                 {"pod.injection.optout", "metadata/labels"},
+		"annotation-false":            {"pod.injection.optout", "metadata/annotations"},
+		"label-false-annotation-true": {"pod.injection.optout", "metadata/labels"},
+	}
+	disabled := map[string][2]string{
+		"label-true":      {"pod.injection.ignored", "metadata/labels"},
+		"annotation-true": {"pod.injection.ignored", "metadata/annotations"},
+	}
+	expected := map[string]map[string][2]string{
+		"injection-enabled":  enabled,
+		"injection-disabled": disabled,
+		"revision":           enabled,
+		// The injection label takes precedence over the revision
+		"enabled-revision":  enabled,
+		"disabled-revision": disabled,
+		// The pods enable the injection with the label only
+		"unlabeled": {
+			"annotation-true": {"pod.injection.annotationonly", "metadata/annotations"},
+		},
+	}
+
+	assert.Len(pods, 6)
+	for namespace, podChecks := range expected {
+		for _, pod := range pods {
+			checks, valid := PodInjectionChecker{Pod: pod, Namespace: namespaces[namespace]}.Check()
+			assert.True(valid)
+			check, found := podChecks[pod.Name]
+			if !found {
+				assert.Empty(checks, "%s in %s", pod.Name, namespace)
+				continue
+			}
+			if assert.Len(checks, 1, "%s in %s", pod.Name, namespace) {
+				assert.Equal(models.CheckMessage(check[0]), checks[0].Message, "%s in %s", pod.Name, namespace)
+				assert.Equal(check[1], checks[0].Path, "%s in %s", pod.Name, namespace)
+				// Opting out is usually deliberate
+				if check[0] == "pod.injection.optout" {
+					assert.Equal(models.Unknown, checks[0].Severity)
+				} else {
+					assert.Equal(models.WarningSeverity, checks[0].Severity)
+				}
+			}
+		}
+	}
+}
+
+// loadInjectionPermutations returns the namespaces by name and the pods of the injection fixture
+func loadInjectionPermutations(t *testing.T) (map[string]models.Namespace, []core_v1.Pod) {
+	content, err := ioutil.ReadFile("../../../tests/data/validations/injection/permutations.yaml")
+	if err != nil {
+		t.Fatalf("Error loading test data: %v", err)
+	}
+	namespaces := map[string]models.Namespace{}
+	pods := []core_v1.Pod{}
+	for _, doc := range strings.Split(string(content), "\n---\n") {
+		js, err := yaml.ToJSON([]byte(doc))
+		if err != nil {
+			t.Fatalf("Error parsing test data: %v", err)
+		}
+		if strings.Contains(string(js), `"kind":"Namespace"`) {
+			namespace := core_v1.Namespace{}
+			if err := json.Unmarshal(js, &namespace); err != nil {
+				t.Fatalf("Error parsing test data: %v", err)
+			}
+			namespaces[namespace.Name] = models.CastNamespace(namespace)
+			continue
+		}
+		pod := core_v1.Pod{}
+		if err := json.Unmarshal(js, &pod); err != nil {
+			t.Fatalf("Error parsing test data: %v", err)
+		}
+		pods = append(pods, pod)
+	}
+	return namespaces, pods
+}
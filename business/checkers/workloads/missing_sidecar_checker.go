@@ -0,0 +1,63 @@
+package workloads
+
+import (
+	"strconv"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/models"
+)
+
+// istioRevisionLabel enables the injection of the sidecar of an Istio revision in a namespace
+const istioRevisionLabel = "istio.io/rev"
+
+// sidecarInjectLabel opts a pod in or out of the sidecar injection
+const sidecarInjectLabel = "sidecar.istio.io/inject"
+
+// MissingSidecarChecker flags workloads running without the istio-proxy container in a namespace
+// with sidecar injection enabled, as their traffic silently bypasses the mesh
+type MissingSidecarChecker struct {
+	Workload  models.WorkloadListItem
+	Namespace models.Namespace
+}
+
+func (m MissingSidecarChecker) Check() ([]*models.IstioCheck, bool) {
+	checks := make([]*models.IstioCheck, 0)
+
+	// Workloads without pods can't be checked, and istioSidecar is only set when all the pods have it
+	if m.Workload.PodCount == 0 || m.Workload.IstioSidecar || !InjectionEnabled(m.Namespace) {
+		return checks, true
+	}
+
+	// The injection label of the pods takes precedence over the annotation
+	disabledPath := ""
+	if injection, err := strconv.ParseBool(m.Workload.Labels[sidecarInjectLabel]); err == nil {
+		if !injection {
+			disabledPath = "spec/template/metadata/labels"
+		}
+	} else if m.Workload.IstioInjectionAnnotation != nil && !*m.Workload.IstioInjectionAnnotation {
+		disabledPath = "spec/template/metadata/annotations"
+	}
+
+	if disabledPath != "" {
+		check := models.Build("workload.sidecar.injectiondisabled", disabledPath)
+		checks = append(checks, &check)
+	} else {
+		// Pods created before enabling the injection need to be restarted to get the sidecar
+		check := models.Build("workload.sidecar.missing", "spec/template")
+		checks = append(checks, &check)
+	}
+
+	// Missing sidecars are warnings, they don't make the workload invalid
+	return checks, true
+}
+
+// InjectionEnabled returns true when the namespace is labeled for sidecar injection,
+// either with the injection label or with an Istio revision
+func InjectionEnabled(namespace models.Namespace) bool {
+	injection, found := namespace.Labels[config.Get().IstioLabels.InjectionLabelName]
+	if found {
+		return injection == "enabled"
+	}
+	_, found = namespace.Labels[istioRevisionLabel]
+	return found
+}
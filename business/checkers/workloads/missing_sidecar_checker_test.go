@@ -0,0 +1,133 @@
+package workloads
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/tests/data"
+)
+
+func TestInjectedWorkload(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	workload := fakeWorkload(true, nil)
+	validations, valid := MissingSidecarChecker{Workload: workload, Namespace: injectionNamespace()}.Check()
+
+	assert.Empty(validations)
+	assert.True(valid)
+}
+
+func TestWorkloadWithInjectionOptOut(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	disabled := false
+	workload := fakeWorkload(false, &disabled)
+	validations, valid := MissingSidecarChecker{Workload: workload, Namespace: injectionNamespace()}.Check()
+
+	assert.True(valid)
+	assert.Len(validations, 1)
+	assert.Equal(models.CheckMessage("workload.sidecar.injectiondisabled"), validations[0].Message)
+	assert.Equal(models.WarningSeverity, validations[0].Severity)
+	assert.Equal("spec/template/metadata/annotations", validations[0].Path)
+}
+
+func TestWorkloadWithInjectionLabelOptOut(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	workload := fakeWorkload(false, nil)
+	workload.Labels["sidecar.istio.io/inject"] = "false"
+	validations, valid := MissingSidecarChecker{Workload: workload, Namespace: injectionNamespace()}.Check()
+
+	assert.True(valid)
+	assert.Len(validations, 1)
+	assert.Equal(models.CheckMessage("workload.sidecar.injectiondisabled"), validations[0].Message)
+	assert.Equal("spec/template/metadata/labels", validations[0].Path)
+}
+
+func TestWorkloadInjectionLabelOverridesAnnotation(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	// The label enables the injection disabled by the annotation: the pods need to be restarted
+	disabled := false
+	workload := fakeWorkload(false, &disabled)
+	workload.Labels["sidecar.istio.io/inject"] = "true"
+	validations, _ := MissingSidecarChecker{Workload: workload, Namespace: injectionNamespace()}.Check()
+	assert.Len(validations, 1)
+	assert.Equal(models.CheckMessage("workload.sidecar.missing"), validations[0].Message)
+
+	// The label disables the injection enabled by the annotation
+	enabled := true
+	workload = fakeWorkload(false, &enabled)
+	workload.Labels["sidecar.istio.io/inject"] = "false"
+	validations, _ = MissingSidecarChecker{Workload: workload, Namespace: injectionNamespace()}.Check()
+	assert.Len(validations, 1)
+	assert.Equal(models.CheckMessage("workload.sidecar.injectiondisabled"), validations[0].Message)
+	assert.Equal("spec/template/metadata/labels", validations[0].Path)
+}
+
+func TestWorkloadCreatedBeforeInjection(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	for _, annotation := range []*bool{nil, new(bool)} {
+		if annotation != nil {
+			*annotation = true
+		}
+		workload := fakeWorkload(false, annotation)
+		validations, valid := MissingSidecarChecker{Workload: workload, Namespace: injectionNamespace()}.Check()
+
+		assert.True(valid)
+		assert.Len(validations, 1)
+		assert.Equal(models.CheckMessage("workload.sidecar.missing"), validations[0].Message)
+		assert.Equal(models.WarningSeverity, validations[0].Severity)
+	}
+}
+
+func TestWorkloadWithoutSidecarInRevisionNamespace(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	namespace := models.Namespace{Name: "bookinfo", Labels: map[string]string{"istio.io/rev": "canary"}}
+	validations, _ := MissingSidecarChecker{Workload: fakeWorkload(false, nil), Namespace: namespace}.Check()
+	assert.Len(validations, 1)
+
+	// The injection label takes precedence over the revision
+	namespace.Labels["istio-injection"] = "disabled"
+	validations, _ = MissingSidecarChecker{Workload: fakeWorkload(false, nil), Namespace: namespace}.Check()
+	assert.Empty(validations)
+}
+
+func TestWorkloadWithoutSidecarOutOfMesh(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	namespace := models.Namespace{Name: "bookinfo", Labels: map[string]string{}}
+	validations, valid := MissingSidecarChecker{Workload: fakeWorkload(false, nil), Namespace: namespace}.Check()
+	assert.Empty(validations)
+	assert.True(valid)
+
+	// Workloads without pods are not checked
+	workload := fakeWorkload(false, nil)
+	workload.PodCount = 0
+	validations, _ = MissingSidecarChecker{Workload: workload, Namespace: injectionNamespace()}.Check()
+	assert.Empty(validations)
+}
+
+func injectionNamespace() models.Namespace {
+	return models.Namespace{Name: "bookinfo", Labels: map[string]string{"istio-injection": "enabled"}}
+}
+
+func fakeWorkload(sidecar bool, injectionAnnotation *bool) models.WorkloadListItem {
+	workload := data.CreateWorkloadListItem("reviews-v1", map[string]string{"app": "reviews", "version": "v1"})
+	workload.PodCount = 2
+	workload.IstioSidecar = sidecar
+	workload.IstioInjectionAnnotation = injectionAnnotation
+	return workload
+}
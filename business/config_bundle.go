@@ -0,0 +1,313 @@
+package business
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	errors2 "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/yaml"
+
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/log"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/prometheus/internalmetrics"
+)
+
+// bundleObject is an object of a config bundle ready to be applied
+type bundleObject struct {
+	api          string
+	apiVersion   string
+	resourceType string
+	name         string
+	// content holds the labels, annotations and spec to apply
+	content map[string]interface{}
+	// current holds the labels, annotations and spec of the existing object, nil when it doesn't exist
+	current map[string]interface{}
+	// body is the object to create or the patch to update the existing object
+	body string
+}
+
+// ApplyConfigBundle applies the Istio objects of a multi-document YAML bundle in the namespace.
+// Every object is validated with a dry-run before applying anything, a single validation failure aborts
+// the whole bundle. Objects are created or updated depending on whether they exist. If applying an object
+// fails the objects already applied are reverted: created objects are deleted and updated objects are restored.
+// Each object applied, and each object rolled back, is recorded in the audit log.
+// The result reports the status of each object, also when an error is returned.
+func (in *IstioConfigService) ApplyConfigBundle(namespace string, bundle []byte) (result models.ConfigBundleResult, err error) {
+	promtimer := internalmetrics.GetGoFunctionMetric("business", "IstioConfigService", "ApplyConfigBundle")
+	defer promtimer.ObserveNow(&err)
+
+	result.Objects = []models.ConfigBundleObject{}
+
+	var objects []*bundleObject
+	if objects, err = parseConfigBundle(namespace, bundle); err != nil {
+		return result, errors2.NewBadRequest(err.Error())
+	}
+	for _, obj := range objects {
+		result.Objects = append(result.Objects, models.ConfigBundleObject{
+			ObjectType: obj.resourceType,
+			Name:       obj.name,
+			Namespace:  namespace,
+			Operation:  models.BundleOperationCreate,
+			Status:     models.BundleObjectNotApplied,
+		})
+	}
+
+	// Validate everything before applying anything
+	valid := true
+	for i, obj := range objects {
+		if vErr := in.validateBundleObject(namespace, obj); vErr != nil {
+			result.Objects[i].Status = models.BundleObjectFailed
+			result.Objects[i].Error = vErr.Error()
+			valid = false
+		} else {
+			result.Objects[i].Status = models.BundleObjectValidated
+		}
+		if obj.current != nil {
+			result.Objects[i].Operation = models.BundleOperationUpdate
+		}
+	}
+	if !valid {
+		return result, errors2.NewBadRequest("config bundle validation failed, no object was applied")
+	}
+
+	for i, obj := range objects {
+		if obj.current == nil {
+			_, err = in.k8s.CreateIstioObject(obj.api, namespace, obj.resourceType, obj.body)
+		} else {
+			_, err = in.k8s.UpdateIstioObject(obj.api, namespace, obj.resourceType, obj.name, obj.body)
+		}
+		if err != nil {
+			result.Objects[i].Status = models.BundleObjectFailed
+			result.Objects[i].Error = err.Error()
+			for j := i + 1; j < len(objects); j++ {
+				result.Objects[j].Status = models.BundleObjectNotApplied
+			}
+			in.rollbackConfigBundle(namespace, objects[:i], result.Objects[:i])
+			result.RolledBack = true
+			break
+		}
+		result.Objects[i].Status = models.BundleObjectApplied
+		if obj.current == nil {
+			in.businessLayer.Audit.Record("", AuditCreate, namespace, istioConfigKind(obj.resourceType), obj.name)
+		} else {
+			in.businessLayer.Audit.Record("", AuditUpdate, namespace, istioConfigKind(obj.resourceType), obj.name)
+		}
+	}
+	result.Applied = err == nil
+
+	// Cache is stopped after a Create/Update/Delete operation to force a refresh
+	if kialiCache != nil {
+		kialiCache.RefreshNamespace(namespace)
+	}
+	return result, err
+}
+
+// validateBundleObject checks whether the object exists, prepares the body to create or update it
+// and validates the operation with a dry-run
+func (in *IstioConfigService) validateBundleObject(namespace string, obj *bundleObject) error {
+	existing, err := in.k8s.GetIstioObject(namespace, obj.resourceType, obj.name)
+	if err != nil && !errors2.IsNotFound(err) {
+		return err
+	}
+
+	var body []byte
+	if err == nil {
+		obj.current = bundleObjectContent(existing.GetObjectMeta().Labels, existing.GetObjectMeta().Annotations, existing.GetSpec())
+		body, err = json.Marshal(bundlePatch(obj.current, obj.content, obj.content))
+		if err != nil {
+			return err
+		}
+		obj.body = string(body)
+		return in.k8s.DryRunUpdateIstioObject(obj.api, namespace, obj.resourceType, obj.name, obj.body)
+	}
+
+	create := map[string]interface{}{
+		"apiVersion": obj.apiVersion,
+		"kind":       kubernetes.PluralType[obj.resourceType],
+		"spec":       obj.content["spec"],
+	}
+	metadata := map[string]interface{}{
+		"name":      obj.name,
+		"namespace": namespace,
+	}
+	for k, v := range obj.content["metadata"].(map[string]interface{}) {
+		metadata[k] = v
+	}
+	create["metadata"] = metadata
+	if body, err = json.Marshal(create); err != nil {
+		return err
+	}
+	obj.body = string(body)
+	return in.k8s.DryRunCreateIstioObject(obj.api, namespace, obj.resourceType, obj.body)
+}
+
+// rollbackConfigBundle reverts the applied objects in reverse order: created objects are deleted
+// and updated objects are patched back to their previous spec, and labels and annotations set by the bundle
+func (in *IstioConfigService) rollbackConfigBundle(namespace string, applied []*bundleObject, results []models.ConfigBundleObject) {
+	for i := len(applied) - 1; i >= 0; i-- {
+		obj := applied[i]
+		var err error
+		operation := AuditUpdate
+		if obj.current == nil {
+			operation = AuditDelete
+			err = in.k8s.DeleteIstioObject(obj.api, namespace, obj.resourceType, obj.name)
+		} else {
+			var patch []byte
+			if patch, err = json.Marshal(bundlePatch(obj.content, obj.current, obj.content)); err == nil {
+				_, err = in.k8s.UpdateIstioObject(obj.api, namespace, obj.resourceType, obj.name, string(patch))
+			}
+		}
+		if err != nil {
+			log.Errorf("Error rolling back %s %s/%s: %v", obj.resourceType, namespace, obj.name, err)
+			results[i].Status = models.BundleObjectRollbackFailed
+			results[i].Error = err.Error()
+		} else {
+			results[i].Status = models.BundleObjectRolledBack
+			in.businessLayer.Audit.Record("", operation, namespace, istioConfigKind(obj.resourceType), obj.name)
+		}
+	}
+}
+
+// parseConfigBundle reads the Istio objects of a multi-document YAML (or JSON) bundle.
+// Objects must be supported Istio types and belong to the namespace, if they set one.
+func parseConfigBundle(namespace string, bundle []byte) ([]*bundleObject, error) {
+	kindToType := make(map[string]string, len(kubernetes.PluralType))
+	for resourceType, kind := range kubernetes.PluralType {
+		kindToType[kind] = resourceType
+	}
+
+	objects := make([]*bundleObject, 0)
+	seen := make(map[string]bool)
+	decoder := yaml.NewYAMLOrJSONDecoder(bytes.NewReader(bundle), 4096)
+	for i := 0; ; i++ {
+		var doc map[string]interface{}
+		if err := decoder.Decode(&doc); err != nil {
+			if err == io.EOF {
+				break
+			}
+			return nil, fmt.Errorf("document %d is not valid: %v", i, err)
+		}
+		if len(doc) == 0 {
+			continue
+		}
+
+		kind, _ := doc["kind"].(string)
+		apiVersion, _ := doc["apiVersion"].(string)
+		resourceType, found := kindToType[kind]
+		if !found {
+			return nil, fmt.Errorf("document %d: kind [%s] is not a supported Istio type", i, kind)
+		}
+		api := kubernetes.ResourceTypesToAPI[resourceType]
+		if !strings.HasPrefix(apiVersion, api+"/") {
+			return nil, fmt.Errorf("document %d: apiVersion [%s] doesn't match kind [%s]", i, apiVersion, kind)
+		}
+
+		metadata, _ := doc["metadata"].(map[string]interface{})
+		name, _ := metadata["name"].(string)
+		if name == "" {
+			return nil, fmt.Errorf("document %d: %s without name", i, kind)
+		}
+		if ns, _ := metadata["namespace"].(string); ns != "" && ns != namespace {
+			return nil, fmt.Errorf("document %d: %s %s belongs to namespace [%s], expected [%s]", i, kind, name, ns, namespace)
+		}
+		key := resourceType + "/" + name
+		if seen[key] {
+			return nil, fmt.Errorf("document %d: %s %s is duplicated", i, kind, name)
+		}
+		seen[key] = true
+
+		labels, _ := metadata["labels"].(map[string]interface{})
+		annotations, _ := metadata["annotations"].(map[string]interface{})
+		spec, _ := doc["spec"].(map[string]interface{})
+		objects = append(objects, &bundleObject{
+			api:          api,
+			apiVersion:   apiVersion,
+			resourceType: resourceType,
+			name:         name,
+			content:      bundleObjectContent(labels, annotations, spec),
+		})
+	}
+	if len(objects) == 0 {
+		return nil, fmt.Errorf("config bundle doesn't contain any object")
+	}
+	return objects, nil
+}
+
+// bundleObjectContent builds the part of an object managed by a config bundle.
+// Labels and annotations maps may hold strings or interface{} values.
+func bundleObjectContent(labels, annotations interface{}, spec map[string]interface{}) map[string]interface{} {
+	metadata := map[string]interface{}{}
+	for key, values := range map[string]interface{}{"labels": labels, "annotations": annotations} {
+		content := map[string]interface{}{}
+		switch v := values.(type) {
+		case map[string]string:
+			for k, value := range v {
+				content[k] = value
+			}
+		case map[string]interface{}:
+			for k, value := range v {
+				content[k] = value
+			}
+		}
+		metadata[key] = content
+	}
+	if spec == nil {
+		spec = map[string]interface{}{}
+	}
+	return map[string]interface{}{
+		"metadata": metadata,
+		"spec":     spec,
+	}
+}
+
+// bundlePatch returns the JSON merge patch that turns the from content of an object into the to content. The spec is
+// managed as a whole by the bundle, while only the labels and annotations of the bundle are: the other ones of the
+// object, set by other tools, are kept.
+func bundlePatch(from, to, bundle map[string]interface{}) map[string]interface{} {
+	toMetadata, _ := to["metadata"].(map[string]interface{})
+	bundleMetadata, _ := bundle["metadata"].(map[string]interface{})
+	metadata := map[string]interface{}{}
+	for _, key := range []string{"labels", "annotations"} {
+		toValues, _ := toMetadata[key].(map[string]interface{})
+		bundleValues, _ := bundleMetadata[key].(map[string]interface{})
+		values := make(map[string]interface{}, len(bundleValues))
+		for k := range bundleValues {
+			if v, found := toValues[k]; found {
+				values[k] = v
+			} else {
+				values[k] = nil
+			}
+		}
+		metadata[key] = values
+	}
+	fromSpec, _ := from["spec"].(map[string]interface{})
+	toSpec, _ := to["spec"].(map[string]interface{})
+	return map[string]interface{}{
+		"metadata": metadata,
+		"spec":     mergePatch(fromSpec, toSpec),
+	}
+}
+
+// mergePatch returns a JSON merge patch (RFC 7386) that turns from into to
+func mergePatch(from, to map[string]interface{}) map[string]interface{} {
+	patch := make(map[string]interface{}, len(to))
+	for k := range from {
+		if _, found := to[k]; !found {
+			patch[k] = nil
+		}
+	}
+	for k, v := range to {
+		fromMap, fromOk := from[k].(map[string]interface{})
+		toMap, toOk := v.(map[string]interface{})
+		if fromOk && toOk {
+			patch[k] = mergePatch(fromMap, toMap)
+		} else {
+			patch[k] = v
+		}
+	}
+	return patch
+}
@@ -0,0 +1,283 @@
+package business
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	errors2 "k8s.io/apimachinery/pkg/api/errors"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/kubernetes/kubetest"
+	"github.com/kiali/kiali/models"
+)
+
+const configBundle = `
+apiVersion: networking.istio.io/v1alpha3
+kind: VirtualService
+metadata:
+  name: reviews
+  namespace: bookinfo
+spec:
+  hosts:
+  - reviews
+  http:
+  - route:
+    - destination:
+        host: reviews
+        subset: v2
+---
+apiVersion: networking.istio.io/v1alpha3
+kind: DestinationRule
+metadata:
+  name: reviews
+  labels:
+    team: reviews
+spec:
+  host: reviews
+  subsets:
+  - name: v2
+    labels:
+      version: v2
+---
+apiVersion: security.istio.io/v1beta1
+kind: PeerAuthentication
+metadata:
+  name: default
+spec:
+  mtls:
+    mode: STRICT
+`
+
+func TestApplyConfigBundle(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	k8s := mockConfigBundle()
+	k8s.On("CreateIstioObject", "networking.istio.io", "bookinfo", "virtualservices", mock.AnythingOfType("string")).Return(&kubernetes.GenericIstioObject{}, nil)
+	k8s.On("UpdateIstioObject", "networking.istio.io", "bookinfo", "destinationrules", "reviews", mock.AnythingOfType("string")).Return(&kubernetes.GenericIstioObject{}, nil)
+	k8s.On("CreateIstioObject", "security.istio.io", "bookinfo", "peerauthentications", mock.AnythingOfType("string")).Return(&kubernetes.GenericIstioObject{}, nil)
+	configService := newConfigBundleService(k8s)
+
+	result, err := configService.ApplyConfigBundle("bookinfo", []byte(configBundle))
+	assert.NoError(err)
+	assert.True(result.Applied)
+	assert.False(result.RolledBack)
+	assert.Len(result.Objects, 3)
+	assertBundleObject(assert, result.Objects[0], "virtualservices", "reviews", models.BundleOperationCreate, models.BundleObjectApplied)
+	assertBundleObject(assert, result.Objects[1], "destinationrules", "reviews", models.BundleOperationUpdate, models.BundleObjectApplied)
+	assertBundleObject(assert, result.Objects[2], "peerauthentications", "default", models.BundleOperationCreate, models.BundleObjectApplied)
+
+	// Everything is validated before anything is applied
+	var methods []string
+	for _, call := range k8s.Calls {
+		if call.Method != "GetIstioObject" {
+			methods = append(methods, call.Method)
+		}
+	}
+	assert.Equal([]string{
+		"DryRunCreateIstioObject", "DryRunUpdateIstioObject", "DryRunCreateIstioObject",
+		"CreateIstioObject", "UpdateIstioObject", "CreateIstioObject",
+	}, methods)
+
+	// Created objects are complete and placed in the namespace
+	created := callBody(k8s, "CreateIstioObject", 0)
+	assert.Equal("VirtualService", created["kind"])
+	assert.Equal("networking.istio.io/v1alpha3", created["apiVersion"])
+	assert.Equal("bookinfo", created["metadata"].(map[string]interface{})["namespace"])
+	assert.Equal([]interface{}{"reviews"}, created["spec"].(map[string]interface{})["hosts"])
+
+	// Updates replace the spec: fields not present in the bundle are removed
+	patch := callBody(k8s, "UpdateIstioObject", 0)
+	spec := patch["spec"].(map[string]interface{})
+	assert.Contains(spec, "trafficPolicy")
+	assert.Nil(spec["trafficPolicy"])
+	assert.Equal("reviews", spec["host"])
+	// Only the labels and annotations of the bundle are set, the others of the object are kept
+	metadata := patch["metadata"].(map[string]interface{})
+	assert.Equal(map[string]interface{}{"team": "reviews"}, metadata["labels"])
+	assert.Equal(map[string]interface{}{}, metadata["annotations"])
+}
+
+func TestApplyConfigBundleValidationFailure(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	k8s := new(kubetest.K8SClientMock)
+	k8s.On("GetIstioObject", "bookinfo", "virtualservices", "reviews").Return(&kubernetes.GenericIstioObject{}, notFound("virtualservices", "reviews"))
+	k8s.On("GetIstioObject", "bookinfo", "destinationrules", "reviews").Return(existingReviewsDestinationRule(), nil)
+	k8s.On("GetIstioObject", "bookinfo", "peerauthentications", "default").Return(&kubernetes.GenericIstioObject{}, notFound("peerauthentications", "default"))
+	k8s.On("DryRunCreateIstioObject", "networking.istio.io", "bookinfo", "virtualservices", mock.AnythingOfType("string")).Return(nil)
+	k8s.On("DryRunUpdateIstioObject", "networking.istio.io", "bookinfo", "destinationrules", "reviews", mock.AnythingOfType("string")).Return(errors2.NewBadRequest("spec.subsets[0].name is required"))
+	k8s.On("DryRunCreateIstioObject", "security.istio.io", "bookinfo", "peerauthentications", mock.AnythingOfType("string")).Return(nil)
+	configService := newConfigBundleService(k8s)
+
+	result, err := configService.ApplyConfigBundle("bookinfo", []byte(configBundle))
+	assert.Error(err)
+	assert.True(errors2.IsBadRequest(err))
+	assert.False(result.Applied)
+	assert.False(result.RolledBack)
+	assertBundleObject(assert, result.Objects[0], "virtualservices", "reviews", models.BundleOperationCreate, models.BundleObjectValidated)
+	assertBundleObject(assert, result.Objects[1], "destinationrules", "reviews", models.BundleOperationUpdate, models.BundleObjectFailed)
+	assert.Equal("spec.subsets[0].name is required", result.Objects[1].Error)
+	assertBundleObject(assert, result.Objects[2], "peerauthentications", "default", models.BundleOperationCreate, models.BundleObjectValidated)
+
+	// Nothing is applied
+	k8s.AssertNotCalled(t, "CreateIstioObject", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	k8s.AssertNotCalled(t, "UpdateIstioObject", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestApplyConfigBundleRollback(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	k8s := mockConfigBundle()
+	k8s.On("CreateIstioObject", "networking.istio.io", "bookinfo", "virtualservices", mock.AnythingOfType("string")).Return(&kubernetes.GenericIstioObject{}, nil)
+	k8s.On("UpdateIstioObject", "networking.istio.io", "bookinfo", "destinationrules", "reviews", mock.AnythingOfType("string")).Return(&kubernetes.GenericIstioObject{}, nil)
+	k8s.On("CreateIstioObject", "security.istio.io", "bookinfo", "peerauthentications", mock.AnythingOfType("string")).Return(&kubernetes.GenericIstioObject{}, errors.New("admission webhook denied the request"))
+	k8s.On("DeleteIstioObject", "networking.istio.io", "bookinfo", "virtualservices", "reviews").Return(nil)
+	configService := newConfigBundleService(k8s)
+
+	result, err := configService.ApplyConfigBundle("bookinfo", []byte(configBundle))
+	assert.EqualError(err, "admission webhook denied the request")
+	assert.False(result.Applied)
+	assert.True(result.RolledBack)
+	assertBundleObject(assert, result.Objects[0], "virtualservices", "reviews", models.BundleOperationCreate, models.BundleObjectRolledBack)
+	assertBundleObject(assert, result.Objects[1], "destinationrules", "reviews", models.BundleOperationUpdate, models.BundleObjectRolledBack)
+	assertBundleObject(assert, result.Objects[2], "peerauthentications", "default", models.BundleOperationCreate, models.BundleObjectFailed)
+
+	// Created objects are deleted
+	k8s.AssertCalled(t, "DeleteIstioObject", "networking.istio.io", "bookinfo", "virtualservices", "reviews")
+
+	// Updated objects are restored to the previous state
+	k8s.AssertNumberOfCalls(t, "UpdateIstioObject", 2)
+	restore := callBody(k8s, "UpdateIstioObject", 1)
+	spec := restore["spec"].(map[string]interface{})
+	assert.Equal(map[string]interface{}{"connectionPool": map[string]interface{}{"tcp": map[string]interface{}{"maxConnections": float64(1)}}}, spec["trafficPolicy"])
+	assert.Nil(spec["subsets"])
+	// Only the labels of the bundle are restored
+	assert.Equal(map[string]interface{}{"team": "ratings"}, restore["metadata"].(map[string]interface{})["labels"])
+
+	// Rollback goes in reverse order
+	var methods []string
+	for _, call := range k8s.Calls {
+		if call.Method == "UpdateIstioObject" || call.Method == "DeleteIstioObject" {
+			methods = append(methods, call.Method)
+		}
+	}
+	assert.Equal([]string{"UpdateIstioObject", "UpdateIstioObject", "DeleteIstioObject"}, methods)
+}
+
+func TestApplyConfigBundleRollbackFailure(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	k8s := mockConfigBundle()
+	k8s.On("CreateIstioObject", "networking.istio.io", "bookinfo", "virtualservices", mock.AnythingOfType("string")).Return(&kubernetes.GenericIstioObject{}, nil)
+	k8s.On("UpdateIstioObject", "networking.istio.io", "bookinfo", "destinationrules", "reviews", mock.AnythingOfType("string")).Return(&kubernetes.GenericIstioObject{}, errors.New("conflict"))
+	k8s.On("DeleteIstioObject", "networking.istio.io", "bookinfo", "virtualservices", "reviews").Return(errors.New("forbidden"))
+	configService := newConfigBundleService(k8s)
+
+	result, err := configService.ApplyConfigBundle("bookinfo", []byte(configBundle))
+	assert.EqualError(err, "conflict")
+	assert.True(result.RolledBack)
+	assertBundleObject(assert, result.Objects[0], "virtualservices", "reviews", models.BundleOperationCreate, models.BundleObjectRollbackFailed)
+	assert.Equal("forbidden", result.Objects[0].Error)
+	assertBundleObject(assert, result.Objects[1], "destinationrules", "reviews", models.BundleOperationUpdate, models.BundleObjectFailed)
+	assertBundleObject(assert, result.Objects[2], "peerauthentications", "default", models.BundleOperationCreate, models.BundleObjectNotApplied)
+	k8s.AssertNotCalled(t, "CreateIstioObject", "security.istio.io", "bookinfo", "peerauthentications", mock.Anything)
+}
+
+func TestApplyConfigBundleInvalid(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	k8s := new(kubetest.K8SClientMock)
+	configService := newConfigBundleService(k8s)
+
+	bundles := map[string]string{
+		"empty":            "---\n---\n",
+		"unsupported kind": "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: istio\n",
+		"wrong api":        "apiVersion: security.istio.io/v1beta1\nkind: VirtualService\nmetadata:\n  name: reviews\n",
+		"without name":     "apiVersion: networking.istio.io/v1alpha3\nkind: VirtualService\nspec:\n  hosts:\n  - reviews\n",
+		"other namespace":  "apiVersion: networking.istio.io/v1alpha3\nkind: VirtualService\nmetadata:\n  name: reviews\n  namespace: istio-system\n",
+		"duplicated":       "apiVersion: networking.istio.io/v1alpha3\nkind: Gateway\nmetadata:\n  name: gw\n---\napiVersion: networking.istio.io/v1alpha3\nkind: Gateway\nmetadata:\n  name: gw\n",
+		"malformed":        "apiVersion: networking.istio.io/v1alpha3\nkind: [Gateway\n",
+	}
+	for name, bundle := range bundles {
+		result, err := configService.ApplyConfigBundle("bookinfo", []byte(bundle))
+		assert.True(errors2.IsBadRequest(err), name)
+		assert.Empty(result.Objects, name)
+	}
+	assert.Empty(k8s.Calls)
+}
+
+// newConfigBundleService returns the config service applying the bundles with the client, and auditing them
+func newConfigBundleService(k8s kubernetes.ClientInterface) IstioConfigService {
+	return IstioConfigService{k8s: k8s, businessLayer: &Layer{Audit: AuditLogger{}}}
+}
+
+func mockConfigBundle() *kubetest.K8SClientMock {
+	k8s := new(kubetest.K8SClientMock)
+	k8s.On("GetIstioObject", "bookinfo", "virtualservices", "reviews").Return(&kubernetes.GenericIstioObject{}, notFound("virtualservices", "reviews"))
+	k8s.On("GetIstioObject", "bookinfo", "destinationrules", "reviews").Return(existingReviewsDestinationRule(), nil)
+	k8s.On("GetIstioObject", "bookinfo", "peerauthentications", "default").Return(&kubernetes.GenericIstioObject{}, notFound("peerauthentications", "default"))
+	k8s.On("DryRunCreateIstioObject", mock.Anything, "bookinfo", mock.Anything, mock.AnythingOfType("string")).Return(nil)
+	k8s.On("DryRunUpdateIstioObject", mock.Anything, "bookinfo", mock.Anything, mock.Anything, mock.AnythingOfType("string")).Return(nil)
+	return k8s
+}
+
+func existingReviewsDestinationRule() kubernetes.IstioObject {
+	return &kubernetes.GenericIstioObject{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:        "reviews",
+			Namespace:   "bookinfo",
+			Labels:      map[string]string{"app": "reviews", "team": "ratings"},
+			Annotations: map[string]string{"kubectl.kubernetes.io/last-applied-configuration": "{}"},
+		},
+		Spec: map[string]interface{}{
+			"host": "reviews",
+			"trafficPolicy": map[string]interface{}{
+				"connectionPool": map[string]interface{}{
+					"tcp": map[string]interface{}{
+						"maxConnections": float64(1),
+					},
+				},
+			},
+		},
+	}
+}
+
+func notFound(resourceType, name string) error {
+	return errors2.NewNotFound(schema.GroupResource{Resource: resourceType}, name)
+}
+
+func assertBundleObject(assert *assert.Assertions, obj models.ConfigBundleObject, objectType, name, operation, status string) {
+	assert.Equal(objectType, obj.ObjectType)
+	assert.Equal(name, obj.Name)
+	assert.Equal("bookinfo", obj.Namespace)
+	assert.Equal(operation, obj.Operation)
+	assert.Equal(status, obj.Status)
+}
+
+// callBody returns the JSON body (last argument) sent in the nth call to the method
+func callBody(k8s *kubetest.K8SClientMock, method string, n int) map[string]interface{} {
+	for _, call := range k8s.Calls {
+		if call.Method != method {
+			continue
+		}
+		if n > 0 {
+			n--
+			continue
+		}
+		body := map[string]interface{}{}
+		_ = json.Unmarshal([]byte(call.Arguments.String(len(call.Arguments)-1)), &body)
+		return body
+	}
+	return nil
+}
@@ -0,0 +1,198 @@
+package business
+
+import (
+	"sync"
+
+	core_v1 "k8s.io/api/core/v1"
+
+	"github.com/kiali/kiali/business/checkers"
+	"github.com/kiali/kiali/business/checkers/common"
+	"github.com/kiali/kiali/business/checkers/virtual_services"
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/prometheus/internalmetrics"
+)
+
+// GetConfigReferenceGraph returns how the Istio objects of the namespace refer to each other and to the Services and
+// workloads, resolved with the same logic used by the validations. Only the resolved references are in the graph:
+// the dangling ones are reported by the validations.
+func (in *IstioValidationsService) GetConfigReferenceGraph(namespace string) (*models.ConfigReferenceGraph, error) {
+	var err error
+	promtimer := internalmetrics.GetGoFunctionMetric("business", "IstioValidationsService", "GetConfigReferenceGraph")
+	defer promtimer.ObserveNow(&err)
+
+	// Check if user has access to the namespace (RBAC) in cache scenarios and/or
+	// if namespace is accessible from Kiali (Deployment.AccessibleNamespaces)
+	if _, err = in.businessLayer.Namespace.GetNamespace(namespace); err != nil {
+		return nil, err
+	}
+
+	analysis := configReferenceAnalysis{namespace: namespace}
+
+	wg := sync.WaitGroup{}
+	errChan := make(chan error, 1)
+
+	wg.Add(6)
+	go in.fetchDetails(&analysis.istioDetails, namespace, errChan, &wg)
+	go in.fetchNamespaces(&analysis.namespaces, errChan, &wg)
+	go in.fetchServices(&analysis.services, namespace, errChan, &wg)
+	go in.fetchWorkloads(&analysis.workloads, namespace, errChan, &wg)
+	go in.fetchGatewaysPerNamespace(&analysis.gatewaysPerNamespace, errChan, &wg)
+	go in.fetchAuthorizationDetails(&analysis.rbacDetails, namespace, errChan, &wg)
+	wg.Wait()
+
+	close(errChan)
+	for e := range errChan {
+		if e != nil { // Check that default value wasn't returned
+			err = e
+			return nil, err
+		}
+	}
+
+	return analysis.graph(), nil
+}
+
+// configReferenceAnalysis resolves the references of the Istio objects of a namespace
+type configReferenceAnalysis struct {
+	namespace            string
+	namespaces           models.Namespaces
+	istioDetails         kubernetes.IstioDetails
+	services             []core_v1.Service
+	workloads            models.WorkloadList
+	gatewaysPerNamespace [][]kubernetes.IstioObject
+	rbacDetails          kubernetes.RBACDetails
+}
+
+func (a configReferenceAnalysis) graph() *models.ConfigReferenceGraph {
+	graph := &models.ConfigReferenceGraph{
+		Namespace: a.namespace,
+		Nodes:     []models.IstioValidationKey{},
+		Edges:     []models.ConfigReferenceEdge{},
+	}
+	for objectType, objects := range map[string][]kubernetes.IstioObject{
+		checkers.VirtualCheckerType:             a.istioDetails.VirtualServices,
+		checkers.DestinationRuleCheckerType:     a.istioDetails.DestinationRules,
+		checkers.GatewayCheckerType:             a.istioDetails.Gateways,
+		checkers.ServiceEntryCheckerType:        a.istioDetails.ServiceEntries,
+		checkers.AuthorizationPolicyCheckerType: a.rbacDetails.AuthorizationPolicies,
+	} {
+		for _, object := range objects {
+			graph.AddNode(objectKey(objectType, object))
+		}
+	}
+
+	// The Gateways of all the namespaces, by the names used to match them
+	gatewayNames := map[string]struct{}{}
+	gatewayKeys := map[string]models.IstioValidationKey{}
+	for _, nsGateways := range a.gatewaysPerNamespace {
+		for _, gw := range nsGateways {
+			for name := range kubernetes.GatewayNames([][]kubernetes.IstioObject{{gw}}) {
+				gatewayNames[name] = struct{}{}
+				gatewayKeys[name] = objectKey(checkers.GatewayCheckerType, gw)
+			}
+		}
+	}
+
+	for _, vs := range a.istioDetails.VirtualServices {
+		a.addGatewayReferences(graph, vs, gatewayNames, gatewayKeys)
+		a.addSubsetReferences(graph, vs)
+	}
+	for _, dr := range a.istioDetails.DestinationRules {
+		a.addServiceReference(graph, dr)
+	}
+	for _, ap := range a.rbacDetails.AuthorizationPolicies {
+		source := objectKey(checkers.AuthorizationPolicyCheckerType, ap)
+		for _, w := range common.SelectorNoWorkloadFoundChecker(checkers.AuthorizationPolicyCheckerType, ap, a.workloads).MatchingWorkloads() {
+			graph.AddEdge(models.ConfigReferenceEdge{
+				Source:    source,
+				Target:    models.BuildKey(checkers.WorkloadCheckerType, w.Name, a.namespace),
+				Reference: models.ConfigReferenceSelector,
+			})
+		}
+	}
+
+	graph.Sort()
+	return graph
+}
+
+// addGatewayReferences adds the references of a VirtualService to its existing Gateways, of any namespace
+func (a configReferenceAnalysis) addGatewayReferences(graph *models.ConfigReferenceGraph, vs kubernetes.IstioObject, gatewayNames map[string]struct{}, gatewayKeys map[string]models.IstioValidationKey) {
+	gateways, _ := virtualServiceGateways(vs)
+	if len(gateways) == 0 {
+		return
+	}
+
+	clusterName := vs.GetObjectMeta().ClusterName
+	if clusterName == "" {
+		clusterName = config.Get().ExternalServices.Istio.IstioIdentityDomain
+	}
+	source := objectKey(checkers.VirtualCheckerType, vs)
+	for _, gateway := range gateways {
+		if name, found := virtual_services.MatchingGateway(gateway, vs.GetObjectMeta().Namespace, clusterName, gatewayNames); found {
+			graph.AddEdge(models.ConfigReferenceEdge{
+				Source:    source,
+				Target:    gatewayKeys[name],
+				Reference: models.ConfigReferenceGateway,
+			})
+		}
+	}
+}
+
+// addSubsetReferences adds the references of the route destinations of a VirtualService to the DestinationRules
+// defining their subsets
+func (a configReferenceAnalysis) addSubsetReferences(graph *models.ConfigReferenceGraph, vs kubernetes.IstioObject) {
+	checker := virtual_services.SubsetPresenceChecker{
+		Namespace:        a.namespace,
+		Namespaces:       a.namespaces.GetNames(),
+		DestinationRules: a.istioDetails.DestinationRules,
+		VirtualService:   vs,
+	}
+	source := objectKey(checkers.VirtualCheckerType, vs)
+	for _, protocol := range []string{"http", "tcp", "tls"} {
+		routes, _ := vs.GetSpec()[protocol].([]interface{})
+		for _, r := range routes {
+			route, _ := r.(map[string]interface{})
+			destinations, _ := route["route"].([]interface{})
+			for _, d := range destinations {
+				destinationWeight, _ := d.(map[string]interface{})
+				destination, _ := destinationWeight["destination"].(map[string]interface{})
+				host, _ := destination["host"].(string)
+				subset, _ := destination["subset"].(string)
+				if host == "" || subset == "" {
+					continue
+				}
+				for _, dr := range checker.DestinationRulesWithSubset(host, subset) {
+					graph.AddEdge(models.ConfigReferenceEdge{
+						Source:    source,
+						Target:    objectKey(checkers.DestinationRuleCheckerType, dr),
+						Reference: models.ConfigReferenceSubset,
+						Subset:    subset,
+					})
+				}
+			}
+		}
+	}
+}
+
+// addServiceReference adds the reference of a DestinationRule to the Service of its host, when it is in the namespace
+func (a configReferenceAnalysis) addServiceReference(graph *models.ConfigReferenceGraph, dr kubernetes.IstioObject) {
+	host, ok := dr.GetSpec()["host"].(string)
+	if !ok {
+		return
+	}
+	fqdn := kubernetes.GetHost(host, dr.GetObjectMeta().Namespace, dr.GetObjectMeta().ClusterName, a.namespaces.GetNames())
+	localSvc, localNs := kubernetes.ParseTwoPartHost(fqdn)
+	if localNs != a.namespace || !kubernetes.HasMatchingServices(localSvc, a.services) {
+		return
+	}
+	graph.AddEdge(models.ConfigReferenceEdge{
+		Source:    objectKey(checkers.DestinationRuleCheckerType, dr),
+		Target:    models.BuildKey(checkers.ServiceCheckerType, localSvc, a.namespace),
+		Reference: models.ConfigReferenceHost,
+	})
+}
+
+func objectKey(objectType string, object kubernetes.IstioObject) models.IstioValidationKey {
+	return models.BuildKey(objectType, object.GetObjectMeta().Name, object.GetObjectMeta().Namespace)
+}
@@ -0,0 +1,78 @@
+package business
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	core_v1 "k8s.io/api/core/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/tests/data"
+)
+
+func TestConfigReferenceGraph(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	loader := &data.YamlFixtureLoader{Filename: "../tests/data/validations/references/bookinfo.yaml"}
+	if err := loader.Load(); err != nil {
+		t.Fatal("Error loading test data.")
+	}
+	gateways := loader.GetResources("Gateway")
+
+	analysis := configReferenceAnalysis{
+		namespace:  "bookinfo",
+		namespaces: models.Namespaces{{Name: "bookinfo"}, {Name: "istio-system"}},
+		istioDetails: kubernetes.IstioDetails{
+			VirtualServices:  loader.GetResources("VirtualService"),
+			DestinationRules: loader.GetResources("DestinationRule"),
+			Gateways:         gateways[:1],
+		},
+		services: []core_v1.Service{
+			{ObjectMeta: meta_v1.ObjectMeta{Name: "productpage", Namespace: "bookinfo"}},
+			{ObjectMeta: meta_v1.ObjectMeta{Name: "reviews", Namespace: "bookinfo"}},
+		},
+		workloads: data.CreateWorkloadList("bookinfo",
+			data.CreateWorkloadListItem("productpage-v1", map[string]string{"app": "productpage", "version": "v1"}),
+			data.CreateWorkloadListItem("reviews-v1", map[string]string{"app": "reviews", "version": "v1"}),
+			data.CreateWorkloadListItem("reviews-v2", map[string]string{"app": "reviews", "version": "v2"}),
+		),
+		gatewaysPerNamespace: [][]kubernetes.IstioObject{gateways[:1], gateways[1:]},
+		rbacDetails:          kubernetes.RBACDetails{AuthorizationPolicies: loader.GetResources("AuthorizationPolicy")},
+	}
+
+	graph := analysis.graph()
+	assert.Equal("bookinfo", graph.Namespace)
+
+	vsBookinfo := models.BuildKey("virtualservice", "bookinfo", "bookinfo")
+	vsReviews := models.BuildKey("virtualservice", "reviews", "bookinfo")
+	drReviews := models.BuildKey("destinationrule", "reviews", "bookinfo")
+	apViewer := models.BuildKey("authorizationpolicy", "reviews-viewer", "bookinfo")
+	apNothing := models.BuildKey("authorizationpolicy", "allow-nothing", "bookinfo")
+	expectedEdges := []models.ConfigReferenceEdge{
+		// Namespace-wide: all the workloads
+		{Source: apNothing, Target: models.BuildKey("workload", "productpage-v1", "bookinfo"), Reference: "selector"},
+		{Source: apNothing, Target: models.BuildKey("workload", "reviews-v1", "bookinfo"), Reference: "selector"},
+		{Source: apNothing, Target: models.BuildKey("workload", "reviews-v2", "bookinfo"), Reference: "selector"},
+		{Source: apViewer, Target: models.BuildKey("workload", "reviews-v1", "bookinfo"), Reference: "selector"},
+		{Source: apViewer, Target: models.BuildKey("workload", "reviews-v2", "bookinfo"), Reference: "selector"},
+		// No edge for the ghost host
+		{Source: drReviews, Target: models.BuildKey("service", "reviews", "bookinfo"), Reference: "host"},
+		// No edge for the missing gateway
+		{Source: vsBookinfo, Target: models.BuildKey("gateway", "bookinfo-gateway", "bookinfo"), Reference: "gateway"},
+		{Source: vsBookinfo, Target: models.BuildKey("gateway", "shared-gateway", "istio-system"), Reference: "gateway"},
+		// No edge for the undefined subset
+		{Source: vsReviews, Target: drReviews, Reference: "subset", Subset: "v1"},
+		{Source: vsReviews, Target: drReviews, Reference: "subset", Subset: "v2"},
+	}
+	assert.Equal(expectedEdges, graph.Edges)
+
+	// The objects of the namespace, even without reference, and the objects they refer to
+	assert.Len(graph.Nodes, 12)
+	assert.Contains(graph.Nodes, models.BuildKey("destinationrule", "ghost", "bookinfo"))
+	assert.Contains(graph.Nodes, models.BuildKey("gateway", "shared-gateway", "istio-system"))
+	assert.NotContains(graph.Nodes, models.BuildKey("service", "productpage", "bookinfo"))
+}
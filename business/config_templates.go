@@ -0,0 +1,45 @@
+package business
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	errors2 "k8s.io/apimachinery/pkg/api/errors"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+)
+
+// ConfigTemplateNamespacePlaceholder is replaced by the target namespace when a config template is instantiated
+const ConfigTemplateNamespacePlaceholder = "${NAMESPACE}"
+
+var configTemplatePlaceholderRegexp = regexp.MustCompile(`\$\{[^}]*\}`)
+
+// ApplyConfigTemplate instantiates the config template in the namespace and applies the resulting objects as a
+// config bundle: they are all validated before applying any of them. The result reports the created (or updated)
+// objects.
+func (in *IstioConfigService) ApplyConfigTemplate(namespace, template string) (models.ConfigBundleResult, error) {
+	bundle, err := instantiateConfigTemplate(namespace, template)
+	if err != nil {
+		return models.ConfigBundleResult{Objects: []models.ConfigBundleObject{}}, err
+	}
+	return in.ApplyConfigBundle(namespace, bundle)
+}
+
+// instantiateConfigTemplate returns the objects of the config template, with the namespace placeholder substituted.
+// Templates using other placeholders are rejected.
+func instantiateConfigTemplate(namespace, template string) ([]byte, error) {
+	for _, t := range config.Get().ConfigTemplates {
+		if t.Name != template {
+			continue
+		}
+		objects := strings.ReplaceAll(t.Objects, ConfigTemplateNamespacePlaceholder, namespace)
+		if unknown := configTemplatePlaceholderRegexp.FindString(objects); unknown != "" {
+			return nil, errors2.NewBadRequest(fmt.Sprintf("config template [%s] uses unknown placeholder %s", template, unknown))
+		}
+		return []byte(objects), nil
+	}
+	return nil, kubernetes.NewNotFound(template, "Kiali", "ConfigTemplate")
+}
@@ -0,0 +1,112 @@
+package business
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	errors2 "k8s.io/apimachinery/pkg/api/errors"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/kubernetes/kubetest"
+	"github.com/kiali/kiali/models"
+)
+
+const onboardingTemplate = `
+apiVersion: security.istio.io/v1beta1
+kind: PeerAuthentication
+metadata:
+  name: default
+  namespace: ${NAMESPACE}
+spec:
+  mtls:
+    mode: STRICT
+---
+apiVersion: networking.istio.io/v1alpha3
+kind: Sidecar
+metadata:
+  name: default
+  namespace: ${NAMESPACE}
+spec:
+  egress:
+  - hosts:
+    - ./*
+    - istio-system/*
+---
+apiVersion: networking.istio.io/v1alpha3
+kind: DestinationRule
+metadata:
+  name: default
+spec:
+  host: '*.${NAMESPACE}.svc.cluster.local'
+  trafficPolicy:
+    tls:
+      mode: ISTIO_MUTUAL
+`
+
+func setupConfigTemplates() {
+	conf := config.NewConfig()
+	conf.ConfigTemplates = []config.ConfigTemplate{
+		{Name: "onboarding", Objects: onboardingTemplate},
+		{Name: "broken", Objects: "metadata:\n  name: ${NAME}\n  namespace: ${NAMESPACE}\n"},
+	}
+	config.Set(conf)
+}
+
+func TestInstantiateConfigTemplate(t *testing.T) {
+	assert := assert.New(t)
+	setupConfigTemplates()
+
+	bundle, err := instantiateConfigTemplate("tutorial", "onboarding")
+	assert.NoError(err)
+	assert.NotContains(string(bundle), ConfigTemplateNamespacePlaceholder)
+	assert.Contains(string(bundle), "namespace: tutorial")
+	assert.Contains(string(bundle), "host: '*.tutorial.svc.cluster.local'")
+
+	_, err = instantiateConfigTemplate("tutorial", "broken")
+	assert.True(errors2.IsBadRequest(err))
+	assert.Contains(err.Error(), "${NAME}")
+
+	_, err = instantiateConfigTemplate("tutorial", "unknown")
+	assert.True(errors2.IsNotFound(err))
+}
+
+func TestApplyConfigTemplate(t *testing.T) {
+	assert := assert.New(t)
+	setupConfigTemplates()
+
+	k8s := new(kubetest.K8SClientMock)
+	k8s.On("GetIstioObject", "bookinfo", mock.AnythingOfType("string"), "default").Return(&kubernetes.GenericIstioObject{}, notFound("", "default"))
+	k8s.On("DryRunCreateIstioObject", mock.Anything, "bookinfo", mock.Anything, mock.AnythingOfType("string")).Return(nil)
+	k8s.On("CreateIstioObject", mock.Anything, "bookinfo", mock.Anything, mock.AnythingOfType("string")).Return(&kubernetes.GenericIstioObject{}, nil)
+	configService := newConfigBundleService(k8s)
+
+	result, err := configService.ApplyConfigTemplate("bookinfo", "onboarding")
+	assert.NoError(err)
+	assert.True(result.Applied)
+	assert.Len(result.Objects, 3)
+	assertBundleObject(assert, result.Objects[0], "peerauthentications", "default", models.BundleOperationCreate, models.BundleObjectApplied)
+	assertBundleObject(assert, result.Objects[1], "sidecars", "default", models.BundleOperationCreate, models.BundleObjectApplied)
+	assertBundleObject(assert, result.Objects[2], "destinationrules", "default", models.BundleOperationCreate, models.BundleObjectApplied)
+
+	// Objects are validated before being created in the target namespace
+	k8s.AssertNumberOfCalls(t, "DryRunCreateIstioObject", 3)
+	created := callBody(k8s, "CreateIstioObject", 2)
+	assert.Equal("bookinfo", created["metadata"].(map[string]interface{})["namespace"])
+	assert.Equal("*.bookinfo.svc.cluster.local", created["spec"].(map[string]interface{})["host"])
+}
+
+func TestApplyConfigTemplateNotFound(t *testing.T) {
+	assert := assert.New(t)
+	setupConfigTemplates()
+
+	k8s := new(kubetest.K8SClientMock)
+	configService := newConfigBundleService(k8s)
+
+	result, err := configService.ApplyConfigTemplate("bookinfo", "unknown")
+	assert.True(errors2.IsNotFound(err))
+	assert.False(result.Applied)
+	assert.Empty(result.Objects)
+	k8s.AssertNotCalled(t, "CreateIstioObject", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
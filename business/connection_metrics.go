@@ -0,0 +1,131 @@
+package business
+
+import (
+	"math"
+	"sort"
+
+	"github.com/prometheus/common/model"
+
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/prometheus/internalmetrics"
+)
+
+// connectionPeerLabels are the labels of the namespace and the name of the peers of a workload, per direction
+var connectionPeerLabels = map[string][2]string{
+	"inbound":  {"source_workload_namespace", "source_workload"},
+	"outbound": {"destination_service_namespace", "destination_service"},
+}
+
+// GetWorkloadConnectionMetrics returns the TCP connections of a workload, inbound and outbound, in total and per peer.
+// Istio doesn't report a gauge of the active connections, they are counted from the opened and closed connections
+// since the proxies started.
+func (in *MetricsService) GetWorkloadConnectionMetrics(q models.WorkloadConnectionMetricsQuery) (*models.WorkloadConnectionMetrics, error) {
+	var err error
+	promtimer := internalmetrics.GetGoFunctionMetric("business", "MetricsService", "GetWorkloadConnectionMetrics")
+	defer promtimer.ObserveNow(&err)
+
+	connectionMetrics := &models.WorkloadConnectionMetrics{
+		Namespace: q.Namespace,
+		Workload:  q.Workload,
+	}
+	connectionMetrics.Inbound, err = in.fetchConnectionCounts(q, "inbound")
+	if err != nil {
+		return nil, err
+	}
+	connectionMetrics.Outbound, err = in.fetchConnectionCounts(q, "outbound")
+	if err != nil {
+		return nil, err
+	}
+	return connectionMetrics, nil
+}
+
+func (in *MetricsService) fetchConnectionCounts(q models.WorkloadConnectionMetricsQuery, direction string) (models.ConnectionCounts, error) {
+	lb := NewMetricsLabelsBuilder(direction)
+	lb.SelfReporter()
+	lb.Workload(q.Workload, q.Namespace)
+	labels := lb.Build()
+
+	peerLabels := connectionPeerLabels[direction]
+	grouping := telemetryGrouping(peerLabels[0] + "," + peerLabels[1])
+	openedMetric := telemetryMetric("istio_tcp_connections_opened_total")
+	closedMetric := telemetryMetric("istio_tcp_connections_closed_total")
+
+	opened, err := in.prom.FetchValues(openedMetric, labels, grouping, q.QueryTime)
+	if err != nil {
+		return models.ConnectionCounts{}, err
+	}
+	closed, err := in.prom.FetchValues(closedMetric, labels, grouping, q.QueryTime)
+	if err != nil {
+		return models.ConnectionCounts{}, err
+	}
+	openedRates, err := in.prom.FetchRateValues(openedMetric, labels, grouping, q.RateInterval, q.QueryTime)
+	if err != nil {
+		return models.ConnectionCounts{}, err
+	}
+	closedRates, err := in.prom.FetchRateValues(closedMetric, labels, grouping, q.RateInterval, q.QueryTime)
+	if err != nil {
+		return models.ConnectionCounts{}, err
+	}
+	return buildConnectionCounts(peerLabels, opened, closed, openedRates, closedRates), nil
+}
+
+// buildConnectionCounts sums the connections per peer. Without any series the traffic isn't proxied as TCP, or
+// there hasn't been any connection since the proxies started.
+func buildConnectionCounts(peerLabels [2]string, opened, closed, openedRates, closedRates model.Vector) models.ConnectionCounts {
+	lblNamespace := model.LabelName(telemetryLabel(peerLabels[0]))
+	lblName := model.LabelName(telemetryLabel(peerLabels[1]))
+
+	peers := map[[2]string]*models.PeerConnectionCount{}
+	getPeer := func(sample *model.Sample) *models.PeerConnectionCount {
+		key := [2]string{string(sample.Metric[lblNamespace]), string(sample.Metric[lblName])}
+		if key[1] == "" {
+			key[1] = "unknown"
+		}
+		if _, found := peers[key]; !found {
+			peers[key] = &models.PeerConnectionCount{Namespace: key[0], Name: key[1]}
+		}
+		return peers[key]
+	}
+	sampleValue := func(sample *model.Sample) float64 {
+		if value := float64(sample.Value); !math.IsNaN(value) {
+			return value
+		}
+		return 0
+	}
+
+	counts := models.ConnectionCounts{Reported: len(opened) > 0, Peers: []models.PeerConnectionCount{}}
+	closedTotals := map[*models.PeerConnectionCount]float64{}
+	for _, sample := range opened {
+		getPeer(sample).Total += sampleValue(sample)
+	}
+	for _, sample := range closed {
+		closedTotals[getPeer(sample)] += sampleValue(sample)
+	}
+	for _, sample := range openedRates {
+		getPeer(sample).OpenedRate += sampleValue(sample)
+	}
+	for _, sample := range closedRates {
+		getPeer(sample).ClosedRate += sampleValue(sample)
+	}
+
+	for _, peer := range peers {
+		// The counters of a proxy are reset together, but the closed connections of a peer may be scraped later
+		peer.Active = math.Max(0, peer.Total-closedTotals[peer])
+		counts.Active += peer.Active
+		counts.Total += peer.Total
+		counts.OpenedRate += peer.OpenedRate
+		counts.ClosedRate += peer.ClosedRate
+		counts.Peers = append(counts.Peers, *peer)
+	}
+	sort.Slice(counts.Peers, func(i, j int) bool {
+		pi, pj := counts.Peers[i], counts.Peers[j]
+		if pi.Active != pj.Active {
+			return pi.Active > pj.Active
+		}
+		if pi.Namespace != pj.Namespace {
+			return pi.Namespace < pj.Namespace
+		}
+		return pi.Name < pj.Name
+	})
+	return counts
+}
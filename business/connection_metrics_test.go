@@ -0,0 +1,105 @@
+package business
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/prometheus/prometheustest"
+)
+
+// ratings-v2 serves HTTP and stores its ratings in MongoDB, over TCP
+func TestGetWorkloadConnectionMetrics(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	queryTime := time.Date(2017, 01, 15, 0, 0, 0, 0, time.UTC)
+	inbound := `{reporter="destination",destination_workload_namespace="bookinfo",destination_workload="ratings-v2"}`
+	inboundGrouping := "source_workload_namespace,source_workload"
+	outbound := `{reporter="source",source_workload_namespace="bookinfo",source_workload="ratings-v2"}`
+	outboundGrouping := "destination_service_namespace,destination_service"
+
+	prom := new(prometheustest.PromClientMock)
+	prom.On("FetchValues", "istio_tcp_connections_opened_total", inbound, inboundGrouping, queryTime).Return(model.Vector{}, nil)
+	prom.On("FetchValues", "istio_tcp_connections_closed_total", inbound, inboundGrouping, queryTime).Return(model.Vector{}, nil)
+	prom.On("FetchRateValues", "istio_tcp_connections_opened_total", inbound, inboundGrouping, "5m", queryTime).Return(model.Vector{}, nil)
+	prom.On("FetchRateValues", "istio_tcp_connections_closed_total", inbound, inboundGrouping, "5m", queryTime).Return(model.Vector{}, nil)
+	prom.On("FetchValues", "istio_tcp_connections_opened_total", outbound, outboundGrouping, queryTime).Return(mongodbConnections(120), nil)
+	prom.On("FetchValues", "istio_tcp_connections_closed_total", outbound, outboundGrouping, queryTime).Return(mongodbConnections(112), nil)
+	prom.On("FetchRateValues", "istio_tcp_connections_opened_total", outbound, outboundGrouping, "5m", queryTime).Return(mongodbConnections(0.2), nil)
+	prom.On("FetchRateValues", "istio_tcp_connections_closed_total", outbound, outboundGrouping, "5m", queryTime).Return(mongodbConnections(0.15), nil)
+
+	connectionMetrics, err := NewMetricsService(prom).GetWorkloadConnectionMetrics(models.WorkloadConnectionMetricsQuery{
+		Namespace:    "bookinfo",
+		Workload:     "ratings-v2",
+		RateInterval: "5m",
+		QueryTime:    queryTime,
+	})
+
+	assert.NoError(err)
+	assert.Equal("ratings-v2", connectionMetrics.Workload)
+	// The HTTP traffic doesn't report connections
+	assert.False(connectionMetrics.Inbound.Reported)
+	assert.Empty(connectionMetrics.Inbound.Peers)
+
+	outboundCounts := connectionMetrics.Outbound
+	assert.True(outboundCounts.Reported)
+	assert.Equal(models.ConnectionCount{Active: 8, Total: 120, OpenedRate: 0.2, ClosedRate: 0.15}, outboundCounts.ConnectionCount)
+	assert.Equal([]models.PeerConnectionCount{{
+		Namespace:       "bookinfo",
+		Name:            "mongodb.bookinfo.svc.cluster.local",
+		ConnectionCount: models.ConnectionCount{Active: 8, Total: 120, OpenedRate: 0.2, ClosedRate: 0.15},
+	}}, outboundCounts.Peers)
+	prom.AssertExpectations(t)
+}
+
+// mongodb-v1 is a TCP-only workload, receiving connections from several workloads
+func TestConnectionCountsPerPeer(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	sample := func(namespace, workload string, value float64) *model.Sample {
+		metric := model.Metric{}
+		if workload != "" {
+			metric["source_workload_namespace"] = model.LabelValue(namespace)
+			metric["source_workload"] = model.LabelValue(workload)
+		}
+		return &model.Sample{Metric: metric, Value: model.SampleValue(value)}
+	}
+	opened := model.Vector{sample("bookinfo", "ratings-v2", 120), sample("travel", "travels-v1", 40), sample("", "", 3)}
+	// The closed connections of travels-v1 were scraped after a new connection was opened and closed
+	closed := model.Vector{sample("bookinfo", "ratings-v2", 112), sample("travel", "travels-v1", 41), sample("", "", 3)}
+	openedRates := model.Vector{sample("bookinfo", "ratings-v2", 0.2), sample("travel", "travels-v1", 0.05)}
+	closedRates := model.Vector{sample("bookinfo", "ratings-v2", 0.15), sample("travel", "travels-v1", 0.05)}
+
+	counts := buildConnectionCounts(connectionPeerLabels["inbound"], opened, closed, openedRates, closedRates)
+
+	assert.True(counts.Reported)
+	assert.Equal(8.0, counts.Active)
+	assert.Equal(163.0, counts.Total)
+	assert.InDelta(0.25, counts.OpenedRate, 0.001)
+	assert.InDelta(0.2, counts.ClosedRate, 0.001)
+
+	// Sorted by active connections
+	assert.Len(counts.Peers, 3)
+	assert.Equal("ratings-v2", counts.Peers[0].Name)
+	assert.Equal(8.0, counts.Peers[0].Active)
+	assert.Equal(models.PeerConnectionCount{Namespace: "", Name: "unknown", ConnectionCount: models.ConnectionCount{Total: 3}}, counts.Peers[1])
+	assert.Equal("travels-v1", counts.Peers[2].Name)
+	assert.Equal(0.0, counts.Peers[2].Active)
+	assert.Equal(40.0, counts.Peers[2].Total)
+}
+
+func mongodbConnections(value float64) model.Vector {
+	return model.Vector{&model.Sample{
+		Metric: model.Metric{
+			"destination_service_namespace": "bookinfo",
+			"destination_service":           "mongodb.bookinfo.svc.cluster.local",
+		},
+		Value: model.SampleValue(value),
+	}}
+}
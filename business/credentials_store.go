@@ -0,0 +1,247 @@
+package business
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s.io/client-go/tools/clientcmd/api"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/log"
+)
+
+// The external services whose credentials can be read from a credentials store
+const (
+	CredentialsServiceGrafana    = "grafana"
+	CredentialsServicePrometheus = "prometheus"
+	CredentialsServiceTracing    = "tracing"
+)
+
+// The built-in credentials stores
+const (
+	CredentialsStoreEnv    = "env"
+	CredentialsStoreSecret = "kubernetes_secret"
+)
+
+var credentialsServices = []string{CredentialsServiceGrafana, CredentialsServicePrometheus, CredentialsServiceTracing}
+
+// Credentials are the credentials of an external service. The empty ones are taken from the config.
+type Credentials struct {
+	Username string
+	Password string
+	Token    string
+}
+
+// String hides the values of the credentials, so that they are never logged by mistake
+func (c Credentials) String() string {
+	return "[redacted]"
+}
+
+// CredentialsStore is a backend the credentials of the external services are read from
+type CredentialsStore interface {
+	// GetCredentials returns the credentials of the external service: grafana, prometheus or tracing
+	GetCredentials(service string) (Credentials, error)
+}
+
+// CredentialsStoreFactory creates the credentials store of the given configuration
+type CredentialsStoreFactory func(conf config.CredentialsStoreConfig) (CredentialsStore, error)
+
+var credentialsStoreFactories = map[string]CredentialsStoreFactory{
+	CredentialsStoreEnv:    newEnvCredentialsStore,
+	CredentialsStoreSecret: newSecretCredentialsStore,
+}
+
+// RegisterCredentialsStore makes an external credentials store available under the type, to be selected in the config.
+// It must be called before the credentials refresher is started, usually from an init function.
+func RegisterCredentialsStore(storeType string, factory CredentialsStoreFactory) {
+	credentialsStoreFactories[storeType] = factory
+}
+
+var (
+	credentialsLock   sync.RWMutex
+	storedCredentials = map[string]Credentials{}
+
+	credentialsRefresherLock sync.Mutex
+	credentialsStopChan      chan struct{}
+)
+
+// StartCredentialsRefresher reads the credentials of the external services from the configured credentials store,
+// then reads them again periodically so that rotated credentials are picked up without restarting Kiali.
+// Nothing is done without credentials store: the credentials of the config are used.
+func StartCredentialsRefresher() error {
+	conf := config.Get().ExternalServices.CredentialsStore
+	if conf.Type == "" {
+		return nil
+	}
+	factory, found := credentialsStoreFactories[conf.Type]
+	if !found {
+		return fmt.Errorf("unknown credentials store [%s]", conf.Type)
+	}
+	store, err := factory(conf)
+	if err != nil {
+		return err
+	}
+
+	credentialsRefresherLock.Lock()
+	defer credentialsRefresherLock.Unlock()
+	if credentialsStopChan != nil {
+		return nil
+	}
+	interval := time.Duration(conf.RefreshInterval) * time.Second
+	if interval <= 0 {
+		interval = time.Minute
+	}
+	stopChan := make(chan struct{})
+	credentialsStopChan = stopChan
+
+	// The first read is done before any client is created
+	refreshCredentials(store)
+	log.Infof("Reading the credentials of the external services from the [%s] store every %v", conf.Type, interval)
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				refreshCredentials(store)
+			case <-stopChan:
+				log.Info("Credentials refresher stopped")
+				return
+			}
+		}
+	}()
+	return nil
+}
+
+// StopCredentialsRefresher stops the credentials refresher if it's running
+func StopCredentialsRefresher() {
+	credentialsRefresherLock.Lock()
+	defer credentialsRefresherLock.Unlock()
+	if credentialsStopChan != nil {
+		close(credentialsStopChan)
+		credentialsStopChan = nil
+	}
+}
+
+// refreshCredentials reads the credentials of every external service. The credentials read before are kept when the
+// store fails. The clients authenticated with rotated credentials are created again.
+func refreshCredentials(store CredentialsStore) {
+	// The store is read out of the lock, not to block the creation of the clients
+	read := map[string]Credentials{}
+	for _, service := range credentialsServices {
+		credentials, err := store.GetCredentials(service)
+		if err != nil {
+			log.Errorf("Unable to read the credentials of [%s], the previous ones are kept: %v", service, err)
+			continue
+		}
+		read[service] = credentials
+	}
+
+	rotated := map[string]bool{}
+	credentialsLock.Lock()
+	for service, credentials := range read {
+		if previous, found := storedCredentials[service]; found && previous == credentials {
+			continue
+		}
+		storedCredentials[service] = credentials
+		rotated[service] = true
+		log.Infof("Credentials of [%s] updated from the credentials store", service)
+	}
+	credentialsLock.Unlock()
+
+	// Out of the lock, as the creation of the clients resolves the credentials
+	if rotated[CredentialsServicePrometheus] {
+		resetPrometheusClient()
+	}
+}
+
+// resolveAuth returns the auth of the external service, with the credentials of the store replacing those of the config
+func resolveAuth(service string, auth config.Auth) config.Auth {
+	credentialsLock.RLock()
+	defer credentialsLock.RUnlock()
+	credentials, found := storedCredentials[service]
+	if !found {
+		return auth
+	}
+	if credentials.Username != "" {
+		auth.Username = credentials.Username
+	}
+	if credentials.Password != "" {
+		auth.Password = credentials.Password
+	}
+	if credentials.Token != "" {
+		auth.Token = credentials.Token
+	}
+	return auth
+}
+
+// EnvCredentialsStore reads the credentials from the environment variables <SERVICE>_USERNAME, <SERVICE>_PASSWORD
+// and <SERVICE>_TOKEN, like PROMETHEUS_PASSWORD
+type EnvCredentialsStore struct {
+	getenv func(string) string
+}
+
+func newEnvCredentialsStore(conf config.CredentialsStoreConfig) (CredentialsStore, error) {
+	return &EnvCredentialsStore{getenv: os.Getenv}, nil
+}
+
+func (in *EnvCredentialsStore) GetCredentials(service string) (Credentials, error) {
+	prefix := strings.ToUpper(service)
+	return Credentials{
+		Username: in.getenv(prefix + "_USERNAME"),
+		Password: in.getenv(prefix + "_PASSWORD"),
+		Token:    in.getenv(prefix + "_TOKEN"),
+	}, nil
+}
+
+// SecretCredentialsStore reads the credentials from the keys <service>-username, <service>-password and
+// <service>-token of a Secret, like prometheus-password. The Secret is read with the Kiali ServiceAccount.
+type SecretCredentialsStore struct {
+	k8s       func() (kubernetes.ClientInterface, error)
+	namespace string
+	name      string
+}
+
+func newSecretCredentialsStore(conf config.CredentialsStoreConfig) (CredentialsStore, error) {
+	if conf.SecretName == "" {
+		return nil, fmt.Errorf("the credentials store [%s] requires a secret_name", CredentialsStoreSecret)
+	}
+	namespace := conf.SecretNamespace
+	if namespace == "" {
+		namespace = config.Get().Deployment.Namespace
+	}
+	return &SecretCredentialsStore{k8s: kialiSAClient, namespace: namespace, name: conf.SecretName}, nil
+}
+
+func (in *SecretCredentialsStore) GetCredentials(service string) (Credentials, error) {
+	k8s, err := in.k8s()
+	if err != nil {
+		return Credentials{}, err
+	}
+	secret, err := k8s.GetSecret(in.namespace, in.name)
+	if err != nil {
+		return Credentials{}, err
+	}
+	return Credentials{
+		Username: string(secret.Data[service+"-username"]),
+		Password: string(secret.Data[service+"-password"]),
+		Token:    string(secret.Data[service+"-token"]),
+	}, nil
+}
+
+// kialiSAClient returns a client with the Kiali ServiceAccount, whose token may be rotated too
+func kialiSAClient() (kubernetes.ClientInterface, error) {
+	clientFactory, err := kubernetes.GetClientFactory()
+	if err != nil {
+		return nil, err
+	}
+	kialiToken, err := kubernetes.GetKialiToken()
+	if err != nil {
+		return nil, err
+	}
+	return clientFactory.GetClient(&api.AuthInfo{Token: kialiToken})
+}
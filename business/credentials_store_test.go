@@ -0,0 +1,177 @@
+package business
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	core_v1 "k8s.io/api/core/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/kubernetes/kubetest"
+	"github.com/kiali/kiali/prometheus/prometheustest"
+)
+
+// fakeCredentialsStore returns the credentials of its map, which are rotated by the tests
+type fakeCredentialsStore struct {
+	credentials map[string]Credentials
+	err         error
+}
+
+func (in *fakeCredentialsStore) GetCredentials(service string) (Credentials, error) {
+	if in.err != nil {
+		return Credentials{}, in.err
+	}
+	return in.credentials[service], nil
+}
+
+func resetStoredCredentials() {
+	storedCredentials = map[string]Credentials{}
+}
+
+func TestResolveAuthWithoutStore(t *testing.T) {
+	assert := assert.New(t)
+	defer resetStoredCredentials()
+
+	auth := config.Auth{Type: config.AuthTypeBasic, Username: "admin", Password: "config"}
+	assert.Equal(auth, resolveAuth(CredentialsServicePrometheus, auth))
+}
+
+func TestRefreshCredentialsRotation(t *testing.T) {
+	assert := assert.New(t)
+	defer resetStoredCredentials()
+
+	store := &fakeCredentialsStore{credentials: map[string]Credentials{
+		CredentialsServicePrometheus: {Password: "first"},
+		CredentialsServiceTracing:    {Token: "tracing-token"},
+	}}
+	refreshCredentials(store)
+
+	auth := config.Auth{Type: config.AuthTypeBasic, Username: "admin", Password: "config"}
+	assert.Equal(config.Auth{Type: config.AuthTypeBasic, Username: "admin", Password: "first"}, resolveAuth(CredentialsServicePrometheus, auth))
+	assert.Equal("tracing-token", resolveAuth(CredentialsServiceTracing, config.Auth{}).Token)
+	assert.Equal(auth, resolveAuth(CredentialsServiceGrafana, auth))
+
+	// The Prometheus client authenticated with the previous password is created again
+	SetWithBackends(nil, new(prometheustest.PromClientMock))
+	store.credentials[CredentialsServicePrometheus] = Credentials{Password: "second"}
+	refreshCredentials(store)
+
+	assert.Equal("second", resolveAuth(CredentialsServicePrometheus, auth).Password)
+	assert.Nil(prometheusClient)
+
+	// The client is kept when the credentials didn't change
+	SetWithBackends(nil, new(prometheustest.PromClientMock))
+	refreshCredentials(store)
+	assert.NotNil(prometheusClient)
+	SetWithBackends(nil, nil)
+}
+
+func TestPrometheusClientsUseRotatedCredentials(t *testing.T) {
+	assert := assert.New(t)
+	defer resetStoredCredentials()
+
+	server := stubServer(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer rotated-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte(`{"status":"success","data":{"yaml":"global:\n  scrape_interval: 15s\n","resultType":"vector","result":[]}}`))
+	})
+	defer server.Close()
+
+	conf := config.NewConfig()
+	conf.ExternalServices.Prometheus.URL = server.URL
+	conf.ExternalServices.Prometheus.Auth = config.Auth{Type: config.AuthTypeBearer, Token: "config-token"}
+	config.Set(conf)
+	refreshCredentials(&fakeCredentialsStore{credentials: map[string]Credentials{
+		CredentialsServicePrometheus: {Token: "rotated-token"},
+	}})
+
+	client, err := NewPrometheusClient()
+	assert.NoError(err)
+	_, err = client.GetConfiguration()
+	assert.NoError(err)
+
+	iss := IstioStatusService{}
+	assert.True(*iss.DiagnosePrometheus().Authenticated)
+}
+
+func TestRefreshCredentialsKeepsPreviousOnError(t *testing.T) {
+	assert := assert.New(t)
+	defer resetStoredCredentials()
+
+	store := &fakeCredentialsStore{credentials: map[string]Credentials{
+		CredentialsServiceGrafana: {Username: "viewer", Password: "first"},
+	}}
+	refreshCredentials(store)
+
+	store.err = errors.New("store unavailable")
+	refreshCredentials(store)
+
+	auth := resolveAuth(CredentialsServiceGrafana, config.Auth{Username: "admin", Password: "config"})
+	assert.Equal("viewer", auth.Username)
+	assert.Equal("first", auth.Password)
+}
+
+func TestEnvCredentialsStore(t *testing.T) {
+	assert := assert.New(t)
+
+	env := map[string]string{
+		"PROMETHEUS_USERNAME": "admin",
+		"PROMETHEUS_PASSWORD": "secret",
+		"TRACING_TOKEN":       "token",
+	}
+	store := &EnvCredentialsStore{getenv: func(key string) string { return env[key] }}
+
+	credentials, err := store.GetCredentials(CredentialsServicePrometheus)
+	assert.NoError(err)
+	assert.Equal(Credentials{Username: "admin", Password: "secret"}, credentials)
+
+	credentials, err = store.GetCredentials(CredentialsServiceTracing)
+	assert.NoError(err)
+	assert.Equal(Credentials{Token: "token"}, credentials)
+}
+
+func TestSecretCredentialsStore(t *testing.T) {
+	assert := assert.New(t)
+
+	k8s := new(kubetest.K8SClientMock)
+	k8s.On("GetSecret", "istio-system", "kiali-credentials").Return(&core_v1.Secret{
+		ObjectMeta: meta_v1.ObjectMeta{Name: "kiali-credentials", Namespace: "istio-system"},
+		Data: map[string][]byte{
+			"grafana-username": []byte("viewer"),
+			"grafana-password": []byte("secret"),
+		},
+	}, nil)
+	store := &SecretCredentialsStore{
+		k8s:       func() (kubernetes.ClientInterface, error) { return k8s, nil },
+		namespace: "istio-system",
+		name:      "kiali-credentials",
+	}
+
+	credentials, err := store.GetCredentials(CredentialsServiceGrafana)
+	assert.NoError(err)
+	assert.Equal(Credentials{Username: "viewer", Password: "secret"}, credentials)
+
+	credentials, err = store.GetCredentials(CredentialsServicePrometheus)
+	assert.NoError(err)
+	assert.Equal(Credentials{}, credentials)
+}
+
+func TestSecretCredentialsStoreRequiresName(t *testing.T) {
+	config.Set(config.NewConfig())
+
+	_, err := newSecretCredentialsStore(config.CredentialsStoreConfig{Type: CredentialsStoreSecret})
+	assert.Error(t, err)
+}
+
+func TestCredentialsAreRedacted(t *testing.T) {
+	credentials := Credentials{Username: "admin", Password: "secret", Token: "token"}
+	assert.Equal(t, "[redacted]", fmt.Sprintf("%v", credentials))
+	assert.NotContains(t, fmt.Sprintf("%+v", credentials), "secret")
+}
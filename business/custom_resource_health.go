@@ -0,0 +1,71 @@
+package business
+
+import (
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/log"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/prometheus/internalmetrics"
+)
+
+const defaultCustomResourceHealthCondition = "Ready"
+
+// GetNamespaceCustomResourceHealth returns the health of the custom resources of the namespace, for the kinds
+// listed in the health configuration. A kind whose CRD is not installed, or can't be listed by the user, is skipped.
+func (in *HealthService) GetNamespaceCustomResourceHealth(namespace string) (models.NamespaceCustomResourceHealth, error) {
+	var err error
+	promtimer := internalmetrics.GetGoFunctionMetric("business", "HealthService", "GetNamespaceCustomResourceHealth")
+	defer promtimer.ObserveNow(&err)
+
+	// Check if user has access to the namespace (RBAC) in cache scenarios and/or
+	// if namespace is accessible from Kiali (Deployment.AccessibleNamespaces)
+	if _, err = in.businessLayer.Namespace.GetNamespace(namespace); err != nil {
+		return nil, err
+	}
+
+	health := models.NamespaceCustomResourceHealth{}
+	for _, crConf := range config.Get().HealthConfig.CustomResources {
+		resource := customResourcePlural(crConf)
+		crs, crErr := in.k8s.GetCustomResources(namespace, crConf.Group, crConf.Version, resource)
+		if crErr != nil {
+			if errors.IsNotFound(crErr) || errors.IsForbidden(crErr) {
+				log.Debugf("Health of %s.%s/%s in namespace [%s] skipped: %v", resource, crConf.Group, crConf.Version, namespace, crErr)
+				continue
+			}
+			err = crErr
+			return nil, err
+		}
+		condition := crConf.Condition
+		if condition == "" {
+			condition = defaultCustomResourceHealthCondition
+		}
+		for _, cr := range crs {
+			h := models.ParseCustomResourceHealth(cr, condition)
+			// The API may omit the kind of the items of a list
+			if h.Kind == "" {
+				h.Group, h.Version, h.Kind = crConf.Group, crConf.Version, crConf.Kind
+			}
+			health = append(health, h)
+		}
+	}
+
+	sort.SliceStable(health, func(i, j int) bool {
+		if health[i].Kind != health[j].Kind {
+			return health[i].Kind < health[j].Kind
+		}
+		return health[i].Name < health[j].Name
+	})
+	return health, nil
+}
+
+// customResourcePlural returns the resource of the kind in the API, when not configured the lowercase kind followed by "s"
+func customResourcePlural(crConf config.CustomResourceHealthConfig) string {
+	if crConf.Resource != "" {
+		return crConf.Resource
+	}
+	return strings.ToLower(crConf.Kind) + "s"
+}
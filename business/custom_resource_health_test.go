@@ -0,0 +1,111 @@
+package business
+
+import (
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	osproject_v1 "github.com/openshift/api/project/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/yaml"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes/kubetest"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/prometheus/prometheustest"
+)
+
+func loadCustomResources(t *testing.T, path string) []unstructured.Unstructured {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Error loading test data: %v", err)
+	}
+	crs := []unstructured.Unstructured{}
+	for _, doc := range strings.Split(string(content), "\n---\n") {
+		js, err := yaml.ToJSON([]byte(doc))
+		if err != nil {
+			t.Fatalf("Error parsing test data: %v", err)
+		}
+		cr := unstructured.Unstructured{}
+		if err := cr.UnmarshalJSON(js); err != nil {
+			t.Fatalf("Error parsing test data: %v", err)
+		}
+		crs = append(crs, cr)
+	}
+	return crs
+}
+
+func TestGetNamespaceCustomResourceHealth(t *testing.T) {
+	assert := assert.New(t)
+
+	conf := config.NewConfig()
+	conf.HealthConfig.CustomResources = []config.CustomResourceHealthConfig{
+		{Group: "kafka.strimzi.io", Version: "v1beta2", Kind: "Kafka"},
+		// Not installed in the cluster
+		{Group: "cert-manager.io", Version: "v1", Kind: "Certificate", Condition: "Issued"},
+	}
+	config.Set(conf)
+
+	k8s := new(kubetest.K8SClientMock)
+	prom := new(prometheustest.PromClientMock)
+	k8s.On("IsOpenShift").Return(true)
+	k8s.On("GetProject", mock.AnythingOfType("string")).Return(&osproject_v1.Project{}, nil)
+	k8s.On("GetCustomResources", "bookinfo", "kafka.strimzi.io", "v1beta2", "kafkas").
+		Return(loadCustomResources(t, "../tests/data/health/custom_resources.yaml"), nil)
+	k8s.On("GetCustomResources", "bookinfo", "cert-manager.io", "v1", "certificates").
+		Return([]unstructured.Unstructured{}, errors.NewNotFound(schema.GroupResource{Group: "cert-manager.io", Resource: "certificates"}, ""))
+
+	hs := HealthService{k8s: k8s, prom: prom, businessLayer: NewWithBackends(k8s, prom, nil)}
+
+	health, err := hs.GetNamespaceCustomResourceHealth("bookinfo")
+	assert.NoError(err)
+	assert.Len(health, 4)
+
+	assert.Equal("audit", health[0].Name)
+	assert.Equal("kafka.strimzi.io", health[0].Group)
+	assert.Equal("Kafka", health[0].Kind)
+	assert.Equal("bookinfo", health[0].Namespace)
+	assert.Equal("Ready", health[0].Condition)
+	assert.Equal(models.CustomResourceUnhealthy, health[0].Status)
+	assert.Equal("ZooKeeperNotReady", health[0].Reason)
+	assert.Equal("ZooKeeper cluster is not ready", health[0].Message)
+	assert.Equal("2021-10-16T10:02:11Z", health[0].LastTransitionTime)
+
+	assert.Equal("events", health[1].Name)
+	assert.Equal(models.CustomResourceHealthy, health[1].Status)
+
+	// Without the condition
+	assert.Equal("logs", health[2].Name)
+	assert.Equal(models.CustomResourceNA, health[2].Status)
+	assert.Empty(health[2].Reason)
+
+	// The condition was set for a previous generation
+	assert.Equal("metrics", health[3].Name)
+	assert.Equal(models.CustomResourceUnknown, health[3].Status)
+}
+
+func TestGetNamespaceCustomResourceHealthError(t *testing.T) {
+	assert := assert.New(t)
+
+	conf := config.NewConfig()
+	conf.HealthConfig.CustomResources = []config.CustomResourceHealthConfig{
+		{Group: "kafka.strimzi.io", Version: "v1beta2", Kind: "Kafka", Resource: "kafkas"},
+	}
+	config.Set(conf)
+
+	k8s := new(kubetest.K8SClientMock)
+	prom := new(prometheustest.PromClientMock)
+	k8s.On("IsOpenShift").Return(true)
+	k8s.On("GetProject", mock.AnythingOfType("string")).Return(&osproject_v1.Project{}, nil)
+	k8s.On("GetCustomResources", "bookinfo", "kafka.strimzi.io", "v1beta2", "kafkas").
+		Return([]unstructured.Unstructured{}, errors.NewServiceUnavailable("unavailable"))
+
+	hs := HealthService{k8s: k8s, prom: prom, businessLayer: NewWithBackends(k8s, prom, nil)}
+
+	_, err := hs.GetNamespaceCustomResourceHealth("bookinfo")
+	assert.Error(err)
+}
@@ -5,7 +5,10 @@ import (
 	"sort"
 	"strings"
 	"sync"
+	"time"
 
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/tools/clientcmd/api"
 
 	"github.com/kiali/kiali/config"
@@ -26,6 +29,7 @@ type DashboardsService struct {
 	promConfig      config.PrometheusConfig
 	globalNamespace string
 	namespaceLabel  string
+	namespaceScope  *namespaceScope
 	CustomEnabled   bool
 }
 
@@ -34,6 +38,7 @@ func NewDashboardsService() *DashboardsService {
 	cfg := config.Get()
 	customEnabled := cfg.ExternalServices.CustomDashboards.Enabled
 	prom := cfg.ExternalServices.Prometheus
+	prom.Auth = resolveAuth(CredentialsServicePrometheus, prom.Auth)
 	if customEnabled && cfg.ExternalServices.CustomDashboards.Prometheus.URL != "" {
 		prom = cfg.ExternalServices.CustomDashboards.Prometheus
 	}
@@ -57,10 +62,22 @@ func (in *DashboardsService) prom() (prometheus.ClientInterface, error) {
 			return nil, fmt.Errorf("cannot initialize Prometheus Client: %v", err)
 		}
 		in.promClient = client
+		if in.namespaceScope != nil {
+			in.promClient = namespaceScopedClient{ClientInterface: client, scope: in.namespaceScope}
+		}
 	}
 	return in.promClient, nil
 }
 
+// ScopeToNamespaces restricts the queries of the dashboards to the namespaces accessible through the
+// NamespaceService of the user
+func (in *DashboardsService) ScopeToNamespaces(namespaces *NamespaceService) {
+	in.namespaceScope = newNamespaceScope(namespaces, in.namespaceLabel)
+	if in.promClient != nil {
+		in.promClient = namespaceScopedClient{ClientInterface: in.promClient, scope: in.namespaceScope}
+	}
+}
+
 func (in *DashboardsService) k8s() (monitoringdashboards.ClientInterface, error) {
 	// Lazy init
 	if in.k8sClient == nil {
@@ -184,6 +201,10 @@ func (in *DashboardsService) resolveReferences(namespace string, dashboard *v1al
 
 // GetDashboard returns a dashboard filled-in with target data
 func (in *DashboardsService) GetDashboard(authInfo *api.AuthInfo, params models.DashboardQuery, template string) (*models.MonitoringDashboard, error) {
+	if err := limitMetricsQuery(&params.RangeQuery); err != nil {
+		return nil, err
+	}
+	downsampleMetricsQuery(&params.RangeQuery)
 	promClient, err := in.prom()
 	if err != nil {
 		return nil, err
@@ -280,9 +301,115 @@ func (in *DashboardsService) GetDashboard(authInfo *api.AuthInfo, params models.
 		Charts:        filledCharts,
 		Aggregations:  aggLabels,
 		ExternalLinks: externalLinks,
+		Step:          int64(params.Step / time.Second),
+	}, nil
+}
+
+// GetWorkloadConfigDashboard returns the time series of the workload dashboard defined in the configuration.
+// The workload and namespace are set in the labels matchers of the metrics. Metrics unknown to Prometheus are
+// returned as charts without series, instead of failing, as not every workload exposes them.
+func (in *DashboardsService) GetWorkloadConfigDashboard(params models.DashboardQuery, workload, name string) (*models.MonitoringDashboard, error) {
+	var dashboardConfig *config.WorkloadDashboardConfig
+	for _, d := range config.Get().ExternalServices.CustomDashboards.WorkloadDashboards {
+		if d.Name == name {
+			dashboardConfig = &d
+			break
+		}
+	}
+	if dashboardConfig == nil {
+		return nil, errors.NewNotFound(schema.GroupResource{Group: "kiali.io", Resource: "workloaddashboards"}, name)
+	}
+	if err := limitMetricsQuery(&params.RangeQuery); err != nil {
+		return nil, err
+	}
+	downsampleMetricsQuery(&params.RangeQuery)
+	promClient, err := in.prom()
+	if err != nil {
+		return nil, err
+	}
+
+	wg := sync.WaitGroup{}
+	wg.Add(len(dashboardConfig.Metrics))
+	charts := make([]models.Chart, len(dashboardConfig.Metrics))
+	for i, metricConfig := range dashboardConfig.Metrics {
+		go func(idx int, metricConfig config.WorkloadMetricConfig) {
+			defer wg.Done()
+			title := metricConfig.Title
+			if title == "" {
+				title = metricConfig.MetricName
+			}
+			charts[idx] = models.Chart{Name: title, Unit: metricConfig.Unit, Spans: 12, Metrics: []models.Metric{}}
+
+			metadata, err := promClient.GetMetricMetadata(metricConfig.MetricName)
+			if err != nil {
+				charts[idx].Error = err.Error()
+				return
+			}
+			if len(metadata) == 0 {
+				log.Debugf("Metric [%s] of workload dashboard [%s] not found in Prometheus", metricConfig.MetricName, name)
+				return
+			}
+
+			labels := buildWorkloadMetricLabels(metricConfig.Labels, params.Namespace, workload)
+			grouping := strings.Join(metricConfig.GroupLabels, ",")
+			var metric prometheus.Metric
+			if metricConfig.DataType == v1alpha1.Rate {
+				metric = promClient.FetchRateRange(metricConfig.MetricName, []string{labels}, grouping, &params.RangeQuery)
+			} else {
+				metric = promClient.FetchRange(metricConfig.MetricName, labels, grouping, workloadMetricAggregator(metricConfig.Aggregator), &params.RangeQuery)
+			}
+			converted, err := models.ConvertMetric(title, metric, models.ConversionParams{Scale: 1.0})
+			if err != nil {
+				charts[idx].Error = err.Error()
+			} else {
+				charts[idx].Metrics = append(charts[idx].Metrics, converted...)
+			}
+		}(i, metricConfig)
+	}
+	wg.Wait()
+
+	title := dashboardConfig.Title
+	if title == "" {
+		title = dashboardConfig.Name
+	}
+	return &models.MonitoringDashboard{
+		Title:         title,
+		Charts:        charts,
+		Aggregations:  []models.Aggregation{},
+		ExternalLinks: []models.ExternalLink{},
+		Step:          int64(params.Step / time.Second),
 	}, nil
 }
 
+// buildWorkloadMetricLabels builds the labels matchers of a workload metric, replacing the ${namespace} and ${workload} variables.
+// Values may start with a matching operator, the equality operator is used otherwise.
+func buildWorkloadMetricLabels(labelsConfig map[string]string, namespace, workload string) string {
+	replacer := strings.NewReplacer("${namespace}", namespace, "${workload}", workload)
+	matchers := make([]string, 0, len(labelsConfig))
+	for label, value := range labelsConfig {
+		op := "="
+		for _, matchOp := range []string{"=~", "!=", "!~"} {
+			if strings.HasPrefix(value, matchOp) {
+				op = matchOp
+				value = strings.TrimPrefix(value, matchOp)
+				break
+			}
+		}
+		matchers = append(matchers, fmt.Sprintf(`%s%s"%s"`, prometheus.SanitizeLabelName(label), op, replacer.Replace(value)))
+	}
+	sort.Strings(matchers)
+	return "{" + strings.Join(matchers, ",") + "}"
+}
+
+// workloadMetricAggregator white-lists the aggregation operators to prevent any kind of injection
+func workloadMetricAggregator(aggregator string) string {
+	switch aggregator {
+	case "sum", "min", "max", "avg", "stddev", "stdvar", "count":
+		return aggregator
+	}
+	return "sum"
+}
+
 // SearchExplicitDashboards will check annotations of all supplied pods to extract a unique list of dashboards
 //	Accepted annotations are "kiali.io/runtimes" and "kiali.io/dashboards"
 func (in *DashboardsService) SearchExplicitDashboards(namespace string, pods []models.Pod) []models.Runtime {
@@ -422,9 +549,10 @@ func (in *DashboardsService) buildLabels(namespace string, labelsFilters map[str
 	if namespaceLabel == "" {
 		namespaceLabel = defaultNamespaceLabel
 	}
-	labels := fmt.Sprintf(`{%s="%s"`, namespaceLabel, namespace)
+	// The values are quoted, so that they can't alter the query
+	labels := fmt.Sprintf(`{%s=%q`, namespaceLabel, namespace)
 	for k, v := range labelsFilters {
-		labels += fmt.Sprintf(`,%s="%s"`, prometheus.SanitizeLabelName(k), v)
+		labels += fmt.Sprintf(`,%s=%q`, prometheus.SanitizeLabelName(k), v)
 	}
 	labels += "}"
 	return labels
@@ -4,7 +4,11 @@ import (
 	"errors"
 	"testing"
 
+	prom_v1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	pmodel "github.com/prometheus/common/model"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/tools/clientcmd/api"
 
@@ -12,6 +16,7 @@ import (
 	kmock "github.com/kiali/kiali/kubernetes/monitoringdashboards/mock"
 	"github.com/kiali/kiali/kubernetes/monitoringdashboards/v1alpha1"
 	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/prometheus"
 	pmock "github.com/kiali/kiali/prometheus/prometheustest"
 )
 
@@ -314,3 +319,89 @@ func fakeMetrics() models.MetricsMap {
 		"response_size":           fakeHistogram(22, 22),
 	}
 }
+
+func TestBuildWorkloadMetricLabels(t *testing.T) {
+	assert := assert.New(t)
+
+	labels := buildWorkloadMetricLabels(map[string]string{
+		"kubernetes_namespace": "${namespace}",
+		"kubernetes_pod_name":  "=~${workload}-.*",
+		"queue":                "!~tmp-.*",
+		"job.name":             "!=${namespace}-${workload}",
+	}, "bookinfo", "reviews-v1")
+
+	assert.Equal(`{job_name!="bookinfo-reviews-v1",kubernetes_namespace="bookinfo",kubernetes_pod_name=~"reviews-v1-.*",queue!~"tmp-.*"}`, labels)
+	assert.Equal("{}", buildWorkloadMetricLabels(nil, "bookinfo", "reviews-v1"))
+}
+
+func TestGetWorkloadConfigDashboard(t *testing.T) {
+	assert := assert.New(t)
+
+	service, _, prom := setupService()
+	conf := config.NewConfig()
+	conf.ExternalServices.CustomDashboards.WorkloadDashboards = []config.WorkloadDashboardConfig{{
+		Name:  "queues",
+		Title: "Queues",
+		Metrics: []config.WorkloadMetricConfig{
+			{
+				MetricName:  "myapp_queue_depth",
+				Title:       "Queue depth",
+				Aggregator:  "max",
+				GroupLabels: []string{"queue"},
+				Labels:      map[string]string{"namespace": "${namespace}", "workload": "${workload}"},
+			},
+			{
+				MetricName: "myapp_messages_total",
+				DataType:   v1alpha1.Rate,
+				Labels:     map[string]string{"namespace": "${namespace}", "pod": "=~${workload}-.*"},
+			},
+			{
+				MetricName: "myapp_dead_letters",
+				// Injection attempts fall back to sum
+				Aggregator: "sum(up) or sum",
+				Labels:     map[string]string{"namespace": "${namespace}"},
+			},
+		},
+	}}
+	config.Set(conf)
+
+	query := models.DashboardQuery{Namespace: "bookinfo"}
+	query.FillDefaults()
+	metadata := []prom_v1.Metadata{{Type: "gauge", Help: "Depth of the queue"}}
+	prom.On("GetMetricMetadata", "myapp_queue_depth").Return(metadata, nil)
+	prom.On("GetMetricMetadata", "myapp_messages_total").Return(metadata, nil)
+	prom.On("GetMetricMetadata", "myapp_dead_letters").Return([]prom_v1.Metadata{}, nil)
+	prom.On("FetchRange", "myapp_queue_depth", `{namespace="bookinfo",workload="reviews-v1"}`, "queue", "max", &query.RangeQuery).Return(fakeSeries(5))
+	prom.On("FetchRateRange", "myapp_messages_total", []string{`{namespace="bookinfo",pod=~"reviews-v1-.*"}`}, "", &query.RangeQuery).Return(fakeSeries(2))
+
+	dashboard, err := service.GetWorkloadConfigDashboard(query, "reviews-v1", "queues")
+
+	assert.NoError(err)
+	assert.Equal("Queues", dashboard.Title)
+	assert.Len(dashboard.Charts, 3)
+	assert.Equal("Queue depth", dashboard.Charts[0].Name)
+	assert.Len(dashboard.Charts[0].Metrics, 1)
+	assert.Equal(float64(5), dashboard.Charts[0].Metrics[0].Datapoints[0].Value)
+	assert.Equal("myapp_messages_total", dashboard.Charts[1].Name)
+	assert.Equal(float64(2), dashboard.Charts[1].Metrics[0].Datapoints[0].Value)
+	// The metric doesn't exist: empty but valid chart, not queried
+	assert.Equal("myapp_dead_letters", dashboard.Charts[2].Name)
+	assert.NotNil(dashboard.Charts[2].Metrics)
+	assert.Empty(dashboard.Charts[2].Metrics)
+	assert.Empty(dashboard.Charts[2].Error)
+	prom.AssertNotCalled(t, "FetchRange", "myapp_dead_letters", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+
+	_, err = service.GetWorkloadConfigDashboard(query, "reviews-v1", "unknown")
+	assert.True(k8serrors.IsNotFound(err))
+}
+
+func fakeSeries(value float64) prometheus.Metric {
+	return prometheus.Metric{
+		Matrix: pmodel.Matrix{
+			&pmodel.SampleStream{
+				Metric: pmodel.Metric{},
+				Values: []pmodel.SamplePair{{Timestamp: 0, Value: pmodel.SampleValue(value)}},
+			},
+		},
+	}
+}
@@ -0,0 +1,152 @@
+package business
+
+import (
+	"fmt"
+	"sort"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+
+	"github.com/kiali/kiali/business/checkers"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/prometheus/internalmetrics"
+)
+
+// GetDeleteImpact previews the objects affected by the deletion of an Istio object. The validations of the
+// accessible namespaces are run with and without the object, the objects getting new checks depend on it: for
+// instance the VirtualServices routing to the subsets of a DestinationRule, or bound to a Gateway.
+func (in *IstioValidationsService) GetDeleteImpact(namespace, objectType, object string) (models.DeleteImpact, error) {
+	var err error
+	promtimer := internalmetrics.GetGoFunctionMetric("business", "IstioValidationsService", "GetDeleteImpact")
+	defer promtimer.ObserveNow(&err)
+
+	singular, found := models.ObjectTypeSingular[objectType]
+	if !found {
+		err = errors.NewBadRequest(fmt.Sprintf("Object type not managed: %s", objectType))
+		return models.DeleteImpact{}, err
+	}
+
+	// Check if user has access to the namespace (RBAC) in cache scenarios and/or
+	// if namespace is accessible from Kiali (Deployment.AccessibleNamespaces)
+	if _, err = in.businessLayer.Namespace.GetNamespace(namespace); err != nil {
+		return models.DeleteImpact{}, err
+	}
+	if _, err = in.k8s.GetIstioObject(namespace, objectType, object); err != nil {
+		return models.DeleteImpact{}, err
+	}
+
+	// Gateways, ServiceEntries and exported objects have dependents in other namespaces
+	namespaces, err := in.businessLayer.Namespace.GetNamespaces()
+	if err != nil {
+		return models.DeleteImpact{}, err
+	}
+
+	deleted := models.BuildKey(singular, object, namespace)
+	impact := models.DeleteImpact{Object: deleted, Dependents: []models.DeleteImpactDependent{}}
+	for _, ns := range namespaces {
+		var before, after models.IstioValidations
+		if before, err = in.runValidations(ns.Name, "", nil, nil); err != nil {
+			return models.DeleteImpact{}, err
+		}
+		if after, err = in.runValidations(ns.Name, "", &deleted, nil); err != nil {
+			return models.DeleteImpact{}, err
+		}
+		impact.Dependents = append(impact.Dependents, newChecks(ns.Name, deleted, before, after)...)
+	}
+
+	checks := []*models.IstioCheck{}
+	for _, dependent := range impact.Dependents {
+		checks = append(checks, dependent.Checks...)
+	}
+	impact.Severity = models.MaxSeverity(checks)
+	sort.SliceStable(impact.Dependents, func(i, j int) bool {
+		di, dj := impact.Dependents[i], impact.Dependents[j]
+		if di.Severity != dj.Severity {
+			return di.Severity.AtLeast(dj.Severity)
+		}
+		if di.Namespace != dj.Namespace {
+			return di.Namespace < dj.Namespace
+		}
+		if di.ObjectType != dj.ObjectType {
+			return di.ObjectType < dj.ObjectType
+		}
+		return di.Name < dj.Name
+	})
+	return impact, nil
+}
+
+// newChecks returns the objects of the namespace having checks after the deletion that they didn't have before.
+// The objects of other namespaces, validated as references, are compared when their own namespace is.
+func newChecks(namespace string, deleted models.IstioValidationKey, before, after models.IstioValidations) []models.DeleteImpactDependent {
+	dependents := []models.DeleteImpactDependent{}
+	for key, validation := range after {
+		if key.Namespace != namespace || key == deleted {
+			continue
+		}
+		previous := map[models.IstioCheck]bool{}
+		if v, found := before[key]; found {
+			for _, check := range v.Checks {
+				previous[*check] = true
+			}
+		}
+		checks := []*models.IstioCheck{}
+		for _, check := range validation.Checks {
+			if !previous[*check] {
+				checks = append(checks, check)
+			}
+		}
+		if len(checks) > 0 {
+			dependents = append(dependents, models.DeleteImpactDependent{IstioValidationKey: key, Severity: models.MaxSeverity(checks), Checks: checks})
+		}
+	}
+	return dependents
+}
+
+// removeDeletedObject removes the deleted object from the Istio objects fetched for the validations, returning the
+// Gateways of every namespace without it. The fetched slices may be cached, they are copied.
+func removeDeletedObject(deleted models.IstioValidationKey, istioDetails *kubernetes.IstioDetails, mtlsDetails *kubernetes.MTLSDetails, rbacDetails *kubernetes.RBACDetails, gatewaysPerNamespace [][]kubernetes.IstioObject, allServiceEntries *[]kubernetes.IstioObject, allSidecars *[]kubernetes.IstioObject) [][]kubernetes.IstioObject {
+	without := func(objects []kubernetes.IstioObject) []kubernetes.IstioObject {
+		kept := make([]kubernetes.IstioObject, 0, len(objects))
+		for _, o := range objects {
+			if o.GetObjectMeta().Name != deleted.Name || o.GetObjectMeta().Namespace != deleted.Namespace {
+				kept = append(kept, o)
+			}
+		}
+		return kept
+	}
+
+	switch deleted.ObjectType {
+	case checkers.VirtualCheckerType:
+		istioDetails.VirtualServices = without(istioDetails.VirtualServices)
+	case checkers.DestinationRuleCheckerType:
+		istioDetails.DestinationRules = without(istioDetails.DestinationRules)
+		mtlsDetails.DestinationRules = without(mtlsDetails.DestinationRules)
+	case checkers.ServiceEntryCheckerType:
+		istioDetails.ServiceEntries = without(istioDetails.ServiceEntries)
+		*allServiceEntries = without(*allServiceEntries)
+	case checkers.GatewayCheckerType:
+		istioDetails.Gateways = without(istioDetails.Gateways)
+		gateways := make([][]kubernetes.IstioObject, 0, len(gatewaysPerNamespace))
+		for _, gws := range gatewaysPerNamespace {
+			gateways = append(gateways, without(gws))
+		}
+		return gateways
+	case checkers.SidecarCheckerType:
+		istioDetails.Sidecars = without(istioDetails.Sidecars)
+		*allSidecars = without(*allSidecars)
+	case checkers.RequestAuthenticationCheckerType:
+		istioDetails.RequestAuthentications = without(istioDetails.RequestAuthentications)
+	case checkers.PeerAuthenticationCheckerType:
+		mtlsDetails.PeerAuthentications = without(mtlsDetails.PeerAuthentications)
+		mtlsDetails.MeshPeerAuthentications = without(mtlsDetails.MeshPeerAuthentications)
+	case checkers.AuthorizationPolicyCheckerType:
+		rbacDetails.AuthorizationPolicies = without(rbacDetails.AuthorizationPolicies)
+		rbacDetails.MeshAuthorizationPolicies = without(rbacDetails.MeshAuthorizationPolicies)
+	case checkers.ProxyConfigCheckerType:
+		istioDetails.ProxyConfigs = without(istioDetails.ProxyConfigs)
+	case checkers.EnvoyFilterCheckerType:
+		istioDetails.EnvoyFilters = without(istioDetails.EnvoyFilters)
+		istioDetails.MeshEnvoyFilters = without(istioDetails.MeshEnvoyFilters)
+	}
+	return gatewaysPerNamespace
+}
@@ -0,0 +1,123 @@
+package business
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	core_v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/kubernetes/kubetest"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/tests/data"
+)
+
+func TestDeleteImpactDestinationRuleSubsets(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	vs := mockDeleteImpactValidationService(t)
+	impact, err := vs.GetDeleteImpact("bookinfo", "destinationrules", "reviews")
+	assert.NoError(err)
+
+	assert.Equal(models.BuildKey("destinationrule", "reviews", "bookinfo"), impact.Object)
+	assert.Equal(models.WarningSeverity, impact.Severity)
+	assert.Len(impact.Dependents, 1)
+	dependent := impact.Dependents[0]
+	assert.Equal(models.BuildKey("virtualservice", "reviews", "bookinfo"), dependent.IstioValidationKey)
+	assert.Equal(models.WarningSeverity, dependent.Severity)
+	// Both subsets are routed to
+	assert.Len(dependent.Checks, 2)
+	for _, check := range dependent.Checks {
+		assert.Contains(check.Message, models.CheckMessage("virtualservices.subsetpresent.subsetnotfound"))
+	}
+}
+
+func TestDeleteImpactGatewayOfOtherNamespace(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	vs := mockDeleteImpactValidationService(t)
+	impact, err := vs.GetDeleteImpact("istio-system", "gateways", "bookinfo-gateway")
+	assert.NoError(err)
+
+	assert.Equal(models.ErrorSeverity, impact.Severity)
+	assert.Len(impact.Dependents, 1)
+	dependent := impact.Dependents[0]
+	assert.Equal(models.BuildKey("virtualservice", "bookinfo", "bookinfo"), dependent.IstioValidationKey)
+	assert.Equal(models.ErrorSeverity, dependent.Severity)
+	assert.Len(dependent.Checks, 1)
+	assert.Contains(dependent.Checks[0].Message, models.CheckMessage("virtualservices.nogateway"))
+}
+
+func TestDeleteImpactWithoutDependents(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	vs := mockDeleteImpactValidationService(t)
+	impact, err := vs.GetDeleteImpact("bookinfo", "destinationrules", "productpage")
+	assert.NoError(err)
+	assert.Empty(impact.Severity)
+	assert.Empty(impact.Dependents)
+}
+
+func TestDeleteImpactErrors(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+	vs := mockDeleteImpactValidationService(t)
+
+	_, err := vs.GetDeleteImpact("bookinfo", "deployments", "reviews-v1")
+	assert.True(errors.IsBadRequest(err))
+
+	_, err = vs.GetDeleteImpact("bookinfo", "destinationrules", "ratings")
+	assert.True(errors.IsNotFound(err))
+}
+
+func mockDeleteImpactValidationService(t *testing.T) IstioValidationsService {
+	loader := &data.YamlFixtureLoader{Filename: "../tests/data/validations/delete_impact/bookinfo.yaml"}
+	if err := loader.Load(); err != nil {
+		t.Fatalf("Error loading test data: %v", err)
+	}
+
+	k8s := new(kubetest.K8SClientMock)
+	k8s.On("IsOpenShift").Return(false)
+	k8s.On("IsMaistraApi").Return(false)
+	k8s.On("GetNamespace", mock.AnythingOfType("string")).Return(&core_v1.Namespace{}, nil)
+	k8s.On("GetNamespaces", mock.AnythingOfType("string")).Return([]core_v1.Namespace{
+		{ObjectMeta: meta_v1.ObjectMeta{Name: "bookinfo"}},
+		{ObjectMeta: meta_v1.ObjectMeta{Name: "istio-system"}},
+	}, nil)
+
+	kinds := map[string]string{
+		kubernetes.Gateways:         "Gateway",
+		kubernetes.VirtualServices:  "VirtualService",
+		kubernetes.DestinationRules: "DestinationRule",
+	}
+	for _, namespace := range []string{"bookinfo", "istio-system"} {
+		for resourceType, kind := range kinds {
+			objects := []kubernetes.IstioObject{}
+			for _, o := range loader.GetResources(kind) {
+				if o.GetObjectMeta().Namespace == namespace {
+					objects = append(objects, o)
+					k8s.On("GetIstioObject", namespace, resourceType, o.GetObjectMeta().Name).Return(o, nil)
+				}
+			}
+			k8s.On("GetIstioObjects", namespace, resourceType, "").Return(objects, nil)
+		}
+	}
+	k8s.On("GetIstioObject", mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string")).
+		Return((*kubernetes.GenericIstioObject)(nil), errors.NewNotFound(schema.GroupResource{Resource: "destinationrules"}, "ratings"))
+	for _, resourceType := range []string{"serviceentries", "sidecars", "requestauthentications", "proxyconfigs", "envoyfilters", "peerauthentications", "authorizationpolicies", "telemetries"} {
+		k8s.On("GetIstioObjects", mock.AnythingOfType("string"), resourceType, "").Return([]kubernetes.IstioObject{}, nil)
+	}
+	k8s.On("GetServices", mock.AnythingOfType("string"), mock.AnythingOfType("map[string]string")).Return(fakeCombinedServices([]string{"productpage", "reviews"}), nil)
+	k8s.On("GetSecrets", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return([]core_v1.Secret{}, nil)
+	mockWorkLoadService(k8s)
+
+	return IstioValidationsService{k8s: k8s, businessLayer: NewWithBackends(k8s, nil, nil)}
+}
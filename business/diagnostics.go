@@ -0,0 +1,169 @@
+package business
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/log"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/util/httputil"
+)
+
+const diagnosticsTimeout = 10 * time.Second
+
+// DiagnoseExternalServices tests the connection to Prometheus and Jaeger
+func (iss *IstioStatusService) DiagnoseExternalServices() []*models.ExternalServiceDiagnostics {
+	diagnostics := make([]*models.ExternalServiceDiagnostics, 2)
+
+	wg := sync.WaitGroup{}
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		diagnostics[0] = iss.DiagnosePrometheus()
+	}()
+	go func() {
+		defer wg.Done()
+		diagnostics[1] = iss.DiagnoseJaeger()
+	}()
+	wg.Wait()
+
+	return diagnostics
+}
+
+// DiagnosePrometheus runs a trivial query against the configured Prometheus and fetches its version
+func (iss *IstioStatusService) DiagnosePrometheus() *models.ExternalServiceDiagnostics {
+	promConfig := config.Get().ExternalServices.Prometheus
+	baseURL := strings.TrimSuffix(promConfig.URL, "/")
+
+	auth := diagnosticsAuth(resolveAuth(CredentialsServicePrometheus, promConfig.Auth))
+	diagnostics, body := diagnose("prometheus", baseURL, baseURL+"/api/v1/query?query=vector(1)", auth)
+	if body == nil {
+		return diagnostics
+	}
+
+	response := struct {
+		Status string `json:"status"`
+		Data   struct {
+			ResultType string            `json:"resultType"`
+			Result     []json.RawMessage `json:"result"`
+		} `json:"data"`
+	}{}
+	if err := json.Unmarshal(body, &response); err != nil || response.Status != "success" {
+		diagnostics.Error = "unexpected response to the test query, the URL may not point to the Prometheus API"
+		return diagnostics
+	}
+	diagnostics.SampleResult = fmt.Sprintf("query vector(1) returned %d %s result(s)", len(response.Data.Result), response.Data.ResultType)
+
+	// The build info endpoint is only available since Prometheus 2.14
+	if versionBody, code, err := httputil.HttpGet(baseURL+"/api/v1/status/buildinfo", auth, diagnosticsTimeout); err == nil && code == http.StatusOK {
+		buildInfo := struct {
+			Data struct {
+				Version string `json:"version"`
+			} `json:"data"`
+		}{}
+		if err := json.Unmarshal(versionBody, &buildInfo); err == nil {
+			diagnostics.Version = buildInfo.Data.Version
+		}
+	}
+	return diagnostics
+}
+
+// DiagnoseJaeger lists the services known by the configured Jaeger, through its HTTP query API
+func (iss *IstioStatusService) DiagnoseJaeger() *models.ExternalServiceDiagnostics {
+	tracingConfig := config.Get().ExternalServices.Tracing
+	baseURL := strings.TrimSuffix(tracingConfig.InClusterURL, "/")
+
+	if !tracingConfig.Enabled {
+		return &models.ExternalServiceDiagnostics{
+			Name:  "jaeger",
+			URL:   maskURL(baseURL),
+			Error: "tracing is disabled",
+		}
+	}
+
+	diagnostics, body := diagnose("jaeger", baseURL, baseURL+"/api/services", diagnosticsAuth(resolveAuth(CredentialsServiceTracing, tracingConfig.Auth)))
+	if body == nil {
+		return diagnostics
+	}
+
+	response := struct {
+		Data []string `json:"data"`
+	}{}
+	if err := json.Unmarshal(body, &response); err != nil {
+		diagnostics.Error = "unexpected response to the test query, the URL may not point to the Jaeger query API"
+		return diagnostics
+	}
+	diagnostics.SampleResult = fmt.Sprintf("%d service(s) with traces", len(response.Data))
+	return diagnostics
+}
+
+// diagnosticsAuth returns a copy of the auth, with the Kiali token when it's used
+func diagnosticsAuth(auth config.Auth) *config.Auth {
+	if auth.UseKialiToken {
+		token, err := kubernetes.GetKialiToken()
+		if err != nil {
+			log.Errorf("Could not read the Kiali Service Account token: %v", err)
+		}
+		auth.Token = token
+	}
+	return &auth
+}
+
+// diagnose requests the test URL and fills the connection diagnostics. The body is returned only on success,
+// for the caller to check the result.
+func diagnose(name, baseURL, testURL string, auth *config.Auth) (*models.ExternalServiceDiagnostics, []byte) {
+	diagnostics := &models.ExternalServiceDiagnostics{
+		Name:     name,
+		URL:      maskURL(baseURL),
+		AuthType: auth.Type,
+	}
+	if baseURL == "" {
+		diagnostics.Error = "no URL configured"
+		return diagnostics, nil
+	}
+
+	start := time.Now()
+	body, code, err := httputil.HttpGet(testURL, auth, diagnosticsTimeout)
+	diagnostics.LatencyMs = time.Since(start).Milliseconds()
+	if err != nil {
+		diagnostics.Error = maskCredentials(err.Error(), auth)
+		return diagnostics, nil
+	}
+
+	diagnostics.Reachable = true
+	diagnostics.StatusCode = code
+	authenticated := code != http.StatusUnauthorized && code != http.StatusForbidden
+	diagnostics.Authenticated = &authenticated
+	if code != http.StatusOK {
+		diagnostics.Error = fmt.Sprintf("test query failed with status code %d", code)
+		return diagnostics, nil
+	}
+	return diagnostics, body
+}
+
+// maskURL hides the user info of the URL, the URL is returned as is when it can't be parsed
+func maskURL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.User == nil {
+		return rawURL
+	}
+	u.User = url.User("xxx")
+	return u.String()
+}
+
+// maskCredentials hides the credentials that errors (i.e. of the url package) could include
+func maskCredentials(message string, auth *config.Auth) string {
+	for _, secret := range []string{auth.Token, auth.Password} {
+		if secret != "" {
+			message = strings.ReplaceAll(message, secret, "xxx")
+		}
+	}
+	return message
+}
@@ -0,0 +1,156 @@
+package business
+
+import (
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/prometheus/common/model"
+
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/prometheus/internalmetrics"
+)
+
+// topEdgeErrorRoutes is the number of routes returned in the errors of an edge
+const topEdgeErrorRoutes = 5
+
+// GetEdgeErrors decomposes the errors of the requests of an edge of the graph by response code, by the response flags
+// set by the proxies, and by route, to explain why the edge is in error.
+func (in *MetricsService) GetEdgeErrors(q models.EdgeErrorsQuery) (*models.EdgeErrors, error) {
+	var err error
+	promtimer := internalmetrics.GetGoFunctionMetric("business", "MetricsService", "GetEdgeErrors")
+	defer promtimer.ObserveNow(&err)
+
+	lb := NewMetricsLabelsBuilder("outbound")
+	lb.Reporter(q.Reporter)
+	if q.SourceWorkload != "" {
+		lb.Workload(q.SourceWorkload, q.SourceNamespace)
+	} else if q.SourceApp != "" {
+		lb.App(q.SourceApp, q.SourceNamespace)
+	} else {
+		lb.Namespace(q.SourceNamespace)
+	}
+	if q.DestinationService != "" {
+		lb.PeerService(q.DestinationService, q.DestinationNamespace)
+	}
+	if q.DestinationWorkload != "" {
+		lb.PeerWorkload(q.DestinationWorkload, q.DestinationNamespace)
+	} else if q.DestinationApp != "" {
+		lb.PeerApp(q.DestinationApp, q.DestinationNamespace)
+	}
+
+	grouping := telemetryGrouping("response_code,grpc_response_status,response_flags,destination_service_name,destination_workload")
+	var rates model.Vector
+	rates, err = in.prom.FetchRateValues(telemetryMetric("istio_requests_total"), lb.Build(), grouping, q.RateInterval, q.QueryTime)
+	if err != nil {
+		return nil, err
+	}
+	return buildEdgeErrors(rates), nil
+}
+
+func buildEdgeErrors(rates model.Vector) *models.EdgeErrors {
+	edgeErrors := &models.EdgeErrors{
+		ResponseCodes: []models.ResponseCodeRate{},
+		ResponseFlags: []models.ResponseFlagRate{},
+		Routes:        []models.RouteErrorRate{},
+	}
+
+	lblCode := model.LabelName(telemetryLabel("response_code"))
+	lblGrpcStatus := model.LabelName(telemetryLabel("grpc_response_status"))
+	lblFlags := model.LabelName(telemetryLabel("response_flags"))
+	lblService := model.LabelName(telemetryLabel("destination_service_name"))
+	lblWorkload := model.LabelName(telemetryLabel("destination_workload"))
+
+	codes := map[string]*models.ResponseCodeRate{}
+	flags := map[string]*models.ResponseFlagRate{}
+	routes := map[string]*models.RouteErrorRate{}
+	for _, sample := range rates {
+		value := float64(sample.Value)
+		if math.IsNaN(value) || value == 0 {
+			continue
+		}
+		code := string(sample.Metric[lblCode])
+		grpcStatus := string(sample.Metric[lblGrpcStatus])
+		isError := isErrorResponse(code, grpcStatus)
+
+		edgeErrors.RequestRate += value
+		if isError {
+			edgeErrors.ErrorRate += value
+		}
+
+		// A gRPC request failing with a successful HTTP status is counted by grpc status
+		if !isHTTPErrorCode(code) && grpcStatus != "" && grpcStatus != "0" {
+			code = "grpc-" + grpcStatus
+		}
+		if _, found := codes[code]; !found {
+			codes[code] = &models.ResponseCodeRate{Code: code, Error: isError}
+		}
+		codes[code].Rate += value
+
+		// Several flags are reported comma separated, "-" stands for no flag
+		for _, flag := range strings.Split(string(sample.Metric[lblFlags]), ",") {
+			if flag == "" || flag == "-" {
+				continue
+			}
+			if _, found := flags[flag]; !found {
+				flags[flag] = &models.ResponseFlagRate{Flag: flag, Meaning: models.ResponseFlagMeaning(flag)}
+			}
+			flags[flag].Rate += value
+		}
+
+		service := string(sample.Metric[lblService])
+		workload := string(sample.Metric[lblWorkload])
+		key := service + "/" + workload
+		if _, found := routes[key]; !found {
+			routes[key] = &models.RouteErrorRate{DestinationService: service, DestinationWorkload: workload}
+		}
+		routes[key].RequestRate += value
+		if isError {
+			routes[key].ErrorRate += value
+		}
+	}
+
+	for _, c := range codes {
+		edgeErrors.ResponseCodes = append(edgeErrors.ResponseCodes, *c)
+	}
+	sort.Slice(edgeErrors.ResponseCodes, func(i, j int) bool {
+		ci, cj := edgeErrors.ResponseCodes[i], edgeErrors.ResponseCodes[j]
+		return ci.Rate > cj.Rate || (ci.Rate == cj.Rate && ci.Code < cj.Code)
+	})
+	for _, f := range flags {
+		edgeErrors.ResponseFlags = append(edgeErrors.ResponseFlags, *f)
+	}
+	sort.Slice(edgeErrors.ResponseFlags, func(i, j int) bool {
+		fi, fj := edgeErrors.ResponseFlags[i], edgeErrors.ResponseFlags[j]
+		return fi.Rate > fj.Rate || (fi.Rate == fj.Rate && fi.Flag < fj.Flag)
+	})
+	for _, r := range routes {
+		if r.ErrorRate > 0 {
+			edgeErrors.Routes = append(edgeErrors.Routes, *r)
+		}
+	}
+	sort.Slice(edgeErrors.Routes, func(i, j int) bool {
+		ri, rj := edgeErrors.Routes[i], edgeErrors.Routes[j]
+		if ri.ErrorRate != rj.ErrorRate {
+			return ri.ErrorRate > rj.ErrorRate
+		}
+		return ri.DestinationService+"/"+ri.DestinationWorkload < rj.DestinationService+"/"+rj.DestinationWorkload
+	})
+	if len(edgeErrors.Routes) > topEdgeErrorRoutes {
+		edgeErrors.Routes = edgeErrors.Routes[:topEdgeErrorRoutes]
+	}
+	return edgeErrors
+}
+
+// isErrorResponse follows the definition of the errors of the metrics: no response (response code 0), 4xx and 5xx
+// response codes, and grpc errors with a successful HTTP status
+func isErrorResponse(code, grpcStatus string) bool {
+	if code == "0" || isHTTPErrorCode(code) {
+		return true
+	}
+	return grpcStatus != "" && grpcStatus != "0"
+}
+
+func isHTTPErrorCode(code string) bool {
+	return len(code) == 3 && (strings.HasPrefix(code, "4") || strings.HasPrefix(code, "5"))
+}
@@ -0,0 +1,145 @@
+package business
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/prometheus/prometheustest"
+)
+
+func TestGetEdgeErrors(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	queryTime := time.Date(2017, 01, 15, 0, 0, 0, 0, time.UTC)
+	prom := new(prometheustest.PromClientMock)
+	prom.On("FetchRateValues",
+		"istio_requests_total",
+		`{reporter="source",source_workload_namespace="bookinfo",source_workload="productpage-v1",destination_service_name="reviews",destination_service_namespace="bookinfo"}`,
+		"response_code,grpc_response_status,response_flags,destination_service_name,destination_workload",
+		"5m",
+		queryTime,
+	).Return(fakeEdgeRates(), nil)
+
+	edgeErrors, err := NewMetricsService(prom).GetEdgeErrors(models.EdgeErrorsQuery{
+		SourceNamespace:      "bookinfo",
+		SourceWorkload:       "productpage-v1",
+		DestinationNamespace: "bookinfo",
+		DestinationService:   "reviews",
+		Reporter:             "source",
+		RateInterval:         "5m",
+		QueryTime:            queryTime,
+	})
+
+	assert.NoError(err)
+	assert.InDelta(10.0, edgeErrors.RequestRate, 0.001)
+	assert.InDelta(4.0, edgeErrors.ErrorRate, 0.001)
+	prom.AssertExpectations(t)
+}
+
+func TestEdgeErrorsDecomposition(t *testing.T) {
+	assert := assert.New(t)
+
+	edgeErrors := buildEdgeErrors(fakeEdgeRates())
+
+	assert.InDelta(10.0, edgeErrors.RequestRate, 0.001)
+	assert.InDelta(4.0, edgeErrors.ErrorRate, 0.001)
+
+	// Sorted by decreasing rate
+	assert.Len(edgeErrors.ResponseCodes, 4)
+	assert.Equal(models.ResponseCodeRate{Code: "200", Rate: 6, Error: false}, edgeErrors.ResponseCodes[0])
+	assert.Equal(models.ResponseCodeRate{Code: "503", Rate: 2.5, Error: true}, edgeErrors.ResponseCodes[1])
+	assert.Equal(models.ResponseCodeRate{Code: "0", Rate: 1, Error: true}, edgeErrors.ResponseCodes[2])
+	assert.Equal(models.ResponseCodeRate{Code: "grpc-14", Rate: 0.5, Error: true}, edgeErrors.ResponseCodes[3])
+
+	// The flags reported together are counted separately, no flag is not reported
+	assert.Len(edgeErrors.ResponseFlags, 3)
+	assert.Equal("UF", edgeErrors.ResponseFlags[0].Flag)
+	assert.Equal("Upstream connection failure", edgeErrors.ResponseFlags[0].Meaning)
+	assert.InDelta(2.0, edgeErrors.ResponseFlags[0].Rate, 0.001)
+	assert.Equal("URX", edgeErrors.ResponseFlags[1].Flag)
+	assert.Equal("Upstream retry limit or max connect attempts reached", edgeErrors.ResponseFlags[1].Meaning)
+	assert.InDelta(1.5, edgeErrors.ResponseFlags[1].Rate, 0.001)
+	assert.Equal("NR", edgeErrors.ResponseFlags[2].Flag)
+	assert.Equal("No route configured for the request", edgeErrors.ResponseFlags[2].Meaning)
+
+	// Only the routes with errors, by decreasing error rate
+	assert.Len(edgeErrors.Routes, 2)
+	assert.Equal("reviews-v3", edgeErrors.Routes[0].DestinationWorkload)
+	assert.InDelta(3.0, edgeErrors.Routes[0].ErrorRate, 0.001)
+	assert.InDelta(3.0, edgeErrors.Routes[0].RequestRate, 0.001)
+	assert.Equal("unknown", edgeErrors.Routes[1].DestinationWorkload)
+	assert.InDelta(1.0, edgeErrors.Routes[1].ErrorRate, 0.001)
+}
+
+func TestEdgeErrorsWithoutTraffic(t *testing.T) {
+	assert := assert.New(t)
+
+	edgeErrors := buildEdgeErrors(model.Vector{})
+
+	assert.Zero(edgeErrors.RequestRate)
+	assert.Zero(edgeErrors.ErrorRate)
+	assert.Empty(edgeErrors.ResponseCodes)
+	assert.Empty(edgeErrors.ResponseFlags)
+	assert.Empty(edgeErrors.Routes)
+}
+
+func TestResponseFlagMeaning(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal("Upstream overflow (circuit breaking)", models.ResponseFlagMeaning("UO"))
+	assert.Equal("Unknown response flag", models.ResponseFlagMeaning("XYZ"))
+}
+
+func fakeEdgeRates() model.Vector {
+	sample := func(code, grpcStatus, flags, workload string, value float64) *model.Sample {
+		metric := model.Metric{
+			"response_code":            model.LabelValue(code),
+			"response_flags":           model.LabelValue(flags),
+			"destination_service_name": "reviews",
+			"destination_workload":     model.LabelValue(workload),
+		}
+		if grpcStatus != "" {
+			metric["grpc_response_status"] = model.LabelValue(grpcStatus)
+		}
+		return &model.Sample{Metric: metric, Value: model.SampleValue(value)}
+	}
+	return model.Vector{
+		sample("200", "", "-", "reviews-v1", 3),
+		sample("200", "", "-", "reviews-v2", 1.5),
+		sample("200", "0", "-", "reviews-v2", 1.5),
+		sample("503", "", "UF,URX", "reviews-v3", 1.5),
+		sample("503", "", "UF", "reviews-v3", 0.5),
+		sample("200", "14", "-", "reviews-v3", 0.5),
+		sample("503", "", "-", "reviews-v3", 0.5),
+		sample("0", "", "NR", "unknown", 1),
+	}
+}
+
+func TestEdgeErrorsWithTelemetryMapping(t *testing.T) {
+	assert := assert.New(t)
+	conf := config.NewConfig()
+	conf.ExternalServices.Istio.TelemetryMapping = config.TelemetryMapping{
+		Labels: map[string]string{
+			"response_code":        "http_status",
+			"destination_workload": "dst_wl",
+		},
+	}
+	config.Set(conf)
+	defer config.Set(config.NewConfig())
+
+	edgeErrors := buildEdgeErrors(model.Vector{
+		&model.Sample{Metric: model.Metric{"http_status": "200", "dst_wl": "reviews-v1"}, Value: 3},
+		&model.Sample{Metric: model.Metric{"http_status": "503", "dst_wl": "reviews-v2"}, Value: 1},
+	})
+
+	assert.InDelta(4.0, edgeErrors.RequestRate, 0.001)
+	assert.InDelta(1.0, edgeErrors.ErrorRate, 0.001)
+	assert.Len(edgeErrors.Routes, 1)
+	assert.Equal("reviews-v2", edgeErrors.Routes[0].DestinationWorkload)
+}
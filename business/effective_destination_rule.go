@@ -0,0 +1,238 @@
+package business
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/prometheus/internalmetrics"
+)
+
+// Precedence of the namespace of a DestinationRule for the proxies of the namespace of a service
+const (
+	drServiceNamespace = iota
+	drRootNamespace
+)
+
+// candidateDestinationRule is a DestinationRule matching the host of a service
+type candidateDestinationRule struct {
+	dr          kubernetes.IstioObject
+	host        string
+	fqdn        string
+	precedence  int
+	specificity int
+}
+
+// GetEffectiveDestinationRule resolves the DestinationRule in effect for the host of a service, for the proxies of
+// the namespace of the service, the way Istio does:
+// - The DestinationRules of the namespace win over the ones of the root namespace. The DestinationRules of the other
+// namespaces only apply to the proxies of their own namespace.
+// - Within a namespace, the most specific host wins: the host of the service, then the longest wildcard.
+// - The DestinationRules with the same host in the same namespace are merged, from the oldest one: the first traffic
+// policy and the first subset of a name win.
+// The other DestinationRules matching the host are reported as shadowed.
+func (in *SvcService) GetEffectiveDestinationRule(namespace, service string) (*models.EffectiveDestinationRule, error) {
+	var err error
+	promtimer := internalmetrics.GetGoFunctionMetric("business", "SvcService", "GetEffectiveDestinationRule")
+	defer promtimer.ObserveNow(&err)
+
+	// Check if user has access to the namespace (RBAC) in cache scenarios and/or
+	// if namespace is accessible from Kiali (Deployment.AccessibleNamespaces)
+	if _, err = in.businessLayer.Namespace.GetNamespace(namespace); err != nil {
+		return nil, err
+	}
+	if _, err = in.getService(namespace, service); err != nil {
+		return nil, err
+	}
+
+	nsNames, rootNamespace, err := in.businessLayer.TLS.getNamespaces(namespace)
+	if err != nil {
+		return nil, err
+	}
+	drs, err := in.businessLayer.TLS.getAllDestinationRules(nsNames)
+	if err != nil {
+		return nil, err
+	}
+
+	fqdn := fmt.Sprintf("%s.%s.%s", service, namespace, config.Get().ExternalServices.Istio.IstioIdentityDomain)
+	effective := &models.EffectiveDestinationRule{
+		Namespace: namespace,
+		Service:   service,
+		Host:      fqdn,
+		Merged:    []models.DestinationRuleResolution{},
+		Subsets:   []interface{}{},
+		Shadowed:  []models.DestinationRuleResolution{},
+	}
+
+	candidates := make([]candidateDestinationRule, 0)
+	for _, dr := range drs {
+		candidate, matched := matchDestinationRule(dr, fqdn, nsNames)
+		if !matched {
+			continue
+		}
+		drNamespace := dr.GetObjectMeta().Namespace
+		switch drNamespace {
+		case namespace:
+			candidate.precedence = drServiceNamespace
+		case rootNamespace:
+			candidate.precedence = drRootNamespace
+		default:
+			effective.Shadowed = append(effective.Shadowed, drResolution(candidate,
+				fmt.Sprintf("Only applies to the proxies of its namespace [%s]", drNamespace)))
+			continue
+		}
+		if !exportedToNamespace(dr, namespace) {
+			effective.Shadowed = append(effective.Shadowed, drResolution(candidate,
+				fmt.Sprintf("Not exported to the namespace [%s]", namespace)))
+			continue
+		}
+		candidates = append(candidates, candidate)
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		ci, cj := candidates[i], candidates[j]
+		if ci.precedence != cj.precedence {
+			return ci.precedence < cj.precedence
+		}
+		if ci.specificity != cj.specificity {
+			return ci.specificity > cj.specificity
+		}
+		return olderObject(ci.dr, cj.dr)
+	})
+
+	if len(candidates) > 0 {
+		winner := candidates[0]
+		winnerRef := objectRef(winner.dr)
+		drModel := models.DestinationRule{}
+		drModel.Parse(winner.dr)
+		effective.DestinationRule = &drModel
+
+		subsetOwners := map[string]string{}
+		var policyOwner string
+		for _, candidate := range candidates {
+			if candidate.precedence != winner.precedence || candidate.fqdn != winner.fqdn {
+				reason := fmt.Sprintf("Shadowed by the more specific host [%s] of [%s]", winner.host, winnerRef)
+				if candidate.precedence != winner.precedence {
+					reason = fmt.Sprintf("Shadowed by [%s], the DestinationRules of the namespace [%s] take precedence", winnerRef, winner.dr.GetObjectMeta().Namespace)
+				}
+				effective.Shadowed = append(effective.Shadowed, drResolution(candidate, reason))
+				continue
+			}
+
+			// Same host in the same namespace: merged into the winner
+			reasons := []string{}
+			if policy, found := candidate.dr.GetSpec()["trafficPolicy"]; found && policy != nil {
+				if policyOwner == "" {
+					policyOwner = objectRef(candidate.dr)
+					effective.TrafficPolicy = policy
+				} else {
+					reasons = append(reasons, fmt.Sprintf("Its traffic policy is overridden by the one of [%s]", policyOwner))
+				}
+			}
+			for _, subset := range destinationRuleSubsets(candidate.dr) {
+				name, _ := subset["name"].(string)
+				if owner, found := subsetOwners[name]; found {
+					reasons = append(reasons, fmt.Sprintf("Its subset [%s] is overridden by the one of [%s]", name, owner))
+					continue
+				}
+				subsetOwners[name] = objectRef(candidate.dr)
+				effective.Subsets = append(effective.Subsets, subset)
+			}
+			if candidate.dr != winner.dr {
+				effective.Merged = append(effective.Merged, drResolution(candidate, reasons...))
+			}
+		}
+	}
+
+	sort.SliceStable(effective.Shadowed, func(i, j int) bool {
+		si, sj := effective.Shadowed[i], effective.Shadowed[j]
+		return si.Namespace+"/"+si.Name < sj.Namespace+"/"+sj.Name
+	})
+	return effective, nil
+}
+
+// matchDestinationRule returns the DestinationRule as a candidate when its host matches the FQDN of a service, with
+// the specificity of the match: the highest for the host of the service, otherwise the length of the wildcard
+func matchDestinationRule(dr kubernetes.IstioObject, fqdn string, namespaces []string) (candidateDestinationRule, bool) {
+	host, ok := dr.GetSpec()["host"].(string)
+	if !ok || host == "" {
+		return candidateDestinationRule{}, false
+	}
+	candidate := candidateDestinationRule{dr: dr, host: host}
+
+	domain := config.Get().ExternalServices.Istio.IstioIdentityDomain
+	parsed := kubernetes.GetHost(host, dr.GetObjectMeta().Namespace, domain, namespaces)
+	switch {
+	case parsed.CompleteInput:
+		candidate.fqdn = parsed.String()
+	case strings.HasSuffix(host, ".svc"):
+		candidate.fqdn = host + "." + domain
+	default:
+		candidate.fqdn = host
+	}
+
+	switch {
+	case candidate.fqdn == fqdn:
+		candidate.specificity = math.MaxInt32
+	case host == "*" || kubernetes.HostWithinWildcardHost(fqdn, host):
+		candidate.specificity = len(host)
+	default:
+		return candidateDestinationRule{}, false
+	}
+	return candidate, true
+}
+
+// exportedToNamespace checks whether the exportTo scope of the DestinationRule, all the namespaces by default,
+// includes the namespace
+func exportedToNamespace(dr kubernetes.IstioObject, namespace string) bool {
+	exportTo, found := dr.GetSpec()["exportTo"].([]interface{})
+	if !found || len(exportTo) == 0 {
+		return true
+	}
+	for _, e := range exportTo {
+		if export, ok := e.(string); ok {
+			if export == "*" || export == namespace || (export == "." && dr.GetObjectMeta().Namespace == namespace) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func destinationRuleSubsets(dr kubernetes.IstioObject) []map[string]interface{} {
+	subsets := make([]map[string]interface{}, 0)
+	if list, ok := dr.GetSpec()["subsets"].([]interface{}); ok {
+		for _, s := range list {
+			if subset, ok := s.(map[string]interface{}); ok {
+				subsets = append(subsets, subset)
+			}
+		}
+	}
+	return subsets
+}
+
+// olderObject sorts the objects by creation time, then by name
+func olderObject(left, right kubernetes.IstioObject) bool {
+	lm, rm := left.GetObjectMeta(), right.GetObjectMeta()
+	if !lm.CreationTimestamp.Equal(&rm.CreationTimestamp) {
+		return lm.CreationTimestamp.Before(&rm.CreationTimestamp)
+	}
+	return lm.Name < rm.Name
+}
+
+func drResolution(candidate candidateDestinationRule, reasons ...string) models.DestinationRuleResolution {
+	meta := candidate.dr.GetObjectMeta()
+	if reasons == nil {
+		reasons = []string{}
+	}
+	return models.DestinationRuleResolution{
+		Namespace: meta.Namespace,
+		Name:      meta.Name,
+		Host:      candidate.host,
+		Reasons:   reasons,
+	}
+}
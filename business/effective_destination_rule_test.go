@@ -0,0 +1,129 @@
+package business
+
+import (
+	"testing"
+
+	osproject_v1 "github.com/openshift/api/project/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	core_v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/kubernetes/kubetest"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/tests/data"
+)
+
+// Context: DestinationRules of the namespace of the service, of the root namespace and of another namespace
+func TestGetEffectiveDestinationRule(t *testing.T) {
+	assert := assert.New(t)
+
+	svc := effectiveDestinationRuleTestPrep(t)
+	effective, err := svc.GetEffectiveDestinationRule("bookinfo", "reviews")
+	assert.NoError(err)
+
+	assert.Equal("reviews.bookinfo.svc.cluster.local", effective.Host)
+	assert.Equal("reviews", effective.DestinationRule.Metadata.Name)
+
+	// The DestinationRules with the same host are merged, the oldest one wins
+	assert.Equal([]models.DestinationRuleResolution{
+		{
+			Namespace: "bookinfo",
+			Name:      "reviews-canary",
+			Host:      "reviews.bookinfo.svc.cluster.local",
+			Reasons: []string{
+				"Its traffic policy is overridden by the one of [bookinfo/reviews]",
+				"Its subset [v2] is overridden by the one of [bookinfo/reviews]",
+			},
+		},
+	}, effective.Merged)
+	assert.Equal(map[string]interface{}{"loadBalancer": map[string]interface{}{"simple": "LEAST_CONN"}}, effective.TrafficPolicy)
+	assert.Len(effective.Subsets, 3)
+	assert.Equal(map[string]interface{}{"version": "v2"}, effective.Subsets[1].(map[string]interface{})["labels"])
+	assert.Equal("v3", effective.Subsets[2].(map[string]interface{})["name"])
+
+	assert.Equal([]models.DestinationRuleResolution{
+		{
+			Namespace: "bookinfo",
+			Name:      "bookinfo-mtls",
+			Host:      "*.bookinfo.svc.cluster.local",
+			Reasons:   []string{"Shadowed by the more specific host [reviews] of [bookinfo/reviews]"},
+		},
+		{
+			Namespace: "istio-system",
+			Name:      "default",
+			Host:      "*.local",
+			Reasons:   []string{"Shadowed by [bookinfo/reviews], the DestinationRules of the namespace [bookinfo] take precedence"},
+		},
+		{
+			Namespace: "travel",
+			Name:      "reviews-outlier",
+			Host:      "reviews.bookinfo",
+			Reasons:   []string{"Only applies to the proxies of its namespace [travel]"},
+		},
+	}, effective.Shadowed)
+}
+
+// Context: Service without DestinationRule in its namespace, a DestinationRule of the root namespace not exported
+func TestGetEffectiveDestinationRuleFromRootNamespace(t *testing.T) {
+	assert := assert.New(t)
+
+	svc := effectiveDestinationRuleTestPrep(t)
+	effective, err := svc.GetEffectiveDestinationRule("travel", "hotels")
+	assert.NoError(err)
+
+	assert.Equal("default", effective.DestinationRule.Metadata.Name)
+	assert.Equal("istio-system", effective.DestinationRule.Metadata.Namespace)
+	assert.Empty(effective.Merged)
+	assert.Empty(effective.Subsets)
+	assert.Equal(map[string]interface{}{"tls": map[string]interface{}{"mode": "ISTIO_MUTUAL"}}, effective.TrafficPolicy)
+	assert.Equal([]models.DestinationRuleResolution{
+		{
+			Namespace: "istio-system",
+			Name:      "travel-internal",
+			Host:      "*.travel.svc.cluster.local",
+			Reasons:   []string{"Not exported to the namespace [travel]"},
+		},
+	}, effective.Shadowed)
+}
+
+func TestGetEffectiveDestinationRuleServiceNotFound(t *testing.T) {
+	assert := assert.New(t)
+
+	svc := effectiveDestinationRuleTestPrep(t)
+	_, err := svc.GetEffectiveDestinationRule("bookinfo", "details")
+	assert.True(errors.IsNotFound(err))
+}
+
+func effectiveDestinationRuleTestPrep(t *testing.T) *SvcService {
+	config.Set(config.NewConfig())
+
+	loader := &data.YamlFixtureLoader{Filename: "../tests/data/routing/effective-destination-rules.yaml"}
+	if err := loader.Load(); err != nil {
+		t.Error("Error loading test data.")
+	}
+
+	namespaces := []string{"bookinfo", "istio-system", "travel"}
+	projects := make([]osproject_v1.Project, 0, len(namespaces))
+	k8s := new(kubetest.K8SClientMock)
+	k8s.On("IsOpenShift").Return(true)
+	k8s.On("IsMaistraApi").Return(false)
+	for _, ns := range namespaces {
+		project := osproject_v1.Project{ObjectMeta: meta_v1.ObjectMeta{Name: ns}}
+		projects = append(projects, project)
+		k8s.On("GetProject", ns).Return(&project, nil)
+		k8s.On("GetIstioObjects", ns, kubernetes.DestinationRules, "").Return(loader.GetResourcesIn(kubernetes.DestinationRuleType, ns), nil)
+	}
+	k8s.On("GetProjects", mock.AnythingOfType("string")).Return(projects, nil)
+	for _, svc := range []string{"reviews", "hotels"} {
+		k8s.On("GetService", mock.AnythingOfType("string"), svc).Return(&core_v1.Service{ObjectMeta: meta_v1.ObjectMeta{Name: svc}}, nil)
+	}
+	k8s.On("GetService", "bookinfo", "details").Return(&core_v1.Service{}, errors.NewNotFound(schema.GroupResource{Resource: "services"}, "details"))
+
+	businessLayer := NewWithBackends(k8s, nil, nil)
+	return &SvcService{k8s: k8s, businessLayer: businessLayer}
+}
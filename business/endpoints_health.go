@@ -0,0 +1,66 @@
+package business
+
+import (
+	core_v1 "k8s.io/api/core/v1"
+	discovery_v1beta1 "k8s.io/api/discovery/v1beta1"
+
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/prometheus/internalmetrics"
+)
+
+// endpointsNotReadyThreshold is the ratio of not ready endpoints above which a service is degraded
+const endpointsNotReadyThreshold = 0.25
+
+// GetServiceEndpointsHealth reads the EndpointSlices of the service and counts its ready and not ready endpoints
+// per zone. Unhealthy endpoints cause partial failures which are not visible at the service level.
+func (in *SvcService) GetServiceEndpointsHealth(namespace, service string) (*models.ServiceEndpointsHealth, error) {
+	var err error
+	promtimer := internalmetrics.GetGoFunctionMetric("business", "SvcService", "GetServiceEndpointsHealth")
+	defer promtimer.ObserveNow(&err)
+
+	// Check if user has access to the namespace (RBAC) in cache scenarios and/or
+	// if namespace is accessible from Kiali (Deployment.AccessibleNamespaces)
+	if _, err = in.businessLayer.Namespace.GetNamespace(namespace); err != nil {
+		return nil, err
+	}
+
+	var svc *core_v1.Service
+	svc, err = in.getService(namespace, service)
+	if err != nil {
+		return nil, err
+	}
+	if svc == nil {
+		return nil, kubernetes.NewNotFound(service, "Kiali", "Service")
+	}
+
+	var slices []discovery_v1beta1.EndpointSlice
+	if IsNamespaceCached(namespace) {
+		slices, err = kialiCache.GetEndpointSlices(namespace, service)
+	} else {
+		slices, err = in.k8s.GetEndpointSlices(namespace, service)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return buildServiceEndpointsHealth(svc, slices), nil
+}
+
+func buildServiceEndpointsHealth(svc *core_v1.Service, slices []discovery_v1beta1.EndpointSlice) *models.ServiceEndpointsHealth {
+	health := &models.ServiceEndpointsHealth{Namespace: svc.Namespace, Service: svc.Name}
+	health.ParseEndpointSlices(slices)
+
+	if health.Total.Ready+health.Total.NotReady == 0 {
+		switch {
+		case svc.Spec.Type == core_v1.ServiceTypeExternalName:
+			// Resolved by DNS, no endpoint expected
+		case len(svc.Spec.Selector) == 0:
+			health.Warning = "The service has no selector and no endpoint is defined for it"
+		default:
+			health.Warning = "No pod matches the selector of the service"
+		}
+		return health
+	}
+	health.Degraded = health.Total.NotReadyRatio > endpointsNotReadyThreshold
+	return health
+}
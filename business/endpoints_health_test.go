@@ -0,0 +1,127 @@
+package business
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	core_v1 "k8s.io/api/core/v1"
+	discovery_v1beta1 "k8s.io/api/discovery/v1beta1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes/kubetest"
+	"github.com/kiali/kiali/models"
+)
+
+func TestGetServiceEndpointsHealth(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	k8s := new(kubetest.K8SClientMock)
+	k8s.On("IsOpenShift").Return(false)
+	k8s.On("GetNamespace", "bookinfo").Return(kubetest.FakeNamespace("bookinfo"), nil)
+	k8s.MockService("bookinfo", "reviews")
+	k8s.On("GetEndpointSlices", "bookinfo", "reviews").Return([]discovery_v1beta1.EndpointSlice{
+		fakeEndpointSlice(discovery_v1beta1.AddressTypeIPv4,
+			fakeEndpoint("reviews-v1-1", "us-east-1a", true),
+			fakeEndpoint("reviews-v2-1", "us-east-1a", true),
+			fakeEndpoint("reviews-v3-1", "us-east-1b", false),
+			fakeEndpoint("reviews-v3-2", "us-east-1b", true),
+		),
+		// Same endpoints of a dual stack service
+		fakeEndpointSlice(discovery_v1beta1.AddressTypeIPv6,
+			fakeEndpoint("reviews-v1-1", "us-east-1a", true),
+			fakeEndpoint("reviews-v3-1", "us-east-1b", false),
+		),
+	}, nil)
+	svc := SvcService{k8s: k8s, businessLayer: NewWithBackends(k8s, nil, nil)}
+
+	health, err := svc.GetServiceEndpointsHealth("bookinfo", "reviews")
+	assert.NoError(err)
+	assert.Equal("reviews", health.Service)
+	assert.Equal(3, health.Total.Ready)
+	assert.Equal(1, health.Total.NotReady)
+	assert.InDelta(0.25, health.Total.NotReadyRatio, 0.001)
+	assert.False(health.Degraded)
+	assert.Empty(health.Warning)
+
+	assert.Len(health.Zones, 2)
+	assert.Equal("us-east-1a", health.Zones[0].Zone)
+	assert.Equal(2, health.Zones[0].Ready)
+	assert.Zero(health.Zones[0].NotReady)
+	assert.Equal("us-east-1b", health.Zones[1].Zone)
+	assert.Equal(1, health.Zones[1].Ready)
+	assert.Equal(1, health.Zones[1].NotReady)
+	assert.InDelta(0.5, health.Zones[1].NotReadyRatio, 0.001)
+}
+
+func TestServiceEndpointsHealthDegraded(t *testing.T) {
+	assert := assert.New(t)
+
+	svc := fakeEndpointsService(map[string]string{"app": "reviews"})
+	notReady := fakeEndpoint("reviews-v2-1", "", false)
+	// An unknown condition is ready
+	unknown := fakeEndpoint("reviews-v1-1", "", true)
+	unknown.Conditions.Ready = nil
+
+	health := buildServiceEndpointsHealth(svc, []discovery_v1beta1.EndpointSlice{
+		fakeEndpointSlice(discovery_v1beta1.AddressTypeIPv4, unknown, notReady),
+	})
+
+	assert.True(health.Degraded)
+	assert.Equal(1, health.Total.Ready)
+	assert.Equal(1, health.Total.NotReady)
+	assert.Len(health.Zones, 1)
+	assert.Equal(models.UnknownZone, health.Zones[0].Zone)
+}
+
+func TestServiceEndpointsHealthWithoutEndpoints(t *testing.T) {
+	assert := assert.New(t)
+
+	health := buildServiceEndpointsHealth(fakeEndpointsService(map[string]string{"app": "reviews"}), []discovery_v1beta1.EndpointSlice{})
+	assert.Equal("No pod matches the selector of the service", health.Warning)
+	assert.False(health.Degraded)
+	assert.Empty(health.Zones)
+
+	// Endpoints of a service without selector are managed manually
+	health = buildServiceEndpointsHealth(fakeEndpointsService(nil), []discovery_v1beta1.EndpointSlice{
+		fakeEndpointSlice(discovery_v1beta1.AddressTypeIPv4),
+	})
+	assert.Equal("The service has no selector and no endpoint is defined for it", health.Warning)
+
+	externalName := fakeEndpointsService(nil)
+	externalName.Spec.Type = core_v1.ServiceTypeExternalName
+	health = buildServiceEndpointsHealth(externalName, []discovery_v1beta1.EndpointSlice{})
+	assert.Empty(health.Warning)
+}
+
+func fakeEndpointsService(selector map[string]string) *core_v1.Service {
+	return &core_v1.Service{
+		ObjectMeta: meta_v1.ObjectMeta{Name: "reviews", Namespace: "bookinfo"},
+		Spec:       core_v1.ServiceSpec{Selector: selector},
+	}
+}
+
+func fakeEndpointSlice(addressType discovery_v1beta1.AddressType, endpoints ...discovery_v1beta1.Endpoint) discovery_v1beta1.EndpointSlice {
+	return discovery_v1beta1.EndpointSlice{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "reviews-" + string(addressType),
+			Namespace: "bookinfo",
+			Labels:    map[string]string{discovery_v1beta1.LabelServiceName: "reviews"},
+		},
+		AddressType: addressType,
+		Endpoints:   endpoints,
+	}
+}
+
+func fakeEndpoint(pod, zone string, ready bool) discovery_v1beta1.Endpoint {
+	endpoint := discovery_v1beta1.Endpoint{
+		Addresses:  []string{"10.0.0.1"},
+		Conditions: discovery_v1beta1.EndpointConditions{Ready: &ready},
+		TargetRef:  &core_v1.ObjectReference{Kind: "Pod", Namespace: "bookinfo", Name: pod},
+	}
+	if zone != "" {
+		endpoint.Topology = map[string]string{core_v1.LabelTopologyZone: zone}
+	}
+	return endpoint
+}
@@ -0,0 +1,179 @@
+package business
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	core_v1 "k8s.io/api/core/v1"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/prometheus/internalmetrics"
+)
+
+// The kinds of the extension providers, by the field of their definition
+var extensionProviderKinds = map[string][]string{
+	"envoyExtAuthzHttp":  {models.ExtensionProviderAuthorization},
+	"envoyExtAuthzGrpc":  {models.ExtensionProviderAuthorization},
+	"zipkin":             {models.ExtensionProviderTracing},
+	"lightstep":          {models.ExtensionProviderTracing},
+	"datadog":            {models.ExtensionProviderTracing},
+	"opencensus":         {models.ExtensionProviderTracing},
+	"skywalking":         {models.ExtensionProviderTracing},
+	"opentelemetry":      {models.ExtensionProviderTracing},
+	"stackdriver":        {models.ExtensionProviderAccessLogging, models.ExtensionProviderMetrics, models.ExtensionProviderTracing},
+	"prometheus":         {models.ExtensionProviderMetrics},
+	"envoyFileAccessLog": {models.ExtensionProviderAccessLogging},
+	"envoyHttpAls":       {models.ExtensionProviderAccessLogging},
+	"envoyTcpAls":        {models.ExtensionProviderAccessLogging},
+	"envoyOtelAls":       {models.ExtensionProviderAccessLogging},
+}
+
+// The providers Istio adds to the mesh config when it doesn't define them, by name, with the field of their definition
+var builtinExtensionProviders = map[string]string{
+	builtinAccessLogProvider: "envoyFileAccessLog",
+	"prometheus":             "prometheus",
+	"stackdriver":            "stackdriver",
+}
+
+// GetExtensionProviders returns the extension providers of the mesh config, with the reachability of their service
+// in the service registry of the namespaces accessible to the user
+func (in *IstioValidationsService) GetExtensionProviders() (models.ExtensionProviders, error) {
+	var err error
+	promtimer := internalmetrics.GetGoFunctionMetric("business", "IstioValidationsService", "GetExtensionProviders")
+	defer promtimer.ObserveNow(&err)
+
+	var meshConfig *models.MeshConfig
+	if meshConfig, err = in.businessLayer.Mesh.GetEffectiveMeshConfig(); err != nil {
+		return nil, err
+	}
+
+	var namespaces models.Namespaces
+	var services []core_v1.Service
+	var serviceEntries []kubernetes.IstioObject
+	wg := sync.WaitGroup{}
+	errChan := make(chan error, 1)
+	wg.Add(3)
+	go in.fetchNamespaces(&namespaces, errChan, &wg)
+	go in.fetchAllServices(&services, errChan, &wg)
+	go in.fetchAllServiceEntries(&serviceEntries, errChan, &wg)
+	wg.Wait()
+	close(errChan)
+	for e := range errChan {
+		if e != nil {
+			err = e
+			return nil, err
+		}
+	}
+
+	return describeExtensionProviders(meshConfig, namespaces.GetNames(), services, serviceEntries), nil
+}
+
+// resolveExtensionProviders returns the extension providers of the mesh config, nil when the mesh config is unknown
+func resolveExtensionProviders(meshConfig *models.MeshConfig, namespaces models.Namespaces, services []core_v1.Service, serviceEntries []kubernetes.IstioObject) models.ExtensionProviders {
+	if meshConfig == nil {
+		return nil
+	}
+	return describeExtensionProviders(meshConfig, namespaces.GetNames(), services, serviceEntries)
+}
+
+// describeExtensionProviders lists the providers defined in the mesh config and the builtin ones it doesn't redefine
+func describeExtensionProviders(meshConfig *models.MeshConfig, namespaces []string, services []core_v1.Service, serviceEntries []kubernetes.IstioObject) models.ExtensionProviders {
+	providers := models.ExtensionProviders{}
+	for name, definition := range meshExtensionProviders(meshConfig) {
+		provider := models.ExtensionProvider{Name: name, Type: "unknown", Kinds: []string{}, Reachable: true}
+		for field, kinds := range extensionProviderKinds {
+			settings, ok := definition[field].(map[string]interface{})
+			if !ok {
+				continue
+			}
+			provider.Type = field
+			provider.Kinds = kinds
+			provider.Service, _ = settings["service"].(string)
+			if port, ok := settings["port"].(float64); ok {
+				provider.Port = int(port)
+			}
+			break
+		}
+		if provider.Service != "" {
+			provider.Reachable, provider.Message = extensionProviderReachability(provider, namespaces, services, serviceEntries)
+		}
+		providers = append(providers, provider)
+	}
+	for name, field := range builtinExtensionProviders {
+		if providers.Get(name) == nil {
+			providers = append(providers, models.ExtensionProvider{Name: name, Type: field, Kinds: extensionProviderKinds[field], Builtin: true, Reachable: true})
+		}
+	}
+	sort.Slice(providers, func(i, j int) bool {
+		return providers[i].Name < providers[j].Name
+	})
+	return providers
+}
+
+// extensionProviderReachability looks for the service of the provider, in the <namespace>/<hostname> or <hostname>
+// format, in the Services and the ServiceEntries of the mesh
+func extensionProviderReachability(provider models.ExtensionProvider, namespaces []string, services []core_v1.Service, serviceEntries []kubernetes.IstioObject) (bool, string) {
+	hostname, namespace := provider.Service, ""
+	if i := strings.Index(hostname, "/"); i >= 0 {
+		namespace, hostname = hostname[:i], hostname[i+1:]
+	}
+
+	host := kubernetes.GetHost(hostname, config.Get().IstioNamespace, config.Get().ExternalServices.Istio.IstioIdentityDomain, namespaces)
+	if host.CompleteInput {
+		for _, svc := range services {
+			if svc.Name != host.Service || svc.Namespace != host.Namespace || (namespace != "" && svc.Namespace != namespace) {
+				continue
+			}
+			if provider.Port == 0 {
+				return true, ""
+			}
+			for _, port := range svc.Spec.Ports {
+				if int(port.Port) == provider.Port {
+					return true, ""
+				}
+			}
+			return false, fmt.Sprintf("The Service %s/%s has no port %d", svc.Namespace, svc.Name, provider.Port)
+		}
+	}
+
+	for _, se := range serviceEntries {
+		if namespace != "" && se.GetObjectMeta().Namespace != namespace {
+			continue
+		}
+		if !serviceEntryHasHost(se, hostname) {
+			continue
+		}
+		if provider.Port == 0 || serviceEntryHasPort(se, provider.Port) {
+			return true, ""
+		}
+		return false, fmt.Sprintf("The ServiceEntry %s/%s has no port %d", se.GetObjectMeta().Namespace, se.GetObjectMeta().Name, provider.Port)
+	}
+
+	return false, fmt.Sprintf("The service [%s] is not found in the Services nor in the ServiceEntries of the mesh", provider.Service)
+}
+
+func serviceEntryHasHost(se kubernetes.IstioObject, hostname string) bool {
+	hosts, _ := se.GetSpec()["hosts"].([]interface{})
+	for _, h := range hosts {
+		if host, ok := h.(string); ok && host == hostname {
+			return true
+		}
+	}
+	return false
+}
+
+func serviceEntryHasPort(se kubernetes.IstioObject, number int) bool {
+	ports, _ := se.GetSpec()["ports"].([]interface{})
+	for _, p := range ports {
+		// The number is an int or a float64 depending on the decoder
+		if port, ok := p.(map[string]interface{}); ok && fmt.Sprintf("%v", port["number"]) == strconv.Itoa(number) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,58 @@
+package business
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	core_v1 "k8s.io/api/core/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/tests/data"
+)
+
+// Context: providers with a Service, a Service without their port, a ServiceEntry and no service at all
+func TestDescribeExtensionProviders(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	meshYaml, err := ioutil.ReadFile("../tests/data/validations/extension_providers/mesh.yaml")
+	if err != nil {
+		t.Fatalf("Error loading test data: %v", err)
+	}
+	meshConfig, err := models.ParseMeshConfig(string(meshYaml), models.DefaultMeshConfig("istio-system"))
+	assert.NoError(err)
+
+	loader := &data.YamlFixtureLoader{Filename: "../tests/data/validations/extension_providers/references.yaml"}
+	if err := loader.Load(); err != nil {
+		t.Fatalf("Error loading test data: %v", err)
+	}
+	services := []core_v1.Service{{
+		ObjectMeta: meta_v1.ObjectMeta{Name: "ext-authz", Namespace: "foo"},
+		Spec:       core_v1.ServiceSpec{Ports: []core_v1.ServicePort{{Name: "http", Port: 8000}}},
+	}}
+
+	providers := describeExtensionProviders(meshConfig, []string{"bookinfo", "foo", "observability"}, services, loader.GetResources("ServiceEntry"))
+
+	assert.Equal(models.ExtensionProviders{
+		{Name: "envoy", Type: "envoyFileAccessLog", Kinds: []string{models.ExtensionProviderAccessLogging}, Builtin: true, Reachable: true},
+		{Name: "ext-authz", Type: "envoyExtAuthzHttp", Kinds: []string{models.ExtensionProviderAuthorization},
+			Service: "ext-authz.foo.svc.cluster.local", Port: 8000, Reachable: true},
+		{Name: "ext-authz-grpc", Type: "envoyExtAuthzGrpc", Kinds: []string{models.ExtensionProviderAuthorization},
+			Service: "foo/ext-authz.foo.svc.cluster.local", Port: 9000, Message: "The Service foo/ext-authz has no port 9000"},
+		{Name: "external-tracing", Type: "zipkin", Kinds: []string{models.ExtensionProviderTracing},
+			Service: "tracing.example.com", Port: 9411, Reachable: true},
+		{Name: "json-logs", Type: "envoyFileAccessLog", Kinds: []string{models.ExtensionProviderAccessLogging}, Reachable: true},
+		{Name: "otel", Type: "opentelemetry", Kinds: []string{models.ExtensionProviderTracing},
+			Service: "otel-collector.observability.svc.cluster.local", Port: 4317,
+			Message: "The service [otel-collector.observability.svc.cluster.local] is not found in the Services nor in the ServiceEntries of the mesh"},
+		{Name: "prometheus", Type: "prometheus", Kinds: []string{models.ExtensionProviderMetrics}, Builtin: true, Reachable: true},
+		{Name: "stackdriver", Type: "stackdriver", Kinds: []string{models.ExtensionProviderAccessLogging, models.ExtensionProviderMetrics, models.ExtensionProviderTracing}, Builtin: true, Reachable: true},
+	}, providers)
+}
+
+func TestResolveExtensionProvidersWithoutMeshConfig(t *testing.T) {
+	assert.Nil(t, resolveExtensionProviders(nil, models.Namespaces{}, []core_v1.Service{}, nil))
+}
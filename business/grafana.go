@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"net/url"
 	"strings"
+	"sync"
 	"time"
 
 	"k8s.io/client-go/tools/clientcmd/api"
@@ -105,6 +106,120 @@ func getGrafanaLinks(conn grafanaConnectionInfo, linksSpec []v1alpha1.Monitoring
 	return linksOut, http.StatusOK, nil
 }
 
+// GrafanaDashboardTarget is the app, workload or service the Grafana dashboard links are built for.
+// Fields are set as the dashboard variables configured for them, empty fields are skipped.
+type GrafanaDashboardTarget struct {
+	Namespace string
+	App       string
+	Version   string
+	Workload  string
+	Service   string
+}
+
+// GetGrafanaDashboardFolders returns the links to the configured Grafana dashboards grouped by folder, with the
+// dashboard variables set for the target. Dashboards not found in Grafana are skipped. Folders keep the order
+// of the configuration, the HTTP status code (int) and eventually an error are returned too.
+func GetGrafanaDashboardFolders(authInfo *api.AuthInfo, target GrafanaDashboardTarget, dashboardSupplier dashboardSupplier) ([]models.GrafanaDashboardFolder, int, error) {
+	grafanaConfig := config.Get().ExternalServices.Grafana
+	if !grafanaConfig.Enabled {
+		return nil, http.StatusNoContent, nil
+	}
+	conn, code, err := getGrafanaConnectionInfo(authInfo, &grafanaConfig)
+	if err != nil {
+		return nil, code, err
+	}
+
+	cacheDuration := time.Duration(grafanaConfig.DashboardsCacheDuration) * time.Second
+	folders := []models.GrafanaDashboardFolder{}
+	folderIndexes := map[string]int{}
+	for _, dashboardConfig := range grafanaConfig.Dashboards {
+		dashboardPath, err := grafanaDashboardsCache.getDashboardPath(dashboardConfig.Name, conn, dashboardSupplier, cacheDuration)
+		if err != nil {
+			return nil, http.StatusServiceUnavailable, err
+		}
+		if dashboardPath == "" {
+			continue
+		}
+
+		folder := dashboardConfig.Folder
+		if folder == "" {
+			folder = models.DefaultGrafanaFolder
+		}
+		i, found := folderIndexes[folder]
+		if !found {
+			i = len(folders)
+			folderIndexes[folder] = i
+			folders = append(folders, models.GrafanaDashboardFolder{Name: folder, Links: []models.GrafanaDashboardLink{}})
+		}
+		folders[i].Links = append(folders[i].Links, models.GrafanaDashboardLink{
+			Name: dashboardConfig.Name,
+			URL:  setDashboardVariables(dashboardPath, dashboardConfig.Variables, target),
+		})
+	}
+
+	return folders, http.StatusOK, nil
+}
+
+// setDashboardVariables adds to the dashboard URL the query parameters of the variables configured for the target fields
+func setDashboardVariables(dashboardURL string, variables config.GrafanaVariablesConfig, target GrafanaDashboardTarget) string {
+	params := url.Values{}
+	for variable, value := range map[string]string{
+		variables.Namespace: target.Namespace,
+		variables.App:       target.App,
+		variables.Version:   target.Version,
+		variables.Workload:  target.Workload,
+		variables.Service:   target.Service,
+	} {
+		if variable != "" && value != "" {
+			params.Set(variable, value)
+		}
+	}
+	if len(params) == 0 {
+		return dashboardURL
+	}
+	if strings.Contains(dashboardURL, "?") {
+		return dashboardURL + "&" + params.Encode()
+	}
+	return dashboardURL + "?" + params.Encode()
+}
+
+type cachedDashboardPath struct {
+	path       string
+	expiration time.Time
+}
+
+// dashboardPathsCache caches the dashboard paths found in the Grafana API, including the dashboards not found
+type dashboardPathsCache struct {
+	lock  sync.RWMutex
+	paths map[string]cachedDashboardPath
+}
+
+var grafanaDashboardsCache = &dashboardPathsCache{paths: map[string]cachedDashboardPath{}}
+
+func (c *dashboardPathsCache) getDashboardPath(name string, conn grafanaConnectionInfo, dashboardSupplier dashboardSupplier, duration time.Duration) (string, error) {
+	if duration <= 0 {
+		return getDashboardPath(name, conn, dashboardSupplier)
+	}
+
+	key := conn.inClusterURL + "|" + conn.baseExternalURL + conn.externalURLParams + "|" + name
+	c.lock.RLock()
+	cached, found := c.paths[key]
+	c.lock.RUnlock()
+	if found && time.Now().Before(cached.expiration) {
+		return cached.path, nil
+	}
+
+	// Errors are not cached, Grafana may be temporarily unavailable
+	path, err := getDashboardPath(name, conn, dashboardSupplier)
+	if err != nil {
+		return "", err
+	}
+	c.lock.Lock()
+	c.paths[key] = cachedDashboardPath{path: path, expiration: time.Now().Add(duration)}
+	c.lock.Unlock()
+	return path, nil
+}
+
 type grafanaConnectionInfo struct {
 	baseExternalURL   string
 	externalURLParams string
@@ -139,11 +254,14 @@ func getGrafanaConnectionInfo(authInfo *api.AuthInfo, cfg *config.GrafanaConfig)
 		externalURLParams = "?" + urlParts[1]
 	}
 
+	// The credentials of the store replace those of the config once rotated
+	auth := resolveAuth(CredentialsServiceGrafana, cfg.Auth)
+
 	return grafanaConnectionInfo{
 		baseExternalURL:   externalURL,
 		externalURLParams: externalURLParams,
 		inClusterURL:      apiURL,
-		auth:              &cfg.Auth,
+		auth:              &auth,
 	}, 0, nil
 }
 
@@ -9,6 +9,7 @@ import (
 	"k8s.io/client-go/tools/clientcmd/api"
 
 	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/models"
 )
 
 var dashboardsConfig = []config.GrafanaDashboardConfig{
@@ -149,6 +150,116 @@ func TestGetGrafanaInfoWithAbsoluteDashboardURL(t *testing.T) {
 	assert.Equal(t, "/system/grafana/some_path", info.ExternalLinks[0].URL)
 }
 
+func TestGetGrafanaDashboardFolders(t *testing.T) {
+	assert := assert.New(t)
+	conf := config.NewConfig()
+	conf.ExternalServices.Grafana.URL = "http://grafana-external:3001/?orgId=1"
+	conf.ExternalServices.Grafana.Dashboards = []config.GrafanaDashboardConfig{
+		{Name: "Istio Workload Dashboard", Folder: "Istio", Variables: config.GrafanaVariablesConfig{Namespace: "var-namespace", Workload: "var-workload"}},
+		{Name: "JVM", Folder: "Runtimes", Variables: config.GrafanaVariablesConfig{App: "var-app", Version: "var-version"}},
+		{Name: "Istio Mesh Dashboard", Folder: "Istio"},
+		{Name: "Node Exporter", Variables: config.GrafanaVariablesConfig{Service: "var-service"}},
+		{Name: "Missing Dashboard", Folder: "Others"},
+	}
+	config.Set(conf)
+	grafanaDashboardsCache.paths = map[string]cachedDashboardPath{}
+
+	supplier := func(_, name string, _ *config.Auth) ([]byte, int, error) {
+		paths := map[string]string{
+			"Istio%20Workload%20Dashboard": "/d/1/istio-workload",
+			"JVM":                          "/d/2/jvm",
+			"Istio%20Mesh%20Dashboard":     "/d/3/istio-mesh",
+			"Node%20Exporter":              "/d/4/node",
+		}
+		if path, ok := paths[name]; ok {
+			bytes, err := json.Marshal(genDashboard(path))
+			return bytes, 200, err
+		}
+		return []byte("[]"), 200, nil
+	}
+	target := GrafanaDashboardTarget{Namespace: "bookinfo", App: "reviews", Version: "v1", Workload: "reviews-v1"}
+
+	folders, code, err := GetGrafanaDashboardFolders(&api.AuthInfo{Token: ""}, target, supplier)
+
+	assert.NoError(err)
+	assert.Equal(http.StatusOK, code)
+	// Folders follow the configuration order, the missing dashboard and its folder are skipped
+	assert.Len(folders, 3)
+	assert.Equal("Istio", folders[0].Name)
+	assert.Equal([]models.GrafanaDashboardLink{
+		{Name: "Istio Workload Dashboard", URL: "http://grafana-external:3001/d/1/istio-workload?orgId=1&var-namespace=bookinfo&var-workload=reviews-v1"},
+		{Name: "Istio Mesh Dashboard", URL: "http://grafana-external:3001/d/3/istio-mesh?orgId=1"},
+	}, folders[0].Links)
+	assert.Equal("Runtimes", folders[1].Name)
+	assert.Equal([]models.GrafanaDashboardLink{
+		{Name: "JVM", URL: "http://grafana-external:3001/d/2/jvm?orgId=1&var-app=reviews&var-version=v1"},
+	}, folders[1].Links)
+	// The service is not set in the target, so its variable is skipped
+	assert.Equal(models.DefaultGrafanaFolder, folders[2].Name)
+	assert.Equal([]models.GrafanaDashboardLink{
+		{Name: "Node Exporter", URL: "http://grafana-external:3001/d/4/node?orgId=1"},
+	}, folders[2].Links)
+}
+
+func TestGetGrafanaDashboardFoldersCache(t *testing.T) {
+	assert := assert.New(t)
+	conf := config.NewConfig()
+	conf.ExternalServices.Grafana.URL = "http://grafana-external:3001"
+	conf.ExternalServices.Grafana.Dashboards = []config.GrafanaDashboardConfig{
+		{Name: "My Dashboard", Variables: config.GrafanaVariablesConfig{Service: "var-service"}},
+		{Name: "Missing Dashboard"},
+	}
+	config.Set(conf)
+	grafanaDashboardsCache.paths = map[string]cachedDashboardPath{}
+
+	calls := 0
+	supplier := func(_, name string, _ *config.Auth) ([]byte, int, error) {
+		calls++
+		if name == "My%20Dashboard" {
+			bytes, err := json.Marshal(genDashboard("/some_path"))
+			return bytes, 200, err
+		}
+		return []byte("[]"), 200, nil
+	}
+
+	for _, service := range []string{"reviews", "ratings"} {
+		folders, _, err := GetGrafanaDashboardFolders(&api.AuthInfo{Token: ""}, GrafanaDashboardTarget{Namespace: "bookinfo", Service: service}, supplier)
+		assert.NoError(err)
+		assert.Len(folders, 1)
+		assert.Equal("http://grafana-external:3001/some_path?var-service="+service, folders[0].Links[0].URL)
+	}
+	// Found and missing dashboards are only looked up once
+	assert.Equal(2, calls)
+
+	// Errors are not cached
+	grafanaDashboardsCache.paths = map[string]cachedDashboardPath{}
+	_, code, err := GetGrafanaDashboardFolders(&api.AuthInfo{Token: ""}, GrafanaDashboardTarget{Namespace: "bookinfo"}, buildDashboardSupplier(anError, 401, "http://grafana-external:3001", t))
+	assert.Error(err)
+	assert.Equal(http.StatusServiceUnavailable, code)
+	assert.Empty(grafanaDashboardsCache.paths)
+
+	// Without cache duration every request looks up the dashboards
+	conf.ExternalServices.Grafana.DashboardsCacheDuration = 0
+	config.Set(conf)
+	calls = 0
+	for i := 0; i < 2; i++ {
+		_, _, err = GetGrafanaDashboardFolders(&api.AuthInfo{Token: ""}, GrafanaDashboardTarget{Namespace: "bookinfo"}, supplier)
+		assert.NoError(err)
+	}
+	assert.Equal(4, calls)
+}
+
+func TestGetGrafanaDashboardFoldersDisabled(t *testing.T) {
+	conf := config.NewConfig()
+	conf.ExternalServices.Grafana.Enabled = false
+	config.Set(conf)
+
+	folders, code, err := GetGrafanaDashboardFolders(&api.AuthInfo{Token: ""}, GrafanaDashboardTarget{Namespace: "bookinfo"}, buildDashboardSupplier(genDashboard("/some_path"), 200, "whatever", t))
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusNoContent, code)
+	assert.Nil(t, folders)
+}
+
 func buildDashboardSupplier(jSon interface{}, code int, expectURL string, t *testing.T) dashboardSupplier {
 	return func(url, _ string, _ *config.Auth) ([]byte, int, error) {
 		assert.Equal(t, expectURL, url)
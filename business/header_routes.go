@@ -0,0 +1,107 @@
+package business
+
+import (
+	"fmt"
+	"sync"
+
+	core_v1 "k8s.io/api/core/v1"
+
+	"github.com/kiali/kiali/business/checkers/virtual_services"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/prometheus/internalmetrics"
+)
+
+// GetHeaderRoutes returns the routes of the VirtualServices of the namespace that match on request headers, with their
+// destinations. The destinations are resolved with the same logic used by the validations of the VirtualServices.
+func (in *IstioValidationsService) GetHeaderRoutes(namespace string) (models.HeaderRoutes, error) {
+	var err error
+	promtimer := internalmetrics.GetGoFunctionMetric("business", "IstioValidationsService", "GetHeaderRoutes")
+	defer promtimer.ObserveNow(&err)
+
+	// Check if user has access to the namespace (RBAC) in cache scenarios and/or
+	// if namespace is accessible from Kiali (Deployment.AccessibleNamespaces)
+	if _, err = in.businessLayer.Namespace.GetNamespace(namespace); err != nil {
+		return models.HeaderRoutes{}, err
+	}
+
+	var istioDetails kubernetes.IstioDetails
+	var namespaces models.Namespaces
+	var services []core_v1.Service
+
+	wg := sync.WaitGroup{}
+	errChan := make(chan error, 1)
+
+	wg.Add(3)
+	go in.fetchDetails(&istioDetails, namespace, errChan, &wg)
+	go in.fetchNamespaces(&namespaces, errChan, &wg)
+	go in.fetchServices(&services, namespace, errChan, &wg)
+	wg.Wait()
+
+	close(errChan)
+	for e := range errChan {
+		if e != nil { // Check that default value wasn't returned
+			err = e
+			return models.HeaderRoutes{}, err
+		}
+	}
+
+	return buildHeaderRoutes(namespace, namespaces, istioDetails, services), nil
+}
+
+func buildHeaderRoutes(namespace string, namespaces models.Namespaces, istioDetails kubernetes.IstioDetails, services []core_v1.Service) models.HeaderRoutes {
+	headerRoutes := models.HeaderRoutes{
+		Namespace: namespace,
+		Routes:    []models.HeaderRoute{},
+	}
+
+	serviceNames := make([]string, 0, len(services))
+	for _, s := range services {
+		serviceNames = append(serviceNames, s.Name)
+	}
+	serviceEntryHosts := kubernetes.ServiceEntryHostnames(istioDetails.ServiceEntries)
+
+	for _, vs := range istioDetails.VirtualServices {
+		routes := models.ParseHeaderRoutes(vs)
+		if len(routes) == 0 {
+			continue
+		}
+
+		// Paths of the destinations that don't exist
+		notFound := map[string]string{}
+		subsetChecks, _ := virtual_services.SubsetPresenceChecker{
+			Namespace:        namespace,
+			Namespaces:       namespaces.GetNames(),
+			DestinationRules: istioDetails.DestinationRules,
+			VirtualService:   vs,
+		}.Check()
+		for _, check := range subsetChecks {
+			notFound[check.Path+"/host"] = models.HeaderRouteSubsetNotFound
+		}
+		// A missing host is reported over a missing subset
+		hostChecks, _ := virtual_services.NoHostChecker{
+			Namespace:         namespace,
+			Namespaces:        namespaces,
+			ServiceNames:      serviceNames,
+			VirtualService:    vs,
+			ServiceEntryHosts: serviceEntryHosts,
+		}.Check()
+		for _, check := range hostChecks {
+			if check.Message == models.CheckMessage("virtualservices.nohost.hostnotfound") {
+				notFound[check.Path] = models.HeaderRouteHostNotFound
+			}
+		}
+
+		for _, route := range routes {
+			for i := range route.Destinations {
+				path := fmt.Sprintf("%s/route[%d]/destination/host", route.Path, i)
+				if reason, found := notFound[path]; found {
+					route.Destinations[i].NotFound = reason
+					route.Invalid = true
+				}
+			}
+			headerRoutes.Routes = append(headerRoutes.Routes, route)
+		}
+	}
+	return headerRoutes
+}
@@ -0,0 +1,80 @@
+package business
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	core_v1 "k8s.io/api/core/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/tests/data"
+)
+
+func TestBuildHeaderRoutes(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	loader := &data.YamlFixtureLoader{Filename: "../tests/data/routing/header-routes.yaml"}
+	if err := loader.Load(); err != nil {
+		t.Fatalf("Error loading test data: %v", err)
+	}
+	istioDetails := kubernetes.IstioDetails{
+		VirtualServices:  loader.GetResources("VirtualService"),
+		DestinationRules: loader.GetResources("DestinationRule"),
+	}
+	services := []core_v1.Service{
+		{ObjectMeta: meta_v1.ObjectMeta{Name: "reviews", Namespace: "bookinfo"}},
+		{ObjectMeta: meta_v1.ObjectMeta{Name: "ratings", Namespace: "bookinfo"}},
+		{ObjectMeta: meta_v1.ObjectMeta{Name: "details", Namespace: "bookinfo"}},
+	}
+	namespaces := models.Namespaces{{Name: "bookinfo"}}
+
+	headerRoutes := buildHeaderRoutes("bookinfo", namespaces, istioDetails, services)
+
+	assert.Equal("bookinfo", headerRoutes.Namespace)
+	assert.Len(headerRoutes.Routes, 3)
+	byPath := map[string]models.HeaderRoute{}
+	for _, r := range headerRoutes.Routes {
+		byPath[r.VirtualService+"/"+r.Path] = r
+	}
+
+	// Exact match
+	jason := byPath["reviews/spec/http[0]"]
+	assert.Equal("jason", jason.Name)
+	assert.Equal([]string{"reviews"}, jason.Hosts)
+	assert.Len(jason.Matches, 1)
+	assert.Equal("spec/http[0]/match[0]", jason.Matches[0].Path)
+	assert.Equal([]models.HeaderCondition{{Header: "end-user", Type: models.HeaderMatchExact, Value: "jason"}}, jason.Matches[0].Conditions)
+	assert.Equal([]models.HeaderRouteDestination{{Host: "reviews", Subset: "v2"}}, jason.Destinations)
+	assert.False(jason.Invalid)
+
+	// Regex and prefix matches, negated conditions and several match blocks
+	mobile := byPath["reviews/spec/http[1]"]
+	assert.Len(mobile.Matches, 2)
+	assert.Equal([]models.HeaderCondition{
+		{Header: "user-agent", Type: models.HeaderMatchRegex, Value: ".*(Android|iPhone).*"},
+		{Header: "x-canary", Type: models.HeaderMatchPrefix, Value: "true"},
+		{Header: "x-internal", Type: models.HeaderMatchExact, Value: "1", Negated: true},
+	}, mobile.Matches[0].Conditions)
+	assert.Equal("spec/http[1]/match[1]", mobile.Matches[1].Path)
+	assert.Equal("cookie", mobile.Matches[1].Conditions[0].Header)
+	assert.True(mobile.Invalid)
+	assert.Len(mobile.Destinations, 2)
+	assert.Equal(uint32(80), mobile.Destinations[0].Weight)
+	assert.Empty(mobile.Destinations[0].NotFound)
+	assert.Equal("v3", mobile.Destinations[1].Subset)
+	assert.Equal(models.HeaderRouteSubsetNotFound, mobile.Destinations[1].NotFound)
+
+	// Dark launch to a service that doesn't exist
+	dark := byPath["ratings/spec/http[0]"]
+	assert.True(dark.Invalid)
+	assert.Equal("ratings-dark", dark.Destinations[0].Host)
+	assert.Equal(models.HeaderRouteHostNotFound, dark.Destinations[0].NotFound)
+
+	// Routes without header conditions are not listed
+	_, found := byPath["reviews/spec/http[2]"]
+	assert.False(found)
+}
@@ -1,6 +1,9 @@
 package business
 
 import (
+	"fmt"
+	"sort"
+	"sync"
 	"time"
 
 	"github.com/prometheus/common/model"
@@ -31,8 +34,9 @@ func (in *HealthService) GetServiceHealth(namespace, service, rateInterval strin
 	promtimer := internalmetrics.GetGoFunctionMetric("business", "HealthService", "GetServiceHealth")
 	defer promtimer.ObserveNow(&err)
 
-	rqHealth, err := in.getServiceRequestsHealth(namespace, service, rateInterval, queryTime)
-	return models.ServiceHealth{Requests: rqHealth}, err
+	var health models.ServiceHealth
+	health, err = in.getServiceHealth(namespace, service, rateInterval, queryTime)
+	return health, err
 }
 
 // GetAppHealth returns an app health from just Namespace and app name (thus, it fetches data from K8S and Prometheus)
@@ -88,27 +92,242 @@ func (in *HealthService) GetWorkloadHealth(namespace, workload, workloadType, ra
 	promtimer := internalmetrics.GetGoFunctionMetric("business", "HealthService", "GetWorkloadHealth")
 	defer promtimer.ObserveNow(&err)
 
+	health, _, err := in.getWorkloadHealth(namespace, workload, workloadType, rateInterval, queryTime)
+	return health, err
+}
+
+// getWorkloadHealth also returns the outbound request rates of the workload, they are the edges to its dependencies
+func (in *HealthService) getWorkloadHealth(namespace, workload, workloadType, rateInterval string, queryTime time.Time) (models.WorkloadHealth, model.Vector, error) {
 	w, err := fetchWorkload(in.businessLayer, namespace, workload, workloadType)
 	if err != nil {
-		return models.WorkloadHealth{}, err
+		return models.WorkloadHealth{}, nil, err
 	}
 
 	status := w.CastWorkloadStatus()
 
 	// Perf: do not bother fetching request rate if workload has no sidecar
 	if !w.IstioSidecar {
-		return models.WorkloadHealth{
+		health := models.WorkloadHealth{
 			WorkloadStatus: status,
 			Requests:       models.NewEmptyRequestHealth(),
-		}, nil
+		}
+		fillWorkloadMaintenance(&health, w, queryTime)
+		fillWorkloadIstioInitIssues(&health, w)
+		fillWorkloadProbeIssues(&health, w)
+		return health, model.Vector{}, nil
 	}
 
 	// Add Telemetry info
-	rate, err := in.getWorkloadRequestsHealth(namespace, workload, rateInterval, queryTime)
-	return models.WorkloadHealth{
+	rate, outbound, err := in.getWorkloadRequestsHealth(namespace, workload, rateInterval, queryTime)
+	health := models.WorkloadHealth{
 		WorkloadStatus: status,
 		Requests:       rate,
-	}, err
+	}
+	fillWorkloadMaintenance(&health, w, queryTime)
+	fillWorkloadIstioInitIssues(&health, w)
+	fillWorkloadProbeIssues(&health, w)
+	return health, outbound, err
+}
+
+// fillWorkloadMaintenance sets the Maintenance status of a workload in maintenance at the query time
+func fillWorkloadMaintenance(health *models.WorkloadHealth, w *models.Workload, queryTime time.Time) {
+	if w.InMaintenance(queryTime) {
+		health.Status = models.HealthStatusMaintenance
+		health.MaintenanceUntil = w.MaintenanceUntil
+	}
+}
+
+// fillWorkloadIstioInitIssues reports the pods of a workload stuck by their Istio networking, an injected sidecar not
+// getting ready is then explained by the failure of the init containers or of the Istio CNI plugin
+func fillWorkloadIstioInitIssues(health *models.WorkloadHealth, w *models.Workload) {
+	for _, pod := range w.Pods {
+		if pod.IstioInitIssue != nil {
+			health.IstioInitIssues = append(health.IstioInitIssues, models.PodIstioInitIssue{Pod: pod.Name, IstioInitIssue: *pod.IstioInitIssue})
+		}
+	}
+}
+
+const (
+	// probeRestartsThreshold is the number of restarts of a container from which its probes are suspected
+	probeRestartsThreshold = 3
+	// probeStartupSeconds is the time a container is expected to need to start, without a startup probe
+	probeStartupSeconds = 30
+)
+
+// The exit codes of a container killed by the kubelet, as when its liveness probe fails
+var probeKilledExitCodes = map[int32]bool{0: true, 137: true, 143: true}
+
+// fillWorkloadProbeIssues correlates the restarts of the containers of a workload with their probes, to suggest
+// the probe settings which may cause a restart loop
+func fillWorkloadProbeIssues(health *models.WorkloadHealth, w *models.Workload) {
+	for _, pod := range w.Pods {
+		for _, container := range pod.Containers {
+			if issue := probeIssue(container); issue != nil {
+				health.ProbeIssues = append(health.ProbeIssues, models.PodProbeIssue{Pod: pod.Name, ProbeIssue: *issue})
+			}
+		}
+	}
+}
+
+// probeIssue returns the probe causes of the restarts of a container, nil when it isn't restarting or when its
+// restarts aren't related to its probes: killed for its memory, or crashing by itself
+func probeIssue(container *models.ContainerInfo) *models.ProbeIssue {
+	liveness := container.LivenessProbe
+	if container.Restarts < probeRestartsThreshold || liveness == nil {
+		return nil
+	}
+	if container.LastTerminationReason == "OOMKilled" || !probeKilledExitCodes[container.LastTerminationExitCode] {
+		return nil
+	}
+
+	suggestions := []string{}
+	if startup := container.StartupProbe; startup != nil {
+		if budget := probeFailureSeconds(startup); budget < probeStartupSeconds {
+			suggestions = append(suggestions, fmt.Sprintf("The startup probe fails the container after %ds: a slow starting container is restarted before being ready, raise its failureThreshold", budget))
+		}
+	} else if budget := probeFailureSeconds(liveness); budget < probeStartupSeconds {
+		suggestions = append(suggestions, fmt.Sprintf("The liveness probe fails the container after %ds, without startup probe: a slow starting container is restarted before being ready, add a startup probe or raise initialDelaySeconds", budget))
+	}
+	if liveness.FailureThreshold == 1 {
+		suggestions = append(suggestions, "The liveness probe restarts the container on its first failure: raise its failureThreshold")
+	}
+	if liveness.TimeoutSeconds <= 1 && liveness.Type != "tcpSocket" {
+		suggestions = append(suggestions, fmt.Sprintf("The liveness probe times out after %ds: a container slow under load is restarted, raise its timeoutSeconds", liveness.TimeoutSeconds))
+	}
+	if readiness := container.ReadinessProbe; readiness != nil && sameProbeCheck(liveness, readiness) {
+		suggestions = append(suggestions, "The liveness and readiness probes make the same check: a container failing because of its dependencies is restarted instead of being removed from the endpoints, make the liveness probe check the container only")
+	}
+	if len(suggestions) == 0 {
+		return nil
+	}
+	return &models.ProbeIssue{
+		Container:             container.Name,
+		Restarts:              container.Restarts,
+		LastTerminationReason: container.LastTerminationReason,
+		Suggestions:           suggestions,
+	}
+}
+
+// probeFailureSeconds is the time before the probe fails a container which doesn't respond since it started
+func probeFailureSeconds(probe *models.Probe) int32 {
+	return probe.InitialDelaySeconds + probe.PeriodSeconds*probe.FailureThreshold
+}
+
+func sameProbeCheck(a, b *models.Probe) bool {
+	return a.Type == b.Type && a.Path == b.Path && a.Port == b.Port && a.Command == b.Command
+}
+
+// GetWorkloadDependencyHealth returns a workload health along with the health of the workloads and services it sends
+// requests to. Dependencies are resolved from the outbound traffic of the workload and bounded to one hop: the health of a
+// dependency only accounts for its replicas and its inbound requests, not for its own dependencies.
+func (in *HealthService) GetWorkloadDependencyHealth(namespace, workload, workloadType, rateInterval string, queryTime time.Time) (models.WorkloadDependencyHealth, error) {
+	var err error
+	promtimer := internalmetrics.GetGoFunctionMetric("business", "HealthService", "GetWorkloadDependencyHealth")
+	defer promtimer.ObserveNow(&err)
+
+	health, outbound, err := in.getWorkloadHealth(namespace, workload, workloadType, rateInterval, queryTime)
+	if err != nil {
+		return models.WorkloadDependencyHealth{}, err
+	}
+
+	dependencyHealth := models.WorkloadDependencyHealth{
+		Health: health,
+		Status: models.WorstHealthStatus(
+			health.WorkloadStatus.Status(),
+			models.RequestsStatus(health.Requests.Inbound, "inbound", namespace, "workload", workload),
+			models.RequestsStatus(health.Requests.Outbound, "outbound", namespace, "workload", workload),
+		),
+		DependencyStatus: models.HealthStatusNA,
+		Dependencies:     buildDependencies(namespace, workload, outbound),
+	}
+	if health.Status == models.HealthStatusMaintenance {
+		dependencyHealth.Status = models.HealthStatusMaintenance
+	}
+
+	var wg sync.WaitGroup
+	for i := range dependencyHealth.Dependencies {
+		dep := &dependencyHealth.Dependencies[i]
+		dep.Status = models.RequestsStatus(dep.Requests, "outbound", namespace, "workload", workload)
+		if dep.Kind != "workload" {
+			continue
+		}
+		wg.Add(1)
+		go func(dep *models.DependencyHealth) {
+			defer wg.Done()
+			depHealth, _, depErr := in.getWorkloadHealth(dep.Namespace, dep.Name, "", rateInterval, queryTime)
+			if depErr != nil {
+				// The dependency may live in a namespace not accessible to the user, keep the status of the requests sent to it
+				log.Debugf("Unable to fetch the health of dependency %s/%s: %v", dep.Namespace, dep.Name, depErr)
+				return
+			}
+			dep.Health = &depHealth
+			if depHealth.Status == models.HealthStatusMaintenance {
+				// The errors of the requests sent to a dependency in maintenance are expected
+				dep.Status = models.HealthStatusMaintenance
+				return
+			}
+			dep.Status = models.WorstHealthStatus(dep.Status, depHealth.WorkloadStatus.Status(),
+				models.RequestsStatus(depHealth.Requests.Inbound, "inbound", dep.Namespace, "workload", dep.Name))
+		}(dep)
+	}
+	wg.Wait()
+
+	for _, dep := range dependencyHealth.Dependencies {
+		dependencyHealth.DependencyStatus = models.WorstHealthStatus(dependencyHealth.DependencyStatus, dep.Status)
+	}
+
+	return dependencyHealth, nil
+}
+
+// buildDependencies groups the outbound request rates of a workload by destination workload. Destinations without
+// workload (i.e. ServiceEntries) are grouped by destination service.
+func buildDependencies(namespace, workload string, outbound model.Vector) []models.DependencyHealth {
+	type dependencyKey struct {
+		namespace, name, kind string
+	}
+	requests := map[dependencyKey]*models.RequestHealth{}
+	for _, sample := range outbound {
+		key := dependencyKey{
+			namespace: string(sample.Metric["destination_workload_namespace"]),
+			name:      string(sample.Metric["destination_workload"]),
+			kind:      "workload",
+		}
+		if key.name == "" || key.name == "unknown" {
+			key = dependencyKey{
+				namespace: string(sample.Metric["destination_service_namespace"]),
+				name:      string(sample.Metric["destination_service_name"]),
+				kind:      "service",
+			}
+		}
+		if key.name == "" || key.name == "unknown" || (key.kind == "workload" && key.namespace == namespace && key.name == workload) {
+			continue
+		}
+		if _, ok := requests[key]; !ok {
+			rqHealth := models.NewEmptyRequestHealth()
+			requests[key] = &rqHealth
+		}
+		requests[key].AggregateOutbound(sample)
+	}
+
+	dependencies := make([]models.DependencyHealth, 0, len(requests))
+	for key, rqHealth := range requests {
+		dependencies = append(dependencies, models.DependencyHealth{
+			Namespace: key.namespace,
+			Name:      key.name,
+			Kind:      key.kind,
+			Requests:  rqHealth.Outbound,
+		})
+	}
+	sort.Slice(dependencies, func(i, j int) bool {
+		if dependencies[i].Namespace != dependencies[j].Namespace {
+			return dependencies[i].Namespace < dependencies[j].Namespace
+		}
+		if dependencies[i].Name != dependencies[j].Name {
+			return dependencies[i].Name < dependencies[j].Name
+		}
+		return dependencies[i].Kind < dependencies[j].Kind
+	})
+	return dependencies
 }
 
 // GetNamespaceAppHealth returns a health for all apps in given Namespace (thus, it fetches data from K8S and Prometheus)
@@ -208,6 +427,39 @@ func (in *HealthService) getNamespaceServiceHealth(namespace string, services []
 	for _, health := range allHealth {
 		health.Requests.CombineReporters()
 	}
+
+	// Fetch the connections of the tcp services, if any
+	tcpServices := false
+	for _, service := range services {
+		health := allHealth[service.Name]
+		health.Protocol = models.ServiceProtocol(service.Spec.Ports)
+		if health.Protocol == models.TCPProtocol {
+			health.Connections = &models.ConnectionHealth{}
+			tcpServices = true
+		}
+	}
+	if tcpServices {
+		lb := NewMetricsLabelsBuilder("inbound")
+		lb.SelfReporter()
+		lb.Add("destination_service_namespace", namespace)
+		opened, closed, err := in.fetchConnectionRates(lb.Build(), "destination_service_name", rateInterval, queryTime)
+		if err != nil {
+			log.Errorf("Error fetching tcp connections of namespace %s: %s", namespace, err)
+		}
+		for _, sample := range opened {
+			if health, ok := allHealth[string(sample.Metric[lblDestSvc])]; ok && health.Connections != nil {
+				health.Connections.AggregateConnections(sample, true)
+			}
+		}
+		for _, sample := range closed {
+			if health, ok := allHealth[string(sample.Metric[lblDestSvc])]; ok && health.Connections != nil {
+				health.Connections.AggregateConnections(sample, false)
+			}
+		}
+	}
+	for _, health := range allHealth {
+		health.ComputeErrorRatio()
+	}
 	return allHealth
 }
 
@@ -234,6 +486,9 @@ func (in *HealthService) getNamespaceWorkloadHealth(namespace string, ws models.
 		allHealth[w.Name] = models.EmptyWorkloadHealth()
 		allHealth[w.Name].Requests.HealthAnnotations = models.GetHealthAnnotation(w.HealthAnnotations, HealthAnnotation)
 		allHealth[w.Name].WorkloadStatus = w.CastWorkloadStatus()
+		fillWorkloadMaintenance(allHealth[w.Name], w, queryTime)
+		fillWorkloadIstioInitIssues(allHealth[w.Name], w)
+		fillWorkloadProbeIssues(allHealth[w.Name], w)
 		if w.IstioSidecar {
 			hasSidecar = true
 		}
@@ -251,6 +506,105 @@ func (in *HealthService) getNamespaceWorkloadHealth(namespace string, ws models.
 	return allHealth, err
 }
 
+// GetNamespaceStaleWorkloads returns the workloads of the namespace without any inbound or outbound traffic
+// for the configured stale window. Workloads labeled as intentionally idle, without sidecar (so without
+// reliable metrics) or created within the window are not reported.
+func (in *HealthService) GetNamespaceStaleWorkloads(namespace string, queryTime time.Time) (models.StaleWorkloads, error) {
+	var err error
+	promtimer := internalmetrics.GetGoFunctionMetric("business", "HealthService", "GetNamespaceStaleWorkloads")
+	defer promtimer.ObserveNow(&err)
+
+	conf := config.Get().HealthConfig.StaleWorkloads
+	window, err := model.ParseDuration(conf.Window)
+	if err != nil {
+		return nil, fmt.Errorf("invalid stale workloads window [%s]: %v", conf.Window, err)
+	}
+	history, err := model.ParseDuration(conf.History)
+	if err != nil {
+		return nil, fmt.Errorf("invalid stale workloads history [%s]: %v", conf.History, err)
+	}
+	if history < window {
+		history = window
+	}
+
+	ws, err := fetchWorkloads(in.businessLayer, namespace, "")
+	if err != nil {
+		return nil, err
+	}
+
+	windowStart := queryTime.Add(-time.Duration(window))
+	candidates := models.Workloads{}
+	for _, w := range ws {
+		if !w.IstioSidecar || (conf.IdleLabel != "" && w.Labels[conf.IdleLabel] == "true") {
+			continue
+		}
+		if createdAt, parseErr := time.Parse(time.RFC3339, w.CreatedAt); parseErr == nil && createdAt.After(windowStart) {
+			continue
+		}
+		candidates = append(candidates, w)
+	}
+
+	stale := models.StaleWorkloads{}
+	if len(candidates) == 0 {
+		return stale, nil
+	}
+
+	// Perf: bound the number of points of the history, using one minute steps at least
+	step := time.Duration(history) / staleWorkloadsHistoryPoints
+	if step < time.Minute {
+		step = time.Minute
+	}
+	inbound, outbound, err := in.prom.GetWorkloadsTrafficHistory(namespace, time.Duration(history), step, queryTime)
+	if err != nil {
+		return nil, err
+	}
+	present := make(map[string]bool)
+	lastSeen := make(map[string]time.Time)
+	fillWorkloadsLastSeen(present, lastSeen, inbound, "destination_workload")
+	fillWorkloadsLastSeen(present, lastSeen, outbound, "source_workload")
+
+	for _, w := range candidates {
+		if !present[w.Name] {
+			stale = append(stale, models.StaleWorkload{Name: w.Name, Type: w.Type, Reason: models.StaleNeverHadTraffic})
+			continue
+		}
+		seen, found := lastSeen[w.Name]
+		if found && !seen.Before(windowStart) {
+			continue
+		}
+		staleWorkload := models.StaleWorkload{Name: w.Name, Type: w.Type, Reason: models.StaleWentSilent}
+		if found {
+			staleWorkload.LastSeen = &seen
+		}
+		stale = append(stale, staleWorkload)
+	}
+	return stale, nil
+}
+
+// Number of points requested per workload when looking for the last traffic seen
+const staleWorkloadsHistoryPoints = 168
+
+// fillWorkloadsLastSeen flags the workloads with series in the traffic history and stores the time
+// of the last point with traffic, if any
+func fillWorkloadsLastSeen(present map[string]bool, lastSeen map[string]time.Time, history model.Matrix, workloadLabel model.LabelName) {
+	for _, series := range history {
+		name := string(series.Metric[workloadLabel])
+		if name == "" {
+			continue
+		}
+		present[name] = true
+		for i := len(series.Values) - 1; i >= 0; i-- {
+			if series.Values[i].Value > 0 {
+				seen := series.Values[i].Timestamp.Time().UTC()
+				if seen.After(lastSeen[name]) {
+					lastSeen[name] = seen
+				}
+				break
+			}
+		}
+	}
+}
+
 // fillAppRequestRates aggregates requests rates from metrics fetched from Prometheus, and stores the result in the health map.
 func fillAppRequestRates(allHealth models.NamespaceAppHealth, rates model.Vector) {
 	lblDest := model.LabelName("destination_canonical_service")
@@ -290,22 +644,61 @@ func fillWorkloadRequestRates(allHealth models.NamespaceWorkloadHealth, rates mo
 	}
 }
 
-func (in *HealthService) getServiceRequestsHealth(namespace, service, rateInterval string, queryTime time.Time) (models.RequestHealth, error) {
-	rqHealth := models.NewEmptyRequestHealth()
+// getServiceHealth computes the health of the service according to its protocol: the requests for http and grpc
+// services, the connections for tcp services
+func (in *HealthService) getServiceHealth(namespace, service, rateInterval string, queryTime time.Time) (models.ServiceHealth, error) {
+	health := models.EmptyServiceHealth()
 	inbound, err := in.prom.GetServiceRequestRates(namespace, service, rateInterval, queryTime)
 	if err != nil {
-		return rqHealth, err
+		return health, err
 	}
 	for _, sample := range inbound {
-		rqHealth.AggregateInbound(sample)
+		health.Requests.AggregateInbound(sample)
 	}
 	svc, err := in.businessLayer.Svc.getService(namespace, service)
 	if err != nil {
-		return rqHealth, err
+		return health, err
 	}
-	rqHealth.HealthAnnotations = models.GetHealthAnnotation(svc.Annotations, HealthAnnotation)
-	rqHealth.CombineReporters()
-	return rqHealth, err
+	health.Requests.HealthAnnotations = models.GetHealthAnnotation(svc.Annotations, HealthAnnotation)
+	health.Requests.CombineReporters()
+
+	health.Protocol = models.ServiceProtocol(svc.Spec.Ports)
+	if health.Protocol == models.TCPProtocol {
+		lb := NewMetricsLabelsBuilder("inbound")
+		lb.SelfReporter()
+		lb.Service(service, namespace)
+		opened, closed, err := in.fetchConnectionRates(lb.Build(), "", rateInterval, queryTime)
+		if err != nil {
+			return health, err
+		}
+		health.Connections = &models.ConnectionHealth{}
+		for _, sample := range opened {
+			health.Connections.AggregateConnections(sample, true)
+		}
+		for _, sample := range closed {
+			health.Connections.AggregateConnections(sample, false)
+		}
+	}
+	health.ComputeErrorRatio()
+	return health, nil
+}
+
+// fetchConnectionRates returns the rates of the opened and closed tcp connections. Closed connections are also
+// grouped by response flags, to tell the failed ones.
+func (in *HealthService) fetchConnectionRates(labels, grouping, rateInterval string, queryTime time.Time) (model.Vector, model.Vector, error) {
+	opened, err := in.prom.FetchRateValues("istio_tcp_connections_opened_total", labels, grouping, rateInterval, queryTime)
+	if err != nil {
+		return nil, nil, err
+	}
+	closedGrouping := "response_flags"
+	if grouping != "" {
+		closedGrouping = grouping + "," + closedGrouping
+	}
+	closed, err := in.prom.FetchRateValues("istio_tcp_connections_closed_total", labels, closedGrouping, rateInterval, queryTime)
+	if err != nil {
+		return nil, nil, err
+	}
+	return opened, closed, nil
 }
 
 func (in *HealthService) getAppRequestsHealth(namespace, app, rateInterval string, queryTime time.Time) (models.RequestHealth, error) {
@@ -321,11 +714,11 @@ func (in *HealthService) getAppRequestsHealth(namespace, app, rateInterval strin
 	return rqHealth, err
 }
 
-func (in *HealthService) getWorkloadRequestsHealth(namespace, workload, rateInterval string, queryTime time.Time) (models.RequestHealth, error) {
+func (in *HealthService) getWorkloadRequestsHealth(namespace, workload, rateInterval string, queryTime time.Time) (models.RequestHealth, model.Vector, error) {
 	rqHealth := models.NewEmptyRequestHealth()
 	inbound, outbound, err := in.prom.GetWorkloadRequestRates(namespace, workload, rateInterval, queryTime)
 	if err != nil {
-		return rqHealth, err
+		return rqHealth, outbound, err
 	}
 	for _, sample := range inbound {
 		rqHealth.AggregateInbound(sample)
@@ -335,11 +728,11 @@ func (in *HealthService) getWorkloadRequestsHealth(namespace, workload, rateInte
 	}
 	w, err := in.businessLayer.Workload.GetWorkload(namespace, workload, "", false)
 	if err != nil {
-		return rqHealth, err
+		return rqHealth, outbound, err
 	}
 	if len(w.Pods) > 0 {
 		rqHealth.HealthAnnotations = models.GetHealthAnnotation(w.HealthAnnotations, HealthAnnotation)
 	}
 	rqHealth.CombineReporters()
-	return rqHealth, err
+	return rqHealth, outbound, err
 }
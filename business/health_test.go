@@ -1,6 +1,9 @@
 package business
 
 import (
+	"encoding/json"
+	"io/ioutil"
+	"strings"
 	"testing"
 	"time"
 
@@ -11,6 +14,7 @@ import (
 	apps_v1 "k8s.io/api/apps/v1"
 	core_v1 "k8s.io/api/core/v1"
 	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/yaml"
 
 	"github.com/kiali/kiali/config"
 	"github.com/kiali/kiali/kubernetes"
@@ -55,6 +59,110 @@ func TestGetServiceHealth(t *testing.T) {
 	assert.Equal(emptyResult, health.Requests.Outbound)
 }
 
+func TestGetServiceHealthGRPC(t *testing.T) {
+	assert := assert.New(t)
+
+	k8s := new(kubetest.K8SClientMock)
+	prom := new(prometheustest.PromClientMock)
+	conf := config.NewConfig()
+	config.Set(conf)
+
+	queryTime := time.Date(2017, 01, 15, 0, 0, 0, 0, time.UTC)
+	prom.MockServiceRequestRates("ns", "httpbin", serviceRates)
+	k8s.On("IsOpenShift").Return(true)
+	k8s.On("GetProject", mock.AnythingOfType("string")).Return(&osproject_v1.Project{}, nil)
+	k8s.On("GetService", "ns", "httpbin").Return(&core_v1.Service{
+		Spec: core_v1.ServiceSpec{Ports: []core_v1.ServicePort{{Name: "http-admin", Port: 8000}, {Name: "grpc-web", Port: 9000}}},
+	}, nil)
+
+	hs := HealthService{k8s: k8s, prom: prom, businessLayer: NewWithBackends(k8s, prom, nil)}
+
+	health, err := hs.GetServiceHealth("ns", "httpbin", "1m", queryTime)
+	assert.NoError(err)
+
+	assert.Equal(models.GRPCProtocol, health.Protocol)
+	assert.Nil(health.Connections)
+	// Only the grpc requests count: 1.4 requests with status 7 out of 15.4
+	assert.InDelta(1.4/15.4, *health.ErrorRatio, 0.0001)
+	prom.AssertNotCalled(t, "FetchRateValues", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestGetServiceHealthTCP(t *testing.T) {
+	assert := assert.New(t)
+
+	k8s := new(kubetest.K8SClientMock)
+	prom := new(prometheustest.PromClientMock)
+	conf := config.NewConfig()
+	config.Set(conf)
+
+	queryTime := time.Date(2017, 01, 15, 0, 0, 0, 0, time.UTC)
+	prom.MockServiceRequestRates("ns", "mongodb", model.Vector{})
+	labels := `{reporter="destination",destination_service_name="mongodb",destination_service_namespace="ns"}`
+	prom.On("FetchRateValues", "istio_tcp_connections_opened_total", labels, "", "1m", queryTime).
+		Return(model.Vector{&model.Sample{Value: 10}}, nil)
+	prom.On("FetchRateValues", "istio_tcp_connections_closed_total", labels, "response_flags", "1m", queryTime).
+		Return(model.Vector{
+			&model.Sample{Metric: model.Metric{"response_flags": "-"}, Value: 6},
+			&model.Sample{Metric: model.Metric{"response_flags": "UF"}, Value: 2},
+		}, nil)
+	k8s.On("IsOpenShift").Return(true)
+	k8s.On("GetProject", mock.AnythingOfType("string")).Return(&osproject_v1.Project{}, nil)
+	k8s.On("GetService", "ns", "mongodb").Return(&core_v1.Service{
+		Spec: core_v1.ServiceSpec{Ports: []core_v1.ServicePort{{Name: "mongo", Port: 27017}}},
+	}, nil)
+
+	hs := HealthService{k8s: k8s, prom: prom, businessLayer: NewWithBackends(k8s, prom, nil)}
+
+	health, err := hs.GetServiceHealth("ns", "mongodb", "1m", queryTime)
+	assert.NoError(err)
+
+	assert.Equal(models.TCPProtocol, health.Protocol)
+	assert.Equal(&models.ConnectionHealth{Opened: 10, Closed: 8, Failed: 2}, health.Connections)
+	assert.Equal(0.25, *health.ErrorRatio)
+	assert.Equal(emptyResult, health.Requests.Inbound)
+}
+
+func TestGetNamespaceServiceHealthTCP(t *testing.T) {
+	assert := assert.New(t)
+
+	k8s := new(kubetest.K8SClientMock)
+	prom := new(prometheustest.PromClientMock)
+	conf := config.NewConfig()
+	config.Set(conf)
+
+	queryTime := time.Date(2017, 01, 15, 0, 0, 0, 0, time.UTC)
+	labels := `{reporter="destination",destination_service_namespace="tutorial"}`
+	prom.On("GetNamespaceServicesRequestRates", "tutorial", "1m", queryTime).Return(serviceRates, nil)
+	prom.On("FetchRateValues", "istio_tcp_connections_opened_total", labels, "destination_service_name", "1m", queryTime).
+		Return(model.Vector{&model.Sample{Metric: model.Metric{"destination_service_name": "mysql"}, Value: 4}}, nil)
+	prom.On("FetchRateValues", "istio_tcp_connections_closed_total", labels, "destination_service_name,response_flags", "1m", queryTime).
+		Return(model.Vector{&model.Sample{Metric: model.Metric{"destination_service_name": "mysql", "response_flags": "-"}, Value: 4}}, nil)
+	k8s.On("IsOpenShift").Return(true)
+	k8s.On("GetProject", mock.AnythingOfType("string")).Return(&osproject_v1.Project{}, nil)
+	k8s.On("GetServices", "tutorial", mock.Anything).Return([]core_v1.Service{
+		{
+			ObjectMeta: meta_v1.ObjectMeta{Name: "httpbin", Namespace: "tutorial"},
+			Spec:       core_v1.ServiceSpec{Ports: []core_v1.ServicePort{{Name: "http", Port: 8000}}},
+		},
+		{
+			ObjectMeta: meta_v1.ObjectMeta{Name: "mysql", Namespace: "tutorial"},
+			Spec:       core_v1.ServiceSpec{Ports: []core_v1.ServicePort{{Name: "tcp-mysql", Port: 3306}}},
+		},
+	}, nil)
+
+	hs := HealthService{k8s: k8s, prom: prom, businessLayer: NewWithBackends(k8s, prom, nil)}
+
+	health, err := hs.GetNamespaceServiceHealth("tutorial", "1m", queryTime)
+	assert.NoError(err)
+
+	assert.Equal(models.HTTPProtocol, health["httpbin"].Protocol)
+	assert.Nil(health["httpbin"].Connections)
+	assert.NotNil(health["httpbin"].ErrorRatio)
+	assert.Equal(models.TCPProtocol, health["mysql"].Protocol)
+	assert.Equal(&models.ConnectionHealth{Opened: 4, Closed: 4}, health["mysql"].Connections)
+	assert.Equal(0.0, *health["mysql"].ErrorRatio)
+}
+
 func TestGetAppHealth(t *testing.T) {
 	assert := assert.New(t)
 
@@ -142,6 +250,118 @@ func TestGetWorkloadHealth(t *testing.T) {
 	assert.Equal(result, health.Requests.Outbound)
 }
 
+func TestGetWorkloadHealthIstioInitIssues(t *testing.T) {
+	assert := assert.New(t)
+
+	k8s := new(kubetest.K8SClientMock)
+	prom := new(prometheustest.PromClientMock)
+	conf := config.NewConfig()
+	config.Set(conf)
+
+	k8s.On("IsOpenShift").Return(true)
+	// The deployment is found, the other workload types are not
+	k8s.On("GetDeployment", "ns", "reviews-v1").Return(&fakeDeploymentsHealthReview()[0], nil)
+	k8s.MockEmptyWorkload("ns", "reviews-v1")
+	k8s.On("GetProject", mock.AnythingOfType("string")).Return(&osproject_v1.Project{}, nil)
+	k8s.On("GetPods", "ns", "").Return(loadPods(t, "../tests/data/health/istio_init_pods.yaml"), nil)
+	k8s.On("GetNode", mock.AnythingOfType("string")).Return(&core_v1.Node{}, nil)
+	k8s.On("GetProxyStatus").Return([]*kubernetes.ProxyStatus{}, nil)
+
+	queryTime := time.Date(2017, 01, 15, 0, 0, 0, 0, time.UTC)
+	prom.MockWorkloadRequestRates("ns", "reviews-v1", otherRatesIn, otherRatesOut)
+
+	hs := HealthService{k8s: k8s, prom: prom, businessLayer: NewWithBackends(k8s, prom, nil)}
+	health, err := hs.GetWorkloadHealth("ns", "reviews-v1", "", "1m", queryTime)
+	assert.NoError(err)
+
+	// The initializing and running pods have no issue
+	issues := health.IstioInitIssues
+	assert.Len(issues, 3)
+
+	assert.Equal("reviews-v1-7f99cc4496-gdxfn", issues[0].Pod)
+	assert.Equal(models.IstioInitContainerName, issues[0].Container)
+	assert.Equal("CrashLoopBackOff", issues[0].Reason)
+	assert.Equal(int32(1), issues[0].ExitCode)
+	assert.Equal(int32(5), issues[0].Restarts)
+	assert.Contains(issues[0].Detail, "Permission denied")
+	assert.Contains(issues[0].Message, "NET_ADMIN")
+
+	assert.Equal("reviews-v1-7f99cc4496-k2x8p", issues[1].Pod)
+	assert.Equal(models.IstioValidationContainerName, issues[1].Container)
+	assert.Equal("Error", issues[1].Reason)
+	assert.Equal(int32(126), issues[1].ExitCode)
+	assert.Contains(issues[1].Message, "istio-cni-node")
+
+	assert.Equal("reviews-v1-7f99cc4496-w4mzq", issues[2].Pod)
+	assert.Empty(issues[2].Container)
+	assert.Equal(models.IstioCNIUninitializedReason, issues[2].Reason)
+	assert.Contains(issues[2].Message, "worker-3")
+}
+
+func TestGetWorkloadHealthProbeIssues(t *testing.T) {
+	assert := assert.New(t)
+
+	k8s := new(kubetest.K8SClientMock)
+	prom := new(prometheustest.PromClientMock)
+	conf := config.NewConfig()
+	config.Set(conf)
+
+	k8s.On("IsOpenShift").Return(true)
+	// The deployment is found, the other workload types are not
+	k8s.On("GetDeployment", "ns", "reviews-v1").Return(&fakeDeploymentsHealthReview()[0], nil)
+	k8s.MockEmptyWorkload("ns", "reviews-v1")
+	k8s.On("GetProject", mock.AnythingOfType("string")).Return(&osproject_v1.Project{}, nil)
+	k8s.On("GetPods", "ns", "").Return(loadPods(t, "../tests/data/health/probe_pods.yaml"), nil)
+	k8s.On("GetNode", mock.AnythingOfType("string")).Return(&core_v1.Node{}, nil)
+	k8s.On("GetProxyStatus").Return([]*kubernetes.ProxyStatus{}, nil)
+
+	queryTime := time.Date(2017, 01, 15, 0, 0, 0, 0, time.UTC)
+	prom.MockWorkloadRequestRates("ns", "reviews-v1", otherRatesIn, otherRatesOut)
+
+	hs := HealthService{k8s: k8s, prom: prom, businessLayer: NewWithBackends(k8s, prom, nil)}
+	health, err := hs.GetWorkloadHealth("ns", "reviews-v1", "", "1m", queryTime)
+	assert.NoError(err)
+
+	// The container killed for its memory, and the one with a startup probe, have no probe issue
+	issues := health.ProbeIssues
+	assert.Len(issues, 1)
+
+	assert.Equal("reviews-v1-7f99cc4496-gdxfn", issues[0].Pod)
+	assert.Equal("reviews", issues[0].Container)
+	assert.Equal(int32(12), issues[0].Restarts)
+	assert.Equal("Error", issues[0].LastTerminationReason)
+	assert.Len(issues[0].Suggestions, 4)
+	assert.Contains(issues[0].Suggestions[0], "after 10s, without startup probe")
+	assert.Contains(issues[0].Suggestions[1], "first failure")
+	assert.Contains(issues[0].Suggestions[2], "times out after 1s")
+	assert.Contains(issues[0].Suggestions[3], "same check")
+}
+
+func TestProbeIssueStartupProbe(t *testing.T) {
+	assert := assert.New(t)
+
+	container := &models.ContainerInfo{
+		Name:                    "reviews",
+		LivenessProbe:           &models.Probe{Type: "tcpSocket", Port: "9080", TimeoutSeconds: 1, PeriodSeconds: 10, SuccessThreshold: 1, FailureThreshold: 3},
+		StartupProbe:            &models.Probe{Type: "tcpSocket", Port: "9080", TimeoutSeconds: 1, PeriodSeconds: 2, SuccessThreshold: 1, FailureThreshold: 5},
+		Restarts:                3,
+		LastTerminationReason:   "Error",
+		LastTerminationExitCode: 137,
+	}
+	issue := probeIssue(container)
+	assert.NotNil(issue)
+	assert.Equal([]string{"The startup probe fails the container after 10s: a slow starting container is restarted before being ready, raise its failureThreshold"}, issue.Suggestions)
+
+	// Crashing by itself
+	container.LastTerminationExitCode = 1
+	assert.Nil(probeIssue(container))
+
+	// Not restarting in a loop
+	container.LastTerminationExitCode = 137
+	container.Restarts = 2
+	assert.Nil(probeIssue(container))
+}
+
 func TestGetAppHealthWithoutIstio(t *testing.T) {
 	assert := assert.New(t)
 
@@ -401,6 +621,108 @@ var (
 //						Port:     3000}}}}}
 //}
 
+func TestGetNamespaceStaleWorkloads(t *testing.T) {
+	assert := assert.New(t)
+
+	// Setup mocks
+	k8s := new(kubetest.K8SClientMock)
+	prom := new(prometheustest.PromClientMock)
+	conf := config.NewConfig()
+	config.Set(conf)
+
+	queryTime := time.Date(2017, 01, 15, 0, 0, 0, 0, time.UTC)
+	old := queryTime.Add(-30 * 24 * time.Hour)
+
+	k8s.On("IsOpenShift").Return(true)
+	k8s.MockEmptyWorkloads("ns")
+	k8s.On("GetProject", mock.AnythingOfType("string")).Return(&osproject_v1.Project{}, nil)
+	k8s.On("GetPods", "ns", mock.AnythingOfType("string")).Return([]core_v1.Pod{
+		fakeStalePod("ratings-v1", map[string]string{"app": "ratings"}, old),
+		fakeStalePod("reviews-v1", map[string]string{"app": "reviews", "version": "v1"}, old),
+		fakeStalePod("reviews-v2", map[string]string{"app": "reviews", "version": "v2"}, old),
+		fakeStalePod("productpage-v1", map[string]string{"app": "productpage"}, old),
+		fakeStalePod("details-v1", map[string]string{"app": "details", "kiali.io/idle": "true"}, old),
+		fakeStalePod("new-v1", map[string]string{"app": "new"}, queryTime.Add(-time.Hour)),
+	}, nil)
+	k8s.On("GetProxyStatus").Return([]*kubernetes.ProxyStatus{}, nil)
+
+	threeDaysAgo := queryTime.Add(-72 * time.Hour)
+	// Recorded responses: reviews-v1 received traffic 3 days ago, reviews-v2 sends traffic,
+	// productpage-v1 counters exist but they didn't increase within the history
+	inbound := model.Matrix{
+		trafficHistory("destination_workload", "reviews-v1", queryTime, map[time.Time]float64{threeDaysAgo: 12, threeDaysAgo.Add(-time.Hour): 3}),
+		trafficHistory("destination_workload", "productpage-v1", queryTime, nil),
+		trafficHistory("destination_workload", "unknown-v1", queryTime, map[time.Time]float64{queryTime: 1}),
+	}
+	outbound := model.Matrix{
+		trafficHistory("source_workload", "reviews-v2", queryTime, map[time.Time]float64{queryTime.Add(-2 * time.Hour): 5}),
+		trafficHistory("source_workload", "reviews-v1", queryTime, nil),
+	}
+	prom.On("GetWorkloadsTrafficHistory", "ns", 7*24*time.Hour, time.Hour, queryTime).Return(inbound, outbound, nil)
+
+	hs := HealthService{k8s: k8s, prom: prom, businessLayer: NewWithBackends(k8s, prom, nil)}
+
+	stale, err := hs.GetNamespaceStaleWorkloads("ns", queryTime)
+	assert.NoError(err)
+	prom.AssertNumberOfCalls(t, "GetWorkloadsTrafficHistory", 1)
+
+	byName := make(map[string]models.StaleWorkload)
+	for _, w := range stale {
+		byName[w.Name] = w
+	}
+	assert.Len(stale, 3)
+	assert.Equal(models.StaleNeverHadTraffic, byName["ratings-v1"].Reason)
+	assert.Nil(byName["ratings-v1"].LastSeen)
+	assert.Equal(models.StaleWentSilent, byName["reviews-v1"].Reason)
+	assert.Equal(threeDaysAgo, *byName["reviews-v1"].LastSeen)
+	assert.Equal(models.StaleWentSilent, byName["productpage-v1"].Reason)
+	assert.Nil(byName["productpage-v1"].LastSeen)
+	assert.Equal("Pod", byName["productpage-v1"].Type)
+	assert.NotContains(byName, "reviews-v2")
+	assert.NotContains(byName, "details-v1")
+	assert.NotContains(byName, "new-v1")
+}
+
+func TestGetNamespaceStaleWorkloadsWithoutIstio(t *testing.T) {
+	assert := assert.New(t)
+
+	// Setup mocks
+	k8s := new(kubetest.K8SClientMock)
+	prom := new(prometheustest.PromClientMock)
+	conf := config.NewConfig()
+	config.Set(conf)
+
+	k8s.On("IsOpenShift").Return(true)
+	k8s.MockEmptyWorkloads("ns")
+	k8s.On("GetProject", mock.AnythingOfType("string")).Return(&osproject_v1.Project{}, nil)
+	k8s.On("GetDeployments", "ns").Return(fakeDeploymentsHealthReview(), nil)
+	k8s.On("GetPods", "ns", mock.AnythingOfType("string")).Return(fakePodsHealthReviewWithoutIstio(), nil)
+
+	hs := HealthService{k8s: k8s, prom: prom, businessLayer: NewWithBackends(k8s, prom, nil)}
+
+	stale, err := hs.GetNamespaceStaleWorkloads("ns", time.Date(2017, 01, 15, 0, 0, 0, 0, time.UTC))
+	assert.NoError(err)
+	assert.Empty(stale)
+
+	// Make sure unnecessary call isn't performed
+	prom.AssertNumberOfCalls(t, "GetWorkloadsTrafficHistory", 0)
+}
+
+func TestGetNamespaceStaleWorkloadsInvalidConfig(t *testing.T) {
+	k8s := new(kubetest.K8SClientMock)
+	prom := new(prometheustest.PromClientMock)
+	conf := config.NewConfig()
+	conf.HealthConfig.StaleWorkloads.Window = "one day"
+	config.Set(conf)
+
+	k8s.On("IsOpenShift").Return(true)
+	hs := HealthService{k8s: k8s, prom: prom, businessLayer: NewWithBackends(k8s, prom, nil)}
+
+	_, err := hs.GetNamespaceStaleWorkloads("ns", time.Now())
+	assert.Error(t, err)
+	k8s.AssertNotCalled(t, "GetDeployments", mock.Anything)
+}
+
 func fakePodsHealthReview() []core_v1.Pod {
 	return []core_v1.Pod{
 		{
@@ -420,6 +742,26 @@ func fakePodsHealthReview() []core_v1.Pod {
 	}
 }
 
+func loadPods(t *testing.T, path string) []core_v1.Pod {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Error loading test data: %v", err)
+	}
+	pods := []core_v1.Pod{}
+	for _, doc := range strings.Split(string(content), "\n---\n") {
+		js, err := yaml.ToJSON([]byte(doc))
+		if err != nil {
+			t.Fatalf("Error parsing test data: %v", err)
+		}
+		pod := core_v1.Pod{}
+		if err := json.Unmarshal(js, &pod); err != nil {
+			t.Fatalf("Error parsing test data: %v", err)
+		}
+		pods = append(pods, pod)
+	}
+	return pods
+}
+
 func fakePodsHealthReviewWithoutIstio() []core_v1.Pod {
 	return []core_v1.Pod{
 		{
@@ -460,3 +802,144 @@ func fakeDeploymentsHealthReview() []apps_v1.Deployment {
 				Selector: &meta_v1.LabelSelector{
 					MatchLabels: map[string]string{"app": "reviews", "version": "v2"}}}}}
 }
+
+func fakeStalePod(name string, labels map[string]string, created time.Time) core_v1.Pod {
+	return core_v1.Pod{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:              name,
+			CreationTimestamp: meta_v1.NewTime(created),
+			Labels:            labels,
+			Annotations:       kubetest.FakeIstioAnnotations(),
+		},
+	}
+}
+
+// trafficHistory builds a week of hourly requests increase for the workload, zero unless set in traffic
+func trafficHistory(workloadLabel, workload string, queryTime time.Time, traffic map[time.Time]float64) *model.SampleStream {
+	series := &model.SampleStream{
+		Metric: model.Metric{model.LabelName(workloadLabel): model.LabelValue(workload)},
+	}
+	for ts := queryTime.Add(-7 * 24 * time.Hour); !ts.After(queryTime); ts = ts.Add(time.Hour) {
+		series.Values = append(series.Values, model.SamplePair{
+			Timestamp: model.TimeFromUnixNano(ts.UnixNano()),
+			Value:     model.SampleValue(traffic[ts]),
+		})
+	}
+	return series
+}
+
+func TestGetWorkloadDependencyHealth(t *testing.T) {
+	assert := assert.New(t)
+
+	// Setup mocks
+	k8s := new(kubetest.K8SClientMock)
+	prom := new(prometheustest.PromClientMock)
+	conf := config.NewConfig()
+	config.Set(conf)
+
+	k8s.On("IsOpenShift").Return(true)
+	k8s.On("GetProject", mock.AnythingOfType("string")).Return(&osproject_v1.Project{}, nil)
+	k8s.On("GetDeployment", "ns", "productpage-v1").Return(&fakeDeploymentsDependencyHealth()[0], nil)
+	k8s.On("GetDeployment", "ns", "reviews-v1").Return(&fakeDeploymentsDependencyHealth()[1], nil)
+	k8s.On("GetDeployment", "ns", "details-v1").Return(&fakeDeploymentsDependencyHealth()[2], nil)
+	k8s.MockEmptyWorkload("ns", "productpage-v1")
+	k8s.MockEmptyWorkload("ns", "reviews-v1")
+	k8s.MockEmptyWorkload("ns", "details-v1")
+	k8s.On("GetPods", "ns", "").Return(fakePodsDependencyHealth(), nil)
+	k8s.On("GetProxyStatus").Return([]*kubernetes.ProxyStatus{}, nil)
+
+	queryTime := time.Date(2017, 01, 15, 0, 0, 0, 0, time.UTC)
+	// Edges of the graph: productpage sends requests to reviews, details and an external service
+	prom.MockWorkloadRequestRates("ns", "productpage-v1", model.Vector{}, model.Vector{
+		dependencySample("reviews-v1", "reviews", "200", 19),
+		dependencySample("reviews-v1", "reviews", "503", 1),
+		dependencySample("details-v1", "details", "200", 10),
+		dependencySample("unknown", "httpbin.org", "200", 4),
+	})
+	prom.MockWorkloadRequestRates("ns", "reviews-v1", model.Vector{
+		dependencySample("reviews-v1", "reviews", "200", 19),
+		dependencySample("reviews-v1", "reviews", "503", 1),
+	}, model.Vector{})
+	prom.MockWorkloadRequestRates("ns", "details-v1", model.Vector{
+		dependencySample("details-v1", "details", "200", 10),
+	}, model.Vector{})
+
+	hs := HealthService{k8s: k8s, prom: prom, businessLayer: NewWithBackends(k8s, prom, nil)}
+
+	health, err := hs.GetWorkloadDependencyHealth("ns", "productpage-v1", "", "1m", queryTime)
+	assert.NoError(err)
+
+	// Only one hop: dependencies of the dependencies are not resolved
+	prom.AssertNumberOfCalls(t, "GetWorkloadRequestRates", 3)
+	// The workload itself sees 5% of errors in its outbound requests
+	assert.Equal(models.HealthStatusDegraded, health.Status)
+	assert.Equal(models.HealthStatusDegraded, health.DependencyStatus)
+
+	assert.Len(health.Dependencies, 3)
+	details := health.Dependencies[0]
+	assert.Equal("details-v1", details.Name)
+	assert.Equal("workload", details.Kind)
+	assert.Equal(models.HealthStatusHealthy, details.Status)
+	assert.NotNil(details.Health)
+
+	httpbin := health.Dependencies[1]
+	assert.Equal("httpbin.org", httpbin.Name)
+	assert.Equal("service", httpbin.Kind)
+	assert.Equal(models.HealthStatusHealthy, httpbin.Status)
+	assert.Nil(httpbin.Health)
+	assert.Equal(map[string]map[string]float64{"http": {"200": 4}}, httpbin.Requests)
+
+	reviews := health.Dependencies[2]
+	assert.Equal("reviews-v1", reviews.Name)
+	assert.Equal("workload", reviews.Kind)
+	assert.Equal(models.HealthStatusDegraded, reviews.Status)
+	assert.Equal(map[string]map[string]float64{"http": {"200": 19, "503": 1}}, reviews.Requests)
+	assert.NotNil(reviews.Health)
+	assert.Equal(int32(3), reviews.Health.WorkloadStatus.AvailableReplicas)
+}
+
+func dependencySample(workload, service, code string, value float64) *model.Sample {
+	return &model.Sample{
+		Metric: model.Metric{
+			"destination_workload":           model.LabelValue(workload),
+			"destination_workload_namespace": "ns",
+			"destination_service_name":       model.LabelValue(service),
+			"destination_service_namespace":  "ns",
+			"request_protocol":               "http",
+			"response_code":                  model.LabelValue(code),
+			"reporter":                       "source",
+		},
+		Value:     model.SampleValue(value),
+		Timestamp: model.Now(),
+	}
+}
+
+func fakeDeploymentsDependencyHealth() []apps_v1.Deployment {
+	deployments := []apps_v1.Deployment{}
+	for _, app := range []string{"productpage", "reviews", "details"} {
+		deployments = append(deployments, apps_v1.Deployment{
+			ObjectMeta: meta_v1.ObjectMeta{
+				Name: app + "-v1"},
+			Status: apps_v1.DeploymentStatus{
+				Replicas:          3,
+				AvailableReplicas: 3},
+			Spec: apps_v1.DeploymentSpec{
+				Selector: &meta_v1.LabelSelector{
+					MatchLabels: map[string]string{"app": app, "version": "v1"}}}})
+	}
+	return deployments
+}
+
+func fakePodsDependencyHealth() []core_v1.Pod {
+	pods := []core_v1.Pod{}
+	for _, app := range []string{"productpage", "reviews", "details"} {
+		pods = append(pods, core_v1.Pod{
+			ObjectMeta: meta_v1.ObjectMeta{
+				Name:        app + "-v1-1234",
+				Labels:      map[string]string{"app": app, "version": "v1"},
+				Annotations: kubetest.FakeIstioAnnotations(),
+			},
+		})
+	}
+	return pods
+}
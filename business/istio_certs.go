@@ -0,0 +1,273 @@
+package business
+
+import (
+	"bytes"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+
+	core_v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/log"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/prometheus/internalmetrics"
+	"github.com/kiali/kiali/util"
+)
+
+// Where Istio stores the certificates of its CA, in the Istio namespace
+const (
+	istioRootCertConfigMap  = "istio-ca-root-cert"
+	istioPluggedCASecret    = "cacerts"
+	istioSelfSignedCASecret = "istio-ca-secret"
+	istioWorkloadSecretName = "default"
+)
+
+// IstioCertsService deals with the certificates of the Istio CA
+type IstioCertsService struct {
+	k8s           kubernetes.ClientInterface
+	businessLayer *Layer
+}
+
+// chainCertificate is a certificate of the chain with the place it was read from
+type chainCertificate struct {
+	cert   *x509.Certificate
+	source string
+}
+
+// GetCertificateChain returns the chain of trust of the mesh: the root certificate distributed to the namespaces,
+// the intermediate certificates of the CA when it is plugged in, and the certificate of a sample workload when a pod
+// is given. The certificate of the workload is read from the secrets of the config dump of its proxy, as the /certs
+// endpoint of the proxy admin reports neither the subject nor the issuer of the certificates.
+// Every certificate is checked to be issued and signed by the previous one, the breaks of the chain are reported.
+func (in *IstioCertsService) GetCertificateChain(namespace, pod string) (*models.CertificateChain, error) {
+	var err error
+	promtimer := internalmetrics.GetGoFunctionMetric("business", "IstioCertsService", "GetCertificateChain")
+	defer promtimer.ObserveNow(&err)
+
+	istioNamespace := config.Get().IstioNamespace
+	root, intermediates, err := in.getCACertificates(istioNamespace)
+	if err != nil {
+		return nil, err
+	}
+
+	var workload *chainCertificate
+	sampleError := ""
+	if pod != "" {
+		// Check if user has access to the namespace (RBAC) in cache scenarios and/or
+		// if namespace is accessible from Kiali (Deployment.AccessibleNamespaces)
+		if _, err = in.businessLayer.Namespace.GetNamespace(namespace); err != nil {
+			return nil, err
+		}
+		var proxyIntermediates []chainCertificate
+		workload, proxyIntermediates, err = in.getWorkloadCertificates(namespace, pod)
+		if err != nil {
+			log.Debugf("Unable to sample the certificate of the pod [%s/%s]: %v", namespace, pod, err)
+			sampleError = err.Error()
+			err = nil
+		}
+		// The proxies also get the intermediate certificates, which may be unknown to Kiali
+		intermediates = appendMissingCertificates(intermediates, proxyIntermediates, root)
+	}
+
+	if root == nil && len(intermediates) == 0 && workload == nil {
+		err = errors.NewNotFound(core_v1.Resource("configmaps"), istioRootCertConfigMap)
+		return nil, err
+	}
+
+	chain := []chainCertificate{}
+	if root != nil {
+		chain = append(chain, *root)
+	}
+	chain = append(chain, intermediates...)
+	if workload != nil {
+		chain = append(chain, *workload)
+	}
+	certificateChain := buildCertificateChain(chain, root != nil, workload != nil)
+	certificateChain.SampleError = sampleError
+	return certificateChain, nil
+}
+
+// getCACertificates returns the root certificate and the intermediate certificates, from the root, of the Istio CA.
+// The Secrets of the CA may be unreadable by Kiali, only the root certificate is required.
+func (in *IstioCertsService) getCACertificates(istioNamespace string) (*chainCertificate, []chainCertificate, error) {
+	var root *chainCertificate
+	var configMap *core_v1.ConfigMap
+	var err error
+	if IsNamespaceCached(istioNamespace) {
+		configMap, err = kialiCache.GetConfigMap(istioNamespace, istioRootCertConfigMap)
+	} else {
+		configMap, err = in.k8s.GetConfigMap(istioNamespace, istioRootCertConfigMap)
+	}
+	if err != nil && !errors.IsNotFound(err) {
+		return nil, nil, err
+	}
+	if err == nil {
+		certs, err := parsePEMCertificates([]byte(configMap.Data["root-cert.pem"]))
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid root certificate of the ConfigMap [%s/%s]: %v", istioNamespace, istioRootCertConfigMap, err)
+		}
+		if len(certs) > 0 {
+			root = &chainCertificate{cert: certs[0], source: fmt.Sprintf("ConfigMap %s/%s", istioNamespace, istioRootCertConfigMap)}
+		}
+	}
+
+	// A plugged-in CA comes with its chain of certificates, a self-signed CA is the root itself
+	for _, secretName := range []string{istioPluggedCASecret, istioSelfSignedCASecret} {
+		secret, err := in.k8s.GetSecret(istioNamespace, secretName)
+		if err != nil {
+			log.Debugf("Unable to read the Secret [%s/%s] of the Istio CA: %v", istioNamespace, secretName, err)
+			continue
+		}
+		source := fmt.Sprintf("Secret %s/%s", istioNamespace, secretName)
+		caChain := secret.Data["cert-chain.pem"]
+		if len(caChain) == 0 {
+			caChain = secret.Data["ca-cert.pem"]
+		}
+		certs, err := parsePEMCertificates(caChain)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid certificates of the Secret [%s/%s]: %v", istioNamespace, secretName, err)
+		}
+		if root == nil {
+			if rootCerts, err := parsePEMCertificates(secret.Data["root-cert.pem"]); err == nil && len(rootCerts) > 0 {
+				root = &chainCertificate{cert: rootCerts[0], source: source}
+			}
+		}
+		// The chain of the CA goes from its certificate up to the root
+		intermediates := make([]chainCertificate, 0, len(certs))
+		for i := len(certs) - 1; i >= 0; i-- {
+			intermediates = append(intermediates, chainCertificate{cert: certs[i], source: source})
+		}
+		return root, appendMissingCertificates(nil, intermediates, root), nil
+	}
+	return root, []chainCertificate{}, nil
+}
+
+// getWorkloadCertificates returns the certificate of the workload of the pod and the intermediate certificates,
+// from the root, sent along with it
+func (in *IstioCertsService) getWorkloadCertificates(namespace, pod string) (*chainCertificate, []chainCertificate, error) {
+	dump, err := in.k8s.GetConfigDump(namespace, pod)
+	if err != nil {
+		return nil, nil, err
+	}
+	secrets, err := dump.GetSecrets()
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, secret := range secrets.DynamicActiveSecrets {
+		if secret.Name != istioWorkloadSecretName || secret.Secret.TLSCertificate == nil {
+			continue
+		}
+		chainPEM, err := base64.StdEncoding.DecodeString(secret.Secret.TLSCertificate.CertificateChain.InlineBytes)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid certificate chain of the proxy: %v", err)
+		}
+		certs, err := parsePEMCertificates(chainPEM)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid certificate chain of the proxy: %v", err)
+		}
+		if len(certs) == 0 {
+			break
+		}
+		source := fmt.Sprintf("Proxy %s/%s", namespace, pod)
+		intermediates := make([]chainCertificate, 0, len(certs)-1)
+		for i := len(certs) - 1; i > 0; i-- {
+			intermediates = append(intermediates, chainCertificate{cert: certs[i], source: source})
+		}
+		return &chainCertificate{cert: certs[0], source: source}, intermediates, nil
+	}
+	return nil, nil, fmt.Errorf("the proxy of the pod [%s/%s] has no workload certificate", namespace, pod)
+}
+
+// buildCertificateChain checks that every certificate is valid and issued by the previous one
+func buildCertificateChain(chain []chainCertificate, hasRoot, hasWorkload bool) *models.CertificateChain {
+	now := util.Clock.Now()
+	certificateChain := &models.CertificateChain{Links: []models.CertificateLink{}, Valid: true}
+	for i, c := range chain {
+		link := models.CertificateLink{
+			Role:         models.CertificateRoleIntermediate,
+			Source:       c.source,
+			Subject:      c.cert.Subject.String(),
+			Issuer:       c.cert.Issuer.String(),
+			SerialNumber: c.cert.SerialNumber.String(),
+			NotBefore:    c.cert.NotBefore,
+			NotAfter:     c.cert.NotAfter,
+			SANs:         certificateSANs(c.cert),
+			Expired:      now.Before(c.cert.NotBefore) || now.After(c.cert.NotAfter),
+		}
+		switch {
+		case i == 0 && hasRoot:
+			link.Role = models.CertificateRoleRoot
+		case i == len(chain)-1 && hasWorkload:
+			link.Role = models.CertificateRoleWorkload
+		}
+
+		if i == 0 {
+			if link.Role == models.CertificateRoleRoot && !bytes.Equal(c.cert.RawIssuer, c.cert.RawSubject) {
+				link.Break = "The root certificate is not self-signed"
+			}
+		} else {
+			parent := chain[i-1].cert
+			if !bytes.Equal(c.cert.RawIssuer, parent.RawSubject) {
+				link.Break = fmt.Sprintf("Issued by [%s], not by the previous certificate [%s]", link.Issuer, parent.Subject.String())
+			} else if err := c.cert.CheckSignatureFrom(parent); err != nil {
+				link.Break = fmt.Sprintf("The signature is not verified by the previous certificate: %v", err)
+			}
+		}
+		if link.Break != "" || link.Expired {
+			certificateChain.Valid = false
+		}
+		certificateChain.Links = append(certificateChain.Links, link)
+	}
+	return certificateChain
+}
+
+// appendMissingCertificates appends the certificates which are neither the root nor already in the list
+func appendMissingCertificates(certs []chainCertificate, more []chainCertificate, root *chainCertificate) []chainCertificate {
+	if certs == nil {
+		certs = []chainCertificate{}
+	}
+	for _, m := range more {
+		missing := root == nil || !m.cert.Equal(root.cert)
+		for _, c := range certs {
+			if c.cert.Equal(m.cert) {
+				missing = false
+				break
+			}
+		}
+		if missing {
+			certs = append(certs, m)
+		}
+	}
+	return certs
+}
+
+func certificateSANs(cert *x509.Certificate) []string {
+	sans := append([]string{}, cert.DNSNames...)
+	for _, uri := range cert.URIs {
+		sans = append(sans, uri.String())
+	}
+	return sans
+}
+
+func parsePEMCertificates(data []byte) ([]*x509.Certificate, error) {
+	certs := []*x509.Certificate{}
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			return certs, nil
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		certs = append(certs, cert)
+	}
+}
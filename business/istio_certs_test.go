@@ -0,0 +1,208 @@
+package business
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net/url"
+	"testing"
+	"time"
+
+	osproject_v1 "github.com/openshift/api/project/v1"
+	"github.com/stretchr/testify/assert"
+	core_v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/kubernetes/kubetest"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/util"
+)
+
+type testCertificate struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pem  []byte
+}
+
+func TestGetCertificateChain(t *testing.T) {
+	assert := assert.New(t)
+	util.Clock = util.ClockMock{Time: time.Date(2022, 01, 01, 0, 0, 0, 0, time.UTC)}
+	defer func() { util.Clock = util.RealClock{} }()
+
+	root, intermediate := testCA(t)
+	workload := newTestCertificate(t, 4, "", intermediate, time.Date(2031, 01, 01, 0, 0, 0, 0, time.UTC))
+	k8s := certsTestPrep(root, intermediate)
+	k8s.On("GetConfigDump", "bookinfo", "reviews-v1-1234").Return(workloadConfigDump(workload, intermediate), nil)
+	svc := IstioCertsService{k8s: k8s, businessLayer: NewWithBackends(k8s, nil, nil)}
+
+	chain, err := svc.GetCertificateChain("bookinfo", "reviews-v1-1234")
+	assert.NoError(err)
+	assert.True(chain.Valid)
+	assert.Empty(chain.SampleError)
+	assert.Len(chain.Links, 3)
+
+	assert.Equal(models.CertificateRoleRoot, chain.Links[0].Role)
+	assert.Equal("ConfigMap istio-system/istio-ca-root-cert", chain.Links[0].Source)
+	assert.Equal("CN=Root CA,O=cluster.local", chain.Links[0].Subject)
+	assert.Equal(models.CertificateRoleIntermediate, chain.Links[1].Role)
+	assert.Equal("Secret istio-system/cacerts", chain.Links[1].Source)
+	assert.Equal("CN=Intermediate CA,O=cluster.local", chain.Links[1].Subject)
+	assert.Equal("CN=Root CA,O=cluster.local", chain.Links[1].Issuer)
+	assert.Equal(models.CertificateRoleWorkload, chain.Links[2].Role)
+	assert.Equal("Proxy bookinfo/reviews-v1-1234", chain.Links[2].Source)
+	assert.Equal("CN=Intermediate CA,O=cluster.local", chain.Links[2].Issuer)
+	assert.Equal([]string{"spiffe://cluster.local/ns/bookinfo/sa/bookinfo-reviews"}, chain.Links[2].SANs)
+	assert.Equal("4", chain.Links[2].SerialNumber)
+	for _, link := range chain.Links {
+		assert.Empty(link.Break)
+		assert.False(link.Expired)
+	}
+}
+
+// Context: the workload certificate is issued by another CA than the intermediate CA of the mesh, and is expired
+func TestGetCertificateChainBroken(t *testing.T) {
+	assert := assert.New(t)
+	util.Clock = util.ClockMock{Time: time.Date(2022, 01, 01, 0, 0, 0, 0, time.UTC)}
+	defer func() { util.Clock = util.RealClock{} }()
+
+	root, intermediate := testCA(t)
+	rogue := newTestCertificate(t, 5, "Rogue CA", nil, time.Date(2031, 01, 01, 0, 0, 0, 0, time.UTC))
+	workload := newTestCertificate(t, 6, "", rogue, time.Date(2021, 06, 01, 0, 0, 0, 0, time.UTC))
+	k8s := certsTestPrep(root, intermediate)
+	k8s.On("GetConfigDump", "bookinfo", "reviews-v1-1234").Return(workloadConfigDump(workload), nil)
+	svc := IstioCertsService{k8s: k8s, businessLayer: NewWithBackends(k8s, nil, nil)}
+
+	chain, err := svc.GetCertificateChain("bookinfo", "reviews-v1-1234")
+	assert.NoError(err)
+	assert.False(chain.Valid)
+	assert.Len(chain.Links, 3)
+	assert.Empty(chain.Links[1].Break)
+	assert.Equal("Issued by [CN=Rogue CA,O=cluster.local], not by the previous certificate [CN=Intermediate CA,O=cluster.local]", chain.Links[2].Break)
+	assert.True(chain.Links[2].Expired)
+}
+
+// Context: the proxy can't be reached and the Secrets of the CA can't be read, only the root certificate is known
+func TestGetCertificateChainRootOnly(t *testing.T) {
+	assert := assert.New(t)
+	util.Clock = util.ClockMock{Time: time.Date(2022, 01, 01, 0, 0, 0, 0, time.UTC)}
+	defer func() { util.Clock = util.RealClock{} }()
+
+	root, _ := testCA(t)
+	k8s := certsTestPrep(root, nil)
+	k8s.On("GetConfigDump", "bookinfo", "reviews-v1-1234").Return(&kubernetes.ConfigDump{}, fmt.Errorf("connection refused"))
+	svc := IstioCertsService{k8s: k8s, businessLayer: NewWithBackends(k8s, nil, nil)}
+
+	chain, err := svc.GetCertificateChain("bookinfo", "reviews-v1-1234")
+	assert.NoError(err)
+	assert.True(chain.Valid)
+	assert.Equal("connection refused", chain.SampleError)
+	assert.Len(chain.Links, 1)
+	assert.Equal(models.CertificateRoleRoot, chain.Links[0].Role)
+}
+
+func certsTestPrep(root, intermediate *testCertificate) *kubetest.K8SClientMock {
+	config.Set(config.NewConfig())
+
+	k8s := new(kubetest.K8SClientMock)
+	k8s.On("IsOpenShift").Return(true)
+	k8s.On("GetProject", "bookinfo").Return(&osproject_v1.Project{ObjectMeta: meta_v1.ObjectMeta{Name: "bookinfo"}}, nil)
+	k8s.On("GetConfigMap", "istio-system", "istio-ca-root-cert").Return(&core_v1.ConfigMap{
+		Data: map[string]string{"root-cert.pem": string(root.pem)},
+	}, nil)
+	forbidden := errors.NewForbidden(core_v1.Resource("secrets"), "cacerts", fmt.Errorf("forbidden"))
+	if intermediate != nil {
+		k8s.On("GetSecret", "istio-system", "cacerts").Return(&core_v1.Secret{
+			Data: map[string][]byte{
+				"ca-cert.pem":    intermediate.pem,
+				"cert-chain.pem": append(append([]byte{}, intermediate.pem...), root.pem...),
+				"root-cert.pem":  root.pem,
+			},
+		}, nil)
+	} else {
+		k8s.On("GetSecret", "istio-system", "cacerts").Return(&core_v1.Secret{}, forbidden)
+	}
+	k8s.On("GetSecret", "istio-system", "istio-ca-secret").Return(&core_v1.Secret{}, forbidden)
+	return k8s
+}
+
+func testCA(t *testing.T) (*testCertificate, *testCertificate) {
+	root := newTestCertificate(t, 1, "Root CA", nil, time.Date(2031, 01, 01, 0, 0, 0, 0, time.UTC))
+	intermediate := newTestCertificate(t, 2, "Intermediate CA", root, time.Date(2031, 01, 01, 0, 0, 0, 0, time.UTC))
+	return root, intermediate
+}
+
+// newTestCertificate creates a CA certificate with the common name, or a workload certificate without common name,
+// issued by the parent or self-signed without parent
+func newTestCertificate(t *testing.T, serial int64, commonName string, parent *testCertificate, notAfter time.Time) *testCertificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{Organization: []string{"cluster.local"}, CommonName: commonName},
+		NotBefore:    time.Date(2021, 01, 01, 0, 0, 0, 0, time.UTC),
+		NotAfter:     notAfter,
+	}
+	if commonName != "" {
+		template.IsCA = true
+		template.BasicConstraintsValid = true
+		template.KeyUsage = x509.KeyUsageCertSign
+	} else {
+		template.Subject = pkix.Name{}
+		spiffe, _ := url.Parse("spiffe://cluster.local/ns/bookinfo/sa/bookinfo-reviews")
+		template.URIs = []*url.URL{spiffe}
+	}
+	issuer, signer := template, key
+	if parent != nil {
+		issuer, signer = parent.cert, parent.key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, issuer, &key.PublicKey, signer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &testCertificate{cert: cert, key: key, pem: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})}
+}
+
+// workloadConfigDump is the config dump of a proxy whose workload certificate chain is made of the certificates
+func workloadConfigDump(certs ...*testCertificate) *kubernetes.ConfigDump {
+	chain := []byte{}
+	for _, c := range certs {
+		chain = append(chain, c.pem...)
+	}
+	return &kubernetes.ConfigDump{Configs: []interface{}{
+		map[string]interface{}{
+			"@type": "type.googleapis.com/envoy.admin.v3.SecretsConfigDump",
+			"dynamic_active_secrets": []interface{}{
+				map[string]interface{}{
+					"name": "ROOTCA",
+					"secret": map[string]interface{}{
+						"validation_context": map[string]interface{}{},
+					},
+				},
+				map[string]interface{}{
+					"name": "default",
+					"secret": map[string]interface{}{
+						"tls_certificate": map[string]interface{}{
+							"certificate_chain": map[string]interface{}{"inline_bytes": base64.StdEncoding.EncodeToString(chain)},
+							"private_key":       map[string]interface{}{"inline_bytes": "W3JlZGFjdGVkXQ=="},
+						},
+					},
+				},
+			},
+		},
+	}}
+}
@@ -33,6 +33,7 @@ type IstioConfigCriteria struct {
 	IncludeWorkloadEntries        bool
 	IncludeRequestAuthentications bool
 	IncludeEnvoyFilters           bool
+	IncludeProxyConfigs           bool
 	LabelSelector                 string
 	WorkloadSelector              string
 }
@@ -61,6 +62,8 @@ func (icc IstioConfigCriteria) Include(resource string) bool {
 		return icc.IncludeRequestAuthentications
 	case kubernetes.EnvoyFilters:
 		return icc.IncludeEnvoyFilters
+	case kubernetes.ProxyConfigs:
+		return icc.IncludeProxyConfigs
 	}
 	return false
 }
@@ -96,6 +99,7 @@ func (in *IstioConfigService) GetIstioConfigList(criteria IstioConfigCriteria) (
 		WorkloadEntries:        models.WorkloadEntries{},
 		RequestAuthentications: models.RequestAuthentications{},
 		EnvoyFilters:           models.EnvoyFilters{},
+		ProxyConfigs:           models.ProxyConfigResources{},
 	}
 
 	// Check if user has access to the namespace (RBAC) in cache scenarios and/or
@@ -110,10 +114,10 @@ func (in *IstioConfigService) GetIstioConfigList(criteria IstioConfigCriteria) (
 		workloadSelector = criteria.WorkloadSelector
 	}
 
-	errChan := make(chan error, 10)
+	errChan := make(chan error, 11)
 
 	var wg sync.WaitGroup
-	wg.Add(10)
+	wg.Add(11)
 
 	go func(errChan chan error) {
 		defer wg.Done()
@@ -303,6 +307,20 @@ func (in *IstioConfigService) GetIstioConfigList(criteria IstioConfigCriteria) (
 		}
 	}(errChan)
 
+	go func(errChan chan error) {
+		defer wg.Done()
+		if criteria.Include(kubernetes.ProxyConfigs) {
+			if pc, pcErr := in.k8s.GetIstioObjects(criteria.Namespace, kubernetes.ProxyConfigs, criteria.LabelSelector); pcErr == nil {
+				if isWorkloadSelector {
+					pc = kubernetes.FilterIstioObjectsForWorkloadSelector(workloadSelector, pc)
+				}
+				(&istioConfigList.ProxyConfigs).Parse(pc)
+			} else {
+				errChan <- pcErr
+			}
+		}
+	}(errChan)
+
 	wg.Wait()
 
 	close(errChan)
@@ -420,6 +438,13 @@ func (in *IstioConfigService) GetIstioConfigDetails(namespace, objectType, objec
 		} else {
 			err = iErr
 		}
+	case kubernetes.ProxyConfigs:
+		if pc, iErr := in.k8s.GetIstioObject(namespace, kubernetes.ProxyConfigs, object); iErr == nil {
+			istioConfigDetail.ProxyConfig = &models.ProxyConfigResource{}
+			istioConfigDetail.ProxyConfig.Parse(pc)
+		} else {
+			err = iErr
+		}
 	default:
 		err = fmt.Errorf("object type not found: %v", objectType)
 	}
@@ -502,29 +527,68 @@ func (in *IstioConfigService) ParseJsonForCreate(resourceType string, body []byt
 	return marshalled, nil
 }
 
-// DeleteIstioConfigDetail deletes the given Istio resource
-func (in *IstioConfigService) DeleteIstioConfigDetail(api, namespace, resourceType, name string) (err error) {
+// getClusterClient returns the client editing the Istio config of the namespace of the cluster, and whether the
+// cluster is a remote one. The clusters are edited with the credentials of the user, so a remote cluster can only be
+// edited when the credentials of the user are available, and when the user can access the namespace in it.
+func (in *IstioConfigService) getClusterClient(cluster, namespace string) (kubernetes.ClientInterface, bool, error) {
+	client, remote, err := in.businessLayer.Mesh.getUserClusterClient(cluster)
+	if err != nil || !remote {
+		return client, remote, err
+	}
+	namespaceService := NewClusterNamespaceService(client)
+	if _, err = namespaceService.GetNamespace(namespace); err != nil {
+		return nil, true, err
+	}
+	return client, true, nil
+}
+
+// DeleteIstioConfigDetail deletes the given Istio resource of the cluster
+func (in *IstioConfigService) DeleteIstioConfigDetail(cluster, api, namespace, resourceType, name string) (err error) {
 	promtimer := internalmetrics.GetGoFunctionMetric("business", "IstioConfigService", "DeleteIstioConfigDetail")
 	defer promtimer.ObserveNow(&err)
 
-	err = in.k8s.DeleteIstioObject(api, namespace, resourceType, name)
+	client, remote, err := in.getClusterClient(cluster, namespace)
+	if err != nil {
+		return err
+	}
+
+	err = client.DeleteIstioObject(api, namespace, resourceType, name)
+	if err == nil {
+		in.businessLayer.Audit.Record(cluster, AuditDelete, namespace, istioConfigKind(resourceType), name)
+	}
 
 	// Cache is stopped after a Create/Update/Delete operation to force a refresh
-	if kialiCache != nil && err == nil {
+	// The cache only holds the Istio config of the home cluster
+	if kialiCache != nil && err == nil && !remote {
 		kialiCache.RefreshNamespace(namespace)
 	}
 	return err
 }
 
-func (in *IstioConfigService) UpdateIstioConfigDetail(api, namespace, resourceType, name, jsonPatch string) (models.IstioConfigDetails, error) {
+// istioConfigKind returns the kind of an Istio resource type, used in the audit records
+func istioConfigKind(resourceType string) string {
+	if kind, ok := kubernetes.PluralType[resourceType]; ok {
+		return kind
+	}
+	return resourceType
+}
+
+// UpdateIstioConfigDetail patches the given Istio resource of the cluster
+func (in *IstioConfigService) UpdateIstioConfigDetail(cluster, api, namespace, resourceType, name, jsonPatch string) (models.IstioConfigDetails, error) {
 	var err error
 	promtimer := internalmetrics.GetGoFunctionMetric("business", "IstioConfigService", "UpdateIstioConfigDetail")
 	defer promtimer.ObserveNow(&err)
 
-	return in.modifyIstioConfigDetail(api, namespace, resourceType, name, jsonPatch, false)
+	client, remote, err := in.getClusterClient(cluster, namespace)
+	if err != nil {
+		return models.IstioConfigDetails{}, err
+	}
+	return in.modifyIstioConfigDetail(cluster, client, remote, api, namespace, resourceType, name, jsonPatch, false)
 }
 
-func (in *IstioConfigService) modifyIstioConfigDetail(api, namespace, resourceType, name, json string, create bool) (models.IstioConfigDetails, error) {
+// modifyIstioConfigDetail creates or updates the Istio resource with the client of its cluster. The resources of a
+// remote cluster are returned with their validation against the registry of that cluster.
+func (in *IstioConfigService) modifyIstioConfigDetail(cluster string, client kubernetes.ClientInterface, remote bool, api, namespace, resourceType, name, json string, create bool) (models.IstioConfigDetails, error) {
 	var err error
 	updatedType := resourceType
 
@@ -535,13 +599,19 @@ func (in *IstioConfigService) modifyIstioConfigDetail(api, namespace, resourceTy
 
 	if create {
 		// Create new object
-		result, err = in.k8s.CreateIstioObject(api, namespace, updatedType, json)
+		result, err = client.CreateIstioObject(api, namespace, updatedType, json)
 	} else {
 		// Update/Path existing object
-		result, err = in.k8s.UpdateIstioObject(api, namespace, updatedType, name, json)
+		result, err = client.UpdateIstioObject(api, namespace, updatedType, name, json)
 	}
 	if err != nil {
-		return istioConfigDetail, err
+		return istioConfigDetail, parseApplyError(err)
+	}
+
+	if create {
+		in.businessLayer.Audit.Record(cluster, AuditCreate, namespace, istioConfigKind(resourceType), result.GetObjectMeta().Name)
+	} else {
+		in.businessLayer.Audit.Record(cluster, AuditUpdate, namespace, istioConfigKind(resourceType), name)
 	}
 
 	switch resourceType {
@@ -575,26 +645,46 @@ func (in *IstioConfigService) modifyIstioConfigDetail(api, namespace, resourceTy
 	case kubernetes.EnvoyFilters:
 		istioConfigDetail.EnvoyFilter = &models.EnvoyFilter{}
 		istioConfigDetail.EnvoyFilter.Parse(result)
+	case kubernetes.ProxyConfigs:
+		istioConfigDetail.ProxyConfig = &models.ProxyConfigResource{}
+		istioConfigDetail.ProxyConfig.Parse(result)
 	default:
 		err = fmt.Errorf("object type not found: %v", resourceType)
 	}
+	if err == nil && remote {
+		objectName := result.GetObjectMeta().Name
+		validations, vErr := in.businessLayer.Validations.GetClusterIstioObjectValidations(client, namespace, resourceType, objectName)
+		if vErr != nil {
+			log.Warningf("Error validating %s [%s.%s] against the registry of its cluster: %s", resourceType, objectName, namespace, vErr)
+		} else {
+			key := models.IstioValidationKey{ObjectType: models.ObjectTypeSingular[resourceType], Name: objectName, Namespace: namespace}
+			istioConfigDetail.IstioValidation = validations[key]
+		}
+	}
 	// Cache is stopped after a Create/Update/Delete operation to force a refresh
-	if kialiCache != nil && err == nil {
+	// The cache only holds the Istio config of the home cluster
+	if kialiCache != nil && err == nil && !remote {
 		kialiCache.RefreshNamespace(namespace)
 	}
 	return istioConfigDetail, err
 }
 
-func (in *IstioConfigService) CreateIstioConfigDetail(api, namespace, resourceType string, body []byte) (models.IstioConfigDetails, error) {
+// CreateIstioConfigDetail creates the Istio resource in the cluster
+func (in *IstioConfigService) CreateIstioConfigDetail(cluster, api, namespace, resourceType string, body []byte) (models.IstioConfigDetails, error) {
 	var err error
 	promtimer := internalmetrics.GetGoFunctionMetric("business", "IstioConfigService", "CreateIstioConfigDetail")
 	defer promtimer.ObserveNow(&err)
 
+	client, remote, err := in.getClusterClient(cluster, namespace)
+	if err != nil {
+		return models.IstioConfigDetails{}, err
+	}
+
 	json, err := in.ParseJsonForCreate(resourceType, body)
 	if err != nil {
 		return models.IstioConfigDetails{}, errors2.NewBadRequest(err.Error())
 	}
-	return in.modifyIstioConfigDetail(api, namespace, resourceType, "", json, true)
+	return in.modifyIstioConfigDetail(cluster, client, remote, api, namespace, resourceType, "", json, true)
 }
 
 func (in *IstioConfigService) GetIstioConfigPermissions(namespaces []string) models.IstioConfigPermissions {
@@ -677,6 +767,7 @@ func ParseIstioConfigCriteria(namespace, objects, labelSelector, workloadSelecto
 	criteria.IncludeWorkloadEntries = defaultInclude
 	criteria.IncludeRequestAuthentications = defaultInclude
 	criteria.IncludeEnvoyFilters = defaultInclude
+	criteria.IncludeProxyConfigs = defaultInclude
 	criteria.LabelSelector = labelSelector
 	criteria.WorkloadSelector = workloadSelector
 
@@ -715,5 +806,8 @@ func ParseIstioConfigCriteria(namespace, objects, labelSelector, workloadSelecto
 	if checkType(types, kubernetes.EnvoyFilters) {
 		criteria.IncludeEnvoyFilters = true
 	}
+	if checkType(types, kubernetes.ProxyConfigs) {
+		criteria.IncludeProxyConfigs = true
+	}
 	return criteria
 }
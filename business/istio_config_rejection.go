@@ -0,0 +1,127 @@
+package business
+
+import (
+	"net/http"
+	"regexp"
+	"strings"
+
+	errors2 "k8s.io/apimachinery/pkg/api/errors"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kiali/kiali/models"
+)
+
+const (
+	rejectedByWebhook   = "webhook"
+	rejectedByAdmission = "admission"
+	rejectedBySchema    = "schema"
+)
+
+var (
+	// Prefix added by the API server to the message of a validating webhook
+	webhookDenialRegex = regexp.MustCompile(`(?s)admission webhook "([^"]+)" denied the request(?::\s*(.*))?`)
+	// Prefix added by the API server to the message of a ValidatingAdmissionPolicy
+	policyDenialRegex = regexp.MustCompile(`(?s)denied request:\s*(.*)`)
+	// Forbidden errors which are not raised by the admission but by RBAC
+	rbacDenialRegex = regexp.MustCompile(`cannot \w+ resource`)
+	// Several errors reported by the Istio webhook, e.g. "2 errors occurred:\n\t* first\n\t* second\n\n"
+	multiErrorRegex = regexp.MustCompile(`^\d+ errors occurred:`)
+)
+
+// ApplyRejectedError is returned when the cluster rejects the creation or the update of an Istio object: denied by a
+// validating webhook (i.e. the one of Istio), by an admission policy, or invalid for the resource definition
+type ApplyRejectedError struct {
+	Code      int
+	Rejection models.IstioConfigRejection
+}
+
+func (in *ApplyRejectedError) Error() string {
+	return in.Rejection.Error
+}
+
+func IsApplyRejectedError(err error) bool {
+	_, isRejected := err.(*ApplyRejectedError)
+	return isRejected
+}
+
+// parseApplyError turns the rejections of the admission into an ApplyRejectedError, other errors are returned as is
+func parseApplyError(err error) error {
+	statusError, isStatus := err.(*errors2.StatusError)
+	if !isStatus {
+		return err
+	}
+	status := statusError.ErrStatus
+	rejection := models.IstioConfigRejection{Error: status.Message}
+
+	var messages []string
+	if match := webhookDenialRegex.FindStringSubmatch(status.Message); match != nil {
+		rejection.Source = rejectedByWebhook
+		rejection.Webhook = match[1]
+		messages = splitRejectionMessage(match[2])
+	} else if match := policyDenialRegex.FindStringSubmatch(status.Message); match != nil {
+		rejection.Source = rejectedByAdmission
+		messages = splitRejectionMessage(match[1])
+	} else if status.Reason == meta_v1.StatusReasonForbidden && !rbacDenialRegex.MatchString(status.Message) {
+		rejection.Source = rejectedByAdmission
+		message := status.Message
+		if i := strings.Index(message, "is forbidden: "); i >= 0 {
+			message = message[i+len("is forbidden: "):]
+		}
+		messages = splitRejectionMessage(message)
+	} else if status.Reason == meta_v1.StatusReasonInvalid {
+		rejection.Source = rejectedBySchema
+	} else {
+		return err
+	}
+
+	// The causes tell the fields which failed, when reported
+	if status.Details != nil && len(status.Details.Causes) > 0 {
+		for _, cause := range status.Details.Causes {
+			rejection.Checks = append(rejection.Checks, &models.IstioCheck{
+				Message:  cause.Message,
+				Severity: models.ErrorSeverity,
+				Path:     fieldPath(cause.Field),
+			})
+		}
+	} else {
+		if len(messages) == 0 {
+			messages = []string{status.Message}
+		}
+		for _, message := range messages {
+			rejection.Checks = append(rejection.Checks, &models.IstioCheck{
+				Message:  message,
+				Severity: models.ErrorSeverity,
+			})
+		}
+	}
+
+	code := int(status.Code)
+	if code < http.StatusBadRequest {
+		code = http.StatusBadRequest
+	}
+	return &ApplyRejectedError{Code: code, Rejection: rejection}
+}
+
+// splitRejectionMessage returns the errors of a rejection, one per error reported by the Istio webhook
+func splitRejectionMessage(message string) []string {
+	message = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(message), "configuration is invalid:"))
+	if message == "" {
+		return nil
+	}
+	if !multiErrorRegex.MatchString(message) {
+		return []string{message}
+	}
+	messages := []string{}
+	for _, line := range strings.Split(message, "\n")[1:] {
+		line = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(line), "*"))
+		if line != "" {
+			messages = append(messages, line)
+		}
+	}
+	return messages
+}
+
+// fieldPath converts the field of a cause (spec.http[0].route) to the path of a check (spec/http[0]/route)
+func fieldPath(field string) string {
+	return strings.ReplaceAll(field, ".", "/")
+}
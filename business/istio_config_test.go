@@ -7,8 +7,14 @@ import (
 	osproject_v1 "github.com/openshift/api/project/v1"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	apps_v1 "k8s.io/api/apps/v1"
 	auth_v1 "k8s.io/api/authorization/v1"
+	core_v1 "k8s.io/api/core/v1"
+	errors2 "k8s.io/apimachinery/pkg/api/errors"
 	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd/api"
 
 	"github.com/kiali/kiali/config"
 	"github.com/kiali/kiali/kubernetes"
@@ -174,6 +180,11 @@ func TestGetIstioConfigDetails(t *testing.T) {
 	assert.Equal("googleapis", istioConfigDetails.ServiceEntry.Metadata.Name)
 	assert.Nil(err)
 
+	istioConfigDetails, err = configService.GetIstioConfigDetails("test", "proxyconfigs", "reviews-proxy")
+	assert.Equal("reviews-proxy", istioConfigDetails.ProxyConfig.Metadata.Name)
+	assert.Equal(2, istioConfigDetails.ProxyConfig.Spec.Concurrency)
+	assert.Nil(err)
+
 	istioConfigDetails, err = configService.GetIstioConfigDetails("test", "rules-bad", "stdio")
 	assert.Error(err)
 }
@@ -506,6 +517,7 @@ func mockGetIstioConfigDetails() IstioConfigService {
 	k8s.On("GetIstioObject", "test", "virtualservices", "reviews").Return(fakeGetVirtualServices()[0], nil)
 	k8s.On("GetIstioObject", "test", "destinationrules", "reviews-dr").Return(fakeGetDestinationRules()[0], nil)
 	k8s.On("GetIstioObject", "test", "serviceentries", "googleapis").Return(fakeGetServiceEntries()[0], nil)
+	k8s.On("GetIstioObject", "test", "proxyconfigs", "reviews-proxy").Return(data.CreateProxyConfig("reviews-proxy", "test", map[string]interface{}{"concurrency": 2}), nil)
 	k8s.On("GetIstioObject", "test", "rules", "checkfromcustomer").Return(fakeCheckFromCustomerRule(), nil)
 	k8s.On("GetIstioObject", "test", "adapters", "preferencewhitelist").Return(fakeGetAdapters()[0], nil)
 	k8s.On("GetIstioObject", "test", "templates", "preferencesource").Return(fakeGetTemplates()[0], nil)
@@ -651,10 +663,10 @@ func TestDeleteIstioConfigDetails(t *testing.T) {
 	assert := assert.New(t)
 	configService := mockDeleteIstioConfigDetails()
 
-	err := configService.DeleteIstioConfigDetail("networking.istio.io", "test", "virtualservices", "reviews-to-delete")
+	err := configService.DeleteIstioConfigDetail("", "networking.istio.io", "test", "virtualservices", "reviews-to-delete")
 	assert.Nil(err)
 
-	err = configService.DeleteIstioConfigDetail("config.istio.io", "test", "templates", "listchecker-to-delete")
+	err = configService.DeleteIstioConfigDetail("", "config.istio.io", "test", "templates", "listchecker-to-delete")
 	assert.Nil(err)
 }
 
@@ -662,14 +674,15 @@ func mockDeleteIstioConfigDetails() IstioConfigService {
 	k8s := new(kubetest.K8SClientMock)
 	k8s.On("DeleteIstioObject", "networking.istio.io", "test", "virtualservices", "reviews-to-delete").Return(nil)
 	k8s.On("DeleteIstioObject", "config.istio.io", "test", "templates", "listchecker-to-delete").Return(nil)
-	return IstioConfigService{k8s: k8s}
+	k8s.On("IsOpenShift").Return(false)
+	return IstioConfigService{k8s: k8s, businessLayer: NewWithBackends(k8s, nil, nil)}
 }
 
 func TestUpdateIstioConfigDetails(t *testing.T) {
 	assert := assert.New(t)
 	configService := mockUpdateIstioConfigDetails()
 
-	updatedVirtualService, err := configService.UpdateIstioConfigDetail("networking.istio.io", "test", "virtualservices", "reviews-to-update", "{}")
+	updatedVirtualService, err := configService.UpdateIstioConfigDetail("", "networking.istio.io", "test", "virtualservices", "reviews-to-update", "{}")
 	assert.Equal("test", updatedVirtualService.Namespace.Name)
 	assert.Equal("virtualservices", updatedVirtualService.ObjectType)
 	assert.Equal("reviews-to-update", updatedVirtualService.VirtualService.Metadata.Name)
@@ -694,7 +707,147 @@ func mockUpdateIstioConfigDetails() IstioConfigService {
 	}
 	k8s.On("UpdateIstioObject", "networking.istio.io", "test", "virtualservices", "reviews-to-update", mock.AnythingOfType("string")).Return(updatedVirtualService, nil)
 	k8s.On("UpdateIstioObject", "config.istio.io", "test", "templates", "listchecker-to-update", mock.AnythingOfType("string")).Return(updatedTemplate, nil)
-	return IstioConfigService{k8s: k8s}
+	k8s.On("IsOpenShift").Return(false)
+	return IstioConfigService{k8s: k8s, businessLayer: NewWithBackends(k8s, nil, nil)}
+}
+
+// Only the config of the home cluster is edited: the remote clusters are only reached with the credentials of the
+// control plane
+func TestEditIstioConfigPerCluster(t *testing.T) {
+	assert := assert.New(t)
+	conf := config.NewConfig()
+	conf.Server.AuditLogSinks = []string{config.AuditLogSinkEvent}
+	config.Set(conf)
+	defer config.Set(config.NewConfig())
+
+	virtualService := data.AddRoutesToVirtualService("http", data.CreateRoute("ratings", "", -1),
+		data.CreateEmptyVirtualService("ratings", "test", []string{"ratings"}))
+	k8s := new(kubetest.K8SClientMock)
+	k8s.On("IsOpenShift").Return(false)
+	k8s.On("GetNamespace", "test").Return(kubetest.FakeNamespace("test"), nil)
+	k8s.On("GetNamespaces", "").Return([]core_v1.Namespace{*kubetest.FakeNamespace("test")}, nil)
+	k8s.On("GetConfigMap", conf.IstioNamespace, "istio").Return(&core_v1.ConfigMap{}, nil)
+	k8s.On("GetDeployment", conf.IstioNamespace, "istiod").Return(&apps_v1.Deployment{
+		Spec: apps_v1.DeploymentSpec{Template: core_v1.PodTemplateSpec{Spec: core_v1.PodSpec{
+			Containers: []core_v1.Container{{Env: []core_v1.EnvVar{{Name: "CLUSTER_ID", Value: "home"}}}},
+		}}},
+	}, nil)
+	k8s.On("GetSecrets", conf.IstioNamespace, "istio/multiCluster=true").Return([]core_v1.Secret{
+		fakeRemoteClusterSecret("west", "https://west.example.com:6443"),
+		fakeRemoteClusterSecret("east", "https://east.example.com:6443"),
+	}, nil)
+	mockIstioObjectEdits(k8s, virtualService)
+
+	// The ratings service is only deployed in the west cluster
+	west := new(kubetest.K8SClientMock)
+	mockIstioObjectEdits(west, virtualService)
+	mockClusterRegistry(west, virtualService, []core_v1.Service{{ObjectMeta: meta_v1.ObjectMeta{Name: "ratings", Namespace: "test"}}})
+	east := new(kubetest.K8SClientMock)
+	mockIstioObjectEdits(east, virtualService)
+	mockClusterRegistry(east, virtualService, []core_v1.Service{})
+	remoteClients := map[string]kubernetes.ClientInterface{"https://west.example.com:6443": west, "https://east.example.com:6443": east}
+	// The edits are audited with the credentials of Kiali
+	homeAudit, westAudit, eastAudit := new(kubetest.K8SClientMock), new(kubetest.K8SClientMock), new(kubetest.K8SClientMock)
+	for _, audit := range []*kubetest.K8SClientMock{homeAudit, westAudit, eastAudit} {
+		audit.On("CreateEvent", "test", mock.AnythingOfType("*v1.Event")).Return(nil)
+	}
+	auditClients := map[string]kubernetes.ClientInterface{"https://west.example.com:6443": westAudit, "https://east.example.com:6443": eastAudit}
+
+	layer := NewWithBackends(k8s, nil, nil)
+	layer.Mesh = NewMeshService(k8s, func(restConfig *rest.Config) (kubernetes.ClientInterface, error) {
+		if restConfig.BearerToken == "token" {
+			return auditClients[restConfig.Host], nil
+		}
+		// The remote clusters are edited with the credentials of the user, not the ones of the remote secrets
+		assert.Equal("user-token", restConfig.BearerToken)
+		return remoteClients[restConfig.Host], nil
+	})
+	layer.Mesh.authInfo = &api.AuthInfo{Token: "user-token"}
+	layer.Audit.kialiClient = func(cluster string) (kubernetes.ClientInterface, error) {
+		if cluster == "home" {
+			return homeAudit, nil
+		}
+		return layer.Mesh.getKialiClusterClient(cluster)
+	}
+	configService := IstioConfigService{k8s: k8s, businessLayer: layer}
+
+	// The home cluster, by name
+	details, err := configService.CreateIstioConfigDetail("home", "networking.istio.io", "test", "virtualservices", []byte("{}"))
+	assert.NoError(err)
+	assert.Nil(details.IstioValidation)
+	_, err = configService.UpdateIstioConfigDetail("home", "networking.istio.io", "test", "virtualservices", "ratings", "{}")
+	assert.NoError(err)
+	assert.NoError(configService.DeleteIstioConfigDetail("home", "networking.istio.io", "test", "virtualservices", "ratings"))
+
+	// A remote cluster of the mesh, validated against its registry
+	details, err = configService.CreateIstioConfigDetail("west", "networking.istio.io", "test", "virtualservices", []byte("{}"))
+	assert.NoError(err)
+	assert.NotNil(details.VirtualService)
+	assert.NotNil(details.IstioValidation)
+	assert.True(details.IstioValidation.Valid)
+	details, err = configService.UpdateIstioConfigDetail("west", "networking.istio.io", "test", "virtualservices", "ratings", "{}")
+	assert.NoError(err)
+	assert.True(details.IstioValidation.Valid)
+	assert.NoError(configService.DeleteIstioConfigDetail("west", "networking.istio.io", "test", "virtualservices", "ratings"))
+
+	details, err = configService.UpdateIstioConfigDetail("east", "networking.istio.io", "test", "virtualservices", "ratings", "{}")
+	assert.NoError(err)
+	assert.NotNil(details.IstioValidation)
+	assert.False(details.IstioValidation.Valid)
+	assert.Equal(models.CheckMessage("virtualservices.nohost.hostnotfound"), details.IstioValidation.Checks[0].Message)
+
+	// A namespace of a remote cluster not accessible to the user
+	_, err = configService.UpdateIstioConfigDetail("east", "networking.istio.io", "private", "virtualservices", "ratings", "{}")
+	assert.True(errors2.IsForbidden(err))
+
+	// A cluster out of the mesh
+	_, err = configService.UpdateIstioConfigDetail("north", "networking.istio.io", "test", "virtualservices", "ratings", "{}")
+	assert.True(errors2.IsNotFound(err))
+
+	// The remote clusters are not edited without the credentials of the user
+	layer.Mesh.authInfo = nil
+	_, err = configService.UpdateIstioConfigDetail("west", "networking.istio.io", "test", "virtualservices", "ratings", "{}")
+	assert.True(IsAccessibleError(err))
+
+	// Each edit reached the API server of its cluster only
+	k8s.AssertNumberOfCalls(t, "CreateIstioObject", 1)
+	k8s.AssertNumberOfCalls(t, "UpdateIstioObject", 1)
+	k8s.AssertNumberOfCalls(t, "DeleteIstioObject", 1)
+	west.AssertNumberOfCalls(t, "CreateIstioObject", 1)
+	west.AssertNumberOfCalls(t, "UpdateIstioObject", 1)
+	west.AssertNumberOfCalls(t, "DeleteIstioObject", 1)
+	east.AssertNumberOfCalls(t, "CreateIstioObject", 0)
+	east.AssertNumberOfCalls(t, "UpdateIstioObject", 1)
+	east.AssertNumberOfCalls(t, "DeleteIstioObject", 0)
+
+	// Each edit was audited in its cluster, not with the credentials of the user
+	homeAudit.AssertNumberOfCalls(t, "CreateEvent", 3)
+	westAudit.AssertNumberOfCalls(t, "CreateEvent", 3)
+	eastAudit.AssertNumberOfCalls(t, "CreateEvent", 1)
+	for _, user := range []*kubetest.K8SClientMock{k8s, west, east} {
+		user.AssertNotCalled(t, "CreateEvent", mock.Anything, mock.Anything)
+	}
+}
+
+func mockIstioObjectEdits(k8s *kubetest.K8SClientMock, virtualService kubernetes.IstioObject) {
+	k8s.On("CreateIstioObject", "networking.istio.io", "test", "virtualservices", mock.AnythingOfType("string")).Return(virtualService, nil)
+	k8s.On("UpdateIstioObject", "networking.istio.io", "test", "virtualservices", "ratings", mock.AnythingOfType("string")).Return(virtualService, nil)
+	k8s.On("DeleteIstioObject", "networking.istio.io", "test", "virtualservices", "ratings").Return(nil)
+}
+
+// mockClusterRegistry mocks the registry of a cluster holding the virtual service and the services of the test
+// namespace, the only namespace of the cluster accessible to the user
+func mockClusterRegistry(k8s *kubetest.K8SClientMock, virtualService kubernetes.IstioObject, services []core_v1.Service) {
+	conf := config.Get()
+	k8s.On("IsOpenShift").Return(false)
+	k8s.On("GetNamespace", "test").Return(kubetest.FakeNamespace("test"), nil)
+	k8s.On("GetNamespace", "private").Return((*core_v1.Namespace)(nil), errors2.NewForbidden(schema.GroupResource{Resource: "namespaces"}, "private", fmt.Errorf("forbidden")))
+	k8s.On("GetNamespaces", "").Return([]core_v1.Namespace{*kubetest.FakeNamespace("test")}, nil)
+	k8s.On("GetConfigMap", conf.IstioNamespace, conf.ExternalServices.Istio.ConfigMapName).Return(&core_v1.ConfigMap{}, nil)
+	k8s.On("GetIstioObjects", "test", kubernetes.VirtualServices, "").Return([]kubernetes.IstioObject{virtualService}, nil)
+	k8s.On("GetIstioObjects", mock.AnythingOfType("string"), mock.AnythingOfType("string"), "").Return([]kubernetes.IstioObject{}, nil)
+	k8s.On("GetServices", "test", mock.Anything).Return(services, nil)
+	k8s.On("GetPods", "test", "").Return([]core_v1.Pod{}, nil)
 }
 
 // mockCreateIstioConfigDetails to verify the behavior of API calls is the same for create and update
@@ -716,20 +869,108 @@ func mockCreateIstioConfigDetails() IstioConfigService {
 	}
 	k8s.On("CreateIstioObject", "networking.istio.io", "test", "virtualservices", mock.AnythingOfType("string")).Return(createdVirtualService, nil)
 	k8s.On("CreateIstioObject", "config.istio.io", "test", "templates", mock.AnythingOfType("string")).Return(createdTemplate, nil)
-	return IstioConfigService{k8s: k8s}
+	k8s.On("IsOpenShift").Return(false)
+	return IstioConfigService{k8s: k8s, businessLayer: NewWithBackends(k8s, nil, nil)}
 }
 
 func TestCreateIstioConfigDetails(t *testing.T) {
 	assert := assert.New(t)
 	configService := mockCreateIstioConfigDetails()
 
-	createVirtualService, err := configService.CreateIstioConfigDetail("networking.istio.io", "test", "virtualservices", []byte("{}"))
+	createVirtualService, err := configService.CreateIstioConfigDetail("", "networking.istio.io", "test", "virtualservices", []byte("{}"))
 	assert.Equal("test", createVirtualService.Namespace.Name)
 	assert.Equal("virtualservices", createVirtualService.ObjectType)
 	assert.Equal("reviews-to-update", createVirtualService.VirtualService.Metadata.Name)
 	assert.Nil(err)
 }
 
+func TestCreateIstioConfigRejectedByIstioWebhook(t *testing.T) {
+	assert := assert.New(t)
+	// As returned by the API server for a denial of the Istio validating webhook
+	k8s := new(kubetest.K8SClientMock)
+	k8s.On("CreateIstioObject", "networking.istio.io", "test", "virtualservices", mock.AnythingOfType("string")).Return((*kubernetes.GenericIstioObject)(nil), &errors2.StatusError{ErrStatus: meta_v1.Status{
+		Status:  meta_v1.StatusFailure,
+		Code:    400,
+		Message: "admission webhook \"validation.istio.io\" denied the request: configuration is invalid: 2 errors occurred:\n\t* virtual service must have at least one rule\n\t* weight 120 must be in the range 0..100\n\n",
+	}})
+	k8s.On("IsOpenShift").Return(false)
+	configService := IstioConfigService{k8s: k8s, businessLayer: NewWithBackends(k8s, nil, nil)}
+
+	_, err := configService.CreateIstioConfigDetail("", "networking.istio.io", "test", "virtualservices", []byte("{}"))
+	assert.True(IsApplyRejectedError(err))
+	rejected := err.(*ApplyRejectedError)
+	assert.Equal(400, rejected.Code)
+	assert.Equal("webhook", rejected.Rejection.Source)
+	assert.Equal("validation.istio.io", rejected.Rejection.Webhook)
+	assert.Contains(rejected.Rejection.Error, "denied the request")
+	assert.Len(rejected.Rejection.Checks, 2)
+	assert.Equal("virtual service must have at least one rule", rejected.Rejection.Checks[0].Message)
+	assert.Equal(models.ErrorSeverity, rejected.Rejection.Checks[0].Severity)
+	assert.Equal("weight 120 must be in the range 0..100", rejected.Rejection.Checks[1].Message)
+}
+
+func TestUpdateIstioConfigRejectedByWebhookWithCauses(t *testing.T) {
+	assert := assert.New(t)
+	k8s := new(kubetest.K8SClientMock)
+	k8s.On("UpdateIstioObject", "networking.istio.io", "test", "virtualservices", "reviews", mock.AnythingOfType("string")).Return((*kubernetes.GenericIstioObject)(nil), &errors2.StatusError{ErrStatus: meta_v1.Status{
+		Status:  meta_v1.StatusFailure,
+		Code:    422,
+		Message: `admission webhook "policy.example.com" denied the request: routes must set a timeout`,
+		Details: &meta_v1.StatusDetails{
+			Causes: []meta_v1.StatusCause{{Message: "routes must set a timeout", Field: "spec.http[0].timeout"}},
+		},
+	}})
+	k8s.On("IsOpenShift").Return(false)
+	configService := IstioConfigService{k8s: k8s, businessLayer: NewWithBackends(k8s, nil, nil)}
+
+	_, err := configService.UpdateIstioConfigDetail("", "networking.istio.io", "test", "virtualservices", "reviews", "{}")
+	rejected, isRejected := err.(*ApplyRejectedError)
+	assert.True(isRejected)
+	assert.Equal(422, rejected.Code)
+	assert.Equal("policy.example.com", rejected.Rejection.Webhook)
+	assert.Len(rejected.Rejection.Checks, 1)
+	assert.Equal("routes must set a timeout", rejected.Rejection.Checks[0].Message)
+	assert.Equal("spec/http[0]/timeout", rejected.Rejection.Checks[0].Path)
+}
+
+func TestUpdateIstioConfigRejectedByAdmission(t *testing.T) {
+	assert := assert.New(t)
+	k8s := new(kubetest.K8SClientMock)
+	k8s.On("UpdateIstioObject", "networking.istio.io", "test", "virtualservices", "reviews", mock.AnythingOfType("string")).Return((*kubernetes.GenericIstioObject)(nil), errors2.NewForbidden(
+		schema.GroupResource{Group: "networking.istio.io", Resource: "virtualservices"}, "reviews",
+		fmt.Errorf("ValidatingAdmissionPolicy 'no-wildcard-hosts' with binding 'no-wildcard-hosts' denied request: hosts must not be wildcards")))
+	k8s.On("IsOpenShift").Return(false)
+	configService := IstioConfigService{k8s: k8s, businessLayer: NewWithBackends(k8s, nil, nil)}
+
+	_, err := configService.UpdateIstioConfigDetail("", "networking.istio.io", "test", "virtualservices", "reviews", "{}")
+	rejected, isRejected := err.(*ApplyRejectedError)
+	assert.True(isRejected)
+	assert.Equal(403, rejected.Code)
+	assert.Equal("admission", rejected.Rejection.Source)
+	assert.Empty(rejected.Rejection.Webhook)
+	assert.Len(rejected.Rejection.Checks, 1)
+	assert.Equal("hosts must not be wildcards", rejected.Rejection.Checks[0].Message)
+}
+
+func TestUpdateIstioConfigErrorsNotRejected(t *testing.T) {
+	assert := assert.New(t)
+	gr := schema.GroupResource{Group: "networking.istio.io", Resource: "virtualservices"}
+	k8s := new(kubetest.K8SClientMock)
+	// Denied by RBAC, not by the admission
+	k8s.On("UpdateIstioObject", "networking.istio.io", "test", "virtualservices", "reviews", mock.AnythingOfType("string")).Return((*kubernetes.GenericIstioObject)(nil), errors2.NewForbidden(
+		gr, "reviews", fmt.Errorf(`User "jdoe" cannot patch resource "virtualservices" in API group "networking.istio.io" in the namespace "test"`)))
+	k8s.On("UpdateIstioObject", "networking.istio.io", "test", "virtualservices", "missing", mock.AnythingOfType("string")).Return((*kubernetes.GenericIstioObject)(nil), errors2.NewNotFound(gr, "missing"))
+	k8s.On("IsOpenShift").Return(false)
+	configService := IstioConfigService{k8s: k8s, businessLayer: NewWithBackends(k8s, nil, nil)}
+
+	_, err := configService.UpdateIstioConfigDetail("", "networking.istio.io", "test", "virtualservices", "reviews", "{}")
+	assert.Error(err)
+	assert.False(IsApplyRejectedError(err))
+
+	_, err = configService.UpdateIstioConfigDetail("", "networking.istio.io", "test", "virtualservices", "missing", "{}")
+	assert.True(errors2.IsNotFound(err))
+}
+
 func TestFilterIstioObjectsForWorkloadSelector(t *testing.T) {
 	assert := assert.New(t)
 
@@ -2,6 +2,9 @@ package business
 
 import (
 	"fmt"
+	"math"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 
@@ -13,12 +16,14 @@ import (
 	"github.com/kiali/kiali/kubernetes"
 	"github.com/kiali/kiali/log"
 	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/prometheus"
 	"github.com/kiali/kiali/util/httputil"
 )
 
 // SvcService deals with fetching istio/kubernetes services related content and convert to kiali model
 type IstioStatusService struct {
-	k8s kubernetes.ClientInterface
+	k8s  kubernetes.ClientInterface
+	prom prometheus.ClientInterface
 }
 
 type ComponentStatus struct {
@@ -43,6 +48,75 @@ type ComponentStatus struct {
 
 type IstioComponentStatus []ComponentStatus
 
+// IstiodReplicaStatus describes the status of a single istiod replica
+type IstiodReplicaStatus struct {
+	// The name of the istiod pod
+	//
+	// example: istiod-58d7f5d8b6-x8wvc
+	// required: true
+	Name string `json:"name"`
+
+	// The Istio revision of the replica, "default" when it doesn't have any revision label
+	//
+	// example: canary
+	// required: true
+	Revision string `json:"revision"`
+
+	// The version of the replica, taken from the image tag
+	//
+	// example: 1.8.1
+	Version string `json:"version"`
+
+	// The status of the replica
+	//
+	// example: Healthy
+	// required: true
+	Status string `json:"status"`
+}
+
+// IstiodStatus aggregates the status of all the istiod replicas of the control plane
+type IstiodStatus struct {
+	// The overall status of the control plane
+	//
+	// example: Healthy
+	// required: true
+	Status string `json:"status"`
+
+	// When true, istiod runs outside the cluster and replicas are not known
+	External bool `json:"external"`
+
+	// The versions run by the replicas of each revision
+	Versions map[string][]string `json:"versions"`
+
+	// When true, replicas of the same revision run different versions, which usually means a rollout is in progress
+	VersionMismatch bool `json:"versionMismatch"`
+
+	Replicas []IstiodReplicaStatus `json:"replicas"`
+}
+
+// PilotMetrics reports the performance of the control plane when pushing the configuration to the proxies
+type PilotMetrics struct {
+	// When false, Prometheus doesn't have any pilot metric: istiod isn't scraped or the time range has no pushes
+	//
+	// required: true
+	Available bool `json:"available"`
+
+	// Quantiles and average of the time (in seconds) needed by the proxies to converge to the pushed configuration
+	ConvergenceTime []models.Stat `json:"convergenceTime"`
+
+	// Rate of successful xDS pushes per second
+	PushRate float64 `json:"pushRate"`
+
+	// Rate of xDS pushes per second, by xDS type (cds, eds, lds, rds...)
+	PushRatesByType map[string]float64 `json:"pushRatesByType"`
+
+	// Rate of failed xDS pushes per second (pilot_xds_push_errors and the *_senderr push types)
+	PushErrorRate float64 `json:"pushErrorRate"`
+
+	// Ratio of failed pushes over all pushes, from 0 to 1. Nil when there is no push.
+	PushErrorRatio *float64 `json:"pushErrorRatio"`
+}
+
 func (ics *IstioComponentStatus) merge(cs IstioComponentStatus) IstioComponentStatus {
 	*ics = append(*ics, cs...)
 	return *ics
@@ -56,6 +130,13 @@ const (
 	Unreachable string = "Unreachable"
 )
 
+const (
+	// IstioRevisionLabel identifies the revision of the control plane
+	IstioRevisionLabel = "istio.io/rev"
+	// DefaultRevision is used for the control plane installed without revision
+	DefaultRevision = "default"
+)
+
 func (iss *IstioStatusService) GetStatus() (IstioComponentStatus, error) {
 	if !config.Get().ExternalServices.Istio.ComponentStatuses.Enabled {
 		return IstioComponentStatus{}, nil
@@ -69,6 +150,214 @@ func (iss *IstioStatusService) GetStatus() (IstioComponentStatus, error) {
 	return ics.merge(iss.getAddonComponentStatus()), nil
 }
 
+// GetIstiodStatus returns the status of every istiod replica, of all the revisions, and their aggregated status.
+func (iss *IstioStatusService) GetIstiodStatus() (*IstiodStatus, error) {
+	if config.Get().ExternalServices.Istio.ExternalIstiod {
+		return getExternalIstiodStatus(), nil
+	}
+
+	cfg := config.Get()
+	istiods, err := iss.k8s.GetPods(cfg.IstioNamespace, labels.Set(map[string]string{"app": "istiod"}).String())
+	if err != nil {
+		return nil, err
+	}
+
+	replicas := istiodReplicas(istiods)
+
+	wg := sync.WaitGroup{}
+	for i := range replicas {
+		if replicas[i].Status != Healthy {
+			continue
+		}
+		wg.Add(1)
+		go func(replica *IstiodReplicaStatus) {
+			defer wg.Done()
+			// Same check than the component status, istiod has to be reachable through the K8s API proxy
+			if _, err := iss.k8s.GetPodProxy(cfg.IstioNamespace, replica.Name, "/ready"); err != nil {
+				replica.Status = Unreachable
+			}
+		}(&replicas[i])
+	}
+	wg.Wait()
+
+	return aggregateIstiodStatus(replicas), nil
+}
+
+// istiodReplicas returns the status of the istiod pods, Healthy when running. The pods replaced by a rollout are
+// not part of the control plane anymore.
+func istiodReplicas(istiods []core_v1.Pod) []IstiodReplicaStatus {
+	replicas := make([]IstiodReplicaStatus, 0, len(istiods))
+	for _, istiod := range istiods {
+		if istiod.DeletionTimestamp != nil {
+			continue
+		}
+		revision := istiod.Labels[IstioRevisionLabel]
+		if revision == "" {
+			revision = DefaultRevision
+		}
+		status := NotReady
+		if istiod.Status.Phase == core_v1.PodRunning {
+			status = Healthy
+		}
+		replicas = append(replicas, IstiodReplicaStatus{
+			Name:     istiod.Name,
+			Revision: revision,
+			Version:  istiodVersion(istiod),
+			Status:   status,
+		})
+	}
+	return replicas
+}
+
+// aggregateIstiodStatus computes the overall status and the versions of the replicas.
+// The control plane is Healthy when all replicas are healthy and Unhealthy when only some of them are.
+func aggregateIstiodStatus(replicas []IstiodReplicaStatus) *IstiodStatus {
+	status := &IstiodStatus{
+		Versions: map[string][]string{},
+		Replicas: replicas,
+	}
+
+	healthy, unreachable := 0, 0
+	versions := map[string]map[string]bool{}
+	for _, replica := range replicas {
+		switch replica.Status {
+		case Healthy:
+			healthy++
+		case Unreachable:
+			unreachable++
+		}
+		if versions[replica.Revision] == nil {
+			versions[replica.Revision] = map[string]bool{}
+		}
+		if replica.Version != "" && !versions[replica.Revision][replica.Version] {
+			versions[replica.Revision][replica.Version] = true
+			status.Versions[replica.Revision] = append(status.Versions[replica.Revision], replica.Version)
+		}
+	}
+	for revision := range status.Versions {
+		sort.Strings(status.Versions[revision])
+		if len(status.Versions[revision]) > 1 {
+			status.VersionMismatch = true
+		}
+	}
+
+	switch {
+	case len(replicas) == 0:
+		status.Status = NotFound
+	case healthy == len(replicas):
+		status.Status = Healthy
+	case healthy > 0:
+		status.Status = Unhealthy
+	case unreachable > 0:
+		status.Status = Unreachable
+	default:
+		status.Status = NotReady
+	}
+
+	return status
+}
+
+var pilotConvergenceQuantiles = []string{"0.5", "0.9", "0.99"}
+
+// GetPilotMetrics queries Prometheus for the istiod push metrics: proxy convergence time percentiles,
+// push rates and push errors. These explain a slow propagation of the configuration to the proxies.
+func (iss *IstioStatusService) GetPilotMetrics(rateInterval string, queryTime time.Time) (*PilotMetrics, error) {
+	metrics := &PilotMetrics{
+		ConvergenceTime: []models.Stat{},
+		PushRatesByType: map[string]float64{},
+	}
+
+	convergence, err := iss.prom.FetchHistogramValues("pilot_proxy_convergence_time", "", "", rateInterval, true, pilotConvergenceQuantiles, queryTime)
+	if err != nil {
+		return nil, err
+	}
+	for stat, vec := range convergence {
+		for _, sample := range vec {
+			value := float64(sample.Value)
+			// No push in the interval gives NaN
+			if math.IsNaN(value) {
+				continue
+			}
+			metrics.ConvergenceTime = append(metrics.ConvergenceTime, models.Stat{Name: stat, Value: value})
+			metrics.Available = true
+		}
+	}
+	sort.Slice(metrics.ConvergenceTime, func(i, j int) bool {
+		return metrics.ConvergenceTime[i].Name < metrics.ConvergenceTime[j].Name
+	})
+
+	pushes, err := iss.prom.FetchRateValues("pilot_xds_pushes", "", "type", rateInterval, queryTime)
+	if err != nil {
+		return nil, err
+	}
+	for _, sample := range pushes {
+		metrics.Available = true
+		value := float64(sample.Value)
+		pushType := string(sample.Metric["type"])
+		metrics.PushRatesByType[pushType] = value
+		// Failed sends are counted as a push type, i.e. cds_senderr
+		if strings.HasSuffix(pushType, "_senderr") {
+			metrics.PushErrorRate += value
+		} else {
+			metrics.PushRate += value
+		}
+	}
+
+	pushErrors, err := iss.prom.FetchRateValues("pilot_xds_push_errors", "", "", rateInterval, queryTime)
+	if err != nil {
+		return nil, err
+	}
+	for _, sample := range pushErrors {
+		metrics.Available = true
+		metrics.PushErrorRate += float64(sample.Value)
+	}
+
+	if total := metrics.PushRate + metrics.PushErrorRate; total > 0 {
+		ratio := metrics.PushErrorRate / total
+		metrics.PushErrorRatio = &ratio
+	}
+	return metrics, nil
+}
+
+// getExternalIstiodStatus checks the remote istiod through its version endpoint, as there are no local replicas
+func getExternalIstiodStatus() *IstiodStatus {
+	status := &IstiodStatus{
+		Status:   Unreachable,
+		External: true,
+		Versions: map[string][]string{},
+		Replicas: []IstiodReplicaStatus{},
+	}
+	body, code, err := httputil.HttpGet(config.Get().ExternalServices.Istio.UrlServiceVersion, nil, 10*time.Second)
+	if err != nil || code >= 400 {
+		log.Debugf("External istiod is unreachable: [code: %d] %v", code, err)
+		return status
+	}
+	status.Status = Healthy
+	if version := strings.TrimSpace(string(body)); version != "" {
+		status.Versions[DefaultRevision] = []string{version}
+	}
+	return status
+}
+
+// istiodVersion returns the image tag of the istiod container, or of the first container when not found
+func istiodVersion(pod core_v1.Pod) string {
+	if len(pod.Spec.Containers) == 0 {
+		return ""
+	}
+	image := pod.Spec.Containers[0].Image
+	for _, c := range pod.Spec.Containers {
+		if c.Name == "discovery" {
+			image = c.Image
+			break
+		}
+	}
+	// Tag is after the last colon, as long as it's not part of the registry host
+	if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		return image[i+1:]
+	}
+	return ""
+}
+
 func (iss *IstioStatusService) getIstioComponentStatus() (IstioComponentStatus, error) {
 	// Fetching workloads from component namespaces
 	ds, err := iss.getComponentNamespacesWorkloads()
@@ -81,6 +370,11 @@ func (iss *IstioStatusService) getIstioComponentStatus() (IstioComponentStatus,
 		return IstioComponentStatus{}, err
 	}
 
+	// Remote istiod replicas are not reachable through the local K8s API
+	if config.Get().ExternalServices.Istio.ExternalIstiod {
+		return deploymentStatus, nil
+	}
+
 	istiodStatus, err := iss.getIstiodReachingCheck()
 	if err != nil {
 		return IstioComponentStatus{}, err
@@ -162,8 +456,12 @@ func getComponentNamespaces() []string {
 
 func istioCoreComponents() map[string]bool {
 	components := map[string]bool{}
-	cs := config.Get().ExternalServices.Istio.ComponentStatuses
-	for _, c := range cs.Components {
+	istioConfig := config.Get().ExternalServices.Istio
+	for _, c := range istioConfig.ComponentStatuses.Components {
+		// There isn't any local istiod deployment when the control plane is external
+		if istioConfig.ExternalIstiod && c.AppLabel == "istiod" {
+			continue
+		}
 		components[c.AppLabel] = c.IsCore
 	}
 	return components
@@ -2,12 +2,15 @@ package business
 
 import (
 	"fmt"
+	"math"
 	"net/http"
 	"net/http/httptest"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/gorilla/mux"
+	"github.com/prometheus/common/model"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	apps_v1 "k8s.io/api/apps/v1"
@@ -17,6 +20,8 @@ import (
 	"github.com/kiali/kiali/config"
 	"github.com/kiali/kiali/kubernetes/kubetest"
 	"github.com/kiali/kiali/log"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/prometheus/prometheustest"
 )
 
 type addOnsSetup struct {
@@ -488,6 +493,147 @@ func TestCustomizedAppLabel(t *testing.T) {
 	assert.Equal(1, *promCalls)
 }
 
+// Default and canary revisions, all the replicas healthy
+func TestIstiodStatusMultipleRevisions(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	k8s := mockDeploymentCall([]apps_v1.Deployment{}, []v1.Pod{
+		fakeIstiodPod("istiod-7d5bc9c4d9-a1b2c", "", "1.8.1", "Running"),
+		fakeIstiodPod("istiod-canary-6f9c8d7b5-d3e4f", "canary", "1.9.0", "Running"),
+		fakeIstiodPod("istiod-canary-6f9c8d7b5-g5h6i", "canary", "1.9.0", "Running"),
+	}, true)
+	iss := IstioStatusService{k8s: k8s}
+
+	status, err := iss.GetIstiodStatus()
+	assert.NoError(err)
+	assert.Equal(Healthy, status.Status)
+	assert.False(status.External)
+	assert.Len(status.Replicas, 3)
+	assert.Equal(map[string][]string{DefaultRevision: {"1.8.1"}, "canary": {"1.9.0"}}, status.Versions)
+	// Revisions are expected to run different versions
+	assert.False(status.VersionMismatch)
+	k8s.AssertNumberOfCalls(t, "GetPodProxy", 3)
+}
+
+// Rollout in progress: a new replica is pending and the running replicas of the revision disagree on version
+func TestIstiodStatusVersionMismatch(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	terminating := fakeIstiodPod("istiod-7d5bc9c4d9-old", "", "1.8.0", "Running")
+	now := meta_v1.Now()
+	terminating.DeletionTimestamp = &now
+
+	k8s := mockDeploymentCall([]apps_v1.Deployment{}, []v1.Pod{
+		fakeIstiodPod("istiod-7d5bc9c4d9-a1b2c", "", "1.8.1", "Running"),
+		fakeIstiodPod("istiod-5c8b7d6f4-j7k8l", "", "1.8.2", "Running"),
+		fakeIstiodPod("istiod-5c8b7d6f4-m9n0o", "", "1.8.2", "Pending"),
+		terminating,
+	}, true)
+	iss := IstioStatusService{k8s: k8s}
+
+	status, err := iss.GetIstiodStatus()
+	assert.NoError(err)
+	assert.Equal(Unhealthy, status.Status)
+	assert.True(status.VersionMismatch)
+	assert.Equal([]string{"1.8.1", "1.8.2"}, status.Versions[DefaultRevision])
+	// Terminating replicas are not reported
+	assert.Len(status.Replicas, 3)
+	assert.Equal(NotReady, status.Replicas[2].Status)
+	k8s.AssertNumberOfCalls(t, "GetPodProxy", 2)
+}
+
+func TestIstiodStatusUnreachable(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	k8s := mockDeploymentCall([]apps_v1.Deployment{}, []v1.Pod{
+		fakeIstiodPod("istiod-7d5bc9c4d9-a1b2c", "", "1.8.1", "Running"),
+		fakeIstiodPod("istiod-canary-6f9c8d7b5-d3e4f", "canary", "1.9.0", "Running"),
+	}, false)
+	iss := IstioStatusService{k8s: k8s}
+
+	status, err := iss.GetIstiodStatus()
+	assert.NoError(err)
+	assert.Equal(Unreachable, status.Status)
+	for _, replica := range status.Replicas {
+		assert.Equal(Unreachable, replica.Status)
+	}
+}
+
+func TestIstiodStatusNotFound(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	k8s := mockDeploymentCall([]apps_v1.Deployment{}, []v1.Pod{}, true)
+	iss := IstioStatusService{k8s: k8s}
+
+	status, err := iss.GetIstiodStatus()
+	assert.NoError(err)
+	assert.Equal(NotFound, status.Status)
+	assert.Empty(status.Replicas)
+}
+
+// Remote control plane: there isn't any local istiod
+func TestExternalIstiodStatus(t *testing.T) {
+	assert := assert.New(t)
+
+	mr := mux.NewRouter()
+	mr.HandleFunc("/version", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("1.9.0\n"))
+	})
+	httpServer := mockServer(mr)
+	defer httpServer.Close()
+
+	conf := config.NewConfig()
+	conf.ExternalServices.Istio.ExternalIstiod = true
+	conf.ExternalServices.Istio.UrlServiceVersion = httpServer.URL + "/version"
+	config.Set(conf)
+
+	k8s := new(kubetest.K8SClientMock)
+	iss := IstioStatusService{k8s: k8s}
+
+	status, err := iss.GetIstiodStatus()
+	assert.NoError(err)
+	assert.Equal(Healthy, status.Status)
+	assert.True(status.External)
+	assert.Empty(status.Replicas)
+	assert.Equal(map[string][]string{DefaultRevision: {"1.9.0"}}, status.Versions)
+	k8s.AssertNotCalled(t, "GetPods", mock.Anything, mock.Anything)
+}
+
+// External istiod must not be reported as not found in the component status
+func TestExternalIstiodComponentStatus(t *testing.T) {
+	assert := assert.New(t)
+
+	pods := []apps_v1.Deployment{
+		fakeDeploymentWithStatus("istio-ingressgateway", map[string]string{"app": "istio-ingressgateway", "istio": "ingressgateway"}, healthyStatus),
+	}
+
+	k8s, httpServer, _, _, _ := mockAddOnsCalls(pods, []v1.Pod{}, true)
+	defer httpServer.Close()
+
+	c := config.Get()
+	c.ExternalServices.Istio.ExternalIstiod = true
+	c.ExternalServices.Istio.ComponentStatuses = config.ComponentStatuses{
+		Enabled: true,
+		Components: []config.ComponentStatus{
+			{AppLabel: "istiod", IsCore: true},
+			{AppLabel: "istio-ingressgateway", IsCore: true},
+		},
+	}
+	config.Set(c)
+
+	iss := IstioStatusService{k8s: k8s}
+
+	icsl, err := iss.GetStatus()
+	assert.NoError(err)
+	assertNotPresent(assert, icsl, "istiod")
+	assertNotPresent(assert, icsl, "istio-ingressgateway")
+	k8s.AssertNotCalled(t, "GetPodProxy", mock.Anything, mock.Anything, mock.Anything)
+}
+
 func assertComponent(assert *assert.Assertions, icsl IstioComponentStatus, name string, status string, isCore bool) {
 	componentFound := false
 	for _, ics := range icsl {
@@ -630,3 +776,82 @@ func addonAddMockUrls(baseUrl string, conf *config.Config) *config.Config {
 	conf.ExternalServices.CustomDashboards.Prometheus.URL = baseUrl + "/prometheus-dashboards/mock"
 	return conf
 }
+
+func fakeIstiodPod(name, revision, version, phase string) v1.Pod {
+	pod := fakePod(name, "istio-system", "istiod", phase)
+	if revision != "" {
+		pod.Labels[IstioRevisionLabel] = revision
+	}
+	pod.Spec.Containers = []v1.Container{
+		{Name: "discovery", Image: "docker.io/istio/pilot:" + version},
+	}
+	return pod
+}
+
+func TestGetPilotMetrics(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	queryTime := time.Date(2021, 01, 15, 0, 0, 0, 0, time.UTC)
+	prom := new(prometheustest.PromClientMock)
+	// Recorded from an istiod 1.8 pushing to the bookinfo proxies
+	prom.On("FetchHistogramValues", "pilot_proxy_convergence_time", "", "", "5m", true, pilotConvergenceQuantiles, queryTime).Return(map[string]model.Vector{
+		"avg":  {&model.Sample{Metric: model.Metric{}, Value: 0.0213}},
+		"0.5":  {&model.Sample{Metric: model.Metric{}, Value: 0.0069}},
+		"0.9":  {&model.Sample{Metric: model.Metric{}, Value: 0.0812}},
+		"0.99": {&model.Sample{Metric: model.Metric{}, Value: 0.4621}},
+	}, nil)
+	prom.On("FetchRateValues", "pilot_xds_pushes", "", "type", "5m", queryTime).Return(model.Vector{
+		&model.Sample{Metric: model.Metric{"type": "cds"}, Value: 0.2},
+		&model.Sample{Metric: model.Metric{"type": "eds"}, Value: 1.1},
+		&model.Sample{Metric: model.Metric{"type": "lds"}, Value: 0.2},
+		&model.Sample{Metric: model.Metric{"type": "rds"}, Value: 0.4},
+		&model.Sample{Metric: model.Metric{"type": "eds_senderr"}, Value: 0.05},
+	}, nil)
+	prom.On("FetchRateValues", "pilot_xds_push_errors", "", "", "5m", queryTime).Return(model.Vector{
+		&model.Sample{Metric: model.Metric{}, Value: 0.05},
+	}, nil)
+
+	iss := IstioStatusService{prom: prom}
+	metrics, err := iss.GetPilotMetrics("5m", queryTime)
+	assert.NoError(err)
+
+	assert.True(metrics.Available)
+	assert.Equal([]models.Stat{
+		{Name: "0.5", Value: 0.0069},
+		{Name: "0.9", Value: 0.0812},
+		{Name: "0.99", Value: 0.4621},
+		{Name: "avg", Value: 0.0213},
+	}, metrics.ConvergenceTime)
+	assert.InDelta(1.9, metrics.PushRate, 0.0001)
+	assert.Len(metrics.PushRatesByType, 5)
+	assert.InDelta(0.1, metrics.PushErrorRate, 0.0001)
+	assert.InDelta(0.05, *metrics.PushErrorRatio, 0.0001)
+}
+
+func TestGetPilotMetricsAbsent(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	queryTime := time.Date(2021, 01, 15, 0, 0, 0, 0, time.UTC)
+	prom := new(prometheustest.PromClientMock)
+	// istiod not scraped: the histogram queries return NaN or nothing at all
+	prom.On("FetchHistogramValues", "pilot_proxy_convergence_time", "", "", "5m", true, pilotConvergenceQuantiles, queryTime).Return(map[string]model.Vector{
+		"avg":  {&model.Sample{Metric: model.Metric{}, Value: model.SampleValue(math.NaN())}},
+		"0.5":  {},
+		"0.9":  {},
+		"0.99": {},
+	}, nil)
+	prom.On("FetchRateValues", "pilot_xds_pushes", "", "type", "5m", queryTime).Return(model.Vector{}, nil)
+	prom.On("FetchRateValues", "pilot_xds_push_errors", "", "", "5m", queryTime).Return(model.Vector{}, nil)
+
+	iss := IstioStatusService{prom: prom}
+	metrics, err := iss.GetPilotMetrics("5m", queryTime)
+	assert.NoError(err)
+
+	assert.False(metrics.Available)
+	assert.Empty(metrics.ConvergenceTime)
+	assert.Zero(metrics.PushRate)
+	assert.Empty(metrics.PushRatesByType)
+	assert.Nil(metrics.PushErrorRatio)
+}
@@ -3,19 +3,26 @@ package business
 import (
 	"fmt"
 	"sync"
+	"time"
 
+	"github.com/prometheus/common/model"
 	apps_v1 "k8s.io/api/apps/v1"
 	core_v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 
 	"github.com/kiali/kiali/business/checkers"
+	"github.com/kiali/kiali/business/checkers/gateways"
 	"github.com/kiali/kiali/config"
 	"github.com/kiali/kiali/kubernetes"
 	"github.com/kiali/kiali/log"
 	"github.com/kiali/kiali/models"
 	"github.com/kiali/kiali/prometheus/internalmetrics"
+	"github.com/kiali/kiali/util"
 )
 
+// trafficProtocolsRateInterval is the interval of the metrics used to find the protocols of the traffic of a service
+const trafficProtocolsRateInterval = "10m"
+
 type IstioValidationsService struct {
 	k8s           kubernetes.ClientInterface
 	businessLayer *Layer
@@ -49,6 +56,12 @@ func (in *IstioValidationsService) GetValidations(namespace, service string) (mo
 		}
 	}
 
+	return in.runValidations(namespace, service, nil, nil)
+}
+
+// runValidations runs the enabled checkers on the namespace, without the deleted Istio object when set, to
+// simulate its deletion. The Istio config of the namespace is fetched unless already fetched by the caller.
+func (in *IstioValidationsService) runValidations(namespace, service string, deleted *models.IstioValidationKey, nsConfig *namespaceIstioConfig) (models.IstioValidations, error) {
 	wg := sync.WaitGroup{}
 	errChan := make(chan error, 1)
 
@@ -62,24 +75,44 @@ func (in *IstioValidationsService) GetValidations(namespace, service string) (mo
 	var mtlsDetails kubernetes.MTLSDetails
 	var rbacDetails kubernetes.RBACDetails
 	var deployments []apps_v1.Deployment
+	var trafficProtocols map[string][]string
+	var remoteRegistries []ClusterRegistry
+	var allServices []core_v1.Service
+	var allServiceEntries []kubernetes.IstioObject
+	var allSidecars []kubernetes.IstioObject
+	var meshConfig *models.MeshConfig
+
+	var credentialSecrets gateways.CredentialSecrets
+
+	// The credentials of the Gateways are fetched once the Gateways and the workloads selecting them are fetched
+	gatewaysFetched := sync.WaitGroup{}
+	gatewaysFetched.Add(2)
 
-	wg.Add(8) // We need to add these here to make sure we don't execute wg.Wait() before scheduler has started goroutines
+	wg.Add(5) // We need to add these here to make sure we don't execute wg.Wait() before scheduler has started goroutines
+
+	if nsConfig != nil {
+		istioDetails, mtlsDetails, rbacDetails = nsConfig.istioDetails, nsConfig.mtlsDetails, nsConfig.rbacDetails
+	} else {
+		wg.Add(3)
+		go in.fetchDetails(&istioDetails, namespace, errChan, &wg)
+		go in.fetchNonLocalmTLSConfigs(&mtlsDetails, namespace, errChan, &wg)
+		go in.fetchAuthorizationDetails(&rbacDetails, namespace, errChan, &wg)
+	}
 
 	if service != "" {
 		// These resources are not used if no service is targeted
 		wg.Add(2)
 		go in.fetchDeployments(&deployments, namespace, errChan, &wg)
-		go in.fetchPods(&pods, namespace, errChan, &wg)
+		go in.fetchTrafficProtocols(&trafficProtocols, namespace, service, util.Clock.Now(), &wg)
 	}
 
 	// We fetch without target service as some validations will require full-namespace details
-	go in.fetchDetails(&istioDetails, namespace, errChan, &wg)
 	go in.fetchNamespaces(&namespaces, errChan, &wg)
+	go in.fetchPods(&pods, namespace, errChan, &wg)
 	go in.fetchWorkloads(&workloads, namespace, errChan, &wg)
-	go in.fetchAllWorkloads(&workloadsPerNamespace, errChan, &wg)
-	go in.fetchGatewaysPerNamespace(&gatewaysPerNamespace, errChan, &wg)
-	go in.fetchNonLocalmTLSConfigs(&mtlsDetails, namespace, errChan, &wg)
-	go in.fetchAuthorizationDetails(&rbacDetails, namespace, errChan, &wg)
+	go in.fetchAllWorkloads(&workloadsPerNamespace, errChan, &gatewaysFetched)
+	go in.fetchGatewaysPerNamespace(&gatewaysPerNamespace, errChan, &gatewaysFetched)
+	go in.fetchCredentialSecrets(&credentialSecrets, namespace, &gatewaysPerNamespace, &workloadsPerNamespace, &gatewaysFetched, &wg)
 	go in.fetchServices(&services, namespace, errChan, &wg)
 
 	wg.Wait()
@@ -90,10 +123,48 @@ func (in *IstioValidationsService) GetValidations(namespace, service string) (mo
 		}
 	}
 
-	objectCheckers := in.getAllObjectCheckers(namespace, istioDetails, services, workloadsPerNamespace, workloads, gatewaysPerNamespace, mtlsDetails, rbacDetails, namespaces)
+	// The resources of the whole mesh are only fetched when the checkers consuming them have objects to validate
+	meshWg := sync.WaitGroup{}
+	meshErrChan := make(chan error, 1)
+	checkServiceEntries := len(istioDetails.ServiceEntries) > 0
+	checkExtensionProviders := len(istioDetails.Telemetries) > 0 || hasCustomAuthorizationPolicies(rbacDetails.AuthorizationPolicies)
+	if checkServiceEntries || checkExtensionProviders {
+		meshWg.Add(2)
+		go in.fetchAllServices(&allServices, meshErrChan, &meshWg)
+		go in.fetchAllServiceEntries(&allServiceEntries, meshErrChan, &meshWg)
+	}
+	if checkServiceEntries {
+		meshWg.Add(1)
+		go in.fetchAllSidecars(&allSidecars, meshErrChan, &meshWg)
+	}
+	if checkExtensionProviders {
+		meshWg.Add(1)
+		go in.fetchMeshConfig(&meshConfig, &meshWg)
+	}
+	// The remote clusters are only queried for the hosts of the VirtualServices and DestinationRules
+	if len(istioDetails.VirtualServices) > 0 || len(istioDetails.DestinationRules) > 0 {
+		meshWg.Add(1)
+		go in.fetchRemoteRegistries(&remoteRegistries, namespace, &meshWg)
+	}
+	meshWg.Wait()
+	close(meshErrChan)
+	for e := range meshErrChan {
+		if e != nil {
+			return nil, e
+		}
+	}
+
+	if deleted != nil {
+		gatewaysPerNamespace = removeDeletedObject(*deleted, &istioDetails, &mtlsDetails, &rbacDetails, gatewaysPerNamespace, &allServiceEntries, &allSidecars)
+	}
+
+	extensionProviders := resolveExtensionProviders(meshConfig, namespaces, allServices, allServiceEntries)
+	objectCheckers := in.getAllObjectCheckers(namespace, istioDetails, services, allServices, allServiceEntries, allSidecars, workloadsPerNamespace, workloads, gatewaysPerNamespace, credentialSecrets, mtlsDetails, rbacDetails, namespaces, remoteRegistries, extensionProviders)
 
 	if service != "" {
-		objectCheckers = append(objectCheckers, in.getServiceCheckers(namespace, services, deployments, pods)...)
+		objectCheckers = append(objectCheckers, in.getServiceCheckers(namespace, services, deployments, pods, trafficProtocols)...)
+	} else {
+		objectCheckers = append(objectCheckers, checkers.InjectionChecker{Namespace: namespace, Namespaces: namespaces, Pods: pods})
 	}
 
 	// Get group validations for same kind istio objects
@@ -105,23 +176,101 @@ func (in *IstioValidationsService) GetValidations(namespace, service string) (mo
 	return validations, nil
 }
 
-func (in *IstioValidationsService) getServiceCheckers(namespace string, services []core_v1.Service, deployments []apps_v1.Deployment, pods []core_v1.Pod) []ObjectChecker {
+// hasCustomAuthorizationPolicies tells whether any of the AuthorizationPolicies delegates the authorization to an
+// extension provider
+func hasCustomAuthorizationPolicies(authorizationPolicies []kubernetes.IstioObject) bool {
+	for _, ap := range authorizationPolicies {
+		if action, _ := ap.GetSpec()["action"].(string); action == "CUSTOM" {
+			return true
+		}
+	}
+	return false
+}
+
+// GetFilteredValidations returns the validations of the Istio objects of the namespace matching the filter, along
+// with the number of objects and checks left out, so that only the relevant findings of large namespaces are sent.
+// The object types of the filter, Istio objects, services or workloads, can be either plural or singular.
+func (in *IstioValidationsService) GetFilteredValidations(namespace string, filter models.IstioValidationsFilter) (models.FilteredIstioValidations, error) {
+	var err error
+	promtimer := internalmetrics.GetGoFunctionMetric("business", "IstioValidationsService", "GetFilteredValidations")
+	defer promtimer.ObserveNow(&err)
+
+	if filter.MinSeverity != "" && !models.IsValidSeverity(filter.MinSeverity) {
+		err = errors.NewBadRequest(fmt.Sprintf("Invalid severity [%s], expected one of [error, warning, unknown]", filter.MinSeverity))
+		return models.FilteredIstioValidations{}, err
+	}
+	objectTypes := make([]string, 0, len(filter.ObjectTypes))
+	for _, objectType := range filter.ObjectTypes {
+		singular, valid := validationObjectType(objectType)
+		if !valid {
+			err = errors.NewBadRequest(fmt.Sprintf("Invalid object type [%s]", objectType))
+			return models.FilteredIstioValidations{}, err
+		}
+		objectTypes = append(objectTypes, singular)
+	}
+	filter.ObjectTypes = objectTypes
+
+	validations, err := in.GetValidations(namespace, "")
+	if err != nil {
+		return models.FilteredIstioValidations{}, err
+	}
+	// The validations of the objects of other namespaces referenced by the namespace are not counted
+	namespaceValidations := models.IstioValidations{}
+	for k, v := range validations {
+		if k.Namespace == namespace {
+			namespaceValidations[k] = v
+		}
+	}
+	return namespaceValidations.FilterBy(filter), nil
+}
+
+// validationObjectType returns the singular type of the validated objects, the workloads, services, namespaces and
+// pods are validated along with the Istio objects
+func validationObjectType(objectType string) (string, bool) {
+	switch objectType {
+	case "services", checkers.ServiceCheckerType:
+		return checkers.ServiceCheckerType, true
+	case "workloads", checkers.WorkloadCheckerType:
+		return checkers.WorkloadCheckerType, true
+	case "namespaces", checkers.NamespaceCheckerType:
+		return checkers.NamespaceCheckerType, true
+	case "pods", checkers.PodCheckerType:
+		return checkers.PodCheckerType, true
+	}
+	if singular, found := models.ObjectTypeSingular[objectType]; found {
+		return singular, true
+	}
+	for _, singular := range models.ObjectTypeSingular {
+		if singular == objectType {
+			return singular, true
+		}
+	}
+	return "", false
+}
+
+func (in *IstioValidationsService) getServiceCheckers(namespace string, services []core_v1.Service, deployments []apps_v1.Deployment, pods []core_v1.Pod, trafficProtocols map[string][]string) []ObjectChecker {
 	return []ObjectChecker{
-		checkers.ServiceChecker{Services: services, Deployments: deployments, Pods: pods},
+		checkers.ServiceChecker{Services: services, Deployments: deployments, Pods: pods, TrafficProtocols: trafficProtocols, Endpoints: in.businessLayer.Svc.getServicesEndpoints(namespace, services)},
 	}
 }
 
-func (in *IstioValidationsService) getAllObjectCheckers(namespace string, istioDetails kubernetes.IstioDetails, services []core_v1.Service, workloadsPerNamespace map[string]models.WorkloadList, workloads models.WorkloadList, gatewaysPerNamespace [][]kubernetes.IstioObject, mtlsDetails kubernetes.MTLSDetails, rbacDetails kubernetes.RBACDetails, namespaces []models.Namespace) []ObjectChecker {
+func (in *IstioValidationsService) getAllObjectCheckers(namespace string, istioDetails kubernetes.IstioDetails, services []core_v1.Service, allServices []core_v1.Service, allServiceEntries []kubernetes.IstioObject, allSidecars []kubernetes.IstioObject, workloadsPerNamespace map[string]models.WorkloadList, workloads models.WorkloadList, gatewaysPerNamespace [][]kubernetes.IstioObject, credentialSecrets gateways.CredentialSecrets, mtlsDetails kubernetes.MTLSDetails, rbacDetails kubernetes.RBACDetails, namespaces []models.Namespace, remoteRegistries []ClusterRegistry, extensionProviders models.ExtensionProviders) []ObjectChecker {
+	meshServices, meshWorkloads := combineRegistries(services, workloads, remoteRegistries)
 	return []ObjectChecker{
-		checkers.NoServiceChecker{Namespace: namespace, Namespaces: namespaces, IstioDetails: &istioDetails, Services: services, WorkloadList: workloads, GatewaysPerNamespace: gatewaysPerNamespace, AuthorizationDetails: &rbacDetails},
-		checkers.VirtualServiceChecker{Namespace: namespace, Namespaces: namespaces, DestinationRules: istioDetails.DestinationRules, VirtualServices: istioDetails.VirtualServices},
+		checkers.NoServiceChecker{Namespace: namespace, Namespaces: namespaces, IstioDetails: &istioDetails, Services: meshServices, WorkloadList: meshWorkloads, GatewaysPerNamespace: gatewaysPerNamespace, AuthorizationDetails: &rbacDetails},
+		// The subsets may be defined by the DestinationRules of any namespace
+		checkers.VirtualServiceChecker{Namespace: namespace, Namespaces: namespaces, DestinationRules: mtlsDetails.DestinationRules, VirtualServices: istioDetails.VirtualServices},
 		checkers.DestinationRulesChecker{Namespaces: namespaces, DestinationRules: istioDetails.DestinationRules, MTLSDetails: mtlsDetails, ServiceEntries: istioDetails.ServiceEntries},
-		checkers.GatewayChecker{GatewaysPerNamespace: gatewaysPerNamespace, Namespace: namespace, WorkloadsPerNamespace: workloadsPerNamespace},
-		checkers.PeerAuthenticationChecker{PeerAuthentications: mtlsDetails.PeerAuthentications, MTLSDetails: mtlsDetails, WorkloadList: workloads},
-		checkers.ServiceEntryChecker{ServiceEntries: istioDetails.ServiceEntries},
-		checkers.AuthorizationPolicyChecker{AuthorizationPolicies: rbacDetails.AuthorizationPolicies, Namespace: namespace, Namespaces: namespaces, Services: services, ServiceEntries: istioDetails.ServiceEntries, WorkloadList: workloads, MtlsDetails: mtlsDetails, VirtualServices: istioDetails.VirtualServices},
+		checkers.GatewayChecker{GatewaysPerNamespace: gatewaysPerNamespace, Namespace: namespace, WorkloadsPerNamespace: workloadsPerNamespace, CredentialSecrets: credentialSecrets},
+		checkers.PeerAuthenticationChecker{Namespace: namespace, PeerAuthentications: mtlsDetails.PeerAuthentications, MTLSDetails: mtlsDetails, WorkloadList: workloads},
+		checkers.ServiceEntryChecker{ServiceEntries: istioDetails.ServiceEntries, MeshServiceEntries: allServiceEntries, MeshSidecars: allSidecars, MeshServices: allServices, Namespaces: namespaces},
+		checkers.AuthorizationPolicyChecker{AuthorizationPolicies: rbacDetails.AuthorizationPolicies, Namespace: namespace, Namespaces: namespaces, Services: services, ServiceEntries: istioDetails.ServiceEntries, WorkloadList: workloads, MtlsDetails: mtlsDetails, VirtualServices: istioDetails.VirtualServices, ExtensionProviders: extensionProviders},
 		checkers.SidecarChecker{Sidecars: istioDetails.Sidecars, Namespaces: namespaces, WorkloadList: workloads, Services: services, ServiceEntries: istioDetails.ServiceEntries},
-		checkers.RequestAuthenticationChecker{RequestAuthentications: istioDetails.RequestAuthentications, WorkloadList: workloads},
+		checkers.RequestAuthenticationChecker{RequestAuthentications: istioDetails.RequestAuthentications, WorkloadList: workloads, AuthorizationDetails: rbacDetails},
+		checkers.WorkloadChecker{Namespace: namespace, Namespaces: namespaces, WorkloadList: workloads},
+		checkers.ProxyConfigChecker{ProxyConfigs: istioDetails.ProxyConfigs, WorkloadList: workloads},
+		checkers.EnvoyFilterChecker{EnvoyFilters: istioDetails.EnvoyFilters, MeshEnvoyFilters: istioDetails.MeshEnvoyFilters},
+		checkers.TelemetryChecker{Telemetries: istioDetails.Telemetries, ExtensionProviders: extensionProviders},
 	}
 }
 
@@ -138,8 +287,12 @@ func (in *IstioValidationsService) GetIstioObjectValidations(namespace string, o
 	var gatewaysPerNamespace [][]kubernetes.IstioObject
 	var mtlsDetails kubernetes.MTLSDetails
 	var rbacDetails kubernetes.RBACDetails
-
-	var objectCheckers []ObjectChecker
+	var remoteRegistries []ClusterRegistry
+	var allServices []core_v1.Service
+	var allServiceEntries []kubernetes.IstioObject
+	var allSidecars []kubernetes.IstioObject
+	var meshConfig *models.MeshConfig
+	var credentialSecrets gateways.CredentialSecrets
 
 	// Check if user has access to the namespace (RBAC) in cache scenarios and/or
 	// if namespace is accessible from Kiali (Deployment.AccessibleNamespaces)
@@ -151,70 +304,370 @@ func (in *IstioValidationsService) GetIstioObjectValidations(namespace string, o
 	errChan := make(chan error, 1)
 
 	// Get all the Istio objects from a Namespace and all gateways from every namespace
-	wg.Add(8)
+	gatewaysFetched := sync.WaitGroup{}
+	gatewaysFetched.Add(2)
+	wg.Add(6)
+	if objectType == kubernetes.Gateways {
+		wg.Add(1)
+		go in.fetchCredentialSecrets(&credentialSecrets, namespace, &gatewaysPerNamespace, &workloadsPerNamespace, &gatewaysFetched, &wg)
+	}
+	switch objectType {
+	case kubernetes.ServiceEntries:
+		wg.Add(3)
+		go in.fetchAllServices(&allServices, errChan, &wg)
+		go in.fetchAllServiceEntries(&allServiceEntries, errChan, &wg)
+		go in.fetchAllSidecars(&allSidecars, errChan, &wg)
+	case kubernetes.AuthorizationPolicies, kubernetes.Telemetries:
+		// The services of the extension providers are looked for in the whole mesh
+		wg.Add(3)
+		go in.fetchAllServices(&allServices, errChan, &wg)
+		go in.fetchAllServiceEntries(&allServiceEntries, errChan, &wg)
+		go in.fetchMeshConfig(&meshConfig, &wg)
+	case kubernetes.VirtualServices, kubernetes.DestinationRules:
+		// The hosts are also resolved against the registries of the remote clusters
+		wg.Add(1)
+		go in.fetchRemoteRegistries(&remoteRegistries, namespace, &wg)
+	}
 	go in.fetchNamespaces(&namespaces, errChan, &wg)
 	go in.fetchDetails(&istioDetails, namespace, errChan, &wg)
 	go in.fetchServices(&services, namespace, errChan, &wg)
 	go in.fetchWorkloads(&workloads, namespace, errChan, &wg)
-	go in.fetchAllWorkloads(&workloadsPerNamespace, errChan, &wg)
-	go in.fetchGatewaysPerNamespace(&gatewaysPerNamespace, errChan, &wg)
+	go in.fetchAllWorkloads(&workloadsPerNamespace, errChan, &gatewaysFetched)
+	go in.fetchGatewaysPerNamespace(&gatewaysPerNamespace, errChan, &gatewaysFetched)
 	go in.fetchNonLocalmTLSConfigs(&mtlsDetails, namespace, errChan, &wg)
 	go in.fetchAuthorizationDetails(&rbacDetails, namespace, errChan, &wg)
 	wg.Wait()
+	gatewaysFetched.Wait()
+
+	close(errChan)
+	for e := range errChan {
+		if e != nil { // Check that default value wasn't returned
+			return nil, err
+		}
+	}
+
+	meshServices, meshWorkloads := combineRegistries(services, workloads, remoteRegistries)
+	data := objectValidationsData{
+		istioDetails:          istioDetails,
+		namespaces:            namespaces,
+		services:              services,
+		meshServices:          meshServices,
+		workloads:             workloads,
+		meshWorkloads:         meshWorkloads,
+		workloadsPerNamespace: workloadsPerNamespace,
+		gatewaysPerNamespace:  gatewaysPerNamespace,
+		mtlsDetails:           mtlsDetails,
+		rbacDetails:           rbacDetails,
+		allServices:           allServices,
+		allServiceEntries:     allServiceEntries,
+		allSidecars:           allSidecars,
+		credentialSecrets:     credentialSecrets,
+		meshConfig:            meshConfig,
+	}
 
-	noServiceChecker := checkers.NoServiceChecker{Namespace: namespace, Namespaces: namespaces, IstioDetails: &istioDetails, Services: services, WorkloadList: workloads, GatewaysPerNamespace: gatewaysPerNamespace, AuthorizationDetails: &rbacDetails}
+	objectCheckers, err := getObjectCheckers(namespace, objectType, data)
+	if objectCheckers == nil {
+		return models.IstioValidations{}, err
+	}
+
+	return runObjectCheckers(objectCheckers).FilterByKey(models.ObjectTypeSingular[objectType], object), nil
+}
+
+// GetClusterIstioObjectValidations validates the Istio object of a remote cluster against the registry of that
+// cluster. Its namespaces, mesh config, Istio config, services and pods are read with the client of the cluster, as
+// the cache only holds the ones of the home cluster.
+func (in *IstioValidationsService) GetClusterIstioObjectValidations(client kubernetes.ClientInterface, namespace, objectType, object string) (models.IstioValidations, error) {
+	var err error
+	promtimer := internalmetrics.GetGoFunctionMetric("business", "IstioValidationsService", "GetClusterIstioObjectValidations")
+	defer promtimer.ObserveNow(&err)
+
+	var namespaces models.Namespaces
+	var services []core_v1.Service
+	var pods []core_v1.Pod
+	var meshConfig *models.MeshConfig
+	var meshConfigErr error
+	var icm *kubernetes.IstioMeshConfig
+	var ns *models.Namespace
+	rootNamespace := config.Get().IstioNamespace
+	objects := map[string][]kubernetes.IstioObject{}
+	rootObjects := map[string][]kubernetes.IstioObject{}
+	objectsLock := sync.Mutex{}
+
+	namespaceService := NewClusterNamespaceService(client)
+	if ns, err = namespaceService.GetNamespace(namespace); err != nil {
+		return nil, err
+	}
+
+	wg := sync.WaitGroup{}
+	errChan := make(chan error, 1)
+	sendError := func(e error) {
+		select {
+		case errChan <- e:
+		default:
+		}
+	}
+
+	namespaceTypes := []string{kubernetes.VirtualServices, kubernetes.DestinationRules, kubernetes.ServiceEntries, kubernetes.Gateways,
+		kubernetes.Sidecars, kubernetes.RequestAuthentications, kubernetes.ProxyConfigs, kubernetes.EnvoyFilters, kubernetes.Telemetries,
+		kubernetes.PeerAuthentications, kubernetes.AuthorizationPolicies}
+	// The root namespace objects apply to the whole mesh
+	rootTypes := []string{kubernetes.EnvoyFilters, kubernetes.PeerAuthentications, kubernetes.AuthorizationPolicies, kubernetes.DestinationRules, kubernetes.Sidecars}
+
+	wg.Add(len(namespaceTypes) + len(rootTypes) + 4)
+	for _, objectType := range namespaceTypes {
+		go func(resourceType string) {
+			defer wg.Done()
+			istioObjects, e := client.GetIstioObjects(namespace, resourceType, "")
+			if e != nil {
+				sendError(e)
+				return
+			}
+			objectsLock.Lock()
+			objects[resourceType] = istioObjects
+			objectsLock.Unlock()
+		}(objectType)
+	}
+	for _, objectType := range rootTypes {
+		go func(resourceType string) {
+			defer wg.Done()
+			if rootNamespace == namespace {
+				return
+			}
+			istioObjects, e := client.GetIstioObjects(rootNamespace, resourceType, "")
+			if e != nil {
+				if !checkForbidden("GetClusterMeshObjects", e, "probably Kiali doesn't have access to the root namespace of the cluster") {
+					sendError(e)
+				}
+				return
+			}
+			objectsLock.Lock()
+			rootObjects[resourceType] = istioObjects
+			objectsLock.Unlock()
+		}(objectType)
+	}
+	go func() {
+		defer wg.Done()
+		var e error
+		if namespaces, e = namespaceService.GetNamespaces(); e != nil {
+			sendError(e)
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		var e error
+		if services, e = client.GetServices(namespace, nil); e != nil {
+			sendError(e)
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		var e error
+		if pods, e = client.GetPods(namespace, ""); e != nil {
+			sendError(e)
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		icm, meshConfigErr = namespaceMeshConfig(ns, func(name string) (*core_v1.ConfigMap, error) {
+			return client.GetConfigMap(rootNamespace, name)
+		})
+	}()
+	if objectType == kubernetes.AuthorizationPolicies || objectType == kubernetes.Telemetries {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var e error
+			if meshConfig, e = getClusterEffectiveMeshConfig(client); e != nil {
+				log.Warningf("Error fetching the mesh config of the cluster, the references to the extension providers are not validated: %s", e)
+			}
+		}()
+	}
+	wg.Wait()
+
+	close(errChan)
+	for e := range errChan {
+		if e != nil {
+			err = e
+			return nil, err
+		}
+	}
+	if meshConfigErr != nil {
+		err = meshConfigErr
+		return nil, err
+	}
+
+	rootNamespaceObjects := func(resourceType string) []kubernetes.IstioObject {
+		if rootNamespace == namespace {
+			return objects[resourceType]
+		}
+		return rootObjects[resourceType]
+	}
+	istioDetails := kubernetes.IstioDetails{
+		VirtualServices:        objects[kubernetes.VirtualServices],
+		DestinationRules:       objects[kubernetes.DestinationRules],
+		ServiceEntries:         objects[kubernetes.ServiceEntries],
+		Gateways:               objects[kubernetes.Gateways],
+		Sidecars:               objects[kubernetes.Sidecars],
+		RequestAuthentications: objects[kubernetes.RequestAuthentications],
+		ProxyConfigs:           objects[kubernetes.ProxyConfigs],
+		EnvoyFilters:           objects[kubernetes.EnvoyFilters],
+		Telemetries:            objects[kubernetes.Telemetries],
+	}
+	if rootNamespace != namespace {
+		istioDetails.MeshEnvoyFilters = rootObjects[kubernetes.EnvoyFilters]
+	}
+	meshSidecars := objects[kubernetes.Sidecars]
+	if rootNamespace != namespace {
+		meshSidecars = append(append([]kubernetes.IstioObject{}, meshSidecars...), rootObjects[kubernetes.Sidecars]...)
+	}
+	destinationRules := objects[kubernetes.DestinationRules]
+	if rootNamespace != namespace {
+		destinationRules = append(append([]kubernetes.IstioObject{}, destinationRules...), rootObjects[kubernetes.DestinationRules]...)
+	}
+
+	// The workloads of the cluster are its pods, holding their labels
+	_, workloads := combineRegistries(nil, models.WorkloadList{Namespace: models.Namespace{Name: namespace}}, []ClusterRegistry{{Pods: pods}})
+	data := objectValidationsData{
+		istioDetails:          istioDetails,
+		namespaces:            namespaces,
+		services:              services,
+		meshServices:          services,
+		workloads:             workloads,
+		meshWorkloads:         workloads,
+		workloadsPerNamespace: map[string]models.WorkloadList{namespace: workloads},
+		gatewaysPerNamespace:  [][]kubernetes.IstioObject{istioDetails.Gateways},
+		credentialSecrets:     gateways.CredentialSecrets{Secrets: []core_v1.Secret{}, Unreadable: map[string]bool{}},
+		mtlsDetails: kubernetes.MTLSDetails{
+			DestinationRules:        destinationRules,
+			MeshPeerAuthentications: rootNamespaceObjects(kubernetes.PeerAuthentications),
+			PeerAuthentications:     objects[kubernetes.PeerAuthentications],
+			EnabledAutoMtls:         icm.GetEnableAutoMtls(),
+		},
+		rbacDetails: kubernetes.RBACDetails{
+			AuthorizationPolicies:     objects[kubernetes.AuthorizationPolicies],
+			MeshAuthorizationPolicies: rootNamespaceObjects(kubernetes.AuthorizationPolicies),
+		},
+		allServices:       services,
+		allServiceEntries: istioDetails.ServiceEntries,
+		allSidecars:       meshSidecars,
+		meshConfig:        meshConfig,
+	}
+	if objectType == kubernetes.Gateways {
+		data.credentialSecrets = getCredentialSecrets(client, istioDetails.Gateways, data.workloadsPerNamespace)
+	}
+
+	objectCheckers, err := getObjectCheckers(namespace, objectType, data)
+	if objectCheckers == nil {
+		return models.IstioValidations{}, err
+	}
+
+	return runObjectCheckers(objectCheckers).FilterByKey(models.ObjectTypeSingular[objectType], object), nil
+}
+
+// objectValidationsData holds the Istio config and the registry the checkers of an Istio object are run against
+type objectValidationsData struct {
+	istioDetails          kubernetes.IstioDetails
+	namespaces            models.Namespaces
+	services              []core_v1.Service
+	meshServices          []core_v1.Service
+	workloads             models.WorkloadList
+	meshWorkloads         models.WorkloadList
+	workloadsPerNamespace map[string]models.WorkloadList
+	gatewaysPerNamespace  [][]kubernetes.IstioObject
+	credentialSecrets     gateways.CredentialSecrets
+	mtlsDetails           kubernetes.MTLSDetails
+	rbacDetails           kubernetes.RBACDetails
+	allServices           []core_v1.Service
+	allServiceEntries     []kubernetes.IstioObject
+	allSidecars           []kubernetes.IstioObject
+	meshConfig            *models.MeshConfig
+}
+
+// getObjectCheckers returns the checkers validating the Istio objects of the type in the namespace
+func getObjectCheckers(namespace, objectType string, data objectValidationsData) ([]ObjectChecker, error) {
+	istioDetails, mtlsDetails, rbacDetails := data.istioDetails, data.mtlsDetails, data.rbacDetails
+	noServiceChecker := checkers.NoServiceChecker{Namespace: namespace, Namespaces: data.namespaces, IstioDetails: &istioDetails, Services: data.meshServices, WorkloadList: data.meshWorkloads, GatewaysPerNamespace: data.gatewaysPerNamespace, AuthorizationDetails: &rbacDetails}
 
 	switch objectType {
 	case kubernetes.Gateways:
-		objectCheckers = []ObjectChecker{
-			checkers.GatewayChecker{GatewaysPerNamespace: gatewaysPerNamespace, Namespace: namespace, WorkloadsPerNamespace: workloadsPerNamespace},
-		}
+		return []ObjectChecker{
+			checkers.GatewayChecker{GatewaysPerNamespace: data.gatewaysPerNamespace, Namespace: namespace, WorkloadsPerNamespace: data.workloadsPerNamespace, CredentialSecrets: data.credentialSecrets},
+		}, nil
 	case kubernetes.VirtualServices:
-		virtualServiceChecker := checkers.VirtualServiceChecker{Namespace: namespace, Namespaces: namespaces, VirtualServices: istioDetails.VirtualServices, DestinationRules: istioDetails.DestinationRules}
-		objectCheckers = []ObjectChecker{noServiceChecker, virtualServiceChecker}
+		virtualServiceChecker := checkers.VirtualServiceChecker{Namespace: namespace, Namespaces: data.namespaces, VirtualServices: istioDetails.VirtualServices, DestinationRules: mtlsDetails.DestinationRules}
+		return []ObjectChecker{noServiceChecker, virtualServiceChecker}, nil
 	case kubernetes.DestinationRules:
-		destinationRulesChecker := checkers.DestinationRulesChecker{Namespaces: namespaces, DestinationRules: istioDetails.DestinationRules, MTLSDetails: mtlsDetails, ServiceEntries: istioDetails.ServiceEntries}
-		objectCheckers = []ObjectChecker{noServiceChecker, destinationRulesChecker}
+		destinationRulesChecker := checkers.DestinationRulesChecker{Namespaces: data.namespaces, DestinationRules: istioDetails.DestinationRules, MTLSDetails: mtlsDetails, ServiceEntries: istioDetails.ServiceEntries}
+		return []ObjectChecker{noServiceChecker, destinationRulesChecker}, nil
 	case kubernetes.ServiceEntries:
-		serviceEntryChecker := checkers.ServiceEntryChecker{ServiceEntries: istioDetails.ServiceEntries}
-		objectCheckers = []ObjectChecker{serviceEntryChecker}
+		serviceEntryChecker := checkers.ServiceEntryChecker{ServiceEntries: istioDetails.ServiceEntries, MeshServiceEntries: data.allServiceEntries, MeshSidecars: data.allSidecars, MeshServices: data.allServices, Namespaces: data.namespaces}
+		return []ObjectChecker{serviceEntryChecker}, nil
 	case kubernetes.Sidecars:
-		sidecarsChecker := checkers.SidecarChecker{Sidecars: istioDetails.Sidecars, Namespaces: namespaces,
-			WorkloadList: workloads, Services: services, ServiceEntries: istioDetails.ServiceEntries}
-		objectCheckers = []ObjectChecker{sidecarsChecker}
+		sidecarsChecker := checkers.SidecarChecker{Sidecars: istioDetails.Sidecars, Namespaces: data.namespaces,
+			WorkloadList: data.workloads, Services: data.services, ServiceEntries: istioDetails.ServiceEntries}
+		return []ObjectChecker{sidecarsChecker}, nil
 	case kubernetes.AuthorizationPolicies:
 		authPoliciesChecker := checkers.AuthorizationPolicyChecker{AuthorizationPolicies: rbacDetails.AuthorizationPolicies,
-			Namespace: namespace, Namespaces: namespaces, Services: services, ServiceEntries: istioDetails.ServiceEntries,
-			WorkloadList: workloads, MtlsDetails: mtlsDetails, VirtualServices: istioDetails.VirtualServices}
-		objectCheckers = []ObjectChecker{authPoliciesChecker}
+			Namespace: namespace, Namespaces: data.namespaces, Services: data.services, ServiceEntries: istioDetails.ServiceEntries,
+			WorkloadList: data.workloads, MtlsDetails: mtlsDetails, VirtualServices: istioDetails.VirtualServices,
+			ExtensionProviders: resolveExtensionProviders(data.meshConfig, data.namespaces, data.allServices, data.allServiceEntries)}
+		return []ObjectChecker{authPoliciesChecker}, nil
 	case kubernetes.PeerAuthentications:
 		// Validations on PeerAuthentications
-		peerAuthnChecker := checkers.PeerAuthenticationChecker{PeerAuthentications: mtlsDetails.PeerAuthentications, MTLSDetails: mtlsDetails, WorkloadList: workloads}
-		objectCheckers = []ObjectChecker{peerAuthnChecker}
+		peerAuthnChecker := checkers.PeerAuthenticationChecker{Namespace: namespace, PeerAuthentications: mtlsDetails.PeerAuthentications, MTLSDetails: mtlsDetails, WorkloadList: data.workloads}
+		return []ObjectChecker{peerAuthnChecker}, nil
 	case kubernetes.WorkloadEntries:
 		// Validation on WorkloadEntries are not yet in place
+		return nil, nil
 	case kubernetes.RequestAuthentications:
-		// Validation on RequestAuthentications are not yet in place
-		requestAuthnChecker := checkers.RequestAuthenticationChecker{RequestAuthentications: istioDetails.RequestAuthentications, WorkloadList: workloads}
-		objectCheckers = []ObjectChecker{requestAuthnChecker}
+		requestAuthnChecker := checkers.RequestAuthenticationChecker{RequestAuthentications: istioDetails.RequestAuthentications, WorkloadList: data.workloads, AuthorizationDetails: rbacDetails}
+		return []ObjectChecker{requestAuthnChecker}, nil
 	case kubernetes.EnvoyFilters:
-		// Validation on EnvoyFilters are not yet in place
+		envoyFilterChecker := checkers.EnvoyFilterChecker{EnvoyFilters: istioDetails.EnvoyFilters, MeshEnvoyFilters: istioDetails.MeshEnvoyFilters}
+		return []ObjectChecker{envoyFilterChecker}, nil
+	case kubernetes.ProxyConfigs:
+		proxyConfigChecker := checkers.ProxyConfigChecker{ProxyConfigs: istioDetails.ProxyConfigs, WorkloadList: data.workloads}
+		return []ObjectChecker{proxyConfigChecker}, nil
+	case kubernetes.Telemetries:
+		telemetryChecker := checkers.TelemetryChecker{Telemetries: istioDetails.Telemetries,
+			ExtensionProviders: resolveExtensionProviders(data.meshConfig, data.namespaces, data.allServices, data.allServiceEntries)}
+		return []ObjectChecker{telemetryChecker}, nil
 	default:
-		err = fmt.Errorf("object type not found: %v", objectType)
+		return nil, fmt.Errorf("object type not found: %v", objectType)
 	}
+}
 
-	close(errChan)
-	for e := range errChan {
-		if e != nil { // Check that default value wasn't returned
-			return nil, err
-		}
+// combineRegistries adds the services and the pods of the remote registries of the namespace to the local ones,
+// so the references to services of the namespace that are only defined in a remote cluster are resolved.
+// A remote pod is added as a workload holding its labels.
+func combineRegistries(services []core_v1.Service, workloads models.WorkloadList, remoteRegistries []ClusterRegistry) ([]core_v1.Service, models.WorkloadList) {
+	if len(remoteRegistries) == 0 {
+		return services, workloads
 	}
 
-	if objectCheckers == nil {
-		return models.IstioValidations{}, err
+	serviceNames := make(map[string]bool, len(services))
+	meshServices := make([]core_v1.Service, 0, len(services))
+	for _, svc := range services {
+		serviceNames[svc.Name] = true
+		meshServices = append(meshServices, svc)
+	}
+	meshWorkloads := models.WorkloadList{
+		Namespace: workloads.Namespace,
+		Workloads: append([]models.WorkloadListItem{}, workloads.Workloads...),
 	}
 
-	return runObjectCheckers(objectCheckers).FilterByKey(models.ObjectTypeSingular[objectType], object), nil
+	for _, registry := range remoteRegistries {
+		for _, svc := range registry.Services {
+			if !serviceNames[svc.Name] {
+				serviceNames[svc.Name] = true
+				meshServices = append(meshServices, svc)
+			}
+		}
+		for _, pod := range registry.Pods {
+			meshWorkloads.Workloads = append(meshWorkloads.Workloads, models.WorkloadListItem{Name: pod.Name, Labels: pod.Labels})
+		}
+	}
+	return meshServices, meshWorkloads
 }
 
 func runObjectCheckers(objectCheckers []ObjectChecker) models.IstioValidations {
@@ -231,6 +684,50 @@ func runObjectCheckers(objectCheckers []ObjectChecker) models.IstioValidations {
 // The following idea is used underneath: if errChan has at least one record, we'll effectively cancel the request (if scheduled in such order). On the other hand, if we can't
 // write to the buffered errChan, we just ignore the error as select does not block even if channel is full. This is because a single error is enough to cancel the whole request.
 
+// fetchCredentialSecrets fetches the Secrets of the credentialNames of the Gateways of the namespace, once the
+// Gateways and the workloads selected by them are fetched
+func (in *IstioValidationsService) fetchCredentialSecrets(rValue *gateways.CredentialSecrets, namespace string, gatewaysPerNamespace *[][]kubernetes.IstioObject, workloadsPerNamespace *map[string]models.WorkloadList, fetched *sync.WaitGroup, wg *sync.WaitGroup) {
+	defer wg.Done()
+	fetched.Wait()
+	gws := []kubernetes.IstioObject{}
+	for _, nsGws := range *gatewaysPerNamespace {
+		for _, gw := range nsGws {
+			if gw.GetObjectMeta().Namespace == namespace {
+				gws = append(gws, gw)
+			}
+		}
+	}
+	*rValue = getCredentialSecrets(in.k8s, gws, *workloadsPerNamespace)
+}
+
+// getCredentialSecrets gets the Secrets of the credentialNames of the Gateways. The missing Secrets are reported by
+// the CredentialChecker, the namespaces whose Secrets can't be read are marked as unreadable.
+func getCredentialSecrets(client kubernetes.ClientInterface, gws []kubernetes.IstioObject, workloadsPerNamespace map[string]models.WorkloadList) gateways.CredentialSecrets {
+	credentialSecrets := gateways.CredentialSecrets{Secrets: []core_v1.Secret{}, Unreadable: map[string]bool{}}
+	fetched := map[string]bool{}
+	for _, gw := range gws {
+		for _, ns := range gateways.CredentialNamespaces(gw, workloadsPerNamespace) {
+			for _, name := range gateways.CredentialNames(gw) {
+				key := ns + "/" + name
+				if fetched[key] || credentialSecrets.Unreadable[ns] {
+					continue
+				}
+				fetched[key] = true
+				secret, err := client.GetSecret(ns, name)
+				if err != nil {
+					if !errors.IsNotFound(err) {
+						log.Debugf("Unable to read the Secret [%s] for the Gateway credentials: %v", key, err)
+						credentialSecrets.Unreadable[ns] = true
+					}
+					continue
+				}
+				credentialSecrets.Secrets = append(credentialSecrets.Secrets, *secret)
+			}
+		}
+	}
+	return credentialSecrets
+}
+
 func (in *IstioValidationsService) fetchGatewaysPerNamespace(gatewaysPerNamespace *[][]kubernetes.IstioObject, errChan chan error, wg *sync.WaitGroup) {
 	defer wg.Done()
 	if nss, err := in.businessLayer.Namespace.GetNamespaces(); err == nil {
@@ -263,6 +760,41 @@ func (in *IstioValidationsService) fetchGatewaysPerNamespace(gatewaysPerNamespac
 	}
 }
 
+// namespaceIstioConfig is the Istio config of a namespace, fetched once to be shared by the sections of the namespace
+// overview: the config counts, the mTLS status and the validations
+type namespaceIstioConfig struct {
+	istioDetails    kubernetes.IstioDetails
+	mtlsDetails     kubernetes.MTLSDetails
+	rbacDetails     kubernetes.RBACDetails
+	workloadEntries []kubernetes.IstioObject
+}
+
+// fetchNamespaceIstioConfig fetches the Istio config of the namespace, the access to the namespace is checked by the
+// callers
+func (in *IstioValidationsService) fetchNamespaceIstioConfig(namespace string) (namespaceIstioConfig, error) {
+	wg := sync.WaitGroup{}
+	errChan := make(chan error, 1)
+	nsConfig := namespaceIstioConfig{}
+
+	wg.Add(4)
+	go in.fetchDetails(&nsConfig.istioDetails, namespace, errChan, &wg)
+	go in.fetchNonLocalmTLSConfigs(&nsConfig.mtlsDetails, namespace, errChan, &wg)
+	go in.fetchAuthorizationDetails(&nsConfig.rbacDetails, namespace, errChan, &wg)
+	getWorkloadEntries := func(namespace string) ([]kubernetes.IstioObject, error) {
+		return in.k8s.GetIstioObjects(namespace, kubernetes.WorkloadEntries, "")
+	}
+	go fetchIstioObjects(&nsConfig.workloadEntries, namespace, getWorkloadEntries, &wg, errChan)
+	wg.Wait()
+
+	close(errChan)
+	for e := range errChan {
+		if e != nil { // Check that default value wasn't returned
+			return namespaceIstioConfig{}, e
+		}
+	}
+	return nsConfig, nil
+}
+
 func fetchIstioObjects(rValue *[]kubernetes.IstioObject, namespace string, fetcher func(string) ([]kubernetes.IstioObject, error), wg *sync.WaitGroup, errChan chan error) {
 	defer wg.Done()
 	if len(errChan) == 0 {
@@ -363,6 +895,67 @@ func (in *IstioValidationsService) fetchPods(rValue *[]core_v1.Pod, namespace st
 	}
 }
 
+// fetchMeshConfig doesn't fail the validations when the mesh config can't be read: the references to the extension
+// providers are then not validated.
+func (in *IstioValidationsService) fetchMeshConfig(rValue **models.MeshConfig, wg *sync.WaitGroup) {
+	defer wg.Done()
+	meshConfig, err := in.businessLayer.Mesh.GetEffectiveMeshConfig()
+	if err != nil {
+		log.Warningf("Error fetching the mesh config, the references to the extension providers are not validated: %s", err)
+		return
+	}
+	*rValue = meshConfig
+}
+
+// fetchRemoteRegistries doesn't fail the validations when the remote clusters can't be resolved:
+// the validations are then run with the local registry only.
+func (in *IstioValidationsService) fetchRemoteRegistries(rValue *[]ClusterRegistry, namespace string, wg *sync.WaitGroup) {
+	defer wg.Done()
+	registries, err := in.businessLayer.Mesh.GetRemoteRegistries(namespace)
+	if err != nil {
+		log.Warningf("Error fetching the remote registries of namespace [%s]: %s", namespace, err)
+		return
+	}
+	*rValue = registries
+}
+
+// fetchTrafficProtocols reads the protocols of the traffic received by the service from the metrics: http and/or grpc
+// for the requests, tcp for the connections. The metrics are optional, the protocols are left unknown on errors.
+func (in *IstioValidationsService) fetchTrafficProtocols(rValue *map[string][]string, namespace, service string, queryTime time.Time, wg *sync.WaitGroup) {
+	defer wg.Done()
+	prom := in.businessLayer.Svc.prom
+	if prom == nil {
+		return
+	}
+
+	labels := fmt.Sprintf(`{%s="destination",%s="%s",%s="%s"}`, telemetryLabel("reporter"), telemetryLabel("destination_service_namespace"), namespace, telemetryLabel("destination_service_name"), service)
+	lblProtocol := telemetryLabel("request_protocol")
+	requests, err := prom.FetchRateValues(telemetryMetric("istio_requests_total"), labels, lblProtocol, trafficProtocolsRateInterval, queryTime)
+	if err != nil {
+		log.Warningf("Error fetching the request protocols of service [%s.%s]: %s", service, namespace, err)
+		return
+	}
+	connections, err := prom.FetchRateValues(telemetryMetric("istio_tcp_connections_opened_total"), labels, "", trafficProtocolsRateInterval, queryTime)
+	if err != nil {
+		log.Warningf("Error fetching the tcp connections of service [%s.%s]: %s", service, namespace, err)
+		return
+	}
+
+	protocols := []string{}
+	for _, sample := range requests {
+		if protocol := string(sample.Metric[model.LabelName(lblProtocol)]); protocol != "" && sample.Value > 0 {
+			protocols = append(protocols, protocol)
+		}
+	}
+	for _, sample := range connections {
+		if sample.Value > 0 {
+			protocols = append(protocols, models.TCPProtocol)
+			break
+		}
+	}
+	*rValue = map[string][]string{service: protocols}
+}
+
 func (in *IstioValidationsService) fetchWorkloads(rValue *models.WorkloadList, namespace string, errChan chan error, wg *sync.WaitGroup) {
 	defer wg.Done()
 	if len(errChan) == 0 {
@@ -403,12 +996,107 @@ func (in *IstioValidationsService) fetchAllWorkloads(rValue *map[string]models.W
 	}
 }
 
+// fetchAllServices fetches the services of every namespace accessible to the user, in parallel, from the cache for
+// the cached namespaces
+func (in *IstioValidationsService) fetchAllServices(rValue *[]core_v1.Service, errChan chan error, wg *sync.WaitGroup) {
+	defer wg.Done()
+	if len(errChan) == 0 {
+		nss, err := in.businessLayer.Namespace.GetNamespaces()
+		if err != nil {
+			select {
+			case errChan <- err:
+			default:
+			}
+			return
+		}
+		servicesPerNamespace := make([][]core_v1.Service, len(nss))
+		nsWg := sync.WaitGroup{}
+		nsWg.Add(len(nss))
+		for i, ns := range nss {
+			go func(i int, namespace string) {
+				defer nsWg.Done()
+				var services []core_v1.Service
+				var err error
+				if IsNamespaceCached(namespace) {
+					services, err = kialiCache.GetServices(namespace, nil)
+				} else {
+					services, err = in.k8s.GetServices(namespace, nil)
+				}
+				if err != nil {
+					select {
+					case errChan <- err:
+					default:
+					}
+					return
+				}
+				servicesPerNamespace[i] = services
+			}(i, ns.Name)
+		}
+		nsWg.Wait()
+
+		allServices := []core_v1.Service{}
+		for _, services := range servicesPerNamespace {
+			allServices = append(allServices, services...)
+		}
+		*rValue = allServices
+	}
+}
+
+// fetchAllServiceEntries fetches the ServiceEntries of every namespace accessible to the user
+func (in *IstioValidationsService) fetchAllServiceEntries(rValue *[]kubernetes.IstioObject, errChan chan error, wg *sync.WaitGroup) {
+	in.fetchAllIstioObjects(rValue, kubernetes.ServiceEntries, errChan, wg)
+}
+
+// fetchAllSidecars fetches the Sidecars of every namespace accessible to the user
+func (in *IstioValidationsService) fetchAllSidecars(rValue *[]kubernetes.IstioObject, errChan chan error, wg *sync.WaitGroup) {
+	in.fetchAllIstioObjects(rValue, kubernetes.Sidecars, errChan, wg)
+}
+
+// fetchAllIstioObjects fetches the Istio objects of the type of every namespace accessible to the user, in parallel,
+// from the cache for the cached namespaces
+func (in *IstioValidationsService) fetchAllIstioObjects(rValue *[]kubernetes.IstioObject, resourceType string, errChan chan error, wg *sync.WaitGroup) {
+	defer wg.Done()
+	if len(errChan) == 0 {
+		nss, err := in.businessLayer.Namespace.GetNamespaces()
+		if err != nil {
+			select {
+			case errChan <- err:
+			default:
+			}
+			return
+		}
+		objectsPerNamespace := make([][]kubernetes.IstioObject, len(nss))
+		nsWg := sync.WaitGroup{}
+		nsWg.Add(len(nss))
+		for i, ns := range nss {
+			var getObjects func(string) ([]kubernetes.IstioObject, error)
+			if IsResourceCached(ns.Name, resourceType) {
+				getObjects = func(namespace string) ([]kubernetes.IstioObject, error) {
+					return kialiCache.GetIstioObjects(namespace, resourceType, "")
+				}
+			} else {
+				getObjects = func(namespace string) ([]kubernetes.IstioObject, error) {
+					return in.k8s.GetIstioObjects(namespace, resourceType, "")
+				}
+			}
+			go fetchIstioObjects(&objectsPerNamespace[i], ns.Name, getObjects, &nsWg, errChan)
+		}
+		nsWg.Wait()
+
+		allObjects := []kubernetes.IstioObject{}
+		for _, objects := range objectsPerNamespace {
+			allObjects = append(allObjects, objects...)
+		}
+		*rValue = allObjects
+	}
+}
+
 func (in *IstioValidationsService) fetchDetails(rValue *kubernetes.IstioDetails, namespace string, errChan chan error, wg *sync.WaitGroup) {
 	defer wg.Done()
 	if len(errChan) == 0 {
 		var err error
 		wg2 := sync.WaitGroup{}
-		errChan2 := make(chan error, 5)
+		errChan2 := make(chan error, 10)
 		istioDetails := kubernetes.IstioDetails{}
 
 		if IsResourceCached(namespace, kubernetes.VirtualServices) {
@@ -465,6 +1153,37 @@ func (in *IstioValidationsService) fetchDetails(rValue *kubernetes.IstioDetails,
 			}
 			go fetchIstioObjects(&istioDetails.RequestAuthentications, namespace, getRequestAuthentications, &wg2, errChan2)
 		}
+		wg2.Add(1)
+		getProxyConfigs := func(namespace string) ([]kubernetes.IstioObject, error) {
+			return in.k8s.GetIstioObjects(namespace, kubernetes.ProxyConfigs, "")
+		}
+		go fetchIstioObjects(&istioDetails.ProxyConfigs, namespace, getProxyConfigs, &wg2, errChan2)
+		wg2.Add(1)
+		getTelemetries := func(namespace string) ([]kubernetes.IstioObject, error) {
+			return in.k8s.GetIstioObjects(namespace, kubernetes.Telemetries, "")
+		}
+		go fetchIstioObjects(&istioDetails.Telemetries, namespace, getTelemetries, &wg2, errChan2)
+		if IsResourceCached(namespace, kubernetes.EnvoyFilters) {
+			istioDetails.EnvoyFilters, err = kialiCache.GetIstioObjects(namespace, kubernetes.EnvoyFilters, "")
+		} else {
+			wg2.Add(1)
+			getEnvoyFilters := func(namespace string) ([]kubernetes.IstioObject, error) {
+				return in.k8s.GetIstioObjects(namespace, kubernetes.EnvoyFilters, "")
+			}
+			go fetchIstioObjects(&istioDetails.EnvoyFilters, namespace, getEnvoyFilters, &wg2, errChan2)
+		}
+		// EnvoyFilters of the root namespace apply to the whole mesh
+		if rootNamespace := config.Get().IstioNamespace; rootNamespace != namespace {
+			wg2.Add(1)
+			getMeshEnvoyFilters := func(namespace string) ([]kubernetes.IstioObject, error) {
+				efs, efErr := in.k8s.GetIstioObjects(namespace, kubernetes.EnvoyFilters, "")
+				if efErr != nil && checkForbidden("GetMeshEnvoyFilters", efErr, "probably Kiali doesn't have access to the root namespace") {
+					return []kubernetes.IstioObject{}, nil
+				}
+				return efs, efErr
+			}
+			go fetchIstioObjects(&istioDetails.MeshEnvoyFilters, rootNamespace, getMeshEnvoyFilters, &wg2, errChan2)
+		}
 		wg2.Wait()
 
 		// Error may come either from errChan2 (when goroutines are used / without cache) or err (with cache / synchronous)
@@ -528,20 +1247,8 @@ func (in *IstioValidationsService) fetchNonLocalmTLSConfigs(mtlsDetails *kuberne
 
 	go func(details *kubernetes.MTLSDetails) {
 		defer wg.Done()
-		cfg := config.Get()
 
-		var istioConfig *core_v1.ConfigMap
-		var err error
-		if IsNamespaceCached(cfg.IstioNamespace) {
-			istioConfig, err = kialiCache.GetConfigMap(cfg.IstioNamespace, cfg.ExternalServices.Istio.ConfigMapName)
-		} else {
-			istioConfig, err = in.k8s.GetConfigMap(cfg.IstioNamespace, cfg.ExternalServices.Istio.ConfigMapName)
-		}
-		if err != nil {
-			errChan <- err
-			return
-		}
-		icm, err := kubernetes.GetIstioConfigMap(istioConfig)
+		icm, err := in.getNamespaceMeshConfig(namespace)
 		if err != nil {
 			errChan <- err
 		} else {
@@ -568,15 +1275,77 @@ func (in *IstioValidationsService) fetchNonLocalmTLSConfigs(mtlsDetails *kuberne
 	}
 }
 
+// getNamespaceMeshConfig returns the mesh config of the control plane revision used by the namespace (istio.io/rev label).
+// Each revision reads its own "<config_map_name>-<revision>" ConfigMap, the default revision reads the configured one.
+// When the ConfigMap of the revision is not found, the default one is used.
+func (in *IstioValidationsService) getNamespaceMeshConfig(namespace string) (*kubernetes.IstioMeshConfig, error) {
+	var ns *models.Namespace
+	if namespace != "" {
+		var err error
+		if ns, err = in.businessLayer.Namespace.GetNamespace(namespace); err != nil {
+			return nil, err
+		}
+	}
+	return namespaceMeshConfig(ns, in.getIstioConfigMap)
+}
+
+// namespaceMeshConfig returns the mesh config of the control plane revision used by the namespace, reading the
+// ConfigMaps of the istio namespace with getConfigMap. A nil namespace reads the default mesh config.
+func namespaceMeshConfig(ns *models.Namespace, getConfigMap func(name string) (*core_v1.ConfigMap, error)) (*kubernetes.IstioMeshConfig, error) {
+	configMapName := config.Get().ExternalServices.Istio.ConfigMapName
+
+	if ns != nil {
+		if revision := ns.Labels[IstioRevisionLabel]; revision != "" && revision != DefaultRevision {
+			revisionConfig, err := getConfigMap(configMapName + "-" + revision)
+			if err == nil {
+				return kubernetes.GetIstioConfigMap(revisionConfig)
+			}
+			if !errors.IsNotFound(err) {
+				return nil, err
+			}
+			log.Debugf("Mesh config of revision [%s] not found, validating namespace [%s] with the default one", revision, ns.Name)
+		}
+	}
+
+	istioConfig, err := getConfigMap(configMapName)
+	if err != nil {
+		return nil, err
+	}
+	return kubernetes.GetIstioConfigMap(istioConfig)
+}
+
+func (in *IstioValidationsService) getIstioConfigMap(name string) (*core_v1.ConfigMap, error) {
+	istioNamespace := config.Get().IstioNamespace
+	if IsNamespaceCached(istioNamespace) {
+		return kialiCache.GetConfigMap(istioNamespace, name)
+	}
+	return in.k8s.GetConfigMap(istioNamespace, name)
+}
+
 func (in *IstioValidationsService) fetchAuthorizationDetails(rValue *kubernetes.RBACDetails, namespace string, errChan chan error, wg *sync.WaitGroup) {
 	defer wg.Done()
 	if len(errChan) == 0 {
 		var err error
 		authDetails := &kubernetes.RBACDetails{}
 
-		innerErrChan := make(chan error, 1)
+		innerErrChan := make(chan error, 2)
 		var wg sync.WaitGroup
-		wg.Add(1)
+		wg.Add(2)
+
+		go func(errChan chan error) {
+			defer wg.Done()
+			// AuthorizationPolicies in the root namespace apply to the whole mesh
+			istioNamespace := config.Get().IstioNamespace
+			var err error
+			if IsResourceCached(istioNamespace, kubernetes.AuthorizationPolicies) {
+				authDetails.MeshAuthorizationPolicies, err = kialiCache.GetIstioObjects(istioNamespace, kubernetes.AuthorizationPolicies, "")
+			} else {
+				authDetails.MeshAuthorizationPolicies, err = in.k8s.GetIstioObjects(istioNamespace, kubernetes.AuthorizationPolicies, "")
+			}
+			if err != nil && !checkForbidden("GetMeshAuthorizationPolicies", err, "probably Kiali doesn't have cluster permissions") {
+				errChan <- err
+			}
+		}(innerErrChan)
 
 		go func(errChan chan error) {
 			defer wg.Done()
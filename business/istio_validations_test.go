@@ -1,22 +1,32 @@
 package business
 
 import (
+	"sync"
 	"testing"
+	"time"
 
 	osapps_v1 "github.com/openshift/api/apps/v1"
+	"github.com/prometheus/common/model"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"gopkg.in/yaml.v2"
 	apps_v1 "k8s.io/api/apps/v1"
 	batch_v1 "k8s.io/api/batch/v1"
 	batch_v1beta1 "k8s.io/api/batch/v1beta1"
 	core_v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
 	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/rest"
 
+	"github.com/kiali/kiali/business/checkers"
 	"github.com/kiali/kiali/config"
 	"github.com/kiali/kiali/kubernetes"
 	"github.com/kiali/kiali/kubernetes/kubetest"
 	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/prometheus/prometheustest"
 	"github.com/kiali/kiali/tests/data"
+	"github.com/kiali/kiali/util"
 )
 
 func TestGetNamespaceValidations(t *testing.T) {
@@ -44,6 +54,166 @@ func TestGetIstioObjectValidations(t *testing.T) {
 	assert.NotEmpty(validations)
 }
 
+func TestGetValidationsResolvesRemoteServices(t *testing.T) {
+	assert := assert.New(t)
+	conf := config.NewConfig()
+	config.Set(conf)
+
+	vsKey := models.IstioValidationKey{ObjectType: "virtualservice", Namespace: "test", Name: "product-vs"}
+	drKey := models.IstioValidationKey{ObjectType: "destinationrule", Namespace: "test", Name: "product-dr"}
+
+	// The product service is not defined in cluster A, where the VirtualService and the DestinationRule live
+	vs := mockCombinedValidationService(fakeCombinedIstioDetails(), []string{"customer"}, fakePods())
+	validations, err := vs.GetValidations("test", "")
+	assert.NoError(err)
+	assert.False(validations[vsKey].Valid)
+	assert.False(validations[drKey].Valid)
+
+	// But it is defined in cluster B
+	newRemoteClient := func(config *rest.Config) (kubernetes.ClientInterface, error) {
+		remoteClient := new(kubetest.K8SClientMock)
+		remoteClient.On("GetServices", "test", mock.Anything).Return([]core_v1.Service{
+			{
+				ObjectMeta: meta_v1.ObjectMeta{Name: "product", Namespace: "test"},
+				Spec:       core_v1.ServiceSpec{Selector: map[string]string{"app": "product"}},
+			},
+		}, nil)
+		remoteClient.On("GetPods", "test", "").Return([]core_v1.Pod{
+			{ObjectMeta: meta_v1.ObjectMeta{Name: "product-v1-12345", Namespace: "test", Labels: map[string]string{"app": "product", "version": "v1"}}},
+		}, nil)
+		return remoteClient, nil
+	}
+	vs = mockMultiClusterValidationService(fakeCombinedIstioDetails(), []string{"customer"}, fakePods(), []core_v1.Secret{fakeRemoteSecret("cluster-b")}, newRemoteClient)
+	validations, err = vs.GetValidations("test", "")
+	assert.NoError(err)
+	assert.True(validations[vsKey].Valid)
+	assert.True(validations[drKey].Valid)
+
+	validations, err = vs.GetIstioObjectValidations("test", "virtualservices", "product-vs")
+	assert.NoError(err)
+	assert.True(validations[vsKey].Valid)
+}
+
+func TestGetValidationsIgnoresRemoteServicesNotExported(t *testing.T) {
+	assert := assert.New(t)
+	conf := config.NewConfig()
+	config.Set(conf)
+
+	newRemoteClient := func(config *rest.Config) (kubernetes.ClientInterface, error) {
+		remoteClient := new(kubetest.K8SClientMock)
+		remoteClient.On("GetServices", "test", mock.Anything).Return([]core_v1.Service{
+			{
+				ObjectMeta: meta_v1.ObjectMeta{
+					Name:        "product",
+					Namespace:   "test",
+					Annotations: map[string]string{"networking.istio.io/exportTo": "~"},
+				},
+				Spec: core_v1.ServiceSpec{Selector: map[string]string{"app": "product"}},
+			},
+		}, nil)
+		remoteClient.On("GetPods", "test", "").Return([]core_v1.Pod{}, nil)
+		return remoteClient, nil
+	}
+	vs := mockMultiClusterValidationService(fakeCombinedIstioDetails(), []string{"customer"}, fakePods(), []core_v1.Secret{fakeRemoteSecret("cluster-b")}, newRemoteClient)
+	validations, err := vs.GetValidations("test", "")
+	assert.NoError(err)
+	assert.False(validations[models.IstioValidationKey{ObjectType: "virtualservice", Namespace: "test", Name: "product-vs"}].Valid)
+}
+
+func TestGetValidationsQueriesRemoteClustersOnDemand(t *testing.T) {
+	assert := assert.New(t)
+	conf := config.NewConfig()
+	config.Set(conf)
+
+	remoteQueried := false
+	newRemoteClient := func(config *rest.Config) (kubernetes.ClientInterface, error) {
+		remoteQueried = true
+		remoteClient := new(kubetest.K8SClientMock)
+		remoteClient.On("GetServices", "test", mock.Anything).Return([]core_v1.Service{}, nil)
+		remoteClient.On("GetPods", "test", "").Return([]core_v1.Pod{}, nil)
+		return remoteClient, nil
+	}
+
+	// Only the hosts of the VirtualServices and DestinationRules are resolved against the remote clusters
+	vs := mockMultiClusterValidationService(fakeCombinedIstioDetails(), []string{"customer"}, fakePods(), []core_v1.Secret{fakeRemoteSecret("cluster-b")}, newRemoteClient)
+	_, err := vs.GetIstioObjectValidations("test", "gateways", "first")
+	assert.NoError(err)
+	assert.False(remoteQueried)
+
+	_, err = vs.GetIstioObjectValidations("test", "virtualservices", "product-vs")
+	assert.NoError(err)
+	assert.True(remoteQueried)
+}
+
+func TestGetValidationsFetchesMeshServiceEntriesOnDemand(t *testing.T) {
+	assert := assert.New(t)
+	conf := config.NewConfig()
+	config.Set(conf)
+
+	// Without ServiceEntries in the namespace, the ServiceEntries and Sidecars of the mesh are not needed
+	vs := mockCombinedValidationService(fakeCombinedIstioDetails(), []string{"details", "product", "customer"}, fakePods())
+	_, err := vs.GetValidations("test", "")
+	assert.NoError(err)
+	k8s := vs.k8s.(*kubetest.K8SClientMock)
+	k8s.AssertNotCalled(t, "GetIstioObjects", "test2", "serviceentries", "")
+	k8s.AssertNotCalled(t, "GetIstioObjects", "test2", "sidecars", "")
+
+	istioDetails := fakeCombinedIstioDetails()
+	istioDetails.ServiceEntries = []kubernetes.IstioObject{data.CreateExternalServiceEntry()}
+	vs = mockCombinedValidationService(istioDetails, []string{"details", "product", "customer"}, fakePods())
+	_, err = vs.GetValidations("test", "")
+	assert.NoError(err)
+	k8s = vs.k8s.(*kubetest.K8SClientMock)
+	k8s.AssertCalled(t, "GetIstioObjects", "test2", "serviceentries", "")
+	k8s.AssertCalled(t, "GetIstioObjects", "test2", "sidecars", "")
+}
+
+func TestGetFilteredValidations(t *testing.T) {
+	assert := assert.New(t)
+	conf := config.NewConfig()
+	config.Set(conf)
+
+	// Without the product service, the VirtualService and the DestinationRule of the namespace are not valid
+	vs := mockCombinedValidationService(fakeCombinedIstioDetails(), []string{"customer"}, fakePods())
+	all, err := vs.GetValidations("test", "")
+	assert.NoError(err)
+
+	filtered, err := vs.GetFilteredValidations("test", models.IstioValidationsFilter{ObjectTypes: []string{"virtualservices"}, MinSeverity: models.ErrorSeverity})
+	assert.NoError(err)
+	assert.NotEmpty(filtered.Validations)
+	for k, v := range filtered.Validations {
+		assert.Equal("virtualservice", k.ObjectType)
+		for _, c := range v.Checks {
+			assert.Equal(models.ErrorSeverity, c.Severity)
+		}
+	}
+	// The objects of other namespaces are neither returned nor counted
+	objects := 0
+	for k := range all {
+		if k.Namespace == "test" {
+			objects++
+		}
+	}
+	assert.Equal(objects, len(filtered.Validations)+filtered.FilteredOut.Objects)
+
+	// Singular types are accepted
+	singular, err := vs.GetFilteredValidations("test", models.IstioValidationsFilter{ObjectTypes: []string{"virtualservice"}, MinSeverity: models.ErrorSeverity})
+	assert.NoError(err)
+	assert.Equal(filtered, singular)
+}
+
+func TestGetFilteredValidationsBadRequest(t *testing.T) {
+	assert := assert.New(t)
+	conf := config.NewConfig()
+	config.Set(conf)
+
+	vs := mockCombinedValidationService(fakeCombinedIstioDetails(), []string{"customer"}, fakePods())
+	_, err := vs.GetFilteredValidations("test", models.IstioValidationsFilter{MinSeverity: "critical"})
+	assert.True(errors.IsBadRequest(err))
+	_, err = vs.GetFilteredValidations("test", models.IstioValidationsFilter{ObjectTypes: []string{"virtualservices", "deployments"}})
+	assert.True(errors.IsBadRequest(err))
+}
+
 func TestGatewayValidation(t *testing.T) {
 	assert := assert.New(t)
 	conf := config.NewConfig()
@@ -79,6 +249,7 @@ func mockMultiNamespaceGatewaysValidationService() IstioValidationsService {
 	k8s.On("GetIstioObjects", "test", "gateways", "").Return(getGateway("first"), nil)
 	k8s.On("GetIstioObjects", "test2", "gateways", "").Return(getGateway("second"), nil)
 	k8s.On("GetNamespaces", mock.AnythingOfType("string")).Return(fakeNamespaces(), nil)
+	k8s.On("GetSecrets", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return([]core_v1.Secret{}, nil)
 	mockWorkLoadService(k8s)
 	k8s.On("GetIstioObjects", mock.AnythingOfType("string"), "destinationrules", "").Return(fakeCombinedIstioDetails().DestinationRules, nil)
 	k8s.On("GetIstioObjects", mock.AnythingOfType("string"), "sidecars", "").Return(fakeCombinedIstioDetails().Sidecars, nil)
@@ -87,6 +258,9 @@ func mockMultiNamespaceGatewaysValidationService() IstioValidationsService {
 	k8s.On("GetMeshPolicies", mock.AnythingOfType("string")).Return(fakeMeshPolicies(), nil)
 	k8s.On("GetIstioObjects", mock.AnythingOfType("string"), "peerauthentications", "").Return(fakePolicies(), nil)
 	k8s.On("GetIstioObjects", mock.AnythingOfType("string"), "requestauthentications", "").Return([]kubernetes.IstioObject{}, nil)
+	k8s.On("GetIstioObjects", mock.AnythingOfType("string"), "proxyconfigs", "").Return([]kubernetes.IstioObject{}, nil)
+	k8s.On("GetIstioObjects", mock.AnythingOfType("string"), "telemetries", "").Return([]kubernetes.IstioObject{}, nil)
+	k8s.On("GetIstioObjects", mock.AnythingOfType("string"), "envoyfilters", "").Return([]kubernetes.IstioObject{}, nil)
 	k8s.On("GetIstioObjects", mock.AnythingOfType("string"), "clusterrbacconfigs", "").Return([]kubernetes.IstioObject{}, nil)
 	k8s.On("GetIstioObjects", mock.AnythingOfType("string"), "authorizationpolicies", "").Return([]kubernetes.IstioObject{}, nil)
 	k8s.On("GetIstioObjects", mock.AnythingOfType("string"), "servicerolebindings", "").Return([]kubernetes.IstioObject{}, nil)
@@ -98,9 +272,16 @@ func mockMultiNamespaceGatewaysValidationService() IstioValidationsService {
 }
 
 func mockCombinedValidationService(istioObjects *kubernetes.IstioDetails, services []string, podList *core_v1.PodList) IstioValidationsService {
+	return mockMultiClusterValidationService(istioObjects, services, podList, []core_v1.Secret{}, nil)
+}
+
+func mockMultiClusterValidationService(istioObjects *kubernetes.IstioDetails, services []string, podList *core_v1.PodList, remoteSecrets []core_v1.Secret, newRemoteClient func(config *rest.Config) (kubernetes.ClientInterface, error)) IstioValidationsService {
 	k8s := new(kubetest.K8SClientMock)
 	k8s.On("GetIstioObjects", mock.AnythingOfType("string"), "sidecars", "").Return(istioObjects.Sidecars, nil)
 	k8s.On("GetIstioObjects", mock.AnythingOfType("string"), "requestauthentications", "").Return(istioObjects.RequestAuthentications, nil)
+	k8s.On("GetIstioObjects", mock.AnythingOfType("string"), "proxyconfigs", "").Return(istioObjects.ProxyConfigs, nil)
+	k8s.On("GetIstioObjects", mock.AnythingOfType("string"), "telemetries", "").Return(istioObjects.Telemetries, nil)
+	k8s.On("GetIstioObjects", mock.AnythingOfType("string"), "envoyfilters", "").Return(istioObjects.EnvoyFilters, nil)
 	k8s.On("GetServices", mock.AnythingOfType("string"), mock.AnythingOfType("map[string]string")).Return(fakeCombinedServices(services), nil)
 	k8s.On("GetDeployments", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return(FakeDepSyncedWithRS(), nil)
 	k8s.On("GetIstioObjects", mock.AnythingOfType("string"), "virtualservices", "").Return(fakeCombinedIstioDetails().VirtualServices, nil)
@@ -109,7 +290,7 @@ func mockCombinedValidationService(istioObjects *kubernetes.IstioDetails, servic
 	k8s.On("GetIstioObjects", mock.AnythingOfType("string"), "clusterrbacconfigs", "").Return([]kubernetes.IstioObject{}, nil)
 	k8s.On("GetIstioObjects", mock.AnythingOfType("string"), "servicerolebindings", "").Return([]kubernetes.IstioObject{}, nil)
 	k8s.On("GetIstioObjects", mock.AnythingOfType("string"), "serviceroles", "").Return([]kubernetes.IstioObject{}, nil)
-	k8s.On("GetIstioObjects", mock.AnythingOfType("string"), "serviceentries", "").Return(fakeCombinedIstioDetails().ServiceEntries, nil)
+	k8s.On("GetIstioObjects", mock.AnythingOfType("string"), "serviceentries", "").Return(istioObjects.ServiceEntries, nil)
 	k8s.On("GetIstioObjects", mock.AnythingOfType("string"), "gateways", "").Return(fakeCombinedIstioDetails().Gateways, nil)
 	k8s.On("GetNamespace", mock.AnythingOfType("string")).Return(kubetest.FakeNamespace("test"), nil)
 	k8s.On("GetIstioObjects", "istio-system", "peerauthentications", "").Return(fakeMeshPolicies(), nil)
@@ -120,10 +301,51 @@ func mockCombinedValidationService(istioObjects *kubernetes.IstioDetails, servic
 	k8s.On("GetIstioObjects", "test2", "gateways", "").Return(getGateway("second"), nil)
 	k8s.On("GetIstioObjects", mock.AnythingOfType("string"), "gateways", "").Return(fakeCombinedIstioDetails().Gateways, nil)
 	k8s.On("GetNamespaces", mock.AnythingOfType("string")).Return(fakeNamespaces(), nil)
+	k8s.On("GetSecrets", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return(remoteSecrets, nil)
 
 	mockWorkLoadService(k8s)
 
-	return IstioValidationsService{k8s: k8s, businessLayer: NewWithBackends(k8s, nil, nil)}
+	// The registries of the remote clusters are cached for a while
+	util.Clock = util.RealClock{}
+	resetRemoteRegistries()
+	layer := NewWithBackends(k8s, nil, nil)
+	layer.Mesh = NewMeshService(k8s, newRemoteClient)
+	return IstioValidationsService{k8s: k8s, businessLayer: layer}
+}
+
+func fakeRemoteSecret(clusterName string) core_v1.Secret {
+	remoteSecretData := kubernetes.RemoteSecret{
+		Clusters: []kubernetes.RemoteSecretClusterListItem{
+			{
+				Name: clusterName,
+				Cluster: kubernetes.RemoteSecretCluster{
+					CertificateAuthorityData: "eAo=",
+					Server:                   "https://192.168.144.17:123",
+				},
+			},
+		},
+		Users: []kubernetes.RemoteSecretUser{
+			{
+				Name: "foo",
+				User: kubernetes.RemoteSecretUserToken{
+					Token: "bar",
+				},
+			},
+		},
+	}
+	marshalledRemoteSecretData, _ := yaml.Marshal(remoteSecretData)
+
+	return core_v1.Secret{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name: "istio-remote-secret-" + clusterName,
+			Annotations: map[string]string{
+				"networking.istio.io/cluster": clusterName,
+			},
+		},
+		Data: map[string][]byte{
+			clusterName: marshalledRemoteSecretData,
+		},
+	}
 }
 
 func fakeCombinedIstioDetails() *kubernetes.IstioDetails {
@@ -218,3 +440,175 @@ func getGateway(name string) []kubernetes.IstioObject {
 			"app": "real",
 		}))}
 }
+
+func TestValidationsWithRevisionMeshConfig(t *testing.T) {
+	assert := assert.New(t)
+	conf := config.NewConfig()
+	config.Set(conf)
+
+	canaryNs := kubetest.FakeNamespace("bookinfo-canary")
+	canaryNs.Labels = map[string]string{IstioRevisionLabel: "canary"}
+	stableNs := kubetest.FakeNamespace("bookinfo")
+	stableNs.Labels = map[string]string{"istio-injection": "enabled"}
+
+	k8s := new(kubetest.K8SClientMock)
+	k8s.On("IsOpenShift").Return(false)
+	k8s.On("IsMaistraApi").Return(false)
+	k8s.On("GetNamespace", "bookinfo-canary").Return(canaryNs, nil)
+	k8s.On("GetNamespace", "bookinfo").Return(stableNs, nil)
+	k8s.On("GetNamespaces", mock.AnythingOfType("string")).Return([]core_v1.Namespace{*canaryNs, *stableNs}, nil)
+	// The default control plane runs with auto mTLS disabled, the canary one enables it
+	k8s.On("GetConfigMap", "istio-system", "istio").Return(fakeMeshConfigMap("istio", "enableAutoMtls: false"), nil)
+	k8s.On("GetConfigMap", "istio-system", "istio-canary").Return(fakeMeshConfigMap("istio-canary", "enableAutoMtls: true"), nil)
+	k8s.On("GetIstioObjects", "istio-system", "peerauthentications", "").Return([]kubernetes.IstioObject{}, nil)
+	k8s.On("GetIstioObjects", "bookinfo-canary", "peerauthentications", "").Return([]kubernetes.IstioObject{
+		data.CreateEmptyPeerAuthentication("default", "bookinfo-canary", data.CreateMTLS("STRICT")),
+	}, nil)
+	k8s.On("GetIstioObjects", "bookinfo", "peerauthentications", "").Return([]kubernetes.IstioObject{
+		data.CreateEmptyPeerAuthentication("default", "bookinfo", data.CreateMTLS("STRICT")),
+	}, nil)
+	k8s.On("GetIstioObjects", mock.AnythingOfType("string"), "destinationrules", "").Return([]kubernetes.IstioObject{}, nil)
+
+	vs := IstioValidationsService{k8s: k8s, businessLayer: NewWithBackends(k8s, nil, nil)}
+
+	validate := func(namespace string) models.IstioValidation {
+		var mtlsDetails kubernetes.MTLSDetails
+		errChan := make(chan error, 1)
+		wg := sync.WaitGroup{}
+		wg.Add(1)
+		vs.fetchNonLocalmTLSConfigs(&mtlsDetails, namespace, errChan, &wg)
+		wg.Wait()
+		assert.Empty(errChan)
+
+		validations := checkers.PeerAuthenticationChecker{Namespace: namespace, PeerAuthentications: mtlsDetails.PeerAuthentications, MTLSDetails: mtlsDetails}.Check()
+		return *validations[models.IstioValidationKey{ObjectType: "peerauthentication", Namespace: namespace, Name: "default"}]
+	}
+
+	// Auto mTLS of the canary revision: no DestinationRule is needed
+	canaryValidation := validate("bookinfo-canary")
+	assert.True(canaryValidation.Valid)
+	assert.Empty(canaryValidation.Checks)
+
+	// Auto mTLS disabled in the default revision: a DestinationRule enabling mTLS is missing
+	stableValidation := validate("bookinfo")
+	assert.NotEmpty(stableValidation.Checks)
+	assert.Equal(models.CheckMessage("peerauthentications.mtls.destinationrulemissing"), stableValidation.Checks[0].Message)
+}
+
+func TestNamespaceMeshConfigFallsBackToDefault(t *testing.T) {
+	assert := assert.New(t)
+	conf := config.NewConfig()
+	config.Set(conf)
+
+	ns := kubetest.FakeNamespace("bookinfo")
+	ns.Labels = map[string]string{IstioRevisionLabel: "1-8-1"}
+	notFound := errors.NewNotFound(schema.GroupResource{Resource: "configmaps"}, "istio-1-8-1")
+
+	k8s := new(kubetest.K8SClientMock)
+	k8s.On("IsOpenShift").Return(false)
+	k8s.On("GetNamespace", "bookinfo").Return(ns, nil)
+	k8s.On("GetConfigMap", "istio-system", "istio-1-8-1").Return(&core_v1.ConfigMap{}, notFound)
+	k8s.On("GetConfigMap", "istio-system", "istio").Return(fakeMeshConfigMap("istio", "enableAutoMtls: false"), nil)
+
+	vs := IstioValidationsService{k8s: k8s, businessLayer: NewWithBackends(k8s, nil, nil)}
+	meshConfig, err := vs.getNamespaceMeshConfig("bookinfo")
+	assert.NoError(err)
+	assert.False(meshConfig.GetEnableAutoMtls())
+	k8s.AssertCalled(t, "GetConfigMap", "istio-system", "istio")
+}
+
+func fakeMeshConfigMap(name, mesh string) *core_v1.ConfigMap {
+	return &core_v1.ConfigMap{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      name,
+			Namespace: "istio-system",
+		},
+		Data: map[string]string{"mesh": mesh},
+	}
+}
+
+func TestFetchTrafficProtocols(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	k8s := new(kubetest.K8SClientMock)
+	k8s.On("IsOpenShift").Return(false)
+	prom := new(prometheustest.PromClientMock)
+	queryTime := time.Date(2017, 01, 15, 0, 0, 0, 0, time.UTC)
+	labels := `{reporter="destination",destination_service_namespace="bookinfo",destination_service_name="reviews"}`
+	prom.On("FetchRateValues", "istio_requests_total", labels, "request_protocol", "10m", queryTime).Return(model.Vector{
+		&model.Sample{Metric: model.Metric{"request_protocol": "http"}, Value: 2},
+		&model.Sample{Metric: model.Metric{"request_protocol": "grpc"}, Value: 0},
+	}, nil)
+	prom.On("FetchRateValues", "istio_tcp_connections_opened_total", labels, "", "10m", queryTime).Return(model.Vector{
+		&model.Sample{Metric: model.Metric{}, Value: 1},
+	}, nil)
+
+	vs := IstioValidationsService{k8s: k8s, businessLayer: NewWithBackends(k8s, prom, nil)}
+	var protocols map[string][]string
+	wg := sync.WaitGroup{}
+	wg.Add(1)
+	vs.fetchTrafficProtocols(&protocols, "bookinfo", "reviews", queryTime, &wg)
+	assert.Equal(map[string][]string{"reviews": {"http", "tcp"}}, protocols)
+
+	// Without Prometheus the protocols are unknown
+	vs = IstioValidationsService{k8s: k8s, businessLayer: NewWithBackends(k8s, nil, nil)}
+	protocols = nil
+	wg.Add(1)
+	vs.fetchTrafficProtocols(&protocols, "bookinfo", "reviews", queryTime, &wg)
+	assert.Nil(protocols)
+}
+
+func TestFetchTrafficProtocolsWithTelemetryMapping(t *testing.T) {
+	assert := assert.New(t)
+	conf := config.NewConfig()
+	conf.ExternalServices.Istio.TelemetryMapping = config.TelemetryMapping{
+		Metrics: map[string]string{"istio_requests_total": "mesh_requests_total", "istio_tcp_connections_opened_total": "mesh_tcp_opened_total"},
+		Labels:  map[string]string{"destination_service_name": "dst_svc", "request_protocol": "proto"},
+	}
+	config.Set(conf)
+	defer config.Set(config.NewConfig())
+
+	k8s := new(kubetest.K8SClientMock)
+	k8s.On("IsOpenShift").Return(false)
+	prom := new(prometheustest.PromClientMock)
+	queryTime := time.Date(2017, 01, 15, 0, 0, 0, 0, time.UTC)
+	labels := `{reporter="destination",destination_service_namespace="bookinfo",dst_svc="reviews"}`
+	prom.On("FetchRateValues", "mesh_requests_total", labels, "proto", "10m", queryTime).Return(model.Vector{
+		&model.Sample{Metric: model.Metric{"proto": "grpc"}, Value: 2},
+	}, nil)
+	prom.On("FetchRateValues", "mesh_tcp_opened_total", labels, "", "10m", queryTime).Return(model.Vector{}, nil)
+
+	vs := IstioValidationsService{k8s: k8s, businessLayer: NewWithBackends(k8s, prom, nil)}
+	var protocols map[string][]string
+	wg := sync.WaitGroup{}
+	wg.Add(1)
+	vs.fetchTrafficProtocols(&protocols, "bookinfo", "reviews", queryTime, &wg)
+	assert.Equal(map[string][]string{"reviews": {"grpc"}}, protocols)
+}
+
+func TestGetCredentialSecrets(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	tlsServer := func(credentialName string) map[string]interface{} {
+		server := data.CreateServer([]string{"bookinfo.example.com"}, 443, "https", "HTTPS")
+		server["tls"] = map[string]interface{}{"mode": "SIMPLE", "credentialName": credentialName}
+		return server
+	}
+	gw := data.AddServerToGateway(tlsServer("bookinfo-cert"), data.CreateEmptyGateway("bookinfo-gateway", "bookinfo", map[string]string{"istio": "ingressgateway"}))
+	gw = data.AddServerToGateway(tlsServer("missing-cert"), gw)
+	otherGw := data.AddServerToGateway(tlsServer("other-cert"), data.CreateEmptyGateway("other-gateway", "other", map[string]string{"istio": "other"}))
+
+	k8s := new(kubetest.K8SClientMock)
+	k8s.On("GetSecret", "bookinfo", "bookinfo-cert").Return(&core_v1.Secret{ObjectMeta: meta_v1.ObjectMeta{Name: "bookinfo-cert", Namespace: "bookinfo"}}, nil)
+	k8s.On("GetSecret", "bookinfo", "missing-cert").Return(&core_v1.Secret{}, errors.NewNotFound(schema.GroupResource{Resource: "secrets"}, "missing-cert"))
+	k8s.On("GetSecret", "other", "other-cert").Return(&core_v1.Secret{}, errors.NewForbidden(schema.GroupResource{Resource: "secrets"}, "other-cert", nil))
+
+	// Only the named Secrets are read, a missing Secret is not an unreadable namespace
+	secrets := getCredentialSecrets(k8s, []kubernetes.IstioObject{gw, otherGw}, map[string]models.WorkloadList{})
+	assert.Len(secrets.Secrets, 1)
+	assert.Equal("bookinfo-cert", secrets.Secrets[0].Name)
+	assert.Equal(map[string]bool{"other": true}, secrets.Unreadable)
+	k8s.AssertNotCalled(t, "GetSecrets", mock.Anything, mock.Anything)
+}
@@ -1,26 +1,34 @@
 package business
 
 import (
+	"io"
+	"sort"
 	"strings"
 	"sync"
 	"time"
 
 	jaegerModels "github.com/jaegertracing/jaeger/model/json"
 
+	"github.com/kiali/kiali/config"
 	"github.com/kiali/kiali/jaeger"
 	"github.com/kiali/kiali/log"
 	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/prometheus"
 	"github.com/kiali/kiali/prometheus/internalmetrics"
 )
 
 type JaegerLoader = func() (jaeger.ClientInterface, error)
+type JaegerClusterLoader = func(cluster string) (jaeger.ClientInterface, error)
 type SpanFilter = func(span *jaegerModels.Span) bool
 
 type JaegerService struct {
-	loader        JaegerLoader
-	loaderErr     error
-	jaeger        jaeger.ClientInterface
-	businessLayer *Layer
+	loader         JaegerLoader
+	loaderErr      error
+	jaeger         jaeger.ClientInterface
+	clusterLoader  JaegerClusterLoader
+	clusterClients map[string]jaeger.ClientInterface
+	prom           prometheus.ClientInterface
+	businessLayer  *Layer
 }
 
 func (in *JaegerService) client() (jaeger.ClientInterface, error) {
@@ -33,6 +41,67 @@ func (in *JaegerService) client() (jaeger.ClientInterface, error) {
 	return in.jaeger, in.loaderErr
 }
 
+// clusterClient returns the client to the tracing backend of the cluster.
+// The default client is returned when the cluster is unknown or doesn't have its own tracing backend.
+func (in *JaegerService) clusterClient(cluster string) (jaeger.ClientInterface, error) {
+	if cluster == "" || in.clusterLoader == nil || !jaeger.HasClusterBackend(cluster) {
+		return in.client()
+	}
+	if client, ok := in.clusterClients[cluster]; ok {
+		return client, nil
+	}
+	client, err := in.clusterLoader(cluster)
+	if err != nil {
+		return nil, err
+	}
+	if in.clusterClients == nil {
+		in.clusterClients = make(map[string]jaeger.ClientInterface)
+	}
+	in.clusterClients[cluster] = client
+	return client, nil
+}
+
+// backendClusters returns a cluster per tracing backend to query for multicluster results,
+// the empty cluster standing for the default backend. Clusters sharing a backend are queried once.
+func (in *JaegerService) backendClusters() []string {
+	clusters := []string{""}
+	if in.clusterLoader == nil {
+		return clusters
+	}
+	clusterURLs := config.Get().ExternalServices.Tracing.ClusterURLs
+	names := make([]string, 0, len(clusterURLs))
+	for cluster := range clusterURLs {
+		if jaeger.HasClusterBackend(cluster) {
+			names = append(names, cluster)
+		}
+	}
+	sort.Strings(names)
+	urls := make(map[string]bool, len(names))
+	for _, cluster := range names {
+		if !urls[clusterURLs[cluster]] {
+			urls[clusterURLs[cluster]] = true
+			clusters = append(clusters, cluster)
+		}
+	}
+	return clusters
+}
+
+// workloadAppAndCluster returns the app of the workload and the cluster where it runs, if known
+func (in *JaegerService) workloadAppAndCluster(ns, workload string) (string, string, error) {
+	wkd, err := fetchWorkload(in.businessLayer, ns, workload, "")
+	if err != nil {
+		return "", "", err
+	}
+	cluster := ""
+	for _, pod := range wkd.Pods {
+		if pod.Cluster != "" {
+			cluster = pod.Cluster
+			break
+		}
+	}
+	return wkd.Labels[config.Get().IstioLabels.AppLabelName], cluster, nil
+}
+
 func (in *JaegerService) getFilteredSpans(ns, app string, query models.TracingQuery, filter SpanFilter) ([]jaeger.JaegerSpan, error) {
 	r, err := in.GetAppTraces(ns, app, query)
 	if err != nil {
@@ -96,11 +165,19 @@ func (in *JaegerService) GetWorkloadSpans(ns, workload string, query models.Trac
 	promtimer := internalmetrics.GetGoFunctionMetric("business", "Jaeger", "GetWorkloadSpans")
 	defer promtimer.ObserveNow(&err)
 
-	app, err := in.businessLayer.Workload.GetWorkloadAppName(ns, workload)
+	if err = limitTracingQuery(&query); err != nil {
+		return nil, err
+	}
+
+	app, cluster, err := in.workloadAppAndCluster(ns, workload)
 	if err != nil {
 		return nil, err
 	}
-	return in.getFilteredSpans(ns, app, query, wkdSpanFilter(ns, workload))
+	r, err := in.getClusterAppTraces(cluster, ns, app, query)
+	if err != nil {
+		return []jaeger.JaegerSpan{}, err
+	}
+	return tracesToSpans(app, r, wkdSpanFilter(ns, workload)), nil
 }
 
 func wkdSpanFilter(ns, workload string) SpanFilter {
@@ -110,15 +187,77 @@ func wkdSpanFilter(ns, workload string) SpanFilter {
 	}
 }
 
+// GetAppTraces returns the traces of the app. When several tracing backends are configured
+// the traces of all of them are merged, as the app may run in several clusters.
 func (in *JaegerService) GetAppTraces(ns, app string, query models.TracingQuery) (*jaeger.JaegerResponse, error) {
 	var err error
 	promtimer := internalmetrics.GetGoFunctionMetric("business", "Jaeger", "GetAppTraces")
 	defer promtimer.ObserveNow(&err)
 
-	client, err := in.client()
+	if err = limitTracingQuery(&query); err != nil {
+		return nil, err
+	}
+
+	clusters := in.backendClusters()
+	if len(clusters) == 1 {
+		return in.getClusterAppTraces(clusters[0], ns, app, query)
+	}
+
+	type tracesChanResult struct {
+		cluster string
+		resp    *jaeger.JaegerResponse
+		err     error
+	}
+	tracesChan := make(chan tracesChanResult, len(clusters))
+	var wg sync.WaitGroup
+	for _, cluster := range clusters {
+		// Clients are loaded before spawning the queries, loading is not thread safe
+		client, clientErr := in.clusterClient(cluster)
+		if clientErr != nil {
+			tracesChan <- tracesChanResult{cluster: cluster, err: clientErr}
+			continue
+		}
+		wg.Add(1)
+		go func(cluster string, client jaeger.ClientInterface) {
+			defer wg.Done()
+			r, err := fetchAppTraces(client, ns, app, query)
+			tracesChan <- tracesChanResult{cluster: cluster, resp: r, err: err}
+		}(cluster, client)
+	}
+	wg.Wait()
+	close(tracesChan)
+
+	var merged *jaeger.JaegerResponse
+	var lastErr error
+	for r := range tracesChan {
+		if r.err != nil {
+			// Keep the traces of the other clusters
+			log.Errorf("Error fetching traces of app [%s/%s] from the tracing backend of cluster [%s]: %v", ns, app, r.cluster, r.err)
+			lastErr = r.err
+			continue
+		}
+		if merged == nil {
+			merged = &jaeger.JaegerResponse{}
+		}
+		mergeResponses(merged, r.resp)
+	}
+	if merged == nil {
+		err = lastErr
+		return nil, err
+	}
+	return merged, nil
+}
+
+// getClusterAppTraces returns the traces of the app from the tracing backend of the cluster
+func (in *JaegerService) getClusterAppTraces(cluster, ns, app string, query models.TracingQuery) (*jaeger.JaegerResponse, error) {
+	client, err := in.clusterClient(cluster)
 	if err != nil {
 		return nil, err
 	}
+	return fetchAppTraces(client, ns, app, query)
+}
+
+func fetchAppTraces(client jaeger.ClientInterface, ns, app string, query models.TracingQuery) (*jaeger.JaegerResponse, error) {
 	r, err := client.GetAppTraces(ns, app, query)
 	if err != nil {
 		return nil, err
@@ -126,7 +265,7 @@ func (in *JaegerService) GetAppTraces(ns, app string, query models.TracingQuery)
 	if len(r.Data) == query.Limit {
 		// Reached the limit, use split & join mode to spread traces over the requested interval
 		log.Trace("Limit of traces was reached, using split & join mode")
-		more, err := in.getAppTracesSlicedInterval(ns, app, query)
+		more, err := getAppTracesSlicedInterval(client, ns, app, query)
 		if err != nil {
 			// Log error but continue to process results (might still have some data fetched)
 			log.Errorf("Traces split & join failed: %v", err)
@@ -178,7 +317,11 @@ func (in *JaegerService) GetWorkloadTraces(ns, workload string, query models.Tra
 	promtimer := internalmetrics.GetGoFunctionMetric("business", "Jaeger", "GetWorkloadTraces")
 	defer promtimer.ObserveNow(&err)
 
-	app, err := in.businessLayer.Workload.GetWorkloadAppName(ns, workload)
+	if err = limitTracingQuery(&query); err != nil {
+		return nil, err
+	}
+
+	app, cluster, err := in.workloadAppAndCluster(ns, workload)
 	if err != nil {
 		return nil, err
 	}
@@ -187,7 +330,8 @@ func (in *JaegerService) GetWorkloadTraces(ns, workload string, query models.Tra
 	// only 3 traces for the workload even if there's more.
 	// To try to attenuate this effect, we will artificially increase the limit, then cut it down after workload filtering.
 	query.Limit *= 5
-	r, err := in.GetAppTraces(ns, app, query)
+	// The workload runs in a single cluster, only its tracing backend is queried
+	r, err := in.getClusterAppTraces(cluster, ns, app, query)
 	// Filter out app traces based on the node_id tag, that contains workload information.
 	if r != nil && err == nil {
 		traces := []jaegerModels.Trace{}
@@ -201,11 +345,8 @@ func (in *JaegerService) GetWorkloadTraces(ns, workload string, query models.Tra
 	return r, err
 }
 
-func (in *JaegerService) getAppTracesSlicedInterval(ns, app string, query models.TracingQuery) (*jaeger.JaegerResponse, error) {
-	client, err := in.client()
-	if err != nil {
-		return nil, err
-	}
+func getAppTracesSlicedInterval(client jaeger.ClientInterface, ns, app string, query models.TracingQuery) (*jaeger.JaegerResponse, error) {
+	var err error
 	// Spread queries over 10 interval slices
 	nSlices := 10
 	limit := query.Limit / nSlices
@@ -248,26 +389,113 @@ func (in *JaegerService) getAppTracesSlicedInterval(ns, app string, query models
 	return merged, err
 }
 
+// ExportAppTraces streams the traces of the app to w in OTLP/JSON, a line per trace, so that they can be ingested by
+// another tracing backend. The traces are converted and written one by one, the export of large trace sets doesn't
+// hold their OTLP conversion in memory. It returns the number of traces exported.
+func (in *JaegerService) ExportAppTraces(ns, app string, query models.TracingQuery, w io.Writer) (exported int, err error) {
+	promtimer := internalmetrics.GetGoFunctionMetric("business", "Jaeger", "ExportAppTraces")
+	defer promtimer.ObserveNow(&err)
+
+	var traces *jaeger.JaegerResponse
+	if traces, err = in.GetAppTraces(ns, app, query); err != nil {
+		return 0, err
+	}
+	exported, err = jaeger.WriteOTLPTraces(w, traces.Data)
+	return exported, err
+}
+
 func (in *JaegerService) GetJaegerTraceDetail(traceID string) (trace *jaeger.JaegerSingleTrace, err error) {
 	promtimer := internalmetrics.GetGoFunctionMetric("business", "Jaeger", "GetJaegerTraceDetail")
 	defer promtimer.ObserveNow(&err)
 
-	client, err := in.client()
-	if err != nil {
-		return nil, err
+	// The trace may be stored in the tracing backend of any cluster
+	var lastErr error
+	for _, cluster := range in.backendClusters() {
+		var client jaeger.ClientInterface
+		if client, err = in.clusterClient(cluster); err == nil {
+			trace, err = client.GetTraceDetail(traceID)
+		}
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		if trace != nil {
+			return trace, nil
+		}
 	}
-	return client.GetTraceDetail(traceID)
+	err = lastErr
+	return nil, err
 }
 
 func (in *JaegerService) GetErrorTraces(ns, app string, duration time.Duration) (errorTraces int, err error) {
 	promtimer := internalmetrics.GetGoFunctionMetric("business", "Jaeger", "GetErrorTraces")
 	defer promtimer.ObserveNow(&err)
 
-	client, err := in.client()
-	if err != nil {
+	// The error traces of the app are counted in all the tracing backends
+	clusters := in.backendClusters()
+	failed := 0
+	var lastErr error
+	for _, cluster := range clusters {
+		client, clientErr := in.clusterClient(cluster)
+		if clientErr != nil {
+			failed++
+			lastErr = clientErr
+			continue
+		}
+		count, countErr := client.GetErrorTraces(ns, app, duration)
+		if countErr != nil {
+			log.Errorf("Error counting error traces of app [%s/%s] in the tracing backend of cluster [%s]: %v", ns, app, cluster, countErr)
+			failed++
+			lastErr = countErr
+			continue
+		}
+		errorTraces += count
+	}
+	if failed == len(clusters) {
+		err = lastErr
 		return 0, err
 	}
-	return client.GetErrorTraces(ns, app, duration)
+	return errorTraces, nil
+}
+
+// CorrelateAccessLogs sets the TraceID of the access logs of a workload, matching their request id
+// with the "guid:x-request-id" tag that Envoy adds to its spans.
+func (in *JaegerService) CorrelateAccessLogs(ns, workload string, accessLogs []models.AccessLog, query models.TracingQuery) error {
+	var err error
+	promtimer := internalmetrics.GetGoFunctionMetric("business", "Jaeger", "CorrelateAccessLogs")
+	defer promtimer.ObserveNow(&err)
+
+	r, err := in.GetWorkloadTraces(ns, workload, query)
+	if err != nil {
+		return err
+	}
+
+	correlateAccessLogs(accessLogs, r.Data)
+	return nil
+}
+
+func correlateAccessLogs(accessLogs []models.AccessLog, traces []jaegerModels.Trace) {
+	traceIDs := make(map[string]string)
+	for _, trace := range traces {
+		for _, span := range trace.Spans {
+			for _, tag := range span.Tags {
+				if tag.Key == "guid:x-request-id" {
+					if requestID, ok := tag.Value.(string); ok {
+						traceIDs[requestID] = string(trace.TraceID)
+					}
+				}
+			}
+		}
+	}
+
+	for i := range accessLogs {
+		if accessLogs[i].TraceID != "" || accessLogs[i].RequestID == "" {
+			continue
+		}
+		if traceID, found := traceIDs[accessLogs[i].RequestID]; found {
+			accessLogs[i].TraceID = traceID
+		}
+	}
 }
 
 func matchesWorkload(trace *jaegerModels.Trace, namespace, workload string) bool {
@@ -1,12 +1,29 @@
 package business
 
 import (
+	"bytes"
+	"errors"
+	"strings"
 	"testing"
+	"time"
 
 	jaegerModels "github.com/jaegertracing/jaeger/model/json"
+	osapps_v1 "github.com/openshift/api/apps/v1"
+	osproject_v1 "github.com/openshift/api/project/v1"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	apps_v1 "k8s.io/api/apps/v1"
+	batch_v1 "k8s.io/api/batch/v1"
+	batch_v1beta1 "k8s.io/api/batch/v1beta1"
+	core_v1 "k8s.io/api/core/v1"
+	errors2 "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 
+	"github.com/kiali/kiali/config"
 	"github.com/kiali/kiali/jaeger"
+	"github.com/kiali/kiali/kubernetes/kubetest"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/prometheus/prometheustest"
 )
 
 var trace1 = jaegerModels.Trace{
@@ -159,3 +176,267 @@ func TestTracesToSpanWithWorkloadFilter(t *testing.T) {
 	assert.Equal("t2_process_2", string(spans[0].ProcessID))
 	assert.Equal("t2_process_3", string(spans[1].ProcessID))
 }
+
+func TestCorrelateAccessLogs(t *testing.T) {
+	assert := assert.New(t)
+
+	traces := []jaegerModels.Trace{{
+		TraceID: "3c1a8b2e6fd0a4b1",
+		Spans: []jaegerModels.Span{{
+			Tags: []jaegerModels.KeyValue{{
+				Key:   "guid:x-request-id",
+				Value: "84961386-6d84-929d-98bd-c5aee93b5c88",
+			}},
+		}},
+	}}
+	accessLogs := []models.AccessLog{
+		{RequestID: "84961386-6d84-929d-98bd-c5aee93b5c88"},
+		{RequestID: "2a2c0d0a-0c0d-9c7e-a4e5-7ec9c5c1bb2e"},
+		{RequestID: "84961386-6d84-929d-98bd-c5aee93b5c88", TraceID: "custom"},
+		{},
+	}
+
+	correlateAccessLogs(accessLogs, traces)
+
+	assert.Equal("3c1a8b2e6fd0a4b1", accessLogs[0].TraceID)
+	assert.Equal("", accessLogs[1].TraceID)
+	assert.Equal("custom", accessLogs[2].TraceID)
+	assert.Equal("", accessLogs[3].TraceID)
+}
+
+func TestGetAppTracesMultiCluster(t *testing.T) {
+	assert := assert.New(t)
+	svc, defaultClient, westClient, loaded := setupMultiClusterJaeger()
+
+	query := models.TracingQuery{Limit: 20}
+	defaultClient.On("GetAppTraces", "bookinfo", "reviews", query).Return(&jaeger.JaegerResponse{
+		Data:              []jaegerModels.Trace{{TraceID: "t1"}, {TraceID: "t2"}},
+		JaegerServiceName: "reviews.bookinfo",
+	}, nil)
+	westClient.On("GetAppTraces", "bookinfo", "reviews", query).Return(&jaeger.JaegerResponse{
+		Data:              []jaegerModels.Trace{{TraceID: "t2"}, {TraceID: "t3"}},
+		JaegerServiceName: "reviews.bookinfo",
+	}, nil)
+
+	r, err := svc.GetAppTraces("bookinfo", "reviews", query)
+	assert.NoError(err)
+	assert.ElementsMatch([]jaegerModels.TraceID{"t1", "t2", "t3"}, traceIDs(r))
+	assert.Equal("reviews.bookinfo", r.JaegerServiceName)
+	// east shares the default backend and north shares the west one, they are not queried twice
+	assert.Equal([]string{"north"}, *loaded)
+	defaultClient.AssertNumberOfCalls(t, "GetAppTraces", 1)
+	westClient.AssertNumberOfCalls(t, "GetAppTraces", 1)
+}
+
+func TestGetAppTracesMultiClusterPartialFailure(t *testing.T) {
+	assert := assert.New(t)
+	svc, defaultClient, westClient, _ := setupMultiClusterJaeger()
+
+	query := models.TracingQuery{Limit: 20}
+	defaultClient.On("GetAppTraces", "bookinfo", "reviews", query).Return(&jaeger.JaegerResponse{
+		Data: []jaegerModels.Trace{{TraceID: "t1"}},
+	}, nil)
+	westClient.On("GetAppTraces", "bookinfo", "reviews", query).Return((*jaeger.JaegerResponse)(nil), errors.New("connection refused"))
+
+	r, err := svc.GetAppTraces("bookinfo", "reviews", query)
+	assert.NoError(err)
+	assert.Equal([]jaegerModels.TraceID{"t1"}, traceIDs(r))
+
+	// All backends failing is an error
+	defaultClient.ExpectedCalls = nil
+	defaultClient.On("GetAppTraces", "bookinfo", "reviews", query).Return((*jaeger.JaegerResponse)(nil), errors.New("timeout"))
+	_, err = svc.GetAppTraces("bookinfo", "reviews", query)
+	assert.Error(err)
+}
+
+func TestExportAppTraces(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	client := new(jaegerClientMock)
+	svc := JaegerService{loader: func() (jaeger.ClientInterface, error) { return client, nil }}
+	query := models.TracingQuery{Limit: 20}
+	process := map[jaegerModels.ProcessID]jaegerModels.Process{"p1": {ServiceName: "reviews.bookinfo"}}
+	client.On("GetAppTraces", "bookinfo", "reviews", query).Return(&jaeger.JaegerResponse{
+		Data: []jaegerModels.Trace{
+			{TraceID: "a1", Spans: []jaegerModels.Span{{TraceID: "a1", SpanID: "b1", ProcessID: "p1"}}, Processes: process},
+			{TraceID: "a2", Spans: []jaegerModels.Span{{TraceID: "a2", SpanID: "b2", ProcessID: "p1"}}, Processes: process},
+		},
+	}, nil)
+
+	var out bytes.Buffer
+	exported, err := svc.ExportAppTraces("bookinfo", "reviews", query, &out)
+	assert.NoError(err)
+	assert.Equal(2, exported)
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	assert.Len(lines, 2)
+	assert.Contains(lines[0], `"traceId":"000000000000000000000000000000a1"`)
+	assert.Contains(lines[1], `"traceId":"000000000000000000000000000000a2"`)
+
+	// Nothing is written when the traces can't be fetched
+	client.ExpectedCalls = nil
+	client.On("GetAppTraces", "bookinfo", "reviews", query).Return((*jaeger.JaegerResponse)(nil), errors.New("timeout"))
+	out.Reset()
+	_, err = svc.ExportAppTraces("bookinfo", "reviews", query, &out)
+	assert.Error(err)
+	assert.Zero(out.Len())
+}
+
+func TestGetWorkloadTracesFromWorkloadCluster(t *testing.T) {
+	assert := assert.New(t)
+	pods := FakePodsSyncedWithDeployments()
+	pods[0].Labels = map[string]string{"app": "details", "version": "v1", models.IstioClusterLabel: "west"}
+	k8s := mockWorkloadForTraces(pods)
+
+	// The fake objects reset the config, so the tracing backends are set up afterwards
+	svc, defaultClient, westClient, _ := setupMultiClusterJaeger()
+	svc.businessLayer = NewWithBackends(k8s, new(prometheustest.PromClientMock), nil)
+
+	trace := jaegerModels.Trace{
+		TraceID: "t1",
+		Spans: []jaegerModels.Span{{
+			ProcessID: "p1",
+			Tags:      []jaegerModels.KeyValue{{Key: "node_id", Value: "sidecar~172.17.0.20~details-v1-3618568057-dnkjp.Namespace~Namespace.svc.cluster.local"}},
+		}},
+	}
+	westClient.On("GetAppTraces", "Namespace", "details", mock.AnythingOfType("models.TracingQuery")).Return(&jaeger.JaegerResponse{
+		Data: []jaegerModels.Trace{trace},
+	}, nil)
+
+	r, err := svc.GetWorkloadTraces("Namespace", "details-v1", models.TracingQuery{Limit: 20})
+	assert.NoError(err)
+	assert.Equal([]jaegerModels.TraceID{"t1"}, traceIDs(r))
+	// The workload runs in a single cluster, the other backends are not queried
+	defaultClient.AssertNotCalled(t, "GetAppTraces", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestGetWorkloadTracesUnknownCluster(t *testing.T) {
+	assert := assert.New(t)
+	pods := FakePodsSyncedWithDeployments()
+	pods[0].Labels = map[string]string{"app": "details", "version": "v1"}
+	k8s := mockWorkloadForTraces(pods)
+
+	// The fake objects reset the config, so the tracing backends are set up afterwards
+	svc, defaultClient, westClient, _ := setupMultiClusterJaeger()
+	svc.businessLayer = NewWithBackends(k8s, new(prometheustest.PromClientMock), nil)
+
+	defaultClient.On("GetAppTraces", "Namespace", "details", mock.AnythingOfType("models.TracingQuery")).Return(&jaeger.JaegerResponse{}, nil)
+
+	_, err := svc.GetWorkloadTraces("Namespace", "details-v1", models.TracingQuery{Limit: 20})
+	assert.NoError(err)
+	// Falls back to the default backend
+	defaultClient.AssertNumberOfCalls(t, "GetAppTraces", 1)
+	westClient.AssertNotCalled(t, "GetAppTraces", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestGetJaegerTraceDetailMultiCluster(t *testing.T) {
+	assert := assert.New(t)
+	svc, defaultClient, westClient, _ := setupMultiClusterJaeger()
+
+	defaultClient.On("GetTraceDetail", "t3").Return((*jaeger.JaegerSingleTrace)(nil), nil)
+	westClient.On("GetTraceDetail", "t3").Return(&jaeger.JaegerSingleTrace{Data: jaegerModels.Trace{TraceID: "t3"}}, nil)
+
+	trace, err := svc.GetJaegerTraceDetail("t3")
+	assert.NoError(err)
+	assert.Equal(jaegerModels.TraceID("t3"), trace.Data.TraceID)
+}
+
+func TestGetErrorTracesMultiCluster(t *testing.T) {
+	assert := assert.New(t)
+	svc, defaultClient, westClient, _ := setupMultiClusterJaeger()
+
+	defaultClient.On("GetErrorTraces", "bookinfo", "reviews", time.Minute).Return(2, nil)
+	westClient.On("GetErrorTraces", "bookinfo", "reviews", time.Minute).Return(3, nil)
+
+	count, err := svc.GetErrorTraces("bookinfo", "reviews", time.Minute)
+	assert.NoError(err)
+	assert.Equal(5, count)
+}
+
+func TestSingleTracingBackend(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	defaultClient := new(jaegerClientMock)
+	svc := JaegerService{
+		loader: func() (jaeger.ClientInterface, error) { return defaultClient, nil },
+		clusterLoader: func(cluster string) (jaeger.ClientInterface, error) {
+			return nil, errors.New("no cluster backend expected")
+		},
+	}
+	defaultClient.On("GetErrorTraces", "bookinfo", "reviews", time.Minute).Return(2, nil)
+
+	count, err := svc.GetErrorTraces("bookinfo", "reviews", time.Minute)
+	assert.NoError(err)
+	assert.Equal(2, count)
+	assert.Equal([]string{""}, svc.backendClusters())
+}
+
+// setupMultiClusterJaeger configures the default tracing backend, shared with the east cluster,
+// and a west backend, shared with the north cluster
+func setupMultiClusterJaeger() (*JaegerService, *jaegerClientMock, *jaegerClientMock, *[]string) {
+	conf := config.NewConfig()
+	conf.ExternalServices.Tracing.ClusterURLs = map[string]string{
+		"east":  conf.ExternalServices.Tracing.InClusterURL,
+		"north": "http://tracing.west:16686/jaeger",
+		"west":  "http://tracing.west:16686/jaeger",
+	}
+	config.Set(conf)
+
+	defaultClient := new(jaegerClientMock)
+	westClient := new(jaegerClientMock)
+	loaded := []string{}
+	svc := &JaegerService{
+		loader: func() (jaeger.ClientInterface, error) {
+			return defaultClient, nil
+		},
+		clusterLoader: func(cluster string) (jaeger.ClientInterface, error) {
+			loaded = append(loaded, cluster)
+			return westClient, nil
+		},
+	}
+	return svc, defaultClient, westClient, &loaded
+}
+
+func mockWorkloadForTraces(pods []core_v1.Pod) *kubetest.K8SClientMock {
+	notfound := errors2.NewNotFound(schema.GroupResource{Group: "test-group", Resource: "test-resource"}, "not found")
+	k8s := new(kubetest.K8SClientMock)
+	k8s.On("IsOpenShift").Return(true)
+	k8s.On("GetProject", mock.AnythingOfType("string")).Return(&osproject_v1.Project{}, nil)
+	k8s.On("GetDeployment", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return(&FakeDepSyncedWithRS()[0], nil)
+	k8s.On("GetDeploymentConfig", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return(&osapps_v1.DeploymentConfig{}, notfound)
+	k8s.On("GetReplicaSets", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return([]apps_v1.ReplicaSet{}, nil)
+	k8s.On("GetReplicationControllers", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return([]core_v1.ReplicationController{}, nil)
+	k8s.On("GetStatefulSet", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return(&apps_v1.StatefulSet{}, notfound)
+	k8s.On("GetPods", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return(pods, nil)
+	k8s.On("GetJobs", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return([]batch_v1.Job{}, nil)
+	k8s.On("GetCronJobs", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return([]batch_v1beta1.CronJob{}, nil)
+	return k8s
+}
+
+func traceIDs(r *jaeger.JaegerResponse) []jaegerModels.TraceID {
+	ids := []jaegerModels.TraceID{}
+	for _, trace := range r.Data {
+		ids = append(ids, trace.TraceID)
+	}
+	return ids
+}
+
+type jaegerClientMock struct {
+	mock.Mock
+}
+
+func (o *jaegerClientMock) GetAppTraces(ns, app string, query models.TracingQuery) (*jaeger.JaegerResponse, error) {
+	args := o.Called(ns, app, query)
+	return args.Get(0).(*jaeger.JaegerResponse), args.Error(1)
+}
+
+func (o *jaegerClientMock) GetTraceDetail(traceID string) (*jaeger.JaegerSingleTrace, error) {
+	args := o.Called(traceID)
+	return args.Get(0).(*jaeger.JaegerSingleTrace), args.Error(1)
+}
+
+func (o *jaegerClientMock) GetErrorTraces(ns, app string, duration time.Duration) (int, error) {
+	args := o.Called(ns, app, duration)
+	return args.Int(0), args.Error(1)
+}
@@ -16,7 +16,9 @@ import (
 // Layer is a container for fast access to inner services
 type Layer struct {
 	App            AppService
+	Audit          AuditLogger
 	Health         HealthService
+	IstioCerts     IstioCertsService
 	IstioConfig    IstioConfigService
 	IstioStatus    IstioStatusService
 	Iter8          Iter8Service
@@ -25,6 +27,7 @@ type Layer struct {
 	Mesh           MeshService
 	Namespace      NamespaceService
 	OpenshiftOAuth OpenshiftOAuthService
+	ProxyLogging   ProxyLoggingService
 	ProxyStatus    ProxyStatus
 	Svc            SvcService
 	TLS            TLSService
@@ -36,6 +39,7 @@ type Layer struct {
 // Global clientfactory and prometheus clients.
 var clientFactory kubernetes.ClientFactory
 var prometheusClient prometheus.ClientInterface
+var prometheusClientLock sync.Mutex
 var once sync.Once
 var kialiCache cache.KialiCache
 
@@ -53,6 +57,12 @@ func initKialiCache() {
 			excludedWorkloads[w] = true
 		}
 	}
+	if includedWorkloads == nil {
+		includedWorkloads = make(map[string]bool)
+		for _, w := range config.Get().KubernetesConfig.IncludeWorkloads {
+			includedWorkloads[w] = true
+		}
+	}
 }
 
 func IsNamespaceCached(namespace string) bool {
@@ -60,6 +70,14 @@ func IsNamespaceCached(namespace string) bool {
 	return ok
 }
 
+// GetGraphCache returns the cache of the graphs computed in background, nil when the Kiali cache is not enabled
+func GetGraphCache() cache.GraphCache {
+	if kialiCache == nil {
+		return nil
+	}
+	return kialiCache
+}
+
 func IsResourceCached(namespace string, resource string) bool {
 	ok := IsNamespaceCached(namespace)
 	if ok && resource != "" {
@@ -88,26 +106,59 @@ func Get(authInfo *api.AuthInfo) (*Layer, error) {
 		return nil, err
 	}
 
-	// Use an existing Prometheus client if it exists, otherwise create and use in the future
+	prom, err := getPrometheusClient()
+	if err != nil {
+		return nil, err
+	}
+
+	// Create Jaeger client, with the credentials of the store when they were rotated
+	jaegerLoader := func() (jaeger.ClientInterface, error) {
+		return jaeger.NewClusterClientWithAuth(authInfo.Token, "", resolveAuth(CredentialsServiceTracing, config.Get().ExternalServices.Tracing.Auth))
+	}
+
+	layer := NewWithBackends(k8s, prom, jaegerLoader)
+	layer.Mesh.authInfo = authInfo
+	layer.Jaeger.clusterLoader = func(cluster string) (jaeger.ClientInterface, error) {
+		return jaeger.NewClusterClientWithAuth(authInfo.Token, cluster, resolveAuth(CredentialsServiceTracing, config.Get().ExternalServices.Tracing.Auth))
+	}
+	return layer, nil
+}
+
+// getPrometheusClient returns the existing Prometheus client if it exists, otherwise creates it, with the
+// credentials of the store, and uses it in the future
+func getPrometheusClient() (prometheus.ClientInterface, error) {
+	prometheusClientLock.Lock()
+	defer prometheusClientLock.Unlock()
 	if prometheusClient == nil {
-		prom, err := prometheus.NewClient()
+		prom, err := NewPrometheusClient()
 		if err != nil {
 			return nil, err
 		}
 		prometheusClient = prom
 	}
+	return prometheusClient, nil
+}
 
-	// Create Jaeger client
-	jaegerLoader := func() (jaeger.ClientInterface, error) {
-		return jaeger.NewClient(authInfo.Token)
-	}
+// NewPrometheusClient creates a Prometheus client with the credentials of the store, when they were rotated. All the
+// Prometheus clients of Kiali are created with it.
+func NewPrometheusClient() (*prometheus.Client, error) {
+	cfg := config.Get().ExternalServices.Prometheus
+	cfg.Auth = resolveAuth(CredentialsServicePrometheus, cfg.Auth)
+	return prometheus.NewClientForConfig(cfg)
+}
 
-	return NewWithBackends(k8s, prometheusClient, jaegerLoader), nil
+// resetPrometheusClient makes the next business layer create a new Prometheus client, when its credentials are rotated
+func resetPrometheusClient() {
+	prometheusClientLock.Lock()
+	defer prometheusClientLock.Unlock()
+	prometheusClient = nil
 }
 
 // SetWithBackends allows for specifying the ClientFactory and Prometheus clients to be used.
 // Mock friendly. Used only with tests.
 func SetWithBackends(cf kubernetes.ClientFactory, prom prometheus.ClientInterface) {
+	prometheusClientLock.Lock()
+	defer prometheusClientLock.Unlock()
 	clientFactory = cf
 	prometheusClient = prom
 }
@@ -116,15 +167,20 @@ func SetWithBackends(cf kubernetes.ClientFactory, prom prometheus.ClientInterfac
 func NewWithBackends(k8s kubernetes.ClientInterface, prom prometheus.ClientInterface, jaegerClient JaegerLoader) *Layer {
 	temporaryLayer := &Layer{}
 	temporaryLayer.App = AppService{prom: prom, k8s: k8s, businessLayer: temporaryLayer}
+	temporaryLayer.Audit = AuditLogger{businessLayer: temporaryLayer}
 	temporaryLayer.Health = HealthService{prom: prom, k8s: k8s, businessLayer: temporaryLayer}
+	temporaryLayer.IstioCerts = IstioCertsService{k8s: k8s, businessLayer: temporaryLayer}
 	temporaryLayer.IstioConfig = IstioConfigService{k8s: k8s, businessLayer: temporaryLayer}
-	temporaryLayer.IstioStatus = IstioStatusService{k8s: k8s}
+	temporaryLayer.IstioStatus = IstioStatusService{k8s: k8s, prom: prom}
 	temporaryLayer.Iter8 = Iter8Service{k8s: k8s, businessLayer: temporaryLayer}
-	temporaryLayer.Jaeger = JaegerService{loader: jaegerClient, businessLayer: temporaryLayer}
+	temporaryLayer.Jaeger = JaegerService{loader: jaegerClient, prom: prom, businessLayer: temporaryLayer}
 	temporaryLayer.k8s = k8s
 	temporaryLayer.Mesh = NewMeshService(k8s, nil)
 	temporaryLayer.Namespace = NewNamespaceService(k8s)
+	temporaryLayer.Namespace.businessLayer = temporaryLayer
+	temporaryLayer.Namespace.prom = prom
 	temporaryLayer.OpenshiftOAuth = OpenshiftOAuthService{k8s: k8s}
+	temporaryLayer.ProxyLogging = ProxyLoggingService{k8s: k8s, businessLayer: temporaryLayer}
 	temporaryLayer.ProxyStatus = ProxyStatus{k8s: k8s, businessLayer: temporaryLayer}
 	temporaryLayer.Svc = SvcService{prom: prom, k8s: k8s, businessLayer: temporaryLayer}
 	temporaryLayer.TLS = TLSService{k8s: k8s, businessLayer: temporaryLayer}
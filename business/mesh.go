@@ -5,15 +5,21 @@ import (
 	"fmt"
 	"net/http"
 	"os"
+	"sync"
 	"time"
 
 	"gopkg.in/yaml.v2"
+	core_v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd/api"
 
 	"github.com/kiali/kiali/config"
 	"github.com/kiali/kiali/kubernetes"
 	"github.com/kiali/kiali/log"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/util"
 	"github.com/kiali/kiali/util/httputil"
 )
 
@@ -27,6 +33,9 @@ type MeshService struct {
 	// initialized kubernetes client using the specified config argument. This was created,
 	// mainly, for tests to set a function returning a mock of the kuberentes client.
 	newRemoteClient func(config *rest.Config) (kubernetes.ClientInterface, error)
+
+	// authInfo holds the credentials of the user, used to perform the operations of the user in the remote clusters
+	authInfo *api.AuthInfo
 }
 
 // Cluster holds some metadata about a cluster that is
@@ -150,33 +159,47 @@ func (in *MeshService) IsMeshConfigured() (isEnabled bool, returnErr error) {
 	return
 }
 
-// ResolveKialiControlPlaneCluster tries to resolve the metadata about the cluster where
-// Kiali is installed. This assumes that the mesh Control Plane is installed in the
-// same cluster as Kiali.
-func (in *MeshService) ResolveKialiControlPlaneCluster(r *http.Request) (*Cluster, error) {
-	conf := config.Get()
+// GetEffectiveMeshConfig returns the mesh config of the istio ConfigMap with the defaults applied by Istio
+// for the fields not set. Fields unknown to Kiali are passed through in the Extra fields.
+func (in *MeshService) GetEffectiveMeshConfig() (*models.MeshConfig, error) {
+	cfg := config.Get()
 
-	// The "cluster_id" is set in an environment variable of
-	// the "istiod" deployment. Let's try to fetch it.
-	istioDeployment, err := in.k8s.GetDeployment(conf.IstioNamespace, "istiod")
+	var istioConfig *core_v1.ConfigMap
+	var err error
+	if IsNamespaceCached(cfg.IstioNamespace) {
+		istioConfig, err = kialiCache.GetConfigMap(cfg.IstioNamespace, cfg.ExternalServices.Istio.ConfigMapName)
+	} else {
+		istioConfig, err = in.k8s.GetConfigMap(cfg.IstioNamespace, cfg.ExternalServices.Istio.ConfigMapName)
+	}
 	if err != nil {
 		return nil, err
 	}
+	return effectiveMeshConfig(istioConfig)
+}
 
-	if istioDeployment == nil {
-		return nil, nil
+// getClusterEffectiveMeshConfig returns the effective mesh config of the istio ConfigMap of a cluster, read with its client
+func getClusterEffectiveMeshConfig(client kubernetes.ClientInterface) (*models.MeshConfig, error) {
+	cfg := config.Get()
+	istioConfig, err := client.GetConfigMap(cfg.IstioNamespace, cfg.ExternalServices.Istio.ConfigMapName)
+	if err != nil {
+		return nil, err
 	}
+	return effectiveMeshConfig(istioConfig)
+}
 
-	if len(istioDeployment.Spec.Template.Spec.Containers) == 0 {
-		return nil, nil
-	}
+// effectiveMeshConfig returns the mesh config of the istio ConfigMap with the Istio defaults applied
+func effectiveMeshConfig(istioConfig *core_v1.ConfigMap) (*models.MeshConfig, error) {
+	// A ConfigMap without mesh config runs with the Istio defaults
+	return models.ParseMeshConfig(istioConfig.Data["mesh"], models.DefaultMeshConfig(config.Get().IstioNamespace))
+}
 
-	myClusterName := ""
-	for _, v := range istioDeployment.Spec.Template.Spec.Containers[0].Env {
-		if v.Name == "CLUSTER_ID" {
-			myClusterName = v.Value
-			break
-		}
+// ResolveKialiControlPlaneCluster tries to resolve the metadata about the cluster where
+// Kiali is installed. This assumes that the mesh Control Plane is installed in the
+// same cluster as Kiali.
+func (in *MeshService) ResolveKialiControlPlaneCluster(r *http.Request) (*Cluster, error) {
+	myClusterName, err := in.resolveKialiClusterName()
+	if err != nil {
+		return nil, err
 	}
 
 	if len(myClusterName) == 0 {
@@ -213,6 +236,30 @@ func (in *MeshService) ResolveKialiControlPlaneCluster(r *http.Request) (*Cluste
 	}, nil
 }
 
+// resolveKialiClusterName returns the CLUSTER_ID of the cluster where Kiali is installed, or an empty string when
+// istiod doesn't set it.
+func (in *MeshService) resolveKialiClusterName() (string, error) {
+	conf := config.Get()
+
+	// The "cluster_id" is set in an environment variable of
+	// the "istiod" deployment. Let's try to fetch it.
+	istioDeployment, err := in.k8s.GetDeployment(conf.IstioNamespace, "istiod")
+	if err != nil {
+		return "", err
+	}
+
+	if istioDeployment == nil || len(istioDeployment.Spec.Template.Spec.Containers) == 0 {
+		return "", nil
+	}
+
+	for _, v := range istioDeployment.Spec.Template.Spec.Containers[0].Env {
+		if v.Name == "CLUSTER_ID" {
+			return v.Value, nil
+		}
+	}
+	return "", nil
+}
+
 // findKialiInNamespace tries to find a Kiali installation certain namespace of a cluster.
 // The clientSet argument should be an already initialized REST client to the API server of the
 // cluster. The namespace argument specifies the namespace where a Kiali instance will be looked for.
@@ -253,15 +300,7 @@ func findKialiInNamespace(namespace string, clusterName string, clientSet kubern
 func (in *MeshService) findRemoteKiali(clusterName string, kubeconfig *kubernetes.RemoteSecret) (kialiInstances []KialiInstance) {
 	conf := config.Get()
 
-	restConfig, restConfigErr := kubernetes.UseRemoteCreds(kubeconfig)
-	if restConfigErr != nil {
-		log.Errorf("Error using remote creds: %v", restConfigErr)
-		return nil
-	}
-
-	restConfig.Timeout = 15 * time.Second
-	restConfig.BearerToken = kubeconfig.Users[0].User.Token
-	clientSet, clientSetErr := in.newRemoteClient(restConfig)
+	clientSet, clientSetErr := in.newRemoteClientFromSecret(kubeconfig)
 	if clientSetErr != nil {
 		log.Errorf("Error creating client set: %v", clientSetErr)
 		return nil
@@ -359,8 +398,6 @@ func (in *MeshService) resolveKialiNetwork() (string, error) {
 // visible to the adjacent mesh control plane. This assumes that the Istio namespace is
 // named the same as in Kiali's Cluster.
 func (in *MeshService) resolveRemoteClustersFromSecrets() ([]Cluster, error) {
-	conf := config.Get()
-
 	// For the ControlPlane to be able to "see" remote clusters, some "remote secrets" need to be in
 	// place. These remote secrets contain <kubeconfig files> that the ControlPlane uses to
 	// query the remote clusters. Without them, the control plane is not capable of pushing traffic
@@ -370,21 +407,54 @@ func (in *MeshService) resolveRemoteClustersFromSecrets() ([]Cluster, error) {
 	// which is resolved in ResolveKialiControlPlaneCluster func).
 	// Strictly speaking, this list may be incomplete: it's list of visible clusters for a control plane.
 	// But, for now, let's use it as the absolute "list of clusters in the mesh (excluding home cluster)".
+	remoteSecrets, err := in.getRemoteClusterSecrets()
+	if err != nil {
+		return []Cluster{}, err
+	}
+
+	clusters := make([]Cluster, 0, len(remoteSecrets))
+
+	for _, remoteSecret := range remoteSecrets {
+		meshCluster := Cluster{
+			Name:        remoteSecret.clusterName,
+			SecretName:  remoteSecret.secretName,
+			ApiEndpoint: remoteSecret.kubeconfig.Clusters[0].Cluster.Server,
+		}
+
+		networkName := in.resolveNetwork(remoteSecret.clusterName, remoteSecret.kubeconfig)
+		if len(networkName) != 0 {
+			meshCluster.Network = networkName
+		}
+
+		meshCluster.KialiInstances = in.findRemoteKiali(remoteSecret.clusterName, remoteSecret.kubeconfig)
+		clusters = append(clusters, meshCluster)
+	}
+
+	return clusters, nil
+}
+
+// remoteClusterSecret holds the kubeconfig file of a "remote secret" giving access to a remote cluster
+type remoteClusterSecret struct {
+	clusterName string
+	secretName  string
+	kubeconfig  *kubernetes.RemoteSecret
+}
+
+// getRemoteClusterSecrets returns the parsed "remote secrets" that the control plane uses to query the remote
+// clusters. Secrets that can't be parsed are ignored.
+func (in *MeshService) getRemoteClusterSecrets() ([]remoteClusterSecret, error) {
+	conf := config.Get()
 
 	// "Remote secrets" are created using the command `istioctl x create-remote-secret` which
 	// labels the secrets with istio/multiCluster=true. Let's use that label to fetch the secrets of interest.
 	secrets, err := in.k8s.GetSecrets(conf.IstioNamespace, "istio/multiCluster=true")
 	if err != nil {
-		return []Cluster{}, err
-	}
-
-	if len(secrets) == 0 {
-		return []Cluster{}, nil
+		return nil, err
 	}
 
-	clusters := make([]Cluster, 0, len(secrets))
+	remoteSecrets := make([]remoteClusterSecret, 0, len(secrets))
 
-	// Inspect the secret to extract the cluster_id and api_endpoint of each remote cluster.
+	// Inspect the secret to extract the cluster_id and the kubeconfig file of each remote cluster.
 	for _, secret := range secrets {
 		clusterName, ok := secret.Annotations["networking.istio.io/cluster"]
 		if !ok {
@@ -408,22 +478,205 @@ func (in *MeshService) resolveRemoteClustersFromSecrets() ([]Cluster, error) {
 			continue
 		}
 
-		meshCluster := Cluster{
-			Name:        clusterName,
-			SecretName:  secret.Name,
-			ApiEndpoint: parsedSecret.Clusters[0].Cluster.Server,
+		remoteSecrets = append(remoteSecrets, remoteClusterSecret{
+			clusterName: clusterName,
+			secretName:  secret.Name,
+			kubeconfig:  parsedSecret,
+		})
+	}
+
+	return remoteSecrets, nil
+}
+
+// newRemoteClientFromSecret returns a client of the remote cluster that can be accessed using the provided
+// kubeconfig file, as generated by the `istioctl x create-remote-secret` command.
+func (in *MeshService) newRemoteClientFromSecret(kubeconfig *kubernetes.RemoteSecret) (kubernetes.ClientInterface, error) {
+	restConfig, err := kubernetes.UseRemoteCreds(kubeconfig)
+	if err != nil {
+		return nil, fmt.Errorf("error using remote creds: %w", err)
+	}
+
+	restConfig.Timeout = 15 * time.Second
+	restConfig.BearerToken = kubeconfig.Users[0].User.Token
+	return in.newRemoteClient(restConfig)
+}
+
+// newUserRemoteClientFromSecret returns a client of the remote cluster of the kubeconfig file authenticated with the
+// credentials of the user, instead of the ones of the remote secret, so that the user can only perform in the
+// remote cluster what the cluster grants to the user.
+func (in *MeshService) newUserRemoteClientFromSecret(clusterName string, kubeconfig *kubernetes.RemoteSecret) (kubernetes.ClientInterface, error) {
+	if in.authInfo == nil || in.authInfo.Token == "" {
+		return nil, &AccessibleNamespaceError{msg: "Cluster [" + clusterName + "] is not accessible: the credentials of the user are not available for the remote clusters"}
+	}
+
+	restConfig, err := kubernetes.UseRemoteCreds(kubeconfig)
+	if err != nil {
+		return nil, fmt.Errorf("error using remote creds: %w", err)
+	}
+
+	restConfig.Timeout = 15 * time.Second
+	restConfig.BearerToken = in.authInfo.Token
+	return in.newRemoteClient(restConfig)
+}
+
+// getUserClusterClient returns the client of the cluster with the credentials of the user, and whether the cluster
+// is a remote one. The home cluster of Kiali, or an empty cluster, is accessed with the client of the user.
+func (in *MeshService) getUserClusterClient(cluster string) (kubernetes.ClientInterface, bool, error) {
+	if cluster == "" {
+		return in.k8s, false, nil
+	}
+	homeName, err := in.homeClusterName()
+	if err != nil {
+		return nil, false, err
+	}
+	if cluster == homeName {
+		return in.k8s, false, nil
+	}
+	remoteSecrets, err := in.getRemoteClusterSecrets()
+	if err != nil {
+		return nil, false, err
+	}
+	for _, remoteSecret := range remoteSecrets {
+		if remoteSecret.clusterName == cluster {
+			client, err := in.newUserRemoteClientFromSecret(cluster, remoteSecret.kubeconfig)
+			if err != nil {
+				if IsAccessibleError(err) {
+					return nil, true, err
+				}
+				return nil, true, errors.NewServiceUnavailable(fmt.Sprintf("the remote cluster [%s] is not accessible: %v", cluster, err))
+			}
+			return client, true, nil
 		}
+	}
+	return nil, false, errors.NewNotFound(schema.GroupResource{Resource: "clusters"}, cluster)
+}
 
-		networkName := in.resolveNetwork(clusterName, parsedSecret)
-		if len(networkName) != 0 {
-			meshCluster.Network = networkName
+// getKialiClusterClient returns the client of the cluster with the credentials of Kiali: its ServiceAccount in the home
+// cluster, the credentials of the remote secret in a remote cluster.
+func (in *MeshService) getKialiClusterClient(cluster string) (kubernetes.ClientInterface, error) {
+	if cluster == "" {
+		return kialiSAClient()
+	}
+	homeName, err := in.homeClusterName()
+	if err != nil {
+		return nil, err
+	}
+	if cluster == homeName {
+		return kialiSAClient()
+	}
+	remoteSecrets, err := in.getRemoteClusterSecrets()
+	if err != nil {
+		return nil, err
+	}
+	for _, remoteSecret := range remoteSecrets {
+		if remoteSecret.clusterName == cluster {
+			return in.newRemoteClientFromSecret(remoteSecret.kubeconfig)
 		}
+	}
+	return nil, errors.NewNotFound(schema.GroupResource{Resource: "clusters"}, cluster)
+}
 
-		meshCluster.KialiInstances = in.findRemoteKiali(clusterName, parsedSecret)
-		clusters = append(clusters, meshCluster)
+// ClusterRegistry holds the services of a namespace in a remote cluster of the mesh, with their pods
+type ClusterRegistry struct {
+	// Cluster is the CLUSTER_ID of the remote cluster
+	Cluster string
+
+	// Services are the services of the namespace in the remote cluster exported to the namespace
+	Services []core_v1.Service
+
+	// Pods are the pods of the namespace in the remote cluster
+	Pods []core_v1.Pod
+}
+
+// How long the registry of a namespace in a remote cluster is kept before querying the cluster again
+const remoteRegistriesTTL = 30 * time.Second
+
+var (
+	remoteRegistriesLock  sync.Mutex
+	remoteRegistriesCache = map[string]cachedClusterRegistry{}
+)
+
+type cachedClusterRegistry struct {
+	registry   ClusterRegistry
+	expiration time.Time
+}
+
+// GetRemoteRegistries returns the registries of the namespace in the remote clusters of the mesh. Istio merges
+// the registries of all the clusters, so a service of the namespace defined only in a remote cluster can be
+// referenced from the local cluster. The clusters are queried in parallel, and their registries are kept for a
+// while, as they are read on every validation. Remote clusters that can't be queried are skipped.
+func (in *MeshService) GetRemoteRegistries(namespace string) ([]ClusterRegistry, error) {
+	remoteSecrets, err := in.getRemoteClusterSecrets()
+	if err != nil {
+		return nil, err
 	}
 
-	return clusters, nil
+	fetched := make([]*ClusterRegistry, len(remoteSecrets))
+	wg := sync.WaitGroup{}
+	wg.Add(len(remoteSecrets))
+	for i, remoteSecret := range remoteSecrets {
+		go func(i int, remoteSecret remoteClusterSecret) {
+			defer wg.Done()
+			fetched[i] = in.getRemoteRegistry(remoteSecret, namespace)
+		}(i, remoteSecret)
+	}
+	wg.Wait()
+
+	registries := make([]ClusterRegistry, 0, len(remoteSecrets))
+	for _, registry := range fetched {
+		if registry != nil {
+			registries = append(registries, *registry)
+		}
+	}
+	return registries, nil
+}
+
+// getRemoteRegistry returns the registry of the namespace in the remote cluster, from the cache when not expired.
+// The registries are read with the credentials of the remote secrets, not the ones of the user, so they are shared
+// by the users; the access to the namespace is checked by the callers. Nil is returned when the cluster can't be
+// queried.
+func (in *MeshService) getRemoteRegistry(remoteSecret remoteClusterSecret, namespace string) *ClusterRegistry {
+	key := remoteSecret.clusterName + "|" + remoteSecret.kubeconfig.Clusters[0].Cluster.Server + "|" + namespace
+	now := util.Clock.Now()
+	remoteRegistriesLock.Lock()
+	cached, found := remoteRegistriesCache[key]
+	remoteRegistriesLock.Unlock()
+	if found && now.Before(cached.expiration) {
+		return &cached.registry
+	}
+
+	clientSet, clientSetErr := in.newRemoteClientFromSecret(remoteSecret.kubeconfig)
+	if clientSetErr != nil {
+		log.Errorf("Error creating client set for cluster [%s]: %v", remoteSecret.clusterName, clientSetErr)
+		return nil
+	}
+
+	services, svcErr := clientSet.GetServices(namespace, nil)
+	if svcErr != nil {
+		log.Warningf("Cannot fetch the services of namespace [%s] in cluster [%s]: %v", namespace, remoteSecret.clusterName, svcErr)
+		return nil
+	}
+	pods, podsErr := clientSet.GetPods(namespace, "")
+	if podsErr != nil {
+		log.Warningf("Cannot fetch the pods of namespace [%s] in cluster [%s]: %v", namespace, remoteSecret.clusterName, podsErr)
+		return nil
+	}
+
+	registry := ClusterRegistry{
+		Cluster:  remoteSecret.clusterName,
+		Services: kubernetes.FilterServicesExportedTo(namespace, services),
+		Pods:     pods,
+	}
+	remoteRegistriesLock.Lock()
+	// The expired registries are dropped so that the ones of removed clusters or namespaces don't pile up
+	for k, c := range remoteRegistriesCache {
+		if !now.Before(c.expiration) {
+			delete(remoteRegistriesCache, k)
+		}
+	}
+	remoteRegistriesCache[key] = cachedClusterRegistry{registry: registry, expiration: now.Add(remoteRegistriesTTL)}
+	remoteRegistriesLock.Unlock()
+	return &registry
 }
 
 // resolveNetwork tries to resolve the NETWORK_ID (as know by the Control Plane) of the
@@ -437,15 +690,7 @@ func (in *MeshService) resolveRemoteClustersFromSecrets() ([]Cluster, error) {
 func (in *MeshService) resolveNetwork(clusterName string, kubeconfig *kubernetes.RemoteSecret) string {
 	conf := config.Get()
 
-	restConfig, restConfigErr := kubernetes.UseRemoteCreds(kubeconfig)
-	if restConfigErr != nil {
-		log.Errorf("Error using remote creds: %v", restConfigErr)
-		return ""
-	}
-
-	restConfig.Timeout = 15 * time.Second
-	restConfig.BearerToken = kubeconfig.Users[0].User.Token
-	clientSet, clientSetErr := in.newRemoteClient(restConfig)
+	clientSet, clientSetErr := in.newRemoteClientFromSecret(kubeconfig)
 	if clientSetErr != nil {
 		log.Errorf("Error creating client set: %v", clientSetErr)
 		return ""
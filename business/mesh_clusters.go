@@ -0,0 +1,220 @@
+package business
+
+import (
+	"sort"
+	"sync"
+
+	core_v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/log"
+	"github.com/kiali/kiali/prometheus/internalmetrics"
+)
+
+// The CLUSTER_ID of istiod when not set
+const defaultIstioClusterName = "Kubernetes"
+
+// ClusterOverview summarizes the health of a cluster of the mesh
+type ClusterOverview struct {
+	// Name is the CLUSTER_ID of the cluster
+	//
+	// required: true
+	// example: east
+	Name string `json:"name"`
+
+	// IsKialiHome specifies if this cluster is hosting this Kiali instance
+	//
+	// required: true
+	IsKialiHome bool `json:"isKialiHome"`
+
+	// ApiEndpoint is the URL of the API server of a remote cluster, as found in its remote secret
+	ApiEndpoint string `json:"apiEndpoint,omitempty"`
+
+	// Status is Healthy, Unhealthy when its control plane is not healthy, or Unreachable when the API of the cluster
+	// can't be queried
+	//
+	// required: true
+	// example: Healthy
+	Status string `json:"status"`
+
+	// Reachable is false when the API of the cluster can't be queried, the other fields are not known then
+	//
+	// required: true
+	Reachable bool `json:"reachable"`
+
+	// Error explains why the cluster is unreachable
+	Error string `json:"error,omitempty"`
+
+	// ControlPlane is the status of the istiod replicas of the cluster. A remote cluster may have none, its proxies
+	// being connected to the control plane of another cluster.
+	ControlPlane *IstiodStatus `json:"controlPlane,omitempty"`
+
+	// Revisions are the revisions of the control plane of the cluster
+	//
+	// required: true
+	// example: ["default"]
+	Revisions []string `json:"revisions"`
+
+	// Versions are the Istio versions run by the control plane of the cluster
+	//
+	// required: true
+	// example: ["1.12.1"]
+	Versions []string `json:"versions"`
+
+	// ConnectedProxies is the number of running pods with an Istio proxy, sidecars and gateways
+	//
+	// required: true
+	ConnectedProxies int `json:"connectedProxies"`
+}
+
+// GetClustersOverview returns the health of the home cluster of Kiali and of the remote clusters of the mesh:
+// reachability, control plane status and version, and number of connected proxies. The clusters are queried in
+// parallel, an unreachable cluster doesn't fail the overview.
+func (in *MeshService) GetClustersOverview() ([]ClusterOverview, error) {
+	var err error
+	promtimer := internalmetrics.GetGoFunctionMetric("business", "MeshService", "GetClustersOverview")
+	defer promtimer.ObserveNow(&err)
+
+	var homeName string
+	if homeName, err = in.homeClusterName(); err != nil {
+		return nil, err
+	}
+	var remoteSecrets []remoteClusterSecret
+	if remoteSecrets, err = in.getRemoteClusterSecrets(); err != nil {
+		return nil, err
+	}
+
+	overviews := make([]ClusterOverview, len(remoteSecrets)+1)
+	wg := sync.WaitGroup{}
+	wg.Add(len(overviews))
+	go func() {
+		defer wg.Done()
+		overviews[0] = in.homeClusterOverview(homeName)
+	}()
+	for i, remoteSecret := range remoteSecrets {
+		go func(overview *ClusterOverview, remoteSecret remoteClusterSecret) {
+			defer wg.Done()
+			*overview = in.remoteClusterOverview(remoteSecret)
+		}(&overviews[i+1], remoteSecret)
+	}
+	wg.Wait()
+
+	// The home cluster first, then the remote clusters by name
+	sort.SliceStable(overviews[1:], func(i, j int) bool {
+		return overviews[i+1].Name < overviews[j+1].Name
+	})
+	return overviews, nil
+}
+
+// homeClusterName returns the CLUSTER_ID of the home cluster of Kiali: the one of istiod, else the one of the Kiali
+// configuration, else the default one of istiod
+func (in *MeshService) homeClusterName() (string, error) {
+	name, err := in.resolveKialiClusterName()
+	if err != nil {
+		return "", err
+	}
+	if name == "" {
+		name = config.Get().KubernetesConfig.ClusterName
+	}
+	if name == "" {
+		name = defaultIstioClusterName
+	}
+	return name, nil
+}
+
+// homeClusterOverview checks the istiod replicas of the home cluster through the API proxy, or the external istiod
+func (in *MeshService) homeClusterOverview(name string) ClusterOverview {
+	istioStatus := IstioStatusService{k8s: in.k8s}
+	istiodStatus, err := istioStatus.GetIstiodStatus()
+	if err != nil {
+		return unreachableClusterOverview(ClusterOverview{Name: name, IsKialiHome: true}, err)
+	}
+	return clusterOverview(ClusterOverview{Name: name, IsKialiHome: true}, in.k8s, istiodStatus)
+}
+
+// remoteClusterOverview checks the istiod pods of a remote cluster with the credentials of its remote secret, which
+// usually don't allow to proxy the pods
+func (in *MeshService) remoteClusterOverview(remoteSecret remoteClusterSecret) ClusterOverview {
+	overview := ClusterOverview{
+		Name:        remoteSecret.clusterName,
+		ApiEndpoint: remoteSecret.kubeconfig.Clusters[0].Cluster.Server,
+	}
+	clientSet, err := in.newRemoteClientFromSecret(remoteSecret.kubeconfig)
+	if err != nil {
+		return unreachableClusterOverview(overview, err)
+	}
+	// Let's assume that the istio namespace has the same name on all clusters in the mesh.
+	istiods, err := clientSet.GetPods(config.Get().IstioNamespace, labels.Set(map[string]string{"app": "istiod"}).String())
+	if err != nil {
+		return unreachableClusterOverview(overview, err)
+	}
+	return clusterOverview(overview, clientSet, aggregateIstiodStatus(istiodReplicas(istiods)))
+}
+
+// clusterOverview completes the overview of a reachable cluster with its control plane and its proxies
+func clusterOverview(overview ClusterOverview, clientSet kubernetes.ClientInterface, istiodStatus *IstiodStatus) ClusterOverview {
+	overview.Reachable = true
+	overview.ControlPlane = istiodStatus
+	overview.Revisions = []string{}
+	overview.Versions = []string{}
+	versions := map[string]bool{}
+	for revision, revisionVersions := range istiodStatus.Versions {
+		overview.Revisions = append(overview.Revisions, revision)
+		for _, version := range revisionVersions {
+			if !versions[version] {
+				versions[version] = true
+				overview.Versions = append(overview.Versions, version)
+			}
+		}
+	}
+	for _, replica := range istiodStatus.Replicas {
+		if !checkType(overview.Revisions, replica.Revision) {
+			overview.Revisions = append(overview.Revisions, replica.Revision)
+		}
+	}
+	sort.Strings(overview.Revisions)
+	sort.Strings(overview.Versions)
+
+	// The proxies of a remote cluster without istiod are connected to the control plane of another cluster
+	switch {
+	case istiodStatus.Status == Healthy:
+		overview.Status = Healthy
+	case istiodStatus.Status == NotFound && !overview.IsKialiHome:
+		overview.Status = Healthy
+	default:
+		overview.Status = Unhealthy
+	}
+
+	pods, err := clientSet.GetPods("", "")
+	if err != nil {
+		log.Warningf("Cannot count the proxies of cluster [%s]: %v", overview.Name, err)
+		return overview
+	}
+	for _, pod := range pods {
+		if pod.Status.Phase == core_v1.PodRunning && pod.DeletionTimestamp == nil && hasIstioProxy(pod) {
+			overview.ConnectedProxies++
+		}
+	}
+	return overview
+}
+
+func unreachableClusterOverview(overview ClusterOverview, err error) ClusterOverview {
+	log.Warningf("Cluster [%s] of the mesh is unreachable: %v", overview.Name, err)
+	overview.Status = Unreachable
+	overview.Error = err.Error()
+	overview.Revisions = []string{}
+	overview.Versions = []string{}
+	return overview
+}
+
+// hasIstioProxy tells whether a pod runs an Istio proxy, either injected as a sidecar or as a gateway
+func hasIstioProxy(pod core_v1.Pod) bool {
+	for _, c := range pod.Spec.Containers {
+		if c.Name == "istio-proxy" {
+			return true
+		}
+	}
+	return false
+}
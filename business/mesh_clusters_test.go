@@ -0,0 +1,148 @@
+package business
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v2"
+	apps_v1 "k8s.io/api/apps/v1"
+	core_v1 "k8s.io/api/core/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/kubernetes/kubetest"
+)
+
+func TestGetClustersOverview(t *testing.T) {
+	check := assert.New(t)
+	conf := config.NewConfig()
+	config.Set(conf)
+
+	k8s := new(kubetest.K8SClientMock)
+	k8s.On("GetDeployment", conf.IstioNamespace, "istiod").Return(&apps_v1.Deployment{
+		Spec: apps_v1.DeploymentSpec{Template: core_v1.PodTemplateSpec{Spec: core_v1.PodSpec{
+			Containers: []core_v1.Container{{Env: []core_v1.EnvVar{{Name: "CLUSTER_ID", Value: "home"}}}},
+		}}},
+	}, nil)
+	k8s.On("GetSecrets", conf.IstioNamespace, "istio/multiCluster=true").Return([]core_v1.Secret{
+		fakeRemoteClusterSecret("west", "https://west.example.com:6443"),
+		fakeRemoteClusterSecret("east", "https://east.example.com:6443"),
+	}, nil)
+	k8s.On("GetPods", conf.IstioNamespace, "app=istiod").Return([]core_v1.Pod{
+		fakeIstiodPod("istiod-1", "", "1.12.1", "Running"),
+		fakeIstiodPod("istiod-canary-1", "canary", "1.13.0", "Running"),
+	}, nil)
+	k8s.On("GetPodProxy", conf.IstioNamespace, "istiod-1", "/ready").Return([]byte{}, nil)
+	k8s.On("GetPodProxy", conf.IstioNamespace, "istiod-canary-1", "/ready").Return([]byte{}, fmt.Errorf("connection refused"))
+	k8s.On("GetPods", "", "").Return([]core_v1.Pod{
+		fakeClusterPod("productpage-1", core_v1.PodRunning, true),
+		fakeClusterPod("reviews-1", core_v1.PodRunning, true),
+		fakeClusterPod("reviews-2", core_v1.PodPending, true),
+		fakeClusterPod("legacy-1", core_v1.PodRunning, false),
+	}, nil)
+
+	// The east cluster has no istiod, its proxies are connected to the home control plane. The west cluster is down.
+	newRemoteClient := func(config *rest.Config) (kubernetes.ClientInterface, error) {
+		remoteClient := new(kubetest.K8SClientMock)
+		if config.Host == "https://west.example.com:6443" {
+			remoteClient.On("GetPods", conf.IstioNamespace, "app=istiod").Return([]core_v1.Pod{}, fmt.Errorf("dial tcp: i/o timeout"))
+			return remoteClient, nil
+		}
+		remoteClient.On("GetPods", conf.IstioNamespace, "app=istiod").Return([]core_v1.Pod{}, nil)
+		remoteClient.On("GetPods", "", "").Return([]core_v1.Pod{
+			fakeClusterPod("ratings-1", core_v1.PodRunning, true),
+			fakeClusterPod("istio-eastwestgateway-1", core_v1.PodRunning, true),
+		}, nil)
+		return remoteClient, nil
+	}
+
+	meshSvc := NewMeshService(k8s, newRemoteClient)
+	overview, err := meshSvc.GetClustersOverview()
+	check.NoError(err)
+	check.Len(overview, 3)
+
+	home := overview[0]
+	check.Equal("home", home.Name)
+	check.True(home.IsKialiHome)
+	check.True(home.Reachable)
+	check.Equal(Unhealthy, home.Status)
+	check.Equal(Unhealthy, home.ControlPlane.Status)
+	check.Equal([]string{"canary", "default"}, home.Revisions)
+	check.Equal([]string{"1.12.1", "1.13.0"}, home.Versions)
+	check.Equal(2, home.ConnectedProxies)
+
+	east := overview[1]
+	check.Equal("east", east.Name)
+	check.False(east.IsKialiHome)
+	check.True(east.Reachable)
+	check.Equal(Healthy, east.Status)
+	check.Equal(NotFound, east.ControlPlane.Status)
+	check.Equal("https://east.example.com:6443", east.ApiEndpoint)
+	check.Empty(east.Revisions)
+	check.Equal(2, east.ConnectedProxies)
+
+	west := overview[2]
+	check.Equal("west", west.Name)
+	check.False(west.Reachable)
+	check.Equal(Unreachable, west.Status)
+	check.Equal("dial tcp: i/o timeout", west.Error)
+	check.Nil(west.ControlPlane)
+	check.Equal(0, west.ConnectedProxies)
+}
+
+func TestGetClustersOverviewSingleCluster(t *testing.T) {
+	check := assert.New(t)
+	conf := config.NewConfig()
+	conf.KubernetesConfig.ClusterName = "prod"
+	config.Set(conf)
+
+	var nilDeployment *apps_v1.Deployment
+	k8s := new(kubetest.K8SClientMock)
+	k8s.On("GetDeployment", conf.IstioNamespace, "istiod").Return(nilDeployment, nil)
+	k8s.On("GetSecrets", conf.IstioNamespace, "istio/multiCluster=true").Return([]core_v1.Secret{}, nil)
+	k8s.On("GetPods", conf.IstioNamespace, "app=istiod").Return([]core_v1.Pod{fakeIstiodPod("istiod-1", "", "1.12.1", "Running")}, nil)
+	k8s.On("GetPodProxy", conf.IstioNamespace, "istiod-1", "/ready").Return([]byte{}, nil)
+	k8s.On("GetPods", "", "").Return([]core_v1.Pod{}, nil)
+
+	meshSvc := NewMeshService(k8s, nil)
+	overview, err := meshSvc.GetClustersOverview()
+	check.NoError(err)
+	check.Len(overview, 1)
+	check.Equal("prod", overview[0].Name)
+	check.Equal(Healthy, overview[0].Status)
+	check.Equal([]string{"default"}, overview[0].Revisions)
+	check.Equal(0, overview[0].ConnectedProxies)
+}
+
+func fakeRemoteClusterSecret(cluster, server string) core_v1.Secret {
+	kubeconfig, _ := yaml.Marshal(kubernetes.RemoteSecret{
+		Clusters: []kubernetes.RemoteSecretClusterListItem{
+			{Name: cluster, Cluster: kubernetes.RemoteSecretCluster{CertificateAuthorityData: "eAo=", Server: server}},
+		},
+		Users: []kubernetes.RemoteSecretUser{
+			{Name: cluster, User: kubernetes.RemoteSecretUserToken{Token: "token"}},
+		},
+	})
+	return core_v1.Secret{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:        "istio-remote-secret-" + cluster,
+			Annotations: map[string]string{"networking.istio.io/cluster": cluster},
+		},
+		Data: map[string][]byte{cluster: kubeconfig},
+	}
+}
+
+func fakeClusterPod(name string, phase core_v1.PodPhase, proxy bool) core_v1.Pod {
+	pod := core_v1.Pod{
+		ObjectMeta: meta_v1.ObjectMeta{Name: name},
+		Spec:       core_v1.PodSpec{Containers: []core_v1.Container{{Name: "app"}}},
+		Status:     core_v1.PodStatus{Phase: phase},
+	}
+	if proxy {
+		pod.Spec.Containers = append(pod.Spec.Containers, core_v1.Container{Name: "istio-proxy"})
+	}
+	return pod
+}
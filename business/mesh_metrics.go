@@ -0,0 +1,131 @@
+package business
+
+import (
+	"sync"
+	"time"
+
+	"k8s.io/client-go/tools/clientcmd/api"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/log"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/prometheus/internalmetrics"
+)
+
+// Mesh metrics that can be listed in the mesh_metrics configuration
+const (
+	MeshMetricMTLS        = "mtls"
+	MeshMetricValidations = "validations"
+)
+
+var (
+	meshMetricsLock     sync.Mutex
+	meshMetricsStopChan chan struct{}
+)
+
+// StartMeshMetricsCollector periodically computes the configured mesh metrics with the Kiali ServiceAccount
+// and publishes them as internal metrics. Metrics are not computed on scrapes, so scrapes don't add any load.
+func StartMeshMetricsCollector() {
+	meshMetricsLock.Lock()
+	defer meshMetricsLock.Unlock()
+	if meshMetricsStopChan != nil {
+		return
+	}
+
+	conf := config.Get().Server.MeshMetrics
+	interval := time.Duration(conf.RefreshInterval) * time.Second
+	if interval <= 0 {
+		interval = time.Minute
+	}
+	stopChan := make(chan struct{})
+	meshMetricsStopChan = stopChan
+
+	log.Infof("Starting mesh metrics collector for %v every %v", conf.Metrics, interval)
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			collectMeshMetricsWithKialiSA(conf.Metrics)
+			select {
+			case <-ticker.C:
+			case <-stopChan:
+				log.Info("Mesh metrics collector stopped")
+				return
+			}
+		}
+	}()
+}
+
+// StopMeshMetricsCollector stops the mesh metrics collector if it's running
+func StopMeshMetricsCollector() {
+	meshMetricsLock.Lock()
+	defer meshMetricsLock.Unlock()
+	if meshMetricsStopChan != nil {
+		close(meshMetricsStopChan)
+		meshMetricsStopChan = nil
+	}
+}
+
+func collectMeshMetricsWithKialiSA(metrics []string) {
+	kialiToken, err := kubernetes.GetKialiToken()
+	if err != nil {
+		log.Errorf("Mesh metrics can't be collected, Kiali token is not available: %s", err)
+		return
+	}
+	layer, err := Get(&api.AuthInfo{Token: kialiToken})
+	if err != nil {
+		log.Errorf("Mesh metrics can't be collected, business layer is not available: %s", err)
+		return
+	}
+	if err = collectMeshMetrics(layer, metrics); err != nil {
+		log.Errorf("Error collecting mesh metrics: %s", err)
+	}
+}
+
+// collectMeshMetrics computes the listed mesh metrics and updates the internal metrics with the results
+func collectMeshMetrics(layer *Layer, metrics []string) error {
+	var err error
+	promtimer := internalmetrics.GetGoFunctionMetric("business", "MeshMetrics", "collectMeshMetrics")
+	defer promtimer.ObserveNow(&err)
+
+	nss, err := layer.Namespace.GetNamespaces()
+	if err != nil {
+		return err
+	}
+	nsNames := make([]string, 0, len(nss))
+	for _, ns := range nss {
+		nsNames = append(nsNames, ns.Name)
+	}
+
+	for _, metric := range metrics {
+		switch metric {
+		case MeshMetricValidations:
+			// A namespace failing to validate doesn't prevent publishing the others
+			counts := make(map[string]map[string]int, len(nsNames))
+			for _, ns := range nsNames {
+				validations, nsErr := layer.Validations.GetValidations(ns, "")
+				if nsErr != nil {
+					log.Warningf("Mesh validations of namespace [%s] can't be collected: %s", ns, nsErr)
+					continue
+				}
+				summary := validations.SummarizeValidation(ns)
+				counts[ns] = map[string]int{
+					string(models.ErrorSeverity):   summary.Errors,
+					string(models.WarningSeverity): summary.Warnings,
+				}
+			}
+			internalmetrics.SetMeshValidations(counts)
+		case MeshMetricMTLS:
+			var status models.MTLSStatus
+			status, err = layer.TLS.MeshWidemTLSStatus(nsNames)
+			if err != nil {
+				return err
+			}
+			internalmetrics.SetMeshMTLSStatus(status.Status)
+		default:
+			log.Warningf("Unknown mesh metric [%s] is ignored", metric)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+package business
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/kubernetes/kubetest"
+	"github.com/kiali/kiali/prometheus/internalmetrics"
+)
+
+func TestCollectMeshMetrics(t *testing.T) {
+	assert := assert.New(t)
+	registry := setupMeshMetricsRegistry(t)
+
+	k8s := mockNamespaceOverview()
+	config.Set(config.NewConfig())
+	layer := NewWithBackends(k8s, nil, nil)
+
+	err := collectMeshMetrics(layer, []string{MeshMetricValidations, MeshMetricMTLS})
+	assert.NoError(err)
+
+	metrics, err := registry.Gather()
+	assert.NoError(err)
+	found := 0
+	for _, m := range metrics {
+		switch m.GetName() {
+		case "kiali_mesh_validations":
+			found++
+			// One timeseries per namespace and severity
+			assert.Len(m.GetMetric(), 4)
+			for _, ts := range m.GetMetric() {
+				labels := map[string]string{}
+				for _, l := range ts.GetLabel() {
+					labels[l.GetName()] = l.GetValue()
+				}
+				assert.Contains([]string{"test", "test2"}, labels["namespace"])
+				assert.Contains([]string{"error", "warning"}, labels["severity"])
+			}
+		case "kiali_mesh_mtls_status":
+			found++
+			assert.Len(m.GetMetric(), 1)
+			assert.Equal(float64(1), m.GetMetric()[0].GetGauge().GetValue())
+		}
+	}
+	assert.Equal(2, found)
+}
+
+func TestCollectMeshMetricsNamespaceFailure(t *testing.T) {
+	assert := assert.New(t)
+	registry := setupMeshMetricsRegistry(t)
+
+	k8s := new(kubetest.K8SClientMock)
+	k8s.On("GetIstioObjects", "test2", "requestauthentications", "").Return([]kubernetes.IstioObject{}, errors.New("forbidden"))
+	setupNamespaceOverviewMocks(k8s)
+	config.Set(config.NewConfig())
+	layer := NewWithBackends(k8s, nil, nil)
+
+	err := collectMeshMetrics(layer, []string{MeshMetricValidations, MeshMetricMTLS})
+	assert.NoError(err)
+
+	metrics, err := registry.Gather()
+	assert.NoError(err)
+	found := 0
+	for _, m := range metrics {
+		switch m.GetName() {
+		case "kiali_mesh_validations":
+			found++
+			// The validations of the other namespace are published
+			assert.Len(m.GetMetric(), 2)
+			for _, ts := range m.GetMetric() {
+				for _, l := range ts.GetLabel() {
+					if l.GetName() == "namespace" {
+						assert.Equal("test", l.GetValue())
+					}
+				}
+			}
+		case "kiali_mesh_mtls_status":
+			found++
+		}
+	}
+	assert.Equal(2, found)
+}
+
+func TestCollectMeshMetricsSubset(t *testing.T) {
+	assert := assert.New(t)
+	registry := setupMeshMetricsRegistry(t)
+
+	k8s := mockNamespaceOverview()
+	config.Set(config.NewConfig())
+	layer := NewWithBackends(k8s, nil, nil)
+
+	err := collectMeshMetrics(layer, []string{MeshMetricMTLS})
+	assert.NoError(err)
+
+	metrics, err := registry.Gather()
+	assert.NoError(err)
+	names := []string{}
+	for _, m := range metrics {
+		names = append(names, m.GetName())
+	}
+	assert.NotContains(names, "kiali_mesh_validations")
+	assert.Contains(names, "kiali_mesh_mtls_status")
+}
+
+func setupMeshMetricsRegistry(t *testing.T) *prometheus.Registry {
+	// put back the original default registry when we are done
+	originalRegistry := prometheus.DefaultRegisterer
+	t.Cleanup(func() {
+		prometheus.DefaultRegisterer = originalRegistry
+		internalmetrics.Metrics.MeshValidations.Reset()
+		internalmetrics.Metrics.MeshMTLSStatus.Reset()
+	})
+
+	internalmetrics.Metrics.MeshValidations.Reset()
+	internalmetrics.Metrics.MeshMTLSStatus.Reset()
+	registry := prometheus.NewRegistry()
+	prometheus.DefaultRegisterer = registry
+	internalmetrics.RegisterInternalMetrics()
+	return registry
+}
@@ -1,11 +1,14 @@
 package business
 
 import (
+	"fmt"
 	"net/http/httptest"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"gopkg.in/yaml.v2"
 	apps_v1 "k8s.io/api/apps/v1"
 	core_v1 "k8s.io/api/core/v1"
@@ -16,6 +19,7 @@ import (
 	"github.com/kiali/kiali/config"
 	"github.com/kiali/kiali/kubernetes"
 	"github.com/kiali/kiali/kubernetes/kubetest"
+	"github.com/kiali/kiali/util"
 )
 
 func TestGetClustersResolvesTheKialiCluster(t *testing.T) {
@@ -211,3 +215,108 @@ func TestGetClustersResolvesRemoteClusters(t *testing.T) {
 	check.Equal("v1.25", a[0].KialiInstances[0].Version, "GetClusters didn't set the right version of the Kiali instance")
 	check.Equal("kiali-service", a[0].KialiInstances[0].ServiceName, "GetClusters didn't set the right service name of the Kiali instance")
 }
+
+func TestGetRemoteRegistriesCached(t *testing.T) {
+	check := assert.New(t)
+	conf := config.NewConfig()
+	config.Set(conf)
+	resetRemoteRegistries()
+	defer resetRemoteRegistries()
+	now := time.Date(2022, 01, 01, 0, 0, 0, 0, time.UTC)
+	util.Clock = util.ClockMock{Time: now}
+	defer func() { util.Clock = util.RealClock{} }()
+
+	k8s := new(kubetest.K8SClientMock)
+	k8s.On("GetSecrets", conf.IstioNamespace, "istio/multiCluster=true").Return([]core_v1.Secret{
+		fakeRemoteClusterSecret("west", "https://west.example.com:6443"),
+		fakeRemoteClusterSecret("east", "https://east.example.com:6443"),
+		fakeRemoteClusterSecret("south", "https://south.example.com:6443"),
+	}, nil)
+	west, east, south := new(kubetest.K8SClientMock), new(kubetest.K8SClientMock), new(kubetest.K8SClientMock)
+	for _, remoteClient := range []*kubetest.K8SClientMock{west, east} {
+		remoteClient.On("GetServices", "bookinfo", mock.Anything).Return([]core_v1.Service{{ObjectMeta: v1.ObjectMeta{Name: "ratings", Namespace: "bookinfo"}}}, nil)
+		remoteClient.On("GetPods", "bookinfo", "").Return([]core_v1.Pod{{ObjectMeta: v1.ObjectMeta{Name: "ratings-1", Namespace: "bookinfo"}}}, nil)
+	}
+	// The south cluster is down
+	south.On("GetServices", "bookinfo", mock.Anything).Return([]core_v1.Service{}, fmt.Errorf("dial tcp: i/o timeout"))
+	remoteClients := map[string]*kubetest.K8SClientMock{
+		"https://west.example.com:6443":  west,
+		"https://east.example.com:6443":  east,
+		"https://south.example.com:6443": south,
+	}
+	meshSvc := NewMeshService(k8s, func(restConfig *rest.Config) (kubernetes.ClientInterface, error) {
+		return remoteClients[restConfig.Host], nil
+	})
+
+	registries, err := meshSvc.GetRemoteRegistries("bookinfo")
+	check.NoError(err)
+	check.Len(registries, 2)
+	check.Equal("west", registries[0].Cluster)
+	check.Equal("east", registries[1].Cluster)
+	check.Equal("ratings", registries[0].Services[0].Name)
+
+	// The registries are kept, the clusters that couldn't be queried are queried again
+	util.Clock = util.ClockMock{Time: now.Add(remoteRegistriesTTL - time.Second)}
+	registries, err = meshSvc.GetRemoteRegistries("bookinfo")
+	check.NoError(err)
+	check.Len(registries, 2)
+	west.AssertNumberOfCalls(t, "GetServices", 1)
+	east.AssertNumberOfCalls(t, "GetPods", 1)
+	south.AssertNumberOfCalls(t, "GetServices", 2)
+
+	util.Clock = util.ClockMock{Time: now.Add(remoteRegistriesTTL)}
+	_, err = meshSvc.GetRemoteRegistries("bookinfo")
+	check.NoError(err)
+	west.AssertNumberOfCalls(t, "GetServices", 2)
+	east.AssertNumberOfCalls(t, "GetPods", 2)
+
+	// The expired registries are dropped when the ones of other namespaces are kept
+	for _, remoteClient := range []*kubetest.K8SClientMock{west, east} {
+		remoteClient.On("GetServices", "travels", mock.Anything).Return([]core_v1.Service{}, nil)
+		remoteClient.On("GetPods", "travels", "").Return([]core_v1.Pod{}, nil)
+	}
+	south.On("GetServices", "travels", mock.Anything).Return([]core_v1.Service{}, fmt.Errorf("dial tcp: i/o timeout"))
+	util.Clock = util.ClockMock{Time: now.Add(3 * remoteRegistriesTTL)}
+	_, err = meshSvc.GetRemoteRegistries("travels")
+	check.NoError(err)
+	check.Len(remoteRegistriesCache, 2)
+}
+
+func resetRemoteRegistries() {
+	remoteRegistriesLock.Lock()
+	defer remoteRegistriesLock.Unlock()
+	remoteRegistriesCache = map[string]cachedClusterRegistry{}
+}
+
+func TestGetEffectiveMeshConfig(t *testing.T) {
+	check := assert.New(t)
+
+	conf := config.NewConfig()
+	config.Set(conf)
+
+	istioConfigMap := core_v1.ConfigMap{
+		Data: map[string]string{
+			"mesh": "accessLogFile: /dev/stdout\nenableAutoMtls: false\ndefaultConfig:\n  concurrency: 4\n",
+		},
+	}
+	k8s := new(kubetest.K8SClientMock)
+	k8s.On("GetConfigMap", conf.IstioNamespace, conf.ExternalServices.Istio.ConfigMapName).Return(&istioConfigMap, nil)
+
+	layer := NewMeshService(k8s, nil)
+	mc, err := layer.GetEffectiveMeshConfig()
+	check.Nil(err)
+	check.Equal("/dev/stdout", mc.AccessLogFile)
+	check.False(mc.EnableAutoMtls)
+	check.Equal(4, mc.DefaultConfig.Concurrency)
+	check.Equal("ALLOW_ANY", mc.OutboundTrafficPolicy.Mode)
+	check.Equal("istiod.istio-system.svc:15012", mc.DefaultConfig.DiscoveryAddress)
+
+	// A ConfigMap without mesh config runs with the defaults
+	k8s = new(kubetest.K8SClientMock)
+	k8s.On("GetConfigMap", conf.IstioNamespace, conf.ExternalServices.Istio.ConfigMapName).Return(&core_v1.ConfigMap{}, nil)
+	layer = NewMeshService(k8s, nil)
+	mc, err = layer.GetEffectiveMeshConfig()
+	check.Nil(err)
+	check.True(mc.EnableAutoMtls)
+	check.Equal("istio-system", mc.RootNamespace)
+}
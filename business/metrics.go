@@ -1,18 +1,23 @@
 package business
 
 import (
+	"errors"
 	"math"
+	"reflect"
 	"sort"
 	"strings"
 	"sync"
+	"time"
 
+	"github.com/kiali/kiali/log"
 	"github.com/kiali/kiali/models"
 	"github.com/kiali/kiali/prometheus"
 )
 
 // MetricsService deals with fetching metrics from prometheus
 type MetricsService struct {
-	prom prometheus.ClientInterface
+	prom  prometheus.ClientInterface
+	scope *namespaceScope
 }
 
 // NewMetricsService initializes this business service
@@ -20,10 +25,124 @@ func NewMetricsService(prom prometheus.ClientInterface) *MetricsService {
 	return &MetricsService{prom: prom}
 }
 
+// NewNamespaceScopedMetricsService initializes this business service, restricting its queries to the namespaces
+// accessible through the NamespaceService of the user
+func NewNamespaceScopedMetricsService(prom prometheus.ClientInterface, namespaces *NamespaceService) *MetricsService {
+	scope := newNamespaceScope(namespaces, telemetryLabel("destination_workload_namespace"))
+	return &MetricsService{prom: namespaceScopedClient{ClientInterface: prom, scope: scope}, scope: scope}
+}
+
 func (in *MetricsService) GetMetrics(q models.IstioMetricsQuery, scaler func(n string) float64) (models.MetricsMap, error) {
+	metrics, _, err := in.GetDownsampledMetrics(q, scaler)
+	return metrics, err
+}
+
+// GetDownsampledMetrics fetches the metrics like GetMetrics, and returns their effective step: the step of the long
+// ranges is increased to fit the max points of the query limits, and their series are cached.
+func (in *MetricsService) GetDownsampledMetrics(q models.IstioMetricsQuery, scaler func(n string) float64) (models.MetricsMap, time.Duration, error) {
+	if err := limitMetricsQuery(&q.RangeQuery); err != nil {
+		return nil, 0, err
+	}
+	downsampled := downsampleMetricsQuery(&q.RangeQuery)
 	lb := createMetricsLabelsBuilder(&q)
-	grouping := strings.Join(q.ByLabels, ",")
-	return in.fetchAllMetrics(q, lb, grouping, scaler)
+	grouping := telemetryGrouping(strings.Join(q.ByLabels, ","))
+	metrics, err := in.fetchAllMetrics(q, lb, grouping, scaler, downsampled)
+	if err != nil {
+		return nil, 0, err
+	}
+	return metrics, q.Step, nil
+}
+
+// GetMetricsComparison fetches the metrics of the query window and of the same window shifted back by offset
+// (e.g. 7d), along with the percentage deltas between them
+func (in *MetricsService) GetMetricsComparison(q models.IstioMetricsQuery, offset string, scaler func(n string) float64) (*models.MetricsComparison, error) {
+	if err := models.ValidateOffset(offset); err != nil {
+		return nil, err
+	}
+
+	shifted := q
+	shifted.Offset = offset
+
+	var current, previous models.MetricsMap
+	var step time.Duration
+	var currentErr, previousErr error
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		current, step, currentErr = in.GetDownsampledMetrics(q, scaler)
+	}()
+	go func() {
+		defer wg.Done()
+		previous, previousErr = in.GetMetrics(shifted, scaler)
+	}()
+	wg.Wait()
+
+	if currentErr != nil {
+		return nil, currentErr
+	}
+	if previousErr != nil {
+		return nil, previousErr
+	}
+
+	return &models.MetricsComparison{
+		Offset:   offset,
+		Step:     int64(step / time.Second),
+		Current:  current,
+		Previous: previous,
+		Deltas:   computeMetricsDeltas(current, previous),
+	}, nil
+}
+
+// computeMetricsDeltas matches every current series with the previous one having the same labels and stat.
+// Series without historical data still get a delta, with no previous value nor percentage.
+func computeMetricsDeltas(current, previous models.MetricsMap) map[string][]models.MetricDelta {
+	deltas := make(map[string][]models.MetricDelta, len(current))
+	for name, series := range current {
+		for _, metric := range series {
+			currentAvg, ok := averageValue(metric.Datapoints)
+			if !ok {
+				continue
+			}
+			delta := models.MetricDelta{Labels: metric.Labels, Stat: metric.Stat, Current: currentAvg}
+			if prev := findSeries(previous[name], metric); prev != nil {
+				if previousAvg, ok := averageValue(prev.Datapoints); ok {
+					delta.Previous = &previousAvg
+					if previousAvg != 0 {
+						percent := (currentAvg - previousAvg) / previousAvg * 100
+						delta.Percent = &percent
+					}
+				}
+			}
+			deltas[name] = append(deltas[name], delta)
+		}
+	}
+	return deltas
+}
+
+func findSeries(series []models.Metric, match models.Metric) *models.Metric {
+	for i := range series {
+		if series[i].Stat == match.Stat && reflect.DeepEqual(series[i].Labels, match.Labels) {
+			return &series[i]
+		}
+	}
+	return nil
+}
+
+// averageValue ignores NaN datapoints, returns false when no value is left
+func averageValue(datapoints []models.Datapoint) (float64, bool) {
+	sum, count := 0.0, 0
+	for _, dp := range datapoints {
+		if math.IsNaN(dp.Value) {
+			continue
+		}
+		sum += dp.Value
+		count++
+	}
+	if count == 0 {
+		return 0, false
+	}
+	return sum / float64(count), true
 }
 
 func createMetricsLabelsBuilder(q *models.IstioMetricsQuery) *MetricsLabelsBuilder {
@@ -55,26 +174,30 @@ func createMetricsLabelsBuilder(q *models.IstioMetricsQuery) *MetricsLabelsBuild
 	return lb
 }
 
-func (in *MetricsService) fetchAllMetrics(q models.IstioMetricsQuery, lb *MetricsLabelsBuilder, grouping string, scaler func(n string) float64) (models.MetricsMap, error) {
+func (in *MetricsService) fetchAllMetrics(q models.IstioMetricsQuery, lb *MetricsLabelsBuilder, grouping string, scaler func(n string) float64, downsampled bool) (models.MetricsMap, error) {
 	labels := lb.Build()
 	labelsError := lb.BuildForErrors()
 
 	var wg sync.WaitGroup
 	fetchRate := func(p8sFamilyName string, metric *prometheus.Metric, lbl []string) {
 		defer wg.Done()
-		m := in.prom.FetchRateRange(p8sFamilyName, lbl, grouping, &q.RangeQuery)
+		m := in.fetchRateRange(p8sFamilyName, lbl, grouping, &q.RangeQuery, downsampled)
 		*metric = m
 	}
 
-	fetchHisto := func(p8sFamilyName string, histo *prometheus.Histogram) {
+	fetchHisto := func(p8sFamilyName string, histo *prometheus.Histogram, exemplars *[]prometheus.ExemplarQueryResult) {
 		defer wg.Done()
-		h := in.prom.FetchHistogramRange(p8sFamilyName, labels, grouping, &q.RangeQuery)
+		h := in.fetchHistogramRange(p8sFamilyName, labels, grouping, &q.RangeQuery, downsampled)
 		*histo = h
+		if q.Exemplars {
+			*exemplars = in.fetchExemplars(p8sFamilyName, labels, &q.RangeQuery)
+		}
 	}
 
 	type resultHolder struct {
 		metric     prometheus.Metric
 		histo      prometheus.Histogram
+		exemplars  []prometheus.ExemplarQueryResult
 		definition istioMetric
 	}
 	maxResults := len(istioMetrics)
@@ -99,10 +222,10 @@ func (in *MetricsService) fetchAllMetrics(q models.IstioMetricsQuery, lb *Metric
 			result := resultHolder{definition: istioMetric}
 			results = append(results, &result)
 			if istioMetric.isHisto {
-				go fetchHisto(istioMetric.istioName, &result.histo)
+				go fetchHisto(istioMetric.promName(), &result.histo, &result.exemplars)
 			} else {
 				labelsToUse := istioMetric.labelsToUse(labels, labelsError)
-				go fetchRate(istioMetric.istioName, &result.metric, labelsToUse)
+				go fetchRate(istioMetric.promName(), &result.metric, labelsToUse)
 			}
 		}
 	}
@@ -126,18 +249,35 @@ func (in *MetricsService) fetchAllMetrics(q models.IstioMetricsQuery, lb *Metric
 				if err != nil {
 					return nil, err
 				}
+				models.AttachExemplars(converted, result.exemplars, conversionParams.Scale)
 			} else {
 				converted, err = models.ConvertMetric(result.definition.kialiName, result.metric, conversionParams)
 				if err != nil {
 					return nil, err
 				}
 			}
+			standardLabels(converted)
 			metrics[result.definition.kialiName] = append(metrics[result.definition.kialiName], converted...)
 		}
 	}
 	return metrics, nil
 }
 
+// fetchExemplars returns the exemplars of the buckets of the histogram. The metrics don't depend on them: they are
+// skipped, with no error, when the backend doesn't support them or fails to return them.
+func (in *MetricsService) fetchExemplars(p8sFamilyName, labels string, q *prometheus.RangeQuery) []prometheus.ExemplarQueryResult {
+	exemplars, err := in.prom.FetchExemplars(p8sFamilyName+"_bucket", labels, q.Start, q.End)
+	if err != nil {
+		if errors.Is(err, prometheus.ErrExemplarsNotSupported) {
+			log.Debugf("Exemplars of %s skipped: %v", p8sFamilyName, err)
+		} else {
+			log.Warningf("Error fetching the exemplars of %s: %v", p8sFamilyName, err)
+		}
+		return nil
+	}
+	return exemplars
+}
+
 // GetStats computes metrics stats, currently response times, for a set of queries
 func (in *MetricsService) GetStats(queries []models.MetricsStatsQuery) (map[string]models.MetricsStats, error) {
 	type statsChanResult struct {
@@ -176,7 +316,7 @@ func (in *MetricsService) GetStats(queries []models.MetricsStatsQuery) (map[stri
 func (in *MetricsService) getSingleQueryStats(q *models.MetricsStatsQuery) (*models.MetricsStats, error) {
 	lb := createStatsMetricsLabelsBuilder(q)
 	labels := lb.Build()
-	stats, err := in.prom.FetchHistogramValues("istio_request_duration_milliseconds", labels, "", q.Interval, q.Avg, q.Quantiles, q.QueryTime)
+	stats, err := in.prom.FetchHistogramValues(telemetryMetric("istio_request_duration_milliseconds"), labels, "", q.Interval, q.Avg, q.Quantiles, q.QueryTime)
 	if err != nil {
 		return nil, err
 	}
@@ -1,8 +1,16 @@
 package business
 
+import (
+	"strings"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/models"
+)
+
 type istioMetric struct {
 	kialiName      string
 	istioName      string
+	suffix         string // e.g. "_sum" to read the sum of a histogram
 	isHisto        bool
 	useErrorLabels bool
 }
@@ -26,12 +34,14 @@ var istioMetrics = []istioMetric{
 	},
 	{
 		kialiName: "request_throughput",
-		istioName: "istio_request_bytes_sum",
+		istioName: "istio_request_bytes",
+		suffix:    "_sum",
 		isHisto:   false,
 	},
 	{
 		kialiName: "response_throughput",
-		istioName: "istio_response_bytes_sum",
+		istioName: "istio_response_bytes",
+		suffix:    "_sum",
 		isHisto:   false,
 	},
 	{
@@ -56,9 +66,54 @@ var istioMetrics = []istioMetric{
 	},
 }
 
+// promName is the name of the metric in the telemetry of the mesh
+func (in *istioMetric) promName() string {
+	return telemetryMetric(in.istioName) + in.suffix
+}
+
 func (in *istioMetric) labelsToUse(labels string, labelsError []string) []string {
 	if in.useErrorLabels {
 		return labelsError
 	}
 	return []string{labels}
 }
+
+// latencyQuantiles are the quantiles of the request durations of the port, subset, route and canary metrics
+var latencyQuantiles = []string{"0.5", "0.95", "0.99"}
+
+// telemetryMetric returns the name of an Istio standard metric in the telemetry of the mesh
+func telemetryMetric(name string) string {
+	return config.Get().ExternalServices.Istio.TelemetryMapping.Metric(name)
+}
+
+// telemetryLabel returns the key of an Istio standard label in the telemetry of the mesh
+func telemetryLabel(name string) string {
+	return config.Get().ExternalServices.Istio.TelemetryMapping.Label(name)
+}
+
+// telemetryGrouping maps the Istio standard labels of a comma separated grouping to the labels of the telemetry
+func telemetryGrouping(grouping string) string {
+	if grouping == "" {
+		return grouping
+	}
+	labels := strings.Split(grouping, ",")
+	for i, label := range labels {
+		labels[i] = telemetryLabel(strings.TrimSpace(label))
+	}
+	return strings.Join(labels, ",")
+}
+
+// standardLabels renames the labels of the telemetry of the mesh to their Istio standard names
+func standardLabels(metrics []models.Metric) {
+	mapping := config.Get().ExternalServices.Istio.TelemetryMapping
+	if len(mapping.Labels) == 0 {
+		return
+	}
+	for i := range metrics {
+		labels := make(map[string]string, len(metrics[i].Labels))
+		for k, v := range metrics[i].Labels {
+			labels[mapping.StandardLabel(k)] = v
+		}
+		metrics[i].Labels = labels
+	}
+}
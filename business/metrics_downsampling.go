@@ -0,0 +1,185 @@
+package business
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/prometheus/common/model"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/log"
+	"github.com/kiali/kiali/prometheus"
+	"github.com/kiali/kiali/util"
+)
+
+// The most series kept by the cache of the downsampled series
+const maxDownsampledSeries = 500
+
+// The steps of the downsampled queries. They are round durations, so that the successive queries of a dashboard get
+// datapoints at the same timestamps, and their series can be cached. Beyond the last one, the step is a number of days.
+var downsamplingSteps = []time.Duration{
+	15 * time.Second,
+	30 * time.Second,
+	time.Minute,
+	2 * time.Minute,
+	5 * time.Minute,
+	10 * time.Minute,
+	15 * time.Minute,
+	30 * time.Minute,
+	time.Hour,
+	2 * time.Hour,
+	3 * time.Hour,
+	6 * time.Hour,
+	12 * time.Hour,
+	24 * time.Hour,
+}
+
+// downsampledSeries is a cached result of a downsampled query
+type downsampledSeries struct {
+	metric  prometheus.Metric
+	histo   prometheus.Histogram
+	expires time.Time
+}
+
+var (
+	downsampledSeriesLock  sync.Mutex
+	downsampledSeriesCache = map[string]downsampledSeries{}
+)
+
+// downsampleMetricsQuery increases the step of a metrics query whose series would have more datapoints than the max
+// points of the query limits, to the finest downsampling step fitting. The time range of a downsampled query is
+// aligned on its step, the end being rounded up so that the latest datapoints are kept. It returns whether the query
+// was downsampled.
+func downsampleMetricsQuery(q *prometheus.RangeQuery) bool {
+	maxPoints := config.Get().QueryLimits.MaxPoints
+	if maxPoints <= 1 || q.Step <= 0 || !q.End.After(q.Start) {
+		return false
+	}
+	step := downsampledStep(q.Start, q.End, q.Step, maxPoints)
+	if step == q.Step {
+		return false
+	}
+	log.Debugf("Downsampling a metrics query of range [%s] from step [%s] to step [%s]", model.Duration(q.End.Sub(q.Start)), model.Duration(q.Step), model.Duration(step))
+	q.Step = step
+	q.Start = q.Start.Truncate(step)
+	q.End = alignEnd(q.End, step)
+	return true
+}
+
+// alignEnd rounds the end of a range up to the step
+func alignEnd(end time.Time, step time.Duration) time.Time {
+	aligned := end.Truncate(step)
+	if aligned.Before(end) {
+		aligned = aligned.Add(step)
+	}
+	return aligned
+}
+
+// downsampledStep returns the step of a query of the range, the requested step when the series fit in maxPoints
+func downsampledStep(start, end time.Time, step time.Duration, maxPoints int) time.Duration {
+	if datapoints(start, end, step) <= maxPoints {
+		return step
+	}
+	for _, candidate := range downsamplingSteps {
+		if candidate > step && datapoints(start.Truncate(candidate), alignEnd(end, candidate), candidate) <= maxPoints {
+			return candidate
+		}
+	}
+	day := downsamplingSteps[len(downsamplingSteps)-1]
+	for days := 2; ; days++ {
+		candidate := time.Duration(days) * day
+		if candidate > step && datapoints(start.Truncate(candidate), alignEnd(end, candidate), candidate) <= maxPoints {
+			return candidate
+		}
+	}
+}
+
+// datapoints returns the number of datapoints of a series of the range
+func datapoints(start, end time.Time, step time.Duration) int {
+	return int(end.Sub(start)/step) + 1
+}
+
+// fetchRateRange fetches the series of a rate, from the cache when the query was downsampled
+func (in *MetricsService) fetchRateRange(name string, labels []string, grouping string, q *prometheus.RangeQuery, downsampled bool) prometheus.Metric {
+	if !downsampled {
+		return in.prom.FetchRateRange(name, labels, grouping, q)
+	}
+	// The cached series are shared by the users, only the scoped queries can be looked up
+	labels, err := in.scopeQueryLabels(labels)
+	if err != nil {
+		return prometheus.Metric{Err: err}
+	}
+	key := downsampledSeriesKey("rate", name, labels, grouping, q)
+	if series, found := getDownsampledSeries(key); found {
+		return series.metric
+	}
+	metric := in.prom.FetchRateRange(name, labels, grouping, q)
+	if metric.Err == nil {
+		setDownsampledSeries(key, downsampledSeries{metric: metric}, q.Step)
+	}
+	return metric
+}
+
+// fetchHistogramRange fetches the series of a histogram, from the cache when the query was downsampled
+func (in *MetricsService) fetchHistogramRange(name string, labels string, grouping string, q *prometheus.RangeQuery, downsampled bool) prometheus.Histogram {
+	if !downsampled {
+		return in.prom.FetchHistogramRange(name, labels, grouping, q)
+	}
+	scoped, err := in.scopeQueryLabels([]string{labels})
+	if err != nil {
+		return prometheus.Histogram{"avg": prometheus.Metric{Err: err}}
+	}
+	labels = scoped[0]
+	key := downsampledSeriesKey("histogram", name, []string{labels}, grouping, q)
+	if series, found := getDownsampledSeries(key); found {
+		return series.histo
+	}
+	histo := in.prom.FetchHistogramRange(name, labels, grouping, q)
+	for _, metric := range histo {
+		if metric.Err != nil {
+			return histo
+		}
+	}
+	setDownsampledSeries(key, downsampledSeries{histo: histo}, q.Step)
+	return histo
+}
+
+func downsampledSeriesKey(kind, name string, labels []string, grouping string, q *prometheus.RangeQuery) string {
+	return fmt.Sprintf("%s:%s:%v:%s:%d:%d:%d:%s:%s:%v:%t:%s", kind, name, labels, grouping, q.Start.Unix(), q.End.Unix(), q.Step,
+		q.RateInterval, q.RateFunc, q.Quantiles, q.Avg, q.Offset)
+}
+
+func getDownsampledSeries(key string) (downsampledSeries, bool) {
+	downsampledSeriesLock.Lock()
+	defer downsampledSeriesLock.Unlock()
+	series, found := downsampledSeriesCache[key]
+	if !found || !util.Clock.Now().Before(series.expires) {
+		return downsampledSeries{}, false
+	}
+	return series, true
+}
+
+// setDownsampledSeries caches the series for a step: the next queries of a dashboard, aligned on the next step,
+// don't use them anymore. The expired series are evicted, then the series expiring first when the cache is full.
+func setDownsampledSeries(key string, series downsampledSeries, step time.Duration) {
+	downsampledSeriesLock.Lock()
+	defer downsampledSeriesLock.Unlock()
+	now := util.Clock.Now()
+	for k, s := range downsampledSeriesCache {
+		if !now.Before(s.expires) {
+			delete(downsampledSeriesCache, k)
+		}
+	}
+	for len(downsampledSeriesCache) >= maxDownsampledSeries {
+		first := ""
+		for k, s := range downsampledSeriesCache {
+			if first == "" || s.expires.Before(downsampledSeriesCache[first].expires) {
+				first = k
+			}
+		}
+		delete(downsampledSeriesCache, first)
+	}
+	series.expires = now.Add(step)
+	downsampledSeriesCache[key] = series
+}
@@ -0,0 +1,144 @@
+package business
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/prometheus"
+	"github.com/kiali/kiali/prometheus/prometheustest"
+	"github.com/kiali/kiali/util"
+)
+
+var downsamplingEnd = time.Date(2022, 03, 10, 14, 27, 42, 0, time.UTC)
+
+func TestDownsampledStep(t *testing.T) {
+	assert := assert.New(t)
+
+	cases := []struct {
+		queryRange time.Duration
+		step       time.Duration
+		maxPoints  int
+		expected   time.Duration
+	}{
+		// The series fit, the step is kept
+		{30 * time.Minute, 15 * time.Second, 1000, 15 * time.Second},
+		{2 * time.Hour, 10 * time.Second, 1000, 10 * time.Second},
+		// The step is increased to the next round step fitting
+		{3 * time.Hour, 10 * time.Second, 1000, 15 * time.Second},
+		{6 * time.Hour, 15 * time.Second, 1000, 30 * time.Second},
+		{12 * time.Hour, 15 * time.Second, 1000, time.Minute},
+		{24 * time.Hour, 15 * time.Second, 1000, 2 * time.Minute},
+		{7 * 24 * time.Hour, 15 * time.Second, 1000, 15 * time.Minute},
+		{30 * 24 * time.Hour, 15 * time.Second, 1000, time.Hour},
+		// A coarser requested step is kept
+		{7 * 24 * time.Hour, time.Hour, 1000, time.Hour},
+		// The budget is in datapoints
+		{24 * time.Hour, 15 * time.Second, 100, 15 * time.Minute},
+		{30 * 24 * time.Hour, 15 * time.Second, 100, 12 * time.Hour},
+		// Beyond the coarsest round step, whole days
+		{90 * 24 * time.Hour, 15 * time.Second, 10, 11 * 24 * time.Hour},
+	}
+	for _, c := range cases {
+		start := downsamplingEnd.Add(-c.queryRange)
+		step := downsampledStep(start, downsamplingEnd, c.step, c.maxPoints)
+		assert.Equal(c.expected, step, "range %s, step %s, max points %d", model.Duration(c.queryRange), model.Duration(c.step), c.maxPoints)
+		if step != c.step {
+			assert.LessOrEqual(datapoints(start.Truncate(step), alignEnd(downsamplingEnd, step), step), c.maxPoints)
+		}
+	}
+}
+
+func TestDownsampleMetricsQuery(t *testing.T) {
+	assert := assert.New(t)
+	conf := config.NewConfig()
+	config.Set(conf)
+	defer config.Set(config.NewConfig())
+
+	q := prometheus.RangeQuery{}
+	q.End = downsamplingEnd
+	q.Start = q.End.Add(-30 * time.Minute)
+	q.Step = 15 * time.Second
+	assert.False(downsampleMetricsQuery(&q))
+	assert.Equal(downsamplingEnd, q.End)
+
+	// The range of a downsampled query is aligned on its step, without dropping the latest datapoints
+	q.Start = q.End.Add(-7 * 24 * time.Hour)
+	assert.True(downsampleMetricsQuery(&q))
+	assert.Equal(15*time.Minute, q.Step)
+	assert.Equal(time.Date(2022, 03, 3, 14, 15, 0, 0, time.UTC), q.Start)
+	assert.Equal(time.Date(2022, 03, 10, 14, 30, 0, 0, time.UTC), q.End)
+
+	// An aligned end is kept
+	q.End = time.Date(2022, 03, 10, 14, 30, 0, 0, time.UTC)
+	q.Start = q.End.Add(-7 * 24 * time.Hour)
+	q.Step = 15 * time.Second
+	assert.True(downsampleMetricsQuery(&q))
+	assert.Equal(time.Date(2022, 03, 10, 14, 30, 0, 0, time.UTC), q.End)
+
+	// Without budget, no downsampling
+	conf.QueryLimits.MaxPoints = 0
+	config.Set(conf)
+	q.Start = q.End.Add(-7 * 24 * time.Hour)
+	q.Step = 15 * time.Second
+	assert.False(downsampleMetricsQuery(&q))
+	assert.Equal(15*time.Second, q.Step)
+}
+
+func TestGetDownsampledMetricsCachesSeries(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+	util.Clock = util.ClockMock{Time: downsamplingEnd}
+	defer func() {
+		util.Clock = util.RealClock{}
+		downsampledSeriesCache = map[string]downsampledSeries{}
+	}()
+
+	prom := new(prometheustest.PromClientMock)
+	prom.On("FetchRateRange", "istio_requests_total", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("*prometheus.RangeQuery")).Return(prometheus.Metric{Matrix: model.Matrix{
+		&model.SampleStream{
+			Metric: model.Metric{"destination_service_name": "productpage"},
+			Values: []model.SamplePair{{Timestamp: 0, Value: 5}},
+		},
+	}})
+
+	q := models.IstioMetricsQuery{Namespace: "bookinfo", Service: "productpage"}
+	q.FillDefaults()
+	q.Filters = []string{"request_count"}
+	q.End = downsamplingEnd
+	q.Start = q.End.Add(-7 * 24 * time.Hour)
+
+	metrics, step, err := NewMetricsService(prom).GetDownsampledMetrics(q, nil)
+	assert.NoError(err)
+	assert.Equal(15*time.Minute, step)
+	assert.Len(metrics["request_count"], 1)
+	prom.AssertNumberOfCalls(t, "FetchRateRange", 1)
+
+	// A later query within the same step gets the cached series
+	q.End = downsamplingEnd.Add(time.Minute)
+	q.Start = q.End.Add(-7 * 24 * time.Hour)
+	metrics, _, err = NewMetricsService(prom).GetDownsampledMetrics(q, nil)
+	assert.NoError(err)
+	assert.Len(metrics["request_count"], 1)
+	prom.AssertNumberOfCalls(t, "FetchRateRange", 1)
+
+	// Once the step has elapsed, Prometheus is queried again
+	util.Clock = util.ClockMock{Time: downsamplingEnd.Add(15 * time.Minute)}
+	_, _, err = NewMetricsService(prom).GetDownsampledMetrics(q, nil)
+	assert.NoError(err)
+	prom.AssertNumberOfCalls(t, "FetchRateRange", 2)
+
+	// The short ranges are not cached
+	q.Start = q.End.Add(-30 * time.Minute)
+	_, step, err = NewMetricsService(prom).GetDownsampledMetrics(q, nil)
+	assert.NoError(err)
+	assert.Equal(15*time.Second, step)
+	_, _, err = NewMetricsService(prom).GetDownsampledMetrics(q, nil)
+	assert.NoError(err)
+	prom.AssertNumberOfCalls(t, "FetchRateRange", 4)
+}
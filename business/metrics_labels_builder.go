@@ -3,6 +3,8 @@ package business
 import (
 	"fmt"
 	"strings"
+
+	"github.com/kiali/kiali/config"
 )
 
 const (
@@ -17,6 +19,7 @@ type MetricsLabelsBuilder struct {
 	peerSide string
 	protocol string
 	labelsKV []string
+	mapping  config.TelemetryMapping
 }
 
 func NewMetricsLabelsBuilder(direction string) *MetricsLabelsBuilder {
@@ -29,17 +32,18 @@ func NewMetricsLabelsBuilder(direction string) *MetricsLabelsBuilder {
 	return &MetricsLabelsBuilder{
 		side:     side,
 		peerSide: peerSide,
+		mapping:  config.Get().ExternalServices.Istio.TelemetryMapping,
 	}
 }
 
+// Add adds a label, the key being the Istio standard name of the label
 func (lb *MetricsLabelsBuilder) Add(key, value string) *MetricsLabelsBuilder {
-	lb.labelsKV = append(lb.labelsKV, fmt.Sprintf(`%s="%s"`, key, value))
+	lb.labelsKV = append(lb.labelsKV, fmt.Sprintf(`%s="%s"`, lb.mapping.Label(key), value))
 	return lb
 }
 
 func (lb *MetricsLabelsBuilder) addSided(partialKey, value, side string) *MetricsLabelsBuilder {
-	lb.labelsKV = append(lb.labelsKV, fmt.Sprintf(`%s_%s="%s"`, side, partialKey, value))
-	return lb
+	return lb.Add(side+"_"+partialKey, value)
 }
 
 func (lb *MetricsLabelsBuilder) Reporter(name string) *MetricsLabelsBuilder {
@@ -71,6 +75,15 @@ func (lb *MetricsLabelsBuilder) Workload(name, namespace string) *MetricsLabelsB
 	return lb.addSided("workload", name, lb.side)
 }
 
+// Workloads matches any of the workloads of the namespace
+func (lb *MetricsLabelsBuilder) Workloads(names []string, namespace string) *MetricsLabelsBuilder {
+	if namespace != "" {
+		lb.addSided("workload_namespace", namespace, lb.side)
+	}
+	lb.labelsKV = append(lb.labelsKV, fmt.Sprintf(`%s=~"%s"`, lb.mapping.Label(lb.side+"_workload"), strings.Join(names, "|")))
+	return lb
+}
+
 func (lb *MetricsLabelsBuilder) App(name, namespace string) *MetricsLabelsBuilder {
 	if namespace != "" {
 		// workload_namespace works for app as well
@@ -126,7 +139,8 @@ func (lb *MetricsLabelsBuilder) BuildForErrors() []string {
 
 	// both http and grpc requests can suffer from no response (response_code=0) or an http error
 	// (response_code=4xx,5xx), and so we always perform a query against response_code:
-	httpLabels := append(lb.labelsKV, fmt.Sprintf(`response_code=~"%s"`, regexResponseCodeErr))
+	responseCode := lb.mapping.Label("response_code")
+	httpLabels := append(lb.labelsKV, fmt.Sprintf(`%s=~"%s"`, responseCode, regexResponseCodeErr))
 	errors = append(errors, "{"+strings.Join(httpLabels, ",")+"}")
 
 	// if necessary also look for grpc errors. note that the grpc test intentionally avoids
@@ -135,7 +149,7 @@ func (lb *MetricsLabelsBuilder) BuildForErrors() []string {
 	// non-existent label match everything, but positive tests match nothing. So, we stay positive.
 	// furthermore, make sure we only count grpc errors with successful http status.
 	if lb.protocol != "http" {
-		grpcLabels := append(lb.labelsKV, fmt.Sprintf(`grpc_response_status=~"%s",response_code!~"%s"`, regexGrpcResponseStatusErr, regexResponseCodeErr))
+		grpcLabels := append(lb.labelsKV, fmt.Sprintf(`%s=~"%s",%s!~"%s"`, lb.mapping.Label("grpc_response_status"), regexGrpcResponseStatusErr, responseCode, regexResponseCodeErr))
 		errors = append(errors, ("{" + strings.Join(grpcLabels, ",") + "}"))
 	}
 	return errors
@@ -0,0 +1,344 @@
+package business
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"k8s.io/apimachinery/pkg/api/errors"
+
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/prometheus"
+)
+
+// namespaceAccess tells the namespaces accessible to a user, as the NamespaceService does
+type namespaceAccess interface {
+	GetNamespace(namespace string) (*models.Namespace, error)
+	GetNamespaces() ([]models.Namespace, error)
+}
+
+// namespaceScope constrains the label matchers of the PromQL queries to the namespaces accessible to a user, so
+// that crafted requests can't read the metrics of the namespaces of other tenants:
+// - The queries matching a namespace that isn't accessible are rejected.
+// - The queries not matching any namespace are restricted to the accessible namespaces.
+type namespaceScope struct {
+	access          namespaceAccess
+	namespaceLabels map[string]bool
+	defaultLabel    string
+
+	lock       sync.Mutex
+	checked    map[string]error
+	accessible []string
+}
+
+func newNamespaceScope(access namespaceAccess, defaultLabel string) *namespaceScope {
+	namespaceLabels := map[string]bool{defaultNamespaceLabel: true, defaultLabel: true}
+	for _, label := range []string{"source_workload_namespace", "destination_workload_namespace", "destination_service_namespace"} {
+		namespaceLabels[label] = true
+		namespaceLabels[telemetryLabel(label)] = true
+	}
+	return &namespaceScope{
+		access:          access,
+		namespaceLabels: namespaceLabels,
+		defaultLabel:    defaultLabel,
+		checked:         map[string]error{},
+	}
+}
+
+// labelMatcher is a label matcher of a PromQL selector, its value kept escaped
+type labelMatcher struct {
+	name  string
+	op    string
+	value string
+}
+
+var labelNameRegexp = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*`)
+
+// parseLabelMatchers parses the label matchers of a PromQL selector, {name="value",...}. Anything else is
+// rejected, as it could alter the query.
+func parseLabelMatchers(labels string) ([]labelMatcher, error) {
+	labels = strings.TrimSpace(labels)
+	if labels == "" {
+		return nil, nil
+	}
+	if !strings.HasPrefix(labels, "{") || !strings.HasSuffix(labels, "}") {
+		return nil, fmt.Errorf("invalid label matchers [%s]", labels)
+	}
+	rest := strings.TrimSpace(labels[1 : len(labels)-1])
+	matchers := []labelMatcher{}
+	for rest != "" {
+		name := labelNameRegexp.FindString(rest)
+		if name == "" {
+			return nil, fmt.Errorf("invalid label matchers [%s]", labels)
+		}
+		rest = strings.TrimSpace(rest[len(name):])
+
+		op := ""
+		for _, candidate := range []string{"=~", "!~", "!=", "="} {
+			if strings.HasPrefix(rest, candidate) {
+				op = candidate
+				break
+			}
+		}
+		if op == "" {
+			return nil, fmt.Errorf("invalid label matchers [%s]", labels)
+		}
+		rest = strings.TrimSpace(rest[len(op):])
+
+		// The quoted value, up to the first unescaped quote
+		end := -1
+		if strings.HasPrefix(rest, `"`) {
+			for i := 1; i < len(rest); i++ {
+				if rest[i] == '\\' {
+					i++
+				} else if rest[i] == '"' {
+					end = i
+					break
+				}
+			}
+		}
+		if end < 0 {
+			return nil, fmt.Errorf("invalid label matchers [%s]", labels)
+		}
+		matchers = append(matchers, labelMatcher{name: name, op: op, value: rest[1:end]})
+		rest = strings.TrimSpace(rest[end+1:])
+
+		if rest != "" {
+			if !strings.HasPrefix(rest, ",") {
+				return nil, fmt.Errorf("invalid label matchers [%s]", labels)
+			}
+			rest = strings.TrimSpace(rest[1:])
+		}
+	}
+	return matchers, nil
+}
+
+func formatLabelMatchers(matchers []labelMatcher) string {
+	formatted := make([]string, 0, len(matchers))
+	for _, m := range matchers {
+		formatted = append(formatted, fmt.Sprintf(`%s%s"%s"`, m.name, m.op, m.value))
+	}
+	return "{" + strings.Join(formatted, ",") + "}"
+}
+
+// scopeLabels returns the label matchers restricted to the accessible namespaces, or an error when they match
+// a namespace that isn't accessible
+func (s *namespaceScope) scopeLabels(labels string) (string, error) {
+	matchers, err := parseLabelMatchers(labels)
+	if err != nil {
+		return "", errors.NewBadRequest(err.Error())
+	}
+
+	scoped := false
+	scopeLabel := s.defaultLabel
+	for _, m := range matchers {
+		if m.name == "reporter" && m.op == "=" && m.value == "source" {
+			scopeLabel = telemetryLabel("source_workload_namespace")
+		}
+		if !s.namespaceLabels[m.name] {
+			continue
+		}
+		switch m.op {
+		case "=":
+			if err := s.checkNamespace(m.value); err != nil {
+				return "", err
+			}
+			scoped = true
+		case "=~":
+			// Only the alternatives of namespace names can be verified
+			for _, namespace := range strings.Split(m.value, "|") {
+				if namespace != regexp.QuoteMeta(namespace) {
+					return "", &AccessibleNamespaceError{msg: fmt.Sprintf("Namespace matcher [%s] is not allowed, only namespace names are", m.value)}
+				}
+				if err := s.checkNamespace(namespace); err != nil {
+					return "", err
+				}
+			}
+			scoped = true
+		}
+	}
+	if scoped {
+		return labels, nil
+	}
+
+	accessible, err := s.accessibleNamespaces()
+	if err != nil {
+		return "", err
+	}
+	matchers = append(matchers, labelMatcher{name: scopeLabel, op: "=~", value: strings.Join(accessible, "|")})
+	return formatLabelMatchers(matchers), nil
+}
+
+func (s *namespaceScope) checkNamespace(namespace string) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if err, found := s.checked[namespace]; found {
+		return err
+	}
+	var err error
+	if _, accessErr := s.access.GetNamespace(namespace); accessErr != nil {
+		err = &AccessibleNamespaceError{msg: fmt.Sprintf("Namespace [%s] is not accessible: %v", namespace, accessErr)}
+	}
+	s.checked[namespace] = err
+	return err
+}
+
+func (s *namespaceScope) accessibleNamespaces() ([]string, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.accessible != nil {
+		return s.accessible, nil
+	}
+	namespaces, err := s.access.GetNamespaces()
+	if err != nil {
+		return nil, err
+	}
+	// Matching an empty value would match the series without namespace
+	if len(namespaces) == 0 {
+		return nil, &AccessibleNamespaceError{msg: "No namespace is accessible"}
+	}
+	accessible := make([]string, 0, len(namespaces))
+	for _, ns := range namespaces {
+		accessible = append(accessible, regexp.QuoteMeta(ns.Name))
+	}
+	sort.Strings(accessible)
+	s.accessible = accessible
+	return accessible, nil
+}
+
+// namespaceScopedClient is a Prometheus client whose queries are constrained to the namespaces of a namespaceScope
+type namespaceScopedClient struct {
+	prometheus.ClientInterface
+	scope *namespaceScope
+}
+
+func (c namespaceScopedClient) GetAllRequestRates(namespace, ratesInterval string, queryTime time.Time) (model.Vector, error) {
+	if err := c.scope.checkNamespace(namespace); err != nil {
+		return nil, err
+	}
+	return c.ClientInterface.GetAllRequestRates(namespace, ratesInterval, queryTime)
+}
+
+func (c namespaceScopedClient) GetAppRequestRates(namespace, app, ratesInterval string, queryTime time.Time) (model.Vector, model.Vector, error) {
+	if err := c.scope.checkNamespace(namespace); err != nil {
+		return nil, nil, err
+	}
+	return c.ClientInterface.GetAppRequestRates(namespace, app, ratesInterval, queryTime)
+}
+
+func (c namespaceScopedClient) GetNamespaceServicesRequestRates(namespace, ratesInterval string, queryTime time.Time) (model.Vector, error) {
+	if err := c.scope.checkNamespace(namespace); err != nil {
+		return nil, err
+	}
+	return c.ClientInterface.GetNamespaceServicesRequestRates(namespace, ratesInterval, queryTime)
+}
+
+func (c namespaceScopedClient) GetServiceRequestRates(namespace, service, ratesInterval string, queryTime time.Time) (model.Vector, error) {
+	if err := c.scope.checkNamespace(namespace); err != nil {
+		return nil, err
+	}
+	return c.ClientInterface.GetServiceRequestRates(namespace, service, ratesInterval, queryTime)
+}
+
+func (c namespaceScopedClient) GetWorkloadRequestRates(namespace, workload, ratesInterval string, queryTime time.Time) (model.Vector, model.Vector, error) {
+	if err := c.scope.checkNamespace(namespace); err != nil {
+		return nil, nil, err
+	}
+	return c.ClientInterface.GetWorkloadRequestRates(namespace, workload, ratesInterval, queryTime)
+}
+
+func (c namespaceScopedClient) GetWorkloadsTrafficHistory(namespace string, history, step time.Duration, queryTime time.Time) (model.Matrix, model.Matrix, error) {
+	if err := c.scope.checkNamespace(namespace); err != nil {
+		return nil, nil, err
+	}
+	return c.ClientInterface.GetWorkloadsTrafficHistory(namespace, history, step, queryTime)
+}
+
+func (c namespaceScopedClient) FetchRange(metricName, labels, grouping, aggregator string, q *prometheus.RangeQuery) prometheus.Metric {
+	scoped, err := c.scope.scopeLabels(labels)
+	if err != nil {
+		return prometheus.Metric{Err: err}
+	}
+	return c.ClientInterface.FetchRange(metricName, scoped, grouping, aggregator, q)
+}
+
+func (c namespaceScopedClient) FetchExemplars(metricName, labels string, start, end time.Time) ([]prometheus.ExemplarQueryResult, error) {
+	scoped, err := c.scope.scopeLabels(labels)
+	if err != nil {
+		return nil, err
+	}
+	return c.ClientInterface.FetchExemplars(metricName, scoped, start, end)
+}
+
+func (c namespaceScopedClient) FetchRateRange(metricName string, labels []string, grouping string, q *prometheus.RangeQuery) prometheus.Metric {
+	scoped := make([]string, 0, len(labels))
+	for _, l := range labels {
+		s, err := c.scope.scopeLabels(l)
+		if err != nil {
+			return prometheus.Metric{Err: err}
+		}
+		scoped = append(scoped, s)
+	}
+	return c.ClientInterface.FetchRateRange(metricName, scoped, grouping, q)
+}
+
+func (c namespaceScopedClient) FetchHistogramRange(metricName, labels, grouping string, q *prometheus.RangeQuery) prometheus.Histogram {
+	scoped, err := c.scope.scopeLabels(labels)
+	if err != nil {
+		return prometheus.Histogram{"avg": prometheus.Metric{Err: err}}
+	}
+	return c.ClientInterface.FetchHistogramRange(metricName, scoped, grouping, q)
+}
+
+func (c namespaceScopedClient) FetchHistogramValues(metricName, labels, grouping, rateInterval string, avg bool, quantiles []string, queryTime time.Time) (map[string]model.Vector, error) {
+	scoped, err := c.scope.scopeLabels(labels)
+	if err != nil {
+		return nil, err
+	}
+	return c.ClientInterface.FetchHistogramValues(metricName, scoped, grouping, rateInterval, avg, quantiles, queryTime)
+}
+
+func (c namespaceScopedClient) FetchRateValues(metricName, labels, grouping, rateInterval string, queryTime time.Time) (model.Vector, error) {
+	scoped, err := c.scope.scopeLabels(labels)
+	if err != nil {
+		return nil, err
+	}
+	return c.ClientInterface.FetchRateValues(metricName, scoped, grouping, rateInterval, queryTime)
+}
+
+func (c namespaceScopedClient) FetchTopRateValues(metricName, labels, grouping, rateInterval string, limit int, queryTime time.Time) (model.Vector, error) {
+	scoped, err := c.scope.scopeLabels(labels)
+	if err != nil {
+		return nil, err
+	}
+	return c.ClientInterface.FetchTopRateValues(metricName, scoped, grouping, rateInterval, limit, queryTime)
+}
+
+func (c namespaceScopedClient) FetchValues(metricName, labels, grouping string, queryTime time.Time) (model.Vector, error) {
+	scoped, err := c.scope.scopeLabels(labels)
+	if err != nil {
+		return nil, err
+	}
+	return c.ClientInterface.FetchValues(metricName, scoped, grouping, queryTime)
+}
+
+// scopeQueryLabels restricts the labels of the queries of the MetricsService before they are looked up in the
+// cache of the downsampled series, shared by the users
+func (in *MetricsService) scopeQueryLabels(labels []string) ([]string, error) {
+	if in.scope == nil {
+		return labels, nil
+	}
+	scoped := make([]string, 0, len(labels))
+	for _, l := range labels {
+		s, err := in.scope.scopeLabels(l)
+		if err != nil {
+			return nil, err
+		}
+		scoped = append(scoped, s)
+	}
+	return scoped, nil
+}
@@ -0,0 +1,140 @@
+package business
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"k8s.io/apimachinery/pkg/api/errors"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/prometheus"
+	"github.com/kiali/kiali/prometheus/prometheustest"
+	"github.com/kiali/kiali/util"
+)
+
+// fakeNamespaceAccess grants the access to the listed namespaces only
+type fakeNamespaceAccess []string
+
+func (f fakeNamespaceAccess) GetNamespace(namespace string) (*models.Namespace, error) {
+	for _, ns := range f {
+		if ns == namespace {
+			return &models.Namespace{Name: ns}, nil
+		}
+	}
+	return nil, fmt.Errorf("namespace [%s] forbidden", namespace)
+}
+
+func (f fakeNamespaceAccess) GetNamespaces() ([]models.Namespace, error) {
+	namespaces := []models.Namespace{}
+	for _, ns := range f {
+		namespaces = append(namespaces, models.Namespace{Name: ns})
+	}
+	return namespaces, nil
+}
+
+func setupScopedMetrics(accessible ...string) (*MetricsService, *prometheustest.PromClientMock) {
+	config.Set(config.NewConfig())
+	prom := new(prometheustest.PromClientMock)
+	scope := newNamespaceScope(fakeNamespaceAccess(accessible), telemetryLabel("destination_workload_namespace"))
+	return &MetricsService{prom: namespaceScopedClient{ClientInterface: prom, scope: scope}, scope: scope}, prom
+}
+
+func TestScopedMetricsRejectInaccessibleNamespace(t *testing.T) {
+	assert := assert.New(t)
+	metrics, prom := setupScopedMetrics("bookinfo")
+
+	_, err := metrics.GetWorkloadConnectionMetrics(models.WorkloadConnectionMetricsQuery{
+		Namespace:    "travel",
+		Workload:     "travels-v1",
+		RateInterval: "5m",
+		QueryTime:    time.Now(),
+	})
+
+	assert.True(IsAccessibleError(err))
+	prom.AssertNotCalled(t, "FetchValues", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	prom.AssertNotCalled(t, "FetchRateValues", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+
+	_, err = metrics.prom.GetNamespaceServicesRequestRates("travel", "5m", time.Now())
+	assert.True(IsAccessibleError(err))
+	prom.AssertNotCalled(t, "GetNamespaceServicesRequestRates", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestScopedMetricsRejectInaccessiblePeerNamespace(t *testing.T) {
+	assert := assert.New(t)
+	metrics, prom := setupScopedMetrics("bookinfo")
+
+	for _, labels := range []string{
+		`{reporter="source",source_workload_namespace="bookinfo",destination_service_namespace="travel"}`,
+		`{reporter="destination",destination_workload_namespace=~"bookinfo|travel"}`,
+		// Only the names of the namespaces are allowed in the regular expressions
+		`{reporter="destination",destination_workload_namespace=~".*"}`,
+		`{reporter="destination",destination_workload_namespace=~"book.*"}`,
+	} {
+		_, err := metrics.prom.FetchValues("istio_requests_total", labels, "", time.Now())
+		assert.True(IsAccessibleError(err), labels)
+	}
+	prom.AssertNotCalled(t, "FetchValues", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestScopedMetricsRejectMalformedLabels(t *testing.T) {
+	assert := assert.New(t)
+	metrics, prom := setupScopedMetrics("bookinfo")
+
+	for _, labels := range []string{
+		`{destination_workload_namespace="bookinfo"} or istio_requests_total{destination_workload_namespace="travel"}`,
+		`{destination_workload_namespace="bookinfo}`,
+		`{destination_workload_namespace=bookinfo}`,
+		`{destination_workload_namespace="bookinfo" destination_workload="reviews-v1"}`,
+	} {
+		metric := metrics.prom.FetchRange("istio_requests_total", labels, "", "sum", &prometheus.RangeQuery{})
+		assert.True(errors.IsBadRequest(metric.Err), labels)
+	}
+	prom.AssertNotCalled(t, "FetchRange", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestScopedMetricsRestrictUnscopedQueries(t *testing.T) {
+	assert := assert.New(t)
+	metrics, prom := setupScopedMetrics("travel", "bookinfo")
+	queryTime := time.Now()
+
+	prom.On("FetchValues", "istio_requests_total", `{reporter="source",source_workload="reviews-v1",source_workload_namespace=~"bookinfo|travel"}`, "", queryTime).Return(model.Vector{}, nil)
+	prom.On("FetchValues", "istio_requests_total", `{reporter="destination",destination_workload_namespace=~"bookinfo|travel"}`, "", queryTime).Return(model.Vector{}, nil)
+	prom.On("FetchValues", "istio_requests_total", `{reporter="destination",destination_workload_namespace="travel"}`, "", queryTime).Return(model.Vector{}, nil)
+
+	_, err := metrics.prom.FetchValues("istio_requests_total", `{reporter="source",source_workload="reviews-v1"}`, "", queryTime)
+	assert.NoError(err)
+	_, err = metrics.prom.FetchValues("istio_requests_total", `{reporter="destination"}`, "", queryTime)
+	assert.NoError(err)
+	// Already scoped to an accessible namespace
+	_, err = metrics.prom.FetchValues("istio_requests_total", `{reporter="destination",destination_workload_namespace="travel"}`, "", queryTime)
+	assert.NoError(err)
+	prom.AssertExpectations(t)
+}
+
+func TestScopedMetricsDontReadDownsampledSeriesOfInaccessibleNamespaces(t *testing.T) {
+	assert := assert.New(t)
+	util.Clock = util.ClockMock{Time: downsamplingEnd}
+	defer func() {
+		util.Clock = util.RealClock{}
+		downsampledSeriesCache = map[string]downsampledSeries{}
+	}()
+	q := &prometheus.RangeQuery{}
+	q.Step = 5 * time.Minute
+	labels := []string{`{reporter="destination",destination_workload_namespace="travel"}`}
+
+	// Queried by a user who can access the namespace, then by a user who can't
+	metrics, prom := setupScopedMetrics("travel")
+	prom.On("FetchRateRange", "istio_requests_total", labels, "", q).Return(prometheus.Metric{Matrix: model.Matrix{}})
+	metric := metrics.fetchRateRange("istio_requests_total", labels, "", q, true)
+	assert.NoError(metric.Err)
+
+	metrics, prom = setupScopedMetrics("bookinfo")
+	metric = metrics.fetchRateRange("istio_requests_total", labels, "", q, true)
+	assert.True(IsAccessibleError(metric.Err))
+	prom.AssertNotCalled(t, "FetchRateRange", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/prometheus/common/model"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 
 	"github.com/kiali/kiali/config"
 	"github.com/kiali/kiali/models"
@@ -438,3 +439,204 @@ func createSample(value float64) *model.Sample {
 		Metric:    model.Metric{},
 	}
 }
+
+func TestGetMetricsComparison(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	prom := new(prometheustest.PromClientMock)
+	isShifted := func(q *prometheus.RangeQuery) bool { return q.Offset == "7d" }
+	isCurrent := func(q *prometheus.RangeQuery) bool { return q.Offset == "" }
+	prom.On("FetchRateRange", "istio_requests_total", mock.Anything, "destination_service_name", mock.MatchedBy(isCurrent)).Return(prometheus.Metric{Matrix: model.Matrix{
+		requestsSeries("productpage", 6, 6),
+		requestsSeries("reviews", 2, 4),
+		requestsSeries("ratings", 1, 1),
+	}})
+	prom.On("FetchRateRange", "istio_requests_total", mock.Anything, "destination_service_name", mock.MatchedBy(isShifted)).Return(prometheus.Metric{Matrix: model.Matrix{
+		requestsSeries("productpage", 4, 4),
+		requestsSeries("reviews", 0, 0),
+	}})
+
+	q := models.IstioMetricsQuery{Namespace: "bookinfo", App: "productpage"}
+	q.FillDefaults()
+	q.Filters = []string{"request_count"}
+	q.ByLabels = []string{"destination_service_name"}
+
+	comparison, err := NewMetricsService(prom).GetMetricsComparison(q, "7d", nil)
+	assert.NoError(err)
+	assert.Equal("7d", comparison.Offset)
+	assert.Len(comparison.Current["request_count"], 3)
+	assert.Len(comparison.Previous["request_count"], 2)
+
+	deltas := map[string]models.MetricDelta{}
+	for _, d := range comparison.Deltas["request_count"] {
+		deltas[d.Labels["destination_service_name"]] = d
+	}
+	assert.Len(deltas, 3)
+	assert.Equal(6.0, deltas["productpage"].Current)
+	assert.Equal(4.0, *deltas["productpage"].Previous)
+	assert.Equal(50.0, *deltas["productpage"].Percent)
+	// Previous value of zero: no percentage
+	assert.Equal(3.0, deltas["reviews"].Current)
+	assert.Equal(0.0, *deltas["reviews"].Previous)
+	assert.Nil(deltas["reviews"].Percent)
+	// No historical data
+	assert.Equal(1.0, deltas["ratings"].Current)
+	assert.Nil(deltas["ratings"].Previous)
+	assert.Nil(deltas["ratings"].Percent)
+}
+
+func TestGetMetricsComparisonInvalidOffset(t *testing.T) {
+	assert := assert.New(t)
+	prom := new(prometheustest.PromClientMock)
+	srv := NewMetricsService(prom)
+
+	q := models.IstioMetricsQuery{Namespace: "bookinfo", App: "productpage"}
+	q.FillDefaults()
+
+	for _, offset := range []string{"", "-7d", "0s", "seven days"} {
+		_, err := srv.GetMetricsComparison(q, offset, nil)
+		assert.Error(err, offset)
+	}
+	prom.AssertNotCalled(t, "FetchRateRange")
+}
+
+func requestsSeries(service string, values ...float64) *model.SampleStream {
+	stream := &model.SampleStream{Metric: model.Metric{"destination_service_name": model.LabelValue(service)}}
+	for i, v := range values {
+		stream.Values = append(stream.Values, model.SamplePair{Timestamp: model.Time(i * 15000), Value: model.SampleValue(v)})
+	}
+	return stream
+}
+
+func TestGetMetricsWithTelemetryMapping(t *testing.T) {
+	assert := assert.New(t)
+	conf := config.NewConfig()
+	conf.ExternalServices.Istio.TelemetryMapping = config.TelemetryMapping{
+		Metrics: map[string]string{
+			"istio_requests_total":                "mesh_requests_total",
+			"istio_request_duration_milliseconds": "mesh_request_duration_ms",
+			"istio_request_bytes":                 "mesh_request_size",
+		},
+		Labels: map[string]string{
+			"reporter":                      "reported_by",
+			"destination_service_name":      "dst_svc",
+			"destination_service_namespace": "dst_ns",
+			"source_workload":               "src_wl",
+			"response_code":                 "http_status",
+		},
+	}
+	config.Set(conf)
+	defer config.Set(config.NewConfig())
+
+	prom := new(prometheustest.PromClientMock)
+	labels := `{reported_by="destination",dst_svc="productpage",dst_ns="bookinfo"}`
+	prom.On("FetchRateRange", "mesh_requests_total", []string{labels}, "src_wl", mock.Anything).Return(prometheus.Metric{Matrix: model.Matrix{
+		&model.SampleStream{
+			Metric: model.Metric{"src_wl": "istio-ingressgateway"},
+			Values: []model.SamplePair{{Timestamp: 0, Value: 5}},
+		},
+	}})
+	prom.On("FetchRateRange", "mesh_requests_total", []string{
+		`{reported_by="destination",dst_svc="productpage",dst_ns="bookinfo",http_status=~"^0$|^[4-5]\\d\\d$"}`,
+		`{reported_by="destination",dst_svc="productpage",dst_ns="bookinfo",grpc_response_status=~"^[1-9]$|^1[0-6]$",http_status!~"^0$|^[4-5]\\d\\d$"}`,
+	}, "src_wl", mock.Anything).Return(prometheus.Metric{})
+	prom.On("FetchRateRange", "mesh_request_size_sum", []string{labels}, "src_wl", mock.Anything).Return(prometheus.Metric{})
+	prom.On("FetchHistogramRange", "mesh_request_duration_ms", labels, "src_wl", mock.Anything).Return(prometheus.Histogram{})
+
+	q := models.IstioMetricsQuery{Namespace: "bookinfo", Service: "productpage"}
+	q.FillDefaults()
+	q.Direction = "inbound"
+	q.Reporter = "destination"
+	q.Filters = []string{"request_count", "request_error_count", "request_throughput", "request_duration_millis"}
+	q.ByLabels = []string{"source_workload"}
+
+	metrics, err := NewMetricsService(prom).GetMetrics(q, nil)
+	assert.NoError(err)
+	prom.AssertExpectations(t)
+
+	// Labels of the results keep the Istio standard names
+	assert.Len(metrics["request_count"], 1)
+	assert.Equal(map[string]string{"source_workload": "istio-ingressgateway"}, metrics["request_count"][0].Labels)
+}
+
+func TestGetMetricsWithExemplars(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	prom := new(prometheustest.PromClientMock)
+	prom.On("FetchHistogramRange", "istio_request_duration_milliseconds", mock.Anything, "source_workload", mock.Anything).Return(prometheus.Histogram{
+		"avg": prometheus.Metric{Matrix: model.Matrix{
+			&model.SampleStream{Metric: model.Metric{"source_workload": "productpage-v1"}, Values: []model.SamplePair{{Timestamp: 0, Value: 20}}},
+			&model.SampleStream{Metric: model.Metric{"source_workload": "istio-ingressgateway"}, Values: []model.SamplePair{{Timestamp: 0, Value: 800}}},
+		}},
+	})
+	prom.On("FetchExemplars", "istio_request_duration_milliseconds_bucket", mock.Anything, mock.Anything, mock.Anything).Return([]prometheus.ExemplarQueryResult{
+		{
+			SeriesLabels: model.LabelSet{"source_workload": "productpage-v1", "le": "50"},
+			Exemplars: []prometheus.Exemplar{
+				{Labels: model.LabelSet{"trace_id": "00f067aa0ba902b7"}, Value: 12, Timestamp: 2000},
+				{Labels: model.LabelSet{"trace_id": "4bf92f3577b34da6"}, Value: 38.5, Timestamp: 1000},
+			},
+		},
+		{
+			// Same exemplar in another bucket
+			SeriesLabels: model.LabelSet{"source_workload": "productpage-v1", "le": "+Inf"},
+			Exemplars:    []prometheus.Exemplar{{Labels: model.LabelSet{"trace_id": "4bf92f3577b34da6"}, Value: 38.5, Timestamp: 1000}},
+		},
+		{
+			SeriesLabels: model.LabelSet{"source_workload": "istio-ingressgateway", "le": "+Inf"},
+			Exemplars:    []prometheus.Exemplar{{Labels: model.LabelSet{"traceID": "a3ce929d0e0e4736"}, Value: 1250, Timestamp: 3000}},
+		},
+	}, nil)
+
+	q := models.IstioMetricsQuery{Namespace: "bookinfo", Service: "reviews"}
+	q.FillDefaults()
+	q.Filters = []string{"request_duration_millis"}
+	q.ByLabels = []string{"source_workload"}
+	q.Exemplars = true
+
+	metrics, err := NewMetricsService(prom).GetMetrics(q, nil)
+	assert.NoError(err)
+
+	durations := map[string]models.Metric{}
+	for _, m := range metrics["request_duration_millis"] {
+		durations[m.Labels["source_workload"]] = m
+	}
+	assert.Len(durations, 2)
+	// Deduplicated and sorted by time
+	productpage := durations["productpage-v1"].Exemplars
+	assert.Len(productpage, 2)
+	assert.Equal("4bf92f3577b34da6", productpage[0].TraceID)
+	assert.Equal(38.5, productpage[0].Value)
+	assert.Equal("00f067aa0ba902b7", productpage[1].TraceID)
+	ingress := durations["istio-ingressgateway"].Exemplars
+	assert.Len(ingress, 1)
+	assert.Equal("a3ce929d0e0e4736", ingress[0].TraceID)
+}
+
+func TestGetMetricsWithExemplarsNotSupported(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	prom := new(prometheustest.PromClientMock)
+	prom.On("FetchHistogramRange", "istio_request_duration_milliseconds", mock.Anything, "source_workload", mock.Anything).Return(prometheus.Histogram{
+		"avg": prometheus.Metric{Matrix: model.Matrix{
+			&model.SampleStream{Metric: model.Metric{"source_workload": "productpage-v1"}, Values: []model.SamplePair{{Timestamp: 0, Value: 20}}},
+		}},
+	})
+	prom.On("FetchExemplars", "istio_request_duration_milliseconds_bucket", mock.Anything, mock.Anything, mock.Anything).Return([]prometheus.ExemplarQueryResult(nil), prometheus.ErrExemplarsNotSupported)
+
+	q := models.IstioMetricsQuery{Namespace: "bookinfo", Service: "reviews"}
+	q.FillDefaults()
+	q.Filters = []string{"request_duration_millis"}
+	q.ByLabels = []string{"source_workload"}
+	q.Exemplars = true
+
+	// The metrics are returned without exemplars
+	metrics, err := NewMetricsService(prom).GetMetrics(q, nil)
+	assert.NoError(err)
+	assert.Len(metrics["request_duration_millis"], 1)
+	assert.Empty(metrics["request_duration_millis"][0].Exemplars)
+	prom.AssertExpectations(t)
+}
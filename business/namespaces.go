@@ -1,9 +1,14 @@
 package business
 
 import (
+	goerrors "errors"
 	"regexp"
+	"sort"
+	"sync"
+	"time"
 
 	osproject_v1 "github.com/openshift/api/project/v1"
+	"github.com/prometheus/common/model"
 	core_v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/client-go/tools/clientcmd/api"
@@ -12,14 +17,33 @@ import (
 	"github.com/kiali/kiali/kubernetes"
 	"github.com/kiali/kiali/log"
 	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/prometheus"
 	"github.com/kiali/kiali/prometheus/internalmetrics"
 )
 
+const (
+	// Window over which a namespace must have mesh traffic to be active
+	activeNamespacesWindow = "10m"
+	// How long the active namespaces are kept before querying Prometheus again
+	activeNamespacesTTL = 30 * time.Second
+)
+
+var (
+	activeNamespacesLock       sync.Mutex
+	activeNamespaces           map[string]bool
+	activeNamespacesExpiration time.Time
+)
+
 // Namespace deals with fetching k8s namespaces / OpenShift projects and convert to kiali model
 type NamespaceService struct {
 	k8s                    kubernetes.ClientInterface
+	prom                   prometheus.ClientInterface
+	businessLayer          *Layer
 	hasProjects            bool
 	isAccessibleNamespaces map[string]bool
+
+	// remote is set for the namespaces of a remote cluster, which are neither cached nor read with the Kiali SA
+	remote bool
 }
 
 type AccessibleNamespaceError struct {
@@ -31,8 +55,8 @@ func (in *AccessibleNamespaceError) Error() string {
 }
 
 func IsAccessibleError(err error) bool {
-	_, isAccessibleError := err.(*AccessibleNamespaceError)
-	return isAccessibleError
+	var accessibleError *AccessibleNamespaceError
+	return goerrors.As(err, &accessibleError)
 }
 
 func NewNamespaceService(k8s kubernetes.ClientInterface) NamespaceService {
@@ -58,13 +82,21 @@ func NewNamespaceService(k8s kubernetes.ClientInterface) NamespaceService {
 	}
 }
 
+// NewClusterNamespaceService returns the service of the namespaces of a remote cluster, read with its client. The
+// Kiali cache only holds the namespaces of the home cluster, so the namespaces of the remote cluster are not cached.
+func NewClusterNamespaceService(k8s kubernetes.ClientInterface) NamespaceService {
+	namespaceService := NewNamespaceService(k8s)
+	namespaceService.remote = true
+	return namespaceService
+}
+
 // Returns a list of the given namespaces / projects
 func (in *NamespaceService) GetNamespaces() ([]models.Namespace, error) {
 	var err error
 	promtimer := internalmetrics.GetGoFunctionMetric("business", "NamespaceService", "GetNamespaces")
 	defer promtimer.ObserveNow(&err)
 
-	if kialiCache != nil {
+	if kialiCache != nil && !in.remote {
 		if ns := kialiCache.GetNamespaces(in.k8s.GetToken()); ns != nil {
 			return ns, nil
 		}
@@ -100,7 +132,7 @@ func (in *NamespaceService) GetNamespaces() ([]models.Namespace, error) {
 			nss, err := in.k8s.GetNamespaces(labelSelector)
 			if err != nil {
 				// Fallback to using the Kiali service account, if needed
-				if errors.IsForbidden(err) {
+				if errors.IsForbidden(err) && !in.remote {
 					if nss, err = in.getNamespacesUsingKialiSA(labelSelector, err); err != nil {
 						return nil, err
 					}
@@ -147,13 +179,66 @@ func (in *NamespaceService) GetNamespaces() ([]models.Namespace, error) {
 		}
 	}
 
-	if kialiCache != nil {
+	if kialiCache != nil && !in.remote {
 		kialiCache.SetNamespaces(in.k8s.GetToken(), result)
 	}
 
 	return result, nil
 }
 
+// GetActiveNamespaces returns the namespaces of GetNamespaces with recent mesh traffic, sent or received.
+// Namespaces are active when Prometheus has requests from or to them over the last 10 minutes. As they don't
+// depend on the user, the active namespaces are cached for a short while.
+func (in *NamespaceService) GetActiveNamespaces(queryTime time.Time) ([]models.Namespace, error) {
+	var err error
+	promtimer := internalmetrics.GetGoFunctionMetric("business", "NamespaceService", "GetActiveNamespaces")
+	defer promtimer.ObserveNow(&err)
+
+	namespaces, err := in.GetNamespaces()
+	if err != nil {
+		return nil, err
+	}
+	active, err := in.getActiveNamespaces(queryTime)
+	if err != nil {
+		return nil, err
+	}
+	result := []models.Namespace{}
+	for _, namespace := range namespaces {
+		if active[namespace.Name] {
+			result = append(result, namespace)
+		}
+	}
+	return result, nil
+}
+
+// getActiveNamespaces returns the namespaces with requests over the window, from the cache when not expired.
+// The lock is held while querying, so that concurrent calls don't query Prometheus several times.
+func (in *NamespaceService) getActiveNamespaces(queryTime time.Time) (map[string]bool, error) {
+	activeNamespacesLock.Lock()
+	defer activeNamespacesLock.Unlock()
+	if activeNamespaces != nil && queryTime.Before(activeNamespacesExpiration) {
+		return activeNamespaces, nil
+	}
+
+	active := map[string]bool{}
+	metric := telemetryMetric("istio_requests_total")
+	for _, label := range []string{telemetryLabel("source_workload_namespace"), telemetryLabel("destination_service_namespace")} {
+		rates, err := in.prom.FetchRateValues(metric, "", label, activeNamespacesWindow, queryTime)
+		if err != nil {
+			return nil, err
+		}
+		for _, sample := range rates {
+			namespace := string(sample.Metric[model.LabelName(label)])
+			if namespace != "" && namespace != "unknown" && sample.Value > 0 {
+				active[namespace] = true
+			}
+		}
+	}
+	activeNamespaces = active
+	activeNamespacesExpiration = queryTime.Add(activeNamespacesTTL)
+	return active, nil
+}
+
 func (in *NamespaceService) isAccessibleNamespace(namespace string) bool {
 	_, queryAllNamespaces := in.isAccessibleNamespaces["**"]
 	if queryAllNamespaces {
@@ -183,7 +268,7 @@ func (in *NamespaceService) GetNamespace(namespace string) (*models.Namespace, e
 	defer promtimer.ObserveNow(&err)
 
 	// Cache already has included/excluded namespaces applied
-	if kialiCache != nil {
+	if kialiCache != nil && !in.remote {
 		if ns := kialiCache.GetNamespace(in.k8s.GetToken(), namespace); ns != nil {
 			return ns, nil
 		}
@@ -214,7 +299,7 @@ func (in *NamespaceService) GetNamespace(namespace string) (*models.Namespace, e
 		result = models.CastNamespace(*ns)
 	}
 	// Refresh cache in case of cache expiration
-	if kialiCache != nil {
+	if kialiCache != nil && !in.remote {
 		if _, err = in.GetNamespaces(); err != nil {
 			return nil, err
 		}
@@ -237,6 +322,7 @@ func (in *NamespaceService) UpdateNamespace(namespace string, jsonPatch string)
 	if err != nil {
 		return nil, err
 	}
+	in.businessLayer.Audit.Record("", AuditUpdate, namespace, "Namespace", namespace)
 
 	// Cache is stopped after a Create/Update/Delete operation to force a refresh
 	if kialiCache != nil && err == nil {
@@ -301,3 +387,153 @@ func getNamespacesForKialiSA(labelSelector string) ([]core_v1.Namespace, error)
 
 	return nss, nil
 }
+
+// GetNamespaceOverview returns the health, the Istio config counts, the mTLS status and the validations summary
+// of a namespace in a single call. Sections are computed in parallel; when one of them fails the overview is still
+// returned with the error of the section reported in its Errors map.
+func (in *NamespaceService) GetNamespaceOverview(namespace, rateInterval string, queryTime time.Time) (models.NamespaceOverview, error) {
+	var err error
+	promtimer := internalmetrics.GetGoFunctionMetric("business", "NamespaceService", "GetNamespaceOverview")
+	defer promtimer.ObserveNow(&err)
+
+	ns, err := in.GetNamespace(namespace)
+	if err != nil {
+		return models.NamespaceOverview{}, err
+	}
+
+	overview := models.NamespaceOverview{
+		Namespace:    *ns,
+		AppHealth:    models.NamespaceAppHealth{},
+		ConfigCounts: map[string]int{},
+		Errors:       map[string]string{},
+	}
+
+	var mu sync.Mutex
+	setError := func(section string, sectionErr error) {
+		mu.Lock()
+		defer mu.Unlock()
+		overview.Errors[section] = sectionErr.Error()
+		log.Warningf("Namespace overview of [%s] is missing its %s: %s", namespace, section, sectionErr)
+	}
+
+	wg := sync.WaitGroup{}
+	wg.Add(2)
+
+	// The Istio config is fetched once, shared by the config counts, the mTLS status and the validations
+	go func() {
+		defer wg.Done()
+		nsConfig, configErr := in.businessLayer.Validations.fetchNamespaceIstioConfig(namespace)
+		if configErr != nil {
+			setError(models.OverviewConfigs, configErr)
+			setError(models.OverviewTLS, configErr)
+			setError(models.OverviewValidations, configErr)
+			return
+		}
+		overview.ConfigCounts = countIstioConfig(nsConfig)
+
+		sectionsWg := sync.WaitGroup{}
+		sectionsWg.Add(2)
+		go func() {
+			defer sectionsWg.Done()
+			tlsStatus, tlsErr := in.businessLayer.TLS.namespaceWidemTLSStatus(namespace, nsConfig.mtlsDetails)
+			if tlsErr != nil {
+				setError(models.OverviewTLS, tlsErr)
+				return
+			}
+			overview.TLSStatus = tlsStatus
+		}()
+		go func() {
+			defer sectionsWg.Done()
+			istioValidations, validationsErr := in.businessLayer.Validations.runValidations(namespace, "", nil, &nsConfig)
+			if validationsErr != nil {
+				setError(models.OverviewValidations, validationsErr)
+				return
+			}
+			overview.Validations = istioValidations.SummarizeValidation(namespace)
+		}()
+		sectionsWg.Wait()
+	}()
+
+	go func() {
+		defer wg.Done()
+		// Health of all the apps is resolved with a single batched Prometheus query
+		appHealth, healthErr := in.businessLayer.Health.GetNamespaceAppHealth(namespace, rateInterval, queryTime)
+		if healthErr != nil {
+			setError(models.OverviewHealth, healthErr)
+			return
+		}
+		overview.AppHealth = appHealth
+	}()
+
+	wg.Wait()
+
+	if len(overview.Errors) == 0 {
+		overview.Errors = nil
+	}
+
+	return overview, nil
+}
+
+// countIstioConfig returns the number of Istio objects of the namespace keyed by object type
+func countIstioConfig(nsConfig namespaceIstioConfig) map[string]int {
+	return map[string]int{
+		kubernetes.Gateways:               len(nsConfig.istioDetails.Gateways),
+		kubernetes.VirtualServices:        len(nsConfig.istioDetails.VirtualServices),
+		kubernetes.DestinationRules:       len(nsConfig.istioDetails.DestinationRules),
+		kubernetes.ServiceEntries:         len(nsConfig.istioDetails.ServiceEntries),
+		kubernetes.WorkloadEntries:        len(nsConfig.workloadEntries),
+		kubernetes.EnvoyFilters:           len(nsConfig.istioDetails.EnvoyFilters),
+		kubernetes.Sidecars:               len(nsConfig.istioDetails.Sidecars),
+		kubernetes.AuthorizationPolicies:  len(nsConfig.rbacDetails.AuthorizationPolicies),
+		kubernetes.PeerAuthentications:    len(nsConfig.mtlsDetails.PeerAuthentications),
+		kubernetes.RequestAuthentications: len(nsConfig.istioDetails.RequestAuthentications),
+		kubernetes.ProxyConfigs:           len(nsConfig.istioDetails.ProxyConfigs),
+	}
+}
+
+// GetMeshSnapshot returns the health, the mTLS status and the validations summary of all the accessible namespaces
+// at queryTime, in a deterministic form: the namespaces and their apps are sorted by name, so two snapshots of an
+// unchanged mesh only differ by their time.
+func (in *NamespaceService) GetMeshSnapshot(rateInterval string, queryTime time.Time) (models.MeshSnapshot, error) {
+	var err error
+	promtimer := internalmetrics.GetGoFunctionMetric("business", "NamespaceService", "GetMeshSnapshot")
+	defer promtimer.ObserveNow(&err)
+
+	var namespaces []models.Namespace
+	if namespaces, err = in.GetNamespaces(); err != nil {
+		return models.MeshSnapshot{}, err
+	}
+
+	snapshots := make([]models.NamespaceSnapshot, len(namespaces))
+	errs := make([]error, len(namespaces))
+	wg := sync.WaitGroup{}
+	wg.Add(len(namespaces))
+	for i, ns := range namespaces {
+		go func(i int, namespace string) {
+			defer wg.Done()
+			overview, overviewErr := in.GetNamespaceOverview(namespace, rateInterval, queryTime)
+			if overviewErr != nil {
+				errs[i] = overviewErr
+				return
+			}
+			snapshots[i] = models.NewNamespaceSnapshot(overview)
+		}(i, ns.Name)
+	}
+	wg.Wait()
+
+	for _, nsErr := range errs {
+		if nsErr != nil {
+			err = nsErr
+			return models.MeshSnapshot{}, err
+		}
+	}
+
+	sort.Slice(snapshots, func(i, j int) bool {
+		return snapshots[i].Name < snapshots[j].Name
+	})
+	return models.MeshSnapshot{
+		Time:         queryTime.UTC(),
+		RateInterval: rateInterval,
+		Namespaces:   snapshots,
+	}, nil
+}
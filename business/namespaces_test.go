@@ -0,0 +1,236 @@
+package business
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	core_v1 "k8s.io/api/core/v1"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/kubernetes/kubetest"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/prometheus/prometheustest"
+)
+
+func TestGetNamespaceOverview(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	k8s := mockNamespaceOverview()
+	prom := new(prometheustest.PromClientMock)
+	prom.On("GetAllRequestRates", "test", "1m", mock.AnythingOfType("time.Time")).Return(model.Vector{}, nil)
+
+	layer := NewWithBackends(k8s, prom, nil)
+	overview, err := layer.Namespace.GetNamespaceOverview("test", "1m", time.Date(2017, 01, 15, 0, 0, 0, 0, time.UTC))
+
+	assert.NoError(err)
+	assert.Equal("test", overview.Namespace.Name)
+	assert.Nil(overview.Errors)
+
+	assert.Equal(1, overview.ConfigCounts[kubernetes.VirtualServices])
+	assert.Equal(2, overview.ConfigCounts[kubernetes.DestinationRules])
+	assert.Equal(0, overview.ConfigCounts[kubernetes.Sidecars])
+	assert.Equal(0, overview.ConfigCounts[kubernetes.ProxyConfigs])
+	assert.Len(overview.ConfigCounts, 11)
+
+	assert.NotEmpty(overview.AppHealth)
+	assert.NotEmpty(overview.TLSStatus.Status)
+	assert.Equal(3, overview.Validations.ObjectCount)
+
+	// Health of all the apps is fetched with a single query
+	prom.AssertNumberOfCalls(t, "GetAllRequestRates", 1)
+
+	// The Istio config is fetched once for all the sections
+	vsFetches := 0
+	for _, call := range k8s.Calls {
+		if call.Method == "GetIstioObjects" && call.Arguments[0] == "test" && call.Arguments[1] == kubernetes.VirtualServices {
+			vsFetches++
+		}
+	}
+	assert.Equal(1, vsFetches)
+}
+
+func TestGetNamespaceOverviewPartialFailure(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	k8s := mockNamespaceOverview()
+	prom := new(prometheustest.PromClientMock)
+	prom.On("GetAllRequestRates", "test", "1m", mock.AnythingOfType("time.Time")).Return(model.Vector{}, errors.New("prometheus unavailable"))
+
+	layer := NewWithBackends(k8s, prom, nil)
+	overview, err := layer.Namespace.GetNamespaceOverview("test", "1m", time.Date(2017, 01, 15, 0, 0, 0, 0, time.UTC))
+
+	assert.NoError(err)
+	assert.Len(overview.Errors, 1)
+	assert.Contains(overview.Errors[models.OverviewHealth], "prometheus unavailable")
+	assert.Empty(overview.AppHealth)
+
+	// The other sections are still returned
+	assert.Equal(1, overview.ConfigCounts[kubernetes.VirtualServices])
+	assert.Equal(2, overview.ConfigCounts[kubernetes.DestinationRules])
+	assert.NotEmpty(overview.TLSStatus.Status)
+	assert.Equal(3, overview.Validations.ObjectCount)
+}
+
+func TestGetNamespaceOverviewNotAccessible(t *testing.T) {
+	// Fake Istio objects reset the config, set it once the mocks are ready
+	k8s := mockNamespaceOverview()
+	conf := config.NewConfig()
+	conf.Deployment.AccessibleNamespaces = []string{"bookinfo"}
+	config.Set(conf)
+
+	prom := new(prometheustest.PromClientMock)
+
+	layer := NewWithBackends(k8s, prom, nil)
+	_, err := layer.Namespace.GetNamespaceOverview("test", "1m", time.Date(2017, 01, 15, 0, 0, 0, 0, time.UTC))
+
+	assert.Error(t, err)
+	prom.AssertNumberOfCalls(t, "GetAllRequestRates", 0)
+}
+
+func mockNamespaceOverview() *kubetest.K8SClientMock {
+	k8s := new(kubetest.K8SClientMock)
+	setupNamespaceOverviewMocks(k8s)
+	return k8s
+}
+
+// setupNamespaceOverviewMocks sets the default expectations, after the ones already set on the mock
+func setupNamespaceOverviewMocks(k8s *kubetest.K8SClientMock) {
+	k8s.On("IsOpenShift").Return(false)
+	k8s.On("IsMaistraApi").Return(false)
+	k8s.On("GetNamespace", "test").Return(kubetest.FakeNamespace("test"), nil)
+	k8s.On("GetNamespace", "test2").Return(kubetest.FakeNamespace("test2"), nil)
+	k8s.On("GetNamespaces", mock.AnythingOfType("string")).Return(fakeNamespaces(), nil)
+	k8s.On("GetServices", mock.AnythingOfType("string"), mock.AnythingOfType("map[string]string")).Return(fakeCombinedServices([]string{"product", "customer"}), nil)
+	k8s.On("GetIstioObjects", mock.AnythingOfType("string"), "virtualservices", "").Return(fakeCombinedIstioDetails().VirtualServices, nil)
+	k8s.On("GetIstioObjects", mock.AnythingOfType("string"), "destinationrules", "").Return(fakeCombinedIstioDetails().DestinationRules, nil)
+	k8s.On("GetIstioObjects", mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return([]kubernetes.IstioObject{}, nil)
+	k8s.On("GetSecrets", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return([]core_v1.Secret{}, nil)
+	mockWorkLoadService(k8s)
+}
+
+func TestGetActiveNamespaces(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+	defer resetActiveNamespaces()
+
+	queryTime := time.Date(2017, 01, 15, 0, 0, 0, 0, time.UTC)
+	k8s := mockActiveNamespaces()
+	prom := mockActiveNamespacesTraffic(queryTime)
+
+	layer := NewWithBackends(k8s, prom, nil)
+	namespaces, err := layer.Namespace.GetActiveNamespaces(queryTime)
+
+	assert.NoError(err)
+	// bookinfo sends requests, reviews receives requests, idle has none
+	assert.Len(namespaces, 2)
+	assert.Equal("bookinfo", namespaces[0].Name)
+	assert.Equal("reviews", namespaces[1].Name)
+}
+
+func TestGetActiveNamespacesIsCached(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+	defer resetActiveNamespaces()
+
+	queryTime := time.Date(2017, 01, 15, 0, 0, 0, 0, time.UTC)
+	k8s := mockActiveNamespaces()
+	prom := mockActiveNamespacesTraffic(queryTime)
+	prom.On("FetchRateValues", "istio_requests_total", "", mock.AnythingOfType("string"), "10m", mock.AnythingOfType("time.Time")).Return(model.Vector{}, nil)
+
+	layer := NewWithBackends(k8s, prom, nil)
+	_, err := layer.Namespace.GetActiveNamespaces(queryTime)
+	assert.NoError(err)
+	namespaces, err := layer.Namespace.GetActiveNamespaces(queryTime.Add(10 * time.Second))
+	assert.NoError(err)
+	assert.Len(namespaces, 2)
+	prom.AssertNumberOfCalls(t, "FetchRateValues", 2)
+
+	// Once expired, the namespaces without traffic anymore are not active
+	namespaces, err = layer.Namespace.GetActiveNamespaces(queryTime.Add(time.Minute))
+	assert.NoError(err)
+	assert.Empty(namespaces)
+	prom.AssertNumberOfCalls(t, "FetchRateValues", 4)
+}
+
+func TestGetActiveNamespacesPrometheusError(t *testing.T) {
+	config.Set(config.NewConfig())
+	defer resetActiveNamespaces()
+
+	k8s := mockActiveNamespaces()
+	prom := new(prometheustest.PromClientMock)
+	prom.On("FetchRateValues", "istio_requests_total", "", "source_workload_namespace", "10m", mock.AnythingOfType("time.Time")).Return(model.Vector{}, errors.New("prometheus unavailable"))
+
+	layer := NewWithBackends(k8s, prom, nil)
+	_, err := layer.Namespace.GetActiveNamespaces(time.Date(2017, 01, 15, 0, 0, 0, 0, time.UTC))
+
+	assert.Error(t, err)
+}
+
+func mockActiveNamespaces() *kubetest.K8SClientMock {
+	k8s := new(kubetest.K8SClientMock)
+	k8s.On("IsOpenShift").Return(false)
+	k8s.On("GetNamespaces", mock.AnythingOfType("string")).Return([]core_v1.Namespace{
+		*kubetest.FakeNamespace("bookinfo"),
+		*kubetest.FakeNamespace("idle"),
+		*kubetest.FakeNamespace("reviews"),
+	}, nil)
+	return k8s
+}
+
+func mockActiveNamespacesTraffic(queryTime time.Time) *prometheustest.PromClientMock {
+	prom := new(prometheustest.PromClientMock)
+	prom.On("FetchRateValues", "istio_requests_total", "", "source_workload_namespace", "10m", queryTime).Return(model.Vector{
+		&model.Sample{Metric: model.Metric{"source_workload_namespace": "bookinfo"}, Value: 2},
+		&model.Sample{Metric: model.Metric{"source_workload_namespace": "unknown"}, Value: 1},
+		// A namespace not accessible to the user
+		&model.Sample{Metric: model.Metric{"source_workload_namespace": "istio-system"}, Value: 1},
+	}, nil)
+	prom.On("FetchRateValues", "istio_requests_total", "", "destination_service_namespace", "10m", queryTime).Return(model.Vector{
+		&model.Sample{Metric: model.Metric{"destination_service_namespace": "reviews"}, Value: 2},
+		&model.Sample{Metric: model.Metric{"destination_service_namespace": "idle"}, Value: 0},
+	}, nil)
+	return prom
+}
+
+func resetActiveNamespaces() {
+	activeNamespacesLock.Lock()
+	defer activeNamespacesLock.Unlock()
+	activeNamespaces = nil
+}
+
+func TestGetMeshSnapshot(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	k8s := mockNamespaceOverview()
+	prom := new(prometheustest.PromClientMock)
+	prom.On("GetAllRequestRates", mock.AnythingOfType("string"), "10m", mock.AnythingOfType("time.Time")).Return(model.Vector{}, nil)
+
+	layer := NewWithBackends(k8s, prom, nil)
+	queryTime := time.Date(2017, 01, 15, 0, 0, 0, 0, time.FixedZone("CET", 3600))
+	snapshot, err := layer.Namespace.GetMeshSnapshot("10m", queryTime)
+	assert.NoError(err)
+
+	actual, err := json.MarshalIndent(snapshot, "", "  ")
+	assert.NoError(err)
+	expected, err := ioutil.ReadFile("../tests/data/snapshots/mesh-snapshot.json")
+	if err != nil {
+		t.Fatalf("Error loading golden file: %v", err)
+	}
+	assert.Equal(strings.TrimSpace(string(expected)), string(actual))
+
+	// The snapshot of an unchanged mesh is the same
+	again, err := layer.Namespace.GetMeshSnapshot("10m", queryTime)
+	assert.NoError(err)
+	assert.Equal(snapshot, again)
+}
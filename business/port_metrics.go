@@ -0,0 +1,103 @@
+package business
+
+import (
+	"math"
+	"sort"
+	"strconv"
+
+	"github.com/prometheus/common/model"
+
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/prometheus/internalmetrics"
+)
+
+// destinationPortLabel is not reported by the Istio standard telemetry, it's usually added through the Telemetry API
+const destinationPortLabel = "destination_port"
+
+// GetWorkloadPortMetrics returns the request rate, the error rate and the latencies of the traffic of a workload
+// per destination port, to isolate a misbehaving port of a workload serving several ports.
+func (in *MetricsService) GetWorkloadPortMetrics(q models.WorkloadPortMetricsQuery) (*models.WorkloadPortMetrics, error) {
+	var err error
+	promtimer := internalmetrics.GetGoFunctionMetric("business", "MetricsService", "GetWorkloadPortMetrics")
+	defer promtimer.ObserveNow(&err)
+
+	lb := NewMetricsLabelsBuilder(q.Direction)
+	lb.SelfReporter()
+	lb.Workload(q.Workload, q.Namespace)
+	labels := lb.Build()
+
+	var rates model.Vector
+	grouping := destinationPortLabel + "," + telemetryGrouping("response_code,grpc_response_status")
+	rates, err = in.prom.FetchRateValues(telemetryMetric("istio_requests_total"), labels, grouping, q.RateInterval, q.QueryTime)
+	if err != nil {
+		return nil, err
+	}
+	var latencies map[string]model.Vector
+	latencies, err = in.prom.FetchHistogramValues(telemetryMetric("istio_request_duration_milliseconds"), labels, destinationPortLabel, q.RateInterval, true, latencyQuantiles, q.QueryTime)
+	if err != nil {
+		return nil, err
+	}
+	return buildWorkloadPortMetrics(q, rates, latencies), nil
+}
+
+func buildWorkloadPortMetrics(q models.WorkloadPortMetricsQuery, rates model.Vector, latencies map[string]model.Vector) *models.WorkloadPortMetrics {
+	lblCode := model.LabelName(telemetryLabel("response_code"))
+	lblGrpcStatus := model.LabelName(telemetryLabel("grpc_response_status"))
+
+	ports := map[string]*models.PortMetrics{}
+	getPort := func(sample *model.Sample) *models.PortMetrics {
+		port := string(sample.Metric[destinationPortLabel])
+		if port == "" {
+			port = models.UnknownPort
+		}
+		if _, found := ports[port]; !found {
+			ports[port] = &models.PortMetrics{Port: port, Latencies: []models.Stat{}}
+		}
+		return ports[port]
+	}
+
+	for _, sample := range rates {
+		value := float64(sample.Value)
+		if math.IsNaN(value) || value == 0 {
+			continue
+		}
+		port := getPort(sample)
+		port.RequestRate += value
+		if isErrorResponse(string(sample.Metric[lblCode]), string(sample.Metric[lblGrpcStatus])) {
+			port.ErrorRate += value
+		}
+	}
+	for stat, vec := range latencies {
+		for _, sample := range vec {
+			value := float64(sample.Value)
+			if math.IsNaN(value) {
+				continue
+			}
+			port := getPort(sample)
+			port.Latencies = append(port.Latencies, models.Stat{Name: stat, Value: value})
+		}
+	}
+
+	portMetrics := &models.WorkloadPortMetrics{
+		Namespace: q.Namespace,
+		Workload:  q.Workload,
+		Direction: q.Direction,
+		Ports:     []models.PortMetrics{},
+	}
+	for _, port := range ports {
+		sort.Slice(port.Latencies, func(i, j int) bool {
+			return port.Latencies[i].Name < port.Latencies[j].Name
+		})
+		portMetrics.Ports = append(portMetrics.Ports, *port)
+	}
+	// Numeric ports first, the unknown port last
+	sort.Slice(portMetrics.Ports, func(i, j int) bool {
+		pi, errI := strconv.Atoi(portMetrics.Ports[i].Port)
+		pj, errJ := strconv.Atoi(portMetrics.Ports[j].Port)
+		if errI != nil || errJ != nil {
+			return errI == nil
+		}
+		return pi < pj
+	})
+	return portMetrics
+}
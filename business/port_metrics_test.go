@@ -0,0 +1,109 @@
+package business
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/prometheus/prometheustest"
+)
+
+func TestGetWorkloadPortMetrics(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	queryTime := time.Date(2017, 01, 15, 0, 0, 0, 0, time.UTC)
+	labels := `{reporter="destination",destination_workload_namespace="bookinfo",destination_workload="reviews-v1"}`
+	prom := new(prometheustest.PromClientMock)
+	prom.On("FetchRateValues", "istio_requests_total", labels, "destination_port,response_code,grpc_response_status", "5m", queryTime).Return(fakePortRates(), nil)
+	prom.On("FetchHistogramValues", "istio_request_duration_milliseconds", labels, "destination_port", "5m", true, []string{"0.5", "0.95", "0.99"}, queryTime).Return(fakePortLatencies(), nil)
+
+	portMetrics, err := NewMetricsService(prom).GetWorkloadPortMetrics(models.WorkloadPortMetricsQuery{
+		Namespace:    "bookinfo",
+		Workload:     "reviews-v1",
+		Direction:    "inbound",
+		RateInterval: "5m",
+		QueryTime:    queryTime,
+	})
+
+	assert.NoError(err)
+	assert.Equal("reviews-v1", portMetrics.Workload)
+	assert.Equal("inbound", portMetrics.Direction)
+	assert.Len(portMetrics.Ports, 3)
+	prom.AssertExpectations(t)
+}
+
+func TestWorkloadPortMetricsPerPort(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	q := models.WorkloadPortMetricsQuery{Namespace: "bookinfo", Workload: "reviews-v1", Direction: "inbound"}
+	portMetrics := buildWorkloadPortMetrics(q, fakePortRates(), fakePortLatencies())
+
+	// Sorted by port, the unknown port last
+	assert.Len(portMetrics.Ports, 3)
+	healthy := portMetrics.Ports[0]
+	assert.Equal("9080", healthy.Port)
+	assert.InDelta(10.0, healthy.RequestRate, 0.001)
+	assert.InDelta(0.0, healthy.ErrorRate, 0.001)
+	assert.Equal([]models.Stat{{Name: "0.99", Value: 50}, {Name: "avg", Value: 20}}, healthy.Latencies)
+
+	failing := portMetrics.Ports[1]
+	assert.Equal("15090", failing.Port)
+	assert.InDelta(4.0, failing.RequestRate, 0.001)
+	assert.InDelta(3.5, failing.ErrorRate, 0.001)
+	assert.Equal([]models.Stat{{Name: "0.99", Value: 3000}, {Name: "avg", Value: 800}}, failing.Latencies)
+
+	// Traffic reported without destination port
+	unknown := portMetrics.Ports[2]
+	assert.Equal(models.UnknownPort, unknown.Port)
+	assert.InDelta(1.0, unknown.RequestRate, 0.001)
+	assert.InDelta(0.5, unknown.ErrorRate, 0.001)
+	assert.Empty(unknown.Latencies)
+}
+
+func TestWorkloadPortMetricsWithoutTraffic(t *testing.T) {
+	assert := assert.New(t)
+
+	q := models.WorkloadPortMetricsQuery{Namespace: "bookinfo", Workload: "reviews-v1", Direction: "outbound"}
+	portMetrics := buildWorkloadPortMetrics(q, model.Vector{}, map[string]model.Vector{})
+
+	assert.Equal("outbound", portMetrics.Direction)
+	assert.Empty(portMetrics.Ports)
+}
+
+func fakePortRates() model.Vector {
+	sample := func(port, code, grpcStatus string, value float64) *model.Sample {
+		metric := model.Metric{"response_code": model.LabelValue(code)}
+		if port != "" {
+			metric["destination_port"] = model.LabelValue(port)
+		}
+		if grpcStatus != "" {
+			metric["grpc_response_status"] = model.LabelValue(grpcStatus)
+		}
+		return &model.Sample{Metric: metric, Value: model.SampleValue(value)}
+	}
+	return model.Vector{
+		sample("9080", "200", "", 10),
+		sample("15090", "200", "", 0.5),
+		sample("15090", "200", "14", 0.5),
+		sample("15090", "503", "", 2.5),
+		sample("15090", "404", "", 0.5),
+		sample("", "200", "", 0.5),
+		sample("", "0", "", 0.5),
+	}
+}
+
+func fakePortLatencies() map[string]model.Vector {
+	sample := func(port string, value float64) *model.Sample {
+		return &model.Sample{Metric: model.Metric{"destination_port": model.LabelValue(port)}, Value: model.SampleValue(value)}
+	}
+	return map[string]model.Vector{
+		"avg":  {sample("9080", 20), sample("15090", 800)},
+		"0.99": {sample("9080", 50), sample("15090", 3000)},
+	}
+}
@@ -0,0 +1,338 @@
+package business
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	core_v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/log"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/prometheus/internalmetrics"
+)
+
+// The reconciler of the namespace log levels doesn't list the pods more often than this, whatever the config
+const minProxyLogLevelReconcileInterval = 10 * time.Second
+
+// The levels of the Envoy loggers
+var proxyLogLevels = map[string]bool{
+	"off":      true,
+	"trace":    true,
+	"debug":    true,
+	"info":     true,
+	"warning":  true,
+	"error":    true,
+	"critical": true,
+}
+
+// namespaceLogLevel is the default log level of the proxies of a namespace, and the pods it was applied to
+type namespaceLogLevel struct {
+	level   string
+	applied map[types.UID]bool
+}
+
+var (
+	namespaceLogLevelsLock sync.Mutex
+	namespaceLogLevels     = map[string]*namespaceLogLevel{}
+	// The levels of a namespace are applied by one caller at a time, so that a new default isn't overwritten by an
+	// older one. The proxies are called outside namespaceLogLevelsLock, not to block the other namespaces meanwhile.
+	namespaceLogLevelApplyLocks = map[string]*sync.Mutex{}
+
+	proxyLogLevelReconcilerLock     sync.Mutex
+	proxyLogLevelReconcilerStopChan chan struct{}
+)
+
+// ProxyLoggingService changes the log level of the proxies
+type ProxyLoggingService struct {
+	k8s           kubernetes.ClientInterface
+	businessLayer *Layer
+}
+
+// SetPodLogLevel changes the log level of the proxy of a pod, until the pod is restarted
+func (in *ProxyLoggingService) SetPodLogLevel(namespace, pod, level string) error {
+	var err error
+	promtimer := internalmetrics.GetGoFunctionMetric("business", "ProxyLoggingService", "SetPodLogLevel")
+	defer promtimer.ObserveNow(&err)
+
+	if !proxyLogLevels[level] {
+		err = errors.NewBadRequest(fmt.Sprintf("invalid proxy log level [%s]", level))
+		return err
+	}
+	// Check if user has access to the namespace (RBAC) in cache scenarios and/or
+	// if namespace is accessible from Kiali (Deployment.AccessibleNamespaces)
+	if _, err = in.businessLayer.Namespace.GetNamespace(namespace); err != nil {
+		return err
+	}
+	if err = in.k8s.SetProxyLogLevel(namespace, pod, level); err != nil {
+		return err
+	}
+	in.businessLayer.Audit.Record("", AuditUpdate, namespace, "Pod", pod)
+	return nil
+}
+
+// SetNamespaceLogLevel changes the log level of the proxies of all the pods of a namespace, and records it as the
+// default of the namespace: the reconciler applies it to the pods created later, and again to the pods failing now.
+// The default is kept in memory, it's lost when Kiali restarts.
+func (in *ProxyLoggingService) SetNamespaceLogLevel(namespace, level string) (*models.NamespaceProxyLogLevel, error) {
+	var err error
+	promtimer := internalmetrics.GetGoFunctionMetric("business", "ProxyLoggingService", "SetNamespaceLogLevel")
+	defer promtimer.ObserveNow(&err)
+
+	if !proxyLogLevels[level] {
+		err = errors.NewBadRequest(fmt.Sprintf("invalid proxy log level [%s]", level))
+		return nil, err
+	}
+	if _, err = in.businessLayer.Namespace.GetNamespace(namespace); err != nil {
+		return nil, err
+	}
+	if err = in.checkProxyLogLevelAccess(namespace); err != nil {
+		return nil, err
+	}
+
+	var pods []core_v1.Pod
+	if pods, err = in.k8s.GetPods(namespace, ""); err != nil {
+		return nil, err
+	}
+	// A new default replaces the previous one: all the pods get the new level. It's applied under the lock of the
+	// namespace, so that the reconciler doesn't apply the previous one meanwhile.
+	applyLock := namespaceLogLevelApplyLock(namespace)
+	applyLock.Lock()
+	nsLevel := &namespaceLogLevel{level: level, applied: map[types.UID]bool{}}
+	result := applyNamespaceLogLevel(in.k8s, namespace, nsLevel, pods)
+	namespaceLogLevelsLock.Lock()
+	namespaceLogLevels[namespace] = nsLevel
+	namespaceLogLevelsLock.Unlock()
+	applyLock.Unlock()
+
+	in.businessLayer.Audit.Record("", AuditUpdate, namespace, "Namespace", namespace)
+	return result, nil
+}
+
+// GetNamespaceLogLevel returns the default log level of the proxies of a namespace, or nil when there is none
+func (in *ProxyLoggingService) GetNamespaceLogLevel(namespace string) (*models.NamespaceProxyLogLevel, error) {
+	if _, err := in.businessLayer.Namespace.GetNamespace(namespace); err != nil {
+		return nil, err
+	}
+
+	namespaceLogLevelsLock.Lock()
+	defer namespaceLogLevelsLock.Unlock()
+	nsLevel, found := namespaceLogLevels[namespace]
+	if !found {
+		return nil, nil
+	}
+	return &models.NamespaceProxyLogLevel{Namespace: namespace, Level: nsLevel.level, Applied: []string{}, Failed: []string{}}, nil
+}
+
+// ClearNamespaceLogLevel forgets the default log level of the proxies of a namespace. The current proxies keep their
+// level, the pods created later start with the level of the mesh.
+func (in *ProxyLoggingService) ClearNamespaceLogLevel(namespace string) error {
+	if _, err := in.businessLayer.Namespace.GetNamespace(namespace); err != nil {
+		return err
+	}
+	if err := in.checkProxyLogLevelAccess(namespace); err != nil {
+		return err
+	}
+
+	namespaceLogLevelsLock.Lock()
+	delete(namespaceLogLevels, namespace)
+	namespaceLogLevelsLock.Unlock()
+
+	in.businessLayer.Audit.Record("", AuditUpdate, namespace, "Namespace", namespace)
+	return nil
+}
+
+// checkProxyLogLevelAccess checks that the user can forward the ports of the pods of the namespace. The default of the
+// namespace is applied later with the Kiali ServiceAccount, so reading the namespace isn't enough to change it.
+func (in *ProxyLoggingService) checkProxyLogLevelAccess(namespace string) error {
+	ssars, err := in.k8s.GetSelfSubjectAccessReview(namespace, "", "pods/portforward", []string{"create"})
+	if err != nil {
+		return err
+	}
+	for _, ssar := range ssars {
+		if ssar.Status.Allowed {
+			return nil
+		}
+	}
+	return &AccessibleNamespaceError{msg: "Proxy log level of namespace [" + namespace + "] can't be changed: pods/portforward is not allowed"}
+}
+
+// namespaceLogLevelApplyLock returns the lock owning the applied pods of the levels of a namespace
+func namespaceLogLevelApplyLock(namespace string) *sync.Mutex {
+	namespaceLogLevelsLock.Lock()
+	defer namespaceLogLevelsLock.Unlock()
+	applyLock, found := namespaceLogLevelApplyLocks[namespace]
+	if !found {
+		applyLock = &sync.Mutex{}
+		namespaceLogLevelApplyLocks[namespace] = applyLock
+	}
+	return applyLock
+}
+
+// applyNamespaceLogLevel applies the level, in parallel up to the configured limit, to the running proxies of the pods
+// which didn't get it yet, and forgets the pods which are gone. The caller must own nsLevel, or hold the apply lock of
+// the namespace.
+func applyNamespaceLogLevel(k8s kubernetes.ClientInterface, namespace string, nsLevel *namespaceLogLevel, pods []core_v1.Pod) *models.NamespaceProxyLogLevel {
+	result := &models.NamespaceProxyLogLevel{Namespace: namespace, Level: nsLevel.level, Applied: []string{}, Failed: []string{}}
+	current := make(map[types.UID]bool, len(pods))
+	var pending []*core_v1.Pod
+	for i := range pods {
+		p := &pods[i]
+		current[p.UID] = true
+		if nsLevel.applied[p.UID] || p.Status.Phase != core_v1.PodRunning || p.DeletionTimestamp != nil {
+			continue
+		}
+		pod := models.Pod{}
+		pod.Parse(p)
+		if pod.HasIstioSidecar() {
+			pending = append(pending, p)
+		}
+	}
+
+	// Each proxy is called through a port forwarding, only a few of them are opened at once
+	maxConcurrency := config.Get().ExternalServices.Istio.ProxyLogLevelMaxConcurrency
+	if maxConcurrency < 1 {
+		maxConcurrency = 1
+	}
+	slots := make(chan struct{}, maxConcurrency)
+	errs := make([]error, len(pending))
+	wg := sync.WaitGroup{}
+	wg.Add(len(pending))
+	for i, p := range pending {
+		slots <- struct{}{}
+		go func(i int, name string) {
+			defer func() {
+				<-slots
+				wg.Done()
+			}()
+			errs[i] = k8s.SetProxyLogLevel(namespace, name, nsLevel.level)
+		}(i, p.Name)
+	}
+	wg.Wait()
+
+	for i, p := range pending {
+		if errs[i] != nil {
+			result.Failed = append(result.Failed, p.Name)
+			continue
+		}
+		nsLevel.applied[p.UID] = true
+		result.Applied = append(result.Applied, p.Name)
+	}
+	for uid := range nsLevel.applied {
+		if !current[uid] {
+			delete(nsLevel.applied, uid)
+		}
+	}
+	sort.Strings(result.Applied)
+	sort.Strings(result.Failed)
+	return result
+}
+
+// StartProxyLogLevelReconciler periodically applies the default log levels of the namespaces to the proxies of the new
+// pods, with the Kiali ServiceAccount. The interval is bounded, not to list the pods too often.
+func StartProxyLogLevelReconciler() {
+	proxyLogLevelReconcilerLock.Lock()
+	defer proxyLogLevelReconcilerLock.Unlock()
+	if proxyLogLevelReconcilerStopChan != nil {
+		return
+	}
+
+	interval := time.Duration(config.Get().ExternalServices.Istio.ProxyLogLevelReconcileInterval) * time.Second
+	if interval < minProxyLogLevelReconcileInterval {
+		interval = minProxyLogLevelReconcileInterval
+	}
+	stopChan := make(chan struct{})
+	proxyLogLevelReconcilerStopChan = stopChan
+
+	log.Infof("Starting proxy log level reconciler every %v", interval)
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				reconcileProxyLogLevelsWithKialiSA()
+			case <-stopChan:
+				log.Info("Proxy log level reconciler stopped")
+				return
+			}
+		}
+	}()
+}
+
+// StopProxyLogLevelReconciler stops the proxy log level reconciler if it's running
+func StopProxyLogLevelReconciler() {
+	proxyLogLevelReconcilerLock.Lock()
+	defer proxyLogLevelReconcilerLock.Unlock()
+	if proxyLogLevelReconcilerStopChan != nil {
+		close(proxyLogLevelReconcilerStopChan)
+		proxyLogLevelReconcilerStopChan = nil
+	}
+}
+
+func reconcileProxyLogLevelsWithKialiSA() {
+	namespaceLogLevelsLock.Lock()
+	empty := len(namespaceLogLevels) == 0
+	namespaceLogLevelsLock.Unlock()
+	if empty {
+		return
+	}
+
+	k8s, err := kialiSAClient()
+	if err != nil {
+		log.Errorf("Proxy log levels can't be reconciled, Kiali client is not available: %s", err)
+		return
+	}
+	reconcileProxyLogLevels(k8s)
+}
+
+// reconcileProxyLogLevels applies the default log levels of the namespaces to the proxies which didn't get it yet
+func reconcileProxyLogLevels(k8s kubernetes.ClientInterface) {
+	var err error
+	promtimer := internalmetrics.GetGoFunctionMetric("business", "ProxyLoggingService", "reconcileProxyLogLevels")
+	defer promtimer.ObserveNow(&err)
+
+	// The namespaces are read under the lock, their levels are applied under the lock of each namespace
+	namespaceLogLevelsLock.Lock()
+	namespaces := make([]string, 0, len(namespaceLogLevels))
+	for namespace := range namespaceLogLevels {
+		namespaces = append(namespaces, namespace)
+	}
+	namespaceLogLevelsLock.Unlock()
+
+	for _, namespace := range namespaces {
+		reconcileNamespaceLogLevel(k8s, namespace)
+	}
+}
+
+func reconcileNamespaceLogLevel(k8s kubernetes.ClientInterface, namespace string) {
+	applyLock := namespaceLogLevelApplyLock(namespace)
+	applyLock.Lock()
+	defer applyLock.Unlock()
+
+	// The default may have been cleared since the namespaces were read
+	namespaceLogLevelsLock.Lock()
+	nsLevel, found := namespaceLogLevels[namespace]
+	namespaceLogLevelsLock.Unlock()
+	if !found {
+		return
+	}
+
+	pods, err := k8s.GetPods(namespace, "")
+	if err != nil {
+		log.Errorf("Proxy log level of namespace [%s] can't be reconciled: %s", namespace, err)
+		return
+	}
+	result := applyNamespaceLogLevel(k8s, namespace, nsLevel, pods)
+	if len(result.Applied) > 0 {
+		log.Debugf("Proxy log level [%s] applied to the new pods of namespace [%s]: %v", nsLevel.level, namespace, result.Applied)
+	}
+	if len(result.Failed) > 0 {
+		log.Warningf("Proxy log level [%s] can't be applied to pods of namespace [%s], will be retried: %v", nsLevel.level, namespace, result.Failed)
+	}
+}
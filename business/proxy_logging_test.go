@@ -0,0 +1,241 @@
+package business
+
+import (
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	osproject_v1 "github.com/openshift/api/project/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	auth_v1 "k8s.io/api/authorization/v1"
+	core_v1 "k8s.io/api/core/v1"
+	k8s_errors "k8s.io/apimachinery/pkg/api/errors"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes/kubetest"
+)
+
+func TestSetNamespaceLogLevelAppliesToNewPods(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+	defer resetNamespaceLogLevels()
+
+	k8s := setupProxyLoggingMocks()
+	k8s.On("GetPods", "bookinfo", "").Return([]core_v1.Pod{
+		fakeLoggingPod("reviews-v1-1", "uid-1", core_v1.PodRunning, true),
+		fakeLoggingPod("reviews-v2-1", "uid-2", core_v1.PodRunning, true),
+		fakeLoggingPod("reviews-v3-1", "uid-3", core_v1.PodPending, true),
+		fakeLoggingPod("legacy-1", "uid-4", core_v1.PodRunning, false),
+	}, nil).Once()
+	k8s.On("SetProxyLogLevel", "bookinfo", mock.AnythingOfType("string"), "debug").Return(nil)
+	svc := ProxyLoggingService{k8s: k8s, businessLayer: NewWithBackends(k8s, nil, nil)}
+
+	// Only the running pods with a proxy are changed
+	result, err := svc.SetNamespaceLogLevel("bookinfo", "debug")
+	assert.NoError(err)
+	assert.Equal([]string{"reviews-v1-1", "reviews-v2-1"}, result.Applied)
+	assert.Empty(result.Failed)
+	k8s.AssertNumberOfCalls(t, "SetProxyLogLevel", 2)
+
+	// The pod started since, and the pod created by a rollout, get the level. The gone pod is forgotten.
+	k8s.On("GetPods", "bookinfo", "").Return([]core_v1.Pod{
+		fakeLoggingPod("reviews-v1-1", "uid-1", core_v1.PodRunning, true),
+		fakeLoggingPod("reviews-v3-1", "uid-3", core_v1.PodRunning, true),
+		fakeLoggingPod("reviews-v2-2", "uid-5", core_v1.PodRunning, true),
+	}, nil).Once()
+	reconcileProxyLogLevels(k8s)
+
+	k8s.AssertNumberOfCalls(t, "SetProxyLogLevel", 4)
+	k8s.AssertCalled(t, "SetProxyLogLevel", "bookinfo", "reviews-v3-1", "debug")
+	k8s.AssertCalled(t, "SetProxyLogLevel", "bookinfo", "reviews-v2-2", "debug")
+	assert.Equal(map[types.UID]bool{"uid-1": true, "uid-3": true, "uid-5": true}, namespaceLogLevels["bookinfo"].applied)
+
+	// Nothing new, nothing applied
+	k8s.On("GetPods", "bookinfo", "").Return([]core_v1.Pod{
+		fakeLoggingPod("reviews-v1-1", "uid-1", core_v1.PodRunning, true),
+	}, nil).Once()
+	reconcileProxyLogLevels(k8s)
+	k8s.AssertNumberOfCalls(t, "SetProxyLogLevel", 4)
+}
+
+func TestReconcileProxyLogLevelsRetriesFailedPods(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+	defer resetNamespaceLogLevels()
+
+	pods := []core_v1.Pod{fakeLoggingPod("reviews-v1-1", "uid-1", core_v1.PodRunning, true)}
+	k8s := setupProxyLoggingMocks()
+	k8s.On("GetPods", "bookinfo", "").Return(pods, nil)
+	k8s.On("SetProxyLogLevel", "bookinfo", "reviews-v1-1", "warning").Return(errors.New("connection refused")).Once()
+	k8s.On("SetProxyLogLevel", "bookinfo", "reviews-v1-1", "warning").Return(nil)
+	svc := ProxyLoggingService{k8s: k8s, businessLayer: NewWithBackends(k8s, nil, nil)}
+
+	result, err := svc.SetNamespaceLogLevel("bookinfo", "warning")
+	assert.NoError(err)
+	assert.Empty(result.Applied)
+	assert.Equal([]string{"reviews-v1-1"}, result.Failed)
+
+	reconcileProxyLogLevels(k8s)
+	reconcileProxyLogLevels(k8s)
+	k8s.AssertNumberOfCalls(t, "SetProxyLogLevel", 2)
+}
+
+func TestSetNamespaceLogLevelBoundsConcurrency(t *testing.T) {
+	assert := assert.New(t)
+	conf := config.NewConfig()
+	conf.ExternalServices.Istio.ProxyLogLevelMaxConcurrency = 2
+	config.Set(conf)
+	defer resetNamespaceLogLevels()
+
+	pods := []core_v1.Pod{}
+	for i := 0; i < 10; i++ {
+		pods = append(pods, fakeLoggingPod(fmt.Sprintf("reviews-v1-%d", i), fmt.Sprintf("uid-%d", i), core_v1.PodRunning, true))
+	}
+	k8s := setupProxyLoggingMocks()
+	k8s.On("GetPods", "bookinfo", "").Return(pods, nil)
+	var inFlight, maxInFlight int32
+	k8s.On("SetProxyLogLevel", "bookinfo", mock.AnythingOfType("string"), "info").Run(func(mock.Arguments) {
+		current := atomic.AddInt32(&inFlight, 1)
+		for {
+			max := atomic.LoadInt32(&maxInFlight)
+			if current <= max || atomic.CompareAndSwapInt32(&maxInFlight, max, current) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+		atomic.AddInt32(&inFlight, -1)
+	}).Return(nil)
+	svc := ProxyLoggingService{k8s: k8s, businessLayer: NewWithBackends(k8s, nil, nil)}
+
+	result, err := svc.SetNamespaceLogLevel("bookinfo", "info")
+	assert.NoError(err)
+	assert.Len(result.Applied, 10)
+	assert.LessOrEqual(atomic.LoadInt32(&maxInFlight), int32(2))
+}
+
+func TestReconcileProxyLogLevelsDoesNotBlockReaders(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+	defer resetNamespaceLogLevels()
+
+	k8s := setupProxyLoggingMocks()
+	k8s.On("GetPods", "bookinfo", "").Return([]core_v1.Pod{}, nil).Once()
+	svc := ProxyLoggingService{k8s: k8s, businessLayer: NewWithBackends(k8s, nil, nil)}
+	_, err := svc.SetNamespaceLogLevel("bookinfo", "debug")
+	assert.NoError(err)
+
+	// The default of the namespace is readable while the proxies are called
+	k8s.On("GetPods", "bookinfo", "").Return([]core_v1.Pod{
+		fakeLoggingPod("reviews-v1-1", "uid-1", core_v1.PodRunning, true),
+		fakeLoggingPod("reviews-v2-1", "uid-2", core_v1.PodRunning, true),
+	}, nil).Once()
+	k8s.On("SetProxyLogLevel", "bookinfo", mock.AnythingOfType("string"), "debug").Run(func(mock.Arguments) {
+		logLevel, err := svc.GetNamespaceLogLevel("bookinfo")
+		assert.NoError(err)
+		assert.Equal("debug", logLevel.Level)
+	}).Return(nil)
+	reconcileProxyLogLevels(k8s)
+
+	k8s.AssertNumberOfCalls(t, "SetProxyLogLevel", 2)
+	assert.Equal(map[types.UID]bool{"uid-1": true, "uid-2": true}, namespaceLogLevels["bookinfo"].applied)
+}
+
+func TestClearNamespaceLogLevel(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+	defer resetNamespaceLogLevels()
+
+	k8s := setupProxyLoggingMocks()
+	k8s.On("GetPods", "bookinfo", "").Return([]core_v1.Pod{}, nil)
+	svc := ProxyLoggingService{k8s: k8s, businessLayer: NewWithBackends(k8s, nil, nil)}
+
+	_, err := svc.SetNamespaceLogLevel("bookinfo", "trace")
+	assert.NoError(err)
+	logLevel, err := svc.GetNamespaceLogLevel("bookinfo")
+	assert.NoError(err)
+	assert.Equal("trace", logLevel.Level)
+
+	assert.NoError(svc.ClearNamespaceLogLevel("bookinfo"))
+	logLevel, err = svc.GetNamespaceLogLevel("bookinfo")
+	assert.NoError(err)
+	assert.Nil(logLevel)
+
+	// Without default, the new pods are not listed
+	reconcileProxyLogLevels(k8s)
+	k8s.AssertNumberOfCalls(t, "GetPods", 1)
+}
+
+func TestNamespaceLogLevelRequiresPortForward(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+	defer resetNamespaceLogLevels()
+
+	// The user can read the namespace, but not forward the ports of its pods
+	k8s := new(kubetest.K8SClientMock)
+	k8s.On("IsOpenShift").Return(true)
+	k8s.On("GetProject", "bookinfo").Return(&osproject_v1.Project{}, nil)
+	k8s.On("GetSelfSubjectAccessReview", "bookinfo", "", "pods/portforward", []string{"create"}).Return(fakePortForwardReviews(false), nil)
+	k8s.On("GetPods", "bookinfo", "").Return([]core_v1.Pod{fakeLoggingPod("reviews-v1-1", "uid-1", core_v1.PodRunning, true)}, nil)
+	k8s.On("SetProxyLogLevel", "bookinfo", "reviews-v1-1", "debug").Return(k8s_errors.NewForbidden(schema.GroupResource{Resource: "pods"}, "reviews-v1-1", errors.New("portforward")))
+	svc := ProxyLoggingService{k8s: k8s, businessLayer: NewWithBackends(k8s, nil, nil)}
+
+	_, err := svc.SetNamespaceLogLevel("bookinfo", "debug")
+	assert.True(IsAccessibleError(err))
+	assert.NotContains(namespaceLogLevels, "bookinfo")
+	reconcileProxyLogLevels(k8s)
+	k8s.AssertNotCalled(t, "SetProxyLogLevel", mock.Anything, mock.Anything, mock.Anything)
+
+	// The default set by another user isn't cleared either
+	namespaceLogLevels["bookinfo"] = &namespaceLogLevel{level: "trace", applied: map[types.UID]bool{}}
+	assert.True(IsAccessibleError(svc.ClearNamespaceLogLevel("bookinfo")))
+	assert.Equal("trace", namespaceLogLevels["bookinfo"].level)
+}
+
+func TestSetLogLevelBadRequest(t *testing.T) {
+	config.Set(config.NewConfig())
+	defer resetNamespaceLogLevels()
+
+	k8s := setupProxyLoggingMocks()
+	svc := ProxyLoggingService{k8s: k8s, businessLayer: NewWithBackends(k8s, nil, nil)}
+
+	_, err := svc.SetNamespaceLogLevel("bookinfo", "verbose")
+	assert.True(t, k8s_errors.IsBadRequest(err))
+	assert.True(t, k8s_errors.IsBadRequest(svc.SetPodLogLevel("bookinfo", "reviews-v1-1", "")))
+	k8s.AssertNotCalled(t, "SetProxyLogLevel", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func setupProxyLoggingMocks() *kubetest.K8SClientMock {
+	k8s := new(kubetest.K8SClientMock)
+	k8s.On("IsOpenShift").Return(true)
+	k8s.On("GetProject", mock.AnythingOfType("string")).Return(&osproject_v1.Project{}, nil)
+	k8s.On("GetSelfSubjectAccessReview", mock.AnythingOfType("string"), "", "pods/portforward", []string{"create"}).Return(fakePortForwardReviews(true), nil)
+	return k8s
+}
+
+func fakePortForwardReviews(allowed bool) []*auth_v1.SelfSubjectAccessReview {
+	return []*auth_v1.SelfSubjectAccessReview{{
+		Spec:   auth_v1.SelfSubjectAccessReviewSpec{ResourceAttributes: &auth_v1.ResourceAttributes{Verb: "create", Resource: "pods", Subresource: "portforward"}},
+		Status: auth_v1.SubjectAccessReviewStatus{Allowed: allowed},
+	}}
+}
+
+func fakeLoggingPod(name, uid string, phase core_v1.PodPhase, sidecar bool) core_v1.Pod {
+	pod := core_v1.Pod{
+		ObjectMeta: meta_v1.ObjectMeta{Name: name, Namespace: "bookinfo", UID: types.UID(uid)},
+		Status:     core_v1.PodStatus{Phase: phase},
+	}
+	if sidecar {
+		pod.Annotations = kubetest.FakeIstioAnnotations()
+	}
+	return pod
+}
+
+func resetNamespaceLogLevels() {
+	namespaceLogLevels = map[string]*namespaceLogLevel{}
+}
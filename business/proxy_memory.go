@@ -0,0 +1,163 @@
+package business
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/prometheus/common/model"
+	core_v1 "k8s.io/api/core/v1"
+
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/prometheus/internalmetrics"
+)
+
+const (
+	// proxyMemoryPressureRatio is the usage over limit from which a proxy is near its memory limit
+	proxyMemoryPressureRatio = 0.9
+	// proxyOOMKilledWindow is how long an OOMKill of a proxy is considered recent
+	proxyOOMKilledWindow = time.Hour
+)
+
+// GetNamespaceProxyMemory returns the memory usage against the limit of the istio-proxy containers of the workloads
+// of the namespace, and their OOMKill events, flagging the proxies near their limit or recently OOMKilled. The usage
+// comes from the cAdvisor metrics scraped by Prometheus, the OOMKills from the status of the pods.
+func (in *WorkloadService) GetNamespaceProxyMemory(namespace string, queryTime time.Time) (*models.NamespaceProxyMemory, error) {
+	var err error
+	promtimer := internalmetrics.GetGoFunctionMetric("business", "WorkloadService", "GetNamespaceProxyMemory")
+	defer promtimer.ObserveNow(&err)
+
+	// Check if user has access to the namespace (RBAC) in cache scenarios and/or
+	// if namespace is accessible from Kiali (Deployment.AccessibleNamespaces)
+	if _, err = in.businessLayer.Namespace.GetNamespace(namespace); err != nil {
+		return nil, err
+	}
+
+	var ws models.Workloads
+	var pods []core_v1.Pod
+	var memoryUsage model.Vector
+
+	wg := sync.WaitGroup{}
+	wg.Add(3)
+	errChan := make(chan error, 3)
+
+	go func() {
+		defer wg.Done()
+		var err2 error
+		ws, err2 = fetchWorkloads(in.businessLayer, namespace, "")
+		if err2 != nil {
+			errChan <- err2
+		}
+	}()
+
+	go func() {
+		defer wg.Done()
+		var err2 error
+		if IsNamespaceCached(namespace) {
+			pods, err2 = kialiCache.GetPods(namespace, "")
+		} else {
+			pods, err2 = in.k8s.GetPods(namespace, "")
+		}
+		if err2 != nil {
+			errChan <- err2
+		}
+	}()
+
+	go func() {
+		defer wg.Done()
+		labels := fmt.Sprintf(`{namespace="%s",container="%s"}`, namespace, models.IstioProxyContainer)
+		var err2 error
+		memoryUsage, err2 = in.prom.FetchValues("container_memory_working_set_bytes", labels, "pod", queryTime)
+		if err2 != nil {
+			errChan <- err2
+		}
+	}()
+
+	wg.Wait()
+	if len(errChan) != 0 {
+		err = <-errChan
+		return nil, err
+	}
+
+	return buildNamespaceProxyMemory(namespace, ws, pods, memoryUsage, queryTime), nil
+}
+
+// buildNamespaceProxyMemory combines the memory usage of the istio-proxy containers with their limit and status
+func buildNamespaceProxyMemory(namespace string, ws models.Workloads, pods []core_v1.Pod, memoryUsage model.Vector, queryTime time.Time) *models.NamespaceProxyMemory {
+	memory := &models.NamespaceProxyMemory{
+		Namespace: namespace,
+		Workloads: []models.WorkloadProxyMemory{},
+	}
+
+	podsByName := make(map[string]core_v1.Pod, len(pods))
+	for _, pod := range pods {
+		podsByName[pod.Name] = pod
+	}
+	memoryByPod := podValues(memoryUsage)
+
+	for _, w := range ws {
+		wm := models.WorkloadProxyMemory{Workload: w.Name, Proxies: []models.ProxyMemory{}}
+		for _, p := range w.Pods {
+			pod, found := podsByName[p.Name]
+			if !found {
+				continue
+			}
+			proxy, found := proxyContainer(pod)
+			if !found {
+				continue
+			}
+			pm := proxyMemory(pod, proxy, memoryByPod[p.Name], queryTime)
+			wm.UnderPressure = wm.UnderPressure || pm.NearLimit || pm.RecentlyOOMKilled
+			wm.Proxies = append(wm.Proxies, pm)
+		}
+		if len(wm.Proxies) == 0 {
+			continue
+		}
+		sort.Slice(wm.Proxies, func(i, j int) bool {
+			return wm.Proxies[i].Pod < wm.Proxies[j].Pod
+		})
+		memory.Workloads = append(memory.Workloads, wm)
+	}
+	sort.Slice(memory.Workloads, func(i, j int) bool {
+		return memory.Workloads[i].Workload < memory.Workloads[j].Workload
+	})
+	return memory
+}
+
+// proxyMemory evaluates the memory pressure of the istio-proxy container of a pod. Only the current state and the
+// last termination of a container are known: an older OOMKill is hidden by a later termination.
+func proxyMemory(pod core_v1.Pod, proxy core_v1.Container, usage *float64, queryTime time.Time) models.ProxyMemory {
+	pm := models.ProxyMemory{Pod: pod.Name, Usage: usage}
+	if limit, found := proxy.Resources.Limits[core_v1.ResourceMemory]; found && !limit.IsZero() {
+		value := limit.AsApproximateFloat64()
+		pm.Limit = &value
+		if usage != nil {
+			ratio := *usage / value
+			pm.UsageRatio = &ratio
+			pm.NearLimit = ratio >= proxyMemoryPressureRatio
+		}
+	}
+
+	for _, statuses := range [][]core_v1.ContainerStatus{pod.Status.ContainerStatuses, pod.Status.InitContainerStatuses} {
+		for _, status := range statuses {
+			if status.Name != models.IstioProxyContainer {
+				continue
+			}
+			pm.Restarts = status.RestartCount
+			for _, terminated := range []*core_v1.ContainerStateTerminated{status.State.Terminated, status.LastTerminationState.Terminated} {
+				if terminated == nil || terminated.Reason != "OOMKilled" {
+					continue
+				}
+				if pm.LastOOMKilled == nil || terminated.FinishedAt.After(*pm.LastOOMKilled) {
+					finishedAt := terminated.FinishedAt.Time
+					pm.LastOOMKilled = &finishedAt
+				}
+			}
+		}
+	}
+	if pm.LastOOMKilled != nil {
+		pm.RecentlyOOMKilled = queryTime.Sub(*pm.LastOOMKilled) <= proxyOOMKilledWindow
+	}
+	return pm
+}
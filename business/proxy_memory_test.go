@@ -0,0 +1,93 @@
+package business
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/models"
+)
+
+func TestNamespaceProxyMemory(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	pods := loadPods(t, "../tests/data/proxy/memory_pods.yaml")
+	ws := models.Workloads{
+		fakeProxyWorkload("reviews-v1", "reviews-v1-7f99cc4496-x7k2p", "reviews-v1-7f99cc4496-gdxfn"),
+		fakeProxyWorkload("details-v1", "details-v1-5f449bdbb9-pmf8l"),
+		fakeProxyWorkload("ratings-v1", "ratings-v1-b6994bb9-rqk5n"),
+		fakeProxyWorkload("mongodb-v1", "mongodb-v1-6b5b7f8d7-9vq2c"),
+	}
+	memoryUsage := model.Vector{
+		&model.Sample{Metric: model.Metric{"pod": "reviews-v1-7f99cc4496-gdxfn"}, Value: 120 * 1024 * 1024},
+		&model.Sample{Metric: model.Metric{"pod": "reviews-v1-7f99cc4496-x7k2p"}, Value: 40 * 1024 * 1024},
+		&model.Sample{Metric: model.Metric{"pod": "details-v1-5f449bdbb9-pmf8l"}, Value: 50 * 1024 * 1024},
+	}
+	queryTime := time.Date(2022, 1, 10, 12, 0, 0, 0, time.UTC)
+
+	memory := buildNamespaceProxyMemory("bookinfo", ws, pods, memoryUsage, queryTime)
+
+	assert.Equal("bookinfo", memory.Namespace)
+	// Sorted by name, mongodb has no proxy
+	assert.Len(memory.Workloads, 3)
+
+	// OOMKilled a day ago, without limit now
+	details := memory.Workloads[0]
+	assert.Equal("details-v1", details.Workload)
+	assert.False(details.UnderPressure)
+	assert.Len(details.Proxies, 1)
+	assert.Nil(details.Proxies[0].Limit)
+	assert.Nil(details.Proxies[0].UsageRatio)
+	assert.Equal(float64(50*1024*1024), *details.Proxies[0].Usage)
+	assert.Equal(time.Date(2022, 1, 9, 12, 0, 0, 0, time.UTC), details.Proxies[0].LastOOMKilled.UTC())
+	assert.False(details.Proxies[0].RecentlyOOMKilled)
+
+	// Native sidecar, OOMKilled without metrics
+	ratings := memory.Workloads[1]
+	assert.Equal("ratings-v1", ratings.Workload)
+	assert.True(ratings.UnderPressure)
+	proxy := ratings.Proxies[0]
+	assert.Nil(proxy.Usage)
+	assert.Equal(float64(256*1024*1024), *proxy.Limit)
+	assert.False(proxy.NearLimit)
+	assert.Equal(int32(4), proxy.Restarts)
+	assert.Equal(time.Date(2022, 1, 10, 11, 58, 0, 0, time.UTC), proxy.LastOOMKilled.UTC())
+	assert.True(proxy.RecentlyOOMKilled)
+
+	reviews := memory.Workloads[2]
+	assert.Equal("reviews-v1", reviews.Workload)
+	assert.True(reviews.UnderPressure)
+	// Sorted by pod
+	assert.Len(reviews.Proxies, 2)
+	pressured, relaxed := reviews.Proxies[0], reviews.Proxies[1]
+	assert.Equal("reviews-v1-7f99cc4496-gdxfn", pressured.Pod)
+	assert.InDelta(0.9375, *pressured.UsageRatio, 0.0001)
+	assert.True(pressured.NearLimit)
+	assert.Equal(int32(2), pressured.Restarts)
+	assert.True(pressured.RecentlyOOMKilled)
+	assert.Equal("reviews-v1-7f99cc4496-x7k2p", relaxed.Pod)
+	assert.InDelta(0.3125, *relaxed.UsageRatio, 0.0001)
+	assert.False(relaxed.NearLimit)
+	assert.Nil(relaxed.LastOOMKilled)
+	assert.False(relaxed.RecentlyOOMKilled)
+}
+
+func TestNamespaceProxyMemoryOOMKillExpires(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	pods := loadPods(t, "../tests/data/proxy/memory_pods.yaml")
+	ws := models.Workloads{fakeProxyWorkload("ratings-v1", "ratings-v1-b6994bb9-rqk5n")}
+
+	// Two hours after the last OOMKill
+	memory := buildNamespaceProxyMemory("bookinfo", ws, pods, model.Vector{}, time.Date(2022, 1, 10, 14, 0, 0, 0, time.UTC))
+
+	assert.Len(memory.Workloads, 1)
+	assert.False(memory.Workloads[0].UnderPressure)
+	assert.NotNil(memory.Workloads[0].Proxies[0].LastOOMKilled)
+	assert.False(memory.Workloads[0].Proxies[0].RecentlyOOMKilled)
+}
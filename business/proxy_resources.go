@@ -0,0 +1,147 @@
+package business
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/prometheus/common/model"
+	core_v1 "k8s.io/api/core/v1"
+
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/prometheus/internalmetrics"
+)
+
+// GetNamespaceProxyResources returns the requests, limits and observed usage of the istio-proxy containers of the
+// workloads of the namespace, to right-size the sidecars. The usage comes from the cAdvisor metrics scraped by Prometheus.
+func (in *WorkloadService) GetNamespaceProxyResources(namespace, rateInterval string, queryTime time.Time) (*models.NamespaceProxyResources, error) {
+	var err error
+	promtimer := internalmetrics.GetGoFunctionMetric("business", "WorkloadService", "GetNamespaceProxyResources")
+	defer promtimer.ObserveNow(&err)
+
+	// Check if user has access to the namespace (RBAC) in cache scenarios and/or
+	// if namespace is accessible from Kiali (Deployment.AccessibleNamespaces)
+	if _, err = in.businessLayer.Namespace.GetNamespace(namespace); err != nil {
+		return nil, err
+	}
+
+	var ws models.Workloads
+	var pods []core_v1.Pod
+	var cpuUsage, memoryUsage model.Vector
+
+	wg := sync.WaitGroup{}
+	wg.Add(3)
+	errChan := make(chan error, 3)
+
+	go func() {
+		defer wg.Done()
+		var err2 error
+		ws, err2 = fetchWorkloads(in.businessLayer, namespace, "")
+		if err2 != nil {
+			errChan <- err2
+		}
+	}()
+
+	go func() {
+		defer wg.Done()
+		var err2 error
+		if IsNamespaceCached(namespace) {
+			pods, err2 = kialiCache.GetPods(namespace, "")
+		} else {
+			pods, err2 = in.k8s.GetPods(namespace, "")
+		}
+		if err2 != nil {
+			errChan <- err2
+		}
+	}()
+
+	go func() {
+		defer wg.Done()
+		labels := fmt.Sprintf(`{namespace="%s",container="%s"}`, namespace, models.IstioProxyContainer)
+		var err2 error
+		cpuUsage, err2 = in.prom.FetchRateValues("container_cpu_usage_seconds_total", labels, "pod", rateInterval, queryTime)
+		if err2 != nil {
+			errChan <- err2
+			return
+		}
+		memoryUsage, err2 = in.prom.FetchValues("container_memory_working_set_bytes", labels, "pod", queryTime)
+		if err2 != nil {
+			errChan <- err2
+		}
+	}()
+
+	wg.Wait()
+	if len(errChan) != 0 {
+		err = <-errChan
+		return nil, err
+	}
+
+	return buildNamespaceProxyResources(namespace, ws, pods, cpuUsage, memoryUsage), nil
+}
+
+// buildNamespaceProxyResources sums the resources of the istio-proxy containers per workload, and for the namespace.
+// The pods without metrics count in the requests and limits, but not in the usage.
+func buildNamespaceProxyResources(namespace string, ws models.Workloads, pods []core_v1.Pod, cpuUsage, memoryUsage model.Vector) *models.NamespaceProxyResources {
+	resources := &models.NamespaceProxyResources{
+		Namespace: namespace,
+		Workloads: []models.WorkloadProxyResources{},
+	}
+
+	podsByName := make(map[string]core_v1.Pod, len(pods))
+	for _, pod := range pods {
+		podsByName[pod.Name] = pod
+	}
+	cpuByPod := podValues(cpuUsage)
+	memoryByPod := podValues(memoryUsage)
+
+	for _, w := range ws {
+		wr := models.WorkloadProxyResources{Workload: w.Name}
+		for _, p := range w.Pods {
+			pod, found := podsByName[p.Name]
+			if !found {
+				continue
+			}
+			proxy, found := proxyContainer(pod)
+			if !found {
+				continue
+			}
+			wr.AddContainer(proxy)
+			wr.AddUsage(cpuByPod[p.Name], memoryByPod[p.Name])
+		}
+		if wr.Proxies == 0 {
+			continue
+		}
+		resources.Total.Add(wr.ProxyResources)
+		resources.Workloads = append(resources.Workloads, wr)
+	}
+	sort.Slice(resources.Workloads, func(i, j int) bool {
+		return resources.Workloads[i].Workload < resources.Workloads[j].Workload
+	})
+	return resources
+}
+
+// proxyContainer returns the istio-proxy container of the pod, which is an init container when injected as a native sidecar
+func proxyContainer(pod core_v1.Pod) (core_v1.Container, bool) {
+	for _, containers := range [][]core_v1.Container{pod.Spec.Containers, pod.Spec.InitContainers} {
+		for _, c := range containers {
+			if c.Name == models.IstioProxyContainer {
+				return c, true
+			}
+		}
+	}
+	return core_v1.Container{}, false
+}
+
+func podValues(vector model.Vector) map[string]*float64 {
+	byPod := make(map[string]*float64, len(vector))
+	for _, sample := range vector {
+		value := float64(sample.Value)
+		if math.IsNaN(value) {
+			continue
+		}
+		byPod[string(sample.Metric["pod"])] = &value
+	}
+	return byPod
+}
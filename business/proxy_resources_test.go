@@ -0,0 +1,129 @@
+package business
+
+import (
+	"testing"
+
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/assert"
+	core_v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/models"
+)
+
+func TestNamespaceProxyResources(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	ws := models.Workloads{
+		fakeProxyWorkload("reviews-v1", "reviews-v1-1", "reviews-v1-2"),
+		fakeProxyWorkload("details-v1", "details-v1-1"),
+		fakeProxyWorkload("mongodb", "mongodb-1"),
+	}
+	pods := []core_v1.Pod{
+		fakeProxyPod("reviews-v1-1", true, "100m", "128Mi", "2", "1Gi"),
+		fakeProxyPod("reviews-v1-2", true, "100m", "128Mi", "2", "1Gi"),
+		fakeProxyPod("details-v1-1", true, "50m", "64Mi", "", ""),
+		// Not in the mesh
+		fakeProxyPod("mongodb-1", false, "", "", "", ""),
+	}
+	cpuUsage := model.Vector{
+		&model.Sample{Metric: model.Metric{"pod": "reviews-v1-1"}, Value: 0.02},
+		&model.Sample{Metric: model.Metric{"pod": "reviews-v1-2"}, Value: 0.03},
+	}
+	memoryUsage := model.Vector{
+		&model.Sample{Metric: model.Metric{"pod": "reviews-v1-1"}, Value: 40 * 1024 * 1024},
+		&model.Sample{Metric: model.Metric{"pod": "reviews-v1-2"}, Value: 60 * 1024 * 1024},
+	}
+
+	resources := buildNamespaceProxyResources("bookinfo", ws, pods, cpuUsage, memoryUsage)
+
+	assert.Equal("bookinfo", resources.Namespace)
+	assert.Len(resources.Workloads, 2)
+
+	// Sorted by name
+	details := resources.Workloads[0]
+	assert.Equal("details-v1", details.Workload)
+	assert.Equal(1, details.Proxies)
+	assert.InDelta(0.05, details.CpuRequests, 0.0001)
+	assert.Equal(float64(64*1024*1024), details.MemoryRequests)
+	assert.Zero(details.CpuLimits)
+	assert.Equal(1, details.Unlimited)
+	// Without metrics
+	assert.Nil(details.CpuUsage)
+	assert.Nil(details.MemoryUsage)
+
+	reviews := resources.Workloads[1]
+	assert.Equal("reviews-v1", reviews.Workload)
+	assert.Equal(2, reviews.Proxies)
+	assert.InDelta(0.2, reviews.CpuRequests, 0.0001)
+	assert.Equal(float64(4), reviews.CpuLimits)
+	assert.Equal(float64(2*1024*1024*1024), reviews.MemoryLimits)
+	assert.Zero(reviews.Unlimited)
+	assert.InDelta(0.05, *reviews.CpuUsage, 0.0001)
+	assert.Equal(float64(100*1024*1024), *reviews.MemoryUsage)
+
+	total := resources.Total
+	assert.Equal(3, total.Proxies)
+	assert.InDelta(0.25, total.CpuRequests, 0.0001)
+	assert.Equal(float64(320*1024*1024), total.MemoryRequests)
+	assert.Equal(1, total.Unlimited)
+	assert.InDelta(0.05, *total.CpuUsage, 0.0001)
+	assert.Equal(float64(100*1024*1024), *total.MemoryUsage)
+}
+
+func TestNamespaceProxyResourcesWithoutMetrics(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	ws := models.Workloads{fakeProxyWorkload("reviews-v1", "reviews-v1-1")}
+	pods := []core_v1.Pod{fakeProxyPod("reviews-v1-1", true, "100m", "128Mi", "2", "1Gi")}
+
+	resources := buildNamespaceProxyResources("bookinfo", ws, pods, model.Vector{}, model.Vector{})
+
+	assert.Len(resources.Workloads, 1)
+	assert.Equal(1, resources.Total.Proxies)
+	assert.Nil(resources.Total.CpuUsage)
+	assert.Nil(resources.Total.MemoryUsage)
+}
+
+func fakeProxyWorkload(name string, pods ...string) *models.Workload {
+	w := &models.Workload{}
+	w.Name = name
+	for _, p := range pods {
+		w.Pods = append(w.Pods, &models.Pod{Name: p})
+	}
+	return w
+}
+
+func fakeProxyPod(name string, injected bool, cpuRequest, memoryRequest, cpuLimit, memoryLimit string) core_v1.Pod {
+	pod := core_v1.Pod{
+		ObjectMeta: meta_v1.ObjectMeta{Name: name, Namespace: "bookinfo"},
+		Spec: core_v1.PodSpec{
+			Containers: []core_v1.Container{{Name: "app"}},
+		},
+	}
+	if !injected {
+		return pod
+	}
+	proxy := core_v1.Container{
+		Name: "istio-proxy",
+		Resources: core_v1.ResourceRequirements{
+			Requests: core_v1.ResourceList{
+				core_v1.ResourceCPU:    resource.MustParse(cpuRequest),
+				core_v1.ResourceMemory: resource.MustParse(memoryRequest),
+			},
+			Limits: core_v1.ResourceList{},
+		},
+	}
+	if cpuLimit != "" {
+		proxy.Resources.Limits[core_v1.ResourceCPU] = resource.MustParse(cpuLimit)
+	}
+	if memoryLimit != "" {
+		proxy.Resources.Limits[core_v1.ResourceMemory] = resource.MustParse(memoryLimit)
+	}
+	pod.Spec.Containers = append(pod.Spec.Containers, proxy)
+	return pod
+}
@@ -1,6 +1,9 @@
 package business
 
 import (
+	"sort"
+	"strings"
+
 	"k8s.io/client-go/tools/clientcmd/api"
 
 	"github.com/kiali/kiali/kubernetes"
@@ -35,6 +38,51 @@ func (in *ProxyStatus) GetPodProxyStatus(ns, pod string) (*kubernetes.ProxyStatu
 	return kialiCache.GetPodProxyStatus(ns, pod), nil
 }
 
+// GetNamespaceProxyStatus counts the proxies of the namespace by sync status, and lists the ones not synced.
+// The sync status is always fetched from istiod, the cache is refreshed with it.
+func (in *ProxyStatus) GetNamespaceProxyStatus(namespace string) (*models.NamespaceProxyStatus, error) {
+	var err error
+	promtimer := internalmetrics.GetGoFunctionMetric("business", "ProxyStatus", "GetNamespaceProxyStatus")
+	defer promtimer.ObserveNow(&err)
+
+	// Check if user has access to the namespace (RBAC) in cache scenarios and/or
+	// if namespace is accessible from Kiali (Deployment.AccessibleNamespaces)
+	if _, err = in.businessLayer.Namespace.GetNamespace(namespace); err != nil {
+		return nil, err
+	}
+
+	var proxyStatus []*kubernetes.ProxyStatus
+	if proxyStatus, err = in.k8s.GetProxyStatus(); err != nil {
+		if proxyStatus, err = in.getProxyStatusUsingKialiSA(); err != nil {
+			return nil, err
+		}
+	}
+	if kialiCache != nil {
+		kialiCache.SetProxyStatus(proxyStatus)
+	}
+
+	return buildNamespaceProxyStatus(namespace, proxyStatus), nil
+}
+
+func buildNamespaceProxyStatus(namespace string, proxyStatus []*kubernetes.ProxyStatus) *models.NamespaceProxyStatus {
+	summary := &models.NamespaceProxyStatus{Namespace: namespace, OutOfSync: []models.PodProxyStatus{}}
+	for _, ps := range proxyStatus {
+		if ps == nil {
+			continue
+		}
+		// Expected format <pod-name>.<namespace>
+		podId := strings.Split(ps.ProxyID, ".")
+		if len(podId) != 2 || podId[1] != namespace {
+			continue
+		}
+		summary.Add(podId[0], *castProxyStatus(ps))
+	}
+	sort.Slice(summary.OutOfSync, func(i, j int) bool {
+		return summary.OutOfSync[i].Pod < summary.OutOfSync[j].Pod
+	})
+	return summary
+}
+
 func (in *ProxyStatus) getProxyStatusUsingKialiSA() ([]*kubernetes.ProxyStatus, error) {
 	clientFactory, err := kubernetes.GetClientFactory()
 	if err != nil {
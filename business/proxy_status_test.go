@@ -0,0 +1,88 @@
+package business
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	core_v1 "k8s.io/api/core/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/kubernetes/kubetest"
+	"github.com/kiali/kiali/models"
+)
+
+func TestGetNamespaceProxyStatus(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	k8s := new(kubetest.K8SClientMock)
+	k8s.On("IsOpenShift").Return(false)
+	k8s.On("GetNamespace", "bookinfo").Return(&core_v1.Namespace{ObjectMeta: meta_v1.ObjectMeta{Name: "bookinfo"}}, nil)
+	k8s.On("GetProxyStatus").Return([]*kubernetes.ProxyStatus{
+		fakeProxyStatus("productpage-v1-6b746f74dc-9stp2.bookinfo", "1", "1"),
+		fakeProxyStatus("reviews-v2-7bf8c9648f-bcqjl.bookinfo", "2", "1"),
+		fakeProxyStatus("details-v1-5974b67c8-wclnd.bookinfo", "1", "1"),
+		fakeProxyStatus("ratings-v1-b6994bb9-gl4nz.bookinfo", "", ""),
+		fakeProxyStatus("reviews-v1-545db77b95-hkh9p.bookinfo", "2", ""),
+		fakeProxyStatus("istio-ingressgateway-5d5b9f7bb-8xk2p.istio-system", "2", "1"),
+		nil,
+	}, nil)
+
+	svc := NewWithBackends(k8s, nil, nil).ProxyStatus
+	status, err := svc.GetNamespaceProxyStatus("bookinfo")
+	assert.NoError(err)
+
+	assert.Equal("bookinfo", status.Namespace)
+	assert.Equal(2, status.Synced)
+	assert.Equal(2, status.Stale)
+	assert.Equal(1, status.NotSent)
+
+	assert.Len(status.OutOfSync, 3)
+	assert.Equal("ratings-v1-b6994bb9-gl4nz", status.OutOfSync[0].Pod)
+	assert.Equal(models.ProxyNotSent, status.OutOfSync[0].Status)
+	assert.Equal("reviews-v1-545db77b95-hkh9p", status.OutOfSync[1].Pod)
+	assert.Equal(models.ProxyStale, status.OutOfSync[1].Status)
+	assert.Equal("Stale (Never Acknowledged)", status.OutOfSync[1].ProxyStatus.RDS)
+	assert.Equal("reviews-v2-7bf8c9648f-bcqjl", status.OutOfSync[2].Pod)
+	assert.Equal(models.ProxyStale, status.OutOfSync[2].Status)
+	assert.Equal("Synced", status.OutOfSync[2].ProxyStatus.CDS)
+	assert.Equal("Stale", status.OutOfSync[2].ProxyStatus.RDS)
+}
+
+func TestGetNamespaceProxyStatusWithoutProxies(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	k8s := new(kubetest.K8SClientMock)
+	k8s.On("IsOpenShift").Return(false)
+	k8s.On("GetNamespace", "empty").Return(&core_v1.Namespace{ObjectMeta: meta_v1.ObjectMeta{Name: "empty"}}, nil)
+	k8s.On("GetProxyStatus").Return([]*kubernetes.ProxyStatus{
+		fakeProxyStatus("productpage-v1-6b746f74dc-9stp2.bookinfo", "1", "1"),
+	}, nil)
+
+	status, err := NewWithBackends(k8s, nil, nil).ProxyStatus.GetNamespaceProxyStatus("empty")
+	assert.NoError(err)
+	assert.Equal(0, status.Synced+status.Stale+status.NotSent)
+	assert.NotNil(status.OutOfSync)
+	assert.Empty(status.OutOfSync)
+}
+
+// fakeProxyStatus returns the status of a proxy whose clusters, listeners and endpoints are synced, and whose routes
+// are sent and acked with the given nonces
+func fakeProxyStatus(proxyID, routeSent, routeAcked string) *kubernetes.ProxyStatus {
+	return &kubernetes.ProxyStatus{
+		SyncStatus: kubernetes.SyncStatus{
+			ProxyID:       proxyID,
+			ClusterSent:   "1",
+			ClusterAcked:  "1",
+			ListenerSent:  "1",
+			ListenerAcked: "1",
+			EndpointSent:  "1",
+			EndpointAcked: "1",
+			RouteSent:     routeSent,
+			RouteAcked:    routeAcked,
+		},
+	}
+}
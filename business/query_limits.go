@@ -0,0 +1,74 @@
+package business
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"k8s.io/apimachinery/pkg/api/errors"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/log"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/prometheus"
+)
+
+// limitMetricsQuery enforces the configured caps of the time range and of the step of a metrics query. A query
+// exceeding a cap is clamped to it when configured so, otherwise it is rejected with a BadRequest error.
+func limitMetricsQuery(q *prometheus.RangeQuery) error {
+	limits := config.Get().QueryLimits
+	start, err := limitQueryRange(q.Start, q.End, limits)
+	if err != nil {
+		return err
+	}
+	q.Start = start
+
+	if limits.MinStep == "" || q.Step <= 0 {
+		return nil
+	}
+	minStep, err := model.ParseDuration(limits.MinStep)
+	if err != nil {
+		return fmt.Errorf("invalid minimum step [%s] of the query limits: %v", limits.MinStep, err)
+	}
+	if q.Step >= time.Duration(minStep) {
+		return nil
+	}
+	if !limits.Clamp {
+		return errors.NewBadRequest(fmt.Sprintf("The step [%s] is below the minimum allowed step [%s]", model.Duration(q.Step), limits.MinStep))
+	}
+	log.Debugf("Clamping the step [%s] of a metrics query to the minimum allowed step [%s]", model.Duration(q.Step), limits.MinStep)
+	q.Step = time.Duration(minStep)
+	return nil
+}
+
+// limitTracingQuery enforces the configured cap of the time range of a traces query, clamping or rejecting it
+// like limitMetricsQuery
+func limitTracingQuery(q *models.TracingQuery) error {
+	start, err := limitQueryRange(q.Start, q.End, config.Get().QueryLimits)
+	if err != nil {
+		return err
+	}
+	q.Start = start
+	return nil
+}
+
+// limitQueryRange returns the start of the time range, moved forward to the maximum allowed range before the end
+// when the range is clamped
+func limitQueryRange(start, end time.Time, limits config.QueryLimitsConfig) (time.Time, error) {
+	if limits.MaxRange == "" {
+		return start, nil
+	}
+	maxRange, err := model.ParseDuration(limits.MaxRange)
+	if err != nil {
+		return start, fmt.Errorf("invalid maximum range [%s] of the query limits: %v", limits.MaxRange, err)
+	}
+	queryRange := end.Sub(start)
+	if queryRange <= time.Duration(maxRange) {
+		return start, nil
+	}
+	if !limits.Clamp {
+		return start, errors.NewBadRequest(fmt.Sprintf("The time range [%s] exceeds the maximum allowed range [%s]", model.Duration(queryRange), limits.MaxRange))
+	}
+	log.Debugf("Clamping the time range [%s] of a query to the maximum allowed range [%s]", model.Duration(queryRange), limits.MaxRange)
+	return end.Add(-time.Duration(maxRange)), nil
+}
@@ -0,0 +1,110 @@
+package business
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/api/errors"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/prometheus"
+)
+
+var queryLimitsEnd = time.Date(2017, 01, 15, 0, 0, 0, 0, time.UTC)
+
+func TestLimitMetricsQueryRejects(t *testing.T) {
+	assert := assert.New(t)
+	conf := config.NewConfig()
+	conf.QueryLimits.MinStep = "5s"
+	config.Set(conf)
+
+	q := prometheus.RangeQuery{}
+	q.End = queryLimitsEnd
+	q.Start = q.End.Add(-90 * 24 * time.Hour)
+	q.Step = time.Minute
+	err := limitMetricsQuery(&q)
+	assert.True(errors.IsBadRequest(err))
+	assert.Equal("The time range [90d] exceeds the maximum allowed range [30d]", err.Error())
+
+	q.Start = q.End.Add(-time.Hour)
+	q.Step = time.Second
+	err = limitMetricsQuery(&q)
+	assert.True(errors.IsBadRequest(err))
+	assert.Equal("The step [1s] is below the minimum allowed step [5s]", err.Error())
+
+	// Within the limits, the query is unchanged
+	q.Step = 15 * time.Second
+	assert.NoError(limitMetricsQuery(&q))
+	assert.Equal(queryLimitsEnd.Add(-time.Hour), q.Start)
+	assert.Equal(15*time.Second, q.Step)
+}
+
+func TestLimitMetricsQueryClamps(t *testing.T) {
+	assert := assert.New(t)
+	conf := config.NewConfig()
+	conf.QueryLimits.Clamp = true
+	conf.QueryLimits.MinStep = "5s"
+	config.Set(conf)
+
+	q := prometheus.RangeQuery{}
+	q.End = queryLimitsEnd
+	q.Start = q.End.Add(-90 * 24 * time.Hour)
+	q.Step = time.Second
+	assert.NoError(limitMetricsQuery(&q))
+	assert.Equal(queryLimitsEnd.Add(-30*24*time.Hour), q.Start)
+	assert.Equal(queryLimitsEnd, q.End)
+	assert.Equal(5*time.Second, q.Step)
+}
+
+func TestLimitQueryDisabled(t *testing.T) {
+	assert := assert.New(t)
+	conf := config.NewConfig()
+	conf.QueryLimits.MaxRange = ""
+	conf.QueryLimits.MinStep = ""
+	config.Set(conf)
+
+	q := prometheus.RangeQuery{}
+	q.End = queryLimitsEnd
+	q.Start = q.End.Add(-365 * 24 * time.Hour)
+	q.Step = time.Second
+	assert.NoError(limitMetricsQuery(&q))
+	assert.Equal(queryLimitsEnd.Add(-365*24*time.Hour), q.Start)
+	assert.Equal(time.Second, q.Step)
+}
+
+func TestLimitTracingQuery(t *testing.T) {
+	assert := assert.New(t)
+	conf := config.NewConfig()
+	conf.QueryLimits.MaxRange = "1d"
+	config.Set(conf)
+
+	q := models.TracingQuery{Start: queryLimitsEnd.Add(-7 * 24 * time.Hour), End: queryLimitsEnd}
+	err := limitTracingQuery(&q)
+	assert.True(errors.IsBadRequest(err))
+	assert.Equal("The time range [1w] exceeds the maximum allowed range [1d]", err.Error())
+
+	conf.QueryLimits.Clamp = true
+	config.Set(conf)
+	assert.NoError(limitTracingQuery(&q))
+	assert.Equal(queryLimitsEnd.Add(-24*time.Hour), q.Start)
+}
+
+func TestGetMetricsRejectsQueryOverLimits(t *testing.T) {
+	assert := assert.New(t)
+	srv, api, err := setupMocked()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	q := models.IstioMetricsQuery{Namespace: "bookinfo", Service: "productpage"}
+	q.FillDefaults()
+	q.Start = q.End.Add(-60 * 24 * time.Hour)
+
+	_, err = srv.GetMetrics(q, nil)
+	assert.True(errors.IsBadRequest(err))
+	// Prometheus is not queried
+	api.AssertNotCalled(t, "QueryRange")
+}
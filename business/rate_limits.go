@@ -0,0 +1,95 @@
+package business
+
+import (
+	"sort"
+	"sync"
+
+	core_v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/prometheus/internalmetrics"
+)
+
+// GetRateLimits returns the local and global rate limits configured by EnvoyFilters for the workloads of the namespace,
+// with the services they apply to. The EnvoyFilters of the root namespace are considered, as they apply to the whole mesh.
+func (in *IstioValidationsService) GetRateLimits(namespace string) (models.RateLimits, error) {
+	var err error
+	promtimer := internalmetrics.GetGoFunctionMetric("business", "IstioValidationsService", "GetRateLimits")
+	defer promtimer.ObserveNow(&err)
+
+	// Check if user has access to the namespace (RBAC) in cache scenarios and/or
+	// if namespace is accessible from Kiali (Deployment.AccessibleNamespaces)
+	if _, err = in.businessLayer.Namespace.GetNamespace(namespace); err != nil {
+		return models.RateLimits{}, err
+	}
+
+	var istioDetails kubernetes.IstioDetails
+	var services []core_v1.Service
+	var workloads models.WorkloadList
+
+	wg := sync.WaitGroup{}
+	errChan := make(chan error, 1)
+
+	wg.Add(3)
+	go in.fetchDetails(&istioDetails, namespace, errChan, &wg)
+	go in.fetchServices(&services, namespace, errChan, &wg)
+	go in.fetchWorkloads(&workloads, namespace, errChan, &wg)
+	wg.Wait()
+
+	close(errChan)
+	for e := range errChan {
+		if e != nil { // Check that default value wasn't returned
+			err = e
+			return models.RateLimits{}, err
+		}
+	}
+
+	return buildRateLimits(namespace, append(istioDetails.EnvoyFilters, istioDetails.MeshEnvoyFilters...), services, workloads), nil
+}
+
+func buildRateLimits(namespace string, envoyFilters []kubernetes.IstioObject, services []core_v1.Service, workloads models.WorkloadList) models.RateLimits {
+	rateLimits := models.RateLimits{
+		Namespace:         namespace,
+		RateLimits:        []models.RateLimit{},
+		UnlimitedServices: []string{},
+	}
+
+	limited := map[string]bool{}
+	for _, envoyFilter := range envoyFilters {
+		for _, rateLimit := range models.ParseRateLimits(envoyFilter) {
+			selector := labels.SelectorFromSet(rateLimit.WorkloadSelector)
+			for _, svc := range services {
+				if len(svc.Spec.Selector) == 0 {
+					continue
+				}
+				svcSelector := labels.SelectorFromSet(svc.Spec.Selector)
+				for _, w := range workloads.Workloads {
+					if selector.Matches(labels.Set(w.Labels)) && svcSelector.Matches(labels.Set(w.Labels)) {
+						rateLimit.Services = append(rateLimit.Services, svc.Name)
+						limited[svc.Name] = true
+						break
+					}
+				}
+			}
+			sort.Strings(rateLimit.Services)
+			rateLimits.RateLimits = append(rateLimits.RateLimits, rateLimit)
+		}
+	}
+	sort.SliceStable(rateLimits.RateLimits, func(i, j int) bool {
+		ri, rj := rateLimits.RateLimits[i], rateLimits.RateLimits[j]
+		if ri.Namespace != rj.Namespace {
+			return ri.Namespace < rj.Namespace
+		}
+		return ri.EnvoyFilter < rj.EnvoyFilter
+	})
+
+	for _, svc := range services {
+		if !limited[svc.Name] {
+			rateLimits.UnlimitedServices = append(rateLimits.UnlimitedServices, svc.Name)
+		}
+	}
+	sort.Strings(rateLimits.UnlimitedServices)
+	return rateLimits
+}
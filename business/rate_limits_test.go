@@ -0,0 +1,112 @@
+package business
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	core_v1 "k8s.io/api/core/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/tests/data"
+)
+
+func TestBuildRateLimits(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	envoyFilters := []kubernetes.IstioObject{}
+	for _, file := range []string{"local_rate_limit.yaml", "global_rate_limit.yaml"} {
+		loader := &data.YamlFixtureLoader{Filename: "../tests/data/validations/envoyfilters/" + file}
+		assert.NoError(loader.Load())
+		envoyFilters = append(envoyFilters, loader.GetResources("EnvoyFilter")...)
+	}
+
+	services := []core_v1.Service{
+		fakeSelectorService("productpage"),
+		fakeSelectorService("reviews"),
+		fakeSelectorService("ratings"),
+		fakeSelectorService("details"),
+	}
+	workloads := models.WorkloadList{Workloads: []models.WorkloadListItem{
+		{Name: "productpage-v1", Labels: map[string]string{"app": "productpage", "version": "v1"}},
+		{Name: "reviews-v1", Labels: map[string]string{"app": "reviews", "version": "v1"}},
+		{Name: "reviews-v2", Labels: map[string]string{"app": "reviews", "version": "v2"}},
+		{Name: "ratings-v1", Labels: map[string]string{"app": "ratings", "version": "v1"}},
+		{Name: "details-v1", Labels: map[string]string{"app": "details", "version": "v1"}},
+	}}
+
+	rateLimits := buildRateLimits("bookinfo", envoyFilters, services, workloads)
+
+	assert.Equal("bookinfo", rateLimits.Namespace)
+	assert.Equal([]string{"details"}, rateLimits.UnlimitedServices)
+	assert.Len(rateLimits.RateLimits, 4)
+
+	productpage := rateLimits.RateLimits[0]
+	assert.Equal("productpage-local-ratelimit", productpage.EnvoyFilter)
+	assert.Equal(models.LocalRateLimit, productpage.Type)
+	assert.Equal("SIDECAR_INBOUND", productpage.Context)
+	assert.Equal([]string{"productpage"}, productpage.Services)
+	assert.Equal(int64(10), *productpage.MaxTokens)
+	assert.Equal(int64(10), *productpage.TokensPerFill)
+	assert.Equal("60s", productpage.FillInterval)
+
+	ratings := rateLimits.RateLimits[1]
+	assert.Equal("ratings-ratelimit", ratings.EnvoyFilter)
+	assert.Equal(models.GlobalRateLimit, ratings.Type)
+	assert.Equal([]string{"ratings"}, ratings.Services)
+	assert.Equal("ratings-ratelimit", ratings.Domain)
+	assert.Equal("outbound|8081||ratelimit.default.svc.cluster.local", ratings.RateLimitService)
+	assert.False(ratings.FailureModeDeny)
+
+	reviews := rateLimits.RateLimits[2]
+	assert.Equal("reviews-route-ratelimit", reviews.EnvoyFilter)
+	assert.Equal(models.LocalRateLimit, reviews.Type)
+	assert.Equal([]string{"reviews"}, reviews.Services)
+	assert.Equal(int64(50), *reviews.MaxTokens)
+	assert.Nil(reviews.TokensPerFill)
+	assert.Equal("1s", reviews.FillInterval)
+
+	// The gateway of the root namespace selects no workload of the namespace
+	gateway := rateLimits.RateLimits[3]
+	assert.Equal("ingressgateway-ratelimit", gateway.EnvoyFilter)
+	assert.Equal("istio-system", gateway.Namespace)
+	assert.Equal(models.GlobalRateLimit, gateway.Type)
+	assert.Equal("GATEWAY", gateway.Context)
+	assert.Equal(map[string]string{"istio": "ingressgateway"}, gateway.WorkloadSelector)
+	assert.Empty(gateway.Services)
+	assert.Equal("rate_limit_cluster", gateway.RateLimitService)
+	assert.True(gateway.FailureModeDeny)
+}
+
+func TestBuildRateLimitsWithoutSelector(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	loader := &data.YamlFixtureLoader{Filename: "../tests/data/validations/envoyfilters/invalid_local_rate_limit.yaml"}
+	assert.NoError(loader.Load())
+	envoyFilter := loader.GetFirstResource("EnvoyFilter")
+	delete(envoyFilter.GetSpec(), "workloadSelector")
+
+	rateLimits := buildRateLimits("bookinfo", []kubernetes.IstioObject{envoyFilter},
+		[]core_v1.Service{fakeSelectorService("details"), fakeSelectorService("reviews")},
+		models.WorkloadList{Workloads: []models.WorkloadListItem{
+			{Name: "details-v1", Labels: map[string]string{"app": "details"}},
+			{Name: "reviews-v1", Labels: map[string]string{"app": "reviews"}},
+		}})
+
+	assert.Len(rateLimits.RateLimits, 1)
+	assert.Nil(rateLimits.RateLimits[0].WorkloadSelector)
+	assert.Equal([]string{"details", "reviews"}, rateLimits.RateLimits[0].Services)
+	assert.Nil(rateLimits.RateLimits[0].MaxTokens)
+	assert.Empty(rateLimits.UnlimitedServices)
+}
+
+func fakeSelectorService(app string) core_v1.Service {
+	return core_v1.Service{
+		ObjectMeta: meta_v1.ObjectMeta{Name: app, Namespace: "bookinfo"},
+		Spec:       core_v1.ServiceSpec{Selector: map[string]string{"app": app}},
+	}
+}
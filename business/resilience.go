@@ -0,0 +1,103 @@
+package business
+
+import (
+	"fmt"
+
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/prometheus/internalmetrics"
+)
+
+// GetResilienceDefaults returns the timeout and retry behavior inherited by the HTTP routes which don't set them.
+// Only the retries and the connect timeout have a mesh setting, the timeout of the requests is disabled by Istio.
+func (in *MeshService) GetResilienceDefaults() (models.MeshResilienceDefaults, error) {
+	meshConfig, err := in.GetEffectiveMeshConfig()
+	if err != nil {
+		return models.MeshResilienceDefaults{}, err
+	}
+	return models.NewMeshResilienceDefaults(meshConfig), nil
+}
+
+// GetResilienceConfig returns the timeout and retries of the HTTP routes of the VirtualServices to the service,
+// telling the settings of the routes from the ones inherited from the mesh.
+func (in *SvcService) GetResilienceConfig(namespace, service string) (*models.ServiceResilienceConfig, error) {
+	var err error
+	promtimer := internalmetrics.GetGoFunctionMetric("business", "SvcService", "GetResilienceConfig")
+	defer promtimer.ObserveNow(&err)
+
+	// Check if user has access to the namespace (RBAC) in cache scenarios and/or
+	// if namespace is accessible from Kiali (Deployment.AccessibleNamespaces)
+	if _, err = in.businessLayer.Namespace.GetNamespace(namespace); err != nil {
+		return nil, err
+	}
+	if _, err = in.getService(namespace, service); err != nil {
+		return nil, err
+	}
+
+	defaults, err := in.businessLayer.Mesh.GetResilienceDefaults()
+	if err != nil {
+		return nil, err
+	}
+
+	var vs []kubernetes.IstioObject
+	if IsResourceCached(namespace, kubernetes.VirtualServices) {
+		vs, err = kialiCache.GetIstioObjects(namespace, kubernetes.VirtualServices, "")
+	} else {
+		vs, err = in.k8s.GetIstioObjects(namespace, kubernetes.VirtualServices, "")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return buildResilienceConfig(namespace, service, defaults, kubernetes.FilterVirtualServices(vs, namespace, service)), nil
+}
+
+// buildResilienceConfig resolves the timeout and retries of the HTTP routes of the VirtualServices with a
+// destination to the service. A route without them inherits the defaults.
+func buildResilienceConfig(namespace, service string, defaults models.MeshResilienceDefaults, virtualServices []kubernetes.IstioObject) *models.ServiceResilienceConfig {
+	resilienceConfig := &models.ServiceResilienceConfig{
+		Namespace: namespace,
+		Service:   service,
+		Defaults:  defaults,
+		Routes:    []models.RouteResilience{},
+	}
+	for _, vs := range virtualServices {
+		routes, _ := vs.GetSpec()["http"].([]interface{})
+		for i, r := range routes {
+			route, _ := r.(map[string]interface{})
+			if !routesToService(route, namespace, service) {
+				continue
+			}
+			resilience := models.RouteResilience{
+				VirtualService: vs.GetObjectMeta().Name,
+				Route:          fmt.Sprintf("http[%d]", i),
+				Timeout:        defaults.Timeout,
+				Retries:        defaults.Retries,
+			}
+			if name, _ := route["name"].(string); name != "" {
+				resilience.Route = name
+			}
+			if timeout, ok := route["timeout"]; ok {
+				resilience.Timeout = models.ResilienceTimeout{Value: fmt.Sprintf("%v", timeout), Source: models.ResilienceSourceRoute}
+			}
+			if retries, ok := route["retries"].(map[string]interface{}); ok {
+				resilience.Retries = models.ParseRetries(retries, models.ResilienceSourceRoute)
+			}
+			resilienceConfig.Routes = append(resilienceConfig.Routes, resilience)
+		}
+	}
+	return resilienceConfig
+}
+
+// routesToService tells if one of the destinations of the route is the service
+func routesToService(route map[string]interface{}, namespace, service string) bool {
+	destinations, _ := route["route"].([]interface{})
+	for _, d := range destinations {
+		destination, _ := d.(map[string]interface{})
+		target, _ := destination["destination"].(map[string]interface{})
+		if host, _ := target["host"].(string); kubernetes.FilterByHost(host, service, namespace) {
+			return true
+		}
+	}
+	return false
+}
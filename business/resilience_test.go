@@ -0,0 +1,101 @@
+package business
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	core_v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/kubernetes/kubetest"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/tests/data"
+)
+
+func TestGetResilienceConfigInheritedFromIstio(t *testing.T) {
+	assert := assert.New(t)
+
+	svc := resilienceTestPrep(t, "connectTimeout: 5s")
+	resilience, err := svc.GetResilienceConfig("bookinfo", "reviews")
+	assert.NoError(err)
+
+	// No defaultHttpRetryPolicy in the mesh config: the retries are the ones of Istio
+	defaultRetries := models.ResilienceRetries{
+		Attempts: 2,
+		RetryOn:  "connect-failure,refused-stream,unavailable,cancelled,retriable-status-codes",
+		Source:   models.ResilienceSourceIstioDefault,
+	}
+	assert.Equal(models.ResilienceTimeout{Source: models.ResilienceSourceIstioDefault}, resilience.Defaults.Timeout)
+	assert.Equal(defaultRetries, resilience.Defaults.Retries)
+	assert.Equal(models.ResilienceTimeout{Value: "5s", Source: models.ResilienceSourceMeshConfig}, resilience.Defaults.ConnectTimeout)
+
+	assert.Len(resilience.Routes, 3)
+
+	explicit := resilience.Routes[0]
+	assert.Equal("reviews", explicit.VirtualService)
+	assert.Equal("jason", explicit.Route)
+	assert.Equal(models.ResilienceTimeout{Value: "5s", Source: models.ResilienceSourceRoute}, explicit.Timeout)
+	assert.Equal(models.ResilienceRetries{Attempts: 3, PerTryTimeout: "2s", RetryOn: "5xx", Source: models.ResilienceSourceRoute}, explicit.Retries)
+
+	disabled := resilience.Routes[1]
+	assert.Equal("http[1]", disabled.Route)
+	assert.Equal(models.ResilienceTimeout{Source: models.ResilienceSourceIstioDefault}, disabled.Timeout)
+	assert.Equal(models.ResilienceRetries{Source: models.ResilienceSourceRoute}, disabled.Retries)
+
+	inherited := resilience.Routes[2]
+	assert.Equal("http[2]", inherited.Route)
+	assert.Equal(models.ResilienceTimeout{Source: models.ResilienceSourceIstioDefault}, inherited.Timeout)
+	assert.Equal(defaultRetries, inherited.Retries)
+}
+
+func TestGetResilienceConfigInheritedFromMeshConfig(t *testing.T) {
+	assert := assert.New(t)
+
+	svc := resilienceTestPrep(t, "defaultHttpRetryPolicy:\n  attempts: 4\n  retryOn: gateway-error")
+	resilience, err := svc.GetResilienceConfig("bookinfo", "reviews")
+	assert.NoError(err)
+
+	meshRetries := models.ResilienceRetries{Attempts: 4, RetryOn: "gateway-error", Source: models.ResilienceSourceMeshConfig}
+	assert.Equal(meshRetries, resilience.Defaults.Retries)
+	assert.Equal(models.ResilienceTimeout{Value: "10s", Source: models.ResilienceSourceMeshConfig}, resilience.Defaults.ConnectTimeout)
+
+	assert.Len(resilience.Routes, 3)
+	// The retries of the routes take precedence over the mesh ones
+	assert.Equal(models.ResilienceSourceRoute, resilience.Routes[0].Retries.Source)
+	assert.Equal(models.ResilienceSourceRoute, resilience.Routes[1].Retries.Source)
+	assert.Equal(meshRetries, resilience.Routes[2].Retries)
+	// The timeout has no mesh setting
+	assert.Equal(models.ResilienceSourceIstioDefault, resilience.Routes[2].Timeout.Source)
+}
+
+func TestGetResilienceConfigServiceNotFound(t *testing.T) {
+	assert := assert.New(t)
+
+	svc := resilienceTestPrep(t, "")
+	_, err := svc.GetResilienceConfig("bookinfo", "details")
+	assert.True(errors.IsNotFound(err))
+}
+
+func resilienceTestPrep(t *testing.T, mesh string) *SvcService {
+	config.Set(config.NewConfig())
+
+	loader := &data.YamlFixtureLoader{Filename: "../tests/data/routing/resilience.yaml"}
+	if err := loader.Load(); err != nil {
+		t.Fatal("Error loading test data.")
+	}
+
+	k8s := new(kubetest.K8SClientMock)
+	k8s.On("IsOpenShift").Return(false)
+	k8s.On("IsMaistraApi").Return(false)
+	k8s.On("GetNamespace", "bookinfo").Return(&core_v1.Namespace{}, nil)
+	k8s.On("GetService", "bookinfo", "reviews").Return(&core_v1.Service{}, nil)
+	k8s.On("GetService", "bookinfo", "details").Return(&core_v1.Service{}, errors.NewNotFound(schema.GroupResource{Resource: "services"}, "details"))
+	k8s.On("GetConfigMap", "istio-system", "istio").Return(fakeMeshConfigMap("istio", mesh), nil)
+	k8s.On("GetIstioObjects", "bookinfo", kubernetes.VirtualServices, "").Return(loader.GetResources("VirtualService"), nil)
+
+	businessLayer := NewWithBackends(k8s, nil, nil)
+	return &SvcService{k8s: k8s, businessLayer: businessLayer}
+}
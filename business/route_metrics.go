@@ -0,0 +1,258 @@
+package business
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/prometheus/common/model"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/prometheus/internalmetrics"
+)
+
+// routeNameLabel is not reported by the Istio standard telemetry, it's usually added through the Telemetry API from
+// the xds.route_name attribute: the name of the HTTP route of the VirtualService taken by the request
+const routeNameLabel = "route_name"
+
+// GetVirtualServiceRouteMetrics returns the request rate, the error rate and the latencies of the HTTP routes of a
+// VirtualService, reported by the proxies applying them. The traffic is mapped to a route by its route name, or by
+// its destination service when a single route of the VirtualService leads to it.
+func (in *IstioConfigService) GetVirtualServiceRouteMetrics(namespace, virtualService, rateInterval string, queryTime time.Time) (*models.VirtualServiceRouteMetrics, error) {
+	var err error
+	promtimer := internalmetrics.GetGoFunctionMetric("business", "IstioConfigService", "GetVirtualServiceRouteMetrics")
+	defer promtimer.ObserveNow(&err)
+
+	// Check if user has access to the namespace (RBAC) in cache scenarios and/or
+	// if namespace is accessible from Kiali (Deployment.AccessibleNamespaces)
+	if _, err = in.businessLayer.Namespace.GetNamespace(namespace); err != nil {
+		return nil, err
+	}
+	if in.businessLayer.Svc.prom == nil {
+		err = errors.New("the traffic of the routes is not available without Prometheus")
+		return nil, err
+	}
+	// The queries are restricted to the namespaces accessible to the user, the routes leading to any namespace
+	scope := newNamespaceScope(&in.businessLayer.Namespace, telemetryLabel("destination_service_namespace"))
+	prom := namespaceScopedClient{ClientInterface: in.businessLayer.Svc.prom, scope: scope}
+
+	var vs kubernetes.IstioObject
+	if vs, err = in.k8s.GetIstioObject(namespace, kubernetes.VirtualServices, virtualService); err != nil {
+		return nil, err
+	}
+	var namespaces models.Namespaces
+	if namespaces, err = in.businessLayer.Namespace.GetNamespaces(); err != nil {
+		return nil, err
+	}
+
+	routes := parseVirtualServiceRoutes(vs, namespaces.GetNames())
+	destinations := map[string]bool{}
+	for _, route := range routes {
+		for service := range route.services {
+			destinations[service] = true
+		}
+	}
+	services := make([]string, 0, len(destinations))
+	for service := range destinations {
+		services = append(services, service)
+	}
+	// Without destination, there is no traffic to look for
+	if len(services) == 0 {
+		return buildRouteMetrics(namespace, virtualService, rateInterval, routes, model.Vector{}, map[string]model.Vector{}), nil
+	}
+	sort.Strings(services)
+
+	// The hosts are matched literally: quoted in the regexp, whose backslashes are escaped in the PromQL string
+	hosts := make([]string, 0, len(services))
+	for _, service := range services {
+		hosts = append(hosts, strings.ReplaceAll(regexp.QuoteMeta(service), `\`, `\\`))
+	}
+	labels := fmt.Sprintf(`{%s="source",%s=~"%s"}`, telemetryLabel("reporter"), telemetryLabel("destination_service"), strings.Join(hosts, "|"))
+	seriesGrouping := routeNameLabel + "," + telemetryGrouping("destination_service")
+	var rates model.Vector
+	rates, err = prom.FetchRateValues(telemetryMetric("istio_requests_total"), labels, seriesGrouping+","+telemetryGrouping("response_code,grpc_response_status"), rateInterval, queryTime)
+	if err != nil {
+		return nil, err
+	}
+	var latencies map[string]model.Vector
+	latencies, err = prom.FetchHistogramValues(telemetryMetric("istio_request_duration_milliseconds"), labels, seriesGrouping, rateInterval, true, latencyQuantiles, queryTime)
+	if err != nil {
+		return nil, err
+	}
+	return buildRouteMetrics(namespace, virtualService, rateInterval, routes, rates, latencies), nil
+}
+
+// virtualServiceRoute is an HTTP route of a VirtualService with the FQDN of its destination services
+type virtualServiceRoute struct {
+	name     string
+	path     string
+	hosts    []string
+	services map[string]bool
+}
+
+func parseVirtualServiceRoutes(vs kubernetes.IstioObject, namespaces []string) []virtualServiceRoute {
+	clusterName := vs.GetObjectMeta().ClusterName
+	if clusterName == "" {
+		clusterName = config.Get().ExternalServices.Istio.IstioIdentityDomain
+	}
+
+	routes := []virtualServiceRoute{}
+	httpRoutes, _ := vs.GetSpec()["http"].([]interface{})
+	for i, r := range httpRoutes {
+		httpRoute, _ := r.(map[string]interface{})
+		route := virtualServiceRoute{
+			path:     fmt.Sprintf("spec/http[%d]", i),
+			hosts:    []string{},
+			services: map[string]bool{},
+		}
+		route.name, _ = httpRoute["name"].(string)
+		destinations, _ := httpRoute["route"].([]interface{})
+		for _, d := range destinations {
+			destinationWeight, _ := d.(map[string]interface{})
+			destination, _ := destinationWeight["destination"].(map[string]interface{})
+			if host, ok := destination["host"].(string); ok && host != "" {
+				route.hosts = append(route.hosts, host)
+				route.services[kubernetes.GetHost(host, vs.GetObjectMeta().Namespace, clusterName, namespaces).String()] = true
+			}
+		}
+		routes = append(routes, route)
+	}
+	return routes
+}
+
+func buildRouteMetrics(namespace, virtualService, rateInterval string, routes []virtualServiceRoute, rates model.Vector, latencies map[string]model.Vector) *models.VirtualServiceRouteMetrics {
+	lblService := model.LabelName(telemetryLabel("destination_service"))
+	lblCode := model.LabelName(telemetryLabel("response_code"))
+	lblGrpcStatus := model.LabelName(telemetryLabel("grpc_response_status"))
+
+	routeMetrics := &models.VirtualServiceRouteMetrics{
+		Namespace:      namespace,
+		VirtualService: virtualService,
+		RateInterval:   rateInterval,
+		Routes:         []models.RouteMetrics{},
+	}
+	byName := map[string]int{}
+	byService := map[string][]int{}
+	for i, route := range routes {
+		routeMetrics.Routes = append(routeMetrics.Routes, models.RouteMetrics{
+			Name:         route.name,
+			Path:         route.path,
+			Destinations: route.hosts,
+			Latencies:    []models.Stat{},
+		})
+		if route.name != "" {
+			byName[route.name] = i
+		}
+		for service := range route.services {
+			byService[service] = append(byService[service], i)
+		}
+	}
+
+	// routeOf returns the index of the route of a series, -1 for the traffic of another VirtualService and
+	// ambiguous=true when several routes lead to its destination
+	routeOf := func(sample *model.Sample) (index int, ambiguous bool) {
+		service := string(sample.Metric[lblService])
+		if name := string(sample.Metric[routeNameLabel]); name != "" {
+			// The route names are only unique in a VirtualService
+			if i, found := byName[name]; found && routes[i].services[service] {
+				return i, false
+			}
+			return -1, false
+		}
+		switch candidates := byService[service]; len(candidates) {
+		case 0:
+			return -1, false
+		case 1:
+			return candidates[0], false
+		default:
+			return -1, true
+		}
+	}
+	seriesKey := func(sample *model.Sample) string {
+		return string(sample.Metric[routeNameLabel]) + "|" + string(sample.Metric[lblService])
+	}
+
+	seriesRates := map[string]float64{}
+	for _, sample := range rates {
+		value := float64(sample.Value)
+		if math.IsNaN(value) || value == 0 {
+			continue
+		}
+		i, ambiguous := routeOf(sample)
+		if ambiguous {
+			routeMetrics.UnattributedRate += value
+			continue
+		}
+		if i < 0 {
+			continue
+		}
+		route := &routeMetrics.Routes[i]
+		route.HasTraffic = true
+		route.RequestRate += value
+		if isErrorResponse(string(sample.Metric[lblCode]), string(sample.Metric[lblGrpcStatus])) {
+			route.ErrorRate += value
+		}
+		seriesRates[seriesKey(sample)] += value
+	}
+
+	// The latencies of the series of each route, per stat
+	routeLatencies := make([]map[string]map[string]float64, len(routes))
+	for stat, vec := range latencies {
+		for _, sample := range vec {
+			value := float64(sample.Value)
+			if math.IsNaN(value) {
+				continue
+			}
+			i, ambiguous := routeOf(sample)
+			if ambiguous || i < 0 {
+				continue
+			}
+			if routeLatencies[i] == nil {
+				routeLatencies[i] = map[string]map[string]float64{}
+			}
+			if routeLatencies[i][stat] == nil {
+				routeLatencies[i][stat] = map[string]float64{}
+			}
+			routeLatencies[i][stat][seriesKey(sample)] = value
+		}
+	}
+	for i, stats := range routeLatencies {
+		series := map[string]bool{}
+		for _, values := range stats {
+			for key := range values {
+				series[key] = true
+			}
+		}
+		route := &routeMetrics.Routes[i]
+		for stat, values := range stats {
+			if len(series) == 1 {
+				for _, value := range values {
+					route.Latencies = append(route.Latencies, models.Stat{Name: stat, Value: value})
+				}
+				continue
+			}
+			if stat != "avg" {
+				continue
+			}
+			// The average of the route is the average of its series weighted by their request rates
+			sum, total := 0.0, 0.0
+			for key, value := range values {
+				sum += value * seriesRates[key]
+				total += seriesRates[key]
+			}
+			if total > 0 {
+				route.Latencies = append(route.Latencies, models.Stat{Name: stat, Value: sum / total})
+			}
+		}
+		sort.Slice(route.Latencies, func(a, b int) bool {
+			return route.Latencies[a].Name < route.Latencies[b].Name
+		})
+	}
+	return routeMetrics
+}
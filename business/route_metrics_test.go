@@ -0,0 +1,162 @@
+package business
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/kubernetes/kubetest"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/prometheus/prometheustest"
+	"github.com/kiali/kiali/tests/data"
+)
+
+func TestGetVirtualServiceRouteMetrics(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	k8s := new(kubetest.K8SClientMock)
+	k8s.On("IsOpenShift").Return(true)
+	k8s.On("IsMaistraApi").Return(false)
+	k8s.On("GetProjects", mock.AnythingOfType("string")).Return(fakeProjects(), nil)
+	k8s.On("GetProject", "bookinfo").Return(&fakeProjects()[0], nil)
+	k8s.On("GetIstioObject", "bookinfo", "virtualservices", "reviews").Return(fakeRouteMetricsVirtualService(t), nil)
+
+	queryTime := time.Date(2022, 1, 10, 12, 0, 0, 0, time.UTC)
+	// The hosts are quoted in the regexp, the traffic is restricted to the accessible namespaces
+	labels := `{reporter="source",destination_service=~"details\\.bookinfo\\.svc\\.cluster\\.local|ratings\\.bookinfo\\.svc\\.cluster\\.local|reviews-next\\.bookinfo\\.svc\\.cluster\\.local|reviews\\.bookinfo\\.svc\\.cluster\\.local",source_workload_namespace=~"bookinfo|foo"}`
+	prom := new(prometheustest.PromClientMock)
+	prom.On("FetchRateValues", "istio_requests_total", labels, "route_name,destination_service,response_code,grpc_response_status", "10m", queryTime).Return(fakeRouteRates(), nil)
+	prom.On("FetchHistogramValues", "istio_request_duration_milliseconds", labels, "route_name,destination_service", "10m", true, []string{"0.5", "0.95", "0.99"}, queryTime).Return(fakeRouteLatencies(), nil)
+
+	layer := NewWithBackends(k8s, prom, nil)
+	layer.Namespace.isAccessibleNamespaces["**"] = true
+
+	routeMetrics, err := layer.IstioConfig.GetVirtualServiceRouteMetrics("bookinfo", "reviews", "10m", queryTime)
+	assert.NoError(err)
+	prom.AssertExpectations(t)
+	assert.Equal("reviews", routeMetrics.VirtualService)
+	assert.Equal("10m", routeMetrics.RateInterval)
+	assert.Len(routeMetrics.Routes, 4)
+}
+
+func TestVirtualServiceRouteMetricsPerRoute(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	routes := parseVirtualServiceRoutes(fakeRouteMetricsVirtualService(t), []string{"bookinfo"})
+	routeMetrics := buildRouteMetrics("bookinfo", "reviews", "10m", routes, fakeRouteRates(), fakeRouteLatencies())
+
+	// In the order of the VirtualService
+	assert.Len(routeMetrics.Routes, 4)
+	jason := routeMetrics.Routes[0]
+	assert.Equal("jason", jason.Name)
+	assert.Equal("spec/http[0]", jason.Path)
+	assert.Equal([]string{"reviews"}, jason.Destinations)
+	assert.True(jason.HasTraffic)
+	assert.InDelta(5.0, jason.RequestRate, 0.001)
+	assert.InDelta(1.0, jason.ErrorRate, 0.001)
+	assert.Equal([]models.Stat{{Name: "0.99", Value: 50}, {Name: "avg", Value: 20}}, jason.Latencies)
+
+	// Two destination services: only the average, weighted by their request rates
+	canary := routeMetrics.Routes[1]
+	assert.Equal("canary", canary.Name)
+	assert.Equal([]string{"reviews", "reviews-next.bookinfo.svc.cluster.local"}, canary.Destinations)
+	assert.InDelta(4.0, canary.RequestRate, 0.001)
+	assert.InDelta(0.5, canary.ErrorRate, 0.001)
+	assert.Len(canary.Latencies, 1)
+	assert.Equal("avg", canary.Latencies[0].Name)
+	assert.InDelta(200.0, canary.Latencies[0].Value, 0.001)
+
+	// Attributed by its destination, the only route to ratings
+	unnamed := routeMetrics.Routes[2]
+	assert.Empty(unnamed.Name)
+	assert.Equal("spec/http[2]", unnamed.Path)
+	assert.InDelta(1.0, unnamed.RequestRate, 0.001)
+	assert.Zero(unnamed.ErrorRate)
+	assert.Equal([]models.Stat{{Name: "avg", Value: 10}}, unnamed.Latencies)
+
+	legacy := routeMetrics.Routes[3]
+	assert.Equal("legacy", legacy.Name)
+	assert.False(legacy.HasTraffic)
+	assert.Zero(legacy.RequestRate)
+	assert.Empty(legacy.Latencies)
+
+	// Without route name, the traffic to reviews can take three routes
+	assert.InDelta(3.0, routeMetrics.UnattributedRate, 0.001)
+}
+
+func TestVirtualServiceRouteMetricsWithoutTraffic(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	routes := parseVirtualServiceRoutes(fakeRouteMetricsVirtualService(t), []string{"bookinfo"})
+	routeMetrics := buildRouteMetrics("bookinfo", "reviews", "10m", routes, model.Vector{}, map[string]model.Vector{})
+
+	assert.Len(routeMetrics.Routes, 4)
+	for _, route := range routeMetrics.Routes {
+		assert.False(route.HasTraffic)
+		assert.Empty(route.Latencies)
+	}
+	assert.Zero(routeMetrics.UnattributedRate)
+}
+
+func fakeRouteMetricsVirtualService(t *testing.T) kubernetes.IstioObject {
+	loader := &data.YamlFixtureLoader{Filename: "../tests/data/routing/route-metrics.yaml"}
+	if err := loader.Load(); err != nil {
+		t.Fatal("Error loading test data.")
+	}
+	return loader.GetResources("VirtualService")[0]
+}
+
+func routeSample(route, service string, metric model.Metric, value float64) *model.Sample {
+	if metric == nil {
+		metric = model.Metric{}
+	}
+	if route != "" {
+		metric["route_name"] = model.LabelValue(route)
+	}
+	metric["destination_service"] = model.LabelValue(service + ".bookinfo.svc.cluster.local")
+	return &model.Sample{Metric: metric, Value: model.SampleValue(value)}
+}
+
+func fakeRouteRates() model.Vector {
+	code := func(code string) model.Metric {
+		return model.Metric{"response_code": model.LabelValue(code)}
+	}
+	return model.Vector{
+		routeSample("jason", "reviews", code("200"), 4),
+		routeSample("jason", "reviews", code("503"), 1),
+		routeSample("canary", "reviews", code("200"), 2),
+		routeSample("canary", "reviews-next", code("200"), 1.5),
+		routeSample("canary", "reviews-next", code("500"), 0.5),
+		routeSample("", "reviews", code("200"), 3),
+		routeSample("", "ratings", code("200"), 1),
+		// A route of another VirtualService
+		routeSample("reviews-mirror", "reviews", code("200"), 7),
+		// Stale series
+		routeSample("legacy", "details", code("200"), 0),
+	}
+}
+
+func fakeRouteLatencies() map[string]model.Vector {
+	return map[string]model.Vector{
+		"avg": {
+			routeSample("jason", "reviews", nil, 20),
+			routeSample("canary", "reviews", nil, 100),
+			routeSample("canary", "reviews-next", nil, 300),
+			routeSample("", "reviews", nil, 30),
+			routeSample("", "ratings", nil, 10),
+		},
+		"0.99": {
+			routeSample("jason", "reviews", nil, 50),
+			routeSample("canary", "reviews", nil, 400),
+			routeSample("canary", "reviews-next", nil, 900),
+		},
+	}
+}
@@ -0,0 +1,124 @@
+package business
+
+import (
+	"sort"
+
+	core_v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/prometheus/internalmetrics"
+)
+
+// GetServicesForWorkload returns the services selecting the pods of the workload, along with all the workloads
+// selected by each of these services
+func (in *SvcService) GetServicesForWorkload(namespace, workload string) (*models.ServiceWorkloadMapping, error) {
+	var err error
+	promtimer := internalmetrics.GetGoFunctionMetric("business", "SvcService", "GetServicesForWorkload")
+	defer promtimer.ObserveNow(&err)
+
+	full, err := in.getServiceWorkloadMapping(namespace)
+	if err != nil {
+		return nil, err
+	}
+	services, found := full.Workloads[workload]
+	if !found {
+		err = kubernetes.NewNotFound(workload, "Kiali", "Workload")
+		return nil, err
+	}
+
+	mapping := newServiceWorkloadMapping(namespace)
+	mapping.Workloads[workload] = services
+	for _, svc := range services {
+		mapping.Services[svc] = full.Services[svc]
+	}
+	return mapping, nil
+}
+
+// GetWorkloadsForService returns the workloads whose pods are selected by the service, along with all the services
+// selecting each of these workloads
+func (in *SvcService) GetWorkloadsForService(namespace, service string) (*models.ServiceWorkloadMapping, error) {
+	var err error
+	promtimer := internalmetrics.GetGoFunctionMetric("business", "SvcService", "GetWorkloadsForService")
+	defer promtimer.ObserveNow(&err)
+
+	full, err := in.getServiceWorkloadMapping(namespace)
+	if err != nil {
+		return nil, err
+	}
+	workloads, found := full.Services[service]
+	if !found {
+		err = kubernetes.NewNotFound(service, "Kiali", "Service")
+		return nil, err
+	}
+
+	mapping := newServiceWorkloadMapping(namespace)
+	mapping.Services[service] = workloads
+	for _, w := range workloads {
+		mapping.Workloads[w] = full.Workloads[w]
+	}
+	return mapping, nil
+}
+
+// getServiceWorkloadMapping maps all the services and workloads of the namespace
+func (in *SvcService) getServiceWorkloadMapping(namespace string) (*models.ServiceWorkloadMapping, error) {
+	// Check if user has access to the namespace (RBAC) in cache scenarios and/or
+	// if namespace is accessible from Kiali (Deployment.AccessibleNamespaces)
+	if _, err := in.businessLayer.Namespace.GetNamespace(namespace); err != nil {
+		return nil, err
+	}
+
+	var services []core_v1.Service
+	var err error
+	if IsNamespaceCached(namespace) {
+		services, err = kialiCache.GetServices(namespace, nil)
+	} else {
+		services, err = in.k8s.GetServices(namespace, nil)
+	}
+	if err != nil {
+		return nil, err
+	}
+	workloads, err := fetchWorkloads(in.businessLayer, namespace, "")
+	if err != nil {
+		return nil, err
+	}
+	return buildServiceWorkloadMapping(namespace, services, workloads), nil
+}
+
+// buildServiceWorkloadMapping matches the selectors of the services with the labels of the pods of the workloads.
+// Services without selector are mapped to no workload, their endpoints are not managed by Kubernetes.
+func buildServiceWorkloadMapping(namespace string, services []core_v1.Service, workloads models.Workloads) *models.ServiceWorkloadMapping {
+	mapping := newServiceWorkloadMapping(namespace)
+	for _, w := range workloads {
+		mapping.Workloads[w.Name] = []string{}
+	}
+	for _, svc := range services {
+		mapping.Services[svc.Name] = []string{}
+		if len(svc.Spec.Selector) == 0 {
+			continue
+		}
+		selector := labels.SelectorFromSet(svc.Spec.Selector)
+		for _, w := range workloads {
+			if selector.Matches(labels.Set(w.Labels)) {
+				mapping.Services[svc.Name] = append(mapping.Services[svc.Name], w.Name)
+				mapping.Workloads[w.Name] = append(mapping.Workloads[w.Name], svc.Name)
+			}
+		}
+	}
+	for _, names := range mapping.Services {
+		sort.Strings(names)
+	}
+	for _, names := range mapping.Workloads {
+		sort.Strings(names)
+	}
+	return mapping
+}
+
+func newServiceWorkloadMapping(namespace string) *models.ServiceWorkloadMapping {
+	return &models.ServiceWorkloadMapping{
+		Namespace: namespace,
+		Services:  map[string][]string{},
+		Workloads: map[string][]string{},
+	}
+}
@@ -0,0 +1,110 @@
+package business
+
+import (
+	"testing"
+
+	osapps_v1 "github.com/openshift/api/apps/v1"
+	osproject_v1 "github.com/openshift/api/project/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	apps_v1 "k8s.io/api/apps/v1"
+	batch_v1 "k8s.io/api/batch/v1"
+	batch_v1beta1 "k8s.io/api/batch/v1beta1"
+	core_v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes/kubetest"
+)
+
+// Context: the reviews service selects two workloads, the pods of reviews-v2 are selected by three services
+func TestGetServicesForWorkload(t *testing.T) {
+	assert := assert.New(t)
+
+	svc := serviceWorkloadMappingTestPrep()
+	mapping, err := svc.GetServicesForWorkload("bookinfo", "reviews-v2")
+	assert.NoError(err)
+
+	assert.Equal("bookinfo", mapping.Namespace)
+	assert.Equal(map[string][]string{"reviews-v2": {"backend", "reviews", "reviews-v2"}}, mapping.Workloads)
+	assert.Equal(map[string][]string{
+		"backend":    {"ratings-v1", "reviews-v2"},
+		"reviews":    {"reviews-v1", "reviews-v2"},
+		"reviews-v2": {"reviews-v2"},
+	}, mapping.Services)
+}
+
+func TestGetWorkloadsForService(t *testing.T) {
+	assert := assert.New(t)
+
+	svc := serviceWorkloadMappingTestPrep()
+	mapping, err := svc.GetWorkloadsForService("bookinfo", "reviews")
+	assert.NoError(err)
+
+	assert.Equal(map[string][]string{"reviews": {"reviews-v1", "reviews-v2"}}, mapping.Services)
+	assert.Equal(map[string][]string{
+		"reviews-v1": {"reviews"},
+		"reviews-v2": {"backend", "reviews", "reviews-v2"},
+	}, mapping.Workloads)
+
+	// Without selector, the service selects no workload
+	mapping, err = svc.GetWorkloadsForService("bookinfo", "external")
+	assert.NoError(err)
+	assert.Equal(map[string][]string{"external": {}}, mapping.Services)
+	assert.Empty(mapping.Workloads)
+}
+
+func TestServiceWorkloadMappingNotFound(t *testing.T) {
+	assert := assert.New(t)
+
+	svc := serviceWorkloadMappingTestPrep()
+	_, err := svc.GetWorkloadsForService("bookinfo", "details")
+	assert.True(errors.IsNotFound(err))
+	_, err = svc.GetServicesForWorkload("bookinfo", "details-v1")
+	assert.True(errors.IsNotFound(err))
+}
+
+func serviceWorkloadMappingTestPrep() *SvcService {
+	config.Set(config.NewConfig())
+
+	fakeDeployment := func(name string, labels map[string]string) apps_v1.Deployment {
+		return apps_v1.Deployment{
+			ObjectMeta: meta_v1.ObjectMeta{Name: name, Namespace: "bookinfo"},
+			Spec: apps_v1.DeploymentSpec{
+				Template: core_v1.PodTemplateSpec{ObjectMeta: meta_v1.ObjectMeta{Labels: labels}},
+			},
+		}
+	}
+	fakeService := func(name string, selector map[string]string) core_v1.Service {
+		return core_v1.Service{
+			ObjectMeta: meta_v1.ObjectMeta{Name: name, Namespace: "bookinfo"},
+			Spec:       core_v1.ServiceSpec{Selector: selector},
+		}
+	}
+
+	k8s := new(kubetest.K8SClientMock)
+	k8s.On("IsOpenShift").Return(true)
+	k8s.On("GetProject", mock.AnythingOfType("string")).Return(&osproject_v1.Project{}, nil)
+	k8s.On("GetServices", "bookinfo", mock.Anything).Return([]core_v1.Service{
+		fakeService("reviews", map[string]string{"app": "reviews"}),
+		fakeService("reviews-v2", map[string]string{"app": "reviews", "version": "v2"}),
+		fakeService("backend", map[string]string{"tier": "backend"}),
+		fakeService("external", nil),
+	}, nil)
+	k8s.On("GetDeployments", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return([]apps_v1.Deployment{
+		fakeDeployment("reviews-v1", map[string]string{"app": "reviews", "version": "v1"}),
+		fakeDeployment("reviews-v2", map[string]string{"app": "reviews", "version": "v2", "tier": "backend"}),
+		fakeDeployment("ratings-v1", map[string]string{"app": "ratings", "version": "v1", "tier": "backend"}),
+	}, nil)
+	k8s.On("GetDeploymentConfigs", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return([]osapps_v1.DeploymentConfig{}, nil)
+	k8s.On("GetReplicaSets", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return([]apps_v1.ReplicaSet{}, nil)
+	k8s.On("GetReplicationControllers", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return([]core_v1.ReplicationController{}, nil)
+	k8s.On("GetStatefulSets", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return([]apps_v1.StatefulSet{}, nil)
+	k8s.On("GetJobs", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return([]batch_v1.Job{}, nil)
+	k8s.On("GetCronJobs", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return([]batch_v1beta1.CronJob{}, nil)
+	k8s.On("GetPods", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return([]core_v1.Pod{}, nil)
+
+	businessLayer := NewWithBackends(k8s, nil, nil)
+	return &SvcService{k8s: k8s, businessLayer: businessLayer}
+}
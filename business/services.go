@@ -107,7 +107,7 @@ func (in *SvcService) GetServiceList(namespace string) (*models.ServiceList, err
 func (in *SvcService) buildServiceList(namespace models.Namespace, svcs []core_v1.Service, pods []core_v1.Pod, deployments []apps_v1.Deployment) *models.ServiceList {
 	services := make([]models.ServiceOverview, len(svcs))
 	conf := config.Get()
-	validations := in.getServiceValidations(svcs, deployments, pods)
+	validations := in.getServiceValidations(namespace.Name, svcs, deployments, pods)
 	// Convert each k8s service into our model
 	for i, item := range svcs {
 		sPods := kubernetes.FilterPodsForService(&item, pods)
@@ -286,6 +286,7 @@ func (in *SvcService) UpdateService(namespace, service string, interval string,
 	if err != nil {
 		return nil, err
 	}
+	in.businessLayer.Audit.Record("", AuditUpdate, namespace, "Service", service)
 
 	// Cache is stopped after a Create/Update/Delete operation to force a refresh
 	if kialiCache != nil && err == nil {
@@ -405,16 +406,36 @@ func (in *SvcService) GetServiceDefinitionList(namespace string) (*models.Servic
 	return &sdl, nil
 }
 
-func (in *SvcService) getServiceValidations(services []core_v1.Service, deployments []apps_v1.Deployment, pods []core_v1.Pod) models.IstioValidations {
+func (in *SvcService) getServiceValidations(namespace string, services []core_v1.Service, deployments []apps_v1.Deployment, pods []core_v1.Pod) models.IstioValidations {
 	validations := checkers.ServiceChecker{
 		Services:    services,
 		Deployments: deployments,
 		Pods:        pods,
+		Endpoints:   in.getServicesEndpoints(namespace, services),
 	}.Check()
 
 	return validations
 }
 
+// getServicesEndpoints returns the Endpoints of the Services by Service name, read from the cache. It returns nil
+// when the namespace is not cached, the Endpoints are not fetched one Service at a time from the API server.
+func (in *SvcService) getServicesEndpoints(namespace string, services []core_v1.Service) map[string]*core_v1.Endpoints {
+	if !IsNamespaceCached(namespace) {
+		return nil
+	}
+	endpoints := make(map[string]*core_v1.Endpoints, len(services))
+	for _, svc := range services {
+		eps, err := kialiCache.GetEndpoints(namespace, svc.Name)
+		if err != nil {
+			log.Errorf("Error fetching Endpoints for namespace %s and service %s: %s", namespace, svc.Name, err)
+			return nil
+		}
+		// The cache returns no Endpoints for a Service without endpoints
+		endpoints[svc.Name] = eps
+	}
+	return endpoints
+}
+
 // GetServiceAppName returns the "Application" name (app label) that relates to a service
 // This label is taken from the service selector, which means it is assumed that pods are selected using that label
 func (in *SvcService) GetServiceAppName(namespace, service string) (string, error) {
@@ -443,3 +464,59 @@ func updateService(layer *Layer, namespace string, service string, jsonPatch str
 
 	return layer.k8s.UpdateService(namespace, service, jsonPatch)
 }
+
+// GetServiceLocalityLB returns the locality load balancing configured for the service by its DestinationRules,
+// combined with the localities where the endpoints of the service run.
+func (in *SvcService) GetServiceLocalityLB(namespace, service string) (*models.LocalityLoadBalancing, error) {
+	var err error
+	promtimer := internalmetrics.GetGoFunctionMetric("business", "SvcService", "GetServiceLocalityLB")
+	defer promtimer.ObserveNow(&err)
+
+	// Check if user has access to the namespace (RBAC) in cache scenarios and/or
+	// if namespace is accessible from Kiali (Deployment.AccessibleNamespaces)
+	if _, err = in.businessLayer.Namespace.GetNamespace(namespace); err != nil {
+		return nil, err
+	}
+
+	svc, eps, err := in.getServiceDefinition(namespace, service)
+	if err != nil {
+		return nil, err
+	}
+
+	var drs []kubernetes.IstioObject
+	if IsResourceCached(namespace, kubernetes.DestinationRules) {
+		drs, err = kialiCache.GetIstioObjects(namespace, kubernetes.DestinationRules, "")
+	} else {
+		drs, err = in.k8s.GetIstioObjects(namespace, kubernetes.DestinationRules, "")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	lb := models.LocalityLoadBalancing{Namespace: namespace, Service: service}
+	for _, dr := range kubernetes.FilterDestinationRules(drs, namespace, service) {
+		if lb.ParseDestinationRule(dr) {
+			break
+		}
+	}
+
+	pods := models.Pods{}
+	labelsSelector := labels.Set(svc.Spec.Selector).String()
+	// If service doesn't have any selector, we can't know which are the pods behind the endpoints.
+	if labelsSelector != "" && eps != nil {
+		var ps []core_v1.Pod
+		if IsNamespaceCached(namespace) {
+			ps, err = kialiCache.GetPods(namespace, labelsSelector)
+		} else {
+			ps, err = in.k8s.GetPods(namespace, labelsSelector)
+		}
+		if err != nil {
+			return nil, err
+		}
+		pods.Parse(kubernetes.FilterPodsForEndpoints(eps, ps))
+		setPodsNodeTopology(in.businessLayer, pods)
+	}
+	lb.SetEndpoints(pods)
+
+	return &lb, nil
+}
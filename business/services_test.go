@@ -1,14 +1,23 @@
 package business
 
 import (
+	"encoding/json"
+	"io/ioutil"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	apps_v1 "k8s.io/api/apps/v1"
 	core_v1 "k8s.io/api/core/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/yaml"
 
 	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/kubernetes/cache"
 	"github.com/kiali/kiali/kubernetes/kubetest"
+	"github.com/kiali/kiali/models"
 )
 
 func TestServiceListParsing(t *testing.T) {
@@ -34,3 +43,261 @@ func TestServiceListParsing(t *testing.T) {
 	assert.Equal("reviews", reviewsOverview.Name)
 	assert.Equal("httpbin", httpbinOverview.Name)
 }
+
+func TestServiceListFlagsServicesWithoutReadyPods(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	services, endpoints := loadServiceEndpoints(t)
+	kialiCache = &fakeServiceEndpointsCache{services: services, endpoints: endpoints}
+	defer func() { kialiCache = nil }()
+
+	k8s := new(kubetest.K8SClientMock)
+	k8s.On("IsOpenShift").Return(false)
+	k8s.On("GetToken").Return("token")
+	svc := SvcService{k8s: k8s, businessLayer: NewWithBackends(k8s, nil, nil)}
+
+	serviceList, err := svc.GetServiceList("bookinfo")
+	assert.NoError(err)
+	assert.Len(serviceList.Services, 8)
+
+	expected := map[string]string{
+		"ratings":     "service.pods.notready",
+		"details":     "service.pods.notfound",
+		"productpage": "service.pods.notfound",
+		"mysqldb":     "service.pods.notfound",
+	}
+	for _, s := range serviceList.Services {
+		validation := serviceList.Validations[models.BuildKey("service", s.Name, "bookinfo")]
+		if !assert.NotNil(validation, s.Name) {
+			continue
+		}
+		if check, found := expected[s.Name]; found {
+			assert.Len(validation.Checks, 1, s.Name)
+			assert.Equal(models.CheckMessage(check), validation.Checks[0].Message, s.Name)
+		} else {
+			assert.Empty(validation.Checks, s.Name)
+		}
+	}
+
+	k8s.AssertNotCalled(t, "GetEndpoints", mock.Anything, mock.Anything)
+}
+
+func TestServiceListWithoutCachedEndpoints(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	services, _ := loadServiceEndpoints(t)
+	svcs := []core_v1.Service{}
+	for _, s := range services {
+		svcs = append(svcs, s)
+	}
+	k8s := new(kubetest.K8SClientMock)
+	k8s.On("IsOpenShift").Return(false)
+	k8s.On("GetNamespace", "bookinfo").Return(&core_v1.Namespace{}, nil)
+	k8s.On("GetServices", "bookinfo", mock.Anything).Return(svcs, nil)
+	k8s.On("GetPods", "bookinfo", "").Return([]core_v1.Pod{}, nil)
+	k8s.On("GetDeployments", "bookinfo").Return([]apps_v1.Deployment{}, nil)
+	svc := SvcService{k8s: k8s, businessLayer: NewWithBackends(k8s, nil, nil)}
+
+	// The Endpoints are not fetched one by one from the API server
+	serviceList, err := svc.GetServiceList("bookinfo")
+	assert.NoError(err)
+	for _, validation := range serviceList.Validations {
+		assert.Empty(validation.Checks)
+	}
+	k8s.AssertNotCalled(t, "GetEndpoints", mock.Anything, mock.Anything)
+}
+
+// fakeServiceEndpointsCache serves the Services and the Endpoints of a namespace without pods
+type fakeServiceEndpointsCache struct {
+	cache.KialiCache
+	services  map[string]core_v1.Service
+	endpoints map[string]*core_v1.Endpoints
+}
+
+func (f *fakeServiceEndpointsCache) CheckNamespace(namespace string) bool {
+	return true
+}
+
+func (f *fakeServiceEndpointsCache) GetNamespace(token string, namespace string) *models.Namespace {
+	return &models.Namespace{Name: namespace}
+}
+
+func (f *fakeServiceEndpointsCache) GetServices(namespace string, selectorLabels map[string]string) ([]core_v1.Service, error) {
+	services := []core_v1.Service{}
+	for _, name := range []string{"reviews", "ratings", "details", "productpage", "mongodb", "mysqldb", "legacy-db", "external-api"} {
+		services = append(services, f.services[name])
+	}
+	return services, nil
+}
+
+func (f *fakeServiceEndpointsCache) GetPods(namespace, labelSelector string) ([]core_v1.Pod, error) {
+	return []core_v1.Pod{}, nil
+}
+
+func (f *fakeServiceEndpointsCache) GetDeployments(namespace string) ([]apps_v1.Deployment, error) {
+	return []apps_v1.Deployment{}, nil
+}
+
+func (f *fakeServiceEndpointsCache) GetEndpoints(namespace, name string) (*core_v1.Endpoints, error) {
+	return f.endpoints[name], nil
+}
+
+// loadServiceEndpoints returns the Services and the Endpoints of the endpoints fixture, by name
+func loadServiceEndpoints(t *testing.T) (map[string]core_v1.Service, map[string]*core_v1.Endpoints) {
+	content, err := ioutil.ReadFile("../tests/data/validations/services/endpoints.yaml")
+	if err != nil {
+		t.Fatalf("Error loading test data: %v", err)
+	}
+	services := map[string]core_v1.Service{}
+	endpoints := map[string]*core_v1.Endpoints{}
+	for _, doc := range strings.Split(string(content), "\n---\n") {
+		js, err := yaml.ToJSON([]byte(doc))
+		if err != nil {
+			t.Fatalf("Error parsing test data: %v", err)
+		}
+		if strings.Contains(string(js), `"kind":"Endpoints"`) {
+			eps := &core_v1.Endpoints{}
+			if err := json.Unmarshal(js, eps); err != nil {
+				t.Fatalf("Error parsing test data: %v", err)
+			}
+			endpoints[eps.Name] = eps
+			continue
+		}
+		svc := core_v1.Service{}
+		if err := json.Unmarshal(js, &svc); err != nil {
+			t.Fatalf("Error parsing test data: %v", err)
+		}
+		services[svc.Name] = svc
+	}
+	return services, endpoints
+}
+
+func TestGetServiceLocalityLBDistribute(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	k8s := mockLocalityLBService(map[string]interface{}{
+		"distribute": []interface{}{
+			map[string]interface{}{
+				"from": "us-east/us-east-1a/*",
+				"to": map[string]interface{}{
+					"us-east/us-east-1a/*": float64(80),
+					"us-west/*":            float64(20),
+				},
+			},
+		},
+	})
+	svc := SvcService{k8s: k8s, businessLayer: NewWithBackends(k8s, nil, nil)}
+
+	lb, err := svc.GetServiceLocalityLB("bookinfo", "reviews")
+	assert.NoError(err)
+	assert.Equal("reviews", lb.DestinationRule)
+	assert.Len(lb.Distribute, 1)
+	assert.Equal("us-east/us-east-1a/*", lb.Distribute[0].From)
+	assert.Equal(uint32(80), lb.Distribute[0].To["us-east/us-east-1a/*"])
+	assert.Equal(uint32(20), lb.Distribute[0].To["us-west/*"])
+	assert.Empty(lb.Failover)
+
+	assert.Equal(map[string]int{"us-east/us-east-1a": 1, "us-east/us-east-1b": 1}, lb.EndpointLocalities)
+	// No endpoints in us-west
+	assert.Equal([]string{"us-west/*"}, lb.UnmatchedLocalities)
+}
+
+func TestGetServiceLocalityLBFailover(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	k8s := mockLocalityLBService(map[string]interface{}{
+		"enabled": true,
+		"failover": []interface{}{
+			map[string]interface{}{"from": "us-east", "to": "eu-west"},
+			map[string]interface{}{"from": "eu-west", "to": "us-east"},
+		},
+	})
+	svc := SvcService{k8s: k8s, businessLayer: NewWithBackends(k8s, nil, nil)}
+
+	lb, err := svc.GetServiceLocalityLB("bookinfo", "reviews")
+	assert.NoError(err)
+	assert.True(*lb.Enabled)
+	assert.Empty(lb.Distribute)
+	assert.Equal([]models.LocalityFailover{{From: "us-east", To: "eu-west"}, {From: "eu-west", To: "us-east"}}, lb.Failover)
+	assert.Equal([]string{"eu-west"}, lb.UnmatchedLocalities)
+}
+
+func TestGetServiceLocalityLBWithoutSetting(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	k8s := mockLocalityLBService(nil)
+	svc := SvcService{k8s: k8s, businessLayer: NewWithBackends(k8s, nil, nil)}
+
+	lb, err := svc.GetServiceLocalityLB("bookinfo", "reviews")
+	assert.NoError(err)
+	assert.Empty(lb.DestinationRule)
+	assert.Len(lb.EndpointLocalities, 2)
+	assert.Empty(lb.UnmatchedLocalities)
+}
+
+func mockLocalityLBService(localityLbSetting map[string]interface{}) *kubetest.K8SClientMock {
+	trafficPolicy := map[string]interface{}{}
+	if localityLbSetting != nil {
+		trafficPolicy["loadBalancer"] = map[string]interface{}{"localityLbSetting": localityLbSetting}
+	}
+	dr := &kubernetes.GenericIstioObject{
+		ObjectMeta: meta_v1.ObjectMeta{Name: "reviews", Namespace: "bookinfo"},
+		Spec: map[string]interface{}{
+			"host":          "reviews",
+			"trafficPolicy": trafficPolicy,
+		},
+	}
+
+	pods := []core_v1.Pod{
+		fakeLocalityPod("reviews-v1-1", "node-1"),
+		fakeLocalityPod("reviews-v2-1", "node-2"),
+		// Pending pod without node
+		fakeLocalityPod("reviews-v3-1", ""),
+	}
+	eps := &core_v1.Endpoints{
+		Subsets: []core_v1.EndpointSubset{{
+			Addresses: []core_v1.EndpointAddress{
+				{TargetRef: &core_v1.ObjectReference{Kind: "Pod", Name: "reviews-v1-1"}},
+				{TargetRef: &core_v1.ObjectReference{Kind: "Pod", Name: "reviews-v2-1"}},
+			},
+			NotReadyAddresses: []core_v1.EndpointAddress{
+				{TargetRef: &core_v1.ObjectReference{Kind: "Pod", Name: "reviews-v3-1"}},
+			},
+		}},
+	}
+
+	k8s := new(kubetest.K8SClientMock)
+	k8s.On("IsOpenShift").Return(false)
+	k8s.On("GetNamespace", "bookinfo").Return(kubetest.FakeNamespace("bookinfo"), nil)
+	k8s.MockService("bookinfo", "reviews")
+	k8s.On("GetEndpoints", "bookinfo", "reviews").Return(eps, nil)
+	k8s.On("GetIstioObjects", "bookinfo", kubernetes.DestinationRules, "").Return([]kubernetes.IstioObject{dr}, nil)
+	k8s.On("GetPods", "bookinfo", "app=reviews").Return(pods, nil)
+	k8s.On("GetNode", "node-1").Return(fakeLocalityNode("node-1", "us-east", "us-east-1a"), nil)
+	k8s.On("GetNode", "node-2").Return(fakeLocalityNode("node-2", "us-east", "us-east-1b"), nil)
+	return k8s
+}
+
+func fakeLocalityPod(name, node string) core_v1.Pod {
+	return core_v1.Pod{
+		ObjectMeta: meta_v1.ObjectMeta{Name: name, Namespace: "bookinfo", Labels: map[string]string{"app": "reviews"}},
+		Spec:       core_v1.PodSpec{NodeName: node},
+	}
+}
+
+func fakeLocalityNode(name, region, zone string) *core_v1.Node {
+	return &core_v1.Node{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name: name,
+			Labels: map[string]string{
+				core_v1.LabelTopologyRegion: region,
+				core_v1.LabelTopologyZone:   zone,
+			},
+		},
+	}
+}
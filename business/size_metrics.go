@@ -0,0 +1,82 @@
+package business
+
+import (
+	"math"
+	"sort"
+
+	"github.com/prometheus/common/model"
+
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/prometheus/internalmetrics"
+)
+
+// GetWorkloadSizeMetrics returns the average and the quantiles of the sizes of the request and response bodies of
+// the traffic of a workload. Istio may be configured not to report the size histograms: the request rate of the
+// workload tells whether their absence is due to a lack of traffic.
+func (in *MetricsService) GetWorkloadSizeMetrics(q models.WorkloadSizeMetricsQuery) (*models.WorkloadSizeMetrics, error) {
+	var err error
+	promtimer := internalmetrics.GetGoFunctionMetric("business", "MetricsService", "GetWorkloadSizeMetrics")
+	defer promtimer.ObserveNow(&err)
+
+	lb := NewMetricsLabelsBuilder(q.Direction)
+	lb.SelfReporter()
+	lb.Workload(q.Workload, q.Namespace)
+	labels := lb.Build()
+
+	var rates model.Vector
+	rates, err = in.prom.FetchRateValues(telemetryMetric("istio_requests_total"), labels, "", q.RateInterval, q.QueryTime)
+	if err != nil {
+		return nil, err
+	}
+	var requestSizes, responseSizes map[string]model.Vector
+	requestSizes, err = in.prom.FetchHistogramValues(telemetryMetric("istio_request_bytes"), labels, "", q.RateInterval, true, q.Quantiles, q.QueryTime)
+	if err != nil {
+		return nil, err
+	}
+	responseSizes, err = in.prom.FetchHistogramValues(telemetryMetric("istio_response_bytes"), labels, "", q.RateInterval, true, q.Quantiles, q.QueryTime)
+	if err != nil {
+		return nil, err
+	}
+
+	hasTraffic := false
+	for _, sample := range rates {
+		if value := float64(sample.Value); !math.IsNaN(value) && value > 0 {
+			hasTraffic = true
+		}
+	}
+	return &models.WorkloadSizeMetrics{
+		Namespace:     q.Namespace,
+		Workload:      q.Workload,
+		Direction:     q.Direction,
+		RequestSizes:  buildSizeDistribution(requestSizes, q.Quantiles, hasTraffic),
+		ResponseSizes: buildSizeDistribution(responseSizes, q.Quantiles, hasTraffic),
+	}, nil
+}
+
+// buildSizeDistribution reads the average and the quantiles of a size histogram. Without any requested quantile, or
+// without any value when none is requested, while there is traffic, the histogram is considered disabled.
+func buildSizeDistribution(sizes map[string]model.Vector, quantiles []string, hasTraffic bool) models.SizeDistribution {
+	distribution := models.SizeDistribution{Stats: []models.Stat{}}
+	hasQuantile := false
+	for stat, vec := range sizes {
+		for _, sample := range vec {
+			value := float64(sample.Value)
+			if math.IsNaN(value) {
+				continue
+			}
+			distribution.Stats = append(distribution.Stats, models.Stat{Name: stat, Value: value})
+			if stat != "avg" {
+				hasQuantile = true
+			}
+		}
+	}
+	sort.Slice(distribution.Stats, func(i, j int) bool {
+		return distribution.Stats[i].Name < distribution.Stats[j].Name
+	})
+	if len(quantiles) > 0 {
+		distribution.HistogramDisabled = hasTraffic && !hasQuantile
+	} else {
+		distribution.HistogramDisabled = hasTraffic && len(distribution.Stats) == 0
+	}
+	return distribution
+}
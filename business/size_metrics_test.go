@@ -0,0 +1,129 @@
+package business
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/prometheus/prometheustest"
+)
+
+const sizeMetricsLabels = `{reporter="destination",destination_workload_namespace="bookinfo",destination_workload="productpage-v1"}`
+
+func TestGetWorkloadSizeMetrics(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	queryTime := time.Date(2017, 01, 15, 0, 0, 0, 0, time.UTC)
+	quantiles := []string{"0.5", "0.95", "0.99"}
+	prom := new(prometheustest.PromClientMock)
+	prom.On("FetchRateValues", "istio_requests_total", sizeMetricsLabels, "", "5m", queryTime).Return(recordedVector(t, `[{"metric":{},"value":[1484438400,"12.5"]}]`), nil)
+	prom.On("FetchHistogramValues", "istio_request_bytes", sizeMetricsLabels, "", "5m", true, quantiles, queryTime).Return(map[string]model.Vector{
+		"avg":  recordedVector(t, `[{"metric":{},"value":[1484438400,"312.4"]}]`),
+		"0.5":  recordedVector(t, `[{"metric":{},"value":[1484438400,"254"]}]`),
+		"0.95": recordedVector(t, `[{"metric":{},"value":[1484438400,"925"]}]`),
+		"0.99": recordedVector(t, `[{"metric":{},"value":[1484438400,"1014"]}]`),
+	}, nil)
+	prom.On("FetchHistogramValues", "istio_response_bytes", sizeMetricsLabels, "", "5m", true, quantiles, queryTime).Return(map[string]model.Vector{
+		"avg":  recordedVector(t, `[{"metric":{},"value":[1484438400,"4876.2"]}]`),
+		"0.5":  recordedVector(t, `[{"metric":{},"value":[1484438400,"3120"]}]`),
+		"0.95": recordedVector(t, `[{"metric":{},"value":[1484438400,"9500"]}]`),
+		"0.99": recordedVector(t, `[{"metric":{},"value":[1484438400,"NaN"]}]`),
+	}, nil)
+
+	sizes, err := NewMetricsService(prom).GetWorkloadSizeMetrics(models.WorkloadSizeMetricsQuery{
+		Namespace:    "bookinfo",
+		Workload:     "productpage-v1",
+		Direction:    "inbound",
+		RateInterval: "5m",
+		Quantiles:    quantiles,
+		QueryTime:    queryTime,
+	})
+
+	assert.NoError(err)
+	assert.Equal("productpage-v1", sizes.Workload)
+	assert.Equal("inbound", sizes.Direction)
+	assert.False(sizes.RequestSizes.HistogramDisabled)
+	assert.Equal([]models.Stat{{Name: "0.5", Value: 254}, {Name: "0.95", Value: 925}, {Name: "0.99", Value: 1014}, {Name: "avg", Value: 312.4}}, sizes.RequestSizes.Stats)
+	// Not enough responses for the 99th percentile
+	assert.False(sizes.ResponseSizes.HistogramDisabled)
+	assert.Equal([]models.Stat{{Name: "0.5", Value: 3120}, {Name: "0.95", Value: 9500}, {Name: "avg", Value: 4876.2}}, sizes.ResponseSizes.Stats)
+	prom.AssertExpectations(t)
+}
+
+func TestGetWorkloadSizeMetricsSelectedQuantile(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	queryTime := time.Date(2017, 01, 15, 0, 0, 0, 0, time.UTC)
+	labels := `{reporter="source",source_workload_namespace="bookinfo",source_workload="productpage-v1"}`
+	quantiles := []string{"0.9"}
+	prom := new(prometheustest.PromClientMock)
+	prom.On("FetchRateValues", "istio_requests_total", labels, "", "10m", queryTime).Return(recordedVector(t, `[{"metric":{},"value":[1484438400,"3"]}]`), nil)
+	prom.On("FetchHistogramValues", "istio_request_bytes", labels, "", "10m", true, quantiles, queryTime).Return(map[string]model.Vector{
+		"avg": recordedVector(t, `[{"metric":{},"value":[1484438400,"120"]}]`),
+		"0.9": recordedVector(t, `[{"metric":{},"value":[1484438400,"200"]}]`),
+	}, nil)
+	prom.On("FetchHistogramValues", "istio_response_bytes", labels, "", "10m", true, quantiles, queryTime).Return(map[string]model.Vector{
+		"avg": recordedVector(t, `[{"metric":{},"value":[1484438400,"2000"]}]`),
+		"0.9": recordedVector(t, `[{"metric":{},"value":[1484438400,"3500"]}]`),
+	}, nil)
+
+	sizes, err := NewMetricsService(prom).GetWorkloadSizeMetrics(models.WorkloadSizeMetricsQuery{
+		Namespace:    "bookinfo",
+		Workload:     "productpage-v1",
+		Direction:    "outbound",
+		RateInterval: "10m",
+		Quantiles:    quantiles,
+		QueryTime:    queryTime,
+	})
+
+	assert.NoError(err)
+	assert.Equal([]models.Stat{{Name: "0.9", Value: 200}, {Name: "avg", Value: 120}}, sizes.RequestSizes.Stats)
+	assert.Equal([]models.Stat{{Name: "0.9", Value: 3500}, {Name: "avg", Value: 2000}}, sizes.ResponseSizes.Stats)
+	prom.AssertExpectations(t)
+}
+
+// Context: the request size metric is disabled, the buckets of the response size histogram are dropped
+func TestWorkloadSizeMetricsHistogramsDisabled(t *testing.T) {
+	assert := assert.New(t)
+
+	quantiles := []string{"0.5", "0.99"}
+	requestSizes := buildSizeDistribution(map[string]model.Vector{"avg": {}, "0.5": {}, "0.99": {}}, quantiles, true)
+	assert.True(requestSizes.HistogramDisabled)
+	assert.Empty(requestSizes.Stats)
+
+	responseSizes := buildSizeDistribution(map[string]model.Vector{
+		"avg":  recordedVector(t, `[{"metric":{},"value":[1484438400,"4876.2"]}]`),
+		"0.5":  recordedVector(t, `[{"metric":{},"value":[1484438400,"NaN"]}]`),
+		"0.99": {},
+	}, quantiles, true)
+	assert.True(responseSizes.HistogramDisabled)
+	assert.Equal([]models.Stat{{Name: "avg", Value: 4876.2}}, responseSizes.Stats)
+
+	// Only the average requested
+	requestSizes = buildSizeDistribution(map[string]model.Vector{"avg": {}}, []string{}, true)
+	assert.True(requestSizes.HistogramDisabled)
+}
+
+func TestWorkloadSizeMetricsWithoutTraffic(t *testing.T) {
+	assert := assert.New(t)
+
+	sizes := buildSizeDistribution(map[string]model.Vector{"avg": {}, "0.5": {}}, []string{"0.5"}, false)
+	assert.False(sizes.HistogramDisabled)
+	assert.Empty(sizes.Stats)
+}
+
+// recordedVector decodes a vector as recorded from the results of the Prometheus query API
+func recordedVector(t *testing.T, result string) model.Vector {
+	var vector model.Vector
+	if err := json.Unmarshal([]byte(result), &vector); err != nil {
+		t.Fatal(err)
+	}
+	return vector
+}
@@ -0,0 +1,121 @@
+package business
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"k8s.io/apimachinery/pkg/api/errors"
+
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/prometheus/internalmetrics"
+)
+
+// burnRateAlertSpec is a multi-window burn rate alert, firing when the given fraction of the error budget is consumed
+// within the long window. These are the alerts recommended by the SRE workbook, for a 30d window they fire at the
+// burn rates 14.4 and 6.
+type burnRateAlertSpec struct {
+	name           string
+	longWindow     string
+	shortWindow    string
+	budgetFraction float64
+}
+
+var burnRateAlertSpecs = []burnRateAlertSpec{
+	{name: "fast", longWindow: "1h", shortWindow: "5m", budgetFraction: 0.02},
+	{name: "slow", longWindow: "6h", shortWindow: "30m", budgetFraction: 0.05},
+}
+
+// GetServiceSLOBurnRate computes the error budget consumed by a service over the window of its SLO, and the burn rates
+// of the multi-window alerts, from the requests received by the service.
+func (in *MetricsService) GetServiceSLOBurnRate(q models.ServiceSLOQuery) (*models.ServiceSLO, error) {
+	var err error
+	promtimer := internalmetrics.GetGoFunctionMetric("business", "MetricsService", "GetServiceSLOBurnRate")
+	defer promtimer.ObserveNow(&err)
+
+	if q.Target <= 0 || q.Target >= 100 {
+		err = errors.NewBadRequest(fmt.Sprintf("the SLO target must be a percentage between 0 and 100 exclusive: %v", q.Target))
+		return nil, err
+	}
+	var window model.Duration
+	window, err = model.ParseDuration(q.Window)
+	if err != nil {
+		err = errors.NewBadRequest(fmt.Sprintf("invalid SLO window [%s]: %v", q.Window, err))
+		return nil, err
+	}
+
+	lb := NewMetricsLabelsBuilder("inbound")
+	lb.SelfReporter()
+	lb.Service(q.Service, q.Namespace)
+	labels := lb.Build()
+	grouping := telemetryGrouping("response_code,grpc_response_status")
+
+	// The error ratio of every window, each window being fetched once
+	errorRatios := map[string]float64{}
+	windows := []string{q.Window}
+	for _, spec := range burnRateAlertSpecs {
+		windows = append(windows, spec.longWindow, spec.shortWindow)
+	}
+	for _, w := range windows {
+		if _, found := errorRatios[w]; found {
+			continue
+		}
+		var rates model.Vector
+		rates, err = in.prom.FetchRateValues(telemetryMetric("istio_requests_total"), labels, grouping, w, q.QueryTime)
+		if err != nil {
+			return nil, err
+		}
+		errorRatios[w] = errorRatio(rates)
+	}
+
+	errorBudget := 1 - q.Target/100
+	slo := &models.ServiceSLO{
+		Namespace:  q.Namespace,
+		Service:    q.Service,
+		Target:     q.Target,
+		Window:     q.Window,
+		ErrorRatio: errorRatios[q.Window],
+		BurnRate:   errorRatios[q.Window] / errorBudget,
+		Alerts:     make([]models.BurnRateAlert, 0, len(burnRateAlertSpecs)),
+	}
+	// The burn rate over the window of the SLO is the fraction of the budget consumed
+	slo.BudgetConsumed = slo.BurnRate
+
+	for _, spec := range burnRateAlertSpecs {
+		longWindow, _ := model.ParseDuration(spec.longWindow)
+		alert := models.BurnRateAlert{
+			Name:          spec.name,
+			LongWindow:    spec.longWindow,
+			ShortWindow:   spec.shortWindow,
+			Threshold:     spec.budgetFraction * float64(time.Duration(window)) / float64(time.Duration(longWindow)),
+			LongBurnRate:  errorRatios[spec.longWindow] / errorBudget,
+			ShortBurnRate: errorRatios[spec.shortWindow] / errorBudget,
+		}
+		alert.Exceeded = alert.LongBurnRate > alert.Threshold && alert.ShortBurnRate > alert.Threshold
+		slo.Alerts = append(slo.Alerts, alert)
+	}
+	return slo, nil
+}
+
+// errorRatio returns the ratio of the requests in error, 0 without requests
+func errorRatio(rates model.Vector) float64 {
+	lblCode := model.LabelName(telemetryLabel("response_code"))
+	lblGrpcStatus := model.LabelName(telemetryLabel("grpc_response_status"))
+
+	total, errs := 0.0, 0.0
+	for _, sample := range rates {
+		value := float64(sample.Value)
+		if math.IsNaN(value) {
+			continue
+		}
+		total += value
+		if isErrorResponse(string(sample.Metric[lblCode]), string(sample.Metric[lblGrpcStatus])) {
+			errs += value
+		}
+	}
+	if total == 0 {
+		return 0
+	}
+	return errs / total
+}
@@ -0,0 +1,110 @@
+package business
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/api/errors"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/prometheus/prometheustest"
+)
+
+const sloLabels = `{reporter="destination",destination_service_name="reviews",destination_service_namespace="bookinfo"}`
+
+func TestGetServiceSLOBurnRate(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	queryTime := time.Date(2017, 01, 15, 0, 0, 0, 0, time.UTC)
+	prom := new(prometheustest.PromClientMock)
+	mockSLORates(prom, queryTime, map[string]model.Vector{
+		"30d": recordedVector(t, `[{"metric":{"response_code":"200"},"value":[1484438400,"99.95"]},{"metric":{"response_code":"503"},"value":[1484438400,"0.05"]}]`),
+		"1h":  recordedVector(t, `[{"metric":{"response_code":"200"},"value":[1484438400,"98"]},{"metric":{"response_code":"503"},"value":[1484438400,"2"]}]`),
+		"5m":  recordedVector(t, `[{"metric":{"response_code":"200"},"value":[1484438400,"97"]},{"metric":{"response_code":"500"},"value":[1484438400,"3"]}]`),
+		"6h":  recordedVector(t, `[{"metric":{"response_code":"200"},"value":[1484438400,"99.5"]},{"metric":{"response_code":"503"},"value":[1484438400,"0.5"]}]`),
+		"30m": recordedVector(t, `[{"metric":{"response_code":"200","grpc_response_status":"0"},"value":[1484438400,"99"]},{"metric":{"response_code":"200","grpc_response_status":"14"},"value":[1484438400,"1"]}]`),
+	})
+
+	slo, err := NewMetricsService(prom).GetServiceSLOBurnRate(models.ServiceSLOQuery{
+		Namespace: "bookinfo",
+		Service:   "reviews",
+		Target:    99.9,
+		Window:    "30d",
+		QueryTime: queryTime,
+	})
+
+	assert.NoError(err)
+	assert.Equal("reviews", slo.Service)
+	assert.InDelta(0.0005, slo.ErrorRatio, 1e-9)
+	assert.InDelta(0.5, slo.BudgetConsumed, 1e-6)
+	assert.InDelta(0.5, slo.BurnRate, 1e-6)
+	assert.Len(slo.Alerts, 2)
+
+	fast := slo.Alerts[0]
+	assert.Equal("fast", fast.Name)
+	assert.InDelta(14.4, fast.Threshold, 1e-9)
+	assert.InDelta(20, fast.LongBurnRate, 1e-6)
+	assert.InDelta(30, fast.ShortBurnRate, 1e-6)
+	assert.True(fast.Exceeded)
+
+	// The errors of the last 30 minutes are not enough for the slow alert over 6 hours
+	slow := slo.Alerts[1]
+	assert.Equal("slow", slow.Name)
+	assert.InDelta(6, slow.Threshold, 1e-9)
+	assert.InDelta(5, slow.LongBurnRate, 1e-6)
+	assert.InDelta(10, slow.ShortBurnRate, 1e-6)
+	assert.False(slow.Exceeded)
+	prom.AssertExpectations(t)
+}
+
+func TestGetServiceSLOBurnRateWithoutTraffic(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	queryTime := time.Date(2017, 01, 15, 0, 0, 0, 0, time.UTC)
+	prom := new(prometheustest.PromClientMock)
+	mockSLORates(prom, queryTime, map[string]model.Vector{
+		"7d": {}, "1h": {}, "5m": {}, "6h": {}, "30m": {},
+	})
+
+	slo, err := NewMetricsService(prom).GetServiceSLOBurnRate(models.ServiceSLOQuery{
+		Namespace: "bookinfo",
+		Service:   "reviews",
+		Target:    99,
+		Window:    "7d",
+		QueryTime: queryTime,
+	})
+
+	assert.NoError(err)
+	assert.Equal(0.0, slo.BudgetConsumed)
+	// The thresholds follow the window of the SLO
+	assert.InDelta(3.36, slo.Alerts[0].Threshold, 1e-9)
+	assert.InDelta(1.4, slo.Alerts[1].Threshold, 1e-9)
+	for _, alert := range slo.Alerts {
+		assert.False(alert.Exceeded)
+	}
+}
+
+func TestGetServiceSLOBurnRateBadRequest(t *testing.T) {
+	config.Set(config.NewConfig())
+	service := NewMetricsService(new(prometheustest.PromClientMock))
+
+	for _, q := range []models.ServiceSLOQuery{
+		{Namespace: "bookinfo", Service: "reviews", Target: 100, Window: "30d"},
+		{Namespace: "bookinfo", Service: "reviews", Target: 0, Window: "30d"},
+		{Namespace: "bookinfo", Service: "reviews", Target: 99.9, Window: "month"},
+	} {
+		_, err := service.GetServiceSLOBurnRate(q)
+		assert.True(t, errors.IsBadRequest(err))
+	}
+}
+
+func mockSLORates(prom *prometheustest.PromClientMock, queryTime time.Time, rates map[string]model.Vector) {
+	for window, vector := range rates {
+		prom.On("FetchRateValues", "istio_requests_total", sloLabels, "response_code,grpc_response_status", window, queryTime).Return(vector, nil).Once()
+	}
+}
@@ -0,0 +1,139 @@
+package business
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/prometheus/internalmetrics"
+)
+
+// GetServiceSubsetHealth returns the inbound success rate and latencies of the workloads of a DestinationRule subset
+// of the service. The pods of the subset are the pods of the service matching the labels of the subset, the metrics
+// are scoped to their workloads.
+func (in *HealthService) GetServiceSubsetHealth(namespace, service, subset, rateInterval string, queryTime time.Time) (*models.SubsetHealth, error) {
+	var err error
+	promtimer := internalmetrics.GetGoFunctionMetric("business", "HealthService", "GetServiceSubsetHealth")
+	defer promtimer.ObserveNow(&err)
+
+	// Check if user has access to the namespace (RBAC) in cache scenarios and/or
+	// if namespace is accessible from Kiali (Deployment.AccessibleNamespaces)
+	if _, err = in.businessLayer.Namespace.GetNamespace(namespace); err != nil {
+		return nil, err
+	}
+
+	svc, err := in.businessLayer.Svc.getService(namespace, service)
+	if err != nil {
+		return nil, err
+	}
+
+	var drs []kubernetes.IstioObject
+	if IsResourceCached(namespace, kubernetes.DestinationRules) {
+		drs, err = kialiCache.GetIstioObjects(namespace, kubernetes.DestinationRules, "")
+	} else {
+		drs, err = in.k8s.GetIstioObjects(namespace, kubernetes.DestinationRules, "")
+	}
+	if err != nil {
+		return nil, err
+	}
+	drs = kubernetes.FilterDestinationRules(drs, namespace, service)
+
+	subsetSelector, found := subsetLabels(drs, subset, namespace, service)
+	if !found {
+		err = kubernetes.NewNotFound(subset, "Kiali", "Subset")
+		return nil, err
+	}
+
+	health := &models.SubsetHealth{
+		Namespace:      namespace,
+		Service:        service,
+		Subset:         subset,
+		Labels:         subsetSelector,
+		Workloads:      []string{},
+		VariantMetrics: models.VariantMetrics{Latencies: []models.Stat{}},
+	}
+
+	// Without selector, the pods of the service are unknown
+	if len(svc.Spec.Selector) == 0 {
+		return health, nil
+	}
+	selector := labels.Set{}
+	for k, v := range svc.Spec.Selector {
+		selector[k] = v
+	}
+	for k, v := range subsetSelector {
+		selector[k] = v
+	}
+	ws, err := fetchWorkloads(in.businessLayer, namespace, selector.String())
+	if err != nil {
+		return nil, err
+	}
+	for _, w := range ws {
+		if len(w.Pods) > 0 {
+			health.Workloads = append(health.Workloads, w.Name)
+			health.Pods += len(w.Pods)
+		}
+	}
+	// No pod matches the subset: there is no traffic to measure
+	if health.Pods == 0 {
+		return health, nil
+	}
+	sort.Strings(health.Workloads)
+
+	lb := NewMetricsLabelsBuilder("inbound")
+	lb.SelfReporter()
+	lb.Service(service, namespace)
+	lb.Workloads(health.Workloads, namespace)
+
+	var rates model.Vector
+	rates, err = in.prom.FetchRateValues(telemetryMetric("istio_requests_total"), lb.Build(), telemetryGrouping("response_code,grpc_response_status"), rateInterval, queryTime)
+	if err != nil {
+		return nil, err
+	}
+	var latencies map[string]model.Vector
+	latencies, err = in.prom.FetchHistogramValues(telemetryMetric("istio_request_duration_milliseconds"), lb.Build(), "", rateInterval, true, latencyQuantiles, queryTime)
+	if err != nil {
+		return nil, err
+	}
+	fillSubsetMetrics(&health.VariantMetrics, rates, latencies)
+	return health, nil
+}
+
+func fillSubsetMetrics(metrics *models.VariantMetrics, rates model.Vector, latencies map[string]model.Vector) {
+	lblCode := model.LabelName(telemetryLabel("response_code"))
+	lblGrpcStatus := model.LabelName(telemetryLabel("grpc_response_status"))
+
+	errorRate := 0.0
+	for _, sample := range rates {
+		value := float64(sample.Value)
+		if math.IsNaN(value) {
+			continue
+		}
+		metrics.RequestRate += value
+		if isErrorResponse(string(sample.Metric[lblCode]), string(sample.Metric[lblGrpcStatus])) {
+			errorRate += value
+		}
+	}
+	if metrics.RequestRate > 0 {
+		success := (metrics.RequestRate - errorRate) / metrics.RequestRate
+		metrics.SuccessRate = &success
+	}
+
+	for stat, vec := range latencies {
+		for _, sample := range vec {
+			value := float64(sample.Value)
+			if math.IsNaN(value) {
+				continue
+			}
+			metrics.Latencies = append(metrics.Latencies, models.Stat{Name: stat, Value: value})
+		}
+	}
+	sort.Slice(metrics.Latencies, func(i, j int) bool {
+		return metrics.Latencies[i].Name < metrics.Latencies[j].Name
+	})
+}
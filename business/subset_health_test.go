@@ -0,0 +1,177 @@
+package business
+
+import (
+	"testing"
+	"time"
+
+	osapps_v1 "github.com/openshift/api/apps/v1"
+	osproject_v1 "github.com/openshift/api/project/v1"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	apps_v1 "k8s.io/api/apps/v1"
+	batch_v1 "k8s.io/api/batch/v1"
+	batch_v1beta1 "k8s.io/api/batch/v1beta1"
+	core_v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/kubernetes/kubetest"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/prometheus/prometheustest"
+	"github.com/kiali/kiali/tests/data"
+)
+
+func TestServiceSubsetHealth(t *testing.T) {
+	assert := assert.New(t)
+
+	k8s, prom := setupSubsetHealthMocks([]core_v1.Pod{
+		fakeSubsetPod("reviews-v1-1", "v1"),
+		fakeSubsetPod("reviews-v1-2", "v1"),
+	})
+	queryTime := time.Date(2017, 01, 15, 0, 0, 0, 0, time.UTC)
+	labels := `{reporter="destination",destination_service_name="reviews",destination_service_namespace="bookinfo",destination_workload_namespace="bookinfo",destination_workload=~"reviews-v1"}`
+	prom.On("FetchRateValues", "istio_requests_total", labels, "response_code,grpc_response_status", "1m", queryTime).Return(model.Vector{
+		fakeResponseRate("200", "", 9),
+		fakeResponseRate("500", "", 1),
+	}, nil)
+	prom.On("FetchHistogramValues", "istio_request_duration_milliseconds", labels, "", "1m", true, latencyQuantiles, queryTime).Return(map[string]model.Vector{
+		"0.5":  {&model.Sample{Value: 10}},
+		"0.99": {&model.Sample{Value: 250}},
+	}, nil)
+
+	hs := HealthService{k8s: k8s, prom: prom, businessLayer: NewWithBackends(k8s, prom, nil)}
+	health, err := hs.GetServiceSubsetHealth("bookinfo", "reviews", "v1", "1m", queryTime)
+
+	assert.NoError(err)
+	assert.Equal(map[string]string{"version": "v1"}, health.Labels)
+	assert.Equal([]string{"reviews-v1"}, health.Workloads)
+	assert.Equal(2, health.Pods)
+	assert.InDelta(10.0, health.RequestRate, 0.001)
+	assert.InDelta(0.9, *health.SuccessRate, 0.001)
+	assert.Equal([]models.Stat{{Name: "0.5", Value: 10}, {Name: "0.99", Value: 250}}, health.Latencies)
+}
+
+func TestServiceSubsetHealthWithoutPods(t *testing.T) {
+	assert := assert.New(t)
+
+	k8s, prom := setupSubsetHealthMocks([]core_v1.Pod{})
+	hs := HealthService{k8s: k8s, prom: prom, businessLayer: NewWithBackends(k8s, prom, nil)}
+	health, err := hs.GetServiceSubsetHealth("bookinfo", "reviews", "v1", "1m", time.Now())
+
+	assert.NoError(err)
+	assert.Empty(health.Workloads)
+	assert.Zero(health.Pods)
+	assert.Zero(health.RequestRate)
+	assert.Nil(health.SuccessRate)
+	prom.AssertNotCalled(t, "FetchRateValues", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestServiceSubsetHealthUnknownSubset(t *testing.T) {
+	assert := assert.New(t)
+
+	k8s, prom := setupSubsetHealthMocks([]core_v1.Pod{})
+	hs := HealthService{k8s: k8s, prom: prom, businessLayer: NewWithBackends(k8s, prom, nil)}
+	_, err := hs.GetServiceSubsetHealth("bookinfo", "reviews", "v3", "1m", time.Now())
+
+	assert.Error(err)
+	assert.True(errors.IsNotFound(err))
+}
+
+func TestFillSubsetMetricsGrpc(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	metrics := models.VariantMetrics{Latencies: []models.Stat{}}
+	fillSubsetMetrics(&metrics, model.Vector{
+		fakeResponseRate("200", "0", 3),
+		fakeResponseRate("200", "14", 1),
+	}, map[string]model.Vector{})
+
+	assert.InDelta(4.0, metrics.RequestRate, 0.001)
+	assert.InDelta(0.75, *metrics.SuccessRate, 0.001)
+	assert.Empty(metrics.Latencies)
+}
+
+func setupSubsetHealthMocks(pods []core_v1.Pod) (*kubetest.K8SClientMock, *prometheustest.PromClientMock) {
+	config.Set(config.NewConfig())
+
+	k8s := new(kubetest.K8SClientMock)
+	prom := new(prometheustest.PromClientMock)
+	k8s.On("IsOpenShift").Return(true)
+	k8s.On("GetProject", "bookinfo").Return(&osproject_v1.Project{ObjectMeta: meta_v1.ObjectMeta{Name: "bookinfo"}}, nil)
+	k8s.On("GetService", "bookinfo", "reviews").Return(&core_v1.Service{
+		ObjectMeta: meta_v1.ObjectMeta{Name: "reviews", Namespace: "bookinfo"},
+		Spec:       core_v1.ServiceSpec{Selector: map[string]string{"app": "reviews"}},
+	}, nil)
+	k8s.On("GetIstioObjects", "bookinfo", kubernetes.DestinationRules, "").Return([]kubernetes.IstioObject{
+		data.CreateTestDestinationRule("bookinfo", "reviews", "reviews"),
+	}, nil)
+	k8s.On("GetReplicaSets", "bookinfo").Return([]apps_v1.ReplicaSet{fakeSubsetReplicaSet("reviews-v1", "v1")}, nil)
+	k8s.On("GetReplicationControllers", "bookinfo").Return([]core_v1.ReplicationController{}, nil)
+	k8s.On("GetDeploymentConfigs", "bookinfo").Return([]osapps_v1.DeploymentConfig{}, nil)
+	k8s.On("GetStatefulSets", "bookinfo").Return([]apps_v1.StatefulSet{}, nil)
+	k8s.On("GetJobs", "bookinfo").Return([]batch_v1.Job{}, nil)
+	k8s.On("GetCronJobs", "bookinfo").Return([]batch_v1beta1.CronJob{}, nil)
+	k8s.On("GetDeployments", "bookinfo").Return([]apps_v1.Deployment{
+		fakeSubsetDeployment("reviews-v1", "v1"),
+		fakeSubsetDeployment("reviews-v2", "v2"),
+	}, nil)
+	k8s.On("GetPods", "bookinfo", "app=reviews,version=v1").Return(pods, nil)
+	return k8s, prom
+}
+
+func fakeSubsetDeployment(name, version string) apps_v1.Deployment {
+	labels := map[string]string{"app": "reviews", "version": version}
+	return apps_v1.Deployment{
+		ObjectMeta: meta_v1.ObjectMeta{Name: name, Namespace: "bookinfo"},
+		Spec: apps_v1.DeploymentSpec{
+			Selector: &meta_v1.LabelSelector{MatchLabels: labels},
+			Template: core_v1.PodTemplateSpec{ObjectMeta: meta_v1.ObjectMeta{Labels: labels}},
+		},
+	}
+}
+
+func fakeSubsetReplicaSet(deployment, version string) apps_v1.ReplicaSet {
+	controller := true
+	return apps_v1.ReplicaSet{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      deployment + "-7f9c",
+			Namespace: "bookinfo",
+			OwnerReferences: []meta_v1.OwnerReference{{
+				Controller: &controller,
+				Kind:       "Deployment",
+				Name:       deployment,
+			}},
+		},
+		Spec: apps_v1.ReplicaSetSpec{
+			Template: core_v1.PodTemplateSpec{ObjectMeta: meta_v1.ObjectMeta{Labels: map[string]string{"app": "reviews", "version": version}}},
+		},
+	}
+}
+
+func fakeSubsetPod(name, version string) core_v1.Pod {
+	controller := true
+	return core_v1.Pod{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      name,
+			Namespace: "bookinfo",
+			Labels:    map[string]string{"app": "reviews", "version": version},
+			OwnerReferences: []meta_v1.OwnerReference{{
+				Controller: &controller,
+				Kind:       "ReplicaSet",
+				Name:       "reviews-" + version + "-7f9c",
+			}},
+			Annotations: kubetest.FakeIstioAnnotations(),
+		},
+	}
+}
+
+func fakeResponseRate(code, grpcStatus string, rate float64) *model.Sample {
+	return &model.Sample{
+		Metric: model.Metric{"response_code": model.LabelValue(code), "grpc_response_status": model.LabelValue(grpcStatus)},
+		Value:  model.SampleValue(rate),
+	}
+}
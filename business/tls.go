@@ -1,12 +1,17 @@
 package business
 
 import (
+	"fmt"
+	"sort"
+	"strings"
 	"sync"
 
 	core_v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
 
 	"github.com/kiali/kiali/config"
 	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/log"
 	"github.com/kiali/kiali/models"
 	"github.com/kiali/kiali/util/mtls"
 )
@@ -24,41 +29,167 @@ const (
 	MTLSDisabled         = "MTLS_DISABLED"
 )
 
+// MeshWidemTLSStatus returns the mesh-wide mTLS status of the namespaces. When the namespaces use control plane
+// revisions with different root namespaces, the status is computed for the mesh-wide PeerAuthentications of each
+// root namespace: it is partially enabled when they don't agree.
 func (in *TLSService) MeshWidemTLSStatus(namespaces []string) (models.MTLSStatus, error) {
-	pas, error := in.getMeshPeerAuthentications()
+	rootNamespaces, error := in.meshRootNamespaces(namespaces)
 	if error != nil {
 		return models.MTLSStatus{}, error
 	}
 
+	cacheKey := in.tlsStatusCacheKey("mesh", rootNamespaces, namespaces)
+	var generation uint64
+	if cacheKey != "" {
+		generation = kialiCache.GetConfigGeneration()
+		if status, found := kialiCache.GetTLSStatus(cacheKey, generation); found {
+			return status, nil
+		}
+	}
+
 	drs, error := in.getAllDestinationRules(namespaces)
 	if error != nil {
 		return models.MTLSStatus{}, error
 	}
 
-	mtlsStatus := mtls.MtlsStatus{
-		PeerAuthentications: pas,
-		DestinationRules:    drs,
-		AutoMtlsEnabled:     in.hasAutoMTLSEnabled(),
-		AllowPermissive:     false,
+	statuses := make([]string, 0, len(rootNamespaces))
+	for _, rootNamespace := range rootNamespaces {
+		pas, error := in.getMeshPeerAuthentications(rootNamespace)
+		if error != nil {
+			return models.MTLSStatus{}, error
+		}
+
+		mtlsStatus := mtls.MtlsStatus{
+			PeerAuthentications: pas,
+			DestinationRules:    drs,
+			AutoMtlsEnabled:     in.hasAutoMTLSEnabled(),
+			AllowPermissive:     false,
+		}
+		statuses = append(statuses, mtlsStatus.MeshMtlsStatus().OverallStatus)
 	}
 
-	return models.MTLSStatus{
-		Status: mtlsStatus.MeshMtlsStatus().OverallStatus,
-	}, nil
+	status := models.MTLSStatus{
+		Status: combineMeshMtlsStatuses(statuses),
+	}
+	if cacheKey != "" {
+		kialiCache.SetTLSStatus(cacheKey, generation, status)
+	}
+
+	return status, nil
+}
+
+// combineMeshMtlsStatuses returns the status shared by all the root namespaces, partially enabled when they differ
+func combineMeshMtlsStatuses(statuses []string) string {
+	for _, status := range statuses[1:] {
+		if status != statuses[0] {
+			return MTLSPartiallyEnabled
+		}
+	}
+	return statuses[0]
+}
+
+// tlsStatusCacheKey returns the key used to store a computed mTLS status in the Kiali cache.
+// It returns an empty key when any of the inputs is not watched by the cache, as then a change
+// in the PeerAuthentications or DestinationRules wouldn't invalidate the stored status.
+func (in *TLSService) tlsStatusCacheKey(scope string, peerAuthnNamespaces []string, namespaces []string) string {
+	if kialiCache == nil {
+		return ""
+	}
+	for _, ns := range peerAuthnNamespaces {
+		if !IsResourceCached(ns, kubernetes.PeerAuthentications) {
+			return ""
+		}
+	}
+	for _, ns := range namespaces {
+		if !IsResourceCached(ns, kubernetes.DestinationRules) {
+			return ""
+		}
+	}
+
+	nss := make([]string, len(namespaces))
+	copy(nss, namespaces)
+	sort.Strings(nss)
+	return fmt.Sprintf("%s:%s:%t:%s", scope, strings.Join(peerAuthnNamespaces, ","), in.hasAutoMTLSEnabled(), strings.Join(nss, ","))
 }
 
-func (in *TLSService) getMeshPeerAuthentications() ([]kubernetes.IstioObject, error) {
+// getMeshPeerAuthentications returns the PeerAuthentications of a root namespace, which apply to the whole mesh
+func (in *TLSService) getMeshPeerAuthentications(rootNamespace string) ([]kubernetes.IstioObject, error) {
 	var mps []kubernetes.IstioObject
 	var err error
-	controlPlaneNs := config.Get().IstioNamespace
-	if IsResourceCached(controlPlaneNs, kubernetes.PeerAuthentications) {
-		mps, err = kialiCache.GetIstioObjects(controlPlaneNs, kubernetes.PeerAuthentications, "")
+	if IsResourceCached(rootNamespace, kubernetes.PeerAuthentications) {
+		mps, err = kialiCache.GetIstioObjects(rootNamespace, kubernetes.PeerAuthentications, "")
 	} else {
-		mps, err = in.k8s.GetIstioObjects(controlPlaneNs, kubernetes.PeerAuthentications, "")
+		mps, err = in.k8s.GetIstioObjects(rootNamespace, kubernetes.PeerAuthentications, "")
 	}
 	return mps, err
 }
 
+// meshRootNamespaces returns the root namespaces of the revisions used by the namespaces, sorted. The Istio
+// namespace is returned when no namespace is given.
+func (in *TLSService) meshRootNamespaces(namespaces []string) ([]string, error) {
+	if len(namespaces) == 0 {
+		return []string{config.Get().IstioNamespace}, nil
+	}
+	revisionRoots := map[string]string{}
+	found := map[string]bool{}
+	rootNamespaces := []string{}
+	for _, namespace := range namespaces {
+		ns, err := in.businessLayer.Namespace.GetNamespace(namespace)
+		if err != nil {
+			return nil, err
+		}
+		rootNamespace, err := in.rootNamespace(ns, revisionRoots)
+		if err != nil {
+			return nil, err
+		}
+		if !found[rootNamespace] {
+			found[rootNamespace] = true
+			rootNamespaces = append(rootNamespaces, rootNamespace)
+		}
+	}
+	sort.Strings(rootNamespaces)
+	return rootNamespaces, nil
+}
+
+// rootNamespace returns the root namespace set in the mesh config of the control plane revision used by the
+// namespace (istio.io/rev label), read from its "<config_map_name>-<revision>" ConfigMap. The default revision, as
+// the revisions whose ConfigMap is not found, uses the Istio namespace. The root namespaces of the revisions already
+// resolved are kept in revisionRoots.
+func (in *TLSService) rootNamespace(namespace *models.Namespace, revisionRoots map[string]string) (string, error) {
+	istioNamespace := config.Get().IstioNamespace
+	revision := namespace.Labels[IstioRevisionLabel]
+	if revision == "" || revision == DefaultRevision {
+		return istioNamespace, nil
+	}
+	if rootNamespace, found := revisionRoots[revision]; found {
+		return rootNamespace, nil
+	}
+
+	rootNamespace := istioNamespace
+	configMapName := config.Get().ExternalServices.Istio.ConfigMapName + "-" + revision
+	var istioConfig *core_v1.ConfigMap
+	var err error
+	if IsNamespaceCached(istioNamespace) {
+		istioConfig, err = kialiCache.GetConfigMap(istioNamespace, configMapName)
+	} else {
+		istioConfig, err = in.k8s.GetConfigMap(istioNamespace, configMapName)
+	}
+	if err != nil {
+		if !errors.IsNotFound(err) {
+			return "", err
+		}
+		log.Debugf("Mesh config of revision [%s] not found, using the root namespace [%s]", revision, istioNamespace)
+	} else {
+		meshConfig, err := models.ParseMeshConfig(istioConfig.Data["mesh"], models.DefaultMeshConfig(istioNamespace))
+		if err != nil {
+			return "", err
+		}
+		rootNamespace = meshConfig.RootNamespace
+	}
+	revisionRoots[revision] = rootNamespace
+	return rootNamespace, nil
+}
+
 func (in *TLSService) getAllDestinationRules(namespaces []string) ([]kubernetes.IstioObject, error) {
 	drChan := make(chan []kubernetes.IstioObject, len(namespaces))
 	errChan := make(chan error, 1)
@@ -106,12 +237,23 @@ func (in *TLSService) getAllDestinationRules(namespaces []string) ([]kubernetes.
 }
 
 func (in TLSService) NamespaceWidemTLSStatus(namespace string) (models.MTLSStatus, error) {
-	pas, err := in.getPeerAuthentications(namespace)
+	nss, rootNamespace, err := in.getNamespaces(namespace)
 	if err != nil {
 		return models.MTLSStatus{}, nil
 	}
 
-	nss, err := in.getNamespaces()
+	// The generation is read before fetching the config, so that a status computed from a config changed meanwhile
+	// is cached for an outdated generation
+	cacheKey := in.tlsStatusCacheKey("namespace", []string{namespace}, nss)
+	var generation uint64
+	if cacheKey != "" {
+		generation = kialiCache.GetConfigGeneration()
+		if status, found := kialiCache.GetTLSStatus(cacheKey, generation); found {
+			return status, nil
+		}
+	}
+
+	pas, err := in.getPeerAuthentications(namespace, rootNamespace)
 	if err != nil {
 		return models.MTLSStatus{}, nil
 	}
@@ -121,6 +263,29 @@ func (in TLSService) NamespaceWidemTLSStatus(namespace string) (models.MTLSStatu
 		return models.MTLSStatus{}, nil
 	}
 
+	status := in.namespaceMtlsStatus(namespace, pas, drs)
+	if cacheKey != "" {
+		kialiCache.SetTLSStatus(cacheKey, generation, status)
+	}
+
+	return status, nil
+}
+
+// namespaceWidemTLSStatus returns the namespace-wide mTLS status from the PeerAuthentications of the namespace and
+// the DestinationRules of the mesh already fetched, e.g. for the namespace overview
+func (in TLSService) namespaceWidemTLSStatus(namespace string, mtlsDetails kubernetes.MTLSDetails) (models.MTLSStatus, error) {
+	_, rootNamespace, err := in.getNamespaces(namespace)
+	if err != nil {
+		return models.MTLSStatus{}, err
+	}
+	pas := mtlsDetails.PeerAuthentications
+	if namespace == rootNamespace {
+		pas = []kubernetes.IstioObject{}
+	}
+	return in.namespaceMtlsStatus(namespace, pas, mtlsDetails.DestinationRules), nil
+}
+
+func (in TLSService) namespaceMtlsStatus(namespace string, pas, drs []kubernetes.IstioObject) models.MTLSStatus {
 	mtlsStatus := mtls.MtlsStatus{
 		Namespace:           namespace,
 		PeerAuthentications: pas,
@@ -128,14 +293,15 @@ func (in TLSService) NamespaceWidemTLSStatus(namespace string) (models.MTLSStatu
 		AutoMtlsEnabled:     in.hasAutoMTLSEnabled(),
 		AllowPermissive:     false,
 	}
-
 	return models.MTLSStatus{
 		Status: mtlsStatus.NamespaceMtlsStatus().OverallStatus,
-	}, nil
+	}
 }
 
-func (in TLSService) getPeerAuthentications(namespace string) ([]kubernetes.IstioObject, error) {
-	if namespace == config.Get().IstioNamespace {
+// getPeerAuthentications returns the PeerAuthentications of the namespace, none for the root namespace as they are
+// mesh-wide
+func (in TLSService) getPeerAuthentications(namespace, rootNamespace string) ([]kubernetes.IstioObject, error) {
+	if namespace == rootNamespace {
 		return []kubernetes.IstioObject{}, nil
 	}
 	if IsResourceCached(namespace, kubernetes.PeerAuthentications) {
@@ -145,18 +311,26 @@ func (in TLSService) getPeerAuthentications(namespace string) ([]kubernetes.Isti
 	}
 }
 
-func (in TLSService) getNamespaces() ([]string, error) {
+// getNamespaces returns the names of the namespaces, and the root namespace of the revision used by the given one
+func (in TLSService) getNamespaces(namespace string) ([]string, string, error) {
 	nss, nssErr := in.businessLayer.Namespace.GetNamespaces()
 	if nssErr != nil {
-		return nil, nssErr
+		return nil, "", nssErr
 	}
 
 	nsNames := make([]string, 0)
-	for _, ns := range nss {
+	rootNamespace := config.Get().IstioNamespace
+	for i, ns := range nss {
 		nsNames = append(nsNames, ns.Name)
+		if ns.Name == namespace {
+			var err error
+			if rootNamespace, err = in.rootNamespace(&nss[i], map[string]string{}); err != nil {
+				return nil, "", err
+			}
+		}
 	}
 
-	return nsNames, nil
+	return nsNames, rootNamespace, nil
 }
 
 func (in *TLSService) hasAutoMTLSEnabled() bool {
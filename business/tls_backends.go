@@ -0,0 +1,308 @@
+package business
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	core_v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/kiali/kiali/business/checkers/common"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/prometheus/internalmetrics"
+	"github.com/kiali/kiali/util/mtls"
+)
+
+// gatewayBackend is a Service referenced by the backendRefs of Gateway API routes
+type gatewayBackend struct {
+	namespace string
+	name      string
+	routes    []string
+}
+
+// backendNamespaceTLS holds the TLS configuration applied to the Services of a namespace
+type backendNamespaceTLS struct {
+	peerAuthentications     []kubernetes.IstioObject
+	meshPeerAuthentications []kubernetes.IstioObject
+	backendTLSPolicies      []kubernetes.IstioObject
+}
+
+// BackendsTLSStatus returns the TLS status of the Services routed by the Kubernetes Gateway API HTTPRoutes of the
+// namespace. The Istio mTLS status of each Service is combined with the BackendTLSPolicies targeting it: when one
+// applies, the gateways originate TLS to the Service instead of Istio mTLS. The TLS expectations of both APIs that
+// conflict, like a BackendTLSPolicy targeting workloads requiring Istio mTLS, are reported.
+func (in TLSService) BackendsTLSStatus(namespace string) (models.BackendTLSStatuses, error) {
+	var err error
+	promtimer := internalmetrics.GetGoFunctionMetric("business", "TLSService", "BackendsTLSStatus")
+	defer promtimer.ObserveNow(&err)
+
+	if _, err = in.businessLayer.Namespace.GetNamespace(namespace); err != nil {
+		return nil, err
+	}
+	routes, err := in.k8s.GetIstioObjects(namespace, kubernetes.HTTPRoutes, "")
+	if err != nil {
+		return nil, err
+	}
+	backends := routeBackends(namespace, routes)
+	statuses := make(models.BackendTLSStatuses, 0, len(backends))
+	if len(backends) == 0 {
+		return statuses, nil
+	}
+
+	nss, _, err := in.getNamespaces(namespace)
+	if err != nil {
+		return nil, err
+	}
+	drs, err := in.getAllDestinationRules(nss)
+	if err != nil {
+		return nil, err
+	}
+
+	autoMtls := in.hasAutoMTLSEnabled()
+	namespacesTLS := map[string]*backendNamespaceTLS{}
+	revisionRoots := map[string]string{}
+	for _, backend := range backends {
+		nsTLS, found := namespacesTLS[backend.namespace]
+		if !found {
+			if nsTLS, err = in.getBackendNamespaceTLS(backend.namespace, revisionRoots); err != nil {
+				return nil, err
+			}
+			namespacesTLS[backend.namespace] = nsTLS
+		}
+
+		// A missing Service has no workloads to select
+		var svc *core_v1.Service
+		var svcErr error
+		if IsNamespaceCached(backend.namespace) {
+			svc, svcErr = kialiCache.GetService(backend.namespace, backend.name)
+		} else {
+			svc, svcErr = in.k8s.GetService(backend.namespace, backend.name)
+		}
+		var selector map[string]string
+		if svcErr == nil {
+			selector = svc.Spec.Selector
+		} else if !errors.IsNotFound(svcErr) {
+			err = svcErr
+			return nil, err
+		}
+
+		statuses = append(statuses, backendTLSStatus(backend, selector, nsTLS, drs, autoMtls))
+	}
+	return statuses, nil
+}
+
+// getBackendNamespaceTLS fetches the PeerAuthentications, of the namespace and of the mesh, and the BackendTLSPolicies
+// applied to the Services of a namespace
+func (in TLSService) getBackendNamespaceTLS(namespace string, revisionRoots map[string]string) (*backendNamespaceTLS, error) {
+	ns, err := in.businessLayer.Namespace.GetNamespace(namespace)
+	if err != nil {
+		return nil, err
+	}
+	rootNamespace, err := in.rootNamespace(ns, revisionRoots)
+	if err != nil {
+		return nil, err
+	}
+
+	nsTLS := &backendNamespaceTLS{}
+	if nsTLS.peerAuthentications, err = in.getPeerAuthentications(namespace, rootNamespace); err != nil {
+		return nil, err
+	}
+	if nsTLS.meshPeerAuthentications, err = in.getMeshPeerAuthentications(rootNamespace); err != nil {
+		return nil, err
+	}
+	if nsTLS.backendTLSPolicies, err = in.k8s.GetIstioObjects(namespace, kubernetes.BackendTLSPolicies, ""); err != nil {
+		return nil, err
+	}
+	return nsTLS, nil
+}
+
+func backendTLSStatus(backend *gatewayBackend, selector map[string]string, nsTLS *backendNamespaceTLS, drs []kubernetes.IstioObject, autoMtls bool) models.BackendTLSStatus {
+	paMode, paRef := servicePeerAuthentication(selector, nsTLS.peerAuthentications, nsTLS.meshPeerAuthentications)
+	drMode := serviceDestinationRule(backend.namespace, backend.name, drs)
+	policies := serviceBackendTLSPolicies(backend.name, nsTLS.backendTLSPolicies)
+
+	status := models.BackendTLSStatus{
+		Namespace:              backend.namespace,
+		Service:                backend.name,
+		Routes:                 backend.routes,
+		MTLSStatus:             mtls.MtlsStatus{AutoMtlsEnabled: autoMtls}.ServiceMtlsStatus(drMode, paMode).OverallStatus,
+		DestinationRuleMode:    drMode,
+		PeerAuthenticationMode: paMode,
+		BackendTLSPolicies:     make([]string, 0, len(policies)),
+		Conflicts:              []string{},
+	}
+	for _, policy := range policies {
+		status.BackendTLSPolicies = append(status.BackendTLSPolicies, objectRef(policy))
+	}
+
+	if len(policies) == 0 {
+		status.Mode = drMode
+		if status.Mode == "" {
+			if autoMtls {
+				status.Mode = "ISTIO_MUTUAL"
+			} else {
+				status.Mode = "DISABLE"
+			}
+		}
+		return status
+	}
+
+	status.Mode = "SIMPLE"
+	applied := status.BackendTLSPolicies[0]
+	if len(policies) > 1 {
+		status.Conflicts = append(status.Conflicts, fmt.Sprintf("BackendTLSPolicies [%s] target the Service of the older BackendTLSPolicy [%s] and are ignored",
+			strings.Join(status.BackendTLSPolicies[1:], ", "), applied))
+	}
+	// The namespace-wide and mesh-wide DestinationRules are defaults a BackendTLSPolicy is expected to override
+	if hostMode, hostRef := hostDestinationRule(backend.namespace, backend.name, drs); hostMode != "" && hostMode != "SIMPLE" {
+		status.Conflicts = append(status.Conflicts, fmt.Sprintf("DestinationRule [%s] sets the TLS mode [%s] of the Service, the gateways originate TLS with BackendTLSPolicy [%s] instead",
+			hostRef, hostMode, applied))
+	}
+	if paMode == "STRICT" {
+		status.Conflicts = append(status.Conflicts, fmt.Sprintf("PeerAuthentication [%s] requires Istio mTLS, the TLS originated by the gateways with BackendTLSPolicy [%s] is rejected",
+			paRef, applied))
+	}
+	return status
+}
+
+// routeBackends returns the Services referenced by the backendRefs of the HTTPRoutes, in order of appearance
+func routeBackends(namespace string, routes []kubernetes.IstioObject) []*gatewayBackend {
+	backends := []*gatewayBackend{}
+	index := map[string]*gatewayBackend{}
+	for _, route := range routes {
+		routeRef := objectRef(route)
+		rules, _ := route.GetSpec()["rules"].([]interface{})
+		for _, r := range rules {
+			rule, _ := r.(map[string]interface{})
+			backendRefs, _ := rule["backendRefs"].([]interface{})
+			for _, br := range backendRefs {
+				backendRef, ok := br.(map[string]interface{})
+				if !ok || !isServiceRef(backendRef) {
+					continue
+				}
+				name, _ := backendRef["name"].(string)
+				ns, _ := backendRef["namespace"].(string)
+				if ns == "" {
+					ns = namespace
+				}
+				backend, found := index[ns+"/"+name]
+				if !found {
+					backend = &gatewayBackend{namespace: ns, name: name, routes: []string{}}
+					index[ns+"/"+name] = backend
+					backends = append(backends, backend)
+				}
+				if len(backend.routes) == 0 || backend.routes[len(backend.routes)-1] != routeRef {
+					backend.routes = append(backend.routes, routeRef)
+				}
+			}
+		}
+	}
+	return backends
+}
+
+// serviceBackendTLSPolicies returns the BackendTLSPolicies targeting a Service, the oldest one first as it is the
+// one applied when several target the same Service
+func serviceBackendTLSPolicies(service string, policies []kubernetes.IstioObject) []kubernetes.IstioObject {
+	targeting := []kubernetes.IstioObject{}
+	for _, policy := range policies {
+		for _, target := range backendTLSPolicyTargets(policy) {
+			if name, _ := target["name"].(string); name == service && isServiceRef(target) {
+				targeting = append(targeting, policy)
+				break
+			}
+		}
+	}
+	sort.SliceStable(targeting, func(i, j int) bool {
+		mi, mj := targeting[i].GetObjectMeta(), targeting[j].GetObjectMeta()
+		if !mi.CreationTimestamp.Equal(&mj.CreationTimestamp) {
+			return mi.CreationTimestamp.Before(&mj.CreationTimestamp)
+		}
+		return mi.Name < mj.Name
+	})
+	return targeting
+}
+
+// backendTLSPolicyTargets returns the references targeted by a BackendTLSPolicy: the targetRef of v1alpha2, or the
+// targetRefs of the later versions
+func backendTLSPolicyTargets(policy kubernetes.IstioObject) []map[string]interface{} {
+	targets := []map[string]interface{}{}
+	if targetRef, ok := policy.GetSpec()["targetRef"].(map[string]interface{}); ok {
+		targets = append(targets, targetRef)
+	}
+	if targetRefs, ok := policy.GetSpec()["targetRefs"].([]interface{}); ok {
+		for _, t := range targetRefs {
+			if targetRef, ok := t.(map[string]interface{}); ok {
+				targets = append(targets, targetRef)
+			}
+		}
+	}
+	return targets
+}
+
+// isServiceRef checks whether a Gateway API object reference targets a core Service, the default kind of the backends
+func isServiceRef(ref map[string]interface{}) bool {
+	group, _ := ref["group"].(string)
+	kind, _ := ref["kind"].(string)
+	return group == "" && (kind == "" || kind == kubernetes.ServiceType)
+}
+
+// servicePeerAuthentication returns the mTLS mode, and the reference, of the PeerAuthentication applied to the
+// workloads of a Service: the one selecting them, otherwise the namespace-wide one, otherwise the mesh-wide one
+func servicePeerAuthentication(selector map[string]string, namespacePas, meshPas []kubernetes.IstioObject) (string, string) {
+	if len(selector) > 0 {
+		for _, pa := range namespacePas {
+			paLabels := common.GetSelectorLabels(pa)
+			if len(paLabels) == 0 || !labels.SelectorFromSet(paLabels).Matches(labels.Set(selector)) {
+				continue
+			}
+			if _, mode := kubernetes.PeerAuthnMTLSMode(pa); mode != "" && mode != "UNSET" {
+				return mode, objectRef(pa)
+			}
+		}
+	}
+	for _, pas := range [][]kubernetes.IstioObject{namespacePas, meshPas} {
+		for _, pa := range pas {
+			if _, mode := kubernetes.PeerAuthnHasMTLSEnabled(pa); mode != "" && mode != "UNSET" {
+				return mode, objectRef(pa)
+			}
+		}
+	}
+	return "", ""
+}
+
+// hostDestinationRule returns the TLS mode, and the reference, of the DestinationRule of the host of a Service
+func hostDestinationRule(namespace, service string, drs []kubernetes.IstioObject) (string, string) {
+	for _, dr := range kubernetes.FilterDestinationRules(drs, namespace, service) {
+		if _, mode := kubernetes.DestinationRuleHasMTLSEnabled(dr); mode != "" {
+			return mode, objectRef(dr)
+		}
+	}
+	return "", ""
+}
+
+// serviceDestinationRule returns the TLS mode of the DestinationRule applied to the host of a Service: the one of its
+// host, otherwise the namespace-wide one, otherwise the mesh-wide one
+func serviceDestinationRule(namespace, service string, drs []kubernetes.IstioObject) string {
+	if mode, _ := hostDestinationRule(namespace, service, drs); mode != "" {
+		return mode
+	}
+	for _, dr := range drs {
+		if _, mode := kubernetes.DestinationRuleHasNamespaceWideMTLSEnabled(namespace, dr); mode != "" {
+			return mode
+		}
+	}
+	for _, dr := range drs {
+		if _, mode := kubernetes.DestinationRuleHasMeshWideMTLSEnabled(dr); mode != "" {
+			return mode
+		}
+	}
+	return ""
+}
+
+func objectRef(object kubernetes.IstioObject) string {
+	meta := object.GetObjectMeta()
+	return meta.Namespace + "/" + meta.Name
+}
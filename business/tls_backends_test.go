@@ -0,0 +1,138 @@
+package business
+
+import (
+	"testing"
+
+	osproject_v1 "github.com/openshift/api/project/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	core_v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/kubernetes/kubetest"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/tests/data"
+)
+
+// Context: HTTPRoutes routing to Services with BackendTLSPolicies, Istio PeerAuthentications and DestinationRules
+func TestBackendsTLSStatus(t *testing.T) {
+	assert := assert.New(t)
+
+	tlsService := backendsTLSTestPrep("gateway_api_backends_1.yaml", true, t)
+	statuses, err := tlsService.BackendsTLSStatus("bookinfo")
+	assert.NoError(err)
+
+	// The ServiceImport backend is ignored
+	assert.Len(statuses, 3)
+
+	// Istio mTLS only
+	assert.Equal(models.BackendTLSStatus{
+		Namespace:              "bookinfo",
+		Service:                "productpage",
+		Routes:                 []string{"bookinfo/bookinfo"},
+		MTLSStatus:             MTLSPartiallyEnabled,
+		PeerAuthenticationMode: "PERMISSIVE",
+		BackendTLSPolicies:     []string{},
+		Mode:                   "ISTIO_MUTUAL",
+		Conflicts:              []string{},
+	}, statuses[0])
+
+	// The gateways originate TLS to workloads requiring Istio mTLS
+	reviews := statuses[1]
+	assert.Equal("reviews", reviews.Service)
+	assert.Equal([]string{"bookinfo/bookinfo", "bookinfo/reviews-canary"}, reviews.Routes)
+	assert.Equal(MTLSEnabled, reviews.MTLSStatus)
+	assert.Equal("STRICT", reviews.PeerAuthenticationMode)
+	assert.Equal([]string{"bookinfo/reviews-tls"}, reviews.BackendTLSPolicies)
+	assert.Equal("SIMPLE", reviews.Mode)
+	assert.Equal([]string{"PeerAuthentication [bookinfo/reviews-strict] requires Istio mTLS, the TLS originated by the gateways with BackendTLSPolicy [bookinfo/reviews-tls] is rejected"}, reviews.Conflicts)
+
+	// Both the DestinationRule and the BackendTLSPolicy (v1alpha3 targetRefs) set the TLS of the Service
+	ratings := statuses[2]
+	assert.Equal("ratings", ratings.Service)
+	assert.Equal("ISTIO_MUTUAL", ratings.DestinationRuleMode)
+	assert.Equal("PERMISSIVE", ratings.PeerAuthenticationMode)
+	assert.Equal([]string{"bookinfo/ratings-tls"}, ratings.BackendTLSPolicies)
+	assert.Equal("SIMPLE", ratings.Mode)
+	assert.Equal([]string{"DestinationRule [bookinfo/ratings] sets the TLS mode [ISTIO_MUTUAL] of the Service, the gateways originate TLS with BackendTLSPolicy [bookinfo/ratings-tls] instead"}, ratings.Conflicts)
+}
+
+// Context: Several BackendTLSPolicies targeting a Service, a backend in another namespace with mesh-wide mTLS disabled
+func TestBackendsTLSStatusConflictingPolicies(t *testing.T) {
+	assert := assert.New(t)
+
+	tlsService := backendsTLSTestPrep("gateway_api_backends_2.yaml", false, t)
+	statuses, err := tlsService.BackendsTLSStatus("travel")
+	assert.NoError(err)
+	assert.Len(statuses, 2)
+
+	// The PeerAuthentication doesn't require mTLS: only the policies conflict
+	hotels := statuses[0]
+	assert.Equal("DISABLE", hotels.PeerAuthenticationMode)
+	assert.Equal([]string{"travel/hotels-tls", "travel/hotels-tls-new"}, hotels.BackendTLSPolicies)
+	assert.Equal("SIMPLE", hotels.Mode)
+	assert.Equal([]string{"BackendTLSPolicies [travel/hotels-tls-new] target the Service of the older BackendTLSPolicy [travel/hotels-tls] and are ignored"}, hotels.Conflicts)
+
+	cars := statuses[1]
+	assert.Equal("travel-agency", cars.Namespace)
+	assert.Equal("cars", cars.Service)
+	assert.Equal([]string{"travel/travels"}, cars.Routes)
+	assert.Equal(MTLSDisabled, cars.MTLSStatus)
+	assert.Equal("DISABLE", cars.DestinationRuleMode)
+	assert.Empty(cars.BackendTLSPolicies)
+	assert.Equal("DISABLE", cars.Mode)
+	assert.Empty(cars.Conflicts)
+}
+
+// Context: Namespace without Gateway API routes
+func TestBackendsTLSStatusWithoutRoutes(t *testing.T) {
+	assert := assert.New(t)
+
+	tlsService := backendsTLSTestPrep("gateway_api_backends_1.yaml", true, t)
+	statuses, err := tlsService.BackendsTLSStatus("travel")
+	assert.NoError(err)
+	assert.Empty(statuses)
+}
+
+func backendsTLSTestPrep(scenario string, autoMtls bool, t *testing.T) *TLSService {
+	config.Set(config.NewConfig())
+
+	loader := &data.YamlFixtureLoader{Filename: "../tests/data/tls/" + scenario}
+	if err := loader.Load(); err != nil {
+		t.Error("Error loading test data.")
+	}
+
+	namespaces := []string{"bookinfo", "istio-system", "travel", "travel-agency"}
+	projects := make([]osproject_v1.Project, 0, len(namespaces))
+	k8s := new(kubetest.K8SClientMock)
+	k8s.On("IsOpenShift").Return(true)
+	k8s.On("IsMaistraApi").Return(false)
+	for _, ns := range namespaces {
+		project := osproject_v1.Project{ObjectMeta: meta_v1.ObjectMeta{Name: ns}}
+		projects = append(projects, project)
+		k8s.On("GetProject", ns).Return(&project, nil)
+		for resourceType, kind := range map[string]string{
+			kubernetes.HTTPRoutes:          kubernetes.HTTPRouteType,
+			kubernetes.BackendTLSPolicies:  kubernetes.BackendTLSPolicyType,
+			kubernetes.PeerAuthentications: kubernetes.PeerAuthenticationsType,
+			kubernetes.DestinationRules:    kubernetes.DestinationRuleType,
+		} {
+			k8s.On("GetIstioObjects", ns, resourceType, "").Return(loader.GetResourcesIn(kind, ns), nil)
+		}
+	}
+	k8s.On("GetProjects", mock.AnythingOfType("string")).Return(projects, nil)
+	for _, svc := range []string{"productpage", "reviews", "ratings", "hotels"} {
+		k8s.On("GetService", mock.AnythingOfType("string"), svc).Return(&core_v1.Service{
+			ObjectMeta: meta_v1.ObjectMeta{Name: svc},
+			Spec:       core_v1.ServiceSpec{Selector: map[string]string{"app": svc}},
+		}, nil)
+	}
+	// A backend without Service is still reported
+	k8s.On("GetService", "travel-agency", "cars").Return(&core_v1.Service{}, errors.NewNotFound(schema.GroupResource{Resource: "services"}, "cars"))
+
+	return &TLSService{k8s: k8s, enabledAutoMtls: &autoMtls, businessLayer: NewWithBackends(k8s, nil, nil)}
+}
@@ -0,0 +1,172 @@
+package business
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/prometheus/common/model"
+
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/prometheus/internalmetrics"
+)
+
+// NamespaceMTLSRecommendation recommends the next step toward STRICT mTLS for the namespace. Its namespace-wide
+// mTLS status is combined with the traffic received by its workloads, reported by their proxies: STRICT mTLS can be
+// enforced when none of it is plaintext, PERMISSIVE exceptions can be removed once it is enforced.
+func (in TLSService) NamespaceMTLSRecommendation(namespace, rateInterval string, queryTime time.Time) (*models.MTLSRecommendation, error) {
+	var err error
+	promtimer := internalmetrics.GetGoFunctionMetric("business", "TLSService", "NamespaceMTLSRecommendation")
+	defer promtimer.ObserveNow(&err)
+
+	if _, err = in.businessLayer.Namespace.GetNamespace(namespace); err != nil {
+		return nil, err
+	}
+	prom := in.businessLayer.Svc.prom
+	if prom == nil {
+		err = errors.New("the traffic of the namespace is not available without Prometheus")
+		return nil, err
+	}
+
+	var status models.MTLSStatus
+	if status, err = in.NamespaceWidemTLSStatus(namespace); err != nil {
+		return nil, err
+	}
+	_, rootNamespace, err := in.getNamespaces(namespace)
+	if err != nil {
+		return nil, err
+	}
+	pas, err := in.getPeerAuthentications(namespace, rootNamespace)
+	if err != nil {
+		return nil, err
+	}
+
+	labels := fmt.Sprintf(`{%s="destination",%s="%s"}`, telemetryLabel("reporter"), telemetryLabel("destination_workload_namespace"), namespace)
+	grouping := telemetryGrouping("source_workload_namespace,source_workload,destination_workload,request_protocol,connection_security_policy")
+	requests, err := prom.FetchRateValues(telemetryMetric("istio_requests_total"), labels, grouping, rateInterval, queryTime)
+	if err != nil {
+		return nil, err
+	}
+	connections, err := prom.FetchRateValues(telemetryMetric("istio_tcp_connections_opened_total"), labels, grouping, rateInterval, queryTime)
+	if err != nil {
+		return nil, err
+	}
+
+	return buildMTLSRecommendation(namespace, status.Status, pas, requests, connections, rateInterval), nil
+}
+
+func buildMTLSRecommendation(namespace, status string, pas []kubernetes.IstioObject, requests, connections model.Vector, rateInterval string) *models.MTLSRecommendation {
+	recommendation := &models.MTLSRecommendation{
+		Namespace:                     namespace,
+		MTLSStatus:                    status,
+		Evidence:                      []string{},
+		RateInterval:                  rateInterval,
+		PlaintextTraffic:              []models.PlaintextTraffic{},
+		PermissivePeerAuthentications: permissivePeerAuthentications(pas),
+	}
+
+	lblSourceNamespace := model.LabelName(telemetryLabel("source_workload_namespace"))
+	lblSourceWorkload := model.LabelName(telemetryLabel("source_workload"))
+	lblWorkload := model.LabelName(telemetryLabel("destination_workload"))
+	lblProtocol := model.LabelName(telemetryLabel("request_protocol"))
+	lblPolicy := model.LabelName(telemetryLabel("connection_security_policy"))
+	for _, samples := range []model.Vector{requests, connections} {
+		for _, sample := range samples {
+			rate := float64(sample.Value)
+			if math.IsNaN(rate) || rate == 0 {
+				continue
+			}
+			// The proxies report none for plaintext, unknown when they can't tell
+			switch sample.Metric[lblPolicy] {
+			case "mutual_tls":
+				recommendation.MTLSRate += rate
+			case "none":
+				recommendation.PlaintextRate += rate
+				protocol := string(sample.Metric[lblProtocol])
+				if protocol == "" {
+					protocol = models.TCPProtocol
+				}
+				recommendation.PlaintextTraffic = append(recommendation.PlaintextTraffic, models.PlaintextTraffic{
+					SourceNamespace:     string(sample.Metric[lblSourceNamespace]),
+					SourceWorkload:      string(sample.Metric[lblSourceWorkload]),
+					DestinationWorkload: string(sample.Metric[lblWorkload]),
+					Protocol:            protocol,
+					Rate:                rate,
+				})
+			}
+		}
+	}
+	sort.SliceStable(recommendation.PlaintextTraffic, func(i, j int) bool {
+		return recommendation.PlaintextTraffic[i].Rate > recommendation.PlaintextTraffic[j].Rate
+	})
+
+	evidence := func(format string, args ...interface{}) {
+		recommendation.Evidence = append(recommendation.Evidence, fmt.Sprintf(format, args...))
+	}
+	switch status {
+	case MTLSEnabled:
+		evidence("STRICT mTLS is enforced namespace-wide")
+	case MTLSPartiallyEnabled:
+		evidence("mTLS is partially enabled namespace-wide: plaintext is accepted")
+	case MTLSNotEnabled:
+		evidence("No namespace-wide mTLS configuration: the mesh-wide one applies")
+	case MTLSDisabled:
+		evidence("mTLS is disabled namespace-wide")
+	}
+	for _, pa := range recommendation.PermissivePeerAuthentications {
+		evidence("PeerAuthentication %s accepts plaintext with a PERMISSIVE mode", pa)
+	}
+	for _, traffic := range recommendation.PlaintextTraffic {
+		evidence("%s/%s sends plaintext %s traffic to %s at %.2f/s", traffic.SourceNamespace, traffic.SourceWorkload, traffic.Protocol, traffic.DestinationWorkload, traffic.Rate)
+	}
+
+	switch {
+	case recommendation.PlaintextRate > 0:
+		// The plaintext traffic would be rejected: its sources need a sidecar, or to be moved into the mesh
+		evidence("Plaintext traffic would be rejected with STRICT mTLS")
+		recommendation.Recommendation = models.MTLSRecommendationNeedsWork
+	case status == MTLSDisabled:
+		evidence("mTLS must be enabled in PERMISSIVE mode first, to observe the traffic which can't use it")
+		recommendation.Recommendation = models.MTLSRecommendationNeedsWork
+	case status == MTLSEnabled && len(recommendation.PermissivePeerAuthentications) > 0:
+		evidence("No plaintext traffic in the last %s: the PERMISSIVE exceptions are not needed anymore", rateInterval)
+		recommendation.Recommendation = models.MTLSRecommendationPermissiveCleanup
+	case status == MTLSEnabled:
+		recommendation.Recommendation = models.MTLSRecommendationEnforced
+	case recommendation.MTLSRate == 0:
+		// Without traffic, nothing tells that all the clients use mTLS
+		evidence("No traffic in the last %s: the clients of the namespace are unknown", rateInterval)
+		recommendation.Recommendation = models.MTLSRecommendationNeedsWork
+	default:
+		evidence("No plaintext traffic in the last %s: all the traffic uses mTLS", rateInterval)
+		recommendation.Recommendation = models.MTLSRecommendationMoveToStrict
+	}
+	return recommendation
+}
+
+// permissivePeerAuthentications returns the names of the PeerAuthentications with a PERMISSIVE mode, for the
+// workloads or for some of their ports
+func permissivePeerAuthentications(pas []kubernetes.IstioObject) []string {
+	names := []string{}
+	for _, pa := range pas {
+		permissive := false
+		if _, mode := kubernetes.PeerAuthnMTLSMode(pa); mode == "PERMISSIVE" {
+			permissive = true
+		}
+		if portLevel, ok := pa.GetSpec()["portLevelMtls"].(map[string]interface{}); ok {
+			for _, portMtls := range portLevel {
+				if portMap, ok := portMtls.(map[string]interface{}); ok && portMap["mode"] == "PERMISSIVE" {
+					permissive = true
+				}
+			}
+		}
+		if permissive {
+			names = append(names, pa.GetObjectMeta().Name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,163 @@
+package business
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/kubernetes/kubetest"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/prometheus/prometheustest"
+	"github.com/kiali/kiali/tests/data"
+)
+
+func TestMTLSRecommendationMoveToStrict(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	pas := fakePermissivePeerAuthn("default", "bookinfo")
+	requests := model.Vector{
+		securityPolicySample("bookinfo", "productpage-v1", "reviews-v1", "http", "mutual_tls", 10),
+		securityPolicySample("istio-system", "istio-ingressgateway", "productpage-v1", "http", "mutual_tls", 5),
+	}
+	connections := model.Vector{
+		securityPolicySample("bookinfo", "ratings-v2", "mongodb-v1", "tcp", "mutual_tls", 1),
+		// Stale series
+		securityPolicySample("unknown", "unknown", "mongodb-v1", "tcp", "none", 0),
+	}
+
+	recommendation := buildMTLSRecommendation("bookinfo", MTLSPartiallyEnabled, pas, requests, connections, "10m")
+	assert.Equal(models.MTLSRecommendationMoveToStrict, recommendation.Recommendation)
+	assert.Equal(16.0, recommendation.MTLSRate)
+	assert.Zero(recommendation.PlaintextRate)
+	assert.Empty(recommendation.PlaintextTraffic)
+	assert.Equal([]string{"default"}, recommendation.PermissivePeerAuthentications)
+	assert.Contains(recommendation.Evidence, "PeerAuthentication default accepts plaintext with a PERMISSIVE mode")
+	assert.Contains(recommendation.Evidence, "No plaintext traffic in the last 10m: all the traffic uses mTLS")
+}
+
+func TestMTLSRecommendationNeedsWork(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	requests := model.Vector{
+		securityPolicySample("bookinfo", "productpage-v1", "reviews-v1", "http", "mutual_tls", 10),
+		securityPolicySample("legacy", "batch-job", "reviews-v1", "http", "none", 0.5),
+	}
+	connections := model.Vector{
+		securityPolicySample("unknown", "unknown", "mongodb-v1", "tcp", "none", 2),
+	}
+
+	recommendation := buildMTLSRecommendation("bookinfo", MTLSNotEnabled, nil, requests, connections, "10m")
+	assert.Equal(models.MTLSRecommendationNeedsWork, recommendation.Recommendation)
+	assert.Equal(2.5, recommendation.PlaintextRate)
+	// Highest rate first
+	assert.Equal([]models.PlaintextTraffic{
+		{SourceNamespace: "unknown", SourceWorkload: "unknown", DestinationWorkload: "mongodb-v1", Protocol: "tcp", Rate: 2},
+		{SourceNamespace: "legacy", SourceWorkload: "batch-job", DestinationWorkload: "reviews-v1", Protocol: "http", Rate: 0.5},
+	}, recommendation.PlaintextTraffic)
+	assert.Contains(recommendation.Evidence, "legacy/batch-job sends plaintext http traffic to reviews-v1 at 0.50/s")
+}
+
+func TestMTLSRecommendationNeedsWorkWithoutTraffic(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	recommendation := buildMTLSRecommendation("bookinfo", MTLSNotEnabled, nil, model.Vector{}, model.Vector{}, "10m")
+	assert.Equal(models.MTLSRecommendationNeedsWork, recommendation.Recommendation)
+	assert.Contains(recommendation.Evidence, "No traffic in the last 10m: the clients of the namespace are unknown")
+}
+
+func TestMTLSRecommendationNeedsWorkWhenDisabled(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	pas := fakePeerAuthnWithMtlsMode("default", "bookinfo", "DISABLE")
+	recommendation := buildMTLSRecommendation("bookinfo", MTLSDisabled, pas, model.Vector{}, model.Vector{}, "10m")
+	assert.Equal(models.MTLSRecommendationNeedsWork, recommendation.Recommendation)
+	assert.Empty(recommendation.PermissivePeerAuthentications)
+}
+
+func TestMTLSRecommendationPermissiveCleanup(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	portLevel := data.CreateEmptyPeerAuthenticationWithSelector("ratings-metrics", "bookinfo", data.CreateOneLabelSelector("ratings"))
+	portLevel.GetSpec()["portLevelMtls"] = map[string]interface{}{
+		"9080": map[string]interface{}{"mode": "PERMISSIVE"},
+	}
+	pas := []kubernetes.IstioObject{
+		fakeStrictPeerAuthn("default", "bookinfo")[0],
+		data.AddSelectorToPeerAuthn(data.CreateOneLabelSelector("reviews"), fakePermissivePeerAuthn("reviews-legacy", "bookinfo")[0]),
+		portLevel,
+	}
+	requests := model.Vector{
+		securityPolicySample("bookinfo", "productpage-v1", "reviews-v1", "http", "mutual_tls", 10),
+	}
+
+	recommendation := buildMTLSRecommendation("bookinfo", MTLSEnabled, pas, requests, model.Vector{}, "10m")
+	assert.Equal(models.MTLSRecommendationPermissiveCleanup, recommendation.Recommendation)
+	assert.Equal([]string{"ratings-metrics", "reviews-legacy"}, recommendation.PermissivePeerAuthentications)
+}
+
+func TestMTLSRecommendationEnforced(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	pas := fakeStrictPeerAuthn("default", "bookinfo")
+	recommendation := buildMTLSRecommendation("bookinfo", MTLSEnabled, pas, model.Vector{}, model.Vector{}, "10m")
+	assert.Equal(models.MTLSRecommendationEnforced, recommendation.Recommendation)
+	assert.Equal([]string{"STRICT mTLS is enforced namespace-wide"}, recommendation.Evidence)
+}
+
+func TestNamespaceMTLSRecommendation(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	autoMtls := true
+	k8s := new(kubetest.K8SClientMock)
+	k8s.On("IsOpenShift").Return(true)
+	k8s.On("IsMaistraApi").Return(false)
+	k8s.On("GetProjects", mock.AnythingOfType("string")).Return(fakeProjects(), nil)
+	k8s.On("GetProject", "bookinfo").Return(&fakeProjects()[0], nil)
+	k8s.On("GetIstioObjects", mock.AnythingOfType("string"), "destinationrules", "").Return([]kubernetes.IstioObject{}, nil)
+	k8s.On("GetIstioObjects", "bookinfo", "peerauthentications", "").Return(fakePermissivePeerAuthn("default", "bookinfo"), nil)
+
+	queryTime := time.Date(2022, 1, 10, 12, 0, 0, 0, time.UTC)
+	labels := `{reporter="destination",destination_workload_namespace="bookinfo"}`
+	grouping := "source_workload_namespace,source_workload,destination_workload,request_protocol,connection_security_policy"
+	prom := new(prometheustest.PromClientMock)
+	prom.On("FetchRateValues", "istio_requests_total", labels, grouping, "10m", queryTime).Return(model.Vector{
+		securityPolicySample("bookinfo", "productpage-v1", "reviews-v1", "http", "mutual_tls", 10),
+	}, nil)
+	prom.On("FetchRateValues", "istio_tcp_connections_opened_total", labels, grouping, "10m", queryTime).Return(model.Vector{}, nil)
+
+	layer := NewWithBackends(k8s, prom, nil)
+	layer.Namespace.isAccessibleNamespaces["**"] = true
+	tlsService := TLSService{k8s: k8s, enabledAutoMtls: &autoMtls, businessLayer: layer}
+
+	recommendation, err := tlsService.NamespaceMTLSRecommendation("bookinfo", "10m", queryTime)
+	assert.NoError(err)
+	prom.AssertExpectations(t)
+	assert.Equal(MTLSPartiallyEnabled, recommendation.MTLSStatus)
+	assert.Equal(models.MTLSRecommendationMoveToStrict, recommendation.Recommendation)
+	assert.Equal([]string{"default"}, recommendation.PermissivePeerAuthentications)
+}
+
+func securityPolicySample(sourceNamespace, sourceWorkload, workload, protocol, policy string, rate float64) *model.Sample {
+	return &model.Sample{
+		Metric: model.Metric{
+			"source_workload_namespace":  model.LabelValue(sourceNamespace),
+			"source_workload":            model.LabelValue(sourceWorkload),
+			"destination_workload":       model.LabelValue(workload),
+			"request_protocol":           model.LabelValue(protocol),
+			"connection_security_policy": model.LabelValue(policy),
+		},
+		Value: model.SampleValue(rate),
+	}
+}
@@ -6,11 +6,15 @@ import (
 	osproject_v1 "github.com/openshift/api/project/v1"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	core_v1 "k8s.io/api/core/v1"
+	k8s_errors "k8s.io/apimachinery/pkg/api/errors"
 	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/kiali/kiali/config"
 	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/kubernetes/cache"
 	"github.com/kiali/kiali/kubernetes/kubetest"
+	"github.com/kiali/kiali/models"
 	"github.com/kiali/kiali/tests/data"
 )
 
@@ -242,6 +246,82 @@ func TestNamespaceHasDestinationRuleEnabledDifferentNs(t *testing.T) {
 	assert.Equal(MTLSEnabled, status.Status)
 }
 
+func TestMeshStatusRevisionsWithDifferentRootNamespaces(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	k8s := new(kubetest.K8SClientMock)
+	k8s.On("GetNamespace", "bookinfo").Return(fakeRevisionNamespace("bookinfo", "stable"), nil)
+	k8s.On("GetNamespace", "travels").Return(fakeRevisionNamespace("travels", "canary"), nil)
+	k8s.On("GetConfigMap", "istio-system", "istio-stable").Return(fakeRevisionMeshConfig("istio-system"), nil)
+	k8s.On("GetConfigMap", "istio-system", "istio-canary").Return(fakeRevisionMeshConfig("istio-canary"), nil)
+	k8s.On("GetIstioObjects", mock.AnythingOfType("string"), "destinationrules", "").Return([]kubernetes.IstioObject{}, nil)
+	k8s.On("GetIstioObjects", "istio-system", "peerauthentications", "").Return(fakeStrictMeshPeerAuthentication("default"), nil)
+	k8s.On("GetIstioObjects", "istio-canary", "peerauthentications", "").Return([]kubernetes.IstioObject{}, nil)
+
+	tlsService := getTLSService(k8s, true)
+
+	// Each revision is checked against the PeerAuthentications of its own root namespace
+	status, err := tlsService.MeshWidemTLSStatus([]string{"bookinfo"})
+	assert.NoError(err)
+	assert.Equal(MTLSEnabled, status.Status)
+
+	status, err = tlsService.MeshWidemTLSStatus([]string{"travels"})
+	assert.NoError(err)
+	assert.Equal(MTLSNotEnabled, status.Status)
+
+	// During the canary upgrade the root namespaces don't agree
+	status, err = tlsService.MeshWidemTLSStatus([]string{"bookinfo", "travels"})
+	assert.NoError(err)
+	assert.Equal(MTLSPartiallyEnabled, status.Status)
+
+	k8s.AssertNumberOfCalls(t, "GetConfigMap", 4)
+}
+
+func TestMeshStatusRevisionWithoutMeshConfig(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	k8s := new(kubetest.K8SClientMock)
+	k8s.On("GetNamespace", "bookinfo").Return(fakeRevisionNamespace("bookinfo", "canary"), nil)
+	k8s.On("GetConfigMap", "istio-system", "istio-canary").Return(&core_v1.ConfigMap{}, k8s_errors.NewNotFound(core_v1.Resource("configmaps"), "istio-canary"))
+	k8s.On("GetIstioObjects", "bookinfo", "destinationrules", "").Return([]kubernetes.IstioObject{}, nil)
+	k8s.On("GetIstioObjects", "istio-system", "peerauthentications", "").Return(fakeStrictMeshPeerAuthentication("default"), nil)
+
+	tlsService := getTLSService(k8s, true)
+	status, err := tlsService.MeshWidemTLSStatus([]string{"bookinfo"})
+
+	assert.NoError(err)
+	assert.Equal(MTLSEnabled, status.Status)
+}
+
+func TestNamespaceStatusRevisionRootNamespace(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	// The PeerAuthentication of the root namespace of the canary revision is mesh-wide, not namespace-wide
+	projects := []osproject_v1.Project{
+		{ObjectMeta: meta_v1.ObjectMeta{Name: "istio-canary", Labels: map[string]string{IstioRevisionLabel: "canary"}}},
+		{ObjectMeta: meta_v1.ObjectMeta{Name: "bookinfo"}},
+	}
+
+	k8s := new(kubetest.K8SClientMock)
+	k8s.On("IsOpenShift").Return(true)
+	k8s.On("IsMaistraApi").Return(false)
+	k8s.On("GetProjects", mock.AnythingOfType("string")).Return(projects, nil)
+	k8s.On("GetConfigMap", "istio-system", "istio-canary").Return(fakeRevisionMeshConfig("istio-canary"), nil)
+	k8s.On("GetIstioObjects", mock.AnythingOfType("string"), "destinationrules", "").Return([]kubernetes.IstioObject{}, nil)
+
+	autoMtls := true
+	tlsService := TLSService{k8s: k8s, enabledAutoMtls: &autoMtls, businessLayer: NewWithBackends(k8s, nil, nil)}
+	tlsService.businessLayer.Namespace.isAccessibleNamespaces["**"] = true
+	status, err := tlsService.NamespaceWidemTLSStatus("istio-canary")
+
+	assert.NoError(err)
+	assert.Equal(MTLSNotEnabled, status.Status)
+	k8s.AssertNotCalled(t, "GetIstioObjects", "istio-canary", "peerauthentications", "")
+}
+
 func testNamespaceScenario(exStatus string, drs []kubernetes.IstioObject, ps []kubernetes.IstioObject, autoMtls bool, t *testing.T) {
 	assert := assert.New(t)
 
@@ -298,8 +378,25 @@ func fakePeerAuthn(name, namespace string, peers interface{}) []kubernetes.Istio
 	return []kubernetes.IstioObject{data.CreateEmptyPeerAuthentication(name, namespace, peers)}
 }
 
-func getTLSService(k8s kubernetes.ClientInterface, autoMtls bool) *TLSService {
-	return &TLSService{k8s: k8s, enabledAutoMtls: &autoMtls}
+func getTLSService(k8s *kubetest.K8SClientMock, autoMtls bool) *TLSService {
+	k8s.On("IsOpenShift").Return(false)
+	k8s.On("GetToken").Return("token")
+	k8s.On("GetNamespace", "test").Return(kubetest.FakeNamespace("test"), nil)
+	layer := NewWithBackends(k8s, nil, nil)
+	layer.Namespace.isAccessibleNamespaces["**"] = true
+	return &TLSService{k8s: k8s, enabledAutoMtls: &autoMtls, businessLayer: layer}
+}
+
+func fakeRevisionNamespace(name, revision string) *core_v1.Namespace {
+	ns := kubetest.FakeNamespace(name)
+	ns.Labels = map[string]string{IstioRevisionLabel: revision}
+	return ns
+}
+
+func fakeRevisionMeshConfig(rootNamespace string) *core_v1.ConfigMap {
+	return &core_v1.ConfigMap{
+		Data: map[string]string{"mesh": "rootNamespace: " + rootNamespace},
+	}
 }
 
 func fakeStrictMeshPeerAuthentication(name string) []kubernetes.IstioObject {
@@ -316,3 +413,102 @@ func fakeMeshPeerAuthenticationWithMtlsMode(name, mTLSmode string) []kubernetes.
 func fakeMeshPeerAuthentication(name string, mtls interface{}) []kubernetes.IstioObject {
 	return []kubernetes.IstioObject{data.CreateEmptyMeshPeerAuthentication(name, mtls)}
 }
+
+func TestMeshStatusCachedByConfigGeneration(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	dr := data.AddTrafficPolicyToDestinationRule(data.CreateMTLSTrafficPolicyForDestinationRules(),
+		data.CreateEmptyDestinationRule("istio-system", "default", "*.local"))
+
+	fakeCache := &fakeTLSStatusCache{
+		istioObjects: map[string][]kubernetes.IstioObject{
+			"test/destinationrules":            {dr},
+			"istio-system/peerauthentications": fakeStrictMeshPeerAuthentication("default"),
+		},
+		statuses: map[string]models.MTLSStatus{},
+		calls:    map[string]int{},
+	}
+	kialiCache = fakeCache
+	defer func() { kialiCache = nil }()
+
+	k8s := new(kubetest.K8SClientMock)
+	tlsService := getTLSService(k8s, false)
+
+	status, err := tlsService.MeshWidemTLSStatus([]string{"test"})
+	assert.NoError(err)
+	assert.Equal(MTLSEnabled, status.Status)
+	assert.Equal(1, fakeCache.calls["test/destinationrules"])
+
+	// No config change: the status is served from the cache
+	status, err = tlsService.MeshWidemTLSStatus([]string{"test"})
+	assert.NoError(err)
+	assert.Equal(MTLSEnabled, status.Status)
+	assert.Equal(1, fakeCache.calls["test/destinationrules"])
+
+	// PeerAuthentication change: the status is recomputed
+	fakeCache.istioObjects["istio-system/peerauthentications"] = []kubernetes.IstioObject{}
+	fakeCache.BumpConfigGeneration()
+	status, err = tlsService.MeshWidemTLSStatus([]string{"test"})
+	assert.NoError(err)
+	assert.Equal(MTLSPartiallyEnabled, status.Status)
+	assert.Equal(2, fakeCache.calls["test/destinationrules"])
+
+	// DestinationRule change: the status is recomputed
+	fakeCache.istioObjects["istio-system/peerauthentications"] = fakeStrictMeshPeerAuthentication("default")
+	fakeCache.istioObjects["test/destinationrules"] = []kubernetes.IstioObject{}
+	fakeCache.BumpConfigGeneration()
+	status, err = tlsService.MeshWidemTLSStatus([]string{"test"})
+	assert.NoError(err)
+	assert.Equal(MTLSPartiallyEnabled, status.Status)
+	assert.Equal(3, fakeCache.calls["test/destinationrules"])
+
+	k8s.AssertNotCalled(t, "GetIstioObjects", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// fakeTLSStatusCache serves Istio objects and stores mTLS statuses in memory
+type fakeTLSStatusCache struct {
+	cache.KialiCache
+	generation   uint64
+	istioObjects map[string][]kubernetes.IstioObject
+	statuses     map[string]models.MTLSStatus
+	calls        map[string]int
+}
+
+func (f *fakeTLSStatusCache) CheckNamespace(namespace string) bool {
+	return true
+}
+
+func (f *fakeTLSStatusCache) CheckIstioResource(resourceType string) bool {
+	return resourceType == kubernetes.PeerAuthentications || resourceType == kubernetes.DestinationRules
+}
+
+func (f *fakeTLSStatusCache) GetIstioObjects(namespace string, resourceType string, labelSelector string) ([]kubernetes.IstioObject, error) {
+	key := namespace + "/" + resourceType
+	f.calls[key]++
+	return f.istioObjects[key], nil
+}
+
+func (f *fakeTLSStatusCache) GetNamespace(token string, namespace string) *models.Namespace {
+	return &models.Namespace{Name: namespace}
+}
+
+func (f *fakeTLSStatusCache) GetConfigGeneration() uint64 {
+	return f.generation
+}
+
+func (f *fakeTLSStatusCache) BumpConfigGeneration() {
+	f.generation++
+	f.statuses = map[string]models.MTLSStatus{}
+}
+
+func (f *fakeTLSStatusCache) GetTLSStatus(key string, generation uint64) (models.MTLSStatus, bool) {
+	status, found := f.statuses[key]
+	return status, found && generation == f.generation
+}
+
+func (f *fakeTLSStatusCache) SetTLSStatus(key string, generation uint64, status models.MTLSStatus) {
+	if generation == f.generation {
+		f.statuses[key] = status
+	}
+}
@@ -0,0 +1,86 @@
+package business
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/prometheus/common/model"
+
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/prometheus/internalmetrics"
+)
+
+// topTalkersMetrics are the Istio metrics of the rates of the top talkers
+var topTalkersMetrics = map[string]istioMetric{
+	"requests":       {istioName: "istio_requests_total"},
+	"request_bytes":  {istioName: "istio_request_bytes", suffix: "_sum"},
+	"response_bytes": {istioName: "istio_response_bytes", suffix: "_sum"},
+}
+
+// GetTopTalkers returns the edges of a namespace with the highest request rate or bytes rate, from the workloads of
+// the namespace (outbound) or to them (inbound). It is a lightweight alternative to the graph to find the hotspots.
+func (in *MetricsService) GetTopTalkers(q models.TopTalkersQuery) (*models.TopTalkers, error) {
+	var err error
+	promtimer := internalmetrics.GetGoFunctionMetric("business", "MetricsService", "GetTopTalkers")
+	defer promtimer.ObserveNow(&err)
+
+	metric, found := topTalkersMetrics[q.Metric]
+	if !found {
+		err = fmt.Errorf("unknown metric [%s], must be either 'requests', 'request_bytes' or 'response_bytes'", q.Metric)
+		return nil, err
+	}
+	if q.Direction != "inbound" && q.Direction != "outbound" {
+		err = fmt.Errorf("unknown direction [%s], must be either 'inbound' or 'outbound'", q.Direction)
+		return nil, err
+	}
+	if q.Limit <= 0 {
+		err = fmt.Errorf("the limit of talkers must be positive")
+		return nil, err
+	}
+
+	lb := NewMetricsLabelsBuilder(q.Direction)
+	lb.SelfReporter()
+	lb.Namespace(q.Namespace)
+
+	grouping := telemetryGrouping("source_workload_namespace,source_workload,destination_service_namespace,destination_service_name,destination_workload")
+	var rates model.Vector
+	rates, err = in.prom.FetchTopRateValues(metric.promName(), lb.Build(), grouping, q.RateInterval, q.Limit, q.QueryTime)
+	if err != nil {
+		return nil, err
+	}
+	return &models.TopTalkers{
+		Direction: q.Direction,
+		Metric:    q.Metric,
+		Talkers:   buildTalkers(rates),
+	}, nil
+}
+
+func buildTalkers(rates model.Vector) []models.Talker {
+	lblSourceNs := model.LabelName(telemetryLabel("source_workload_namespace"))
+	lblSourceWk := model.LabelName(telemetryLabel("source_workload"))
+	lblDestNs := model.LabelName(telemetryLabel("destination_service_namespace"))
+	lblDestSvc := model.LabelName(telemetryLabel("destination_service_name"))
+	lblDestWk := model.LabelName(telemetryLabel("destination_workload"))
+
+	talkers := []models.Talker{}
+	for _, sample := range rates {
+		value := float64(sample.Value)
+		if math.IsNaN(value) || value == 0 {
+			continue
+		}
+		talkers = append(talkers, models.Talker{
+			SourceNamespace:      string(sample.Metric[lblSourceNs]),
+			SourceWorkload:       string(sample.Metric[lblSourceWk]),
+			DestinationNamespace: string(sample.Metric[lblDestNs]),
+			DestinationService:   string(sample.Metric[lblDestSvc]),
+			DestinationWorkload:  string(sample.Metric[lblDestWk]),
+			Value:                value,
+		})
+	}
+	// topk doesn't sort an instant vector
+	sort.SliceStable(talkers, func(i, j int) bool {
+		return talkers[i].Value > talkers[j].Value
+	})
+	return talkers
+}
@@ -0,0 +1,147 @@
+package business
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/prometheus/prometheustest"
+)
+
+const topTalkersGrouping = "source_workload_namespace,source_workload,destination_service_namespace,destination_service_name,destination_workload"
+
+func TestGetTopTalkers(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	queryTime := time.Date(2017, 01, 15, 0, 0, 0, 0, time.UTC)
+	prom := new(prometheustest.PromClientMock)
+	prom.On("FetchTopRateValues",
+		"istio_requests_total",
+		`{reporter="source",source_workload_namespace="bookinfo"}`,
+		topTalkersGrouping,
+		"5m",
+		3,
+		queryTime,
+	).Return(model.Vector{
+		talkerSample("bookinfo", "reviews-v2", "bookinfo", "ratings", "ratings-v1", 4),
+		talkerSample("bookinfo", "productpage-v1", "bookinfo", "reviews", "reviews-v2", 12),
+		talkerSample("bookinfo", "productpage-v1", "bookinfo", "details", "details-v1", 0),
+	}, nil)
+
+	topTalkers, err := NewMetricsService(prom).GetTopTalkers(models.TopTalkersQuery{
+		Namespace:    "bookinfo",
+		Direction:    "outbound",
+		Metric:       "requests",
+		Limit:        3,
+		RateInterval: "5m",
+		QueryTime:    queryTime,
+	})
+
+	assert.NoError(err)
+	assert.Equal("outbound", topTalkers.Direction)
+	assert.Equal("requests", topTalkers.Metric)
+	// Sorted by decreasing rate, without idle edges
+	assert.Equal([]models.Talker{
+		{SourceNamespace: "bookinfo", SourceWorkload: "productpage-v1", DestinationNamespace: "bookinfo", DestinationService: "reviews", DestinationWorkload: "reviews-v2", Value: 12},
+		{SourceNamespace: "bookinfo", SourceWorkload: "reviews-v2", DestinationNamespace: "bookinfo", DestinationService: "ratings", DestinationWorkload: "ratings-v1", Value: 4},
+	}, topTalkers.Talkers)
+	prom.AssertExpectations(t)
+}
+
+func TestGetTopTalkersInboundBytes(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	queryTime := time.Date(2017, 01, 15, 0, 0, 0, 0, time.UTC)
+	prom := new(prometheustest.PromClientMock)
+	prom.On("FetchTopRateValues",
+		"istio_response_bytes_sum",
+		`{reporter="destination",destination_workload_namespace="bookinfo"}`,
+		topTalkersGrouping,
+		"10m",
+		10,
+		queryTime,
+	).Return(model.Vector{
+		talkerSample("istio-system", "istio-ingressgateway", "bookinfo", "productpage", "productpage-v1", 2048),
+	}, nil)
+
+	topTalkers, err := NewMetricsService(prom).GetTopTalkers(models.TopTalkersQuery{
+		Namespace:    "bookinfo",
+		Direction:    "inbound",
+		Metric:       "response_bytes",
+		Limit:        10,
+		RateInterval: "10m",
+		QueryTime:    queryTime,
+	})
+
+	assert.NoError(err)
+	assert.Len(topTalkers.Talkers, 1)
+	assert.Equal("istio-ingressgateway", topTalkers.Talkers[0].SourceWorkload)
+	assert.Equal(2048.0, topTalkers.Talkers[0].Value)
+	prom.AssertExpectations(t)
+}
+
+func TestGetTopTalkersMappedBytes(t *testing.T) {
+	assert := assert.New(t)
+	conf := config.NewConfig()
+	conf.ExternalServices.Istio.TelemetryMapping.Metrics = map[string]string{"istio_request_bytes": "mesh_request_bytes"}
+	config.Set(conf)
+	defer config.Set(config.NewConfig())
+
+	queryTime := time.Date(2017, 01, 15, 0, 0, 0, 0, time.UTC)
+	prom := new(prometheustest.PromClientMock)
+	prom.On("FetchTopRateValues",
+		"mesh_request_bytes_sum",
+		`{reporter="source",source_workload_namespace="bookinfo"}`,
+		topTalkersGrouping,
+		"10m",
+		10,
+		queryTime,
+	).Return(model.Vector{}, nil)
+
+	_, err := NewMetricsService(prom).GetTopTalkers(models.TopTalkersQuery{
+		Namespace:    "bookinfo",
+		Direction:    "outbound",
+		Metric:       "request_bytes",
+		Limit:        10,
+		RateInterval: "10m",
+		QueryTime:    queryTime,
+	})
+
+	assert.NoError(err)
+	prom.AssertExpectations(t)
+}
+
+func TestGetTopTalkersUnknownMetric(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	prom := new(prometheustest.PromClientMock)
+	q := models.TopTalkersQuery{}
+	q.FillDefaults()
+	q.Namespace = "bookinfo"
+	q.Metric = "tcp_bytes"
+
+	_, err := NewMetricsService(prom).GetTopTalkers(q)
+	assert.Error(err)
+	prom.AssertNotCalled(t, "FetchTopRateValues", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func talkerSample(sourceNs, sourceWk, destNs, destSvc, destWk string, value float64) *model.Sample {
+	return &model.Sample{
+		Metric: model.Metric{
+			"source_workload_namespace":     model.LabelValue(sourceNs),
+			"source_workload":               model.LabelValue(sourceWk),
+			"destination_service_namespace": model.LabelValue(destNs),
+			"destination_service_name":      model.LabelValue(destSvc),
+			"destination_workload":          model.LabelValue(destWk),
+		},
+		Value: model.SampleValue(value),
+	}
+}
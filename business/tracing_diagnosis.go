@@ -0,0 +1,374 @@
+package business
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/jaeger"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/log"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/prometheus/internalmetrics"
+)
+
+const (
+	// Sampling of the proxies when neither a Telemetry nor the mesh config sets it
+	istioDefaultSamplingPercentage = 1.0
+	// At this sampling or under, a workload receiving few requests may have no trace
+	lowSamplingPercentage = 1.0
+)
+
+// The tracers of the legacy tracing config, in the defaultConfig of the mesh config
+var legacyTracers = []string{"datadog", "lightstep", "openCensusAgent", "stackdriver", "zipkin"}
+
+// telemetryTracing is the tracing config of the Telemetry resources applied to a workload,
+// with the Telemetry setting each field
+type telemetryTracing struct {
+	providers            []string
+	providersFrom        string
+	sampling             *float64
+	samplingFrom         string
+	disableSpanReporting bool
+	disableFrom          string
+}
+
+// GetWorkloadTracingDiagnosis tells why a workload may have no trace: it checks that the workload has a sidecar,
+// that its proxy reports spans to a provider with a sampling above zero, and searches the traces of the workload
+// over the window.
+func (in *JaegerService) GetWorkloadTracingDiagnosis(ns, workload, window string, queryTime time.Time) (*models.TracingDiagnosis, error) {
+	var err error
+	promtimer := internalmetrics.GetGoFunctionMetric("business", "Jaeger", "GetWorkloadTracingDiagnosis")
+	defer promtimer.ObserveNow(&err)
+
+	if _, err = in.businessLayer.Namespace.GetNamespace(ns); err != nil {
+		return nil, err
+	}
+	duration, parseErr := model.ParseDuration(window)
+	if parseErr != nil {
+		err = errors.NewBadRequest("invalid window: " + parseErr.Error())
+		return nil, err
+	}
+	wkd, err := fetchWorkload(in.businessLayer, ns, workload, "")
+	if err != nil {
+		return nil, err
+	}
+	meshConfig, err := in.businessLayer.Mesh.GetEffectiveMeshConfig()
+	if err != nil {
+		return nil, err
+	}
+	telemetries, err := fetchTelemetries(in.businessLayer.k8s, ns, meshConfig.RootNamespace)
+	if err != nil {
+		return nil, err
+	}
+
+	var traces models.TracingCheck
+	if config.Get().ExternalServices.Tracing.Enabled {
+		query := models.TracingQuery{
+			Start: queryTime.Add(-time.Duration(duration)),
+			End:   queryTime,
+			Limit: 1,
+		}
+		r, tracesErr := in.GetWorkloadTraces(ns, workload, query)
+		traces = tracesCheck(window, r, tracesErr)
+	} else {
+		traces = models.TracingCheck{
+			Name:    models.TracingCheckTraces,
+			Status:  models.TracingCheckUnknown,
+			Message: "Tracing is not enabled in Kiali, the traces were not searched",
+		}
+	}
+
+	diagnosis := diagnoseTracing(wkd, meshConfig, workloadTelemetries(ns, meshConfig.RootNamespace, wkd.Labels, telemetries), traces)
+	diagnosis.Namespace = ns
+	diagnosis.Workload = workload
+	diagnosis.Window = window
+	return diagnosis, nil
+}
+
+// diagnoseTracing runs the config checks of the workload, the telemetries being sorted from the least to the most
+// specific, and sets the likely cause of the missing traces
+func diagnoseTracing(wkd *models.Workload, meshConfig *models.MeshConfig, telemetries []kubernetes.IstioObject, traces models.TracingCheck) *models.TracingDiagnosis {
+	tracing := resolveTelemetryTracing(telemetries)
+	provider, providers := providerCheck(meshConfig, tracing)
+	sampling, percentage := samplingCheck(meshConfig, tracing)
+
+	diagnosis := &models.TracingDiagnosis{
+		Providers:          providers,
+		SamplingPercentage: percentage,
+		Checks:             []models.TracingCheck{injectionCheck(wkd), provider, sampling, traces},
+	}
+	diagnosis.LikelyCause = likelyTracingCause(diagnosis.Checks)
+	return diagnosis
+}
+
+// likelyTracingCause returns the cause of the first failed config check, then of the first warning, then of the
+// traces check. There is no cause when traces were found.
+func likelyTracingCause(checks []models.TracingCheck) string {
+	var tracesCause string
+	configChecks := []models.TracingCheck{}
+	for _, check := range checks {
+		if check.Name == models.TracingCheckTraces {
+			if check.Status == models.TracingCheckPassed {
+				return ""
+			}
+			tracesCause = check.Cause
+		} else {
+			configChecks = append(configChecks, check)
+		}
+	}
+	for _, status := range []string{models.TracingCheckFailed, models.TracingCheckWarning} {
+		for _, check := range configChecks {
+			if check.Status == status {
+				return check.Cause
+			}
+		}
+	}
+	return tracesCause
+}
+
+func injectionCheck(wkd *models.Workload) models.TracingCheck {
+	if wkd.IstioSidecar {
+		return models.TracingCheck{
+			Name:    models.TracingCheckInjection,
+			Status:  models.TracingCheckPassed,
+			Message: "The pods of the workload have an Istio sidecar",
+		}
+	}
+	return models.TracingCheck{
+		Name:    models.TracingCheckInjection,
+		Status:  models.TracingCheckFailed,
+		Message: "The pods of the workload have no Istio sidecar, no proxy reports the spans of its requests",
+		Cause:   models.TracingCauseNotInjected,
+	}
+}
+
+// providerCheck checks the providers the proxy reports spans to: the ones of the Telemetries or, when not set, the
+// default providers of the mesh config, which must be defined in its extensionProviders. Without providers, the proxy
+// uses the legacy tracer of the mesh defaultConfig, if tracing is enabled.
+func providerCheck(meshConfig *models.MeshConfig, tracing telemetryTracing) (models.TracingCheck, []string) {
+	check := models.TracingCheck{Name: models.TracingCheckProvider}
+	if tracing.disableSpanReporting {
+		check.Status = models.TracingCheckFailed
+		check.Message = fmt.Sprintf("Span reporting is disabled by the Telemetry %s", tracing.disableFrom)
+		check.Cause = models.TracingCauseSpanReportingDisabled
+		return check, []string{}
+	}
+
+	names, from := tracing.providers, "the Telemetry "+tracing.providersFrom
+	if len(names) == 0 {
+		names, from = meshDefaultProviders(meshConfig, "tracing"), "the defaultProviders of the mesh config"
+	}
+	if len(names) > 0 {
+		defined := meshExtensionProviders(meshConfig)
+		for _, name := range names {
+			if _, found := defined[name]; !found {
+				check.Status = models.TracingCheckFailed
+				check.Message = fmt.Sprintf("The tracing provider [%s], set by %s, is not defined in the extensionProviders of the mesh config", name, from)
+				check.Cause = models.TracingCauseNoProvider
+				return check, []string{}
+			}
+		}
+		check.Status = models.TracingCheckPassed
+		check.Message = fmt.Sprintf("Spans are reported to [%s], set by %s", strings.Join(names, ", "), from)
+		return check, names
+	}
+
+	if !meshConfig.EnableTracing {
+		check.Status = models.TracingCheckFailed
+		check.Message = "Tracing is disabled by the enableTracing field of the mesh config, and no tracing provider is set by a Telemetry or the defaultProviders of the mesh config"
+		check.Cause = models.TracingCauseNoProvider
+		return check, []string{}
+	}
+	for _, tracer := range legacyTracers {
+		if _, found := meshConfig.DefaultConfig.Tracing[tracer]; found {
+			check.Status = models.TracingCheckPassed
+			check.Message = fmt.Sprintf("Spans are reported to the %s tracer of the defaultConfig of the mesh config", tracer)
+			return check, []string{tracer}
+		}
+	}
+	check.Status = models.TracingCheckFailed
+	check.Message = "No tracing provider is set by a Telemetry or the mesh config"
+	check.Cause = models.TracingCauseNoProvider
+	return check, []string{}
+}
+
+// samplingCheck checks the sampling of the proxy: the one of the Telemetries or, when not set, the one of the mesh
+// defaultConfig, defaulting to 1% as Istio
+func samplingCheck(meshConfig *models.MeshConfig, tracing telemetryTracing) (models.TracingCheck, float64) {
+	percentage, from := istioDefaultSamplingPercentage, "the Istio default"
+	if tracing.sampling != nil {
+		percentage, from = *tracing.sampling, "the Telemetry "+tracing.samplingFrom
+	} else if sampling, ok := meshConfig.DefaultConfig.Tracing["sampling"].(float64); ok {
+		percentage, from = sampling, "the defaultConfig of the mesh config"
+	}
+
+	check := models.TracingCheck{Name: models.TracingCheckSampling}
+	switch {
+	case percentage <= 0:
+		check.Status = models.TracingCheckFailed
+		check.Message = fmt.Sprintf("No request is sampled: the sampling is 0%%, set by %s", from)
+		check.Cause = models.TracingCauseSamplingTooLow
+	case percentage <= lowSamplingPercentage:
+		check.Status = models.TracingCheckWarning
+		check.Message = fmt.Sprintf("Only %g%% of the requests are sampled, set by %s: a workload receiving few requests may have no trace", percentage, from)
+		check.Cause = models.TracingCauseSamplingTooLow
+	default:
+		check.Status = models.TracingCheckPassed
+		check.Message = fmt.Sprintf("%g%% of the requests are sampled, set by %s", percentage, from)
+	}
+	return check, percentage
+}
+
+func tracesCheck(window string, r *jaeger.JaegerResponse, err error) models.TracingCheck {
+	check := models.TracingCheck{Name: models.TracingCheckTraces}
+	switch {
+	case err != nil:
+		check.Status = models.TracingCheckUnknown
+		check.Message = "The traces could not be searched: " + err.Error()
+	case r == nil || len(r.Data) == 0:
+		check.Status = models.TracingCheckFailed
+		check.Message = fmt.Sprintf("No trace was found for the workload in the last %s", window)
+		check.Cause = models.TracingCauseNoTraces
+	default:
+		check.Status = models.TracingCheckPassed
+		check.Message = fmt.Sprintf("Traces were found for the workload in the last %s", window)
+	}
+	return check
+}
+
+// fetchTelemetries returns the Telemetries of the namespace and of the root namespace
+func fetchTelemetries(k8s kubernetes.ClientInterface, ns, rootNamespace string) ([]kubernetes.IstioObject, error) {
+	telemetries, err := k8s.GetIstioObjects(ns, kubernetes.Telemetries, "")
+	if err != nil {
+		return nil, err
+	}
+	if rootNamespace != "" && rootNamespace != ns {
+		rootTelemetries, rootErr := k8s.GetIstioObjects(rootNamespace, kubernetes.Telemetries, "")
+		if rootErr != nil {
+			// The workload config can still be resolved without the mesh-wide one
+			log.Debugf("Cannot get the Telemetries of the root namespace [%s]: %v", rootNamespace, rootErr)
+		} else {
+			telemetries = append(rootTelemetries, telemetries...)
+		}
+	}
+	return telemetries, nil
+}
+
+// workloadTelemetries returns the Telemetries applied to a workload, from the least to the most specific: the one of
+// the root namespace, the one of the namespace and the one selecting the workload. As Istio, the oldest one is taken
+// when several apply at the same level.
+func workloadTelemetries(namespace, rootNamespace string, workloadLabels map[string]string, telemetries []kubernetes.IstioObject) []kubernetes.IstioObject {
+	var rootWide, namespaceWide, withSelector []kubernetes.IstioObject
+	for _, telemetry := range telemetries {
+		_, hasSelector := telemetry.GetSpec()["selector"]
+		switch telemetry.GetObjectMeta().Namespace {
+		case namespace:
+			if hasSelector {
+				withSelector = append(withSelector, telemetry)
+			} else {
+				namespaceWide = append(namespaceWide, telemetry)
+			}
+		case rootNamespace:
+			if !hasSelector {
+				rootWide = append(rootWide, telemetry)
+			}
+		}
+	}
+	selected := kubernetes.FilterIstioObjectsForWorkloadSelector(labels.Set(workloadLabels).String(), withSelector)
+
+	applied := []kubernetes.IstioObject{}
+	for _, candidates := range [][]kubernetes.IstioObject{rootWide, namespaceWide, selected} {
+		if oldest := oldestIstioObject(candidates); oldest != nil {
+			applied = append(applied, oldest)
+		}
+	}
+	return applied
+}
+
+func oldestIstioObject(objects []kubernetes.IstioObject) kubernetes.IstioObject {
+	if len(objects) == 0 {
+		return nil
+	}
+	sort.SliceStable(objects, func(i, j int) bool {
+		mi, mj := objects[i].GetObjectMeta(), objects[j].GetObjectMeta()
+		if !mi.CreationTimestamp.Equal(&mj.CreationTimestamp) {
+			return mi.CreationTimestamp.Before(&mj.CreationTimestamp)
+		}
+		return mi.Name < mj.Name
+	})
+	return objects[0]
+}
+
+// resolveTelemetryTracing merges the tracing of the Telemetries, each field being set by the most specific one
+func resolveTelemetryTracing(telemetries []kubernetes.IstioObject) telemetryTracing {
+	tracing := telemetryTracing{}
+	for _, telemetry := range telemetries {
+		entries, ok := telemetry.GetSpec()["tracing"].([]interface{})
+		if !ok || len(entries) == 0 {
+			continue
+		}
+		entry, ok := entries[0].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		meta := telemetry.GetObjectMeta()
+		name := meta.Namespace + "/" + meta.Name
+		if providers, ok := entry["providers"].([]interface{}); ok && len(providers) > 0 {
+			tracing.providers = []string{}
+			for _, provider := range providers {
+				if p, ok := provider.(map[string]interface{}); ok {
+					if providerName, ok := p["name"].(string); ok {
+						tracing.providers = append(tracing.providers, providerName)
+					}
+				}
+			}
+			tracing.providersFrom = name
+		}
+		if sampling, ok := entry["randomSamplingPercentage"].(float64); ok {
+			tracing.sampling = &sampling
+			tracing.samplingFrom = name
+		}
+		if disable, ok := entry["disableSpanReporting"].(bool); ok {
+			tracing.disableSpanReporting = disable
+			tracing.disableFrom = name
+		}
+	}
+	return tracing
+}
+
+// meshDefaultProviders returns the default providers of the mesh config for the kind: tracing, accessLogging...
+func meshDefaultProviders(meshConfig *models.MeshConfig, kind string) []string {
+	names := []string{}
+	defaultProviders, ok := meshConfig.Extra["defaultProviders"].(map[string]interface{})
+	if !ok {
+		return names
+	}
+	providers, _ := defaultProviders[kind].([]interface{})
+	for _, provider := range providers {
+		if name, ok := provider.(string); ok {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// meshExtensionProviders returns the extensionProviders of the mesh config by name
+func meshExtensionProviders(meshConfig *models.MeshConfig) map[string]map[string]interface{} {
+	defined := map[string]map[string]interface{}{}
+	providers, _ := meshConfig.Extra["extensionProviders"].([]interface{})
+	for _, provider := range providers {
+		if p, ok := provider.(map[string]interface{}); ok {
+			if name, ok := p["name"].(string); ok {
+				defined[name] = p
+			}
+		}
+	}
+	return defined
+}
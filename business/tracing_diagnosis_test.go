@@ -0,0 +1,224 @@
+package business
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	jaegerModels "github.com/jaegertracing/jaeger/model/json"
+	"github.com/stretchr/testify/assert"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kiali/kiali/jaeger"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+)
+
+func TestTracingDiagnosisHealthy(t *testing.T) {
+	assert := assert.New(t)
+
+	diagnosis := diagnoseTracing(fakeTracingWorkload(true), fakeTracingMeshConfig(t, ""), nil, tracesFound())
+
+	assert.Equal([]string{"zipkin"}, diagnosis.Providers)
+	assert.Equal(1.0, diagnosis.SamplingPercentage)
+	assert.Len(diagnosis.Checks, 4)
+	assert.Equal(models.TracingCheckPassed, diagnosis.Checks[0].Status)
+	assert.Equal(models.TracingCheckPassed, diagnosis.Checks[1].Status)
+	// The Istio default sampling is low, but traces were found
+	assert.Equal(models.TracingCheckWarning, diagnosis.Checks[2].Status)
+	assert.Equal(models.TracingCheckPassed, diagnosis.Checks[3].Status)
+	assert.Empty(diagnosis.LikelyCause)
+}
+
+func TestTracingDiagnosisNotInjected(t *testing.T) {
+	assert := assert.New(t)
+
+	diagnosis := diagnoseTracing(fakeTracingWorkload(false), fakeTracingMeshConfig(t, ""), nil, noTraces())
+
+	assert.Equal(models.TracingCheckInjection, diagnosis.Checks[0].Name)
+	assert.Equal(models.TracingCheckFailed, diagnosis.Checks[0].Status)
+	assert.Equal(models.TracingCauseNotInjected, diagnosis.LikelyCause)
+}
+
+func TestTracingDiagnosisTracingDisabled(t *testing.T) {
+	assert := assert.New(t)
+
+	diagnosis := diagnoseTracing(fakeTracingWorkload(true), fakeTracingMeshConfig(t, "enableTracing: false"), nil, noTraces())
+
+	assert.Empty(diagnosis.Providers)
+	assert.Equal(models.TracingCheckProvider, diagnosis.Checks[1].Name)
+	assert.Equal(models.TracingCheckFailed, diagnosis.Checks[1].Status)
+	assert.Equal(models.TracingCauseNoProvider, diagnosis.LikelyCause)
+}
+
+func TestTracingDiagnosisUndefinedProvider(t *testing.T) {
+	assert := assert.New(t)
+
+	meshConfig := fakeTracingMeshConfig(t, `
+extensionProviders:
+- name: otel
+  opentelemetry:
+    service: otel-collector.istio-system.svc.cluster.local
+    port: 4317
+`)
+	telemetries := []kubernetes.IstioObject{
+		fakeTelemetry("bookinfo", "tracing", nil, map[string]interface{}{
+			"providers": []interface{}{map[string]interface{}{"name": "jaeger"}},
+		}),
+	}
+
+	diagnosis := diagnoseTracing(fakeTracingWorkload(true), meshConfig, telemetries, noTraces())
+
+	assert.Equal(models.TracingCheckFailed, diagnosis.Checks[1].Status)
+	assert.Contains(diagnosis.Checks[1].Message, "[jaeger]")
+	assert.Contains(diagnosis.Checks[1].Message, "bookinfo/tracing")
+	assert.Equal(models.TracingCauseNoProvider, diagnosis.LikelyCause)
+}
+
+func TestTracingDiagnosisDefaultProvider(t *testing.T) {
+	assert := assert.New(t)
+
+	meshConfig := fakeTracingMeshConfig(t, `
+enableTracing: false
+defaultProviders:
+  tracing:
+  - otel
+extensionProviders:
+- name: otel
+  opentelemetry:
+    service: otel-collector.istio-system.svc.cluster.local
+    port: 4317
+`)
+
+	diagnosis := diagnoseTracing(fakeTracingWorkload(true), meshConfig, nil, tracesFound())
+
+	assert.Equal([]string{"otel"}, diagnosis.Providers)
+	assert.Equal(models.TracingCheckPassed, diagnosis.Checks[1].Status)
+}
+
+func TestTracingDiagnosisSpanReportingDisabled(t *testing.T) {
+	assert := assert.New(t)
+
+	telemetries := []kubernetes.IstioObject{
+		fakeTelemetry("bookinfo", "no-spans", nil, map[string]interface{}{
+			"disableSpanReporting": true,
+		}),
+	}
+
+	diagnosis := diagnoseTracing(fakeTracingWorkload(true), fakeTracingMeshConfig(t, ""), telemetries, noTraces())
+
+	assert.Equal(models.TracingCheckFailed, diagnosis.Checks[1].Status)
+	assert.Equal(models.TracingCauseSpanReportingDisabled, diagnosis.LikelyCause)
+}
+
+func TestTracingDiagnosisSamplingDisabled(t *testing.T) {
+	assert := assert.New(t)
+
+	meshConfig := fakeTracingMeshConfig(t, `
+defaultConfig:
+  tracing:
+    sampling: 0
+`)
+
+	diagnosis := diagnoseTracing(fakeTracingWorkload(true), meshConfig, nil, noTraces())
+
+	assert.Equal(0.0, diagnosis.SamplingPercentage)
+	assert.Equal(models.TracingCheckSampling, diagnosis.Checks[2].Name)
+	assert.Equal(models.TracingCheckFailed, diagnosis.Checks[2].Status)
+	assert.Equal(models.TracingCauseSamplingTooLow, diagnosis.LikelyCause)
+}
+
+func TestTracingDiagnosisSamplingTooLow(t *testing.T) {
+	assert := assert.New(t)
+
+	// The Telemetry selecting the workload overrides the sampling of the namespace and of the mesh
+	telemetries := []kubernetes.IstioObject{
+		fakeTelemetry("istio-system", "mesh-default", nil, map[string]interface{}{
+			"randomSamplingPercentage": 100.0,
+		}),
+		fakeTelemetry("bookinfo", "namespace", nil, map[string]interface{}{
+			"randomSamplingPercentage": 50.0,
+		}),
+		fakeTelemetry("bookinfo", "reviews", map[string]interface{}{"app": "reviews"}, map[string]interface{}{
+			"randomSamplingPercentage": 0.1,
+		}),
+		fakeTelemetry("bookinfo", "ratings", map[string]interface{}{"app": "ratings"}, map[string]interface{}{
+			"randomSamplingPercentage": 0.0,
+		}),
+	}
+	applied := workloadTelemetries("bookinfo", "istio-system", map[string]string{"app": "reviews", "version": "v1"}, telemetries)
+	assert.Len(applied, 3)
+
+	diagnosis := diagnoseTracing(fakeTracingWorkload(true), fakeTracingMeshConfig(t, ""), applied, noTraces())
+
+	assert.Equal(0.1, diagnosis.SamplingPercentage)
+	assert.Equal(models.TracingCheckWarning, diagnosis.Checks[2].Status)
+	assert.Contains(diagnosis.Checks[2].Message, "bookinfo/reviews")
+	assert.Equal(models.TracingCauseSamplingTooLow, diagnosis.LikelyCause)
+}
+
+func TestTracingDiagnosisNoTraces(t *testing.T) {
+	assert := assert.New(t)
+
+	telemetries := []kubernetes.IstioObject{
+		fakeTelemetry("bookinfo", "namespace", nil, map[string]interface{}{
+			"randomSamplingPercentage": 100.0,
+		}),
+	}
+
+	diagnosis := diagnoseTracing(fakeTracingWorkload(true), fakeTracingMeshConfig(t, ""), telemetries, noTraces())
+
+	assert.Equal(models.TracingCheckPassed, diagnosis.Checks[2].Status)
+	assert.Equal(models.TracingCheckFailed, diagnosis.Checks[3].Status)
+	assert.Equal(models.TracingCauseNoTraces, diagnosis.LikelyCause)
+}
+
+func TestTracingDiagnosisTracesUnknown(t *testing.T) {
+	assert := assert.New(t)
+
+	check := tracesCheck("10m", nil, errors.New("connection refused"))
+
+	assert.Equal(models.TracingCheckUnknown, check.Status)
+	assert.Empty(check.Cause)
+	assert.Empty(likelyTracingCause([]models.TracingCheck{check}))
+}
+
+func fakeTracingWorkload(sidecar bool) *models.Workload {
+	wkd := &models.Workload{}
+	wkd.Name = "reviews-v1"
+	wkd.Labels = map[string]string{"app": "reviews", "version": "v1"}
+	wkd.IstioSidecar = sidecar
+	return wkd
+}
+
+func fakeTracingMeshConfig(t *testing.T, meshConfigYaml string) *models.MeshConfig {
+	meshConfig, err := models.ParseMeshConfig(meshConfigYaml, models.DefaultMeshConfig("istio-system"))
+	assert.NoError(t, err)
+	return meshConfig
+}
+
+func fakeTelemetry(namespace, name string, matchLabels, tracing map[string]interface{}) kubernetes.IstioObject {
+	spec := map[string]interface{}{
+		"tracing": []interface{}{tracing},
+	}
+	if matchLabels != nil {
+		spec["selector"] = map[string]interface{}{"matchLabels": matchLabels}
+	}
+	return &kubernetes.GenericIstioObject{
+		TypeMeta: meta_v1.TypeMeta{Kind: kubernetes.TelemetryType},
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:              name,
+			Namespace:         namespace,
+			CreationTimestamp: meta_v1.NewTime(time.Date(2021, 01, 15, 0, 0, 0, 0, time.UTC)),
+		},
+		Spec: spec,
+	}
+}
+
+func tracesFound() models.TracingCheck {
+	return tracesCheck("10m", &jaeger.JaegerResponse{Data: []jaegerModels.Trace{{TraceID: "a"}}}, nil)
+}
+
+func noTraces() models.TracingCheck {
+	return tracesCheck("10m", &jaeger.JaegerResponse{}, nil)
+}
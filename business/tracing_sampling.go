@@ -0,0 +1,126 @@
+package business
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	jaegerModels "github.com/jaegertracing/jaeger/model/json"
+	"github.com/prometheus/common/model"
+	"k8s.io/apimachinery/pkg/api/errors"
+
+	"github.com/kiali/kiali/log"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/prometheus/internalmetrics"
+)
+
+// GetSamplingEstimates estimates the trace sampling of the services of the namespace, comparing the requests
+// they received over the window, according to Istio telemetry, with the server spans found for them in the traces.
+// The traces of each service are queried up to the given limit.
+func (in *JaegerService) GetSamplingEstimates(ns, window string, limit int, queryTime time.Time) (*models.TracingSampling, error) {
+	var err error
+	promtimer := internalmetrics.GetGoFunctionMetric("business", "Jaeger", "GetSamplingEstimates")
+	defer promtimer.ObserveNow(&err)
+
+	if _, err = in.businessLayer.Namespace.GetNamespace(ns); err != nil {
+		return nil, err
+	}
+	duration, parseErr := model.ParseDuration(window)
+	if parseErr != nil {
+		err = errors.NewBadRequest("invalid window: " + parseErr.Error())
+		return nil, err
+	}
+	rates, err := in.prom.GetNamespaceServicesRequestRates(ns, window, queryTime)
+	if err != nil {
+		return nil, err
+	}
+	requests := servicesRequestCounts(rates, time.Duration(duration))
+
+	query := models.TracingQuery{
+		Start: queryTime.Add(-time.Duration(duration)),
+		End:   queryTime,
+		Tags:  map[string]string{"span.kind": "server"},
+		Limit: limit,
+	}
+	services := make([]string, 0, len(requests))
+	for service := range requests {
+		services = append(services, service)
+	}
+	sort.Strings(services)
+
+	sampling := &models.TracingSampling{
+		Namespace:   ns,
+		Window:      window,
+		Services:    []models.ServiceSampling{},
+		Limitations: models.TracingSamplingLimitations,
+	}
+	for _, service := range services {
+		serviceSampling := models.ServiceSampling{
+			Service:  service,
+			Requests: requests[service],
+		}
+		r, tracesErr := in.GetAppTraces(ns, service, query)
+		if tracesErr != nil {
+			err = tracesErr
+			return nil, err
+		}
+		if r != nil {
+			serviceSampling.Spans = len(tracesToSpans(service, r, windowServerSpanFilter(query.Start, query.End)))
+			serviceSampling.LimitReached = limit > 0 && len(r.Data) >= limit
+		}
+		serviceSampling.SamplingPercentage = samplingPercentage(serviceSampling.Spans, serviceSampling.Requests)
+		sampling.Services = append(sampling.Services, serviceSampling)
+	}
+	return sampling, nil
+}
+
+// servicesRequestCounts turns the request rates of the namespace services into request counts over the window.
+// Only the destination reporter is considered, as the source one would count the same requests twice.
+func servicesRequestCounts(rates model.Vector, window time.Duration) map[string]float64 {
+	counts := map[string]float64{}
+	for _, sample := range rates {
+		if string(sample.Metric["reporter"]) != "destination" {
+			continue
+		}
+		service := string(sample.Metric["destination_canonical_service"])
+		if service == "" || service == "unknown" {
+			service = string(sample.Metric["destination_app"])
+		}
+		if service == "" || service == "unknown" {
+			log.Tracef("Skipping requests to a service without canonical name: %v", sample.Metric)
+			continue
+		}
+		value := float64(sample.Value)
+		if math.IsNaN(value) {
+			continue
+		}
+		counts[service] += value * window.Seconds()
+	}
+	return counts
+}
+
+// windowServerSpanFilter keeps the server spans, one per request received by the service, started within the window
+func windowServerSpanFilter(start, end time.Time) SpanFilter {
+	startMicros := uint64(start.UnixNano() / int64(time.Microsecond))
+	endMicros := uint64(end.UnixNano() / int64(time.Microsecond))
+	return func(span *jaegerModels.Span) bool {
+		if span.StartTime < startMicros || span.StartTime > endMicros {
+			return false
+		}
+		for _, tag := range span.Tags {
+			if tag.Key == "span.kind" {
+				return tag.Value == "server"
+			}
+		}
+		return false
+	}
+}
+
+// samplingPercentage is capped to 100%, as the extrapolated request count can be lower than the spans found
+func samplingPercentage(spans int, requests float64) *float64 {
+	if requests <= 0 {
+		return nil
+	}
+	percentage := math.Min(100*float64(spans)/requests, 100)
+	return &percentage
+}
@@ -0,0 +1,143 @@
+package business
+
+import (
+	"testing"
+	"time"
+
+	jaegerModels "github.com/jaegertracing/jaeger/model/json"
+	osproject_v1 "github.com/openshift/api/project/v1"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/api/errors"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/jaeger"
+	"github.com/kiali/kiali/kubernetes/kubetest"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/prometheus/prometheustest"
+)
+
+func TestGetSamplingEstimates(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	queryTime := time.Date(2017, 01, 15, 0, 10, 0, 0, time.UTC)
+	inWindow := queryTime.Add(-5 * time.Minute)
+	beforeWindow := queryTime.Add(-15 * time.Minute)
+
+	k8s := new(kubetest.K8SClientMock)
+	k8s.On("IsOpenShift").Return(true)
+	k8s.On("GetProject", "bookinfo").Return(&osproject_v1.Project{}, nil)
+
+	prom := new(prometheustest.PromClientMock)
+	prom.On("GetNamespaceServicesRequestRates", "bookinfo", "10m", queryTime).Return(model.Vector{
+		samplingRequestSample("destination", "reviews", "200", 0.8),
+		samplingRequestSample("destination", "reviews", "500", 0.2),
+		// The same requests, reported by the client proxy
+		samplingRequestSample("source", "reviews", "200", 1),
+		samplingRequestSample("destination", "ratings", "200", 0.5),
+		samplingRequestSample("destination", "details", "200", 0.1),
+	}, nil)
+
+	client := new(jaegerClientMock)
+	query := models.TracingQuery{
+		Start: queryTime.Add(-10 * time.Minute),
+		End:   queryTime,
+		Tags:  map[string]string{"span.kind": "server"},
+		Limit: 100,
+	}
+	reviewsTraces := []jaegerModels.Trace{}
+	for i := 0; i < 6; i++ {
+		reviewsTraces = append(reviewsTraces, samplingTrace("reviews.bookinfo", inWindow))
+	}
+	client.On("GetAppTraces", "bookinfo", "reviews", query).Return(&jaeger.JaegerResponse{
+		Data:              reviewsTraces,
+		JaegerServiceName: "reviews.bookinfo",
+	}, nil)
+	client.On("GetAppTraces", "bookinfo", "ratings", query).Return(&jaeger.JaegerResponse{
+		Data: []jaegerModels.Trace{
+			samplingTrace("ratings.bookinfo", inWindow),
+			samplingTrace("ratings.bookinfo", inWindow),
+			samplingTrace("ratings.bookinfo", beforeWindow),
+		},
+		JaegerServiceName: "ratings.bookinfo",
+	}, nil)
+	client.On("GetAppTraces", "bookinfo", "details", query).Return(&jaeger.JaegerResponse{
+		Data:              []jaegerModels.Trace{},
+		JaegerServiceName: "details.bookinfo",
+	}, nil)
+
+	layer := NewWithBackends(k8s, prom, func() (jaeger.ClientInterface, error) {
+		return client, nil
+	})
+
+	sampling, err := layer.Jaeger.GetSamplingEstimates("bookinfo", "10m", 100, queryTime)
+	assert.NoError(err)
+	assert.Equal("bookinfo", sampling.Namespace)
+	assert.Equal(models.TracingSamplingLimitations, sampling.Limitations)
+	assert.Len(sampling.Services, 3)
+
+	details := sampling.Services[0]
+	assert.Equal("details", details.Service)
+	assert.InDelta(60, details.Requests, 0.0001)
+	assert.Equal(0, details.Spans)
+	assert.Equal(0.0, *details.SamplingPercentage)
+
+	// The span started before the window is not counted
+	ratings := sampling.Services[1]
+	assert.Equal("ratings", ratings.Service)
+	assert.InDelta(300, ratings.Requests, 0.0001)
+	assert.Equal(2, ratings.Spans)
+	assert.InDelta(0.6667, *ratings.SamplingPercentage, 0.0001)
+
+	// The source reporter is ignored, the client spans aren't counted
+	reviews := sampling.Services[2]
+	assert.Equal("reviews", reviews.Service)
+	assert.InDelta(600, reviews.Requests, 0.0001)
+	assert.Equal(6, reviews.Spans)
+	assert.InDelta(1, *reviews.SamplingPercentage, 0.0001)
+	assert.False(reviews.LimitReached)
+
+	_, err = layer.Jaeger.GetSamplingEstimates("bookinfo", "ten minutes", 100, queryTime)
+	assert.True(errors.IsBadRequest(err))
+}
+
+func TestSamplingPercentage(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Nil(samplingPercentage(0, 0))
+	assert.Equal(50.0, *samplingPercentage(5, 10))
+	// Extrapolated request counts can be lower than the spans found
+	assert.Equal(100.0, *samplingPercentage(12, 10))
+}
+
+func samplingRequestSample(reporter, service, code string, value float64) *model.Sample {
+	return &model.Sample{
+		Metric: model.Metric{
+			"reporter":                      model.LabelValue(reporter),
+			"destination_service_namespace": "bookinfo",
+			"destination_canonical_service": model.LabelValue(service),
+			"response_code":                 model.LabelValue(code),
+		},
+		Value: model.SampleValue(value),
+	}
+}
+
+// samplingTrace returns a trace with the server span of the service and a client span to another service
+func samplingTrace(service string, start time.Time) jaegerModels.Trace {
+	startMicros := uint64(start.UnixNano() / int64(time.Microsecond))
+	return jaegerModels.Trace{
+		Spans: []jaegerModels.Span{{
+			ProcessID: "p1",
+			StartTime: startMicros,
+			Tags:      []jaegerModels.KeyValue{{Key: "span.kind", Value: "server"}},
+		}, {
+			ProcessID: "p1",
+			StartTime: startMicros,
+			Tags:      []jaegerModels.KeyValue{{Key: "span.kind", Value: "client"}},
+		}},
+		Processes: map[jaegerModels.ProcessID]jaegerModels.Process{
+			"p1": {ServiceName: service},
+		},
+	}
+}
@@ -0,0 +1,239 @@
+package business
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	jaegerModels "github.com/jaegertracing/jaeger/model/json"
+
+	"github.com/kiali/kiali/log"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/prometheus/internalmetrics"
+)
+
+// GetTrace returns the trace with its spans organized as a tree. In multicluster, the spans of the trace
+// may be stored in the tracing backends of several clusters: all of them are queried and their spans merged.
+// The spans are correlated to the Kiali workloads that reported them, when possible.
+// Nil is returned when no tracing backend knows the trace.
+func (in *JaegerService) GetTrace(traceID string) (*models.TracingTrace, error) {
+	var err error
+	promtimer := internalmetrics.GetGoFunctionMetric("business", "Jaeger", "GetTrace")
+	defer promtimer.ObserveNow(&err)
+
+	clusters := in.backendClusters()
+	found := map[string]*jaegerModels.Trace{}
+	failed := 0
+	var lastErr error
+	for _, cluster := range clusters {
+		client, clientErr := in.clusterClient(cluster)
+		if clientErr != nil {
+			failed++
+			lastErr = clientErr
+			continue
+		}
+		r, traceErr := client.GetTraceDetail(traceID)
+		if traceErr != nil {
+			log.Errorf("Error fetching trace [%s] from the tracing backend of cluster [%s]: %v", traceID, cluster, traceErr)
+			failed++
+			lastErr = traceErr
+			continue
+		}
+		if r != nil {
+			found[cluster] = &r.Data
+		}
+	}
+	if failed == len(clusters) {
+		err = lastErr
+		return nil, err
+	}
+	if len(found) == 0 {
+		return nil, nil
+	}
+
+	spans := collectTraceSpans(found)
+	if in.businessLayer != nil {
+		correlateSpanWorkloads(in.businessLayer, spans)
+	}
+	return buildTracingTrace(traceID, spans), nil
+}
+
+// collectTraceSpans converts the spans of the trace found in each cluster, keyed by cluster. A span stored in
+// several tracing backends is kept once, the default backend, keyed by the empty cluster, coming first.
+func collectTraceSpans(found map[string]*jaegerModels.Trace) []*models.TracingSpan {
+	clusters := make([]string, 0, len(found))
+	for cluster := range found {
+		clusters = append(clusters, cluster)
+	}
+	sort.Strings(clusters)
+
+	spans := []*models.TracingSpan{}
+	seen := map[jaegerModels.SpanID]bool{}
+	for _, cluster := range clusters {
+		trace := found[cluster]
+		for i := range trace.Spans {
+			span := &trace.Spans[i]
+			if seen[span.SpanID] {
+				continue
+			}
+			seen[span.SpanID] = true
+			process := span.Process
+			if p, ok := trace.Processes[span.ProcessID]; ok {
+				process = &p
+			}
+			spans = append(spans, convertSpan(span, process, cluster))
+		}
+	}
+	return spans
+}
+
+func convertSpan(span *jaegerModels.Span, process *jaegerModels.Process, cluster string) *models.TracingSpan {
+	converted := &models.TracingSpan{
+		SpanID:        string(span.SpanID),
+		ParentSpanID:  string(spanParentID(span)),
+		OperationName: span.OperationName,
+		Cluster:       cluster,
+		StartTime:     span.StartTime,
+		Duration:      span.Duration,
+		Tags:          map[string]string{},
+		Children:      []*models.TracingSpan{},
+	}
+	if process != nil {
+		converted.Service = process.ServiceName
+	}
+	for _, tag := range span.Tags {
+		value := fmt.Sprintf("%v", tag.Value)
+		converted.Tags[tag.Key] = value
+		switch tag.Key {
+		case "error":
+			converted.Error = value == "true"
+		case "node_id":
+			converted.Pod, converted.Namespace = parseNodeIDPod(value)
+		}
+	}
+	return converted
+}
+
+// spanParentID returns the span referenced as parent, preferring a CHILD_OF reference over a FOLLOWS_FROM one
+func spanParentID(span *jaegerModels.Span) jaegerModels.SpanID {
+	var followed jaegerModels.SpanID
+	for _, ref := range span.References {
+		if ref.TraceID != "" && span.TraceID != "" && ref.TraceID != span.TraceID {
+			continue
+		}
+		if ref.RefType == jaegerModels.ChildOf {
+			return ref.SpanID
+		}
+		if ref.RefType == jaegerModels.FollowsFrom && followed == "" {
+			followed = ref.SpanID
+		}
+	}
+	if followed != "" {
+		return followed
+	}
+	return span.ParentSpanID
+}
+
+// parseNodeIDPod returns the pod and namespace of an Envoy node_id tag, like:
+// sidecar~172.17.0.20~reviews-6d8996bff-ztg6z.default~default.svc.cluster.local
+func parseNodeIDPod(nodeID string) (string, string) {
+	parts := strings.Split(nodeID, "~")
+	if len(parts) < 3 {
+		return "", ""
+	}
+	i := strings.LastIndex(parts[2], ".")
+	if i <= 0 || i == len(parts[2])-1 {
+		return "", ""
+	}
+	return parts[2][:i], parts[2][i+1:]
+}
+
+// correlateSpanWorkloads sets the workload of the spans reported by known pods, and the cluster of the pod when set.
+// The workloads of each namespace are fetched once; a namespace whose workloads can't be fetched is skipped.
+func correlateSpanWorkloads(layer *Layer, spans []*models.TracingSpan) {
+	podWorkloads := map[string]map[string]*models.Workload{}
+	for _, span := range spans {
+		if span.Pod == "" {
+			continue
+		}
+		workloads, ok := podWorkloads[span.Namespace]
+		if !ok {
+			workloads = map[string]*models.Workload{}
+			podWorkloads[span.Namespace] = workloads
+			ws, err := fetchWorkloads(layer, span.Namespace, "")
+			if err != nil {
+				log.Debugf("Workloads of namespace [%s] not correlated to trace spans: %v", span.Namespace, err)
+				continue
+			}
+			for _, w := range ws {
+				for _, pod := range w.Pods {
+					workloads[pod.Name] = w
+				}
+			}
+		}
+		w, found := workloads[span.Pod]
+		if !found {
+			continue
+		}
+		span.Workload = w.Name
+		for _, pod := range w.Pods {
+			if pod.Name == span.Pod && pod.Cluster != "" {
+				span.Cluster = pod.Cluster
+			}
+		}
+	}
+}
+
+// buildTracingTrace links the spans to their parent. Spans without parent in the trace are the roots.
+func buildTracingTrace(traceID string, spans []*models.TracingSpan) *models.TracingTrace {
+	trace := &models.TracingTrace{
+		TraceID:    traceID,
+		Services:   []string{},
+		Clusters:   []string{},
+		SpansCount: len(spans),
+		Roots:      []*models.TracingSpan{},
+	}
+
+	byID := make(map[string]*models.TracingSpan, len(spans))
+	for _, span := range spans {
+		byID[span.SpanID] = span
+	}
+	services := map[string]bool{}
+	clusters := map[string]bool{}
+	var end uint64
+	for _, span := range spans {
+		if parent, ok := byID[span.ParentSpanID]; ok && parent != span {
+			parent.Children = append(parent.Children, span)
+		} else {
+			trace.Roots = append(trace.Roots, span)
+		}
+		if trace.StartTime == 0 || span.StartTime < trace.StartTime {
+			trace.StartTime = span.StartTime
+		}
+		if span.StartTime+span.Duration > end {
+			end = span.StartTime + span.Duration
+		}
+		if span.Service != "" && !services[span.Service] {
+			services[span.Service] = true
+			trace.Services = append(trace.Services, span.Service)
+		}
+		if span.Cluster != "" && !clusters[span.Cluster] {
+			clusters[span.Cluster] = true
+			trace.Clusters = append(trace.Clusters, span.Cluster)
+		}
+	}
+	trace.Duration = end - trace.StartTime
+	sort.Strings(trace.Services)
+	sort.Strings(trace.Clusters)
+
+	byStartTime := func(spans []*models.TracingSpan) {
+		sort.SliceStable(spans, func(i, j int) bool {
+			return spans[i].StartTime < spans[j].StartTime
+		})
+	}
+	byStartTime(trace.Roots)
+	for _, span := range spans {
+		byStartTime(span.Children)
+	}
+	return trace
+}
@@ -0,0 +1,160 @@
+package business
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	jaegerModels "github.com/jaegertracing/jaeger/model/json"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kiali/kiali/jaeger"
+)
+
+const productpageTraceJSON = `{
+  "traceID": "a1b2c3",
+  "spans": [
+    {
+      "traceID": "a1b2c3", "spanID": "ratings", "operationName": "ratings.bookinfo.svc.cluster.local:9080/*",
+      "references": [{"refType": "CHILD_OF", "traceID": "a1b2c3", "spanID": "reviews"}],
+      "startTime": 1000300, "duration": 100, "processID": "p3",
+      "tags": [
+        {"key": "node_id", "type": "string", "value": "sidecar~172.17.0.22~ratings-v1-b6994bb9-gl4nz.bookinfo~bookinfo.svc.cluster.local"},
+        {"key": "http.status_code", "type": "string", "value": "503"},
+        {"key": "error", "type": "bool", "value": true}
+      ]
+    },
+    {
+      "traceID": "a1b2c3", "spanID": "productpage", "operationName": "productpage.bookinfo.svc.cluster.local:9080/productpage",
+      "references": [], "startTime": 1000000, "duration": 1000, "processID": "p1",
+      "tags": [
+        {"key": "node_id", "type": "string", "value": "sidecar~172.17.0.20~productpage-v1-6b746f74dc-9stp2.bookinfo~bookinfo.svc.cluster.local"}
+      ]
+    },
+    {
+      "traceID": "a1b2c3", "spanID": "details", "operationName": "details.bookinfo.svc.cluster.local:9080/*",
+      "references": [{"refType": "CHILD_OF", "traceID": "a1b2c3", "spanID": "productpage"}],
+      "startTime": 1000500, "duration": 200, "processID": "p2", "tags": []
+    },
+    {
+      "traceID": "a1b2c3", "spanID": "reviews", "operationName": "reviews.bookinfo.svc.cluster.local:9080/*",
+      "references": [{"refType": "CHILD_OF", "traceID": "a1b2c3", "spanID": "productpage"}],
+      "startTime": 1000100, "duration": 400, "processID": "p2", "tags": []
+    },
+    {
+      "traceID": "a1b2c3", "spanID": "audit", "operationName": "audit",
+      "references": [{"refType": "FOLLOWS_FROM", "traceID": "a1b2c3", "spanID": "productpage"}],
+      "startTime": 1001000, "duration": 300, "processID": "p1", "tags": []
+    }
+  ],
+  "processes": {
+    "p1": {"serviceName": "productpage.bookinfo", "tags": []},
+    "p2": {"serviceName": "reviews.bookinfo", "tags": []},
+    "p3": {"serviceName": "ratings.bookinfo", "tags": []}
+  }
+}`
+
+func TestBuildTracingTraceFromJaegerJSON(t *testing.T) {
+	assert := assert.New(t)
+
+	trace := jaegerModels.Trace{}
+	assert.NoError(json.Unmarshal([]byte(productpageTraceJSON), &trace))
+
+	tree := buildTracingTrace("a1b2c3", collectTraceSpans(map[string]*jaegerModels.Trace{"": &trace}))
+
+	assert.Equal("a1b2c3", tree.TraceID)
+	assert.Equal(5, tree.SpansCount)
+	assert.Equal(uint64(1000000), tree.StartTime)
+	assert.Equal(uint64(1300), tree.Duration)
+	assert.Equal([]string{"productpage.bookinfo", "ratings.bookinfo", "reviews.bookinfo"}, tree.Services)
+	assert.Empty(tree.Clusters)
+
+	assert.Len(tree.Roots, 1)
+	root := tree.Roots[0]
+	assert.Equal("productpage", root.SpanID)
+	assert.Empty(root.ParentSpanID)
+	assert.Equal("productpage-v1-6b746f74dc-9stp2", root.Pod)
+	assert.Equal("bookinfo", root.Namespace)
+	assert.Equal(uint64(1000), root.Duration)
+
+	// Children sorted by start time, the FOLLOWS_FROM reference makes a child too
+	assert.Len(root.Children, 3)
+	assert.Equal("reviews", root.Children[0].SpanID)
+	assert.Equal("details", root.Children[1].SpanID)
+	assert.Equal("audit", root.Children[2].SpanID)
+	assert.Equal("productpage", root.Children[2].ParentSpanID)
+
+	reviews := root.Children[0]
+	assert.Equal("reviews.bookinfo", reviews.Service)
+	assert.Len(reviews.Children, 1)
+	ratings := reviews.Children[0]
+	assert.Equal("ratings", ratings.SpanID)
+	assert.Equal("ratings.bookinfo", ratings.Service)
+	assert.Equal("503", ratings.Tags["http.status_code"])
+	assert.True(ratings.Error)
+	assert.Empty(ratings.Children)
+	assert.NotNil(ratings.Children)
+}
+
+func TestBuildTracingTraceWithMissingParent(t *testing.T) {
+	assert := assert.New(t)
+
+	trace := jaegerModels.Trace{}
+	assert.NoError(json.Unmarshal([]byte(productpageTraceJSON), &trace))
+	// The productpage span is not reported: its children become roots
+	trace.Spans = trace.Spans[2:]
+
+	tree := buildTracingTrace("a1b2c3", collectTraceSpans(map[string]*jaegerModels.Trace{"": &trace}))
+
+	assert.Equal(3, tree.SpansCount)
+	assert.Len(tree.Roots, 3)
+	assert.Equal("reviews", tree.Roots[0].SpanID)
+	assert.Equal("productpage", tree.Roots[0].ParentSpanID)
+	assert.Equal("details", tree.Roots[1].SpanID)
+	assert.Equal("audit", tree.Roots[2].SpanID)
+}
+
+func TestGetTraceMultiCluster(t *testing.T) {
+	assert := assert.New(t)
+	svc, defaultClient, westClient, _ := setupMultiClusterJaeger()
+
+	full := jaegerModels.Trace{}
+	assert.NoError(json.Unmarshal([]byte(productpageTraceJSON), &full))
+	// The default backend stores the productpage and reviews spans, the west one the reviews and ratings spans
+	east := full
+	east.Spans = []jaegerModels.Span{full.Spans[1], full.Spans[3]}
+	west := full
+	west.Spans = []jaegerModels.Span{full.Spans[3], full.Spans[0]}
+
+	defaultClient.On("GetTraceDetail", "a1b2c3").Return(&jaeger.JaegerSingleTrace{Data: east}, nil)
+	westClient.On("GetTraceDetail", "a1b2c3").Return(&jaeger.JaegerSingleTrace{Data: west}, nil)
+
+	tree, err := svc.GetTrace("a1b2c3")
+	assert.NoError(err)
+	assert.Equal(3, tree.SpansCount)
+	// The west backend, shared with the north cluster, is queried as the north one
+	assert.Equal([]string{"north"}, tree.Clusters)
+
+	assert.Len(tree.Roots, 1)
+	assert.Equal("productpage", tree.Roots[0].SpanID)
+	assert.Empty(tree.Roots[0].Cluster)
+	assert.Len(tree.Roots[0].Children, 1)
+	reviews := tree.Roots[0].Children[0]
+	// Stored in both backends, kept from the default one
+	assert.Empty(reviews.Cluster)
+	assert.Len(reviews.Children, 1)
+	assert.Equal("ratings", reviews.Children[0].SpanID)
+	assert.Equal("north", reviews.Children[0].Cluster)
+}
+
+func TestGetTraceNotFound(t *testing.T) {
+	assert := assert.New(t)
+	svc, defaultClient, westClient, _ := setupMultiClusterJaeger()
+
+	defaultClient.On("GetTraceDetail", "t4").Return((*jaeger.JaegerSingleTrace)(nil), nil)
+	westClient.On("GetTraceDetail", "t4").Return((*jaeger.JaegerSingleTrace)(nil), errors.New("unavailable"))
+
+	tree, err := svc.GetTrace("t4")
+	assert.NoError(err)
+	assert.Nil(tree)
+}
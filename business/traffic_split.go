@@ -0,0 +1,237 @@
+package business
+
+import (
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/log"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/prometheus/internalmetrics"
+)
+
+// trafficSplitDivergence is the difference, in percentage points, between the configured and the observed weight
+// of a destination above which a split is flagged as divergent
+const trafficSplitDivergence = 10.0
+
+// GetServiceTrafficSplits compares the weights of the routes of the VirtualServices splitting the traffic of the service
+// across several destinations with the traffic observed per subset, to detect routing problems.
+func (in *SvcService) GetServiceTrafficSplits(namespace, service, rateInterval string, queryTime time.Time) (*models.TrafficSplits, error) {
+	var err error
+	promtimer := internalmetrics.GetGoFunctionMetric("business", "SvcService", "GetServiceTrafficSplits")
+	defer promtimer.ObserveNow(&err)
+
+	// Check if user has access to the namespace (RBAC) in cache scenarios and/or
+	// if namespace is accessible from Kiali (Deployment.AccessibleNamespaces)
+	if _, err = in.businessLayer.Namespace.GetNamespace(namespace); err != nil {
+		return nil, err
+	}
+
+	svc, err := in.getService(namespace, service)
+	if err != nil {
+		return nil, err
+	}
+
+	var vs, dr []kubernetes.IstioObject
+	var ws models.Workloads
+	var requestRates, connectionRates model.Vector
+
+	wg := sync.WaitGroup{}
+	wg.Add(4)
+	errChan := make(chan error, 4)
+
+	go func() {
+		defer wg.Done()
+		var err2 error
+		if IsResourceCached(namespace, kubernetes.VirtualServices) {
+			vs, err2 = kialiCache.GetIstioObjects(namespace, kubernetes.VirtualServices, "")
+		} else {
+			vs, err2 = in.k8s.GetIstioObjects(namespace, kubernetes.VirtualServices, "")
+		}
+		if err2 != nil {
+			errChan <- err2
+		} else {
+			vs = kubernetes.FilterVirtualServices(vs, namespace, service)
+		}
+	}()
+
+	go func() {
+		defer wg.Done()
+		var err2 error
+		if IsResourceCached(namespace, kubernetes.DestinationRules) {
+			dr, err2 = kialiCache.GetIstioObjects(namespace, kubernetes.DestinationRules, "")
+		} else {
+			dr, err2 = in.k8s.GetIstioObjects(namespace, kubernetes.DestinationRules, "")
+		}
+		if err2 != nil {
+			errChan <- err2
+		} else {
+			dr = kubernetes.FilterDestinationRules(dr, namespace, service)
+		}
+	}()
+
+	go func() {
+		defer wg.Done()
+		selector := labels.Set(svc.Spec.Selector).String()
+		// Without selector, the workloads of the service are unknown
+		if selector == "" {
+			return
+		}
+		var err2 error
+		ws, err2 = fetchWorkloads(in.businessLayer, namespace, selector)
+		if err2 != nil {
+			log.Errorf("Error fetching Workloads per namespace %s and service %s: %s", namespace, service, err2)
+			errChan <- err2
+		}
+	}()
+
+	go func() {
+		defer wg.Done()
+		lb := NewMetricsLabelsBuilder("inbound")
+		lb.SelfReporter()
+		lb.Service(service, namespace)
+		var err2 error
+		requestRates, err2 = in.prom.FetchRateValues(telemetryMetric("istio_requests_total"), lb.Build(), telemetryLabel("destination_workload"), rateInterval, queryTime)
+		if err2 != nil {
+			errChan <- err2
+			return
+		}
+		connectionRates, err2 = in.prom.FetchRateValues(telemetryMetric("istio_tcp_connections_opened_total"), lb.Build(), telemetryLabel("destination_workload"), rateInterval, queryTime)
+		if err2 != nil {
+			errChan <- err2
+		}
+	}()
+
+	wg.Wait()
+	if len(errChan) != 0 {
+		err = <-errChan
+		return nil, err
+	}
+
+	return buildTrafficSplits(namespace, service, vs, dr, ws, requestRates, connectionRates), nil
+}
+
+// buildTrafficSplits sets the observed traffic of the destinations of the weighted routes to the service.
+// The telemetry doesn't tell which route a request took: the traffic of the service is only compared with
+// the weights of a route when it is the only route of its VirtualService to the service.
+func buildTrafficSplits(namespace, service string, virtualServices, destinationRules []kubernetes.IstioObject, ws models.Workloads, requestRates, connectionRates model.Vector) *models.TrafficSplits {
+	splits := &models.TrafficSplits{
+		Namespace: namespace,
+		Service:   service,
+		Splits:    []models.TrafficSplit{},
+	}
+
+	ratesByProtocol := map[string]map[string]float64{
+		"http": workloadRates(requestRates),
+		"tcp":  workloadRates(connectionRates),
+		"tls":  workloadRates(connectionRates),
+	}
+
+	for _, vs := range virtualServices {
+		vsSplits := models.ParseTrafficSplits(vs, namespace, service)
+		routes := countServiceRoutes(vs, namespace, service)
+		for _, split := range vsSplits {
+			rates := ratesByProtocol[split.Protocol]
+			for _, rate := range rates {
+				split.ObservedRate += rate
+			}
+			split.Comparable = routes == 1
+			for i := range split.Destinations {
+				d := &split.Destinations[i]
+				selectorLabels, found := subsetLabels(destinationRules, d.Subset, namespace, service)
+				if !found || !kubernetes.FilterByHost(d.Host, service, namespace) {
+					split.Comparable = false
+					continue
+				}
+				observed := 0.0
+				selector := labels.SelectorFromSet(selectorLabels)
+				for _, w := range ws {
+					if selector.Matches(labels.Set(w.Labels)) {
+						observed += rates[w.Name]
+					}
+				}
+				d.ObservedRate = &observed
+				if split.ObservedRate > 0 {
+					weight := observed * 100 / split.ObservedRate
+					d.ObservedWeight = &weight
+				}
+			}
+			if split.Comparable && split.ObservedRate > 0 {
+				for _, d := range split.Destinations {
+					if math.Abs(*d.ObservedWeight-float64(d.Weight)) > trafficSplitDivergence {
+						split.Divergent = true
+					}
+				}
+			}
+			splits.Splits = append(splits.Splits, split)
+		}
+	}
+	return splits
+}
+
+func workloadRates(rates model.Vector) map[string]float64 {
+	lblWorkload := model.LabelName(telemetryLabel("destination_workload"))
+	byWorkload := map[string]float64{}
+	for _, sample := range rates {
+		value := float64(sample.Value)
+		if math.IsNaN(value) {
+			continue
+		}
+		byWorkload[string(sample.Metric[lblWorkload])] += value
+	}
+	return byWorkload
+}
+
+// countServiceRoutes returns the number of routes of the VirtualService with a destination to the service
+func countServiceRoutes(vs kubernetes.IstioObject, namespace, service string) int {
+	count := 0
+	for _, protocol := range []string{"http", "tcp", "tls"} {
+		routes, _ := vs.GetSpec()[protocol].([]interface{})
+		for _, r := range routes {
+			route, _ := r.(map[string]interface{})
+			destinations, _ := route["route"].([]interface{})
+			for _, d := range destinations {
+				destination, _ := d.(map[string]interface{})
+				target, _ := destination["destination"].(map[string]interface{})
+				if host, _ := target["host"].(string); kubernetes.FilterByHost(host, service, namespace) {
+					count++
+					break
+				}
+			}
+		}
+	}
+	return count
+}
+
+// subsetLabels returns the labels of the subset of the service defined by the DestinationRules.
+// A destination without subset routes to all the workloads of the service.
+func subsetLabels(destinationRules []kubernetes.IstioObject, subset, namespace, service string) (map[string]string, bool) {
+	if subset == "" {
+		return map[string]string{}, true
+	}
+	for _, dr := range destinationRules {
+		if drHost, _ := dr.GetSpec()["host"].(string); !kubernetes.FilterByHost(drHost, service, namespace) {
+			continue
+		}
+		subsets, _ := dr.GetSpec()["subsets"].([]interface{})
+		for _, s := range subsets {
+			ss, _ := s.(map[string]interface{})
+			if name, _ := ss["name"].(string); name != subset {
+				continue
+			}
+			ls := map[string]string{}
+			if subsetLabels, ok := ss["labels"].(map[string]interface{}); ok {
+				for k, v := range subsetLabels {
+					ls[k] = fmt.Sprintf("%v", v)
+				}
+			}
+			return ls, true
+		}
+	}
+	return nil, false
+}
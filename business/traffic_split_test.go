@@ -0,0 +1,133 @@
+package business
+
+import (
+	"testing"
+
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/tests/data"
+)
+
+func fakeSplitWorkloads() models.Workloads {
+	return models.Workloads{
+		&models.Workload{WorkloadListItem: models.WorkloadListItem{Name: "reviews-v1", Labels: map[string]string{"app": "reviews", "version": "v1"}}},
+		&models.Workload{WorkloadListItem: models.WorkloadListItem{Name: "reviews-v2", Labels: map[string]string{"app": "reviews", "version": "v2"}}},
+	}
+}
+
+func fakeWorkloadRates(rates map[string]float64) model.Vector {
+	vector := model.Vector{}
+	for workload, rate := range rates {
+		vector = append(vector, &model.Sample{Metric: model.Metric{"destination_workload": model.LabelValue(workload)}, Value: model.SampleValue(rate)})
+	}
+	return vector
+}
+
+func fakeSplitVirtualService() kubernetes.IstioObject {
+	return data.AddRoutesToVirtualService("http", data.CreateRoute("reviews", "v2", 10),
+		data.AddRoutesToVirtualService("http", data.CreateRoute("reviews", "v1", 90),
+			data.CreateEmptyVirtualService("reviews", "bookinfo", []string{"reviews"})))
+}
+
+func TestTrafficSplitsMatchingConfiguration(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	vs := []kubernetes.IstioObject{fakeSplitVirtualService()}
+	dr := []kubernetes.IstioObject{data.CreateTestDestinationRule("bookinfo", "reviews", "reviews")}
+	rates := fakeWorkloadRates(map[string]float64{"reviews-v1": 9.2, "reviews-v2": 0.8})
+
+	splits := buildTrafficSplits("bookinfo", "reviews", vs, dr, fakeSplitWorkloads(), rates, model.Vector{})
+
+	assert.Len(splits.Splits, 1)
+	split := splits.Splits[0]
+	assert.Equal("reviews", split.VirtualService)
+	assert.Equal("spec/http[0]", split.Path)
+	assert.Equal("http", split.Protocol)
+	assert.InDelta(10.0, split.ObservedRate, 0.001)
+	assert.True(split.Comparable)
+	assert.False(split.Divergent)
+
+	assert.Len(split.Destinations, 2)
+	assert.Equal("v1", split.Destinations[0].Subset)
+	assert.Equal(uint32(90), split.Destinations[0].Weight)
+	assert.InDelta(9.2, *split.Destinations[0].ObservedRate, 0.001)
+	assert.InDelta(92.0, *split.Destinations[0].ObservedWeight, 0.001)
+	assert.Equal("v2", split.Destinations[1].Subset)
+	assert.Equal(uint32(10), split.Destinations[1].Weight)
+	assert.InDelta(8.0, *split.Destinations[1].ObservedWeight, 0.001)
+}
+
+func TestTrafficSplitsDivergent(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	vs := []kubernetes.IstioObject{fakeSplitVirtualService()}
+	dr := []kubernetes.IstioObject{data.CreateTestDestinationRule("bookinfo", "reviews", "reviews")}
+	// Configured 90/10, observed 50/50
+	rates := fakeWorkloadRates(map[string]float64{"reviews-v1": 5, "reviews-v2": 5})
+
+	splits := buildTrafficSplits("bookinfo", "reviews", vs, dr, fakeSplitWorkloads(), rates, model.Vector{})
+
+	assert.Len(splits.Splits, 1)
+	assert.True(splits.Splits[0].Divergent)
+	assert.InDelta(50.0, *splits.Splits[0].Destinations[0].ObservedWeight, 0.001)
+	assert.InDelta(50.0, *splits.Splits[0].Destinations[1].ObservedWeight, 0.001)
+}
+
+func TestTrafficSplitsWithoutTraffic(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	vs := []kubernetes.IstioObject{fakeSplitVirtualService()}
+	dr := []kubernetes.IstioObject{data.CreateTestDestinationRule("bookinfo", "reviews", "reviews")}
+
+	// No traffic at all: nothing to compare
+	splits := buildTrafficSplits("bookinfo", "reviews", vs, dr, fakeSplitWorkloads(), model.Vector{}, model.Vector{})
+	split := splits.Splits[0]
+	assert.Zero(split.ObservedRate)
+	assert.False(split.Divergent)
+	assert.Zero(*split.Destinations[0].ObservedRate)
+	assert.Nil(split.Destinations[0].ObservedWeight)
+
+	// All the traffic to v1: the v2 subset receives none of its 10%, within the tolerance
+	rates := fakeWorkloadRates(map[string]float64{"reviews-v1": 4})
+	splits = buildTrafficSplits("bookinfo", "reviews", vs, dr, fakeSplitWorkloads(), rates, model.Vector{})
+	split = splits.Splits[0]
+	assert.False(split.Divergent)
+	assert.Zero(*split.Destinations[1].ObservedRate)
+	assert.Zero(*split.Destinations[1].ObservedWeight)
+	assert.InDelta(100.0, *split.Destinations[0].ObservedWeight, 0.001)
+}
+
+func TestTrafficSplitsNotComparable(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	// Another route to the service: the traffic can't be attributed to the weighted route
+	vs := fakeSplitVirtualService()
+	vs.GetSpec()["http"] = append([]interface{}{
+		map[string]interface{}{
+			"match": []interface{}{map[string]interface{}{"headers": map[string]interface{}{"end-user": map[string]interface{}{"exact": "jason"}}}},
+			"route": []interface{}{data.CreateRoute("reviews", "v2", -1)},
+		},
+	}, vs.GetSpec()["http"].([]interface{})...)
+	dr := []kubernetes.IstioObject{data.CreateTestDestinationRule("bookinfo", "reviews", "reviews")}
+	rates := fakeWorkloadRates(map[string]float64{"reviews-v1": 5, "reviews-v2": 5})
+
+	splits := buildTrafficSplits("bookinfo", "reviews", []kubernetes.IstioObject{vs}, dr, fakeSplitWorkloads(), rates, model.Vector{})
+	assert.Len(splits.Splits, 1)
+	assert.Equal("spec/http[1]", splits.Splits[0].Path)
+	assert.False(splits.Splits[0].Comparable)
+	assert.False(splits.Splits[0].Divergent)
+
+	// Subset not defined by any DestinationRule
+	splits = buildTrafficSplits("bookinfo", "reviews", []kubernetes.IstioObject{fakeSplitVirtualService()}, nil, fakeSplitWorkloads(), rates, model.Vector{})
+	assert.False(splits.Splits[0].Comparable)
+	assert.False(splits.Splits[0].Divergent)
+	assert.Nil(splits.Splits[0].Destinations[0].ObservedRate)
+}
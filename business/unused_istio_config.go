@@ -0,0 +1,244 @@
+package business
+
+import (
+	"strings"
+	"sync"
+
+	core_v1 "k8s.io/api/core/v1"
+
+	"github.com/kiali/kiali/business/checkers"
+	"github.com/kiali/kiali/business/checkers/common"
+	"github.com/kiali/kiali/business/checkers/destinationrules"
+	"github.com/kiali/kiali/business/checkers/virtual_services"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/prometheus/internalmetrics"
+)
+
+// GetUnusedIstioConfig finds the Istio objects of the namespace that have no effect, so they can be cleaned up.
+// Hosts of other namespaces can't be resolved, the objects referring to them are considered in use.
+func (in *IstioValidationsService) GetUnusedIstioConfig(namespace string) (models.UnusedIstioConfig, error) {
+	var err error
+	promtimer := internalmetrics.GetGoFunctionMetric("business", "IstioValidationsService", "GetUnusedIstioConfig")
+	defer promtimer.ObserveNow(&err)
+
+	// Check if user has access to the namespace (RBAC) in cache scenarios and/or
+	// if namespace is accessible from Kiali (Deployment.AccessibleNamespaces)
+	if _, err = in.businessLayer.Namespace.GetNamespace(namespace); err != nil {
+		return models.UnusedIstioConfig{}, err
+	}
+
+	analysis := unusedConfigAnalysis{namespace: namespace}
+
+	wg := sync.WaitGroup{}
+	errChan := make(chan error, 1)
+
+	wg.Add(7)
+	go in.fetchDetails(&analysis.istioDetails, namespace, errChan, &wg)
+	go in.fetchNamespaces(&analysis.namespaces, errChan, &wg)
+	go in.fetchServices(&analysis.services, namespace, errChan, &wg)
+	go in.fetchWorkloads(&analysis.workloads, namespace, errChan, &wg)
+	go in.fetchGatewaysPerNamespace(&analysis.gatewaysPerNamespace, errChan, &wg)
+	go in.fetchNonLocalmTLSConfigs(&analysis.mtlsDetails, namespace, errChan, &wg)
+	go in.fetchAuthorizationDetails(&analysis.rbacDetails, namespace, errChan, &wg)
+	wg.Wait()
+
+	close(errChan)
+	for e := range errChan {
+		if e != nil { // Check that default value wasn't returned
+			err = e
+			return models.UnusedIstioConfig{}, err
+		}
+	}
+
+	return analysis.report(), nil
+}
+
+// unusedConfigAnalysis resolves the Istio objects of a namespace with the same logic used by the validations
+type unusedConfigAnalysis struct {
+	namespace            string
+	namespaces           models.Namespaces
+	istioDetails         kubernetes.IstioDetails
+	services             []core_v1.Service
+	workloads            models.WorkloadList
+	gatewaysPerNamespace [][]kubernetes.IstioObject
+	mtlsDetails          kubernetes.MTLSDetails
+	rbacDetails          kubernetes.RBACDetails
+}
+
+func (a unusedConfigAnalysis) report() models.UnusedIstioConfig {
+	unused := map[string][]models.IstioValidationKey{}
+	add := func(reason, objectType string, object kubernetes.IstioObject) {
+		unused[reason] = append(unused[reason], models.BuildKey(objectType, object.GetObjectMeta().Name, object.GetObjectMeta().Namespace))
+	}
+
+	serviceEntryHosts := kubernetes.ServiceEntryHostnames(a.istioDetails.ServiceEntries)
+
+	for _, dr := range a.istioDetails.DestinationRules {
+		checks, _ := destinationrules.NoDestinationChecker{
+			Namespace:       a.namespace,
+			Namespaces:      a.namespaces,
+			WorkloadList:    a.workloads,
+			DestinationRule: dr,
+			ServiceEntries:  serviceEntryHosts,
+			Services:        a.services,
+		}.Check()
+		if hasCheck(checks, "destinationrules.nodest.matchingregistry") {
+			add(models.UnusedNoMatchingHost, checkers.DestinationRuleCheckerType, dr)
+		}
+	}
+
+	gatewayNames := kubernetes.GatewayNames(a.gatewaysPerNamespace)
+	for _, vs := range a.istioDetails.VirtualServices {
+		gateways, appliesToMesh := virtualServiceGateways(vs)
+		if a.isBoundToGateway(vs, gateways, gatewayNames) {
+			continue
+		}
+		if appliesToMesh && a.matchesMeshTraffic(vs, serviceEntryHosts) {
+			continue
+		}
+		add(models.UnusedNoGatewayNoMesh, checkers.VirtualCheckerType, vs)
+	}
+
+	for _, ap := range a.rbacDetails.AuthorizationPolicies {
+		if a.selectsNoWorkload(checkers.AuthorizationPolicyCheckerType, ap) {
+			add(models.UnusedNoMatchingWorkload, checkers.AuthorizationPolicyCheckerType, ap)
+		}
+	}
+	for _, pa := range a.mtlsDetails.PeerAuthentications {
+		if a.selectsNoWorkload(checkers.PeerAuthenticationCheckerType, pa) {
+			add(models.UnusedNoMatchingWorkload, checkers.PeerAuthenticationCheckerType, pa)
+		}
+	}
+
+	referencedHosts := a.referencedHosts()
+	for _, se := range a.istioDetails.ServiceEntries {
+		if !isServiceEntryReferenced(se, referencedHosts) {
+			add(models.UnusedNotReferenced, checkers.ServiceEntryCheckerType, se)
+		}
+	}
+
+	return models.NewUnusedIstioConfig(a.namespace, unused)
+}
+
+// isBoundToGateway returns true when at least one of the gateways of the VirtualService exists
+func (a unusedConfigAnalysis) isBoundToGateway(vs kubernetes.IstioObject, gateways []string, gatewayNames map[string]struct{}) bool {
+	if len(gateways) == 0 {
+		return false
+	}
+	checks := make([]*models.IstioCheck, 0)
+	virtual_services.NoGatewayChecker{VirtualService: vs, GatewayNames: gatewayNames}.
+		ValidateVirtualServiceGateways(vs.GetSpec(), vs.GetObjectMeta().Namespace, vs.GetObjectMeta().ClusterName, &checks)
+
+	missing := 0
+	for _, check := range checks {
+		if check.Message == models.CheckMessage("virtualservices.nogateway") {
+			missing++
+		}
+	}
+	return missing < len(gateways)
+}
+
+// matchesMeshTraffic returns true when a host of the VirtualService is a service, a workload app or a ServiceEntry host
+func (a unusedConfigAnalysis) matchesMeshTraffic(vs kubernetes.IstioObject, serviceEntryHosts map[string][]string) bool {
+	hosts, _ := vs.GetSpec()["hosts"].([]interface{})
+	for _, h := range hosts {
+		host, ok := h.(string)
+		if !ok {
+			continue
+		}
+		if strings.HasPrefix(host, "*") {
+			return true
+		}
+		fqdn := kubernetes.GetHost(host, a.namespace, vs.GetObjectMeta().ClusterName, a.namespaces.GetNames())
+		if fqdn.Namespace != a.namespace && fqdn.Namespace != "" && fqdn.CompleteInput {
+			return true
+		}
+		localSvc, localNs := kubernetes.ParseTwoPartHost(fqdn)
+		if localNs == a.namespace && (kubernetes.HasMatchingServices(localSvc, a.services) || kubernetes.HasMatchingWorkloads(localSvc, a.workloads.GetLabels())) {
+			return true
+		}
+		if kubernetes.HasMatchingServiceEntries(host, serviceEntryHosts) {
+			return true
+		}
+	}
+	return false
+}
+
+func (a unusedConfigAnalysis) selectsNoWorkload(objectType string, object kubernetes.IstioObject) bool {
+	checks, _ := common.SelectorNoWorkloadFoundChecker(objectType, object, a.workloads).Check()
+	return len(checks) > 0
+}
+
+// referencedHosts returns the hosts of the VirtualServices (including their route destinations) and DestinationRules
+func (a unusedConfigAnalysis) referencedHosts() []string {
+	hosts := make([]string, 0)
+	for _, vs := range a.istioDetails.VirtualServices {
+		vsHosts, _ := vs.GetSpec()["hosts"].([]interface{})
+		for _, h := range vsHosts {
+			if host, ok := h.(string); ok {
+				hosts = append(hosts, host)
+			}
+		}
+		for _, protocol := range []string{"http", "tcp", "tls"} {
+			routes, _ := vs.GetSpec()[protocol].([]interface{})
+			for _, r := range routes {
+				route, _ := r.(map[string]interface{})
+				destinations, _ := route["route"].([]interface{})
+				for _, d := range destinations {
+					destinationWeight, _ := d.(map[string]interface{})
+					destination, _ := destinationWeight["destination"].(map[string]interface{})
+					if host, ok := destination["host"].(string); ok {
+						hosts = append(hosts, host)
+					}
+				}
+			}
+		}
+	}
+	for _, dr := range a.istioDetails.DestinationRules {
+		if host, ok := dr.GetSpec()["host"].(string); ok {
+			hosts = append(hosts, host)
+		}
+	}
+	return hosts
+}
+
+func isServiceEntryReferenced(se kubernetes.IstioObject, referencedHosts []string) bool {
+	seHosts := kubernetes.ServiceEntryHostnames([]kubernetes.IstioObject{se})
+	for _, host := range referencedHosts {
+		if kubernetes.HasMatchingServiceEntries(host, seHosts) {
+			return true
+		}
+	}
+	return false
+}
+
+// virtualServiceGateways returns the gateways of the VirtualService, without the reserved mesh gateway,
+// and whether the VirtualService applies to the sidecars of the mesh
+func virtualServiceGateways(vs kubernetes.IstioObject) ([]string, bool) {
+	spec, found := vs.GetSpec()["gateways"].([]interface{})
+	if !found || len(spec) == 0 {
+		return []string{}, true
+	}
+	gateways := make([]string, 0, len(spec))
+	appliesToMesh := false
+	for _, g := range spec {
+		if gateway, ok := g.(string); ok {
+			if gateway == "mesh" {
+				appliesToMesh = true
+			} else {
+				gateways = append(gateways, gateway)
+			}
+		}
+	}
+	return gateways, appliesToMesh
+}
+
+func hasCheck(checks []*models.IstioCheck, checkId string) bool {
+	for _, check := range checks {
+		if check.Message == models.CheckMessage(checkId) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,88 @@
+package business
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	core_v1 "k8s.io/api/core/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/tests/data"
+)
+
+func TestUnusedIstioConfigNoCleanup(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	report := unusedConfigAnalysisFor(t, "used_config.yaml").report()
+
+	assert.Equal("bookinfo", report.Namespace)
+	assert.Empty(report.Groups)
+}
+
+func TestUnusedIstioConfig(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	report := unusedConfigAnalysisFor(t, "used_config.yaml", "unused_config.yaml").report()
+
+	assert.Len(report.Groups, 4)
+
+	assert.Equal(models.UnusedNoMatchingHost, report.Groups[0].Reason)
+	assert.NotEmpty(report.Groups[0].Description)
+	assert.Equal([]models.IstioValidationKey{models.BuildKey("destinationrule", "ghost", "bookinfo")}, report.Groups[0].Objects)
+
+	assert.Equal(models.UnusedNoGatewayNoMesh, report.Groups[1].Reason)
+	assert.ElementsMatch([]models.IstioValidationKey{
+		models.BuildKey("virtualservice", "ingress-only", "bookinfo"),
+		models.BuildKey("virtualservice", "ghost", "bookinfo"),
+	}, report.Groups[1].Objects)
+
+	assert.Equal(models.UnusedNoMatchingWorkload, report.Groups[2].Reason)
+	assert.ElementsMatch([]models.IstioValidationKey{
+		models.BuildKey("authorizationpolicy", "ghost-viewer", "bookinfo"),
+		models.BuildKey("peerauthentication", "ghost-permissive", "bookinfo"),
+	}, report.Groups[2].Objects)
+
+	assert.Equal(models.UnusedNotReferenced, report.Groups[3].Reason)
+	assert.Equal([]models.IstioValidationKey{models.BuildKey("serviceentry", "legacy-api", "bookinfo")}, report.Groups[3].Objects)
+}
+
+func unusedConfigAnalysisFor(t *testing.T, files ...string) unusedConfigAnalysis {
+	resources := map[string][]kubernetes.IstioObject{}
+	for _, file := range files {
+		loader := &data.YamlFixtureLoader{Filename: fmt.Sprintf("../tests/data/validations/unused/%s", file)}
+		if err := loader.Load(); err != nil {
+			t.Error("Error loading test data.")
+		}
+		for _, kind := range []string{"Gateway", "VirtualService", "DestinationRule", "ServiceEntry", "AuthorizationPolicy", "PeerAuthentication"} {
+			resources[kind] = append(resources[kind], loader.GetResources(kind)...)
+		}
+	}
+
+	return unusedConfigAnalysis{
+		namespace:  "bookinfo",
+		namespaces: models.Namespaces{{Name: "bookinfo"}, {Name: "istio-system"}},
+		istioDetails: kubernetes.IstioDetails{
+			VirtualServices:  resources["VirtualService"],
+			DestinationRules: resources["DestinationRule"],
+			ServiceEntries:   resources["ServiceEntry"],
+			Gateways:         resources["Gateway"],
+		},
+		services: []core_v1.Service{
+			{ObjectMeta: meta_v1.ObjectMeta{Name: "productpage", Namespace: "bookinfo"}, Spec: core_v1.ServiceSpec{Selector: map[string]string{"app": "productpage"}}},
+			{ObjectMeta: meta_v1.ObjectMeta{Name: "reviews", Namespace: "bookinfo"}, Spec: core_v1.ServiceSpec{Selector: map[string]string{"app": "reviews"}}},
+		},
+		workloads: data.CreateWorkloadList("bookinfo",
+			data.CreateWorkloadListItem("productpage-v1", map[string]string{"app": "productpage", "version": "v1"}),
+			data.CreateWorkloadListItem("reviews-v1", map[string]string{"app": "reviews", "version": "v1"}),
+		),
+		gatewaysPerNamespace: [][]kubernetes.IstioObject{resources["Gateway"]},
+		mtlsDetails:          kubernetes.MTLSDetails{PeerAuthentications: resources["PeerAuthentication"]},
+		rbacDetails:          kubernetes.RBACDetails{AuthorizationPolicies: resources["AuthorizationPolicy"]},
+	}
+}
@@ -0,0 +1,139 @@
+package business
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/prometheus/internalmetrics"
+)
+
+// CompareWorkloads returns two workloads side by side: their inbound request rate, success rate and latencies over
+// the same rate interval, ending at queryTime, and the differences of their configuration.
+// A workload without traffic in the interval has no metrics, it is still compared on its configuration.
+func (in *WorkloadService) CompareWorkloads(leftNamespace, leftWorkload, rightNamespace, rightWorkload, rateInterval string, queryTime time.Time) (*models.WorkloadComparison, error) {
+	var err error
+	promtimer := internalmetrics.GetGoFunctionMetric("business", "WorkloadService", "CompareWorkloads")
+	defer promtimer.ObserveNow(&err)
+
+	left, err := fetchWorkload(in.businessLayer, leftNamespace, leftWorkload, "")
+	if err != nil {
+		return nil, err
+	}
+	right, err := fetchWorkload(in.businessLayer, rightNamespace, rightWorkload, "")
+	if err != nil {
+		return nil, err
+	}
+
+	comparison := &models.WorkloadComparison{
+		RateInterval: rateInterval,
+		QueryTime:    queryTime.Unix(),
+		Left:         comparisonSide(leftNamespace, left),
+		Right:        comparisonSide(rightNamespace, right),
+		ConfigDiffs:  workloadConfigDiffs(left, right),
+	}
+	if comparison.Left.Metrics, err = in.getComparedMetrics(leftNamespace, leftWorkload, rateInterval, queryTime); err != nil {
+		return nil, err
+	}
+	if comparison.Right.Metrics, err = in.getComparedMetrics(rightNamespace, rightWorkload, rateInterval, queryTime); err != nil {
+		return nil, err
+	}
+	comparison.Delta = models.NewVariantMetricsDelta(comparison.Left.Metrics, comparison.Right.Metrics)
+	return comparison, nil
+}
+
+// getComparedMetrics returns the inbound metrics of a workload, nil when it didn't receive traffic
+func (in *WorkloadService) getComparedMetrics(namespace, workload, rateInterval string, queryTime time.Time) (*models.VariantMetrics, error) {
+	metrics, err := in.getVariantMetrics(namespace, workload, rateInterval, queryTime)
+	if err != nil {
+		return nil, err
+	}
+	if metrics.RequestRate == 0 && len(metrics.Latencies) == 0 {
+		return nil, nil
+	}
+	return metrics, nil
+}
+
+func comparisonSide(namespace string, workload *models.Workload) models.WorkloadComparisonSide {
+	return models.WorkloadComparisonSide{
+		Namespace:         namespace,
+		Workload:          workload.Name,
+		Type:              workload.Type,
+		DesiredReplicas:   workload.DesiredReplicas,
+		AvailableReplicas: workload.AvailableReplicas,
+	}
+}
+
+// workloadConfigDiffs returns the configuration fields with different values on each workload: the controller type,
+// the replicas, the sidecar, the labels of the pod template and the images of the containers
+func workloadConfigDiffs(left, right *models.Workload) []models.ConfigDiff {
+	diffs := []models.ConfigDiff{}
+	add := func(field, leftValue, rightValue string) {
+		if leftValue != rightValue {
+			diffs = append(diffs, models.ConfigDiff{Field: field, Left: leftValue, Right: rightValue})
+		}
+	}
+
+	add("type", left.Type, right.Type)
+	add("desiredReplicas", strconv.Itoa(int(left.DesiredReplicas)), strconv.Itoa(int(right.DesiredReplicas)))
+	add("istioSidecar", strconv.FormatBool(left.HasIstioSidecar()), strconv.FormatBool(right.HasIstioSidecar()))
+	add("istioInjectionAnnotation", formatOptionalBool(left.IstioInjectionAnnotation), formatOptionalBool(right.IstioInjectionAnnotation))
+	for _, key := range unionKeys(left.Labels, right.Labels) {
+		add(fmt.Sprintf("labels[%s]", key), left.Labels[key], right.Labels[key])
+	}
+	leftImages, rightImages := containerImages(left.Pods), containerImages(right.Pods)
+	for _, name := range unionKeys(leftImages, rightImages) {
+		add(fmt.Sprintf("containers[%s].image", name), leftImages[name], rightImages[name])
+	}
+	return diffs
+}
+
+func formatOptionalBool(value *bool) string {
+	if value == nil {
+		return ""
+	}
+	return strconv.FormatBool(*value)
+}
+
+// containerImages returns the images of the containers of the pods by container name. The pods of a workload
+// may run different images during an update, they are all listed.
+func containerImages(pods models.Pods) map[string]string {
+	images := map[string]map[string]bool{}
+	for _, pod := range pods {
+		for _, containers := range [][]*models.ContainerInfo{pod.Containers, pod.IstioContainers} {
+			for _, c := range containers {
+				if images[c.Name] == nil {
+					images[c.Name] = map[string]bool{}
+				}
+				images[c.Name][c.Image] = true
+			}
+		}
+	}
+	result := make(map[string]string, len(images))
+	for name, set := range images {
+		list := make([]string, 0, len(set))
+		for image := range set {
+			list = append(list, image)
+		}
+		sort.Strings(list)
+		result[name] = strings.Join(list, ",")
+	}
+	return result
+}
+
+func unionKeys(left, right map[string]string) []string {
+	keys := make([]string, 0, len(left)+len(right))
+	for key := range left {
+		keys = append(keys, key)
+	}
+	for key := range right {
+		if _, found := left[key]; !found {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
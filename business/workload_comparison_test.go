@@ -0,0 +1,134 @@
+package business
+
+import (
+	"testing"
+	"time"
+
+	osproject_v1 "github.com/openshift/api/project/v1"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	apps_v1 "k8s.io/api/apps/v1"
+	core_v1 "k8s.io/api/core/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes/kubetest"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/prometheus/prometheustest"
+)
+
+func TestCompareWorkloads(t *testing.T) {
+	assert := assert.New(t)
+
+	k8s, prom := setupWorkloadComparisonMocks()
+	queryTime := time.Date(2017, 01, 15, 0, 0, 0, 0, time.UTC)
+	mockComparedMetrics(prom, "reviews-v1", queryTime, model.Vector{
+		dependencySample("reviews-v1", "reviews", "200", 18),
+		dependencySample("reviews-v1", "reviews", "500", 2),
+	}, map[string]model.Vector{"0.99": {&model.Sample{Value: 90}}, "avg": {&model.Sample{Value: 30}}})
+	mockComparedMetrics(prom, "reviews-v2", queryTime, model.Vector{
+		dependencySample("reviews-v2", "reviews", "200", 5),
+	}, map[string]model.Vector{"0.99": {&model.Sample{Value: 120}}, "avg": {&model.Sample{Value: 45}}})
+
+	svc := WorkloadService{k8s: k8s, prom: prom, businessLayer: NewWithBackends(k8s, prom, nil)}
+	comparison, err := svc.CompareWorkloads("bookinfo", "reviews-v1", "bookinfo", "reviews-v2", "1m", queryTime)
+	assert.NoError(err)
+
+	assert.Equal("1m", comparison.RateInterval)
+	assert.Equal(queryTime.Unix(), comparison.QueryTime)
+
+	assert.Equal("reviews-v1", comparison.Left.Workload)
+	assert.Equal("Deployment", comparison.Left.Type)
+	assert.Equal(int32(2), comparison.Left.DesiredReplicas)
+	assert.Equal(20.0, comparison.Left.Metrics.RequestRate)
+	assert.InDelta(0.9, *comparison.Left.Metrics.SuccessRate, 0.0001)
+	assert.Equal([]models.Stat{{Name: "0.99", Value: 90}, {Name: "avg", Value: 30}}, comparison.Left.Metrics.Latencies)
+
+	assert.Equal("reviews-v2", comparison.Right.Workload)
+	assert.Equal(5.0, comparison.Right.Metrics.RequestRate)
+	assert.Equal(1.0, *comparison.Right.Metrics.SuccessRate)
+
+	assert.Equal(-15.0, comparison.Delta.RequestRate)
+	assert.InDelta(0.1, *comparison.Delta.SuccessRate, 0.0001)
+	assert.Equal([]models.Stat{{Name: "0.99", Value: 30}, {Name: "avg", Value: 15}}, comparison.Delta.Latencies)
+
+	assert.Equal([]models.ConfigDiff{
+		{Field: "desiredReplicas", Left: "2", Right: "1"},
+		{Field: "labels[version]", Left: "v1", Right: "v2"},
+		{Field: "containers[reviews].image", Left: "docker.io/istio/examples-bookinfo-reviews-v1:1.16.2", Right: "docker.io/istio/examples-bookinfo-reviews-v2:1.16.2"},
+	}, comparison.ConfigDiffs)
+}
+
+func TestCompareWorkloadsWithoutTraffic(t *testing.T) {
+	assert := assert.New(t)
+
+	k8s, prom := setupWorkloadComparisonMocks()
+	queryTime := time.Date(2017, 01, 15, 0, 0, 0, 0, time.UTC)
+	mockComparedMetrics(prom, "reviews-v1", queryTime, model.Vector{
+		dependencySample("reviews-v1", "reviews", "200", 10),
+	}, map[string]model.Vector{"avg": {&model.Sample{Value: 30}}})
+	mockComparedMetrics(prom, "reviews-v2", queryTime, model.Vector{}, map[string]model.Vector{})
+
+	svc := WorkloadService{k8s: k8s, prom: prom, businessLayer: NewWithBackends(k8s, prom, nil)}
+	comparison, err := svc.CompareWorkloads("bookinfo", "reviews-v1", "bookinfo", "reviews-v2", "1m", queryTime)
+	assert.NoError(err)
+
+	// The side without traffic has no metrics, the configuration is still compared
+	assert.NotNil(comparison.Left.Metrics)
+	assert.Nil(comparison.Right.Metrics)
+	assert.Nil(comparison.Delta)
+	assert.Len(comparison.ConfigDiffs, 3)
+}
+
+func TestCompareWorkloadsNotFound(t *testing.T) {
+	assert := assert.New(t)
+
+	k8s, prom := setupWorkloadComparisonMocks()
+	svc := WorkloadService{k8s: k8s, prom: prom, businessLayer: NewWithBackends(k8s, prom, nil)}
+	_, err := svc.CompareWorkloads("bookinfo", "reviews-v1", "bookinfo", "reviews-v3", "1m", time.Now())
+	assert.Error(err)
+	prom.AssertNotCalled(t, "GetWorkloadRequestRates", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func setupWorkloadComparisonMocks() (*kubetest.K8SClientMock, *prometheustest.PromClientMock) {
+	config.Set(config.NewConfig())
+
+	k8s := new(kubetest.K8SClientMock)
+	prom := new(prometheustest.PromClientMock)
+	k8s.On("IsOpenShift").Return(true)
+	k8s.On("GetProject", "bookinfo").Return(&osproject_v1.Project{ObjectMeta: meta_v1.ObjectMeta{Name: "bookinfo"}}, nil)
+
+	v1, v2 := fakeSubsetDeployment("reviews-v1", "v1"), fakeSubsetDeployment("reviews-v2", "v2")
+	v1Replicas, v2Replicas := int32(2), int32(1)
+	v1.Spec.Replicas, v2.Spec.Replicas = &v1Replicas, &v2Replicas
+	k8s.On("GetDeployment", "bookinfo", "reviews-v1").Return(&v1, nil)
+	k8s.On("GetDeployment", "bookinfo", "reviews-v2").Return(&v2, nil)
+	// Registered before the empty workload mocks, to take precedence
+	k8s.On("GetReplicaSets", "bookinfo").Return([]apps_v1.ReplicaSet{
+		fakeSubsetReplicaSet("reviews-v1", "v1"),
+		fakeSubsetReplicaSet("reviews-v2", "v2"),
+	}, nil)
+	k8s.MockEmptyWorkload("bookinfo", mock.AnythingOfType("string"))
+	k8s.On("GetPods", "bookinfo", "").Return([]core_v1.Pod{
+		fakeComparedPod("reviews-v1-1", "v1"),
+		fakeComparedPod("reviews-v1-2", "v1"),
+		fakeComparedPod("reviews-v2-1", "v2"),
+	}, nil)
+	return k8s, prom
+}
+
+func fakeComparedPod(name, version string) core_v1.Pod {
+	pod := fakeSubsetPod(name, version)
+	pod.Spec.Containers = []core_v1.Container{
+		{Name: "reviews", Image: "docker.io/istio/examples-bookinfo-reviews-" + version + ":1.16.2"},
+		{Name: "istio-proxy", Image: "docker.io/istio/proxyv2:1.9.0"},
+	}
+	return pod
+}
+
+func mockComparedMetrics(prom *prometheustest.PromClientMock, workload string, queryTime time.Time, inbound model.Vector, latencies map[string]model.Vector) {
+	prom.MockWorkloadRequestRates("bookinfo", workload, inbound, model.Vector{})
+	labels := `{reporter="destination",destination_workload_namespace="bookinfo",destination_workload="` + workload + `"}`
+	prom.On("FetchHistogramValues", "istio_request_duration_milliseconds", labels, "", "1m", true, latencyQuantiles, queryTime).Return(latencies, nil)
+}
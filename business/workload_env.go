@@ -0,0 +1,326 @@
+package business
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	core_v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/prometheus/internalmetrics"
+)
+
+// metadata.labels['key'] and metadata.annotations['key'] field paths
+var mapFieldPath = regexp.MustCompile(`^metadata\.(labels|annotations)\['(.+)'\]$`)
+
+// GetWorkloadEnv returns the environment of the containers of a pod. The values taken from ConfigMaps and Secrets are
+// resolved with the permissions of the user, the ones taken from a Secret are masked but keep their source.
+// A missing referenced object or key doesn't fail the request, it is reported in the variables it affects.
+func (in *WorkloadService) GetWorkloadEnv(namespace, pod string) (*models.WorkloadEnv, error) {
+	var err error
+	promtimer := internalmetrics.GetGoFunctionMetric("business", "WorkloadService", "GetWorkloadEnv")
+	defer promtimer.ObserveNow(&err)
+
+	p, err := in.k8s.GetPod(namespace, pod)
+	if err != nil {
+		return nil, err
+	}
+
+	resolver := envResolver{
+		k8s:        in.k8s,
+		pod:        p,
+		configMaps: map[string]envSource{},
+		secrets:    map[string]envSource{},
+	}
+	workloadEnv := &models.WorkloadEnv{
+		Namespace:  namespace,
+		Pod:        pod,
+		Containers: make([]models.ContainerEnv, 0, len(p.Spec.Containers)),
+	}
+	for _, container := range p.Spec.Containers {
+		workloadEnv.Containers = append(workloadEnv.Containers, resolver.containerEnv(container))
+	}
+	return workloadEnv, nil
+}
+
+// envSource holds the data of a ConfigMap, or the keys of a Secret, fetched once per request
+type envSource struct {
+	data map[string]string
+	err  error
+}
+
+type envResolver struct {
+	k8s        kubernetes.ClientInterface
+	pod        *core_v1.Pod
+	configMaps map[string]envSource
+	secrets    map[string]envSource
+}
+
+func (r *envResolver) configMap(name string) envSource {
+	if source, ok := r.configMaps[name]; ok {
+		return source
+	}
+	source := envSource{}
+	cm, err := r.k8s.GetConfigMap(r.pod.Namespace, name)
+	if err != nil {
+		source.err = err
+	} else {
+		source.data = cm.Data
+	}
+	r.configMaps[name] = source
+	return source
+}
+
+// secret only keeps the keys of the Secret, its values are never read
+func (r *envResolver) secret(name string) envSource {
+	if source, ok := r.secrets[name]; ok {
+		return source
+	}
+	source := envSource{}
+	secret, err := r.k8s.GetSecret(r.pod.Namespace, name)
+	if err != nil {
+		source.err = err
+	} else {
+		source.data = make(map[string]string, len(secret.Data)+len(secret.StringData))
+		for key := range secret.Data {
+			source.data[key] = ""
+		}
+		for key := range secret.StringData {
+			source.data[key] = ""
+		}
+	}
+	r.secrets[name] = source
+	return source
+}
+
+func (r *envResolver) containerEnv(container core_v1.Container) models.ContainerEnv {
+	containerEnv := models.ContainerEnv{Name: container.Name, Env: []models.EnvVar{}}
+
+	// Later definitions of a variable override the earlier ones, in place
+	index := map[string]int{}
+	set := func(envVar models.EnvVar) {
+		if i, found := index[envVar.Name]; found {
+			containerEnv.Env[i] = envVar
+			return
+		}
+		index[envVar.Name] = len(containerEnv.Env)
+		containerEnv.Env = append(containerEnv.Env, envVar)
+	}
+
+	for _, from := range container.EnvFrom {
+		var source envSource
+		var name, sourceType string
+		var optional *bool
+		if from.ConfigMapRef != nil {
+			name, sourceType, optional = from.ConfigMapRef.Name, models.EnvSourceConfigMap, from.ConfigMapRef.Optional
+			source = r.configMap(name)
+		} else if from.SecretRef != nil {
+			name, sourceType, optional = from.SecretRef.Name, models.EnvSourceSecret, from.SecretRef.Optional
+			source = r.secret(name)
+		} else {
+			continue
+		}
+		if source.err != nil {
+			if !isOptionalMissing(source.err, optional) {
+				containerEnv.Errors = append(containerEnv.Errors, envSourceError(sourceType, name, source.err))
+			}
+			continue
+		}
+		keys := make([]string, 0, len(source.data))
+		for key := range source.data {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			envVar := models.EnvVar{Name: from.Prefix + key, Source: sourceType, SourceName: name, SourceKey: key}
+			if sourceType == models.EnvSourceSecret {
+				envVar.Masked = true
+			} else {
+				envVar.Value = source.data[key]
+			}
+			set(envVar)
+		}
+	}
+
+	for _, env := range container.Env {
+		if envVar, ok := r.resolve(container, env); ok {
+			set(envVar)
+		}
+	}
+
+	return containerEnv
+}
+
+// resolve returns the variable of an env entry, false when Kubernetes doesn't set it (an optional missing reference)
+func (r *envResolver) resolve(container core_v1.Container, env core_v1.EnvVar) (models.EnvVar, bool) {
+	envVar := models.EnvVar{Name: env.Name, Source: models.EnvSourceValue, Value: env.Value}
+	if env.ValueFrom == nil {
+		return envVar, true
+	}
+	envVar.Value = ""
+
+	switch {
+	case env.ValueFrom.ConfigMapKeyRef != nil:
+		ref := env.ValueFrom.ConfigMapKeyRef
+		envVar.Source, envVar.SourceName, envVar.SourceKey = models.EnvSourceConfigMap, ref.Name, ref.Key
+		value, err := lookupKey(r.configMap(ref.Name), ref.Key)
+		if err != nil {
+			if isOptionalMissing(err, ref.Optional) {
+				return envVar, false
+			}
+			envVar.Error = envSourceError(models.EnvSourceConfigMap, ref.Name, err)
+		}
+		envVar.Value = value
+	case env.ValueFrom.SecretKeyRef != nil:
+		ref := env.ValueFrom.SecretKeyRef
+		envVar.Source, envVar.SourceName, envVar.SourceKey = models.EnvSourceSecret, ref.Name, ref.Key
+		if _, err := lookupKey(r.secret(ref.Name), ref.Key); err != nil {
+			if isOptionalMissing(err, ref.Optional) {
+				return envVar, false
+			}
+			envVar.Error = envSourceError(models.EnvSourceSecret, ref.Name, err)
+		} else {
+			envVar.Masked = true
+		}
+	case env.ValueFrom.FieldRef != nil:
+		envVar.Source, envVar.SourceName = models.EnvSourceField, env.ValueFrom.FieldRef.FieldPath
+		if value, ok := podFieldValue(r.pod, env.ValueFrom.FieldRef.FieldPath); ok {
+			envVar.Value = value
+		} else {
+			envVar.Error = fmt.Sprintf("Field [%s] is not supported", env.ValueFrom.FieldRef.FieldPath)
+		}
+	case env.ValueFrom.ResourceFieldRef != nil:
+		ref := env.ValueFrom.ResourceFieldRef
+		envVar.Source, envVar.SourceName = models.EnvSourceResource, ref.Resource
+		value, err := r.resourceValue(container, ref)
+		if err != nil {
+			envVar.Error = err.Error()
+		}
+		envVar.Value = value
+	}
+	return envVar, true
+}
+
+// missingKeyError is returned when the referenced ConfigMap or Secret exists without the key
+type missingKeyError struct {
+	key string
+}
+
+func (e missingKeyError) Error() string {
+	return fmt.Sprintf("key [%s] not found", e.key)
+}
+
+func lookupKey(source envSource, key string) (string, error) {
+	if source.err != nil {
+		return "", source.err
+	}
+	value, found := source.data[key]
+	if !found {
+		return "", missingKeyError{key: key}
+	}
+	return value, nil
+}
+
+// isOptionalMissing checks whether the reference is optional and the object, or the key, doesn't exist
+func isOptionalMissing(err error, optional *bool) bool {
+	if optional == nil || !*optional {
+		return false
+	}
+	_, missingKey := err.(missingKeyError)
+	return missingKey || errors.IsNotFound(err)
+}
+
+func envSourceError(sourceType, name string, err error) string {
+	kind := kubernetes.ConfigMapType
+	if sourceType == models.EnvSourceSecret {
+		kind = "Secret"
+	}
+	if errors.IsNotFound(err) {
+		return fmt.Sprintf("%s [%s] not found", kind, name)
+	}
+	return fmt.Sprintf("%s [%s]: %s", kind, name, err.Error())
+}
+
+// podFieldValue returns the value of the pod fields supported by the Downward API for env variables
+func podFieldValue(pod *core_v1.Pod, fieldPath string) (string, bool) {
+	if match := mapFieldPath.FindStringSubmatch(fieldPath); match != nil {
+		if match[1] == "labels" {
+			return pod.Labels[match[2]], true
+		}
+		return pod.Annotations[match[2]], true
+	}
+	switch fieldPath {
+	case "metadata.name":
+		return pod.Name, true
+	case "metadata.namespace":
+		return pod.Namespace, true
+	case "metadata.uid":
+		return string(pod.UID), true
+	case "spec.nodeName":
+		return pod.Spec.NodeName, true
+	case "spec.serviceAccountName":
+		return pod.Spec.ServiceAccountName, true
+	case "status.hostIP":
+		return pod.Status.HostIP, true
+	case "status.podIP":
+		return pod.Status.PodIP, true
+	case "status.podIPs":
+		ips := make([]string, 0, len(pod.Status.PodIPs))
+		for _, ip := range pod.Status.PodIPs {
+			ips = append(ips, ip.IP)
+		}
+		return strings.Join(ips, ","), true
+	}
+	return "", false
+}
+
+// resourceValue returns the value of a limit or a request of a container, rounded up to the divisor as Kubernetes does
+func (r *envResolver) resourceValue(container core_v1.Container, ref *core_v1.ResourceFieldSelector) (string, error) {
+	if ref.ContainerName != "" && ref.ContainerName != container.Name {
+		found := false
+		for _, c := range r.pod.Spec.Containers {
+			if c.Name == ref.ContainerName {
+				container, found = c, true
+				break
+			}
+		}
+		if !found {
+			return "", fmt.Errorf("Container [%s] not found", ref.ContainerName)
+		}
+	}
+
+	parts := strings.SplitN(ref.Resource, ".", 2)
+	if len(parts) != 2 {
+		return "", fmt.Errorf("Resource [%s] is not supported", ref.Resource)
+	}
+	var resources core_v1.ResourceList
+	switch parts[0] {
+	case "limits":
+		resources = container.Resources.Limits
+	case "requests":
+		resources = container.Resources.Requests
+	default:
+		return "", fmt.Errorf("Resource [%s] is not supported", ref.Resource)
+	}
+	quantity, found := resources[core_v1.ResourceName(parts[1])]
+	if !found {
+		// Unset limits default to the node allocatable, unknown here
+		return "", fmt.Errorf("Resource [%s] is not set on container [%s]", ref.Resource, container.Name)
+	}
+
+	divisor := ref.Divisor
+	if divisor.IsZero() {
+		divisor = resource.MustParse("1")
+	}
+	if parts[1] == string(core_v1.ResourceCPU) {
+		return strconv.FormatInt(int64(math.Ceil(float64(quantity.MilliValue())/float64(divisor.MilliValue()))), 10), nil
+	}
+	return strconv.FormatInt(int64(math.Ceil(float64(quantity.Value())/float64(divisor.Value()))), 10), nil
+}
@@ -0,0 +1,143 @@
+package business
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	core_v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes/kubetest"
+	"github.com/kiali/kiali/models"
+)
+
+func TestGetWorkloadEnvSources(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	optional := true
+	pod := fakeEnvPod(core_v1.Container{
+		Name: "reviews",
+		EnvFrom: []core_v1.EnvFromSource{
+			{ConfigMapRef: &core_v1.ConfigMapEnvSource{LocalObjectReference: core_v1.LocalObjectReference{Name: "reviews-config"}}},
+			{Prefix: "DB_", SecretRef: &core_v1.SecretEnvSource{LocalObjectReference: core_v1.LocalObjectReference{Name: "db-credentials"}}},
+		},
+		Env: []core_v1.EnvVar{
+			{Name: "LOG_LEVEL", Value: "debug"},
+			{Name: "STAR_COLOR", ValueFrom: &core_v1.EnvVarSource{ConfigMapKeyRef: &core_v1.ConfigMapKeySelector{LocalObjectReference: core_v1.LocalObjectReference{Name: "reviews-config"}, Key: "color"}}},
+			{Name: "API_TOKEN", ValueFrom: &core_v1.EnvVarSource{SecretKeyRef: &core_v1.SecretKeySelector{LocalObjectReference: core_v1.LocalObjectReference{Name: "db-credentials"}, Key: "token"}}},
+			{Name: "POD_NAME", ValueFrom: &core_v1.EnvVarSource{FieldRef: &core_v1.ObjectFieldSelector{FieldPath: "metadata.name"}}},
+			{Name: "VERSION", ValueFrom: &core_v1.EnvVarSource{FieldRef: &core_v1.ObjectFieldSelector{FieldPath: "metadata.labels['version']"}}},
+			{Name: "CPU_LIMIT", ValueFrom: &core_v1.EnvVarSource{ResourceFieldRef: &core_v1.ResourceFieldSelector{Resource: "limits.cpu", Divisor: resource.MustParse("1m")}}},
+			{Name: "MEMORY_MB", ValueFrom: &core_v1.EnvVarSource{ResourceFieldRef: &core_v1.ResourceFieldSelector{Resource: "requests.memory", Divisor: resource.MustParse("1Mi")}}},
+			// Overrides the variable of the ConfigMap source
+			{Name: "ENABLE_RATINGS", Value: "true"},
+			// Optional and missing: not set
+			{Name: "FEATURE_FLAGS", ValueFrom: &core_v1.EnvVarSource{ConfigMapKeyRef: &core_v1.ConfigMapKeySelector{LocalObjectReference: core_v1.LocalObjectReference{Name: "reviews-config"}, Key: "flags", Optional: &optional}}},
+		},
+		Resources: core_v1.ResourceRequirements{
+			Limits:   core_v1.ResourceList{core_v1.ResourceCPU: resource.MustParse("500m")},
+			Requests: core_v1.ResourceList{core_v1.ResourceMemory: resource.MustParse("128Mi")},
+		},
+	})
+
+	k8s := new(kubetest.K8SClientMock)
+	k8s.On("GetPod", "bookinfo", "reviews-v1-1").Return(pod, nil)
+	k8s.On("GetConfigMap", "bookinfo", "reviews-config").Return(&core_v1.ConfigMap{
+		Data: map[string]string{"color": "black", "ENABLE_RATINGS": "false"},
+	}, nil)
+	k8s.On("GetSecret", "bookinfo", "db-credentials").Return(&core_v1.Secret{
+		Data: map[string][]byte{"password": []byte("s3cr3t"), "token": []byte("t0k3n")},
+	}, nil)
+
+	svc := WorkloadService{k8s: k8s}
+	env, err := svc.GetWorkloadEnv("bookinfo", "reviews-v1-1")
+	assert.NoError(err)
+
+	assert.Len(env.Containers, 1)
+	assert.Equal("reviews", env.Containers[0].Name)
+	assert.Empty(env.Containers[0].Errors)
+	assert.Equal([]models.EnvVar{
+		{Name: "ENABLE_RATINGS", Value: "true", Source: models.EnvSourceValue},
+		{Name: "color", Value: "black", Source: models.EnvSourceConfigMap, SourceName: "reviews-config", SourceKey: "color"},
+		{Name: "DB_password", Source: models.EnvSourceSecret, SourceName: "db-credentials", SourceKey: "password", Masked: true},
+		{Name: "DB_token", Source: models.EnvSourceSecret, SourceName: "db-credentials", SourceKey: "token", Masked: true},
+		{Name: "LOG_LEVEL", Value: "debug", Source: models.EnvSourceValue},
+		{Name: "STAR_COLOR", Value: "black", Source: models.EnvSourceConfigMap, SourceName: "reviews-config", SourceKey: "color"},
+		{Name: "API_TOKEN", Source: models.EnvSourceSecret, SourceName: "db-credentials", SourceKey: "token", Masked: true},
+		{Name: "POD_NAME", Value: "reviews-v1-1", Source: models.EnvSourceField, SourceName: "metadata.name"},
+		{Name: "VERSION", Value: "v1", Source: models.EnvSourceField, SourceName: "metadata.labels['version']"},
+		{Name: "CPU_LIMIT", Value: "500", Source: models.EnvSourceResource, SourceName: "limits.cpu"},
+		{Name: "MEMORY_MB", Value: "128", Source: models.EnvSourceResource, SourceName: "requests.memory"},
+	}, env.Containers[0].Env)
+
+	// Each referenced object is fetched once
+	k8s.AssertNumberOfCalls(t, "GetConfigMap", 1)
+	k8s.AssertNumberOfCalls(t, "GetSecret", 1)
+}
+
+func TestGetWorkloadEnvMissingReferences(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	optional := true
+	pod := fakeEnvPod(core_v1.Container{
+		Name: "reviews",
+		EnvFrom: []core_v1.EnvFromSource{
+			{ConfigMapRef: &core_v1.ConfigMapEnvSource{LocalObjectReference: core_v1.LocalObjectReference{Name: "missing-config"}}},
+			{SecretRef: &core_v1.SecretEnvSource{LocalObjectReference: core_v1.LocalObjectReference{Name: "missing-secret"}, Optional: &optional}},
+		},
+		Env: []core_v1.EnvVar{
+			{Name: "COLOR", ValueFrom: &core_v1.EnvVarSource{ConfigMapKeyRef: &core_v1.ConfigMapKeySelector{LocalObjectReference: core_v1.LocalObjectReference{Name: "missing-config"}, Key: "color"}}},
+			{Name: "PASSWORD", ValueFrom: &core_v1.EnvVarSource{SecretKeyRef: &core_v1.SecretKeySelector{LocalObjectReference: core_v1.LocalObjectReference{Name: "db-credentials"}, Key: "password"}}},
+			{Name: "TOKEN", ValueFrom: &core_v1.EnvVarSource{SecretKeyRef: &core_v1.SecretKeySelector{LocalObjectReference: core_v1.LocalObjectReference{Name: "missing-secret"}, Key: "token", Optional: &optional}}},
+			{Name: "MEMORY_LIMIT", ValueFrom: &core_v1.EnvVarSource{ResourceFieldRef: &core_v1.ResourceFieldSelector{Resource: "limits.memory"}}},
+		},
+	})
+
+	k8s := new(kubetest.K8SClientMock)
+	k8s.On("GetPod", "bookinfo", "reviews-v1-1").Return(pod, nil)
+	k8s.On("GetConfigMap", "bookinfo", "missing-config").Return(&core_v1.ConfigMap{}, errors.NewNotFound(schema.GroupResource{Resource: "configmaps"}, "missing-config"))
+	k8s.On("GetSecret", "bookinfo", "missing-secret").Return(&core_v1.Secret{}, errors.NewNotFound(schema.GroupResource{Resource: "secrets"}, "missing-secret"))
+	k8s.On("GetSecret", "bookinfo", "db-credentials").Return(&core_v1.Secret{Data: map[string][]byte{"user": []byte("admin")}}, nil)
+
+	svc := WorkloadService{k8s: k8s}
+	env, err := svc.GetWorkloadEnv("bookinfo", "reviews-v1-1")
+	assert.NoError(err)
+
+	container := env.Containers[0]
+	// The optional missing Secret source isn't an error
+	assert.Equal([]string{"ConfigMap [missing-config] not found"}, container.Errors)
+	assert.Len(container.Env, 3)
+	assert.Equal(models.EnvVar{Name: "COLOR", Source: models.EnvSourceConfigMap, SourceName: "missing-config", SourceKey: "color", Error: "ConfigMap [missing-config] not found"}, container.Env[0])
+	assert.Equal(models.EnvVar{Name: "PASSWORD", Source: models.EnvSourceSecret, SourceName: "db-credentials", SourceKey: "password", Error: "Secret [db-credentials]: key [password] not found"}, container.Env[1])
+	assert.Equal("MEMORY_LIMIT", container.Env[2].Name)
+	assert.Empty(container.Env[2].Value)
+	assert.NotEmpty(container.Env[2].Error)
+}
+
+func TestGetWorkloadEnvPodNotFound(t *testing.T) {
+	assert := assert.New(t)
+
+	k8s := new(kubetest.K8SClientMock)
+	k8s.On("GetPod", "bookinfo", "reviews-v1-1").Return(&core_v1.Pod{}, errors.NewNotFound(schema.GroupResource{Resource: "pods"}, "reviews-v1-1"))
+
+	svc := WorkloadService{k8s: k8s}
+	_, err := svc.GetWorkloadEnv("bookinfo", "reviews-v1-1")
+	assert.True(errors.IsNotFound(err))
+}
+
+func fakeEnvPod(container core_v1.Container) *core_v1.Pod {
+	return &core_v1.Pod{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "reviews-v1-1",
+			Namespace: "bookinfo",
+			Labels:    map[string]string{"app": "reviews", "version": "v1"},
+		},
+		Spec: core_v1.PodSpec{Containers: []core_v1.Container{container}},
+	}
+}
@@ -0,0 +1,88 @@
+package business
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/prometheus/internalmetrics"
+	"github.com/kiali/kiali/util"
+)
+
+// SetWorkloadMaintenance puts a workload in maintenance for the given duration (Prometheus format, i.e. 30m, 2h), or
+// for the configured duration when empty. The end of the maintenance is annotated on the controller, not on the pod
+// template, so the pods are not restarted. Until then, the degraded or failure health of the workload is suppressed.
+func (in *WorkloadService) SetWorkloadMaintenance(namespace, workloadName, workloadType, duration string) (*models.Workload, error) {
+	var err error
+	promtimer := internalmetrics.GetGoFunctionMetric("business", "WorkloadService", "SetWorkloadMaintenance")
+	defer promtimer.ObserveNow(&err)
+
+	conf := config.Get().HealthConfig.Maintenance
+	if duration == "" {
+		duration = conf.Duration
+	}
+	var d model.Duration
+	if d, err = model.ParseDuration(duration); err != nil || d <= 0 {
+		err = errors.NewBadRequest(fmt.Sprintf("invalid maintenance duration [%s]", duration))
+		return nil, err
+	}
+
+	until := util.Clock.Now().Add(time.Duration(d)).UTC().Format(time.RFC3339)
+	var w *models.Workload
+	w, err = in.patchWorkloadMaintenance(namespace, workloadName, workloadType, &until)
+	return w, err
+}
+
+// ClearWorkloadMaintenance ends the maintenance of a workload, restoring its health
+func (in *WorkloadService) ClearWorkloadMaintenance(namespace, workloadName, workloadType string) (*models.Workload, error) {
+	var err error
+	promtimer := internalmetrics.GetGoFunctionMetric("business", "WorkloadService", "ClearWorkloadMaintenance")
+	defer promtimer.ObserveNow(&err)
+
+	var w *models.Workload
+	w, err = in.patchWorkloadMaintenance(namespace, workloadName, workloadType, nil)
+	return w, err
+}
+
+// patchWorkloadMaintenance sets the maintenance annotation of the controller of a workload, or removes it when nil
+func (in *WorkloadService) patchWorkloadMaintenance(namespace, workloadName, workloadType string, until *string) (*models.Workload, error) {
+	// Check if user has access to the namespace (RBAC) in cache scenarios and/or
+	// if namespace is accessible from Kiali (Deployment.AccessibleNamespaces)
+	if _, err := in.businessLayer.Namespace.GetNamespace(namespace); err != nil {
+		return nil, err
+	}
+
+	// The type is resolved from the workload when not given
+	w, err := fetchWorkload(in.businessLayer, namespace, workloadName, workloadType)
+	if err != nil {
+		return nil, err
+	}
+
+	annotation := config.Get().HealthConfig.Maintenance.Annotation
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]*string{annotation: until},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err = in.k8s.PatchWorkload(namespace, workloadName, w.Type, string(patch), types.MergePatchType); err != nil {
+		return nil, err
+	}
+	in.businessLayer.Audit.Record("", AuditUpdate, namespace, w.Type, workloadName)
+
+	// Cache is stopped after a Create/Update/Delete operation to force a refresh
+	if kialiCache != nil {
+		kialiCache.RefreshNamespace(namespace)
+	}
+
+	return in.GetWorkload(namespace, workloadName, w.Type, false)
+}
@@ -0,0 +1,135 @@
+package business
+
+import (
+	"testing"
+	"time"
+
+	osproject_v1 "github.com/openshift/api/project/v1"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/kubernetes/kubetest"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/prometheus/prometheustest"
+	"github.com/kiali/kiali/util"
+)
+
+func TestSetWorkloadMaintenance(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+	util.Clock = util.ClockMock{Time: time.Date(2017, 01, 15, 0, 0, 0, 0, time.UTC)}
+	defer func() { util.Clock = util.RealClock{} }()
+
+	k8s, _ := setupMaintenanceMocks("")
+	k8s.On("PatchWorkload", "ns", "reviews-v1", kubernetes.DeploymentType, `{"metadata":{"annotations":{"kiali.io/maintenance-until":"2017-01-15T02:00:00Z"}}}`, types.MergePatchType).Return(nil)
+	k8s.On("PatchWorkload", "ns", "reviews-v1", kubernetes.DeploymentType, `{"metadata":{"annotations":{"kiali.io/maintenance-until":"2017-01-15T01:00:00Z"}}}`, types.MergePatchType).Return(nil)
+	svc := setupWorkloadService(k8s)
+
+	// The type is resolved from the workload
+	_, err := svc.SetWorkloadMaintenance("ns", "reviews-v1", "", "2h")
+	assert.NoError(err)
+	// Configured duration by default
+	_, err = svc.SetWorkloadMaintenance("ns", "reviews-v1", kubernetes.DeploymentType, "")
+	assert.NoError(err)
+	k8s.AssertNumberOfCalls(t, "PatchWorkload", 2)
+
+	for _, duration := range []string{"2 hours", "0s"} {
+		_, err = svc.SetWorkloadMaintenance("ns", "reviews-v1", "", duration)
+		assert.True(errors.IsBadRequest(err), duration)
+	}
+	k8s.AssertNumberOfCalls(t, "PatchWorkload", 2)
+}
+
+func TestClearWorkloadMaintenance(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	k8s, _ := setupMaintenanceMocks("2017-01-15T01:00:00Z")
+	expectedPatch := `{"metadata":{"annotations":{"kiali.io/maintenance-until":null}}}`
+	k8s.On("PatchWorkload", "ns", "reviews-v1", kubernetes.DeploymentType, expectedPatch, types.MergePatchType).Return(nil)
+	svc := setupWorkloadService(k8s)
+
+	_, err := svc.ClearWorkloadMaintenance("ns", "reviews-v1", "")
+	assert.NoError(err)
+	k8s.AssertCalled(t, "PatchWorkload", "ns", "reviews-v1", kubernetes.DeploymentType, expectedPatch, types.MergePatchType)
+}
+
+func TestWorkloadHealthInMaintenance(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	k8s, prom := setupMaintenanceMocks("2017-01-15T01:00:00Z")
+	hs := HealthService{k8s: k8s, prom: prom, businessLayer: NewWithBackends(k8s, prom, nil)}
+
+	health, err := hs.GetWorkloadHealth("ns", "reviews-v1", "", "1m", time.Date(2017, 01, 15, 0, 0, 0, 0, time.UTC))
+	assert.NoError(err)
+	assert.Equal(models.HealthStatusMaintenance, health.Status)
+	assert.Equal(time.Date(2017, 01, 15, 1, 0, 0, 0, time.UTC), *health.MaintenanceUntil)
+
+	// The degraded dependency in maintenance is suppressed
+	dependencyHealth, err := hs.GetWorkloadDependencyHealth("ns", "productpage-v1", "", "1m", time.Date(2017, 01, 15, 0, 0, 0, 0, time.UTC))
+	assert.NoError(err)
+	reviews := dependencyHealth.Dependencies[1]
+	assert.Equal("reviews-v1", reviews.Name)
+	assert.Equal(models.HealthStatusMaintenance, reviews.Status)
+	assert.Equal(models.HealthStatusHealthy, dependencyHealth.DependencyStatus)
+}
+
+func TestWorkloadHealthMaintenanceExpired(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	k8s, prom := setupMaintenanceMocks("2017-01-15T01:00:00Z")
+	hs := HealthService{k8s: k8s, prom: prom, businessLayer: NewWithBackends(k8s, prom, nil)}
+
+	health, err := hs.GetWorkloadHealth("ns", "reviews-v1", "", "1m", time.Date(2017, 01, 15, 1, 0, 0, 0, time.UTC))
+	assert.NoError(err)
+	assert.Empty(health.Status)
+	assert.Nil(health.MaintenanceUntil)
+
+	dependencyHealth, err := hs.GetWorkloadDependencyHealth("ns", "productpage-v1", "", "1m", time.Date(2017, 01, 15, 1, 0, 0, 0, time.UTC))
+	assert.NoError(err)
+	assert.Equal(models.HealthStatusDegraded, dependencyHealth.Dependencies[1].Status)
+	assert.Equal(models.HealthStatusDegraded, dependencyHealth.DependencyStatus)
+}
+
+// setupMaintenanceMocks mocks the workloads of the dependency health tests, reviews-v1 being in maintenance until
+// the given time when set
+func setupMaintenanceMocks(until string) (*kubetest.K8SClientMock, *prometheustest.PromClientMock) {
+	k8s := new(kubetest.K8SClientMock)
+	prom := new(prometheustest.PromClientMock)
+
+	deployments := fakeDeploymentsDependencyHealth()
+	if until != "" {
+		deployments[1].Annotations = map[string]string{"kiali.io/maintenance-until": until}
+	}
+	k8s.On("IsOpenShift").Return(true)
+	k8s.On("GetProject", mock.AnythingOfType("string")).Return(&osproject_v1.Project{}, nil)
+	k8s.On("GetDeployment", "ns", "productpage-v1").Return(&deployments[0], nil)
+	k8s.On("GetDeployment", "ns", "reviews-v1").Return(&deployments[1], nil)
+	k8s.On("GetDeployment", "ns", "details-v1").Return(&deployments[2], nil)
+	k8s.MockEmptyWorkload("ns", "productpage-v1")
+	k8s.MockEmptyWorkload("ns", "reviews-v1")
+	k8s.MockEmptyWorkload("ns", "details-v1")
+	k8s.On("GetPods", "ns", "").Return(fakePodsDependencyHealth(), nil)
+	k8s.On("GetProxyStatus").Return([]*kubernetes.ProxyStatus{}, nil)
+
+	prom.MockWorkloadRequestRates("ns", "productpage-v1", model.Vector{}, model.Vector{
+		dependencySample("reviews-v1", "reviews", "200", 19),
+		dependencySample("reviews-v1", "reviews", "503", 1),
+		dependencySample("details-v1", "details", "200", 10),
+	})
+	prom.MockWorkloadRequestRates("ns", "reviews-v1", model.Vector{
+		dependencySample("reviews-v1", "reviews", "200", 19),
+		dependencySample("reviews-v1", "reviews", "503", 1),
+	}, model.Vector{})
+	prom.MockWorkloadRequestRates("ns", "details-v1", model.Vector{
+		dependencySample("details-v1", "details", "200", 10),
+	}, model.Vector{})
+	return k8s, prom
+}
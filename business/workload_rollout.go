@@ -0,0 +1,177 @@
+package business
+
+import (
+	"fmt"
+
+	apps_v1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/prometheus/internalmetrics"
+)
+
+// The reason of the Progressing condition of a Deployment whose rollout exceeded its progress deadline
+const progressDeadlineExceededReason = "ProgressDeadlineExceeded"
+
+// GetWorkloadRolloutStatus returns the progress of the rollout of a Deployment or a StatefulSet, evaluated as
+// `kubectl rollout status` does. The type is resolved from the workload when not given.
+func (in *WorkloadService) GetWorkloadRolloutStatus(namespace, workload, workloadType string) (*models.WorkloadRolloutStatus, error) {
+	var err error
+	promtimer := internalmetrics.GetGoFunctionMetric("business", "WorkloadService", "GetWorkloadRolloutStatus")
+	defer promtimer.ObserveNow(&err)
+
+	// Check if user has access to the namespace (RBAC) in cache scenarios and/or
+	// if namespace is accessible from Kiali (Deployment.AccessibleNamespaces)
+	if _, err = in.businessLayer.Namespace.GetNamespace(namespace); err != nil {
+		return nil, err
+	}
+
+	switch workloadType {
+	case "", kubernetes.DeploymentType, kubernetes.StatefulSetType:
+	default:
+		err = errors.NewBadRequest(fmt.Sprintf("the rollout status of a %s is not supported, only of a Deployment or a StatefulSet", workloadType))
+		return nil, err
+	}
+
+	if workloadType != kubernetes.StatefulSetType {
+		var dep *apps_v1.Deployment
+		if IsNamespaceCached(namespace) {
+			dep, err = kialiCache.GetDeployment(namespace, workload)
+		} else {
+			dep, err = in.k8s.GetDeployment(namespace, workload)
+		}
+		// The cache returns no error for a missing object
+		if err == nil && dep == nil {
+			err = errors.NewNotFound(schema.GroupResource{Group: "apps", Resource: "deployments"}, workload)
+		}
+		if err == nil {
+			return deploymentRolloutStatus(dep), nil
+		}
+		if !errors.IsNotFound(err) || workloadType == kubernetes.DeploymentType {
+			return nil, err
+		}
+	}
+
+	var ss *apps_v1.StatefulSet
+	if IsNamespaceCached(namespace) {
+		ss, err = kialiCache.GetStatefulSet(namespace, workload)
+	} else {
+		ss, err = in.k8s.GetStatefulSet(namespace, workload)
+	}
+	if err == nil && ss == nil {
+		err = errors.NewNotFound(schema.GroupResource{Group: "apps", Resource: "statefulsets"}, workload)
+	}
+	if err != nil {
+		if errors.IsNotFound(err) && workloadType == "" {
+			err = errors.NewNotFound(schema.GroupResource{Group: "apps", Resource: "deployments,statefulsets"}, workload)
+		}
+		return nil, err
+	}
+	return statefulSetRolloutStatus(ss), nil
+}
+
+func deploymentRolloutStatus(dep *apps_v1.Deployment) *models.WorkloadRolloutStatus {
+	desired := int32(1)
+	if dep.Spec.Replicas != nil {
+		desired = *dep.Spec.Replicas
+	}
+	rollout := &models.WorkloadRolloutStatus{
+		Namespace:           dep.Namespace,
+		Workload:            dep.Name,
+		Type:                kubernetes.DeploymentType,
+		DesiredReplicas:     desired,
+		UpdatedReplicas:     dep.Status.UpdatedReplicas,
+		AvailableReplicas:   dep.Status.AvailableReplicas,
+		UnavailableReplicas: dep.Status.UnavailableReplicas,
+	}
+	for _, condition := range dep.Status.Conditions {
+		if condition.Type == apps_v1.DeploymentProgressing {
+			rollout.Condition = &models.RolloutCondition{
+				Type:           string(condition.Type),
+				Status:         string(condition.Status),
+				Reason:         condition.Reason,
+				Message:        condition.Message,
+				LastUpdateTime: condition.LastUpdateTime.Time,
+			}
+		}
+	}
+
+	status := dep.Status
+	switch {
+	case dep.Generation > status.ObservedGeneration:
+		rollout.Status = models.RolloutProgressing
+		rollout.Message = "Waiting for the deployment spec update to be observed"
+	case rollout.Condition != nil && rollout.Condition.Reason == progressDeadlineExceededReason:
+		rollout.Status = models.RolloutStuck
+		rollout.Stuck = true
+		rollout.Message = fmt.Sprintf("The rollout exceeded its progress deadline: %d of %d updated replicas are available", status.AvailableReplicas, desired)
+	case status.UpdatedReplicas < desired:
+		rollout.Status = models.RolloutProgressing
+		rollout.Message = fmt.Sprintf("Waiting for rollout to finish: %d out of %d new replicas have been updated", status.UpdatedReplicas, desired)
+	case status.Replicas > status.UpdatedReplicas:
+		rollout.Status = models.RolloutProgressing
+		rollout.Message = fmt.Sprintf("Waiting for rollout to finish: %d old replicas are pending termination", status.Replicas-status.UpdatedReplicas)
+	case status.AvailableReplicas < status.UpdatedReplicas:
+		rollout.Status = models.RolloutProgressing
+		rollout.Message = fmt.Sprintf("Waiting for rollout to finish: %d of %d updated replicas are available", status.AvailableReplicas, status.UpdatedReplicas)
+	default:
+		rollout.Status = models.RolloutComplete
+	}
+	// A paused rollout doesn't progress until resumed, and is never stuck
+	if dep.Spec.Paused && rollout.Status == models.RolloutProgressing {
+		rollout.Status = models.RolloutPaused
+	}
+	return rollout
+}
+
+func statefulSetRolloutStatus(ss *apps_v1.StatefulSet) *models.WorkloadRolloutStatus {
+	desired := int32(1)
+	if ss.Spec.Replicas != nil {
+		desired = *ss.Spec.Replicas
+	}
+	status := ss.Status
+	rollout := &models.WorkloadRolloutStatus{
+		Namespace:         ss.Namespace,
+		Workload:          ss.Name,
+		Type:              kubernetes.StatefulSetType,
+		DesiredReplicas:   desired,
+		UpdatedReplicas:   status.UpdatedReplicas,
+		AvailableReplicas: status.ReadyReplicas,
+	}
+	if desired > status.ReadyReplicas {
+		rollout.UnavailableReplicas = desired - status.ReadyReplicas
+	}
+
+	// The pods of the ordinals under the partition are not updated
+	updateTarget := desired
+	if rollingUpdate := ss.Spec.UpdateStrategy.RollingUpdate; rollingUpdate != nil && rollingUpdate.Partition != nil && *rollingUpdate.Partition > 0 {
+		updateTarget = desired - *rollingUpdate.Partition
+		if updateTarget < 0 {
+			updateTarget = 0
+		}
+	}
+
+	switch {
+	case status.ObservedGeneration == 0 || ss.Generation > status.ObservedGeneration:
+		rollout.Status = models.RolloutProgressing
+		rollout.Message = "Waiting for the statefulset spec update to be observed"
+	case status.ReadyReplicas < desired:
+		rollout.Status = models.RolloutProgressing
+		rollout.Message = fmt.Sprintf("Waiting for %d pods to be ready", desired-status.ReadyReplicas)
+	case status.UpdatedReplicas < updateTarget:
+		rollout.Status = models.RolloutProgressing
+		rollout.Message = fmt.Sprintf("Waiting for rollout to finish: %d out of %d new pods have been updated", status.UpdatedReplicas, updateTarget)
+		if ss.Spec.UpdateStrategy.Type == apps_v1.OnDeleteStatefulSetStrategyType {
+			// The pods are only updated when deleted
+			rollout.Message += ", the old pods must be deleted to be updated"
+		}
+	case updateTarget == desired && ss.Spec.UpdateStrategy.Type != apps_v1.OnDeleteStatefulSetStrategyType && status.UpdateRevision != status.CurrentRevision:
+		rollout.Status = models.RolloutProgressing
+		rollout.Message = fmt.Sprintf("Waiting for the pods of the revision %s to replace the pods of the revision %s", status.UpdateRevision, status.CurrentRevision)
+	default:
+		rollout.Status = models.RolloutComplete
+	}
+	return rollout
+}
@@ -0,0 +1,208 @@
+package business
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	osproject_v1 "github.com/openshift/api/project/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	apps_v1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/yaml"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/kubernetes/cache"
+	"github.com/kiali/kiali/kubernetes/kubetest"
+	"github.com/kiali/kiali/models"
+)
+
+func TestDeploymentRolloutStatus(t *testing.T) {
+	assert := assert.New(t)
+	deployments, _ := loadRolloutControllers(t)
+
+	complete := deploymentRolloutStatus(deployments["reviews-complete"])
+	assert.Equal(models.RolloutComplete, complete.Status)
+	assert.False(complete.Stuck)
+	assert.Empty(complete.Message)
+	assert.Equal(int32(3), complete.DesiredReplicas)
+	assert.Equal(int32(3), complete.UpdatedReplicas)
+	assert.Equal(int32(3), complete.AvailableReplicas)
+	assert.Equal(int32(0), complete.UnavailableReplicas)
+	assert.Equal("NewReplicaSetAvailable", complete.Condition.Reason)
+
+	progressing := deploymentRolloutStatus(deployments["reviews-progressing"])
+	assert.Equal(models.RolloutProgressing, progressing.Status)
+	assert.False(progressing.Stuck)
+	assert.Equal("Waiting for rollout to finish: 2 out of 3 new replicas have been updated", progressing.Message)
+	assert.Equal(int32(2), progressing.UpdatedReplicas)
+	assert.Equal(int32(1), progressing.UnavailableReplicas)
+	assert.Equal("ReplicaSetUpdated", progressing.Condition.Reason)
+
+	stuck := deploymentRolloutStatus(deployments["reviews-stuck"])
+	assert.Equal(models.RolloutStuck, stuck.Status)
+	assert.True(stuck.Stuck)
+	assert.Equal("False", stuck.Condition.Status)
+	assert.Equal(progressDeadlineExceededReason, stuck.Condition.Reason)
+	assert.Equal(2021, stuck.Condition.LastUpdateTime.Year())
+
+	paused := deploymentRolloutStatus(deployments["reviews-paused"])
+	assert.Equal(models.RolloutPaused, paused.Status)
+	assert.False(paused.Stuck)
+
+	// A spec update not yet observed by the controller
+	unobserved := deployments["reviews-complete"].DeepCopy()
+	unobserved.Generation++
+	assert.Equal(models.RolloutProgressing, deploymentRolloutStatus(unobserved).Status)
+}
+
+func TestStatefulSetRolloutStatus(t *testing.T) {
+	assert := assert.New(t)
+	_, statefulSets := loadRolloutControllers(t)
+
+	complete := statefulSetRolloutStatus(statefulSets["ratings-complete"])
+	assert.Equal(models.RolloutComplete, complete.Status)
+	assert.False(complete.Stuck)
+	assert.Nil(complete.Condition)
+	assert.Equal(int32(3), complete.AvailableReplicas)
+
+	progressing := statefulSetRolloutStatus(statefulSets["ratings-progressing"])
+	assert.Equal(models.RolloutProgressing, progressing.Status)
+	assert.Equal("Waiting for rollout to finish: 1 out of 3 new pods have been updated", progressing.Message)
+	assert.False(progressing.Stuck)
+
+	// Only the ordinals from the partition are updated
+	partitioned := statefulSetRolloutStatus(statefulSets["ratings-partitioned"])
+	assert.Equal(models.RolloutComplete, partitioned.Status)
+
+	notReady := statefulSets["ratings-complete"].DeepCopy()
+	notReady.Status.ReadyReplicas = 1
+	rollout := statefulSetRolloutStatus(notReady)
+	assert.Equal(models.RolloutProgressing, rollout.Status)
+	assert.Equal(int32(2), rollout.UnavailableReplicas)
+
+	onDelete := statefulSets["ratings-progressing"].DeepCopy()
+	onDelete.Spec.UpdateStrategy.Type = apps_v1.OnDeleteStatefulSetStrategyType
+	assert.Contains(statefulSetRolloutStatus(onDelete).Message, "must be deleted")
+}
+
+func TestGetWorkloadRolloutStatus(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+	deployments, statefulSets := loadRolloutControllers(t)
+
+	notfound := errors.NewNotFound(schema.GroupResource{Group: "apps", Resource: "deployments"}, "ratings-progressing")
+	k8s := new(kubetest.K8SClientMock)
+	k8s.On("IsOpenShift").Return(true)
+	k8s.On("GetProject", "bookinfo").Return(&osproject_v1.Project{}, nil)
+	k8s.On("GetDeployment", "bookinfo", "reviews-stuck").Return(deployments["reviews-stuck"], nil)
+	k8s.On("GetDeployment", "bookinfo", "ratings-progressing").Return(&apps_v1.Deployment{}, notfound)
+	k8s.On("GetStatefulSet", "bookinfo", "ratings-progressing").Return(statefulSets["ratings-progressing"], nil)
+
+	svc := setupWorkloadService(k8s)
+
+	rollout, err := svc.GetWorkloadRolloutStatus("bookinfo", "reviews-stuck", "")
+	assert.NoError(err)
+	assert.Equal(kubernetes.DeploymentType, rollout.Type)
+	assert.Equal(models.RolloutStuck, rollout.Status)
+	assert.True(rollout.Stuck)
+
+	// The type is resolved from the workload
+	rollout, err = svc.GetWorkloadRolloutStatus("bookinfo", "ratings-progressing", "")
+	assert.NoError(err)
+	assert.Equal(kubernetes.StatefulSetType, rollout.Type)
+	assert.Equal(models.RolloutProgressing, rollout.Status)
+
+	_, err = svc.GetWorkloadRolloutStatus("bookinfo", "ratings-progressing", kubernetes.DeploymentType)
+	assert.True(errors.IsNotFound(err))
+
+	_, err = svc.GetWorkloadRolloutStatus("bookinfo", "details", kubernetes.DaemonSetType)
+	assert.True(errors.IsBadRequest(err))
+}
+
+func TestGetWorkloadRolloutStatusFromCache(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+	deployments, statefulSets := loadRolloutControllers(t)
+
+	kialiCache = &fakeRolloutCache{deployments: deployments, statefulSets: statefulSets}
+	defer func() { kialiCache = nil }()
+
+	k8s := new(kubetest.K8SClientMock)
+	k8s.On("IsOpenShift").Return(false)
+	k8s.On("GetToken").Return("token")
+
+	svc := setupWorkloadService(k8s)
+
+	rollout, err := svc.GetWorkloadRolloutStatus("bookinfo", "reviews-progressing", kubernetes.DeploymentType)
+	assert.NoError(err)
+	assert.Equal(models.RolloutProgressing, rollout.Status)
+	assert.False(rollout.Stuck)
+
+	rollout, err = svc.GetWorkloadRolloutStatus("bookinfo", "ratings-complete", "")
+	assert.NoError(err)
+	assert.Equal(kubernetes.StatefulSetType, rollout.Type)
+	assert.Equal(models.RolloutComplete, rollout.Status)
+
+	_, err = svc.GetWorkloadRolloutStatus("bookinfo", "details", "")
+	assert.True(errors.IsNotFound(err))
+
+	k8s.AssertNotCalled(t, "GetDeployment", mock.Anything, mock.Anything)
+	k8s.AssertNotCalled(t, "GetStatefulSet", mock.Anything, mock.Anything)
+}
+
+// fakeRolloutCache serves the controllers from memory, nil when not found as the cache does
+type fakeRolloutCache struct {
+	cache.KialiCache
+	deployments  map[string]*apps_v1.Deployment
+	statefulSets map[string]*apps_v1.StatefulSet
+}
+
+func (f *fakeRolloutCache) CheckNamespace(namespace string) bool {
+	return true
+}
+
+func (f *fakeRolloutCache) GetNamespace(token string, namespace string) *models.Namespace {
+	return &models.Namespace{Name: namespace}
+}
+
+func (f *fakeRolloutCache) GetDeployment(namespace, name string) (*apps_v1.Deployment, error) {
+	return f.deployments[name], nil
+}
+
+func (f *fakeRolloutCache) GetStatefulSet(namespace, name string) (*apps_v1.StatefulSet, error) {
+	return f.statefulSets[name], nil
+}
+
+func loadRolloutControllers(t *testing.T) (map[string]*apps_v1.Deployment, map[string]*apps_v1.StatefulSet) {
+	content, err := ioutil.ReadFile("../tests/data/workloads/rollouts.yaml")
+	if err != nil {
+		t.Fatalf("Error loading test data: %v", err)
+	}
+	deployments := map[string]*apps_v1.Deployment{}
+	statefulSets := map[string]*apps_v1.StatefulSet{}
+	for _, doc := range strings.Split(string(content), "\n---\n") {
+		js, err := yaml.ToJSON([]byte(doc))
+		if err != nil {
+			t.Fatalf("Error parsing test data: %v", err)
+		}
+		if strings.Contains(doc, "kind: StatefulSet") {
+			ss := &apps_v1.StatefulSet{}
+			if err := json.Unmarshal(js, ss); err != nil {
+				t.Fatalf("Error parsing test data: %v", err)
+			}
+			statefulSets[ss.Name] = ss
+		} else {
+			dep := &apps_v1.Deployment{}
+			if err := json.Unmarshal(js, dep); err != nil {
+				t.Fatalf("Error parsing test data: %v", err)
+			}
+			deployments[dep.Name] = dep
+		}
+	}
+	return deployments, statefulSets
+}
@@ -1,6 +1,7 @@
 package business
 
 import (
+	"encoding/json"
 	"fmt"
 	"regexp"
 	"sort"
@@ -17,6 +18,8 @@ import (
 	"k8s.io/apimachinery/pkg/api/errors"
 	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
 
 	"github.com/kiali/kiali/config"
 	"github.com/kiali/kiali/kubernetes"
@@ -54,14 +57,15 @@ type LogOptions struct {
 
 var (
 	excludedWorkloads map[string]bool
+	includedWorkloads map[string]bool
 
 	// Matches an ISO8601 full date
 	severityRegexp = regexp.MustCompile(`(?i)ERROR|WARN|DEBUG|TRACE`)
 )
 
 func isWorkloadIncluded(workload string) bool {
-	if excludedWorkloads == nil {
-		return true
+	if len(includedWorkloads) > 0 && !includedWorkloads[workload] {
+		return false
 	}
 	return !excludedWorkloads[workload]
 }
@@ -133,6 +137,8 @@ func (in *WorkloadService) GetWorkload(namespace string, workloadName string, wo
 
 	wg.Wait()
 	workload.Runtimes = runtimes
+	// The digests come from the status of the pods fetched with the workload, cached when the namespace is
+	workload.Images = models.NewWorkloadImages(workload.Pods)
 
 	return workload, nil
 }
@@ -147,6 +153,7 @@ func (in *WorkloadService) UpdateWorkload(namespace string, workloadName string,
 	if err != nil {
 		return nil, err
 	}
+	in.businessLayer.Audit.Record("", AuditUpdate, namespace, workloadType, workloadName)
 
 	// Cache is stopped after a Create/Update/Delete operation to force a refresh
 	if kialiCache != nil && err == nil {
@@ -157,6 +164,114 @@ func (in *WorkloadService) UpdateWorkload(namespace string, workloadName string,
 	return in.GetWorkload(namespace, workloadName, workloadType, includeServices)
 }
 
+// PatchWorkloadMetadata adds, updates or removes (nil value) labels and annotations of a workload. They are patched in
+// the metadata of the workload itself, unless podTemplate is set: then they are patched in its pod template, where
+// Kiali and Istio read them, which rolls out new pods. Pods are always patched directly. The app and version labels
+// required by Istio on the pods can be updated but not cleared.
+func (in *WorkloadService) PatchWorkloadMetadata(namespace, workloadName, workloadType string, labels, annotations map[string]*string, podTemplate bool) (*models.Workload, error) {
+	var err error
+	promtimer := internalmetrics.GetGoFunctionMetric("business", "WorkloadService", "PatchWorkloadMetadata")
+	defer promtimer.ObserveNow(&err)
+
+	// Check if user has access to the namespace (RBAC) in cache scenarios and/or
+	// if namespace is accessible from Kiali (Deployment.AccessibleNamespaces)
+	if _, err = in.businessLayer.Namespace.GetNamespace(namespace); err != nil {
+		return nil, err
+	}
+
+	var patch string
+	if patch, err = buildWorkloadMetadataPatch(workloadType, labels, annotations, podTemplate); err != nil {
+		err = errors.NewBadRequest(err.Error())
+		return nil, err
+	}
+
+	if err = in.k8s.PatchWorkload(namespace, workloadName, workloadType, patch, types.StrategicMergePatchType); err != nil {
+		return nil, err
+	}
+	in.businessLayer.Audit.Record("", AuditUpdate, namespace, workloadType, workloadName)
+
+	// Cache is stopped after a Create/Update/Delete operation to force a refresh
+	if kialiCache != nil {
+		kialiCache.RefreshNamespace(namespace)
+	}
+
+	return in.GetWorkload(namespace, workloadName, workloadType, false)
+}
+
+// buildWorkloadMetadataPatch validates the labels and annotations and returns the strategic merge patch setting them
+// in the metadata of the workload, or in its pod template
+func buildWorkloadMetadataPatch(workloadType string, labels, annotations map[string]*string, podTemplate bool) (string, error) {
+	if len(labels) == 0 && len(annotations) == 0 {
+		return "", fmt.Errorf("no label nor annotation to patch")
+	}
+
+	conf := config.Get()
+	// Only the labels of the pods are required by Istio
+	requiredLabels := map[string]bool{}
+	if podTemplate || workloadType == kubernetes.PodType {
+		requiredLabels[conf.IstioLabels.AppLabelName] = true
+		requiredLabels[conf.IstioLabels.VersionLabelName] = true
+	}
+	for key, value := range labels {
+		if errs := validation.IsQualifiedName(key); len(errs) > 0 {
+			return "", fmt.Errorf("invalid label key [%s]: %s", key, strings.Join(errs, "; "))
+		}
+		if value == nil || *value == "" {
+			if requiredLabels[key] {
+				return "", fmt.Errorf("label [%s] is required by Istio and can't be cleared", key)
+			}
+			continue
+		}
+		if errs := validation.IsValidLabelValue(*value); len(errs) > 0 {
+			return "", fmt.Errorf("invalid value for label [%s]: %s", key, strings.Join(errs, "; "))
+		}
+	}
+	for key := range annotations {
+		if errs := validation.IsQualifiedName(strings.ToLower(key)); len(errs) > 0 {
+			return "", fmt.Errorf("invalid annotation key [%s]: %s", key, strings.Join(errs, "; "))
+		}
+	}
+
+	metadata := map[string]interface{}{}
+	if len(labels) > 0 {
+		metadata["labels"] = labels
+	}
+	if len(annotations) > 0 {
+		metadata["annotations"] = annotations
+	}
+
+	var patch map[string]interface{}
+	switch workloadType {
+	case kubernetes.DeploymentType, kubernetes.ReplicaSetType, kubernetes.ReplicationControllerType, kubernetes.DeploymentConfigType, kubernetes.StatefulSetType:
+		if podTemplate {
+			patch = map[string]interface{}{"spec": map[string]interface{}{"template": map[string]interface{}{"metadata": metadata}}}
+		} else {
+			patch = map[string]interface{}{"metadata": metadata}
+		}
+	case kubernetes.CronJobType:
+		if podTemplate {
+			patch = map[string]interface{}{"spec": map[string]interface{}{"jobTemplate": map[string]interface{}{"spec": map[string]interface{}{"template": map[string]interface{}{"metadata": metadata}}}}}
+		} else {
+			patch = map[string]interface{}{"metadata": metadata}
+		}
+	case kubernetes.PodType:
+		patch = map[string]interface{}{"metadata": metadata}
+	case kubernetes.JobType:
+		if podTemplate {
+			return "", fmt.Errorf("the pod template of a Job can't be updated")
+		}
+		patch = map[string]interface{}{"metadata": metadata}
+	default:
+		return "", fmt.Errorf("workload type [%s] not supported", workloadType)
+	}
+
+	bytePatch, err := json.Marshal(patch)
+	if err != nil {
+		return "", err
+	}
+	return string(bytePatch), nil
+}
+
 func (in *WorkloadService) GetPods(namespace string, labelSelector string) (models.Pods, error) {
 	var err error
 	promtimer := internalmetrics.GetGoFunctionMetric("business", "WorkloadService", "GetPods")
@@ -337,6 +452,41 @@ func (in *WorkloadService) GetPodLogs(namespace, name string, opts *LogOptions)
 	return in.getParsedLogs(namespace, name, opts)
 }
 
+// GetPodAccessLogs returns the parsed access logs of the istio-proxy container of a pod.
+// Lines not matching the access log pattern (i.e. proxy logs) are skipped.
+func (in *WorkloadService) GetPodAccessLogs(namespace, name string, opts *LogOptions) ([]models.AccessLog, error) {
+	var err error
+	promtimer := internalmetrics.GetGoFunctionMetric("business", "WorkloadService", "GetPodAccessLogs")
+	defer promtimer.ObserveNow(&err)
+
+	pattern := models.DefaultAccessLogPattern
+	if custom := config.Get().ExternalServices.Istio.AccessLogPattern; custom != "" {
+		pattern = custom
+	}
+	accessLogRegexp, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid access log pattern [%s]: %v", pattern, err)
+	}
+
+	proxyOpts := LogOptions{}
+	if opts != nil {
+		proxyOpts = *opts
+	}
+	proxyOpts.Container = models.IstioProxyContainer
+	podLog, err := in.getParsedLogs(namespace, name, &proxyOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	accessLogs := make([]models.AccessLog, 0, len(podLog.Entries))
+	for _, entry := range podLog.Entries {
+		if al := models.ParseAccessLog(entry.Message, accessLogRegexp); al != nil {
+			accessLogs = append(accessLogs, *al)
+		}
+	}
+	return accessLogs, nil
+}
+
 func fetchWorkloads(layer *Layer, namespace string, labelSelector string) (models.Workloads, error) {
 	var pods []core_v1.Pod
 	var repcon []core_v1.ReplicationController
@@ -410,7 +560,8 @@ func fetchWorkloads(layer *Layer, namespace string, labelSelector string) (model
 	go func() {
 		defer wg.Done()
 		var err error
-		if isWorkloadIncluded(kubernetes.ReplicationControllerType) {
+		// ReplicationControllers are needed to resolve the DeploymentConfigs of their pods
+		if isWorkloadIncluded(kubernetes.ReplicationControllerType) || isWorkloadIncluded(kubernetes.DeploymentConfigType) {
 			repcon, err = layer.k8s.GetReplicationControllers(namespace)
 			if err != nil {
 				log.Errorf("Error fetching GetReplicationControllers per namespace %s: %s", namespace, err)
@@ -462,7 +613,8 @@ func fetchWorkloads(layer *Layer, namespace string, labelSelector string) (model
 	go func() {
 		defer wg.Done()
 		var err error
-		if isWorkloadIncluded(kubernetes.JobType) {
+		// Jobs are needed to resolve the CronJobs of their pods
+		if isWorkloadIncluded(kubernetes.JobType) || isWorkloadIncluded(kubernetes.CronJobType) {
 			jbs, err = layer.k8s.GetJobs(namespace)
 			if err != nil {
 				log.Errorf("Error fetching Jobs per namespace %s: %s", namespace, err)
@@ -477,125 +629,7 @@ func fetchWorkloads(layer *Layer, namespace string, labelSelector string) (model
 		return ws, err
 	}
 
-	// Key: name of controller; Value: type of controller
-	controllers := map[string]string{}
-
-	// Find controllers from pods
-	for _, pod := range pods {
-		if len(pod.OwnerReferences) != 0 {
-			for _, ref := range pod.OwnerReferences {
-				if ref.Controller != nil && *ref.Controller {
-					if _, exist := controllers[ref.Name]; !exist {
-						controllers[ref.Name] = ref.Kind
-					} else {
-						if controllers[ref.Name] != ref.Kind {
-							controllers[ref.Name] = controllerPriority(controllers[ref.Name], ref.Kind)
-						}
-					}
-				}
-			}
-		} else {
-			if _, exist := controllers[pod.Name]; !exist {
-				// Pod without controller
-				controllers[pod.Name] = "Pod"
-			}
-		}
-	}
-
-	// Resolve ReplicaSets from Deployments
-	// Resolve ReplicationControllers from DeploymentConfigs
-	// Resolve Jobs from CronJobs
-	for cname, ctype := range controllers {
-		if ctype == kubernetes.ReplicaSetType {
-			found := false
-			iFound := -1
-			for i, rs := range repset {
-				if rs.Name == cname {
-					iFound = i
-					found = true
-					break
-				}
-			}
-			if found && len(repset[iFound].OwnerReferences) > 0 {
-				for _, ref := range repset[iFound].OwnerReferences {
-					if ref.Controller != nil && *ref.Controller {
-						// Delete the child ReplicaSet and add the parent controller
-						if _, exist := controllers[ref.Name]; !exist {
-							controllers[ref.Name] = ref.Kind
-						} else {
-							if controllers[ref.Name] != ref.Kind {
-								controllers[ref.Name] = controllerPriority(controllers[ref.Name], ref.Kind)
-							}
-						}
-						delete(controllers, cname)
-					}
-				}
-			}
-		}
-		if ctype == kubernetes.ReplicationControllerType {
-			found := false
-			iFound := -1
-			for i, rc := range repcon {
-				if rc.Name == cname {
-					iFound = i
-					found = true
-					break
-				}
-			}
-			if found && len(repcon[iFound].OwnerReferences) > 0 {
-				for _, ref := range repcon[iFound].OwnerReferences {
-					if ref.Controller != nil && *ref.Controller {
-						// Delete the child ReplicationController and add the parent controller
-						if _, exist := controllers[ref.Name]; !exist {
-							controllers[ref.Name] = ref.Kind
-						} else {
-							if controllers[ref.Name] != ref.Kind {
-								controllers[ref.Name] = controllerPriority(controllers[ref.Name], ref.Kind)
-							}
-						}
-						delete(controllers, cname)
-					}
-				}
-			}
-		}
-		if ctype == kubernetes.JobType {
-			found := false
-			iFound := -1
-			for i, jb := range jbs {
-				if jb.Name == cname {
-					iFound = i
-					found = true
-					break
-				}
-			}
-			if found && len(jbs[iFound].OwnerReferences) > 0 {
-				for _, ref := range jbs[iFound].OwnerReferences {
-					if ref.Controller != nil && *ref.Controller {
-						// Delete the child Job and add the parent controller
-						if _, exist := controllers[ref.Name]; !exist {
-							controllers[ref.Name] = ref.Kind
-						} else {
-							if controllers[ref.Name] != ref.Kind {
-								controllers[ref.Name] = controllerPriority(controllers[ref.Name], ref.Kind)
-							}
-						}
-						// Jobs are special as deleting CronJob parent doesn't delete children
-						// So we need to check that parent exists before to delete children controller
-						cnExist := false
-						for _, cnj := range conjbs {
-							if cnj.Name == ref.Name {
-								cnExist = true
-								break
-							}
-						}
-						if cnExist {
-							delete(controllers, cname)
-						}
-					}
-				}
-			}
-		}
-	}
+	controllers := resolveControllers(pods, repset, repcon, jbs, conjbs)
 
 	// Cornercase, check for controllers without pods, to show them as a workload
 	var selector labels.Selector
@@ -651,6 +685,16 @@ func fetchWorkloads(layer *Layer, namespace string, labelSelector string) (model
 			controllers[fs.Name] = "StatefulSet"
 		}
 	}
+	for _, cjb := range conjbs {
+		selectorCheck := true
+		if selector != nil {
+			selectorCheck = selector.Matches(labels.Set(cjb.Spec.JobTemplate.Spec.Template.Labels))
+		}
+		if _, exist := controllers[cjb.Name]; !exist && selectorCheck {
+			controllers[cjb.Name] = kubernetes.CronJobType
+		}
+	}
+	filterExcludedControllers(controllers)
 
 	// Build workloads from controllers
 	var cnames []string
@@ -850,6 +894,143 @@ func fetchWorkloads(layer *Layer, namespace string, labelSelector string) (model
 	return ws, nil
 }
 
+// resolveControllers returns the top-level controllers of the pods, keyed by name, with their type as value.
+// Pods are grouped under their controller, and the ownership chains are collapsed to their root:
+// Pod->ReplicaSet->Deployment, Pod->ReplicationController->DeploymentConfig and Pod->Job->CronJob.
+// Pods without controller are their own workload.
+func resolveControllers(pods []core_v1.Pod, repset []apps_v1.ReplicaSet, repcon []core_v1.ReplicationController, jbs []batch_v1.Job, conjbs []batch_v1beta1.CronJob) map[string]string {
+	controllers := map[string]string{}
+
+	// Find controllers from pods
+	for _, pod := range pods {
+		if len(pod.OwnerReferences) != 0 {
+			for _, ref := range pod.OwnerReferences {
+				if ref.Controller != nil && *ref.Controller {
+					if _, exist := controllers[ref.Name]; !exist {
+						controllers[ref.Name] = ref.Kind
+					} else {
+						if controllers[ref.Name] != ref.Kind {
+							controllers[ref.Name] = controllerPriority(controllers[ref.Name], ref.Kind)
+						}
+					}
+				}
+			}
+		} else {
+			if _, exist := controllers[pod.Name]; !exist {
+				// Pod without controller
+				controllers[pod.Name] = "Pod"
+			}
+		}
+	}
+
+	// Resolve ReplicaSets from Deployments
+	// Resolve ReplicationControllers from DeploymentConfigs
+	// Resolve Jobs from CronJobs
+	for cname, ctype := range controllers {
+		if ctype == kubernetes.ReplicaSetType {
+			found := false
+			iFound := -1
+			for i, rs := range repset {
+				if rs.Name == cname {
+					iFound = i
+					found = true
+					break
+				}
+			}
+			if found && len(repset[iFound].OwnerReferences) > 0 {
+				for _, ref := range repset[iFound].OwnerReferences {
+					if ref.Controller != nil && *ref.Controller {
+						// Delete the child ReplicaSet and add the parent controller
+						if _, exist := controllers[ref.Name]; !exist {
+							controllers[ref.Name] = ref.Kind
+						} else {
+							if controllers[ref.Name] != ref.Kind {
+								controllers[ref.Name] = controllerPriority(controllers[ref.Name], ref.Kind)
+							}
+						}
+						delete(controllers, cname)
+					}
+				}
+			}
+		}
+		if ctype == kubernetes.ReplicationControllerType {
+			found := false
+			iFound := -1
+			for i, rc := range repcon {
+				if rc.Name == cname {
+					iFound = i
+					found = true
+					break
+				}
+			}
+			if found && len(repcon[iFound].OwnerReferences) > 0 {
+				for _, ref := range repcon[iFound].OwnerReferences {
+					if ref.Controller != nil && *ref.Controller {
+						// Delete the child ReplicationController and add the parent controller
+						if _, exist := controllers[ref.Name]; !exist {
+							controllers[ref.Name] = ref.Kind
+						} else {
+							if controllers[ref.Name] != ref.Kind {
+								controllers[ref.Name] = controllerPriority(controllers[ref.Name], ref.Kind)
+							}
+						}
+						delete(controllers, cname)
+					}
+				}
+			}
+		}
+		if ctype == kubernetes.JobType {
+			found := false
+			iFound := -1
+			for i, jb := range jbs {
+				if jb.Name == cname {
+					iFound = i
+					found = true
+					break
+				}
+			}
+			if found && len(jbs[iFound].OwnerReferences) > 0 {
+				for _, ref := range jbs[iFound].OwnerReferences {
+					if ref.Controller != nil && *ref.Controller {
+						// Delete the child Job and add the parent controller
+						if _, exist := controllers[ref.Name]; !exist {
+							controllers[ref.Name] = ref.Kind
+						} else {
+							if controllers[ref.Name] != ref.Kind {
+								controllers[ref.Name] = controllerPriority(controllers[ref.Name], ref.Kind)
+							}
+						}
+						// Jobs are special as deleting CronJob parent doesn't delete children
+						// So we need to check that parent exists before to delete children controller.
+						// CronJobs are not fetched when excluded: their Jobs are hidden with them.
+						cnExist := !isWorkloadIncluded(ref.Kind)
+						for _, cnj := range conjbs {
+							if cnj.Name == ref.Name {
+								cnExist = true
+								break
+							}
+						}
+						if cnExist {
+							delete(controllers, cname)
+						}
+					}
+				}
+			}
+		}
+	}
+
+	return controllers
+}
+
+// filterExcludedControllers removes the controllers whose type is not included in the workloads, with their pods
+func filterExcludedControllers(controllers map[string]string) {
+	for cname, ctype := range controllers {
+		if !isWorkloadIncluded(ctype) {
+			delete(controllers, cname)
+		}
+	}
+}
+
 func fetchWorkload(layer *Layer, namespace string, workloadName string, workloadType string) (*models.Workload, error) {
 	var pods []core_v1.Pod
 	var repcon []core_v1.ReplicationController
@@ -949,7 +1130,8 @@ func fetchWorkload(layer *Layer, namespace string, workloadName string, workload
 			return
 		}
 		var err error
-		if isWorkloadIncluded(kubernetes.ReplicationControllerType) {
+		// ReplicationControllers are needed to resolve the DeploymentConfigs of their pods
+		if isWorkloadIncluded(kubernetes.ReplicationControllerType) || isWorkloadIncluded(kubernetes.DeploymentConfigType) {
 			repcon, err = layer.k8s.GetReplicationControllers(namespace)
 			if err != nil {
 				log.Errorf("Error fetching GetReplicationControllers per namespace %s: %s", namespace, err)
@@ -1011,11 +1193,12 @@ func fetchWorkload(layer *Layer, namespace string, workloadName string, workload
 	go func() {
 		defer wg.Done()
 		// Check if workloadType is passed
-		if workloadType != "" && workloadType != kubernetes.JobType {
+		if workloadType != "" && workloadType != kubernetes.JobType && workloadType != kubernetes.CronJobType {
 			return
 		}
 		var err error
-		if isWorkloadIncluded(kubernetes.JobType) {
+		// Jobs are needed to resolve the CronJobs of their pods
+		if isWorkloadIncluded(kubernetes.JobType) || isWorkloadIncluded(kubernetes.CronJobType) {
 			jbs, err = layer.k8s.GetJobs(namespace)
 			if err != nil {
 				log.Errorf("Error fetching Jobs per namespace %s: %s", namespace, err)
@@ -1030,125 +1213,7 @@ func fetchWorkload(layer *Layer, namespace string, workloadName string, workload
 		return wl, err
 	}
 
-	// Key: name of controller; Value: type of controller
-	controllers := map[string]string{}
-
-	// Find controllers from pods
-	for _, pod := range pods {
-		if len(pod.OwnerReferences) != 0 {
-			for _, ref := range pod.OwnerReferences {
-				if ref.Controller != nil && *ref.Controller {
-					if _, exist := controllers[ref.Name]; !exist {
-						controllers[ref.Name] = ref.Kind
-					} else {
-						if controllers[ref.Name] != ref.Kind {
-							controllers[ref.Name] = controllerPriority(controllers[ref.Name], ref.Kind)
-						}
-					}
-				}
-			}
-		} else {
-			if _, exist := controllers[pod.Name]; !exist {
-				// Pod without controller
-				controllers[pod.Name] = "Pod"
-			}
-		}
-	}
-
-	// Resolve ReplicaSets from Deployments
-	// Resolve ReplicationControllers from DeploymentConfigs
-	// Resolve Jobs from CronJobs
-	for cname, ctype := range controllers {
-		if ctype == kubernetes.ReplicaSetType {
-			found := false
-			iFound := -1
-			for i, rs := range repset {
-				if rs.Name == cname {
-					iFound = i
-					found = true
-					break
-				}
-			}
-			if found && len(repset[iFound].OwnerReferences) > 0 {
-				for _, ref := range repset[iFound].OwnerReferences {
-					if ref.Controller != nil && *ref.Controller {
-						// Delete the child ReplicaSet and add the parent controller
-						if _, exist := controllers[ref.Name]; !exist {
-							controllers[ref.Name] = ref.Kind
-						} else {
-							if controllers[ref.Name] != ref.Kind {
-								controllers[ref.Name] = controllerPriority(controllers[ref.Name], ref.Kind)
-							}
-						}
-						delete(controllers, cname)
-					}
-				}
-			}
-		}
-		if ctype == kubernetes.ReplicationControllerType {
-			found := false
-			iFound := -1
-			for i, rc := range repcon {
-				if rc.Name == cname {
-					iFound = i
-					found = true
-					break
-				}
-			}
-			if found && len(repcon[iFound].OwnerReferences) > 0 {
-				for _, ref := range repcon[iFound].OwnerReferences {
-					if ref.Controller != nil && *ref.Controller {
-						// Delete the child ReplicationController and add the parent controller
-						if _, exist := controllers[ref.Name]; !exist {
-							controllers[ref.Name] = ref.Kind
-						} else {
-							if controllers[ref.Name] != ref.Kind {
-								controllers[ref.Name] = controllerPriority(controllers[ref.Name], ref.Kind)
-							}
-						}
-						delete(controllers, cname)
-					}
-				}
-			}
-		}
-		if ctype == kubernetes.JobType {
-			found := false
-			iFound := -1
-			for i, jb := range jbs {
-				if jb.Name == cname {
-					iFound = i
-					found = true
-					break
-				}
-			}
-			if found && len(jbs[iFound].OwnerReferences) > 0 {
-				for _, ref := range jbs[iFound].OwnerReferences {
-					if ref.Controller != nil && *ref.Controller {
-						// Delete the child Job and add the parent controller
-						if _, exist := controllers[ref.Name]; !exist {
-							controllers[ref.Name] = ref.Kind
-						} else {
-							if controllers[ref.Name] != ref.Kind {
-								controllers[ref.Name] = controllerPriority(controllers[ref.Name], ref.Kind)
-							}
-						}
-						// Jobs are special as deleting CronJob parent doesn't delete children
-						// So we need to check that parent exists before to delete children controller
-						cnExist := false
-						for _, cnj := range conjbs {
-							if cnj.Name == ref.Name {
-								cnExist = true
-								break
-							}
-						}
-						if cnExist {
-							delete(controllers, cname)
-						}
-					}
-				}
-			}
-		}
-	}
+	controllers := resolveControllers(pods, repset, repcon, jbs, conjbs)
 
 	// Cornercase, check for controllers without pods, to show them as a workload
 	if dep != nil {
@@ -1176,6 +1241,12 @@ func fetchWorkload(layer *Layer, namespace string, workloadName string, workload
 			controllers[fulset.Name] = kubernetes.StatefulSetType
 		}
 	}
+	for _, cjb := range conjbs {
+		if _, exist := controllers[cjb.Name]; !exist {
+			controllers[cjb.Name] = kubernetes.CronJobType
+		}
+	}
+	filterExcludedControllers(controllers)
 
 	// Build workload from controllers
 
@@ -1337,6 +1408,9 @@ func fetchWorkload(layer *Layer, namespace string, workloadName string, workload
 			}
 		}
 
+		// Add the node placement to the pods
+		setPodsNodeTopology(layer, w.Pods)
+
 		if cnFound {
 			return &w, nil
 		}
@@ -1344,6 +1418,40 @@ func fetchWorkload(layer *Layer, namespace string, workloadName string, workload
 	return wl, kubernetes.NewNotFound(workloadName, "Kiali", "Workload")
 }
 
+// setPodsNodeTopology fills the zone and region of the pods from the nodes where they run.
+// Nodes are read once per call, pods not scheduled yet are skipped.
+func setPodsNodeTopology(layer *Layer, pods models.Pods) {
+	nodes := make(map[string]*core_v1.Node)
+	for _, pod := range pods {
+		if pod.NodeName == "" {
+			continue
+		}
+		node, found := nodes[pod.NodeName]
+		if !found {
+			node = fetchNode(layer, pod.NodeName)
+			nodes[pod.NodeName] = node
+		}
+		pod.SetNodeTopology(node)
+	}
+}
+
+func fetchNode(layer *Layer, name string) *core_v1.Node {
+	var node *core_v1.Node
+	var err error
+	if kialiCache != nil {
+		node, err = kialiCache.GetNode(name)
+	}
+	// Nodes may not be cached when Kiali is not allowed to watch them
+	if node == nil && err == nil {
+		node, err = layer.k8s.GetNode(name)
+	}
+	if err != nil {
+		log.Debugf("Node [%s] placement info is not available: %s", name, err.Error())
+		return nil
+	}
+	return node
+}
+
 func updateWorkload(layer *Layer, namespace string, workloadName string, workloadType string, jsonPatch string) error {
 	// Check if user has access to the namespace (RBAC) in cache scenarios and/or
 	// if namespace is accessible from Kiali (Deployment.AccessibleNamespaces)
@@ -14,7 +14,9 @@ import (
 	batch_v1beta1 "k8s.io/api/batch/v1beta1"
 	core_v1 "k8s.io/api/core/v1"
 	errors "k8s.io/apimachinery/pkg/api/errors"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
 
 	"github.com/kiali/kiali/config"
 	"github.com/kiali/kiali/kubernetes"
@@ -362,6 +364,131 @@ func TestGetWorkloadFromDeployment(t *testing.T) {
 	assert.Equal(true, workload.VersionLabel)
 }
 
+func TestGetWorkloadNodePlacement(t *testing.T) {
+	assert := assert.New(t)
+
+	pods := FakePodsSyncedWithDeployments()
+	pods[0].Labels = map[string]string{"app": "details", "version": "v1"}
+	pods[0].Spec.NodeName = "node-1"
+	pods[0].Status.PodIP = "10.128.0.10"
+	pending := pods[0]
+	pending.Name = "details-v1-3618568057-pending"
+	pending.Spec.NodeName = ""
+	pending.Status = core_v1.PodStatus{Phase: core_v1.PodPending}
+	pods = append(pods, pending)
+	config.Set(config.NewConfig())
+
+	gr := schema.GroupResource{
+		Group:    "test-group",
+		Resource: "test-resource",
+	}
+	notfound := errors.NewNotFound(gr, "not found")
+	k8s := new(kubetest.K8SClientMock)
+	k8s.On("IsOpenShift").Return(true)
+	k8s.On("GetProject", mock.AnythingOfType("string")).Return(&osproject_v1.Project{}, nil)
+	k8s.On("GetDeployment", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return(&FakeDepSyncedWithRS()[0], nil)
+	k8s.On("GetDeploymentConfig", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return(&osapps_v1.DeploymentConfig{}, notfound)
+	k8s.On("GetReplicaSets", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return([]apps_v1.ReplicaSet{}, nil)
+	k8s.On("GetReplicationControllers", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return([]core_v1.ReplicationController{}, nil)
+	k8s.On("GetStatefulSet", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return(&apps_v1.StatefulSet{}, notfound)
+	k8s.On("GetPods", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return(pods, nil)
+	k8s.On("GetJobs", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return([]batch_v1.Job{}, nil)
+	k8s.On("GetCronJobs", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return([]batch_v1beta1.CronJob{}, nil)
+	k8s.On("GetNode", "node-1").Return(&core_v1.Node{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name: "node-1",
+			Labels: map[string]string{
+				core_v1.LabelTopologyZone:   "us-east-1a",
+				core_v1.LabelTopologyRegion: "us-east-1",
+			},
+		},
+	}, nil)
+
+	svc := setupWorkloadService(k8s)
+
+	workload, err := svc.GetWorkload("Namespace", "details-v1", "", false)
+	assert.NoError(err)
+	assert.Len(workload.Pods, 2)
+
+	assert.Equal("node-1", workload.Pods[0].NodeName)
+	assert.Equal("10.128.0.10", workload.Pods[0].PodIP)
+	assert.Equal("us-east-1a", workload.Pods[0].Zone)
+	assert.Equal("us-east-1", workload.Pods[0].Region)
+
+	// Pending pod is not scheduled on any node yet
+	assert.Equal("Pending", workload.Pods[1].Status)
+	assert.Empty(workload.Pods[1].NodeName)
+	assert.Empty(workload.Pods[1].PodIP)
+	assert.Empty(workload.Pods[1].Zone)
+	k8s.AssertNumberOfCalls(t, "GetNode", 1)
+}
+
+func TestGetWorkloadImages(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	gr := schema.GroupResource{
+		Group:    "test-group",
+		Resource: "test-resource",
+	}
+	notfound := errors.NewNotFound(gr, "not found")
+	k8s := new(kubetest.K8SClientMock)
+	k8s.On("IsOpenShift").Return(true)
+	k8s.On("GetProject", mock.AnythingOfType("string")).Return(&osproject_v1.Project{}, nil)
+	k8s.On("GetDeployment", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return(&FakeDepSyncedWithRS()[0], nil)
+	k8s.On("GetDeploymentConfig", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return(&osapps_v1.DeploymentConfig{}, notfound)
+	k8s.On("GetReplicaSets", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return([]apps_v1.ReplicaSet{}, nil)
+	k8s.On("GetReplicationControllers", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return([]core_v1.ReplicationController{}, nil)
+	k8s.On("GetStatefulSet", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return(&apps_v1.StatefulSet{}, notfound)
+	k8s.On("GetPods", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return(loadPods(t, "../tests/data/workloads/image_pods.yaml"), nil)
+	k8s.On("GetJobs", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return([]batch_v1.Job{}, nil)
+	k8s.On("GetCronJobs", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return([]batch_v1beta1.CronJob{}, nil)
+
+	svc := setupWorkloadService(k8s)
+
+	workload, err := svc.GetWorkload("bookinfo", "details-v1", "", false)
+	assert.NoError(err)
+	assert.Len(workload.Pods, 2)
+
+	images := workload.Images
+	// Sorted by container, without the proxy
+	assert.Len(images.Containers, 3)
+
+	cache := images.Containers[0]
+	assert.Equal("cache", cache.Container)
+	assert.Equal("docker.io", cache.Registry)
+	assert.Equal("library/redis", cache.Repository)
+	assert.Equal("latest", cache.Tag)
+	assert.True(cache.MutableTag)
+	// Still pulling in one of the pods
+	assert.Equal([]string{"sha256:9b2c6e5f8a1d4c7b0e3f6a9d2c5b8e1f4a7d0c3b6e9f2a5d8c1b4e7f0a3d6c9b"}, cache.ResolvedDigests)
+
+	// The latest tag moved between the pulls of the pods
+	details := images.Containers[1]
+	assert.Equal("details", details.Container)
+	assert.Equal("docker.io", details.Registry)
+	assert.Equal("istio/examples-bookinfo-details-v1", details.Repository)
+	assert.True(details.MutableTag)
+	assert.Len(details.ResolvedDigests, 2)
+
+	logShipper := images.Containers[2]
+	assert.Equal("log-shipper", logShipper.Container)
+	assert.Equal("registry.example.com:5000", logShipper.Registry)
+	assert.Equal("tools/fluent-bit", logShipper.Repository)
+	assert.Empty(logShipper.Tag)
+	assert.Equal("sha256:0a8b1fe3ad1a0e4a3fe1d3f93c27a11d8cbb2d4bf4a5a1c46c1c1c15f0e3e0e1", logShipper.Digest)
+	assert.False(logShipper.MutableTag)
+	assert.Equal([]string{logShipper.Digest}, logShipper.ResolvedDigests)
+
+	proxy := images.Proxy
+	assert.NotNil(proxy)
+	assert.Equal("gcr.io", proxy.Registry)
+	assert.Equal("istio-release/proxyv2", proxy.Repository)
+	assert.Equal("1.12.1", proxy.Tag)
+	assert.False(proxy.MutableTag)
+	assert.Equal([]string{"sha256:3a6a5b8d0c6b2f0d5ac1b3f5f5e8b6d4c2e1a0f9e8d7c6b5a4f3e2d1c0b9a8f7"}, proxy.ResolvedDigests)
+}
+
 func TestGetWorkloadFromPods(t *testing.T) {
 	assert := assert.New(t)
 	conf := config.NewConfig()
@@ -584,3 +711,224 @@ func TestDuplicatedControllers(t *testing.T) {
 
 	assert.Equal(workloads[0].Type, workload.Type)
 }
+
+func TestGetPodAccessLogs(t *testing.T) {
+	assert := assert.New(t)
+	conf := config.NewConfig()
+	config.Set(conf)
+
+	proxyLogs := &kubernetes.PodLogs{
+		Logs: `2020-11-25T21:26:18.000000000Z 2020-11-25T21:26:18.000Z	info	Envoy proxy is ready
+2020-11-25T21:26:19.000000000Z [2020-11-25T21:26:19.409Z] "GET /status/418 HTTP/1.1" 418 - via_upstream - "-" 0 135 3 1 "-" "curl/7.73.0-DEV" "84961386-6d84-929d-98bd-c5aee93b5c88" "httpbin:8000" "127.0.0.1:80" inbound|8000|| 127.0.0.1:41854 10.44.1.27:80 10.44.1.23:37652 outbound_.8000_._.httpbin.foo.svc.cluster.local default`,
+	}
+
+	k8s := new(kubetest.K8SClientMock)
+	k8s.On("GetPodLogs", "Namespace", "httpbin-v1-3618568057-dnkjp", mock.MatchedBy(func(opts *core_v1.PodLogOptions) bool {
+		return opts.Container == "istio-proxy"
+	})).Return(proxyLogs, nil)
+	k8s.On("IsOpenShift").Return(false)
+
+	svc := setupWorkloadService(k8s)
+
+	accessLogs, err := svc.GetPodAccessLogs("Namespace", "httpbin-v1-3618568057-dnkjp", &LogOptions{PodLogOptions: core_v1.PodLogOptions{Container: "httpbin"}})
+
+	assert.NoError(err)
+	assert.Len(accessLogs, 1)
+	assert.Equal("/status/418", accessLogs[0].Path)
+	assert.Equal("84961386-6d84-929d-98bd-c5aee93b5c88", accessLogs[0].RequestID)
+
+	// The default options read all the logs of the proxy
+	accessLogs, err = svc.GetPodAccessLogs("Namespace", "httpbin-v1-3618568057-dnkjp", nil)
+
+	assert.NoError(err)
+	assert.Len(accessLogs, 1)
+}
+
+func TestPatchWorkloadMetadata(t *testing.T) {
+	assert := assert.New(t)
+	conf := config.NewConfig()
+	config.Set(conf)
+
+	gr := schema.GroupResource{
+		Group:    "test-group",
+		Resource: "test-resource",
+	}
+	notfound := errors.NewNotFound(gr, "not found")
+	k8s := new(kubetest.K8SClientMock)
+	k8s.On("IsOpenShift").Return(true)
+	k8s.On("GetProject", mock.AnythingOfType("string")).Return(&osproject_v1.Project{}, nil)
+	k8s.On("GetDeployment", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return(&FakeDepSyncedWithRS()[0], nil)
+	k8s.On("GetDeploymentConfig", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return(&osapps_v1.DeploymentConfig{}, notfound)
+	k8s.On("GetReplicaSets", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return([]apps_v1.ReplicaSet{}, nil)
+	k8s.On("GetReplicationControllers", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return([]core_v1.ReplicationController{}, nil)
+	k8s.On("GetStatefulSet", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return(&apps_v1.StatefulSet{}, notfound)
+	k8s.On("GetPods", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return(FakePodsSyncedWithDeployments(), nil)
+	k8s.On("GetJobs", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return([]batch_v1.Job{}, nil)
+	k8s.On("GetCronJobs", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return([]batch_v1beta1.CronJob{}, nil)
+	k8s.On("PatchWorkload", "Namespace", "details-v1", kubernetes.DeploymentType, mock.AnythingOfType("string"), types.StrategicMergePatchType).Return(nil)
+
+	svc := setupWorkloadService(k8s)
+
+	// The metadata of the workload itself, not restarting its pods
+	owner, inject := "team-a", "true"
+	workload, err := svc.PatchWorkloadMetadata("Namespace", "details-v1", kubernetes.DeploymentType,
+		map[string]*string{"owner": &owner, "tmp": nil}, nil, false)
+	assert.NoError(err)
+	assert.Equal("details-v1", workload.Name)
+	expectedPatch := `{"metadata":{"labels":{"owner":"team-a","tmp":null}}}`
+	k8s.AssertCalled(t, "PatchWorkload", "Namespace", "details-v1", kubernetes.DeploymentType, expectedPatch, types.StrategicMergePatchType)
+
+	// The pod template, on demand
+	_, err = svc.PatchWorkloadMetadata("Namespace", "details-v1", kubernetes.DeploymentType,
+		map[string]*string{"owner": &owner, "tmp": nil},
+		map[string]*string{"sidecar.istio.io/inject": &inject}, true)
+	assert.NoError(err)
+	expectedPatch = `{"spec":{"template":{"metadata":{"annotations":{"sidecar.istio.io/inject":"true"},"labels":{"owner":"team-a","tmp":null}}}}}`
+	k8s.AssertCalled(t, "PatchWorkload", "Namespace", "details-v1", kubernetes.DeploymentType, expectedPatch, types.StrategicMergePatchType)
+}
+
+func TestPatchWorkloadMetadataValidation(t *testing.T) {
+	assert := assert.New(t)
+	conf := config.NewConfig()
+	config.Set(conf)
+
+	k8s := new(kubetest.K8SClientMock)
+	k8s.On("IsOpenShift").Return(true)
+	k8s.On("GetProject", mock.AnythingOfType("string")).Return(&osproject_v1.Project{}, nil)
+
+	svc := setupWorkloadService(k8s)
+
+	valid, empty, invalid := "v2", "", "not valid!"
+	cases := map[string]struct {
+		workloadType string
+		labels       map[string]*string
+		annotations  map[string]*string
+		podTemplate  bool
+	}{
+		"nothing to patch":     {kubernetes.DeploymentType, nil, nil, false},
+		"invalid label key":    {kubernetes.DeploymentType, map[string]*string{"bad key": &valid}, nil, false},
+		"invalid label value":  {kubernetes.DeploymentType, map[string]*string{"owner": &invalid}, nil, false},
+		"app label removed":    {kubernetes.DeploymentType, map[string]*string{"app": nil}, nil, true},
+		"version label empty":  {kubernetes.PodType, map[string]*string{"version": &empty}, nil, false},
+		"invalid annotation":   {kubernetes.DeploymentType, nil, map[string]*string{"-bad/key": &valid}, false},
+		"immutable template":   {kubernetes.JobType, map[string]*string{"owner": &valid}, nil, true},
+		"unknown workloadType": {"Unknown", map[string]*string{"owner": &valid}, nil, false},
+	}
+	for name, c := range cases {
+		_, err := svc.PatchWorkloadMetadata("Namespace", "details-v1", c.workloadType, c.labels, c.annotations, c.podTemplate)
+		assert.True(errors.IsBadRequest(err), name)
+	}
+
+	// Required labels can be updated
+	_, err := buildWorkloadMetadataPatch(kubernetes.DeploymentType, map[string]*string{"version": &valid}, nil, true)
+	assert.NoError(err)
+	// The labels of the workload itself are not required
+	_, err = buildWorkloadMetadataPatch(kubernetes.DeploymentType, map[string]*string{"app": nil}, nil, false)
+	assert.NoError(err)
+	// The metadata of a Job can be updated
+	_, err = buildWorkloadMetadataPatch(kubernetes.JobType, map[string]*string{"owner": &valid}, nil, false)
+	assert.NoError(err)
+	k8s.AssertNotCalled(t, "PatchWorkload", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func fakeOwned(name, ownerKind, ownerName string) meta_v1.ObjectMeta {
+	controller := true
+	meta := meta_v1.ObjectMeta{Name: name}
+	if ownerKind != "" {
+		meta.OwnerReferences = []meta_v1.OwnerReference{{Controller: &controller, Kind: ownerKind, Name: ownerName}}
+	}
+	return meta
+}
+
+func TestResolveControllersCollapsesOwnership(t *testing.T) {
+	assert := assert.New(t)
+
+	pods := []core_v1.Pod{
+		{ObjectMeta: fakeOwned("details-v1-79f774bdb9-hgcch", "ReplicaSet", "details-v1-79f774bdb9")},
+		{ObjectMeta: fakeOwned("ratings-v1-1-deploy-x2c4d", "ReplicationController", "ratings-v1-1")},
+		{ObjectMeta: fakeOwned("backup-1634567890-abcde", "Job", "backup-1634567890")},
+		{ObjectMeta: fakeOwned("migrate-xyz12", "Job", "migrate")},
+		{ObjectMeta: fakeOwned("daemon-pod", "DaemonSet", "daemon-controller")},
+		{ObjectMeta: fakeOwned("standalone", "", "")},
+	}
+	repset := []apps_v1.ReplicaSet{{ObjectMeta: fakeOwned("details-v1-79f774bdb9", "Deployment", "details-v1")}}
+	repcon := []core_v1.ReplicationController{{ObjectMeta: fakeOwned("ratings-v1-1", "DeploymentConfig", "ratings-v1")}}
+	jbs := []batch_v1.Job{
+		{ObjectMeta: fakeOwned("backup-1634567890", "CronJob", "backup")},
+		{ObjectMeta: fakeOwned("migrate", "", "")},
+	}
+	conjbs := []batch_v1beta1.CronJob{{ObjectMeta: fakeOwned("backup", "", "")}}
+
+	controllers := resolveControllers(pods, repset, repcon, jbs, conjbs)
+
+	assert.Equal(map[string]string{
+		"details-v1":        kubernetes.DeploymentType,
+		"ratings-v1":        kubernetes.DeploymentConfigType,
+		"backup":            kubernetes.CronJobType,
+		"migrate":           kubernetes.JobType,
+		"daemon-controller": kubernetes.DaemonSetType,
+		"standalone":        kubernetes.PodType,
+	}, controllers)
+}
+
+func TestResolveControllersKeepsJobsOfDeletedCronJob(t *testing.T) {
+	assert := assert.New(t)
+
+	pods := []core_v1.Pod{{ObjectMeta: fakeOwned("backup-1634567890-abcde", "Job", "backup-1634567890")}}
+	jbs := []batch_v1.Job{{ObjectMeta: fakeOwned("backup-1634567890", "CronJob", "backup")}}
+
+	// The CronJob is deleted but its Job is still in the namespace
+	controllers := resolveControllers(pods, nil, nil, jbs, nil)
+
+	assert.Equal(kubernetes.JobType, controllers["backup-1634567890"])
+	assert.Equal(kubernetes.CronJobType, controllers["backup"])
+}
+
+func TestFilterExcludedControllers(t *testing.T) {
+	assert := assert.New(t)
+	defer func(excluded, included map[string]bool) {
+		excludedWorkloads, includedWorkloads = excluded, included
+	}(excludedWorkloads, includedWorkloads)
+
+	newControllers := func() map[string]string {
+		return map[string]string{
+			"details-v1":        kubernetes.DeploymentType,
+			"backup":            kubernetes.CronJobType,
+			"migrate":           kubernetes.JobType,
+			"daemon-controller": kubernetes.DaemonSetType,
+			"standalone":        kubernetes.PodType,
+		}
+	}
+
+	excludedWorkloads = map[string]bool{kubernetes.JobType: true, kubernetes.PodType: true}
+	controllers := newControllers()
+	filterExcludedControllers(controllers)
+	assert.Equal(map[string]string{
+		"details-v1":        kubernetes.DeploymentType,
+		"backup":            kubernetes.CronJobType,
+		"daemon-controller": kubernetes.DaemonSetType,
+	}, controllers)
+
+	// Excluded types are hidden even when included
+	includedWorkloads = map[string]bool{kubernetes.CronJobType: true, kubernetes.JobType: true}
+	controllers = newControllers()
+	filterExcludedControllers(controllers)
+	assert.Equal(map[string]string{"backup": kubernetes.CronJobType}, controllers)
+}
+
+func TestResolveControllersHidesJobsOfExcludedCronJob(t *testing.T) {
+	assert := assert.New(t)
+	defer func(excluded map[string]bool) {
+		excludedWorkloads = excluded
+	}(excludedWorkloads)
+	excludedWorkloads = map[string]bool{kubernetes.CronJobType: true}
+
+	pods := []core_v1.Pod{{ObjectMeta: fakeOwned("backup-1634567890-abcde", "Job", "backup-1634567890")}}
+	jbs := []batch_v1.Job{{ObjectMeta: fakeOwned("backup-1634567890", "CronJob", "backup")}}
+
+	// Excluded CronJobs are not fetched: their Jobs are collapsed, then hidden with them
+	controllers := resolveControllers(pods, nil, nil, jbs, nil)
+	filterExcludedControllers(controllers)
+
+	assert.Empty(controllers)
+}
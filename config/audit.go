@@ -0,0 +1,24 @@
+package config
+
+import "fmt"
+
+// The sinks where the audit records of the mutations performed through Kiali are written
+const (
+	AuditLogSinkEvent = "event" // A Kubernetes Event on the mutated object
+	AuditLogSinkLog   = "log"   // A structured entry of the Kiali log
+)
+
+// ValidateAuditLogSinks checks that the audit log sinks are supported and not repeated
+func (s Server) ValidateAuditLogSinks() error {
+	found := map[string]bool{}
+	for _, sink := range s.AuditLogSinks {
+		if sink != AuditLogSinkEvent && sink != AuditLogSinkLog {
+			return fmt.Errorf("audit log: sink [%s] is not supported, valid sinks are [%s] and [%s]", sink, AuditLogSinkLog, AuditLogSinkEvent)
+		}
+		if found[sink] {
+			return fmt.Errorf("audit log: sink [%s] is repeated", sink)
+		}
+		found[sink] = true
+	}
+	return nil
+}
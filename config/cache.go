@@ -0,0 +1,46 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+// CacheResyncTypes are the resource types cached by Kiali whose informers resync period can be set
+var CacheResyncTypes = []string{
+	"ConfigMap", "Deployment", "Endpoints", "EndpointSlice", "Node", "Pod", "ReplicaSet", "Service", "StatefulSet",
+	"AuthorizationPolicy", "DestinationRule", "Gateway", "PeerAuthentication", "RequestAuthentication", "ServiceEntry",
+	"Sidecar", "VirtualService",
+}
+
+// MinCacheResyncPeriod is the shortest resync period, in seconds, accepted for a resource type.
+// Shorter periods replay every cached object so often that the handlers barely do anything else.
+const MinCacheResyncPeriod = 10
+
+// ValidateCacheResyncPeriods checks that the resync periods are set for cached resource types, and are either
+// disabled (0) or not shorter than MinCacheResyncPeriod
+func (k KubernetesConfig) ValidateCacheResyncPeriods() error {
+	for resourceType, period := range k.CacheResyncPeriods {
+		supported := false
+		for _, cacheType := range CacheResyncTypes {
+			if resourceType == cacheType {
+				supported = true
+				break
+			}
+		}
+		if !supported {
+			return fmt.Errorf("cache resync periods: resource type [%s] is not cached, valid types are %v", resourceType, CacheResyncTypes)
+		}
+		if period < 0 || (period > 0 && period < MinCacheResyncPeriod) {
+			return fmt.Errorf("cache resync periods: period [%d] of resource type [%s] must be 0 (disabled) or at least %d seconds", period, resourceType, MinCacheResyncPeriod)
+		}
+	}
+	return nil
+}
+
+// CacheResyncPeriod returns the resync period of the informers of a resource type, CacheDuration when not set
+func (k KubernetesConfig) CacheResyncPeriod(resourceType string) time.Duration {
+	if period, ok := k.CacheResyncPeriods[resourceType]; ok {
+		return time.Duration(period) * time.Second
+	}
+	return time.Duration(k.CacheDuration) * time.Second
+}
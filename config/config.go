@@ -71,19 +71,49 @@ var rwMutex sync.RWMutex
 
 // Server configuration
 type Server struct {
-	Address                    string `yaml:",omitempty"`
-	AuditLog                   bool   `yaml:"audit_log,omitempty"` // When true, allows additional audit logging on Write operations
-	CORSAllowAll               bool   `yaml:"cors_allow_all,omitempty"`
-	GzipEnabled                bool   `yaml:"gzip_enabled,omitempty"`
-	MetricsEnabled             bool   `yaml:"metrics_enabled,omitempty"`
-	MetricsPort                int    `yaml:"metrics_port,omitempty"`
-	Port                       int    `yaml:",omitempty"`
-	StaticContentRootDirectory string `yaml:"static_content_root_directory,omitempty"`
-	WebFQDN                    string `yaml:"web_fqdn,omitempty"`
-	WebPort                    string `yaml:"web_port,omitempty"`
-	WebRoot                    string `yaml:"web_root,omitempty"`
-	WebHistoryMode             string `yaml:"web_history_mode,omitempty"`
-	WebSchema                  string `yaml:"web_schema,omitempty"`
+	Address                    string                `yaml:",omitempty"`
+	AuditLog                   bool                  `yaml:"audit_log,omitempty"`       // When true, allows additional audit logging on Write operations
+	AuditLogSinks              []string              `yaml:"audit_log_sinks,omitempty"` // Where the audit records are written: "log" and/or "event"
+	CORSAllowAll               bool                  `yaml:"cors_allow_all,omitempty"`
+	GraphCache                 GraphCacheConfig      `yaml:"graph_cache,omitempty"`
+	GzipEnabled                bool                  `yaml:"gzip_enabled,omitempty"`
+	MeshMetrics                MeshMetricsConfig     `yaml:"mesh_metrics,omitempty"`
+	MetricsEnabled             bool                  `yaml:"metrics_enabled,omitempty"`
+	MetricsPort                int                   `yaml:"metrics_port,omitempty"`
+	Port                       int                   `yaml:",omitempty"`
+	StaleWhileError            StaleWhileErrorConfig `yaml:"stale_while_error,omitempty"`
+	StaticContentRootDirectory string                `yaml:"static_content_root_directory,omitempty"`
+	WebFQDN                    string                `yaml:"web_fqdn,omitempty"`
+	WebPort                    string                `yaml:"web_port,omitempty"`
+	WebRoot                    string                `yaml:"web_root,omitempty"`
+	WebHistoryMode             string                `yaml:"web_history_mode,omitempty"`
+	WebSchema                  string                `yaml:"web_schema,omitempty"`
+}
+
+// MeshMetricsConfig defines the mesh metrics periodically computed by Kiali and exposed on the metrics endpoint.
+// Metrics lists the computed metrics, supported values are "validations" and "mtls".
+type MeshMetricsConfig struct {
+	Enabled         bool     `yaml:"enabled"`
+	Metrics         []string `yaml:"metrics,omitempty"`
+	RefreshInterval int      `yaml:"refresh_interval,omitempty"` // in seconds
+}
+
+// GraphCacheConfig defines the background computation of the namespaces graphs. The graphs viewed within the
+// inactivity period are recomputed every refresh interval and served from the Kiali cache while younger than the TTL.
+// It requires the Kiali cache to be enabled.
+type GraphCacheConfig struct {
+	Enabled          bool `yaml:"enabled"`
+	InactivityPeriod int  `yaml:"inactivity_period,omitempty"` // in seconds
+	RefreshInterval  int  `yaml:"refresh_interval,omitempty"`  // in seconds
+	TTL              int  `yaml:"ttl,omitempty"`               // in seconds
+}
+
+// StaleWhileErrorConfig defines the serving of the last-known-good responses of the health, metrics and Istio config
+// APIs when their backend (Prometheus or the API server) fails. The stale responses carry their age in the Age header
+// and a 110 Warning header. Responses older than MaxStaleAge are not served, the error is returned instead.
+type StaleWhileErrorConfig struct {
+	Enabled     bool `yaml:"enabled"`
+	MaxStaleAge int  `yaml:"max_stale_age,omitempty"` // in seconds
 }
 
 // Auth provides authentication data for external services
@@ -112,8 +142,15 @@ type PrometheusConfig struct {
 	// Enable cache for Prometheus queries
 	CacheEnabled bool `yaml:"cache_enabled,omitempty"`
 	// Global cache expiration expressed in seconds
-	CacheExpiration int    `yaml:"cache_expiration:omitempty"`
-	URL             string `yaml:"url,omitempty"`
+	CacheExpiration int `yaml:"cache_expiration:omitempty"`
+	// Maximum number of in-flight queries to Prometheus, 0 means unlimited
+	MaxConcurrentQueries int `yaml:"max_concurrent_queries,omitempty"`
+	// Time to wait for an in-flight slot before failing a query, expressed in seconds
+	QueryQueueTimeout int `yaml:"query_queue_timeout,omitempty"`
+	// External label of the series federated from several Prometheus, e.g. prometheus or cluster. The identical
+	// series only differing by this label are counted once.
+	DedupLabel string `yaml:"dedup_label,omitempty"`
+	URL        string `yaml:"url,omitempty"`
 }
 
 // CustomDashboardsConfig describes configuration specific to Custom Dashboards
@@ -124,19 +161,45 @@ type CustomDashboardsConfig struct {
 	IsCoreComponent        bool             `yaml:"is_core_component,omitempty"`
 	NamespaceLabel         string           `yaml:"namespace_label,omitempty"`
 	Prometheus             PrometheusConfig `yaml:"prometheus,omitempty"`
+	// WorkloadDashboards are dashboards of custom metrics exposed by the workloads
+	WorkloadDashboards []WorkloadDashboardConfig `yaml:"workload_dashboards,omitempty"`
+}
+
+// WorkloadDashboardConfig describes a dashboard of custom metrics available for every workload
+type WorkloadDashboardConfig struct {
+	Name    string                 `yaml:"name"`
+	Title   string                 `yaml:"title,omitempty"`
+	Metrics []WorkloadMetricConfig `yaml:"metrics"`
+}
+
+// WorkloadMetricConfig describes a chart of a workload dashboard.
+// Labels values may use the ${namespace} and ${workload} variables and start with a
+// Prometheus matching operator (=~, != or !~), the equality operator is used by default.
+type WorkloadMetricConfig struct {
+	Aggregator  string            `yaml:"aggregator,omitempty"` // sum by default
+	DataType    string            `yaml:"data_type,omitempty"`  // raw (default) or rate
+	GroupLabels []string          `yaml:"group_labels,omitempty"`
+	Labels      map[string]string `yaml:"labels,omitempty"`
+	MetricName  string            `yaml:"metric_name"`
+	Title       string            `yaml:"title,omitempty"`
+	Unit        string            `yaml:"unit,omitempty"`
 }
 
 // GrafanaConfig describes configuration used for Grafana links
 type GrafanaConfig struct {
-	Auth            Auth                     `yaml:"auth"`
-	Dashboards      []GrafanaDashboardConfig `yaml:"dashboards"`
-	Enabled         bool                     `yaml:"enabled"` // Enable or disable Grafana support in Kiali
-	InClusterURL    string                   `yaml:"in_cluster_url"`
-	IsCoreComponent bool                     `yaml:"is_core_component"`
-	URL             string                   `yaml:"url"`
+	Auth       Auth                     `yaml:"auth"`
+	Dashboards []GrafanaDashboardConfig `yaml:"dashboards"`
+	// Dashboards lookups in the Grafana API are cached for DashboardsCacheDuration seconds
+	DashboardsCacheDuration int    `yaml:"dashboards_cache_duration,omitempty"`
+	Enabled                 bool   `yaml:"enabled"` // Enable or disable Grafana support in Kiali
+	InClusterURL            string `yaml:"in_cluster_url"`
+	IsCoreComponent         bool   `yaml:"is_core_component"`
+	URL                     string `yaml:"url"`
 }
 
 type GrafanaDashboardConfig struct {
+	// Folder groups the dashboard links, dashboards without folder are listed in the "General" folder
+	Folder    string                 `yaml:"folder,omitempty"`
 	Name      string                 `yaml:"name"`
 	Variables GrafanaVariablesConfig `yaml:"variables"`
 }
@@ -151,25 +214,35 @@ type GrafanaVariablesConfig struct {
 
 // TracingConfig describes configuration used for tracing links
 type TracingConfig struct {
-	Auth                 Auth     `yaml:"auth"`
-	Enabled              bool     `yaml:"enabled"` // Enable Jaeger in Kiali
-	InClusterURL         string   `yaml:"in_cluster_url"`
-	IsCoreComponent      bool     `yaml:"is_core_component"`
-	NamespaceSelector    bool     `yaml:"namespace_selector"`
-	URL                  string   `yaml:"url"`
-	UseGRPC              bool     `yaml:"use_grpc"`
-	WhiteListIstioSystem []string `yaml:"whitelist_istio_system"`
+	Auth                 Auth              `yaml:"auth"`
+	ClusterURLs          map[string]string `yaml:"cluster_urls"` // Tracing URL per cluster name, in multicluster meshes with a tracing backend per cluster
+	Enabled              bool              `yaml:"enabled"`      // Enable Jaeger in Kiali
+	InClusterURL         string            `yaml:"in_cluster_url"`
+	IsCoreComponent      bool              `yaml:"is_core_component"`
+	NamespaceSelector    bool              `yaml:"namespace_selector"`
+	URL                  string            `yaml:"url"`
+	UseGRPC              bool              `yaml:"use_grpc"`
+	WhiteListIstioSystem []string          `yaml:"whitelist_istio_system"`
 }
 
 // IstioConfig describes configuration used for istio links
 type IstioConfig struct {
+	// Regular expression with named groups used to parse the proxy access logs, the default Istio format is used when empty
+	AccessLogPattern         string            `yaml:"access_log_pattern,omitempty"`
 	ComponentStatuses        ComponentStatuses `yaml:"component_status,omitempty"`
 	ConfigMapName            string            `yaml:"config_map_name,omitempty"`
-	EnvoyAdminLocalPort      int               `yaml:"envoy_admin_local_port,omitempty"`
+	EnvoyAdminLocalPort      int               `yaml:"envoy_admin_local_port,omitempty"` // Local port of the forwardings to the Envoy admin, picked by the system when 0
+	ExternalIstiod           bool              `yaml:"external_istiod,omitempty"`        // When true, istiod runs outside the cluster and its status is read from UrlServiceVersion
 	IstioIdentityDomain      string            `yaml:"istio_identity_domain,omitempty"`
 	IstioInjectionAnnotation string            `yaml:"istio_injection_annotation,omitempty"`
 	IstioSidecarAnnotation   string            `yaml:"istio_sidecar_annotation,omitempty"`
-	UrlServiceVersion        string            `yaml:"url_service_version"`
+	// Maximum number of proxies whose log level is changed at once, each of them through a port forwarding
+	ProxyLogLevelMaxConcurrency int `yaml:"proxy_log_level_max_concurrency,omitempty"`
+	// How often the namespace defaults of the proxy log level are applied to the new pods, in seconds
+	ProxyLogLevelReconcileInterval int `yaml:"proxy_log_level_reconcile_interval,omitempty"`
+	// Names of the metrics and labels used by a custom telemetry, the Istio standard names are used when not mapped
+	TelemetryMapping  TelemetryMapping `yaml:"telemetry_mapping,omitempty"`
+	UrlServiceVersion string           `yaml:"url_service_version"`
 }
 
 type ComponentStatuses struct {
@@ -197,6 +270,7 @@ type Extensions struct {
 
 // ExternalServices holds configurations for other systems that Kiali depends on
 type ExternalServices struct {
+	CredentialsStore CredentialsStoreConfig `yaml:"credentials_store,omitempty"`
 	Grafana          GrafanaConfig          `yaml:"grafana,omitempty"`
 	Istio            IstioConfig            `yaml:"istio,omitempty"`
 	Prometheus       PrometheusConfig       `yaml:"prometheus,omitempty"`
@@ -204,6 +278,18 @@ type ExternalServices struct {
 	Tracing          TracingConfig          `yaml:"tracing,omitempty"`
 }
 
+// CredentialsStoreConfig defines the backend the credentials of Prometheus, Tracing and Grafana are read from, instead
+// of the config. Supported types are "kubernetes_secret" and "env", other backends can be registered. The credentials
+// of the config are used when the type is empty.
+type CredentialsStoreConfig struct {
+	Type string `yaml:"type,omitempty"`
+	// The Secret holding the credentials, for the kubernetes_secret type, in the Kiali deployment namespace by default
+	SecretName      string `yaml:"secret_name,omitempty"`
+	SecretNamespace string `yaml:"secret_namespace,omitempty"`
+	// Interval between two reads of the credentials, to pick up rotated credentials
+	RefreshInterval int `yaml:"refresh_interval,omitempty"` // in seconds
+}
+
 // LoginToken holds config used for generating the Kiali session tokens.
 type LoginToken struct {
 	ExpirationSeconds int64  `yaml:"expiration_seconds,omitempty"`
@@ -228,6 +314,14 @@ type AdditionalDisplayItem struct {
 	Title          string `yaml:"title"`
 }
 
+// ConfigTemplate is a named set of Istio objects to apply when onboarding a namespace. The objects are a multi-document
+// YAML where the ${NAMESPACE} placeholder is replaced by the target namespace.
+type ConfigTemplate struct {
+	Description string `yaml:"description,omitempty"`
+	Name        string `yaml:"name"`
+	Objects     string `yaml:"objects"`
+}
+
 // KubernetesConfig holds the k8s client, caching and performance configuration
 type KubernetesConfig struct {
 	Burst int `yaml:"burst,omitempty"`
@@ -241,15 +335,24 @@ type KubernetesConfig struct {
 	CacheIstioTypes []string `yaml:"cache_istio_types,omitempty"`
 	// List of namespaces or regex defining namespaces to include in a cache
 	CacheNamespaces []string `yaml:"cache_namespaces,omitempty"`
+	// Resync period expressed in seconds of the cache informers per resource type, like Pod or Gateway
+	// The types not listed resync every CacheDuration, a 0 period disables the resync of a type
+	CacheResyncPeriods map[string]int `yaml:"cache_resync_periods,omitempty"`
 	// Cache duration expressed in seconds
 	// Kiali cache list of namespaces per user, this is typically short lived cache compared with the duration of the
 	// namespace cache defined by previous CacheDuration parameter
 	CacheTokenNamespaceDuration int `yaml:"cache_token_namespace_duration,omitempty"`
+	// Name of the cluster Kiali runs in, reported in the audit records
+	ClusterName string `yaml:"cluster_name,omitempty"`
 	// List of controllers that won't be used for Workload calculation
 	// Kiali queries Deployment,ReplicaSet,ReplicationController,DeploymentConfig,StatefulSet,Job and CronJob controllers
 	// Deployment and ReplicaSet will be always queried, but ReplicationController,DeploymentConfig,StatefulSet,Job and CronJobs
 	// can be skipped from Kiali workloads query if they are present in this list
 	ExcludeWorkloads []string `yaml:"excluded_workloads,omitempty"`
+	// List of the only workload types listed by Kiali, like Deployment, StatefulSet, DaemonSet, ReplicaSet, Job, CronJob
+	// or Pod (for pods without controller). When empty, all the types not excluded by ExcludeWorkloads are listed.
+	// The pods of a hidden workload are hidden with it.
+	IncludeWorkloads []string `yaml:"included_workloads,omitempty"`
 	QPS              float32  `yaml:"qps,omitempty"`
 }
 
@@ -333,9 +436,75 @@ type Rate struct {
 	Tolerance []Tolerance `yaml:"tolerance,omitempty" json:"tolerance"`
 }
 
+// StaleWorkloadsConfig defines how workloads without traffic are detected.
+// Durations use the Prometheus format (i.e. 30m, 12h, 7d).
+type StaleWorkloadsConfig struct {
+	// History is how far back traffic is looked for to find when a workload was last seen
+	History string `yaml:"history,omitempty" json:"history,omitempty"`
+	// Workloads with this label set to "true" are intentionally idle and never reported
+	IdleLabel string `yaml:"idle_label,omitempty" json:"idleLabel,omitempty"`
+	// Window without traffic after which a workload is stale
+	Window string `yaml:"window,omitempty" json:"window,omitempty"`
+}
+
+// MaintenanceConfig defines how workloads are put in maintenance. The degraded and failure health of a workload
+// in maintenance is suppressed until the maintenance expires.
+type MaintenanceConfig struct {
+	// Annotation set on the workloads in maintenance, its value is the end of the maintenance (RFC3339)
+	Annotation string `yaml:"annotation,omitempty" json:"annotation,omitempty"`
+	// Duration of the maintenance when none is requested, in the Prometheus format (i.e. 30m, 2h)
+	Duration string `yaml:"duration,omitempty" json:"duration,omitempty"`
+}
+
+// CustomResourceHealthConfig defines a kind of custom resource whose health is read from one of its status conditions,
+// like the Ready condition set by the operator managing the resources.
+type CustomResourceHealthConfig struct {
+	Group   string `yaml:"group" json:"group"`
+	Version string `yaml:"version" json:"version"`
+	Kind    string `yaml:"kind" json:"kind"`
+	// Resource is the plural name of the kind in the API. Defaults to the lowercase kind followed by "s".
+	Resource string `yaml:"resource,omitempty" json:"resource,omitempty"`
+	// Condition is the type of the status condition reporting the health. Defaults to "Ready".
+	Condition string `yaml:"condition,omitempty" json:"condition,omitempty"`
+}
+
+// QueryLimitsConfig caps the time range and the resolution of the metrics and traces queries, to protect Prometheus
+// and the tracing backend from accidental huge queries. Durations use the Prometheus format (i.e. 30s, 12h, 30d),
+// an empty duration disables the cap.
+type QueryLimitsConfig struct {
+	// Clamp the queries exceeding a cap to the cap, instead of rejecting them
+	Clamp bool `yaml:"clamp,omitempty" json:"clamp,omitempty"`
+	// MaxRange is the longest time range of a metrics or traces query
+	MaxRange string `yaml:"max_range,omitempty" json:"maxRange,omitempty"`
+	// MaxPoints is the most datapoints of a series of a metrics query: the step of the longer ranges is increased to
+	// fit, and their downsampled series are cached. 0 disables the downsampling.
+	MaxPoints int `yaml:"max_points,omitempty" json:"maxPoints,omitempty"`
+	// MinStep is the finest resolution of a metrics query
+	MinStep string `yaml:"min_step,omitempty" json:"minStep,omitempty"`
+}
+
+// JwksFetchConfig defines the checks of the jwksUri of the RequestAuthentications. Kiali then makes requests to the
+// URLs of the Istio config, so they are only made to the allowed hosts, over HTTPS unless HTTP is allowed, and out of
+// the validation requests: a result is reported once the JWKS was fetched in background.
+type JwksFetchConfig struct {
+	// AllowHTTP allows fetching the JWKS over HTTP
+	AllowHTTP bool `yaml:"allow_http,omitempty" json:"allowHttp,omitempty"`
+	// AllowedHosts are the hosts where the JWKS can be fetched. A "*." prefix matches any subdomain.
+	AllowedHosts []string `yaml:"allowed_hosts,omitempty" json:"allowedHosts,omitempty"`
+	Enabled      bool     `yaml:"enabled,omitempty" json:"enabled,omitempty"`
+}
+
+// ValidationsConfig defines the optional checks of the Istio config validations
+type ValidationsConfig struct {
+	JwksFetch JwksFetchConfig `yaml:"jwks_fetch,omitempty" json:"jwksFetch,omitempty"`
+}
+
 // HealthConfig rates
 type HealthConfig struct {
-	Rate []Rate `yaml:"rate,omitempty" json:"rate,omitempty"`
+	CustomResources []CustomResourceHealthConfig `yaml:"custom_resources,omitempty" json:"customResources,omitempty"`
+	Maintenance     MaintenanceConfig            `yaml:"maintenance,omitempty" json:"maintenance,omitempty"`
+	Rate            []Rate                       `yaml:"rate,omitempty" json:"rate,omitempty"`
+	StaleWorkloads  StaleWorkloadsConfig         `yaml:"stale_workloads,omitempty" json:"staleWorkloads,omitempty"`
 }
 
 // Config defines full YAML configuration.
@@ -343,6 +512,7 @@ type Config struct {
 	AdditionalDisplayDetails []AdditionalDisplayItem  `yaml:"additional_display_details,omitempty"`
 	API                      ApiConfig                `yaml:"api,omitempty"`
 	Auth                     AuthConfig               `yaml:"auth,omitempty"`
+	ConfigTemplates          []ConfigTemplate         `yaml:"config_templates,omitempty"`
 	Deployment               DeploymentConfig         `yaml:"deployment,omitempty"`
 	Extensions               Extensions               `yaml:"extensions,omitempty"`
 	ExternalServices         ExternalServices         `yaml:"external_services,omitempty"`
@@ -356,7 +526,9 @@ type Config struct {
 	KialiFeatureFlags        KialiFeatureFlags        `yaml:"kiali_feature_flags,omitempty"`
 	KubernetesConfig         KubernetesConfig         `yaml:"kubernetes_config,omitempty"`
 	LoginToken               LoginToken               `yaml:"login_token,omitempty"`
+	QueryLimits              QueryLimitsConfig        `yaml:"query_limits,omitempty"`
 	Server                   Server                   `yaml:",omitempty"`
+	Validations              ValidationsConfig        `yaml:"validations,omitempty"`
 }
 
 // NewConfig creates a default Config struct
@@ -405,6 +577,9 @@ func NewConfig() (c *Config) {
 			},
 		},
 		ExternalServices: ExternalServices{
+			CredentialsStore: CredentialsStoreConfig{
+				RefreshInterval: 60,
+			},
 			CustomDashboards: CustomDashboardsConfig{
 				DiscoveryEnabled:       DashboardsDiscoveryAuto,
 				DiscoveryAutoThreshold: 10,
@@ -415,8 +590,9 @@ func NewConfig() (c *Config) {
 				Auth: Auth{
 					Type: AuthTypeNone,
 				},
-				Enabled:         true,
-				IsCoreComponent: false,
+				DashboardsCacheDuration: 300,
+				Enabled:                 true,
+				IsCoreComponent:         false,
 			},
 			Istio: IstioConfig{
 				ComponentStatuses: ComponentStatuses{
@@ -436,12 +612,13 @@ func NewConfig() (c *Config) {
 						},
 					},
 				},
-				ConfigMapName:            "istio",
-				EnvoyAdminLocalPort:      15000,
-				IstioIdentityDomain:      "svc.cluster.local",
-				IstioInjectionAnnotation: "sidecar.istio.io/inject",
-				IstioSidecarAnnotation:   "sidecar.istio.io/status",
-				UrlServiceVersion:        "http://istiod:15014/version",
+				ConfigMapName:                  "istio",
+				IstioIdentityDomain:            "svc.cluster.local",
+				IstioInjectionAnnotation:       "sidecar.istio.io/inject",
+				IstioSidecarAnnotation:         "sidecar.istio.io/status",
+				ProxyLogLevelMaxConcurrency:    10,
+				ProxyLogLevelReconcileInterval: 60,
+				UrlServiceVersion:              "http://istiod:15014/version",
 			},
 			Prometheus: PrometheusConfig{
 				Auth: Auth{
@@ -451,8 +628,10 @@ func NewConfig() (c *Config) {
 				// 1/2 Prom Scrape Interval
 				CacheDuration: 7,
 				// Prom Cache expires and it forces to repopulate cache
-				CacheExpiration: 300,
-				URL:             "http://prometheus.istio-system:9090",
+				CacheExpiration:      300,
+				MaxConcurrentQueries: 100,
+				QueryQueueTimeout:    10,
+				URL:                  "http://prometheus.istio-system:9090",
 			},
 			Tracing: TracingConfig{
 				Auth: Auth{
@@ -467,6 +646,17 @@ func NewConfig() (c *Config) {
 				WhiteListIstioSystem: []string{"jaeger-query", "istio-ingressgateway"},
 			},
 		},
+		HealthConfig: HealthConfig{
+			Maintenance: MaintenanceConfig{
+				Annotation: "kiali.io/maintenance-until",
+				Duration:   "1h",
+			},
+			StaleWorkloads: StaleWorkloadsConfig{
+				History:   "7d",
+				IdleLabel: "kiali.io/idle",
+				Window:    "1d",
+			},
+		},
 		IstioLabels: IstioLabels{
 			AppLabelName:       "app",
 			InjectionLabelName: "istio-injection",
@@ -486,6 +676,7 @@ func NewConfig() (c *Config) {
 			CacheEnabled:                true,
 			CacheIstioTypes:             []string{"DestinationRule", "Gateway", "ServiceEntry", "VirtualService", "Sidecar", "PeerAuthentication", "RequestAuthentication", "AuthorizationPolicy"},
 			CacheNamespaces:             []string{".*"},
+			CacheResyncPeriods:          map[string]int{"Endpoints": 60, "EndpointSlice": 60, "Pod": 60},
 			CacheTokenNamespaceDuration: 10,
 			ExcludeWorkloads:            []string{"CronJob", "DeploymentConfig", "Job", "ReplicationController"},
 			QPS:                         175,
@@ -494,8 +685,14 @@ func NewConfig() (c *Config) {
 			ExpirationSeconds: 24 * 3600,
 			SigningKey:        "kiali",
 		},
+		QueryLimits: QueryLimitsConfig{
+			MaxPoints: 1000,
+			MaxRange:  "30d",
+			MinStep:   "1s",
+		},
 		Server: Server{
 			AuditLog:                   true,
+			AuditLogSinks:              []string{AuditLogSinkLog},
 			GzipEnabled:                true,
 			MetricsEnabled:             true,
 			MetricsPort:                9090,
@@ -505,6 +702,28 @@ func NewConfig() (c *Config) {
 			WebRoot:                    "/",
 			WebHistoryMode:             "browser",
 			WebSchema:                  "",
+			GraphCache: GraphCacheConfig{
+				Enabled:          false,
+				InactivityPeriod: 300,
+				RefreshInterval:  60,
+				TTL:              120,
+			},
+			MeshMetrics: MeshMetricsConfig{
+				Enabled:         false,
+				Metrics:         []string{"validations", "mtls"},
+				RefreshInterval: 60,
+			},
+			StaleWhileError: StaleWhileErrorConfig{
+				Enabled:     false,
+				MaxStaleAge: 600,
+			},
+		},
+		Validations: ValidationsConfig{
+			JwksFetch: JwksFetchConfig{
+				AllowHTTP:    false,
+				AllowedHosts: []string{},
+				Enabled:      false,
+			},
 		},
 	}
 
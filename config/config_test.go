@@ -5,6 +5,7 @@ import (
 	"os"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -236,3 +237,76 @@ func TestRaces(t *testing.T) {
 
 	wg.Wait()
 }
+
+func TestTelemetryMapping(t *testing.T) {
+	mapping := TelemetryMapping{
+		Metrics: map[string]string{"istio_requests_total": "mesh_requests_total"},
+		Labels:  map[string]string{"source_workload": "src_wl"},
+	}
+	if err := mapping.Validate(); err != nil {
+		t.Fatalf("Valid mapping failed validation: %v", err)
+	}
+	if mapping.Metric("istio_requests_total") != "mesh_requests_total" || mapping.Metric("istio_tcp_sent_bytes_total") != "istio_tcp_sent_bytes_total" {
+		t.Errorf("Metric names are not mapped")
+	}
+	if mapping.Label("source_workload") != "src_wl" || mapping.StandardLabel("src_wl") != "source_workload" || mapping.Label("reporter") != "reporter" {
+		t.Errorf("Labels are not mapped")
+	}
+	query := `sum(rate(istio_requests_total{reporter="source",source_workload="source_workload"}[60s])) by (source_workload) > 0.001`
+	expected := `sum(rate(mesh_requests_total{reporter="source",src_wl="source_workload"}[60s])) by (src_wl) > 0.001`
+	if mapped := mapping.Query(query); mapped != expected {
+		t.Errorf("Query is not mapped: %s", mapped)
+	}
+
+	invalid := []TelemetryMapping{
+		{Metrics: map[string]string{"istio_unknown_total": "mesh_unknown_total"}},
+		{Metrics: map[string]string{"istio_requests_total": ""}},
+		{Labels: map[string]string{"source_workload": "src-wl"}},
+		{Labels: map[string]string{"source_workload": "destination_workload"}},
+	}
+	for _, m := range invalid {
+		if err := m.Validate(); err == nil {
+			t.Errorf("Invalid mapping passed validation: %v", m)
+		}
+	}
+}
+
+func TestAuditLogSinks(t *testing.T) {
+	for _, sinks := range [][]string{nil, {AuditLogSinkLog}, {AuditLogSinkEvent, AuditLogSinkLog}} {
+		if err := (Server{AuditLogSinks: sinks}).ValidateAuditLogSinks(); err != nil {
+			t.Errorf("Valid audit log sinks %v failed validation: %v", sinks, err)
+		}
+	}
+	for _, sinks := range [][]string{{"file"}, {AuditLogSinkLog, AuditLogSinkLog}} {
+		if err := (Server{AuditLogSinks: sinks}).ValidateAuditLogSinks(); err == nil {
+			t.Errorf("Invalid audit log sinks %v passed validation", sinks)
+		}
+	}
+}
+
+func TestCacheResyncPeriods(t *testing.T) {
+	conf, err := Unmarshal("kubernetes_config:\n  cache_duration: 600\n  cache_resync_periods:\n    Gateway: 3600\n    Pod: 0\n")
+	if err != nil {
+		t.Fatalf("Failed to unmarshal the config: %v", err)
+	}
+	k := conf.KubernetesConfig
+	if err := k.ValidateCacheResyncPeriods(); err != nil {
+		t.Errorf("Valid cache resync periods %v failed validation: %v", k.CacheResyncPeriods, err)
+	}
+	for resourceType, expected := range map[string]time.Duration{
+		"Gateway":       time.Hour,
+		"Pod":           0,
+		"EndpointSlice": time.Minute,
+		"Deployment":    10 * time.Minute,
+	} {
+		if period := k.CacheResyncPeriod(resourceType); period != expected {
+			t.Errorf("Resync period of [%s] is [%v], expected [%v]", resourceType, period, expected)
+		}
+	}
+
+	for _, periods := range []map[string]int{{"Secret": 60}, {"Pod": -1}, {"Gateway": MinCacheResyncPeriod - 1}} {
+		if err := (KubernetesConfig{CacheResyncPeriods: periods}).ValidateCacheResyncPeriods(); err == nil {
+			t.Errorf("Invalid cache resync periods %v passed validation", periods)
+		}
+	}
+}
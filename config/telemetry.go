@@ -0,0 +1,197 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// TelemetryMapping maps the metrics and labels of the Istio standard telemetry to the names used by a mesh
+// customizing its telemetry, e.g. through the Telemetry API. Keys are the Istio standard names, values the names
+// actually found in Prometheus.
+type TelemetryMapping struct {
+	Labels  map[string]string `yaml:"labels,omitempty"`
+	Metrics map[string]string `yaml:"metrics,omitempty"`
+}
+
+// IstioStandardMetrics are the Istio standard metrics which can be mapped
+var IstioStandardMetrics = []string{
+	"istio_request_bytes",
+	"istio_request_duration_milliseconds",
+	"istio_requests_total",
+	"istio_response_bytes",
+	"istio_tcp_connections_closed_total",
+	"istio_tcp_connections_opened_total",
+	"istio_tcp_received_bytes_total",
+	"istio_tcp_sent_bytes_total",
+}
+
+// IstioStandardLabels are the labels of the Istio standard metrics which can be mapped
+var IstioStandardLabels = []string{
+	"connection_security_policy",
+	"destination_app",
+	"destination_canonical_revision",
+	"destination_canonical_service",
+	"destination_cluster",
+	"destination_principal",
+	"destination_service",
+	"destination_service_name",
+	"destination_service_namespace",
+	"destination_version",
+	"destination_workload",
+	"destination_workload_namespace",
+	"grpc_response_status",
+	"reporter",
+	"request_protocol",
+	"response_code",
+	"response_flags",
+	"source_app",
+	"source_canonical_revision",
+	"source_canonical_service",
+	"source_cluster",
+	"source_principal",
+	"source_version",
+	"source_workload",
+	"source_workload_namespace",
+}
+
+var (
+	metricNameRegex = regexp.MustCompile(`^[a-zA-Z_:][a-zA-Z0-9_:]*$`)
+	labelNameRegex  = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+)
+
+// Metric returns the name of an Istio standard metric in the telemetry
+func (tm TelemetryMapping) Metric(name string) string {
+	if mapped, found := tm.Metrics[name]; found {
+		return mapped
+	}
+	return name
+}
+
+// Label returns the key of a label of the Istio standard metrics in the telemetry
+func (tm TelemetryMapping) Label(name string) string {
+	if mapped, found := tm.Labels[name]; found {
+		return mapped
+	}
+	return name
+}
+
+// StandardLabel is the reverse of Label: it returns the Istio standard name of a label of the telemetry
+func (tm TelemetryMapping) StandardLabel(name string) string {
+	for standard, mapped := range tm.Labels {
+		if mapped == name {
+			return standard
+		}
+	}
+	return name
+}
+
+// Query maps the Istio standard metrics and labels of a PromQL query to the names used by the telemetry. Quoted
+// strings, e.g. the values of the label matchers, are left untouched.
+func (tm TelemetryMapping) Query(query string) string {
+	if len(tm.Metrics) == 0 && len(tm.Labels) == 0 {
+		return query
+	}
+	var mapped strings.Builder
+	for i := 0; i < len(query); {
+		c := query[i]
+		switch {
+		case c == '"' || c == '\'' || c == '`':
+			end := i + 1
+			for end < len(query) && query[end] != c {
+				if query[end] == '\\' && c != '`' {
+					end++
+				}
+				end++
+			}
+			if end < len(query) {
+				end++
+			}
+			mapped.WriteString(query[i:end])
+			i = end
+		case isNameStart(c):
+			end := i + 1
+			for end < len(query) && (isNameStart(query[end]) || isDigit(query[end])) {
+				end++
+			}
+			name := query[i:end]
+			if metric, found := tm.Metrics[name]; found {
+				name = metric
+			} else if label, found := tm.Labels[name]; found {
+				name = label
+			}
+			mapped.WriteString(name)
+			i = end
+		case isDigit(c):
+			// Numbers and durations, e.g. 0.001 or 60s
+			end := i + 1
+			for end < len(query) && (isNameStart(query[end]) || isDigit(query[end]) || query[end] == '.') {
+				end++
+			}
+			mapped.WriteString(query[i:end])
+			i = end
+		default:
+			mapped.WriteByte(c)
+			i++
+		}
+	}
+	return mapped.String()
+}
+
+func isNameStart(c byte) bool {
+	return c == '_' || c == ':' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+// Validate checks that only Istio standard metrics and labels are mapped, to valid Prometheus names, and that no
+// two labels are mapped to the same key
+func (tm TelemetryMapping) Validate() error {
+	if err := validateMapping("metric", tm.Metrics, IstioStandardMetrics, metricNameRegex); err != nil {
+		return err
+	}
+	if err := validateMapping("label", tm.Labels, IstioStandardLabels, labelNameRegex); err != nil {
+		return err
+	}
+	// A label mapped to the key of another standard label would make the results ambiguous
+	keys := map[string]string{}
+	for _, standard := range IstioStandardLabels {
+		mapped := tm.Label(standard)
+		if other, found := keys[mapped]; found {
+			return fmt.Errorf("telemetry mapping: labels [%s] and [%s] are both mapped to [%s]", other, standard, mapped)
+		}
+		keys[mapped] = standard
+	}
+	return nil
+}
+
+func validateMapping(kind string, mapping map[string]string, standardNames []string, validName *regexp.Regexp) error {
+	// Sorted for a deterministic error
+	names := make([]string, 0, len(mapping))
+	for name := range mapping {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if !isStandardName(name, standardNames) {
+			return fmt.Errorf("telemetry mapping: [%s] is not an Istio standard %s", name, kind)
+		}
+		if mapped := mapping[name]; !validName.MatchString(mapped) {
+			return fmt.Errorf("telemetry mapping: %s [%s] is mapped to an invalid name [%s]", kind, name, mapped)
+		}
+	}
+	return nil
+}
+
+func isStandardName(name string, standardNames []string) bool {
+	for _, standard := range standardNames {
+		if name == standard {
+			return true
+		}
+	}
+	return false
+}
@@ -34,7 +34,7 @@ type AggregateValueParam struct {
 	Name string `json:"aggregateValue"`
 }
 
-// swagger:parameters appMetrics appDetails graphApp graphAppVersion appDashboard appSpans appTraces errorTraces
+// swagger:parameters appMetrics appDetails graphApp graphAppVersion appDashboard appSpans appTraces appTracesExport errorTraces appGrafanaDashboards appRollouts
 type AppParam struct {
 	// The app name (label value).
 	//
@@ -52,6 +52,15 @@ type AppVersionParam struct {
 	Name string `json:"version"`
 }
 
+// swagger:parameters appGrafanaDashboards
+type AppVersionQueryParam struct {
+	// The app version (label value), set in the dashboards with a version variable.
+	//
+	// in: query
+	// required: false
+	Name string `json:"version"`
+}
+
 // swagger:parameters graphAggregate graphAggregateByService graphApp graphAppVersion graphService graphWorkload
 type ClusterParam struct {
 	// The cluster name. If not supplied queries/results will not be constrained by cluster.
@@ -61,6 +70,15 @@ type ClusterParam struct {
 	Name string `json:"container"`
 }
 
+// swagger:parameters istioConfigCreate istioConfigCreateSubtype istioConfigUpdate istioConfigUpdateSubtype istioConfigDelete istioConfigDeleteSubtype
+type IstioConfigClusterParam struct {
+	// The cluster of the Istio object: the home cluster of Kiali, or a remote cluster of the mesh edited with the credentials of the user. Defaults to the home cluster.
+	//
+	// in: query
+	// required: false
+	Name string `json:"cluster"`
+}
+
 // swagger:parameters podLogs
 type ContainerParam struct {
 	// The pod container name. Optional for single-container pod. Otherwise required.
@@ -70,7 +88,7 @@ type ContainerParam struct {
 	Name string `json:"container"`
 }
 
-// swagger:parameters istioConfigList workloadList workloadDetails workloadUpdate serviceDetails serviceUpdate appSpans serviceSpans workloadSpans appTraces serviceTraces workloadTraces errorTraces workloadValidations appList serviceMetrics aggregateMetrics appMetrics workloadMetrics istioConfigDetails istioConfigDetailsSubtype istioConfigDelete istioConfigDeleteSubtype istioConfigUpdate istioConfigUpdateSubtype serviceList appDetails graphAggregate graphAggregateByService graphApp graphAppVersion graphNamespace graphService graphWorkload namespaceMetrics customDashboard appDashboard serviceDashboard workloadDashboard istioConfigCreate istioConfigCreateSubtype namespaceUpdate namespaceTls podDetails podLogs namespaceValidations getIter8Experiments postIter8Experiments patchIter8Experiments deleteIter8Experiments podProxyDump podProxyResource
+// swagger:parameters istioConfigList workloadList workloadDetails workloadUpdate serviceDetails serviceUpdate appSpans serviceSpans workloadSpans appTraces appTracesExport serviceTraces workloadTraces errorTraces workloadValidations appList serviceMetrics aggregateMetrics appMetrics workloadMetrics istioConfigDetails istioConfigDetailsSubtype istioConfigDelete istioConfigDeleteSubtype istioConfigUpdate istioConfigUpdateSubtype serviceList appDetails graphAggregate graphAggregateByService graphApp graphAppVersion graphNamespace graphService graphWorkload namespaceMetrics customDashboard appDashboard serviceDashboard workloadDashboard istioConfigCreate istioConfigCreateSubtype namespaceUpdate namespaceTls podDetails podLogs namespaceValidations getIter8Experiments postIter8Experiments patchIter8Experiments deleteIter8Experiments podProxyDump podProxyResource appGrafanaDashboards serviceGrafanaDashboards workloadGrafanaDashboards workloadConfigDashboard rolloutList appRollouts rolloutMetrics workloadMetadataUpdate tracingSampling namespaceUnusedIstioConfig namespaceProxyStatus namespaceRateLimits serviceTrafficSplits namespaceHeaderRoutes namespaceProxyResources namespaceEdgeErrors workloadPortMetrics serviceEndpointsHealth workloadTracingDiagnosis serviceSubsetHealth podEnv workloadComparison namespaceBackendsTls namespaceTopTalkers workloadMaintenanceSet workloadMaintenanceClear workloadRolloutStatus serviceEffectiveDestinationRule namespaceFilteredValidations workloadSizeMetrics serviceSLOBurnRate podProxyLogging namespaceProxyLogLevel namespaceProxyLogLevelSet namespaceProxyLogLevelClear workloadAccessLogging workloadConnectionMetrics istioConfigDeleteImpact serviceResilienceConfig namespaceProxyMemory namespaceMtlsRecommendation namespaceConfigReferenceGraph virtualServiceRouteMetrics namespaceOverview podAccessLogs
 type NamespaceParam struct {
 	// The namespace name.
 	//
@@ -88,7 +106,7 @@ type NameParam struct {
 	Name string `json:"name"`
 }
 
-// swagger:parameters istioConfigDetails istioConfigDetailsSubtype istioConfigDelete istioConfigDeleteSubtype istioConfigUpdate istioConfigUpdateSubtype
+// swagger:parameters istioConfigDetails istioConfigDetailsSubtype istioConfigDelete istioConfigDeleteSubtype istioConfigUpdate istioConfigUpdateSubtype istioConfigDeleteImpact virtualServiceRouteMetrics
 type ObjectNameParam struct {
 	// The Istio object name.
 	//
@@ -97,7 +115,7 @@ type ObjectNameParam struct {
 	Name string `json:"object"`
 }
 
-// swagger:parameters istioConfigDetails istioConfigDetailsSubtype istioConfigDelete istioConfigDeleteSubtype istioConfigUpdate istioConfigUpdateSubtype istioConfigCreate istioConfigCreateSubtype
+// swagger:parameters istioConfigDetails istioConfigDetailsSubtype istioConfigDelete istioConfigDeleteSubtype istioConfigUpdate istioConfigUpdateSubtype istioConfigCreate istioConfigCreateSubtype istioConfigDeleteImpact
 type ObjectTypeParam struct {
 	// The Istio object type.
 	//
@@ -107,7 +125,7 @@ type ObjectTypeParam struct {
 	Name string `json:"object_type"`
 }
 
-// swagger:parameters podDetails podLogs podProxyDump podProxyResource
+// swagger:parameters podDetails podLogs podProxyDump podProxyResource podEnv podProxyLogging podAccessLogs
 type PodParam struct {
 	// The pod name.
 	//
@@ -116,6 +134,15 @@ type PodParam struct {
 	Name string `json:"pod"`
 }
 
+// swagger:parameters podProxyLogging namespaceProxyLogLevelSet
+type ProxyLogLevelParam struct {
+	// The Envoy log level: off, trace, debug, info, warning, error or critical.
+	//
+	// in: query
+	// required: true
+	Level string `json:"level"`
+}
+
 // swagger:parameters podProxyResource
 type ResourceParam struct {
 	// The discovery service resource
@@ -125,7 +152,7 @@ type ResourceParam struct {
 	Name string `json:"resource"`
 }
 
-// swagger:parameters serviceDetails serviceUpdate serviceMetrics graphService graphAggregateByService serviceDashboard serviceSpans serviceTraces
+// swagger:parameters serviceDetails serviceUpdate serviceMetrics graphService graphAggregateByService serviceDashboard serviceSpans serviceTraces serviceGrafanaDashboards serviceTrafficSplits serviceEndpointsHealth serviceSubsetHealth serviceEffectiveDestinationRule serviceSLOBurnRate serviceResilienceConfig
 type ServiceParam struct {
 	// The service name.
 	//
@@ -134,7 +161,17 @@ type ServiceParam struct {
 	Name string `json:"service"`
 }
 
-// swagger:parameters podLogs
+// swagger:parameters namespaceList
+type ActiveNamespacesParam struct {
+	// Only the namespaces with mesh traffic, sent or received, over the last 10 minutes.
+	//
+	// in: query
+	// required: false
+	// default: false
+	Name bool `json:"active"`
+}
+
+// swagger:parameters podLogs podAccessLogs
 type SinceTimeParam struct {
 	// The start time for fetching logs. UNIX time in seconds. Default is all logs.
 	//
@@ -143,7 +180,7 @@ type SinceTimeParam struct {
 	Name string `json:"sinceTime"`
 }
 
-// swagger:parameters podLogs
+// swagger:parameters podLogs podAccessLogs
 type DurationLogParam struct {
 	// Query time-range duration (Golang string duration). Duration starts on
 	// `sinceTime` if set, or the time for the first log message if not set.
@@ -153,7 +190,36 @@ type DurationLogParam struct {
 	Name string `json:"duration"`
 }
 
-// swagger:parameters traceDetails
+// swagger:parameters podAccessLogs
+type AccessLogsWorkloadParam struct {
+	// The workload of the pod. When set, the access logs are linked to the traces of the workload.
+	//
+	// in: query
+	// required: false
+	Name string `json:"workload"`
+}
+
+// swagger:parameters tracingSampling workloadTracingDiagnosis
+type SamplingWindowParam struct {
+	// The window over which the requests and the traces are counted, or the traces searched.
+	//
+	// in: query
+	// required: false
+	// default: 10m
+	Name string `json:"window"`
+}
+
+// swagger:parameters tracingSampling
+type SamplingLimitParam struct {
+	// The maximum number of traces fetched per service.
+	//
+	// in: query
+	// required: false
+	// default: 1000
+	Name string `json:"limit"`
+}
+
+// swagger:parameters traceDetails traceSpanTree
 type TraceIDParam struct {
 	// The trace ID.
 	//
@@ -162,7 +228,7 @@ type TraceIDParam struct {
 	Name string `json:"traceID"`
 }
 
-// swagger:parameters customDashboard
+// swagger:parameters customDashboard workloadConfigDashboard
 type DashboardParam struct {
 	// The dashboard resource name.
 	//
@@ -171,7 +237,7 @@ type DashboardParam struct {
 	Name string `json:"dashboard"`
 }
 
-// swagger:parameters workloadDetails workloadUpdate workloadValidations workloadMetrics graphWorkload workloadDashboard workloadSpans workloadTraces
+// swagger:parameters workloadDetails workloadUpdate workloadMetadataUpdate workloadValidations workloadMetrics workloadPortMetrics graphWorkload workloadDashboard workloadSpans workloadTraces workloadGrafanaDashboards workloadConfigDashboard workloadTracingDiagnosis workloadComparison workloadMaintenanceSet workloadMaintenanceClear workloadRolloutStatus workloadSizeMetrics workloadAccessLogging workloadConnectionMetrics
 type WorkloadParam struct {
 	// The workload name.
 	//
@@ -180,6 +246,191 @@ type WorkloadParam struct {
 	Name string `json:"workload"`
 }
 
+// swagger:parameters rolloutMetrics
+type RolloutParam struct {
+	// The rollout name: the Flagger target Deployment or the Argo Rollout.
+	//
+	// in: path
+	// required: true
+	Name string `json:"rollout"`
+}
+
+// swagger:parameters serviceSubsetHealth
+type SubsetParam struct {
+	// The name of the DestinationRule subset.
+	//
+	// in: path
+	// required: true
+	Name string `json:"subset"`
+}
+
+// swagger:parameters rolloutMetrics pilotMetrics serviceTrafficSplits namespaceProxyResources namespaceEdgeErrors workloadPortMetrics serviceSubsetHealth workloadComparison namespaceTopTalkers workloadSizeMetrics workloadConnectionMetrics namespaceMtlsRecommendation virtualServiceRouteMetrics meshSnapshot namespaceOverview
+type RolloutRateIntervalParam struct {
+	// The rate interval used for fetching the rates.
+	//
+	// in: query
+	// default: 10m
+	Name string `json:"rateInterval"`
+}
+
+// swagger:parameters serviceSLOBurnRate
+type ServiceSLOParams struct {
+	// The percentage of successful requests of the SLO.
+	//
+	// in: query
+	// default: 99.9
+	Target string `json:"target"`
+
+	// The period of the SLO, as a Prometheus duration.
+	//
+	// in: query
+	// default: 30d
+	Window string `json:"window"`
+}
+
+// swagger:parameters workloadComparison
+type WorkloadComparisonParams struct {
+	// The workload compared with the workload of the path.
+	//
+	// in: query
+	// required: true
+	With string `json:"with"`
+
+	// The namespace of the compared workload, the namespace of the path by default.
+	//
+	// in: query
+	// required: false
+	WithNamespace string `json:"withNamespace"`
+}
+
+// swagger:parameters workloadMaintenanceSet
+type WorkloadMaintenanceParams struct {
+	// The duration of the maintenance (i.e. 30m, 2h). Defaults to the duration of the maintenance configuration.
+	//
+	// in: query
+	// required: false
+	Duration string `json:"duration"`
+}
+
+// swagger:parameters workloadMaintenanceSet workloadMaintenanceClear workloadRolloutStatus
+type WorkloadTypeParam struct {
+	// The type of the workload, resolved from the workload when not set.
+	//
+	// in: query
+	// required: false
+	Type string `json:"type"`
+}
+
+// swagger:parameters workloadMetadataUpdate
+type WorkloadMetadataPodTemplateParam struct {
+	// Set the labels and annotations in the pod template of the workload, which restarts its pods, instead of the
+	// metadata of the workload itself. Pods are always patched directly.
+	//
+	// in: query
+	// required: false
+	// default: false
+	PodTemplate bool `json:"podTemplate"`
+}
+
+// swagger:parameters namespaceEdgeErrors
+type EdgeErrorsParams struct {
+	// The source workload of the edge. The source is the whole namespace when neither the workload nor the app is set.
+	//
+	// in: query
+	// required: false
+	SourceWorkload string `json:"sourceWorkload"`
+
+	// The source app of the edge.
+	//
+	// in: query
+	// required: false
+	SourceApp string `json:"sourceApp"`
+
+	// The namespace of the destination of the edge. Defaults to the source namespace.
+	//
+	// in: query
+	// required: false
+	DestinationNamespace string `json:"destinationNamespace"`
+
+	// The destination service of the edge. One of destinationService, destinationWorkload and destinationApp is required.
+	//
+	// in: query
+	// required: false
+	DestinationService string `json:"destinationService"`
+
+	// The destination workload of the edge.
+	//
+	// in: query
+	// required: false
+	DestinationWorkload string `json:"destinationWorkload"`
+
+	// The destination app of the edge.
+	//
+	// in: query
+	// required: false
+	DestinationApp string `json:"destinationApp"`
+}
+
+// swagger:parameters namespaceTopTalkers
+type TopTalkersParams struct {
+	// The direction of the traffic: 'outbound' from the workloads of the namespace, or 'inbound' to them.
+	//
+	// in: query
+	// required: false
+	// default: outbound
+	Direction string `json:"direction"`
+
+	// The metric of the rates: 'requests', 'request_bytes' or 'response_bytes'.
+	//
+	// in: query
+	// required: false
+	// default: requests
+	Metric string `json:"metric"`
+
+	// The number of edges returned.
+	//
+	// in: query
+	// required: false
+	// default: 10
+	Limit int `json:"limit"`
+}
+
+// swagger:parameters meshCertificateChain
+type CertificateChainParams struct {
+	// The namespace of the pod whose workload certificate is sampled.
+	//
+	// in: query
+	// required: false
+	Namespace string `json:"namespace"`
+
+	// The pod whose workload certificate is sampled from its proxy.
+	//
+	// in: query
+	// required: false
+	Pod string `json:"pod"`
+}
+
+// swagger:parameters namespaceFilteredValidations
+type FilteredValidationsParams struct {
+	// The types of the objects, comma separated, either plural or singular. Default is all types.
+	//
+	// in: query
+	// required: false
+	ObjectType string `json:"objectType"`
+
+	// The name of the objects. Default is all names.
+	//
+	// in: query
+	// required: false
+	ObjectName string `json:"objectName"`
+
+	// The minimum severity of the checks: unknown, warning or error. Default is all severities.
+	//
+	// in: query
+	// required: false
+	Severity string `json:"severity"`
+}
+
 /////////////////////
 // SWAGGER PARAMETERS - GRAPH
 // - keep this alphabetized
@@ -187,7 +438,7 @@ type WorkloadParam struct {
 
 // swagger:parameters graphApp graphAppVersion graphNamespaces graphService graphWorkload
 type AppendersParam struct {
-	// Comma-separated list of Appenders to run. Available appenders: [aggregateNode, deadNode, idleNode, istio, responseTime, securityPolicy, serviceEntry, sidecarsCheck].
+	// Comma-separated list of Appenders to run. Available appenders: [aggregateNode, deadNode, idleNode, istio, responseTime, retries, securityPolicy, serviceEntry, sidecarsCheck].
 	//
 	// in: query
 	// required: false
@@ -225,6 +476,16 @@ type BoxByParam struct {
 	Name string `json:"boxBy"`
 }
 
+// swagger:parameters graphApp graphAppVersion graphNamespaces graphService graphWorkload
+type ConfigVendorParam struct {
+	// The graph format. Available config vendors: [cytoscape, layout]. The layout vendor returns the cytoscape elements with pre-computed node positions.
+	//
+	// in: query
+	// required: false
+	// default: cytoscape
+	Name string `json:"configVendor"`
+}
+
 // swagger:parameters graphApp graphAppVersion graphNamespaces graphWorkload
 type IncludeIdleEdges struct {
 	// Flag for including edges that have no request traffic for the time period.
@@ -245,6 +506,35 @@ type InjectServiceNodes struct {
 	Name string `json:"injectServiceNodes"`
 }
 
+// swagger:parameters graphApp graphAppVersion graphNamespaces graphService graphWorkload
+type ProtocolsParam struct {
+	// Comma-separated list of the protocols of the traffic to include. Available protocols: [grpc, http, tcp].
+	//
+	// in: query
+	// required: false
+	Name string `json:"protocols"`
+}
+
+// swagger:parameters graphApp graphAppVersion graphNamespaces graphService graphWorkload
+type SecurityParam struct {
+	// Security of the traffic to include, combined with the protocols filter. Available values: [mtls, plaintext].
+	//
+	// in: query
+	// required: false
+	Name string `json:"security"`
+}
+
+// swagger:parameters graphApp graphAppVersion graphNamespaces graphService graphWorkload
+type UnhealthyOnlyParam struct {
+	// Flag for keeping only the nodes with a Degraded or Failure health, along with their immediate neighbors.
+	// The graph is flagged as trimmed when healthy nodes were removed.
+	//
+	// in: query
+	// required: false
+	// default: false
+	Name string `json:"unhealthyOnly"`
+}
+
 // swagger:parameters graphNamespaces
 type NamespacesParam struct {
 	// Comma-separated list of namespaces to include in the graph. The namespaces must be accessible to the client.
@@ -298,7 +588,16 @@ type ByLabelsParam struct {
 	Name []string `json:"byLabels[]"`
 }
 
-// swagger:parameters serviceMetrics aggregateMetrics appMetrics workloadMetrics appDashboard serviceDashboard workloadDashboard
+// swagger:parameters serviceMetrics aggregateMetrics appMetrics workloadMetrics namespaceMetrics
+type CompareOffsetParam struct {
+	// When set, compares the metrics with the ones of the same period shifted back by this Prometheus duration (Ex: 7d).
+	//
+	// in: query
+	// required: false
+	Name string `json:"compareOffset"`
+}
+
+// swagger:parameters serviceMetrics aggregateMetrics appMetrics workloadMetrics appDashboard serviceDashboard workloadDashboard workloadPortMetrics workloadSizeMetrics
 type DirectionParam struct {
 	// Traffic direction: 'inbound' or 'outbound'.
 	//
@@ -348,6 +647,16 @@ type QuantilesParam struct {
 	Name []string `json:"quantiles[]"`
 }
 
+// swagger:parameters workloadSizeMetrics
+type SizeQuantilesParam struct {
+	// List of quantiles of the sizes to fetch. Ex: [0.5, 0.95, 0.99].
+	//
+	// in: query
+	// required: false
+	// default: [0.5, 0.95, 0.99]
+	Name []string `json:"quantiles[]"`
+}
+
 // swagger:parameters serviceMetrics aggregateMetrics appMetrics workloadMetrics customDashboard appDashboard serviceDashboard workloadDashboard
 type RateFuncParam struct {
 	// Prometheus function used to calculate rate: 'rate' or 'irate'.
@@ -378,7 +687,7 @@ type RequestProtocolParam struct {
 	Name string `json:"requestProtocol"`
 }
 
-// swagger:parameters serviceMetrics aggregateMetrics appMetrics workloadMetrics appDashboard serviceDashboard workloadDashboard
+// swagger:parameters serviceMetrics aggregateMetrics appMetrics workloadMetrics appDashboard serviceDashboard workloadDashboard namespaceEdgeErrors
 type ReporterParam struct {
 	// Istio telemetry reporter: 'source' or 'destination'.
 	//
@@ -388,6 +697,17 @@ type ReporterParam struct {
 	Name string `json:"reporter"`
 }
 
+// swagger:parameters serviceMetrics aggregateMetrics appMetrics workloadMetrics namespaceMetrics
+type ExemplarsParam struct {
+	// Flag for attaching to the histogram series their exemplars, with the IDs of representative traces.
+	// Skipped when the Prometheus backend doesn't support exemplars.
+	//
+	// in: query
+	// required: false
+	// default: false
+	Name string `json:"exemplars"`
+}
+
 // swagger:parameters serviceMetrics aggregateMetrics appMetrics workloadMetrics customDashboard appDashboard serviceDashboard workloadDashboard
 type StepParam struct {
 	// Step between [graph] datapoints, in seconds.
@@ -564,6 +884,27 @@ type workloadHealthResponse struct {
 	Body models.WorkloadHealth
 }
 
+// workloadDependencyHealthResponse contains the health of a workload and of its immediate dependencies
+// swagger:response workloadDependencyHealthResponse
+type workloadDependencyHealthResponse struct {
+	// in:body
+	Body models.WorkloadDependencyHealth
+}
+
+// rolloutListResponse is a list of progressive delivery rollouts
+// swagger:response rolloutListResponse
+type rolloutListResponse struct {
+	// in:body
+	Body models.CanaryRollouts
+}
+
+// rolloutMetricsResponse compares the inbound metrics of the canary and the stable variants of a rollout
+// swagger:response rolloutMetricsResponse
+type rolloutMetricsResponse struct {
+	// in:body
+	Body models.CanaryMetrics
+}
+
 // namespaceAppHealthResponse is a map of app name x health
 // swagger:response namespaceAppHealthResponse
 type namespaceAppHealthResponse struct {
@@ -571,6 +912,76 @@ type namespaceAppHealthResponse struct {
 	Body models.NamespaceAppHealth
 }
 
+// namespaceCustomResourceHealthResponse is the list of the health of the custom resources of a namespace
+// swagger:response namespaceCustomResourceHealthResponse
+type namespaceCustomResourceHealthResponse struct {
+	// in:body
+	Body models.NamespaceCustomResourceHealth
+}
+
+// serviceEndpointsHealthResponse counts the ready and not ready endpoints of a service per zone
+// swagger:response serviceEndpointsHealthResponse
+type serviceEndpointsHealthResponse struct {
+	// in:body
+	Body models.ServiceEndpointsHealth
+}
+
+// serviceEffectiveDestinationRuleResponse is the DestinationRule in effect for a service
+// swagger:response serviceEffectiveDestinationRuleResponse
+type serviceEffectiveDestinationRuleResponse struct {
+	// in:body
+	Body models.EffectiveDestinationRule
+}
+
+// serviceResilienceConfigResponse is the timeout and retries of the routes to a service
+// swagger:response serviceResilienceConfigResponse
+type serviceResilienceConfigResponse struct {
+	// in:body
+	Body models.ServiceResilienceConfig
+}
+
+// serviceSubsetHealthResponse is the health of the workloads of a DestinationRule subset of a service
+// swagger:response serviceSubsetHealthResponse
+type serviceSubsetHealthResponse struct {
+	// in:body
+	Body models.SubsetHealth
+}
+
+// namespaceProxyLogLevelResponse is the default log level of the proxies of a namespace
+// swagger:response namespaceProxyLogLevelResponse
+type namespaceProxyLogLevelResponse struct {
+	// in:body
+	Body models.NamespaceProxyLogLevel
+}
+
+// serviceSLOBurnRateResponse is the error budget consumed by a service and its burn rates
+// swagger:response serviceSLOBurnRateResponse
+type serviceSLOBurnRateResponse struct {
+	// in:body
+	Body models.ServiceSLO
+}
+
+// workloadComparisonResponse holds two workloads side by side: their metrics, over the same time window, and the differences of their configuration
+// swagger:response workloadComparisonResponse
+type workloadComparisonResponse struct {
+	// in:body
+	Body models.WorkloadComparison
+}
+
+// podEnvResponse is the environment of the containers of a pod, with the values taken from Secrets masked
+// swagger:response podEnvResponse
+type podEnvResponse struct {
+	// in:body
+	Body models.WorkloadEnv
+}
+
+// serviceTrafficSplitsResponse compares the weights of the routes of a service with the observed traffic
+// swagger:response serviceTrafficSplitsResponse
+type serviceTrafficSplitsResponse struct {
+	// in:body
+	Body models.TrafficSplits
+}
+
 // namespaceResponse is a basic namespace
 // swagger:response namespaceResponse
 type namespaceResponse struct {
@@ -592,6 +1003,20 @@ type TraceDetailsResponse struct {
 	Body []jaegerModels.Trace
 }
 
+// The traces exported in OTLP/JSON, a TracesData per line
+// swagger:response otlpTracesResponse
+type OTLPTracesResponse struct {
+	// in:body
+	Body []jaeger.OTLPTracesData
+}
+
+// A trace with its spans organized as a tree
+// swagger:response traceSpanTreeResponse
+type TraceSpanTreeResponse struct {
+	// in:body
+	Body models.TracingTrace
+}
+
 // Number of traces in error
 // swagger:response errorTracesResponse
 type ErrorTracesResponse struct {
@@ -599,6 +1024,34 @@ type ErrorTracesResponse struct {
 	Body int
 }
 
+// Estimated trace sampling of the services of a namespace
+// swagger:response tracingSamplingResponse
+type TracingSamplingResponse struct {
+	// in:body
+	Body models.TracingSampling
+}
+
+// Diagnosis of the missing traces of a workload
+// swagger:response tracingDiagnosisResponse
+type TracingDiagnosisResponse struct {
+	// in:body
+	Body models.TracingDiagnosis
+}
+
+// Rollout status of a Deployment or a StatefulSet
+// swagger:response workloadRolloutStatusResponse
+type WorkloadRolloutStatusResponse struct {
+	// in:body
+	Body models.WorkloadRolloutStatus
+}
+
+// Effective access logging of the proxy of a workload
+// swagger:response workloadAccessLoggingResponse
+type WorkloadAccessLoggingResponse struct {
+	// in:body
+	Body models.AccessLogging
+}
+
 // Listing all the information related to a Span
 // swagger:response spansResponse
 type SpansResponse struct {
@@ -634,6 +1087,34 @@ type IstioConfigDetailsResponse struct {
 	Body models.IstioConfigDetails
 }
 
+// The traffic of a workload per destination port
+// swagger:response workloadPortMetricsResponse
+type WorkloadPortMetricsResponse struct {
+	// in:body
+	Body models.WorkloadPortMetrics
+}
+
+// The sizes of the request and response bodies of a workload
+// swagger:response workloadSizeMetricsResponse
+type WorkloadSizeMetricsResponse struct {
+	// in:body
+	Body models.WorkloadSizeMetrics
+}
+
+// The TCP connections of a workload
+// swagger:response workloadConnectionMetricsResponse
+type WorkloadConnectionMetricsResponse struct {
+	// in:body
+	Body models.WorkloadConnectionMetrics
+}
+
+// The reasons why the cluster rejected the creation or the update of an Istio object
+// swagger:response istioConfigRejectionResponse
+type IstioConfigRejectionResponse struct {
+	// in:body
+	Body models.IstioConfigRejection
+}
+
 // Detailed information of an specific app
 // swagger:response appDetails
 type AppDetailsResponse struct {
@@ -655,6 +1136,13 @@ type GrafanaInfoResponse struct {
 	Body models.GrafanaInfo
 }
 
+// Return the links to the Grafana dashboards grouped by folder
+// swagger:response grafanaDashboardFoldersResponse
+type GrafanaDashboardFoldersResponse struct {
+	// in: body
+	Body []models.GrafanaDashboardFolder
+}
+
 // Return all the descriptor data related to Jaeger
 // swagger:response jaegerInfoResponse
 type JaegerInfoResponse struct {
@@ -676,6 +1164,27 @@ type MeshTlsResponse struct {
 	Body models.MTLSStatus
 }
 
+// Return the snapshot of the health, the mTLS status and the validations of all the namespaces
+// swagger:response meshSnapshotResponse
+type MeshSnapshotResponse struct {
+	// in:body
+	Body models.MeshSnapshot
+}
+
+// Return the extension providers of the mesh config
+// swagger:response extensionProvidersResponse
+type ExtensionProvidersResponse struct {
+	// in:body
+	Body models.ExtensionProviders
+}
+
+// Return the chain of trust of the mesh, from the root certificate
+// swagger:response certificateChainResponse
+type CertificateChainResponse struct {
+	// in:body
+	Body models.CertificateChain
+}
+
 // Return the mTLS status of a specific Namespace
 // swagger:response namespaceTlsResponse
 type NamespaceTlsResponse struct {
@@ -683,6 +1192,27 @@ type NamespaceTlsResponse struct {
 	Body models.MTLSStatus
 }
 
+// Return the TLS status of the Services routed by the Gateway API routes of a specific Namespace
+// swagger:response namespaceBackendsTlsResponse
+type NamespaceBackendsTlsResponse struct {
+	// in:body
+	Body models.BackendTLSStatuses
+}
+
+// Return the next step toward STRICT mTLS for a specific Namespace
+// swagger:response namespaceMtlsRecommendationResponse
+type NamespaceMtlsRecommendationResponse struct {
+	// in:body
+	Body models.MTLSRecommendation
+}
+
+// Return the overview of a specific Namespace
+// swagger:response namespaceOverviewResponse
+type NamespaceOverviewResponse struct {
+	// in:body
+	Body models.NamespaceOverview
+}
+
 // Return the validation status of a specific Namespace
 // swagger:response namespaceValidationSummaryResponse
 type NamespaceValidationSummaryResponse struct {
@@ -690,6 +1220,90 @@ type NamespaceValidationSummaryResponse struct {
 	Body models.IstioValidationSummary
 }
 
+// Return the validations of the objects of a namespace matching a filter, with the number of those left out
+// swagger:response filteredValidationsResponse
+type FilteredValidationsResponse struct {
+	// in:body
+	Body models.FilteredIstioValidations
+}
+
+// Return the Istio objects of a namespace that have no effect, grouped by reason
+// swagger:response unusedIstioConfigResponse
+type UnusedIstioConfigResponse struct {
+	// in:body
+	Body models.UnusedIstioConfig
+}
+
+// Return the graph of the references of the Istio objects of a namespace
+// swagger:response configReferenceGraphResponse
+type ConfigReferenceGraphResponse struct {
+	// in:body
+	Body models.ConfigReferenceGraph
+}
+
+// Return the objects affected by the deletion of an Istio object
+// swagger:response deleteImpactResponse
+type DeleteImpactResponse struct {
+	// in:body
+	Body models.DeleteImpact
+}
+
+// Return the traffic of the HTTP routes of a VirtualService
+// swagger:response virtualServiceRouteMetricsResponse
+type VirtualServiceRouteMetricsResponse struct {
+	// in:body
+	Body models.VirtualServiceRouteMetrics
+}
+
+// Return the rate limits applying to the workloads of a namespace
+// swagger:response rateLimitsResponse
+type RateLimitsResponse struct {
+	// in:body
+	Body models.RateLimits
+}
+
+// Return the routes of the VirtualServices of a namespace that match on request headers
+// swagger:response headerRoutesResponse
+type HeaderRoutesResponse struct {
+	// in:body
+	Body models.HeaderRoutes
+}
+
+// Return the sync status of the proxies of a namespace
+// swagger:response namespaceProxyStatusResponse
+type NamespaceProxyStatusResponse struct {
+	// in:body
+	Body models.NamespaceProxyStatus
+}
+
+// Return the decomposition of the errors of an edge of the graph
+// swagger:response edgeErrorsResponse
+type EdgeErrorsResponse struct {
+	// in:body
+	Body models.EdgeErrors
+}
+
+// Return the edges of a namespace with the highest rates
+// swagger:response topTalkersResponse
+type TopTalkersResponse struct {
+	// in:body
+	Body models.TopTalkers
+}
+
+// Return the resources and the usage of the proxies of a namespace
+// swagger:response namespaceProxyResourcesResponse
+type NamespaceProxyResourcesResponse struct {
+	// in:body
+	Body models.NamespaceProxyResources
+}
+
+// Return the memory pressure of the proxies of a namespace
+// swagger:response namespaceProxyMemoryResponse
+type NamespaceProxyMemoryResponse struct {
+	// in:body
+	Body models.NamespaceProxyMemory
+}
+
 // Return a dump of the configuration of a given envoy proxy
 // swagger:response configDump
 type ConfigDumpResponse struct {
@@ -751,6 +1365,20 @@ type IstioStatusResponse struct {
 	Body business.IstioComponentStatus
 }
 
+// Return the push latency percentiles and the push error rates of the control plane
+// swagger:response pilotMetricsResponse
+type PilotMetricsResponse struct {
+	// in: body
+	Body business.PilotMetrics
+}
+
+// Return the connection diagnostics of Prometheus and Jaeger
+// swagger:response externalServicesDiagnosticsResponse
+type ExternalServicesDiagnosticsResponse struct {
+	// in: body
+	Body []models.ExternalServiceDiagnostics
+}
+
 // Posted parameters for a metrics stats query
 // swagger:parameters metricsStats
 type MetricsStatsQueryBody struct {
@@ -771,3 +1399,17 @@ type ClustersResponse struct {
 	// in: body
 	Body []business.Cluster
 }
+
+// Return the health of each cluster of the mesh
+// swagger:response clustersOverviewResponse
+type ClustersOverviewResponse struct {
+	// in: body
+	Body []business.ClusterOverview
+}
+
+// Return the parsed access logs of the proxy of a pod
+// swagger:response accessLogsResponse
+type AccessLogsResponse struct {
+	// in: body
+	Body []models.AccessLog
+}
@@ -7,6 +7,8 @@ import (
 	"github.com/kiali/kiali/business"
 	"github.com/kiali/kiali/graph"
 	"github.com/kiali/kiali/graph/config/cytoscape"
+	"github.com/kiali/kiali/graph/config/layout"
+	"github.com/kiali/kiali/graph/telemetry"
 	"github.com/kiali/kiali/graph/telemetry/istio"
 	"github.com/kiali/kiali/log"
 	"github.com/kiali/kiali/prometheus"
@@ -14,16 +16,16 @@ import (
 )
 
 // GraphNamespaces generates a namespaces graph using the provided options
-func GraphNamespaces(business *business.Layer, o graph.Options) (code int, config interface{}) {
+func GraphNamespaces(businessLayer *business.Layer, o graph.Options) (code int, config interface{}) {
 	// time how long it takes to generate this graph
 	promtimer := internalmetrics.GetGraphGenerationTimePrometheusTimer(o.GetGraphKind(), o.TelemetryOptions.GraphType, o.InjectServiceNodes)
 	defer promtimer.ObserveDuration()
 
 	switch o.TelemetryVendor {
 	case graph.VendorIstio:
-		prom, err := prometheus.NewClient()
+		prom, err := business.NewPrometheusClient()
 		graph.CheckError(err)
-		code, config = graphNamespacesIstio(business, prom, o)
+		code, config = graphNamespacesIstio(businessLayer, prom, o)
 	default:
 		graph.Error(fmt.Sprintf("TelemetryVendor [%s] not supported", o.TelemetryVendor))
 	}
@@ -48,7 +50,7 @@ func graphNamespacesIstio(business *business.Layer, prom *prometheus.Client, o g
 }
 
 // GraphNode generates a node graph using the provided options
-func GraphNode(business *business.Layer, o graph.Options) (code int, config interface{}) {
+func GraphNode(businessLayer *business.Layer, o graph.Options) (code int, config interface{}) {
 	if len(o.Namespaces) != 1 {
 		graph.Error(fmt.Sprintf("Node graph does not support the 'namespaces' query parameter or the 'all' namespace"))
 	}
@@ -59,9 +61,9 @@ func GraphNode(business *business.Layer, o graph.Options) (code int, config inte
 
 	switch o.TelemetryVendor {
 	case graph.VendorIstio:
-		prom, err := prometheus.NewClient()
+		prom, err := business.NewPrometheusClient()
 		graph.CheckError(err)
-		code, config = graphNodeIstio(business, prom, o)
+		code, config = graphNodeIstio(businessLayer, prom, o)
 	default:
 		graph.Error(fmt.Sprintf("TelemetryVendor [%s] not supported", o.TelemetryVendor))
 	}
@@ -90,10 +92,21 @@ func generateGraph(trafficMap graph.TrafficMap, o graph.Options) (int, interface
 	promtimer := internalmetrics.GetGraphMarshalTimePrometheusTimer(o.GetGraphKind(), o.TelemetryOptions.GraphType, o.InjectServiceNodes)
 	defer promtimer.ObserveDuration()
 
+	trimmed := false
+	if o.ConfigOptions.Filters.UnhealthyOnly {
+		trimmed = telemetry.FilterUnhealthy(trafficMap)
+	}
+
 	var vendorConfig interface{}
 	switch o.ConfigVendor {
 	case graph.VendorCytoscape:
-		vendorConfig = cytoscape.NewConfig(trafficMap, o.ConfigOptions)
+		cyConfig := cytoscape.NewConfig(trafficMap, o.ConfigOptions)
+		cyConfig.Trimmed = trimmed
+		vendorConfig = cyConfig
+	case graph.VendorLayout:
+		layoutConfig := layout.NewConfig(trafficMap, o.ConfigOptions)
+		layoutConfig.Trimmed = trimmed
+		vendorConfig = layoutConfig
 	default:
 		graph.Error(fmt.Sprintf("ConfigVendor [%s] not supported", o.ConfigVendor))
 	}
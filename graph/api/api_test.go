@@ -1968,6 +1968,81 @@ func TestServiceNodeGraph(t *testing.T) {
 	assert.Equal(t, 200, resp.StatusCode)
 }
 
+// TestFilteredServiceNodeGraph checks that the protocol filter constrains the queries: only http requests are
+// queried, the tcp traffic isn't queried at all
+func TestFilteredServiceNodeGraph(t *testing.T) {
+	q0 := `round(sum(rate(istio_requests_total{reporter="source",request_protocol="http",destination_workload="unknown",destination_service=~"^productpage\\.bookinfo\\..*$"} [600s])) by (source_cluster,source_workload_namespace,source_workload,source_canonical_service,source_canonical_revision,destination_cluster,destination_service_namespace,destination_service,destination_service_name,destination_workload_namespace,destination_workload,destination_canonical_service,destination_canonical_revision,request_protocol,response_code,grpc_response_status,response_flags) > 0,0.001)`
+	v0 := model.Vector{}
+
+	q1 := `round(sum(rate(istio_requests_total{reporter="destination",request_protocol="http",destination_service_namespace="bookinfo",destination_service=~"^productpage\\.bookinfo\\..*$"} [600s])) by (source_cluster,source_workload_namespace,source_workload,source_canonical_service,source_canonical_revision,destination_cluster,destination_service_namespace,destination_service,destination_service_name,destination_workload_namespace,destination_workload,destination_canonical_service,destination_canonical_revision,request_protocol,response_code,grpc_response_status,response_flags) > 0,0.001)`
+	q1m0 := model.Metric{
+		"source_workload_namespace":      "istio-system",
+		"source_workload":                "ingressgateway-unknown",
+		"source_canonical_service":       "ingressgateway",
+		"source_canonical_revision":      "latest",
+		"destination_service_namespace":  "bookinfo",
+		"destination_service":            "productpage:9080",
+		"destination_service_name":       "productpage",
+		"destination_workload_namespace": "bookinfo",
+		"destination_workload":           "productpage-v1",
+		"destination_canonical_service":  "productpage",
+		"destination_canonical_revision": "v1",
+		"request_protocol":               "http",
+		"response_code":                  "200",
+		"grpc_response_status":           "0",
+		"response_flags":                 "-"}
+	v1 := model.Vector{
+		&model.Sample{
+			Metric: q1m0,
+			Value:  100}}
+
+	client, xapi, _, err := setupMocked()
+	if err != nil {
+		return
+	}
+
+	mockQuery(xapi, q0, &v0)
+	mockQuery(xapi, q1, &v1)
+
+	mr := mux.NewRouter()
+	mr.HandleFunc("/api/namespaces/{namespace}/services/{service}/graph", http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			context := context.WithValue(r.Context(), "authInfo", &api.AuthInfo{Token: "test"})
+			code, config := graphNodeIstio(nil, client, graph.NewOptions(r.WithContext(context)))
+			respond(w, code, config)
+		}))
+
+	ts := httptest.NewServer(mr)
+	defer ts.Close()
+
+	url := ts.URL + "/api/namespaces/bookinfo/services/productpage/graph?graphType=workload&appenders&protocols=http&queryTime=1523364075"
+	resp, err := http.Get(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, 200, resp.StatusCode)
+
+	config := struct {
+		Filters  graph.Filters `json:"filters"`
+		Elements struct {
+			Edges []struct {
+				Data struct {
+					Traffic struct {
+						Protocol string `json:"protocol"`
+					} `json:"traffic"`
+				} `json:"data"`
+			} `json:"edges"`
+		} `json:"elements"`
+	}{}
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&config))
+	assert.Equal(t, graph.Filters{Protocols: []string{"http"}}, config.Filters)
+	assert.NotEmpty(t, config.Elements.Edges)
+	for _, e := range config.Elements.Edges {
+		assert.Equal(t, "http", e.Data.Traffic.Protocol)
+	}
+	xapi.AssertNumberOfCalls(t, "Query", 2)
+}
+
 // TestComplexGraph aims to provide test coverage for a more robust graph and specific corner cases. Listed below are coverage cases
 // - multi-cluster graph
 // - multi-namespace graph
@@ -0,0 +1,186 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s.io/client-go/tools/clientcmd/api"
+
+	"github.com/kiali/kiali/business"
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/graph"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/kubernetes/cache"
+	"github.com/kiali/kiali/log"
+	"github.com/kiali/kiali/util"
+)
+
+var (
+	graphRefresherLock     sync.Mutex
+	graphRefresherStopChan chan struct{}
+
+	// test hooks
+	getGraphCache          = business.GetGraphCache
+	computeNamespacesGraph = GraphNamespaces
+)
+
+// GraphNamespacesCached serves a namespaces graph from the graphs computed in background while it is younger than the
+// TTL of the graph cache, computing it otherwise. The graph is then refreshed in background while it is viewed.
+// It returns the age of the graph, 0 when just computed.
+func GraphNamespacesCached(business *business.Layer, o graph.Options) (code int, payload interface{}, age time.Duration) {
+	graphCache := getGraphCache()
+	conf := config.Get().Server.GraphCache
+	if !conf.Enabled || graphCache == nil || !isCacheableGraph(o) {
+		code, payload = computeNamespacesGraph(business, o)
+		return code, payload, 0
+	}
+
+	now := util.Clock.Now()
+	key := graphCacheKey(o)
+	graphCache.ViewGraph(key, o, now)
+	if cached, found := graphCache.GetGraph(key); found {
+		if age = now.Sub(cached.Computed); age <= time.Duration(conf.TTL)*time.Second {
+			log.Tracef("Serving graph [%s] computed %v ago", key, age)
+			return http.StatusOK, cached.Graph, age
+		}
+	}
+
+	code, payload = computeNamespacesGraph(business, o)
+	if code == http.StatusOK {
+		graphCache.SetGraph(key, cache.CachedGraph{Graph: payload, Computed: now})
+	}
+	return code, payload, 0
+}
+
+// isCacheableGraph tells whether a graph is refreshed in background: the namespaces graphs ending now
+func isCacheableGraph(o graph.Options) bool {
+	return o.GetGraphKind() == graph.GraphKindNamespace && o.TelemetryVendor == graph.VendorIstio && o.TelemetryOptions.Params.Get("queryTime") == ""
+}
+
+// graphCacheKey identifies a graph by its request parameters, other than the query time, and the namespaces
+// accessible to its viewer, as they determine what the graph shows
+func graphCacheKey(o graph.Options) string {
+	params := url.Values{}
+	for k, v := range o.TelemetryOptions.Params {
+		if k != "queryTime" {
+			params[k] = v
+		}
+	}
+	accessible := make([]string, 0, len(o.AccessibleNamespaces))
+	for ns := range o.AccessibleNamespaces {
+		accessible = append(accessible, ns)
+	}
+	sort.Strings(accessible)
+	return fmt.Sprintf("%s|%s", params.Encode(), strings.Join(accessible, ","))
+}
+
+// StartGraphRefresher periodically recomputes with the Kiali ServiceAccount the graphs viewed within the inactivity
+// period of the graph cache
+func StartGraphRefresher() {
+	graphRefresherLock.Lock()
+	defer graphRefresherLock.Unlock()
+	if graphRefresherStopChan != nil {
+		return
+	}
+
+	interval := time.Duration(config.Get().Server.GraphCache.RefreshInterval) * time.Second
+	if interval <= 0 {
+		interval = time.Minute
+	}
+	stopChan := make(chan struct{})
+	graphRefresherStopChan = stopChan
+
+	log.Infof("Starting graph refresher every %v", interval)
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				refreshViewedGraphsWithKialiSA()
+			case <-stopChan:
+				log.Info("Graph refresher stopped")
+				return
+			}
+		}
+	}()
+}
+
+// StopGraphRefresher stops the graph refresher if it's running
+func StopGraphRefresher() {
+	graphRefresherLock.Lock()
+	defer graphRefresherLock.Unlock()
+	if graphRefresherStopChan != nil {
+		close(graphRefresherStopChan)
+		graphRefresherStopChan = nil
+	}
+}
+
+func refreshViewedGraphsWithKialiSA() {
+	graphCache := getGraphCache()
+	if graphCache == nil {
+		return
+	}
+	kialiToken, err := kubernetes.GetKialiToken()
+	if err != nil {
+		log.Errorf("Graphs can't be refreshed, Kiali token is not available: %s", err)
+		return
+	}
+	layer, err := business.Get(&api.AuthInfo{Token: kialiToken})
+	if err != nil {
+		log.Errorf("Graphs can't be refreshed, business layer is not available: %s", err)
+		return
+	}
+	refreshViewedGraphs(graphCache, layer)
+}
+
+// refreshViewedGraphs recomputes the graphs viewed within the inactivity period, one after the other so that their
+// Prometheus queries are spread over the refresh rather than sent in bursts. It returns the number of graphs refreshed.
+func refreshViewedGraphs(graphCache cache.GraphCache, layer *business.Layer) int {
+	inactivityPeriod := time.Duration(config.Get().Server.GraphCache.InactivityPeriod) * time.Second
+	requests := graphCache.ViewedGraphs(util.Clock.Now().Add(-inactivityPeriod))
+
+	refreshed := 0
+	for key, request := range requests {
+		o, ok := request.(graph.Options)
+		if !ok {
+			continue
+		}
+		now := util.Clock.Now()
+		payload, err := refreshGraph(layer, o, now)
+		if err != nil {
+			log.Warningf("Error refreshing graph [%s]: %v", key, err)
+			continue
+		}
+		graphCache.SetGraph(key, cache.CachedGraph{Graph: payload, Computed: now})
+		refreshed++
+	}
+	log.Debugf("Refreshed %d of %d viewed graphs", refreshed, len(requests))
+	return refreshed
+}
+
+// refreshGraph computes a graph ending at the time. The graph errors are raised as panics, they are returned.
+func refreshGraph(layer *business.Layer, o graph.Options, queryTime time.Time) (payload interface{}, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			if response, ok := r.(graph.Response); ok {
+				err = errors.New(response.Message)
+			} else {
+				err = fmt.Errorf("%v", r)
+			}
+		}
+	}()
+
+	o.SetQueryTime(queryTime.Unix())
+	code, payload := computeNamespacesGraph(layer, o)
+	if code != http.StatusOK {
+		return nil, fmt.Errorf("graph computation failed with code %d", code)
+	}
+	return payload, nil
+}
@@ -0,0 +1,175 @@
+package api
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kiali/kiali/business"
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/graph"
+	"github.com/kiali/kiali/kubernetes/cache"
+	"github.com/kiali/kiali/util"
+)
+
+var graphCacheTime = time.Date(2022, 03, 10, 14, 0, 0, 0, time.UTC)
+
+// fakeGraphCache is a GraphCache without eviction, but of the graphs not viewed
+type fakeGraphCache struct {
+	graphs   map[string]cache.CachedGraph
+	requests map[string]interface{}
+	viewed   map[string]time.Time
+}
+
+func newFakeGraphCache() *fakeGraphCache {
+	return &fakeGraphCache{graphs: map[string]cache.CachedGraph{}, requests: map[string]interface{}{}, viewed: map[string]time.Time{}}
+}
+
+func (c *fakeGraphCache) GetGraph(key string) (cache.CachedGraph, bool) {
+	g, ok := c.graphs[key]
+	return g, ok
+}
+
+func (c *fakeGraphCache) SetGraph(key string, graph cache.CachedGraph) {
+	c.graphs[key] = graph
+}
+
+func (c *fakeGraphCache) ViewGraph(key string, request interface{}, viewed time.Time) {
+	c.requests[key] = request
+	c.viewed[key] = viewed
+}
+
+func (c *fakeGraphCache) ViewedGraphs(since time.Time) map[string]interface{} {
+	requests := map[string]interface{}{}
+	for key, request := range c.requests {
+		if c.viewed[key].Before(since) {
+			delete(c.requests, key)
+			delete(c.graphs, key)
+			continue
+		}
+		requests[key] = request
+	}
+	return requests
+}
+
+// setupGraphCache enables the graph cache and counts the graph computations, returning the query time of the graphs
+func setupGraphCache(t *testing.T) (*fakeGraphCache, *[]int64) {
+	conf := config.NewConfig()
+	conf.Server.GraphCache.Enabled = true
+	config.Set(conf)
+
+	graphCache := newFakeGraphCache()
+	computed := []int64{}
+	getGraphCache = func() cache.GraphCache { return graphCache }
+	computeNamespacesGraph = func(business *business.Layer, o graph.Options) (int, interface{}) {
+		computed = append(computed, o.TelemetryOptions.QueryTime)
+		return http.StatusOK, len(computed)
+	}
+	util.Clock = util.ClockMock{Time: graphCacheTime}
+	t.Cleanup(func() {
+		getGraphCache = business.GetGraphCache
+		computeNamespacesGraph = GraphNamespaces
+		util.Clock = util.RealClock{}
+		config.Set(config.NewConfig())
+	})
+	return graphCache, &computed
+}
+
+func namespacesGraphOptions(params url.Values, accessible ...string) graph.Options {
+	o := graph.Options{TelemetryVendor: graph.VendorIstio}
+	o.TelemetryOptions.Params = params
+	o.TelemetryOptions.QueryTime = graphCacheTime.Unix()
+	o.AccessibleNamespaces = map[string]time.Time{}
+	for _, ns := range accessible {
+		o.AccessibleNamespaces[ns] = graphCacheTime.Add(-24 * time.Hour)
+	}
+	o.Namespaces = graph.NamespaceInfoMap{"bookinfo": {Name: "bookinfo", Duration: 10 * time.Minute}}
+	return o
+}
+
+func TestGraphNamespacesCachedServesYoungGraphs(t *testing.T) {
+	assert := assert.New(t)
+	_, computed := setupGraphCache(t)
+	o := namespacesGraphOptions(url.Values{"namespaces": {"bookinfo"}, "graphType": {"app"}}, "bookinfo")
+
+	code, payload, age := GraphNamespacesCached(nil, o)
+	assert.Equal(http.StatusOK, code)
+	assert.Equal(1, payload)
+	assert.Zero(age)
+
+	// Served from the cache while younger than the TTL
+	util.Clock = util.ClockMock{Time: graphCacheTime.Add(90 * time.Second)}
+	code, payload, age = GraphNamespacesCached(nil, o)
+	assert.Equal(http.StatusOK, code)
+	assert.Equal(1, payload)
+	assert.Equal(90*time.Second, age)
+	assert.Len(*computed, 1)
+
+	// Recomputed once older
+	util.Clock = util.ClockMock{Time: graphCacheTime.Add(3 * time.Minute)}
+	_, payload, age = GraphNamespacesCached(nil, o)
+	assert.Equal(2, payload)
+	assert.Zero(age)
+
+	// Another graph type or a viewer accessing other namespaces don't share the graph
+	_, payload, _ = GraphNamespacesCached(nil, namespacesGraphOptions(url.Values{"namespaces": {"bookinfo"}, "graphType": {"workload"}}, "bookinfo"))
+	assert.Equal(3, payload)
+	_, payload, _ = GraphNamespacesCached(nil, namespacesGraphOptions(url.Values{"namespaces": {"bookinfo"}, "graphType": {"app"}}, "bookinfo", "travels"))
+	assert.Equal(4, payload)
+
+	// The graphs of a past time are not cached
+	past := namespacesGraphOptions(url.Values{"namespaces": {"bookinfo"}, "queryTime": {"1646920000"}}, "bookinfo")
+	GraphNamespacesCached(nil, past)
+	GraphNamespacesCached(nil, past)
+	assert.Len(*computed, 6)
+}
+
+func TestGraphNamespacesCachedDisabled(t *testing.T) {
+	assert := assert.New(t)
+	graphCache, computed := setupGraphCache(t)
+	conf := config.NewConfig()
+	config.Set(conf)
+	o := namespacesGraphOptions(url.Values{"namespaces": {"bookinfo"}}, "bookinfo")
+
+	GraphNamespacesCached(nil, o)
+	_, _, age := GraphNamespacesCached(nil, o)
+	assert.Zero(age)
+	assert.Len(*computed, 2)
+	assert.Empty(graphCache.requests)
+}
+
+func TestRefreshViewedGraphs(t *testing.T) {
+	assert := assert.New(t)
+	graphCache, computed := setupGraphCache(t)
+
+	viewed := namespacesGraphOptions(url.Values{"namespaces": {"bookinfo"}}, "bookinfo")
+	GraphNamespacesCached(nil, viewed)
+	util.Clock = util.ClockMock{Time: graphCacheTime.Add(-10 * time.Minute)}
+	GraphNamespacesCached(nil, namespacesGraphOptions(url.Values{"namespaces": {"bookinfo"}, "graphType": {"service"}}, "bookinfo"))
+	assert.Len(*computed, 2)
+
+	// Only the graph viewed within the inactivity period is refreshed, at the refresh time
+	refreshTime := graphCacheTime.Add(time.Minute)
+	util.Clock = util.ClockMock{Time: refreshTime}
+	assert.Equal(1, refreshViewedGraphs(graphCache, nil))
+	assert.Equal([]int64{graphCacheTime.Unix(), graphCacheTime.Unix(), refreshTime.Unix()}, *computed)
+	assert.Len(graphCache.requests, 1)
+
+	// The refreshed graph is served
+	util.Clock = util.ClockMock{Time: refreshTime.Add(30 * time.Second)}
+	_, payload, age := GraphNamespacesCached(nil, viewed)
+	assert.Equal(3, payload)
+	assert.Equal(30*time.Second, age)
+
+	// A failed refresh keeps the previous graph
+	computeNamespacesGraph = func(business *business.Layer, o graph.Options) (int, interface{}) {
+		graph.Error("Prometheus is unavailable")
+		return http.StatusOK, nil
+	}
+	assert.Equal(0, refreshViewedGraphs(graphCache, nil))
+	_, payload, _ = GraphNamespacesCached(nil, viewed)
+	assert.Equal(3, payload)
+}
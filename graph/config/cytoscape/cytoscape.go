@@ -96,6 +96,9 @@ type EdgeData struct {
 	DestPrincipal   string          `json:"destPrincipal,omitempty"`   // principal used for the edge destination
 	IsMTLS          string          `json:"isMTLS,omitempty"`          // set to the percentage of traffic using a mutual TLS connection
 	ResponseTime    string          `json:"responseTime,omitempty"`    // in millis
+	RetryExhausted  string          `json:"retryExhausted,omitempty"`  // percentage of requests failed after exhausting their retries
+	RetryRate       string          `json:"retryRate,omitempty"`       // retries per second
+	RetrySuccess    string          `json:"retrySuccess,omitempty"`    // percentage of requests succeeding only after a retry
 	SourcePrincipal string          `json:"sourcePrincipal,omitempty"` // principal used for the edge source
 	Traffic         ProtocolTraffic `json:"traffic,omitempty"`         // traffic rates for the edge protocol
 }
@@ -114,10 +117,12 @@ type Elements struct {
 }
 
 type Config struct {
-	Timestamp int64    `json:"timestamp"`
-	Duration  int64    `json:"duration"`
-	GraphType string   `json:"graphType"`
-	Elements  Elements `json:"elements"`
+	Timestamp int64          `json:"timestamp"`
+	Duration  int64          `json:"duration"`
+	GraphType string         `json:"graphType"`
+	Filters   *graph.Filters `json:"filters,omitempty"`
+	Trimmed   bool           `json:"trimmed,omitempty"` // true when the unhealthyOnly filter removed nodes
+	Elements  Elements       `json:"elements"`
 }
 
 func nodeHash(id string) string {
@@ -197,6 +202,10 @@ func NewConfig(trafficMap graph.TrafficMap, o graph.ConfigOptions) (result Confi
 		GraphType: o.GraphType,
 		Elements:  elements,
 	}
+	if !o.Filters.IsEmpty() {
+		filters := o.Filters
+		result.Filters = &filters
+	}
 	return result
 }
 
@@ -346,6 +355,15 @@ func addEdgeTelemetry(e *graph.Edge, ed *EdgeData) {
 		responseTime := val.(float64)
 		ed.ResponseTime = fmt.Sprintf("%.0f", responseTime)
 	}
+	if val, ok := e.Metadata[graph.RetryRate]; ok {
+		ed.RetryRate = fmt.Sprintf("%.2f", val.(float64))
+	}
+	if val, ok := e.Metadata[graph.RetrySuccess]; ok {
+		ed.RetrySuccess = fmt.Sprintf("%.1f", val.(float64))
+	}
+	if val, ok := e.Metadata[graph.RetryExhausted]; ok {
+		ed.RetryExhausted = fmt.Sprintf("%.1f", val.(float64))
+	}
 
 	// an edge represents traffic for at most one protocol
 	for _, p := range graph.Protocols {
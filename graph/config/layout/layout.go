@@ -0,0 +1,395 @@
+// Layout ConfigVendor.
+//
+// The layout vendor returns the cytoscape elements along with pre-computed positions, for the tools rendering the graph
+// without a layout engine (e.g. to embed it in reports). The positions are computed by a layered (Sugiyama) layout,
+// from left to right like the graph of the Kiali UI. The layout is deterministic: the same traffic always gets the
+// same positions.
+//
+// The layout phases are:
+//  1. Cycle removal: the edges closing a cycle are reversed.
+//  2. Layering: every node gets the layer of its longest path from a source node. Edges spanning several layers
+//     are split by virtual nodes, one per crossed layer.
+//  3. Ordering: the nodes of every layer are ordered by the barycenter of their neighbours, to reduce the crossings.
+//  4. Positioning: the layers are spaced horizontally, the nodes of a layer are spaced vertically and centered.
+//
+// The boxes (compound nodes) are positioned at the center of the bounding box of their children.
+package layout
+
+import (
+	"math"
+	"sort"
+
+	"github.com/kiali/kiali/graph"
+	"github.com/kiali/kiali/graph/config/cytoscape"
+)
+
+const (
+	// LayerSpacing is the horizontal distance between two layers
+	LayerSpacing = 200.0
+	// NodeSpacing is the vertical distance between two nodes of a layer
+	NodeSpacing = 100.0
+	// orderingSweeps is the number of down and up sweeps ordering the layers
+	orderingSweeps = 4
+)
+
+type Position struct {
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
+}
+
+// Bounds is the bounding box of the children of a box
+type Bounds struct {
+	X1 float64 `json:"x1"`
+	Y1 float64 `json:"y1"`
+	X2 float64 `json:"x2"`
+	Y2 float64 `json:"y2"`
+}
+
+type NodeWrapper struct {
+	Data     *cytoscape.NodeData `json:"data"`
+	Position Position            `json:"position"`
+	Bounds   *Bounds             `json:"bounds,omitempty"` // only set for boxes
+}
+
+type Elements struct {
+	Nodes []*NodeWrapper           `json:"nodes"`
+	Edges []*cytoscape.EdgeWrapper `json:"edges"`
+}
+
+// Config is the cytoscape config with the positions of the nodes
+// swagger:model graphLayout
+type Config struct {
+	Timestamp int64          `json:"timestamp"`
+	Duration  int64          `json:"duration"`
+	GraphType string         `json:"graphType"`
+	Filters   *graph.Filters `json:"filters,omitempty"`
+	Trimmed   bool           `json:"trimmed,omitempty"` // true when the unhealthyOnly filter removed nodes
+	Width     float64        `json:"width"`
+	Height    float64        `json:"height"`
+	Elements  Elements       `json:"elements"`
+}
+
+// NewConfig is required by the graph/ConfigVendor interface
+func NewConfig(trafficMap graph.TrafficMap, o graph.ConfigOptions) (result Config) {
+	cyConfig := cytoscape.NewConfig(trafficMap, o)
+
+	result = Config{
+		Timestamp: cyConfig.Timestamp,
+		Duration:  cyConfig.Duration,
+		GraphType: cyConfig.GraphType,
+		Filters:   cyConfig.Filters,
+		Elements: Elements{
+			Nodes: make([]*NodeWrapper, 0, len(cyConfig.Elements.Nodes)),
+			Edges: cyConfig.Elements.Edges,
+		},
+	}
+
+	// The nodes are sorted by id (a hash of the node), which keeps the layout deterministic
+	ids := []string{}
+	for _, n := range cyConfig.Elements.Nodes {
+		if n.Data.IsBox == "" {
+			ids = append(ids, n.Data.ID)
+		}
+	}
+	sort.Strings(ids)
+	edges := [][2]string{}
+	for _, e := range cyConfig.Elements.Edges {
+		edges = append(edges, [2]string{e.Data.Source, e.Data.Target})
+	}
+	positions := computeLayout(ids, edges)
+
+	for _, n := range cyConfig.Elements.Nodes {
+		result.Elements.Nodes = append(result.Elements.Nodes, &NodeWrapper{Data: n.Data, Position: positions[n.Data.ID]})
+	}
+	positionBoxes(result.Elements.Nodes)
+
+	for _, p := range positions {
+		result.Width = math.Max(result.Width, p.X)
+		result.Height = math.Max(result.Height, p.Y)
+	}
+	return result
+}
+
+// computeLayout returns the positions of the nodes, the edges being the source and the target ids
+func computeLayout(ids []string, edges [][2]string) map[string]Position {
+	l := newLayeredGraph(ids, edges)
+	l.removeCycles()
+	l.assignLayers()
+	l.splitLongEdges()
+	l.orderLayers()
+	return l.positions()
+}
+
+// positionBoxes positions the boxes at the center of their children, inner boxes first as they are children of the
+// outer boxes
+func positionBoxes(nodes []*NodeWrapper) {
+	children := map[string][]*NodeWrapper{}
+	for _, n := range nodes {
+		if n.Data.Parent != "" {
+			children[n.Data.Parent] = append(children[n.Data.Parent], n)
+		}
+	}
+	// Boxes are sorted from the outer to the inner ones
+	for i := len(nodes) - 1; i >= 0; i-- {
+		box := nodes[i]
+		if box.Data.IsBox == "" || len(children[box.Data.ID]) == 0 {
+			continue
+		}
+		bounds := Bounds{X1: math.Inf(1), Y1: math.Inf(1), X2: math.Inf(-1), Y2: math.Inf(-1)}
+		for _, child := range children[box.Data.ID] {
+			if child.Bounds != nil {
+				bounds.X1 = math.Min(bounds.X1, child.Bounds.X1)
+				bounds.Y1 = math.Min(bounds.Y1, child.Bounds.Y1)
+				bounds.X2 = math.Max(bounds.X2, child.Bounds.X2)
+				bounds.Y2 = math.Max(bounds.Y2, child.Bounds.Y2)
+			} else {
+				bounds.X1 = math.Min(bounds.X1, child.Position.X)
+				bounds.Y1 = math.Min(bounds.Y1, child.Position.Y)
+				bounds.X2 = math.Max(bounds.X2, child.Position.X)
+				bounds.Y2 = math.Max(bounds.Y2, child.Position.Y)
+			}
+		}
+		box.Bounds = &bounds
+		box.Position = Position{X: (bounds.X1 + bounds.X2) / 2, Y: (bounds.Y1 + bounds.Y2) / 2}
+	}
+}
+
+// layeredGraph holds the state of the layout. Nodes are referenced by index, the real nodes come first followed by
+// the virtual nodes splitting the long edges.
+type layeredGraph struct {
+	ids    []string
+	succ   [][]int
+	pred   [][]int
+	layer  []int
+	layers [][]int
+}
+
+func newLayeredGraph(ids []string, edges [][2]string) *layeredGraph {
+	l := &layeredGraph{
+		ids:  ids,
+		succ: make([][]int, len(ids)),
+		pred: make([][]int, len(ids)),
+	}
+	index := make(map[string]int, len(ids))
+	for i, id := range ids {
+		index[id] = i
+	}
+	seen := map[[2]int]bool{}
+	for _, e := range edges {
+		from, okFrom := index[e[0]]
+		to, okTo := index[e[1]]
+		// Edges of the boxes are ignored, self-loops don't affect the layout, parallel edges (one per protocol)
+		// count once
+		if !okFrom || !okTo || from == to || seen[[2]int{from, to}] {
+			continue
+		}
+		seen[[2]int{from, to}] = true
+		l.succ[from] = append(l.succ[from], to)
+		l.pred[to] = append(l.pred[to], from)
+	}
+	return l
+}
+
+// removeCycles reverses the edges going back to a node being visited by a depth-first search
+func (l *layeredGraph) removeCycles() {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make([]int, len(l.ids))
+	var reversed [][2]int
+	var visit func(n int)
+	visit = func(n int) {
+		state[n] = visiting
+		for _, s := range l.succ[n] {
+			switch state[s] {
+			case visiting:
+				reversed = append(reversed, [2]int{n, s})
+			case unvisited:
+				visit(s)
+			}
+		}
+		state[n] = visited
+	}
+	// Sources first, so the cycles are broken as close as possible to their entry
+	for n := range l.ids {
+		if len(l.pred[n]) == 0 && state[n] == unvisited {
+			visit(n)
+		}
+	}
+	for n := range l.ids {
+		if state[n] == unvisited {
+			visit(n)
+		}
+	}
+
+	for _, e := range reversed {
+		l.succ[e[0]] = remove(l.succ[e[0]], e[1])
+		l.pred[e[1]] = remove(l.pred[e[1]], e[0])
+		if !contains(l.succ[e[1]], e[0]) {
+			l.succ[e[1]] = append(l.succ[e[1]], e[0])
+			l.pred[e[0]] = append(l.pred[e[0]], e[1])
+		}
+	}
+}
+
+// assignLayers sets the layer of every node to the length of its longest path from a source node
+func (l *layeredGraph) assignLayers() {
+	l.layer = make([]int, len(l.ids))
+	inDegree := make([]int, len(l.ids))
+	queue := []int{}
+	for n := range l.ids {
+		inDegree[n] = len(l.pred[n])
+		if inDegree[n] == 0 {
+			queue = append(queue, n)
+		}
+	}
+	// Kahn's topological order, the graph is acyclic after the cycle removal
+	for len(queue) > 0 {
+		n := queue[0]
+		queue = queue[1:]
+		for _, s := range l.succ[n] {
+			if l.layer[n]+1 > l.layer[s] {
+				l.layer[s] = l.layer[n] + 1
+			}
+			inDegree[s]--
+			if inDegree[s] == 0 {
+				queue = append(queue, s)
+			}
+		}
+	}
+}
+
+// splitLongEdges adds a virtual node in every layer crossed by an edge, so that all edges join adjacent layers
+func (l *layeredGraph) splitLongEdges() {
+	realNodes := len(l.ids)
+	for n := 0; n < realNodes; n++ {
+		for i, s := range l.succ[n] {
+			if l.layer[s]-l.layer[n] <= 1 {
+				continue
+			}
+			prev := n
+			for layer := l.layer[n] + 1; layer < l.layer[s]; layer++ {
+				virtual := len(l.ids)
+				l.ids = append(l.ids, "")
+				l.layer = append(l.layer, layer)
+				l.succ = append(l.succ, nil)
+				l.pred = append(l.pred, []int{prev})
+				if prev == n {
+					l.succ[n][i] = virtual
+				} else {
+					l.succ[prev] = []int{virtual}
+				}
+				prev = virtual
+			}
+			l.succ[prev] = []int{s}
+			l.pred[s] = replace(l.pred[s], n, prev)
+		}
+	}
+
+	maxLayer := 0
+	for _, layer := range l.layer {
+		if layer > maxLayer {
+			maxLayer = layer
+		}
+	}
+	l.layers = make([][]int, maxLayer+1)
+	for n, layer := range l.layer {
+		l.layers[layer] = append(l.layers[layer], n)
+	}
+}
+
+// orderLayers sorts the nodes of every layer by the barycenter of the positions of their neighbours in the previous
+// layer (down sweep) or in the next layer (up sweep)
+func (l *layeredGraph) orderLayers() {
+	order := make([]float64, len(l.ids))
+	setOrder := func(layer []int) {
+		for i, n := range layer {
+			order[n] = float64(i)
+		}
+	}
+	for _, layer := range l.layers {
+		setOrder(layer)
+	}
+	sortLayer := func(layer []int, neighbours [][]int) {
+		barycenter := make(map[int]float64, len(layer))
+		for _, n := range layer {
+			if len(neighbours[n]) == 0 {
+				// Keep its current position
+				barycenter[n] = order[n]
+				continue
+			}
+			sum := 0.0
+			for _, m := range neighbours[n] {
+				sum += order[m]
+			}
+			barycenter[n] = sum / float64(len(neighbours[n]))
+		}
+		// Stable: ties keep their current order
+		sort.SliceStable(layer, func(i, j int) bool {
+			return barycenter[layer[i]] < barycenter[layer[j]]
+		})
+		setOrder(layer)
+	}
+
+	for sweep := 0; sweep < orderingSweeps; sweep++ {
+		for i := 1; i < len(l.layers); i++ {
+			sortLayer(l.layers[i], l.pred)
+		}
+		for i := len(l.layers) - 2; i >= 0; i-- {
+			sortLayer(l.layers[i], l.succ)
+		}
+	}
+}
+
+// positions spaces the layers horizontally and centers the nodes of every layer vertically. Positions start at 0.
+func (l *layeredGraph) positions() map[string]Position {
+	maxNodes := 0
+	for _, layer := range l.layers {
+		if len(layer) > maxNodes {
+			maxNodes = len(layer)
+		}
+	}
+	positions := map[string]Position{}
+	for i, layer := range l.layers {
+		offset := float64(maxNodes-len(layer)) * NodeSpacing / 2
+		for j, n := range layer {
+			if l.ids[n] == "" {
+				// Virtual node
+				continue
+			}
+			positions[l.ids[n]] = Position{X: float64(i) * LayerSpacing, Y: offset + float64(j)*NodeSpacing}
+		}
+	}
+	return positions
+}
+
+func contains(nodes []int, n int) bool {
+	for _, m := range nodes {
+		if m == n {
+			return true
+		}
+	}
+	return false
+}
+
+func remove(nodes []int, n int) []int {
+	result := nodes[:0]
+	for _, m := range nodes {
+		if m != n {
+			result = append(result, m)
+		}
+	}
+	return result
+}
+
+func replace(nodes []int, old, new int) []int {
+	for i, m := range nodes {
+		if m == old {
+			nodes[i] = new
+			return nodes
+		}
+	}
+	return nodes
+}
@@ -0,0 +1,91 @@
+package layout
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kiali/kiali/graph"
+)
+
+func TestComputeLayout(t *testing.T) {
+	assert := assert.New(t)
+
+	// a -> d crosses the layer of b and c, e has no traffic
+	positions := computeLayout(
+		[]string{"a", "b", "c", "d", "e"},
+		[][2]string{{"a", "b"}, {"a", "c"}, {"a", "d"}, {"b", "d"}, {"c", "d"}},
+	)
+
+	assert.Len(positions, 5)
+	assert.Equal(Position{X: 0, Y: 50}, positions["a"])
+	assert.Equal(Position{X: 200, Y: 0}, positions["b"])
+	assert.Equal(Position{X: 200, Y: 100}, positions["c"])
+	assert.Equal(Position{X: 400, Y: 100}, positions["d"])
+	assert.Equal(Position{X: 0, Y: 150}, positions["e"])
+}
+
+func TestComputeLayoutWithCycle(t *testing.T) {
+	assert := assert.New(t)
+
+	// c -> a closes the cycle and is reversed, the self-loop is ignored
+	positions := computeLayout(
+		[]string{"a", "b", "c"},
+		[][2]string{{"a", "b"}, {"b", "c"}, {"c", "a"}, {"b", "b"}},
+	)
+
+	assert.Len(positions, 3)
+	assert.Equal(Position{X: 0, Y: 50}, positions["a"])
+	assert.Equal(Position{X: 200, Y: 0}, positions["b"])
+	assert.Equal(Position{X: 400, Y: 50}, positions["c"])
+}
+
+func TestNewConfig(t *testing.T) {
+	assert := assert.New(t)
+
+	o := graph.ConfigOptions{BoxBy: graph.BoxByNamespace}
+	o.GraphType = graph.GraphTypeWorkload
+
+	first := NewConfig(fakeTrafficMap(), o)
+
+	// 3 workloads and their namespace box
+	assert.Len(first.Elements.Nodes, 4)
+	assert.Len(first.Elements.Edges, 2)
+	assert.Equal(400.0, first.Width)
+	assert.Equal(0.0, first.Height)
+
+	positions := map[string]Position{}
+	for _, n := range first.Elements.Nodes {
+		if n.Data.IsBox != "" {
+			assert.Equal(&Bounds{X1: 0, Y1: 0, X2: 400, Y2: 0}, n.Bounds)
+		} else {
+			assert.Nil(n.Bounds)
+		}
+		positions[n.Data.Workload] = n.Position
+	}
+	assert.Equal(Position{X: 0, Y: 0}, positions["productpage-v1"])
+	assert.Equal(Position{X: 200, Y: 0}, positions["reviews-v1"])
+	assert.Equal(Position{X: 400, Y: 0}, positions["ratings-v1"])
+	assert.Equal(Position{X: 200, Y: 0}, positions[""])
+
+	// The same traffic always gets the same layout
+	for i := 0; i < 10; i++ {
+		assert.Equal(first, NewConfig(fakeTrafficMap(), o))
+	}
+}
+
+func fakeTrafficMap() graph.TrafficMap {
+	trafficMap := graph.NewTrafficMap()
+
+	productpage := graph.NewNode(graph.Unknown, "bookinfo", "", "bookinfo", "productpage-v1", "productpage", "v1", graph.GraphTypeWorkload)
+	reviews := graph.NewNode(graph.Unknown, "bookinfo", "", "bookinfo", "reviews-v1", "reviews", "v1", graph.GraphTypeWorkload)
+	ratings := graph.NewNode(graph.Unknown, "bookinfo", "", "bookinfo", "ratings-v1", "ratings", "v1", graph.GraphTypeWorkload)
+	trafficMap[productpage.ID] = &productpage
+	trafficMap[reviews.ID] = &reviews
+	trafficMap[ratings.ID] = &ratings
+
+	productpage.AddEdge(&reviews).Metadata[graph.ProtocolKey] = "http"
+	reviews.AddEdge(&ratings).Metadata[graph.ProtocolKey] = "http"
+
+	return trafficMap
+}
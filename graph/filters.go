@@ -0,0 +1,95 @@
+package graph
+
+// Filters.go holds the traffic filters of a graph request.
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// The supported security filters
+const (
+	SecurityMTLS      string = "mtls"
+	SecurityPlaintext string = "plaintext"
+)
+
+// Filters restrict the traffic represented in the graph. Combined filters are ANDed.
+type Filters struct {
+	// Protocols of the edges to keep: http, grpc and/or tcp. Empty for any protocol.
+	Protocols []string `json:"protocols,omitempty"`
+	// Security of the edges to keep: mtls or plaintext. Empty for any security.
+	Security string `json:"security,omitempty"`
+	// UnhealthyOnly keeps only the nodes with a Degraded or Failure health, along with their immediate neighbors.
+	UnhealthyOnly bool `json:"unhealthyOnly,omitempty"`
+}
+
+// NewFilters parses the protocols (csl), security and unhealthyOnly query params, it panics with BadRequest on
+// invalid values
+func NewFilters(params url.Values) Filters {
+	filters := Filters{}
+	if protocols := params.Get("protocols"); protocols != "" {
+		for _, protocol := range strings.Split(protocols, ",") {
+			protocol = strings.ToLower(strings.TrimSpace(protocol))
+			switch protocol {
+			case grpc, http, tcp:
+				filters.Protocols = append(filters.Protocols, protocol)
+			default:
+				BadRequest(fmt.Sprintf("Invalid protocols [%s]", protocols))
+			}
+		}
+	}
+	if security := params.Get("security"); security != "" {
+		switch security {
+		case SecurityMTLS, SecurityPlaintext:
+			filters.Security = security
+		default:
+			BadRequest(fmt.Sprintf("Invalid security [%s]", security))
+		}
+	}
+	if unhealthyOnly := params.Get("unhealthyOnly"); unhealthyOnly != "" {
+		var err error
+		if filters.UnhealthyOnly, err = strconv.ParseBool(unhealthyOnly); err != nil {
+			BadRequest(fmt.Sprintf("Invalid unhealthyOnly [%s]", unhealthyOnly))
+		}
+	}
+	return filters
+}
+
+// IsEmpty returns true when no filter is applied
+func (f Filters) IsEmpty() bool {
+	return len(f.Protocols) == 0 && f.Security == "" && !f.UnhealthyOnly
+}
+
+// IncludesProtocol returns true when the protocol filter accepts the protocol
+func (f Filters) IncludesProtocol(protocol string) bool {
+	if len(f.Protocols) == 0 {
+		return true
+	}
+	for _, p := range f.Protocols {
+		if p == protocol {
+			return true
+		}
+	}
+	return false
+}
+
+// MatchesEdge returns true when the edge passes all the filters. The security of the edge is read from its
+// isMTLS metadata (percentage of mTLS traffic), an edge partially secured matches both security filters.
+func (f Filters) MatchesEdge(e *Edge) bool {
+	if len(f.Protocols) > 0 {
+		protocol, _ := e.Metadata[ProtocolKey].(string)
+		if !f.IncludesProtocol(protocol) {
+			return false
+		}
+	}
+	mtls, _ := e.Metadata[IsMTLS].(float64)
+	switch f.Security {
+	case SecurityMTLS:
+		return mtls > 0
+	case SecurityPlaintext:
+		return mtls < 100
+	}
+	return true
+}
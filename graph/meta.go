@@ -31,6 +31,9 @@ const (
 	IsServiceEntry  MetadataKey = "isServiceEntry"
 	ProtocolKey     MetadataKey = "protocol"
 	ResponseTime    MetadataKey = "responseTime"
+	RetryExhausted  MetadataKey = "retryExhausted" // percentage of requests failed after exhausting their retries
+	RetryRate       MetadataKey = "retryRate"      // retries per second
+	RetrySuccess    MetadataKey = "retrySuccess"   // percentage of requests succeeding only after a retry
 	SourcePrincipal MetadataKey = "sourcePrincipal"
 )
 
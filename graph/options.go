@@ -23,6 +23,7 @@ import (
 const (
 	VendorCytoscape        string = "cytoscape"
 	VendorIstio            string = "istio"
+	VendorLayout           string = "layout"
 	defaultConfigVendor    string = VendorCytoscape
 	defaultTelemetryVendor string = VendorIstio
 )
@@ -41,8 +42,8 @@ const (
 )
 
 const (
-	graphKindNamespace string = "namespace"
-	graphKindNode      string = "node"
+	GraphKindNamespace string = "namespace"
+	GraphKindNode      string = "node"
 )
 
 // NodeOptions are those that apply only to node-detail graphs
@@ -60,6 +61,7 @@ type NodeOptions struct {
 // CommonOptions are those supplied to Telemetry and Config Vendors
 type CommonOptions struct {
 	Duration  time.Duration
+	Filters   Filters // protocol and security filters of the traffic
 	GraphType string
 	Params    url.Values // make available the raw query params for vendor-specific handling
 	QueryTime int64      // unix time in seconds
@@ -123,6 +125,7 @@ func NewOptions(r *net_http.Request) Options {
 	namespaces := params.Get("namespaces") // csl of namespaces
 	queryTimeString := params.Get("queryTime")
 	telemetryVendor := params.Get("telemetryVendor")
+	filters := NewFilters(params)
 
 	if _, ok := params["appenders"]; ok {
 		appenderNames := strings.Split(params.Get("appenders"), ",")
@@ -137,7 +140,7 @@ func NewOptions(r *net_http.Request) Options {
 	}
 	if configVendor == "" {
 		configVendor = defaultConfigVendor
-	} else if configVendor != VendorCytoscape {
+	} else if configVendor != VendorCytoscape && configVendor != VendorLayout {
 		BadRequest(fmt.Sprintf("Invalid configVendor [%s]", configVendor))
 	}
 	if durationString == "" {
@@ -260,6 +263,7 @@ func NewOptions(r *net_http.Request) Options {
 			BoxBy: boxBy,
 			CommonOptions: CommonOptions{
 				Duration:  time.Duration(duration),
+				Filters:   filters,
 				GraphType: graphType,
 				Params:    params,
 				QueryTime: queryTime,
@@ -273,6 +277,7 @@ func NewOptions(r *net_http.Request) Options {
 			Namespaces:           namespaceMap,
 			CommonOptions: CommonOptions{
 				Duration:  time.Duration(duration),
+				Filters:   filters,
 				GraphType: graphType,
 				Params:    params,
 				QueryTime: queryTime,
@@ -293,15 +298,30 @@ func NewOptions(r *net_http.Request) Options {
 	return options
 }
 
+// SetQueryTime moves the options of a graph to a new query time, the query durations of the young namespaces are
+// adjusted accordingly
+func (o *Options) SetQueryTime(queryTime int64) {
+	o.ConfigOptions.QueryTime = queryTime
+	o.TelemetryOptions.QueryTime = queryTime
+	namespaces := NewNamespaceInfoMap()
+	for name, info := range o.Namespaces {
+		if creationTime, found := o.AccessibleNamespaces[name]; found {
+			info.Duration = getSafeNamespaceDuration(name, creationTime, o.TelemetryOptions.Duration, queryTime)
+		}
+		namespaces[name] = info
+	}
+	o.Namespaces = namespaces
+}
+
 // GetGraphKind will return the kind of graph represented by the options.
 func (o *TelemetryOptions) GetGraphKind() string {
 	if o.NodeOptions.App != "" ||
 		o.NodeOptions.Version != "" ||
 		o.NodeOptions.Workload != "" ||
 		o.NodeOptions.Service != "" {
-		return graphKindNode
+		return GraphKindNode
 	}
-	return graphKindNamespace
+	return GraphKindNamespace
 }
 
 // getAccessibleNamespaces returns a Set of all namespaces accessible to the user.
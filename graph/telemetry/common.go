@@ -8,6 +8,7 @@ import (
 
 	"github.com/kiali/kiali/graph"
 	"github.com/kiali/kiali/log"
+	"github.com/kiali/kiali/models"
 )
 
 // MergeTrafficMaps typically combines two namespace traffic maps. It ensures that we only
@@ -99,6 +100,125 @@ func isInaccessible(n *graph.Node, accessibleNamespaces map[string]time.Time) bo
 	}
 }
 
+// FilterTraffic removes the edges not matching the graph filters, along with the nodes left without traffic. Nodes
+// without any edge before filtering (i.e. idle nodes) are kept. It is called after appender work is complete.
+func FilterTraffic(trafficMap graph.TrafficMap, filters graph.Filters) {
+	if filters.IsEmpty() {
+		return
+	}
+	withTraffic := make(map[string]bool)
+	withFilteredTraffic := make(map[string]bool)
+	for _, n := range trafficMap {
+		edges := []*graph.Edge{}
+		for _, e := range n.Edges {
+			withTraffic[n.ID] = true
+			withTraffic[e.Dest.ID] = true
+			if filters.MatchesEdge(e) {
+				withFilteredTraffic[n.ID] = true
+				withFilteredTraffic[e.Dest.ID] = true
+				edges = append(edges, e)
+			}
+		}
+		n.Edges = edges
+	}
+	for id := range withTraffic {
+		if !withFilteredTraffic[id] {
+			delete(trafficMap, id)
+		}
+	}
+}
+
+// FilterUnhealthy trims the graph to the nodes with a Degraded or Failure health, along with their immediate
+// neighbors and the edges between them and the unhealthy nodes. The health of a node is evaluated inline from the
+// error ratios of its HTTP and GRPC traffic, against the health_config tolerances: services are evaluated on their
+// inbound traffic, the other nodes on both directions. It returns true when nodes were removed.
+func FilterUnhealthy(trafficMap graph.TrafficMap) bool {
+	inbound := make(map[string][]*graph.Edge)
+	for _, n := range trafficMap {
+		for _, e := range n.Edges {
+			inbound[e.Dest.ID] = append(inbound[e.Dest.ID], e)
+		}
+	}
+	unhealthy := make(map[string]bool)
+	for id, n := range trafficMap {
+		switch nodeHealthStatus(n, inbound[id]) {
+		case models.HealthStatusDegraded, models.HealthStatusFailure:
+			unhealthy[id] = true
+		}
+	}
+	keep := make(map[string]bool)
+	for id, n := range trafficMap {
+		if !unhealthy[id] {
+			continue
+		}
+		keep[id] = true
+		for _, e := range n.Edges {
+			keep[e.Dest.ID] = true
+		}
+		for _, e := range inbound[id] {
+			keep[e.Source.ID] = true
+		}
+	}
+
+	trimmed := false
+	for id, n := range trafficMap {
+		if !keep[id] {
+			delete(trafficMap, id)
+			trimmed = true
+			continue
+		}
+		edges := []*graph.Edge{}
+		for _, e := range n.Edges {
+			if unhealthy[n.ID] || unhealthy[e.Dest.ID] {
+				edges = append(edges, e)
+			}
+		}
+		n.Edges = edges
+	}
+	return trimmed
+}
+
+// nodeHealthStatus evaluates the request health of a node given its inbound edges
+func nodeHealthStatus(n *graph.Node, inbound []*graph.Edge) models.HealthStatus {
+	var name string
+	switch n.NodeType {
+	case graph.NodeTypeApp:
+		name = n.App
+	case graph.NodeTypeService:
+		name = n.Service
+	case graph.NodeTypeWorkload:
+		name = n.Workload
+	}
+	status := models.RequestsStatus(edgeResponseRates(inbound), "inbound", n.Namespace, n.NodeType, name)
+	if n.NodeType != graph.NodeTypeService {
+		status = models.WorstHealthStatus(status, models.RequestsStatus(edgeResponseRates(n.Edges), "outbound", n.Namespace, n.NodeType, name))
+	}
+	return status
+}
+
+// edgeResponseRates sums the request rates of the edges by protocol and response code
+func edgeResponseRates(edges []*graph.Edge) map[string]map[string]float64 {
+	rates := make(map[string]map[string]float64)
+	for _, e := range edges {
+		protocol, _ := e.Metadata[graph.ProtocolKey].(string)
+		for _, p := range []graph.Protocol{graph.GRPC, graph.HTTP} {
+			if p.Name != protocol {
+				continue
+			}
+			responses, _ := e.Metadata[p.EdgeResponses].(graph.Responses)
+			for code, detail := range responses {
+				if rates[protocol] == nil {
+					rates[protocol] = make(map[string]float64)
+				}
+				for _, rate := range detail.Flags {
+					rates[protocol][code] += rate
+				}
+			}
+		}
+	}
+	return rates
+}
+
 // MarkTrafficGenerators set IsRoot metadata. It is called after appender work is complete.
 func MarkTrafficGenerators(trafficMap graph.TrafficMap) {
 	destMap := make(map[string]*graph.Node)
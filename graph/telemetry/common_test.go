@@ -0,0 +1,119 @@
+package telemetry
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/graph"
+)
+
+func TestFilterTraffic(t *testing.T) {
+	assert := assert.New(t)
+
+	trafficMap := graph.NewTrafficMap()
+	productpage := graph.NewNode(graph.Unknown, "bookinfo", "", "bookinfo", "productpage-v1", "productpage", "v1", graph.GraphTypeVersionedApp)
+	reviews := graph.NewNode(graph.Unknown, "bookinfo", "", "bookinfo", "reviews-v1", "reviews", "v1", graph.GraphTypeVersionedApp)
+	mongodb := graph.NewNode(graph.Unknown, "bookinfo", "", "bookinfo", "mongodb-v1", "mongodb", "v1", graph.GraphTypeVersionedApp)
+	idle := graph.NewNode(graph.Unknown, "bookinfo", "", "bookinfo", "details-v1", "details", "v1", graph.GraphTypeVersionedApp)
+	for _, n := range []*graph.Node{&productpage, &reviews, &mongodb, &idle} {
+		trafficMap[n.ID] = n
+	}
+	toReviews := productpage.AddEdge(&reviews)
+	toReviews.Metadata[graph.ProtocolKey] = "grpc"
+	toReviews.Metadata[graph.IsMTLS] = 100.0
+	toMongodb := reviews.AddEdge(&mongodb)
+	toMongodb.Metadata[graph.ProtocolKey] = "tcp"
+	toMongodb.Metadata[graph.IsMTLS] = 100.0
+
+	// Both filters must match
+	FilterTraffic(trafficMap, graph.Filters{Protocols: []string{"tcp"}, Security: graph.SecurityMTLS})
+
+	assert.Len(trafficMap, 3)
+	assert.NotContains(trafficMap, productpage.ID)
+	assert.Len(trafficMap[reviews.ID].Edges, 1)
+	assert.Equal(mongodb.ID, trafficMap[reviews.ID].Edges[0].Dest.ID)
+	assert.Contains(trafficMap, idle.ID)
+
+	FilterTraffic(trafficMap, graph.Filters{Security: graph.SecurityPlaintext})
+	assert.Len(trafficMap, 1)
+	assert.Contains(trafficMap, idle.ID)
+}
+
+func TestFilterUnhealthy(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	trafficMap := graph.NewTrafficMap()
+	workload := func(name string) *graph.Node {
+		n := graph.NewNode(graph.Unknown, "bookinfo", "", "bookinfo", name, name, "v1", graph.GraphTypeWorkload)
+		trafficMap[n.ID] = &n
+		return &n
+	}
+	request := func(source, dest *graph.Node, code string, rate float64) {
+		var e *graph.Edge
+		for _, edge := range source.Edges {
+			if edge.Dest == dest {
+				e = edge
+			}
+		}
+		if e == nil {
+			e = source.AddEdge(dest)
+			e.Metadata[graph.ProtocolKey] = "http"
+		}
+		graph.AddToMetadata("http", rate, code, "-", "", source.Metadata, dest.Metadata, e.Metadata)
+	}
+	ingress := workload("istio-ingressgateway")
+	productpage := workload("productpage-v1")
+	details := workload("details-v1")
+	reviews := workload("reviews-v1")
+	ratings := workload("ratings-v1")
+	mongodb := workload("mongodb-v1")
+	client := workload("travels-v1")
+	hotels := workload("hotels-v1")
+
+	request(ingress, productpage, "200", 10)
+	request(productpage, details, "200", 10)
+	// 20% of 5xx fail reviews, and productpage calling it
+	request(productpage, reviews, "200", 8)
+	request(productpage, reviews, "503", 2)
+	request(reviews, ratings, "200", 10)
+	request(ratings, mongodb, "200", 10)
+	request(client, hotels, "200", 10)
+	// 5% of 4xx are under the degraded tolerance
+	request(client, hotels, "404", 0.5)
+
+	assert.True(FilterUnhealthy(trafficMap))
+
+	// The unhealthy nodes and their neighbors remain
+	assert.Len(trafficMap, 5)
+	for _, n := range []*graph.Node{ingress, productpage, details, reviews, ratings} {
+		assert.Contains(trafficMap, n.ID)
+	}
+	assert.Len(trafficMap[productpage.ID].Edges, 2)
+	assert.Len(trafficMap[reviews.ID].Edges, 1)
+	// The edges of the neighbors to healthy nodes are trimmed
+	assert.Empty(trafficMap[ratings.ID].Edges)
+
+	// Nothing else to trim
+	assert.False(FilterUnhealthy(trafficMap))
+	assert.Len(trafficMap, 5)
+}
+
+func TestFilterUnhealthyAllHealthy(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	trafficMap := graph.NewTrafficMap()
+	productpage := graph.NewNode(graph.Unknown, "bookinfo", "", "bookinfo", "productpage-v1", "productpage", "v1", graph.GraphTypeWorkload)
+	reviews := graph.NewNode(graph.Unknown, "bookinfo", "", "bookinfo", "reviews-v1", "reviews", "v1", graph.GraphTypeWorkload)
+	trafficMap[productpage.ID] = &productpage
+	trafficMap[reviews.ID] = &reviews
+	e := productpage.AddEdge(&reviews)
+	e.Metadata[graph.ProtocolKey] = "http"
+	graph.AddToMetadata("http", 10, "200", "-", "", productpage.Metadata, reviews.Metadata, e.Metadata)
+
+	assert.True(FilterUnhealthy(trafficMap))
+	assert.Empty(trafficMap)
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/prometheus/common/model"
 
+	"github.com/kiali/kiali/business"
 	"github.com/kiali/kiali/graph"
 	"github.com/kiali/kiali/graph/telemetry/istio/util"
 	"github.com/kiali/kiali/log"
@@ -41,7 +42,7 @@ func (a AggregateNodeAppender) AppendGraph(trafficMap graph.TrafficMap, globalIn
 
 	if globalInfo.PromClient == nil {
 		var err error
-		globalInfo.PromClient, err = prometheus.NewClient()
+		globalInfo.PromClient, err = business.NewPrometheusClient()
 		graph.CheckError(err)
 	}
 
@@ -82,7 +83,7 @@ func (a AggregateNodeAppender) appendGraph(trafficMap graph.TrafficMap, namespac
 	query := fmt.Sprintf(`(%s) OR (%s)`, httpQuery, tcpQuery)
 	*/
 	query := httpQuery
-	vector := promQuery(query, time.Unix(a.QueryTime, 0), client.GetContext(), client.API(), a)
+	vector := promQuery(query, time.Unix(a.QueryTime, 0), client, a)
 	a.injectAggregates(trafficMap, &vector)
 
 	// 2) query for requests originating from a workload inside of the namespace
@@ -102,7 +103,7 @@ func (a AggregateNodeAppender) appendGraph(trafficMap graph.TrafficMap, namespac
 	query = fmt.Sprintf(`(%s) OR (%s)`, httpQuery, tcpQuery)
 	*/
 	query = httpQuery
-	vector = promQuery(query, time.Unix(a.QueryTime, 0), client.GetContext(), client.API(), a)
+	vector = promQuery(query, time.Unix(a.QueryTime, 0), client, a)
 	a.injectAggregates(trafficMap, &vector)
 }
 
@@ -138,7 +139,7 @@ func (a AggregateNodeAppender) appendNodeGraph(trafficMap graph.TrafficMap, name
 	query := fmt.Sprintf(`(%s) OR (%s)`, httpQuery, tcpQuery)
 	*/
 	query := httpQuery
-	vector := promQuery(query, time.Unix(a.QueryTime, 0), client.GetContext(), client.API(), a)
+	vector := promQuery(query, time.Unix(a.QueryTime, 0), client, a)
 	a.injectAggregates(trafficMap, &vector)
 }
 
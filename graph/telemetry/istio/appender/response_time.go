@@ -8,6 +8,7 @@ import (
 
 	"github.com/prometheus/common/model"
 
+	"github.com/kiali/kiali/business"
 	"github.com/kiali/kiali/graph"
 	"github.com/kiali/kiali/graph/telemetry/istio/util"
 	"github.com/kiali/kiali/log"
@@ -52,7 +53,7 @@ func (a ResponseTimeAppender) AppendGraph(trafficMap graph.TrafficMap, globalInf
 
 	if globalInfo.PromClient == nil {
 		var err error
-		globalInfo.PromClient, err = prometheus.NewClient()
+		globalInfo.PromClient, err = business.NewPrometheusClient()
 		graph.CheckError(err)
 	}
 
@@ -83,7 +84,7 @@ func (a ResponseTimeAppender) appendGraph(trafficMap graph.TrafficMap, namespace
 		namespace,
 		int(duration.Seconds()), // range duration for the query
 		groupBy)
-	incomingVector := promQuery(query, time.Unix(a.QueryTime, 0), client.GetContext(), client.API(), a)
+	incomingVector := promQuery(query, time.Unix(a.QueryTime, 0), client, a)
 	a.populateResponseTimeMap(responseTimeMap, &incomingVector)
 
 	// 2) Outgoing: query source telemetry to capture namespace workloads' outgoing traffic
@@ -93,7 +94,7 @@ func (a ResponseTimeAppender) appendGraph(trafficMap graph.TrafficMap, namespace
 		namespace,
 		int(duration.Seconds()), // range duration for the query
 		groupBy)
-	outgoingVector := promQuery(query, time.Unix(a.QueryTime, 0), client.GetContext(), client.API(), a)
+	outgoingVector := promQuery(query, time.Unix(a.QueryTime, 0), client, a)
 	a.populateResponseTimeMap(responseTimeMap, &outgoingVector)
 
 	applyResponseTime(trafficMap, responseTimeMap)
@@ -0,0 +1,226 @@
+package appender
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/prometheus/common/model"
+
+	"github.com/kiali/kiali/business"
+	"github.com/kiali/kiali/graph"
+	"github.com/kiali/kiali/graph/telemetry/istio/util"
+	"github.com/kiali/kiali/log"
+	"github.com/kiali/kiali/prometheus"
+)
+
+const (
+	// RetriesAppenderName uniquely identifies the appender: retries
+	RetriesAppenderName = "retries"
+	// Envoy response flag: upstream retry limit exceeded
+	retryLimitExceededFlag = "URX"
+)
+
+// RetriesAppender is responsible for annotating edges with the retries performed by the source proxy.
+// Istio doesn't report retries directly, so they are derived from the request metrics: every retry reaches
+// the destination proxy as a new request, so the retry rate is the difference between the requests reported
+// by the destination proxy and the requests reported by the source proxy. The failed attempts reported by
+// the destination that don't show up as failures at the source are considered recovered by a retry, and
+// the requests flagged with URX by the source failed after exhausting their retries.
+// Edges without telemetry from both proxies (e.g. a destination without sidecar) are not annotated.
+// Only HTTP and gRPC requests are considered.
+// Name: retries
+type RetriesAppender struct {
+	GraphType          string
+	InjectServiceNodes bool
+	Namespaces         graph.NamespaceInfoMap
+	QueryTime          int64 // unix time in seconds
+}
+
+// retryRates holds the rates reported for an edge by both proxies
+type retryRates struct {
+	requests       float64 // reported by source
+	failed         float64 // reported by source
+	exhausted      float64 // reported by source
+	attempts       float64 // reported by destination
+	failedAttempts float64 // reported by destination
+}
+
+// Name implements Appender
+func (a RetriesAppender) Name() string {
+	return RetriesAppenderName
+}
+
+// AppendGraph implements Appender
+func (a RetriesAppender) AppendGraph(trafficMap graph.TrafficMap, globalInfo *graph.AppenderGlobalInfo, namespaceInfo *graph.AppenderNamespaceInfo) {
+	if len(trafficMap) == 0 {
+		return
+	}
+
+	if globalInfo.PromClient == nil {
+		var err error
+		globalInfo.PromClient, err = business.NewPrometheusClient()
+		graph.CheckError(err)
+	}
+
+	a.appendGraph(trafficMap, namespaceInfo.Namespace, globalInfo.PromClient)
+}
+
+func (a RetriesAppender) appendGraph(trafficMap graph.TrafficMap, namespace string, client *prometheus.Client) {
+	log.Tracef("Generating retries; namespace = %v", namespace)
+	duration := a.Namespaces[namespace].Duration
+
+	// query prometheus for the requests sent by the namespace workloads, as reported by each proxy. Retries
+	// are performed by the source proxy, so the namespace is matched on the source.
+	groupBy := "source_cluster,source_workload_namespace,source_workload,source_canonical_service,source_canonical_revision,destination_cluster,destination_service_namespace,destination_service,destination_service_name,destination_workload_namespace,destination_workload,destination_canonical_service,destination_canonical_revision,response_code,grpc_response_status,response_flags"
+	query := fmt.Sprintf(`sum(rate(%s{reporter="source",source_workload_namespace="%s"}[%vs])) by (%s) > 0`,
+		"istio_requests_total",
+		namespace,
+		int(duration.Seconds()), // range duration for the query
+		groupBy)
+	sourceVector := promQuery(query, time.Unix(a.QueryTime, 0), client, a)
+
+	query = fmt.Sprintf(`sum(rate(%s{reporter="destination",source_workload_namespace="%s"}[%vs])) by (%s) > 0`,
+		"istio_requests_total",
+		namespace,
+		int(duration.Seconds()), // range duration for the query
+		groupBy)
+	destVector := promQuery(query, time.Unix(a.QueryTime, 0), client, a)
+
+	// create map to quickly look up the retry rates
+	retriesMap := make(map[string]*retryRates)
+	a.populateRetriesMap(retriesMap, &sourceVector, true)
+	a.populateRetriesMap(retriesMap, &destVector, false)
+
+	applyRetries(trafficMap, retriesMap)
+}
+
+func (a RetriesAppender) populateRetriesMap(retriesMap map[string]*retryRates, vector *model.Vector, isSource bool) {
+	for _, s := range *vector {
+		m := s.Metric
+		lSourceCluster, sourceClusterOk := m["source_cluster"]
+		lSourceWlNs, sourceWlNsOk := m["source_workload_namespace"]
+		lSourceWl, sourceWlOk := m["source_workload"]
+		lSourceApp, sourceAppOk := m["source_canonical_service"]
+		lSourceVer, sourceVerOk := m["source_canonical_revision"]
+		lDestCluster, destClusterOk := m["destination_cluster"]
+		lDestSvcNs, destSvcNsOk := m["destination_service_namespace"]
+		lDestSvc, destSvcOk := m["destination_service"]
+		lDestSvcName, destSvcNameOk := m["destination_service_name"]
+		lDestWlNs, destWlNsOk := m["destination_workload_namespace"]
+		lDestWl, destWlOk := m["destination_workload"]
+		lDestApp, destAppOk := m["destination_canonical_service"]
+		lDestVer, destVerOk := m["destination_canonical_revision"]
+		lResponseCode, responseCodeOk := m["response_code"]
+		lGrpcResponseStatus, grpcReponseStatusOk := m["grpc_response_status"]
+		lFlags, flagsOk := m["response_flags"]
+
+		if !sourceWlNsOk || !sourceWlOk || !sourceAppOk || !sourceVerOk || !destSvcNsOk || !destSvcNameOk || !destSvcOk || !destWlNsOk || !destWlOk || !destAppOk || !destVerOk || !responseCodeOk || !flagsOk {
+			log.Warningf("Skipping %v, missing expected labels", m.String())
+			continue
+		}
+
+		sourceWlNs := string(lSourceWlNs)
+		sourceWl := string(lSourceWl)
+		sourceApp := string(lSourceApp)
+		sourceVer := string(lSourceVer)
+		destSvc := string(lDestSvc)
+		responseCode := string(lResponseCode)
+		flags := string(lFlags)
+
+		// handle clusters
+		sourceCluster, destCluster := util.HandleClusters(lSourceCluster, sourceClusterOk, lDestCluster, destClusterOk)
+
+		if util.IsBadSourceTelemetry(sourceCluster, sourceClusterOk, sourceWlNs, sourceWl, sourceApp) {
+			continue
+		}
+
+		// This was added in istio 1.5, handle in a backward compatible way
+		grpcReponseStatus := "0"
+		if grpcReponseStatusOk {
+			grpcReponseStatus = string(lGrpcResponseStatus)
+		}
+
+		val := float64(s.Value)
+		if math.IsNaN(val) {
+			continue
+		}
+
+		// handle unusual destinations
+		destCluster, destSvcNs, destSvcName, destWlNs, destWl, destApp, destVer, _ := util.HandleDestination(sourceCluster, sourceWlNs, sourceWl, destCluster, string(lDestSvcNs), string(lDestSvc), string(lDestSvcName), string(lDestWlNs), string(lDestWl), string(lDestApp), string(lDestVer))
+
+		if util.IsBadDestTelemetry(destCluster, destClusterOk, destSvcNs, destSvc, destSvcName, destWl) {
+			continue
+		}
+
+		// don't inject a service node if destSvcName is not set or the dest node is already a service node.
+		inject := false
+		if a.InjectServiceNodes && graph.IsOK(destSvcName) {
+			_, destNodeType := graph.Id(destCluster, destSvcNs, destSvcName, destWlNs, destWl, destApp, destVer, a.GraphType)
+			inject = (graph.NodeTypeService != destNodeType)
+		}
+
+		// The source proxy retries the requests sent to the service, so with injected service nodes the retries
+		// are reported on the incoming edge of the service node
+		var rates *retryRates
+		if inject {
+			rates = a.getRetryRates(retriesMap, sourceCluster, sourceWlNs, "", sourceWl, sourceApp, sourceVer, destCluster, destSvcNs, destSvcName, "", "", "", "")
+		} else {
+			rates = a.getRetryRates(retriesMap, sourceCluster, sourceWlNs, "", sourceWl, sourceApp, sourceVer, destCluster, destSvcNs, destSvcName, destWlNs, destWl, destApp, destVer)
+		}
+
+		failed := grpcReponseStatus != "0" || regexpHTTPFailure.MatchString(responseCode)
+		if isSource {
+			rates.requests += val
+			if failed {
+				rates.failed += val
+			}
+			if strings.Contains(flags, retryLimitExceededFlag) {
+				rates.exhausted += val
+			}
+		} else {
+			rates.attempts += val
+			if failed {
+				rates.failedAttempts += val
+			}
+		}
+	}
+}
+
+func (a RetriesAppender) getRetryRates(retriesMap map[string]*retryRates, sourceCluster, sourceNs, sourceSvc, sourceWl, sourceApp, sourceVer, destCluster, destSvcNs, destSvc, destWlNs, destWl, destApp, destVer string) *retryRates {
+	sourceID, _ := graph.Id(sourceCluster, sourceNs, sourceSvc, sourceNs, sourceWl, sourceApp, sourceVer, a.GraphType)
+	destID, _ := graph.Id(destCluster, destSvcNs, destSvc, destWlNs, destWl, destApp, destVer, a.GraphType)
+	key := fmt.Sprintf("%s %s", sourceID, destID)
+
+	rates, found := retriesMap[key]
+	if !found {
+		rates = &retryRates{}
+		retriesMap[key] = rates
+	}
+	return rates
+}
+
+func applyRetries(trafficMap graph.TrafficMap, retriesMap map[string]*retryRates) {
+	for _, n := range trafficMap {
+		for _, e := range n.Edges {
+			key := fmt.Sprintf("%s %s", e.Source.ID, e.Dest.ID)
+			rates, ok := retriesMap[key]
+			// Retries can't be derived without the telemetry of both proxies
+			if !ok || rates.requests == 0 || rates.attempts == 0 {
+				continue
+			}
+			retries := rates.attempts - rates.requests
+			if retries > 0 {
+				e.Metadata[graph.RetryRate] = retries
+				// a failed attempt is recovered only by a retry
+				if recovered := math.Min(rates.failedAttempts-rates.failed, retries); recovered > 0 {
+					e.Metadata[graph.RetrySuccess] = recovered / rates.requests * 100
+				}
+			}
+			if rates.exhausted > 0 {
+				e.Metadata[graph.RetryExhausted] = rates.exhausted / rates.requests * 100
+			}
+		}
+	}
+}
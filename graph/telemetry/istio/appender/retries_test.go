@@ -0,0 +1,133 @@
+package appender
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/kiali/kiali/graph"
+	"github.com/kiali/kiali/prometheus/prometheustest"
+)
+
+const (
+	retriesSourceQuery = `round(sum(rate(istio_requests_total{reporter="source",source_workload_namespace="bookinfo"}[60s])) by (source_cluster,source_workload_namespace,source_workload,source_canonical_service,source_canonical_revision,destination_cluster,destination_service_namespace,destination_service,destination_service_name,destination_workload_namespace,destination_workload,destination_canonical_service,destination_canonical_revision,response_code,grpc_response_status,response_flags) > 0,0.001)`
+	retriesDestQuery   = `round(sum(rate(istio_requests_total{reporter="destination",source_workload_namespace="bookinfo"}[60s])) by (source_cluster,source_workload_namespace,source_workload,source_canonical_service,source_canonical_revision,destination_cluster,destination_service_namespace,destination_service,destination_service_name,destination_workload_namespace,destination_workload,destination_canonical_service,destination_canonical_revision,response_code,grpc_response_status,response_flags) > 0,0.001)`
+)
+
+func TestRetries(t *testing.T) {
+	assert := assert.New(t)
+
+	// productpage retries the requests to reviews-v1, some of them exhausting the retries
+	sourceVector := model.Vector{
+		retriesSample("productpage-v1", "productpage", "v1", "reviews", "reviews-v1", "v1", "200", "-", 9.5),
+		retriesSample("productpage-v1", "productpage", "v1", "reviews", "reviews-v1", "v1", "503", "URX", 0.5),
+		retriesSample("productpage-v1", "productpage", "v1", "reviews", "reviews-v2", "v2", "200", "-", 10),
+		retriesSample("reviews-v1", "reviews", "v1", "ratings", "ratings-v1", "v1", "200", "-", 5),
+	}
+	// every attempt reaches the destination proxy. There's no destination telemetry for ratings.
+	destVector := model.Vector{
+		retriesSample("productpage-v1", "productpage", "v1", "reviews", "reviews-v1", "v1", "200", "-", 9.5),
+		retriesSample("productpage-v1", "productpage", "v1", "reviews", "reviews-v1", "v1", "503", "-", 2.5),
+		retriesSample("productpage-v1", "productpage", "v1", "reviews", "reviews-v2", "v2", "200", "-", 10),
+	}
+
+	client, api, err := setupMocked()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	mockRetriesQueries(api, sourceVector, destVector)
+
+	trafficMap := responseTimeTestTraffic()
+	appender := retriesTestAppender()
+	appender.appendGraph(trafficMap, "bookinfo", client)
+
+	productpageID, _ := graph.Id(graph.Unknown, "bookinfo", "productpage", "bookinfo", "productpage-v1", "productpage", "v1", graph.GraphTypeVersionedApp)
+	productpage := trafficMap[productpageID]
+	assert.Equal(1, len(productpage.Edges))
+	reviewsEdge := productpage.Edges[0]
+	assert.Equal("reviews", reviewsEdge.Dest.Service)
+	// 22 attempts for 20 requests, the 2 failed attempts not seen by productpage were recovered
+	assert.InDelta(2.0, reviewsEdge.Metadata[graph.RetryRate], 0.0001)
+	assert.InDelta(10.0, reviewsEdge.Metadata[graph.RetrySuccess], 0.0001)
+	assert.InDelta(2.5, reviewsEdge.Metadata[graph.RetryExhausted], 0.0001)
+
+	reviewsV1ID, _ := graph.Id(graph.Unknown, "bookinfo", "reviews", "bookinfo", "reviews-v1", "reviews", "v1", graph.GraphTypeVersionedApp)
+	ratingsEdge := trafficMap[reviewsV1ID].Edges[0]
+	assert.Equal("ratings", ratingsEdge.Dest.Service)
+	assert.Nil(ratingsEdge.Metadata[graph.RetryRate])
+	assert.Nil(ratingsEdge.Metadata[graph.RetrySuccess])
+	assert.Nil(ratingsEdge.Metadata[graph.RetryExhausted])
+}
+
+func TestRetriesUnavailable(t *testing.T) {
+	assert := assert.New(t)
+
+	sourceVector := model.Vector{
+		retriesSample("productpage-v1", "productpage", "v1", "reviews", "reviews-v1", "v1", "200", "-", 10),
+	}
+	destVector := model.Vector{}
+
+	client, api, err := setupMocked()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	mockRetriesQueries(api, sourceVector, destVector)
+
+	trafficMap := responseTimeTestTraffic()
+	appender := retriesTestAppender()
+	appender.appendGraph(trafficMap, "bookinfo", client)
+
+	for _, n := range trafficMap {
+		for _, e := range n.Edges {
+			assert.Nil(e.Metadata[graph.RetryRate])
+			assert.Nil(e.Metadata[graph.RetrySuccess])
+			assert.Nil(e.Metadata[graph.RetryExhausted])
+		}
+	}
+}
+
+func mockRetriesQueries(api *prometheustest.PromAPIMock, sourceVector, destVector model.Vector) {
+	api.On("Query", mock.Anything, retriesSourceQuery, mock.AnythingOfType("time.Time")).Return(sourceVector, nil)
+	api.On("Query", mock.Anything, retriesDestQuery, mock.AnythingOfType("time.Time")).Return(destVector, nil)
+}
+
+func retriesTestAppender() RetriesAppender {
+	duration, _ := time.ParseDuration("60s")
+	return RetriesAppender{
+		GraphType:          graph.GraphTypeVersionedApp,
+		InjectServiceNodes: true,
+		Namespaces: graph.NamespaceInfoMap{
+			"bookinfo": graph.NamespaceInfo{
+				Name:     "bookinfo",
+				Duration: duration,
+				IsIstio:  false,
+			},
+		},
+		QueryTime: time.Now().Unix(),
+	}
+}
+
+func retriesSample(sourceWl, sourceApp, sourceVer, destSvc, destWl, destVer, code, flags string, value float64) *model.Sample {
+	return &model.Sample{
+		Metric: model.Metric{
+			"source_workload_namespace":      "bookinfo",
+			"source_workload":                model.LabelValue(sourceWl),
+			"source_canonical_service":       model.LabelValue(sourceApp),
+			"source_canonical_revision":      model.LabelValue(sourceVer),
+			"destination_service_namespace":  "bookinfo",
+			"destination_service":            model.LabelValue(destSvc + ".bookinfo.svc.cluster.local"),
+			"destination_service_name":       model.LabelValue(destSvc),
+			"destination_workload_namespace": "bookinfo",
+			"destination_workload":           model.LabelValue(destWl),
+			"destination_canonical_service":  model.LabelValue(destSvc),
+			"destination_canonical_revision": model.LabelValue(destVer),
+			"response_code":                  model.LabelValue(code),
+			"response_flags":                 model.LabelValue(flags)},
+		Value: model.SampleValue(value),
+	}
+}
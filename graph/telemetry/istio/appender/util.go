@@ -1,28 +1,28 @@
 package appender
 
 import (
-	"context"
 	"fmt"
 	"strings"
 	"time"
 
-	prom_v1 "github.com/prometheus/client_golang/api/prometheus/v1"
 	"github.com/prometheus/common/model"
 
+	"github.com/kiali/kiali/config"
 	"github.com/kiali/kiali/graph"
 	"github.com/kiali/kiali/log"
+	"github.com/kiali/kiali/prometheus"
 	"github.com/kiali/kiali/prometheus/internalmetrics"
 )
 
 // package-private util functions (used by multiple files)
 
-func promQuery(query string, queryTime time.Time, ctx context.Context, api prom_v1.API, a graph.Appender) model.Vector {
-	// wrap with a round() to be in line with metrics api
-	query = fmt.Sprintf("round(%s,0.001)", query)
+func promQuery(query string, queryTime time.Time, client *prometheus.Client, a graph.Appender) model.Vector {
+	// wrap with a round() to be in line with metrics api, and map the Istio standard telemetry to the mesh telemetry
+	query = config.Get().ExternalServices.Istio.TelemetryMapping.Query(fmt.Sprintf("round(%s,0.001)", client.DedupRates(query)))
 	log.Tracef("Appender query:\n%s&time=%v (now=%v, %v)\n", query, queryTime.Format(graph.TF), time.Now().Format(graph.TF), queryTime.Unix())
 
 	promtimer := internalmetrics.GetPrometheusProcessingTimePrometheusTimer("Graph-Appender-" + a.Name())
-	value, warnings, err := api.Query(ctx, query, queryTime)
+	value, warnings, err := client.API().Query(client.GetContext(), query, queryTime)
 	if warnings != nil && len(warnings) > 0 {
 		log.Warningf("promQuery. Prometheus Warnings: [%s]", strings.Join(warnings, ","))
 	}
@@ -31,7 +31,11 @@ func promQuery(query string, queryTime time.Time, ctx context.Context, api prom_
 
 	switch t := value.Type(); t {
 	case model.ValVector: // Instant Vector
-		return value.(model.Vector)
+		vector := value.(model.Vector)
+		for _, sample := range vector {
+			prometheus.StandardLabels(sample.Metric)
+		}
+		return vector
 	default:
 		graph.Error(fmt.Sprintf("No handling for type %v!\n", t))
 	}
@@ -21,9 +21,9 @@ import (
 	"strings"
 	"time"
 
-	prom_v1 "github.com/prometheus/client_golang/api/prometheus/v1"
 	"github.com/prometheus/common/model"
 
+	"github.com/kiali/kiali/config"
 	"github.com/kiali/kiali/graph"
 	"github.com/kiali/kiali/graph/telemetry"
 	"github.com/kiali/kiali/graph/telemetry/istio/appender"
@@ -42,7 +42,7 @@ const (
 func BuildNamespacesTrafficMap(o graph.TelemetryOptions, client *prometheus.Client, globalInfo *graph.AppenderGlobalInfo) graph.TrafficMap {
 	log.Tracef("Build [%s] graph for [%d] namespaces [%v]", o.GraphType, len(o.Namespaces), o.Namespaces)
 
-	appenders := appender.ParseAppenders(o)
+	appenders := appender.ParseAppenders(withFilterAppenders(o))
 	trafficMap := graph.NewTrafficMap()
 
 	for _, namespace := range o.Namespaces {
@@ -61,6 +61,7 @@ func BuildNamespacesTrafficMap(o graph.TelemetryOptions, client *prometheus.Clie
 	// we can make some final adjustments:
 	// - mark the outsiders (i.e. nodes not in the requested namespaces)
 	// - mark the insider traffic generators (i.e. inside the namespace and only outgoing edges)
+	telemetry.FilterTraffic(trafficMap, o.Filters)
 	telemetry.MarkOutsideOrInaccessible(trafficMap, o)
 	telemetry.MarkTrafficGenerators(trafficMap)
 
@@ -78,79 +79,90 @@ func buildNamespaceTrafficMap(namespace string, o graph.TelemetryOptions, client
 	trafficMap := graph.NewTrafficMap()
 	duration := o.Namespaces[namespace].Duration
 
-	// HTTP/GRPC traffic
-	metric := "istio_requests_total"
-	groupBy := "source_cluster,source_workload_namespace,source_workload,source_canonical_service,source_canonical_revision,destination_cluster,destination_service_namespace,destination_service,destination_service_name,destination_workload_namespace,destination_workload,destination_canonical_service,destination_canonical_revision,request_protocol,response_code,grpc_response_status,response_flags"
 	idleCondition := "> 0"
 	if o.IncludeIdleEdges {
 		idleCondition = ""
 	}
 
-	// 0) Incoming: query source telemetry to capture unserviced namespace services' incoming traffic
-	query := fmt.Sprintf(`sum(rate(%s{reporter="source",source_workload_namespace!="%s",destination_workload_namespace="unknown",destination_workload="unknown",destination_service=~"^.+\\.%s\\..+$"} [%vs])) by (%s) %s`,
-		metric,
-		namespace,
-		namespace,
-		int(duration.Seconds()), // range duration for the query
-		groupBy,
-		idleCondition)
-	incomingVector := promQuery(query, time.Unix(o.QueryTime, 0), client.API())
-	populateTrafficMap(trafficMap, &incomingVector, false, o)
-
-	// 1) Incoming: query destination telemetry to capture namespace services' incoming traffic
-	query = fmt.Sprintf(`sum(rate(%s{reporter="destination",destination_workload_namespace="%s"} [%vs])) by (%s) %s`,
-		metric,
-		namespace,
-		int(duration.Seconds()), // range duration for the query
-		groupBy,
-		idleCondition)
-	incomingVector = promQuery(query, time.Unix(o.QueryTime, 0), client.API())
-	populateTrafficMap(trafficMap, &incomingVector, false, o)
-
-	// 2) Outgoing: query source telemetry to capture namespace workloads' outgoing traffic
-	query = fmt.Sprintf(`sum(rate(%s{reporter="source",source_workload_namespace="%s"} [%vs])) by (%s) %s`,
-		metric,
-		namespace,
-		int(duration.Seconds()), // range duration for the query
-		groupBy,
-		idleCondition)
-	outgoingVector := promQuery(query, time.Unix(o.QueryTime, 0), client.API())
-	populateTrafficMap(trafficMap, &outgoingVector, false, o)
+	// HTTP/GRPC traffic
+	if sourceFilter, destFilter, ok := filterSelectors(o.Filters, false); ok {
+		metric := "istio_requests_total"
+		groupBy := "source_cluster,source_workload_namespace,source_workload,source_canonical_service,source_canonical_revision,destination_cluster,destination_service_namespace,destination_service,destination_service_name,destination_workload_namespace,destination_workload,destination_canonical_service,destination_canonical_revision,request_protocol,response_code,grpc_response_status,response_flags"
+
+		// 0) Incoming: query source telemetry to capture unserviced namespace services' incoming traffic
+		query := fmt.Sprintf(`sum(rate(%s{reporter="source"%s,source_workload_namespace!="%s",destination_workload_namespace="unknown",destination_workload="unknown",destination_service=~"^.+\\.%s\\..+$"} [%vs])) by (%s) %s`,
+			metric,
+			sourceFilter,
+			namespace,
+			namespace,
+			int(duration.Seconds()), // range duration for the query
+			groupBy,
+			idleCondition)
+		incomingVector := promQuery(query, time.Unix(o.QueryTime, 0), client)
+		populateTrafficMap(trafficMap, &incomingVector, false, o)
+
+		// 1) Incoming: query destination telemetry to capture namespace services' incoming traffic
+		query = fmt.Sprintf(`sum(rate(%s{reporter="destination"%s,destination_workload_namespace="%s"} [%vs])) by (%s) %s`,
+			metric,
+			destFilter,
+			namespace,
+			int(duration.Seconds()), // range duration for the query
+			groupBy,
+			idleCondition)
+		incomingVector = promQuery(query, time.Unix(o.QueryTime, 0), client)
+		populateTrafficMap(trafficMap, &incomingVector, false, o)
+
+		// 2) Outgoing: query source telemetry to capture namespace workloads' outgoing traffic
+		query = fmt.Sprintf(`sum(rate(%s{reporter="source"%s,source_workload_namespace="%s"} [%vs])) by (%s) %s`,
+			metric,
+			sourceFilter,
+			namespace,
+			int(duration.Seconds()), // range duration for the query
+			groupBy,
+			idleCondition)
+		outgoingVector := promQuery(query, time.Unix(o.QueryTime, 0), client)
+		populateTrafficMap(trafficMap, &outgoingVector, false, o)
+	}
 
 	// TCP traffic
-	metric = "istio_tcp_sent_bytes_total"
-	groupBy = "source_cluster,source_workload_namespace,source_workload,source_canonical_service,source_canonical_revision,destination_cluster,destination_service_namespace,destination_service,destination_service_name,destination_workload_namespace,destination_workload,destination_canonical_service,destination_canonical_revision,response_flags"
+	if sourceFilter, destFilter, ok := filterSelectors(o.Filters, true); ok {
+		metric := "istio_tcp_sent_bytes_total"
+		groupBy := "source_cluster,source_workload_namespace,source_workload,source_canonical_service,source_canonical_revision,destination_cluster,destination_service_namespace,destination_service,destination_service_name,destination_workload_namespace,destination_workload,destination_canonical_service,destination_canonical_revision,response_flags"
 
-	// 0) Incoming: query source telemetry to capture unserviced namespace services' incoming traffic
-	query = fmt.Sprintf(`sum(rate(%s{reporter="source",source_workload_namespace!="%s",destination_workload_namespace="unknown",destination_workload="unknown",destination_service=~"^.+\\.%s\\..+$"} [%vs])) by (%s) %s`,
-		metric,
-		namespace,
-		namespace,
-		int(duration.Seconds()), // range duration for the query
-		groupBy,
-		idleCondition)
-	incomingVector = promQuery(query, time.Unix(o.QueryTime, 0), client.API())
-	populateTrafficMap(trafficMap, &incomingVector, true, o)
-
-	// 1) Incoming: query destination telemetry to capture namespace services' incoming traffic	query = fmt.Sprintf(`sum(rate(%s{reporter="destination",destination_service_namespace="%s"} [%vs])) by (%s) %s`,
-	query = fmt.Sprintf(`sum(rate(%s{reporter="destination",destination_workload_namespace="%s"} [%vs])) by (%s) %s`,
-		metric,
-		namespace,
-		int(duration.Seconds()), // range duration for the query
-		groupBy,
-		idleCondition)
-	incomingVector = promQuery(query, time.Unix(o.QueryTime, 0), client.API())
-	populateTrafficMap(trafficMap, &incomingVector, true, o)
-
-	// 2) Outgoing: query source telemetry to capture namespace workloads' outgoing traffic
-	query = fmt.Sprintf(`sum(rate(%s{reporter="source",source_workload_namespace="%s"} [%vs])) by (%s) %s`,
-		metric,
-		namespace,
-		int(duration.Seconds()), // range duration for the query
-		groupBy,
-		idleCondition)
-	outgoingVector = promQuery(query, time.Unix(o.QueryTime, 0), client.API())
-	populateTrafficMap(trafficMap, &outgoingVector, true, o)
+		// 0) Incoming: query source telemetry to capture unserviced namespace services' incoming traffic
+		query := fmt.Sprintf(`sum(rate(%s{reporter="source"%s,source_workload_namespace!="%s",destination_workload_namespace="unknown",destination_workload="unknown",destination_service=~"^.+\\.%s\\..+$"} [%vs])) by (%s) %s`,
+			metric,
+			sourceFilter,
+			namespace,
+			namespace,
+			int(duration.Seconds()), // range duration for the query
+			groupBy,
+			idleCondition)
+		incomingVector := promQuery(query, time.Unix(o.QueryTime, 0), client)
+		populateTrafficMap(trafficMap, &incomingVector, true, o)
+
+		// 1) Incoming: query destination telemetry to capture namespace services' incoming traffic	query = fmt.Sprintf(`sum(rate(%s{reporter="destination",destination_service_namespace="%s"} [%vs])) by (%s) %s`,
+		query = fmt.Sprintf(`sum(rate(%s{reporter="destination"%s,destination_workload_namespace="%s"} [%vs])) by (%s) %s`,
+			metric,
+			destFilter,
+			namespace,
+			int(duration.Seconds()), // range duration for the query
+			groupBy,
+			idleCondition)
+		incomingVector = promQuery(query, time.Unix(o.QueryTime, 0), client)
+		populateTrafficMap(trafficMap, &incomingVector, true, o)
+
+		// 2) Outgoing: query source telemetry to capture namespace workloads' outgoing traffic
+		query = fmt.Sprintf(`sum(rate(%s{reporter="source"%s,source_workload_namespace="%s"} [%vs])) by (%s) %s`,
+			metric,
+			sourceFilter,
+			namespace,
+			int(duration.Seconds()), // range duration for the query
+			groupBy,
+			idleCondition)
+		outgoingVector := promQuery(query, time.Unix(o.QueryTime, 0), client)
+		populateTrafficMap(trafficMap, &outgoingVector, true, o)
+	}
 
 	return trafficMap
 }
@@ -326,7 +338,7 @@ func BuildNodeTrafficMap(o graph.TelemetryOptions, client *prometheus.Client, gl
 
 	log.Tracef("Build graph for node [%+v]", n)
 
-	appenders := appender.ParseAppenders(o)
+	appenders := appender.ParseAppenders(withFilterAppenders(o))
 	trafficMap := buildNodeTrafficMap(o.Cluster, o.NodeOptions.Namespace, n, o, client)
 
 	namespaceInfo := graph.NewAppenderNamespaceInfo(o.NodeOptions.Namespace)
@@ -341,6 +353,7 @@ func BuildNodeTrafficMap(o graph.TelemetryOptions, client *prometheus.Client, gl
 	// we can make some final adjustments:
 	// - mark the outsiders (i.e. nodes not in the requested namespaces)
 	// - mark the traffic generators
+	telemetry.FilterTraffic(trafficMap, o.Filters)
 	telemetry.MarkOutsideOrInaccessible(trafficMap, o)
 	telemetry.MarkTrafficGenerators(trafficMap)
 
@@ -367,209 +380,231 @@ func buildNodeTrafficMap(cluster, namespace string, n graph.Node, o graph.Teleme
 		destCluster = fmt.Sprintf(",destination_cluster=%s", cluster)
 	}
 
-	// HTTP/GRPC Traffic
-	metric := "istio_requests_total"
-	groupBy := "source_cluster,source_workload_namespace,source_workload,source_canonical_service,source_canonical_revision,destination_cluster,destination_service_namespace,destination_service,destination_service_name,destination_workload_namespace,destination_workload,destination_canonical_service,destination_canonical_revision,request_protocol,response_code,grpc_response_status,response_flags"
 	idleCondition := "> 0"
 	if o.IncludeIdleEdges {
 		idleCondition = ""
 	}
 
-	// query prometheus for request traffic in two queries:
-	// 1) query for incoming traffic
-	var query string
-	switch n.NodeType {
-	case graph.NodeTypeWorkload:
-		query = fmt.Sprintf(`sum(rate(%s{reporter="destination"%s,destination_workload_namespace="%s",destination_workload="%s"} [%vs])) by (%s) %s`,
-			metric,
-			destCluster,
-			namespace,
-			n.Workload,
-			int(duration.Seconds()), // range duration for the query
-			groupBy,
-			idleCondition)
-	case graph.NodeTypeApp:
-		if graph.IsOK(n.Version) {
-			query = fmt.Sprintf(`sum(rate(%s{reporter="destination"%s,destination_service_namespace="%s",destination_canonical_service="%s",destination_canonical_revision="%s"} [%vs])) by (%s) %s`,
+	// HTTP/GRPC Traffic
+	if sourceFilter, destFilter, ok := filterSelectors(o.Filters, false); ok {
+		metric := "istio_requests_total"
+		groupBy := "source_cluster,source_workload_namespace,source_workload,source_canonical_service,source_canonical_revision,destination_cluster,destination_service_namespace,destination_service,destination_service_name,destination_workload_namespace,destination_workload,destination_canonical_service,destination_canonical_revision,request_protocol,response_code,grpc_response_status,response_flags"
+
+		// query prometheus for request traffic in two queries:
+		// 1) query for incoming traffic
+		var query string
+		switch n.NodeType {
+		case graph.NodeTypeWorkload:
+			query = fmt.Sprintf(`sum(rate(%s{reporter="destination"%s%s,destination_workload_namespace="%s",destination_workload="%s"} [%vs])) by (%s) %s`,
 				metric,
+				destFilter,
 				destCluster,
 				namespace,
-				n.App,
-				n.Version,
+				n.Workload,
 				int(duration.Seconds()), // range duration for the query
 				groupBy,
 				idleCondition)
-		} else {
-			query = fmt.Sprintf(`sum(rate(%s{reporter="destination"%s,destination_service_namespace="%s",destination_canonical_service="%s"} [%vs])) by (%s) %s`,
+		case graph.NodeTypeApp:
+			if graph.IsOK(n.Version) {
+				query = fmt.Sprintf(`sum(rate(%s{reporter="destination"%s%s,destination_service_namespace="%s",destination_canonical_service="%s",destination_canonical_revision="%s"} [%vs])) by (%s) %s`,
+					metric,
+					destFilter,
+					destCluster,
+					namespace,
+					n.App,
+					n.Version,
+					int(duration.Seconds()), // range duration for the query
+					groupBy,
+					idleCondition)
+			} else {
+				query = fmt.Sprintf(`sum(rate(%s{reporter="destination"%s%s,destination_service_namespace="%s",destination_canonical_service="%s"} [%vs])) by (%s) %s`,
+					metric,
+					destFilter,
+					destCluster,
+					namespace,
+					n.App,
+					int(duration.Seconds()), // range duration for the query
+					groupBy,
+					idleCondition)
+			}
+		case graph.NodeTypeService:
+			// Service nodes require two queries for incoming
+			// 1.a) query source telemetry for requests to the service that could not be serviced
+			query = fmt.Sprintf(`sum(rate(%s{reporter="source"%s%s,destination_workload="unknown",destination_service=~"^%s\\.%s\\..*$"} [%vs])) by (%s) %s`,
 				metric,
+				sourceFilter,
 				destCluster,
+				n.Service,
 				namespace,
-				n.App,
 				int(duration.Seconds()), // range duration for the query
 				groupBy,
 				idleCondition)
-		}
-	case graph.NodeTypeService:
-		// Service nodes require two queries for incoming
-		// 1.a) query source telemetry for requests to the service that could not be serviced
-		query = fmt.Sprintf(`sum(rate(%s{reporter="source"%s,destination_workload="unknown",destination_service=~"^%s\\.%s\\..*$"} [%vs])) by (%s) %s`,
-			metric,
-			destCluster,
-			n.Service,
-			namespace,
-			int(duration.Seconds()), // range duration for the query
-			groupBy,
-			idleCondition)
-		vector := promQuery(query, time.Unix(o.QueryTime, 0), client.API())
-		populateTrafficMap(trafficMap, &vector, false, o)
+			vector := promQuery(query, time.Unix(o.QueryTime, 0), client)
+			populateTrafficMap(trafficMap, &vector, false, o)
 
-		// 1.b) query dest telemetry for requests to the service, serviced by service workloads
-		query = fmt.Sprintf(`sum(rate(%s{reporter="destination"%s,destination_service_namespace="%s",destination_service=~"^%s\\.%s\\..*$"} [%vs])) by (%s) %s`,
-			metric,
-			destCluster,
-			namespace,
-			n.Service,
-			namespace,
-			int(duration.Seconds()), // range duration for the query
-			groupBy,
-			idleCondition)
-	default:
-		graph.Error(fmt.Sprintf("NodeType [%s] not supported", n.NodeType))
-	}
-	inVector := promQuery(query, time.Unix(o.QueryTime, 0), client.API())
-	populateTrafficMap(trafficMap, &inVector, false, o)
-
-	// 2) query for outbound traffic
-	switch n.NodeType {
-	case graph.NodeTypeWorkload:
-		query = fmt.Sprintf(`sum(rate(%s{reporter="source"%s,source_workload_namespace="%s",source_workload="%s"} [%vs])) by (%s) %s`,
-			metric,
-			sourceCluster,
-			namespace,
-			n.Workload,
-			int(duration.Seconds()), // range duration for the query
-			groupBy,
-			idleCondition)
-	case graph.NodeTypeApp:
-		if graph.IsOK(n.Version) {
-			query = fmt.Sprintf(`sum(rate(%s{reporter="source"%s,source_workload_namespace="%s",source_canonical_service="%s",source_canonical_revision="%s"} [%vs])) by (%s) %s`,
+			// 1.b) query dest telemetry for requests to the service, serviced by service workloads
+			query = fmt.Sprintf(`sum(rate(%s{reporter="destination"%s%s,destination_service_namespace="%s",destination_service=~"^%s\\.%s\\..*$"} [%vs])) by (%s) %s`,
 				metric,
-				sourceCluster,
+				destFilter,
+				destCluster,
+				namespace,
+				n.Service,
 				namespace,
-				n.App,
-				n.Version,
 				int(duration.Seconds()), // range duration for the query
 				groupBy,
 				idleCondition)
-		} else {
-			query = fmt.Sprintf(`sum(rate(%s{reporter="source"%s,source_workload_namespace="%s",source_canonical_service="%s"} [%vs])) by (%s) %s`,
+		default:
+			graph.Error(fmt.Sprintf("NodeType [%s] not supported", n.NodeType))
+		}
+		inVector := promQuery(query, time.Unix(o.QueryTime, 0), client)
+		populateTrafficMap(trafficMap, &inVector, false, o)
+
+		// 2) query for outbound traffic
+		switch n.NodeType {
+		case graph.NodeTypeWorkload:
+			query = fmt.Sprintf(`sum(rate(%s{reporter="source"%s%s,source_workload_namespace="%s",source_workload="%s"} [%vs])) by (%s) %s`,
 				metric,
+				sourceFilter,
 				sourceCluster,
 				namespace,
-				n.App,
+				n.Workload,
 				int(duration.Seconds()), // range duration for the query
 				groupBy,
 				idleCondition)
+		case graph.NodeTypeApp:
+			if graph.IsOK(n.Version) {
+				query = fmt.Sprintf(`sum(rate(%s{reporter="source"%s%s,source_workload_namespace="%s",source_canonical_service="%s",source_canonical_revision="%s"} [%vs])) by (%s) %s`,
+					metric,
+					sourceFilter,
+					sourceCluster,
+					namespace,
+					n.App,
+					n.Version,
+					int(duration.Seconds()), // range duration for the query
+					groupBy,
+					idleCondition)
+			} else {
+				query = fmt.Sprintf(`sum(rate(%s{reporter="source"%s%s,source_workload_namespace="%s",source_canonical_service="%s"} [%vs])) by (%s) %s`,
+					metric,
+					sourceFilter,
+					sourceCluster,
+					namespace,
+					n.App,
+					int(duration.Seconds()), // range duration for the query
+					groupBy,
+					idleCondition)
+			}
+		case graph.NodeTypeService:
+			query = ""
+		default:
+			graph.Error(fmt.Sprintf("NodeType [%s] not supported", n.NodeType))
 		}
-	case graph.NodeTypeService:
-		query = ""
-	default:
-		graph.Error(fmt.Sprintf("NodeType [%s] not supported", n.NodeType))
+		outVector := promQuery(query, time.Unix(o.QueryTime, 0), client)
+		populateTrafficMap(trafficMap, &outVector, false, o)
 	}
-	outVector := promQuery(query, time.Unix(o.QueryTime, 0), client.API())
-	populateTrafficMap(trafficMap, &outVector, false, o)
 
 	// TCP traffic
-	metric = "istio_tcp_sent_bytes_total"
-	groupBy = "source_cluster,source_workload_namespace,source_workload,source_canonical_service,source_canonical_revision,destination_cluster,destination_service_namespace,destination_service,destination_service_name,destination_workload_namespace,destination_workload,destination_canonical_service,destination_canonical_revision,response_flags"
-
-	switch n.NodeType {
-	case graph.NodeTypeWorkload:
-		query = fmt.Sprintf(`sum(rate(%s{reporter="source"%s,destination_workload_namespace="%s",destination_workload="%s"} [%vs])) by (%s) %s`,
-			metric,
-			destCluster,
-			namespace,
-			n.Workload,
-			int(duration.Seconds()), // range duration for the query
-			groupBy,
-			idleCondition)
-	case graph.NodeTypeApp:
-		if graph.IsOK(n.Version) {
-			query = fmt.Sprintf(`sum(rate(%s{reporter="source"%s,destination_service_namespace="%s",destination_canonical_service="%s",destination_canonical_revision="%s"} [%vs])) by (%s) %s`,
+	// (tcp telemetry is reported by the source only)
+	if sourceFilter, _, ok := filterSelectors(o.Filters, true); ok {
+		var query string
+		metric := "istio_tcp_sent_bytes_total"
+		groupBy := "source_cluster,source_workload_namespace,source_workload,source_canonical_service,source_canonical_revision,destination_cluster,destination_service_namespace,destination_service,destination_service_name,destination_workload_namespace,destination_workload,destination_canonical_service,destination_canonical_revision,response_flags"
+
+		switch n.NodeType {
+		case graph.NodeTypeWorkload:
+			query = fmt.Sprintf(`sum(rate(%s{reporter="source"%s%s,destination_workload_namespace="%s",destination_workload="%s"} [%vs])) by (%s) %s`,
 				metric,
+				sourceFilter,
 				destCluster,
 				namespace,
-				n.App,
-				n.Version,
+				n.Workload,
 				int(duration.Seconds()), // range duration for the query
 				groupBy,
 				idleCondition)
-		} else {
-			query = fmt.Sprintf(`sum(rate(%s{reporter="source"%s,destination_service_namespace="%s",destination_canonical_service="%s"} [%vs])) by (%s) %s`,
+		case graph.NodeTypeApp:
+			if graph.IsOK(n.Version) {
+				query = fmt.Sprintf(`sum(rate(%s{reporter="source"%s%s,destination_service_namespace="%s",destination_canonical_service="%s",destination_canonical_revision="%s"} [%vs])) by (%s) %s`,
+					metric,
+					sourceFilter,
+					destCluster,
+					namespace,
+					n.App,
+					n.Version,
+					int(duration.Seconds()), // range duration for the query
+					groupBy,
+					idleCondition)
+			} else {
+				query = fmt.Sprintf(`sum(rate(%s{reporter="source"%s%s,destination_service_namespace="%s",destination_canonical_service="%s"} [%vs])) by (%s) %s`,
+					metric,
+					sourceFilter,
+					destCluster,
+					namespace,
+					n.App,
+					int(duration.Seconds()), // range duration for the query
+					groupBy,
+					idleCondition)
+			}
+		case graph.NodeTypeService:
+			// TODO: Do we need to handle requests from unknown in a special way (like in HTTP above)? Not sure how tcp is reported from unknown.
+			query = fmt.Sprintf(`sum(rate(%s{reporter="source"%s%s,destination_service_namespace="%s",destination_service=~"^%s\\.%s\\..*$"} [%vs])) by (%s) %s`,
 				metric,
+				sourceFilter,
 				destCluster,
 				namespace,
-				n.App,
+				n.Service,
+				namespace,
 				int(duration.Seconds()), // range duration for the query
 				groupBy,
 				idleCondition)
+		default:
+			graph.Error(fmt.Sprintf("NodeType [%s] not supported", n.NodeType))
 		}
-	case graph.NodeTypeService:
-		// TODO: Do we need to handle requests from unknown in a special way (like in HTTP above)? Not sure how tcp is reported from unknown.
-		query = fmt.Sprintf(`sum(rate(%s{reporter="source"%s,destination_service_namespace="%s",destination_service=~"^%s\\.%s\\..*$"} [%vs])) by (%s) %s`,
-			metric,
-			destCluster,
-			namespace,
-			n.Service,
-			namespace,
-			int(duration.Seconds()), // range duration for the query
-			groupBy,
-			idleCondition)
-	default:
-		graph.Error(fmt.Sprintf("NodeType [%s] not supported", n.NodeType))
-	}
-	tcpInVector := promQuery(query, time.Unix(o.QueryTime, 0), client.API())
-	populateTrafficMap(trafficMap, &tcpInVector, true, o)
+		tcpInVector := promQuery(query, time.Unix(o.QueryTime, 0), client)
+		populateTrafficMap(trafficMap, &tcpInVector, true, o)
 
-	// 2) query for outbound traffic
-	switch n.NodeType {
-	case graph.NodeTypeWorkload:
-		query = fmt.Sprintf(`sum(rate(%s{reporter="source"%s,source_workload_namespace="%s",source_workload="%s"} [%vs])) by (%s) %s`,
-			metric,
-			sourceCluster,
-			namespace,
-			n.Workload,
-			int(duration.Seconds()), // range duration for the query
-			groupBy,
-			idleCondition)
-	case graph.NodeTypeApp:
-		if graph.IsOK(n.Version) {
-			query = fmt.Sprintf(`sum(rate(%s{reporter="source"%s,source_workload_namespace="%s",source_canonical_service="%s",source_canonical_revision="%s"} [%vs])) by (%s) %s`,
-				metric,
-				sourceCluster,
-				namespace,
-				n.App,
-				n.Version,
-				int(duration.Seconds()), // range duration for the query
-				groupBy,
-				idleCondition)
-		} else {
-			query = fmt.Sprintf(`sum(rate(%s{reporter="source"%s,source_workload_namespace="%s",source_canonical_service="%s"} [%vs])) by (%s) %s`,
+		// 2) query for outbound traffic
+		switch n.NodeType {
+		case graph.NodeTypeWorkload:
+			query = fmt.Sprintf(`sum(rate(%s{reporter="source"%s%s,source_workload_namespace="%s",source_workload="%s"} [%vs])) by (%s) %s`,
 				metric,
+				sourceFilter,
 				sourceCluster,
 				namespace,
-				n.App,
+				n.Workload,
 				int(duration.Seconds()), // range duration for the query
 				groupBy,
 				idleCondition)
+		case graph.NodeTypeApp:
+			if graph.IsOK(n.Version) {
+				query = fmt.Sprintf(`sum(rate(%s{reporter="source"%s%s,source_workload_namespace="%s",source_canonical_service="%s",source_canonical_revision="%s"} [%vs])) by (%s) %s`,
+					metric,
+					sourceFilter,
+					sourceCluster,
+					namespace,
+					n.App,
+					n.Version,
+					int(duration.Seconds()), // range duration for the query
+					groupBy,
+					idleCondition)
+			} else {
+				query = fmt.Sprintf(`sum(rate(%s{reporter="source"%s%s,source_workload_namespace="%s",source_canonical_service="%s"} [%vs])) by (%s) %s`,
+					metric,
+					sourceFilter,
+					sourceCluster,
+					namespace,
+					n.App,
+					int(duration.Seconds()), // range duration for the query
+					groupBy,
+					idleCondition)
+			}
+		case graph.NodeTypeService:
+			query = ""
+		default:
+			graph.Error(fmt.Sprintf("NodeType [%s] not supported", n.NodeType))
 		}
-	case graph.NodeTypeService:
-		query = ""
-	default:
-		graph.Error(fmt.Sprintf("NodeType [%s] not supported", n.NodeType))
+		tcpOutVector := promQuery(query, time.Unix(o.QueryTime, 0), client)
+		populateTrafficMap(trafficMap, &tcpOutVector, true, o)
 	}
-	tcpOutVector := promQuery(query, time.Unix(o.QueryTime, 0), client.API())
-	populateTrafficMap(trafficMap, &tcpOutVector, true, o)
 
 	return trafficMap
 }
@@ -582,7 +617,7 @@ func handleAggregateNodeTrafficMap(o graph.TelemetryOptions, client *prometheus.
 	if !o.Appenders.All {
 		o.Appenders.AppenderNames = append(o.Appenders.AppenderNames, appender.AggregateNodeAppenderName)
 	}
-	appenders := appender.ParseAppenders(o)
+	appenders := appender.ParseAppenders(withFilterAppenders(o))
 	trafficMap := buildAggregateNodeTrafficMap(o.NodeOptions.Namespace, n, o, client)
 
 	namespaceInfo := graph.NewAppenderNamespaceInfo(o.NodeOptions.Namespace)
@@ -597,6 +632,7 @@ func handleAggregateNodeTrafficMap(o graph.TelemetryOptions, client *prometheus.
 	// we can make some final adjustments:
 	// - mark the outsiders (i.e. nodes not in the requested namespaces)
 	// - mark the traffic generators
+	telemetry.FilterTraffic(trafficMap, o.Filters)
 	telemetry.MarkOutsideOrInaccessible(trafficMap, o)
 	telemetry.MarkTrafficGenerators(trafficMap)
 
@@ -611,14 +647,20 @@ func buildAggregateNodeTrafficMap(namespace string, n graph.Node, o graph.Teleme
 	// create map to aggregate traffic by response code
 	trafficMap := graph.NewTrafficMap()
 
+	_, destFilter, ok := filterSelectors(o.Filters, false)
+	if !ok {
+		return trafficMap
+	}
+
 	// It takes only one prometheus query to get everything involving the target operation
 	serviceFragment := ""
 	if n.Service != "" {
 		serviceFragment = fmt.Sprintf(`,destination_service_name="%s"`, n.Service)
 	}
 	groupBy := "source_cluster,source_workload_namespace,source_workload,source_canonical_service,source_canonical_revision,destination_cluster,destination_service_namespace,destination_service,destination_service_name,destination_workload_namespace,destination_workload,destination_canonical_service,destination_canonical_revision,request_protocol,response_code,grpc_response_status,response_flags"
-	httpQuery := fmt.Sprintf(`sum(rate(%s{reporter="destination",destination_service_namespace="%s",%s="%s"%s}[%vs])) by (%s) > 0`,
+	httpQuery := fmt.Sprintf(`sum(rate(%s{reporter="destination"%s,destination_service_namespace="%s",%s="%s"%s}[%vs])) by (%s) > 0`,
 		"istio_requests_total",
+		destFilter,
 		namespace,
 		n.Metadata[graph.Aggregate],
 		n.Metadata[graph.AggregateValue],
@@ -637,13 +679,50 @@ func buildAggregateNodeTrafficMap(namespace string, n graph.Node, o graph.Teleme
 	query := fmt.Sprintf(`(%s) OR (%s)`, httpQuery, tcpQuery)
 	*/
 	query := httpQuery
-	vector := promQuery(query, time.Unix(o.QueryTime, 0), client.API())
+	vector := promQuery(query, time.Unix(o.QueryTime, 0), client)
 	populateTrafficMap(trafficMap, &vector, false, o)
 
 	return trafficMap
 }
 
-func promQuery(query string, queryTime time.Time, api prom_v1.API) model.Vector {
+// withFilterAppenders adds the appenders required to filter the edges: the security filter needs the edge security
+func withFilterAppenders(o graph.TelemetryOptions) graph.TelemetryOptions {
+	if o.Filters.Security != "" && !o.Appenders.All {
+		o.Appenders.AppenderNames = append(o.Appenders.AppenderNames, appender.SecurityPolicyAppenderName)
+	}
+	return o
+}
+
+// filterSelectors returns the label selectors constraining the source and destination reported metrics to the graph
+// filters. The security policy is only reported by the destination, source reported traffic is filtered afterwards
+// from the edge security. It returns false when the protocol filter excludes the metric altogether.
+func filterSelectors(filters graph.Filters, isTCP bool) (sourceFilter, destFilter string, ok bool) {
+	if isTCP {
+		if !filters.IncludesProtocol(graph.TCP.Name) {
+			return "", "", false
+		}
+	} else {
+		http, grpc := filters.IncludesProtocol(graph.HTTP.Name), filters.IncludesProtocol(graph.GRPC.Name)
+		switch {
+		case !http && !grpc:
+			return "", "", false
+		case !http:
+			sourceFilter = fmt.Sprintf(`,request_protocol="%s"`, graph.GRPC.Name)
+		case !grpc:
+			sourceFilter = fmt.Sprintf(`,request_protocol="%s"`, graph.HTTP.Name)
+		}
+	}
+	destFilter = sourceFilter
+	switch filters.Security {
+	case graph.SecurityMTLS:
+		destFilter += `,connection_security_policy="mutual_tls"`
+	case graph.SecurityPlaintext:
+		destFilter += `,connection_security_policy!="mutual_tls"`
+	}
+	return sourceFilter, destFilter, true
+}
+
+func promQuery(query string, queryTime time.Time, client *prometheus.Client) model.Vector {
 	if query == "" {
 		return model.Vector{}
 	}
@@ -651,12 +730,12 @@ func promQuery(query string, queryTime time.Time, api prom_v1.API) model.Vector
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	// wrap with a round() to be in line with metrics api
-	query = fmt.Sprintf("round(%s,0.001)", query)
+	// wrap with a round() to be in line with metrics api, and map the Istio standard telemetry to the mesh telemetry
+	query = config.Get().ExternalServices.Istio.TelemetryMapping.Query(fmt.Sprintf("round(%s,0.001)", client.DedupRates(query)))
 	log.Tracef("Graph query:\n%s@time=%v (now=%v, %v)\n", query, queryTime.Format(graph.TF), time.Now().Format(graph.TF), queryTime.Unix())
 
 	promtimer := internalmetrics.GetPrometheusProcessingTimePrometheusTimer("Graph-Generation")
-	value, warnings, err := api.Query(ctx, query, queryTime)
+	value, warnings, err := client.API().Query(ctx, query, queryTime)
 	if warnings != nil && len(warnings) > 0 {
 		log.Warningf("promQuery. Prometheus Warnings: [%s]", strings.Join(warnings, ","))
 	}
@@ -665,7 +744,11 @@ func promQuery(query string, queryTime time.Time, api prom_v1.API) model.Vector
 
 	switch t := value.Type(); t {
 	case model.ValVector: // Instant Vector
-		return value.(model.Vector)
+		vector := value.(model.Vector)
+		for _, sample := range vector {
+			prometheus.StandardLabels(sample.Metric)
+		}
+		return vector
 	default:
 		graph.Error(fmt.Sprintf("No handling for type %v!\n", t))
 	}
@@ -0,0 +1,39 @@
+package istio
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kiali/kiali/graph"
+)
+
+func TestFilterSelectors(t *testing.T) {
+	assert := assert.New(t)
+
+	source, dest, ok := filterSelectors(graph.Filters{}, false)
+	assert.True(ok)
+	assert.Empty(source)
+	assert.Empty(dest)
+
+	source, dest, ok = filterSelectors(graph.Filters{Protocols: []string{"grpc"}, Security: graph.SecurityMTLS}, false)
+	assert.True(ok)
+	assert.Equal(`,request_protocol="grpc"`, source)
+	assert.Equal(`,request_protocol="grpc",connection_security_policy="mutual_tls"`, dest)
+
+	_, _, ok = filterSelectors(graph.Filters{Protocols: []string{"grpc"}}, true)
+	assert.False(ok)
+
+	source, dest, ok = filterSelectors(graph.Filters{Protocols: []string{"http", "grpc"}, Security: graph.SecurityPlaintext}, false)
+	assert.True(ok)
+	assert.Empty(source)
+	assert.Equal(`,connection_security_policy!="mutual_tls"`, dest)
+
+	_, _, ok = filterSelectors(graph.Filters{Protocols: []string{"tcp"}}, false)
+	assert.False(ok)
+
+	source, dest, ok = filterSelectors(graph.Filters{Protocols: []string{"tcp"}, Security: graph.SecurityMTLS}, true)
+	assert.True(ok)
+	assert.Empty(source)
+	assert.Equal(`,connection_security_policy="mutual_tls"`, dest)
+}
@@ -462,7 +462,7 @@ func checkOpenshiftSession(w http.ResponseWriter, r *http.Request) (int, string)
 		_, err = business.OpenshiftOAuth.GetUserInfo(claims.SessionId)
 		if err == nil {
 			// Internal header used to propagate the subject of the request for audit purposes
-			r.Header.Add("Kiali-User", claims.Subject)
+			r.Header.Set("Kiali-User", claims.Subject)
 			return http.StatusOK, claims.SessionId
 		}
 
@@ -530,7 +530,7 @@ func checkOpenIdSession(w http.ResponseWriter, r *http.Request) (int, string) {
 	}
 
 	// Internal header used to propagate the subject of the request for audit purposes
-	r.Header.Add("Kiali-User", claims.Subject)
+	r.Header.Set("Kiali-User", claims.Subject)
 	return http.StatusOK, claims.SessionId
 }
 
@@ -554,7 +554,7 @@ func checkTokenSession(w http.ResponseWriter, r *http.Request) (int, string) {
 		_, err = business.Namespace.GetNamespaces()
 		if err == nil {
 			// Internal header used to propagate the subject of the request for audit purposes
-			r.Header.Add("Kiali-User", claims.Subject)
+			r.Header.Set("Kiali-User", claims.Subject)
 			return http.StatusOK, claims.SessionId
 		}
 
@@ -578,6 +578,9 @@ func (aHandler AuthenticationHandler) Handle(next http.Handler) http.Handler {
 		statusCode := http.StatusOK
 		conf := config.Get()
 
+		// The internal Kiali-User header is only set by the session checks, a value sent by the client is dropped
+		r.Header.Del("Kiali-User")
+
 		var authInfo *api.AuthInfo
 		var token string
 
@@ -632,6 +635,7 @@ func (aHandler AuthenticationHandler) Handle(next http.Handler) http.Handler {
 
 func (aHandler AuthenticationHandler) HandleUnauthenticated(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.Header.Del("Kiali-User")
 		context := context.WithValue(r.Context(), "authInfo", &api.AuthInfo{Token: ""})
 		next.ServeHTTP(w, r.WithContext(context))
 	})
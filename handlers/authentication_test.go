@@ -596,3 +596,20 @@ func IsValidUUID(uuid string) bool {
 	r := regexp.MustCompile("^[0-9a-f]{8}-[0-9a-f]{4}-[0-5][0-9a-f]{3}-[089ab][0-9a-f]{3}-[0-9a-f]{12}$")
 	return r.MatchString(uuid)
 }
+
+// TestAuthenticationHandlerDropsKialiUserHeader checks that the user of the audit can't be forged by the client
+func TestAuthenticationHandlerDropsKialiUserHeader(t *testing.T) {
+	cfg := config.NewConfig()
+	cfg.Auth.Strategy = config.AuthStrategyAnonymous
+	config.Set(cfg)
+
+	var user string
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user = r.Header.Get("Kiali-User")
+	})
+	request := httptest.NewRequest("GET", "http://kiali/api/namespaces", nil)
+	request.Header.Set("Kiali-User", "admin")
+
+	AuthenticationHandler{saToken: "token"}.Handle(next).ServeHTTP(httptest.NewRecorder(), request)
+	assert.Empty(t, user)
+}
@@ -13,7 +13,6 @@ import (
 	"github.com/kiali/kiali/config"
 	"github.com/kiali/kiali/kubernetes"
 	"github.com/kiali/kiali/log"
-	"github.com/kiali/kiali/prometheus"
 )
 
 const (
@@ -138,7 +137,7 @@ func getPrometheusConfig() PrometheusConfig {
 		GlobalScrapeInterval: defaultPrometheusGlobalScrapeInterval,
 	}
 
-	client, err := prometheus.NewClient()
+	client, err := business.NewPrometheusClient()
 	if !checkErr(err, "") {
 		log.Error(err)
 		return promConfig
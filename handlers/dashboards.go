@@ -4,6 +4,7 @@ import (
 	"net/http"
 	"net/url"
 	"strings"
+	"time"
 
 	"github.com/gorilla/mux"
 	"k8s.io/apimachinery/pkg/api/errors"
@@ -44,6 +45,7 @@ func CustomDashboard(w http.ResponseWriter, r *http.Request) {
 		RespondWithError(w, http.StatusForbidden, "Cannot access namespace data: "+err.Error())
 		return
 	}
+	svc.ScopeToNamespaces(&layer.Namespace)
 	params := models.DashboardQuery{Namespace: namespace}
 	err = extractDashboardQueryParams(queryParams, &params, info)
 	if err != nil {
@@ -56,7 +58,7 @@ func CustomDashboard(w http.ResponseWriter, r *http.Request) {
 		if errors.IsNotFound(err) {
 			RespondWithError(w, http.StatusNotFound, err.Error())
 		} else {
-			RespondWithError(w, http.StatusInternalServerError, err.Error())
+			respondWithQueryError(w, err, http.StatusInternalServerError)
 		}
 		return
 	}
@@ -116,12 +118,13 @@ func AppDashboard(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	metrics, err := metricsService.GetMetrics(params, business.GetIstioScaler())
+	metrics, step, err := metricsService.GetDownsampledMetrics(params, business.GetIstioScaler())
 	if err != nil {
-		RespondWithError(w, http.StatusInternalServerError, err.Error())
+		respondWithQueryError(w, err, http.StatusInternalServerError)
 		return
 	}
 	dashboard := business.NewDashboardsService().BuildIstioDashboard(metrics, params.Direction)
+	dashboard.Step = int64(step / time.Second)
 	RespondWithJSON(w, http.StatusOK, dashboard)
 }
 
@@ -144,12 +147,13 @@ func ServiceDashboard(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	metrics, err := metricsService.GetMetrics(params, business.GetIstioScaler())
+	metrics, step, err := metricsService.GetDownsampledMetrics(params, business.GetIstioScaler())
 	if err != nil {
-		RespondWithError(w, http.StatusInternalServerError, err.Error())
+		respondWithQueryError(w, err, http.StatusInternalServerError)
 		return
 	}
 	dashboard := business.NewDashboardsService().BuildIstioDashboard(metrics, params.Direction)
+	dashboard.Step = int64(step / time.Second)
 	RespondWithJSON(w, http.StatusOK, dashboard)
 }
 
@@ -172,11 +176,55 @@ func WorkloadDashboard(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	metrics, err := metricsService.GetMetrics(params, business.GetIstioScaler())
+	metrics, step, err := metricsService.GetDownsampledMetrics(params, business.GetIstioScaler())
 	if err != nil {
-		RespondWithError(w, http.StatusInternalServerError, err.Error())
+		respondWithQueryError(w, err, http.StatusInternalServerError)
 		return
 	}
 	dashboard := business.NewDashboardsService().BuildIstioDashboard(metrics, params.Direction)
+	dashboard.Step = int64(step / time.Second)
+	RespondWithJSON(w, http.StatusOK, dashboard)
+}
+
+// WorkloadConfigDashboard is the API handler to fetch a dashboard of custom metrics defined in the configuration, related to a single workload
+func WorkloadConfigDashboard(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	namespace := vars["namespace"]
+	workload := vars["workload"]
+	dashboardName := vars["dashboard"]
+
+	svc := business.NewDashboardsService()
+	if !svc.CustomEnabled {
+		RespondWithError(w, http.StatusServiceUnavailable, "Custom dashboards are disabled in config")
+		return
+	}
+
+	layer, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	info, err := checkNamespaceAccess(layer.Namespace, namespace)
+	if err != nil {
+		RespondWithError(w, http.StatusForbidden, "Cannot access namespace data: "+err.Error())
+		return
+	}
+	svc.ScopeToNamespaces(&layer.Namespace)
+	params := models.DashboardQuery{Namespace: namespace}
+	err = extractDashboardQueryParams(r.URL.Query(), &params, info)
+	if err != nil {
+		RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	dashboard, err := svc.GetWorkloadConfigDashboard(params, workload, dashboardName)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			RespondWithError(w, http.StatusNotFound, err.Error())
+		} else {
+			respondWithQueryError(w, err, http.StatusInternalServerError)
+		}
+		return
+	}
 	RespondWithJSON(w, http.StatusOK, dashboard)
 }
@@ -1,13 +1,17 @@
 package handlers
 
 import (
+	goerrors "errors"
 	"net/http"
+	"strconv"
 	"strings"
 
 	"k8s.io/apimachinery/pkg/api/errors"
 
 	"github.com/kiali/kiali/business"
+	"github.com/kiali/kiali/config"
 	"github.com/kiali/kiali/log"
+	"github.com/kiali/kiali/prometheus"
 )
 
 // Helper method to adjust error code in the handler's response
@@ -23,6 +27,12 @@ func handleErrorResponse(w http.ResponseWriter, err error, extraMesg ...string)
 		RespondWithError(w, http.StatusForbidden, errorMsg)
 	} else if errors.IsNotFound(err) {
 		RespondWithError(w, http.StatusNotFound, errorMsg)
+	} else if goerrors.Is(err, prometheus.ErrOverloaded) {
+		setRetryAfter(w)
+		RespondWithError(w, http.StatusServiceUnavailable, errorMsg)
+	} else if rejected, isRejected := err.(*business.ApplyRejectedError); isRejected {
+		// The reasons of the rejection are returned to be displayed along the object
+		RespondWithJSON(w, rejected.Code, rejected.Rejection)
 	} else if statusError, isStatus := err.(*errors.StatusError); isStatus {
 		errorMsg = statusError.ErrStatus.Message
 		RespondWithError(w, http.StatusInternalServerError, errorMsg)
@@ -30,3 +40,38 @@ func handleErrorResponse(w http.ResponseWriter, err error, extraMesg ...string)
 		RespondWithError(w, http.StatusInternalServerError, errorMsg)
 	}
 }
+
+// queryErrorStatus is the status of the response to a failed metrics or traces query: BadRequest when the query
+// was rejected, for instance for exceeding the configured query limits, Forbidden when it reads a namespace that
+// isn't accessible, ServiceUnavailable when Prometheus is overloaded, the default status otherwise
+func queryErrorStatus(err error, defaultStatus int) int {
+	if errors.IsBadRequest(err) {
+		return http.StatusBadRequest
+	}
+	if business.IsAccessibleError(err) {
+		return http.StatusForbidden
+	}
+	if goerrors.Is(err, prometheus.ErrOverloaded) {
+		return http.StatusServiceUnavailable
+	}
+	return defaultStatus
+}
+
+// respondWithQueryError responds to a failed metrics or traces query with the status of queryErrorStatus. The
+// queries rejected because Prometheus is overloaded tell the client when to retry them.
+func respondWithQueryError(w http.ResponseWriter, err error, defaultStatus int) {
+	if goerrors.Is(err, prometheus.ErrOverloaded) {
+		setRetryAfter(w)
+	}
+	RespondWithError(w, queryErrorStatus(err, defaultStatus), err.Error())
+}
+
+// setRetryAfter asks the client to retry a query rejected by the Prometheus limiter after the queue timeout, when
+// the queued queries are either served or rejected
+func setRetryAfter(w http.ResponseWriter) {
+	retryAfter := config.Get().ExternalServices.Prometheus.QueryQueueTimeout
+	if retryAfter < 1 {
+		retryAfter = 1
+	}
+	w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+}
@@ -3,6 +3,8 @@ package handlers
 import (
 	"net/http"
 
+	"github.com/gorilla/mux"
+
 	"github.com/kiali/kiali/business"
 	"github.com/kiali/kiali/log"
 )
@@ -25,3 +27,39 @@ func GetGrafanaInfo(w http.ResponseWriter, r *http.Request) {
 	}
 	RespondWithJSON(w, code, info)
 }
+
+// GrafanaDashboardFolders provides the links to the Grafana dashboards grouped by folder, for an app, workload or service
+func GrafanaDashboardFolders(w http.ResponseWriter, r *http.Request) {
+	pathParams := mux.Vars(r)
+	namespace := pathParams["namespace"]
+
+	requestAuthInfo, err := getAuthInfo(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "authInfo initialization error: "+err.Error())
+		return
+	}
+	layer, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if _, err = checkNamespaceAccess(layer.Namespace, namespace); err != nil {
+		RespondWithError(w, http.StatusForbidden, "Cannot access namespace data: "+err.Error())
+		return
+	}
+
+	target := business.GrafanaDashboardTarget{
+		Namespace: namespace,
+		App:       pathParams["app"],
+		Version:   r.URL.Query().Get("version"),
+		Workload:  pathParams["workload"],
+		Service:   pathParams["service"],
+	}
+	folders, code, err := business.GetGrafanaDashboardFolders(requestAuthInfo, target, business.GrafanaDashboardSupplier)
+	if err != nil {
+		log.Error(err)
+		RespondWithError(w, code, err.Error())
+		return
+	}
+	RespondWithJSON(w, code, folders)
+}
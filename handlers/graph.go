@@ -33,12 +33,17 @@ import (
 	"fmt"
 	"net/http"
 	"runtime/debug"
+	"strconv"
+	"time"
 
 	"github.com/kiali/kiali/graph"
 	"github.com/kiali/kiali/graph/api"
 	"github.com/kiali/kiali/log"
 )
 
+// The age in seconds of the data of a namespaces graph, computed in background when the graph cache is enabled
+const graphAgeHeader = "Kiali-Graph-Age"
+
 // GraphNamespaces is a REST http.HandlerFunc handling graph generation for 1 or more namespaces
 func GraphNamespaces(w http.ResponseWriter, r *http.Request) {
 	defer handlePanic(w)
@@ -48,7 +53,8 @@ func GraphNamespaces(w http.ResponseWriter, r *http.Request) {
 	business, err := getBusiness(r)
 	graph.CheckError(err)
 
-	code, payload := api.GraphNamespaces(business, o)
+	code, payload, age := api.GraphNamespacesCached(business, o)
+	w.Header().Set(graphAgeHeader, strconv.FormatInt(int64(age/time.Second), 10))
 	respond(w, code, payload)
 }
 
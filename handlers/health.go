@@ -29,6 +29,17 @@ func NamespaceHealth(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Custom resources health is read from their status, there is no rate to adjust
+	if p.Type == "customresource" {
+		health, err := business.Health.GetNamespaceCustomResourceHealth(p.Namespace)
+		if err != nil {
+			handleErrorResponse(w, err, "Error while fetching custom resource health: "+err.Error())
+			return
+		}
+		RespondWithJSON(w, http.StatusOK, health)
+		return
+	}
+
 	// Adjust rate interval
 	rateInterval, err := adjustRateInterval(business, p.Namespace, p.RateInterval, p.QueryTime)
 	if err != nil {
@@ -102,6 +113,27 @@ func WorkloadHealth(w http.ResponseWriter, r *http.Request) {
 	handleHealthResponse(w, health, err)
 }
 
+// WorkloadDependencyHealth is the API handler to get health of a single workload along with the health of its dependencies
+func WorkloadDependencyHealth(w http.ResponseWriter, r *http.Request) {
+	business, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Services initialization error: "+err.Error())
+		return
+	}
+
+	p := workloadHealthParams{}
+	p.extract(r)
+	rateInterval, err := adjustRateInterval(business, p.Namespace, p.RateInterval, p.QueryTime)
+	if err != nil {
+		handleErrorResponse(w, err, "Adjust rate interval error: "+err.Error())
+		return
+	}
+	p.RateInterval = rateInterval
+
+	health, err := business.Health.GetWorkloadDependencyHealth(p.Namespace, p.Workload, p.WorkloadType, rateInterval, p.QueryTime)
+	handleHealthResponse(w, health, err)
+}
+
 // ServiceHealth is the API handler to get health of a single service
 func ServiceHealth(w http.ResponseWriter, r *http.Request) {
 	business, err := getBusiness(r)
@@ -160,10 +192,10 @@ func (p *baseHealthParams) baseExtract(r *http.Request, vars map[string]string)
 // swagger:parameters namespaceHealth
 type namespaceHealthParams struct {
 	baseHealthParams
-	// The type of health, "app", "service" or "workload".
+	// The type of health, "app", "service", "workload" or "customresource".
 	//
 	// in: query
-	// pattern: ^(app|service|workload)$
+	// pattern: ^(app|service|workload|customresource)$
 	// default: app
 	Type string `json:"type"`
 }
@@ -174,8 +206,8 @@ func (p *namespaceHealthParams) extract(r *http.Request) (bool, string) {
 	p.Type = "app"
 	queryParams := r.URL.Query()
 	if healthType := queryParams.Get("type"); healthType != "" {
-		if healthType != "app" && healthType != "service" && healthType != "workload" {
-			return false, "Bad request, query parameter 'type' must be one of ['app','service','workload']"
+		if healthType != "app" && healthType != "service" && healthType != "workload" && healthType != "customresource" {
+			return false, "Bad request, query parameter 'type' must be one of ['app','service','workload','customresource']"
 		}
 		p.Type = healthType
 	}
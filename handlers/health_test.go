@@ -5,6 +5,7 @@ import (
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
+	neturl "net/url"
 	"testing"
 	"time"
 
@@ -20,6 +21,7 @@ import (
 	"github.com/kiali/kiali/business"
 	"github.com/kiali/kiali/config"
 	"github.com/kiali/kiali/kubernetes/kubetest"
+	"github.com/kiali/kiali/prometheus"
 	"github.com/kiali/kiali/prometheus/prometheustest"
 	"github.com/kiali/kiali/util"
 )
@@ -151,6 +153,29 @@ func TestServiceHealth(t *testing.T) {
 	prom.AssertNumberOfCalls(t, "GetServiceRequestRates", 1)
 }
 
+// TestServiceHealthPrometheusOverloaded checks that the queries rejected by the Prometheus limiter can be retried
+func TestServiceHealthPrometheusOverloaded(t *testing.T) {
+	conf := config.NewConfig()
+	config.Set(conf)
+	ts, _, prom := setupServiceHealthEndpoint(t)
+	defer ts.Close()
+
+	url := ts.URL + "/api/namespaces/ns/services/svc/health"
+
+	// The limiter rejects the query in the transport of the Prometheus client
+	overloaded := &neturl.Error{Op: "Post", URL: conf.ExternalServices.Prometheus.URL, Err: prometheus.ErrOverloaded}
+	prom.On("GetServiceRequestRates", mock.AnythingOfType("string"), mock.AnythingOfType("string"), "17s", util.Clock.Now()).Return(model.Vector{}, overloaded)
+
+	resp, err := http.Get(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	actual, _ := ioutil.ReadAll(resp.Body)
+
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode, string(actual))
+	assert.Equal(t, "10", resp.Header.Get("Retry-After"))
+}
+
 func setupServiceHealthEndpoint(t *testing.T) (*httptest.Server, *kubetest.K8SClientMock, *prometheustest.PromClientMock) {
 	k8s := kubetest.NewK8SClientMock()
 	prom := new(prometheustest.PromClientMock)
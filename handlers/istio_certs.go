@@ -0,0 +1,30 @@
+package handlers
+
+import (
+	"net/http"
+)
+
+// MeshCertificateChain is the API handler to fetch the chain of trust of the mesh, down to the certificate of the
+// workload of a pod when the 'namespace' and 'pod' query parameters are set
+func MeshCertificateChain(w http.ResponseWriter, r *http.Request) {
+	business, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Services initialization error: "+err.Error())
+		return
+	}
+
+	queryParams := r.URL.Query()
+	namespace := queryParams.Get("namespace")
+	pod := queryParams.Get("pod")
+	if (namespace == "") != (pod == "") {
+		RespondWithError(w, http.StatusBadRequest, "The 'namespace' and 'pod' query parameters must be set together")
+		return
+	}
+
+	chain, err := business.IstioCerts.GetCertificateChain(namespace, pod)
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+	RespondWithJSON(w, http.StatusOK, chain)
+}
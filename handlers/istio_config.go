@@ -7,11 +7,11 @@ import (
 	"sync"
 
 	"github.com/gorilla/mux"
+	"k8s.io/apimachinery/pkg/api/errors"
 
 	"github.com/kiali/kiali/business"
-	"github.com/kiali/kiali/config"
-	"github.com/kiali/kiali/log"
 	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/util"
 )
 
 func IstioConfigList(w http.ResponseWriter, r *http.Request) {
@@ -162,16 +162,73 @@ func IstioConfigDelete(w http.ResponseWriter, r *http.Request) {
 		RespondWithError(w, http.StatusInternalServerError, "Services initialization error: "+err.Error())
 		return
 	}
-	err = business.IstioConfig.DeleteIstioConfigDetail(api, namespace, objectType, object)
+	err = business.IstioConfig.DeleteIstioConfigDetail(r.URL.Query().Get("cluster"), api, namespace, objectType, object)
 	if err != nil {
-		handleErrorResponse(w, err)
+		if errors.IsBadRequest(err) {
+			RespondWithError(w, http.StatusBadRequest, err.Error())
+		} else {
+			handleErrorResponse(w, err)
+		}
 		return
 	} else {
-		audit(r, "DELETE on Namespace: "+namespace+" Type: "+objectType+" Name: "+object)
 		RespondWithCode(w, http.StatusOK)
 	}
 }
 
+// IstioConfigDeleteImpact is the API handler to preview the objects affected by the deletion of an Istio object
+func IstioConfigDeleteImpact(w http.ResponseWriter, r *http.Request) {
+	params := mux.Vars(r)
+	namespace := params["namespace"]
+	objectType := params["object_type"]
+	object := params["object"]
+
+	business, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Services initialization error: "+err.Error())
+		return
+	}
+	impact, err := business.Validations.GetDeleteImpact(namespace, objectType, object)
+	if err != nil {
+		if errors.IsBadRequest(err) {
+			RespondWithError(w, http.StatusBadRequest, err.Error())
+		} else {
+			handleErrorResponse(w, err)
+		}
+		return
+	}
+	RespondWithJSON(w, http.StatusOK, impact)
+}
+
+// VirtualServiceRouteMetrics is the API handler to fetch the traffic of the HTTP routes of a VirtualService
+func VirtualServiceRouteMetrics(w http.ResponseWriter, r *http.Request) {
+	business, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Services initialization error: "+err.Error())
+		return
+	}
+
+	rateInterval := r.URL.Query().Get("rateInterval")
+	if rateInterval == "" {
+		rateInterval = defaultHealthRateInterval
+	}
+
+	params := mux.Vars(r)
+	namespace := params["namespace"]
+	queryTime := util.Clock.Now()
+	rateInterval, err = adjustRateInterval(business, namespace, rateInterval, queryTime)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Adjust rate interval error: "+err.Error())
+		return
+	}
+
+	routeMetrics, err := business.IstioConfig.GetVirtualServiceRouteMetrics(namespace, params["object"], rateInterval, queryTime)
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+	RespondWithJSON(w, http.StatusOK, routeMetrics)
+}
+
 func IstioConfigUpdate(w http.ResponseWriter, r *http.Request) {
 	params := mux.Vars(r)
 	namespace := params["namespace"]
@@ -196,14 +253,17 @@ func IstioConfigUpdate(w http.ResponseWriter, r *http.Request) {
 		RespondWithError(w, http.StatusBadRequest, "Update request with bad update patch: "+err.Error())
 	}
 	jsonPatch := string(body)
-	updatedConfigDetails, err := business.IstioConfig.UpdateIstioConfigDetail(api, namespace, objectType, object, jsonPatch)
+	updatedConfigDetails, err := business.IstioConfig.UpdateIstioConfigDetail(r.URL.Query().Get("cluster"), api, namespace, objectType, object, jsonPatch)
 
 	if err != nil {
-		handleErrorResponse(w, err)
+		if errors.IsBadRequest(err) {
+			RespondWithError(w, http.StatusBadRequest, err.Error())
+		} else {
+			handleErrorResponse(w, err)
+		}
 		return
 	}
 
-	audit(r, "UPDATE on Namespace: "+namespace+" Type: "+objectType+" Name: "+object+" Patch: "+jsonPatch)
 	RespondWithJSON(w, http.StatusOK, updatedConfigDetails)
 }
 
@@ -231,13 +291,16 @@ func IstioConfigCreate(w http.ResponseWriter, r *http.Request) {
 		RespondWithError(w, http.StatusBadRequest, "Create request could not be read: "+err.Error())
 	}
 
-	createdConfigDetails, err := business.IstioConfig.CreateIstioConfigDetail(api, namespace, objectType, body)
+	createdConfigDetails, err := business.IstioConfig.CreateIstioConfigDetail(r.URL.Query().Get("cluster"), api, namespace, objectType, body)
 	if err != nil {
-		handleErrorResponse(w, err)
+		if errors.IsBadRequest(err) {
+			RespondWithError(w, http.StatusBadRequest, err.Error())
+		} else {
+			handleErrorResponse(w, err)
+		}
 		return
 	}
 
-	audit(r, "CREATE on Namespace: "+namespace+" Type: "+objectType+" Object: "+string(body))
 	RespondWithJSON(w, http.StatusOK, createdConfigDetails)
 }
 
@@ -245,13 +308,6 @@ func checkObjectType(objectType string) bool {
 	return business.GetIstioAPI(objectType) != ""
 }
 
-func audit(r *http.Request, message string) {
-	if config.Get().Server.AuditLog {
-		user := r.Header.Get("Kiali-User")
-		log.Infof("AUDIT User [%s] Msg [%s]", user, message)
-	}
-}
-
 func IstioConfigPermissions(w http.ResponseWriter, r *http.Request) {
 	// query params
 	params := r.URL.Query()
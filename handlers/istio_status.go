@@ -2,6 +2,8 @@ package handlers
 
 import (
 	"net/http"
+
+	"github.com/kiali/kiali/util"
 )
 
 // IstioStatus returns a list of istio components and its status
@@ -21,3 +23,43 @@ func IstioStatus(w http.ResponseWriter, r *http.Request) {
 
 	RespondWithJSON(w, http.StatusOK, istioStatus)
 }
+
+// PilotMetrics returns the istiod push latency percentiles and error rates
+func PilotMetrics(w http.ResponseWriter, r *http.Request) {
+	// Get business layer
+	business, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Services initialization error: "+err.Error())
+		return
+	}
+
+	rateInterval := defaultHealthRateInterval
+	if ri := r.URL.Query().Get("rateInterval"); ri != "" {
+		rateInterval = ri
+	}
+	queryTime := util.Clock.Now()
+	if _, err = util.GetStartTimeForRateInterval(queryTime, rateInterval); err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Bad request, cannot parse query parameter 'rateInterval': "+err.Error())
+		return
+	}
+
+	pilotMetrics, err := business.IstioStatus.GetPilotMetrics(rateInterval, queryTime)
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+
+	RespondWithJSON(w, http.StatusOK, pilotMetrics)
+}
+
+// ExternalServicesDiagnostics tests the connection to the configured Prometheus and Jaeger
+func ExternalServicesDiagnostics(w http.ResponseWriter, r *http.Request) {
+	// Get business layer
+	business, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Services initialization error: "+err.Error())
+		return
+	}
+
+	RespondWithJSON(w, http.StatusOK, business.IstioStatus.DiagnoseExternalServices())
+}
@@ -9,9 +9,17 @@ import (
 	"time"
 
 	"github.com/gorilla/mux"
+	"k8s.io/apimachinery/pkg/api/errors"
 
 	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/log"
 	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/util"
+)
+
+const (
+	defaultSamplingWindow = "10m"
+	defaultSamplingLimit  = 1000
 )
 
 // Get JaegerInfo provides the Jaeger URL and other info
@@ -53,12 +61,69 @@ func AppTraces(w http.ResponseWriter, r *http.Request) {
 	}
 	traces, err := business.Jaeger.GetAppTraces(namespace, app, q)
 	if err != nil {
-		RespondWithError(w, http.StatusServiceUnavailable, err.Error())
+		respondWithQueryError(w, err, http.StatusServiceUnavailable)
 		return
 	}
 	RespondWithJSON(w, http.StatusOK, traces)
 }
 
+// AppTracesExport is the API handler streaming the traces of an app in OTLP/JSON, a line per trace
+func AppTracesExport(w http.ResponseWriter, r *http.Request) {
+	business, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "AppTracesExport initialization error: "+err.Error())
+		return
+	}
+	params := mux.Vars(r)
+	namespace := params["namespace"]
+	app := params["app"]
+	q, err := readQuery(r.URL.Query())
+	if err != nil {
+		RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	out := &otlpExportWriter{w: w, filename: fmt.Sprintf("%s-%s-traces.jsonl", namespace, app)}
+	exported, err := business.Jaeger.ExportAppTraces(namespace, app, q, out)
+	if err != nil {
+		if !out.started {
+			respondWithQueryError(w, err, http.StatusServiceUnavailable)
+			return
+		}
+		// The response is already partially sent
+		log.Errorf("Export of the traces of app [%s/%s] interrupted after %d traces: %v", namespace, app, exported, err)
+		return
+	}
+	if !out.started {
+		out.start()
+	}
+}
+
+// otlpExportWriter sends the response headers on the first write only, so that an error fetching the traces can
+// still be responded, then flushes every trace to the client as it is written
+type otlpExportWriter struct {
+	w        http.ResponseWriter
+	filename string
+	started  bool
+}
+
+func (ow *otlpExportWriter) start() {
+	ow.started = true
+	ow.w.Header().Set("Content-Type", "application/x-ndjson")
+	ow.w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", ow.filename))
+	ow.w.WriteHeader(http.StatusOK)
+}
+
+func (ow *otlpExportWriter) Write(p []byte) (int, error) {
+	if !ow.started {
+		ow.start()
+	}
+	n, err := ow.w.Write(p)
+	if flusher, ok := ow.w.(http.Flusher); ok {
+		flusher.Flush()
+	}
+	return n, err
+}
+
 func ServiceTraces(w http.ResponseWriter, r *http.Request) {
 	business, err := getBusiness(r)
 	if err != nil {
@@ -75,7 +140,7 @@ func ServiceTraces(w http.ResponseWriter, r *http.Request) {
 	}
 	traces, err := business.Jaeger.GetServiceTraces(namespace, service, q)
 	if err != nil {
-		RespondWithError(w, http.StatusServiceUnavailable, err.Error())
+		respondWithQueryError(w, err, http.StatusServiceUnavailable)
 		return
 	}
 	RespondWithJSON(w, http.StatusOK, traces)
@@ -97,7 +162,7 @@ func WorkloadTraces(w http.ResponseWriter, r *http.Request) {
 	}
 	traces, err := business.Jaeger.GetWorkloadTraces(namespace, workload, q)
 	if err != nil {
-		RespondWithError(w, http.StatusServiceUnavailable, err.Error())
+		respondWithQueryError(w, err, http.StatusServiceUnavailable)
 		return
 	}
 	RespondWithJSON(w, http.StatusOK, traces)
@@ -148,6 +213,28 @@ func TraceDetails(w http.ResponseWriter, r *http.Request) {
 	RespondWithJSON(w, http.StatusOK, trace)
 }
 
+// TraceSpanTree is the API handler to fetch a trace with its spans organized as a tree
+func TraceSpanTree(w http.ResponseWriter, r *http.Request) {
+	business, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Trace initialization error: "+err.Error())
+		return
+	}
+	params := mux.Vars(r)
+	traceID := params["traceID"]
+	trace, err := business.Jaeger.GetTrace(traceID)
+	if err != nil {
+		RespondWithError(w, http.StatusServiceUnavailable, err.Error())
+		return
+	}
+	if trace == nil {
+		// Trace not found
+		RespondWithError(w, http.StatusNotFound, fmt.Sprintf("Trace %s not found", traceID))
+		return
+	}
+	RespondWithJSON(w, http.StatusOK, trace)
+}
+
 // AppSpans is the API handler to fetch Jaeger spans of a specific app
 func AppSpans(w http.ResponseWriter, r *http.Request) {
 	business, err := getBusiness(r)
@@ -167,7 +254,7 @@ func AppSpans(w http.ResponseWriter, r *http.Request) {
 
 	spans, err := business.Jaeger.GetAppSpans(namespace, app, q)
 	if err != nil {
-		RespondWithError(w, http.StatusServiceUnavailable, err.Error())
+		respondWithQueryError(w, err, http.StatusServiceUnavailable)
 		return
 	}
 
@@ -193,7 +280,7 @@ func ServiceSpans(w http.ResponseWriter, r *http.Request) {
 
 	spans, err := business.Jaeger.GetServiceSpans(namespace, service, q)
 	if err != nil {
-		RespondWithError(w, http.StatusServiceUnavailable, err.Error())
+		respondWithQueryError(w, err, http.StatusServiceUnavailable)
 		return
 	}
 
@@ -219,7 +306,7 @@ func WorkloadSpans(w http.ResponseWriter, r *http.Request) {
 
 	spans, err := business.Jaeger.GetWorkloadSpans(namespace, workload, q)
 	if err != nil {
-		RespondWithError(w, http.StatusServiceUnavailable, err.Error())
+		respondWithQueryError(w, err, http.StatusServiceUnavailable)
 		return
 	}
 
@@ -270,3 +357,62 @@ func readQuery(values url.Values) (models.TracingQuery, error) {
 	}
 	return q, nil
 }
+
+// TracingSampling estimates the trace sampling of the services of a namespace
+func TracingSampling(w http.ResponseWriter, r *http.Request) {
+	business, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Tracing Sampling initialization error: "+err.Error())
+		return
+	}
+	params := mux.Vars(r)
+	namespace := params["namespace"]
+	queryParams := r.URL.Query()
+	window := defaultSamplingWindow
+	if v := queryParams.Get("window"); v != "" {
+		window = v
+	}
+	limit := defaultSamplingLimit
+	if v := queryParams.Get("limit"); v != "" {
+		if limit, err = strconv.Atoi(v); err != nil {
+			RespondWithError(w, http.StatusBadRequest, "Cannot parse parameter 'limit': "+err.Error())
+			return
+		}
+	}
+	sampling, err := business.Jaeger.GetSamplingEstimates(namespace, window, limit, util.Clock.Now())
+	if err != nil {
+		if errors.IsBadRequest(err) {
+			RespondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		handleErrorResponse(w, err)
+		return
+	}
+	RespondWithJSON(w, http.StatusOK, sampling)
+}
+
+// WorkloadTracingDiagnosis tells why a workload may have no trace
+func WorkloadTracingDiagnosis(w http.ResponseWriter, r *http.Request) {
+	business, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Tracing Diagnosis initialization error: "+err.Error())
+		return
+	}
+	params := mux.Vars(r)
+	namespace := params["namespace"]
+	workload := params["workload"]
+	window := defaultSamplingWindow
+	if v := r.URL.Query().Get("window"); v != "" {
+		window = v
+	}
+	diagnosis, err := business.Jaeger.GetWorkloadTracingDiagnosis(namespace, workload, window, util.Clock.Now())
+	if err != nil {
+		if errors.IsBadRequest(err) {
+			RespondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		handleErrorResponse(w, err)
+		return
+	}
+	RespondWithJSON(w, http.StatusOK, diagnosis)
+}
@@ -1,6 +1,10 @@
 package handlers
 
-import "net/http"
+import (
+	"net/http"
+
+	"github.com/kiali/kiali/util"
+)
 
 // GetClusters writes to the HTTP response a JSON document with the
 // list of clusters that are part of the mesh when multi-cluster is enabled. If
@@ -21,3 +25,67 @@ func GetClusters(w http.ResponseWriter, r *http.Request) {
 
 	RespondWithJSON(w, http.StatusOK, meshClusters)
 }
+
+// ClustersOverview writes to the HTTP response a JSON document with the health of each cluster of the
+// mesh: reachability, control plane status and version, and number of connected proxies.
+func ClustersOverview(w http.ResponseWriter, r *http.Request) {
+	business, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Business layer initialization error: "+err.Error())
+		return
+	}
+
+	overview, err := business.Mesh.GetClustersOverview()
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Cannot fetch the overview of the mesh clusters: "+err.Error())
+		return
+	}
+
+	RespondWithJSON(w, http.StatusOK, overview)
+}
+
+// MeshSnapshot writes to the HTTP response a JSON document with the health, the mTLS status and the validations
+// summary of all the accessible namespaces, in a deterministic form suitable to track the posture of the mesh in git.
+func MeshSnapshot(w http.ResponseWriter, r *http.Request) {
+	business, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Business layer initialization error: "+err.Error())
+		return
+	}
+
+	rateInterval := r.URL.Query().Get("rateInterval")
+	if rateInterval == "" {
+		rateInterval = defaultHealthRateInterval
+	}
+	queryTime := util.Clock.Now()
+	if _, err = util.GetStartTimeForRateInterval(queryTime, rateInterval); err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Bad request, cannot parse query parameter 'rateInterval': "+err.Error())
+		return
+	}
+
+	snapshot, err := business.Namespace.GetMeshSnapshot(rateInterval, queryTime)
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+
+	RespondWithJSON(w, http.StatusOK, snapshot)
+}
+
+// MeshExtensionProviders writes to the HTTP response a JSON document with the extension providers of the mesh
+// config, and whether their services are found in the mesh.
+func MeshExtensionProviders(w http.ResponseWriter, r *http.Request) {
+	business, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Business layer initialization error: "+err.Error())
+		return
+	}
+
+	providers, err := business.Validations.GetExtensionProviders()
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+
+	RespondWithJSON(w, http.StatusOK, providers)
+}
@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/gorilla/mux"
+	errors2 "k8s.io/apimachinery/pkg/api/errors"
 
 	"github.com/kiali/kiali/business"
 	"github.com/kiali/kiali/log"
@@ -43,12 +44,7 @@ func getAppMetrics(w http.ResponseWriter, r *http.Request, promSupplier promClie
 		return
 	}
 
-	metrics, err := metricsService.GetMetrics(params, nil)
-	if err != nil {
-		RespondWithError(w, http.StatusInternalServerError, err.Error())
-		return
-	}
-	RespondWithJSON(w, http.StatusOK, metrics)
+	respondWithMetrics(w, r, metricsService, params)
 }
 
 // WorkloadMetrics is the API handler to fetch metrics to be displayed, related to a single workload
@@ -75,12 +71,193 @@ func getWorkloadMetrics(w http.ResponseWriter, r *http.Request, promSupplier pro
 		return
 	}
 
-	metrics, err := metricsService.GetMetrics(params, nil)
+	respondWithMetrics(w, r, metricsService, params)
+}
+
+// WorkloadPortMetrics is the API handler to fetch the traffic of a workload per destination port
+func WorkloadPortMetrics(w http.ResponseWriter, r *http.Request) {
+	getWorkloadPortMetrics(w, r, defaultPromClientSupplier)
+}
+
+// getWorkloadPortMetrics (mock-friendly version)
+func getWorkloadPortMetrics(w http.ResponseWriter, r *http.Request, promSupplier promClientSupplier) {
+	vars := mux.Vars(r)
+	queryParams := r.URL.Query()
+
+	q := models.WorkloadPortMetricsQuery{}
+	q.FillDefaults()
+	q.Namespace = vars["namespace"]
+	q.Workload = vars["workload"]
+	if direction := queryParams.Get("direction"); direction != "" {
+		if direction != "inbound" && direction != "outbound" {
+			RespondWithError(w, http.StatusBadRequest, "Bad request, query parameter 'direction' must be either 'inbound' or 'outbound'")
+			return
+		}
+		q.Direction = direction
+	}
+	if rateInterval := queryParams.Get("rateInterval"); rateInterval != "" {
+		q.RateInterval = rateInterval
+	}
+
+	metricsService, namespaceInfo := createMetricsServiceForNamespace(w, r, promSupplier, q.Namespace)
+	if metricsService == nil {
+		// any returned value nil means error & response already written
+		return
+	}
+	rateInterval, err := util.AdjustRateInterval(namespaceInfo.CreationTimestamp, q.QueryTime, q.RateInterval)
 	if err != nil {
-		RespondWithError(w, http.StatusInternalServerError, err.Error())
+		RespondWithError(w, http.StatusBadRequest, "Bad request, cannot parse query parameter 'rateInterval': "+err.Error())
 		return
 	}
-	RespondWithJSON(w, http.StatusOK, metrics)
+	q.RateInterval = rateInterval
+
+	portMetrics, err := metricsService.GetWorkloadPortMetrics(q)
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+	RespondWithJSON(w, http.StatusOK, portMetrics)
+}
+
+// WorkloadSizeMetrics is the API handler to fetch the sizes of the request and response bodies of a workload
+func WorkloadSizeMetrics(w http.ResponseWriter, r *http.Request) {
+	getWorkloadSizeMetrics(w, r, defaultPromClientSupplier)
+}
+
+// getWorkloadSizeMetrics (mock-friendly version)
+func getWorkloadSizeMetrics(w http.ResponseWriter, r *http.Request, promSupplier promClientSupplier) {
+	vars := mux.Vars(r)
+	queryParams := r.URL.Query()
+
+	q := models.WorkloadSizeMetricsQuery{}
+	q.FillDefaults()
+	q.Namespace = vars["namespace"]
+	q.Workload = vars["workload"]
+	if direction := queryParams.Get("direction"); direction != "" {
+		if direction != "inbound" && direction != "outbound" {
+			RespondWithError(w, http.StatusBadRequest, "Bad request, query parameter 'direction' must be either 'inbound' or 'outbound'")
+			return
+		}
+		q.Direction = direction
+	}
+	if rateInterval := queryParams.Get("rateInterval"); rateInterval != "" {
+		q.RateInterval = rateInterval
+	}
+	if quantiles, ok := queryParams["quantiles[]"]; ok && len(quantiles) > 0 {
+		for _, quantile := range quantiles {
+			f, err := strconv.ParseFloat(quantile, 64)
+			if err != nil {
+				RespondWithError(w, http.StatusBadRequest, "Bad request, cannot parse query parameter 'quantiles', float expected")
+				return
+			}
+			if f < 0 || f > 1 {
+				RespondWithError(w, http.StatusBadRequest, "Bad request, invalid quantile(s): should be between 0 and 1")
+				return
+			}
+		}
+		q.Quantiles = quantiles
+	}
+
+	metricsService, namespaceInfo := createMetricsServiceForNamespace(w, r, promSupplier, q.Namespace)
+	if metricsService == nil {
+		// any returned value nil means error & response already written
+		return
+	}
+	rateInterval, err := util.AdjustRateInterval(namespaceInfo.CreationTimestamp, q.QueryTime, q.RateInterval)
+	if err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Bad request, cannot parse query parameter 'rateInterval': "+err.Error())
+		return
+	}
+	q.RateInterval = rateInterval
+
+	sizeMetrics, err := metricsService.GetWorkloadSizeMetrics(q)
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+	RespondWithJSON(w, http.StatusOK, sizeMetrics)
+}
+
+// WorkloadConnectionMetrics is the API handler to fetch the TCP connections of a workload
+func WorkloadConnectionMetrics(w http.ResponseWriter, r *http.Request) {
+	getWorkloadConnectionMetrics(w, r, defaultPromClientSupplier)
+}
+
+// getWorkloadConnectionMetrics (mock-friendly version)
+func getWorkloadConnectionMetrics(w http.ResponseWriter, r *http.Request, promSupplier promClientSupplier) {
+	vars := mux.Vars(r)
+	queryParams := r.URL.Query()
+
+	q := models.WorkloadConnectionMetricsQuery{}
+	q.FillDefaults()
+	q.Namespace = vars["namespace"]
+	q.Workload = vars["workload"]
+	if rateInterval := queryParams.Get("rateInterval"); rateInterval != "" {
+		q.RateInterval = rateInterval
+	}
+
+	metricsService, namespaceInfo := createMetricsServiceForNamespace(w, r, promSupplier, q.Namespace)
+	if metricsService == nil {
+		// any returned value nil means error & response already written
+		return
+	}
+	rateInterval, err := util.AdjustRateInterval(namespaceInfo.CreationTimestamp, q.QueryTime, q.RateInterval)
+	if err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Bad request, cannot parse query parameter 'rateInterval': "+err.Error())
+		return
+	}
+	q.RateInterval = rateInterval
+
+	connectionMetrics, err := metricsService.GetWorkloadConnectionMetrics(q)
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+	RespondWithJSON(w, http.StatusOK, connectionMetrics)
+}
+
+// ServiceSLOBurnRate is the API handler to compute the error budget consumed by a service and its burn rates
+func ServiceSLOBurnRate(w http.ResponseWriter, r *http.Request) {
+	getServiceSLOBurnRate(w, r, defaultPromClientSupplier)
+}
+
+// getServiceSLOBurnRate (mock-friendly version)
+func getServiceSLOBurnRate(w http.ResponseWriter, r *http.Request, promSupplier promClientSupplier) {
+	vars := mux.Vars(r)
+	queryParams := r.URL.Query()
+
+	q := models.ServiceSLOQuery{}
+	q.FillDefaults()
+	q.Namespace = vars["namespace"]
+	q.Service = vars["service"]
+	if target := queryParams.Get("target"); target != "" {
+		f, err := strconv.ParseFloat(target, 64)
+		if err != nil {
+			RespondWithError(w, http.StatusBadRequest, "Bad request, cannot parse query parameter 'target', float expected")
+			return
+		}
+		q.Target = f
+	}
+	if window := queryParams.Get("window"); window != "" {
+		q.Window = window
+	}
+
+	metricsService, _ := createMetricsServiceForNamespace(w, r, promSupplier, q.Namespace)
+	if metricsService == nil {
+		// any returned value nil means error & response already written
+		return
+	}
+
+	slo, err := metricsService.GetServiceSLOBurnRate(q)
+	if err != nil {
+		if errors2.IsBadRequest(err) {
+			RespondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		handleErrorResponse(w, err)
+		return
+	}
+	RespondWithJSON(w, http.StatusOK, slo)
 }
 
 // ServiceMetrics is the API handler to fetch metrics to be displayed, related to a single service
@@ -107,12 +284,7 @@ func getServiceMetrics(w http.ResponseWriter, r *http.Request, promSupplier prom
 		return
 	}
 
-	metrics, err := metricsService.GetMetrics(params, nil)
-	if err != nil {
-		RespondWithError(w, http.StatusInternalServerError, err.Error())
-		return
-	}
-	RespondWithJSON(w, http.StatusOK, metrics)
+	respondWithMetrics(w, r, metricsService, params)
 }
 
 // AggregateMetrics is the API handler to fetch metrics to be displayed, related to a single aggregate
@@ -148,12 +320,7 @@ func getAggregateMetrics(w http.ResponseWriter, r *http.Request, promSupplier pr
 		return
 	}
 
-	metrics, err := metricsService.GetMetrics(params, nil)
-	if err != nil {
-		RespondWithError(w, http.StatusInternalServerError, err.Error())
-		return
-	}
-	RespondWithJSON(w, http.StatusOK, metrics)
+	respondWithMetrics(w, r, metricsService, params)
 }
 
 // NamespaceMetrics is the API handler to fetch metrics to be displayed, related to all
@@ -180,11 +347,35 @@ func getNamespaceMetrics(w http.ResponseWriter, r *http.Request, promSupplier pr
 		return
 	}
 
-	metrics, err := metricsService.GetMetrics(params, nil)
+	respondWithMetrics(w, r, metricsService, params)
+}
+
+// The response header holding the effective step of the metrics in seconds, increased for the long ranges
+const metricsStepHeader = "Kiali-Metrics-Step"
+
+// respondWithMetrics writes the metrics of the query, or their comparison with the same time window
+// shifted back by the 'compareOffset' query parameter (e.g. 7d) when it is set
+func respondWithMetrics(w http.ResponseWriter, r *http.Request, metricsService *business.MetricsService, params models.IstioMetricsQuery) {
+	if offset := r.URL.Query().Get("compareOffset"); offset != "" {
+		if err := models.ValidateOffset(offset); err != nil {
+			RespondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		comparison, err := metricsService.GetMetricsComparison(params, offset, nil)
+		if err != nil {
+			respondWithQueryError(w, err, http.StatusInternalServerError)
+			return
+		}
+		RespondWithJSON(w, http.StatusOK, comparison)
+		return
+	}
+
+	metrics, step, err := metricsService.GetDownsampledMetrics(params, nil)
 	if err != nil {
-		RespondWithError(w, http.StatusInternalServerError, err.Error())
+		respondWithQueryError(w, err, http.StatusInternalServerError)
 		return
 	}
+	w.Header().Set(metricsStepHeader, strconv.FormatInt(int64(step/time.Second), 10))
 	RespondWithJSON(w, http.StatusOK, metrics)
 }
 
@@ -213,6 +404,13 @@ func extractIstioMetricsQueryParams(r *http.Request, q *models.IstioMetricsQuery
 		}
 		q.Reporter = reporter
 	}
+	if exemplars := queryParams.Get("exemplars"); exemplars != "" {
+		if include, err := strconv.ParseBool(exemplars); err == nil {
+			q.Exemplars = include
+		} else {
+			return errors.New("bad request, cannot parse query parameter 'exemplars'")
+		}
+	}
 	return extractBaseMetricsQueryParams(queryParams, &q.RangeQuery, namespaceInfo)
 }
 
@@ -303,6 +501,128 @@ func extractBaseMetricsQueryParams(queryParams url.Values, q *prometheus.RangeQu
 	return nil
 }
 
+// NamespaceEdgeErrors is the API handler to explain the errors of an edge of the graph, from a source of the namespace
+func NamespaceEdgeErrors(w http.ResponseWriter, r *http.Request) {
+	getNamespaceEdgeErrors(w, r, defaultPromClientSupplier)
+}
+
+// getNamespaceEdgeErrors (mock-friendly version)
+func getNamespaceEdgeErrors(w http.ResponseWriter, r *http.Request, promSupplier promClientSupplier) {
+	vars := mux.Vars(r)
+	queryParams := r.URL.Query()
+
+	q := models.EdgeErrorsQuery{}
+	q.FillDefaults()
+	q.SourceNamespace = vars["namespace"]
+	q.SourceWorkload = queryParams.Get("sourceWorkload")
+	q.SourceApp = queryParams.Get("sourceApp")
+	q.DestinationNamespace = queryParams.Get("destinationNamespace")
+	if q.DestinationNamespace == "" {
+		q.DestinationNamespace = q.SourceNamespace
+	}
+	q.DestinationService = queryParams.Get("destinationService")
+	q.DestinationWorkload = queryParams.Get("destinationWorkload")
+	q.DestinationApp = queryParams.Get("destinationApp")
+	if q.DestinationService == "" && q.DestinationWorkload == "" && q.DestinationApp == "" {
+		RespondWithError(w, http.StatusBadRequest, "The destination of the edge is required: destinationService, destinationWorkload or destinationApp")
+		return
+	}
+	if reporter := queryParams.Get("reporter"); reporter != "" {
+		if reporter != "source" && reporter != "destination" {
+			RespondWithError(w, http.StatusBadRequest, "Bad request, query parameter 'reporter' must be either 'source' or 'destination'")
+			return
+		}
+		q.Reporter = reporter
+	}
+	if rateInterval := queryParams.Get("rateInterval"); rateInterval != "" {
+		q.RateInterval = rateInterval
+	}
+
+	metricsService, nsInfos := createMetricsServiceForNamespaces(w, r, promSupplier, []string{q.SourceNamespace, q.DestinationNamespace})
+	if metricsService == nil {
+		// any returned value nil means error & response already written
+		return
+	}
+	for _, nsInfo := range nsInfos {
+		if nsInfo.err != nil {
+			RespondWithError(w, http.StatusForbidden, "Cannot access namespace data: "+nsInfo.err.Error())
+			return
+		}
+	}
+	rateInterval, err := util.AdjustRateInterval(nsInfos[q.SourceNamespace].info.CreationTimestamp, q.QueryTime, q.RateInterval)
+	if err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Bad request, cannot parse query parameter 'rateInterval': "+err.Error())
+		return
+	}
+	q.RateInterval = rateInterval
+
+	edgeErrors, err := metricsService.GetEdgeErrors(q)
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+	RespondWithJSON(w, http.StatusOK, edgeErrors)
+}
+
+// NamespaceTopTalkers is the API handler to fetch the edges of a namespace with the highest rates
+func NamespaceTopTalkers(w http.ResponseWriter, r *http.Request) {
+	getNamespaceTopTalkers(w, r, defaultPromClientSupplier)
+}
+
+// getNamespaceTopTalkers (mock-friendly version)
+func getNamespaceTopTalkers(w http.ResponseWriter, r *http.Request, promSupplier promClientSupplier) {
+	vars := mux.Vars(r)
+	queryParams := r.URL.Query()
+
+	q := models.TopTalkersQuery{}
+	q.FillDefaults()
+	q.Namespace = vars["namespace"]
+	if direction := queryParams.Get("direction"); direction != "" {
+		if direction != "inbound" && direction != "outbound" {
+			RespondWithError(w, http.StatusBadRequest, "Bad request, query parameter 'direction' must be either 'inbound' or 'outbound'")
+			return
+		}
+		q.Direction = direction
+	}
+	if metric := queryParams.Get("metric"); metric != "" {
+		if metric != "requests" && metric != "request_bytes" && metric != "response_bytes" {
+			RespondWithError(w, http.StatusBadRequest, "Bad request, query parameter 'metric' must be either 'requests', 'request_bytes' or 'response_bytes'")
+			return
+		}
+		q.Metric = metric
+	}
+	if limit := queryParams.Get("limit"); limit != "" {
+		num, err := strconv.Atoi(limit)
+		if err != nil || num <= 0 {
+			RespondWithError(w, http.StatusBadRequest, "Bad request, query parameter 'limit' must be a positive number")
+			return
+		}
+		q.Limit = num
+	}
+	if rateInterval := queryParams.Get("rateInterval"); rateInterval != "" {
+		q.RateInterval = rateInterval
+	}
+
+	metricsService, namespaceInfo := createMetricsServiceForNamespace(w, r, promSupplier, q.Namespace)
+	if metricsService == nil {
+		// any returned value nil means error & response already written
+		return
+	}
+	rateInterval, err := util.AdjustRateInterval(namespaceInfo.CreationTimestamp, q.QueryTime, q.RateInterval)
+	if err != nil {
+		handleErrorResponse(w, err, "Adjust rate interval error: "+err.Error())
+		return
+	}
+	q.RateInterval = rateInterval
+
+	talkers, err := metricsService.GetTopTalkers(q)
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+	RespondWithJSON(w, http.StatusOK, talkers)
+}
+
 // MetricsStats is the API handler to compute some stats based on metrics
 func MetricsStats(w http.ResponseWriter, r *http.Request) {
 	body, err := ioutil.ReadAll(r.Body)
@@ -3,11 +3,14 @@ package handlers
 import (
 	"io/ioutil"
 	"net/http"
+	"strings"
 
 	"github.com/gorilla/mux"
+	"k8s.io/apimachinery/pkg/api/errors"
 
 	"github.com/kiali/kiali/log"
 	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/util"
 )
 
 func NamespaceList(w http.ResponseWriter, r *http.Request) {
@@ -19,7 +22,13 @@ func NamespaceList(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	namespaces, err := business.Namespace.GetNamespaces()
+	var namespaces []models.Namespace
+	// Only the namespaces with recent mesh traffic
+	if r.URL.Query().Get("active") == "true" {
+		namespaces, err = business.Namespace.GetActiveNamespaces(util.Clock.Now())
+	} else {
+		namespaces, err = business.Namespace.GetNamespaces()
+	}
 	if err != nil {
 		log.Error(err)
 		RespondWithError(w, http.StatusInternalServerError, err.Error())
@@ -55,6 +64,161 @@ func NamespaceValidationSummary(w http.ResponseWriter, r *http.Request) {
 	RespondWithJSON(w, http.StatusOK, validationSummary)
 }
 
+// NamespaceOverview is the API handler to fetch the health, the config counts, the mTLS status and the validations
+// summary of a namespace in a single call
+func NamespaceOverview(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	namespace := vars["namespace"]
+
+	business, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Business layer initialization error: "+err.Error())
+		return
+	}
+	if _, err = checkNamespaceAccess(business.Namespace, namespace); err != nil {
+		RespondWithError(w, http.StatusForbidden, "Cannot access namespace data: "+err.Error())
+		return
+	}
+
+	rateInterval := r.URL.Query().Get("rateInterval")
+	if rateInterval == "" {
+		rateInterval = defaultHealthRateInterval
+	}
+	queryTime := util.Clock.Now()
+	rateInterval, err = adjustRateInterval(business, namespace, rateInterval, queryTime)
+	if err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Bad request, cannot parse query parameter 'rateInterval': "+err.Error())
+		return
+	}
+
+	overview, err := business.Namespace.GetNamespaceOverview(namespace, rateInterval, queryTime)
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+
+	RespondWithJSON(w, http.StatusOK, overview)
+}
+
+// NamespaceFilteredValidations is the API handler to fetch the validations of the objects of a namespace matching
+// the objectType (comma separated), objectName and severity (minimum) query params
+func NamespaceFilteredValidations(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	namespace := vars["namespace"]
+	query := r.URL.Query()
+
+	filter := models.IstioValidationsFilter{
+		Name:        query.Get("objectName"),
+		MinSeverity: models.SeverityLevel(query.Get("severity")),
+	}
+	if objectTypes := query.Get("objectType"); objectTypes != "" {
+		filter.ObjectTypes = strings.Split(objectTypes, ",")
+	}
+
+	business, err := getBusiness(r)
+	if err != nil {
+		log.Error(err)
+		RespondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	validations, err := business.Validations.GetFilteredValidations(namespace, filter)
+	if err != nil {
+		if errors.IsBadRequest(err) {
+			RespondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		handleErrorResponse(w, err)
+		return
+	}
+
+	RespondWithJSON(w, http.StatusOK, validations)
+}
+
+// NamespaceUnusedIstioConfig is the API handler to fetch the report of the Istio objects of a namespace that have no effect
+func NamespaceUnusedIstioConfig(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	namespace := vars["namespace"]
+
+	business, err := getBusiness(r)
+	if err != nil {
+		log.Error(err)
+		RespondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	report, err := business.Validations.GetUnusedIstioConfig(namespace)
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+
+	RespondWithJSON(w, http.StatusOK, report)
+}
+
+// NamespaceConfigReferenceGraph is the API handler to fetch the graph of the references of the Istio objects of a namespace
+func NamespaceConfigReferenceGraph(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	namespace := vars["namespace"]
+
+	business, err := getBusiness(r)
+	if err != nil {
+		log.Error(err)
+		RespondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	graph, err := business.Validations.GetConfigReferenceGraph(namespace)
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+
+	RespondWithJSON(w, http.StatusOK, graph)
+}
+
+// NamespaceRateLimits is the API handler to fetch the inventory of the rate limits applying to the workloads of a namespace
+func NamespaceRateLimits(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	namespace := vars["namespace"]
+
+	business, err := getBusiness(r)
+	if err != nil {
+		log.Error(err)
+		RespondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	rateLimits, err := business.Validations.GetRateLimits(namespace)
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+
+	RespondWithJSON(w, http.StatusOK, rateLimits)
+}
+
+// NamespaceHeaderRoutes is the API to audit the routes of the VirtualServices of a namespace that match on request headers
+func NamespaceHeaderRoutes(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	namespace := vars["namespace"]
+
+	business, err := getBusiness(r)
+	if err != nil {
+		log.Error(err)
+		RespondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	headerRoutes, err := business.Validations.GetHeaderRoutes(namespace)
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+
+	RespondWithJSON(w, http.StatusOK, headerRoutes)
+}
+
 // NamespaceUpdate is the API to perform a patch on a Namespace configuration
 func NamespaceUpdate(w http.ResponseWriter, r *http.Request) {
 	params := mux.Vars(r)
@@ -75,6 +239,5 @@ func NamespaceUpdate(w http.ResponseWriter, r *http.Request) {
 		handleErrorResponse(w, err)
 		return
 	}
-	audit(r, "UPDATE on Namespace: "+namespace+" Patch: "+jsonPatch)
 	RespondWithJSON(w, http.StatusOK, ns)
 }
@@ -7,6 +7,7 @@ import (
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"strings"
 	"sync/atomic"
 	"testing"
@@ -26,6 +27,7 @@ import (
 	"github.com/kiali/kiali/kubernetes/kubetest"
 	"github.com/kiali/kiali/prometheus"
 	"github.com/kiali/kiali/prometheus/prometheustest"
+	"github.com/kiali/kiali/util"
 )
 
 // TestNamespaceMetricsDefault is unit test (testing request handling, not the prometheus client behaviour)
@@ -160,6 +162,145 @@ func setupNamespaceMetricsEndpoint(t *testing.T) (*httptest.Server, *prometheust
 	return ts, xapi, k8s
 }
 
+func TestNamespaceEdgeErrorsWithoutDestination(t *testing.T) {
+	ts, _, _ := setupNamespaceEdgeErrorsEndpoint(t)
+	defer ts.Close()
+
+	resp, err := http.Get(ts.URL + "/api/namespaces/ns/edge_errors?sourceWorkload=productpage-v1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
+func TestNamespaceEdgeErrorsBadRateInterval(t *testing.T) {
+	ts, _, _ := setupNamespaceEdgeErrorsEndpoint(t)
+	defer ts.Close()
+
+	resp, err := http.Get(ts.URL + "/api/namespaces/ns/edge_errors?sourceWorkload=productpage-v1&destinationService=reviews&rateInterval=" + url.QueryEscape("5m]) or vector(1) #"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
+func TestNamespaceEdgeErrorsInaccessibleDestination(t *testing.T) {
+	ts, _, k8s := setupNamespaceEdgeErrorsEndpoint(t)
+	defer ts.Close()
+
+	var nsNil *osproject_v1.Project
+	k8s.On("GetProject", "my_namespace").Return(nsNil, errors.New("no privileges"))
+
+	resp, err := http.Get(ts.URL + "/api/namespaces/ns/edge_errors?sourceWorkload=productpage-v1&destinationNamespace=my_namespace&destinationService=reviews")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	k8s.AssertCalled(t, "GetProject", "my_namespace")
+}
+
+func setupNamespaceEdgeErrorsEndpoint(t *testing.T) (*httptest.Server, *prometheustest.PromAPIMock, *kubetest.K8SClientMock) {
+	client, xapi, k8s, err := setupMocked()
+	if err != nil {
+		t.Fatal(err)
+	}
+	k8s.On("GetProject", "ns").Return(&osproject_v1.Project{}, nil)
+
+	mr := mux.NewRouter()
+	mr.HandleFunc("/api/namespaces/{namespace}/edge_errors", http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			context := context.WithValue(r.Context(), "authInfo", &api.AuthInfo{Token: "test"})
+			getNamespaceEdgeErrors(w, r.WithContext(context), func() (*prometheus.Client, error) {
+				return client, nil
+			})
+		}))
+
+	ts := httptest.NewServer(mr)
+	return ts, xapi, k8s
+}
+
+func TestNamespaceOverviewInaccessibleNamespace(t *testing.T) {
+	_, _, k8s, err := setupMocked()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var nsNil *osproject_v1.Project
+	k8s.On("GetProject", "my_namespace").Return(nsNil, errors.New("no privileges"))
+	util.Clock = util.RealClock{}
+
+	mr := mux.NewRouter()
+	mr.HandleFunc("/api/namespaces/{namespace}/overview", http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			context := context.WithValue(r.Context(), "authInfo", &api.AuthInfo{Token: "test"})
+			NamespaceOverview(w, r.WithContext(context))
+		}))
+	ts := httptest.NewServer(mr)
+	defer ts.Close()
+
+	resp, err := http.Get(ts.URL + "/api/namespaces/my_namespace/overview")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	k8s.AssertCalled(t, "GetProject", "my_namespace")
+}
+
+func TestNamespaceOverviewBadRateInterval(t *testing.T) {
+	_, _, k8s, err := setupMocked()
+	if err != nil {
+		t.Fatal(err)
+	}
+	k8s.On("GetProject", "ns").Return(&osproject_v1.Project{}, nil)
+	util.Clock = util.RealClock{}
+
+	mr := mux.NewRouter()
+	mr.HandleFunc("/api/namespaces/{namespace}/overview", http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			context := context.WithValue(r.Context(), "authInfo", &api.AuthInfo{Token: "test"})
+			NamespaceOverview(w, r.WithContext(context))
+		}))
+	ts := httptest.NewServer(mr)
+	defer ts.Close()
+
+	resp, err := http.Get(ts.URL + "/api/namespaces/ns/overview?rateInterval=" + url.QueryEscape("5m]) or vector(1) #"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
+func TestNamespaceTopTalkersBadParams(t *testing.T) {
+	client, _, k8s, err := setupMocked()
+	if err != nil {
+		t.Fatal(err)
+	}
+	k8s.On("GetProject", "ns").Return(&osproject_v1.Project{}, nil)
+
+	mr := mux.NewRouter()
+	mr.HandleFunc("/api/namespaces/{namespace}/top_talkers", http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			context := context.WithValue(r.Context(), "authInfo", &api.AuthInfo{Token: "test"})
+			getNamespaceTopTalkers(w, r.WithContext(context), func() (*prometheus.Client, error) {
+				return client, nil
+			})
+		}))
+	ts := httptest.NewServer(mr)
+	defer ts.Close()
+
+	for _, params := range []string{"direction=both", "metric=tcp_bytes", "limit=0", "limit=ten"} {
+		resp, err := http.Get(ts.URL + "/api/namespaces/ns/top_talkers?" + params)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode, params)
+	}
+}
+
 // Setup mock
 
 func setupMocked() (*prometheus.Client, *prometheustest.PromAPIMock, *kubetest.K8SClientMock, error) {
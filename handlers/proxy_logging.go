@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"k8s.io/apimachinery/pkg/api/errors"
+)
+
+// PodProxyLogging is the API handler to change the log level of the proxy of a pod
+func PodProxyLogging(w http.ResponseWriter, r *http.Request) {
+	params := mux.Vars(r)
+
+	// Get business layer
+	business, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Services initialization error: "+err.Error())
+		return
+	}
+
+	err = business.ProxyLogging.SetPodLogLevel(params["namespace"], params["pod"], r.URL.Query().Get("level"))
+	if err != nil {
+		if errors.IsBadRequest(err) {
+			RespondWithError(w, http.StatusBadRequest, err.Error())
+		} else {
+			handleErrorResponse(w, err)
+		}
+		return
+	}
+	RespondWithCode(w, http.StatusOK)
+}
+
+// NamespaceProxyLogLevel is the API handler to fetch the default log level of the proxies of a namespace
+func NamespaceProxyLogLevel(w http.ResponseWriter, r *http.Request) {
+	params := mux.Vars(r)
+
+	// Get business layer
+	business, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Services initialization error: "+err.Error())
+		return
+	}
+
+	logLevel, err := business.ProxyLogging.GetNamespaceLogLevel(params["namespace"])
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+	if logLevel == nil {
+		RespondWithError(w, http.StatusNotFound, "No default proxy log level in namespace "+params["namespace"])
+		return
+	}
+	RespondWithJSON(w, http.StatusOK, logLevel)
+}
+
+// NamespaceProxyLogLevelSet is the API handler to change the log level of the proxies of a namespace, current and new
+func NamespaceProxyLogLevelSet(w http.ResponseWriter, r *http.Request) {
+	params := mux.Vars(r)
+
+	// Get business layer
+	business, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Services initialization error: "+err.Error())
+		return
+	}
+
+	logLevel, err := business.ProxyLogging.SetNamespaceLogLevel(params["namespace"], r.URL.Query().Get("level"))
+	if err != nil {
+		if errors.IsBadRequest(err) {
+			RespondWithError(w, http.StatusBadRequest, err.Error())
+		} else {
+			handleErrorResponse(w, err)
+		}
+		return
+	}
+	RespondWithJSON(w, http.StatusOK, logLevel)
+}
+
+// NamespaceProxyLogLevelClear is the API handler to forget the default log level of the proxies of a namespace
+func NamespaceProxyLogLevelClear(w http.ResponseWriter, r *http.Request) {
+	params := mux.Vars(r)
+
+	// Get business layer
+	business, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Services initialization error: "+err.Error())
+		return
+	}
+
+	if err = business.ProxyLogging.ClearNamespaceLogLevel(params["namespace"]); err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+	RespondWithCode(w, http.StatusOK)
+}
@@ -4,6 +4,8 @@ import (
 	"net/http"
 
 	"github.com/gorilla/mux"
+
+	"github.com/kiali/kiali/util"
 )
 
 func ConfigDump(w http.ResponseWriter, r *http.Request) {
@@ -50,3 +52,75 @@ func ConfigDumpResourceEntries(w http.ResponseWriter, r *http.Request) {
 
 	RespondWithJSON(w, http.StatusOK, dump)
 }
+
+// NamespaceProxyStatus is the API handler to fetch the sync status of the proxies of a namespace
+func NamespaceProxyStatus(w http.ResponseWriter, r *http.Request) {
+	params := mux.Vars(r)
+
+	// Get business layer
+	business, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Services initialization error: "+err.Error())
+		return
+	}
+
+	status, err := business.ProxyStatus.GetNamespaceProxyStatus(params["namespace"])
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+
+	RespondWithJSON(w, http.StatusOK, status)
+}
+
+// NamespaceProxyResources is the API handler to fetch the resources and the usage of the proxies of a namespace
+func NamespaceProxyResources(w http.ResponseWriter, r *http.Request) {
+	params := mux.Vars(r)
+
+	// Get business layer
+	business, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Workloads initialization error: "+err.Error())
+		return
+	}
+
+	rateInterval := r.URL.Query().Get("rateInterval")
+	if rateInterval == "" {
+		rateInterval = defaultHealthRateInterval
+	}
+	namespace := params["namespace"]
+	queryTime := util.Clock.Now()
+	rateInterval, err = adjustRateInterval(business, namespace, rateInterval, queryTime)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Adjust rate interval error: "+err.Error())
+		return
+	}
+
+	resources, err := business.Workload.GetNamespaceProxyResources(namespace, rateInterval, queryTime)
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+
+	RespondWithJSON(w, http.StatusOK, resources)
+}
+
+// NamespaceProxyMemory is the API handler to fetch the memory pressure of the proxies of a namespace
+func NamespaceProxyMemory(w http.ResponseWriter, r *http.Request) {
+	params := mux.Vars(r)
+
+	// Get business layer
+	business, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Workloads initialization error: "+err.Error())
+		return
+	}
+
+	memory, err := business.Workload.GetNamespaceProxyMemory(params["namespace"], util.Clock.Now())
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+
+	RespondWithJSON(w, http.StatusOK, memory)
+}
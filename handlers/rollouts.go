@@ -0,0 +1,135 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/kiali/kiali/util"
+)
+
+const defaultRolloutRateInterval = "10m"
+
+// RolloutList is the API handler to fetch the progressive delivery rollouts (Flagger, Argo Rollouts) of a namespace
+func RolloutList(w http.ResponseWriter, r *http.Request) {
+	params := mux.Vars(r)
+
+	// Get business layer
+	business, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Workloads initialization error: "+err.Error())
+		return
+	}
+	namespace := params["namespace"]
+
+	rollouts, err := business.Workload.GetCanaryRollouts(namespace)
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+
+	RespondWithJSON(w, http.StatusOK, rollouts)
+}
+
+// AppRollouts is the API handler to fetch the progressive delivery rollouts of an app
+func AppRollouts(w http.ResponseWriter, r *http.Request) {
+	params := mux.Vars(r)
+
+	// Get business layer
+	business, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Apps initialization error: "+err.Error())
+		return
+	}
+	namespace := params["namespace"]
+	app := params["app"]
+
+	rollouts, err := business.App.GetAppCanaryRollouts(namespace, app)
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+
+	RespondWithJSON(w, http.StatusOK, rollouts)
+}
+
+// RolloutMetrics is the API handler to compare the success rate and latencies of the canary and the stable variants of a rollout
+func RolloutMetrics(w http.ResponseWriter, r *http.Request) {
+	params := mux.Vars(r)
+	query := r.URL.Query()
+
+	// Get business layer
+	business, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Workloads initialization error: "+err.Error())
+		return
+	}
+	namespace := params["namespace"]
+	rollout := params["rollout"]
+
+	queryTime := util.Clock.Now()
+	rateInterval := defaultRolloutRateInterval
+	if ri := query.Get("rateInterval"); ri != "" {
+		rateInterval = ri
+	}
+	rateInterval, err = adjustRateInterval(business, namespace, rateInterval, queryTime)
+	if err != nil {
+		handleErrorResponse(w, err, "Adjust rate interval error: "+err.Error())
+		return
+	}
+
+	metrics, err := business.Workload.GetCanaryRolloutMetrics(namespace, rollout, rateInterval, queryTime)
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+
+	RespondWithJSON(w, http.StatusOK, metrics)
+}
+
+// WorkloadComparison is the API handler to compare the metrics and the configuration of two workloads, like the
+// versions of a canary release
+func WorkloadComparison(w http.ResponseWriter, r *http.Request) {
+	params := mux.Vars(r)
+	query := r.URL.Query()
+
+	// Get business layer
+	business, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Workloads initialization error: "+err.Error())
+		return
+	}
+	namespace := params["namespace"]
+	workload := params["workload"]
+	withWorkload := query.Get("with")
+	if withWorkload == "" {
+		RespondWithError(w, http.StatusBadRequest, "The compared workload is required: with parameter is empty")
+		return
+	}
+	withNamespace := query.Get("withNamespace")
+	if withNamespace == "" {
+		withNamespace = namespace
+	}
+
+	// Both workloads share the time window, shortened to the age of the youngest namespace
+	queryTime := util.Clock.Now()
+	rateInterval := defaultRolloutRateInterval
+	if ri := query.Get("rateInterval"); ri != "" {
+		rateInterval = ri
+	}
+	for _, ns := range []string{namespace, withNamespace} {
+		rateInterval, err = adjustRateInterval(business, ns, rateInterval, queryTime)
+		if err != nil {
+			handleErrorResponse(w, err, "Adjust rate interval error: "+err.Error())
+			return
+		}
+	}
+
+	comparison, err := business.Workload.CompareWorkloads(namespace, workload, withNamespace, withWorkload, rateInterval, queryTime)
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+
+	RespondWithJSON(w, http.StatusOK, comparison)
+}
@@ -151,6 +151,120 @@ func ServiceUpdate(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	audit(r, "UPDATE on Namespace: "+namespace+" Service name: "+service+" Patch: "+jsonPatch)
 	RespondWithJSON(w, http.StatusOK, serviceDetails)
 }
+
+// ServiceTrafficSplits is the API handler to compare the weights configured for a service with its observed traffic
+func ServiceTrafficSplits(w http.ResponseWriter, r *http.Request) {
+	business, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Services initialization error: "+err.Error())
+		return
+	}
+
+	queryParams := r.URL.Query()
+	rateInterval := queryParams.Get("rateInterval")
+	if rateInterval == "" {
+		rateInterval = defaultHealthRateInterval
+	}
+
+	params := mux.Vars(r)
+	namespace := params["namespace"]
+	service := params["service"]
+	queryTime := util.Clock.Now()
+	rateInterval, err = adjustRateInterval(business, namespace, rateInterval, queryTime)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Adjust rate interval error: "+err.Error())
+		return
+	}
+
+	splits, err := business.Svc.GetServiceTrafficSplits(namespace, service, rateInterval, queryTime)
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+	RespondWithJSON(w, http.StatusOK, splits)
+}
+
+// ServiceSubsetHealth is the API handler to fetch the health of the workloads of a DestinationRule subset of a service
+func ServiceSubsetHealth(w http.ResponseWriter, r *http.Request) {
+	business, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Services initialization error: "+err.Error())
+		return
+	}
+
+	queryParams := r.URL.Query()
+	rateInterval := queryParams.Get("rateInterval")
+	if rateInterval == "" {
+		rateInterval = defaultHealthRateInterval
+	}
+
+	params := mux.Vars(r)
+	namespace := params["namespace"]
+	queryTime := util.Clock.Now()
+	rateInterval, err = adjustRateInterval(business, namespace, rateInterval, queryTime)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Adjust rate interval error: "+err.Error())
+		return
+	}
+
+	health, err := business.Health.GetServiceSubsetHealth(namespace, params["service"], params["subset"], rateInterval, queryTime)
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+	RespondWithJSON(w, http.StatusOK, health)
+}
+
+// ServiceEndpointsHealth is the API handler to count the ready and not ready endpoints of a service per zone
+func ServiceEndpointsHealth(w http.ResponseWriter, r *http.Request) {
+	business, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Services initialization error: "+err.Error())
+		return
+	}
+
+	params := mux.Vars(r)
+	health, err := business.Svc.GetServiceEndpointsHealth(params["namespace"], params["service"])
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+	RespondWithJSON(w, http.StatusOK, health)
+}
+
+// ServiceEffectiveDestinationRule is the API handler to resolve the DestinationRule in effect for a service
+func ServiceEffectiveDestinationRule(w http.ResponseWriter, r *http.Request) {
+	business, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Services initialization error: "+err.Error())
+		return
+	}
+
+	params := mux.Vars(r)
+	effective, err := business.Svc.GetEffectiveDestinationRule(params["namespace"], params["service"])
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+	RespondWithJSON(w, http.StatusOK, effective)
+}
+
+// ServiceResilienceConfig is the API handler to tell the timeout and retries of the routes to a service from the
+// ones inherited from the mesh
+func ServiceResilienceConfig(w http.ResponseWriter, r *http.Request) {
+	business, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Services initialization error: "+err.Error())
+		return
+	}
+
+	params := mux.Vars(r)
+	resilience, err := business.Svc.GetResilienceConfig(params["namespace"], params["service"])
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+	RespondWithJSON(w, http.StatusOK, resilience)
+}
@@ -330,6 +330,19 @@ func TestServiceMetricsInaccessibleNamespace(t *testing.T) {
 	k8s.AssertCalled(t, "GetProject", "my_namespace")
 }
 
+func TestServiceSLOBurnRateBadRequest(t *testing.T) {
+	ts, _, _ := setupServiceMetricsEndpoint(t)
+	defer ts.Close()
+
+	for _, query := range []string{"target=high", "target=100", "window=month"} {
+		resp, err := http.Get(ts.URL + "/api/namespaces/ns/services/svc/slo_burn_rate?" + query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode, query)
+	}
+}
+
 func setupServiceMetricsEndpoint(t *testing.T) (*httptest.Server, *prometheustest.PromAPIMock, *kubetest.K8SClientMock) {
 	conf := config.NewConfig()
 	conf.KubernetesConfig.CacheEnabled = false
@@ -352,6 +365,13 @@ func setupServiceMetricsEndpoint(t *testing.T) (*httptest.Server, *prometheustes
 				return prom, nil
 			})
 		}))
+	mr.HandleFunc("/api/namespaces/{namespace}/services/{service}/slo_burn_rate", http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			context := context.WithValue(r.Context(), "authInfo", &api.AuthInfo{Token: "test"})
+			getServiceSLOBurnRate(w, r.WithContext(context), func() (*prometheus.Client, error) {
+				return prom, nil
+			})
+		}))
 
 	ts := httptest.NewServer(mr)
 
@@ -0,0 +1,144 @@
+package handlers
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/log"
+	"github.com/kiali/kiali/util"
+)
+
+// The warning of the responses served stale, as defined by RFC 7234
+const staleResponseWarning = `110 - "Response is Stale"`
+
+// StaleWhileErrorHandler keeps the last successful response of each route, per user and per URL, and serves it
+// when the route fails because of its backend, marked as stale with its age
+type StaleWhileErrorHandler struct {
+	maxStaleAge time.Duration
+	lock        sync.RWMutex
+	responses   map[string]staleResponse
+	lastSweep   time.Time
+}
+
+type staleResponse struct {
+	time   time.Time
+	status int
+	header http.Header
+	body   []byte
+}
+
+// NewStaleWhileErrorHandler creates the handler keeping the responses for the max stale age of the configuration
+func NewStaleWhileErrorHandler() *StaleWhileErrorHandler {
+	return &StaleWhileErrorHandler{
+		maxStaleAge: time.Duration(config.Get().Server.StaleWhileError.MaxStaleAge) * time.Second,
+		responses:   map[string]staleResponse{},
+	}
+}
+
+// Handle wraps the handler of a route. It must be wrapped by the authentication handler: the responses are kept per
+// user, as they depend on the permissions of the user.
+func (h *StaleWhileErrorHandler) Handle(next http.Handler, routeName string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			next.ServeHTTP(w, r)
+			return
+		}
+		authInfo, err := getAuthInfo(r)
+		if err != nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+		key := staleResponseKey(routeName, authInfo.Token, r.URL)
+
+		buffered := &bufferedResponseWriter{header: http.Header{}, status: http.StatusOK}
+		next.ServeHTTP(buffered, r)
+
+		now := util.Clock.Now()
+		switch {
+		case buffered.status >= 200 && buffered.status < 300:
+			h.store(key, staleResponse{time: now, status: buffered.status, header: buffered.header, body: buffered.body.Bytes()}, now)
+		case buffered.status >= 500:
+			if cached, found := h.load(key, now); found {
+				age := now.Sub(cached.time)
+				log.Debugf("Route %s failed with status %d, serving the response of %s ago", routeName, buffered.status, age.Round(time.Second))
+				copyHeader(w.Header(), cached.header)
+				w.Header().Set("Age", strconv.Itoa(int(age.Seconds())))
+				w.Header().Add("Warning", staleResponseWarning)
+				w.WriteHeader(cached.status)
+				_, _ = w.Write(cached.body)
+				return
+			}
+		}
+
+		copyHeader(w.Header(), buffered.header)
+		w.WriteHeader(buffered.status)
+		_, _ = w.Write(buffered.body.Bytes())
+	})
+}
+
+// load returns the response kept for the key, unless older than the max stale age
+func (h *StaleWhileErrorHandler) load(key string, now time.Time) (staleResponse, bool) {
+	h.lock.RLock()
+	defer h.lock.RUnlock()
+	response, found := h.responses[key]
+	if !found || now.Sub(response.time) > h.maxStaleAge {
+		return staleResponse{}, false
+	}
+	return response, true
+}
+
+// store keeps the response for the key, dropping the responses older than the max stale age once per max stale age
+func (h *StaleWhileErrorHandler) store(key string, response staleResponse, now time.Time) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	h.responses[key] = response
+	if now.Sub(h.lastSweep) > h.maxStaleAge {
+		for k, r := range h.responses {
+			if now.Sub(r.time) > h.maxStaleAge {
+				delete(h.responses, k)
+			}
+		}
+		h.lastSweep = now
+	}
+}
+
+// staleResponseKey identifies a response by route, user and URL. The token is hashed not to be kept in memory, the
+// queryTime parameter is ignored as it changes with every refresh of the same view.
+func staleResponseKey(routeName, token string, u *url.URL) string {
+	query := u.Query()
+	query.Del("queryTime")
+	hash := sha256.Sum256([]byte(token))
+	return routeName + ":" + hex.EncodeToString(hash[:]) + ":" + u.Path + "?" + query.Encode()
+}
+
+func copyHeader(dst, src http.Header) {
+	for name, values := range src {
+		dst[name] = append([]string(nil), values...)
+	}
+}
+
+// bufferedResponseWriter buffers the response of a handler, to decide whether it's served or replaced
+type bufferedResponseWriter struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (r *bufferedResponseWriter) Header() http.Header {
+	return r.header
+}
+
+func (r *bufferedResponseWriter) WriteHeader(status int) {
+	r.status = status
+}
+
+func (r *bufferedResponseWriter) Write(b []byte) (int, error) {
+	return r.body.Write(b)
+}
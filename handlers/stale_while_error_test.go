@@ -0,0 +1,139 @@
+package handlers
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/client-go/tools/clientcmd/api"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/util"
+)
+
+// fakeBackendHandler responds with the health of the namespace while its backend is up, with an error otherwise
+type fakeBackendHandler struct {
+	down  bool
+	calls int
+}
+
+func (f *fakeBackendHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.calls++
+	if f.down {
+		RespondWithError(w, http.StatusServiceUnavailable, "Prometheus is unreachable")
+		return
+	}
+	if r.URL.Query().Get("namespace") == "missing" {
+		RespondWithError(w, http.StatusNotFound, "namespace not found")
+		return
+	}
+	RespondWithJSON(w, http.StatusOK, map[string]string{"status": "Healthy", "call": strconv.Itoa(f.calls)})
+}
+
+func staleWhileErrorRequest(t *testing.T, handler http.Handler, token, url string) (*http.Response, string) {
+	request := httptest.NewRequest("GET", url, nil)
+	request = request.WithContext(context.WithValue(request.Context(), "authInfo", &api.AuthInfo{Token: token}))
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, request)
+	response := recorder.Result()
+	body, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return response, string(body)
+}
+
+func setupStaleWhileErrorHandler(now time.Time) *StaleWhileErrorHandler {
+	conf := config.NewConfig()
+	conf.Server.StaleWhileError.Enabled = true
+	conf.Server.StaleWhileError.MaxStaleAge = 300
+	config.Set(conf)
+	util.Clock = util.ClockMock{Time: now}
+	return NewStaleWhileErrorHandler()
+}
+
+func TestStaleWhileErrorServesLastKnownGood(t *testing.T) {
+	assert := assert.New(t)
+	now := time.Date(2021, 6, 1, 10, 0, 0, 0, time.UTC)
+	backend := &fakeBackendHandler{}
+	handler := setupStaleWhileErrorHandler(now).Handle(backend, "NamespaceHealth")
+
+	// Prime
+	response, primed := staleWhileErrorRequest(t, handler, "token", "http://kiali/api/namespaces/bookinfo/health?rateInterval=10m&queryTime=1622541600")
+	assert.Equal(http.StatusOK, response.StatusCode)
+	assert.Empty(response.Header.Get("Warning"))
+	assert.Empty(response.Header.Get("Age"))
+
+	// Backend failure: the primed response is served, marked as stale with its age. The queryTime of the refresh
+	// differs.
+	backend.down = true
+	util.Clock = util.ClockMock{Time: now.Add(90 * time.Second)}
+	response, body := staleWhileErrorRequest(t, handler, "token", "http://kiali/api/namespaces/bookinfo/health?rateInterval=10m&queryTime=1622541690")
+	assert.Equal(http.StatusOK, response.StatusCode)
+	assert.Equal(primed, body)
+	assert.Equal("90", response.Header.Get("Age"))
+	assert.Equal(`110 - "Response is Stale"`, response.Header.Get("Warning"))
+	assert.Equal("application/json", response.Header.Get("Content-Type"))
+	assert.Equal(2, backend.calls)
+
+	// Backend recovery: the fresh response is served and kept
+	backend.down = false
+	util.Clock = util.ClockMock{Time: now.Add(120 * time.Second)}
+	response, refreshed := staleWhileErrorRequest(t, handler, "token", "http://kiali/api/namespaces/bookinfo/health?rateInterval=10m")
+	assert.Equal(http.StatusOK, response.StatusCode)
+	assert.NotEqual(primed, refreshed)
+	assert.Empty(response.Header.Get("Warning"))
+
+	backend.down = true
+	util.Clock = util.ClockMock{Time: now.Add(130 * time.Second)}
+	response, body = staleWhileErrorRequest(t, handler, "token", "http://kiali/api/namespaces/bookinfo/health?rateInterval=10m")
+	assert.Equal(http.StatusOK, response.StatusCode)
+	assert.Equal(refreshed, body)
+	assert.Equal("10", response.Header.Get("Age"))
+}
+
+func TestStaleWhileErrorReturnsErrorWithoutLastKnownGood(t *testing.T) {
+	assert := assert.New(t)
+	now := time.Date(2021, 6, 1, 10, 0, 0, 0, time.UTC)
+	backend := &fakeBackendHandler{}
+	handler := setupStaleWhileErrorHandler(now).Handle(backend, "NamespaceHealth")
+
+	response, _ := staleWhileErrorRequest(t, handler, "token", "http://kiali/api/namespaces/bookinfo/health?rateInterval=10m")
+	assert.Equal(http.StatusOK, response.StatusCode)
+
+	backend.down = true
+
+	// Never served to this user
+	response, _ = staleWhileErrorRequest(t, handler, "other-token", "http://kiali/api/namespaces/bookinfo/health?rateInterval=10m")
+	assert.Equal(http.StatusServiceUnavailable, response.StatusCode)
+
+	// Never served with these parameters
+	response, _ = staleWhileErrorRequest(t, handler, "token", "http://kiali/api/namespaces/bookinfo/health?rateInterval=1m")
+	assert.Equal(http.StatusServiceUnavailable, response.StatusCode)
+
+	// Older than the max stale age
+	util.Clock = util.ClockMock{Time: now.Add(301 * time.Second)}
+	response, body := staleWhileErrorRequest(t, handler, "token", "http://kiali/api/namespaces/bookinfo/health?rateInterval=10m")
+	assert.Equal(http.StatusServiceUnavailable, response.StatusCode)
+	assert.Contains(body, "Prometheus is unreachable")
+	assert.Empty(response.Header.Get("Warning"))
+}
+
+func TestStaleWhileErrorKeepsClientErrors(t *testing.T) {
+	assert := assert.New(t)
+	now := time.Date(2021, 6, 1, 10, 0, 0, 0, time.UTC)
+	backend := &fakeBackendHandler{}
+	handler := setupStaleWhileErrorHandler(now).Handle(backend, "NamespaceHealth")
+
+	response, _ := staleWhileErrorRequest(t, handler, "token", "http://kiali/api/namespaces/bookinfo/health?namespace=missing")
+	assert.Equal(http.StatusNotFound, response.StatusCode)
+
+	backend.down = true
+	response, _ = staleWhileErrorRequest(t, handler, "token", "http://kiali/api/namespaces/bookinfo/health?namespace=missing")
+	assert.Equal(http.StatusServiceUnavailable, response.StatusCode)
+}
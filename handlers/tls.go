@@ -6,6 +6,7 @@ import (
 	"github.com/gorilla/mux"
 
 	"github.com/kiali/kiali/log"
+	"github.com/kiali/kiali/util"
 )
 
 // NamespaceTls is the API to get namespace-wide mTLS status
@@ -31,6 +32,60 @@ func NamespaceTls(w http.ResponseWriter, r *http.Request) {
 	RespondWithJSON(w, http.StatusOK, status)
 }
 
+// NamespaceBackendsTls is the API to get the TLS status of the Services routed by the Gateway API routes of a namespace
+func NamespaceBackendsTls(w http.ResponseWriter, r *http.Request) {
+	params := mux.Vars(r)
+
+	// Get business layer
+	business, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Services initialization error: "+err.Error())
+		return
+	}
+
+	namespace := params["namespace"]
+
+	statuses, err := business.TLS.BackendsTLSStatus(namespace)
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+
+	RespondWithJSON(w, http.StatusOK, statuses)
+}
+
+// NamespaceMtlsRecommendation is the API to get the next step toward STRICT mTLS of a namespace
+func NamespaceMtlsRecommendation(w http.ResponseWriter, r *http.Request) {
+	params := mux.Vars(r)
+
+	// Get business layer
+	business, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Services initialization error: "+err.Error())
+		return
+	}
+
+	namespace := params["namespace"]
+	queryTime := util.Clock.Now()
+	rateInterval := defaultHealthRateInterval
+	if ri := r.URL.Query().Get("rateInterval"); ri != "" {
+		rateInterval = ri
+	}
+	rateInterval, err = adjustRateInterval(business, namespace, rateInterval, queryTime)
+	if err != nil {
+		handleErrorResponse(w, err, "Adjust rate interval error: "+err.Error())
+		return
+	}
+
+	recommendation, err := business.TLS.NamespaceMTLSRecommendation(namespace, rateInterval, queryTime)
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+
+	RespondWithJSON(w, http.StatusOK, recommendation)
+}
+
 // MeshTls is the API to get mesh-wide mTLS status
 func MeshTls(w http.ResponseWriter, r *http.Request) {
 	// Get business layer
@@ -14,7 +14,7 @@ import (
 
 type promClientSupplier func() (*prometheus.Client, error)
 
-var defaultPromClientSupplier = prometheus.NewClient
+var defaultPromClientSupplier = business.NewPrometheusClient
 
 func checkNamespaceAccess(nsServ business.NamespaceService, namespace string) (*models.Namespace, error) {
 	if nsInfo, err := nsServ.GetNamespace(namespace); err != nil {
@@ -59,7 +59,7 @@ func createMetricsServiceForNamespaces(w http.ResponseWriter, r *http.Request, p
 		info, err := checkNamespaceAccess(layer.Namespace, ns)
 		nsInfos[ns] = nsInfoError{info: info, err: err}
 	}
-	metrics := business.NewMetricsService(prom)
+	metrics := business.NewNamespaceScopedMetricsService(prom, &layer.Namespace)
 	return metrics, nsInfos
 }
 
@@ -84,5 +84,11 @@ func getBusiness(r *http.Request) (*business.Layer, error) {
 		return nil, err
 	}
 
-	return business.Get(authInfo)
+	layer, err := business.Get(authInfo)
+	if err != nil {
+		return nil, err
+	}
+	// The subject of the request, propagated by the authentication, is recorded in the audit log
+	layer.Audit.SetUser(r.Header.Get("Kiali-User"))
+	return layer, nil
 }
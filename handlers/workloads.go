@@ -1,10 +1,15 @@
 package handlers
 
 import (
+	"encoding/json"
 	"io/ioutil"
 	"net/http"
+	"strconv"
 
 	"github.com/gorilla/mux"
+	"k8s.io/apimachinery/pkg/api/errors"
+
+	"github.com/kiali/kiali/log"
 )
 
 // WorkloadList is the API handler to fetch all the workloads to be displayed, related to a single namespace
@@ -81,10 +86,145 @@ func WorkloadUpdate(w http.ResponseWriter, r *http.Request) {
 		handleErrorResponse(w, err)
 		return
 	}
-	audit(r, "UPDATE on Namespace: "+namespace+" Workload name: "+workload+" Type: "+workloadType+" Patch: "+jsonPatch)
 	RespondWithJSON(w, http.StatusOK, workloadDetails)
 }
 
+// WorkloadMetadataUpdate is the API to add, update or remove labels and annotations of a Workload, or of its pods
+func WorkloadMetadataUpdate(w http.ResponseWriter, r *http.Request) {
+	params := mux.Vars(r)
+	query := r.URL.Query()
+
+	// Get business layer
+	business, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Workloads initialization error: "+err.Error())
+		return
+	}
+
+	namespace := params["namespace"]
+	workload := params["workload"]
+	workloadType := query.Get("type")
+	podTemplate := false
+	if value := query.Get("podTemplate"); value != "" {
+		if podTemplate, err = strconv.ParseBool(value); err != nil {
+			RespondWithError(w, http.StatusBadRequest, "Update request with bad podTemplate: "+err.Error())
+			return
+		}
+	}
+
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Update request with bad metadata: "+err.Error())
+		return
+	}
+	metadata := struct {
+		Labels      map[string]*string `json:"labels"`
+		Annotations map[string]*string `json:"annotations"`
+	}{}
+	if err = json.Unmarshal(body, &metadata); err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Update request with bad metadata: "+err.Error())
+		return
+	}
+
+	workloadDetails, err := business.Workload.PatchWorkloadMetadata(namespace, workload, workloadType, metadata.Labels, metadata.Annotations, podTemplate)
+	if err != nil {
+		if errors.IsBadRequest(err) {
+			RespondWithError(w, http.StatusBadRequest, err.Error())
+		} else {
+			handleErrorResponse(w, err)
+		}
+		return
+	}
+	RespondWithJSON(w, http.StatusOK, workloadDetails)
+}
+
+// WorkloadMaintenanceSet is the API to put a Workload in maintenance, suppressing its degraded or failure health
+func WorkloadMaintenanceSet(w http.ResponseWriter, r *http.Request) {
+	params := mux.Vars(r)
+	query := r.URL.Query()
+
+	// Get business layer
+	business, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Workloads initialization error: "+err.Error())
+		return
+	}
+
+	workloadDetails, err := business.Workload.SetWorkloadMaintenance(params["namespace"], params["workload"], query.Get("type"), query.Get("duration"))
+	if err != nil {
+		if errors.IsBadRequest(err) {
+			RespondWithError(w, http.StatusBadRequest, err.Error())
+		} else {
+			handleErrorResponse(w, err)
+		}
+		return
+	}
+	RespondWithJSON(w, http.StatusOK, workloadDetails)
+}
+
+// WorkloadMaintenanceClear is the API to end the maintenance of a Workload
+func WorkloadMaintenanceClear(w http.ResponseWriter, r *http.Request) {
+	params := mux.Vars(r)
+	query := r.URL.Query()
+
+	// Get business layer
+	business, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Workloads initialization error: "+err.Error())
+		return
+	}
+
+	workloadDetails, err := business.Workload.ClearWorkloadMaintenance(params["namespace"], params["workload"], query.Get("type"))
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+	RespondWithJSON(w, http.StatusOK, workloadDetails)
+}
+
+// WorkloadRolloutStatus is the API to get the rollout status of a Deployment or a StatefulSet
+func WorkloadRolloutStatus(w http.ResponseWriter, r *http.Request) {
+	params := mux.Vars(r)
+	query := r.URL.Query()
+
+	// Get business layer
+	business, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Workloads initialization error: "+err.Error())
+		return
+	}
+
+	rollout, err := business.Workload.GetWorkloadRolloutStatus(params["namespace"], params["workload"], query.Get("type"))
+	if err != nil {
+		if errors.IsBadRequest(err) {
+			RespondWithError(w, http.StatusBadRequest, err.Error())
+		} else {
+			handleErrorResponse(w, err)
+		}
+		return
+	}
+	RespondWithJSON(w, http.StatusOK, rollout)
+}
+
+// WorkloadAccessLogging is the API to get the effective access logging of the proxy of a Workload
+func WorkloadAccessLogging(w http.ResponseWriter, r *http.Request) {
+	params := mux.Vars(r)
+
+	// Get business layer
+	business, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Workloads initialization error: "+err.Error())
+		return
+	}
+
+	accessLogging, err := business.Workload.GetWorkloadAccessLogging(params["namespace"], params["workload"])
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+	RespondWithJSON(w, http.StatusOK, accessLogging)
+}
+
 // PodDetails is the API handler to fetch all details to be displayed, related to a single pod
 func PodDetails(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
@@ -108,6 +248,28 @@ func PodDetails(w http.ResponseWriter, r *http.Request) {
 	RespondWithJSON(w, http.StatusOK, podDetails)
 }
 
+// PodEnv is the API handler to fetch the environment of the containers of a pod
+func PodEnv(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	// Get business layer
+	business, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Pod Env initialization error: "+err.Error())
+		return
+	}
+	namespace := vars["namespace"]
+	pod := vars["pod"]
+
+	podEnv, err := business.Workload.GetWorkloadEnv(namespace, pod)
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+
+	RespondWithJSON(w, http.StatusOK, podEnv)
+}
+
 // PodLogs is the API handler to fetch logs for a single pod container
 func PodLogs(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
@@ -143,3 +305,50 @@ func PodLogs(w http.ResponseWriter, r *http.Request) {
 
 	RespondWithJSON(w, http.StatusOK, podLogs)
 }
+
+// PodAccessLogs is the API handler to fetch the parsed access logs of the proxy of a pod. When the workload of the
+// pod is given, the access logs are linked to the traces of the workload.
+func PodAccessLogs(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	queryParams := r.URL.Query()
+
+	// Get business layer
+	business, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Pod Access Logs initialization error: "+err.Error())
+		return
+	}
+	namespace := vars["namespace"]
+	pod := vars["pod"]
+
+	// Get log options, the container is always the proxy
+	opts, err := business.Workload.BuildLogOptionsCriteria(
+		"",
+		queryParams.Get("duration"),
+		queryParams.Get("sinceTime"),
+		queryParams.Get("tailLines"))
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+
+	accessLogs, err := business.Workload.GetPodAccessLogs(namespace, pod, opts)
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+
+	if workload := queryParams.Get("workload"); workload != "" {
+		q, err := readQuery(queryParams)
+		if err != nil {
+			RespondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		// The traces are optional, the access logs are returned without their trace ids when they can't be fetched
+		if err := business.Jaeger.CorrelateAccessLogs(namespace, workload, accessLogs, q); err != nil {
+			log.Warningf("Error linking the access logs of pod [%s.%s] to the traces of workload [%s]: %v", pod, namespace, workload, err)
+		}
+	}
+
+	RespondWithJSON(w, http.StatusOK, accessLogs)
+}
@@ -6,6 +6,7 @@ import (
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"strings"
 	"sync/atomic"
 	"testing"
@@ -25,6 +26,7 @@ import (
 
 	"github.com/kiali/kiali/business"
 	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
 	"github.com/kiali/kiali/kubernetes/kubetest"
 	"github.com/kiali/kiali/prometheus"
 	"github.com/kiali/kiali/prometheus/prometheustest"
@@ -311,6 +313,67 @@ func TestWorkloadMetricsInaccessibleNamespace(t *testing.T) {
 	k8s.AssertCalled(t, "GetProject", "my_namespace")
 }
 
+func TestWorkloadPortMetricsBadDirection(t *testing.T) {
+	ts, _, _ := setupWorkloadMetricsEndpoint(t)
+	defer ts.Close()
+
+	resp, err := http.Get(ts.URL + "/api/namespaces/ns/workloads/my_workload/port_metrics?direction=sideways")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
+func TestWorkloadPortMetricsBadRateInterval(t *testing.T) {
+	ts, _, _ := setupWorkloadMetricsEndpoint(t)
+	defer ts.Close()
+
+	resp, err := http.Get(ts.URL + "/api/namespaces/ns/workloads/my_workload/port_metrics?rateInterval=" + url.QueryEscape("5m]) or vector(1) #"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
+func TestWorkloadSizeMetricsBadQuantile(t *testing.T) {
+	ts, _, _ := setupWorkloadMetricsEndpoint(t)
+	defer ts.Close()
+
+	for _, quantile := range []string{"high", "95"} {
+		resp, err := http.Get(ts.URL + "/api/namespaces/ns/workloads/my_workload/size_metrics?quantiles[]=0.5&quantiles[]=" + quantile)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	}
+}
+
+func TestWorkloadSizeMetricsBadRateInterval(t *testing.T) {
+	ts, _, _ := setupWorkloadMetricsEndpoint(t)
+	defer ts.Close()
+
+	resp, err := http.Get(ts.URL + "/api/namespaces/ns/workloads/my_workload/size_metrics?rateInterval=" + url.QueryEscape("5m]) or vector(1) #"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
+func TestWorkloadConnectionMetricsBadRateInterval(t *testing.T) {
+	ts, _, _ := setupWorkloadMetricsEndpoint(t)
+	defer ts.Close()
+
+	resp, err := http.Get(ts.URL + "/api/namespaces/ns/workloads/my_workload/connection_metrics?rateInterval=" + url.QueryEscape("5m]) or vector(1) #"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
 func setupWorkloadMetricsEndpoint(t *testing.T) (*httptest.Server, *prometheustest.PromAPIMock, *kubetest.K8SClientMock) {
 	config.Set(config.NewConfig())
 	xapi := new(prometheustest.PromAPIMock)
@@ -330,6 +393,27 @@ func setupWorkloadMetricsEndpoint(t *testing.T) (*httptest.Server, *prometheuste
 				return prom, nil
 			})
 		}))
+	mr.HandleFunc("/api/namespaces/{namespace}/workloads/{workload}/port_metrics", http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			context := context.WithValue(r.Context(), "authInfo", &api.AuthInfo{Token: "test"})
+			getWorkloadPortMetrics(w, r.WithContext(context), func() (*prometheus.Client, error) {
+				return prom, nil
+			})
+		}))
+	mr.HandleFunc("/api/namespaces/{namespace}/workloads/{workload}/size_metrics", http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			context := context.WithValue(r.Context(), "authInfo", &api.AuthInfo{Token: "test"})
+			getWorkloadSizeMetrics(w, r.WithContext(context), func() (*prometheus.Client, error) {
+				return prom, nil
+			})
+		}))
+	mr.HandleFunc("/api/namespaces/{namespace}/workloads/{workload}/connection_metrics", http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			context := context.WithValue(r.Context(), "authInfo", &api.AuthInfo{Token: "test"})
+			getWorkloadConnectionMetrics(w, r.WithContext(context), func() (*prometheus.Client, error) {
+				return prom, nil
+			})
+		}))
 
 	ts := httptest.NewServer(mr)
 
@@ -338,3 +422,36 @@ func setupWorkloadMetricsEndpoint(t *testing.T) (*httptest.Server, *prometheuste
 
 	return ts, xapi, k8s
 }
+
+func TestPodAccessLogsEndpoint(t *testing.T) {
+	conf := config.NewConfig()
+	config.Set(conf)
+
+	k8s := kubetest.NewK8SClientMock()
+	k8s.On("GetProject", mock.AnythingOfType("string")).Return(&osproject_v1.Project{}, nil)
+	k8s.On("GetPodLogs", "ns", "httpbin-v1-3618568057-dnkjp", mock.MatchedBy(func(opts *core_v1.PodLogOptions) bool {
+		return opts.Container == "istio-proxy"
+	})).Return(&kubernetes.PodLogs{
+		Logs: `2020-11-25T21:26:19.000000000Z [2020-11-25T21:26:19.409Z] "GET /status/418 HTTP/1.1" 418 - via_upstream - "-" 0 135 3 1 "-" "curl/7.73.0-DEV" "84961386-6d84-929d-98bd-c5aee93b5c88" "httpbin:8000" "127.0.0.1:80" inbound|8000|| 127.0.0.1:41854 10.44.1.27:80 10.44.1.23:37652 outbound_.8000_._.httpbin.foo.svc.cluster.local default`,
+	}, nil)
+	mockClientFactory := kubetest.NewK8SClientFactoryMock(k8s)
+	business.SetWithBackends(mockClientFactory, nil)
+
+	mr := mux.NewRouter()
+	mr.HandleFunc("/api/namespaces/{namespace}/pods/{pod}/accesslogs", http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			context := context.WithValue(r.Context(), "authInfo", &api.AuthInfo{Token: "test"})
+			PodAccessLogs(w, r.WithContext(context))
+		}))
+	ts := httptest.NewServer(mr)
+	defer ts.Close()
+
+	resp, err := http.Get(ts.URL + "/api/namespaces/ns/pods/httpbin-v1-3618568057-dnkjp/accesslogs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	actual, _ := ioutil.ReadAll(resp.Body)
+
+	assert.Equal(t, 200, resp.StatusCode, string(actual))
+	assert.Contains(t, string(actual), "84961386-6d84-929d-98bd-c5aee93b5c88")
+}
@@ -42,21 +42,55 @@ type Client struct {
 	ctx        context.Context
 }
 
+// NewClient creates a client to the default tracing backend
 func NewClient(token string) (*Client, error) {
+	return newClient(token, "", config.Get().ExternalServices.Tracing.Auth)
+}
+
+// NewClusterClient creates a client to the tracing backend of the cluster.
+// The default tracing backend is used when the cluster doesn't have its own.
+func NewClusterClient(token, cluster string) (*Client, error) {
+	return NewClusterClientWithAuth(token, cluster, config.Get().ExternalServices.Tracing.Auth)
+}
+
+// NewClusterClientWithAuth creates a client to the tracing backend of the cluster, the default one when the cluster
+// is empty, authenticated with the given auth rather than the one of the config
+func NewClusterClientWithAuth(token, cluster string, auth config.Auth) (*Client, error) {
+	return newClient(token, config.Get().ExternalServices.Tracing.ClusterURLs[cluster], auth)
+}
+
+// HasClusterBackend returns true when the cluster has its own tracing backend,
+// different from the default one
+func HasClusterBackend(cluster string) bool {
+	cfg := config.Get()
+	clusterURL, found := cfg.ExternalServices.Tracing.ClusterURLs[cluster]
+	if !found || clusterURL == "" {
+		return false
+	}
+	defaultURL := cfg.ExternalServices.Tracing.InClusterURL
+	if !cfg.InCluster {
+		defaultURL = cfg.ExternalServices.Tracing.URL
+	}
+	return clusterURL != defaultURL
+}
+
+// newClient creates a client to the tracing backend at tracingURL, or to the default one when it's empty
+func newClient(token, tracingURL string, auth config.Auth) (*Client, error) {
 	cfg := config.Get()
 	cfgTracing := cfg.ExternalServices.Tracing
 
 	if !cfgTracing.Enabled {
 		return nil, errors.New("jaeger is not enabled")
 	} else {
-		auth := cfgTracing.Auth
 		if auth.UseKialiToken {
 			auth.Token = token
 		}
 		ctx := context.Background()
 
 		u, errParse := url.Parse(cfgTracing.InClusterURL)
-		if !cfg.InCluster {
+		if tracingURL != "" {
+			u, errParse = url.Parse(tracingURL)
+		} else if !cfg.InCluster {
 			u, errParse = url.Parse(cfgTracing.URL)
 		}
 		if errParse != nil {
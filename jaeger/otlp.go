@@ -0,0 +1,576 @@
+package jaeger
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/jaegertracing/jaeger/model"
+	jaegerModels "github.com/jaegertracing/jaeger/model/json"
+
+	"github.com/kiali/kiali/log"
+)
+
+// The span kinds of OTLP
+const (
+	OTLPSpanKindUnspecified = 0
+	OTLPSpanKindInternal    = 1
+	OTLPSpanKindServer      = 2
+	OTLPSpanKindClient      = 3
+	OTLPSpanKindProducer    = 4
+	OTLPSpanKindConsumer    = 5
+)
+
+// The status codes of OTLP
+const (
+	OTLPStatusCodeUnset = 0
+	OTLPStatusCodeOk    = 1
+	OTLPStatusCodeError = 2
+)
+
+// The tags of the Jaeger spans holding the fields of the OpenTelemetry spans, as set by the OpenTelemetry exporters
+const (
+	serviceNameAttribute        = "service.name"
+	spanKindTag                 = "span.kind"
+	errorTag                    = "error"
+	statusCodeTag               = "otel.status_code"
+	statusDescriptionTag        = "otel.status_description"
+	scopeNameTag                = "otel.scope.name"
+	scopeVersionTag             = "otel.scope.version"
+	libraryNameTag              = "otel.library.name"
+	libraryVersionTag           = "otel.library.version"
+	eventTag                    = "event"
+	refTypeAttribute            = "opentracing.ref_type"
+	refTypeChildOfAttribute     = "child_of"
+	refTypeFollowsFromAttribute = "follows_from"
+)
+
+var spanKinds = map[string]int{
+	"internal": OTLPSpanKindInternal,
+	"server":   OTLPSpanKindServer,
+	"client":   OTLPSpanKindClient,
+	"producer": OTLPSpanKindProducer,
+	"consumer": OTLPSpanKindConsumer,
+}
+
+// OTLPTracesData is the TracesData message of OTLP, in its JSON encoding
+type OTLPTracesData struct {
+	ResourceSpans []OTLPResourceSpans `json:"resourceSpans"`
+}
+
+// OTLPResourceSpans are the spans of a resource, a Jaeger process
+type OTLPResourceSpans struct {
+	Resource   OTLPResource     `json:"resource"`
+	ScopeSpans []OTLPScopeSpans `json:"scopeSpans"`
+}
+
+type OTLPResource struct {
+	Attributes []OTLPKeyValue `json:"attributes"`
+}
+
+// OTLPScopeSpans are the spans of a resource emitted by an instrumentation library
+type OTLPScopeSpans struct {
+	Scope OTLPScope  `json:"scope"`
+	Spans []OTLPSpan `json:"spans"`
+}
+
+type OTLPScope struct {
+	Name    string `json:"name,omitempty"`
+	Version string `json:"version,omitempty"`
+}
+
+// OTLPSpan is a span of OTLP. The IDs are hex encoded, the timestamps are nanoseconds since the epoch.
+type OTLPSpan struct {
+	TraceID           string         `json:"traceId"`
+	SpanID            string         `json:"spanId"`
+	ParentSpanID      string         `json:"parentSpanId,omitempty"`
+	Flags             uint32         `json:"flags,omitempty"`
+	Name              string         `json:"name"`
+	Kind              int            `json:"kind"`
+	StartTimeUnixNano string         `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string         `json:"endTimeUnixNano"`
+	Attributes        []OTLPKeyValue `json:"attributes,omitempty"`
+	Events            []OTLPEvent    `json:"events,omitempty"`
+	Links             []OTLPLink     `json:"links,omitempty"`
+	Status            OTLPStatus     `json:"status"`
+}
+
+// OTLPEvent is an event of a span, a Jaeger log
+type OTLPEvent struct {
+	TimeUnixNano string         `json:"timeUnixNano"`
+	Name         string         `json:"name"`
+	Attributes   []OTLPKeyValue `json:"attributes,omitempty"`
+}
+
+// OTLPLink is a link of a span to another one, a Jaeger reference other than the parent
+type OTLPLink struct {
+	TraceID    string         `json:"traceId"`
+	SpanID     string         `json:"spanId"`
+	Attributes []OTLPKeyValue `json:"attributes,omitempty"`
+}
+
+type OTLPStatus struct {
+	Code    int    `json:"code,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+type OTLPKeyValue struct {
+	Key   string       `json:"key"`
+	Value OTLPAnyValue `json:"value"`
+}
+
+// OTLPAnyValue is a typed value of OTLP, a single field is set. The integers are encoded as strings, the bytes in
+// base64, like in the JSON encoding of protobuf.
+type OTLPAnyValue struct {
+	StringValue *string  `json:"stringValue,omitempty"`
+	BoolValue   *bool    `json:"boolValue,omitempty"`
+	IntValue    *string  `json:"intValue,omitempty"`
+	DoubleValue *float64 `json:"doubleValue,omitempty"`
+	BytesValue  *string  `json:"bytesValue,omitempty"`
+}
+
+// WriteOTLPTraces streams the traces to w in OTLP/JSON, a TracesData per trace and per line, the format of the OTLP
+// JSON files read by the OpenTelemetry collector. A trace which can't be converted is skipped.
+// It returns the number of traces written.
+func WriteOTLPTraces(w io.Writer, traces []jaegerModels.Trace) (int, error) {
+	encoder := json.NewEncoder(w)
+	written := 0
+	for _, trace := range traces {
+		data, err := TraceToOTLP(trace)
+		if err != nil {
+			log.Warningf("Skipping the export of trace [%s]: %v", trace.TraceID, err)
+			continue
+		}
+		// The encoder writes a value per line
+		if err := encoder.Encode(data); err != nil {
+			return written, err
+		}
+		written++
+	}
+	return written, nil
+}
+
+// TraceToOTLP converts a Jaeger trace to OTLP: a resource per process, grouping the spans by instrumentation library.
+// The tags are all kept as attributes, the OTLP fields they map to are also set: the span kind, the status and the
+// scope. The warnings of the trace are not exported.
+func TraceToOTLP(trace jaegerModels.Trace) (OTLPTracesData, error) {
+	data := OTLPTracesData{ResourceSpans: []OTLPResourceSpans{}}
+	resources := map[string]int{}
+	scopes := map[string]int{}
+	for i, span := range trace.Spans {
+		process, processKey := span.Process, string(span.ProcessID)
+		if process == nil {
+			p, ok := trace.Processes[span.ProcessID]
+			if !ok {
+				return data, fmt.Errorf("unknown process [%s] of span [%s]", span.ProcessID, span.SpanID)
+			}
+			process = &p
+		} else {
+			// A span embedding its process has its own resource
+			processKey = fmt.Sprintf("span-%d", i)
+		}
+		resourceIdx, ok := resources[processKey]
+		if !ok {
+			resourceIdx = len(data.ResourceSpans)
+			resources[processKey] = resourceIdx
+			data.ResourceSpans = append(data.ResourceSpans, OTLPResourceSpans{
+				Resource:   OTLPResource{Attributes: processToAttributes(*process)},
+				ScopeSpans: []OTLPScopeSpans{},
+			})
+		}
+		resource := &data.ResourceSpans[resourceIdx]
+
+		otlpSpan, scope, err := spanToOTLP(span)
+		if err != nil {
+			return data, err
+		}
+		scopeKey := fmt.Sprintf("%s/%s/%s", processKey, scope.Name, scope.Version)
+		scopeIdx, ok := scopes[scopeKey]
+		if !ok {
+			scopeIdx = len(resource.ScopeSpans)
+			scopes[scopeKey] = scopeIdx
+			resource.ScopeSpans = append(resource.ScopeSpans, OTLPScopeSpans{Scope: scope, Spans: []OTLPSpan{}})
+		}
+		resource.ScopeSpans[scopeIdx].Spans = append(resource.ScopeSpans[scopeIdx].Spans, otlpSpan)
+	}
+	return data, nil
+}
+
+func processToAttributes(process jaegerModels.Process) []OTLPKeyValue {
+	serviceName := process.ServiceName
+	attributes := []OTLPKeyValue{{Key: serviceNameAttribute, Value: OTLPAnyValue{StringValue: &serviceName}}}
+	return append(attributes, tagsToAttributes(process.Tags)...)
+}
+
+func spanToOTLP(span jaegerModels.Span) (OTLPSpan, OTLPScope, error) {
+	traceID, err := otlpTraceID(span.TraceID)
+	if err != nil {
+		return OTLPSpan{}, OTLPScope{}, err
+	}
+	spanID, err := otlpSpanID(span.SpanID)
+	if err != nil {
+		return OTLPSpan{}, OTLPScope{}, err
+	}
+	otlpSpan := OTLPSpan{
+		TraceID:           traceID,
+		SpanID:            spanID,
+		Flags:             span.Flags,
+		Name:              span.OperationName,
+		StartTimeUnixNano: strconv.FormatUint(span.StartTime*1000, 10),
+		EndTimeUnixNano:   strconv.FormatUint((span.StartTime+span.Duration)*1000, 10),
+		Attributes:        tagsToAttributes(span.Tags),
+	}
+
+	// The parent is the first CHILD_OF reference within the trace, the other references are links
+	parentFound := false
+	for _, ref := range span.References {
+		if !parentFound && ref.RefType == jaegerModels.ChildOf && ref.TraceID == span.TraceID {
+			if otlpSpan.ParentSpanID, err = otlpSpanID(ref.SpanID); err != nil {
+				return OTLPSpan{}, OTLPScope{}, err
+			}
+			parentFound = true
+			continue
+		}
+		link, err := referenceToLink(ref)
+		if err != nil {
+			return OTLPSpan{}, OTLPScope{}, err
+		}
+		otlpSpan.Links = append(otlpSpan.Links, link)
+	}
+	if !parentFound && span.ParentSpanID != "" {
+		if otlpSpan.ParentSpanID, err = otlpSpanID(span.ParentSpanID); err != nil {
+			return OTLPSpan{}, OTLPScope{}, err
+		}
+	}
+
+	for _, l := range span.Logs {
+		event := OTLPEvent{
+			TimeUnixNano: strconv.FormatUint(l.Timestamp*1000, 10),
+			Attributes:   tagsToAttributes(l.Fields),
+		}
+		if name, ok := tagValue(l.Fields, eventTag); ok {
+			event.Name = name
+		}
+		otlpSpan.Events = append(otlpSpan.Events, event)
+	}
+
+	if kind, ok := tagValue(span.Tags, spanKindTag); ok {
+		otlpSpan.Kind = spanKinds[strings.ToLower(kind)]
+	}
+	if code, ok := tagValue(span.Tags, statusCodeTag); ok {
+		switch strings.ToUpper(code) {
+		case "OK":
+			otlpSpan.Status.Code = OTLPStatusCodeOk
+		case "ERROR":
+			otlpSpan.Status.Code = OTLPStatusCodeError
+		}
+	}
+	if isError, ok := tagValue(span.Tags, errorTag); ok && isError == "true" {
+		otlpSpan.Status.Code = OTLPStatusCodeError
+	}
+	otlpSpan.Status.Message, _ = tagValue(span.Tags, statusDescriptionTag)
+
+	scope := OTLPScope{}
+	if scope.Name, _ = tagValue(span.Tags, scopeNameTag); scope.Name == "" {
+		scope.Name, _ = tagValue(span.Tags, libraryNameTag)
+	}
+	if scope.Version, _ = tagValue(span.Tags, scopeVersionTag); scope.Version == "" {
+		scope.Version, _ = tagValue(span.Tags, libraryVersionTag)
+	}
+	return otlpSpan, scope, nil
+}
+
+func referenceToLink(ref jaegerModels.Reference) (OTLPLink, error) {
+	traceID, err := otlpTraceID(ref.TraceID)
+	if err != nil {
+		return OTLPLink{}, err
+	}
+	spanID, err := otlpSpanID(ref.SpanID)
+	if err != nil {
+		return OTLPLink{}, err
+	}
+	refType := refTypeFollowsFromAttribute
+	if ref.RefType == jaegerModels.ChildOf {
+		refType = refTypeChildOfAttribute
+	}
+	return OTLPLink{
+		TraceID:    traceID,
+		SpanID:     spanID,
+		Attributes: []OTLPKeyValue{{Key: refTypeAttribute, Value: OTLPAnyValue{StringValue: &refType}}},
+	}, nil
+}
+
+// otlpTraceID converts a Jaeger trace ID, without its leading zeros, to the 32 hex characters of OTLP
+func otlpTraceID(id jaegerModels.TraceID) (string, error) {
+	traceID, err := model.TraceIDFromString(string(id))
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%016x%016x", traceID.High, traceID.Low), nil
+}
+
+// otlpSpanID converts a Jaeger span ID, without its leading zeros, to the 16 hex characters of OTLP
+func otlpSpanID(id jaegerModels.SpanID) (string, error) {
+	spanID, err := model.SpanIDFromString(string(id))
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%016x", uint64(spanID)), nil
+}
+
+// tagValue returns the value of a tag as a string
+func tagValue(tags []jaegerModels.KeyValue, key string) (string, bool) {
+	for _, tag := range tags {
+		if tag.Key == key {
+			return fmt.Sprint(tag.Value), true
+		}
+	}
+	return "", false
+}
+
+func tagsToAttributes(tags []jaegerModels.KeyValue) []OTLPKeyValue {
+	if len(tags) == 0 {
+		return nil
+	}
+	attributes := make([]OTLPKeyValue, 0, len(tags))
+	for _, tag := range tags {
+		attributes = append(attributes, OTLPKeyValue{Key: tag.Key, Value: tagToAnyValue(tag)})
+	}
+	return attributes
+}
+
+// tagToAnyValue converts the value of a tag, either as returned by the gRPC API of Jaeger, or as decoded from its
+// HTTP API: the numbers are then float64 and the binaries are base64 strings.
+func tagToAnyValue(tag jaegerModels.KeyValue) OTLPAnyValue {
+	switch tag.Type {
+	case jaegerModels.BoolType:
+		if b, ok := tag.Value.(bool); ok {
+			return OTLPAnyValue{BoolValue: &b}
+		}
+		b := fmt.Sprint(tag.Value) == "true"
+		return OTLPAnyValue{BoolValue: &b}
+	case jaegerModels.Int64Type:
+		var i string
+		switch v := tag.Value.(type) {
+		case float64:
+			i = strconv.FormatInt(int64(v), 10)
+		default:
+			i = fmt.Sprint(v)
+		}
+		return OTLPAnyValue{IntValue: &i}
+	case jaegerModels.Float64Type:
+		switch v := tag.Value.(type) {
+		case float64:
+			return OTLPAnyValue{DoubleValue: &v}
+		case json.Number:
+			if f, err := v.Float64(); err == nil {
+				return OTLPAnyValue{DoubleValue: &f}
+			}
+		}
+	case jaegerModels.BinaryType:
+		switch v := tag.Value.(type) {
+		case []byte:
+			s := base64.StdEncoding.EncodeToString(v)
+			return OTLPAnyValue{BytesValue: &s}
+		case string:
+			return OTLPAnyValue{BytesValue: &v}
+		}
+	}
+	s := fmt.Sprint(tag.Value)
+	return OTLPAnyValue{StringValue: &s}
+}
+
+// OTLPToTraces converts OTLP spans back to Jaeger traces, as returned by the gRPC API of Jaeger. The tags missing
+// for the span kind, the status and the scope are added.
+func OTLPToTraces(data OTLPTracesData) ([]jaegerModels.Trace, error) {
+	traces := []jaegerModels.Trace{}
+	traceIdx := map[jaegerModels.TraceID]int{}
+	for _, resourceSpans := range data.ResourceSpans {
+		process := attributesToProcess(resourceSpans.Resource.Attributes)
+		// The process IDs of a trace are p1, p2..., like in the traces of Jaeger
+		processIDs := map[jaegerModels.TraceID]jaegerModels.ProcessID{}
+		for _, scopeSpans := range resourceSpans.ScopeSpans {
+			for _, otlpSpan := range scopeSpans.Spans {
+				span, err := otlpToSpan(otlpSpan, scopeSpans.Scope)
+				if err != nil {
+					return nil, err
+				}
+				idx, ok := traceIdx[span.TraceID]
+				if !ok {
+					idx = len(traces)
+					traceIdx[span.TraceID] = idx
+					traces = append(traces, jaegerModels.Trace{
+						TraceID:   span.TraceID,
+						Spans:     []jaegerModels.Span{},
+						Processes: map[jaegerModels.ProcessID]jaegerModels.Process{},
+					})
+				}
+				trace := &traces[idx]
+				processID, ok := processIDs[span.TraceID]
+				if !ok {
+					processID = jaegerModels.ProcessID(fmt.Sprintf("p%d", len(trace.Processes)+1))
+					processIDs[span.TraceID] = processID
+					trace.Processes[processID] = process
+				}
+				span.ProcessID = processID
+				trace.Spans = append(trace.Spans, span)
+			}
+		}
+	}
+	return traces, nil
+}
+
+func attributesToProcess(attributes []OTLPKeyValue) jaegerModels.Process {
+	process := jaegerModels.Process{}
+	tags := make([]jaegerModels.KeyValue, 0, len(attributes))
+	for _, attribute := range attributes {
+		if attribute.Key == serviceNameAttribute && attribute.Value.StringValue != nil {
+			process.ServiceName = *attribute.Value.StringValue
+			continue
+		}
+		tags = append(tags, attributeToTag(attribute))
+	}
+	if len(tags) > 0 {
+		process.Tags = tags
+	}
+	return process
+}
+
+func otlpToSpan(otlpSpan OTLPSpan, scope OTLPScope) (jaegerModels.Span, error) {
+	traceID, err := model.TraceIDFromString(otlpSpan.TraceID)
+	if err != nil {
+		return jaegerModels.Span{}, err
+	}
+	spanID, err := model.SpanIDFromString(otlpSpan.SpanID)
+	if err != nil {
+		return jaegerModels.Span{}, err
+	}
+	start, err := strconv.ParseUint(otlpSpan.StartTimeUnixNano, 10, 64)
+	if err != nil {
+		return jaegerModels.Span{}, fmt.Errorf("invalid start time of span [%s]: %v", otlpSpan.SpanID, err)
+	}
+	end, err := strconv.ParseUint(otlpSpan.EndTimeUnixNano, 10, 64)
+	if err != nil {
+		return jaegerModels.Span{}, fmt.Errorf("invalid end time of span [%s]: %v", otlpSpan.SpanID, err)
+	}
+	span := jaegerModels.Span{
+		TraceID:       jaegerModels.TraceID(traceID.String()),
+		SpanID:        jaegerModels.SpanID(spanID.String()),
+		Flags:         otlpSpan.Flags,
+		OperationName: otlpSpan.Name,
+		References:    []jaegerModels.Reference{},
+		StartTime:     start / 1000,
+		Duration:      (end - start) / 1000,
+		Tags:          attributesToTags(otlpSpan.Attributes),
+		Logs:          []jaegerModels.Log{},
+	}
+
+	if otlpSpan.ParentSpanID != "" {
+		parentID, err := model.SpanIDFromString(otlpSpan.ParentSpanID)
+		if err != nil {
+			return jaegerModels.Span{}, err
+		}
+		span.References = append(span.References, jaegerModels.Reference{RefType: jaegerModels.ChildOf, TraceID: span.TraceID, SpanID: jaegerModels.SpanID(parentID.String())})
+	}
+	for _, link := range otlpSpan.Links {
+		ref, err := linkToReference(link)
+		if err != nil {
+			return jaegerModels.Span{}, err
+		}
+		span.References = append(span.References, ref)
+	}
+
+	for _, event := range otlpSpan.Events {
+		timestamp, err := strconv.ParseUint(event.TimeUnixNano, 10, 64)
+		if err != nil {
+			return jaegerModels.Span{}, fmt.Errorf("invalid time of an event of span [%s]: %v", otlpSpan.SpanID, err)
+		}
+		fields := attributesToTags(event.Attributes)
+		if _, ok := tagValue(fields, eventTag); !ok && event.Name != "" {
+			fields = append(fields, jaegerModels.KeyValue{Key: eventTag, Type: jaegerModels.StringType, Value: event.Name})
+		}
+		span.Logs = append(span.Logs, jaegerModels.Log{Timestamp: timestamp / 1000, Fields: fields})
+	}
+
+	if _, ok := tagValue(span.Tags, spanKindTag); !ok && otlpSpan.Kind != OTLPSpanKindUnspecified {
+		for kind, value := range spanKinds {
+			if value == otlpSpan.Kind {
+				span.Tags = append(span.Tags, jaegerModels.KeyValue{Key: spanKindTag, Type: jaegerModels.StringType, Value: kind})
+			}
+		}
+	}
+	_, hasError := tagValue(span.Tags, errorTag)
+	_, hasStatusCode := tagValue(span.Tags, statusCodeTag)
+	if otlpSpan.Status.Code == OTLPStatusCodeError && !hasError && !hasStatusCode {
+		span.Tags = append(span.Tags, jaegerModels.KeyValue{Key: errorTag, Type: jaegerModels.BoolType, Value: true})
+	}
+	if _, ok := tagValue(span.Tags, statusDescriptionTag); !ok && otlpSpan.Status.Message != "" {
+		span.Tags = append(span.Tags, jaegerModels.KeyValue{Key: statusDescriptionTag, Type: jaegerModels.StringType, Value: otlpSpan.Status.Message})
+	}
+	_, hasScopeName := tagValue(span.Tags, scopeNameTag)
+	_, hasLibraryName := tagValue(span.Tags, libraryNameTag)
+	if scope.Name != "" && !hasScopeName && !hasLibraryName {
+		span.Tags = append(span.Tags, jaegerModels.KeyValue{Key: scopeNameTag, Type: jaegerModels.StringType, Value: scope.Name})
+	}
+	_, hasScopeVersion := tagValue(span.Tags, scopeVersionTag)
+	_, hasLibraryVersion := tagValue(span.Tags, libraryVersionTag)
+	if scope.Version != "" && !hasScopeVersion && !hasLibraryVersion {
+		span.Tags = append(span.Tags, jaegerModels.KeyValue{Key: scopeVersionTag, Type: jaegerModels.StringType, Value: scope.Version})
+	}
+	return span, nil
+}
+
+func linkToReference(link OTLPLink) (jaegerModels.Reference, error) {
+	traceID, err := model.TraceIDFromString(link.TraceID)
+	if err != nil {
+		return jaegerModels.Reference{}, err
+	}
+	spanID, err := model.SpanIDFromString(link.SpanID)
+	if err != nil {
+		return jaegerModels.Reference{}, err
+	}
+	ref := jaegerModels.Reference{RefType: jaegerModels.FollowsFrom, TraceID: jaegerModels.TraceID(traceID.String()), SpanID: jaegerModels.SpanID(spanID.String())}
+	for _, attribute := range link.Attributes {
+		if attribute.Key == refTypeAttribute && attribute.Value.StringValue != nil && *attribute.Value.StringValue == refTypeChildOfAttribute {
+			ref.RefType = jaegerModels.ChildOf
+		}
+	}
+	return ref, nil
+}
+
+func attributesToTags(attributes []OTLPKeyValue) []jaegerModels.KeyValue {
+	tags := make([]jaegerModels.KeyValue, 0, len(attributes))
+	for _, attribute := range attributes {
+		tags = append(tags, attributeToTag(attribute))
+	}
+	return tags
+}
+
+func attributeToTag(attribute OTLPKeyValue) jaegerModels.KeyValue {
+	tag := jaegerModels.KeyValue{Key: attribute.Key, Type: jaegerModels.StringType, Value: ""}
+	v := attribute.Value
+	switch {
+	case v.StringValue != nil:
+		tag.Value = *v.StringValue
+	case v.BoolValue != nil:
+		tag.Type, tag.Value = jaegerModels.BoolType, *v.BoolValue
+	case v.IntValue != nil:
+		if i, err := strconv.ParseInt(*v.IntValue, 10, 64); err == nil {
+			tag.Type, tag.Value = jaegerModels.Int64Type, i
+		} else {
+			tag.Value = *v.IntValue
+		}
+	case v.DoubleValue != nil:
+		tag.Type, tag.Value = jaegerModels.Float64Type, *v.DoubleValue
+	case v.BytesValue != nil:
+		if b, err := base64.StdEncoding.DecodeString(*v.BytesValue); err == nil {
+			tag.Type, tag.Value = jaegerModels.BinaryType, b
+		} else {
+			tag.Value = *v.BytesValue
+		}
+	}
+	return tag
+}
@@ -0,0 +1,213 @@
+package jaeger
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	jaegerModels "github.com/jaegertracing/jaeger/model/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// otlpTrace is a trace as returned by the gRPC API of Jaeger, with native tag values
+func otlpTrace() jaegerModels.Trace {
+	return jaegerModels.Trace{
+		TraceID: "a1b2c3d4e5f6",
+		Spans: []jaegerModels.Span{{
+			TraceID:       "a1b2c3d4e5f6",
+			SpanID:        "1f",
+			Flags:         1,
+			OperationName: "productpage.bookinfo.svc.cluster.local:9080/*",
+			References:    []jaegerModels.Reference{},
+			StartTime:     1646922462000000,
+			Duration:      25000,
+			Tags: []jaegerModels.KeyValue{
+				{Key: "span.kind", Type: jaegerModels.StringType, Value: "server"},
+				{Key: "http.status_code", Type: jaegerModels.StringType, Value: "500"},
+				{Key: "error", Type: jaegerModels.BoolType, Value: true},
+				{Key: "request_size", Type: jaegerModels.Int64Type, Value: int64(1024)},
+				{Key: "sampling.ratio", Type: jaegerModels.Float64Type, Value: 0.25},
+			},
+			Logs: []jaegerModels.Log{{
+				Timestamp: 1646922462010000,
+				Fields: []jaegerModels.KeyValue{
+					{Key: "event", Type: jaegerModels.StringType, Value: "retry"},
+					{Key: "attempt", Type: jaegerModels.Int64Type, Value: int64(2)},
+				},
+			}},
+			ProcessID: "p1",
+		}, {
+			TraceID:       "a1b2c3d4e5f6",
+			SpanID:        "2e",
+			OperationName: "reviews.bookinfo.svc.cluster.local:9080/*",
+			References: []jaegerModels.Reference{
+				{RefType: jaegerModels.ChildOf, TraceID: "a1b2c3d4e5f6", SpanID: "1f"},
+				{RefType: jaegerModels.FollowsFrom, TraceID: "ffee00112233445566778899aabbccdd", SpanID: "3d"},
+			},
+			StartTime: 1646922462005000,
+			Duration:  12000,
+			Tags: []jaegerModels.KeyValue{
+				{Key: "span.kind", Type: jaegerModels.StringType, Value: "client"},
+				{Key: "otel.library.name", Type: jaegerModels.StringType, Value: "io.opentelemetry.okhttp"},
+				{Key: "otel.library.version", Type: jaegerModels.StringType, Value: "1.9.0"},
+				{Key: "payload", Type: jaegerModels.BinaryType, Value: []byte{0xca, 0xfe}},
+			},
+			Logs:      []jaegerModels.Log{},
+			ProcessID: "p2",
+		}},
+		Processes: map[jaegerModels.ProcessID]jaegerModels.Process{
+			"p1": {
+				ServiceName: "productpage.bookinfo",
+				Tags:        []jaegerModels.KeyValue{{Key: "hostname", Type: jaegerModels.StringType, Value: "productpage-v1-6b746f74dc-9stvs"}},
+			},
+			"p2": {
+				ServiceName: "reviews.bookinfo",
+				Tags:        []jaegerModels.KeyValue{{Key: "ip", Type: jaegerModels.StringType, Value: "10.244.0.12"}},
+			},
+		},
+	}
+}
+
+func TestTraceToOTLP(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	data, err := TraceToOTLP(otlpTrace())
+	require.NoError(err)
+	require.Len(data.ResourceSpans, 2)
+
+	productpage := data.ResourceSpans[0]
+	assert.Equal("service.name", productpage.Resource.Attributes[0].Key)
+	assert.Equal("productpage.bookinfo", *productpage.Resource.Attributes[0].Value.StringValue)
+	assert.Equal("productpage-v1-6b746f74dc-9stvs", *productpage.Resource.Attributes[1].Value.StringValue)
+	require.Len(productpage.ScopeSpans, 1)
+	assert.Equal(OTLPScope{}, productpage.ScopeSpans[0].Scope)
+
+	span := productpage.ScopeSpans[0].Spans[0]
+	assert.Equal("00000000000000000000a1b2c3d4e5f6", span.TraceID)
+	assert.Equal("000000000000001f", span.SpanID)
+	assert.Empty(span.ParentSpanID)
+	assert.Equal("1646922462000000000", span.StartTimeUnixNano)
+	assert.Equal("1646922462025000000", span.EndTimeUnixNano)
+	assert.Equal(OTLPSpanKindServer, span.Kind)
+	assert.Equal(OTLPStatusCodeError, span.Status.Code)
+	// The tags are kept, typed
+	require.Len(span.Attributes, 5)
+	assert.Equal("server", *span.Attributes[0].Value.StringValue)
+	assert.True(*span.Attributes[2].Value.BoolValue)
+	assert.Equal("1024", *span.Attributes[3].Value.IntValue)
+	assert.Equal(0.25, *span.Attributes[4].Value.DoubleValue)
+	require.Len(span.Events, 1)
+	assert.Equal("retry", span.Events[0].Name)
+	assert.Equal("1646922462010000000", span.Events[0].TimeUnixNano)
+
+	reviews := data.ResourceSpans[1]
+	require.Len(reviews.ScopeSpans, 1)
+	assert.Equal(OTLPScope{Name: "io.opentelemetry.okhttp", Version: "1.9.0"}, reviews.ScopeSpans[0].Scope)
+	span = reviews.ScopeSpans[0].Spans[0]
+	assert.Equal("000000000000001f", span.ParentSpanID)
+	assert.Equal(OTLPSpanKindClient, span.Kind)
+	assert.Equal(OTLPStatusCodeUnset, span.Status.Code)
+	assert.Equal("yv4=", *span.Attributes[3].Value.BytesValue)
+	require.Len(span.Links, 1)
+	assert.Equal("ffee00112233445566778899aabbccdd", span.Links[0].TraceID)
+	assert.Equal("000000000000003d", span.Links[0].SpanID)
+	assert.Equal("follows_from", *span.Links[0].Attributes[0].Value.StringValue)
+}
+
+func TestOTLPRoundTrip(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	trace := otlpTrace()
+	data, err := TraceToOTLP(trace)
+	require.NoError(err)
+
+	// Through the JSON encoding
+	var b bytes.Buffer
+	require.NoError(json.NewEncoder(&b).Encode(data))
+	decoded := OTLPTracesData{}
+	require.NoError(json.Unmarshal(b.Bytes(), &decoded))
+
+	traces, err := OTLPToTraces(decoded)
+	require.NoError(err)
+	require.Len(traces, 1)
+	assert.Equal(trace, traces[0])
+}
+
+func TestOTLPRoundTripAddsDerivedTags(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	start, end := "1646922462000000000", "1646922462001000000"
+	traces, err := OTLPToTraces(OTLPTracesData{ResourceSpans: []OTLPResourceSpans{{
+		ScopeSpans: []OTLPScopeSpans{{
+			Scope: OTLPScope{Name: "envoy"},
+			Spans: []OTLPSpan{{
+				TraceID:           "0000000000000000000000000000abcd",
+				SpanID:            "00000000000000ef",
+				Name:              "egress",
+				Kind:              OTLPSpanKindProducer,
+				StartTimeUnixNano: start,
+				EndTimeUnixNano:   end,
+				Events:            []OTLPEvent{{TimeUnixNano: end, Name: "sent"}},
+				Status:            OTLPStatus{Code: OTLPStatusCodeError, Message: "timeout"},
+			}},
+		}},
+	}}})
+	require.NoError(err)
+	require.Len(traces, 1)
+	span := traces[0].Spans[0]
+	assert.Equal(jaegerModels.TraceID("abcd"), span.TraceID)
+	assert.Equal(jaegerModels.SpanID("ef"), span.SpanID)
+	assert.Equal(uint64(1000), span.Duration)
+	assert.Equal([]jaegerModels.KeyValue{
+		{Key: "span.kind", Type: jaegerModels.StringType, Value: "producer"},
+		{Key: "error", Type: jaegerModels.BoolType, Value: true},
+		{Key: "otel.status_description", Type: jaegerModels.StringType, Value: "timeout"},
+		{Key: "otel.scope.name", Type: jaegerModels.StringType, Value: "envoy"},
+	}, span.Tags)
+	assert.Equal([]jaegerModels.KeyValue{{Key: "event", Type: jaegerModels.StringType, Value: "sent"}}, span.Logs[0].Fields)
+}
+
+func TestTraceToOTLPFromHTTPAPI(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	// The numbers and binaries decoded from the HTTP API of Jaeger
+	trace := jaegerModels.Trace{}
+	require.NoError(json.Unmarshal([]byte(`{"traceID":"abcd","spans":[{"traceID":"abcd","spanID":"ef","processID":"p1",
+		"tags":[{"key":"request_size","type":"int64","value":1024},{"key":"payload","type":"binary","value":"yv4="}]}],
+		"processes":{"p1":{"serviceName":"reviews.bookinfo"}}}`), &trace))
+
+	data, err := TraceToOTLP(trace)
+	require.NoError(err)
+	attributes := data.ResourceSpans[0].ScopeSpans[0].Spans[0].Attributes
+	assert.Equal("1024", *attributes[0].Value.IntValue)
+	assert.Equal("yv4=", *attributes[1].Value.BytesValue)
+}
+
+func TestWriteOTLPTraces(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	unknownProcess := otlpTrace()
+	unknownProcess.Processes = nil
+	var b bytes.Buffer
+	written, err := WriteOTLPTraces(&b, []jaegerModels.Trace{otlpTrace(), unknownProcess, otlpTrace()})
+	require.NoError(err)
+	assert.Equal(2, written)
+
+	// A TracesData per line
+	lines := 0
+	scanner := bufio.NewScanner(&b)
+	for scanner.Scan() {
+		data := OTLPTracesData{}
+		require.NoError(json.Unmarshal(scanner.Bytes(), &data))
+		assert.Len(data.ResourceSpans, 2)
+		lines++
+	}
+	assert.Equal(2, lines)
+}
@@ -165,6 +165,21 @@ func validateConfig() error {
 		return err
 	}
 
+	// Check the cache informers resync at a supported period
+	if err := config.Get().KubernetesConfig.ValidateCacheResyncPeriods(); err != nil {
+		return err
+	}
+
+	// Check the audit records are written to supported sinks
+	if err := config.Get().Server.ValidateAuditLogSinks(); err != nil {
+		return err
+	}
+
+	// Check the metrics and labels of a custom telemetry are mapped to valid names
+	if err := config.Get().ExternalServices.Istio.TelemetryMapping.Validate(); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -34,7 +34,10 @@ type (
 		KubernetesCache
 		IstioCache
 		NamespacesCache
+		NodesCache
 		ProxyStatusCache
+		TLSStatusCache
+		GraphCache
 	}
 
 	// This map will store Informers per specific types
@@ -59,6 +62,7 @@ type (
 		istioNetworkingGetter  cache.Getter
 		istioSecurityGetter    cache.Getter
 		refreshDuration        time.Duration
+		resyncPeriods          map[string]time.Duration
 		cacheNamespaces        []string
 		cacheIstioTypes        map[string]bool
 		stopChan               map[string]chan struct{}
@@ -70,6 +74,15 @@ type (
 		proxyStatusLock        sync.RWMutex
 		proxyStatusCreated     *time.Time
 		proxyStatusNamespaces  map[string]map[string]podProxyStatus
+		tlsStatusLock          sync.RWMutex
+		configGeneration       uint64
+		tlsStatuses            map[string]tlsStatusEntry
+		graphLock              sync.RWMutex
+		graphs                 map[string]*graphEntry
+		nodeLock               sync.Mutex
+		nodeInformer           cache.SharedIndexInformer
+		nodeStopChan           chan struct{}
+		nodeSyncFailed         bool
 	}
 )
 
@@ -106,6 +119,11 @@ func NewKialiCache() (KialiCache, error) {
 	}
 
 	refreshDuration := time.Duration(kConfig.KubernetesConfig.CacheDuration) * time.Second
+	resyncPeriods := make(map[string]time.Duration)
+	for _, resourceType := range kialiConfig.CacheResyncTypes {
+		resyncPeriods[resourceType] = kConfig.KubernetesConfig.CacheResyncPeriod(resourceType)
+	}
+	log.Tracef("[Kiali Cache] resyncPeriods %v", resyncPeriods)
 	tokenNamespaceDuration := time.Duration(kConfig.KubernetesConfig.CacheTokenNamespaceDuration) * time.Second
 	cacheNamespaces := kConfig.KubernetesConfig.CacheNamespaces
 	cacheIstioTypes := make(map[string]bool)
@@ -123,6 +141,7 @@ func NewKialiCache() (KialiCache, error) {
 	kialiCacheImpl := kialiCacheImpl{
 		istioClient:            *istioClient,
 		refreshDuration:        refreshDuration,
+		resyncPeriods:          resyncPeriods,
 		cacheNamespaces:        cacheNamespaces,
 		cacheIstioTypes:        cacheIstioTypes,
 		stopChan:               stopChan,
@@ -130,6 +149,7 @@ func NewKialiCache() (KialiCache, error) {
 		tokenNamespaces:        make(map[string]namespaceCache),
 		tokenNamespaceDuration: tokenNamespaceDuration,
 		proxyStatusNamespaces:  make(map[string]map[string]podProxyStatus),
+		tlsStatuses:            make(map[string]tlsStatusEntry),
 	}
 
 	kialiCacheImpl.k8sApi = istioClient.GetK8sApi()
@@ -150,6 +170,14 @@ func (c *kialiCacheImpl) isCached(namespace string) bool {
 	return false
 }
 
+// It will return the resync period of the informers of a resource type, the refresh duration by default
+func (c *kialiCacheImpl) resyncPeriod(resourceType string) time.Duration {
+	if period, ok := c.resyncPeriods[resourceType]; ok {
+		return period
+	}
+	return c.refreshDuration
+}
+
 func (c *kialiCacheImpl) createCache(namespace string) bool {
 	if _, exist := c.nsCache[namespace]; exist {
 		return true
@@ -225,6 +253,7 @@ func (c *kialiCacheImpl) Stop() {
 		close(nsChan)
 		delete(c.stopChan, namespace)
 	}
+	c.stopNodes()
 	log.Infof("Clearing Kiali Cache")
 	for ns := range c.nsCache {
 		delete(c.nsCache, ns)
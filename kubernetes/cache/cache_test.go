@@ -1,11 +1,18 @@
 package cache
 
 import (
+	"reflect"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	core_v1 "k8s.io/api/core/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/cache"
 
 	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
 )
 
 func TestNewKialiCache_isCached(t *testing.T) {
@@ -26,3 +33,165 @@ func TestNewKialiCache_isCached(t *testing.T) {
 	assert.False(kialiCacheImpl.isCached("bbcdefghi"))
 	assert.True(kialiCacheImpl.isCached("galicia"))
 }
+
+func TestTLSStatusCacheHit(t *testing.T) {
+	assert := assert.New(t)
+
+	kialiCacheImpl := kialiCacheImpl{
+		tlsStatuses: map[string]tlsStatusEntry{},
+	}
+
+	generation := kialiCacheImpl.GetConfigGeneration()
+	_, found := kialiCacheImpl.GetTLSStatus("mesh", generation)
+	assert.False(found)
+
+	kialiCacheImpl.SetTLSStatus("mesh", generation, models.MTLSStatus{Status: "MTLS_ENABLED"})
+	status, found := kialiCacheImpl.GetTLSStatus("mesh", generation)
+	assert.True(found)
+	assert.Equal("MTLS_ENABLED", status.Status)
+
+	// Resyncs don't change the object version and must keep the cached statuses
+	pa := &kubernetes.GenericIstioObject{ObjectMeta: meta_v1.ObjectMeta{Name: "default", ResourceVersion: "1"}}
+	kialiCacheImpl.tlsStatusEventHandler().OnUpdate(pa, pa)
+	assert.Equal(generation, kialiCacheImpl.GetConfigGeneration())
+	_, found = kialiCacheImpl.GetTLSStatus("mesh", generation)
+	assert.True(found)
+}
+
+func TestTLSStatusCacheInvalidation(t *testing.T) {
+	assert := assert.New(t)
+
+	kialiCacheImpl := kialiCacheImpl{
+		tlsStatuses: map[string]tlsStatusEntry{},
+	}
+	handler := kialiCacheImpl.tlsStatusEventHandler()
+
+	oldPa := &kubernetes.GenericIstioObject{ObjectMeta: meta_v1.ObjectMeta{Name: "default", ResourceVersion: "1"}}
+	newPa := &kubernetes.GenericIstioObject{ObjectMeta: meta_v1.ObjectMeta{Name: "default", ResourceVersion: "2"}}
+	dr := &kubernetes.GenericIstioObject{ObjectMeta: meta_v1.ObjectMeta{Name: "reviews", ResourceVersion: "1"}}
+
+	changes := []func(){
+		func() { handler.OnAdd(dr) },
+		func() { handler.OnUpdate(oldPa, newPa) },
+		func() { handler.OnDelete(dr) },
+	}
+
+	for _, change := range changes {
+		generation := kialiCacheImpl.GetConfigGeneration()
+		kialiCacheImpl.SetTLSStatus("mesh", generation, models.MTLSStatus{Status: "MTLS_ENABLED"})
+		kialiCacheImpl.SetTLSStatus("namespace:bookinfo", generation, models.MTLSStatus{Status: "MTLS_DISABLED"})
+
+		change()
+
+		newGeneration := kialiCacheImpl.GetConfigGeneration()
+		assert.Equal(generation+1, newGeneration)
+		_, found := kialiCacheImpl.GetTLSStatus("mesh", generation)
+		assert.False(found)
+		_, found = kialiCacheImpl.GetTLSStatus("namespace:bookinfo", newGeneration)
+		assert.False(found)
+	}
+
+	// Statuses computed with a stale generation are discarded
+	kialiCacheImpl.SetTLSStatus("mesh", kialiCacheImpl.GetConfigGeneration()-1, models.MTLSStatus{Status: "MTLS_ENABLED"})
+	_, found := kialiCacheImpl.GetTLSStatus("mesh", kialiCacheImpl.GetConfigGeneration())
+	assert.False(found)
+}
+
+func TestGetNode(t *testing.T) {
+	assert := assert.New(t)
+
+	kialiCacheImpl := kialiCacheImpl{
+		k8sApi: fake.NewSimpleClientset(&core_v1.Node{
+			ObjectMeta: meta_v1.ObjectMeta{
+				Name:   "node-1",
+				Labels: map[string]string{core_v1.LabelTopologyZone: "us-east-1a"},
+			},
+		}),
+	}
+	defer kialiCacheImpl.Stop()
+
+	node, err := kialiCacheImpl.GetNode("node-1")
+	assert.NoError(err)
+	assert.NotNil(node)
+	assert.Equal("us-east-1a", node.Labels[core_v1.LabelTopologyZone])
+
+	node, err = kialiCacheImpl.GetNode("node-2")
+	assert.NoError(err)
+	assert.Nil(node)
+}
+
+func TestGetNodeWhileSyncing(t *testing.T) {
+	assert := assert.New(t)
+
+	kialiCacheImpl := kialiCacheImpl{
+		k8sApi: fake.NewSimpleClientset(&core_v1.Node{ObjectMeta: meta_v1.ObjectMeta{Name: "node-1"}}),
+	}
+	// Another caller is syncing the informer: the node is read from the API meanwhile
+	kialiCacheImpl.nodeStopChan = make(chan struct{})
+	node, err := kialiCacheImpl.GetNode("node-1")
+	assert.NoError(err)
+	assert.Nil(node)
+
+	// Once stopped, the next caller syncs a new informer
+	kialiCacheImpl.stopNodes()
+	defer kialiCacheImpl.Stop()
+	node, err = kialiCacheImpl.GetNode("node-1")
+	assert.NoError(err)
+	assert.NotNil(node)
+}
+
+func TestInformersResyncPeriods(t *testing.T) {
+	assert := assert.New(t)
+
+	kialiCacheImpl := kialiCacheImpl{
+		k8sApi:          fake.NewSimpleClientset(),
+		refreshDuration: 5 * time.Minute,
+		resyncPeriods: map[string]time.Duration{
+			kubernetes.PodType:     time.Minute,
+			kubernetes.GatewayType: time.Hour,
+		},
+		cacheIstioTypes: map[string]bool{kubernetes.GatewayType: true, kubernetes.VirtualServiceType: true},
+	}
+
+	informer := make(typeCache)
+	kialiCacheImpl.createKubernetesInformers("bookinfo", &informer)
+	kialiCacheImpl.createIstioInformers("bookinfo", &informer)
+
+	assert.Equal(time.Minute, informerResyncPeriod(informer[kubernetes.PodType]))
+	assert.Equal(5*time.Minute, informerResyncPeriod(informer[kubernetes.DeploymentType]))
+	assert.Equal(time.Hour, informerResyncPeriod(informer[kubernetes.Gateways]))
+	assert.Equal(5*time.Minute, informerResyncPeriod(informer[kubernetes.VirtualServices]))
+}
+
+// informerResyncPeriod returns the resync period an informer was created with, client-go doesn't expose it
+func informerResyncPeriod(informer cache.SharedIndexInformer) time.Duration {
+	return time.Duration(reflect.ValueOf(informer).Elem().FieldByName("defaultEventHandlerResyncPeriod").Int())
+}
+
+func TestGraphCacheEvictsUnviewedGraphs(t *testing.T) {
+	assert := assert.New(t)
+
+	kialiCacheImpl := kialiCacheImpl{}
+	now := time.Date(2022, 03, 10, 14, 0, 0, 0, time.UTC)
+
+	kialiCacheImpl.ViewGraph("bookinfo", "bookinfo request", now.Add(-time.Minute))
+	kialiCacheImpl.ViewGraph("travels", "travels request", now.Add(-10*time.Minute))
+	kialiCacheImpl.SetGraph("bookinfo", CachedGraph{Graph: "bookinfo graph", Computed: now})
+	// Graphs are only cached while viewed
+	kialiCacheImpl.SetGraph("unknown", CachedGraph{Graph: "unknown graph", Computed: now})
+	_, found := kialiCacheImpl.GetGraph("unknown")
+	assert.False(found)
+
+	graph, found := kialiCacheImpl.GetGraph("bookinfo")
+	assert.True(found)
+	assert.Equal("bookinfo graph", graph.Graph)
+	assert.Equal(now, graph.Computed)
+
+	// A view older than the last one doesn't make the graph inactive
+	kialiCacheImpl.ViewGraph("bookinfo", "bookinfo request", now.Add(-time.Hour))
+	assert.Equal(map[string]interface{}{"bookinfo": "bookinfo request"}, kialiCacheImpl.ViewedGraphs(now.Add(-5*time.Minute)))
+	_, found = kialiCacheImpl.GetGraph("travels")
+	assert.False(found)
+	_, found = kialiCacheImpl.GetGraph("bookinfo")
+	assert.True(found)
+}
@@ -0,0 +1,83 @@
+package cache
+
+import (
+	"time"
+
+	"github.com/kiali/kiali/log"
+)
+
+type (
+	// GraphCache stores the graphs computed in background, keyed by their request. A graph is refreshed while it
+	// is viewed, the graphs not viewed for a while are evicted.
+	GraphCache interface {
+		// GetGraph returns the cached graph of the key
+		GetGraph(key string) (CachedGraph, bool)
+		// SetGraph caches the graph of a key still viewed
+		SetGraph(key string, graph CachedGraph)
+		// ViewGraph records a viewer of the graph of the key, with the request needed to refresh it
+		ViewGraph(key string, request interface{}, viewed time.Time)
+		// ViewedGraphs returns the requests of the graphs viewed since the time, the other graphs are evicted
+		ViewedGraphs(since time.Time) map[string]interface{}
+	}
+
+	// CachedGraph is a graph and the time it was computed at
+	CachedGraph struct {
+		Graph    interface{}
+		Computed time.Time
+	}
+
+	graphEntry struct {
+		graph   *CachedGraph
+		request interface{}
+		viewed  time.Time
+	}
+)
+
+func (c *kialiCacheImpl) GetGraph(key string) (CachedGraph, bool) {
+	defer c.graphLock.RUnlock()
+	c.graphLock.RLock()
+	if entry, ok := c.graphs[key]; ok && entry.graph != nil {
+		return *entry.graph, true
+	}
+	return CachedGraph{}, false
+}
+
+func (c *kialiCacheImpl) SetGraph(key string, graph CachedGraph) {
+	defer c.graphLock.Unlock()
+	c.graphLock.Lock()
+	// A graph evicted while it was computed isn't viewed anymore
+	if entry, ok := c.graphs[key]; ok {
+		entry.graph = &graph
+	}
+}
+
+func (c *kialiCacheImpl) ViewGraph(key string, request interface{}, viewed time.Time) {
+	defer c.graphLock.Unlock()
+	c.graphLock.Lock()
+	if c.graphs == nil {
+		c.graphs = make(map[string]*graphEntry)
+	}
+	if entry, ok := c.graphs[key]; ok {
+		entry.request = request
+		if viewed.After(entry.viewed) {
+			entry.viewed = viewed
+		}
+		return
+	}
+	c.graphs[key] = &graphEntry{request: request, viewed: viewed}
+}
+
+func (c *kialiCacheImpl) ViewedGraphs(since time.Time) map[string]interface{} {
+	defer c.graphLock.Unlock()
+	c.graphLock.Lock()
+	requests := make(map[string]interface{}, len(c.graphs))
+	for key, entry := range c.graphs {
+		if entry.viewed.Before(since) {
+			log.Tracef("[Kiali Cache] Evicting graph [%s] not viewed since %v", key, entry.viewed)
+			delete(c.graphs, key)
+			continue
+		}
+		requests[key] = entry.request
+	}
+	return requests
+}
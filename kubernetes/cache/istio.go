@@ -28,28 +28,30 @@ func (c *kialiCacheImpl) CheckIstioResource(resourceType string) bool {
 func (c *kialiCacheImpl) createIstioInformers(namespace string, informer *typeCache) {
 	// Networking API
 	if c.CheckIstioResource(kubernetes.VirtualServices) {
-		(*informer)[kubernetes.VirtualServices] = createIstioIndexInformer(c.istioNetworkingGetter, kubernetes.VirtualServices, c.refreshDuration, namespace)
+		(*informer)[kubernetes.VirtualServices] = createIstioIndexInformer(c.istioNetworkingGetter, kubernetes.VirtualServices, c.resyncPeriod(kubernetes.VirtualServiceType), namespace)
 	}
 	if c.CheckIstioResource(kubernetes.DestinationRules) {
-		(*informer)[kubernetes.DestinationRules] = createIstioIndexInformer(c.istioNetworkingGetter, kubernetes.DestinationRules, c.refreshDuration, namespace)
+		(*informer)[kubernetes.DestinationRules] = createIstioIndexInformer(c.istioNetworkingGetter, kubernetes.DestinationRules, c.resyncPeriod(kubernetes.DestinationRuleType), namespace)
+		(*informer)[kubernetes.DestinationRules].AddEventHandler(c.tlsStatusEventHandler())
 	}
 	if c.CheckIstioResource(kubernetes.Gateways) {
-		(*informer)[kubernetes.Gateways] = createIstioIndexInformer(c.istioNetworkingGetter, kubernetes.Gateways, c.refreshDuration, namespace)
+		(*informer)[kubernetes.Gateways] = createIstioIndexInformer(c.istioNetworkingGetter, kubernetes.Gateways, c.resyncPeriod(kubernetes.GatewayType), namespace)
 	}
 	if c.CheckIstioResource(kubernetes.ServiceEntries) {
-		(*informer)[kubernetes.ServiceEntries] = createIstioIndexInformer(c.istioNetworkingGetter, kubernetes.ServiceEntries, c.refreshDuration, namespace)
+		(*informer)[kubernetes.ServiceEntries] = createIstioIndexInformer(c.istioNetworkingGetter, kubernetes.ServiceEntries, c.resyncPeriod(kubernetes.ServiceEntryType), namespace)
 	}
 	if c.CheckIstioResource(kubernetes.Sidecars) {
-		(*informer)[kubernetes.Sidecars] = createIstioIndexInformer(c.istioNetworkingGetter, kubernetes.Sidecars, c.refreshDuration, namespace)
+		(*informer)[kubernetes.Sidecars] = createIstioIndexInformer(c.istioNetworkingGetter, kubernetes.Sidecars, c.resyncPeriod(kubernetes.SidecarType), namespace)
 	}
 	if c.CheckIstioResource(kubernetes.PeerAuthentications) {
-		(*informer)[kubernetes.PeerAuthentications] = createIstioIndexInformer(c.istioSecurityGetter, kubernetes.PeerAuthentications, c.refreshDuration, namespace)
+		(*informer)[kubernetes.PeerAuthentications] = createIstioIndexInformer(c.istioSecurityGetter, kubernetes.PeerAuthentications, c.resyncPeriod(kubernetes.PeerAuthenticationsType), namespace)
+		(*informer)[kubernetes.PeerAuthentications].AddEventHandler(c.tlsStatusEventHandler())
 	}
 	if c.CheckIstioResource(kubernetes.RequestAuthentications) {
-		(*informer)[kubernetes.RequestAuthentications] = createIstioIndexInformer(c.istioSecurityGetter, kubernetes.RequestAuthentications, c.refreshDuration, namespace)
+		(*informer)[kubernetes.RequestAuthentications] = createIstioIndexInformer(c.istioSecurityGetter, kubernetes.RequestAuthentications, c.resyncPeriod(kubernetes.RequestAuthenticationsType), namespace)
 	}
 	if c.CheckIstioResource(kubernetes.AuthorizationPolicies) {
-		(*informer)[kubernetes.AuthorizationPolicies] = createIstioIndexInformer(c.istioSecurityGetter, kubernetes.AuthorizationPolicies, c.refreshDuration, namespace)
+		(*informer)[kubernetes.AuthorizationPolicies] = createIstioIndexInformer(c.istioSecurityGetter, kubernetes.AuthorizationPolicies, c.resyncPeriod(kubernetes.AuthorizationPoliciesType), namespace)
 	}
 }
 
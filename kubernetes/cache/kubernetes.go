@@ -3,9 +3,12 @@ package cache
 import (
 	"errors"
 	"fmt"
+	"time"
 
 	apps_v1 "k8s.io/api/apps/v1"
 	core_v1 "k8s.io/api/core/v1"
+	discovery_v1beta1 "k8s.io/api/discovery/v1beta1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/client-go/informers"
 
@@ -19,6 +22,7 @@ type (
 		GetDeployments(namespace string) ([]apps_v1.Deployment, error)
 		GetDeployment(namespace, name string) (*apps_v1.Deployment, error)
 		GetEndpoints(namespace, name string) (*core_v1.Endpoints, error)
+		GetEndpointSlices(namespace, service string) ([]discovery_v1beta1.EndpointSlice, error)
 		GetStatefulSets(namespace string) ([]apps_v1.StatefulSet, error)
 		GetStatefulSet(namespace, name string) (*apps_v1.StatefulSet, error)
 		GetServices(namespace string, selectorLabels map[string]string) ([]core_v1.Service, error)
@@ -29,7 +33,17 @@ type (
 )
 
 func (c *kialiCacheImpl) createKubernetesInformers(namespace string, informer *typeCache) {
-	sharedInformers := informers.NewSharedInformerFactoryWithOptions(c.k8sApi, c.refreshDuration, informers.WithNamespace(namespace))
+	resyncConfig := map[meta_v1.Object]time.Duration{
+		&apps_v1.Deployment{}:              c.resyncPeriod(kubernetes.DeploymentType),
+		&apps_v1.StatefulSet{}:             c.resyncPeriod(kubernetes.StatefulSetType),
+		&apps_v1.ReplicaSet{}:              c.resyncPeriod(kubernetes.ReplicaSetType),
+		&core_v1.Service{}:                 c.resyncPeriod(kubernetes.ServiceType),
+		&core_v1.Pod{}:                     c.resyncPeriod(kubernetes.PodType),
+		&core_v1.ConfigMap{}:               c.resyncPeriod(kubernetes.ConfigMapType),
+		&core_v1.Endpoints{}:               c.resyncPeriod(kubernetes.EndpointsType),
+		&discovery_v1beta1.EndpointSlice{}: c.resyncPeriod(kubernetes.EndpointSliceType),
+	}
+	sharedInformers := informers.NewSharedInformerFactoryWithOptions(c.k8sApi, c.refreshDuration, informers.WithNamespace(namespace), informers.WithCustomResyncConfig(resyncConfig))
 	(*informer)[kubernetes.DeploymentType] = sharedInformers.Apps().V1().Deployments().Informer()
 	(*informer)[kubernetes.StatefulSetType] = sharedInformers.Apps().V1().StatefulSets().Informer()
 	(*informer)[kubernetes.ReplicaSetType] = sharedInformers.Apps().V1().ReplicaSets().Informer()
@@ -37,6 +51,7 @@ func (c *kialiCacheImpl) createKubernetesInformers(namespace string, informer *t
 	(*informer)[kubernetes.PodType] = sharedInformers.Core().V1().Pods().Informer()
 	(*informer)[kubernetes.ConfigMapType] = sharedInformers.Core().V1().ConfigMaps().Informer()
 	(*informer)[kubernetes.EndpointsType] = sharedInformers.Core().V1().Endpoints().Informer()
+	(*informer)[kubernetes.EndpointSliceType] = sharedInformers.Discovery().V1beta1().EndpointSlices().Informer()
 }
 
 func (c *kialiCacheImpl) isKubernetesSynced(namespace string) bool {
@@ -48,7 +63,8 @@ func (c *kialiCacheImpl) isKubernetesSynced(namespace string) bool {
 			nsCache[kubernetes.ServiceType].HasSynced() &&
 			nsCache[kubernetes.PodType].HasSynced() &&
 			nsCache[kubernetes.ConfigMapType].HasSynced() &&
-			nsCache[kubernetes.EndpointsType].HasSynced()
+			nsCache[kubernetes.EndpointsType].HasSynced() &&
+			nsCache[kubernetes.EndpointSliceType].HasSynced()
 	} else {
 		isSynced = false
 	}
@@ -135,6 +151,26 @@ func (c *kialiCacheImpl) GetEndpoints(namespace, name string) (*core_v1.Endpoint
 	return nil, nil
 }
 
+func (c *kialiCacheImpl) GetEndpointSlices(namespace, service string) ([]discovery_v1beta1.EndpointSlice, error) {
+	if nsCache, ok := c.nsCache[namespace]; ok {
+		slices := nsCache[kubernetes.EndpointSliceType].GetStore().List()
+		nsSlices := []discovery_v1beta1.EndpointSlice{}
+		for _, obj := range slices {
+			slice, ok := obj.(*discovery_v1beta1.EndpointSlice)
+			if !ok {
+				return nil, errors.New("bad EndpointSlice type found in cache")
+			}
+			// The slices of a service are labeled with its name
+			if slice.Labels[discovery_v1beta1.LabelServiceName] == service {
+				nsSlices = append(nsSlices, *slice)
+			}
+		}
+		log.Tracef("[Kiali Cache] Get [resource: EndpointSlice] for [namespace: %s] [service: %s] = %d", namespace, service, len(nsSlices))
+		return nsSlices, nil
+	}
+	return []discovery_v1beta1.EndpointSlice{}, nil
+}
+
 func (c *kialiCacheImpl) GetStatefulSets(namespace string) ([]apps_v1.StatefulSet, error) {
 	if nsCache, ok := c.nsCache[namespace]; ok {
 		ss := nsCache[kubernetes.StatefulSetType].GetStore().List()
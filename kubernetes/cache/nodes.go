@@ -0,0 +1,97 @@
+package cache
+
+import (
+	"errors"
+	"time"
+
+	core_v1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/log"
+)
+
+// Nodes informer may not sync if the Kiali ServiceAccount is not allowed to list nodes,
+// in that case the cache gives up after this timeout and the callers use the API instead.
+const nodesSyncTimeout = 10 * time.Second
+
+type (
+	NodesCache interface {
+		GetNode(name string) (*core_v1.Node, error)
+	}
+)
+
+// checkNodes returns the nodes informer, or nil while it's not synced. It's created on first use.
+// Nodes are cluster scoped, so a single informer is shared by all the namespace caches.
+// The informer is started and synced outside the lock, the callers use the API meanwhile.
+func (c *kialiCacheImpl) checkNodes() cache.SharedIndexInformer {
+	c.nodeLock.Lock()
+	if c.nodeInformer != nil || c.nodeStopChan != nil || c.nodeSyncFailed {
+		informer := c.nodeInformer
+		c.nodeLock.Unlock()
+		return informer
+	}
+	stopChan := make(chan struct{})
+	c.nodeStopChan = stopChan
+	c.nodeLock.Unlock()
+
+	sharedInformers := informers.NewSharedInformerFactory(c.k8sApi, c.resyncPeriod(kubernetes.NodeType))
+	informer := sharedInformers.Core().V1().Nodes().Informer()
+	go informer.Run(stopChan)
+
+	log.Infof("Waiting for Kiali cache for nodes to sync")
+	syncTimeout := make(chan struct{})
+	timer := time.AfterFunc(nodesSyncTimeout, func() { close(syncTimeout) })
+	defer timer.Stop()
+	synced := cache.WaitForCacheSync(syncTimeout, informer.HasSynced)
+
+	defer c.nodeLock.Unlock()
+	c.nodeLock.Lock()
+	if c.nodeStopChan != stopChan {
+		// Stopped while syncing
+		return nil
+	}
+	if !synced {
+		close(stopChan)
+		c.nodeStopChan = nil
+		c.nodeSyncFailed = true
+		log.Warningf("Kiali cache for nodes sync failure, nodes won't be cached")
+		return nil
+	}
+	c.nodeInformer = informer
+	log.Infof("Kiali cache for nodes started")
+	return informer
+}
+
+// GetNode returns the cached node, or nil when the node is not found or nodes can't be cached
+func (c *kialiCacheImpl) GetNode(name string) (*core_v1.Node, error) {
+	informer := c.checkNodes()
+	if informer == nil {
+		return nil, nil
+	}
+	// Nodes are not namespaced, the store key is the node name
+	obj, exist, err := informer.GetStore().GetByKey(name)
+	if err != nil {
+		return nil, err
+	}
+	if exist {
+		node, ok := obj.(*core_v1.Node)
+		if !ok {
+			return nil, errors.New("bad Node type found in cache")
+		}
+		log.Tracef("[Kiali Cache] Get [resource: Node] for [name: %s]", name)
+		return node, nil
+	}
+	return nil, nil
+}
+
+func (c *kialiCacheImpl) stopNodes() {
+	defer c.nodeLock.Unlock()
+	c.nodeLock.Lock()
+	if c.nodeStopChan != nil {
+		close(c.nodeStopChan)
+		c.nodeStopChan = nil
+	}
+	c.nodeInformer = nil
+}
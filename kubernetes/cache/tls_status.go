@@ -0,0 +1,86 @@
+package cache
+
+import (
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/log"
+	"github.com/kiali/kiali/models"
+)
+
+type (
+	// TLSStatusCache stores computed mTLS statuses keyed by the config generation they were computed with.
+	// The config generation is bumped every time a PeerAuthentication or DestinationRule changes in a cached
+	// namespace, so a single change invalidates all the dependent statuses.
+	TLSStatusCache interface {
+		GetConfigGeneration() uint64
+		BumpConfigGeneration()
+		GetTLSStatus(key string, generation uint64) (models.MTLSStatus, bool)
+		SetTLSStatus(key string, generation uint64, status models.MTLSStatus)
+	}
+
+	tlsStatusEntry struct {
+		generation uint64
+		status     models.MTLSStatus
+	}
+)
+
+func (c *kialiCacheImpl) GetConfigGeneration() uint64 {
+	defer c.tlsStatusLock.RUnlock()
+	c.tlsStatusLock.RLock()
+	return c.configGeneration
+}
+
+func (c *kialiCacheImpl) BumpConfigGeneration() {
+	defer c.tlsStatusLock.Unlock()
+	c.tlsStatusLock.Lock()
+	c.configGeneration++
+	c.tlsStatuses = make(map[string]tlsStatusEntry)
+	log.Tracef("[Kiali Cache] Config generation bumped to %d", c.configGeneration)
+}
+
+func (c *kialiCacheImpl) GetTLSStatus(key string, generation uint64) (models.MTLSStatus, bool) {
+	defer c.tlsStatusLock.RUnlock()
+	c.tlsStatusLock.RLock()
+	if entry, ok := c.tlsStatuses[key]; ok && entry.generation == generation && generation == c.configGeneration {
+		return entry.status, true
+	}
+	return models.MTLSStatus{}, false
+}
+
+func (c *kialiCacheImpl) SetTLSStatus(key string, generation uint64, status models.MTLSStatus) {
+	defer c.tlsStatusLock.Unlock()
+	c.tlsStatusLock.Lock()
+	// A status computed with an old generation may be based on outdated config, discard it
+	if generation != c.configGeneration {
+		return
+	}
+	if c.tlsStatuses == nil {
+		c.tlsStatuses = make(map[string]tlsStatusEntry)
+	}
+	c.tlsStatuses[key] = tlsStatusEntry{
+		generation: generation,
+		status:     status,
+	}
+}
+
+// tlsStatusEventHandler bumps the config generation on any change of the watched resources
+func (c *kialiCacheImpl) tlsStatusEventHandler() cache.ResourceEventHandler {
+	return cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			c.BumpConfigGeneration()
+		},
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			// Periodic resyncs deliver updates with the same object version, those are not changes
+			oldIo, oldOk := oldObj.(kubernetes.IstioObject)
+			newIo, newOk := newObj.(kubernetes.IstioObject)
+			if oldOk && newOk && oldIo.GetObjectMeta().ResourceVersion == newIo.GetObjectMeta().ResourceVersion {
+				return
+			}
+			c.BumpConfigGeneration()
+		},
+		DeleteFunc: func(obj interface{}) {
+			c.BumpConfigGeneration()
+		},
+	}
+}
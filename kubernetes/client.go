@@ -17,10 +17,13 @@ import (
 	batch_v1 "k8s.io/api/batch/v1"
 	batch_v1beta1 "k8s.io/api/batch/v1beta1"
 	core_v1 "k8s.io/api/core/v1"
+	discovery_v1beta1 "k8s.io/api/discovery/v1beta1"
 	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/runtime/serializer"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/version"
 	kube "k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
@@ -44,31 +47,39 @@ type PodLogs struct {
 type IstioClientInterface interface {
 	CreateIstioObject(api, namespace, resourceType, json string) (IstioObject, error)
 	DeleteIstioObject(api, namespace, resourceType, name string) error
+	DryRunCreateIstioObject(api, namespace, resourceType, json string) error
+	DryRunUpdateIstioObject(api, namespace, resourceType, name, jsonPatch string) error
 	GetIstioObject(namespace, resourceType, name string) (IstioObject, error)
 	GetIstioObjects(namespace, resourceType, labelSelector string) ([]IstioObject, error)
 	UpdateIstioObject(api, namespace, resourceType, name, jsonPatch string) (IstioObject, error)
 	GetProxyStatus() ([]*ProxyStatus, error)
 	GetConfigDump(namespace, podName string) (*ConfigDump, error)
+	SetProxyLogLevel(namespace, podName, level string) error
 }
 
 type K8SClientInterface interface {
+	CreateEvent(namespace string, event *core_v1.Event) error
 	GetConfigMap(namespace, configName string) (*core_v1.ConfigMap, error)
 	GetCronJobs(namespace string) ([]batch_v1beta1.CronJob, error)
+	GetCustomResources(namespace, group, version, resource string) ([]unstructured.Unstructured, error)
 	GetDeployment(namespace string, deploymentName string) (*apps_v1.Deployment, error)
 	GetDeployments(namespace string) ([]apps_v1.Deployment, error)
 	GetDeploymentsByLabel(namespace string, labelSelector string) ([]apps_v1.Deployment, error)
 	GetDeploymentConfig(namespace string, deploymentconfigName string) (*osapps_v1.DeploymentConfig, error)
 	GetDeploymentConfigs(namespace string) ([]osapps_v1.DeploymentConfig, error)
 	GetEndpoints(namespace string, serviceName string) (*core_v1.Endpoints, error)
+	GetEndpointSlices(namespace string, serviceName string) ([]discovery_v1beta1.EndpointSlice, error)
 	GetJobs(namespace string) ([]batch_v1.Job, error)
 	GetNamespace(namespace string) (*core_v1.Namespace, error)
 	GetNamespaces(labelSelector string) ([]core_v1.Namespace, error)
+	GetNode(name string) (*core_v1.Node, error)
 	GetPod(namespace, name string) (*core_v1.Pod, error)
 	GetPodLogs(namespace, name string, opts *core_v1.PodLogOptions) (*PodLogs, error)
 	GetPodProxy(namespace, name, path string) ([]byte, error)
 	GetPods(namespace, labelSelector string) ([]core_v1.Pod, error)
 	GetReplicationControllers(namespace string) ([]core_v1.ReplicationController, error)
 	GetReplicaSets(namespace string) ([]apps_v1.ReplicaSet, error)
+	GetSecret(namespace, name string) (*core_v1.Secret, error)
 	GetSecrets(namespace string, labelSelector string) ([]core_v1.Secret, error)
 	GetSelfSubjectAccessReview(namespace, api, resourceType string, verbs []string) ([]*auth_v1.SelfSubjectAccessReview, error)
 	GetService(namespace string, serviceName string) (*core_v1.Service, error)
@@ -80,6 +91,7 @@ type K8SClientInterface interface {
 	UpdateNamespace(namespace string, jsonPatch string) (*core_v1.Namespace, error)
 	UpdateService(namespace string, serviceName string, jsonPatch string) error
 	UpdateWorkload(namespace string, workloadName string, workloadType string, jsonPatch string) error
+	PatchWorkload(namespace string, workloadName string, workloadType string, patch string, patchType types.PatchType) error
 }
 
 type OSClientInterface interface {
@@ -108,8 +120,12 @@ type K8SClient struct {
 	token              string
 	k8s                *kube.Clientset
 	istioNetworkingApi *rest.RESTClient
-	istioSecurityApi   *rest.RESTClient
-	iter8Api           *rest.RESTClient
+	// Used for the networking resources only served by the v1beta1 version of the API
+	istioNetworkingV1Beta1Api *rest.RESTClient
+	istioSecurityApi          *rest.RESTClient
+	istioTelemetryApi         *rest.RESTClient
+	gatewayAPI                *rest.RESTClient
+	iter8Api                  *rest.RESTClient
 	// Used in REST queries after bump to client-go v0.20.x
 	ctx context.Context
 	// isOpenShift private variable will check if kiali is deployed under an OpenShift cluster or not
@@ -127,10 +143,22 @@ type K8SClient struct {
 	// See istio_details_service.go#hasNetworkingResource() for more details.
 	networkingResources *map[string]bool
 
+	// networkingV1Beta1Resources private variable will check which resources kiali has access to from networking.istio.io/v1beta1
+	// It is represented as a pointer to include the initialization phase.
+	networkingV1Beta1Resources *map[string]bool
+
 	// securityResources private variable will check which resources kiali has access to from security.istio.io group
 	// It is represented as a pointer to include the initialization phase.
 	// See istio_details_service.go#hasSecurityResource() for more details.
 	securityResources *map[string]bool
+
+	// telemetryResources private variable will check which resources kiali has access to from telemetry.istio.io group
+	// It is represented as a pointer to include the initialization phase.
+	telemetryResources *map[string]bool
+
+	// gatewayAPIResources private variable will check which resources kiali has access to from gateway.networking.k8s.io group
+	// It is represented as a pointer to include the initialization phase.
+	gatewayAPIResources *map[string]bool
 }
 
 // GetK8sApi returns the clientset referencing all K8s rest clients
@@ -248,10 +276,22 @@ func NewClientFromConfig(config *rest.Config) (*K8SClient, error) {
 				scheme.AddKnownTypeWithName(NetworkingGroupVersion.WithKind(nt.objectKind), &GenericIstioObject{})
 				scheme.AddKnownTypeWithName(NetworkingGroupVersion.WithKind(nt.collectionKind), &GenericIstioObjectList{})
 			}
+			for _, nt := range networkingV1Beta1Types {
+				scheme.AddKnownTypeWithName(NetworkingV1Beta1GroupVersion.WithKind(nt.objectKind), &GenericIstioObject{})
+				scheme.AddKnownTypeWithName(NetworkingV1Beta1GroupVersion.WithKind(nt.collectionKind), &GenericIstioObjectList{})
+			}
 			for _, rt := range securityTypes {
 				scheme.AddKnownTypeWithName(SecurityGroupVersion.WithKind(rt.objectKind), &GenericIstioObject{})
 				scheme.AddKnownTypeWithName(SecurityGroupVersion.WithKind(rt.collectionKind), &GenericIstioObjectList{})
 			}
+			for _, tt := range telemetryTypes {
+				scheme.AddKnownTypeWithName(TelemetryGroupVersion.WithKind(tt.objectKind), &GenericIstioObject{})
+				scheme.AddKnownTypeWithName(TelemetryGroupVersion.WithKind(tt.collectionKind), &GenericIstioObjectList{})
+			}
+			for _, gt := range gatewayAPITypes {
+				scheme.AddKnownTypeWithName(GatewayAPIGroupVersion.WithKind(gt.objectKind), &GenericIstioObject{})
+				scheme.AddKnownTypeWithName(GatewayAPIGroupVersion.WithKind(gt.collectionKind), &GenericIstioObjectList{})
+			}
 			// Register Extension (iter8) types
 			for _, rt := range iter8Types {
 				// We will use a Iter8ExperimentObject which only contains metadata and spec with interfaces
@@ -261,7 +301,10 @@ func NewClientFromConfig(config *rest.Config) (*K8SClient, error) {
 			}
 
 			meta_v1.AddToGroupVersion(scheme, NetworkingGroupVersion)
+			meta_v1.AddToGroupVersion(scheme, NetworkingV1Beta1GroupVersion)
 			meta_v1.AddToGroupVersion(scheme, SecurityGroupVersion)
+			meta_v1.AddToGroupVersion(scheme, TelemetryGroupVersion)
+			meta_v1.AddToGroupVersion(scheme, GatewayAPIGroupVersion)
 			meta_v1.AddToGroupVersion(scheme, Iter8GroupVersion)
 			return nil
 		})
@@ -276,18 +319,36 @@ func NewClientFromConfig(config *rest.Config) (*K8SClient, error) {
 		return nil, err
 	}
 
+	istioNetworkingV1Beta1API, err := newClientForAPI(config, NetworkingV1Beta1GroupVersion, types)
+	if err != nil {
+		return nil, err
+	}
+
 	istioSecurityApi, err := newClientForAPI(config, SecurityGroupVersion, types)
 	if err != nil {
 		return nil, err
 	}
 
+	istioTelemetryApi, err := newClientForAPI(config, TelemetryGroupVersion, types)
+	if err != nil {
+		return nil, err
+	}
+
+	gatewayAPI, err := newClientForAPI(config, GatewayAPIGroupVersion, types)
+	if err != nil {
+		return nil, err
+	}
+
 	iter8Api, err := newClientForAPI(config, Iter8GroupVersion, types)
 	if err != nil {
 		return nil, err
 	}
 
 	client.istioNetworkingApi = istioNetworkingAPI
+	client.istioNetworkingV1Beta1Api = istioNetworkingV1Beta1API
 	client.istioSecurityApi = istioSecurityApi
+	client.istioTelemetryApi = istioTelemetryApi
+	client.gatewayAPI = gatewayAPI
 	client.iter8Api = iter8Api
 	client.ctx = context.Background()
 	return &client, nil
@@ -112,6 +112,23 @@ type FilterChainMatch struct {
 	} `mapstructure:"prefix_ranges"`
 }
 
+type SecretDump struct {
+	DynamicActiveSecrets []DynamicSecret `mapstructure:"dynamic_active_secrets"`
+}
+
+// DynamicSecret is a secret delivered by SDS to the proxy, like the certificate of the workload ("default") or the
+// root certificate ("ROOTCA"). The certificates are base64 encoded PEM, the private keys are redacted.
+type DynamicSecret struct {
+	Name   string `mapstructure:"name"`
+	Secret struct {
+		TLSCertificate *struct {
+			CertificateChain struct {
+				InlineBytes string `mapstructure:"inline_bytes"`
+			} `mapstructure:"certificate_chain"`
+		} `mapstructure:"tls_certificate,omitempty"`
+	} `mapstructure:"secret"`
+}
+
 func (cd *ConfigDump) GetListeners() (*ListenerDump, error) {
 	listenersDumpRaw := cd.GetConfig("type.googleapis.com/envoy.admin.v3.ListenersConfigDump")
 	var listenersDump ListenerDump
@@ -130,6 +147,12 @@ func (cd *ConfigDump) GetRoutes() (*RouteDump, error) {
 	return &routeDump, mapstructure.Decode(routeDumpRaw, &routeDump)
 }
 
+func (cd *ConfigDump) GetSecrets() (*SecretDump, error) {
+	secretDumpRaw := cd.GetConfig("type.googleapis.com/envoy.admin.v3.SecretsConfigDump")
+	var secretDump SecretDump
+	return &secretDump, mapstructure.Decode(secretDumpRaw, &secretDump)
+}
+
 func (cd *ConfigDump) GetConfig(objectType string) map[string]interface{} {
 	for _, configRaw := range cd.Configs {
 		conf, ok := configRaw.(map[string]interface{})
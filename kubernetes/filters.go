@@ -75,6 +75,27 @@ func FilterServicesForSelector(selector labels.Selector, allServices []core_v1.S
 	return services
 }
 
+// FilterServicesExportedTo returns the services visible from the namespace, according to their
+// networking.istio.io/exportTo annotation. Services without the annotation are exported to all the namespaces.
+func FilterServicesExportedTo(namespace string, allServices []core_v1.Service) []core_v1.Service {
+	var services []core_v1.Service
+	for _, svc := range allServices {
+		exportTo, found := svc.Annotations["networking.istio.io/exportTo"]
+		if !found {
+			services = append(services, svc)
+			continue
+		}
+		for _, export := range strings.Split(exportTo, ",") {
+			export = strings.TrimSpace(export)
+			if export == "*" || export == namespace || (export == "." && svc.Namespace == namespace) {
+				services = append(services, svc)
+				break
+			}
+		}
+	}
+	return services
+}
+
 func FilterVirtualServices(allVs []IstioObject, namespace string, serviceName string) []IstioObject {
 	typeMeta := meta_v1.TypeMeta{
 		Kind:       PluralType[VirtualServices],
@@ -205,6 +226,9 @@ func FilterIstioObjectsForWorkloadSelector(workloadSelector string, allObjects [
 	// - RequestAuthentications -> spec/selector (istio.type.v1beta1.WorkloadSelector) -> map<string, string> match_labels
 	// - PeerAuthentications	-> spec/selector (istio.type.v1beta1.WorkloadSelector) -> map<string, string> match_labels
 	// - AuthorizationPolicies	-> spec/selector (istio.type.v1beta1.WorkloadSelector) -> map<string, string> match_labels
+	// - ProxyConfigs		-> spec/selector (istio.type.v1beta1.WorkloadSelector) -> map<string, string> match_labels
+	// Telemetry:
+	// - Telemetries		-> spec/selector (istio.type.v1beta1.WorkloadSelector) -> map<string, string> match_labels
 	istioObjects := []IstioObject{}
 
 	// workloadSelector is a representation of the template labels of a workload
@@ -239,7 +263,7 @@ func FilterIstioObjectsForWorkloadSelector(workloadSelector string, allObjects [
 					}
 				}
 			}
-		case RequestAuthenticationsType, PeerAuthenticationsType, AuthorizationPoliciesType:
+		case RequestAuthenticationsType, PeerAuthenticationsType, AuthorizationPoliciesType, ProxyConfigType, TelemetryType:
 			if workloadSelectorField, ok := object.GetSpec()["selector"]; ok {
 				if workloadSelectorFieldM, ok := workloadSelectorField.(map[string]interface{}); ok {
 					if labelsField, ok := workloadSelectorFieldM["matchLabels"]; ok {
@@ -63,3 +63,32 @@ func TestFilterPodsForEndpoints(t *testing.T) {
 	assert.Equal("pod-2", filtered[1].Name)
 	assert.Equal("pod-3", filtered[2].Name)
 }
+
+func TestFilterServicesExportedTo(t *testing.T) {
+	assert := assert.New(t)
+
+	exported := func(name, exportTo string) core_v1.Service {
+		svc := core_v1.Service{ObjectMeta: meta_v1.ObjectMeta{Name: name, Namespace: "bookinfo"}}
+		if exportTo != "" {
+			svc.Annotations = map[string]string{"networking.istio.io/exportTo": exportTo}
+		}
+		return svc
+	}
+	services := []core_v1.Service{
+		exported("default", ""),
+		exported("all", "*"),
+		exported("local", "."),
+		exported("private", "~"),
+		exported("listed", "foo, bar"),
+	}
+
+	filtered := FilterServicesExportedTo("bookinfo", services)
+	assert.Len(filtered, 3)
+	assert.Equal("default", filtered[0].Name)
+	assert.Equal("all", filtered[1].Name)
+	assert.Equal("local", filtered[2].Name)
+
+	filtered = FilterServicesExportedTo("bar", services)
+	assert.Len(filtered, 3)
+	assert.Equal("listed", filtered[2].Name)
+}
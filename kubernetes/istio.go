@@ -5,6 +5,8 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"net/http"
+	"net/url"
 	"regexp"
 	"strings"
 	"sync"
@@ -27,14 +29,23 @@ import (
 var (
 	portNameMatcher = regexp.MustCompile(`^[\-].*`)
 	portProtocols   = [...]string{"grpc", "http", "http2", "https", "mongo", "redis", "tcp", "tls", "udp", "mysql"}
+	// The forwardings to a fixed local port are done one at a time, they would collide otherwise
+	envoyLocalPortLock sync.Mutex
 )
 
-// Aux method to fetch proper (RESTClient, APIVersion) per API group
-func (in *K8SClient) getApiClientVersion(apiGroup string) (*rest.RESTClient, string) {
+// Aux method to fetch proper (RESTClient, APIVersion) per API group and resource type
+func (in *K8SClient) getApiClientVersion(apiGroup, resourceType string) (*rest.RESTClient, string) {
 	if apiGroup == NetworkingGroupVersion.Group {
+		if resourceType == ProxyConfigs {
+			return in.istioNetworkingV1Beta1Api, ApiNetworkingV1Beta1Version
+		}
 		return in.istioNetworkingApi, ApiNetworkingVersion
 	} else if apiGroup == SecurityGroupVersion.Group {
 		return in.istioSecurityApi, ApiSecurityVersion
+	} else if apiGroup == TelemetryGroupVersion.Group {
+		return in.istioTelemetryApi, ApiTelemetryVersion
+	} else if apiGroup == GatewayAPIGroupVersion.Group {
+		return in.gatewayAPI, ApiGatewayAPIVersion
 	}
 	return nil, ""
 }
@@ -52,7 +63,7 @@ func (in *K8SClient) CreateIstioObject(api, namespace, resourceType, json string
 	byteJson := []byte(json)
 
 	var apiClient *rest.RESTClient
-	apiClient, typeMeta.APIVersion = in.getApiClientVersion(api)
+	apiClient, typeMeta.APIVersion = in.getApiClientVersion(api, resourceType)
 	if apiClient == nil {
 		return nil, fmt.Errorf("%s is not supported in CreateIstioObject operation", api)
 	}
@@ -74,7 +85,7 @@ func (in *K8SClient) CreateIstioObject(api, namespace, resourceType, json string
 func (in *K8SClient) DeleteIstioObject(api, namespace, resourceType, name string) error {
 	log.Debugf("DeleteIstioObject input: %s / %s / %s / %s", api, namespace, resourceType, name)
 	var err error
-	apiClient, _ := in.getApiClientVersion(api)
+	apiClient, _ := in.getApiClientVersion(api, resourceType)
 	if apiClient == nil {
 		return fmt.Errorf("%s is not supported in DeleteIstioObject operation", api)
 	}
@@ -95,7 +106,7 @@ func (in *K8SClient) UpdateIstioObject(api, namespace, resourceType, name, jsonP
 	typeMeta.Kind = PluralType[resourceType]
 	bytePatch := []byte(jsonPatch)
 	var apiClient *rest.RESTClient
-	apiClient, typeMeta.APIVersion = in.getApiClientVersion(api)
+	apiClient, typeMeta.APIVersion = in.getApiClientVersion(api, resourceType)
 	if apiClient == nil {
 		return nil, fmt.Errorf("%s is not supported in UpdateIstioObject operation", api)
 	}
@@ -111,12 +122,32 @@ func (in *K8SClient) UpdateIstioObject(api, namespace, resourceType, name, jsonP
 	return istioObject, err
 }
 
+// DryRunCreateIstioObject validates the creation of an Istio object in the API server without persisting it
+func (in *K8SClient) DryRunCreateIstioObject(api, namespace, resourceType, json string) error {
+	log.Debugf("DryRunCreateIstioObject input: %s / %s / %s", api, namespace, resourceType)
+	apiClient, _ := in.getApiClientVersion(api, resourceType)
+	if apiClient == nil {
+		return fmt.Errorf("%s is not supported in DryRunCreateIstioObject operation", api)
+	}
+	return apiClient.Post().Namespace(namespace).Resource(resourceType).Param("dryRun", meta_v1.DryRunAll).Body([]byte(json)).Do(in.ctx).Error()
+}
+
+// DryRunUpdateIstioObject validates the patch of an Istio object in the API server without persisting it
+func (in *K8SClient) DryRunUpdateIstioObject(api, namespace, resourceType, name, jsonPatch string) error {
+	log.Debugf("DryRunUpdateIstioObject input: %s / %s / %s / %s", api, namespace, resourceType, name)
+	apiClient, _ := in.getApiClientVersion(api, resourceType)
+	if apiClient == nil {
+		return fmt.Errorf("%s is not supported in DryRunUpdateIstioObject operation", api)
+	}
+	return apiClient.Patch(types.MergePatchType).Namespace(namespace).Resource(resourceType).Name(name).Param("dryRun", meta_v1.DryRunAll).Body([]byte(jsonPatch)).Do(in.ctx).Error()
+}
+
 func (in *K8SClient) GetIstioObjects(namespace, resourceType, labelSelector string) ([]IstioObject, error) {
 	var apiClient *rest.RESTClient
 	var apiGroup, apiVersion string
 	var ok bool
 	if apiGroup, ok = ResourceTypesToAPI[resourceType]; ok {
-		apiClient, apiVersion = in.getApiClientVersion(apiGroup)
+		apiClient, apiVersion = in.getApiClientVersion(apiGroup, resourceType)
 	} else {
 		return []IstioObject{}, fmt.Errorf("%s not found in ResourcesTypeToAPI", resourceType)
 	}
@@ -129,6 +160,14 @@ func (in *K8SClient) GetIstioObjects(namespace, resourceType, labelSelector stri
 		return []IstioObject{}, nil
 	}
 
+	if apiGroup == TelemetryGroupVersion.Group && !in.hasTelemetryResource(resourceType) {
+		return []IstioObject{}, nil
+	}
+
+	if apiGroup == GatewayAPIGroupVersion.Group && !in.hasGatewayAPIResource(resourceType) {
+		return []IstioObject{}, nil
+	}
+
 	var result runtime.Object
 	var err error
 	result, err = apiClient.Get().Namespace(namespace).Resource(resourceType).Param("labelSelector", labelSelector).Do(in.ctx).Get()
@@ -157,7 +196,7 @@ func (in *K8SClient) GetIstioObject(namespace, resourceType, name string) (Istio
 	var apiGroup, apiVersion string
 	var ok bool
 	if apiGroup, ok = ResourceTypesToAPI[resourceType]; ok {
-		apiClient, apiVersion = in.getApiClientVersion(apiGroup)
+		apiClient, apiVersion = in.getApiClientVersion(apiGroup, resourceType)
 	} else {
 		return nil, fmt.Errorf("%s not found in ResourcesTypeToAPI", resourceType)
 	}
@@ -309,7 +348,20 @@ func (in *K8SClient) GetConfigDump(namespace, podName string) (*ConfigDump, erro
 	return cd, err
 }
 
+// SetProxyLogLevel changes the level of all the loggers of the Envoy of the pod, until the pod is restarted
+func (in *K8SClient) SetProxyLogLevel(namespace, podName, level string) error {
+	_, err := in.envoyForward(namespace, podName, http.MethodPost, "/logging?level="+url.QueryEscape(level))
+	if err != nil {
+		log.Errorf("Error setting the log level of the proxy: %v", err)
+	}
+	return err
+}
+
 func (in *K8SClient) EnvoyForward(namespace, podName, path string) ([]byte, error) {
+	return in.envoyForward(namespace, podName, http.MethodGet, path)
+}
+
+func (in *K8SClient) envoyForward(namespace, podName, method, path string) ([]byte, error) {
 	writer := new(bytes.Buffer)
 
 	clientConfig, err := ConfigClient()
@@ -325,8 +377,13 @@ func (in *K8SClient) EnvoyForward(namespace, podName, path string) ([]byte, erro
 		return nil, err
 	}
 
-	// Building the port mapping local:target port
+	// Building the port mapping local:target port. Unless a local port is configured, it's picked by the system, so
+	// that the concurrent forwardings don't collide.
 	envoyLocalPort := config.Get().ExternalServices.Istio.EnvoyAdminLocalPort
+	if envoyLocalPort > 0 {
+		envoyLocalPortLock.Lock()
+		defer envoyLocalPortLock.Unlock()
+	}
 	portMap := fmt.Sprintf("%d:15000", envoyLocalPort)
 
 	// Create a Port Forwarder
@@ -344,16 +401,31 @@ func (in *K8SClient) EnvoyForward(namespace, podName, path string) ([]byte, erro
 	// Defering the finish of the port-forwarding
 	defer f.Stop()
 
+	envoyLocalPort, err = f.LocalPort()
+	if err != nil {
+		return nil, err
+	}
+
 	// Ready to create a request
-	resp, code, err := httputil.HttpGet(fmt.Sprintf("http://localhost:%d%s", envoyLocalPort, path), nil, 10*time.Second)
+	envoyURL := fmt.Sprintf("http://localhost:%d%s", envoyLocalPort, path)
+	var resp []byte
+	var code int
+	if method == http.MethodPost {
+		resp, code, err = httputil.HttpPost(envoyURL, nil, 10*time.Second)
+	} else {
+		resp, code, err = httputil.HttpGet(envoyURL, nil, 10*time.Second)
+	}
 	if code >= 400 {
-		return resp, fmt.Errorf("error fetching the /config_dump for the Envoy. Response code: %d", code)
+		return resp, fmt.Errorf("error requesting %s from the Envoy. Response code: %d", path, code)
 	}
 
 	return resp, err
 }
 
 func (in *K8SClient) hasNetworkingResource(resource string) bool {
+	if resource == ProxyConfigs {
+		return in.getNetworkingV1Beta1Resources()[resource]
+	}
 	return in.getNetworkingResources()[resource]
 }
 
@@ -378,6 +450,27 @@ func (in *K8SClient) getNetworkingResources() map[string]bool {
 	return *in.networkingResources
 }
 
+func (in *K8SClient) getNetworkingV1Beta1Resources() map[string]bool {
+	if in.networkingV1Beta1Resources != nil {
+		return *in.networkingV1Beta1Resources
+	}
+
+	networkingV1Beta1Resources := map[string]bool{}
+	path := fmt.Sprintf("/apis/%s", ApiNetworkingV1Beta1Version)
+	resourceListRaw, err := in.k8s.RESTClient().Get().AbsPath(path).Do(in.ctx).Raw()
+	if err == nil {
+		resourceList := meta_v1.APIResourceList{}
+		if errMarshall := json.Unmarshal(resourceListRaw, &resourceList); errMarshall == nil {
+			for _, resource := range resourceList.APIResources {
+				networkingV1Beta1Resources[resource.Name] = true
+			}
+		}
+	}
+	in.networkingV1Beta1Resources = &networkingV1Beta1Resources
+
+	return *in.networkingV1Beta1Resources
+}
+
 func (in *K8SClient) hasSecurityResource(resource string) bool {
 	return in.getSecurityResources()[resource]
 }
@@ -403,6 +496,56 @@ func (in *K8SClient) getSecurityResources() map[string]bool {
 	return *in.securityResources
 }
 
+func (in *K8SClient) hasTelemetryResource(resource string) bool {
+	return in.getTelemetryResources()[resource]
+}
+
+func (in *K8SClient) getTelemetryResources() map[string]bool {
+	if in.telemetryResources != nil {
+		return *in.telemetryResources
+	}
+
+	telemetryResources := map[string]bool{}
+	path := fmt.Sprintf("/apis/%s", ApiTelemetryVersion)
+	resourceListRaw, err := in.k8s.RESTClient().Get().AbsPath(path).Do(in.ctx).Raw()
+	if err == nil {
+		resourceList := meta_v1.APIResourceList{}
+		if errMarshall := json.Unmarshal(resourceListRaw, &resourceList); errMarshall == nil {
+			for _, resource := range resourceList.APIResources {
+				telemetryResources[resource.Name] = true
+			}
+		}
+	}
+	in.telemetryResources = &telemetryResources
+
+	return *in.telemetryResources
+}
+
+func (in *K8SClient) hasGatewayAPIResource(resource string) bool {
+	return in.getGatewayAPIResources()[resource]
+}
+
+func (in *K8SClient) getGatewayAPIResources() map[string]bool {
+	if in.gatewayAPIResources != nil {
+		return *in.gatewayAPIResources
+	}
+
+	gatewayAPIResources := map[string]bool{}
+	path := fmt.Sprintf("/apis/%s", ApiGatewayAPIVersion)
+	resourceListRaw, err := in.k8s.RESTClient().Get().AbsPath(path).Do(in.ctx).Raw()
+	if err == nil {
+		resourceList := meta_v1.APIResourceList{}
+		if errMarshall := json.Unmarshal(resourceListRaw, &resourceList); errMarshall == nil {
+			for _, resource := range resourceList.APIResources {
+				gatewayAPIResources[resource.Name] = true
+			}
+		}
+	}
+	in.gatewayAPIResources = &gatewayAPIResources
+
+	return *in.gatewayAPIResources
+}
+
 func GetIstioConfigMap(istioConfig *core_v1.ConfigMap) (*IstioMeshConfig, error) {
 	meshConfig := &IstioMeshConfig{}
 
@@ -545,6 +688,19 @@ func MatchPortNameWithValidProtocols(portName string) bool {
 	return false
 }
 
+// MatchPortAppProtocolWithValidProtocols returns true when the appProtocol of a port is a protocol known by Istio
+func MatchPortAppProtocolWithValidProtocols(appProtocol *string) bool {
+	if appProtocol == nil {
+		return false
+	}
+	for _, protocol := range portProtocols {
+		if strings.ToLower(*appProtocol) == protocol {
+			return true
+		}
+	}
+	return false
+}
+
 // GatewayNames extracts the gateway names for easier matching
 func GatewayNames(gateways [][]IstioObject) map[string]struct{} {
 	var empty struct{}
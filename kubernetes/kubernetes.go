@@ -4,6 +4,7 @@ import (
 	"bytes"
 	goerrors "errors"
 	"fmt"
+	"strings"
 
 	osapps_v1 "github.com/openshift/api/apps/v1"
 	osproject_v1 "github.com/openshift/api/project/v1"
@@ -14,8 +15,10 @@ import (
 	batch_v1 "k8s.io/api/batch/v1"
 	batch_v1beta1 "k8s.io/api/batch/v1beta1"
 	core_v1 "k8s.io/api/core/v1"
+	discovery_v1beta1 "k8s.io/api/discovery/v1beta1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
@@ -259,6 +262,27 @@ func (in *K8SClient) GetEndpoints(namespace, serviceName string) (*core_v1.Endpo
 	return in.k8s.CoreV1().Endpoints(namespace).Get(in.ctx, serviceName, emptyGetOptions)
 }
 
+// GetEndpointSlices returns the EndpointSlices of a specific service.
+// It returns an error on any problem.
+func (in *K8SClient) GetEndpointSlices(namespace, serviceName string) ([]discovery_v1beta1.EndpointSlice, error) {
+	selector := labels.Set{discovery_v1beta1.LabelServiceName: serviceName}.String()
+	if sliceList, err := in.k8s.DiscoveryV1beta1().EndpointSlices(namespace).List(in.ctx, meta_v1.ListOptions{LabelSelector: selector}); err == nil {
+		return sliceList.Items, nil
+	} else {
+		return []discovery_v1beta1.EndpointSlice{}, err
+	}
+}
+
+// GetNode returns the node definition for a given node name.
+// It returns an error on any problem.
+func (in *K8SClient) GetNode(name string) (*core_v1.Node, error) {
+	if node, err := in.k8s.CoreV1().Nodes().Get(in.ctx, name, emptyGetOptions); err != nil {
+		return nil, err
+	} else {
+		return node, nil
+	}
+}
+
 // GetPods returns the pods definitions for a given set of labels.
 // An empty labelSelector will fetch all pods found per a namespace.
 // It returns an error on any problem.
@@ -322,6 +346,19 @@ func (in *K8SClient) GetCronJobs(namespace string) ([]batch_v1beta1.CronJob, err
 	}
 }
 
+// GetCustomResources returns the custom resources of the namespace, of any group, version and resource (plural name of a kind)
+func (in *K8SClient) GetCustomResources(namespace, group, version, resource string) ([]unstructured.Unstructured, error) {
+	raw, err := in.k8s.RESTClient().Get().AbsPath("/apis", group, version, "namespaces", namespace, resource).Do(in.ctx).Raw()
+	if err != nil {
+		return []unstructured.Unstructured{}, err
+	}
+	list := unstructured.UnstructuredList{}
+	if err := list.UnmarshalJSON(raw); err != nil {
+		return []unstructured.Unstructured{}, err
+	}
+	return list.Items, nil
+}
+
 func (in *K8SClient) GetJobs(namespace string) ([]batch_v1.Job, error) {
 	if jList, err := in.k8s.BatchV1().Jobs(namespace).List(in.ctx, emptyListOptions); err == nil {
 		return jList.Items, nil
@@ -336,8 +373,13 @@ func NewNotFound(name, group, resource string) error {
 	return errors.NewNotFound(schema.GroupResource{Group: group, Resource: resource}, name)
 }
 
-// GetSelfSubjectAccessReview provides information on Kiali permissions
+// GetSelfSubjectAccessReview provides information on Kiali permissions. The resourceType can name a subresource,
+// i.e. "pods/portforward".
 func (in *K8SClient) GetSelfSubjectAccessReview(namespace, api, resourceType string, verbs []string) ([]*auth_v1.SelfSubjectAccessReview, error) {
+	resource, subresource := resourceType, ""
+	if i := strings.Index(resourceType, "/"); i >= 0 {
+		resource, subresource = resourceType[:i], resourceType[i+1:]
+	}
 	calls := len(verbs)
 	ch := make(chan *auth_v1.SelfSubjectAccessReview, calls)
 	errChan := make(chan error)
@@ -346,10 +388,11 @@ func (in *K8SClient) GetSelfSubjectAccessReview(namespace, api, resourceType str
 			res, err := in.k8s.AuthorizationV1().SelfSubjectAccessReviews().Create(in.ctx, &auth_v1.SelfSubjectAccessReview{
 				Spec: auth_v1.SelfSubjectAccessReviewSpec{
 					ResourceAttributes: &auth_v1.ResourceAttributes{
-						Namespace: namespace,
-						Verb:      verb,
-						Group:     api,
-						Resource:  resourceType,
+						Namespace:   namespace,
+						Verb:        verb,
+						Group:       api,
+						Resource:    resource,
+						Subresource: subresource,
 					},
 				},
 			}, meta_v1.CreateOptions{})
@@ -375,35 +418,46 @@ func (in *K8SClient) GetSelfSubjectAccessReview(namespace, api, resourceType str
 }
 
 func (in *K8SClient) UpdateWorkload(namespace string, workloadName string, workloadType string, jsonPatch string) error {
+	return in.PatchWorkload(namespace, workloadName, workloadType, jsonPatch, types.MergePatchType)
+}
+
+// PatchWorkload applies a patch of the given type (i.e. merge or strategic merge) to a workload
+func (in *K8SClient) PatchWorkload(namespace string, workloadName string, workloadType string, patch string, patchType types.PatchType) error {
 	emptyPatchOptions := meta_v1.PatchOptions{}
-	bytePatch := []byte(jsonPatch)
+	bytePatch := []byte(patch)
 	var err error
 	switch workloadType {
 	case DeploymentType:
-		_, err = in.k8s.AppsV1().Deployments(namespace).Patch(in.ctx, workloadName, types.MergePatchType, bytePatch, emptyPatchOptions)
+		_, err = in.k8s.AppsV1().Deployments(namespace).Patch(in.ctx, workloadName, patchType, bytePatch, emptyPatchOptions)
 	case ReplicaSetType:
-		_, err = in.k8s.AppsV1().ReplicaSets(namespace).Patch(in.ctx, workloadName, types.MergePatchType, bytePatch, emptyPatchOptions)
+		_, err = in.k8s.AppsV1().ReplicaSets(namespace).Patch(in.ctx, workloadName, patchType, bytePatch, emptyPatchOptions)
 	case ReplicationControllerType:
-		_, err = in.k8s.CoreV1().ReplicationControllers(namespace).Patch(in.ctx, workloadName, types.MergePatchType, bytePatch, emptyPatchOptions)
+		_, err = in.k8s.CoreV1().ReplicationControllers(namespace).Patch(in.ctx, workloadName, patchType, bytePatch, emptyPatchOptions)
 	case DeploymentConfigType:
 		if in.IsOpenShift() {
 			result := &osapps_v1.DeploymentConfigList{}
-			err = in.k8s.RESTClient().Patch(types.MergePatchType).Prefix("apis", "apps.openshift.io", "v1").Namespace(namespace).Resource("deploymentconfigs").SubResource(workloadName).Body(bytePatch).Do(in.ctx).Into(result)
+			err = in.k8s.RESTClient().Patch(patchType).Prefix("apis", "apps.openshift.io", "v1").Namespace(namespace).Resource("deploymentconfigs").SubResource(workloadName).Body(bytePatch).Do(in.ctx).Into(result)
 		}
 	case StatefulSetType:
-		_, err = in.k8s.AppsV1().StatefulSets(namespace).Patch(in.ctx, workloadName, types.MergePatchType, bytePatch, emptyPatchOptions)
+		_, err = in.k8s.AppsV1().StatefulSets(namespace).Patch(in.ctx, workloadName, patchType, bytePatch, emptyPatchOptions)
 	case JobType:
-		_, err = in.k8s.BatchV1().Jobs(namespace).Patch(in.ctx, workloadName, types.MergePatchType, bytePatch, emptyPatchOptions)
+		_, err = in.k8s.BatchV1().Jobs(namespace).Patch(in.ctx, workloadName, patchType, bytePatch, emptyPatchOptions)
 	case CronJobType:
-		_, err = in.k8s.BatchV1beta1().CronJobs(namespace).Patch(in.ctx, workloadName, types.MergePatchType, bytePatch, emptyPatchOptions)
+		_, err = in.k8s.BatchV1beta1().CronJobs(namespace).Patch(in.ctx, workloadName, patchType, bytePatch, emptyPatchOptions)
 	case PodType:
-		_, err = in.k8s.CoreV1().Pods(namespace).Patch(in.ctx, workloadName, types.MergePatchType, bytePatch, emptyPatchOptions)
+		_, err = in.k8s.CoreV1().Pods(namespace).Patch(in.ctx, workloadName, patchType, bytePatch, emptyPatchOptions)
 	default:
 		err = fmt.Errorf("Workload type %s not found", workloadType)
 	}
 	return err
 }
 
+// CreateEvent records an Event on an object of the namespace
+func (in *K8SClient) CreateEvent(namespace string, event *core_v1.Event) error {
+	_, err := in.k8s.CoreV1().Events(namespace).Create(in.ctx, event, meta_v1.CreateOptions{})
+	return err
+}
+
 func (in *K8SClient) UpdateService(namespace string, serviceName string, jsonPatch string) error {
 	emptyPatchOptions := meta_v1.PatchOptions{}
 	bytePatch := []byte(jsonPatch)
@@ -14,6 +14,16 @@ func (o *K8SClientMock) DeleteIstioObject(api, namespace, objectType, objectName
 	return args.Error(0)
 }
 
+func (o *K8SClientMock) DryRunCreateIstioObject(api, namespace, resourceType, json string) error {
+	args := o.Called(api, namespace, resourceType, json)
+	return args.Error(0)
+}
+
+func (o *K8SClientMock) DryRunUpdateIstioObject(api, namespace, resourceType, name, jsonPatch string) error {
+	args := o.Called(api, namespace, resourceType, name, jsonPatch)
+	return args.Error(0)
+}
+
 func (o *K8SClientMock) GetIstioObject(namespace string, resourceType string, object string) (kubernetes.IstioObject, error) {
 	args := o.Called(namespace, resourceType, object)
 	return args.Get(0).(kubernetes.IstioObject), args.Error(1)
@@ -38,3 +48,8 @@ func (o *K8SClientMock) GetConfigDump(namespace string, podName string) (*kubern
 	args := o.Called(namespace, podName)
 	return args.Get(0).(*kubernetes.ConfigDump), args.Error(1)
 }
+
+func (o *K8SClientMock) SetProxyLogLevel(namespace, podName, level string) error {
+	args := o.Called(namespace, podName, level)
+	return args.Error(0)
+}
@@ -6,10 +6,18 @@ import (
 	batch_v1 "k8s.io/api/batch/v1"
 	batch_apps_v1 "k8s.io/api/batch/v1beta1"
 	core_v1 "k8s.io/api/core/v1"
+	discovery_v1beta1 "k8s.io/api/discovery/v1beta1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
 
 	"github.com/kiali/kiali/kubernetes"
 )
 
+func (o *K8SClientMock) CreateEvent(namespace string, event *core_v1.Event) error {
+	args := o.Called(namespace, event)
+	return args.Error(0)
+}
+
 func (o *K8SClientMock) GetConfigMap(namespace, configName string) (*core_v1.ConfigMap, error) {
 	args := o.Called(namespace, configName)
 	return args.Get(0).(*core_v1.ConfigMap), args.Error(1)
@@ -20,6 +28,11 @@ func (o *K8SClientMock) GetCronJobs(namespace string) ([]batch_apps_v1.CronJob,
 	return args.Get(0).([]batch_apps_v1.CronJob), args.Error(1)
 }
 
+func (o *K8SClientMock) GetCustomResources(namespace, group, version, resource string) ([]unstructured.Unstructured, error) {
+	args := o.Called(namespace, group, version, resource)
+	return args.Get(0).([]unstructured.Unstructured), args.Error(1)
+}
+
 func (o *K8SClientMock) GetDeployment(namespace string, deploymentName string) (*apps_v1.Deployment, error) {
 	args := o.Called(namespace, deploymentName)
 	return args.Get(0).(*apps_v1.Deployment), args.Error(1)
@@ -40,6 +53,11 @@ func (o *K8SClientMock) GetEndpoints(namespace string, serviceName string) (*cor
 	return args.Get(0).(*core_v1.Endpoints), args.Error(1)
 }
 
+func (o *K8SClientMock) GetEndpointSlices(namespace string, serviceName string) ([]discovery_v1beta1.EndpointSlice, error) {
+	args := o.Called(namespace, serviceName)
+	return args.Get(0).([]discovery_v1beta1.EndpointSlice), args.Error(1)
+}
+
 func (o *K8SClientMock) GetJobs(namespace string) ([]batch_v1.Job, error) {
 	args := o.Called(namespace)
 	return args.Get(0).([]batch_v1.Job), args.Error(1)
@@ -55,6 +73,11 @@ func (o *K8SClientMock) GetNamespaces(labelSelector string) ([]core_v1.Namespace
 	return args.Get(0).([]core_v1.Namespace), args.Error(1)
 }
 
+func (o *K8SClientMock) GetNode(name string) (*core_v1.Node, error) {
+	args := o.Called(name)
+	return args.Get(0).(*core_v1.Node), args.Error(1)
+}
+
 func (o *K8SClientMock) GetPods(namespace, labelSelector string) ([]core_v1.Pod, error) {
 	args := o.Called(namespace, labelSelector)
 	return args.Get(0).([]core_v1.Pod), args.Error(1)
@@ -85,6 +108,11 @@ func (o *K8SClientMock) GetReplicaSets(namespace string) ([]apps_v1.ReplicaSet,
 	return args.Get(0).([]apps_v1.ReplicaSet), args.Error(1)
 }
 
+func (o *K8SClientMock) GetSecret(namespace, name string) (*core_v1.Secret, error) {
+	args := o.Called(namespace, name)
+	return args.Get(0).(*core_v1.Secret), args.Error(1)
+}
+
 func (o *K8SClientMock) GetSecrets(namespace string, labelSelector string) ([]core_v1.Secret, error) {
 	args := o.Called(namespace, labelSelector)
 	return args.Get(0).([]core_v1.Secret), args.Error(1)
@@ -130,6 +158,11 @@ func (o *K8SClientMock) UpdateWorkload(namespace string, workloadName string, wo
 	return args.Error(1)
 }
 
+func (o *K8SClientMock) PatchWorkload(namespace string, workloadName string, workloadType string, patch string, patchType types.PatchType) error {
+	args := o.Called(namespace, workloadName, workloadType, patch, patchType)
+	return args.Error(0)
+}
+
 func (o *K8SClientMock) UpdateService(namespace string, serviceName string, jsonPatch string) error {
 	args := o.Called(namespace, serviceName, jsonPatch)
 	return args.Error(1)
@@ -52,6 +52,15 @@ func GetRemoteSecret(path string) (*RemoteSecret, error) {
 	return ParseRemoteSecretBytes(secretFile)
 }
 
+// GetSecret returns the secret of a given namespace and name
+func (in *K8SClient) GetSecret(namespace, name string) (*core_v1.Secret, error) {
+	secret, err := in.k8s.CoreV1().Secrets(namespace).Get(in.ctx, name, emptyGetOptions)
+	if err != nil {
+		return &core_v1.Secret{}, err
+	}
+	return secret, nil
+}
+
 // GetSecrets returns a list of secrets for a given namespace.
 // If selectorLabels is defined, the list will only contain services matching
 // the specified label selector.
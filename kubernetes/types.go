@@ -13,10 +13,13 @@ const (
 	// Kubernetes Controllers
 	ConfigMapType             = "ConfigMap"
 	CronJobType               = "CronJob"
+	DaemonSetType             = "DaemonSet"
 	DeploymentType            = "Deployment"
 	DeploymentConfigType      = "DeploymentConfig"
 	EndpointsType             = "Endpoints"
+	EndpointSliceType         = "EndpointSlice"
 	JobType                   = "Job"
+	NodeType                  = "Node"
 	PodType                   = "Pod"
 	ReplicationControllerType = "ReplicationController"
 	ReplicaSetType            = "ReplicaSet"
@@ -37,6 +40,10 @@ const (
 	EnvoyFilterType     = "EnvoyFilter"
 	EnvoyFilterTypeList = "EnvoyFilterList"
 
+	ProxyConfigs        = "proxyconfigs"
+	ProxyConfigType     = "ProxyConfig"
+	ProxyConfigTypeList = "ProxyConfigList"
+
 	Sidecars        = "sidecars"
 	SidecarType     = "Sidecar"
 	SidecarTypeList = "SidecarList"
@@ -68,6 +75,19 @@ const (
 	RequestAuthenticationsType     = "RequestAuthentication"
 	RequestAuthenticationsTypeList = "RequestAuthenticationList"
 
+	// Telemetries
+	Telemetries       = "telemetries"
+	TelemetryType     = "Telemetry"
+	TelemetryTypeList = "TelemetryList"
+
+	// Gateway API types
+	HTTPRoutes               = "httproutes"
+	HTTPRouteType            = "HTTPRoute"
+	HTTPRouteTypeList        = "HTTPRouteList"
+	BackendTLSPolicies       = "backendtlspolicies"
+	BackendTLSPolicyType     = "BackendTLSPolicy"
+	BackendTLSPolicyTypeList = "BackendTLSPolicyList"
+
 	// Iter8 types
 
 	Iter8Experiments        = "experiments"
@@ -83,12 +103,32 @@ var (
 	}
 	ApiNetworkingVersion = NetworkingGroupVersion.Group + "/" + NetworkingGroupVersion.Version
 
+	// Some networking resources, like ProxyConfig, are only served by the v1beta1 version of the API
+	NetworkingV1Beta1GroupVersion = schema.GroupVersion{
+		Group:   "networking.istio.io",
+		Version: "v1beta1",
+	}
+	ApiNetworkingV1Beta1Version = NetworkingV1Beta1GroupVersion.Group + "/" + NetworkingV1Beta1GroupVersion.Version
+
 	SecurityGroupVersion = schema.GroupVersion{
 		Group:   "security.istio.io",
 		Version: "v1beta1",
 	}
 	ApiSecurityVersion = SecurityGroupVersion.Group + "/" + SecurityGroupVersion.Version
 
+	TelemetryGroupVersion = schema.GroupVersion{
+		Group:   "telemetry.istio.io",
+		Version: "v1alpha1",
+	}
+	ApiTelemetryVersion = TelemetryGroupVersion.Group + "/" + TelemetryGroupVersion.Version
+
+	// Kubernetes Gateway API, whose resources are read as Istio objects
+	GatewayAPIGroupVersion = schema.GroupVersion{
+		Group:   "gateway.networking.k8s.io",
+		Version: "v1alpha2",
+	}
+	ApiGatewayAPIVersion = GatewayAPIGroupVersion.Group + "/" + GatewayAPIGroupVersion.Version
+
 	// We will add a new extesion API in a similar way as we added the Kubernetes + Istio APIs
 	Iter8GroupVersion = schema.GroupVersion{
 		Group:   "iter8.tools",
@@ -130,6 +170,16 @@ var (
 		},
 	}
 
+	networkingV1Beta1Types = []struct {
+		objectKind     string
+		collectionKind string
+	}{
+		{
+			objectKind:     ProxyConfigType,
+			collectionKind: ProxyConfigTypeList,
+		},
+	}
+
 	securityTypes = []struct {
 		objectKind     string
 		collectionKind string
@@ -148,6 +198,30 @@ var (
 		},
 	}
 
+	telemetryTypes = []struct {
+		objectKind     string
+		collectionKind string
+	}{
+		{
+			objectKind:     TelemetryType,
+			collectionKind: TelemetryTypeList,
+		},
+	}
+
+	gatewayAPITypes = []struct {
+		objectKind     string
+		collectionKind string
+	}{
+		{
+			objectKind:     HTTPRouteType,
+			collectionKind: HTTPRouteTypeList,
+		},
+		{
+			objectKind:     BackendTLSPolicyType,
+			collectionKind: BackendTLSPolicyTypeList,
+		},
+	}
+
 	iter8Types = []struct {
 		objectKind     string
 		collectionKind string
@@ -168,12 +242,20 @@ var (
 		Sidecars:         SidecarType,
 		WorkloadEntries:  WorkloadEntryType,
 		EnvoyFilters:     EnvoyFilterType,
+		ProxyConfigs:     ProxyConfigType,
 
 		// Security
 		AuthorizationPolicies:  AuthorizationPoliciesType,
 		PeerAuthentications:    PeerAuthenticationsType,
 		RequestAuthentications: RequestAuthenticationsType,
 
+		// Telemetry
+		Telemetries: TelemetryType,
+
+		// Gateway API
+		HTTPRoutes:         HTTPRouteType,
+		BackendTLSPolicies: BackendTLSPolicyType,
+
 		// Iter8
 		Iter8Experiments: Iter8ExperimentType,
 	}
@@ -186,9 +268,13 @@ var (
 		Sidecars:               NetworkingGroupVersion.Group,
 		WorkloadEntries:        NetworkingGroupVersion.Group,
 		EnvoyFilters:           NetworkingGroupVersion.Group,
+		ProxyConfigs:           NetworkingGroupVersion.Group,
 		AuthorizationPolicies:  SecurityGroupVersion.Group,
 		PeerAuthentications:    SecurityGroupVersion.Group,
 		RequestAuthentications: SecurityGroupVersion.Group,
+		Telemetries:            TelemetryGroupVersion.Group,
+		HTTPRoutes:             GatewayAPIGroupVersion.Group,
+		BackendTLSPolicies:     GatewayAPIGroupVersion.Group,
 		// Extensions
 		Iter8Experiments: Iter8GroupVersion.Group,
 	}
@@ -196,6 +282,8 @@ var (
 	ApiToVersion = map[string]string{
 		NetworkingGroupVersion.Group: ApiNetworkingVersion,
 		SecurityGroupVersion.Group:   ApiSecurityVersion,
+		TelemetryGroupVersion.Group:  ApiTelemetryVersion,
+		GatewayAPIGroupVersion.Group: ApiGatewayAPIVersion,
 	}
 )
 
@@ -254,6 +342,10 @@ type IstioDetails struct {
 	Gateways               []IstioObject `json:"gateways"`
 	Sidecars               []IstioObject `json:"sidecars"`
 	RequestAuthentications []IstioObject `json:"requestauthentications"`
+	ProxyConfigs           []IstioObject `json:"proxyconfigs"`
+	EnvoyFilters           []IstioObject `json:"envoyfilters"`
+	MeshEnvoyFilters       []IstioObject `json:"meshenvoyfilters"`
+	Telemetries            []IstioObject `json:"telemetries"`
 }
 
 // MTLSDetails is a wrapper to group all Istio objects related to non-local mTLS configurations
@@ -266,7 +358,8 @@ type MTLSDetails struct {
 
 // RBACDetails is a wrapper for objects related to Istio RBAC (Role Based Access Control)
 type RBACDetails struct {
-	AuthorizationPolicies []IstioObject `json:"authorizationpolicies"`
+	AuthorizationPolicies     []IstioObject `json:"authorizationpolicies"`
+	MeshAuthorizationPolicies []IstioObject `json:"meshauthorizationpolicies"`
 }
 
 // GenericIstioObject is a type to test Istio types defined by Istio as a Kubernetes extension.
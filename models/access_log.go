@@ -0,0 +1,75 @@
+package models
+
+import (
+	"regexp"
+)
+
+// DefaultAccessLogPattern matches the default Istio proxy access log format.
+// Both the Istio 1.8 format (with response code details and connection termination details)
+// and the previous format (with the mixer status field) are supported.
+const DefaultAccessLogPattern = `^\[(?P<timestamp>[^\]]+)\] "(?P<method>\S+) (?P<path>\S+) (?P<protocol>[^"]+)" ` +
+	`(?P<response_code>\d+) (?P<response_flags>\S+)(?: (?P<response_code_details>[^\s"]+) (?P<connection_termination_details>[^\s"]+))?(?: "[^"]*")? ` +
+	`"(?P<upstream_failure_reason>[^"]*)" (?P<bytes_received>\d+) (?P<bytes_sent>\d+) (?P<duration>\d+) (?P<upstream_service_time>\S+) ` +
+	`"(?P<forwarded_for>[^"]*)" "(?P<user_agent>[^"]*)" "(?P<request_id>[^"]*)" "(?P<authority>[^"]*)" "(?P<upstream_host>[^"]*)" ` +
+	`(?P<upstream_cluster>\S+)`
+
+// AccessLog holds the fields of a single proxy access log entry.
+// TraceID is only set when the pattern provides it or after correlating the entry with the traces.
+type AccessLog struct {
+	Authority             string `json:"authority,omitempty"`
+	BytesReceived         string `json:"bytesReceived,omitempty"`
+	BytesSent             string `json:"bytesSent,omitempty"`
+	Duration              string `json:"duration,omitempty"`
+	ForwardedFor          string `json:"forwardedFor,omitempty"`
+	Method                string `json:"method,omitempty"`
+	Path                  string `json:"path,omitempty"`
+	Protocol              string `json:"protocol,omitempty"`
+	RequestID             string `json:"requestId,omitempty"`
+	ResponseCode          string `json:"responseCode,omitempty"`
+	ResponseFlags         string `json:"responseFlags,omitempty"`
+	Timestamp             string `json:"timestamp,omitempty"`
+	TraceID               string `json:"traceId,omitempty"`
+	UpstreamCluster       string `json:"upstreamCluster,omitempty"`
+	UpstreamFailureReason string `json:"upstreamFailureReason,omitempty"`
+	UpstreamHost          string `json:"upstreamHost,omitempty"`
+	UpstreamServiceTime   string `json:"upstreamServiceTime,omitempty"`
+	UserAgent             string `json:"userAgent,omitempty"`
+}
+
+// ParseAccessLog parses an access log line using the named groups of the pattern.
+// Unknown groups are ignored, it returns nil when the line doesn't match the pattern.
+func ParseAccessLog(line string, pattern *regexp.Regexp) *AccessLog {
+	match := pattern.FindStringSubmatch(line)
+	if match == nil {
+		return nil
+	}
+
+	al := AccessLog{}
+	fields := map[string]*string{
+		"authority":               &al.Authority,
+		"bytes_received":          &al.BytesReceived,
+		"bytes_sent":              &al.BytesSent,
+		"duration":                &al.Duration,
+		"forwarded_for":           &al.ForwardedFor,
+		"method":                  &al.Method,
+		"path":                    &al.Path,
+		"protocol":                &al.Protocol,
+		"request_id":              &al.RequestID,
+		"response_code":           &al.ResponseCode,
+		"response_flags":          &al.ResponseFlags,
+		"timestamp":               &al.Timestamp,
+		"trace_id":                &al.TraceID,
+		"upstream_cluster":        &al.UpstreamCluster,
+		"upstream_failure_reason": &al.UpstreamFailureReason,
+		"upstream_host":           &al.UpstreamHost,
+		"upstream_service_time":   &al.UpstreamServiceTime,
+		"user_agent":              &al.UserAgent,
+	}
+	for i, name := range pattern.SubexpNames() {
+		if field, ok := fields[name]; ok && match[i] != "-" {
+			*field = match[i]
+		}
+	}
+
+	return &al
+}
@@ -0,0 +1,81 @@
+package models
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseDefaultAccessLog(t *testing.T) {
+	assert := assert.New(t)
+
+	line := `[2020-11-25T21:26:18.409Z] "GET /status/418 HTTP/1.1" 418 - via_upstream - "-" 0 135 3 1 "-" "curl/7.73.0-DEV" "84961386-6d84-929d-98bd-c5aee93b5c88" "httpbin:8000" "127.0.0.1:80" inbound|8000|| 127.0.0.1:41854 10.44.1.27:80 10.44.1.23:37652 outbound_.8000_._.httpbin.foo.svc.cluster.local default`
+	al := ParseAccessLog(line, regexp.MustCompile(DefaultAccessLogPattern))
+
+	assert.NotNil(al)
+	assert.Equal("2020-11-25T21:26:18.409Z", al.Timestamp)
+	assert.Equal("GET", al.Method)
+	assert.Equal("/status/418", al.Path)
+	assert.Equal("HTTP/1.1", al.Protocol)
+	assert.Equal("418", al.ResponseCode)
+	assert.Equal("", al.ResponseFlags)
+	assert.Equal("0", al.BytesReceived)
+	assert.Equal("135", al.BytesSent)
+	assert.Equal("3", al.Duration)
+	assert.Equal("1", al.UpstreamServiceTime)
+	assert.Equal("", al.ForwardedFor)
+	assert.Equal("curl/7.73.0-DEV", al.UserAgent)
+	assert.Equal("84961386-6d84-929d-98bd-c5aee93b5c88", al.RequestID)
+	assert.Equal("httpbin:8000", al.Authority)
+	assert.Equal("127.0.0.1:80", al.UpstreamHost)
+	assert.Equal("inbound|8000||", al.UpstreamCluster)
+	assert.Equal("", al.TraceID)
+}
+
+func TestParsePreviousDefaultAccessLog(t *testing.T) {
+	assert := assert.New(t)
+
+	line := `[2020-08-05T10:03:43.512Z] "GET /reviews/0 HTTP/1.1" 200 - "-" "-" 0 295 6 5 "-" "python-requests/2.24.0" "2a2c0d0a-0c0d-9c7e-a4e5-7ec9c5c1bb2e" "reviews:9080" "10.244.0.12:9080" outbound|9080||reviews.bookinfo.svc.cluster.local 10.244.0.9:51620 10.101.35.119:9080 10.244.0.9:39990 - default`
+	al := ParseAccessLog(line, regexp.MustCompile(DefaultAccessLogPattern))
+
+	assert.NotNil(al)
+	assert.Equal("200", al.ResponseCode)
+	assert.Equal("295", al.BytesSent)
+	assert.Equal("2a2c0d0a-0c0d-9c7e-a4e5-7ec9c5c1bb2e", al.RequestID)
+	assert.Equal("reviews:9080", al.Authority)
+	assert.Equal("outbound|9080||reviews.bookinfo.svc.cluster.local", al.UpstreamCluster)
+}
+
+func TestParseTcpAccessLog(t *testing.T) {
+	assert := assert.New(t)
+
+	line := `[2020-11-25T21:30:01.120Z] "- - -" 0 UH - - "-" 0 0 0 - "-" "-" "-" "-" "-" BlackHoleCluster - 10.96.0.1:443 10.44.1.23:49318 - -`
+	al := ParseAccessLog(line, regexp.MustCompile(DefaultAccessLogPattern))
+
+	assert.NotNil(al)
+	assert.Equal("", al.Method)
+	assert.Equal("0", al.ResponseCode)
+	assert.Equal("UH", al.ResponseFlags)
+	assert.Equal("", al.RequestID)
+	assert.Equal("BlackHoleCluster", al.UpstreamCluster)
+}
+
+func TestParseNonAccessLog(t *testing.T) {
+	line := `2020-11-25T21:26:18.409Z	info	Envoy proxy is ready`
+	assert.Nil(t, ParseAccessLog(line, regexp.MustCompile(DefaultAccessLogPattern)))
+}
+
+func TestParseCustomAccessLog(t *testing.T) {
+	assert := assert.New(t)
+
+	pattern := regexp.MustCompile(`^(?P<timestamp>\S+) (?P<method>\S+) (?P<path>\S+) (?P<response_code>\d+) trace=(?P<trace_id>\S+) request=(?P<request_id>\S+)`)
+	al := ParseAccessLog("2020-11-25T21:26:18Z GET /productpage 200 trace=3c1a8b2e6fd0a4b1 request=84961386", pattern)
+
+	assert.NotNil(al)
+	assert.Equal("GET", al.Method)
+	assert.Equal("/productpage", al.Path)
+	assert.Equal("200", al.ResponseCode)
+	assert.Equal("3c1a8b2e6fd0a4b1", al.TraceID)
+	assert.Equal("84961386", al.RequestID)
+}
@@ -0,0 +1,66 @@
+package models
+
+// The sources of the access logging config of a workload
+const (
+	AccessLoggingFromTelemetry        = "telemetry"
+	AccessLoggingFromDefaultProviders = "defaultProviders"
+	AccessLoggingFromAccessLogFile    = "accessLogFile"
+	AccessLoggingFromNone             = "none"
+)
+
+// AccessLogging is the effective access logging of the proxy of a workload, resolved from the mesh config and the
+// Telemetry resources applied to the workload
+// swagger:model accessLogging
+type AccessLogging struct {
+	// required: true
+	Namespace string `json:"namespace"`
+
+	// required: true
+	Workload string `json:"workload"`
+
+	// Whether the proxy of the workload writes access logs
+	// required: true
+	Enabled bool `json:"enabled"`
+
+	// Where the effective config comes from: telemetry, defaultProviders, accessLogFile or none
+	// required: true
+	// example: telemetry
+	Source string `json:"source"`
+
+	// The Telemetry setting the access logging, as namespace/name, when the source is a Telemetry
+	// example: bookinfo/access-logs
+	Telemetry string `json:"telemetry,omitempty"`
+
+	// The providers the access logs are written to
+	// required: true
+	Providers []AccessLogProvider `json:"providers"`
+
+	// The disagreements between the mesh config and the Telemetry resources, or with the extensionProviders
+	// required: true
+	Conflicts []string `json:"conflicts"`
+}
+
+// AccessLogProvider is a destination of the access logs
+type AccessLogProvider struct {
+	// The name of the provider: an extensionProvider of the mesh config, envoy for the built-in one, or
+	// accessLogFile for the legacy config of the mesh
+	// required: true
+	// example: envoy
+	Name string `json:"name"`
+
+	// The type of provider: file, otel, grpc or unknown when not defined in the mesh config
+	// required: true
+	// example: file
+	Type string `json:"type"`
+
+	// The file the logs are written to, for the file providers
+	// example: /dev/stdout
+	Path string `json:"path,omitempty"`
+
+	// The encoding of the logs, for the file providers: TEXT or JSON
+	// example: TEXT
+	Encoding string `json:"encoding,omitempty"`
+
+	// The custom format of the logs, the Envoy default format is used when empty
+	Format string `json:"format,omitempty"`
+}
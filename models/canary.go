@@ -0,0 +1,98 @@
+package models
+
+const (
+	// FlaggerTool identifies rollouts managed by Flagger: the canary target Deployment "<name>" is paired
+	// with a "<name>-primary" Deployment, created by Flagger, serving the stable version
+	FlaggerTool = "flagger"
+	// ArgoRolloutsTool identifies rollouts managed by Argo Rollouts: a Rollout owns one ReplicaSet per revision
+	ArgoRolloutsTool = "argo-rollouts"
+
+	CanaryRole = "canary"
+	StableRole = "stable"
+)
+
+// CanaryRollouts is a list of progressive delivery rollouts detected in a namespace
+// This type is used for returning an array of CanaryRollout
+type CanaryRollouts []CanaryRollout
+
+// CanaryRollout pairs the canary and the stable variants of a workload under progressive delivery
+type CanaryRollout struct {
+	// Name of the rollout: the Flagger target Deployment or the Argo Rollout
+	// required: true
+	Name string `json:"name"`
+	// Namespace of the rollout
+	// required: true
+	Namespace string `json:"namespace"`
+	// Progressive delivery tool managing the rollout
+	// required: true
+	// example: flagger
+	Tool string `json:"tool"`
+	// App label of the rolled out pods
+	App string `json:"app,omitempty"`
+	// Canary variant, nil when no canary is in progress
+	Canary *CanaryVariant `json:"canary"`
+	// Stable variant
+	Stable *CanaryVariant `json:"stable"`
+}
+
+// CanaryVariant is one side (canary or stable) of a rollout
+type CanaryVariant struct {
+	// Role of the variant, canary or stable
+	// required: true
+	Role string `json:"role"`
+	// Name of the workload as reported by the Istio telemetry
+	// required: true
+	Workload string `json:"workload"`
+	// Revision of the variant, when the tool provides one
+	Revision string `json:"revision,omitempty"`
+	// Number of desired replicas
+	DesiredReplicas int32 `json:"desiredReplicas"`
+	// Number of available replicas
+	AvailableReplicas int32 `json:"availableReplicas"`
+}
+
+// CanaryMetrics holds the inbound success rate and latencies of the canary and the stable variants of a rollout
+type CanaryMetrics struct {
+	Rollout CanaryRollout   `json:"rollout"`
+	Canary  *VariantMetrics `json:"canary"`
+	Stable  *VariantMetrics `json:"stable"`
+}
+
+// VariantMetrics holds the inbound metrics of a single variant
+type VariantMetrics struct {
+	// Inbound requests per second
+	RequestRate float64 `json:"requestRate"`
+	// Ratio of non-error requests, from 0 to 1. Nil when there is no traffic.
+	SuccessRate *float64 `json:"successRate"`
+	// Request duration (ms) quantiles and average
+	Latencies []Stat `json:"latencies"`
+}
+
+// RequestsSuccessRate returns the total rate of the requests (rates by protocol and code) and the ratio of
+// non-error requests. Errors are responses without code ("-"), 5xx http codes and non-zero grpc codes.
+func RequestsSuccessRate(requests map[string]map[string]float64) (float64, *float64) {
+	total, errors := 0.0, 0.0
+	for protocol, codes := range requests {
+		for code, rate := range codes {
+			total += rate
+			if isErrorCode(protocol, code) {
+				errors += rate
+			}
+		}
+	}
+	if total == 0 {
+		return 0, nil
+	}
+	success := (total - errors) / total
+	return total, &success
+}
+
+func isErrorCode(protocol, code string) bool {
+	if code == "-" {
+		return true
+	}
+	if protocol == "grpc" {
+		return code != "0"
+	}
+	return len(code) > 0 && code[0] == '5'
+}
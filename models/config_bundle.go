@@ -0,0 +1,43 @@
+package models
+
+// Status of the objects of an applied config bundle
+const (
+	BundleObjectApplied        = "applied"
+	BundleObjectFailed         = "failed"
+	BundleObjectNotApplied     = "not applied"
+	BundleObjectRolledBack     = "rolled back"
+	BundleObjectRollbackFailed = "rollback failed"
+	BundleObjectValidated      = "validated"
+)
+
+// Operation performed to apply an object of a config bundle
+const (
+	BundleOperationCreate = "create"
+	BundleOperationUpdate = "update"
+)
+
+// ConfigBundleResult reports the outcome of applying a bundle of Istio config objects.
+// Objects are listed in the order they appear in the bundle.
+type ConfigBundleResult struct {
+	// Applied is true when all the objects of the bundle were applied
+	// required: true
+	Applied bool `json:"applied"`
+
+	// RolledBack is true when an apply failure forced to revert the objects already applied
+	// required: true
+	RolledBack bool `json:"rolledBack"`
+
+	// Per object results
+	// required: true
+	Objects []ConfigBundleObject `json:"objects"`
+}
+
+// ConfigBundleObject reports the outcome of applying a single object of a config bundle
+type ConfigBundleObject struct {
+	ObjectType string `json:"objectType"`
+	Name       string `json:"name"`
+	Namespace  string `json:"namespace"`
+	Operation  string `json:"operation"`
+	Status     string `json:"status"`
+	Error      string `json:"error,omitempty"`
+}
@@ -0,0 +1,104 @@
+package models
+
+import (
+	"sort"
+)
+
+// Kinds of references between the Istio objects and the objects they refer to
+const (
+	ConfigReferenceGateway  = "gateway"
+	ConfigReferenceSubset   = "subset"
+	ConfigReferenceHost     = "host"
+	ConfigReferenceSelector = "selector"
+)
+
+// ConfigReferenceGraph is the graph of the references of the Istio objects of a namespace: VirtualServices to their
+// Gateways and to the DestinationRules defining their subsets, DestinationRules to their Services and
+// AuthorizationPolicies to their workloads
+// swagger:model
+type ConfigReferenceGraph struct {
+	// required: true
+	// example: bookinfo
+	Namespace string `json:"namespace"`
+
+	// The Istio objects of the namespace and the objects they refer to, like Gateways of other namespaces, Services
+	// and workloads
+	// required: true
+	Nodes []IstioValidationKey `json:"nodes"`
+
+	// The references from an Istio object to another object
+	// required: true
+	Edges []ConfigReferenceEdge `json:"edges"`
+}
+
+// ConfigReferenceEdge is the reference of an Istio object to another object
+type ConfigReferenceEdge struct {
+	// The referring Istio object
+	// required: true
+	Source IstioValidationKey `json:"source"`
+
+	// The referred object
+	// required: true
+	Target IstioValidationKey `json:"target"`
+
+	// The kind of reference: gateway, subset, host or selector
+	// required: true
+	// example: subset
+	Reference string `json:"reference"`
+
+	// The subset of the DestinationRule referred by a VirtualService
+	// example: v1
+	Subset string `json:"subset,omitempty"`
+}
+
+// AddNode adds an object to the graph, once
+func (g *ConfigReferenceGraph) AddNode(node IstioValidationKey) {
+	for _, n := range g.Nodes {
+		if n == node {
+			return
+		}
+	}
+	g.Nodes = append(g.Nodes, node)
+}
+
+// AddEdge adds a reference to the graph, once, with its objects
+func (g *ConfigReferenceGraph) AddEdge(edge ConfigReferenceEdge) {
+	g.AddNode(edge.Source)
+	g.AddNode(edge.Target)
+	for _, e := range g.Edges {
+		if e == edge {
+			return
+		}
+	}
+	g.Edges = append(g.Edges, edge)
+}
+
+// Sort sorts the nodes and the edges, for stable responses
+func (g *ConfigReferenceGraph) Sort() {
+	sort.Slice(g.Nodes, func(i, j int) bool {
+		return lessKey(g.Nodes[i], g.Nodes[j])
+	})
+	sort.Slice(g.Edges, func(i, j int) bool {
+		a, b := g.Edges[i], g.Edges[j]
+		if a.Source != b.Source {
+			return lessKey(a.Source, b.Source)
+		}
+		if a.Target != b.Target {
+			return lessKey(a.Target, b.Target)
+		}
+		if a.Reference != b.Reference {
+			return a.Reference < b.Reference
+		}
+		return a.Subset < b.Subset
+	})
+}
+
+func lessKey(a, b IstioValidationKey) bool {
+	if a.ObjectType != b.ObjectType {
+		return a.ObjectType < b.ObjectType
+	}
+	if a.Namespace != b.Namespace {
+		return a.Namespace < b.Namespace
+	}
+	return a.Name < b.Name
+}
@@ -0,0 +1,85 @@
+package models
+
+import (
+	"time"
+)
+
+// WorkloadConnectionMetricsQuery holds the parameters of the TCP connections of a workload
+type WorkloadConnectionMetricsQuery struct {
+	Namespace    string
+	Workload     string
+	RateInterval string
+	QueryTime    time.Time
+}
+
+// FillDefaults fills the struct with default parameters
+func (q *WorkloadConnectionMetricsQuery) FillDefaults() {
+	q.RateInterval = "10m"
+	q.QueryTime = time.Now()
+}
+
+// WorkloadConnectionMetrics holds the TCP connections received and opened by a workload, to size its connection pools
+// swagger:model workloadConnectionMetrics
+type WorkloadConnectionMetrics struct {
+	// required: true
+	Namespace string `json:"namespace"`
+
+	// required: true
+	Workload string `json:"workload"`
+
+	// The downstream connections, opened by the clients of the workload
+	// required: true
+	Inbound ConnectionCounts `json:"inbound"`
+
+	// The upstream connections, opened by the workload to its destinations
+	// required: true
+	Outbound ConnectionCounts `json:"outbound"`
+}
+
+// ConnectionCounts are the TCP connections of a direction of the traffic of a workload
+type ConnectionCounts struct {
+	// Whether Istio reports connections for this traffic. The connections are only reported for the traffic
+	// proxied as TCP, not for the HTTP and gRPC traffic.
+	// required: true
+	Reported bool `json:"reported"`
+
+	ConnectionCount
+
+	// The connections per peer: the source workloads inbound, the destination services outbound. Sorted by
+	// active connections.
+	// required: true
+	Peers []PeerConnectionCount `json:"peers"`
+}
+
+// ConnectionCount counts the TCP connections reported by the proxies of a workload
+type ConnectionCount struct {
+	// The connections open at the query time: the connections opened minus the connections closed since the proxies started
+	// required: true
+	Active float64 `json:"active"`
+
+	// The connections opened since the proxies started
+	// required: true
+	Total float64 `json:"total"`
+
+	// The connections opened per second over the rate interval
+	// required: true
+	OpenedRate float64 `json:"openedRate"`
+
+	// The connections closed per second over the rate interval
+	// required: true
+	ClosedRate float64 `json:"closedRate"`
+}
+
+// PeerConnectionCount counts the TCP connections between a workload and a peer
+type PeerConnectionCount struct {
+	// The namespace of the peer
+	// required: true
+	Namespace string `json:"namespace"`
+
+	// The source workload inbound, the destination service outbound, unknown when not reported
+	// required: true
+	// example: reviews.bookinfo.svc.cluster.local
+	Name string `json:"name"`
+
+	ConnectionCount
+}
@@ -0,0 +1,97 @@
+package models
+
+import (
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// Health status of a custom resource, read from one of its status conditions
+const (
+	// CustomResourceHealthy when the condition status is True
+	CustomResourceHealthy = "Healthy"
+	// CustomResourceUnhealthy when the condition status is False
+	CustomResourceUnhealthy = "Unhealthy"
+	// CustomResourceUnknown when the condition status is Unknown, or the condition was set for a previous generation of the resource
+	CustomResourceUnknown = "Unknown"
+	// CustomResourceNA when the resource has no such condition, i.e. its operator has not reconciled it yet
+	CustomResourceNA = "NA"
+)
+
+// NamespaceCustomResourceHealth is the health of the custom resources of a namespace
+// swagger:model namespaceCustomResourceHealth
+type NamespaceCustomResourceHealth []CustomResourceHealth
+
+// CustomResourceHealth is the health of a custom resource, as reported by a condition of its status
+type CustomResourceHealth struct {
+	// required: true
+	Group string `json:"group"`
+	// required: true
+	Version string `json:"version"`
+	// required: true
+	Kind string `json:"kind"`
+	// required: true
+	Name string `json:"name"`
+	// required: true
+	Namespace string `json:"namespace"`
+
+	// The type of the condition the health is read from
+	//
+	// example: Ready
+	// required: true
+	Condition string `json:"condition"`
+
+	// Healthy, Unhealthy, Unknown or NA
+	//
+	// required: true
+	Status string `json:"status"`
+
+	// The reason of the last transition of the condition
+	Reason string `json:"reason,omitempty"`
+
+	// The message of the last transition of the condition
+	Message string `json:"message,omitempty"`
+
+	// The time of the last transition of the condition
+	LastTransitionTime string `json:"lastTransitionTime,omitempty"`
+}
+
+// ParseCustomResourceHealth returns the health of the custom resource from the condition of its status
+func ParseCustomResourceHealth(cr unstructured.Unstructured, condition string) CustomResourceHealth {
+	gvk := cr.GroupVersionKind()
+	health := CustomResourceHealth{
+		Group:     gvk.Group,
+		Version:   gvk.Version,
+		Kind:      gvk.Kind,
+		Name:      cr.GetName(),
+		Namespace: cr.GetNamespace(),
+		Condition: condition,
+		Status:    CustomResourceNA,
+	}
+
+	conditions, _, _ := unstructured.NestedSlice(cr.Object, "status", "conditions")
+	for _, c := range conditions {
+		cond, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if t, _ := cond["type"].(string); t != condition {
+			continue
+		}
+		health.Reason, _ = cond["reason"].(string)
+		health.Message, _ = cond["message"].(string)
+		health.LastTransitionTime, _ = cond["lastTransitionTime"].(string)
+		switch status, _ := cond["status"].(string); status {
+		case "True":
+			health.Status = CustomResourceHealthy
+		case "False":
+			health.Status = CustomResourceUnhealthy
+		default:
+			health.Status = CustomResourceUnknown
+		}
+		// The condition does not reflect the last changes of the spec yet
+		if observed, found, _ := unstructured.NestedInt64(cond, "observedGeneration"); found && observed < cr.GetGeneration() {
+			health.Status = CustomResourceUnknown
+		}
+		break
+	}
+	return health
+}
@@ -29,6 +29,8 @@ type MonitoringDashboard struct {
 	Charts        []Chart        `json:"charts"`
 	Aggregations  []Aggregation  `json:"aggregations"`
 	ExternalLinks []ExternalLink `json:"externalLinks"`
+	// The effective step of the series in seconds, increased for the long ranges
+	Step int64 `json:"step,omitempty"`
 }
 
 // Chart is the model representing a custom chart, transformed from charts in MonitoringDashboard k8s resource
@@ -0,0 +1,43 @@
+package models
+
+// DeleteImpact is the preview of the objects affected by the deletion of an Istio object, so that the deletion
+// can be confirmed knowing what it breaks
+// swagger:model
+type DeleteImpact struct {
+	// The Istio object to delete
+	// required: true
+	Object IstioValidationKey `json:"object"`
+
+	// The most important severity of the checks of the dependents, empty when nothing is affected
+	// example: error
+	Severity SeverityLevel `json:"severity,omitempty"`
+
+	// The objects getting new checks once the object is deleted, most affected first
+	// required: true
+	Dependents []DeleteImpactDependent `json:"dependents"`
+}
+
+// DeleteImpactDependent is an object depending on the deleted Istio object
+type DeleteImpactDependent struct {
+	IstioValidationKey
+
+	// The most important severity of the new checks
+	// required: true
+	// example: warning
+	Severity SeverityLevel `json:"severity"`
+
+	// The checks the object gets once the Istio object is deleted
+	// required: true
+	Checks []*IstioCheck `json:"checks"`
+}
+
+// MaxSeverity returns the most important severity of the checks, empty when there is none
+func MaxSeverity(checks []*IstioCheck) SeverityLevel {
+	var severity SeverityLevel
+	for _, check := range checks {
+		if severity == "" || severityRanks[check.Severity] > severityRanks[severity] {
+			severity = check.Severity
+		}
+	}
+	return severity
+}
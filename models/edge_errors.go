@@ -0,0 +1,135 @@
+package models
+
+import (
+	"time"
+)
+
+// EdgeErrorsQuery identifies an edge of the graph: the traffic from a source workload or app to a destination
+// service, workload or app
+type EdgeErrorsQuery struct {
+	SourceNamespace      string
+	SourceWorkload       string
+	SourceApp            string
+	DestinationNamespace string
+	DestinationService   string
+	DestinationWorkload  string
+	DestinationApp       string
+	Reporter             string // source | destination, defaults to source if not provided
+	RateInterval         string
+	QueryTime            time.Time
+}
+
+// FillDefaults fills the struct with default parameters
+func (q *EdgeErrorsQuery) FillDefaults() {
+	q.Reporter = "source"
+	q.RateInterval = "10m"
+	q.QueryTime = time.Now()
+}
+
+// EdgeErrors is the decomposition of the errors of the requests of an edge of the graph
+// swagger:model edgeErrors
+type EdgeErrors struct {
+	// The request rate of the edge
+	// required: true
+	RequestRate float64 `json:"requestRate"`
+
+	// The rate of the requests in error: no response, 4xx and 5xx response codes, and grpc errors
+	// required: true
+	ErrorRate float64 `json:"errorRate"`
+
+	// The request rate per response code, sorted by decreasing rate
+	// required: true
+	ResponseCodes []ResponseCodeRate `json:"responseCodes"`
+
+	// The request rate per response flag set by the proxies, sorted by decreasing rate
+	// required: true
+	ResponseFlags []ResponseFlagRate `json:"responseFlags"`
+
+	// The routes of the edge producing the most errors
+	// required: true
+	Routes []RouteErrorRate `json:"routes"`
+}
+
+// ResponseCodeRate is the request rate of a response code. gRPC requests are counted per grpc status.
+type ResponseCodeRate struct {
+	// The HTTP response code, or the grpc status prefixed with "grpc-"
+	// example: 503
+	// required: true
+	Code string `json:"code"`
+
+	// required: true
+	Rate float64 `json:"rate"`
+
+	// True when the response is an error
+	// required: true
+	Error bool `json:"error"`
+}
+
+// ResponseFlagRate is the request rate of a response flag
+type ResponseFlagRate struct {
+	// The Envoy response flag
+	// example: UF
+	// required: true
+	Flag string `json:"flag"`
+
+	// The meaning of the flag
+	// example: Upstream connection failure
+	// required: true
+	Meaning string `json:"meaning"`
+
+	// required: true
+	Rate float64 `json:"rate"`
+}
+
+// RouteErrorRate is the error rate of the traffic of an edge routed to a destination workload
+type RouteErrorRate struct {
+	// required: true
+	DestinationService string `json:"destinationService"`
+
+	// Unknown when the request wasn't routed to any workload
+	// required: true
+	DestinationWorkload string `json:"destinationWorkload"`
+
+	// required: true
+	RequestRate float64 `json:"requestRate"`
+
+	// required: true
+	ErrorRate float64 `json:"errorRate"`
+}
+
+// responseFlagMeanings maps the Envoy response flags reported by Istio to their meaning
+var responseFlagMeanings = map[string]string{
+	"DC":    "Downstream connection termination",
+	"DI":    "Request delayed by fault injection",
+	"DPE":   "Downstream request had an HTTP protocol error",
+	"DT":    "Request or connection exceeded the downstream max connection duration",
+	"FI":    "Request aborted by fault injection",
+	"IH":    "Request rejected for invalid values in strictly-checked headers",
+	"LH":    "Local service failed health check",
+	"LR":    "Connection local reset",
+	"NC":    "Upstream cluster not found",
+	"NFCF":  "Filter configuration not found",
+	"NR":    "No route configured for the request",
+	"OM":    "Overload manager terminated the request",
+	"RL":    "Request rate limited locally",
+	"RLSE":  "Request rejected because of an error in the rate limit service",
+	"SI":    "Stream idle timeout",
+	"UAEX":  "Request denied by the external authorization service",
+	"UC":    "Upstream connection termination",
+	"UF":    "Upstream connection failure",
+	"UH":    "No healthy upstream hosts",
+	"UMSDR": "Upstream request reached the max stream duration",
+	"UO":    "Upstream overflow (circuit breaking)",
+	"UPE":   "Upstream response had an HTTP protocol error",
+	"UR":    "Upstream remote reset",
+	"URX":   "Upstream retry limit or max connect attempts reached",
+	"UT":    "Upstream request timeout",
+}
+
+// ResponseFlagMeaning returns the meaning of an Envoy response flag
+func ResponseFlagMeaning(flag string) string {
+	if meaning, found := responseFlagMeanings[flag]; found {
+		return meaning
+	}
+	return "Unknown response flag"
+}
@@ -0,0 +1,53 @@
+package models
+
+// EffectiveDestinationRule is the DestinationRule in effect for the host of a service, as resolved by the proxies
+// of the namespace of the service, along with the other DestinationRules matching the host
+// swagger:model effectiveDestinationRule
+type EffectiveDestinationRule struct {
+	// required: true
+	Namespace string `json:"namespace"`
+
+	// required: true
+	Service string `json:"service"`
+
+	// The FQDN of the service
+	// example: reviews.bookinfo.svc.cluster.local
+	// required: true
+	Host string `json:"host"`
+
+	// The winning DestinationRule, nil when no DestinationRule applies to the service
+	DestinationRule *DestinationRule `json:"destinationRule"`
+
+	// The DestinationRules with the same host and namespace as the winning one, merged into it
+	// required: true
+	Merged []DestinationRuleResolution `json:"merged"`
+
+	// The traffic policy of the merged DestinationRules: the one of the oldest DestinationRule defining it
+	TrafficPolicy interface{} `json:"trafficPolicy"`
+
+	// The subsets of the merged DestinationRules, the oldest DestinationRule wins on duplicated names
+	// required: true
+	Subsets []interface{} `json:"subsets"`
+
+	// The DestinationRules matching the host of the service but not in effect
+	// required: true
+	Shadowed []DestinationRuleResolution `json:"shadowed"`
+}
+
+// DestinationRuleResolution is a DestinationRule matching the host of a service, with the reasons why it is not,
+// or only partially, in effect
+type DestinationRuleResolution struct {
+	// required: true
+	Namespace string `json:"namespace"`
+
+	// required: true
+	Name string `json:"name"`
+
+	// The host of the DestinationRule
+	// example: *.bookinfo.svc.cluster.local
+	// required: true
+	Host string `json:"host"`
+
+	// required: true
+	Reasons []string `json:"reasons"`
+}
@@ -0,0 +1,116 @@
+package models
+
+import (
+	"sort"
+
+	core_v1 "k8s.io/api/core/v1"
+	discovery_v1beta1 "k8s.io/api/discovery/v1beta1"
+)
+
+// UnknownZone is the zone of the endpoints without topology
+const UnknownZone = "unknown"
+
+// ServiceEndpointsHealth counts the ready and not ready endpoints of a service per zone, to reveal the unhealthy
+// endpoints causing partial failures of the service
+// swagger:model serviceEndpointsHealth
+type ServiceEndpointsHealth struct {
+	// required: true
+	Namespace string `json:"namespace"`
+
+	// required: true
+	Service string `json:"service"`
+
+	// EndpointsCount of all the zones
+	// required: true
+	Total EndpointsCount `json:"total"`
+
+	// The endpoints per zone, sorted by zone
+	// required: true
+	Zones []ZoneEndpoints `json:"zones"`
+
+	// True when the ratio of the not ready endpoints exceeds the threshold
+	// required: true
+	Degraded bool `json:"degraded"`
+
+	// Set when the service has no endpoint, which is usually a misconfiguration
+	// example: No pod matches the selector of the service
+	Warning string `json:"warning,omitempty"`
+}
+
+// EndpointsCount is the number of ready and not ready endpoints
+type EndpointsCount struct {
+	// required: true
+	Ready int `json:"ready"`
+
+	// required: true
+	NotReady int `json:"notReady"`
+
+	// The ratio of the not ready endpoints, between 0 and 1
+	// required: true
+	NotReadyRatio float64 `json:"notReadyRatio"`
+}
+
+// ZoneEndpoints is the number of endpoints of a zone
+type ZoneEndpoints struct {
+	// The zone of the endpoints, unknown when the EndpointSlices don't report it
+	// required: true
+	// example: us-east-1a
+	Zone string `json:"zone"`
+
+	EndpointsCount
+}
+
+func (c *EndpointsCount) add(ready bool) {
+	if ready {
+		c.Ready++
+	} else {
+		c.NotReady++
+	}
+	c.NotReadyRatio = float64(c.NotReady) / float64(c.Ready+c.NotReady)
+}
+
+// ParseEndpointSlices counts the endpoints of the slices of the service per zone. An endpoint is reported by one
+// slice per address type (i.e. IPv4 and IPv6 for dual stack services), it's only counted once.
+func (h *ServiceEndpointsHealth) ParseEndpointSlices(slices []discovery_v1beta1.EndpointSlice) {
+	zones := map[string]*ZoneEndpoints{}
+	seen := map[string]bool{}
+	for _, slice := range slices {
+		for _, endpoint := range slice.Endpoints {
+			key := endpointKey(endpoint)
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+
+			zone := lookupLabel(endpoint.Topology, core_v1.LabelTopologyZone, core_v1.LabelFailureDomainBetaZone)
+			if zone == "" {
+				zone = UnknownZone
+			}
+			if _, found := zones[zone]; !found {
+				zones[zone] = &ZoneEndpoints{Zone: zone}
+			}
+			// A nil condition is an unknown state, to be interpreted as ready
+			ready := endpoint.Conditions.Ready == nil || *endpoint.Conditions.Ready
+			zones[zone].add(ready)
+			h.Total.add(ready)
+		}
+	}
+
+	h.Zones = []ZoneEndpoints{}
+	for _, zone := range zones {
+		h.Zones = append(h.Zones, *zone)
+	}
+	sort.Slice(h.Zones, func(i, j int) bool {
+		return h.Zones[i].Zone < h.Zones[j].Zone
+	})
+}
+
+func endpointKey(endpoint discovery_v1beta1.Endpoint) string {
+	if endpoint.TargetRef != nil {
+		return endpoint.TargetRef.Kind + "/" + endpoint.TargetRef.Namespace + "/" + endpoint.TargetRef.Name
+	}
+	if len(endpoint.Addresses) > 0 {
+		return endpoint.Addresses[0]
+	}
+	return ""
+}
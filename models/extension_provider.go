@@ -0,0 +1,60 @@
+package models
+
+// Kinds of the extension providers of the mesh config, by the Istio API using them
+const (
+	ExtensionProviderAccessLogging = "accessLogging"
+	ExtensionProviderAuthorization = "authorization"
+	ExtensionProviderMetrics       = "metrics"
+	ExtensionProviderTracing       = "tracing"
+)
+
+// ExtensionProviders are the extension providers of the mesh config, sorted by name
+// swagger:model extensionProviders
+type ExtensionProviders []ExtensionProvider
+
+// ExtensionProvider is a provider of the extensionProviders of the mesh config, referenced by name from the
+// Telemetries and from the AuthorizationPolicies with the CUSTOM action
+type ExtensionProvider struct {
+	// required: true
+	// example: ext-authz
+	Name string `json:"name"`
+
+	// The field of the provider definition, giving its implementation
+	// required: true
+	// example: envoyExtAuthzHttp
+	Type string `json:"type"`
+
+	// The kinds of the provider: accessLogging, authorization, metrics or tracing
+	// required: true
+	Kinds []string `json:"kinds"`
+
+	// True for the providers available without definition in the mesh config: envoy, prometheus and stackdriver
+	// required: true
+	Builtin bool `json:"builtin"`
+
+	// The service the proxies send the data or the requests to, when the provider has one
+	// example: ext-authz.foo.svc.cluster.local
+	Service string `json:"service,omitempty"`
+
+	// The port of the service
+	// example: 8000
+	Port int `json:"port,omitempty"`
+
+	// False when the service of the provider, or its port, is not found in the Services and the ServiceEntries of the
+	// mesh. The providers without service are always reachable.
+	// required: true
+	Reachable bool `json:"reachable"`
+
+	// Why the service of the provider is not reachable
+	Message string `json:"message,omitempty"`
+}
+
+// Get returns the provider with the name, nil when not defined
+func (providers ExtensionProviders) Get(name string) *ExtensionProvider {
+	for i := range providers {
+		if providers[i].Name == name {
+			return &providers[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+package models
+
+// ExternalServiceDiagnostics reports the result of testing the connection to an external service (Prometheus, Jaeger)
+type ExternalServiceDiagnostics struct {
+	// The name of the external service
+	//
+	// example: prometheus
+	// required: true
+	Name string `json:"name"`
+
+	// The configured URL, without credentials
+	//
+	// example: http://prometheus.istio-system:9090
+	// required: true
+	URL string `json:"url"`
+
+	// The configured authentication type
+	//
+	// example: bearer
+	AuthType string `json:"authType"`
+
+	// When true, the service answered the request, whatever the status code
+	//
+	// required: true
+	Reachable bool `json:"reachable"`
+
+	// When false, the service rejected the credentials (401 or 403). Nil when the service is not reachable.
+	Authenticated *bool `json:"authenticated"`
+
+	// The HTTP status code of the test query
+	StatusCode int `json:"statusCode,omitempty"`
+
+	// Duration of the test query, in milliseconds
+	LatencyMs int64 `json:"latencyMs"`
+
+	// Version of the service, when it exposes it
+	Version string `json:"version,omitempty"`
+
+	// Short description of the result of the test query
+	SampleResult string `json:"sampleResult,omitempty"`
+
+	// The error found testing the service, if any
+	Error string `json:"error,omitempty"`
+}
@@ -4,3 +4,31 @@ package models
 type GrafanaInfo struct {
 	ExternalLinks []ExternalLink `json:"externalLinks"`
 }
+
+// DefaultGrafanaFolder holds the dashboards configured without folder
+const DefaultGrafanaFolder = "General"
+
+// GrafanaDashboardFolder groups the links to the Grafana dashboards of a folder
+type GrafanaDashboardFolder struct {
+	// Name of the folder
+	// required: true
+	// example: Istio
+	Name string `json:"name"`
+
+	// Links to the dashboards of the folder, with the variables of the target already set
+	// required: true
+	Links []GrafanaDashboardLink `json:"links"`
+}
+
+// GrafanaDashboardLink is a link to a Grafana dashboard for a given app, workload or service
+type GrafanaDashboardLink struct {
+	// Name of the dashboard
+	// required: true
+	// example: Istio Workload Dashboard
+	Name string `json:"name"`
+
+	// URL of the dashboard
+	// required: true
+	// example: http://grafana:3000/d/UbsSZTDik/istio-workload-dashboard?var-namespace=bookinfo&var-workload=reviews-v1
+	URL string `json:"url"`
+}
@@ -0,0 +1,193 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/kiali/kiali/kubernetes"
+)
+
+// Match types of a header condition
+const (
+	HeaderMatchExact  = "exact"
+	HeaderMatchPrefix = "prefix"
+	HeaderMatchRegex  = "regex"
+)
+
+// Reasons for which the destination of a header route doesn't exist
+const (
+	HeaderRouteHostNotFound   = "HostNotFound"
+	HeaderRouteSubsetNotFound = "SubsetNotFound"
+)
+
+// HeaderRoutes is the audit of the routes of the VirtualServices of a namespace that match on request headers,
+// as used for A/B testing and dark launches
+// swagger:model headerRoutes
+type HeaderRoutes struct {
+	// The namespace of the VirtualServices
+	//
+	// required: true
+	Namespace string `json:"namespace"`
+
+	// The HTTP routes matching on headers
+	//
+	// required: true
+	Routes []HeaderRoute `json:"routes"`
+}
+
+// HeaderRoute is an HTTP route of a VirtualService with header conditions
+type HeaderRoute struct {
+	// The name of the VirtualService
+	//
+	// required: true
+	VirtualService string `json:"virtualService"`
+
+	// The path of the route in the VirtualService
+	//
+	// example: spec/http[0]
+	// required: true
+	Path string `json:"path"`
+
+	// The name of the route, when set
+	Name string `json:"name,omitempty"`
+
+	// The hosts of the VirtualService
+	//
+	// required: true
+	Hosts []string `json:"hosts"`
+
+	// The match blocks of the route with header conditions. A request matching any of them takes the route.
+	//
+	// required: true
+	Matches []HeaderRouteMatch `json:"matches"`
+
+	// The destinations of the route
+	//
+	// required: true
+	Destinations []HeaderRouteDestination `json:"destinations"`
+
+	// True when a destination of the route doesn't exist
+	//
+	// required: true
+	Invalid bool `json:"invalid"`
+}
+
+// HeaderRouteMatch is a match block of a route. All its header conditions must be satisfied.
+type HeaderRouteMatch struct {
+	// The path of the match block in the VirtualService
+	//
+	// example: spec/http[0]/match[0]
+	// required: true
+	Path string `json:"path"`
+
+	// required: true
+	Conditions []HeaderCondition `json:"conditions"`
+}
+
+// HeaderCondition is a condition on the value of a request header
+type HeaderCondition struct {
+	// The name of the header
+	//
+	// example: end-user
+	// required: true
+	Header string `json:"header"`
+
+	// exact, prefix or regex
+	//
+	// required: true
+	Type string `json:"type"`
+
+	// required: true
+	Value string `json:"value"`
+
+	// True for the conditions of withoutHeaders: the request must not have the header value
+	Negated bool `json:"negated,omitempty"`
+}
+
+// HeaderRouteDestination is a destination of a header route
+type HeaderRouteDestination struct {
+	// required: true
+	Host string `json:"host"`
+
+	Subset string `json:"subset,omitempty"`
+
+	Weight uint32 `json:"weight,omitempty"`
+
+	// HostNotFound or SubsetNotFound when the destination doesn't exist
+	NotFound string `json:"notFound,omitempty"`
+}
+
+// ParseHeaderRoutes returns the HTTP routes of the VirtualService with at least a header condition
+func ParseHeaderRoutes(virtualService kubernetes.IstioObject) []HeaderRoute {
+	headerRoutes := []HeaderRoute{}
+	hosts := []string{}
+	switch hs := virtualService.GetSpec()["hosts"].(type) {
+	case []interface{}:
+		for _, h := range hs {
+			if host, ok := h.(string); ok {
+				hosts = append(hosts, host)
+			}
+		}
+	case []string:
+		hosts = append(hosts, hs...)
+	}
+
+	routes, _ := virtualService.GetSpec()["http"].([]interface{})
+	for i, r := range routes {
+		route, _ := r.(map[string]interface{})
+		headerRoute := HeaderRoute{
+			VirtualService: virtualService.GetObjectMeta().Name,
+			Path:           fmt.Sprintf("spec/http[%d]", i),
+			Hosts:          hosts,
+			Matches:        []HeaderRouteMatch{},
+			Destinations:   []HeaderRouteDestination{},
+		}
+		headerRoute.Name, _ = route["name"].(string)
+
+		matches, _ := route["match"].([]interface{})
+		for j, m := range matches {
+			match, _ := m.(map[string]interface{})
+			conditions := append(parseHeaderConditions(match["headers"], false), parseHeaderConditions(match["withoutHeaders"], true)...)
+			if len(conditions) == 0 {
+				continue
+			}
+			headerRoute.Matches = append(headerRoute.Matches, HeaderRouteMatch{
+				Path:       fmt.Sprintf("%s/match[%d]", headerRoute.Path, j),
+				Conditions: conditions,
+			})
+		}
+		if len(headerRoute.Matches) == 0 {
+			continue
+		}
+
+		destinations, _ := route["route"].([]interface{})
+		for _, d := range destinations {
+			destination, _ := d.(map[string]interface{})
+			target, _ := destination["destination"].(map[string]interface{})
+			hrd := HeaderRouteDestination{Weight: castWeight(destination["weight"])}
+			hrd.Host, _ = target["host"].(string)
+			hrd.Subset, _ = target["subset"].(string)
+			headerRoute.Destinations = append(headerRoute.Destinations, hrd)
+		}
+		headerRoutes = append(headerRoutes, headerRoute)
+	}
+	return headerRoutes
+}
+
+// parseHeaderConditions returns the conditions of a headers map, sorted by header name
+func parseHeaderConditions(headers interface{}, negated bool) []HeaderCondition {
+	conditions := []HeaderCondition{}
+	hs, _ := headers.(map[string]interface{})
+	for header, h := range hs {
+		stringMatch, _ := h.(map[string]interface{})
+		for _, matchType := range []string{HeaderMatchExact, HeaderMatchPrefix, HeaderMatchRegex} {
+			if value, ok := stringMatch[matchType].(string); ok {
+				conditions = append(conditions, HeaderCondition{Header: header, Type: matchType, Value: value, Negated: negated})
+			}
+		}
+	}
+	sort.Slice(conditions, func(i, j int) bool {
+		return conditions[i].Header < conditions[j].Header
+	})
+	return conditions
+}
@@ -1,7 +1,11 @@
 package models
 
 import (
+	"strings"
+	"time"
+
 	"github.com/prometheus/common/model"
+	core_v1 "k8s.io/api/core/v1"
 
 	"github.com/kiali/kiali/log"
 )
@@ -15,9 +19,33 @@ type NamespaceServiceHealth map[string]*ServiceHealth
 // NamespaceWorkloadHealth is an alias of map of workload name x health
 type NamespaceWorkloadHealth map[string]*WorkloadHealth
 
+// Protocols of the services, as detected from their ports, that select the metrics used to compute their health
+const (
+	HTTPProtocol = "http"
+	GRPCProtocol = "grpc"
+	TCPProtocol  = "tcp"
+)
+
 // ServiceHealth contains aggregated health from various sources, for a given service
 type ServiceHealth struct {
 	Requests RequestHealth `json:"requests"`
+	// Protocol of the service: http, grpc or tcp
+	Protocol string `json:"protocol"`
+	// Connection stats, only available for tcp services
+	Connections *ConnectionHealth `json:"connections,omitempty"`
+	// Ratio of failed requests (5xx http codes, non-OK grpc statuses) or connections (closed with response flags),
+	// from 0 to 1. Nil when there is no traffic.
+	ErrorRatio *float64 `json:"errorRatio"`
+}
+
+// ConnectionHealth holds the rates of the tcp connections of a service
+type ConnectionHealth struct {
+	// Opened connections per second
+	Opened float64 `json:"opened"`
+	// Closed connections per second
+	Closed float64 `json:"closed"`
+	// Connections per second closed with response flags (i.e. UF, upstream connection failure)
+	Failed float64 `json:"failed"`
 }
 
 // AppHealth contains aggregated health from various sources, for a given app
@@ -48,6 +76,74 @@ func EmptyAppHealth() AppHealth {
 func EmptyServiceHealth() ServiceHealth {
 	return ServiceHealth{
 		Requests: NewEmptyRequestHealth(),
+		Protocol: HTTPProtocol,
+	}
+}
+
+// ServiceProtocol detects the protocol of a service from the appProtocol or the name prefix of its ports (i.e. grpc-web,
+// tcp-db). grpc takes precedence over http, as a grpc service still reports http requests, and request based protocols
+// take precedence over tcp ones. Services without any recognized port default to http, for Istio protocol sniffing.
+func ServiceProtocol(ports []core_v1.ServicePort) string {
+	protocol := ""
+	for _, port := range ports {
+		name := port.Name
+		if port.AppProtocol != nil {
+			name = *port.AppProtocol
+		}
+		switch strings.ToLower(strings.SplitN(name, "-", 2)[0]) {
+		case "grpc":
+			return GRPCProtocol
+		case "http", "http2":
+			protocol = HTTPProtocol
+		case "tcp", "tls", "https", "mongo", "mysql", "redis":
+			// https ports are TLS passthrough, Istio only reports tcp metrics for them
+			if protocol == "" {
+				protocol = TCPProtocol
+			}
+		}
+	}
+	if protocol == "" {
+		return HTTPProtocol
+	}
+	return protocol
+}
+
+// ComputeErrorRatio sets the error ratio according to the protocol of the service: the http requests of http services,
+// the grpc requests of grpc services and the connections of tcp services
+func (in *ServiceHealth) ComputeErrorRatio() {
+	in.ErrorRatio = nil
+	switch in.Protocol {
+	case TCPProtocol:
+		if in.Connections != nil && in.Connections.Closed > 0 {
+			ratio := in.Connections.Failed / in.Connections.Closed
+			in.ErrorRatio = &ratio
+		}
+	case GRPCProtocol:
+		if codes, ok := in.Requests.Inbound[GRPCProtocol]; ok {
+			if _, success := RequestsSuccessRate(map[string]map[string]float64{GRPCProtocol: codes}); success != nil {
+				ratio := 1 - *success
+				in.ErrorRatio = &ratio
+			}
+		}
+	default:
+		if _, success := RequestsSuccessRate(in.Requests.Inbound); success != nil {
+			ratio := 1 - *success
+			in.ErrorRatio = &ratio
+		}
+	}
+}
+
+// AggregateConnections adds the provided istio_tcp_connections_closed_total or istio_tcp_connections_opened_total
+// sample to the connection stats. Samples are expected to be reported by the destination only.
+func (in *ConnectionHealth) AggregateConnections(sample *model.Sample, opened bool) {
+	value := float64(sample.Value)
+	if opened {
+		in.Opened += value
+		return
+	}
+	in.Closed += value
+	if flags, ok := sample.Metric["response_flags"]; ok && flags != "-" && flags != "" {
+		in.Failed += value
 	}
 }
 
@@ -62,6 +158,37 @@ func EmptyWorkloadHealth() *WorkloadHealth {
 type WorkloadHealth struct {
 	WorkloadStatus *WorkloadStatus `json:"workloadStatus"`
 	Requests       RequestHealth   `json:"requests"`
+	// Maintenance while the workload is in maintenance, its degraded or failure health is then suppressed
+	Status HealthStatus `json:"status,omitempty"`
+	// End of the maintenance of the workload
+	MaintenanceUntil *time.Time `json:"maintenanceUntil,omitempty"`
+	// Pods stuck by the initialization of their Istio networking, with the failure reason
+	IstioInitIssues []PodIstioInitIssue `json:"istioInitIssues,omitempty"`
+	// Containers restarting in a loop, with the probe settings which may cause it
+	ProbeIssues []PodProbeIssue `json:"probeIssues,omitempty"`
+}
+
+// WorkloadDependencyHealth holds the health of a workload along with the health of its immediate dependencies,
+// that is the workloads and services it sends requests to
+type WorkloadDependencyHealth struct {
+	Health WorkloadHealth `json:"health"`
+	// Status of the workload itself
+	Status HealthStatus `json:"status"`
+	// Worst status among the dependencies
+	DependencyStatus HealthStatus       `json:"dependencyStatus"`
+	Dependencies     []DependencyHealth `json:"dependencies"`
+}
+
+// DependencyHealth is the health of a workload or service receiving requests from a workload
+type DependencyHealth struct {
+	Namespace string       `json:"namespace"`
+	Name      string       `json:"name"`
+	Kind      string       `json:"kind"` // workload | service
+	Status    HealthStatus `json:"status"`
+	// Rates of the requests sent to the dependency, by protocol and code
+	Requests map[string]map[string]float64 `json:"requests"`
+	// Health of the dependency itself, only available for workloads
+	Health *WorkloadHealth `json:"health,omitempty"`
 }
 
 // WorkloadStatus gives
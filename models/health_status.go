@@ -0,0 +1,129 @@
+package models
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/log"
+)
+
+// HealthStatus is the outcome of the evaluation of a health, mirroring the statuses displayed in the UI
+type HealthStatus string
+
+const (
+	HealthStatusNA       HealthStatus = "NA"
+	HealthStatusHealthy  HealthStatus = "Healthy"
+	HealthStatusDegraded HealthStatus = "Degraded"
+	HealthStatusFailure  HealthStatus = "Failure"
+
+	// HealthStatusMaintenance suppresses the health of a workload intentionally down for maintenance. It has no
+	// priority: a workload in maintenance doesn't weigh in the worst status of its dependents.
+	HealthStatusMaintenance HealthStatus = "Maintenance"
+)
+
+var healthStatusPriority = map[HealthStatus]int{
+	HealthStatusNA:       0,
+	HealthStatusHealthy:  1,
+	HealthStatusDegraded: 2,
+	HealthStatusFailure:  3,
+}
+
+// WorstHealthStatus returns the most severe of the given statuses, NA when none is given
+func WorstHealthStatus(statuses ...HealthStatus) HealthStatus {
+	worst := HealthStatusNA
+	for _, s := range statuses {
+		if healthStatusPriority[s] > healthStatusPriority[worst] {
+			worst = s
+		}
+	}
+	return worst
+}
+
+// Status evaluates the replicas and the proxies of the workload
+func (ws *WorkloadStatus) Status() HealthStatus {
+	if ws == nil || (ws.DesiredReplicas == 0 && ws.AvailableReplicas == 0) {
+		return HealthStatusNA
+	}
+	if ws.AvailableReplicas == 0 {
+		return HealthStatusFailure
+	}
+	if ws.AvailableReplicas < ws.DesiredReplicas {
+		return HealthStatusDegraded
+	}
+	// SyncedProxies is -1 when the workload has no sidecar
+	if ws.SyncedProxies >= 0 && ws.SyncedProxies < ws.AvailableReplicas {
+		return HealthStatusDegraded
+	}
+	return HealthStatusHealthy
+}
+
+// RequestsStatus evaluates the error ratios of the requests (rates by protocol and code) against the
+// tolerances of the first health_config rate matching the object. Direction is either inbound or outbound.
+func RequestsStatus(requests map[string]map[string]float64, direction, namespace, kind, name string) HealthStatus {
+	tolerances := getRateTolerances(namespace, kind, name)
+	status := HealthStatusNA
+	for protocol, codes := range requests {
+		total := 0.0
+		for _, rate := range codes {
+			total += rate
+		}
+		if total == 0 {
+			continue
+		}
+		status = WorstHealthStatus(status, HealthStatusHealthy)
+		for _, tolerance := range tolerances {
+			if !matchesHealthRegex(tolerance.Protocol, protocol) || !matchesHealthRegex(tolerance.Direction, direction) {
+				continue
+			}
+			codeRegex, err := regexp.Compile(strings.NewReplacer("X", `\d`, "x", `\d`).Replace(tolerance.Code))
+			if err != nil {
+				log.Warningf("Invalid code [%s] in health tolerance: %v", tolerance.Code, err)
+				continue
+			}
+			errors := 0.0
+			for code, rate := range codes {
+				if codeRegex.MatchString(code) {
+					errors += rate
+				}
+			}
+			status = WorstHealthStatus(status, toleranceStatus(errors/total*100, tolerance))
+		}
+	}
+	return status
+}
+
+func toleranceStatus(errorRatio float64, tolerance config.Tolerance) HealthStatus {
+	if errorRatio <= 0 {
+		return HealthStatusHealthy
+	}
+	if tolerance.Failure > 0 && errorRatio >= float64(tolerance.Failure) {
+		return HealthStatusFailure
+	}
+	if errorRatio >= float64(tolerance.Degraded) {
+		return HealthStatusDegraded
+	}
+	return HealthStatusHealthy
+}
+
+func getRateTolerances(namespace, kind, name string) []config.Tolerance {
+	for _, rate := range config.Get().HealthConfig.Rate {
+		if matchesHealthRegex(rate.Namespace, namespace) && matchesHealthRegex(rate.Kind, kind) && matchesHealthRegex(rate.Name, name) {
+			return rate.Tolerance
+		}
+	}
+	return []config.Tolerance{}
+}
+
+// matchesHealthRegex matches the value against a health_config expression, an empty expression matches any value
+func matchesHealthRegex(expr, value string) bool {
+	if expr == "" {
+		return true
+	}
+	matched, err := regexp.MatchString(expr, value)
+	if err != nil {
+		log.Warningf("Invalid expression [%s] in health config: %v", expr, err)
+		return false
+	}
+	return matched
+}
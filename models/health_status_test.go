@@ -0,0 +1,70 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kiali/kiali/config"
+)
+
+func TestWorkloadStatusStatus(t *testing.T) {
+	assert := assert.New(t)
+
+	var noStatus *WorkloadStatus
+	assert.Equal(HealthStatusNA, noStatus.Status())
+	assert.Equal(HealthStatusNA, (&WorkloadStatus{DesiredReplicas: 0, AvailableReplicas: 0}).Status())
+	assert.Equal(HealthStatusFailure, (&WorkloadStatus{DesiredReplicas: 2, AvailableReplicas: 0}).Status())
+	assert.Equal(HealthStatusDegraded, (&WorkloadStatus{DesiredReplicas: 2, AvailableReplicas: 1, SyncedProxies: 1}).Status())
+	assert.Equal(HealthStatusDegraded, (&WorkloadStatus{DesiredReplicas: 2, AvailableReplicas: 2, SyncedProxies: 1}).Status())
+	assert.Equal(HealthStatusHealthy, (&WorkloadStatus{DesiredReplicas: 2, AvailableReplicas: 2, SyncedProxies: 2}).Status())
+	assert.Equal(HealthStatusHealthy, (&WorkloadStatus{DesiredReplicas: 2, AvailableReplicas: 2, SyncedProxies: -1}).Status())
+}
+
+func TestRequestsStatusDefaultTolerances(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	assert.Equal(HealthStatusNA, RequestsStatus(map[string]map[string]float64{}, "inbound", "ns", "workload", "reviews"))
+	assert.Equal(HealthStatusHealthy, RequestsStatus(map[string]map[string]float64{"http": {"200": 10}}, "inbound", "ns", "workload", "reviews"))
+	// Any 5XX error is degraded, failure from 10%
+	assert.Equal(HealthStatusDegraded, RequestsStatus(map[string]map[string]float64{"http": {"200": 99, "500": 1}}, "inbound", "ns", "workload", "reviews"))
+	assert.Equal(HealthStatusFailure, RequestsStatus(map[string]map[string]float64{"http": {"200": 9, "503": 1}}, "inbound", "ns", "workload", "reviews"))
+	// 4XX errors are degraded from 10%
+	assert.Equal(HealthStatusHealthy, RequestsStatus(map[string]map[string]float64{"http": {"200": 95, "404": 5}}, "outbound", "ns", "workload", "reviews"))
+	assert.Equal(HealthStatusDegraded, RequestsStatus(map[string]map[string]float64{"http": {"200": 85, "404": 15}}, "outbound", "ns", "workload", "reviews"))
+	// gRPC errors and requests without response
+	assert.Equal(HealthStatusFailure, RequestsStatus(map[string]map[string]float64{"grpc": {"0": 8, "14": 2}}, "inbound", "ns", "workload", "reviews"))
+	assert.Equal(HealthStatusFailure, RequestsStatus(map[string]map[string]float64{"http": {"200": 1, "-": 1}}, "inbound", "ns", "workload", "reviews"))
+}
+
+func TestRequestsStatusCustomTolerances(t *testing.T) {
+	assert := assert.New(t)
+	conf := config.NewConfig()
+	conf.HealthConfig.Rate = []config.Rate{
+		{
+			Namespace: "bookinfo",
+			Kind:      "workload",
+			Name:      "reviews-.*",
+			Tolerance: []config.Tolerance{
+				{Code: "5XX", Protocol: "http", Direction: "inbound", Degraded: 20, Failure: 50},
+			},
+		},
+	}
+	config.Set(conf)
+
+	requests := map[string]map[string]float64{"http": {"200": 70, "500": 30}}
+	assert.Equal(HealthStatusDegraded, RequestsStatus(requests, "inbound", "bookinfo", "workload", "reviews-v1"))
+	// The custom tolerances don't apply to outbound requests
+	assert.Equal(HealthStatusHealthy, RequestsStatus(requests, "outbound", "bookinfo", "workload", "reviews-v1"))
+	// Other objects get the default tolerances
+	assert.Equal(HealthStatusFailure, RequestsStatus(requests, "inbound", "bookinfo", "workload", "ratings-v1"))
+}
+
+func TestWorstHealthStatus(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal(HealthStatusNA, WorstHealthStatus())
+	assert.Equal(HealthStatusHealthy, WorstHealthStatus(HealthStatusNA, HealthStatusHealthy))
+	assert.Equal(HealthStatusFailure, WorstHealthStatus(HealthStatusFailure, HealthStatusDegraded, HealthStatusHealthy))
+}
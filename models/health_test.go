@@ -0,0 +1,41 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	core_v1 "k8s.io/api/core/v1"
+)
+
+func TestServiceProtocol(t *testing.T) {
+	assert := assert.New(t)
+	grpc := "grpc"
+
+	assert.Equal(HTTPProtocol, ServiceProtocol(nil))
+	assert.Equal(HTTPProtocol, ServiceProtocol([]core_v1.ServicePort{{Name: "web"}}))
+	assert.Equal(HTTPProtocol, ServiceProtocol([]core_v1.ServicePort{{Name: "tcp-metrics"}, {Name: "http2-api"}}))
+	assert.Equal(GRPCProtocol, ServiceProtocol([]core_v1.ServicePort{{Name: "http"}, {Name: "grpc-web"}}))
+	assert.Equal(GRPCProtocol, ServiceProtocol([]core_v1.ServicePort{{Name: "api", AppProtocol: &grpc}}))
+	assert.Equal(TCPProtocol, ServiceProtocol([]core_v1.ServicePort{{Name: "mongo"}, {Name: "https-admin"}}))
+}
+
+func TestServiceErrorRatio(t *testing.T) {
+	assert := assert.New(t)
+
+	health := EmptyServiceHealth()
+	health.ComputeErrorRatio()
+	assert.Nil(health.ErrorRatio)
+
+	health.Requests.Inbound = map[string]map[string]float64{"http": {"200": 3, "500": 1}, "grpc": {"0": 1, "14": 1}}
+	health.ComputeErrorRatio()
+	assert.InDelta(2.0/6, *health.ErrorRatio, 0.0001)
+
+	health.Protocol = GRPCProtocol
+	health.ComputeErrorRatio()
+	assert.Equal(0.5, *health.ErrorRatio)
+
+	health.Protocol = TCPProtocol
+	health.Connections = &ConnectionHealth{Opened: 5, Closed: 4, Failed: 1}
+	health.ComputeErrorRatio()
+	assert.Equal(0.25, *health.ErrorRatio)
+}
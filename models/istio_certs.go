@@ -0,0 +1,64 @@
+package models
+
+import "time"
+
+// Roles of the certificates in the chain of trust of the mesh
+const (
+	CertificateRoleRoot         = "root"
+	CertificateRoleIntermediate = "intermediate"
+	CertificateRoleWorkload     = "workload"
+)
+
+// CertificateChain is the chain of trust of the mesh, from the root certificate down to a sample workload
+// certificate. Each certificate must be issued by the previous one.
+// swagger:model certificateChain
+type CertificateChain struct {
+	// The certificates of the chain, from the root
+	// required: true
+	Links []CertificateLink `json:"links"`
+
+	// Whether every certificate is valid and issued by the previous one
+	// required: true
+	Valid bool `json:"valid"`
+
+	// Why the certificate of the requested workload could not be sampled
+	SampleError string `json:"sampleError,omitempty"`
+}
+
+// CertificateLink is a certificate of the chain of trust of the mesh
+type CertificateLink struct {
+	// The role of the certificate: root, intermediate or workload
+	// example: intermediate
+	// required: true
+	Role string `json:"role"`
+
+	// Where the certificate was read
+	// example: Secret istio-system/cacerts
+	// required: true
+	Source string `json:"source"`
+
+	// required: true
+	Subject string `json:"subject"`
+
+	// required: true
+	Issuer string `json:"issuer"`
+
+	// required: true
+	SerialNumber string `json:"serialNumber"`
+
+	// required: true
+	NotBefore time.Time `json:"notBefore"`
+
+	// required: true
+	NotAfter time.Time `json:"notAfter"`
+
+	// The DNS names and URIs (SPIFFE identities) of the certificate
+	SANs []string `json:"sans,omitempty"`
+
+	// Whether the certificate is expired or not yet valid
+	// required: true
+	Expired bool `json:"expired"`
+
+	// Why the certificate does not chain up to the previous one
+	Break string `json:"break,omitempty"`
+}
@@ -16,6 +16,7 @@ type IstioConfigList struct {
 	ServiceEntries         ServiceEntries         `json:"serviceEntries"`
 	WorkloadEntries        WorkloadEntries        `json:"workloadEntries"`
 	EnvoyFilters           EnvoyFilters           `json:"envoyFilters"`
+	ProxyConfigs           ProxyConfigResources   `json:"proxyConfigs"`
 	Sidecars               Sidecars               `json:"sidecars"`
 	AuthorizationPolicies  AuthorizationPolicies  `json:"authorizationPolicies"`
 	PeerAuthentications    PeerAuthentications    `json:"peerAuthentications"`
@@ -32,6 +33,7 @@ type IstioConfigDetails struct {
 	ServiceEntry          *ServiceEntry          `json:"serviceEntry"`
 	WorkloadEntry         *WorkloadEntry         `json:"workloadEntry"`
 	EnvoyFilter           *EnvoyFilter           `json:"envoyFilter"`
+	ProxyConfig           *ProxyConfigResource   `json:"proxyConfig"`
 	Sidecar               *Sidecar               `json:"sidecar"`
 	AuthorizationPolicy   *AuthorizationPolicy   `json:"authorizationPolicy"`
 	PeerAuthentication    *PeerAuthentication    `json:"peerAuthentication"`
@@ -53,3 +55,26 @@ type ResourcesPermissions map[string]*ResourcePermissions
 
 // IstioConfigPermissions holds a map of ResourcesPermissions per namespace
 type IstioConfigPermissions map[string]*ResourcesPermissions
+
+// IstioConfigRejection explains why the creation or the update of an Istio object was rejected by the cluster
+//
+// swagger:model istioConfigRejection
+type IstioConfigRejection struct {
+	// The error returned by the API server
+	// required: true
+	Error string `json:"error"`
+
+	// What rejected the object: webhook (a validating webhook, such as the one of Istio), admission (an admission
+	// policy or plugin) or schema (the validation of the resource definition)
+	// required: true
+	// example: webhook
+	Source string `json:"source"`
+
+	// The name of the validating webhook, when rejected by a webhook
+	// example: validation.istio.io
+	Webhook string `json:"webhook,omitempty"`
+
+	// The reasons of the rejection, to be displayed along the object
+	// required: true
+	Checks []*IstioCheck `json:"checks"`
+}
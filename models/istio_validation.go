@@ -2,6 +2,7 @@ package models
 
 import (
 	"encoding/json"
+	"fmt"
 )
 
 // NamespaceValidations represents a set of IstioValidations grouped by namespace
@@ -33,6 +34,41 @@ type IstioValidationSummary struct {
 // IstioValidations represents a set of IstioValidation grouped by IstioValidationKey.
 type IstioValidations map[IstioValidationKey]*IstioValidation
 
+// IstioValidationsFilter selects the validations of a set of Istio objects. Every criterion set must be matched.
+type IstioValidationsFilter struct {
+	// The singular types of the objects, any type when empty
+	ObjectTypes []string
+	// The name of the objects, any name when empty
+	Name string
+	// The minimum severity of the checks, any severity when empty
+	MinSeverity SeverityLevel
+}
+
+// FilteredIstioValidations is a set of Istio validations selected by a filter, with what was left out
+// swagger:model filteredIstioValidations
+type FilteredIstioValidations struct {
+	// The validations matching the filter, grouped by object type and name
+	// required: true
+	Validations IstioValidations `json:"validations"`
+
+	// What the filter left out
+	// required: true
+	FilteredOut IstioValidationsCount `json:"filteredOut"`
+}
+
+// IstioValidationsCount counts Istio objects and their checks
+type IstioValidationsCount struct {
+	// Number of Istio objects
+	// required: true
+	// example: 3
+	Objects int `json:"objects"`
+
+	// Number of checks
+	// required: true
+	// example: 5
+	Checks int `json:"checks"`
+}
+
 // IstioValidation represents a list of checks associated to an Istio object.
 // swagger:model
 type IstioValidation struct {
@@ -84,6 +120,24 @@ const (
 	Unknown         SeverityLevel = "unknown"
 )
 
+// severityRanks orders the severities, from the least to the most important
+var severityRanks = map[SeverityLevel]int{
+	Unknown:         0,
+	WarningSeverity: 1,
+	ErrorSeverity:   2,
+}
+
+// IsValidSeverity tells whether the severity is a known one
+func IsValidSeverity(severity SeverityLevel) bool {
+	_, found := severityRanks[severity]
+	return found
+}
+
+// AtLeast tells whether the severity is as important as the minimum severity
+func (s SeverityLevel) AtLeast(minSeverity SeverityLevel) bool {
+	return severityRanks[s] >= severityRanks[minSeverity]
+}
+
 var ObjectTypeSingular = map[string]string{
 	"gateways":               "gateway",
 	"virtualservices":        "virtualservice",
@@ -101,6 +155,9 @@ var ObjectTypeSingular = map[string]string{
 	"sidecars":               "sidecar",
 	"peerauthentications":    "peerauthentication",
 	"requestauthentications": "requestauthentication",
+	"proxyconfigs":           "proxyconfig",
+	"envoyfilters":           "envoyfilter",
+	"telemetries":            "telemetry",
 }
 
 var checkDescriptors = map[string]IstioCheck{
@@ -120,6 +177,26 @@ var checkDescriptors = map[string]IstioCheck{
 		Message:  "KIA0105 This field requires mTLS to be enabled",
 		Severity: ErrorSeverity,
 	},
+	"authorizationpolicy.source.anysource": {
+		Message:  "KIA0106 This rule allows any source to sensitive operations",
+		Severity: ErrorSeverity,
+	},
+	"authorizationpolicy.source.anyprincipal": {
+		Message:  "KIA0107 This rule allows any principal",
+		Severity: WarningSeverity,
+	},
+	"authorizationpolicy.source.anynamespace": {
+		Message:  "KIA0108 This rule allows any namespace",
+		Severity: WarningSeverity,
+	},
+	"authorizationpolicy.provider.notfound": {
+		Message:  "KIA0109 Extension provider not defined in the mesh config, the requests are denied",
+		Severity: ErrorSeverity,
+	},
+	"authorizationpolicy.provider.unreachable": {
+		Message:  "KIA0110 The service of the extension provider is not found in the mesh",
+		Severity: WarningSeverity,
+	},
 	"destinationrules.multimatch": {
 		Message:  "KIA0201 More than one DestinationRules for the same host subset combination",
 		Severity: WarningSeverity,
@@ -156,6 +233,18 @@ var checkDescriptors = map[string]IstioCheck{
 		Message:  "KIA0209 This subset has not labels",
 		Severity: WarningSeverity,
 	},
+	"envoyfilter.ratelimit.rlsmissing": {
+		Message:  "KIA1601 Global rate limit doesn't define the cluster of its rate limit service",
+		Severity: ErrorSeverity,
+	},
+	"envoyfilter.ratelimit.rlsnotfound": {
+		Message:  "KIA1602 Rate limit service cluster is not added by any EnvoyFilter nor generated by Istio for a service",
+		Severity: ErrorSeverity,
+	},
+	"envoyfilter.ratelimit.tokenbucketinvalid": {
+		Message:  "KIA1603 Local rate limit requires a token bucket with max_tokens and fill_interval",
+		Severity: ErrorSeverity,
+	},
 	"gateways.multimatch": {
 		Message:  "KIA0301 More than one Gateway for the same host port combination",
 		Severity: WarningSeverity,
@@ -164,6 +253,30 @@ var checkDescriptors = map[string]IstioCheck{
 		Message:  "KIA0302 No matching workload found for gateway selector in this namespace",
 		Severity: WarningSeverity,
 	},
+	"gateways.credential.notfound": {
+		Message:  "KIA0303 Secret of the credentialName not found in the namespace of the gateway workload",
+		Severity: ErrorSeverity,
+	},
+	"gateways.credential.invalid": {
+		Message:  "KIA0304 Secret of the credentialName has no valid certificate",
+		Severity: ErrorSeverity,
+	},
+	"gateways.credential.expired": {
+		Message:  "KIA0305 The certificate of the credentialName Secret is expired",
+		Severity: ErrorSeverity,
+	},
+	"gateways.credential.expiring": {
+		Message:  "KIA0306 The certificate of the credentialName Secret expires within 30 days",
+		Severity: WarningSeverity,
+	},
+	"gateways.credential.unreadable": {
+		Message:  "KIA0307 Unable to verify the Secret of the credentialName, the Secrets of the gateway workload namespace can't be read",
+		Severity: Unknown,
+	},
+	"gateways.credential.filemounted": {
+		Message:  "KIA0308 The certificate is mounted from a file of the gateway workload and can't be validated",
+		Severity: Unknown,
+	},
 	"generic.multimatch.selectorless": {
 		Message:  "KIA0002 More than one selector-less object in the same namespace",
 		Severity: ErrorSeverity,
@@ -192,14 +305,114 @@ var checkDescriptors = map[string]IstioCheck{
 		Message:  "KIA0506 Destination Rule disabling mesh-wide mTLS is missing",
 		Severity: ErrorSeverity,
 	},
+	"peerauthentications.mtls.overrides": {
+		Message:  "KIA0507 mTLS mode of this PeerAuthentication takes precedence over the one of a broader-scoped PeerAuthentication",
+		Severity: Unknown,
+	},
+	"peerauthentications.mtls.shadowed": {
+		Message:  "KIA0508 mTLS mode of this PeerAuthentication is overridden for some workloads by a narrower-scoped PeerAuthentication",
+		Severity: WarningSeverity,
+	},
 	"port.name.mismatch": {
 		Message:  "KIA0601 Port name must follow <protocol>[-suffix] form",
 		Severity: ErrorSeverity,
 	},
+	"port.appprotocol.unknown": {
+		Message:  "KIA0603 appProtocol is not a protocol supported by Istio, it is ignored",
+		Severity: WarningSeverity,
+	},
+	"port.protocol.traffic.mismatch": {
+		Message:  "KIA0604 Port declares HTTP or gRPC but the Service only receives TCP traffic, L7 features don't apply to it",
+		Severity: WarningSeverity,
+	},
+	"proxyconfigs.concurrency.invalid": {
+		Message:  "KIA1501 Concurrency must be a non-negative integer, 0 uses a worker thread per CPU core",
+		Severity: ErrorSeverity,
+	},
+	"proxyconfigs.concurrency.high": {
+		Message:  "KIA1502 Concurrency is unusually high, every worker thread of the proxy consumes CPU and memory",
+		Severity: WarningSeverity,
+	},
+	"proxyconfigs.image.invalidtype": {
+		Message:  "KIA1503 Image type must be one of default, debug or distroless",
+		Severity: ErrorSeverity,
+	},
+	"proxyconfigs.env.invalidname": {
+		Message:  "KIA1504 Environment variable name must consist of alphabetic characters, digits, '_', '-' or '.', and must not start with a digit",
+		Severity: ErrorSeverity,
+	},
+	"proxyconfigs.env.invalidvalue": {
+		Message:  "KIA1505 Environment variable value must be a string",
+		Severity: ErrorSeverity,
+	},
+	"proxyconfigs.multimatch.conflict": {
+		Message:  "KIA1506 More than one ProxyConfig applies to the same workloads with conflicting settings",
+		Severity: WarningSeverity,
+	},
+	"requestauthentications.jwt.issuermissing": {
+		Message:  "KIA1401 JWT rule is missing the issuer",
+		Severity: ErrorSeverity,
+	},
+	"requestauthentications.jwt.invalidissuer": {
+		Message:  "KIA1402 Issuer must be a valid URL or a string without whitespaces",
+		Severity: ErrorSeverity,
+	},
+	"requestauthentications.jwt.jwksmissing": {
+		Message:  "KIA1403 Neither jwks nor jwksUri are defined, the JWKS will be discovered from the issuer's OpenID configuration",
+		Severity: WarningSeverity,
+	},
+	"requestauthentications.jwt.invalidjwks": {
+		Message:  "KIA1404 Inline jwks is not a valid JSON Web Key Set",
+		Severity: ErrorSeverity,
+	},
+	"requestauthentications.jwt.invalidjwksuri": {
+		Message:  "KIA1405 jwksUri must be a valid HTTP or HTTPS URL",
+		Severity: ErrorSeverity,
+	},
+	"requestauthentications.jwt.unreachablejwksuri": {
+		Message:  "KIA1406 Unable to fetch a JSON Web Key Set from jwksUri",
+		Severity: WarningSeverity,
+	},
+	"requestauthentications.jwt.notenforced": {
+		Message:  "KIA1407 No AuthorizationPolicy requires a JWT, requests without a token are still allowed",
+		Severity: WarningSeverity,
+	},
 	"service.deployment.port.mismatch": {
 		Message:  "KIA0701 Deployment exposing same port as Service not found",
 		Severity: WarningSeverity,
 	},
+	"service.pods.notfound": {
+		Message:  "KIA0702 The selector of the Service matches no pods: the Service has no endpoints",
+		Severity: WarningSeverity,
+	},
+	"service.pods.notready": {
+		Message:  "KIA0703 None of the pods selected by the Service is ready: the Service has no ready endpoints",
+		Severity: WarningSeverity,
+	},
+	"serviceentries.sidecar.unreachable": {
+		Message:  "KIA1201 Sidecar egress configuration excludes all the hosts of this ServiceEntry in every namespace it is exported to",
+		Severity: WarningSeverity,
+	},
+	"serviceentries.host.shadowing.local": {
+		Message:  "KIA1202 Host shadows an in-mesh Service: the ServiceEntry wins the resolution in its own namespace",
+		Severity: WarningSeverity,
+	},
+	"serviceentries.host.shadowing.mesh": {
+		Message:  "KIA1203 Host shadows an in-mesh Service: the ServiceEntry, older, wins the resolution in the other namespaces both are exported to",
+		Severity: WarningSeverity,
+	},
+	"serviceentries.host.shadowed": {
+		Message:  "KIA1204 Host collides with an in-mesh Service, which wins the resolution in every namespace both are exported to",
+		Severity: WarningSeverity,
+	},
+	"serviceentries.host.duplicate": {
+		Message:  "KIA1205 Host is also declared by other ServiceEntries with a conflicting resolution, ports or endpoints: this ServiceEntry is likely to win the resolution in the namespaces they are exported to",
+		Severity: WarningSeverity,
+	},
+	"serviceentries.host.duplicate.ignored": {
+		Message:  "KIA1206 Host is also declared by other ServiceEntries with a conflicting resolution, ports or endpoints, which are likely to win the resolution in some of the namespaces they are exported to",
+		Severity: WarningSeverity,
+	},
 	"servicerole.invalid.services": {
 		Message:  "KIA0901 Unable to find all the defined services",
 		Severity: ErrorSeverity,
@@ -224,6 +437,14 @@ var checkDescriptors = map[string]IstioCheck{
 		Message:  "KIA1006 Global default sidecar should not have workloadSelector",
 		Severity: WarningSeverity,
 	},
+	"telemetry.provider.notfound": {
+		Message:  "KIA1701 Extension provider not defined in the mesh config",
+		Severity: ErrorSeverity,
+	},
+	"telemetry.provider.unreachable": {
+		Message:  "KIA1702 The service of the extension provider is not found in the mesh",
+		Severity: WarningSeverity,
+	},
 	"virtualservices.gateway.oldnomenclature": {
 		Message:  "KIA1108 Preferred nomenclature: <gateway namespace>/<gateway name>",
 		Severity: Unknown,
@@ -256,6 +477,34 @@ var checkDescriptors = map[string]IstioCheck{
 		Message:  "KIA1107 Subset not found",
 		Severity: WarningSeverity,
 	},
+	"workload.sidecar.injectiondisabled": {
+		Message:  "KIA1301 Sidecar injection is disabled for this workload in a namespace with injection enabled",
+		Severity: WarningSeverity,
+	},
+	"workload.sidecar.missing": {
+		Message:  "KIA1302 Pods are missing the sidecar, they were likely created before enabling the injection in the namespace and need to be restarted",
+		Severity: WarningSeverity,
+	},
+	"namespace.injection.revisionignored": {
+		Message:  "KIA1303 The namespace has both the istio-injection and istio.io/rev labels: istio-injection takes precedence, the sidecar of the default revision is injected and the revision is ignored",
+		Severity: WarningSeverity,
+	},
+	"namespace.injection.revisiondisabled": {
+		Message:  "KIA1304 The namespace has both the istio-injection and istio.io/rev labels: istio-injection takes precedence, no sidecar is injected and the revision is ignored",
+		Severity: WarningSeverity,
+	},
+	"pod.injection.optout": {
+		Message:  "KIA1305 sidecar.istio.io/inject=false takes precedence over the injection enabled in the namespace: the pod runs without sidecar",
+		Severity: Unknown,
+	},
+	"pod.injection.ignored": {
+		Message:  "KIA1306 sidecar.istio.io/inject=true is ignored: the injection disabled in the namespace takes precedence",
+		Severity: WarningSeverity,
+	},
+	"pod.injection.annotationonly": {
+		Message:  "KIA1307 The sidecar.istio.io/inject=true annotation is ignored in a namespace without injection label, only the label of the same name enables the injection",
+		Severity: WarningSeverity,
+	},
 	"validation.unable.cross-namespace": {
 		Message:  "KIA0001 Unable to verify the validity, cross-namespace validation is not supported for this field",
 		Severity: Unknown,
@@ -268,6 +517,13 @@ func Build(checkId string, path string) IstioCheck {
 	return check
 }
 
+// BuildWithDetail builds the check with a detail appended to its message, like the name of the missing reference
+func BuildWithDetail(checkId, path, detail string) IstioCheck {
+	check := Build(checkId, path)
+	check.Message = fmt.Sprintf("%s: %s", check.Message, detail)
+	return check
+}
+
 func BuildKey(objectType, name, namespace string) IstioValidationKey {
 	return IstioValidationKey{ObjectType: objectType, Namespace: namespace, Name: name}
 }
@@ -320,6 +576,39 @@ func (iv IstioValidations) FilterByTypes(objectTypes []string) IstioValidations
 	return fiv
 }
 
+// FilterBy returns the validations of the objects matching the filter, with only the checks of the minimum severity.
+// Objects without any such check are left out when a minimum severity is set. The validations are not modified.
+func (iv IstioValidations) FilterBy(filter IstioValidationsFilter) FilteredIstioValidations {
+	types := make(map[string]bool, len(filter.ObjectTypes))
+	for _, objectType := range filter.ObjectTypes {
+		types[objectType] = true
+	}
+	filtered := FilteredIstioValidations{Validations: IstioValidations{}}
+	for k, v := range iv {
+		matches := (len(types) == 0 || types[k.ObjectType]) && (filter.Name == "" || k.Name == filter.Name)
+		checks := v.Checks
+		if matches && filter.MinSeverity != "" {
+			checks = make([]*IstioCheck, 0, len(v.Checks))
+			for _, c := range v.Checks {
+				if c.Severity.AtLeast(filter.MinSeverity) {
+					checks = append(checks, c)
+				}
+			}
+			matches = len(checks) > 0
+		}
+		if !matches {
+			filtered.FilteredOut.Objects++
+			filtered.FilteredOut.Checks += len(v.Checks)
+			continue
+		}
+		filtered.FilteredOut.Checks += len(v.Checks) - len(checks)
+		validation := *v
+		validation.Checks = checks
+		filtered.Validations[k] = &validation
+	}
+	return filtered
+}
+
 func (iv IstioValidations) MergeValidations(validations IstioValidations) IstioValidations {
 	for key, validation := range validations {
 		v, ok := iv[key]
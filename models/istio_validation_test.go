@@ -69,3 +69,93 @@ func TestSummarizeValidations(t *testing.T) {
 	assert.Equal(2, summary.Errors)
 	assert.Equal(2, summary.Errors)
 }
+
+func TestIstioValidationsFilterByType(t *testing.T) {
+	assert := assert.New(t)
+
+	filtered := fakeFilterValidations().FilterBy(IstioValidationsFilter{ObjectTypes: []string{"virtualservice", "gateway"}})
+	assert.Len(filtered.Validations, 3)
+	assert.NotContains(filtered.Validations, BuildKey("destinationrule", "reviews", "bookinfo"))
+	assert.Equal(IstioValidationsCount{Objects: 1, Checks: 2}, filtered.FilteredOut)
+}
+
+func TestIstioValidationsFilterByName(t *testing.T) {
+	assert := assert.New(t)
+
+	filtered := fakeFilterValidations().FilterBy(IstioValidationsFilter{Name: "reviews"})
+	assert.Len(filtered.Validations, 2)
+	assert.Contains(filtered.Validations, BuildKey("virtualservice", "reviews", "bookinfo"))
+	assert.Contains(filtered.Validations, BuildKey("destinationrule", "reviews", "bookinfo"))
+	assert.Equal(IstioValidationsCount{Objects: 2, Checks: 2}, filtered.FilteredOut)
+}
+
+func TestIstioValidationsFilterBySeverity(t *testing.T) {
+	assert := assert.New(t)
+
+	validations := fakeFilterValidations()
+	filtered := validations.FilterBy(IstioValidationsFilter{MinSeverity: ErrorSeverity})
+	assert.Len(filtered.Validations, 2)
+	assert.Len(filtered.Validations[BuildKey("destinationrule", "reviews", "bookinfo")].Checks, 1)
+	assert.Equal(ErrorSeverity, filtered.Validations[BuildKey("destinationrule", "reviews", "bookinfo")].Checks[0].Severity)
+	// The valid gateway and the virtualservice with a warning only are left out, as well as the other warnings
+	assert.Equal(IstioValidationsCount{Objects: 2, Checks: 3}, filtered.FilteredOut)
+	// The filtered validations are not modified
+	assert.Len(validations[BuildKey("destinationrule", "reviews", "bookinfo")].Checks, 2)
+
+	filtered = validations.FilterBy(IstioValidationsFilter{MinSeverity: WarningSeverity})
+	assert.Len(filtered.Validations, 3)
+	assert.Equal(IstioValidationsCount{Objects: 1, Checks: 0}, filtered.FilteredOut)
+
+	filtered = validations.FilterBy(IstioValidationsFilter{MinSeverity: Unknown})
+	assert.Len(filtered.Validations, 3)
+	assert.Equal(IstioValidationsCount{Objects: 1, Checks: 0}, filtered.FilteredOut)
+}
+
+func TestIstioValidationsFilterCombined(t *testing.T) {
+	assert := assert.New(t)
+
+	filtered := fakeFilterValidations().FilterBy(IstioValidationsFilter{
+		ObjectTypes: []string{"virtualservice"},
+		Name:        "reviews",
+		MinSeverity: ErrorSeverity,
+	})
+	assert.Empty(filtered.Validations)
+	assert.Equal(IstioValidationsCount{Objects: 4, Checks: 5}, filtered.FilteredOut)
+
+	filtered = fakeFilterValidations().FilterBy(IstioValidationsFilter{})
+	assert.Len(filtered.Validations, 4)
+	assert.Equal(IstioValidationsCount{}, filtered.FilteredOut)
+}
+
+func fakeFilterValidations() IstioValidations {
+	check := func(checkId string) *IstioCheck {
+		c := Build(checkId, "")
+		return &c
+	}
+	return IstioValidations{
+		BuildKey("virtualservice", "reviews", "bookinfo"): &IstioValidation{
+			Name:       "reviews",
+			ObjectType: "virtualservice",
+			Valid:      true,
+			Checks:     []*IstioCheck{check("virtualservices.route.repeatedsubset")},
+		},
+		BuildKey("virtualservice", "ratings", "bookinfo"): &IstioValidation{
+			Name:       "ratings",
+			ObjectType: "virtualservice",
+			Valid:      false,
+			Checks:     []*IstioCheck{check("virtualservices.nohost.hostnotfound"), check("virtualservices.singlehost")},
+		},
+		BuildKey("destinationrule", "reviews", "bookinfo"): &IstioValidation{
+			Name:       "reviews",
+			ObjectType: "destinationrule",
+			Valid:      false,
+			Checks:     []*IstioCheck{check("destinationrules.nodest.subsetlabels"), check("destinationrules.multimatch")},
+		},
+		BuildKey("gateway", "bookinfo-gateway", "bookinfo"): &IstioValidation{
+			Name:       "bookinfo-gateway",
+			ObjectType: "gateway",
+			Valid:      true,
+			Checks:     []*IstioCheck{},
+		},
+	}
+}
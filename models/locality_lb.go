@@ -0,0 +1,178 @@
+package models
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/kiali/kiali/kubernetes"
+)
+
+// LocalityLoadBalancing describes the locality load balancing configured for a service
+// together with the localities where the endpoints of the service run.
+type LocalityLoadBalancing struct {
+	// Namespace of the service
+	// required: true
+	Namespace string `json:"namespace"`
+
+	// Name of the service
+	// required: true
+	Service string `json:"service"`
+
+	// Name of the DestinationRule defining the localityLbSetting, empty when there is none
+	DestinationRule string `json:"destinationRule"`
+
+	// Enabled is only set when the localityLbSetting sets it explicitly
+	Enabled *bool `json:"enabled,omitempty"`
+
+	// Weighted distribution of the traffic originated in a locality
+	Distribute []LocalityDistribute `json:"distribute"`
+
+	// Region to send the traffic to when the endpoints of a region are unhealthy
+	Failover []LocalityFailover `json:"failover"`
+
+	// Number of endpoints per locality (region/zone)
+	EndpointLocalities map[string]int `json:"endpointLocalities"`
+
+	// Destination localities referenced by the config without any endpoint
+	UnmatchedLocalities []string `json:"unmatchedLocalities"`
+}
+
+// LocalityDistribute holds the weight per destination locality of the traffic originated in From
+type LocalityDistribute struct {
+	From string            `json:"from"`
+	To   map[string]uint32 `json:"to"`
+}
+
+// LocalityFailover holds the region used when the endpoints of the From region are unhealthy
+type LocalityFailover struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// ParseDestinationRule reads the localityLbSetting of the DestinationRule traffic policy.
+// It returns false when the DestinationRule doesn't define any localityLbSetting.
+func (lb *LocalityLoadBalancing) ParseDestinationRule(dr kubernetes.IstioObject) bool {
+	setting := getLocalityLbSetting(dr)
+	if setting == nil {
+		return false
+	}
+
+	lb.DestinationRule = dr.GetObjectMeta().Name
+	if enabled, ok := setting["enabled"].(bool); ok {
+		lb.Enabled = &enabled
+	}
+	if distribute, ok := setting["distribute"].([]interface{}); ok {
+		for _, d := range distribute {
+			if dCasted, ok := d.(map[string]interface{}); ok {
+				ld := LocalityDistribute{To: map[string]uint32{}}
+				ld.From, _ = dCasted["from"].(string)
+				if to, ok := dCasted["to"].(map[string]interface{}); ok {
+					for locality, weight := range to {
+						ld.To[locality] = castWeight(weight)
+					}
+				}
+				lb.Distribute = append(lb.Distribute, ld)
+			}
+		}
+	}
+	if failover, ok := setting["failover"].([]interface{}); ok {
+		for _, f := range failover {
+			if fCasted, ok := f.(map[string]interface{}); ok {
+				lf := LocalityFailover{}
+				lf.From, _ = fCasted["from"].(string)
+				lf.To, _ = fCasted["to"].(string)
+				lb.Failover = append(lb.Failover, lf)
+			}
+		}
+	}
+	return true
+}
+
+// SetEndpoints counts the endpoints per locality and flags the destination localities without endpoints.
+// Pods must have their node topology set, pods without region are not placed in any locality.
+func (lb *LocalityLoadBalancing) SetEndpoints(pods Pods) {
+	lb.EndpointLocalities = map[string]int{}
+	for _, pod := range pods {
+		if pod.Region == "" {
+			continue
+		}
+		lb.EndpointLocalities[pod.Region+"/"+pod.Zone]++
+	}
+
+	unmatched := map[string]bool{}
+	for _, d := range lb.Distribute {
+		for locality := range d.To {
+			if !lb.hasEndpointsIn(locality) {
+				unmatched[locality] = true
+			}
+		}
+	}
+	for _, f := range lb.Failover {
+		if f.To != "" && !lb.hasEndpointsIn(f.To) {
+			unmatched[f.To] = true
+		}
+	}
+
+	lb.UnmatchedLocalities = make([]string, 0, len(unmatched))
+	for locality := range unmatched {
+		lb.UnmatchedLocalities = append(lb.UnmatchedLocalities, locality)
+	}
+	sort.Strings(lb.UnmatchedLocalities)
+}
+
+func (lb *LocalityLoadBalancing) hasEndpointsIn(locality string) bool {
+	for endpointLocality := range lb.EndpointLocalities {
+		if LocalityMatches(locality, endpointLocality) {
+			return true
+		}
+	}
+	return false
+}
+
+// LocalityMatches returns true when the region/zone/subzone pattern matches the region/zone locality.
+// Pattern segments may be "*" and missing segments match anything. Subzones are not compared,
+// as the endpoint localities are only known up to the zone.
+func LocalityMatches(pattern, locality string) bool {
+	patternSegments := strings.Split(pattern, "/")
+	localitySegments := strings.Split(locality, "/")
+	for i := 0; i < len(patternSegments) && i < 2; i++ {
+		if patternSegments[i] == "*" {
+			return true
+		}
+		if i >= len(localitySegments) || patternSegments[i] != localitySegments[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func getLocalityLbSetting(dr kubernetes.IstioObject) map[string]interface{} {
+	if trafficPolicy, trafficPresent := dr.GetSpec()["trafficPolicy"]; trafficPresent {
+		if trafficCasted, ok := trafficPolicy.(map[string]interface{}); ok {
+			if loadBalancer, found := trafficCasted["loadBalancer"]; found {
+				if lbCasted, ok := loadBalancer.(map[string]interface{}); ok {
+					if setting, found := lbCasted["localityLbSetting"]; found {
+						if settingCasted, ok := setting.(map[string]interface{}); ok {
+							return settingCasted
+						}
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func castWeight(weight interface{}) uint32 {
+	switch w := weight.(type) {
+	case float64:
+		return uint32(w)
+	case int:
+		return uint32(w)
+	case int64:
+		return uint32(w)
+	case uint64:
+		return uint32(w)
+	}
+	return 0
+}
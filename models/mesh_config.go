@@ -0,0 +1,270 @@
+package models
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/yaml"
+)
+
+// MeshConfig is the effective mesh configuration: the mesh config of the istio ConfigMap
+// with the defaults applied by Istio for the fields not set.
+type MeshConfig struct {
+	// Encoding of the proxy access logs, TEXT or JSON
+	// example: TEXT
+	AccessLogEncoding string `json:"accessLogEncoding"`
+
+	// File where the proxies write the access logs, access logs are disabled when empty
+	// example: /dev/stdout
+	AccessLogFile string `json:"accessLogFile"`
+
+	// Format of the proxy access logs, the Envoy default format is used when empty
+	AccessLogFormat string `json:"accessLogFormat"`
+
+	// Connection timeout used by Envoy to connect to the upstream hosts
+	// example: 10s
+	ConnectTimeout string `json:"connectTimeout"`
+
+	// Default proxy configuration of the workloads, it can be overridden per workload
+	DefaultConfig ProxyConfig `json:"defaultConfig"`
+
+	// Refresh rate of the DNS resolution of the STRICT_DNS clusters
+	// example: 5s
+	DnsRefreshRate string `json:"dnsRefreshRate"`
+
+	// Enable auto mTLS, mTLS is used for the destinations with sidecar when no DestinationRule sets the TLS mode
+	// example: true
+	EnableAutoMtls bool `json:"enableAutoMtls"`
+
+	// Merge the application metrics with the Istio metrics in the metrics endpoint of the proxy
+	// example: true
+	EnablePrometheusMerge bool `json:"enablePrometheusMerge"`
+
+	// Enable the tracing of the requests in the proxies
+	// example: true
+	EnableTracing bool `json:"enableTracing"`
+
+	// Class of the Kubernetes ingresses handled by Istio
+	// example: istio
+	IngressClass string `json:"ingressClass"`
+
+	// Which Kubernetes ingresses are handled by Istio: OFF, STRICT or DEFAULT
+	// example: STRICT
+	IngressControllerMode string `json:"ingressControllerMode"`
+
+	// Name of the Kubernetes service of the ingress gateway handling the Kubernetes ingresses
+	// example: istio-ingressgateway
+	IngressService string `json:"ingressService"`
+
+	// Locality load balancing settings of the mesh
+	LocalityLbSetting map[string]interface{} `json:"localityLbSetting,omitempty"`
+
+	// Policy for the traffic to hosts out of the service registry
+	OutboundTrafficPolicy OutboundTrafficPolicy `json:"outboundTrafficPolicy"`
+
+	// Namespace of the config that applies to all the namespaces of the mesh
+	// example: istio-system
+	RootNamespace string `json:"rootNamespace"`
+
+	// Trust domain of the workload identities
+	// example: cluster.local
+	TrustDomain string `json:"trustDomain"`
+
+	// Other trust domains accepted as the trust domain of the mesh
+	TrustDomainAliases []string `json:"trustDomainAliases,omitempty"`
+
+	// Fields not modeled by Kiali, such as the fields of newer Istio versions, as they are in the mesh config
+	Extra map[string]interface{} `json:"extra,omitempty"`
+}
+
+// ProxyConfig holds the configuration of the proxies
+type ProxyConfig struct {
+	// Path of the Envoy binary
+	// example: /usr/local/bin/envoy
+	BinaryPath string `json:"binaryPath"`
+
+	// Directory of the generated Envoy configuration
+	// example: ./etc/istio/proxy
+	ConfigPath string `json:"configPath"`
+
+	// Number of worker threads of the proxies, 0 uses a thread per CPU core
+	// example: 2
+	Concurrency int `json:"concurrency"`
+
+	// Address of the discovery service (istiod)
+	// example: istiod.istio-system.svc:15012
+	DiscoveryAddress string `json:"discoveryAddress"`
+
+	// Time the proxies wait for the connections to drain on hot restarts
+	// example: 45s
+	DrainDuration string `json:"drainDuration"`
+
+	// Time the parent process waits before shutting down on hot restarts
+	// example: 60s
+	ParentShutdownDuration string `json:"parentShutdownDuration"`
+
+	// Port of the Envoy admin interface
+	// example: 15000
+	ProxyAdminPort int `json:"proxyAdminPort"`
+
+	// Additional environment variables set in the proxies
+	ProxyMetadata map[string]string `json:"proxyMetadata,omitempty"`
+
+	// Cluster reported by the proxies, used in tracing
+	// example: istio-proxy
+	ServiceCluster string `json:"serviceCluster"`
+
+	// Port of the readiness and status endpoints of the proxies
+	// example: 15020
+	StatusPort int `json:"statusPort"`
+
+	// Time the proxies wait for the connections to drain when the pod is terminated
+	// example: 5s
+	TerminationDrainDuration string `json:"terminationDrainDuration"`
+
+	// Tracer configuration of the proxies
+	Tracing map[string]interface{} `json:"tracing,omitempty"`
+
+	// Fields not modeled by Kiali, as they are in the proxy config
+	Extra map[string]interface{} `json:"extra,omitempty"`
+}
+
+// OutboundTrafficPolicy sets how the traffic to hosts out of the service registry is handled
+type OutboundTrafficPolicy struct {
+	// ALLOW_ANY lets the traffic go through, REGISTRY_ONLY blocks it
+	// example: ALLOW_ANY
+	Mode string `json:"mode"`
+}
+
+// DefaultMeshConfig returns the mesh config defaults applied by Istio, as in the mesh config YAML
+func DefaultMeshConfig(istioNamespace string) map[string]interface{} {
+	return map[string]interface{}{
+		"accessLogEncoding": "TEXT",
+		"accessLogFile":     "",
+		"accessLogFormat":   "",
+		"connectTimeout":    "10s",
+		"defaultConfig": map[string]interface{}{
+			"binaryPath":               "/usr/local/bin/envoy",
+			"concurrency":              2,
+			"configPath":               "./etc/istio/proxy",
+			"discoveryAddress":         "istiod." + istioNamespace + ".svc:15012",
+			"drainDuration":            "45s",
+			"parentShutdownDuration":   "60s",
+			"proxyAdminPort":           15000,
+			"serviceCluster":           "istio-proxy",
+			"statusPort":               15020,
+			"terminationDrainDuration": "5s",
+			"tracing": map[string]interface{}{
+				"zipkin": map[string]interface{}{
+					"address": "zipkin." + istioNamespace + ":9411",
+				},
+			},
+		},
+		"dnsRefreshRate":        "5s",
+		"enableAutoMtls":        true,
+		"enablePrometheusMerge": true,
+		"enableTracing":         true,
+		"ingressClass":          "istio",
+		"ingressControllerMode": "STRICT",
+		"ingressService":        "istio-ingressgateway",
+		"outboundTrafficPolicy": map[string]interface{}{
+			"mode": "ALLOW_ANY",
+		},
+		"rootNamespace": istioNamespace,
+		"trustDomain":   "cluster.local",
+	}
+}
+
+// ParseMeshConfig parses the mesh config YAML and applies the defaults to the fields not set.
+// The fields not modeled are kept in the Extra fields.
+func ParseMeshConfig(meshConfigYaml string, defaults map[string]interface{}) (*MeshConfig, error) {
+	meshConfigJson, err := yaml.ToJSON([]byte(meshConfigYaml))
+	if err != nil {
+		return nil, err
+	}
+	values := map[string]interface{}{}
+	// An empty mesh config is null
+	if err = json.Unmarshal(meshConfigJson, &values); err != nil {
+		return nil, err
+	}
+	// Defaults go through JSON to get the same types as the parsed values
+	defaultsJson, err := json.Marshal(defaults)
+	if err != nil {
+		return nil, err
+	}
+	merged := map[string]interface{}{}
+	if err = json.Unmarshal(defaultsJson, &merged); err != nil {
+		return nil, err
+	}
+	mergeMeshConfigValues(merged, values, "")
+
+	mergedJson, err := json.Marshal(merged)
+	if err != nil {
+		return nil, err
+	}
+	meshConfig := &MeshConfig{}
+	if err = json.Unmarshal(mergedJson, meshConfig); err != nil {
+		return nil, err
+	}
+	meshConfig.Extra = extraFields(merged, meshConfig)
+	if defaultConfig, ok := merged["defaultConfig"].(map[string]interface{}); ok {
+		meshConfig.DefaultConfig.Extra = extraFields(defaultConfig, &meshConfig.DefaultConfig)
+	}
+	return meshConfig, nil
+}
+
+// meshConfigOneofs are the oneofs of the mesh config, by path. A member of a oneof set in the values replaces the
+// members of the defaults as a whole, e.g. a zipkin tracer in the defaults must not be kept next to a datadog tracer.
+var meshConfigOneofs = map[string][]string{
+	"defaultConfig.tracing": {"zipkin", "lightstep", "datadog", "stackdriver", "openCensusAgent"},
+}
+
+// mergeMeshConfigValues sets the values in the defaults, nested maps are merged unless they are members of a oneof
+func mergeMeshConfigValues(defaults, values map[string]interface{}, path string) {
+	oneof := map[string]bool{}
+	for _, member := range meshConfigOneofs[path] {
+		oneof[member] = true
+	}
+	for k := range values {
+		if oneof[k] {
+			for member := range oneof {
+				delete(defaults, member)
+			}
+			break
+		}
+	}
+
+	for k, v := range values {
+		vPath := k
+		if path != "" {
+			vPath = path + "." + k
+		}
+		vMap, vIsMap := v.(map[string]interface{})
+		dMap, dIsMap := defaults[k].(map[string]interface{})
+		if vIsMap && dIsMap {
+			mergeMeshConfigValues(dMap, vMap, vPath)
+		} else {
+			defaults[k] = v
+		}
+	}
+}
+
+// extraFields returns the values not mapped to any field of the model
+func extraFields(values map[string]interface{}, model interface{}) map[string]interface{} {
+	known := map[string]bool{}
+	t := reflect.TypeOf(model).Elem()
+	for i := 0; i < t.NumField(); i++ {
+		known[strings.Split(t.Field(i).Tag.Get("json"), ",")[0]] = true
+	}
+	var extra map[string]interface{}
+	for k, v := range values {
+		if !known[k] || k == "extra" {
+			if extra == nil {
+				extra = map[string]interface{}{}
+			}
+			extra[k] = v
+		}
+	}
+	return extra
+}
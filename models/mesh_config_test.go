@@ -0,0 +1,113 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Mesh config of the istio ConfigMap of a default Istio 1.8 installation, with some customizations
+const sampleMeshConfig = `
+accessLogFile: /dev/stdout
+defaultConfig:
+  discoveryAddress: istiod.istio-system.svc:15012
+  proxyMetadata:
+    ISTIO_META_DNS_CAPTURE: "true"
+  tracing:
+    sampling: 10
+    zipkin:
+      address: zipkin.istio-system:9411
+  holdApplicationUntilProxyStarts: true
+enablePrometheusMerge: true
+outboundTrafficPolicy:
+  mode: REGISTRY_ONLY
+rootNamespace: istio-system
+trustDomain: cluster.local
+trustDomainAliases:
+- old.domain
+discoverySelectors:
+- matchLabels:
+    env: prod
+`
+
+func TestParseMeshConfig(t *testing.T) {
+	assert := assert.New(t)
+
+	mc, err := ParseMeshConfig(sampleMeshConfig, DefaultMeshConfig("istio-system"))
+	assert.NoError(err)
+
+	// Values set in the ConfigMap
+	assert.Equal("/dev/stdout", mc.AccessLogFile)
+	assert.Equal("REGISTRY_ONLY", mc.OutboundTrafficPolicy.Mode)
+	assert.Equal([]string{"old.domain"}, mc.TrustDomainAliases)
+	assert.Equal(map[string]string{"ISTIO_META_DNS_CAPTURE": "true"}, mc.DefaultConfig.ProxyMetadata)
+
+	// Defaults applied by Istio
+	assert.Equal("TEXT", mc.AccessLogEncoding)
+	assert.Equal("10s", mc.ConnectTimeout)
+	assert.True(mc.EnableAutoMtls)
+	assert.True(mc.EnableTracing)
+	assert.Equal("STRICT", mc.IngressControllerMode)
+	assert.Equal(2, mc.DefaultConfig.Concurrency)
+	assert.Equal(15020, mc.DefaultConfig.StatusPort)
+	assert.Equal("45s", mc.DefaultConfig.DrainDuration)
+
+	// Nested maps are merged with the defaults
+	assert.Equal(float64(10), mc.DefaultConfig.Tracing["sampling"])
+	assert.Equal(map[string]interface{}{"address": "zipkin.istio-system:9411"}, mc.DefaultConfig.Tracing["zipkin"])
+
+	// Unknown fields are passed through
+	assert.Equal(map[string]interface{}{
+		"discoverySelectors": []interface{}{
+			map[string]interface{}{"matchLabels": map[string]interface{}{"env": "prod"}},
+		},
+	}, mc.Extra)
+	assert.Equal(map[string]interface{}{"holdApplicationUntilProxyStarts": true}, mc.DefaultConfig.Extra)
+}
+
+func TestParseMeshConfigOverridesDefaults(t *testing.T) {
+	assert := assert.New(t)
+
+	mc, err := ParseMeshConfig("enableAutoMtls: false\ndefaultConfig:\n  concurrency: 0\n", DefaultMeshConfig("istio-system"))
+	assert.NoError(err)
+	// Zero values set explicitly are kept
+	assert.False(mc.EnableAutoMtls)
+	assert.Equal(0, mc.DefaultConfig.Concurrency)
+	assert.Equal("istiod.istio-system.svc:15012", mc.DefaultConfig.DiscoveryAddress)
+	assert.Nil(mc.Extra)
+	assert.Nil(mc.DefaultConfig.Extra)
+}
+
+func TestParseMeshConfigReplacesTracer(t *testing.T) {
+	assert := assert.New(t)
+
+	mc, err := ParseMeshConfig("defaultConfig:\n  tracing:\n    sampling: 10\n    datadog:\n      address: datadog:8126\n", DefaultMeshConfig("istio-system"))
+	assert.NoError(err)
+	// The default zipkin tracer is not kept next to the datadog one
+	assert.Equal(map[string]interface{}{
+		"sampling": 10.0,
+		"datadog":  map[string]interface{}{"address": "datadog:8126"},
+	}, mc.DefaultConfig.Tracing)
+	assert.Equal("istiod.istio-system.svc:15012", mc.DefaultConfig.DiscoveryAddress)
+
+	// Without tracer, the default one is kept
+	mc, err = ParseMeshConfig("defaultConfig:\n  tracing:\n    sampling: 10\n", DefaultMeshConfig("istio-system"))
+	assert.NoError(err)
+	assert.Equal(map[string]interface{}{
+		"sampling": 10.0,
+		"zipkin":   map[string]interface{}{"address": "zipkin.istio-system:9411"},
+	}, mc.DefaultConfig.Tracing)
+}
+
+func TestParseEmptyMeshConfig(t *testing.T) {
+	assert := assert.New(t)
+
+	mc, err := ParseMeshConfig("", DefaultMeshConfig("istio-control"))
+	assert.NoError(err)
+	assert.Equal("istio-control", mc.RootNamespace)
+	assert.Equal("istiod.istio-control.svc:15012", mc.DefaultConfig.DiscoveryAddress)
+	assert.Equal("ALLOW_ANY", mc.OutboundTrafficPolicy.Mode)
+
+	_, err = ParseMeshConfig("enableTracing: [", DefaultMeshConfig("istio-system"))
+	assert.Error(err)
+}
@@ -0,0 +1,122 @@
+package models
+
+import (
+	"sort"
+	"time"
+)
+
+// MeshSnapshot is the posture of the mesh at a point in time: the health, the mTLS status and the validations of
+// every accessible namespace. It's meant to be committed and diffed: the namespaces and the apps are sorted by name
+// and the snapshot time is the only time it holds.
+// swagger:model meshSnapshot
+type MeshSnapshot struct {
+	// The time the snapshot was taken, in UTC
+	// required: true
+	Time time.Time `json:"time"`
+
+	// The rate interval of the traffic evaluated by the health
+	// required: true
+	// example: 10m
+	RateInterval string `json:"rateInterval"`
+
+	// The namespaces, sorted by name
+	// required: true
+	Namespaces []NamespaceSnapshot `json:"namespaces"`
+}
+
+// NamespaceSnapshot is the posture of a namespace in a MeshSnapshot
+type NamespaceSnapshot struct {
+	// required: true
+	// example: bookinfo
+	Name string `json:"name"`
+
+	// required: true
+	Health HealthSummary `json:"health"`
+
+	// Namespace-wide mTLS status
+	// required: true
+	// example: MTLS_ENABLED
+	TLSStatus string `json:"tlsStatus"`
+
+	// Number of errors and warnings of the Istio objects of the namespace
+	// required: true
+	Validations IstioValidationSummary `json:"validations"`
+
+	// Errors per section of the NamespaceOverview which couldn't be computed
+	Errors map[string]string `json:"errors,omitempty"`
+}
+
+// HealthSummary is the number of apps per health status, with the status of each app
+type HealthSummary struct {
+	// required: true
+	Healthy int `json:"healthy"`
+
+	// required: true
+	Degraded int `json:"degraded"`
+
+	// required: true
+	Failure int `json:"failure"`
+
+	// Number of apps without workload replicas nor traffic to evaluate
+	// required: true
+	NA int `json:"na"`
+
+	// The status of the apps, sorted by name
+	// required: true
+	Apps []AppHealthStatus `json:"apps"`
+}
+
+// AppHealthStatus is the health status of an app
+type AppHealthStatus struct {
+	// required: true
+	// example: reviews
+	Name string `json:"name"`
+
+	// required: true
+	// example: Healthy
+	Status HealthStatus `json:"status"`
+}
+
+// NewNamespaceSnapshot summarizes the overview of a namespace. The status of an app is the worst status of its
+// workloads and of its inbound and outbound requests.
+func NewNamespaceSnapshot(overview NamespaceOverview) NamespaceSnapshot {
+	namespace := overview.Namespace.Name
+	snapshot := NamespaceSnapshot{
+		Name: namespace,
+		Health: HealthSummary{
+			Apps: []AppHealthStatus{},
+		},
+		TLSStatus:   overview.TLSStatus.Status,
+		Validations: overview.Validations,
+		Errors:      overview.Errors,
+	}
+
+	for app, health := range overview.AppHealth {
+		statuses := []HealthStatus{}
+		if health != nil {
+			for _, ws := range health.WorkloadStatuses {
+				statuses = append(statuses, ws.Status())
+			}
+			statuses = append(statuses,
+				RequestsStatus(health.Requests.Inbound, "inbound", namespace, "app", app),
+				RequestsStatus(health.Requests.Outbound, "outbound", namespace, "app", app))
+		}
+		status := WorstHealthStatus(statuses...)
+		switch status {
+		case HealthStatusHealthy:
+			snapshot.Health.Healthy++
+		case HealthStatusDegraded:
+			snapshot.Health.Degraded++
+		case HealthStatusFailure:
+			snapshot.Health.Failure++
+		default:
+			snapshot.Health.NA++
+		}
+		snapshot.Health.Apps = append(snapshot.Health.Apps, AppHealthStatus{Name: app, Status: status})
+	}
+	sort.Slice(snapshot.Health.Apps, func(i, j int) bool {
+		return snapshot.Health.Apps[i].Name < snapshot.Health.Apps[j].Name
+	})
+
+	return snapshot
+}
@@ -28,6 +28,7 @@ type IstioMetricsQuery struct {
 	Reporter        string // source | destination, defaults to source if not provided
 	Aggregate       string
 	AggregateValue  string
+	Exemplars       bool // attach the exemplars of the histograms, linking them to traces
 }
 
 // FillDefaults fills the struct with default parameters
@@ -52,6 +53,18 @@ func (q *CustomMetricsQuery) FillDefaults() {
 	q.RawDataAggregator = "sum"
 }
 
+// ValidateOffset checks that the offset of a time-shifted query is a positive Prometheus duration (e.g. 7d)
+func ValidateOffset(offset string) error {
+	d, err := pmod.ParseDuration(offset)
+	if err != nil {
+		return fmt.Errorf("bad request: cannot parse offset '%s', Prometheus duration expected (e.g. 7d)", offset)
+	}
+	if d <= 0 {
+		return fmt.Errorf("bad request: offset '%s' must be positive", offset)
+	}
+	return nil
+}
+
 type MetricsStatsQueries struct {
 	Queries []MetricsStatsQuery
 }
@@ -118,6 +131,18 @@ type Metric struct {
 	Datapoints []Datapoint       `json:"datapoints"`
 	Stat       string            `json:"stat,omitempty"`
 	Name       string            `json:"name"`
+	// Samples of the series linked to the traces of their requests, when requested
+	Exemplars []Exemplar `json:"exemplars,omitempty"`
+}
+
+// Exemplar is a sample of a series linked to a representative trace, to get from a spike in the metrics to the
+// requests causing it
+type Exemplar struct {
+	// The ID of the trace of the request, empty when the exemplar has no trace label
+	TraceID   string            `json:"traceId,omitempty"`
+	Labels    map[string]string `json:"labels"`
+	Value     float64           `json:"value"`
+	Timestamp pmod.Time         `json:"timestamp"`
 }
 
 type Datapoint struct {
@@ -139,6 +164,27 @@ type MetricsStats struct {
 	ResponseTimes []Stat `json:"responseTimes"`
 }
 
+// MetricsComparison holds the metrics of a time window along with the ones of the same window shifted back
+// by the offset. Timestamps of both series belong to the current window.
+type MetricsComparison struct {
+	Offset   string                   `json:"offset"`
+	Current  MetricsMap               `json:"current"`
+	Previous MetricsMap               `json:"previous"`
+	Deltas   map[string][]MetricDelta `json:"deltas"`
+	// The effective step of the series in seconds, increased for the long ranges
+	Step int64 `json:"step"`
+}
+
+// MetricDelta compares the average value of a series over the current window and over the shifted window.
+// Previous and Percent are nil when there is no historical data (or when it averages to zero).
+type MetricDelta struct {
+	Labels   map[string]string `json:"labels"`
+	Stat     string            `json:"stat,omitempty"`
+	Current  float64           `json:"current"`
+	Previous *float64          `json:"previous"`
+	Percent  *float64          `json:"percent"`
+}
+
 // MetricsStatsResult holds the MetricsStats per target, plus errors
 type MetricsStatsResult struct {
 	Stats    map[string]MetricsStats `json:"stats"` // Key is built from query params, see "GenKey" above. The same key needs to be generated client-side for matching.
@@ -166,7 +212,7 @@ func ConvertHistogram(name string, from prometheus.Histogram, conversionParams C
 	for _, stat := range stats {
 		promMetric := from[stat]
 		if promMetric.Err != nil {
-			return nil, fmt.Errorf("error in metric %s/%s: %v", name, stat, promMetric.Err)
+			return nil, fmt.Errorf("error in metric %s/%s: %w", name, stat, promMetric.Err)
 		}
 		metric := convertMatrix(promMetric.Matrix, name, stat, conversionParams)
 		out = append(out, metric...)
@@ -176,7 +222,7 @@ func ConvertHistogram(name string, from prometheus.Histogram, conversionParams C
 
 func ConvertMetric(name string, from prometheus.Metric, conversionParams ConversionParams) ([]Metric, error) {
 	if from.Err != nil {
-		return nil, fmt.Errorf("error in metric %s: %v", name, from.Err)
+		return nil, fmt.Errorf("error in metric %s: %w", name, from.Err)
 	}
 	return convertMatrix(from.Matrix, name, "", conversionParams), nil
 }
@@ -223,6 +269,62 @@ func convertSampleStream(from *pmod.SampleStream, name, stat string, conversionP
 	}
 }
 
+// exemplarTraceIDLabels are the labels of the exemplars holding the trace ID, depending on the instrumentation
+var exemplarTraceIDLabels = []pmod.LabelName{"trace_id", "traceID", "traceId"}
+
+// AttachExemplars adds to the series the exemplars of the raw series they aggregate, i.e. whose labels include the
+// labels of the series. The exemplars are sorted by time.
+func AttachExemplars(series []Metric, from []prometheus.ExemplarQueryResult, scale float64) {
+	for i := range series {
+		// The exemplars of a trace can be stored in several buckets of a histogram
+		seen := map[string]bool{}
+		for _, result := range from {
+			if !includesLabels(result.SeriesLabels, series[i].Labels) {
+				continue
+			}
+			for _, e := range result.Exemplars {
+				exemplar := convertExemplar(e, scale)
+				key := fmt.Sprintf("%s/%v/%d", exemplar.TraceID, exemplar.Value, exemplar.Timestamp)
+				if seen[key] {
+					continue
+				}
+				seen[key] = true
+				series[i].Exemplars = append(series[i].Exemplars, exemplar)
+			}
+		}
+		sort.SliceStable(series[i].Exemplars, func(a, b int) bool {
+			return series[i].Exemplars[a].Timestamp < series[i].Exemplars[b].Timestamp
+		})
+	}
+}
+
+func includesLabels(labelSet pmod.LabelSet, labels map[string]string) bool {
+	for k, v := range labels {
+		if string(labelSet[pmod.LabelName(k)]) != v {
+			return false
+		}
+	}
+	return true
+}
+
+func convertExemplar(from prometheus.Exemplar, scale float64) Exemplar {
+	exemplar := Exemplar{
+		Labels:    make(map[string]string, len(from.Labels)),
+		Value:     scale * float64(from.Value),
+		Timestamp: from.Timestamp,
+	}
+	for k, v := range from.Labels {
+		exemplar.Labels[string(k)] = string(v)
+	}
+	for _, label := range exemplarTraceIDLabels {
+		if traceID, ok := from.Labels[label]; ok {
+			exemplar.TraceID = string(traceID)
+			break
+		}
+	}
+	return exemplar
+}
+
 // MarshalJSON implements json.Marshaler.
 func (s Datapoint) MarshalJSON() ([]byte, error) {
 	return pmod.SamplePair{
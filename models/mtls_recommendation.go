@@ -0,0 +1,79 @@
+package models
+
+const (
+	// MTLSRecommendationMoveToStrict: no plaintext traffic reaches the namespace, STRICT mTLS can be enforced
+	MTLSRecommendationMoveToStrict = "MOVE_TO_STRICT"
+	// MTLSRecommendationPermissiveCleanup: STRICT mTLS is enforced, the PERMISSIVE exceptions left can be removed
+	MTLSRecommendationPermissiveCleanup = "PERMISSIVE_CLEANUP"
+	// MTLSRecommendationNeedsWork: enforcing STRICT mTLS would break traffic, or the traffic doesn't tell
+	MTLSRecommendationNeedsWork = "NEEDS_WORK"
+	// MTLSRecommendationEnforced: STRICT mTLS is enforced without exceptions
+	MTLSRecommendationEnforced = "ENFORCED"
+)
+
+// MTLSRecommendation is the next step toward STRICT mTLS for a namespace, from its mTLS status and the plaintext
+// traffic observed toward its workloads
+// swagger:model mtlsRecommendation
+type MTLSRecommendation struct {
+	// required: true
+	// example: bookinfo
+	Namespace string `json:"namespace"`
+
+	// Namespace-wide mTLS status: MTLS_ENABLED, MTLS_PARTIALLY_ENABLED, MTLS_NOT_ENABLED or MTLS_DISABLED
+	// required: true
+	// example: MTLS_PARTIALLY_ENABLED
+	MTLSStatus string `json:"mtlsStatus"`
+
+	// The recommendation: MOVE_TO_STRICT, PERMISSIVE_CLEANUP, NEEDS_WORK or ENFORCED
+	// required: true
+	// example: MOVE_TO_STRICT
+	Recommendation string `json:"recommendation"`
+
+	// The facts supporting the recommendation
+	// required: true
+	Evidence []string `json:"evidence"`
+
+	// The rate interval of the observed traffic
+	// required: true
+	// example: 10m
+	RateInterval string `json:"rateInterval"`
+
+	// Requests and connections per second received with mutual TLS by the workloads of the namespace
+	// required: true
+	MTLSRate float64 `json:"mtlsRate"`
+
+	// Requests and connections per second received in plaintext by the workloads of the namespace
+	// required: true
+	PlaintextRate float64 `json:"plaintextRate"`
+
+	// The plaintext traffic received by the workloads of the namespace, from the highest rate
+	// required: true
+	PlaintextTraffic []PlaintextTraffic `json:"plaintextTraffic"`
+
+	// The PeerAuthentications of the namespace allowing plaintext with a PERMISSIVE mode, at workload or port level
+	// required: true
+	PermissivePeerAuthentications []string `json:"permissivePeerAuthentications"`
+}
+
+// PlaintextTraffic is the plaintext traffic from a source to a workload
+type PlaintextTraffic struct {
+	// Namespace of the source workload, unknown for sources out of the mesh
+	// required: true
+	SourceNamespace string `json:"sourceNamespace"`
+
+	// The source workload, unknown for sources out of the mesh
+	// required: true
+	SourceWorkload string `json:"sourceWorkload"`
+
+	// The workload of the namespace receiving the traffic
+	// required: true
+	DestinationWorkload string `json:"destinationWorkload"`
+
+	// The protocol: http, grpc or tcp
+	// required: true
+	Protocol string `json:"protocol"`
+
+	// Requests per second, or connections opened per second for tcp
+	// required: true
+	Rate float64 `json:"rate"`
+}
@@ -7,3 +7,32 @@ type MTLSStatus struct {
 	// example: MTLS_ENABLED
 	Status string `json:"status"`
 }
+
+// BackendTLSStatuses is the TLS status of the Services routed by the Kubernetes Gateway API routes of a namespace
+// swagger:model backendTLSStatuses
+type BackendTLSStatuses []BackendTLSStatus
+
+// BackendTLSStatus describes the TLS of a Service routed by Kubernetes Gateway API routes. During a migration, both
+// the Istio mTLS (PeerAuthentications and DestinationRules) and the Gateway API BackendTLSPolicies may apply to it.
+type BackendTLSStatus struct {
+	// required: true
+	Namespace string `json:"namespace"`
+	// required: true
+	Service string `json:"service"`
+	// Gateway API routes sending traffic to the Service, as <namespace>/<name>
+	Routes []string `json:"routes"`
+	// Istio mTLS status of the Service: MTLS_ENABLED, MTLS_PARTIALLY_ENABLED, MTLS_NOT_ENABLED or MTLS_DISABLED
+	// example: MTLS_ENABLED
+	MTLSStatus string `json:"mtlsStatus"`
+	// TLS mode of the DestinationRule applied to the host of the Service, empty when none sets it
+	DestinationRuleMode string `json:"destinationRuleMode"`
+	// mTLS mode of the PeerAuthentication applied to the workloads of the Service, empty when none sets it
+	PeerAuthenticationMode string `json:"peerAuthenticationMode"`
+	// BackendTLSPolicies targeting the Service, as <namespace>/<name>, the applied one first
+	BackendTLSPolicies []string `json:"backendTLSPolicies"`
+	// TLS originated by the gateways towards the Service: SIMPLE when a BackendTLSPolicy applies, the Istio mode otherwise
+	// example: ISTIO_MUTUAL
+	Mode string `json:"mode"`
+	// Conflicting TLS expectations of the Istio and the Gateway API configurations
+	Conflicts []string `json:"conflicts"`
+}
@@ -0,0 +1,32 @@
+package models
+
+// Sections of the NamespaceOverview, used as keys of the Errors map
+const (
+	OverviewConfigs     = "configs"
+	OverviewHealth      = "health"
+	OverviewTLS         = "tls"
+	OverviewValidations = "validations"
+)
+
+// NamespaceOverview aggregates the information displayed in the overview of a namespace.
+// A section that couldn't be computed keeps its empty value and its error is reported in Errors.
+type NamespaceOverview struct {
+	// The namespace of the overview
+	// required: true
+	Namespace Namespace `json:"namespace"`
+
+	// Health of the apps of the namespace
+	AppHealth NamespaceAppHealth `json:"appHealth"`
+
+	// Number of Istio objects per object type
+	ConfigCounts map[string]int `json:"configCounts"`
+
+	// Namespace-wide mTLS status
+	TLSStatus MTLSStatus `json:"tlsStatus"`
+
+	// Number of errors and warnings of the Istio objects of the namespace
+	Validations IstioValidationSummary `json:"validations"`
+
+	// Errors per section, empty when the whole overview could be computed
+	Errors map[string]string `json:"errors,omitempty"`
+}
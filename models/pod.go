@@ -27,18 +27,38 @@ type Pod struct {
 	VersionLabel        bool              `json:"versionLabel"`
 	Annotations         map[string]string `json:"annotations"`
 	ProxyStatus         *ProxyStatus      `json:"proxyStatus"`
+	NodeName            string            `json:"nodeName"`
+	PodIP               string            `json:"podIP"`
+	Zone                string            `json:"zone"`
+	Region              string            `json:"region"`
+	Cluster             string            `json:"cluster"`
+	IstioInitIssue      *IstioInitIssue   `json:"istioInitIssue,omitempty"`
 }
 
+const (
+	// IstioClusterIDEnv is set by the sidecar injector with the cluster of the pod
+	IstioClusterIDEnv = "ISTIO_META_CLUSTER_ID"
+	// IstioClusterLabel may be set on pods to state their cluster
+	IstioClusterLabel = "topology.istio.io/cluster"
+)
+
 // Reference holds some information on the pod creator
 type Reference struct {
 	Name string `json:"name"`
 	Kind string `json:"kind"`
 }
 
-// ContainerInfo holds container name and image
+// ContainerInfo holds container name and image, and for the containers of the application their probes and restarts
 type ContainerInfo struct {
-	Name  string `json:"name"`
-	Image string `json:"image"`
+	Name                    string `json:"name"`
+	Image                   string `json:"image"`
+	ImageID                 string `json:"imageId,omitempty"`
+	LivenessProbe           *Probe `json:"livenessProbe,omitempty"`
+	ReadinessProbe          *Probe `json:"readinessProbe,omitempty"`
+	StartupProbe            *Probe `json:"startupProbe,omitempty"`
+	Restarts                int32  `json:"restarts,omitempty"`
+	LastTerminationReason   string `json:"lastTerminationReason,omitempty"`
+	LastTerminationExitCode int32  `json:"lastTerminationExitCode,omitempty"`
 }
 
 // Parse extracts desired information from k8s []Pod info
@@ -81,17 +101,22 @@ func (pod *Pod) Parse(p *core_v1.Pod) {
 		if err == nil {
 			for _, name := range scs.InitContainers {
 				container := ContainerInfo{
-					Name:  name,
-					Image: lookupImage(name, p.Spec.InitContainers)}
+					Name:    name,
+					Image:   lookupImage(name, p.Spec.InitContainers),
+					ImageID: lookupImageID(name, p.Status.InitContainerStatuses)}
 				pod.IstioInitContainers = append(pod.IstioInitContainers, &container)
 				istioContainerNames[name] = true
 			}
 			for _, name := range scs.Containers {
 				container := ContainerInfo{
-					Name:  name,
-					Image: lookupImage(name, p.Spec.Containers)}
+					Name:    name,
+					Image:   lookupImage(name, p.Spec.Containers),
+					ImageID: lookupImageID(name, p.Status.ContainerStatuses)}
 				pod.IstioContainers = append(pod.IstioContainers, &container)
 				istioContainerNames[name] = true
+				if pod.Cluster == "" {
+					pod.Cluster = lookupEnv(name, IstioClusterIDEnv, p.Spec.Containers)
+				}
 			}
 		}
 	}
@@ -100,18 +125,48 @@ func (pod *Pod) Parse(p *core_v1.Pod) {
 			continue
 		}
 		container := ContainerInfo{
-			Name:  c.Name,
-			Image: c.Image,
+			Name:           c.Name,
+			Image:          c.Image,
+			LivenessProbe:  parseProbe(c.LivenessProbe),
+			ReadinessProbe: parseProbe(c.ReadinessProbe),
+			StartupProbe:   parseProbe(c.StartupProbe),
 		}
+		container.parseContainerStatus(p.Status.ContainerStatuses)
 		pod.Containers = append(pod.Containers, &container)
 	}
 	pod.Status = string(p.Status.Phase)
 	pod.StatusMessage = string(p.Status.Message)
 	pod.StatusReason = string(p.Status.Reason)
+	pod.IstioInitIssue = parseIstioInitIssue(p)
+	// Pending pods may not be scheduled to any node yet
+	pod.NodeName = p.Spec.NodeName
+	pod.PodIP = p.Status.PodIP
+	if pod.Cluster == "" {
+		pod.Cluster = p.Labels[IstioClusterLabel]
+	}
 	_, pod.AppLabel = p.Labels[conf.IstioLabels.AppLabelName]
 	_, pod.VersionLabel = p.Labels[conf.IstioLabels.VersionLabelName]
 }
 
+// SetNodeTopology sets the zone and region of the node where the pod runs.
+// The deprecated beta labels are used when the node doesn't have the topology labels.
+func (pod *Pod) SetNodeTopology(node *core_v1.Node) {
+	if node == nil {
+		return
+	}
+	pod.Zone = lookupLabel(node.Labels, core_v1.LabelTopologyZone, core_v1.LabelFailureDomainBetaZone)
+	pod.Region = lookupLabel(node.Labels, core_v1.LabelTopologyRegion, core_v1.LabelFailureDomainBetaRegion)
+}
+
+func lookupLabel(labels map[string]string, names ...string) string {
+	for _, name := range names {
+		if value, ok := labels[name]; ok {
+			return value
+		}
+	}
+	return ""
+}
+
 func lookupImage(containerName string, containers []core_v1.Container) string {
 	for _, c := range containers {
 		if c.Name == containerName {
@@ -121,6 +176,29 @@ func lookupImage(containerName string, containers []core_v1.Container) string {
 	return ""
 }
 
+// lookupImageID returns the image resolved by the runtime for the container, holding the digest of the image
+func lookupImageID(containerName string, statuses []core_v1.ContainerStatus) string {
+	for _, s := range statuses {
+		if s.Name == containerName {
+			return s.ImageID
+		}
+	}
+	return ""
+}
+
+func lookupEnv(containerName, envName string, containers []core_v1.Container) string {
+	for _, c := range containers {
+		if c.Name == containerName {
+			for _, env := range c.Env {
+				if env.Name == envName {
+					return env.Value
+				}
+			}
+		}
+	}
+	return ""
+}
+
 // HasIstioSidecar returns true if there are no pods or all pods have a sidecar
 func (pods Pods) HasIstioSidecar() bool {
 	if len(pods) > 0 {
@@ -0,0 +1,122 @@
+package models
+
+import (
+	"fmt"
+
+	core_v1 "k8s.io/api/core/v1"
+)
+
+const (
+	// IstioInitContainerName is the init container setting up the traffic redirection to the sidecar
+	IstioInitContainerName = "istio-init"
+	// IstioValidationContainerName is the init container checking the traffic redirection set up by the Istio CNI plugin
+	IstioValidationContainerName = "istio-validation"
+	// IstioCNIUninitializedLabel is set by the repair controller of the Istio CNI plugin on the pods started
+	// before their network was configured
+	IstioCNIUninitializedLabel = "cni.istio.io/uninitialized"
+	// IstioCNIUninitializedReason is the reason of the issue of a pod flagged by the Istio CNI plugin
+	IstioCNIUninitializedReason = "CNIUninitialized"
+)
+
+// The waiting reasons of a container which is starting normally
+var startingReasons = map[string]bool{
+	"":                  true,
+	"PodInitializing":   true,
+	"ContainerCreating": true,
+}
+
+// IstioInitIssue explains why the Istio networking of a pod can't be initialized, leaving the pod stuck in its
+// init phase or without traffic redirection to its sidecar
+type IstioInitIssue struct {
+	// Container is the failing init container, empty when the issue is reported by the Istio CNI plugin
+	//
+	// example: istio-init
+	Container string `json:"container,omitempty"`
+
+	// Reason is the reason reported for the container, or CNIUninitialized
+	//
+	// required: true
+	// example: CrashLoopBackOff
+	Reason string `json:"reason"`
+
+	// Message is the diagnosis of the issue
+	//
+	// required: true
+	Message string `json:"message"`
+
+	// Detail is the message of the container, usually the error of the last run
+	Detail string `json:"detail,omitempty"`
+
+	// ExitCode is the exit code of the last failed run of the container
+	ExitCode int32 `json:"exitCode,omitempty"`
+
+	// Restarts is the number of restarts of the container
+	Restarts int32 `json:"restarts,omitempty"`
+}
+
+// PodIstioInitIssue is the Istio init issue of a pod of a workload
+type PodIstioInitIssue struct {
+	Pod string `json:"pod"`
+	IstioInitIssue
+}
+
+// parseIstioInitIssue returns the Istio init issue of a pod, from its container statuses and the labels of the
+// Istio CNI plugin, nil when the pod has none
+func parseIstioInitIssue(p *core_v1.Pod) *IstioInitIssue {
+	// The repair controller may flag running pods, whose traffic doesn't go through the sidecar
+	if p.Labels[IstioCNIUninitializedLabel] == "true" || p.Annotations[IstioCNIUninitializedLabel] == "true" {
+		return &IstioInitIssue{
+			Reason: IstioCNIUninitializedReason,
+			Message: fmt.Sprintf("The Istio CNI plugin didn't configure the network of the pod: check that the istio-cni-node pod of node [%s] is ready, then restart the pod",
+				p.Spec.NodeName),
+		}
+	}
+	// The init containers of a running pod have completed
+	if p.Status.Phase == core_v1.PodRunning || p.Status.Phase == core_v1.PodSucceeded {
+		return nil
+	}
+	for _, status := range p.Status.InitContainerStatuses {
+		if status.Name != IstioInitContainerName && status.Name != IstioValidationContainerName {
+			continue
+		}
+		if issue := initContainerIssue(status); issue != nil {
+			return issue
+		}
+	}
+	return nil
+}
+
+func initContainerIssue(status core_v1.ContainerStatus) *IstioInitIssue {
+	issue := IstioInitIssue{Container: status.Name, Restarts: status.RestartCount}
+	switch state := status.State; {
+	case state.Waiting != nil && !startingReasons[state.Waiting.Reason]:
+		issue.Reason = state.Waiting.Reason
+		issue.Detail = state.Waiting.Message
+	case state.Terminated != nil && state.Terminated.ExitCode != 0:
+		issue.Reason = state.Terminated.Reason
+	default:
+		return nil
+	}
+
+	// The termination of the last run tells why the container fails
+	terminated := status.State.Terminated
+	if terminated == nil {
+		terminated = status.LastTerminationState.Terminated
+	}
+	if terminated != nil && terminated.ExitCode != 0 {
+		issue.ExitCode = terminated.ExitCode
+		if terminated.Message != "" {
+			issue.Detail = terminated.Message
+		}
+	}
+
+	switch {
+	case issue.Reason == "ErrImagePull" || issue.Reason == "ImagePullBackOff" || issue.Reason == "InvalidImageName":
+		issue.Message = fmt.Sprintf("The image of the %s container can't be pulled: check the proxy image and the image pull secrets of the injection", status.Name)
+	case status.Name == IstioValidationContainerName:
+		issue.Message = "The traffic redirection to the sidecar is missing: the Istio CNI plugin didn't configure the network of the pod, check the istio-cni-node pod of its node"
+	default:
+		issue.Message = "The istio-init container can't set up the traffic redirection to the sidecar: it needs the NET_ADMIN and NET_RAW capabilities, which may be denied by the security policies of the cluster, or the Istio CNI plugin should be used"
+	}
+	return &issue
+}
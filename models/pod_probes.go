@@ -0,0 +1,124 @@
+package models
+
+import (
+	"strings"
+
+	core_v1 "k8s.io/api/core/v1"
+)
+
+// Probe is the configuration of a liveness, readiness or startup probe of a container
+type Probe struct {
+	// Type is the action of the probe: httpGet, tcpSocket or exec
+	//
+	// required: true
+	// example: httpGet
+	Type string `json:"type"`
+
+	// Path is the path requested by an httpGet probe
+	//
+	// example: /healthz
+	Path string `json:"path,omitempty"`
+
+	// Port is the port, number or name, of an httpGet or tcpSocket probe
+	//
+	// example: 8080
+	Port string `json:"port,omitempty"`
+
+	// Command is the command of an exec probe
+	Command string `json:"command,omitempty"`
+
+	// InitialDelaySeconds is the delay before the first probe
+	InitialDelaySeconds int32 `json:"initialDelaySeconds"`
+
+	// TimeoutSeconds is the timeout of a probe, 1 by default
+	TimeoutSeconds int32 `json:"timeoutSeconds"`
+
+	// PeriodSeconds is the period of the probes, 10 by default
+	PeriodSeconds int32 `json:"periodSeconds"`
+
+	// SuccessThreshold is the number of consecutive successes for the probe to pass, 1 by default
+	SuccessThreshold int32 `json:"successThreshold"`
+
+	// FailureThreshold is the number of consecutive failures for the probe to fail, 3 by default
+	FailureThreshold int32 `json:"failureThreshold"`
+}
+
+// ProbeIssue suggests the probe causes of the restarts of a container
+type ProbeIssue struct {
+	// Container is the restarting container
+	//
+	// required: true
+	Container string `json:"container"`
+
+	// Restarts is the number of restarts of the container
+	//
+	// required: true
+	Restarts int32 `json:"restarts"`
+
+	// LastTerminationReason is the reason of the last termination of the container
+	//
+	// example: Error
+	LastTerminationReason string `json:"lastTerminationReason,omitempty"`
+
+	// Suggestions are the probe settings which may cause the restarts
+	//
+	// required: true
+	Suggestions []string `json:"suggestions"`
+}
+
+// PodProbeIssue is the probe issue of a container of a pod of a workload
+type PodProbeIssue struct {
+	Pod string `json:"pod"`
+	ProbeIssue
+}
+
+// parseProbe returns the probe with the defaults of Kubernetes filled, nil when the container has none
+func parseProbe(p *core_v1.Probe) *Probe {
+	if p == nil {
+		return nil
+	}
+	probe := Probe{
+		InitialDelaySeconds: p.InitialDelaySeconds,
+		TimeoutSeconds:      defaultProbeValue(p.TimeoutSeconds, 1),
+		PeriodSeconds:       defaultProbeValue(p.PeriodSeconds, 10),
+		SuccessThreshold:    defaultProbeValue(p.SuccessThreshold, 1),
+		FailureThreshold:    defaultProbeValue(p.FailureThreshold, 3),
+	}
+	switch {
+	case p.HTTPGet != nil:
+		probe.Type = "httpGet"
+		probe.Path = p.HTTPGet.Path
+		probe.Port = p.HTTPGet.Port.String()
+	case p.TCPSocket != nil:
+		probe.Type = "tcpSocket"
+		probe.Port = p.TCPSocket.Port.String()
+	case p.Exec != nil:
+		probe.Type = "exec"
+		probe.Command = strings.Join(p.Exec.Command, " ")
+	}
+	return &probe
+}
+
+func defaultProbeValue(value, defaultValue int32) int32 {
+	if value == 0 {
+		return defaultValue
+	}
+	return value
+}
+
+// parseContainerStatus fills the image ID, the restarts and the last termination of a container from the statuses of
+// its pod
+func (container *ContainerInfo) parseContainerStatus(statuses []core_v1.ContainerStatus) {
+	for _, status := range statuses {
+		if status.Name != container.Name {
+			continue
+		}
+		container.ImageID = status.ImageID
+		container.Restarts = status.RestartCount
+		if terminated := status.LastTerminationState.Terminated; terminated != nil {
+			container.LastTerminationReason = terminated.Reason
+			container.LastTerminationExitCode = terminated.ExitCode
+		}
+		return
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	core_v1 "k8s.io/api/core/v1"
 	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 
 	"github.com/kiali/kiali/config"
 )
@@ -182,3 +183,165 @@ func TestSyncedPodProxiesCount(t *testing.T) {
 	pods = append(pods, pod)
 	assert.Equal(int32(-1), pods.SyncedPodProxiesCount())
 }
+
+func TestPodNodeTopology(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	k8sPod := core_v1.Pod{
+		ObjectMeta: meta_v1.ObjectMeta{Name: "details-v1-3618568057-dnkjp"},
+		Spec:       core_v1.PodSpec{NodeName: "node-1"},
+		Status:     core_v1.PodStatus{Phase: core_v1.PodRunning, PodIP: "10.128.0.10"},
+	}
+	pod := Pod{}
+	pod.Parse(&k8sPod)
+	assert.Equal("node-1", pod.NodeName)
+	assert.Equal("10.128.0.10", pod.PodIP)
+
+	// Nodes labeled only with the deprecated beta labels
+	pod.SetNodeTopology(&core_v1.Node{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name: "node-1",
+			Labels: map[string]string{
+				core_v1.LabelFailureDomainBetaZone:   "us-east-1b",
+				core_v1.LabelFailureDomainBetaRegion: "us-east-1",
+			},
+		},
+	})
+	assert.Equal("us-east-1b", pod.Zone)
+	assert.Equal("us-east-1", pod.Region)
+
+	// Topology labels take precedence
+	pod.SetNodeTopology(&core_v1.Node{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name: "node-1",
+			Labels: map[string]string{
+				core_v1.LabelTopologyZone:          "us-east-1a",
+				core_v1.LabelFailureDomainBetaZone: "us-east-1b",
+			},
+		},
+	})
+	assert.Equal("us-east-1a", pod.Zone)
+	assert.Empty(pod.Region)
+
+	// Pending pods are not scheduled to any node
+	k8sPod = core_v1.Pod{
+		ObjectMeta: meta_v1.ObjectMeta{Name: "details-v1-3618568057-pending"},
+		Status:     core_v1.PodStatus{Phase: core_v1.PodPending},
+	}
+	pod = Pod{}
+	pod.Parse(&k8sPod)
+	pod.SetNodeTopology(nil)
+	assert.Equal("Pending", pod.Status)
+	assert.Empty(pod.NodeName)
+	assert.Empty(pod.PodIP)
+	assert.Empty(pod.Zone)
+}
+
+func TestPodCluster(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	k8sPod := core_v1.Pod{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:        "details-v1-3618568057-dnkjp",
+			Labels:      map[string]string{IstioClusterLabel: "label-cluster"},
+			Annotations: map[string]string{"sidecar.istio.io/status": `{"containers":["istio-proxy"]}`},
+		},
+		Spec: core_v1.PodSpec{
+			Containers: []core_v1.Container{
+				{Name: "details", Env: []core_v1.EnvVar{{Name: IstioClusterIDEnv, Value: "app-container"}}},
+				{Name: "istio-proxy", Env: []core_v1.EnvVar{{Name: "POD_NAME"}, {Name: IstioClusterIDEnv, Value: "east"}}},
+			},
+		},
+	}
+	pod := Pod{}
+	pod.Parse(&k8sPod)
+	// The sidecar env takes precedence over the label
+	assert.Equal("east", pod.Cluster)
+
+	// Pods without sidecar may be labeled
+	k8sPod.Annotations = nil
+	pod = Pod{}
+	pod.Parse(&k8sPod)
+	assert.Equal("label-cluster", pod.Cluster)
+
+	k8sPod.Labels = nil
+	pod = Pod{}
+	pod.Parse(&k8sPod)
+	assert.Equal("", pod.Cluster)
+}
+
+func TestPodParsingIstioInitIssue(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	k8sPod := core_v1.Pod{
+		ObjectMeta: meta_v1.ObjectMeta{Name: "details-v1-3618568057-dnkjp"},
+		Status: core_v1.PodStatus{
+			Phase: core_v1.PodPending,
+			InitContainerStatuses: []core_v1.ContainerStatus{
+				{Name: "enable-core-dump", State: core_v1.ContainerState{Terminated: &core_v1.ContainerStateTerminated{ExitCode: 0}}},
+				{Name: "istio-init", State: core_v1.ContainerState{Waiting: &core_v1.ContainerStateWaiting{
+					Reason:  "ImagePullBackOff",
+					Message: "Back-off pulling image \"registry.local/istio/proxyv2:1.12.1\"",
+				}}},
+			},
+		},
+	}
+
+	pod := Pod{}
+	pod.Parse(&k8sPod)
+	assert.NotNil(pod.IstioInitIssue)
+	assert.Equal("istio-init", pod.IstioInitIssue.Container)
+	assert.Equal("ImagePullBackOff", pod.IstioInitIssue.Reason)
+	assert.Contains(pod.IstioInitIssue.Message, "can't be pulled")
+	assert.Contains(pod.IstioInitIssue.Detail, "registry.local")
+
+	// Once running, the init containers have completed
+	k8sPod.Status.Phase = core_v1.PodRunning
+	pod = Pod{}
+	pod.Parse(&k8sPod)
+	assert.Nil(pod.IstioInitIssue)
+}
+
+func TestPodParsingProbes(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	k8sPod := core_v1.Pod{
+		ObjectMeta: meta_v1.ObjectMeta{Name: "details-v1-3618568057-dnkjp"},
+		Spec: core_v1.PodSpec{
+			Containers: []core_v1.Container{{
+				Name: "details",
+				LivenessProbe: &core_v1.Probe{
+					Handler:             core_v1.Handler{HTTPGet: &core_v1.HTTPGetAction{Path: "/health", Port: intstr.FromString("http")}},
+					InitialDelaySeconds: 5,
+					FailureThreshold:    1,
+				},
+				ReadinessProbe: &core_v1.Probe{
+					Handler: core_v1.Handler{Exec: &core_v1.ExecAction{Command: []string{"cat", "/tmp/ready"}}},
+				},
+			}},
+		},
+		Status: core_v1.PodStatus{
+			ContainerStatuses: []core_v1.ContainerStatus{{
+				Name:                 "details",
+				RestartCount:         6,
+				LastTerminationState: core_v1.ContainerState{Terminated: &core_v1.ContainerStateTerminated{ExitCode: 137, Reason: "Error"}},
+			}},
+		},
+	}
+
+	pod := Pod{}
+	pod.Parse(&k8sPod)
+	container := pod.Containers[0]
+	// The defaults of Kubernetes are filled
+	assert.Equal(&Probe{Type: "httpGet", Path: "/health", Port: "http", InitialDelaySeconds: 5, TimeoutSeconds: 1, PeriodSeconds: 10, SuccessThreshold: 1, FailureThreshold: 1}, container.LivenessProbe)
+	assert.Equal("exec", container.ReadinessProbe.Type)
+	assert.Equal("cat /tmp/ready", container.ReadinessProbe.Command)
+	assert.Nil(container.StartupProbe)
+	assert.Equal(int32(6), container.Restarts)
+	assert.Equal("Error", container.LastTerminationReason)
+	assert.Equal(int32(137), container.LastTerminationExitCode)
+}
@@ -0,0 +1,62 @@
+package models
+
+import (
+	"time"
+)
+
+// UnknownPort is the port of the traffic reported without destination port
+const UnknownPort = "unknown"
+
+// WorkloadPortMetricsQuery holds the parameters of the metrics per destination port of a workload
+type WorkloadPortMetricsQuery struct {
+	Namespace    string
+	Workload     string
+	Direction    string // inbound | outbound, defaults to inbound if not provided
+	RateInterval string
+	QueryTime    time.Time
+}
+
+// FillDefaults fills the struct with default parameters
+func (q *WorkloadPortMetricsQuery) FillDefaults() {
+	q.Direction = "inbound"
+	q.RateInterval = "10m"
+	q.QueryTime = time.Now()
+}
+
+// WorkloadPortMetrics holds the traffic of a workload per destination port
+// swagger:model workloadPortMetrics
+type WorkloadPortMetrics struct {
+	// required: true
+	Namespace string `json:"namespace"`
+
+	// required: true
+	Workload string `json:"workload"`
+
+	// inbound: the ports of the workload, outbound: the ports of the destinations of the workload
+	// required: true
+	// example: inbound
+	Direction string `json:"direction"`
+
+	// The traffic per port, sorted by port
+	// required: true
+	Ports []PortMetrics `json:"ports"`
+}
+
+// PortMetrics is the traffic of a destination port
+type PortMetrics struct {
+	// The destination port, unknown when the telemetry doesn't report the destination_port label
+	// required: true
+	// example: 9080
+	Port string `json:"port"`
+
+	// required: true
+	RequestRate float64 `json:"requestRate"`
+
+	// The rate of the requests in error: no response, 4xx and 5xx response codes, and grpc errors
+	// required: true
+	ErrorRate float64 `json:"errorRate"`
+
+	// The response times in milliseconds: average and quantiles
+	// required: true
+	Latencies []Stat `json:"latencies"`
+}
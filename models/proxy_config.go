@@ -0,0 +1,46 @@
+package models
+
+import (
+	"github.com/kiali/kiali/kubernetes"
+)
+
+// ProxyConfigResources proxyConfigResources
+//
+// This is used for returning an array of ProxyConfig resources
+//
+// swagger:model proxyConfigResources
+// An array of proxyConfigResource
+// swagger:allOf
+type ProxyConfigResources []ProxyConfigResource
+
+// ProxyConfigResource proxyConfigResource
+//
+// This is used for returning a ProxyConfig resource. It's named after the resource to not be confused with the
+// ProxyConfig of the mesh config, which holds the mesh-wide defaults.
+//
+// swagger:model proxyConfigResource
+type ProxyConfigResource struct {
+	IstioBase
+	Spec struct {
+		Selector             interface{} `json:"selector"`
+		Concurrency          interface{} `json:"concurrency"`
+		EnvironmentVariables interface{} `json:"environmentVariables"`
+		Image                interface{} `json:"image"`
+	} `json:"spec"`
+}
+
+func (pcs *ProxyConfigResources) Parse(proxyConfigs []kubernetes.IstioObject) {
+	for _, pc := range proxyConfigs {
+		proxyConfig := ProxyConfigResource{}
+		proxyConfig.Parse(pc)
+		*pcs = append(*pcs, proxyConfig)
+	}
+}
+
+func (pc *ProxyConfigResource) Parse(proxyConfig kubernetes.IstioObject) {
+	pc.IstioBase.Parse(proxyConfig)
+	pc.Spec.Selector = proxyConfig.GetSpec()["selector"]
+	pc.Spec.Concurrency = proxyConfig.GetSpec()["concurrency"]
+	pc.Spec.EnvironmentVariables = proxyConfig.GetSpec()["environmentVariables"]
+	pc.Spec.Image = proxyConfig.GetSpec()["image"]
+}
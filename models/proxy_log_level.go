@@ -0,0 +1,22 @@
+package models
+
+// NamespaceProxyLogLevel is the default log level of the proxies of a namespace, applied to its current pods and to
+// the pods created later
+// swagger:model namespaceProxyLogLevel
+type NamespaceProxyLogLevel struct {
+	// required: true
+	Namespace string `json:"namespace"`
+
+	// The Envoy log level: off, trace, debug, info, warning, error or critical
+	// required: true
+	// example: debug
+	Level string `json:"level"`
+
+	// The pods whose proxy log level was changed
+	// required: true
+	Applied []string `json:"applied"`
+
+	// The pods whose proxy couldn't be reached, the level is applied to them again later
+	// required: true
+	Failed []string `json:"failed"`
+}
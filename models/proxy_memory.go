@@ -0,0 +1,65 @@
+package models
+
+import (
+	"time"
+)
+
+// NamespaceProxyMemory is the memory pressure of the istio-proxy containers of the workloads of a namespace. Proxies
+// under memory pressure drop config or get OOMKilled, which explains sync failures and dropped traffic.
+// swagger:model namespaceProxyMemory
+type NamespaceProxyMemory struct {
+	// Namespace of the proxies
+	// required: true
+	Namespace string `json:"namespace"`
+
+	// The memory of the proxies per workload. Workloads without proxy are not listed.
+	// required: true
+	Workloads []WorkloadProxyMemory `json:"workloads"`
+}
+
+// WorkloadProxyMemory is the memory pressure of the istio-proxy containers of the pods of a workload
+type WorkloadProxyMemory struct {
+	// Name of the workload
+	// required: true
+	Workload string `json:"workload"`
+
+	// True when one of the proxies is near its memory limit or was recently OOMKilled
+	// required: true
+	UnderPressure bool `json:"underPressure"`
+
+	// The proxies of the pods of the workload
+	// required: true
+	Proxies []ProxyMemory `json:"proxies"`
+}
+
+// ProxyMemory is the memory usage of an istio-proxy container against its limit, and its OOMKill events
+type ProxyMemory struct {
+	// Name of the pod of the proxy
+	// required: true
+	Pod string `json:"pod"`
+
+	// Observed memory usage (working set) in bytes. Nil without metrics.
+	Usage *float64 `json:"usage,omitempty"`
+
+	// Memory limit in bytes. Nil when the proxy has no limit.
+	Limit *float64 `json:"limit,omitempty"`
+
+	// Usage over limit. Nil without usage or limit.
+	UsageRatio *float64 `json:"usageRatio,omitempty"`
+
+	// True when the usage is near the limit
+	// required: true
+	NearLimit bool `json:"nearLimit"`
+
+	// Number of restarts of the proxy
+	// required: true
+	Restarts int32 `json:"restarts"`
+
+	// The time of the last OOMKill of the proxy, when it was its last termination. Kubernetes only keeps the
+	// last termination of a container.
+	LastOOMKilled *time.Time `json:"lastOOMKilled,omitempty"`
+
+	// True when the last OOMKill is recent
+	// required: true
+	RecentlyOOMKilled bool `json:"recentlyOOMKilled"`
+}
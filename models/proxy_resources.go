@@ -0,0 +1,106 @@
+package models
+
+import (
+	core_v1 "k8s.io/api/core/v1"
+)
+
+// IstioProxyContainer is the name of the sidecar container injected by Istio
+const IstioProxyContainer = "istio-proxy"
+
+// NamespaceProxyResources is the overhead of the istio-proxy containers of the workloads of a namespace
+// swagger:model namespaceProxyResources
+type NamespaceProxyResources struct {
+	// Namespace of the proxies
+	// required: true
+	Namespace string `json:"namespace"`
+
+	// The resources of all the proxies of the namespace
+	// required: true
+	Total ProxyResources `json:"total"`
+
+	// The resources of the proxies per workload. Workloads without proxy are not listed.
+	// required: true
+	Workloads []WorkloadProxyResources `json:"workloads"`
+}
+
+// WorkloadProxyResources are the resources of the istio-proxy containers of the pods of a workload
+type WorkloadProxyResources struct {
+	// Name of the workload
+	// required: true
+	Workload string `json:"workload"`
+
+	ProxyResources
+}
+
+// ProxyResources sums the resources of a set of istio-proxy containers. CPU is in cores, memory in bytes.
+type ProxyResources struct {
+	// Number of proxies
+	// required: true
+	Proxies int `json:"proxies"`
+
+	// required: true
+	CpuRequests float64 `json:"cpuRequests"`
+
+	// Sum of the CPU limits of the proxies that set one
+	// required: true
+	CpuLimits float64 `json:"cpuLimits"`
+
+	// required: true
+	MemoryRequests float64 `json:"memoryRequests"`
+
+	// Sum of the memory limits of the proxies that set one
+	// required: true
+	MemoryLimits float64 `json:"memoryLimits"`
+
+	// Number of proxies without CPU or memory limit
+	// required: true
+	Unlimited int `json:"unlimited"`
+
+	// Observed CPU usage of the proxies with metrics. Nil when none has metrics.
+	CpuUsage *float64 `json:"cpuUsage,omitempty"`
+
+	// Observed memory usage (working set) of the proxies with metrics. Nil when none has metrics.
+	MemoryUsage *float64 `json:"memoryUsage,omitempty"`
+}
+
+// AddContainer adds the requests and limits of an istio-proxy container
+func (r *ProxyResources) AddContainer(container core_v1.Container) {
+	r.Proxies++
+	r.CpuRequests += container.Resources.Requests.Cpu().AsApproximateFloat64()
+	r.MemoryRequests += container.Resources.Requests.Memory().AsApproximateFloat64()
+	cpuLimit, hasCpuLimit := container.Resources.Limits[core_v1.ResourceCPU]
+	memoryLimit, hasMemoryLimit := container.Resources.Limits[core_v1.ResourceMemory]
+	r.CpuLimits += cpuLimit.AsApproximateFloat64()
+	r.MemoryLimits += memoryLimit.AsApproximateFloat64()
+	if !hasCpuLimit || !hasMemoryLimit {
+		r.Unlimited++
+	}
+}
+
+// AddUsage adds the observed usage of a proxy. Nil values are proxies without metrics.
+func (r *ProxyResources) AddUsage(cpu, memory *float64) {
+	r.CpuUsage = addUsage(r.CpuUsage, cpu)
+	r.MemoryUsage = addUsage(r.MemoryUsage, memory)
+}
+
+// Add adds the resources of another set of proxies
+func (r *ProxyResources) Add(other ProxyResources) {
+	r.Proxies += other.Proxies
+	r.CpuRequests += other.CpuRequests
+	r.CpuLimits += other.CpuLimits
+	r.MemoryRequests += other.MemoryRequests
+	r.MemoryLimits += other.MemoryLimits
+	r.Unlimited += other.Unlimited
+	r.AddUsage(other.CpuUsage, other.MemoryUsage)
+}
+
+func addUsage(total, usage *float64) *float64 {
+	if usage == nil {
+		return total
+	}
+	sum := *usage
+	if total != nil {
+		sum += *total
+	}
+	return &sum
+}
@@ -0,0 +1,82 @@
+package models
+
+// Sync status of a proxy, summarizing the status of its xDS components
+const (
+	// ProxySynced means all the components sent to the proxy were acknowledged
+	ProxySynced = "SYNCED"
+	// ProxyStale means the proxy didn't acknowledge the last version of some component
+	ProxyStale = "STALE"
+	// ProxyNotSent means some component was never sent to the proxy
+	ProxyNotSent = "NOT_SENT"
+)
+
+// NamespaceProxyStatus summarizes the sync status of the proxies of a namespace
+// swagger:model namespaceProxyStatus
+type NamespaceProxyStatus struct {
+	// Namespace of the proxies
+	// required: true
+	Namespace string `json:"namespace"`
+
+	// Number of proxies with all their components synced
+	// required: true
+	Synced int `json:"synced"`
+
+	// Number of proxies with some component stale
+	// required: true
+	Stale int `json:"stale"`
+
+	// Number of proxies with some component never sent, and none stale
+	// required: true
+	NotSent int `json:"notSent"`
+
+	// The pods whose proxy is not synced
+	// required: true
+	OutOfSync []PodProxyStatus `json:"outOfSync"`
+}
+
+// PodProxyStatus is the sync status of the proxy of a pod
+type PodProxyStatus struct {
+	// Name of the pod
+	// required: true
+	Pod string `json:"pod"`
+
+	// Status is either SYNCED, STALE or NOT_SENT
+	// required: true
+	Status string `json:"status"`
+
+	// Status of each component
+	// required: true
+	ProxyStatus ProxyStatus `json:"proxyStatus"`
+}
+
+// Summary returns the sync status of the proxy: STALE when any component is stale, else NOT_SENT when any component
+// was never sent, else SYNCED
+func (ps ProxyStatus) Summary() string {
+	summary := ProxySynced
+	for _, component := range []string{ps.CDS, ps.EDS, ps.LDS, ps.RDS} {
+		switch {
+		case isComponentStatusSynced(component):
+		case component == ProxyNotSent:
+			summary = ProxyNotSent
+		default:
+			// Stale, or Stale (Never Acknowledged)
+			return ProxyStale
+		}
+	}
+	return summary
+}
+
+// Add counts the proxy of the pod, listing it as out of sync when it's not synced
+func (nps *NamespaceProxyStatus) Add(pod string, ps ProxyStatus) {
+	status := ps.Summary()
+	switch status {
+	case ProxySynced:
+		nps.Synced++
+		return
+	case ProxyStale:
+		nps.Stale++
+	case ProxyNotSent:
+		nps.NotSent++
+	}
+	nps.OutOfSync = append(nps.OutOfSync, PodProxyStatus{Pod: pod, Status: status, ProxyStatus: ps})
+}
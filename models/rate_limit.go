@@ -0,0 +1,253 @@
+package models
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/kiali/kiali/kubernetes"
+)
+
+// Types of rate limit configured by EnvoyFilters
+const (
+	// LocalRateLimit is enforced by each proxy with a token bucket
+	LocalRateLimit = "local"
+	// GlobalRateLimit is enforced by an external rate limit service (RLS) shared by the proxies
+	GlobalRateLimit = "global"
+)
+
+const (
+	localRateLimitFilter  = "envoy.filters.http.local_ratelimit"
+	globalRateLimitFilter = "envoy.filters.http.ratelimit"
+)
+
+// RateLimits is the inventory of the rate limits applying to the workloads of a namespace
+// swagger:model rateLimits
+type RateLimits struct {
+	// The namespace of the workloads
+	//
+	// required: true
+	Namespace string `json:"namespace"`
+
+	// The rate limits configured by the EnvoyFilters of the namespace and of the root namespace
+	//
+	// required: true
+	RateLimits []RateLimit `json:"rateLimits"`
+
+	// The services of the namespace that no rate limit applies to
+	//
+	// required: true
+	UnlimitedServices []string `json:"unlimitedServices"`
+}
+
+// RateLimit is a rate limit configured by a patch of an EnvoyFilter
+type RateLimit struct {
+	// The name of the EnvoyFilter
+	//
+	// required: true
+	EnvoyFilter string `json:"envoyFilter"`
+
+	// The namespace of the EnvoyFilter
+	//
+	// required: true
+	Namespace string `json:"namespace"`
+
+	// The path of the patch in the EnvoyFilter
+	//
+	// example: spec/configPatches[0]
+	// required: true
+	Path string `json:"path"`
+
+	// Either local or global
+	//
+	// required: true
+	Type string `json:"type"`
+
+	// The listeners patched, like SIDECAR_INBOUND or GATEWAY. Empty when any listener is patched.
+	Context string `json:"context,omitempty"`
+
+	// The labels of the workloads the EnvoyFilter applies to. Empty when it applies to all the workloads of its namespace.
+	WorkloadSelector map[string]string `json:"workloadSelector,omitempty"`
+
+	// The services of the namespace whose workloads are rate limited
+	//
+	// required: true
+	Services []string `json:"services"`
+
+	// The maximum number of tokens of the bucket of a local rate limit
+	MaxTokens *int64 `json:"maxTokens,omitempty"`
+
+	// The number of tokens added to the bucket of a local rate limit at each fill interval
+	TokensPerFill *int64 `json:"tokensPerFill,omitempty"`
+
+	// The interval the bucket of a local rate limit is filled at
+	//
+	// example: 60s
+	FillInterval string `json:"fillInterval,omitempty"`
+
+	// The domain of the descriptors sent to the rate limit service
+	Domain string `json:"domain,omitempty"`
+
+	// The cluster of the rate limit service of a global rate limit
+	RateLimitService string `json:"rateLimitService,omitempty"`
+
+	// When true, requests are denied when the rate limit service can't be reached
+	FailureModeDeny bool `json:"failureModeDeny,omitempty"`
+}
+
+// ParseRateLimits returns the rate limits configured by the patches of the EnvoyFilter, the local
+// rate limit filters being either added to the listeners or configured per route.
+func ParseRateLimits(envoyFilter kubernetes.IstioObject) []RateLimit {
+	rateLimits := []RateLimit{}
+	patches, _ := envoyFilter.GetSpec()["configPatches"].([]interface{})
+	for i, p := range patches {
+		patch, ok := p.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		value := patchMap(patchMap(patch, "patch"), "value")
+		if value == nil {
+			continue
+		}
+
+		filters := map[string]map[string]interface{}{}
+		if name, ok := value["name"].(string); ok {
+			filters[name] = typedConfig(patchMap(value, "typed_config", "typedConfig"))
+		}
+		// Only the local rate limit is fully configured per route, the global one refers to the listener filter
+		perRoute := patchMap(value, "typed_per_filter_config", "typedPerFilterConfig")
+		if config, ok := perRoute[localRateLimitFilter].(map[string]interface{}); ok {
+			filters[localRateLimitFilter] = typedConfig(config)
+		}
+
+		for _, name := range []string{localRateLimitFilter, globalRateLimitFilter} {
+			config, found := filters[name]
+			if !found {
+				continue
+			}
+			rateLimit := RateLimit{
+				EnvoyFilter:      envoyFilter.GetObjectMeta().Name,
+				Namespace:        envoyFilter.GetObjectMeta().Namespace,
+				Path:             fmt.Sprintf("spec/configPatches[%d]", i),
+				WorkloadSelector: envoyFilterSelector(envoyFilter),
+				Services:         []string{},
+			}
+			rateLimit.Context, _ = patchMap(patch, "match")["context"].(string)
+			if name == localRateLimitFilter {
+				rateLimit.Type = LocalRateLimit
+				bucket := patchMap(config, "token_bucket", "tokenBucket")
+				rateLimit.MaxTokens = patchInt64(bucket, "max_tokens", "maxTokens")
+				rateLimit.TokensPerFill = patchInt64(bucket, "tokens_per_fill", "tokensPerFill")
+				rateLimit.FillInterval = patchString(bucket, "fill_interval", "fillInterval")
+			} else {
+				rateLimit.Type = GlobalRateLimit
+				rateLimit.Domain = patchString(config, "domain")
+				rateLimit.RateLimitService = rateLimitServiceCluster(config)
+				rateLimit.FailureModeDeny, _ = patchField(config, "failure_mode_deny", "failureModeDeny").(bool)
+			}
+			rateLimits = append(rateLimits, rateLimit)
+		}
+	}
+	return rateLimits
+}
+
+// rateLimitServiceCluster returns the cluster of the rate limit service of a global rate limit filter configuration
+func rateLimitServiceCluster(config map[string]interface{}) string {
+	service := patchMap(config, "rate_limit_service", "rateLimitService")
+	grpcService := patchMap(service, "grpc_service", "grpcService")
+	return patchString(patchMap(grpcService, "envoy_grpc", "envoyGrpc"), "cluster_name", "clusterName")
+}
+
+// EnvoyFilterClusters returns the names of the clusters added by the EnvoyFilter
+func EnvoyFilterClusters(envoyFilter kubernetes.IstioObject) []string {
+	clusters := []string{}
+	patches, _ := envoyFilter.GetSpec()["configPatches"].([]interface{})
+	for _, p := range patches {
+		patch, _ := p.(map[string]interface{})
+		if applyTo, _ := patch["applyTo"].(string); applyTo != "CLUSTER" {
+			continue
+		}
+		if operation, _ := patchMap(patch, "patch")["operation"].(string); operation != "ADD" {
+			continue
+		}
+		if name := patchString(patchMap(patchMap(patch, "patch"), "value"), "name"); name != "" {
+			clusters = append(clusters, name)
+		}
+	}
+	sort.Strings(clusters)
+	return clusters
+}
+
+// IsIstioCluster returns true when the name has the format of the clusters Istio generates for the services
+// of its registry, like outbound|8081||ratelimit.istio-system.svc.cluster.local
+func IsIstioCluster(name string) bool {
+	parts := strings.Split(name, "|")
+	return len(parts) == 4 && (parts[0] == "outbound" || parts[0] == "inbound") && parts[1] != "" && parts[3] != ""
+}
+
+func envoyFilterSelector(envoyFilter kubernetes.IstioObject) map[string]string {
+	labels := patchMap(envoyFilter.GetSpec(), "workloadSelector")["labels"]
+	selector := map[string]string{}
+	switch l := labels.(type) {
+	case map[string]interface{}:
+		for k, v := range l {
+			selector[k] = fmt.Sprintf("%v", v)
+		}
+	case map[string]string:
+		for k, v := range l {
+			selector[k] = v
+		}
+	}
+	if len(selector) == 0 {
+		return nil
+	}
+	return selector
+}
+
+// typedConfig returns the configuration of a filter, unwrapping it from a TypedStruct when needed
+func typedConfig(config map[string]interface{}) map[string]interface{} {
+	if t, _ := config["@type"].(string); strings.HasSuffix(t, "/udpa.type.v1.TypedStruct") || strings.HasSuffix(t, "/xds.type.v3.TypedStruct") {
+		return patchMap(config, "value")
+	}
+	return config
+}
+
+// patchField returns the first field found among the names, as EnvoyFilter patches accept both snake_case and camelCase fields
+func patchField(m map[string]interface{}, names ...string) interface{} {
+	for _, name := range names {
+		if v, found := m[name]; found {
+			return v
+		}
+	}
+	return nil
+}
+
+func patchMap(m map[string]interface{}, names ...string) map[string]interface{} {
+	v, _ := patchField(m, names...).(map[string]interface{})
+	return v
+}
+
+func patchString(m map[string]interface{}, names ...string) string {
+	v, _ := patchField(m, names...).(string)
+	return v
+}
+
+// patchInt64 returns the integer field, whatever the decoder used to parse it (JSON or YAML)
+func patchInt64(m map[string]interface{}, names ...string) *int64 {
+	var value int64
+	switch v := patchField(m, names...).(type) {
+	case int:
+		value = int64(v)
+	case int64:
+		value = v
+	case float64:
+		if v != math.Trunc(v) {
+			return nil
+		}
+		value = int64(v)
+	default:
+		return nil
+	}
+	return &value
+}
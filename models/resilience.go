@@ -0,0 +1,150 @@
+package models
+
+import (
+	"fmt"
+)
+
+// Sources of the resilience settings of a route
+const (
+	// ResilienceSourceRoute is a setting of the route of a VirtualService
+	ResilienceSourceRoute = "VirtualService"
+	// ResilienceSourceMeshConfig is a setting inherited from the mesh config
+	ResilienceSourceMeshConfig = "MeshConfig"
+	// ResilienceSourceIstioDefault is the behavior hardcoded in Istio, which has no mesh setting
+	ResilienceSourceIstioDefault = "IstioDefault"
+)
+
+// Retry policy of Istio for the routes without retries, when the mesh config doesn't set a default one
+const (
+	istioDefaultRetryAttempts = 2
+	istioDefaultRetryOn       = "connect-failure,refused-stream,unavailable,cancelled,retriable-status-codes"
+)
+
+// MeshResilienceDefaults is the timeout and retry behavior inherited by the HTTP routes which don't set them:
+// - The timeout of the requests has no mesh setting: Istio disables it.
+// - The retries are the defaultHttpRetryPolicy of the mesh config, when set, or the retry policy of Istio:
+// 2 attempts on connection failures and on retriable status codes.
+// - The connect timeout is the connectTimeout of the mesh config, the DestinationRules can override it.
+// swagger:model meshResilienceDefaults
+type MeshResilienceDefaults struct {
+	// The timeout of the requests
+	// required: true
+	Timeout ResilienceTimeout `json:"timeout"`
+
+	// The retries of the requests
+	// required: true
+	Retries ResilienceRetries `json:"retries"`
+
+	// The timeout of the connections to the upstream hosts
+	// required: true
+	ConnectTimeout ResilienceTimeout `json:"connectTimeout"`
+}
+
+// ResilienceTimeout is a timeout, with where it comes from
+type ResilienceTimeout struct {
+	// The timeout, empty when disabled
+	// example: 10s
+	Value string `json:"value"`
+
+	// Where the timeout is set: VirtualService, MeshConfig or IstioDefault
+	// required: true
+	// example: IstioDefault
+	Source string `json:"source"`
+}
+
+// ResilienceRetries are the retries of the requests, with where they come from
+type ResilienceRetries struct {
+	// The number of retries, 0 when disabled
+	// required: true
+	// example: 2
+	Attempts int `json:"attempts"`
+
+	// The timeout of each attempt, the timeout of the request when empty
+	// example: 2s
+	PerTryTimeout string `json:"perTryTimeout,omitempty"`
+
+	// The conditions of the retries
+	// example: connect-failure,refused-stream,unavailable,cancelled,retriable-status-codes
+	RetryOn string `json:"retryOn,omitempty"`
+
+	// Where the retries are set: VirtualService, MeshConfig or IstioDefault
+	// required: true
+	// example: MeshConfig
+	Source string `json:"source"`
+}
+
+// NewMeshResilienceDefaults returns the timeout and retry behavior of the mesh config
+func NewMeshResilienceDefaults(meshConfig *MeshConfig) MeshResilienceDefaults {
+	defaults := MeshResilienceDefaults{
+		Timeout:        ResilienceTimeout{Source: ResilienceSourceIstioDefault},
+		Retries:        ResilienceRetries{Attempts: istioDefaultRetryAttempts, RetryOn: istioDefaultRetryOn, Source: ResilienceSourceIstioDefault},
+		ConnectTimeout: ResilienceTimeout{Value: meshConfig.ConnectTimeout, Source: ResilienceSourceMeshConfig},
+	}
+	// Set by newer Istio versions only
+	if policy, ok := meshConfig.Extra["defaultHttpRetryPolicy"].(map[string]interface{}); ok {
+		defaults.Retries = ParseRetries(policy, ResilienceSourceMeshConfig)
+	}
+	return defaults
+}
+
+// ParseRetries parses a retry policy of a route or of the mesh config. Istio retries on its default conditions when
+// the policy doesn't set them.
+func ParseRetries(policy map[string]interface{}, source string) ResilienceRetries {
+	retries := ResilienceRetries{RetryOn: istioDefaultRetryOn, Source: source}
+	// The attempts are a number in JSON and YAML
+	switch attempts := policy["attempts"].(type) {
+	case float64:
+		retries.Attempts = int(attempts)
+	case int:
+		retries.Attempts = attempts
+	case int64:
+		retries.Attempts = int(attempts)
+	}
+	if perTryTimeout, ok := policy["perTryTimeout"]; ok {
+		retries.PerTryTimeout = fmt.Sprintf("%v", perTryTimeout)
+	}
+	if retryOn, ok := policy["retryOn"].(string); ok && retryOn != "" {
+		retries.RetryOn = retryOn
+	}
+	if retries.Attempts == 0 {
+		retries.PerTryTimeout, retries.RetryOn = "", ""
+	}
+	return retries
+}
+
+// ServiceResilienceConfig is the timeout and retry behavior of the HTTP routes to a service, telling the settings
+// of the routes from the ones inherited from the mesh
+// swagger:model serviceResilienceConfig
+type ServiceResilienceConfig struct {
+	// required: true
+	Namespace string `json:"namespace"`
+
+	// required: true
+	Service string `json:"service"`
+
+	// The behavior of the routes which don't set it, and of the traffic not routed by a VirtualService
+	// required: true
+	Defaults MeshResilienceDefaults `json:"defaults"`
+
+	// The HTTP routes of the VirtualServices to the service
+	// required: true
+	Routes []RouteResilience `json:"routes"`
+}
+
+// RouteResilience is the timeout and retry behavior of an HTTP route of a VirtualService
+type RouteResilience struct {
+	// The VirtualService of the route
+	// required: true
+	VirtualService string `json:"virtualService"`
+
+	// The name of the route, its path in the VirtualService when not named
+	// required: true
+	// example: http[0]
+	Route string `json:"route"`
+
+	// required: true
+	Timeout ResilienceTimeout `json:"timeout"`
+
+	// required: true
+	Retries ResilienceRetries `json:"retries"`
+}
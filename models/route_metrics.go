@@ -0,0 +1,59 @@
+package models
+
+// VirtualServiceRouteMetrics holds the traffic of the HTTP routes of a VirtualService
+// swagger:model virtualServiceRouteMetrics
+type VirtualServiceRouteMetrics struct {
+	// required: true
+	// example: bookinfo
+	Namespace string `json:"namespace"`
+
+	// required: true
+	// example: reviews
+	VirtualService string `json:"virtualService"`
+
+	// The rate interval of the traffic
+	// required: true
+	// example: 10m
+	RateInterval string `json:"rateInterval"`
+
+	// The traffic per HTTP route, in the order of the routes of the VirtualService
+	// required: true
+	Routes []RouteMetrics `json:"routes"`
+
+	// The rate of the requests to the destinations of the VirtualService reported without route name, which can't be
+	// attributed to a route because several routes lead to their destination
+	// required: true
+	UnattributedRate float64 `json:"unattributedRate"`
+}
+
+// RouteMetrics is the traffic of an HTTP route of a VirtualService
+type RouteMetrics struct {
+	// The name of the route, when set
+	// example: reviews-v2-route
+	Name string `json:"name,omitempty"`
+
+	// The path of the route in the VirtualService
+	// required: true
+	// example: spec/http[0]
+	Path string `json:"path"`
+
+	// The hosts of the destinations of the route
+	// required: true
+	Destinations []string `json:"destinations"`
+
+	// False when no traffic was observed for the route in the rate interval
+	// required: true
+	HasTraffic bool `json:"hasTraffic"`
+
+	// required: true
+	RequestRate float64 `json:"requestRate"`
+
+	// The rate of the requests in error: no response, 4xx and 5xx response codes, and grpc errors
+	// required: true
+	ErrorRate float64 `json:"errorRate"`
+
+	// The response times in milliseconds: average and quantiles. Only the average is known for a route whose traffic
+	// is reported for several destination services, the quantiles can't be combined.
+	// required: true
+	Latencies []Stat `json:"latencies"`
+}
@@ -0,0 +1,18 @@
+package models
+
+// ServiceWorkloadMapping maps the services of a namespace to the workloads whose pods they select, and the
+// workloads to the services selecting their pods. A service may select the pods of several workloads, and the pods
+// of a workload may be selected by several services.
+// swagger:model serviceWorkloadMapping
+type ServiceWorkloadMapping struct {
+	// required: true
+	Namespace string `json:"namespace"`
+
+	// The names of the workloads selected by each service
+	// required: true
+	Services map[string][]string `json:"services"`
+
+	// The names of the services selecting each workload
+	// required: true
+	Workloads map[string][]string `json:"workloads"`
+}
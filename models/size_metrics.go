@@ -0,0 +1,58 @@
+package models
+
+import (
+	"time"
+)
+
+// WorkloadSizeMetricsQuery holds the parameters of the request and response sizes of a workload
+type WorkloadSizeMetricsQuery struct {
+	Namespace    string
+	Workload     string
+	Direction    string // inbound | outbound, defaults to inbound if not provided
+	RateInterval string
+	Quantiles    []string
+	QueryTime    time.Time
+}
+
+// FillDefaults fills the struct with default parameters
+func (q *WorkloadSizeMetricsQuery) FillDefaults() {
+	q.Direction = "inbound"
+	q.RateInterval = "10m"
+	q.Quantiles = []string{"0.5", "0.95", "0.99"}
+	q.QueryTime = time.Now()
+}
+
+// WorkloadSizeMetrics holds the sizes of the bodies of the requests and responses of a workload
+// swagger:model workloadSizeMetrics
+type WorkloadSizeMetrics struct {
+	// required: true
+	Namespace string `json:"namespace"`
+
+	// required: true
+	Workload string `json:"workload"`
+
+	// inbound: the requests received by the workload, outbound: the requests sent by the workload
+	// required: true
+	// example: inbound
+	Direction string `json:"direction"`
+
+	// The sizes of the request bodies, from the istio_request_bytes histogram
+	// required: true
+	RequestSizes SizeDistribution `json:"requestSizes"`
+
+	// The sizes of the response bodies, from the istio_response_bytes histogram
+	// required: true
+	ResponseSizes SizeDistribution `json:"responseSizes"`
+}
+
+// SizeDistribution is the distribution of the sizes of the bodies of the requests or of the responses
+type SizeDistribution struct {
+	// The sizes in bytes: average and quantiles
+	// required: true
+	Stats []Stat `json:"stats"`
+
+	// Whether the workload has traffic but the histogram doesn't report its quantiles, as when the metric is
+	// disabled through the Telemetry API or its buckets are dropped. The average may still be known.
+	// required: true
+	HistogramDisabled bool `json:"histogramDisabled"`
+}
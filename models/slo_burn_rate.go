@@ -0,0 +1,89 @@
+package models
+
+import (
+	"time"
+)
+
+// ServiceSLOQuery holds the parameters of the SLO burn rate of a service
+type ServiceSLOQuery struct {
+	Namespace string
+	Service   string
+	Target    float64 // The percentage of successful requests, e.g. 99.9
+	Window    string  // The period of the SLO, e.g. 30d
+	QueryTime time.Time
+}
+
+// FillDefaults fills the struct with default parameters
+func (q *ServiceSLOQuery) FillDefaults() {
+	q.Target = 99.9
+	q.Window = "30d"
+	q.QueryTime = time.Now()
+}
+
+// BurnRateAlert is a multi-window burn rate alert: it fires when the burn rates of both the long and the short windows
+// exceed the threshold, the short window making the alert stop soon after the errors stop.
+type BurnRateAlert struct {
+	// The name of the alert: fast, paging on a sudden loss of the budget, or slow, on a steady loss
+	// required: true
+	// example: fast
+	Name string `json:"name"`
+
+	// required: true
+	// example: 1h
+	LongWindow string `json:"longWindow"`
+
+	// required: true
+	// example: 5m
+	ShortWindow string `json:"shortWindow"`
+
+	// The burn rate above which the alert fires
+	// required: true
+	// example: 14.4
+	Threshold float64 `json:"threshold"`
+
+	// required: true
+	LongBurnRate float64 `json:"longBurnRate"`
+
+	// required: true
+	ShortBurnRate float64 `json:"shortBurnRate"`
+
+	// Whether both burn rates exceed the threshold
+	// required: true
+	Exceeded bool `json:"exceeded"`
+}
+
+// ServiceSLO is the consumption of the error budget of the SLO of a service, from the requests received by the service
+// swagger:model serviceSLO
+type ServiceSLO struct {
+	// required: true
+	Namespace string `json:"namespace"`
+
+	// required: true
+	Service string `json:"service"`
+
+	// The percentage of successful requests of the SLO
+	// required: true
+	// example: 99.9
+	Target float64 `json:"target"`
+
+	// The period of the SLO
+	// required: true
+	// example: 30d
+	Window string `json:"window"`
+
+	// The ratio of the requests in error over the window of the SLO
+	// required: true
+	ErrorRatio float64 `json:"errorRatio"`
+
+	// The ratio of the error budget consumed over the window of the SLO, 1 when the budget is exhausted
+	// required: true
+	BudgetConsumed float64 `json:"budgetConsumed"`
+
+	// The burn rate over the window of the SLO: 1 consumes exactly the budget by the end of the window
+	// required: true
+	BurnRate float64 `json:"burnRate"`
+
+	// The fast and slow burn rate alerts
+	// required: true
+	Alerts []BurnRateAlert `json:"alerts"`
+}
@@ -0,0 +1,35 @@
+package models
+
+import "time"
+
+// Reasons why a workload is stale
+const (
+	// StaleNeverHadTraffic means the workload has no request metrics at all within the history
+	StaleNeverHadTraffic = "never_had_traffic"
+	// StaleWentSilent means the workload had traffic at some point but not within the stale window
+	StaleWentSilent = "went_silent"
+)
+
+// StaleWorkloads is a list of workloads without any inbound or outbound traffic
+// swagger:model staleWorkloads
+type StaleWorkloads []StaleWorkload
+
+// StaleWorkload is a workload without any inbound or outbound traffic for the stale window,
+// hence a candidate for cleanup
+// swagger:model staleWorkload
+type StaleWorkload struct {
+	// Name of the workload
+	// required: true
+	Name string `json:"name"`
+
+	// Type of the workload
+	// required: true
+	Type string `json:"type"`
+
+	// Reason is either never_had_traffic or went_silent
+	// required: true
+	Reason string `json:"reason"`
+
+	// LastSeen is the last time traffic was seen, only set when it happened within the history
+	LastSeen *time.Time `json:"lastSeen,omitempty"`
+}
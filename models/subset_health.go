@@ -0,0 +1,32 @@
+package models
+
+// SubsetHealth is the health of the workloads of a DestinationRule subset of a service, e.g. of the v2 subset
+// during a canary release
+// swagger:model subsetHealth
+type SubsetHealth struct {
+	// required: true
+	Namespace string `json:"namespace"`
+
+	// required: true
+	Service string `json:"service"`
+
+	// required: true
+	Subset string `json:"subset"`
+
+	// The labels of the subset, selecting its pods among the pods of the service
+	//
+	// required: true
+	Labels map[string]string `json:"labels"`
+
+	// The workloads of the subset, which the metrics are scoped to
+	//
+	// required: true
+	Workloads []string `json:"workloads"`
+
+	// The number of pods of the subset. A subset without pod has no metric.
+	//
+	// required: true
+	Pods int `json:"pods"`
+
+	VariantMetrics
+}
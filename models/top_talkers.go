@@ -0,0 +1,65 @@
+package models
+
+import (
+	"time"
+)
+
+// TopTalkersQuery selects the heaviest edges of a namespace
+type TopTalkersQuery struct {
+	Namespace    string
+	Direction    string // inbound | outbound, defaults to outbound if not provided
+	Metric       string // requests | request_bytes | response_bytes, defaults to requests if not provided
+	Limit        int
+	RateInterval string
+	QueryTime    time.Time
+}
+
+// FillDefaults fills the struct with default parameters
+func (q *TopTalkersQuery) FillDefaults() {
+	q.Direction = "outbound"
+	q.Metric = "requests"
+	q.Limit = 10
+	q.RateInterval = "10m"
+	q.QueryTime = time.Now()
+}
+
+// TopTalkers are the edges of a namespace with the highest rates
+// swagger:model topTalkers
+type TopTalkers struct {
+	// The direction of the traffic, from (outbound) or to (inbound) the namespace
+	// example: outbound
+	// required: true
+	Direction string `json:"direction"`
+
+	// The metric of the rates: requests per second, or bytes per second of the requests or of the responses
+	// example: requests
+	// required: true
+	Metric string `json:"metric"`
+
+	// The edges sorted by decreasing rate
+	// required: true
+	Talkers []Talker `json:"talkers"`
+}
+
+// Talker is an edge from a source workload to a destination service
+type Talker struct {
+	// required: true
+	SourceNamespace string `json:"sourceNamespace"`
+
+	// The source workload, "unknown" for the traffic from outside of the mesh
+	// required: true
+	SourceWorkload string `json:"sourceWorkload"`
+
+	// required: true
+	DestinationNamespace string `json:"destinationNamespace"`
+
+	// required: true
+	DestinationService string `json:"destinationService"`
+
+	// The destination workload, "unknown" when the requests didn't reach a workload
+	DestinationWorkload string `json:"destinationWorkload"`
+
+	// The rate of the edge, in requests or bytes per second
+	// required: true
+	Value float64 `json:"value"`
+}
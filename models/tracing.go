@@ -17,3 +17,240 @@ type TracingQuery struct {
 	MinDuration time.Duration
 	Limit       int
 }
+
+// TracingSamplingLimitations explains why the sampling percentages are approximations
+const TracingSamplingLimitations = "Sampling percentages are estimated: request counts are extrapolated from Prometheus rates, " +
+	"traces started close to the window edges may be counted on one side only, traces may have been dropped by the tracing " +
+	"backend retention, and trace queries are capped by the limit, in which case the percentage is a lower bound."
+
+// TracingSampling holds the estimated trace sampling of the services of a namespace
+// swagger:model TracingSampling
+type TracingSampling struct {
+	// The namespace of the services
+	//
+	// required: true
+	Namespace string `json:"namespace"`
+
+	// The duration of the window over which traces and requests were counted
+	//
+	// example: 10m
+	// required: true
+	Window string `json:"window"`
+
+	// The estimated sampling of each service receiving requests
+	//
+	// required: true
+	Services []ServiceSampling `json:"services"`
+
+	// Why the estimates are approximations
+	//
+	// required: true
+	Limitations string `json:"limitations"`
+}
+
+// ServiceSampling compares the requests received by a service with the server spans found for it
+type ServiceSampling struct {
+	// The canonical name of the service
+	//
+	// required: true
+	Service string `json:"service"`
+
+	// The number of requests received during the window, as reported by Istio telemetry
+	//
+	// required: true
+	Requests float64 `json:"requests"`
+
+	// The number of server spans found in the traces of the window
+	//
+	// required: true
+	Spans int `json:"spans"`
+
+	// The estimated percentage of requests that were traced. Nil when no request was received.
+	SamplingPercentage *float64 `json:"samplingPercentage"`
+
+	// When true, the traces query reached its limit and the percentage is a lower bound
+	LimitReached bool `json:"limitReached"`
+}
+
+// TracingTrace is a trace whose spans are organized as a tree, following their parent/child relationships
+// swagger:model TracingTrace
+type TracingTrace struct {
+	// The trace ID
+	//
+	// required: true
+	TraceID string `json:"traceID"`
+
+	// The start of the earliest span, in microseconds since epoch
+	//
+	// required: true
+	StartTime uint64 `json:"startTime"`
+
+	// The time elapsed between the start of the earliest span and the end of the latest one, in microseconds
+	//
+	// required: true
+	Duration uint64 `json:"duration"`
+
+	// The tracing service names of the spans, sorted
+	//
+	// required: true
+	Services []string `json:"services"`
+
+	// The clusters where the spans were found, sorted. The default tracing backend is not listed.
+	//
+	// required: true
+	Clusters []string `json:"clusters"`
+
+	// The number of spans of the trace
+	//
+	// required: true
+	SpansCount int `json:"spansCount"`
+
+	// The spans without parent in the trace, sorted by start time. A span whose parent is missing,
+	// i.e. not reported or stored in another tracing backend, is a root too.
+	//
+	// required: true
+	Roots []*TracingSpan `json:"roots"`
+}
+
+// TracingSpan is a span of a trace with its child spans
+type TracingSpan struct {
+	// The span ID
+	//
+	// required: true
+	SpanID string `json:"spanID"`
+
+	// The ID of the parent span, empty for the spans starting the trace
+	ParentSpanID string `json:"parentSpanID,omitempty"`
+
+	// The operation name
+	//
+	// required: true
+	OperationName string `json:"operationName"`
+
+	// The tracing service name of the process that reported the span
+	//
+	// required: true
+	Service string `json:"service"`
+
+	// The namespace of the workload that reported the span, when known
+	Namespace string `json:"namespace,omitempty"`
+
+	// The Kiali workload that reported the span, when known
+	Workload string `json:"workload,omitempty"`
+
+	// The pod that reported the span, when known
+	Pod string `json:"pod,omitempty"`
+
+	// The cluster of the workload or, when unknown, of the tracing backend storing the span
+	Cluster string `json:"cluster,omitempty"`
+
+	// The start of the span, in microseconds since epoch
+	//
+	// required: true
+	StartTime uint64 `json:"startTime"`
+
+	// The duration of the span, in microseconds
+	//
+	// required: true
+	Duration uint64 `json:"duration"`
+
+	// The tags of the span
+	//
+	// required: true
+	Tags map[string]string `json:"tags"`
+
+	// True when the span is tagged as an error
+	Error bool `json:"error,omitempty"`
+
+	// The child spans, sorted by start time
+	//
+	// required: true
+	Children []*TracingSpan `json:"children"`
+}
+
+const (
+	TracingCheckPassed  = "passed"
+	TracingCheckWarning = "warning"
+	TracingCheckFailed  = "failed"
+	// The check could not be run, e.g. the tracing backend is not reachable
+	TracingCheckUnknown = "unknown"
+)
+
+const (
+	TracingCheckInjection = "injection"
+	TracingCheckProvider  = "provider"
+	TracingCheckSampling  = "sampling"
+	TracingCheckTraces    = "traces"
+)
+
+const (
+	TracingCauseNotInjected           = "notInjected"
+	TracingCauseNoProvider            = "noProvider"
+	TracingCauseSpanReportingDisabled = "spanReportingDisabled"
+	TracingCauseSamplingTooLow        = "samplingTooLow"
+	TracingCauseNoTraces              = "noTraces"
+)
+
+// TracingDiagnosis tells why a workload may have no trace, checking its tracing configuration
+// and the traces found for it
+// swagger:model TracingDiagnosis
+type TracingDiagnosis struct {
+	// The namespace of the workload
+	//
+	// required: true
+	Namespace string `json:"namespace"`
+
+	// The workload name
+	//
+	// required: true
+	Workload string `json:"workload"`
+
+	// The duration of the window over which traces were searched
+	//
+	// example: 10m
+	// required: true
+	Window string `json:"window"`
+
+	// The tracing providers the proxy reports spans to, the legacy tracer of the mesh config being named after its type
+	//
+	// required: true
+	Providers []string `json:"providers"`
+
+	// The percentage of the requests sampled by the proxy
+	//
+	// example: 1
+	// required: true
+	SamplingPercentage float64 `json:"samplingPercentage"`
+
+	// The checks, in the order they were run: injection, provider, sampling, traces
+	//
+	// required: true
+	Checks []TracingCheck `json:"checks"`
+
+	// The cause of the first failed check, or of the first warning when none failed.
+	// Empty when traces were found for the workload.
+	//
+	// example: samplingTooLow
+	LikelyCause string `json:"likelyCause,omitempty"`
+}
+
+// TracingCheck is a step of a tracing diagnosis
+type TracingCheck struct {
+	// The check name: injection, provider, sampling or traces
+	//
+	// required: true
+	Name string `json:"name"`
+
+	// The result of the check: passed, warning, failed or unknown
+	//
+	// required: true
+	Status string `json:"status"`
+
+	// Explains the result of the check
+	//
+	// required: true
+	Message string `json:"message"`
+
+	// The cause of missing traces this check points to, set when it didn't pass
+	Cause string `json:"cause,omitempty"`
+}
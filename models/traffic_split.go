@@ -0,0 +1,109 @@
+package models
+
+import (
+	"fmt"
+
+	"github.com/kiali/kiali/kubernetes"
+)
+
+// TrafficSplits compares the weights configured by the VirtualServices routing to a service with the observed traffic
+// swagger:model trafficSplits
+type TrafficSplits struct {
+	// required: true
+	Namespace string `json:"namespace"`
+
+	// required: true
+	Service string `json:"service"`
+
+	// The weighted routes to the service
+	//
+	// required: true
+	Splits []TrafficSplit `json:"splits"`
+}
+
+// TrafficSplit is a route of a VirtualService splitting the traffic across several destinations
+type TrafficSplit struct {
+	// The name of the VirtualService
+	//
+	// required: true
+	VirtualService string `json:"virtualService"`
+
+	// The path of the route in the VirtualService
+	//
+	// example: spec/http[0]
+	// required: true
+	Path string `json:"path"`
+
+	// http, tcp or tls
+	//
+	// required: true
+	Protocol string `json:"protocol"`
+
+	// The observed request rate (or connection rate for tcp and tls) of the service
+	ObservedRate float64 `json:"observedRate"`
+
+	// True when the observed traffic can be compared with the configured weights: the route is the only route
+	// of the VirtualService to the service, and all its destinations are subsets of the service
+	Comparable bool `json:"comparable"`
+
+	// True when the observed weight of a destination diverges from its configured weight
+	Divergent bool `json:"divergent"`
+
+	// required: true
+	Destinations []SplitDestination `json:"destinations"`
+}
+
+// SplitDestination is a destination of a weighted route
+type SplitDestination struct {
+	// required: true
+	Host string `json:"host"`
+
+	Subset string `json:"subset,omitempty"`
+
+	// The configured weight, in percent
+	//
+	// required: true
+	Weight uint32 `json:"weight"`
+
+	// The observed rate of the destination. Nil when it can't be matched to the workloads of the service.
+	ObservedRate *float64 `json:"observedRate,omitempty"`
+
+	// The observed weight of the destination, in percent. Nil without observed traffic.
+	ObservedWeight *float64 `json:"observedWeight,omitempty"`
+}
+
+// ParseTrafficSplits returns the routes of the VirtualService that split the traffic across several destinations,
+// one of them at least routing to the service
+func ParseTrafficSplits(virtualService kubernetes.IstioObject, namespace, service string) []TrafficSplit {
+	splits := []TrafficSplit{}
+	for _, protocol := range []string{"http", "tcp", "tls"} {
+		routes, _ := virtualService.GetSpec()[protocol].([]interface{})
+		for i, r := range routes {
+			route, _ := r.(map[string]interface{})
+			destinations, _ := route["route"].([]interface{})
+			if len(destinations) < 2 {
+				continue
+			}
+			split := TrafficSplit{
+				VirtualService: virtualService.GetObjectMeta().Name,
+				Path:           fmt.Sprintf("spec/%s[%d]", protocol, i),
+				Protocol:       protocol,
+				Destinations:   []SplitDestination{},
+			}
+			toService := false
+			for _, d := range destinations {
+				destination, _ := d.(map[string]interface{})
+				target, _ := destination["destination"].(map[string]interface{})
+				sd := SplitDestination{Weight: castWeight(destination["weight"])}
+				sd.Host, _ = target["host"].(string)
+				sd.Subset, _ = target["subset"].(string)
+				toService = toService || kubernetes.FilterByHost(sd.Host, service, namespace)
+				split.Destinations = append(split.Destinations, sd)
+			}
+			if toService {
+				splits = append(splits, split)
+			}
+		}
+	}
+	return splits
+}
@@ -0,0 +1,61 @@
+package models
+
+// Reasons for which an Istio object has no effect
+const (
+	UnusedNoMatchingHost     = "NoMatchingHost"
+	UnusedNoGatewayNoMesh    = "NoGatewayNoMeshTraffic"
+	UnusedNoMatchingWorkload = "NoMatchingWorkload"
+	UnusedNotReferenced      = "NotReferenced"
+)
+
+var unusedReasonDescriptions = map[string]string{
+	UnusedNoMatchingHost:     "DestinationRules whose host matches no service of the registry",
+	UnusedNoGatewayNoMesh:    "VirtualServices bound to no existing gateway and matching no mesh traffic",
+	UnusedNoMatchingWorkload: "Policies whose selector matches no workload",
+	UnusedNotReferenced:      "ServiceEntries whose hosts are referenced by no VirtualService or DestinationRule",
+}
+
+// UnusedIstioConfig is the cleanup report of the Istio objects of a namespace that have no effect
+// swagger:model
+type UnusedIstioConfig struct {
+	// The namespace of the report
+	// required: true
+	// example: bookinfo
+	Namespace string `json:"namespace"`
+
+	// The unused objects, grouped by reason. Reasons without objects are not included.
+	// required: true
+	Groups []UnusedIstioConfigGroup `json:"groups"`
+}
+
+// UnusedIstioConfigGroup lists the objects that are unused for the same reason
+type UnusedIstioConfigGroup struct {
+	// The reason for which the objects have no effect
+	// required: true
+	// example: NoMatchingWorkload
+	Reason string `json:"reason"`
+
+	// Description of the reason
+	// example: Policies whose selector matches no workload
+	Description string `json:"description"`
+
+	// The unused objects
+	// required: true
+	Objects []IstioValidationKey `json:"objects"`
+}
+
+// NewUnusedIstioConfig builds the report from the unused objects keyed by reason, in a stable order of reasons
+func NewUnusedIstioConfig(namespace string, unused map[string][]IstioValidationKey) UnusedIstioConfig {
+	report := UnusedIstioConfig{Namespace: namespace, Groups: []UnusedIstioConfigGroup{}}
+	for _, reason := range []string{UnusedNoMatchingHost, UnusedNoGatewayNoMesh, UnusedNoMatchingWorkload, UnusedNotReferenced} {
+		if len(unused[reason]) == 0 {
+			continue
+		}
+		report.Groups = append(report.Groups, UnusedIstioConfigGroup{
+			Reason:      reason,
+			Description: unusedReasonDescriptions[reason],
+			Objects:     unused[reason],
+		})
+	}
+	return report
+}
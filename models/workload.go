@@ -2,6 +2,7 @@ package models
 
 import (
 	"strconv"
+	"time"
 
 	osapps_v1 "github.com/openshift/api/apps/v1"
 	apps_v1 "k8s.io/api/apps/v1"
@@ -116,6 +117,14 @@ type Workload struct {
 
 	// Additional details to display, such as configured annotations
 	AdditionalDetails []AdditionalItem `json:"additionalDetails"`
+
+	// End of the maintenance of the workload, set by the maintenance annotation of the controller
+	// required: false
+	MaintenanceUntil *time.Time `json:"maintenanceUntil,omitempty"`
+
+	// Images run by the pods of the workload, with their registry and resolved digests
+	// required: false
+	Images *WorkloadImages `json:"images,omitempty"`
 }
 
 type Workloads []*Workload
@@ -127,6 +136,7 @@ func (workload *WorkloadListItem) ParseWorkload(w *Workload) {
 	workload.CreatedAt = w.CreatedAt
 	workload.ResourceVersion = w.ResourceVersion
 	workload.IstioSidecar = w.HasIstioSidecar()
+	workload.IstioInjectionAnnotation = w.IstioInjectionAnnotation
 	workload.Labels = w.Labels
 	workload.PodCount = len(w.Pods)
 	workload.AdditionalDetailSample = w.AdditionalDetailSample
@@ -162,6 +172,17 @@ func (workload *Workload) parseObjectMeta(meta *meta_v1.ObjectMeta, tplMeta *met
 	workload.ResourceVersion = meta.ResourceVersion
 	workload.AdditionalDetails = GetAdditionalDetails(conf, meta.Annotations)
 	workload.AdditionalDetailSample = GetFirstAdditionalIcon(conf, meta.Annotations)
+	if until, exist := meta.Annotations[conf.HealthConfig.Maintenance.Annotation]; exist {
+		// An invalid end of maintenance is ignored, the workload is not in maintenance
+		if t, err := time.Parse(time.RFC3339, until); err == nil {
+			workload.MaintenanceUntil = &t
+		}
+	}
+}
+
+// InMaintenance returns true when the maintenance of the workload didn't expire at the given time
+func (workload *Workload) InMaintenance(now time.Time) bool {
+	return workload.MaintenanceUntil != nil && now.Before(*workload.MaintenanceUntil)
 }
 
 func (workload *Workload) ParseDeployment(d *apps_v1.Deployment) {
@@ -0,0 +1,84 @@
+package models
+
+// WorkloadComparison holds two workloads side by side: their inbound metrics, over the same time window, and the
+// differences of their configuration
+// swagger:model workloadComparison
+type WorkloadComparison struct {
+	// Rate interval of the metrics of both workloads
+	// example: 10m
+	RateInterval string `json:"rateInterval"`
+	// Unix time (seconds) ending the rate interval of the metrics of both workloads
+	QueryTime int64 `json:"queryTime"`
+	// Left workload of the comparison
+	// required: true
+	Left WorkloadComparisonSide `json:"left"`
+	// Right workload of the comparison
+	// required: true
+	Right WorkloadComparisonSide `json:"right"`
+	// Differences of the metrics (right minus left), nil when a side has no metrics
+	Delta *VariantMetricsDelta `json:"delta"`
+	// Configuration fields with different values on each side
+	// required: true
+	ConfigDiffs []ConfigDiff `json:"configDiffs"`
+}
+
+// WorkloadComparisonSide is one of the compared workloads
+type WorkloadComparisonSide struct {
+	// required: true
+	Namespace string `json:"namespace"`
+	// required: true
+	Workload string `json:"workload"`
+	// Type of the workload controller
+	// example: Deployment
+	Type string `json:"type"`
+	// Number of desired replicas
+	DesiredReplicas int32 `json:"desiredReplicas"`
+	// Number of available replicas
+	AvailableReplicas int32 `json:"availableReplicas"`
+	// Inbound metrics, nil when the workload didn't receive traffic in the rate interval
+	Metrics *VariantMetrics `json:"metrics"`
+}
+
+// VariantMetricsDelta holds the differences of the metrics of two variants
+type VariantMetricsDelta struct {
+	// Difference of the inbound requests per second
+	RequestRate float64 `json:"requestRate"`
+	// Difference of the success ratios, nil when a side has no success rate
+	SuccessRate *float64 `json:"successRate"`
+	// Differences of the request duration (ms) quantiles and average reported on both sides
+	Latencies []Stat `json:"latencies"`
+}
+
+// ConfigDiff is a configuration field with a different value on each side, empty when unset
+type ConfigDiff struct {
+	// Field of the configuration, like labels[version] or containers[reviews].image
+	// required: true
+	Field string `json:"field"`
+	Left  string `json:"left"`
+	Right string `json:"right"`
+}
+
+// NewVariantMetricsDelta returns the differences of the right metrics minus the left ones, nil when a side is nil
+func NewVariantMetricsDelta(left, right *VariantMetrics) *VariantMetricsDelta {
+	if left == nil || right == nil {
+		return nil
+	}
+	delta := &VariantMetricsDelta{
+		RequestRate: right.RequestRate - left.RequestRate,
+		Latencies:   []Stat{},
+	}
+	if left.SuccessRate != nil && right.SuccessRate != nil {
+		successRate := *right.SuccessRate - *left.SuccessRate
+		delta.SuccessRate = &successRate
+	}
+	leftLatencies := make(map[string]float64, len(left.Latencies))
+	for _, stat := range left.Latencies {
+		leftLatencies[stat.Name] = stat.Value
+	}
+	for _, stat := range right.Latencies {
+		if value, found := leftLatencies[stat.Name]; found {
+			delta.Latencies = append(delta.Latencies, Stat{Name: stat.Name, Value: stat.Value - value})
+		}
+	}
+	return delta
+}
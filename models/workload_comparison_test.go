@@ -0,0 +1,25 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewVariantMetricsDelta(t *testing.T) {
+	assert := assert.New(t)
+
+	success := 0.95
+	left := &VariantMetrics{RequestRate: 4, SuccessRate: &success, Latencies: []Stat{{Name: "0.5", Value: 10}, {Name: "avg", Value: 12}}}
+	right := &VariantMetrics{RequestRate: 6, Latencies: []Stat{{Name: "avg", Value: 20}, {Name: "0.99", Value: 80}}}
+
+	delta := NewVariantMetricsDelta(left, right)
+	assert.Equal(2.0, delta.RequestRate)
+	// No success rate on the right side
+	assert.Nil(delta.SuccessRate)
+	// Only the stats of both sides are compared
+	assert.Equal([]Stat{{Name: "avg", Value: 8}}, delta.Latencies)
+
+	assert.Nil(NewVariantMetricsDelta(nil, right))
+	assert.Nil(NewVariantMetricsDelta(left, nil))
+}
@@ -0,0 +1,49 @@
+package models
+
+// The sources of the value of an environment variable
+const (
+	EnvSourceValue     = "value"
+	EnvSourceConfigMap = "configMap"
+	EnvSourceSecret    = "secret"
+	EnvSourceField     = "field"
+	EnvSourceResource  = "resource"
+)
+
+// WorkloadEnv holds the environment of the containers of a pod
+// swagger:model workloadEnv
+type WorkloadEnv struct {
+	Namespace  string         `json:"namespace"`
+	Pod        string         `json:"pod"`
+	Containers []ContainerEnv `json:"containers"`
+}
+
+// ContainerEnv holds the environment of a container, resolved as Kubernetes does: the variables of the env list
+// override the ones of the envFrom sources, and later envFrom sources override earlier ones. The $(VAR) references
+// are left unexpanded.
+type ContainerEnv struct {
+	Name string   `json:"name"`
+	Env  []EnvVar `json:"env"`
+	// Why the variables of an envFrom source couldn't be listed, like a missing ConfigMap
+	Errors []string `json:"errors,omitempty"`
+}
+
+// EnvVar holds an environment variable and where its value comes from.
+// The values taken from a Secret are never returned.
+type EnvVar struct {
+	// Name of the variable
+	// required: true
+	Name string `json:"name"`
+	// Value of the variable, empty when masked or unresolved
+	Value string `json:"value"`
+	// Source of the value: value, configMap, secret, field or resource
+	// example: configMap
+	Source string `json:"source"`
+	// Name of the ConfigMap or Secret, path of the field or name of the resource holding the value
+	SourceName string `json:"sourceName,omitempty"`
+	// Key of the ConfigMap or Secret holding the value
+	SourceKey string `json:"sourceKey,omitempty"`
+	// The value is hidden as it comes from a Secret
+	Masked bool `json:"masked"`
+	// Why the value couldn't be resolved, like a missing ConfigMap or key
+	Error string `json:"error,omitempty"`
+}
@@ -0,0 +1,161 @@
+package models
+
+import (
+	"sort"
+	"strings"
+)
+
+const (
+	// DefaultImageRegistry is the registry of the image references without registry
+	DefaultImageRegistry = "docker.io"
+	// DefaultImageTag is the tag of the image references without tag nor digest
+	DefaultImageTag = "latest"
+)
+
+// mutableImageTags are the tags conventionally moved to newer images, so that the image run by a pod depends on the
+// time it was pulled
+var mutableImageTags = map[string]bool{
+	"latest":  true,
+	"main":    true,
+	"master":  true,
+	"nightly": true,
+	"dev":     true,
+}
+
+// WorkloadImages are the images run by the containers of the pods of a workload, for supply-chain visibility
+type WorkloadImages struct {
+	// The images of the application containers, sorted by container
+	// required: true
+	Containers []ContainerImage `json:"containers"`
+
+	// The image of the istio-proxy container, nil without sidecar
+	Proxy *ContainerImage `json:"proxy,omitempty"`
+}
+
+// ContainerImage is the image reference of a container and the digests it resolved to in the pods
+type ContainerImage struct {
+	// Name of the container
+	// required: true
+	// example: reviews
+	Container string `json:"container"`
+
+	// The image reference of the container spec
+	// required: true
+	// example: docker.io/istio/examples-bookinfo-reviews-v1:1.16.2
+	Image string `json:"image"`
+
+	// The registry of the image, docker.io when the reference has none
+	// required: true
+	// example: docker.io
+	Registry string `json:"registry"`
+
+	// The repository of the image in the registry
+	// required: true
+	// example: istio/examples-bookinfo-reviews-v1
+	Repository string `json:"repository"`
+
+	// The tag of the image, empty when the image is only referenced by digest
+	// example: 1.16.2
+	Tag string `json:"tag,omitempty"`
+
+	// The digest pinned by the image reference, if any
+	Digest string `json:"digest,omitempty"`
+
+	// The digests of the images resolved by the pods, from their status. Several digests for a tag mean that the
+	// pods run different images.
+	// required: true
+	ResolvedDigests []string `json:"resolvedDigests"`
+
+	// True when the image is referenced by a tag like latest, without digest
+	// required: true
+	MutableTag bool `json:"mutableTag"`
+}
+
+// NewWorkloadImages returns the images run by the pods. The images of a container are expected to be the same in all
+// the pods of a workload, the first pod gives the image reference.
+func NewWorkloadImages(pods Pods) *WorkloadImages {
+	images := &WorkloadImages{Containers: []ContainerImage{}}
+	containers := map[string]*ContainerImage{}
+	for _, pod := range pods {
+		for _, c := range pod.Containers {
+			image, found := containers[c.Name]
+			if !found {
+				image = NewContainerImage(c.Name, c.Image)
+				containers[c.Name] = image
+			}
+			image.addResolvedDigest(c.ImageID)
+		}
+		// The proxy is an init container when running as a native sidecar
+		for _, istioContainers := range [][]*ContainerInfo{pod.IstioContainers, pod.IstioInitContainers} {
+			for _, c := range istioContainers {
+				if c.Name != IstioProxyContainer {
+					continue
+				}
+				if images.Proxy == nil {
+					images.Proxy = NewContainerImage(c.Name, c.Image)
+				}
+				images.Proxy.addResolvedDigest(c.ImageID)
+			}
+		}
+	}
+	for _, image := range containers {
+		images.Containers = append(images.Containers, *image)
+	}
+	sort.Slice(images.Containers, func(i, j int) bool {
+		return images.Containers[i].Container < images.Containers[j].Container
+	})
+	return images
+}
+
+// NewContainerImage parses an image reference: [registry/]repository[:tag][@digest]
+func NewContainerImage(container, image string) *ContainerImage {
+	ci := &ContainerImage{Container: container, Image: image, ResolvedDigests: []string{}}
+	name := image
+	if i := strings.Index(name, "@"); i >= 0 {
+		ci.Digest = name[i+1:]
+		name = name[:i]
+	}
+	// A colon after the last slash separates the tag, a colon before is the port of the registry
+	if i := strings.LastIndex(name, ":"); i > strings.LastIndex(name, "/") {
+		ci.Tag = name[i+1:]
+		name = name[:i]
+	}
+	if ci.Tag == "" && ci.Digest == "" {
+		ci.Tag = DefaultImageTag
+	}
+
+	// The first component is a registry when it looks like a host, as done by the container runtimes
+	ci.Registry = DefaultImageRegistry
+	ci.Repository = name
+	if i := strings.Index(name, "/"); i >= 0 {
+		host := name[:i]
+		if strings.ContainsAny(host, ".:") || host == "localhost" {
+			ci.Registry = host
+			ci.Repository = name[i+1:]
+		}
+	}
+	if ci.Registry == DefaultImageRegistry && !strings.Contains(ci.Repository, "/") {
+		ci.Repository = "library/" + ci.Repository
+	}
+
+	ci.MutableTag = ci.Digest == "" && mutableImageTags[ci.Tag]
+	return ci
+}
+
+// addResolvedDigest adds the digest of the image ID of a container status, e.g.
+// docker-pullable://docker.io/istio/proxyv2@sha256:...
+func (ci *ContainerImage) addResolvedDigest(imageID string) {
+	i := strings.LastIndex(imageID, "@")
+	if i < 0 {
+		// Not resolved yet, or a local image without repository digest
+		return
+	}
+	digest := imageID[i+1:]
+	for _, d := range ci.ResolvedDigests {
+		if d == digest {
+			return
+		}
+	}
+	ci.ResolvedDigests = append(ci.ResolvedDigests, digest)
+	sort.Strings(ci.ResolvedDigests)
+}
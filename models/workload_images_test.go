@@ -0,0 +1,34 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewContainerImage(t *testing.T) {
+	assert := assert.New(t)
+
+	cases := []struct {
+		image, registry, repository, tag, digest string
+		mutable                                  bool
+	}{
+		{"nginx", "docker.io", "library/nginx", "latest", "", true},
+		{"nginx:1.21", "docker.io", "library/nginx", "1.21", "", false},
+		{"istio/proxyv2:1.12.1", "docker.io", "istio/proxyv2", "1.12.1", "", false},
+		{"quay.io/kiali/kiali:latest", "quay.io", "kiali/kiali", "latest", "", true},
+		{"localhost/app:dev", "localhost", "app", "dev", "", true},
+		{"localhost:5000/team/app", "localhost:5000", "team/app", "latest", "", true},
+		{"gcr.io/istio-release/proxyv2@sha256:3a6a5b8d", "gcr.io", "istio-release/proxyv2", "", "sha256:3a6a5b8d", false},
+		// Pinned by digest, the tag is informative
+		{"quay.io/kiali/kiali:latest@sha256:3a6a5b8d", "quay.io", "kiali/kiali", "latest", "sha256:3a6a5b8d", false},
+	}
+	for _, c := range cases {
+		image := NewContainerImage("c", c.image)
+		assert.Equal(c.registry, image.Registry, c.image)
+		assert.Equal(c.repository, image.Repository, c.image)
+		assert.Equal(c.tag, image.Tag, c.image)
+		assert.Equal(c.digest, image.Digest, c.image)
+		assert.Equal(c.mutable, image.MutableTag, c.image)
+	}
+}
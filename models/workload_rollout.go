@@ -0,0 +1,79 @@
+package models
+
+import "time"
+
+// Statuses of the rollout of a workload
+const (
+	RolloutComplete    = "Complete"
+	RolloutProgressing = "Progressing"
+	RolloutPaused      = "Paused"
+	RolloutStuck       = "Stuck"
+)
+
+// WorkloadRolloutStatus is the progress of the rollout of the last revision of a Deployment or a StatefulSet
+// swagger:model workloadRolloutStatus
+type WorkloadRolloutStatus struct {
+	// required: true
+	// example: bookinfo
+	Namespace string `json:"namespace"`
+
+	// required: true
+	// example: reviews-v1
+	Workload string `json:"workload"`
+
+	// Deployment or StatefulSet
+	// required: true
+	// example: Deployment
+	Type string `json:"type"`
+
+	// Complete, Progressing, Paused or Stuck
+	// required: true
+	// example: Progressing
+	Status string `json:"status"`
+
+	// Why the rollout is not complete, empty when it is
+	// example: Waiting for rollout to finish: 1 of 3 updated replicas are available
+	Message string `json:"message,omitempty"`
+
+	// True when the rollout made no progress within the progress deadline of the Deployment. The StatefulSets have no
+	// progress deadline, their rollout is never stuck.
+	// required: true
+	Stuck bool `json:"stuck"`
+
+	// required: true
+	DesiredReplicas int32 `json:"desiredReplicas"`
+
+	// Number of replicas of the last revision
+	// required: true
+	UpdatedReplicas int32 `json:"updatedReplicas"`
+
+	// Number of replicas available to serve requests: the ready ones for the StatefulSets
+	// required: true
+	AvailableReplicas int32 `json:"availableReplicas"`
+
+	// required: true
+	UnavailableReplicas int32 `json:"unavailableReplicas"`
+
+	// The Progressing condition of the Deployment, none for the StatefulSets
+	Condition *RolloutCondition `json:"condition,omitempty"`
+}
+
+// RolloutCondition is the condition of the controller reporting the progress of its rollout
+type RolloutCondition struct {
+	// required: true
+	// example: Progressing
+	Type string `json:"type"`
+
+	// True, False or Unknown
+	// required: true
+	// example: True
+	Status string `json:"status"`
+
+	// example: ReplicaSetUpdated
+	Reason string `json:"reason,omitempty"`
+
+	Message string `json:"message,omitempty"`
+
+	// The last time the condition was updated
+	LastUpdateTime time.Time `json:"lastUpdateTime"`
+}
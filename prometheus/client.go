@@ -30,6 +30,10 @@ type ClientInterface interface {
 	FetchHistogramValues(metricName, labels, grouping, rateInterval string, avg bool, quantiles []string, queryTime time.Time) (map[string]model.Vector, error)
 	FetchRange(metricName, labels, grouping, aggregator string, q *RangeQuery) Metric
 	FetchRateRange(metricName string, labels []string, grouping string, q *RangeQuery) Metric
+	FetchRateValues(metricName, labels, grouping, rateInterval string, queryTime time.Time) (model.Vector, error)
+	FetchTopRateValues(metricName, labels, grouping, rateInterval string, limit int, queryTime time.Time) (model.Vector, error)
+	FetchValues(metricName, labels, grouping string, queryTime time.Time) (model.Vector, error)
+	FetchExemplars(metricName, labels string, start, end time.Time) ([]ExemplarQueryResult, error)
 	GetAllRequestRates(namespace, ratesInterval string, queryTime time.Time) (model.Vector, error)
 	GetAppRequestRates(namespace, app, ratesInterval string, queryTime time.Time) (model.Vector, model.Vector, error)
 	GetConfiguration() (prom_v1.ConfigResult, error)
@@ -37,7 +41,9 @@ type ClientInterface interface {
 	GetNamespaceServicesRequestRates(namespace, ratesInterval string, queryTime time.Time) (model.Vector, error)
 	GetServiceRequestRates(namespace, service, ratesInterval string, queryTime time.Time) (model.Vector, error)
 	GetWorkloadRequestRates(namespace, workload, ratesInterval string, queryTime time.Time) (model.Vector, model.Vector, error)
+	GetWorkloadsTrafficHistory(namespace string, history, step time.Duration, queryTime time.Time) (model.Matrix, model.Matrix, error)
 	GetMetricsForLabels(labels []string) ([]string, error)
+	GetMetricMetadata(metricName string) ([]prom_v1.Metadata, error)
 }
 
 // Client for Prometheus API.
@@ -47,6 +53,8 @@ type Client struct {
 	p8s api.Client
 	api prom_v1.API
 	ctx context.Context
+	// dedupLabel is the external label distinguishing the series of the federated Prometheus, if any
+	dedupLabel string
 }
 
 var once sync.Once
@@ -74,6 +82,8 @@ func NewClientForConfig(cfg config.PrometheusConfig) (*Client, error) {
 
 	// Prom Cache will be initialized once at first use of Prometheus Client
 	once.Do(initPromCache)
+	// Query limiter is shared by all the clients, so the limit is global to Kiali
+	limiterOnce.Do(initQueryLimiter)
 
 	// Be sure to copy config.Auth and not modify the existing
 	auth := cfg.Auth
@@ -104,13 +114,13 @@ func NewClientForConfig(cfg config.PrometheusConfig) (*Client, error) {
 	if err != nil {
 		return nil, err
 	}
-	clientConfig.RoundTripper = transportConfig
+	clientConfig.RoundTripper = queryLimiter.RoundTripper(transportConfig)
 
 	p8s, err := api.NewClient(clientConfig)
 	if err != nil {
 		return nil, err
 	}
-	client := Client{p8s: p8s, api: prom_v1.NewAPI(p8s), ctx: context.Background()}
+	client := Client{p8s: p8s, api: prom_v1.NewAPI(p8s), ctx: context.Background(), dedupLabel: cfg.DedupLabel}
 	return &client, nil
 }
 
@@ -131,7 +141,7 @@ func (in *Client) GetAllRequestRates(namespace string, ratesInterval string, que
 			return result, nil
 		}
 	}
-	result, err := getAllRequestRates(in.ctx, in.api, namespace, queryTime, ratesInterval)
+	result, err := getAllRequestRates(in.ctx, in.api, in.dedupLabel, namespace, queryTime, ratesInterval)
 	if err != nil {
 		return result, err
 	}
@@ -153,7 +163,7 @@ func (in *Client) GetNamespaceServicesRequestRates(namespace string, ratesInterv
 			return result, nil
 		}
 	}
-	result, err := getNamespaceServicesRequestRates(in.ctx, in.api, namespace, queryTime, ratesInterval)
+	result, err := getNamespaceServicesRequestRates(in.ctx, in.api, in.dedupLabel, namespace, queryTime, ratesInterval)
 	if err != nil {
 		return result, err
 	}
@@ -175,7 +185,7 @@ func (in *Client) GetServiceRequestRates(namespace, service, ratesInterval strin
 			return result, nil
 		}
 	}
-	result, err := getServiceRequestRates(in.ctx, in.api, namespace, service, queryTime, ratesInterval)
+	result, err := getServiceRequestRates(in.ctx, in.api, in.dedupLabel, namespace, service, queryTime, ratesInterval)
 	if err != nil {
 		return result, err
 	}
@@ -197,7 +207,7 @@ func (in *Client) GetAppRequestRates(namespace, app, ratesInterval string, query
 			return inResult, outResult, nil
 		}
 	}
-	inResult, outResult, err := getItemRequestRates(in.ctx, in.api, namespace, app, "app", queryTime, ratesInterval)
+	inResult, outResult, err := getItemRequestRates(in.ctx, in.api, in.dedupLabel, namespace, app, "app", queryTime, ratesInterval)
 	if err != nil {
 		return inResult, outResult, err
 	}
@@ -219,7 +229,7 @@ func (in *Client) GetWorkloadRequestRates(namespace, workload, ratesInterval str
 			return inResult, outResult, nil
 		}
 	}
-	inResult, outResult, err := getItemRequestRates(in.ctx, in.api, namespace, workload, "workload", queryTime, ratesInterval)
+	inResult, outResult, err := getItemRequestRates(in.ctx, in.api, in.dedupLabel, namespace, workload, "workload", queryTime, ratesInterval)
 	if err != nil {
 		return inResult, outResult, err
 	}
@@ -229,9 +239,22 @@ func (in *Client) GetWorkloadRequestRates(namespace, workload, ratesInterval str
 	return inResult, outResult, nil
 }
 
+// GetWorkloadsTrafficHistory queries Prometheus to fetch the number of requests received (in) and sent (out)
+// by each workload of the namespace, per step, over the history ending at queryTime.
+// A workload without any series has not had traffic within the history (or since its counters were reset).
+// Returns (in, out, error)
+func (in *Client) GetWorkloadsTrafficHistory(namespace string, history, step time.Duration, queryTime time.Time) (model.Matrix, model.Matrix, error) {
+	log.Tracef("GetWorkloadsTrafficHistory [namespace: %s] [history: %s] [step: %s] [queryTime: %s]", namespace, history, step, queryTime.String())
+	return getWorkloadsTrafficHistory(in.ctx, in.api, in.dedupLabel, namespace, history, step, queryTime)
+}
+
 // FetchRange fetches a simple metric (gauge or counter) in given range
 func (in *Client) FetchRange(metricName, labels, grouping, aggregator string, q *RangeQuery) Metric {
-	query := fmt.Sprintf("%s(%s%s)", aggregator, metricName, labels)
+	series := metricName + labels
+	if q.Offset != "" {
+		series += " offset " + q.Offset
+	}
+	query := fmt.Sprintf("%s(%s)", aggregator, dedupSeries(series, in.dedupLabel))
 	if grouping != "" {
 		query += fmt.Sprintf(" by (%s)", grouping)
 	}
@@ -241,17 +264,38 @@ func (in *Client) FetchRange(metricName, labels, grouping, aggregator string, q
 
 // FetchRateRange fetches a counter's rate in given range
 func (in *Client) FetchRateRange(metricName string, labels []string, grouping string, q *RangeQuery) Metric {
-	return fetchRateRange(in.ctx, in.api, metricName, labels, grouping, q)
+	return fetchRateRange(in.ctx, in.api, in.dedupLabel, metricName, labels, grouping, q)
 }
 
 // FetchHistogramRange fetches bucketed metric as histogram in given range
 func (in *Client) FetchHistogramRange(metricName, labels, grouping string, q *RangeQuery) Histogram {
-	return fetchHistogramRange(in.ctx, in.api, metricName, labels, grouping, q)
+	return fetchHistogramRange(in.ctx, in.api, in.dedupLabel, metricName, labels, grouping, q)
 }
 
 // FetchHistogramValues fetches bucketed metric as histogram at a given specific time
 func (in *Client) FetchHistogramValues(metricName, labels, grouping, rateInterval string, avg bool, quantiles []string, queryTime time.Time) (map[string]model.Vector, error) {
-	return fetchHistogramValues(in.ctx, in.api, metricName, labels, grouping, rateInterval, avg, quantiles, queryTime)
+	return fetchHistogramValues(in.ctx, in.api, in.dedupLabel, metricName, labels, grouping, rateInterval, avg, quantiles, queryTime)
+}
+
+// FetchRateValues fetches a counter's rate at a given specific time
+func (in *Client) FetchRateValues(metricName, labels, grouping, rateInterval string, queryTime time.Time) (model.Vector, error) {
+	return fetchRateValues(in.ctx, in.api, in.dedupLabel, metricName, labels, grouping, rateInterval, queryTime)
+}
+
+// FetchTopRateValues fetches the limit highest rates of a counter at a given specific time, grouped by the given labels
+func (in *Client) FetchTopRateValues(metricName, labels, grouping, rateInterval string, limit int, queryTime time.Time) (model.Vector, error) {
+	return fetchTopRateValues(in.ctx, in.api, in.dedupLabel, metricName, labels, grouping, rateInterval, limit, queryTime)
+}
+
+// FetchValues fetches the sum of a gauge metric at the given time, grouped by the given labels.
+func (in *Client) FetchValues(metricName, labels, grouping string, queryTime time.Time) (model.Vector, error) {
+	return fetchValues(in.ctx, in.api, in.dedupLabel, metricName, labels, grouping, queryTime)
+}
+
+// FetchExemplars returns the exemplars of the series of the metric matching the labels, over the time range.
+// It returns ErrExemplarsNotSupported when the backend doesn't support them.
+func (in *Client) FetchExemplars(metricName, labels string, start, end time.Time) ([]ExemplarQueryResult, error) {
+	return fetchExemplars(in.ctx, in.p8s, metricName+labels, start, end)
 }
 
 // API returns the Prometheus V1 HTTP API for performing calls not supported natively by this client
@@ -276,6 +320,11 @@ func (in *Client) GetContext() context.Context {
 	return in.ctx
 }
 
+// DedupRates deduplicates the rates of a query built outside of this package, for federated Prometheus
+func (in *Client) DedupRates(query string) string {
+	return dedupRates(query, in.dedupLabel)
+}
+
 func (in *Client) GetFlags() (prom_v1.FlagsResult, error) {
 	flags, err := in.API().Flags(in.ctx)
 	if err != nil {
@@ -306,6 +355,16 @@ func (in *Client) GetMetricsForLabels(labels []string) ([]string, error) {
 	return names, nil
 }
 
+// GetMetricMetadata returns the metadata of the metric known by the Prometheus targets, empty when the metric doesn't exist
+func (in *Client) GetMetricMetadata(metricName string) ([]prom_v1.Metadata, error) {
+	log.Tracef("[Prom] GetMetricMetadata: %s", metricName)
+	metadata, err := in.api.Metadata(in.ctx, metricName, "")
+	if err != nil {
+		return nil, err
+	}
+	return metadata[metricName], nil
+}
+
 // SanitizeLabelName replaces anything that doesn't match invalidLabelCharRE with an underscore.
 // Copied from https://github.com/prometheus/prometheus/blob/df80dc4d3970121f2f76cba79050983ffb3cdbb0/util/strutil/strconv.go
 func SanitizeLabelName(name string) string {
@@ -0,0 +1,68 @@
+package prometheus
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/api"
+	"github.com/prometheus/common/model"
+
+	"github.com/kiali/kiali/log"
+)
+
+// ErrExemplarsNotSupported is returned by the backends without the exemplars API (Prometheus before 2.26)
+var ErrExemplarsNotSupported = errors.New("exemplars are not supported by the Prometheus backend")
+
+// Exemplar is a sample of a series linked to the trace of a request, through its labels
+type Exemplar struct {
+	Labels    model.LabelSet    `json:"labels"`
+	Value     model.SampleValue `json:"value"`
+	Timestamp model.Time        `json:"timestamp"`
+}
+
+// ExemplarQueryResult holds the exemplars of a series
+type ExemplarQueryResult struct {
+	SeriesLabels model.LabelSet `json:"seriesLabels"`
+	Exemplars    []Exemplar     `json:"exemplars"`
+}
+
+// The exemplars API is not part of the client_golang version in use, it is called directly
+func fetchExemplars(ctx context.Context, client api.Client, query string, start, end time.Time) ([]ExemplarQueryResult, error) {
+	log.Tracef("[Prom] fetchExemplars: %s", query)
+	u := client.URL("/api/v1/query_exemplars", nil)
+	params := u.Query()
+	params.Set("query", query)
+	params.Set("start", start.Format(time.RFC3339Nano))
+	params.Set("end", end.Format(time.RFC3339Nano))
+	u.RawQuery = params.Encode()
+
+	req, err := http.NewRequest(http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, body, err := client.Do(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrExemplarsNotSupported
+	}
+
+	var result struct {
+		Status    string                `json:"status"`
+		Data      []ExemplarQueryResult `json:"data"`
+		ErrorType string                `json:"errorType"`
+		Error     string                `json:"error"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("cannot parse the exemplars of [%s]: %w", query, err)
+	}
+	if result.Status != "success" {
+		return nil, fmt.Errorf("exemplars query [%s] failed: %s: %s", query, result.ErrorType, result.Error)
+	}
+	return result.Data, nil
+}
@@ -21,6 +21,9 @@ const (
 	labelPackage          = "package"
 	labelType             = "type"
 	labelFunction         = "function"
+	labelNamespace        = "namespace"
+	labelSeverity         = "severity"
+	labelStatus           = "status"
 )
 
 // MetricsType defines all of Kiali's own internal metrics.
@@ -34,6 +37,8 @@ type MetricsType struct {
 	GoFunctionProcessingTime *prometheus.HistogramVec
 	GoFunctionFailures       *prometheus.CounterVec
 	KubernetesClients        *prometheus.GaugeVec
+	MeshValidations          *prometheus.GaugeVec
+	MeshMTLSStatus           *prometheus.GaugeVec
 }
 
 // Metrics contains all of Kiali's own internal metrics.
@@ -103,6 +108,20 @@ var Metrics = MetricsType{
 		},
 		[]string{},
 	),
+	MeshValidations: prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "kiali_mesh_validations",
+			Help: "The number of Istio config validations per namespace and severity.",
+		},
+		[]string{labelNamespace, labelSeverity},
+	),
+	MeshMTLSStatus: prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "kiali_mesh_mtls_status",
+			Help: "The mesh-wide mTLS status, the current status has a value of 1.",
+		},
+		[]string{labelStatus},
+	),
 }
 
 // SuccessOrFailureMetricType let's you capture metrics for both successes and failures,
@@ -160,6 +179,8 @@ func RegisterInternalMetrics() {
 		Metrics.GoFunctionProcessingTime,
 		Metrics.GoFunctionFailures,
 		Metrics.KubernetesClients,
+		Metrics.MeshValidations,
+		Metrics.MeshMTLSStatus,
 	)
 }
 
@@ -176,6 +197,28 @@ func SetGraphNodes(graphKind string, graphType string, withServiceNodes bool, no
 	}).Set(float64(nodeCount))
 }
 
+// SetMeshValidations replaces the validation counts of all namespaces.
+// The counts are keyed by namespace and then by severity.
+func SetMeshValidations(counts map[string]map[string]int) {
+	Metrics.MeshValidations.Reset()
+	for namespace, severities := range counts {
+		for severity, count := range severities {
+			Metrics.MeshValidations.With(prometheus.Labels{
+				labelNamespace: namespace,
+				labelSeverity:  severity,
+			}).Set(float64(count))
+		}
+	}
+}
+
+// SetMeshMTLSStatus sets the current mesh-wide mTLS status
+func SetMeshMTLSStatus(status string) {
+	Metrics.MeshMTLSStatus.Reset()
+	Metrics.MeshMTLSStatus.With(prometheus.Labels{
+		labelStatus: status,
+	}).Set(1)
+}
+
 // GetGraphGenerationTimePrometheusTimer returns a timer that can be used to store
 // a value for the graph generation time metric. The timer is ticking immediately
 // when this function returns.
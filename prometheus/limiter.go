@@ -0,0 +1,135 @@
+package prometheus
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/log"
+)
+
+// ErrOverloaded is returned when a query waited too long for a free slot to be sent to Prometheus
+var ErrOverloaded = errors.New("prometheus overloaded: too many concurrent queries, try again later")
+
+var limiterOnce sync.Once
+var queryLimiter *QueryLimiter
+
+func initQueryLimiter() {
+	cfg := config.Get().ExternalServices.Prometheus
+	queryLimiter = NewQueryLimiter(cfg.MaxConcurrentQueries, time.Duration(cfg.QueryQueueTimeout)*time.Second)
+	if queryLimiter != nil {
+		log.Infof("[Prom Limiter] Limiting concurrent queries to %d", cfg.MaxConcurrentQueries)
+	} else {
+		log.Infof("[Prom Limiter] Disabled")
+	}
+}
+
+// QueryLimiter bounds the number of in-flight queries to Prometheus.
+// Queries over the limit wait in queue for a free slot up to the queue timeout.
+// A nil QueryLimiter doesn't limit anything.
+type QueryLimiter struct {
+	slots        chan struct{}
+	queueTimeout time.Duration
+}
+
+// NewQueryLimiter creates a limiter allowing maxConcurrent in-flight queries.
+// It returns nil (no limit) when maxConcurrent is not positive.
+func NewQueryLimiter(maxConcurrent int, queueTimeout time.Duration) *QueryLimiter {
+	if maxConcurrent <= 0 {
+		return nil
+	}
+	return &QueryLimiter{
+		slots:        make(chan struct{}, maxConcurrent),
+		queueTimeout: queueTimeout,
+	}
+}
+
+// Acquire takes a slot, waiting for one up to the queue timeout when all of them are in use.
+// It returns ErrOverloaded on timeout, or the context error when the context is done first.
+// Every successful Acquire must be followed by a Release.
+func (l *QueryLimiter) Acquire(ctx context.Context) error {
+	if l == nil {
+		return nil
+	}
+	select {
+	case l.slots <- struct{}{}:
+		return nil
+	default:
+	}
+	if l.queueTimeout <= 0 {
+		return ErrOverloaded
+	}
+
+	timer := time.NewTimer(l.queueTimeout)
+	defer timer.Stop()
+	select {
+	case l.slots <- struct{}{}:
+		return nil
+	case <-timer.C:
+		return ErrOverloaded
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Release frees a slot taken by Acquire
+func (l *QueryLimiter) Release() {
+	if l == nil {
+		return
+	}
+	<-l.slots
+}
+
+// InFlight returns the number of slots in use
+func (l *QueryLimiter) InFlight() int {
+	if l == nil {
+		return 0
+	}
+	return len(l.slots)
+}
+
+// RoundTripper wraps next so every request sent to Prometheus holds a slot of the limiter
+func (l *QueryLimiter) RoundTripper(next http.RoundTripper) http.RoundTripper {
+	if l == nil {
+		return next
+	}
+	return &limitedRoundTripper{limiter: l, next: next}
+}
+
+type limitedRoundTripper struct {
+	limiter *QueryLimiter
+	next    http.RoundTripper
+}
+
+func (rt *limitedRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := rt.limiter.Acquire(req.Context()); err != nil {
+		log.Warningf("[Prom Limiter] Query to [%s] rejected: %v", req.URL.Path, err)
+		return nil, err
+	}
+	resp, err := rt.next.RoundTrip(req)
+	if err != nil {
+		rt.limiter.Release()
+		return nil, err
+	}
+	// Prometheus is still busy with the query while the response is streamed, so the slot is held until the body
+	// is closed
+	resp.Body = &limitedBody{ReadCloser: resp.Body, release: rt.limiter.Release}
+	return resp, nil
+}
+
+// limitedBody releases the slot of the query once its body is closed, closing it again doesn't release anything
+type limitedBody struct {
+	io.ReadCloser
+	once    sync.Once
+	release func()
+}
+
+func (b *limitedBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(b.release)
+	return err
+}
@@ -9,34 +9,131 @@ import (
 	prom_v1 "github.com/prometheus/client_golang/api/prometheus/v1"
 	"github.com/prometheus/common/model"
 
+	"github.com/kiali/kiali/config"
 	"github.com/kiali/kiali/log"
 	"github.com/kiali/kiali/prometheus/internalmetrics"
 )
 
-func fetchRateRange(ctx context.Context, api prom_v1.API, metricName string, labels []string, grouping string, q *RangeQuery) Metric {
+func fetchRateRange(ctx context.Context, api prom_v1.API, dedupLabel, metricName string, labels []string, grouping string, q *RangeQuery) Metric {
+	query := buildRateQuery(dedupLabel, metricName, labels, grouping, q)
+	return fetchRange(ctx, api, query, q.Range)
+}
+
+func buildRateQuery(dedupLabel, metricName string, labels []string, grouping string, q *RangeQuery) string {
 	var query string
+	selector := rangeSelector(q.RateInterval, q.Offset)
 	// Example: round(sum(rate(my_counter{foo=bar}[5m])) by (baz), 0.001)
 	for i, labelsInstance := range labels {
 		if i > 0 {
 			query += " OR "
 		}
+		rate := dedupSeries(fmt.Sprintf("%s(%s%s%s)", q.RateFunc, metricName, labelsInstance, selector), dedupLabel)
 		if grouping == "" {
-			query += fmt.Sprintf("sum(%s(%s%s[%s]))", q.RateFunc, metricName, labelsInstance, q.RateInterval)
+			query += fmt.Sprintf("sum(%s)", rate)
 		} else {
-			query += fmt.Sprintf("sum(%s(%s%s[%s])) by (%s)", q.RateFunc, metricName, labelsInstance, q.RateInterval, grouping)
+			query += fmt.Sprintf("sum(%s) by (%s)", rate, grouping)
 		}
 	}
 	if len(labels) > 1 {
 		query = fmt.Sprintf("(%s)", query)
 	}
-	query = roundSignificant(query, 0.001)
-	return fetchRange(ctx, api, query, q.Range)
+	return roundSignificant(query, 0.001)
+}
+
+// rangeSelector returns the range vector selector, with the offset modifier when set.
+// Example: [5m] offset 7d
+func rangeSelector(rateInterval, offset string) string {
+	if offset == "" {
+		return fmt.Sprintf("[%s]", rateInterval)
+	}
+	return fmt.Sprintf("[%s] offset %s", rateInterval, offset)
+}
+
+// dedupSeries keeps one of the identical series scraped by several federated Prometheus, which only differ by the
+// dedup label, so that they are not counted several times by the aggregations. Without dedup label, the expression
+// is left unchanged.
+// Example: max without (prometheus) (rate(my_counter{foo=bar}[5m]))
+func dedupSeries(expr, dedupLabel string) string {
+	if dedupLabel == "" {
+		return expr
+	}
+	return fmt.Sprintf("max without (%s) (%s)", dedupLabel, expr)
+}
+
+// dedupRates applies dedupSeries to every rate of a query built outside of this package, e.g. by the graph.
+// Example: sum(rate(my_counter[5m])) by (baz) => sum(max without (prometheus) (rate(my_counter[5m]))) by (baz)
+func dedupRates(query, dedupLabel string) string {
+	if dedupLabel == "" {
+		return query
+	}
+	var deduped strings.Builder
+	for i := 0; i < len(query); {
+		end := i + 1
+		switch c := query[i]; {
+		case c == '"' || c == '\'' || c == '`':
+			end = skipQuoted(query, i)
+		case isIdentifier(c):
+			for end < len(query) && isIdentifier(query[end]) {
+				end++
+			}
+			if fn := query[i:end]; (fn == "rate" || fn == "irate" || fn == "increase") && end < len(query) && query[end] == '(' {
+				end = matchingParen(query, end)
+				deduped.WriteString(dedupSeries(query[i:end], dedupLabel))
+				i = end
+				continue
+			}
+		}
+		deduped.WriteString(query[i:end])
+		i = end
+	}
+	return deduped.String()
+}
+
+// skipQuoted returns the position following the string starting at start
+func skipQuoted(query string, start int) int {
+	quote := query[start]
+	i := start + 1
+	for i < len(query) && query[i] != quote {
+		if query[i] == '\\' && quote != '`' {
+			i++
+		}
+		i++
+	}
+	if i < len(query) {
+		i++
+	}
+	return i
+}
+
+// matchingParen returns the position following the parenthesis closing the one at start
+func matchingParen(query string, start int) int {
+	depth := 0
+	for i := start; i < len(query); {
+		switch query[i] {
+		case '"', '\'', '`':
+			i = skipQuoted(query, i)
+			continue
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return i + 1
+			}
+		}
+		i++
+	}
+	return len(query)
+}
+
+func isIdentifier(c byte) bool {
+	return c == '_' || c == ':' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
 }
 
-func fetchHistogramRange(ctx context.Context, api prom_v1.API, metricName, labels, grouping string, q *RangeQuery) Histogram {
+func fetchHistogramRange(ctx context.Context, api prom_v1.API, dedupLabel, metricName, labels, grouping string, q *RangeQuery) Histogram {
 	// Note: the p8s queries are not run in parallel here, but they are at the caller's place.
 	//	This is because we may not want to create too many threads in the lowest layer
-	queries := buildHistogramQueries(metricName, labels, grouping, q.RateInterval, q.Avg, q.Quantiles)
+	queries := buildHistogramQueries(dedupLabel, metricName, labels, grouping, q.RateInterval, q.Offset, q.Avg, q.Quantiles)
 	histogram := make(Histogram, len(queries))
 	for k, query := range queries {
 		histogram[k] = fetchRange(ctx, api, query, q.Range)
@@ -44,10 +141,10 @@ func fetchHistogramRange(ctx context.Context, api prom_v1.API, metricName, label
 	return histogram
 }
 
-func fetchHistogramValues(ctx context.Context, api prom_v1.API, metricName, labels, grouping, rateInterval string, avg bool, quantiles []string, queryTime time.Time) (map[string]model.Vector, error) {
+func fetchHistogramValues(ctx context.Context, api prom_v1.API, dedupLabel, metricName, labels, grouping, rateInterval string, avg bool, quantiles []string, queryTime time.Time) (map[string]model.Vector, error) {
 	// Note: the p8s queries are not run in parallel here, but they are at the caller's place.
 	//	This is because we may not want to create too many threads in the lowest layer
-	queries := buildHistogramQueries(metricName, labels, grouping, rateInterval, avg, quantiles)
+	queries := buildHistogramQueries(dedupLabel, metricName, labels, grouping, rateInterval, "", avg, quantiles)
 	histogram := make(map[string]model.Vector, len(queries))
 	for k, query := range queries {
 		log.Tracef("[Prom] fetchHistogramValues: %s", query)
@@ -63,8 +160,56 @@ func fetchHistogramValues(ctx context.Context, api prom_v1.API, metricName, labe
 	return histogram, nil
 }
 
-func buildHistogramQueries(metricName, labels, grouping, rateInterval string, avg bool, quantiles []string) map[string]string {
+func fetchRateValues(ctx context.Context, api prom_v1.API, dedupLabel, metricName, labels, grouping, rateInterval string, queryTime time.Time) (model.Vector, error) {
+	query := fmt.Sprintf("sum(%s)", dedupSeries(fmt.Sprintf("rate(%s%s[%s])", metricName, labels, rateInterval), dedupLabel))
+	if grouping != "" {
+		query += fmt.Sprintf(" by (%s)", grouping)
+	}
+	log.Tracef("[Prom] fetchRateValues: %s", query)
+	result, warnings, err := api.Query(ctx, query, queryTime)
+	if warnings != nil && len(warnings) > 0 {
+		log.Warningf("fetchRateValues. Prometheus Warnings: [%s]", strings.Join(warnings, ","))
+	}
+	if err != nil {
+		return nil, err
+	}
+	return result.(model.Vector), nil
+}
+
+// fetchTopRateValues fetches the rates of a counter grouped by the given labels, keeping the limit highest ones
+func fetchTopRateValues(ctx context.Context, api prom_v1.API, dedupLabel, metricName, labels, grouping, rateInterval string, limit int, queryTime time.Time) (model.Vector, error) {
+	rate := dedupSeries(fmt.Sprintf("rate(%s%s[%s])", metricName, labels, rateInterval), dedupLabel)
+	query := fmt.Sprintf("topk(%d, sum(%s) by (%s))", limit, rate, grouping)
+	log.Tracef("[Prom] fetchTopRateValues: %s", query)
+	result, warnings, err := api.Query(ctx, query, queryTime)
+	if warnings != nil && len(warnings) > 0 {
+		log.Warningf("fetchTopRateValues. Prometheus Warnings: [%s]", strings.Join(warnings, ","))
+	}
+	if err != nil {
+		return nil, err
+	}
+	return result.(model.Vector), nil
+}
+
+func fetchValues(ctx context.Context, api prom_v1.API, dedupLabel, metricName, labels, grouping string, queryTime time.Time) (model.Vector, error) {
+	query := fmt.Sprintf("sum(%s)", dedupSeries(metricName+labels, dedupLabel))
+	if grouping != "" {
+		query += fmt.Sprintf(" by (%s)", grouping)
+	}
+	log.Tracef("[Prom] fetchValues: %s", query)
+	result, warnings, err := api.Query(ctx, query, queryTime)
+	if warnings != nil && len(warnings) > 0 {
+		log.Warningf("fetchValues. Prometheus Warnings: [%s]", strings.Join(warnings, ","))
+	}
+	if err != nil {
+		return nil, err
+	}
+	return result.(model.Vector), nil
+}
+
+func buildHistogramQueries(dedupLabel, metricName, labels, grouping, rateInterval, offset string, avg bool, quantiles []string) map[string]string {
 	queries := make(map[string]string)
+	selector := rangeSelector(rateInterval, offset)
 	if avg {
 		groupingAvg := ""
 		if grouping != "" {
@@ -72,8 +217,9 @@ func buildHistogramQueries(metricName, labels, grouping, rateInterval string, av
 		}
 		// Average
 		// Example: sum(rate(my_histogram_sum{foo=bar}[5m])) by (baz) / sum(rate(my_histogram_count{foo=bar}[5m])) by (baz)
-		query := fmt.Sprintf("sum(rate(%s_sum%s[%s]))%s / sum(rate(%s_count%s[%s]))%s",
-			metricName, labels, rateInterval, groupingAvg, metricName, labels, rateInterval, groupingAvg)
+		sum := dedupSeries(fmt.Sprintf("rate(%s_sum%s%s)", metricName, labels, selector), dedupLabel)
+		count := dedupSeries(fmt.Sprintf("rate(%s_count%s%s)", metricName, labels, selector), dedupLabel)
+		query := fmt.Sprintf("sum(%s)%s / sum(%s)%s", sum, groupingAvg, count, groupingAvg)
 		query = roundSignificant(query, 0.001)
 		queries["avg"] = query
 	}
@@ -84,8 +230,8 @@ func buildHistogramQueries(metricName, labels, grouping, rateInterval string, av
 	}
 	for _, quantile := range quantiles {
 		// Example: round(histogram_quantile(0.5, sum(rate(my_histogram_bucket{foo=bar}[5m])) by (le,baz)), 0.001)
-		query := fmt.Sprintf("histogram_quantile(%s, sum(rate(%s_bucket%s[%s])) by (le%s))",
-			quantile, metricName, labels, rateInterval, groupingQuantile)
+		buckets := dedupSeries(fmt.Sprintf("rate(%s_bucket%s%s)", metricName, labels, selector), dedupLabel)
+		query := fmt.Sprintf("histogram_quantile(%s, sum(%s) by (le%s))", quantile, buckets, groupingQuantile)
 		query = roundSignificant(query, 0.001)
 		queries[quantile] = query
 	}
@@ -112,16 +258,16 @@ func fetchRange(ctx context.Context, api prom_v1.API, query string, bounds prom_
 // getAllRequestRates retrieves traffic rates for requests entering, internal to, or exiting the namespace.
 // Note that it does not discriminate on "reporter", so rates can be inflated due to duplication, and therefore
 // should be used mainly for calculating ratios (e.g total rates / error rates)
-func getAllRequestRates(ctx context.Context, api prom_v1.API, namespace string, queryTime time.Time, ratesInterval string) (model.Vector, error) {
+func getAllRequestRates(ctx context.Context, api prom_v1.API, dedupLabel string, namespace string, queryTime time.Time, ratesInterval string) (model.Vector, error) {
 	// traffic originating outside the namespace to destinations inside the namespace
-	lbl := fmt.Sprintf(`destination_service_namespace="%s",source_workload_namespace!="%s"`, namespace, namespace)
-	fromOutside, err := getRequestRatesForLabel(ctx, api, queryTime, lbl, ratesInterval)
+	lbl := fmt.Sprintf(`%s="%s",%s!="%s"`, telemetryLabel("destination_service_namespace"), namespace, telemetryLabel("source_workload_namespace"), namespace)
+	fromOutside, err := getRequestRatesForLabel(ctx, api, dedupLabel, queryTime, lbl, ratesInterval)
 	if err != nil {
 		return model.Vector{}, err
 	}
 	// traffic originating inside the namespace to destinations inside or outside the namespace
-	lbl = fmt.Sprintf(`source_workload_namespace="%s"`, namespace)
-	fromInside, err := getRequestRatesForLabel(ctx, api, queryTime, lbl, ratesInterval)
+	lbl = fmt.Sprintf(`%s="%s"`, telemetryLabel("source_workload_namespace"), namespace)
+	fromInside, err := getRequestRatesForLabel(ctx, api, dedupLabel, queryTime, lbl, ratesInterval)
 	if err != nil {
 		return model.Vector{}, err
 	}
@@ -133,10 +279,10 @@ func getAllRequestRates(ctx context.Context, api prom_v1.API, namespace string,
 // getNamespaceServicesRequestRates retrieves traffic rates for requests entering or internal to the namespace.
 // Note that it does not discriminate on "reporter", so rates can be inflated due to duplication, and therefore
 // should be used mainly for calculating ratios (e.g total rates / error rates)
-func getNamespaceServicesRequestRates(ctx context.Context, api prom_v1.API, namespace string, queryTime time.Time, ratesInterval string) (model.Vector, error) {
+func getNamespaceServicesRequestRates(ctx context.Context, api prom_v1.API, dedupLabel string, namespace string, queryTime time.Time, ratesInterval string) (model.Vector, error) {
 	// traffic for the namespace services
-	lblNs := fmt.Sprintf(`destination_service_namespace="%s"`, namespace)
-	ns, err := getRequestRatesForLabel(ctx, api, queryTime, lblNs, ratesInterval)
+	lblNs := fmt.Sprintf(`%s="%s"`, telemetryLabel("destination_service_namespace"), namespace)
+	ns, err := getRequestRatesForLabel(ctx, api, dedupLabel, queryTime, lblNs, ratesInterval)
 	if err != nil {
 		return model.Vector{}, err
 	}
@@ -146,9 +292,9 @@ func getNamespaceServicesRequestRates(ctx context.Context, api prom_v1.API, name
 // getServiceRequestRates retrieves traffic rates for requests entering, or internal to the namespace, for a specific service name
 // Note that it does not discriminate on "reporter", so rates can be inflated due to duplication, and therefore
 // should be used mainly for calculating ratios (e.g total rates / error rates)
-func getServiceRequestRates(ctx context.Context, api prom_v1.API, namespace, service string, queryTime time.Time, ratesInterval string) (model.Vector, error) {
-	lbl := fmt.Sprintf(`destination_service_name="%s",destination_service_namespace="%s"`, service, namespace)
-	in, err := getRequestRatesForLabel(ctx, api, queryTime, lbl, ratesInterval)
+func getServiceRequestRates(ctx context.Context, api prom_v1.API, dedupLabel string, namespace, service string, queryTime time.Time, ratesInterval string) (model.Vector, error) {
+	lbl := fmt.Sprintf(`%s="%s",%s="%s"`, telemetryLabel("destination_service_name"), service, telemetryLabel("destination_service_namespace"), namespace)
+	in, err := getRequestRatesForLabel(ctx, api, dedupLabel, queryTime, lbl, ratesInterval)
 	if err != nil {
 		return model.Vector{}, err
 	}
@@ -158,22 +304,53 @@ func getServiceRequestRates(ctx context.Context, api prom_v1.API, namespace, ser
 // getItemRequestRates retrieves traffic rates for requests entering, internal to, or exiting the namespace, for a specific destinatation_<itemLabelSuffix> value
 // Note that it does not discriminate on "reporter", so rates can be inflated due to duplication, and therefore
 // should be used mainly for calculating ratios (e.g total rates / error rates)
-func getItemRequestRates(ctx context.Context, api prom_v1.API, namespace, item, itemLabelSuffix string, queryTime time.Time, ratesInterval string) (model.Vector, model.Vector, error) {
-	lblIn := fmt.Sprintf(`destination_workload_namespace="%s",destination_%s="%s"`, namespace, itemLabelSuffix, item)
-	lblOut := fmt.Sprintf(`source_workload_namespace="%s",source_%s="%s"`, namespace, itemLabelSuffix, item)
-	in, err := getRequestRatesForLabel(ctx, api, queryTime, lblIn, ratesInterval)
+func getItemRequestRates(ctx context.Context, api prom_v1.API, dedupLabel string, namespace, item, itemLabelSuffix string, queryTime time.Time, ratesInterval string) (model.Vector, model.Vector, error) {
+	lblIn := fmt.Sprintf(`%s="%s",%s="%s"`, telemetryLabel("destination_workload_namespace"), namespace, telemetryLabel("destination_"+itemLabelSuffix), item)
+	lblOut := fmt.Sprintf(`%s="%s",%s="%s"`, telemetryLabel("source_workload_namespace"), namespace, telemetryLabel("source_"+itemLabelSuffix), item)
+	in, err := getRequestRatesForLabel(ctx, api, dedupLabel, queryTime, lblIn, ratesInterval)
 	if err != nil {
 		return model.Vector{}, model.Vector{}, err
 	}
-	out, err := getRequestRatesForLabel(ctx, api, queryTime, lblOut, ratesInterval)
+	out, err := getRequestRatesForLabel(ctx, api, dedupLabel, queryTime, lblOut, ratesInterval)
 	if err != nil {
 		return model.Vector{}, model.Vector{}, err
 	}
 	return in, out, nil
 }
 
-func getRequestRatesForLabel(ctx context.Context, api prom_v1.API, time time.Time, labels, ratesInterval string) (model.Vector, error) {
-	query := fmt.Sprintf("rate(istio_requests_total{%s}[%s]) > 0", labels, ratesInterval)
+// getWorkloadsTrafficHistory retrieves the requests increase per step for the workloads of the namespace, grouped by
+// destination workload for the inbound traffic and by source workload for the outbound traffic
+func getWorkloadsTrafficHistory(ctx context.Context, api prom_v1.API, dedupLabel string, namespace string, history, step time.Duration, queryTime time.Time) (model.Matrix, model.Matrix, error) {
+	bounds := prom_v1.Range{
+		Start: queryTime.Add(-history),
+		End:   queryTime,
+		Step:  step,
+	}
+	stepInterval := model.Duration(step).String()
+	metric := telemetryMetric("istio_requests_total")
+	increaseIn := dedupSeries(fmt.Sprintf(`increase(%s{%s="%s"}[%s])`, metric, telemetryLabel("destination_workload_namespace"), namespace, stepInterval), dedupLabel)
+	queryIn := fmt.Sprintf("sum(%s) by (%s)", increaseIn, telemetryLabel("destination_workload"))
+	in := fetchRange(ctx, api, queryIn, bounds)
+	if in.Err != nil {
+		return model.Matrix{}, model.Matrix{}, in.Err
+	}
+	increaseOut := dedupSeries(fmt.Sprintf(`increase(%s{%s="%s"}[%s])`, metric, telemetryLabel("source_workload_namespace"), namespace, stepInterval), dedupLabel)
+	queryOut := fmt.Sprintf("sum(%s) by (%s)", increaseOut, telemetryLabel("source_workload"))
+	out := fetchRange(ctx, api, queryOut, bounds)
+	if out.Err != nil {
+		return model.Matrix{}, model.Matrix{}, out.Err
+	}
+	for _, stream := range in.Matrix {
+		StandardLabels(stream.Metric)
+	}
+	for _, stream := range out.Matrix {
+		StandardLabels(stream.Metric)
+	}
+	return in.Matrix, out.Matrix, nil
+}
+
+func getRequestRatesForLabel(ctx context.Context, api prom_v1.API, dedupLabel string, time time.Time, labels, ratesInterval string) (model.Vector, error) {
+	query := fmt.Sprintf("%s > 0", dedupSeries(fmt.Sprintf("rate(%s{%s}[%s])", telemetryMetric("istio_requests_total"), labels, ratesInterval), dedupLabel))
 	log.Tracef("[Prom] getRequestRatesForLabel: %s", query)
 	promtimer := internalmetrics.GetPrometheusProcessingTimePrometheusTimer("Metrics-GetRequestRates")
 	result, warnings, err := api.Query(ctx, query, time)
@@ -184,7 +361,32 @@ func getRequestRatesForLabel(ctx context.Context, api prom_v1.API, time time.Tim
 		return model.Vector{}, err
 	}
 	promtimer.ObserveDuration() // notice we only collect metrics for successful prom queries
-	return result.(model.Vector), nil
+	vector := result.(model.Vector)
+	for _, sample := range vector {
+		StandardLabels(sample.Metric)
+	}
+	return vector, nil
+}
+
+// telemetryMetric returns the name of an Istio standard metric in the telemetry of the mesh
+func telemetryMetric(name string) string {
+	return config.Get().ExternalServices.Istio.TelemetryMapping.Metric(name)
+}
+
+// telemetryLabel returns the key of an Istio standard label in the telemetry of the mesh
+func telemetryLabel(name string) string {
+	return config.Get().ExternalServices.Istio.TelemetryMapping.Label(name)
+}
+
+// StandardLabels renames the labels of the telemetry of the mesh to their Istio standard names, so that the callers
+// can keep reading the rates by their standard labels
+func StandardLabels(metric model.Metric) {
+	for standard, mapped := range config.Get().ExternalServices.Istio.TelemetryMapping.Labels {
+		if value, found := metric[model.LabelName(mapped)]; found {
+			delete(metric, model.LabelName(mapped))
+			metric[model.LabelName(standard)] = value
+		}
+	}
 }
 
 // roundSignificant will output promQL that performs rounding only if the resulting value is significant, that is, higher than the requested precision
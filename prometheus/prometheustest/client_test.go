@@ -144,3 +144,130 @@ func mockConfig(api *PromAPIMock, ret prom_v1.ConfigResult) {
 func mockFlags(api *PromAPIMock, ret prom_v1.FlagsResult) {
 	api.On("Flags", mock.AnythingOfType("*context.emptyCtx")).Return(ret, nil)
 }
+
+func TestFetchRateRangeWithOffset(t *testing.T) {
+	client, api, err := setupMocked()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	q := prometheus.RangeQuery{}
+	q.FillDefaults()
+	q.RateInterval = "5m"
+	q.Offset = "7d"
+
+	query := round(`sum(rate(istio_requests_total{reporter="source"}[5m] offset 7d)) by (destination_service_name)`)
+	api.On("QueryRange", mock.Anything, query, q.Range).Return(singleValueMatrix(3), nil)
+
+	metric := client.FetchRateRange("istio_requests_total", []string{`{reporter="source"}`}, "destination_service_name", &q)
+	assert.Nil(t, metric.Err)
+	assert.Equal(t, model.SampleValue(3), metric.Matrix[0].Values[0].Value)
+	api.AssertExpectations(t)
+}
+
+func TestFetchHistogramRangeWithOffset(t *testing.T) {
+	client, api, err := setupMocked()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	q := prometheus.RangeQuery{}
+	q.FillDefaults()
+	q.RateInterval = "5m"
+	q.Quantiles = []string{"0.99"}
+	q.Offset = "1w"
+
+	labels := `{reporter="source"}`
+	api.On("QueryRange", mock.Anything, round(`sum(rate(istio_request_bytes_sum{reporter="source"}[5m] offset 1w)) / sum(rate(istio_request_bytes_count{reporter="source"}[5m] offset 1w))`), q.Range).Return(singleValueMatrix(1), nil)
+	api.On("QueryRange", mock.Anything, round(`histogram_quantile(0.99, sum(rate(istio_request_bytes_bucket{reporter="source"}[5m] offset 1w)) by (le))`), q.Range).Return(singleValueMatrix(2), nil)
+
+	histo := client.FetchHistogramRange("istio_request_bytes", labels, "", &q)
+	assert.Len(t, histo, 2)
+	assert.Equal(t, model.SampleValue(1), histo["avg"].Matrix[0].Values[0].Value)
+	assert.Equal(t, model.SampleValue(2), histo["0.99"].Matrix[0].Values[0].Value)
+	api.AssertExpectations(t)
+}
+
+func TestFetchRangeWithOffset(t *testing.T) {
+	client, api, err := setupMocked()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	q := prometheus.RangeQuery{}
+	q.FillDefaults()
+	q.Offset = "7d"
+
+	api.On("QueryRange", mock.Anything, round(`sum(process_cpu_seconds_total{app="foo"} offset 7d) by (pod)`), q.Range).Return(singleValueMatrix(4), nil)
+
+	metric := client.FetchRange("process_cpu_seconds_total", `{app="foo"}`, "pod", "sum", &q)
+	assert.Nil(t, metric.Err)
+	assert.Equal(t, model.SampleValue(4), metric.Matrix[0].Values[0].Value)
+	api.AssertExpectations(t)
+}
+
+func TestFetchRateValues(t *testing.T) {
+	client, api, err := setupMocked()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	queryTime := time.Date(2017, 01, 15, 0, 0, 0, 0, time.UTC)
+	vector := model.Vector{
+		&model.Sample{Metric: model.Metric{"type": "eds"}, Value: 1.5},
+	}
+	api.On("Query", mock.Anything, "sum(rate(pilot_xds_pushes[5m])) by (type)", queryTime).Return(vector, nil)
+	api.On("Query", mock.Anything, `sum(rate(pilot_xds_push_errors{app="istiod"}[5m]))`, queryTime).Return(model.Vector{}, nil)
+
+	pushes, err := client.FetchRateValues("pilot_xds_pushes", "", "type", "5m", queryTime)
+	assert.NoError(t, err)
+	assert.Equal(t, vector, pushes)
+
+	pushErrors, err := client.FetchRateValues("pilot_xds_push_errors", `{app="istiod"}`, "", "5m", queryTime)
+	assert.NoError(t, err)
+	assert.Empty(t, pushErrors)
+	api.AssertExpectations(t)
+}
+
+func TestFetchTopRateValues(t *testing.T) {
+	client, api, err := setupMocked()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	queryTime := time.Date(2017, 01, 15, 0, 0, 0, 0, time.UTC)
+	vector := model.Vector{
+		&model.Sample{Metric: model.Metric{"source_workload": "productpage-v1", "destination_service_name": "reviews"}, Value: 12},
+		&model.Sample{Metric: model.Metric{"source_workload": "reviews-v2", "destination_service_name": "ratings"}, Value: 4},
+	}
+	api.On("Query", mock.Anything, `topk(2, sum(rate(istio_requests_total{reporter="source",source_workload_namespace="bookinfo"}[5m])) by (source_workload,destination_service_name))`, queryTime).Return(vector, nil)
+
+	talkers, err := client.FetchTopRateValues("istio_requests_total", `{reporter="source",source_workload_namespace="bookinfo"}`, "source_workload,destination_service_name", "5m", 2, queryTime)
+	assert.NoError(t, err)
+	assert.Equal(t, vector, talkers)
+	api.AssertExpectations(t)
+}
+
+func TestFetchValues(t *testing.T) {
+	client, api, err := setupMocked()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	queryTime := time.Date(2017, 01, 15, 0, 0, 0, 0, time.UTC)
+	vector := model.Vector{
+		&model.Sample{Metric: model.Metric{"pod": "reviews-v1-1234"}, Value: 52428800},
+	}
+	api.On("Query", mock.Anything, `sum(container_memory_working_set_bytes{container="istio-proxy"}) by (pod)`, queryTime).Return(vector, nil)
+
+	memory, err := client.FetchValues("container_memory_working_set_bytes", `{container="istio-proxy"}`, "pod", queryTime)
+	assert.NoError(t, err)
+	assert.Equal(t, vector, memory)
+	api.AssertExpectations(t)
+}
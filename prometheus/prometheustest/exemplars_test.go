@@ -0,0 +1,87 @@
+package prometheustest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/prometheus"
+)
+
+func TestFetchExemplars(t *testing.T) {
+	assert := assert.New(t)
+	recorded, err := os.ReadFile("../../tests/data/prometheus/exemplars.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var query exemplarsRequest
+	client := exemplarsTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		query = exemplarsRequest{path: r.URL.Path, query: r.URL.Query().Get("query"), start: r.URL.Query().Get("start"), end: r.URL.Query().Get("end")}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(recorded)
+	})
+
+	start := time.Date(2022, 1, 10, 12, 0, 0, 0, time.UTC)
+	results, err := client.FetchExemplars("istio_request_duration_milliseconds_bucket", `{destination_service_name="reviews"}`, start, start.Add(time.Minute))
+	assert.NoError(err)
+	assert.Equal("/api/v1/query_exemplars", query.path)
+	assert.Equal(`istio_request_duration_milliseconds_bucket{destination_service_name="reviews"}`, query.query)
+	assert.Equal("2022-01-10T12:00:00Z", query.start)
+	assert.Equal("2022-01-10T12:01:00Z", query.end)
+
+	assert.Len(results, 2)
+	assert.Equal(model.LabelValue("productpage-v1"), results[0].SeriesLabels["source_workload"])
+	assert.Len(results[0].Exemplars, 2)
+	assert.Equal(model.LabelValue("4bf92f3577b34da6a3ce929d0e0e4736"), results[0].Exemplars[0].Labels["trace_id"])
+	assert.Equal(model.SampleValue(38.5), results[0].Exemplars[0].Value)
+	assert.Equal(model.Time(1641816000123), results[0].Exemplars[0].Timestamp)
+	assert.Equal(model.LabelValue("a3ce929d0e0e47364bf92f3577b34da6"), results[1].Exemplars[0].Labels["traceID"])
+	assert.Equal(model.SampleValue(1250), results[1].Exemplars[0].Value)
+}
+
+func TestFetchExemplarsNotSupported(t *testing.T) {
+	// Prometheus before 2.26 has no exemplars API
+	client := exemplarsTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		http.NotFound(w, r)
+	})
+
+	_, err := client.FetchExemplars("istio_request_duration_milliseconds_bucket", "", time.Now().Add(-time.Minute), time.Now())
+	assert.Equal(t, prometheus.ErrExemplarsNotSupported, err)
+}
+
+func TestFetchExemplarsError(t *testing.T) {
+	assert := assert.New(t)
+	client := exemplarsTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"status":"error","errorType":"bad_data","error":"1:9: parse error: unexpected \"}\""}`))
+	})
+
+	_, err := client.FetchExemplars("istio_request_duration_milliseconds_bucket", "{}}", time.Now().Add(-time.Minute), time.Now())
+	assert.Error(err)
+	assert.NotEqual(prometheus.ErrExemplarsNotSupported, err)
+	assert.Contains(err.Error(), "bad_data")
+}
+
+type exemplarsRequest struct {
+	path, query, start, end string
+}
+
+func exemplarsTestClient(t *testing.T, handler http.HandlerFunc) *prometheus.Client {
+	config.Set(config.NewConfig())
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	client, err := prometheus.NewClientForConfig(config.PrometheusConfig{URL: server.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client
+}
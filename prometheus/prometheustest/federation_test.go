@@ -0,0 +1,156 @@
+package prometheustest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/prometheus"
+)
+
+// setupFederatedMocked returns a client of Prometheus federating the series of two Prometheus, distinguished by
+// their "prometheus" external label
+func setupFederatedMocked() (*prometheus.Client, *PromAPIMock, error) {
+	conf := config.NewConfig()
+	conf.ExternalServices.Prometheus.DedupLabel = "prometheus"
+	config.Set(conf)
+	api := new(PromAPIMock)
+	client, err := prometheus.NewClient()
+	if err != nil {
+		return nil, nil, err
+	}
+	client.Inject(api)
+	return client, api, nil
+}
+
+func TestFetchFederatedRateValues(t *testing.T) {
+	client, api, err := setupFederatedMocked()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer config.Set(config.NewConfig())
+
+	// The duplicates of the series of reviews-v1 scraped by both Prometheus are counted once
+	queryTime := time.Date(2017, 01, 15, 0, 0, 0, 0, time.UTC)
+	vector := model.Vector{
+		&model.Sample{Metric: model.Metric{"destination_workload": "reviews-v1"}, Value: 10},
+	}
+	api.On("Query", mock.Anything, `sum(max without (prometheus) (rate(istio_requests_total{reporter="destination"}[5m]))) by (destination_workload)`, queryTime).Return(vector, nil)
+	api.On("Query", mock.Anything, `topk(1, sum(max without (prometheus) (rate(istio_requests_total{reporter="destination"}[5m]))) by (destination_workload))`, queryTime).Return(vector, nil)
+
+	rates, err := client.FetchRateValues("istio_requests_total", `{reporter="destination"}`, "destination_workload", "5m", queryTime)
+	assert.NoError(t, err)
+	assert.Equal(t, vector, rates)
+
+	top, err := client.FetchTopRateValues("istio_requests_total", `{reporter="destination"}`, "destination_workload", "5m", 1, queryTime)
+	assert.NoError(t, err)
+	assert.Equal(t, vector, top)
+	api.AssertExpectations(t)
+}
+
+func TestFetchFederatedValues(t *testing.T) {
+	client, api, err := setupFederatedMocked()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer config.Set(config.NewConfig())
+
+	queryTime := time.Date(2017, 01, 15, 0, 0, 0, 0, time.UTC)
+	vector := model.Vector{
+		&model.Sample{Metric: model.Metric{"pod": "reviews-v1-1234"}, Value: 52428800},
+	}
+	api.On("Query", mock.Anything, `sum(max without (prometheus) (container_memory_working_set_bytes{container="istio-proxy"})) by (pod)`, queryTime).Return(vector, nil)
+
+	memory, err := client.FetchValues("container_memory_working_set_bytes", `{container="istio-proxy"}`, "pod", queryTime)
+	assert.NoError(t, err)
+	assert.Equal(t, vector, memory)
+	api.AssertExpectations(t)
+}
+
+func TestFetchFederatedHistogramValues(t *testing.T) {
+	client, api, err := setupFederatedMocked()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer config.Set(config.NewConfig())
+
+	// The buckets are deduplicated before the quantiles are computed
+	queryTime := time.Date(2017, 01, 15, 0, 0, 0, 0, time.UTC)
+	api.On("Query", mock.Anything, round(`sum(max without (prometheus) (rate(istio_request_duration_milliseconds_sum{reporter="destination"}[5m]))) / sum(max without (prometheus) (rate(istio_request_duration_milliseconds_count{reporter="destination"}[5m])))`), queryTime).Return(model.Vector{&model.Sample{Value: 20}}, nil)
+	api.On("Query", mock.Anything, round(`histogram_quantile(0.99, sum(max without (prometheus) (rate(istio_request_duration_milliseconds_bucket{reporter="destination"}[5m]))) by (le))`), queryTime).Return(model.Vector{&model.Sample{Value: 50}}, nil)
+
+	histogram, err := client.FetchHistogramValues("istio_request_duration_milliseconds", `{reporter="destination"}`, "", "5m", true, []string{"0.99"}, queryTime)
+	assert.NoError(t, err)
+	assert.Equal(t, model.SampleValue(20), histogram["avg"][0].Value)
+	assert.Equal(t, model.SampleValue(50), histogram["0.99"][0].Value)
+	api.AssertExpectations(t)
+}
+
+func TestFetchFederatedRanges(t *testing.T) {
+	client, api, err := setupFederatedMocked()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer config.Set(config.NewConfig())
+
+	q := prometheus.RangeQuery{}
+	q.FillDefaults()
+	q.RateInterval = "5m"
+	q.Offset = "7d"
+
+	api.On("QueryRange", mock.Anything, round(`sum(max without (prometheus) (rate(istio_requests_total{reporter="source"}[5m] offset 7d))) by (destination_service_name)`), q.Range).Return(singleValueMatrix(3), nil)
+	api.On("QueryRange", mock.Anything, round(`sum(max without (prometheus) (process_cpu_seconds_total{app="foo"} offset 7d)) by (pod)`), q.Range).Return(singleValueMatrix(4), nil)
+
+	rate := client.FetchRateRange("istio_requests_total", []string{`{reporter="source"}`}, "destination_service_name", &q)
+	assert.Nil(t, rate.Err)
+	assert.Equal(t, model.SampleValue(3), rate.Matrix[0].Values[0].Value)
+
+	cpu := client.FetchRange("process_cpu_seconds_total", `{app="foo"}`, "pod", "sum", &q)
+	assert.Nil(t, cpu.Err)
+	assert.Equal(t, model.SampleValue(4), cpu.Matrix[0].Values[0].Value)
+	api.AssertExpectations(t)
+}
+
+func TestGetFederatedNamespaceServicesRequestRates(t *testing.T) {
+	client, api, err := setupFederatedMocked()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer config.Set(config.NewConfig())
+
+	// The series are returned without the dedup label, once for both Prometheus
+	queryTime := time.Date(2017, 01, 15, 0, 0, 0, 0, time.UTC)
+	vector := model.Vector{
+		&model.Sample{Metric: model.Metric{"destination_service_name": "reviews", "response_code": "200"}, Value: 4},
+	}
+	api.On("Query", mock.Anything, `max without (prometheus) (rate(istio_requests_total{destination_service_namespace="bookinfo"}[5m])) > 0`, queryTime).Return(vector, nil)
+
+	rates, err := client.GetNamespaceServicesRequestRates("bookinfo", "5m", queryTime)
+	assert.NoError(t, err)
+	assert.Equal(t, vector, rates)
+	api.AssertExpectations(t)
+}
+
+func TestDedupFederatedRates(t *testing.T) {
+	client, _, err := setupFederatedMocked()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer config.Set(config.NewConfig())
+
+	// The rates are deduplicated, the quoted strings are left untouched
+	query := `sum(rate(istio_requests_total{reporter="source",destination_service=~"rate(.+)"} [60s])) by (source_workload) > 0`
+	assert.Equal(t,
+		`sum(max without (prometheus) (rate(istio_requests_total{reporter="source",destination_service=~"rate(.+)"} [60s]))) by (source_workload) > 0`,
+		client.DedupRates(query))
+
+	conf := config.NewConfig()
+	config.Set(conf)
+	client, err = prometheus.NewClient()
+	assert.NoError(t, err)
+	assert.Equal(t, query, client.DedupRates(query))
+}
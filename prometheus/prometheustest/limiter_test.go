@@ -0,0 +1,193 @@
+package prometheustest
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kiali/kiali/prometheus"
+)
+
+func TestLimiterBoundsConcurrentQueries(t *testing.T) {
+	assert := assert.New(t)
+	limiter := prometheus.NewQueryLimiter(5, 10*time.Second)
+
+	var inFlight, maxInFlight int32
+	wg := sync.WaitGroup{}
+	errs := make(chan error, 50)
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := limiter.Acquire(context.Background()); err != nil {
+				errs <- err
+				return
+			}
+			defer limiter.Release()
+			current := atomic.AddInt32(&inFlight, 1)
+			for {
+				max := atomic.LoadInt32(&maxInFlight)
+				if current <= max || atomic.CompareAndSwapInt32(&maxInFlight, max, current) {
+					break
+				}
+			}
+			time.Sleep(5 * time.Millisecond)
+			atomic.AddInt32(&inFlight, -1)
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		assert.NoError(err)
+	}
+	assert.True(maxInFlight <= 5, "max in-flight queries %d over the limit", maxInFlight)
+	assert.Equal(0, limiter.InFlight())
+}
+
+func TestLimiterQueueTimeout(t *testing.T) {
+	assert := assert.New(t)
+	limiter := prometheus.NewQueryLimiter(2, 20*time.Millisecond)
+
+	assert.NoError(limiter.Acquire(context.Background()))
+	assert.NoError(limiter.Acquire(context.Background()))
+
+	wg := sync.WaitGroup{}
+	errs := make(chan error, 10)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- limiter.Acquire(context.Background())
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		assert.Equal(prometheus.ErrOverloaded, err)
+	}
+	assert.Equal(2, limiter.InFlight())
+
+	// Queued queries get the slot once it's released
+	done := make(chan error)
+	go func() {
+		done <- limiter.Acquire(context.Background())
+	}()
+	limiter.Release()
+	assert.NoError(<-done)
+	limiter.Release()
+	limiter.Release()
+	assert.Equal(0, limiter.InFlight())
+}
+
+func TestLimiterContextCancelled(t *testing.T) {
+	limiter := prometheus.NewQueryLimiter(1, time.Minute)
+	assert.NoError(t, limiter.Acquire(context.Background()))
+	defer limiter.Release()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.Equal(t, context.DeadlineExceeded, limiter.Acquire(ctx))
+}
+
+func TestLimiterDisabled(t *testing.T) {
+	limiter := prometheus.NewQueryLimiter(0, time.Second)
+	assert.Nil(t, limiter)
+	for i := 0; i < 100; i++ {
+		assert.NoError(t, limiter.Acquire(context.Background()))
+	}
+	assert.Equal(t, http.DefaultTransport, limiter.RoundTripper(http.DefaultTransport))
+}
+
+func TestLimiterRoundTripper(t *testing.T) {
+	assert := assert.New(t)
+
+	release := make(chan struct{})
+	var served int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&served, 1)
+		<-release
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	limiter := prometheus.NewQueryLimiter(3, 50*time.Millisecond)
+	client := &http.Client{Transport: limiter.RoundTripper(http.DefaultTransport)}
+
+	wg := sync.WaitGroup{}
+	var succeeded, overloaded int32
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, err := client.Get(server.URL)
+			if err != nil {
+				assert.Contains(err.Error(), prometheus.ErrOverloaded.Error())
+				atomic.AddInt32(&overloaded, 1)
+				return
+			}
+			resp.Body.Close()
+			atomic.AddInt32(&succeeded, 1)
+		}()
+	}
+	// Hold the in-flight queries until the queued ones time out
+	time.Sleep(200 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	assert.Equal(int32(3), served)
+	assert.Equal(int32(3), succeeded)
+	assert.Equal(int32(5), overloaded)
+	assert.Equal(0, limiter.InFlight())
+}
+
+func TestLimiterRoundTripperHoldsSlotUntilBodyClosed(t *testing.T) {
+	assert := assert.New(t)
+
+	flushed := make(chan struct{})
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/error" {
+			// Drop the connection without any response
+			conn, _, _ := w.(http.Hijacker).Hijack()
+			conn.Close()
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		w.(http.Flusher).Flush()
+		close(flushed)
+		<-release
+		_, _ = w.Write([]byte("{}"))
+	}))
+	defer server.Close()
+
+	limiter := prometheus.NewQueryLimiter(1, time.Second)
+	client := &http.Client{Transport: limiter.RoundTripper(&http.Transport{})}
+
+	resp, err := client.Get(server.URL)
+	assert.NoError(err)
+	<-flushed
+	// The headers are received but the body is still being sent
+	assert.Equal(1, limiter.InFlight())
+	close(release)
+	_, err = ioutil.ReadAll(resp.Body)
+	assert.NoError(err)
+	assert.Equal(1, limiter.InFlight())
+	resp.Body.Close()
+	assert.Equal(0, limiter.InFlight())
+	// Closing the body again doesn't free another slot
+	resp.Body.Close()
+	assert.Equal(0, limiter.InFlight())
+
+	_, err = client.Get(server.URL + "/error")
+	assert.Error(err)
+	assert.Equal(0, limiter.InFlight())
+}
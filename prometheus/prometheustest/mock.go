@@ -296,6 +296,11 @@ func (o *PromClientMock) GetServiceRequestRates(namespace, service, ratesInterva
 	return args.Get(0).(model.Vector), args.Error(1)
 }
 
+func (o *PromClientMock) GetWorkloadsTrafficHistory(namespace string, history, step time.Duration, queryTime time.Time) (model.Matrix, model.Matrix, error) {
+	args := o.Called(namespace, history, step, queryTime)
+	return args.Get(0).(model.Matrix), args.Get(1).(model.Matrix), args.Error(2)
+}
+
 func (o *PromClientMock) GetWorkloadRequestRates(namespace, workload, ratesInterval string, queryTime time.Time) (model.Vector, model.Vector, error) {
 	args := o.Called(namespace, workload, ratesInterval, queryTime)
 	return args.Get(0).(model.Vector), args.Get(1).(model.Vector), args.Error(2)
@@ -321,11 +326,36 @@ func (o *PromClientMock) FetchHistogramValues(metricName, labels, grouping, rate
 	return args.Get(0).(map[string]model.Vector), args.Error((1))
 }
 
+func (o *PromClientMock) FetchRateValues(metricName, labels, grouping, rateInterval string, queryTime time.Time) (model.Vector, error) {
+	args := o.Called(metricName, labels, grouping, rateInterval, queryTime)
+	return args.Get(0).(model.Vector), args.Error(1)
+}
+
+func (o *PromClientMock) FetchTopRateValues(metricName, labels, grouping, rateInterval string, limit int, queryTime time.Time) (model.Vector, error) {
+	args := o.Called(metricName, labels, grouping, rateInterval, limit, queryTime)
+	return args.Get(0).(model.Vector), args.Error(1)
+}
+
+func (o *PromClientMock) FetchValues(metricName, labels, grouping string, queryTime time.Time) (model.Vector, error) {
+	args := o.Called(metricName, labels, grouping, queryTime)
+	return args.Get(0).(model.Vector), args.Error(1)
+}
+
+func (o *PromClientMock) FetchExemplars(metricName, labels string, start, end time.Time) ([]prometheus.ExemplarQueryResult, error) {
+	args := o.Called(metricName, labels, start, end)
+	return args.Get(0).([]prometheus.ExemplarQueryResult), args.Error(1)
+}
+
 func (o *PromClientMock) GetMetricsForLabels(labels []string) ([]string, error) {
 	args := o.Called(labels)
 	return args.Get(0).([]string), args.Error(1)
 }
 
+func (o *PromClientMock) GetMetricMetadata(metricName string) ([]prom_v1.Metadata, error) {
+	args := o.Called(metricName)
+	return args.Get(0).([]prom_v1.Metadata), args.Error(1)
+}
+
 func round(q string) string {
 	return fmt.Sprintf("round(%s, 0.001000) > 0.001000 or %s", q, q)
 }
@@ -15,6 +15,9 @@ type RangeQuery struct {
 	Quantiles    []string
 	Avg          bool
 	ByLabels     []string
+	// Offset shifts the query back in time, as a Prometheus duration (e.g. "7d"). Timestamps of the results
+	// still belong to the requested range, which makes them comparable to the non-shifted ones.
+	Offset string
 }
 
 // FillDefaults fills the struct with default parameters
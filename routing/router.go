@@ -10,6 +10,27 @@ import (
 	"github.com/kiali/kiali/prometheus/internalmetrics"
 )
 
+// The routes of the health, the metrics and the Istio config, served from their last successful response when their
+// backend fails and StaleWhileError is enabled
+var staleWhileErrorRoutes = map[string]bool{
+	"NamespaceHealth":          true,
+	"AppHealth":                true,
+	"ServiceHealth":            true,
+	"WorkloadHealth":           true,
+	"WorkloadDependencyHealth": true,
+	"NamespaceMetrics":         true,
+	"ServiceMetrics":           true,
+	"AppMetrics":               true,
+	"WorkloadMetrics":          true,
+	"AggregateMetrics":         true,
+	"ServiceDashboard":         true,
+	"AppDashboard":             true,
+	"WorkloadDashboard":        true,
+	"CustomDashboard":          true,
+	"IstioConfigList":          true,
+	"IstioConfigDetails":       true,
+}
+
 // NewRouter creates the router with all API routes and the static files handler
 func NewRouter() *mux.Router {
 
@@ -48,8 +69,12 @@ func NewRouter() *mux.Router {
 	// Build our API server routes and install them.
 	apiRoutes := NewRoutes()
 	authenticationHandler, _ := handlers.NewAuthenticationHandler()
+	staleWhileErrorHandler := handlers.NewStaleWhileErrorHandler()
 	for _, route := range apiRoutes.Routes {
 		handlerFunction := metricHandler(route.HandlerFunc, route)
+		if conf.Server.StaleWhileError.Enabled && staleWhileErrorRoutes[route.Name] {
+			handlerFunction = staleWhileErrorHandler.Handle(handlerFunction, route.Name)
+		}
 		if route.Authenticated {
 			handlerFunction = authenticationHandler.Handle(handlerFunction)
 		} else {
@@ -273,6 +273,7 @@ func NewRoutes() (r *Routes) {
 		//     Schemes: http, https
 		//
 		// responses:
+		//      400: badRequestError
 		//      404: notFoundError
 		//      500: internalError
 		//      200
@@ -284,6 +285,49 @@ func NewRoutes() (r *Routes) {
 			handlers.IstioConfigDelete,
 			true,
 		},
+		// swagger:route GET /namespaces/{namespace}/istio/{object_type}/{object}/delete_impact config istioConfigDeleteImpact
+		// ---
+		// Preview the objects affected by the deletion of an Istio object, with the checks they would get
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      200: deleteImpactResponse
+		//      400: badRequestError
+		//      404: notFoundError
+		//      500: internalError
+		//
+		{
+			"IstioConfigDeleteImpact",
+			"GET",
+			"/api/namespaces/{namespace}/istio/{object_type}/{object}/delete_impact",
+			handlers.IstioConfigDeleteImpact,
+			true,
+		},
+		// swagger:route GET /namespaces/{namespace}/istio/virtualservices/{object}/route_metrics config virtualServiceRouteMetrics
+		// ---
+		// Endpoint to fetch the request rate, the error rate and the latencies of the HTTP routes of a VirtualService
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      200: virtualServiceRouteMetricsResponse
+		//      404: notFoundError
+		//      500: internalError
+		//
+		{
+			"VirtualServiceRouteMetrics",
+			"GET",
+			"/api/namespaces/{namespace}/istio/virtualservices/{object}/route_metrics",
+			handlers.VirtualServiceRouteMetrics,
+			true,
+		},
 		// swagger:route PATCH /namespaces/{namespace}/istio/{object_type}/{object} config istioConfigUpdate
 		// ---
 		// Endpoint to update the Istio Config of an Istio object used for templates and adapters using Json Merge Patch strategy.
@@ -299,6 +343,7 @@ func NewRoutes() (r *Routes) {
 		// responses:
 		//      400: badRequestError
 		//      404: notFoundError
+		//      422: istioConfigRejectionResponse
 		//      500: internalError
 		//      200: istioConfigDetailsResponse
 		//
@@ -319,7 +364,9 @@ func NewRoutes() (r *Routes) {
 		//     Schemes: http, https
 		//
 		// responses:
+		//      400: badRequestError
 		//      404: notFoundError
+		//      422: istioConfigRejectionResponse
 		//      500: internalError
 		//		202
 		//		201: istioConfigDetailsResponse
@@ -398,6 +445,135 @@ func NewRoutes() (r *Routes) {
 			handlers.ServiceUpdate,
 			true,
 		},
+		// swagger:route GET /namespaces/{namespace}/services/{service}/traffic_splits services serviceTrafficSplits
+		// ---
+		// Endpoint to compare the weights of the routes splitting the traffic of the service with the observed traffic
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      404: notFoundError
+		//      500: internalError
+		//      200: serviceTrafficSplitsResponse
+		//
+		{
+			"ServiceTrafficSplits",
+			"GET",
+			"/api/namespaces/{namespace}/services/{service}/traffic_splits",
+			handlers.ServiceTrafficSplits,
+			true,
+		},
+		// swagger:route GET /namespaces/{namespace}/services/{service}/subsets/{subset}/health services serviceSubsetHealth
+		// ---
+		// Endpoint to get the success rate and latencies of the workloads of a DestinationRule subset of the service
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      404: notFoundError
+		//      500: internalError
+		//      200: serviceSubsetHealthResponse
+		//
+		{
+			"ServiceSubsetHealth",
+			"GET",
+			"/api/namespaces/{namespace}/services/{service}/subsets/{subset}/health",
+			handlers.ServiceSubsetHealth,
+			true,
+		},
+		// swagger:route GET /namespaces/{namespace}/services/{service}/slo_burn_rate services serviceSLOBurnRate
+		// ---
+		// Endpoint to compute the error budget consumed by the service over the window of an SLO, and the burn rates
+		// of the fast and slow multi-window alerts
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      400: badRequestError
+		//      503: serviceUnavailableError
+		//      200: serviceSLOBurnRateResponse
+		//
+		{
+			"ServiceSLOBurnRate",
+			"GET",
+			"/api/namespaces/{namespace}/services/{service}/slo_burn_rate",
+			handlers.ServiceSLOBurnRate,
+			true,
+		},
+		// swagger:route GET /namespaces/{namespace}/services/{service}/endpoints_health services serviceEndpointsHealth
+		// ---
+		// Endpoint to count the ready and not ready endpoints of the service per zone, from its EndpointSlices
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      404: notFoundError
+		//      500: internalError
+		//      200: serviceEndpointsHealthResponse
+		//
+		{
+			"ServiceEndpointsHealth",
+			"GET",
+			"/api/namespaces/{namespace}/services/{service}/endpoints_health",
+			handlers.ServiceEndpointsHealth,
+			true,
+		},
+		// swagger:route GET /namespaces/{namespace}/services/{service}/effective_destination_rule services serviceEffectiveDestinationRule
+		// ---
+		// Endpoint to resolve the DestinationRule in effect for the service, with the merged traffic policy and subsets,
+		// and the DestinationRules it shadows
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      404: notFoundError
+		//      500: internalError
+		//      200: serviceEffectiveDestinationRuleResponse
+		//
+		{
+			"ServiceEffectiveDestinationRule",
+			"GET",
+			"/api/namespaces/{namespace}/services/{service}/effective_destination_rule",
+			handlers.ServiceEffectiveDestinationRule,
+			true,
+		},
+		// swagger:route GET /namespaces/{namespace}/services/{service}/resilience services serviceResilienceConfig
+		// ---
+		// Endpoint to get the timeout and retries of the routes to the service, telling the settings of the routes
+		// from the ones inherited from the mesh
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      404: notFoundError
+		//      500: internalError
+		//      200: serviceResilienceConfigResponse
+		//
+		{
+			"ServiceResilienceConfig",
+			"GET",
+			"/api/namespaces/{namespace}/services/{service}/resilience",
+			handlers.ServiceResilienceConfig,
+			true,
+		},
 		// swagger:route GET /namespaces/{namespace}/apps/{app}/spans traces appSpans
 		// ---
 		// Endpoint to get Jaeger spans for a given app
@@ -476,6 +652,28 @@ func NewRoutes() (r *Routes) {
 			handlers.AppTraces,
 			true,
 		},
+		// swagger:route GET /namespaces/{namespace}/apps/{app}/traces/export traces appTracesExport
+		// ---
+		// Endpoint to export the traces of a given app in OTLP/JSON, a line per trace
+		//
+		//     Produces:
+		//     - application/x-ndjson
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      400: badRequestError
+		//      404: notFoundError
+		//      503: serviceUnavailableError
+		//      200: otlpTracesResponse
+		//
+		{
+			"AppTracesExport",
+			"GET",
+			"/api/namespaces/{namespace}/apps/{app}/traces/export",
+			handlers.AppTracesExport,
+			true,
+		},
 		// swagger:route GET /namespaces/{namespace}/services/{service}/traces traces serviceTraces
 		// ---
 		// Endpoint to get the traces of a given service
@@ -539,6 +737,50 @@ func NewRoutes() (r *Routes) {
 			handlers.ErrorTraces,
 			true,
 		},
+		// swagger:route GET /namespaces/{namespace}/tracing/sampling traces tracingSampling
+		// ---
+		// Endpoint to estimate the trace sampling of the services of a namespace, comparing the traces found with the requests received
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      400: badRequestError
+		//      404: notFoundError
+		//      500: internalError
+		//      200: tracingSamplingResponse
+		//
+		{
+			"TracingSampling",
+			"GET",
+			"/api/namespaces/{namespace}/tracing/sampling",
+			handlers.TracingSampling,
+			true,
+		},
+		// swagger:route GET /namespaces/{namespace}/workloads/{workload}/tracing/diagnosis traces workloadTracingDiagnosis
+		// ---
+		// Endpoint to diagnose why a workload may have no trace: sidecar injection, tracing provider, sampling and traces found
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      400: badRequestError
+		//      404: notFoundError
+		//      500: internalError
+		//      200: tracingDiagnosisResponse
+		//
+		{
+			"WorkloadTracingDiagnosis",
+			"GET",
+			"/api/namespaces/{namespace}/workloads/{workload}/tracing/diagnosis",
+			handlers.WorkloadTracingDiagnosis,
+			true,
+		},
 		// swagger:route GET /traces/{traceID} traces traceDetails
 		// ---
 		// Endpoint to get a specific trace from ID
@@ -560,6 +802,27 @@ func NewRoutes() (r *Routes) {
 			handlers.TraceDetails,
 			true,
 		},
+		// swagger:route GET /traces/{traceID}/tree traces traceSpanTree
+		// ---
+		// Endpoint to get a specific trace from ID, with its spans organized as a tree and correlated to workloads
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      404: notFoundError
+		//      503: serviceUnavailableError
+		//      200: traceSpanTreeResponse
+		//
+		{
+			"TraceSpanTree",
+			"GET",
+			"/api/traces/{traceID}/tree",
+			handlers.TraceSpanTree,
+			true,
+		},
 		// swagger:route GET /namespaces/{namespace}/workloads workloads workloadList
 		// ---
 		// Endpoint to get the list of workloads for a namespace
@@ -626,9 +889,825 @@ func NewRoutes() (r *Routes) {
 			handlers.WorkloadUpdate,
 			true,
 		},
-		// swagger:route GET /namespaces/{namespace}/apps apps appList
+		// swagger:route GET /namespaces/{namespace}/workloads/{workload}/access_logging workloads workloadAccessLogging
+		// ---
+		// Endpoint to get the effective access logging of the proxy of a Workload, from the mesh config and the Telemetry resources
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      404: notFoundError
+		//      500: internalError
+		//      200: workloadAccessLoggingResponse
+		//
+		{
+			"WorkloadAccessLogging",
+			"GET",
+			"/api/namespaces/{namespace}/workloads/{workload}/access_logging",
+			handlers.WorkloadAccessLogging,
+			true,
+		},
+		// swagger:route PATCH /namespaces/{namespace}/workloads/{workload}/metadata workloads workloadMetadataUpdate
+		// ---
+		// Endpoint to add, update or remove (null value) the labels and annotations of a Workload. With podTemplate,
+		// they are set in the pod template of the Workload instead, which rolls out new pods.
+		//
+		//     Consumes:
+		//	   - application/json
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      400: badRequestError
+		//      404: notFoundError
+		//      500: internalError
+		//      200: workloadDetails
+		//
+		{
+			"WorkloadMetadataUpdate",
+			"PATCH",
+			"/api/namespaces/{namespace}/workloads/{workload}/metadata",
+			handlers.WorkloadMetadataUpdate,
+			true,
+		},
+		// swagger:route PUT /namespaces/{namespace}/workloads/{workload}/maintenance workloads workloadMaintenanceSet
+		// ---
+		// Endpoint to put a Workload in maintenance for a duration: its degraded or failure health is suppressed
+		// until the maintenance expires.
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      400: badRequestError
+		//      404: notFoundError
+		//      500: internalError
+		//      200: workloadDetails
+		//
+		{
+			"WorkloadMaintenanceSet",
+			"PUT",
+			"/api/namespaces/{namespace}/workloads/{workload}/maintenance",
+			handlers.WorkloadMaintenanceSet,
+			true,
+		},
+		// swagger:route DELETE /namespaces/{namespace}/workloads/{workload}/maintenance workloads workloadMaintenanceClear
+		// ---
+		// Endpoint to end the maintenance of a Workload, restoring its health.
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      404: notFoundError
+		//      500: internalError
+		//      200: workloadDetails
+		//
+		{
+			"WorkloadMaintenanceClear",
+			"DELETE",
+			"/api/namespaces/{namespace}/workloads/{workload}/maintenance",
+			handlers.WorkloadMaintenanceClear,
+			true,
+		},
+		// swagger:route GET /namespaces/{namespace}/workloads/{workload}/rollout_status workloads workloadRolloutStatus
+		// ---
+		// Endpoint to get the rollout status of a Deployment or a StatefulSet: its replicas, its Progressing
+		// condition and whether the rollout exceeded its progress deadline.
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      400: badRequestError
+		//      404: notFoundError
+		//      500: internalError
+		//      200: workloadRolloutStatusResponse
+		//
+		{
+			"WorkloadRolloutStatus",
+			"GET",
+			"/api/namespaces/{namespace}/workloads/{workload}/rollout_status",
+			handlers.WorkloadRolloutStatus,
+			true,
+		},
+		// swagger:route GET /namespaces/{namespace}/apps apps appList
+		// ---
+		// Endpoint to get the list of apps for a namespace
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      500: internalError
+		//      200: appListResponse
+		//
+		{
+			"AppList",
+			"GET",
+			"/api/namespaces/{namespace}/apps",
+			handlers.AppList,
+			true,
+		},
+		// swagger:route GET /namespaces/{namespace}/apps/{app} apps appDetails
+		// ---
+		// Endpoint to get the app details
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      500: internalError
+		//      404: notFoundError
+		//      200: appDetails
+		//
+		{
+			"AppDetails",
+			"GET",
+			"/api/namespaces/{namespace}/apps/{app}",
+			handlers.AppDetails,
+			true,
+		},
+		// swagger:route GET /namespaces namespaces namespaceList
+		// ---
+		// Endpoint to get the list of the available namespaces
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      500: internalError
+		//      200: namespaceList
+		//
+		{
+			"NamespaceList",
+			"GET",
+			"/api/namespaces",
+			handlers.NamespaceList,
+			true,
+		},
+		// swagger:route PATCH /namespaces/{namespace} namespaces namespaceUpdate
+		// ---
+		// Endpoint to update the Namespace configuration using Json Merge Patch strategy.
+		//
+		//     Consumes:
+		//	   - application/json
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      400: badRequestError
+		//      404: notFoundError
+		//      500: internalError
+		//      200: namespaceResponse
+		//
+		{
+			"NamespaceUpdate",
+			"PATCH",
+			"/api/namespaces/{namespace}",
+			handlers.NamespaceUpdate,
+			true,
+		},
+		// swagger:route GET /namespaces/{namespace}/services/{service}/metrics services serviceMetrics
+		// ---
+		// Endpoint to fetch metrics to be displayed, related to a single service
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      400: badRequestError
+		//      503: serviceUnavailableError
+		//      200: metricsResponse
+		//
+		{
+			"ServiceMetrics",
+			"GET",
+			"/api/namespaces/{namespace}/services/{service}/metrics",
+			handlers.ServiceMetrics,
+			true,
+		},
+		// swagger:route GET /namespaces/{namespace}/aggregates/{aggregate}/{aggregateValue}/metrics aggregates aggregateMetrics
+		// ---
+		// Endpoint to fetch metrics to be displayed, related to a single aggregate
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      400: badRequestError
+		//      503: serviceUnavailableError
+		//      200: metricsResponse
+		//
+		{
+			"AggregateMetrics",
+			"GET",
+			"/api/namespaces/{namespace}/aggregates/{aggregate}/{aggregateValue}/metrics",
+			handlers.AggregateMetrics,
+			true,
+		},
+		// swagger:route GET /namespaces/{namespace}/apps/{app}/metrics apps appMetrics
+		// ---
+		// Endpoint to fetch metrics to be displayed, related to a single app
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      400: badRequestError
+		//      503: serviceUnavailableError
+		//      200: metricsResponse
+		//
+		{
+			"AppMetrics",
+			"GET",
+			"/api/namespaces/{namespace}/apps/{app}/metrics",
+			handlers.AppMetrics,
+			true,
+		},
+		// swagger:route GET /namespaces/{namespace}/workloads/{workload}/metrics workloads workloadMetrics
+		// ---
+		// Endpoint to fetch metrics to be displayed, related to a single workload
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      400: badRequestError
+		//      503: serviceUnavailableError
+		//      200: metricsResponse
+		//
+		{
+			"WorkloadMetrics",
+			"GET",
+			"/api/namespaces/{namespace}/workloads/{workload}/metrics",
+			handlers.WorkloadMetrics,
+			true,
+		},
+		// swagger:route GET /namespaces/{namespace}/workloads/{workload}/port_metrics workloads workloadPortMetrics
+		// ---
+		// Endpoint to fetch the request rate, the error rate and the latencies of the traffic of a workload per
+		// destination port
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      400: badRequestError
+		//      503: serviceUnavailableError
+		//      200: workloadPortMetricsResponse
+		//
+		{
+			"WorkloadPortMetrics",
+			"GET",
+			"/api/namespaces/{namespace}/workloads/{workload}/port_metrics",
+			handlers.WorkloadPortMetrics,
+			true,
+		},
+		// swagger:route GET /namespaces/{namespace}/workloads/{workload}/size_metrics workloads workloadSizeMetrics
+		// ---
+		// Endpoint to fetch the average and the quantiles of the sizes of the request and response bodies of a workload
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      400: badRequestError
+		//      503: serviceUnavailableError
+		//      200: workloadSizeMetricsResponse
+		//
+		{
+			"WorkloadSizeMetrics",
+			"GET",
+			"/api/namespaces/{namespace}/workloads/{workload}/size_metrics",
+			handlers.WorkloadSizeMetrics,
+			true,
+		},
+		// swagger:route GET /namespaces/{namespace}/workloads/{workload}/connection_metrics workloads workloadConnectionMetrics
+		// ---
+		// Endpoint to fetch the active and total TCP connections of a workload, inbound and outbound, per peer
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      400: badRequestError
+		//      503: serviceUnavailableError
+		//      200: workloadConnectionMetricsResponse
+		//
+		{
+			"WorkloadConnectionMetrics",
+			"GET",
+			"/api/namespaces/{namespace}/workloads/{workload}/connection_metrics",
+			handlers.WorkloadConnectionMetrics,
+			true,
+		},
+		// swagger:route GET /namespaces/{namespace}/services/{service}/dashboard services serviceDashboard
+		// ---
+		// Endpoint to fetch dashboard to be displayed, related to a single service
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      400: badRequestError
+		//      503: serviceUnavailableError
+		//      200: dashboardResponse
+		//
+		{
+			"ServiceDashboard",
+			"GET",
+			"/api/namespaces/{namespace}/services/{service}/dashboard",
+			handlers.ServiceDashboard,
+			true,
+		},
+		// swagger:route GET /namespaces/{namespace}/apps/{app}/dashboard apps appDashboard
+		// ---
+		// Endpoint to fetch dashboard to be displayed, related to a single app
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      400: badRequestError
+		//      503: serviceUnavailableError
+		//      200: dashboardResponse
+		//
+		{
+			"AppDashboard",
+			"GET",
+			"/api/namespaces/{namespace}/apps/{app}/dashboard",
+			handlers.AppDashboard,
+			true,
+		},
+		// swagger:route GET /namespaces/{namespace}/workloads/{workload}/dashboard workloads workloadDashboard
+		// ---
+		// Endpoint to fetch dashboard to be displayed, related to a single workload
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      400: badRequestError
+		//      503: serviceUnavailableError
+		//      200: dashboardResponse
+		//
+		{
+			"WorkloadDashboard",
+			"GET",
+			"/api/namespaces/{namespace}/workloads/{workload}/dashboard",
+			handlers.WorkloadDashboard,
+			true,
+		},
+		// swagger:route GET /namespaces/{namespace}/apps/{app}/grafana apps appGrafanaDashboards
+		// ---
+		// Endpoint to fetch the links to the Grafana dashboards grouped by folder, related to a single app
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      500: internalError
+		//      503: serviceUnavailableError
+		//      200: grafanaDashboardFoldersResponse
+		//      204: noContent
+		//
+		{
+			"AppGrafanaDashboards",
+			"GET",
+			"/api/namespaces/{namespace}/apps/{app}/grafana",
+			handlers.GrafanaDashboardFolders,
+			true,
+		},
+		// swagger:route GET /namespaces/{namespace}/workloads/{workload}/grafana workloads workloadGrafanaDashboards
+		// ---
+		// Endpoint to fetch the links to the Grafana dashboards grouped by folder, related to a single workload
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      500: internalError
+		//      503: serviceUnavailableError
+		//      200: grafanaDashboardFoldersResponse
+		//      204: noContent
+		//
+		{
+			"WorkloadGrafanaDashboards",
+			"GET",
+			"/api/namespaces/{namespace}/workloads/{workload}/grafana",
+			handlers.GrafanaDashboardFolders,
+			true,
+		},
+		// swagger:route GET /namespaces/{namespace}/services/{service}/grafana services serviceGrafanaDashboards
+		// ---
+		// Endpoint to fetch the links to the Grafana dashboards grouped by folder, related to a single service
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      500: internalError
+		//      503: serviceUnavailableError
+		//      200: grafanaDashboardFoldersResponse
+		//      204: noContent
+		//
+		{
+			"ServiceGrafanaDashboards",
+			"GET",
+			"/api/namespaces/{namespace}/services/{service}/grafana",
+			handlers.GrafanaDashboardFolders,
+			true,
+		},
+		// swagger:route GET /namespaces/{namespace}/workloads/{workload}/customdashboards/{dashboard} workloads workloadConfigDashboard
+		// ---
+		// Endpoint to fetch a dashboard of custom metrics defined in the configuration, related to a single workload
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      400: badRequestError
+		//      404: notFoundError
+		//      500: internalError
+		//      503: serviceUnavailableError
+		//      200: dashboardResponse
+		//
+		{
+			"WorkloadConfigDashboard",
+			"GET",
+			"/api/namespaces/{namespace}/workloads/{workload}/customdashboards/{dashboard}",
+			handlers.WorkloadConfigDashboard,
+			true,
+		},
+		// swagger:route GET /namespaces/{namespace}/customdashboard/{dashboard} dashboards customDashboard
+		// ---
+		// Endpoint to fetch a custom dashboard
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      400: badRequestError
+		//      503: serviceUnavailableError
+		//      200: dashboardResponse
+		//
+		{
+			"CustomDashboard",
+			"GET",
+			"/api/namespaces/{namespace}/customdashboard/{dashboard}",
+			handlers.CustomDashboard,
+			true,
+		},
+		// swagger:route GET /namespaces/{namespace}/services/{service}/health services serviceHealth
+		// ---
+		// Get health associated to the given service
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      200: serviceHealthResponse
+		//      404: notFoundError
+		//      500: internalError
+		//
+		{
+			"ServiceHealth",
+			"GET",
+			"/api/namespaces/{namespace}/services/{service}/health",
+			handlers.ServiceHealth,
+			true,
+		},
+		// swagger:route GET /namespaces/{namespace}/apps/{app}/health apps appHealth
+		// ---
+		// Get health associated to the given app
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      200: appHealthResponse
+		//      404: notFoundError
+		//      500: internalError
+		//
+		{
+			"AppHealth",
+			"GET",
+			"/api/namespaces/{namespace}/apps/{app}/health",
+			handlers.AppHealth,
+			true,
+		},
+		// swagger:route GET /namespaces/{namespace}/workloads/{workload}/health workloads workloadHealth
+		// ---
+		// Get health associated to the given workload
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      200: workloadHealthResponse
+		//      404: notFoundError
+		//      500: internalError
+		//
+		{
+			"WorkloadHealth",
+			"GET",
+			"/api/namespaces/{namespace}/workloads/{workload}/health",
+			handlers.WorkloadHealth,
+			true,
+		},
+		// swagger:route GET /namespaces/{namespace}/workloads/{workload}/health/dependencies workloads workloadDependencyHealth
+		// ---
+		// Get health associated to the given workload, along with the health of the workloads and services it sends requests to
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      200: workloadDependencyHealthResponse
+		//      404: notFoundError
+		//      500: internalError
+		//
+		{
+			"WorkloadDependencyHealth",
+			"GET",
+			"/api/namespaces/{namespace}/workloads/{workload}/health/dependencies",
+			handlers.WorkloadDependencyHealth,
+			true,
+		},
+		// swagger:route GET /namespaces/{namespace}/rollouts workloads rolloutList
+		// ---
+		// Endpoint to get the progressive delivery rollouts (Flagger, Argo Rollouts) of a namespace
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      500: internalError
+		//      200: rolloutListResponse
+		//
+		{
+			"RolloutList",
+			"GET",
+			"/api/namespaces/{namespace}/rollouts",
+			handlers.RolloutList,
+			true,
+		},
+		// swagger:route GET /namespaces/{namespace}/rollouts/{rollout}/metrics workloads rolloutMetrics
+		// ---
+		// Endpoint to compare the success rate and latencies of the canary and the stable variants of a rollout
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      404: notFoundError
+		//      500: internalError
+		//      200: rolloutMetricsResponse
+		//
+		{
+			"RolloutMetrics",
+			"GET",
+			"/api/namespaces/{namespace}/rollouts/{rollout}/metrics",
+			handlers.RolloutMetrics,
+			true,
+		},
+		// swagger:route GET /namespaces/{namespace}/workloads/{workload}/compare workloads workloadComparison
+		// ---
+		// Endpoint to compare the metrics, over the same time window, and the configuration of two workloads
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      400: badRequestError
+		//      404: notFoundError
+		//      500: internalError
+		//      200: workloadComparisonResponse
+		//
+		{
+			"WorkloadComparison",
+			"GET",
+			"/api/namespaces/{namespace}/workloads/{workload}/compare",
+			handlers.WorkloadComparison,
+			true,
+		},
+		// swagger:route GET /namespaces/{namespace}/apps/{app}/rollouts apps appRollouts
+		// ---
+		// Endpoint to get the progressive delivery rollouts of an app
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      500: internalError
+		//      200: rolloutListResponse
+		//
+		{
+			"AppRollouts",
+			"GET",
+			"/api/namespaces/{namespace}/apps/{app}/rollouts",
+			handlers.AppRollouts,
+			true,
+		},
+		// swagger:route GET /namespaces/{namespace}/metrics namespaces namespaceMetrics
+		// ---
+		// Endpoint to fetch metrics to be displayed, related to a namespace
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      400: badRequestError
+		//      503: serviceUnavailableError
+		//      200: metricsResponse
+		//
+		{
+			"NamespaceMetrics",
+			"GET",
+			"/api/namespaces/{namespace}/metrics",
+			handlers.NamespaceMetrics,
+			true,
+		},
+		// swagger:route GET /namespaces/{namespace}/edge_errors namespaces namespaceEdgeErrors
+		// ---
+		// Endpoint to explain the errors of an edge of the graph from a source of the namespace: the response codes,
+		// the response flags set by the proxies and the routes producing the most errors
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      400: badRequestError
+		//      503: serviceUnavailableError
+		//      200: edgeErrorsResponse
+		//
+		{
+			"NamespaceEdgeErrors",
+			"GET",
+			"/api/namespaces/{namespace}/edge_errors",
+			handlers.NamespaceEdgeErrors,
+			true,
+		},
+		// swagger:route GET /namespaces/{namespace}/top_talkers namespaces namespaceTopTalkers
+		// ---
+		// Endpoint to fetch the edges of the namespace with the highest request rate or bytes rate, a lightweight
+		// alternative to the graph to find the hotspots
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      400: badRequestError
+		//      503: serviceUnavailableError
+		//      200: topTalkersResponse
+		//
+		{
+			"NamespaceTopTalkers",
+			"GET",
+			"/api/namespaces/{namespace}/top_talkers",
+			handlers.NamespaceTopTalkers,
+			true,
+		},
+		// swagger:route GET /namespaces/{namespace}/health namespaces namespaceHealth
+		// ---
+		// Get health for all objects in the given namespace
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      200: namespaceAppHealthResponse
+		//      400: badRequestError
+		//      500: internalError
+		//
+		{
+			"NamespaceHealth",
+			"GET",
+			"/api/namespaces/{namespace}/health",
+			handlers.NamespaceHealth,
+			true,
+		},
+		// swagger:route GET /namespaces/{namespace}/validations namespaces namespaceValidations
+		// ---
+		// Get validation summary for all objects in the given namespace
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      200: namespaceValidationSummaryResponse
+		//      400: badRequestError
+		//      500: internalError
+		//
+		{
+			"NamespaceValidationSummary",
+			"GET",
+			"/api/namespaces/{namespace}/validations",
+			handlers.NamespaceValidationSummary,
+			true,
+		},
+		// swagger:route GET /namespaces/{namespace}/overview namespaces namespaceOverview
+		// ---
+		// Get the health, the Istio config counts, the mTLS status and the validations summary of the given namespace
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      200: namespaceOverviewResponse
+		//      404: notFoundError
+		//      500: internalError
+		//
+		{
+			"NamespaceOverview",
+			"GET",
+			"/api/namespaces/{namespace}/overview",
+			handlers.NamespaceOverview,
+			true,
+		},
+		// swagger:route GET /namespaces/{namespace}/validations/findings namespaces namespaceFilteredValidations
 		// ---
-		// Endpoint to get the list of apps for a namespace
+		// Get the validations of the objects of the given namespace matching the object type, name and minimum severity
 		//
 		//     Produces:
 		//     - application/json
@@ -636,19 +1715,20 @@ func NewRoutes() (r *Routes) {
 		//     Schemes: http, https
 		//
 		// responses:
+		//      200: filteredValidationsResponse
+		//      400: badRequestError
 		//      500: internalError
-		//      200: appListResponse
 		//
 		{
-			"AppList",
+			"NamespaceFilteredValidations",
 			"GET",
-			"/api/namespaces/{namespace}/apps",
-			handlers.AppList,
+			"/api/namespaces/{namespace}/validations/findings",
+			handlers.NamespaceFilteredValidations,
 			true,
 		},
-		// swagger:route GET /namespaces/{namespace}/apps/{app} apps appDetails
+		// swagger:route GET /namespaces/{namespace}/validations/unused namespaces namespaceUnusedIstioConfig
 		// ---
-		// Endpoint to get the app details
+		// Get the Istio objects of the given namespace that have no effect, grouped by reason
 		//
 		//     Produces:
 		//     - application/json
@@ -656,20 +1736,21 @@ func NewRoutes() (r *Routes) {
 		//     Schemes: http, https
 		//
 		// responses:
+		//      200: unusedIstioConfigResponse
+		//      400: badRequestError
 		//      500: internalError
-		//      404: notFoundError
-		//      200: appDetails
 		//
 		{
-			"AppDetails",
+			"NamespaceUnusedIstioConfig",
 			"GET",
-			"/api/namespaces/{namespace}/apps/{app}",
-			handlers.AppDetails,
+			"/api/namespaces/{namespace}/validations/unused",
+			handlers.NamespaceUnusedIstioConfig,
 			true,
 		},
-		// swagger:route GET /namespaces namespaces namespaceList
+		// swagger:route GET /namespaces/{namespace}/config_references namespaces namespaceConfigReferenceGraph
 		// ---
-		// Endpoint to get the list of the available namespaces
+		// Get the graph of the references of the Istio objects of the given namespace to each other, to the Services
+		// and to the workloads
 		//
 		//     Produces:
 		//     - application/json
@@ -677,22 +1758,20 @@ func NewRoutes() (r *Routes) {
 		//     Schemes: http, https
 		//
 		// responses:
+		//      200: configReferenceGraphResponse
+		//      400: badRequestError
 		//      500: internalError
-		//      200: namespaceList
 		//
 		{
-			"NamespaceList",
+			"NamespaceConfigReferenceGraph",
 			"GET",
-			"/api/namespaces",
-			handlers.NamespaceList,
+			"/api/namespaces/{namespace}/config_references",
+			handlers.NamespaceConfigReferenceGraph,
 			true,
 		},
-		// swagger:route PATCH /namespaces/{namespace} namespaces namespaceUpdate
+		// swagger:route GET /namespaces/{namespace}/ratelimits namespaces namespaceRateLimits
 		// ---
-		// Endpoint to update the Namespace configuration using Json Merge Patch strategy.
-		//
-		//     Consumes:
-		//	   - application/json
+		// Get the local and global rate limits configured by EnvoyFilters for the workloads of the given namespace
 		//
 		//     Produces:
 		//     - application/json
@@ -700,21 +1779,20 @@ func NewRoutes() (r *Routes) {
 		//     Schemes: http, https
 		//
 		// responses:
-		//      400: badRequestError
+		//      200: rateLimitsResponse
 		//      404: notFoundError
 		//      500: internalError
-		//      200: namespaceResponse
 		//
 		{
-			"NamespaceUpdate",
-			"PATCH",
-			"/api/namespaces/{namespace}",
-			handlers.NamespaceUpdate,
+			"NamespaceRateLimits",
+			"GET",
+			"/api/namespaces/{namespace}/ratelimits",
+			handlers.NamespaceRateLimits,
 			true,
 		},
-		// swagger:route GET /namespaces/{namespace}/services/{service}/metrics services serviceMetrics
+		// swagger:route GET /namespaces/{namespace}/header_routes namespaces namespaceHeaderRoutes
 		// ---
-		// Endpoint to fetch metrics to be displayed, related to a single service
+		// Get the routes of the VirtualServices of the given namespace that match on request headers, with their destinations
 		//
 		//     Produces:
 		//     - application/json
@@ -722,20 +1800,20 @@ func NewRoutes() (r *Routes) {
 		//     Schemes: http, https
 		//
 		// responses:
-		//      400: badRequestError
-		//      503: serviceUnavailableError
-		//      200: metricsResponse
+		//      200: headerRoutesResponse
+		//      404: notFoundError
+		//      500: internalError
 		//
 		{
-			"ServiceMetrics",
+			"NamespaceHeaderRoutes",
 			"GET",
-			"/api/namespaces/{namespace}/services/{service}/metrics",
-			handlers.ServiceMetrics,
+			"/api/namespaces/{namespace}/header_routes",
+			handlers.NamespaceHeaderRoutes,
 			true,
 		},
-		// swagger:route GET /namespaces/{namespace}/aggregates/{aggregate}/{aggregateValue}/metrics aggregates aggregateMetrics
+		// swagger:route GET /namespaces/{namespace}/proxy_status namespaces namespaceProxyStatus
 		// ---
-		// Endpoint to fetch metrics to be displayed, related to a single aggregate
+		// Get the number of proxies of the given namespace by sync status, and the pods whose proxy is not synced
 		//
 		//     Produces:
 		//     - application/json
@@ -743,20 +1821,20 @@ func NewRoutes() (r *Routes) {
 		//     Schemes: http, https
 		//
 		// responses:
-		//      400: badRequestError
-		//      503: serviceUnavailableError
-		//      200: metricsResponse
+		//      200: namespaceProxyStatusResponse
+		//      404: notFoundError
+		//      500: internalError
 		//
 		{
-			"AggregateMetrics",
+			"NamespaceProxyStatus",
 			"GET",
-			"/api/namespaces/{namespace}/aggregates/{aggregate}/{aggregateValue}/metrics",
-			handlers.AggregateMetrics,
+			"/api/namespaces/{namespace}/proxy_status",
+			handlers.NamespaceProxyStatus,
 			true,
 		},
-		// swagger:route GET /namespaces/{namespace}/apps/{app}/metrics apps appMetrics
+		// swagger:route GET /namespaces/{namespace}/proxy_log_level namespaces namespaceProxyLogLevel
 		// ---
-		// Endpoint to fetch metrics to be displayed, related to a single app
+		// Get the default log level of the proxies of the given namespace
 		//
 		//     Produces:
 		//     - application/json
@@ -764,20 +1842,20 @@ func NewRoutes() (r *Routes) {
 		//     Schemes: http, https
 		//
 		// responses:
-		//      400: badRequestError
-		//      503: serviceUnavailableError
-		//      200: metricsResponse
+		//      200: namespaceProxyLogLevelResponse
+		//      404: notFoundError
+		//      500: internalError
 		//
 		{
-			"AppMetrics",
+			"NamespaceProxyLogLevel",
 			"GET",
-			"/api/namespaces/{namespace}/apps/{app}/metrics",
-			handlers.AppMetrics,
+			"/api/namespaces/{namespace}/proxy_log_level",
+			handlers.NamespaceProxyLogLevel,
 			true,
 		},
-		// swagger:route GET /namespaces/{namespace}/workloads/{workload}/metrics workloads workloadMetrics
+		// swagger:route PUT /namespaces/{namespace}/proxy_log_level namespaces namespaceProxyLogLevelSet
 		// ---
-		// Endpoint to fetch metrics to be displayed, related to a single workload
+		// Change the log level of the proxies of the given namespace, and apply it periodically to the new pods
 		//
 		//     Produces:
 		//     - application/json
@@ -785,20 +1863,21 @@ func NewRoutes() (r *Routes) {
 		//     Schemes: http, https
 		//
 		// responses:
+		//      200: namespaceProxyLogLevelResponse
 		//      400: badRequestError
-		//      503: serviceUnavailableError
-		//      200: metricsResponse
+		//      404: notFoundError
+		//      500: internalError
 		//
 		{
-			"WorkloadMetrics",
-			"GET",
-			"/api/namespaces/{namespace}/workloads/{workload}/metrics",
-			handlers.WorkloadMetrics,
+			"NamespaceProxyLogLevelSet",
+			"PUT",
+			"/api/namespaces/{namespace}/proxy_log_level",
+			handlers.NamespaceProxyLogLevelSet,
 			true,
 		},
-		// swagger:route GET /namespaces/{namespace}/services/{service}/dashboard services serviceDashboard
+		// swagger:route DELETE /namespaces/{namespace}/proxy_log_level namespaces namespaceProxyLogLevelClear
 		// ---
-		// Endpoint to fetch dashboard to be displayed, related to a single service
+		// Stop applying the default log level of the proxies of the given namespace to the new pods
 		//
 		//     Produces:
 		//     - application/json
@@ -806,20 +1885,20 @@ func NewRoutes() (r *Routes) {
 		//     Schemes: http, https
 		//
 		// responses:
-		//      400: badRequestError
-		//      503: serviceUnavailableError
-		//      200: dashboardResponse
+		//      200: noContent
+		//      404: notFoundError
+		//      500: internalError
 		//
 		{
-			"ServiceDashboard",
-			"GET",
-			"/api/namespaces/{namespace}/services/{service}/dashboard",
-			handlers.ServiceDashboard,
+			"NamespaceProxyLogLevelClear",
+			"DELETE",
+			"/api/namespaces/{namespace}/proxy_log_level",
+			handlers.NamespaceProxyLogLevelClear,
 			true,
 		},
-		// swagger:route GET /namespaces/{namespace}/apps/{app}/dashboard apps appDashboard
+		// swagger:route GET /namespaces/{namespace}/proxy_resources namespaces namespaceProxyResources
 		// ---
-		// Endpoint to fetch dashboard to be displayed, related to a single app
+		// Get the requests, limits and observed usage of CPU and memory of the istio-proxy containers of the given namespace, per workload
 		//
 		//     Produces:
 		//     - application/json
@@ -827,20 +1906,21 @@ func NewRoutes() (r *Routes) {
 		//     Schemes: http, https
 		//
 		// responses:
-		//      400: badRequestError
-		//      503: serviceUnavailableError
-		//      200: dashboardResponse
+		//      200: namespaceProxyResourcesResponse
+		//      404: notFoundError
+		//      500: internalError
 		//
 		{
-			"AppDashboard",
+			"NamespaceProxyResources",
 			"GET",
-			"/api/namespaces/{namespace}/apps/{app}/dashboard",
-			handlers.AppDashboard,
+			"/api/namespaces/{namespace}/proxy_resources",
+			handlers.NamespaceProxyResources,
 			true,
 		},
-		// swagger:route GET /namespaces/{namespace}/workloads/{workload}/dashboard workloads workloadDashboard
+		// swagger:route GET /namespaces/{namespace}/proxy_memory namespaces namespaceProxyMemory
 		// ---
-		// Endpoint to fetch dashboard to be displayed, related to a single workload
+		// Get the memory usage against the limit and the OOMKills of the istio-proxy containers of the given namespace, per workload,
+		// flagging the proxies near their limit or recently OOMKilled
 		//
 		//     Produces:
 		//     - application/json
@@ -848,20 +1928,20 @@ func NewRoutes() (r *Routes) {
 		//     Schemes: http, https
 		//
 		// responses:
-		//      400: badRequestError
-		//      503: serviceUnavailableError
-		//      200: dashboardResponse
+		//      200: namespaceProxyMemoryResponse
+		//      404: notFoundError
+		//      500: internalError
 		//
 		{
-			"WorkloadDashboard",
+			"NamespaceProxyMemory",
 			"GET",
-			"/api/namespaces/{namespace}/workloads/{workload}/dashboard",
-			handlers.WorkloadDashboard,
+			"/api/namespaces/{namespace}/proxy_memory",
+			handlers.NamespaceProxyMemory,
 			true,
 		},
-		// swagger:route GET /namespaces/{namespace}/customdashboard/{dashboard} dashboards customDashboard
+		// swagger:route GET /mesh/tls tls meshTls
 		// ---
-		// Endpoint to fetch a custom dashboard
+		// Get TLS status for the whole mesh
 		//
 		//     Produces:
 		//     - application/json
@@ -869,20 +1949,20 @@ func NewRoutes() (r *Routes) {
 		//     Schemes: http, https
 		//
 		// responses:
+		//      200: meshTlsResponse
 		//      400: badRequestError
-		//      503: serviceUnavailableError
-		//      200: dashboardResponse
+		//      500: internalError
 		//
 		{
-			"CustomDashboard",
+			"NamespaceTls",
 			"GET",
-			"/api/namespaces/{namespace}/customdashboard/{dashboard}",
-			handlers.CustomDashboard,
+			"/api/mesh/tls",
+			handlers.MeshTls,
 			true,
 		},
-		// swagger:route GET /namespaces/{namespace}/services/{service}/health services serviceHealth
+		// swagger:route GET /mesh/snapshot mesh meshSnapshot
 		// ---
-		// Get health associated to the given service
+		// Get a deterministic snapshot of the health, the mTLS status and the validations of all the namespaces
 		//
 		//     Produces:
 		//     - application/json
@@ -890,20 +1970,19 @@ func NewRoutes() (r *Routes) {
 		//     Schemes: http, https
 		//
 		// responses:
-		//      200: serviceHealthResponse
-		//      404: notFoundError
+		//      200: meshSnapshotResponse
 		//      500: internalError
 		//
 		{
-			"ServiceHealth",
+			"MeshSnapshot",
 			"GET",
-			"/api/namespaces/{namespace}/services/{service}/health",
-			handlers.ServiceHealth,
+			"/api/mesh/snapshot",
+			handlers.MeshSnapshot,
 			true,
 		},
-		// swagger:route GET /namespaces/{namespace}/apps/{app}/health apps appHealth
+		// swagger:route GET /mesh/extension_providers mesh meshExtensionProviders
 		// ---
-		// Get health associated to the given app
+		// Get the extension providers of the mesh config, with the reachability of their services
 		//
 		//     Produces:
 		//     - application/json
@@ -911,20 +1990,19 @@ func NewRoutes() (r *Routes) {
 		//     Schemes: http, https
 		//
 		// responses:
-		//      200: appHealthResponse
-		//      404: notFoundError
+		//      200: extensionProvidersResponse
 		//      500: internalError
 		//
 		{
-			"AppHealth",
+			"MeshExtensionProviders",
 			"GET",
-			"/api/namespaces/{namespace}/apps/{app}/health",
-			handlers.AppHealth,
+			"/api/mesh/extension_providers",
+			handlers.MeshExtensionProviders,
 			true,
 		},
-		// swagger:route GET /namespaces/{namespace}/workloads/{workload}/health workloads workloadHealth
+		// swagger:route GET /mesh/certs/chain certs meshCertificateChain
 		// ---
-		// Get health associated to the given workload
+		// Get the chain of trust of the mesh, from the root certificate down to the certificate of a sample workload
 		//
 		//     Produces:
 		//     - application/json
@@ -932,20 +2010,21 @@ func NewRoutes() (r *Routes) {
 		//     Schemes: http, https
 		//
 		// responses:
-		//      200: workloadHealthResponse
+		//      200: certificateChainResponse
+		//      400: badRequestError
 		//      404: notFoundError
 		//      500: internalError
 		//
 		{
-			"WorkloadHealth",
+			"MeshCertificateChain",
 			"GET",
-			"/api/namespaces/{namespace}/workloads/{workload}/health",
-			handlers.WorkloadHealth,
+			"/api/mesh/certs/chain",
+			handlers.MeshCertificateChain,
 			true,
 		},
-		// swagger:route GET /namespaces/{namespace}/metrics namespaces namespaceMetrics
+		// swagger:route GET /namespaces/{namespace}/tls tls namespaceTls
 		// ---
-		// Endpoint to fetch metrics to be displayed, related to a namespace
+		// Get TLS status for the given namespace
 		//
 		//     Produces:
 		//     - application/json
@@ -953,20 +2032,21 @@ func NewRoutes() (r *Routes) {
 		//     Schemes: http, https
 		//
 		// responses:
+		//      200: namespaceTlsResponse
 		//      400: badRequestError
-		//      503: serviceUnavailableError
-		//      200: metricsResponse
+		//      500: internalError
 		//
 		{
-			"NamespaceMetrics",
+			"NamespaceTls",
 			"GET",
-			"/api/namespaces/{namespace}/metrics",
-			handlers.NamespaceMetrics,
+			"/api/namespaces/{namespace}/tls",
+			handlers.NamespaceTls,
 			true,
 		},
-		// swagger:route GET /namespaces/{namespace}/health namespaces namespaceHealth
+		// swagger:route GET /namespaces/{namespace}/tls/backends tls namespaceBackendsTls
 		// ---
-		// Get health for all objects in the given namespace
+		// Get the TLS status of the Services routed by the Kubernetes Gateway API routes of the given namespace,
+		// combining the Istio mTLS with the BackendTLSPolicies
 		//
 		//     Produces:
 		//     - application/json
@@ -974,20 +2054,21 @@ func NewRoutes() (r *Routes) {
 		//     Schemes: http, https
 		//
 		// responses:
-		//      200: namespaceAppHealthResponse
-		//      400: badRequestError
+		//      200: namespaceBackendsTlsResponse
+		//      404: notFoundError
 		//      500: internalError
 		//
 		{
-			"NamespaceHealth",
+			"NamespaceBackendsTls",
 			"GET",
-			"/api/namespaces/{namespace}/health",
-			handlers.NamespaceHealth,
+			"/api/namespaces/{namespace}/tls/backends",
+			handlers.NamespaceBackendsTls,
 			true,
 		},
-		// swagger:route GET /namespaces/{namespace}/validations namespaces namespaceValidations
+		// swagger:route GET /namespaces/{namespace}/tls/recommendation tls namespaceMtlsRecommendation
 		// ---
-		// Get validation summary for all objects in the given namespace
+		// Recommend the next step toward STRICT mTLS for the given namespace, from its mTLS status and the plaintext
+		// traffic received by its workloads
 		//
 		//     Produces:
 		//     - application/json
@@ -995,20 +2076,20 @@ func NewRoutes() (r *Routes) {
 		//     Schemes: http, https
 		//
 		// responses:
-		//      200: namespaceValidationSummaryResponse
-		//      400: badRequestError
+		//      200: namespaceMtlsRecommendationResponse
+		//      404: notFoundError
 		//      500: internalError
 		//
 		{
-			"NamespaceValidationSummary",
+			"NamespaceMtlsRecommendation",
 			"GET",
-			"/api/namespaces/{namespace}/validations",
-			handlers.NamespaceValidationSummary,
+			"/api/namespaces/{namespace}/tls/recommendation",
+			handlers.NamespaceMtlsRecommendation,
 			true,
 		},
-		// swagger:route GET /mesh/tls tls meshTls
+		// swagger:route GET /istio/status status istioStatus
 		// ---
-		// Get TLS status for the whole mesh
+		// Get the status of each components needed in the control plane
 		//
 		//     Produces:
 		//     - application/json
@@ -1016,20 +2097,20 @@ func NewRoutes() (r *Routes) {
 		//     Schemes: http, https
 		//
 		// responses:
-		//      200: meshTlsResponse
+		//      200: istioStatusResponse
 		//      400: badRequestError
 		//      500: internalError
 		//
 		{
-			"NamespaceTls",
+			"IstioStatus",
 			"GET",
-			"/api/mesh/tls",
-			handlers.MeshTls,
+			"/api/istio/status",
+			handlers.IstioStatus,
 			true,
 		},
-		// swagger:route GET /namespaces/{namespace}/tls tls namespaceTls
+		// swagger:route GET /istio/status/pilot status pilotMetrics
 		// ---
-		// Get TLS status for the given namespace
+		// Get the push latency percentiles and the push error rates of the control plane
 		//
 		//     Produces:
 		//     - application/json
@@ -1037,20 +2118,19 @@ func NewRoutes() (r *Routes) {
 		//     Schemes: http, https
 		//
 		// responses:
-		//      200: namespaceTlsResponse
-		//      400: badRequestError
+		//      200: pilotMetricsResponse
 		//      500: internalError
 		//
 		{
-			"NamespaceTls",
+			"PilotMetrics",
 			"GET",
-			"/api/namespaces/{namespace}/tls",
-			handlers.NamespaceTls,
+			"/api/istio/status/pilot",
+			handlers.PilotMetrics,
 			true,
 		},
-		// swagger:route GET /istio/status status istioStatus
+		// swagger:route GET /istio/status/diagnostics status externalServicesDiagnostics
 		// ---
-		// Get the status of each components needed in the control plane
+		// Test the connection to the configured Prometheus and Jaeger, reporting reachability, authentication, latency and version
 		//
 		//     Produces:
 		//     - application/json
@@ -1058,15 +2138,14 @@ func NewRoutes() (r *Routes) {
 		//     Schemes: http, https
 		//
 		// responses:
-		//      200: istioStatusResponse
-		//      400: badRequestError
+		//      200: externalServicesDiagnosticsResponse
 		//      500: internalError
 		//
 		{
-			"IstioStatus",
+			"ExternalServicesDiagnostics",
 			"GET",
-			"/api/istio/status",
-			handlers.IstioStatus,
+			"/api/istio/status/diagnostics",
+			handlers.ExternalServicesDiagnostics,
 			true,
 		},
 		// swagger:route GET /namespaces/graph graphs graphNamespaces
@@ -1304,6 +2383,49 @@ func NewRoutes() (r *Routes) {
 			handlers.PodLogs,
 			true,
 		},
+		// swagger:route GET /namespaces/{namespace}/pods/{pod}/accesslogs pods podAccessLogs
+		// ---
+		// Endpoint to get the parsed access logs of the proxy of a pod, linked to the traces of the workload when given
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      500: internalError
+		//      404: notFoundError
+		//      400: badRequestError
+		//      200: accessLogsResponse
+		//
+		{
+			"PodAccessLogs",
+			"GET",
+			"/api/namespaces/{namespace}/pods/{pod}/accesslogs",
+			handlers.PodAccessLogs,
+			true,
+		},
+		// swagger:route GET /namespaces/{namespace}/pods/{pod}/env pods podEnv
+		// ---
+		// Endpoint to get the environment of the containers of a pod, the values taken from Secrets are masked
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      500: internalError
+		//      404: notFoundError
+		//      200: podEnvResponse
+		//
+		{
+			"PodEnv",
+			"GET",
+			"/api/namespaces/{namespace}/pods/{pod}/env",
+			handlers.PodEnv,
+			true,
+		},
 		// swagger:route GET /namespaces/{namespace}/pods/{pod}/config_dump pods podProxyDump
 		// ---
 		// Endpoint to get pod proxy dump
@@ -1325,6 +2447,25 @@ func NewRoutes() (r *Routes) {
 			handlers.ConfigDump,
 			true,
 		},
+		// swagger:route POST /namespaces/{namespace}/pods/{pod}/logging pods podProxyLogging
+		// ---
+		// Endpoint to change the log level of the proxy of the pod, until the pod is restarted
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      500: internalError
+		//      404: notFoundError
+		//      400: badRequestError
+		//      200: noContent
+		//
+		{
+			"PodProxyLogging",
+			"POST",
+			"/api/namespaces/{namespace}/pods/{pod}/logging",
+			handlers.PodProxyLogging,
+			true,
+		},
 		// swagger:route GET /namespaces/{namespace}/pods/{pod}/config_dump/{resource} pods podProxyResource
 		// ---
 		// Endpoint to get pod logs
@@ -1540,6 +2681,26 @@ func NewRoutes() (r *Routes) {
 			handlers.GetClusters,
 			true,
 		},
+		// swagger:route GET /clusters/overview clusters clustersOverview
+		// ---
+		// Endpoint to get the health of each cluster of the mesh: reachability, control plane status and version, and number of connected proxies.
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      500: internalError
+		//      200: clustersOverviewResponse
+		//
+		{
+			"ClustersOverview",
+			"GET",
+			"/api/clusters/overview",
+			handlers.ClustersOverview,
+			true,
+		},
 	}
 
 	return
@@ -11,6 +11,7 @@ import (
 
 	"github.com/kiali/kiali/business"
 	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/graph/api"
 	"github.com/kiali/kiali/log"
 	"github.com/kiali/kiali/routing"
 )
@@ -70,6 +71,12 @@ func (s *Server) Start() {
 	log.Infof("Server endpoint will start at [%v%v]", s.httpServer.Addr, conf.Server.WebRoot)
 	log.Infof("Server endpoint will serve static content from [%v]", conf.Server.StaticContentRootDirectory)
 	secure := conf.Identity.CertFile != "" && conf.Identity.PrivateKeyFile != ""
+
+	// Read the credentials of the external services before any client is created
+	if err := business.StartCredentialsRefresher(); err != nil {
+		log.Errorf("Unable to start the credentials store, the credentials of the config are used: %v", err)
+	}
+
 	go func() {
 		var err error
 		if secure {
@@ -83,15 +90,30 @@ func (s *Server) Start() {
 		log.Warning(err)
 	}()
 
+	// Apply the namespace defaults of the proxy log level to the new pods
+	business.StartProxyLogLevelReconciler()
+
+	// Refresh in background the viewed graphs
+	if conf.Server.GraphCache.Enabled {
+		api.StartGraphRefresher()
+	}
+
 	// Start the Metrics Server
 	if conf.Server.MetricsEnabled {
 		StartMetricsServer()
+		if conf.Server.MeshMetrics.Enabled {
+			business.StartMeshMetricsCollector()
+		}
 	}
 }
 
 // Stop the HTTP server
 func (s *Server) Stop() {
 	StopMetricsServer()
+	business.StopMeshMetricsCollector()
+	api.StopGraphRefresher()
+	business.StopCredentialsRefresher()
+	business.StopProxyLogLevelReconciler()
 	business.Stop()
 	log.Infof("Server endpoint will stop at [%v]", s.httpServer.Addr)
 	s.httpServer.Close()
@@ -0,0 +1,81 @@
+package data
+
+import (
+	"strconv"
+
+	apps_v1 "k8s.io/api/apps/v1"
+	core_v1 "k8s.io/api/core/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// CreateFlaggerDeployments returns the Deployments of a Flagger canary: the "<name>" target Deployment,
+// running the canary version, and the "<name>-primary" Deployment created by Flagger to serve the stable version
+func CreateFlaggerDeployments(name, namespace string, canaryReplicas, primaryReplicas int32) []apps_v1.Deployment {
+	return []apps_v1.Deployment{
+		createDeployment(name, namespace, map[string]string{"app": name}, canaryReplicas),
+		createDeployment(name+"-primary", namespace, map[string]string{"app": name + "-primary"}, primaryReplicas),
+	}
+}
+
+// CreateArgoRolloutReplicaSet returns a ReplicaSet owned by an Argo Rollout, for the given revision
+func CreateArgoRolloutReplicaSet(rollout, namespace, hash string, revision int, replicas int32) apps_v1.ReplicaSet {
+	controller := true
+	return apps_v1.ReplicaSet{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      rollout + "-" + hash,
+			Namespace: namespace,
+			Labels: map[string]string{
+				"app":                        rollout,
+				"rollouts-pod-template-hash": hash,
+			},
+			Annotations: map[string]string{
+				"rollout.argoproj.io/revision": strconv.Itoa(revision),
+			},
+			OwnerReferences: []meta_v1.OwnerReference{{
+				APIVersion: "argoproj.io/v1alpha1",
+				Kind:       "Rollout",
+				Name:       rollout,
+				Controller: &controller,
+			}},
+		},
+		Spec: apps_v1.ReplicaSetSpec{
+			Replicas: &replicas,
+			Template: core_v1.PodTemplateSpec{
+				ObjectMeta: meta_v1.ObjectMeta{
+					Labels: map[string]string{
+						"app":                        rollout,
+						"rollouts-pod-template-hash": hash,
+					},
+				},
+			},
+		},
+		Status: apps_v1.ReplicaSetStatus{
+			Replicas:          replicas,
+			AvailableReplicas: replicas,
+			ReadyReplicas:     replicas,
+		},
+	}
+}
+
+func createDeployment(name, namespace string, labels map[string]string, replicas int32) apps_v1.Deployment {
+	return apps_v1.Deployment{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Labels:    labels,
+		},
+		Spec: apps_v1.DeploymentSpec{
+			Replicas: &replicas,
+			Template: core_v1.PodTemplateSpec{
+				ObjectMeta: meta_v1.ObjectMeta{
+					Labels: labels,
+				},
+			},
+		},
+		Status: apps_v1.DeploymentStatus{
+			Replicas:          replicas,
+			AvailableReplicas: replicas,
+			ReadyReplicas:     replicas,
+		},
+	}
+}
@@ -0,0 +1,21 @@
+package data
+
+import (
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kiali/kiali/kubernetes"
+)
+
+func CreateProxyConfig(name, namespace string, spec map[string]interface{}) kubernetes.IstioObject {
+	return (&kubernetes.GenericIstioObject{
+		TypeMeta: meta_v1.TypeMeta{
+			Kind:       kubernetes.ProxyConfigType,
+			APIVersion: kubernetes.ApiNetworkingV1Beta1Version,
+		},
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Spec: spec,
+	}).DeepCopyIstioObject()
+}
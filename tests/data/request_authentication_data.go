@@ -0,0 +1,71 @@
+package data
+
+import (
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kiali/kiali/kubernetes"
+)
+
+func CreateRequestAuthentication(name, namespace string, selector map[string]interface{}, jwtRules ...interface{}) kubernetes.IstioObject {
+	spec := map[string]interface{}{
+		"jwtRules": jwtRules,
+	}
+	if selector != nil {
+		spec["selector"] = selector
+	}
+	return (&kubernetes.GenericIstioObject{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Spec: spec,
+	}).DeepCopyIstioObject()
+}
+
+func CreateJwtRuleWithJwksUri(issuer, jwksUri string) interface{} {
+	return map[string]interface{}{
+		"issuer":  issuer,
+		"jwksUri": jwksUri,
+	}
+}
+
+func CreateJwtRuleWithJwks(issuer, jwks string) interface{} {
+	return map[string]interface{}{
+		"issuer": issuer,
+		"jwks":   jwks,
+	}
+}
+
+// CreateUnreachableJwksRequestAuthentication points to a JWKS endpoint that doesn't resolve
+func CreateUnreachableJwksRequestAuthentication(name, namespace string) kubernetes.IstioObject {
+	return CreateRequestAuthentication(name, namespace, CreateOneLabelSelector("productpage"),
+		CreateJwtRuleWithJwksUri("https://auth.unreachable.example", "https://auth.unreachable.example/.well-known/jwks.json"))
+}
+
+// CreateJwtAuthorizationPolicy creates an AuthorizationPolicy allowing only requests with a valid JWT
+func CreateJwtAuthorizationPolicy(name, namespace string, selector map[string]interface{}, requestPrincipals []interface{}) kubernetes.IstioObject {
+	spec := map[string]interface{}{
+		"action": "ALLOW",
+		"rules": []interface{}{
+			map[string]interface{}{
+				"from": []interface{}{
+					map[string]interface{}{
+						"source": map[string]interface{}{
+							"requestPrincipals": requestPrincipals,
+						},
+					},
+				},
+			},
+		},
+	}
+	if selector != nil {
+		spec["selector"] = selector
+	}
+	return (&kubernetes.GenericIstioObject{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Spec: spec,
+	}).DeepCopyIstioObject()
+}
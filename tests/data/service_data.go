@@ -0,0 +1,43 @@
+package data
+
+import (
+	core_v1 "k8s.io/api/core/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// CreateServiceWithPorts returns a Service with the given selector, exposing the given ports
+func CreateServiceWithPorts(name, namespace string, selector map[string]string, ports ...core_v1.ServicePort) core_v1.Service {
+	return core_v1.Service{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Spec: core_v1.ServiceSpec{
+			Ports:    ports,
+			Selector: selector,
+		},
+	}
+}
+
+// CreateServicePort returns a TCP port with the given name, the appProtocol is left unset when empty
+func CreateServicePort(name string, port int32, appProtocol string) core_v1.ServicePort {
+	servicePort := core_v1.ServicePort{
+		Name:     name,
+		Port:     port,
+		Protocol: core_v1.ProtocolTCP,
+	}
+	if appProtocol != "" {
+		servicePort.AppProtocol = &appProtocol
+	}
+	return servicePort
+}
+
+// CreateUnnamedServicePort returns a TCP port without name nor appProtocol, as allowed for single-port Services
+func CreateUnnamedServicePort(port int32) core_v1.ServicePort {
+	return CreateServicePort("", port, "")
+}
+
+// CreateMisnamedServicePort returns a TCP port whose name doesn't declare any protocol known by Istio
+func CreateMisnamedServicePort(port int32) core_v1.ServicePort {
+	return CreateServicePort("web", port, "")
+}
@@ -1,6 +1,7 @@
 package config_dump
 
 import (
+	"fmt"
 	"io"
 	"net/http"
 	"os"
@@ -15,6 +16,7 @@ import (
 type PortForwarder interface {
 	Start() error
 	Stop()
+	LocalPort() (int, error)
 }
 
 type forwarder struct {
@@ -41,6 +43,18 @@ func (f forwarder) Start() error {
 	}
 }
 
+// LocalPort returns the local port of the forwarding, the one picked by the system when the port map asked for 0
+func (f forwarder) LocalPort() (int, error) {
+	ports, err := f.forwarder.GetPorts()
+	if err != nil {
+		return 0, err
+	}
+	if len(ports) == 0 {
+		return 0, fmt.Errorf("no port forwarded")
+	}
+	return int(ports[0].Local), nil
+}
+
 func (f forwarder) Stop() {
 	// Closing the StopCh channel is closing the forwarding
 	close(f.StopCh)
@@ -23,7 +23,16 @@ func HttpMethods() []string {
 }
 
 func HttpGet(url string, auth *config.Auth, timeout time.Duration) ([]byte, int, error) {
-	req, err := http.NewRequest(http.MethodGet, url, nil)
+	return httpDo(http.MethodGet, url, auth, timeout)
+}
+
+// HttpPost sends a POST request without body, the parameters being in the url
+func HttpPost(url string, auth *config.Auth, timeout time.Duration) ([]byte, int, error) {
+	return httpDo(http.MethodPost, url, auth, timeout)
+}
+
+func httpDo(method, url string, auth *config.Auth, timeout time.Duration) ([]byte, int, error) {
+	req, err := http.NewRequest(method, url, nil)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -134,6 +134,16 @@ func (m MtlsStatus) MeshMtlsStatus() TlsStatus {
 	}
 }
 
+// ServiceMtlsStatus returns the mTLS status of a service from the TLS mode of the DestinationRule applied to its host
+// and the mTLS mode of the PeerAuthentication applied to its workloads
+func (m MtlsStatus) ServiceMtlsStatus(drStatus, paStatus string) TlsStatus {
+	return TlsStatus{
+		DestinationRuleStatus:    drStatus,
+		PeerAuthenticationStatus: paStatus,
+		OverallStatus:            m.OverallMtlsStatus(TlsStatus{}, m.finalStatus(drStatus, paStatus)),
+	}
+}
+
 func (m MtlsStatus) hasPeerAuthnMeshTLSDefinition() string {
 	for _, mp := range m.PeerAuthentications {
 		if _, mode := kubernetes.PeerAuthnHasMTLSEnabled(mp); mode != "" {